@@ -338,6 +338,19 @@ var allEnums = []*enumInfo{
 			},
 		},
 	},
+	{
+		Pkg:  "model/gurps/enums/armor",
+		Name: "construction",
+		Desc: "holds whether a piece of armor is flexible or rigid, which determines how it may be layered",
+		Values: []*enumValue{
+			{
+				Key:    "unspecified",
+				String: "Unspecified",
+			},
+			{Key: "flexible"},
+			{Key: "rigid"},
+		},
+	},
 	{
 		Pkg:  "model/gurps/enums/attribute",
 		Name: "placement",
@@ -375,6 +388,28 @@ var allEnums = []*enumInfo{
 			{Key: "fit_page"},
 		},
 	},
+	{
+		Pkg:  "model/gurps/enums/casting",
+		Name: "mode",
+		Desc: "holds the way the energy needed to cast spells is paid for",
+		Values: []*enumValue{
+			{
+				Key:    "standard",
+				String: "Standard (FP)",
+				Alt:    "Spells are paid for with FP, as described on p. B236",
+			},
+			{
+				Key:    "threshold",
+				String: "Threshold-Limited",
+				Alt:    "Spells cost no FP; instead, their energy cost is added to a tally that risks calamity once it exceeds the caster's threshold, as described on p. T77",
+			},
+			{
+				Key:    "no_fatigue",
+				String: "No Fatigue Cost",
+				Alt:    "Spells cost no energy to cast or maintain",
+			},
+		},
+	},
 	{
 		Pkg:  "model/gurps/enums/cell",
 		Name: "type",
@@ -405,6 +440,16 @@ var allEnums = []*enumInfo{
 			},
 		},
 	},
+	{
+		Pkg:  "model/gurps/enums/density",
+		Name: "option",
+		Desc: "holds how tightly the content of a sheet is packed onto its pages",
+		Values: []*enumValue{
+			{Key: "comfortable"},
+			{Key: "compact"},
+			{Key: "dense"},
+		},
+	},
 	{
 		Pkg:  "model/gurps/enums/dgroup",
 		Name: "group",
@@ -413,7 +458,8 @@ var allEnums = []*enumInfo{
 			{Key: "character_sheets"},
 			{Key: "character_templates"},
 			{Key: "loot_sheets"},
-			// {Key: "campaigns"}, // TODO: Re-enable Campaign files
+			{Key: "campaigns"},
+			{Key: "vehicles"},
 			{Key: "editors"},
 			{Key: "images"},
 			{Key: "libraries"},
@@ -604,6 +650,21 @@ var allEnums = []*enumInfo{
 			},
 		},
 	},
+	{
+		Pkg:  "model/gurps/enums/eqploc",
+		Name: "location",
+		Desc: "holds where a piece of carried equipment is currently kept",
+		Values: []*enumValue{
+			{Key: "carried"},
+			{Key: "equipped"},
+			{
+				Key:    "mount",
+				String: "Mount/Vehicle",
+			},
+			{Key: "stashed"},
+			{Key: "bank"},
+		},
+	},
 	{
 		Pkg:  "model/gurps/enums/feature",
 		Name: "type",
@@ -793,6 +854,42 @@ var allEnums = []*enumInfo{
 			{Key: "not_applicable"},
 			{Key: "count"},
 			{Key: "points"},
+			{Key: "allocate"},
+			{Key: "optional"},
+		},
+	},
+	{
+		Pkg:  "model/gurps/enums/portrait",
+		Name: "kind",
+		Desc: "holds the kind of image an additional portrait holds",
+		Values: []*enumValue{
+			{
+				Key:    "full_body",
+				String: "Full Body",
+			},
+			{Key: "token"},
+			{
+				Key:    "alternate",
+				String: "Alternate Form",
+			},
+		},
+	},
+	{
+		Pkg:  "model/gurps/enums/power",
+		Name: "source",
+		Desc: "holds the source of a power, which determines the power modifier applied to its abilities",
+		Values: []*enumValue{
+			{Key: "none"},
+			{Key: "biological"},
+			{Key: "chi"},
+			{Key: "cosmic"},
+			{Key: "divine"},
+			{Key: "magical"},
+			{Key: "moral"},
+			{Key: "nature"},
+			{Key: "psionic"},
+			{Key: "spirit"},
+			{Key: "super"},
 		},
 	},
 	{
@@ -901,6 +998,55 @@ var allEnums = []*enumInfo{
 			},
 		},
 	},
+	{
+		Pkg:  "model/gurps/enums/reaction",
+		Name: "source",
+		Desc: "holds the kind of thing a reaction modifier comes from",
+		Values: []*enumValue{
+			{Key: "appearance"},
+			{Key: "status"},
+			{Key: "reputation"},
+			{
+				Key:    "trait",
+				String: "Other Traits",
+			},
+			{Key: "equipment"},
+			{
+				Key:    "skill",
+				String: "Skills",
+			},
+		},
+	},
+	{
+		Pkg:  "model/gurps/enums/rpm",
+		Name: "effect",
+		Desc: "holds the type of effect a Ritual Path Magic ritual produces",
+		Values: []*enumValue{
+			{Key: "sense"},
+			{Key: "strengthen"},
+			{Key: "restore"},
+			{Key: "control"},
+			{Key: "destroy"},
+			{Key: "create"},
+			{Key: "transform"},
+		},
+	},
+	{
+		Pkg:  "model/gurps/enums/rpm",
+		Name: "path",
+		Desc: "holds the Ritual Path Magic path a ritual effect draws upon",
+		Values: []*enumValue{
+			{Key: "body"},
+			{Key: "chance"},
+			{Key: "crossroads"},
+			{Key: "energy"},
+			{Key: "magic"},
+			{Key: "matter"},
+			{Key: "mind"},
+			{Key: "spirit"},
+			{Key: "undead"},
+		},
+	},
 	{
 		Pkg:  "model/gurps/enums/selfctrl",
 		Name: "adjustment",
@@ -1166,6 +1312,25 @@ var allEnums = []*enumInfo{
 			},
 		},
 	},
+	{
+		Pkg:  "model/gurps/enums/study",
+		Name: "tracking",
+		Desc: "controls whether points must be earned through study",
+		Values: []*enumValue{
+			{
+				Key:    "off",
+				String: "Off",
+			},
+			{
+				Key:    "display",
+				String: "Display Only",
+			},
+			{
+				Key:    "enforce",
+				String: "Enforce",
+			},
+		},
+	},
 	{
 		Pkg:  "model/gurps/enums/study",
 		Name: "type",
@@ -1189,6 +1354,33 @@ var allEnums = []*enumInfo{
 			},
 		},
 	},
+	{
+		Pkg:  "model/gurps/enums/threat",
+		Name: "level",
+		Desc: "holds the threat level of an NPC, relative to the party",
+		Values: []*enumValue{
+			{
+				Key: "fodder",
+				Alt: "Fodder (¼ of the party's points)",
+			},
+			{
+				Key: "minion",
+				Alt: "Minion (½ of the party's points)",
+			},
+			{
+				Key: "peer",
+				Alt: "Peer (equal to the party's points)",
+			},
+			{
+				Key: "elite",
+				Alt: "Elite (1½× the party's points)",
+			},
+			{
+				Key: "boss",
+				Alt: "Boss (2½× the party's points)",
+			},
+		},
+	},
 	{
 		Pkg:  "model/gurps/enums/threshold",
 		Name: "op",
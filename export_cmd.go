@@ -0,0 +1,42 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/ux"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+// exportCmd handles the "export" command, which renders character sheets without opening the UI.
+func exportCmd(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, i18n.Text("Usage: %s export [options] file...\n\n"), xos.AppCmdName)
+		fmt.Fprintln(out, i18n.Text("Exports character sheets without opening the UI. If a directory is specified, it will be traversed recursively and all character sheets found will be exported.\n\nOptions:"))
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "pdf", fmt.Sprintf(i18n.Text("The `format` to export to. One of: %s"), strings.Join(ux.BatchExportFormats(), ", ")))
+	output := fs.String("output", "", i18n.Text("The `dir` to write exported files into. If not specified, each export is written next to its source file"))
+	dpi := fs.Int("dpi", 0, fmt.Sprintf(i18n.Text("The resolution to render images at, from %d to %d. If not specified, the image export resolution from the settings is used"), gurps.ImageResolutionMin, gurps.ImageResolutionMax))
+	_ = fs.Parse(args) //nolint:errcheck // ExitOnError is set, so no error can be returned
+	if fs.NArg() == 0 {
+		xos.ExitWithMsg(i18n.Text("No files to process."))
+	}
+	if err := ux.BatchExport(*format, *output, *dpi, fs.Args()); err != nil {
+		xos.ExitWithMsg(err.Error())
+	}
+}
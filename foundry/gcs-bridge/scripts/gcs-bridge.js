@@ -0,0 +1,185 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// GCS Bridge connects the GM's browser to the local API server of GURPS Character Sheet. Rolls made in GCS are posted
+// to the chat and actors linked to the sheets open in GCS are updated as those sheets change. See README.md.
+
+const MODULE_ID = "gcs-bridge";
+const RECONNECT_DELAY = 5000;
+const SYNC_DELAY = 1000;
+
+let socket = null;
+let generation = 0;
+const pendingSyncs = new Map();
+
+Hooks.once("init", () => {
+	game.settings.register(MODULE_ID, "url", {
+		name: "GCS Address",
+		hint: "The address of the GCS local API server, which must be running on this machine.",
+		scope: "client",
+		config: true,
+		type: String,
+		default: "http://127.0.0.1:13323",
+		onChange: connect,
+	});
+	game.settings.register(MODULE_ID, "token", {
+		name: "GCS Token",
+		hint: "The token shown in the Local API Server section of the GCS General Settings. It is kept in this browser only.",
+		scope: "client",
+		config: true,
+		type: String,
+		default: "",
+		onChange: connect,
+	});
+	game.settings.register(MODULE_ID, "postRolls", {
+		name: "Post GCS Rolls to Chat",
+		scope: "client",
+		config: true,
+		type: Boolean,
+		default: true,
+	});
+	game.settings.register(MODULE_ID, "syncActors", {
+		name: "Update Linked Actors",
+		hint: "Actors are linked to a GCS sheet by name the first time the sheet is seen.",
+		scope: "client",
+		config: true,
+		type: Boolean,
+		default: true,
+	});
+});
+
+Hooks.once("ready", () => {
+	if (game.user.isGM) {
+		connect();
+	}
+});
+
+function baseURL() {
+	return game.settings.get(MODULE_ID, "url").trim().replace(/\/+$/, "");
+}
+
+function token() {
+	return game.settings.get(MODULE_ID, "token").trim();
+}
+
+function connect() {
+	generation++;
+	if (socket) {
+		socket.close();
+		socket = null;
+	}
+	if (!game.user.isGM || !token()) {
+		return;
+	}
+	const current = generation;
+	const ws = new WebSocket(`${baseURL().replace(/^http/, "ws")}/api/events?token=${encodeURIComponent(token())}`);
+	ws.onmessage = (msg) => {
+		try {
+			handleEvent(JSON.parse(msg.data));
+		} catch (err) {
+			console.error(`${MODULE_ID} | unable to handle event`, err);
+		}
+	};
+	ws.onclose = () => {
+		if (current === generation) {
+			socket = null;
+			setTimeout(() => {
+				if (current === generation) {
+					connect();
+				}
+			}, RECONNECT_DELAY);
+		}
+	};
+	socket = ws;
+}
+
+function handleEvent(event) {
+	switch (event.type) {
+		case "roll":
+			if (game.settings.get(MODULE_ID, "postRolls")) {
+				postRoll(event.roll);
+			}
+			break;
+		case "sheet_opened":
+		case "attribute_changed":
+		case "item_added":
+		case "item_removed":
+			if (game.settings.get(MODULE_ID, "syncActors")) {
+				scheduleSync(event.sheet);
+			}
+			break;
+	}
+}
+
+function escapeHTML(text) {
+	const div = document.createElement("div");
+	div.textContent = text ?? "";
+	return div.innerHTML;
+}
+
+function postRoll(roll) {
+	let content = "";
+	if (roll.what) {
+		content += `<strong>${escapeHTML(roll.what)}</strong><br>`;
+	}
+	content += escapeHTML(roll.text);
+	ChatMessage.create({ speaker: { alias: roll.who || "GCS" }, content });
+}
+
+// Changes tend to arrive in bursts, so wait for them to settle before fetching the sheet.
+function scheduleSync(sheet) {
+	clearTimeout(pendingSyncs.get(sheet.id));
+	pendingSyncs.set(
+		sheet.id,
+		setTimeout(() => {
+			pendingSyncs.delete(sheet.id);
+			syncActor(sheet).catch((err) => console.error(`${MODULE_ID} | unable to update actor`, err));
+		}, SYNC_DELAY),
+	);
+}
+
+async function fetchJSON(path) {
+	const rsp = await fetch(`${baseURL()}${path}`, { headers: { Authorization: `Bearer ${token()}` } });
+	if (!rsp.ok) {
+		throw new Error(`${path} -> ${rsp.status}`);
+	}
+	return rsp.json();
+}
+
+function linkedActor(sheet) {
+	let actor = game.actors.find((a) => a.getFlag(MODULE_ID, "sheet") === sheet.id);
+	if (!actor && sheet.name) {
+		actor = game.actors.getName(sheet.name);
+	}
+	return actor;
+}
+
+async function syncActor(sheet) {
+	const actor = linkedActor(sheet);
+	if (!actor) {
+		return;
+	}
+	const id = encodeURIComponent(sheet.id);
+	const data = await fetchJSON(`/api/sheets/${id}/data`);
+	// The sheet is stored as text so that values removed from it don't linger, as they would if Foundry merged objects.
+	const update = {
+		[`flags.${MODULE_ID}.sheet`]: sheet.id,
+		[`flags.${MODULE_ID}.data`]: JSON.stringify(data),
+	};
+	if (game.system.id === "gurps") {
+		for (const pool of await fetchJSON(`/api/sheets/${id}/pools`)) {
+			const key = pool.id.toUpperCase();
+			if (key === "HP" || key === "FP") {
+				update[`system.${key}.value`] = pool.current;
+				update[`system.${key}.max`] = pool.maximum;
+			}
+		}
+	}
+	await actor.update(update);
+}
@@ -29,7 +29,7 @@ func main() {
 	early.Configure()
 	ux.LoadLanguageSetting()
 	unison.AttachConsole()
-	xflag.SetUsage(nil, ux.AppDescription(), i18n.Text("[file]..."))
+	xflag.SetUsage(nil, ux.AppDescription(), i18n.Text("[file]...\n       export [options] file..."))
 	savedUsage := flag.CommandLine.Usage
 	flag.CommandLine.Usage = func() {
 		savedUsage()
@@ -40,6 +40,7 @@ func main() {
 		default:
 			w = xterm.NewAnsiWriter(out)
 		}
+		w.WriteString(i18n.Text("Use \"export -h\" to see the options for batch exporting character sheets.\n"))
 		w.WriteString(i18n.Text("Translations dir: "))
 		w.Blue()
 		w.WriteString(i18n.Dir)
@@ -82,6 +83,8 @@ func main() {
 	}
 
 	switch {
+	case len(fileList) != 0 && fileList[0] == "export":
+		exportCmd(fileList[1:])
 	case *convertFiles:
 		if err := gurps.Convert(fileList...); err != nil {
 			xos.ExitWithMsg(err.Error())
@@ -0,0 +1,118 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package cloudsync keeps local folders synchronized with a remote store, such as a WebDAV server, an S3 bucket or a
+// Git repository, so that the same settings and sheets can be used from more than one machine.
+//
+// A record of each file's state at the end of the previous synchronization is kept locally. A file that has changed on
+// only one side since then is copied to the other. A file that has changed on both sides is reported as a Conflict and
+// left untouched until it is resolved. Deletions are not propagated: a file that is missing from one side is copied
+// back to it from the other.
+package cloudsync
+
+import (
+	"context"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+// Provider kinds.
+const (
+	WebDAVKind = "webdav"
+	S3Kind     = "s3"
+	GitKind    = "git"
+)
+
+// Provider stores files in a remote location. Paths are slash-separated and relative to the root of the store.
+// Versions are opaque strings that change whenever the content of a file changes.
+type Provider interface {
+	// List returns the version of each file stored beneath the prefix, keyed by path. A prefix that doesn't exist
+	// yields an empty result rather than an error.
+	List(ctx context.Context, prefix string) (map[string]string, error)
+	// Get returns the content and version of the file at the path.
+	Get(ctx context.Context, path string) (data []byte, version string, err error)
+	// Put stores the data as the content of the file at the path, returning its new version.
+	Put(ctx context.Context, path string, data []byte) (version string, err error)
+}
+
+// Finisher is implemented by providers that accumulate changes and need to be told when a synchronization is complete
+// in order to publish them.
+type Finisher interface {
+	Finish(ctx context.Context) error
+}
+
+// Config holds the settings for synchronizing with a remote store. It is specific to a machine, as it holds both
+// credentials and local paths, and so is stored separately from the settings that get synchronized.
+type Config struct {
+	// Kind is one of WebDAVKind, S3Kind or GitKind. An empty Kind disables synchronization.
+	Kind string `json:"kind,omitzero"`
+	// URL is the base URL of the WebDAV folder, the S3 endpoint, or the Git repository.
+	URL string `json:"url,omitzero"`
+	// Username is the WebDAV user name, the S3 access key ID, or the Git user name.
+	Username string `json:"username,omitzero"`
+	// Password is the WebDAV password, the S3 secret access key, or the Git access token.
+	Password string `json:"password,omitzero"`
+	// Region is the S3 region.
+	Region string `json:"region,omitzero"`
+	// Bucket is the S3 bucket.
+	Bucket string `json:"bucket,omitzero"`
+	// Folders holds the local folders whose sheets should be synchronized. Folders are matched up across machines by
+	// their names, so the same folder may live at a different path on each machine.
+	Folders []string `json:"folders,omitzero"`
+	// Settings is true if the settings file should be synchronized.
+	Settings bool `json:"settings,omitzero"`
+}
+
+// LoadConfig loads the configuration from the file. A missing file yields an empty configuration.
+func LoadConfig(filePath string) (*Config, error) {
+	var cfg Config
+	if !xos.FileExists(filePath) {
+		return &cfg, nil
+	}
+	if err := jio.Load(nil, filePath, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Save the configuration to the file.
+func (c *Config) Save(filePath string) error {
+	return jio.SaveToFile(filePath, c)
+}
+
+// Enabled returns true if a provider has been configured.
+func (c *Config) Enabled() bool {
+	return c.Kind != "" && strings.TrimSpace(c.URL) != ""
+}
+
+// Identity returns a string that identifies the remote store. Synchronization state recorded against one store is
+// meaningless for another.
+func (c *Config) Identity() string {
+	return c.Kind + "|" + strings.TrimSpace(c.URL) + "|" + c.Bucket
+}
+
+// NewProvider creates the provider described by the configuration.
+func (c *Config) NewProvider() (Provider, error) {
+	if !c.Enabled() {
+		return nil, errs.New("no cloud sync provider has been configured")
+	}
+	switch c.Kind {
+	case WebDAVKind:
+		return NewWebDAV(c.URL, c.Username, c.Password)
+	case S3Kind:
+		return NewS3(c.URL, c.Region, c.Bucket, c.Username, c.Password)
+	case GitKind:
+		return NewGit(c.URL, c.Username, c.Password), nil
+	default:
+		return nil, errs.New("unknown cloud sync provider: " + c.Kind)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package cloudsync_test
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/cloudsync"
+	"github.com/richardwilkes/toolbox/v2/check"
+	"golang.org/x/net/webdav"
+)
+
+func TestSync(t *testing.T) {
+	c := check.New(t)
+	server := httptest.NewServer(&webdav.Handler{
+		Prefix:     "/dav",
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	})
+	defer server.Close()
+
+	// Each "machine" has its own copy of the folder and its own state.
+	machineA := t.TempDir()
+	machineB := t.TempDir()
+	newSyncer := func() *cloudsync.Syncer {
+		provider, err := (&cloudsync.Config{Kind: cloudsync.WebDAVKind, URL: server.URL + "/dav"}).NewProvider()
+		c.NoError(err)
+		return &cloudsync.Syncer{Provider: provider, State: &cloudsync.State{}}
+	}
+	folder := func(dir string) *cloudsync.Folder {
+		return &cloudsync.Folder{
+			Local:   dir,
+			Remote:  "sheets/party",
+			Include: func(relPath string) bool { return filepath.Ext(relPath) == ".gcs" },
+		}
+	}
+	write := func(dir, name, content string) {
+		c.NoError(os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		c.NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o640))
+	}
+	read := func(dir, name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		c.NoError(err)
+		return string(data)
+	}
+
+	write(machineA, "hero.gcs", "v1")
+	write(machineA, "pcs/rogue.gcs", "r1")
+	write(machineA, "notes.txt", "not synced")
+	write(machineA, ".hidden/secret.gcs", "not synced")
+	a := newSyncer()
+	result, err := a.Sync(t.Context(), folder(machineA))
+	c.NoError(err)
+	c.Equal(2, len(result.Uploaded))
+	c.Equal(0, len(result.Downloaded))
+
+	b := newSyncer()
+	result, err = b.Sync(t.Context(), folder(machineB))
+	c.NoError(err)
+	c.Equal(2, len(result.Downloaded))
+	c.Equal("v1", read(machineB, "hero.gcs"))
+	c.Equal("r1", read(machineB, "pcs/rogue.gcs"))
+	c.False(fileExists(filepath.Join(machineB, "notes.txt")))
+
+	// Nothing has changed, so nothing should move.
+	result, err = a.Sync(t.Context(), folder(machineA))
+	c.NoError(err)
+	c.Equal(0, len(result.Uploaded)+len(result.Downloaded)+len(result.Conflicts))
+
+	// A change on one side is carried to the other.
+	write(machineB, "pcs/rogue.gcs", "r2")
+	_, err = b.Sync(t.Context(), folder(machineB))
+	c.NoError(err)
+	result, err = a.Sync(t.Context(), folder(machineA))
+	c.NoError(err)
+	c.Equal(1, len(result.Downloaded))
+	c.Equal("r2", read(machineA, "pcs/rogue.gcs"))
+
+	// Files that are busy are left alone.
+	write(machineA, "hero.gcs", "v2")
+	a.Busy = func(string) bool { return true }
+	result, err = a.Sync(t.Context(), folder(machineA))
+	c.NoError(err)
+	c.Equal(1, len(result.Skipped))
+	a.Busy = nil
+
+	// Changes on both sides produce a conflict rather than overwriting either one.
+	write(machineB, "hero.gcs", "v3")
+	_, err = b.Sync(t.Context(), folder(machineB))
+	c.NoError(err)
+	result, err = a.Sync(t.Context(), folder(machineA))
+	c.NoError(err)
+	c.Equal(1, len(result.Conflicts))
+	c.Equal("v2", read(machineA, "hero.gcs"))
+	conflict := result.Conflicts[0]
+	c.Equal("sheets/party/hero.gcs", conflict.RemotePath)
+	c.Equal("v2", string(conflict.Local))
+	c.Equal("v3", string(conflict.Remote))
+
+	conflict.Resolution = cloudsync.KeepBoth
+	result, err = a.Resolve(t.Context(), conflict)
+	c.NoError(err)
+	c.Equal(1, len(result.Uploaded))
+	c.Equal("v2", read(machineA, "hero.gcs"))
+	c.Equal("v3", read(machineA, "hero (remote copy).gcs"))
+
+	result, err = b.Sync(t.Context(), folder(machineB))
+	c.NoError(err)
+	c.Equal(0, len(result.Conflicts))
+	c.Equal("v2", read(machineB, "hero.gcs"))
+	c.Equal("v3", read(machineB, "hero (remote copy).gcs"))
+
+	// A state recorded against a different store is discarded.
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	c.NoError(a.State.Save(statePath))
+	var state *cloudsync.State
+	state, err = cloudsync.LoadState(statePath, a.State.Identity)
+	c.NoError(err)
+	c.Equal(len(a.State.Files), len(state.Files))
+	state, err = cloudsync.LoadState(statePath, "other")
+	c.NoError(err)
+	c.Equal(0, len(state.Files))
+}
+
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package cloudsync
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-billy/v6/memfs"
+	"github.com/go-git/go-billy/v6/util"
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/config"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/plumbing/transport"
+	githttp "github.com/go-git/go-git/v6/plumbing/transport/http"
+	"github.com/go-git/go-git/v6/storage/memory"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+// Git stores files in a Git repository. The repository is cloned into memory when first needed and any changes are
+// committed and pushed back by Finish.
+type Git struct {
+	url      string
+	auth     transport.AuthMethod
+	repo     *git.Repository
+	fs       billy.Filesystem
+	modified bool
+}
+
+// NewGit creates a provider that stores files in the repository at the URL. The access token is used as the password
+// for HTTP basic authentication, which is what the popular Git hosting services expect.
+func NewGit(repoURL, username, accessToken string) *Git {
+	g := &Git{url: strings.TrimSpace(repoURL)}
+	if username != "" || accessToken != "" {
+		if username == "" {
+			username = "gcs"
+		}
+		g.auth = &githttp.BasicAuth{
+			Username: username,
+			Password: accessToken,
+		}
+	}
+	return g
+}
+
+func (g *Git) open(ctx context.Context) error {
+	if g.repo != nil {
+		return nil
+	}
+	g.fs = memfs.New()
+	storage := memory.NewStorage()
+	repo, err := git.CloneContext(ctx, storage, g.fs, &git.CloneOptions{
+		URL:          g.url,
+		Auth:         g.auth,
+		SingleBranch: true,
+		Tags:         plumbing.NoTags,
+	})
+	if err != nil {
+		if !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return errs.NewWithCause("unable to clone "+g.url, err)
+		}
+		// The repository has no commits yet, so start a fresh one that will be pushed to it.
+		storage = memory.NewStorage()
+		if repo, err = git.Init(storage, git.WithWorkTree(g.fs)); err != nil {
+			return errs.Wrap(err)
+		}
+		if _, err = repo.CreateRemote(&config.RemoteConfig{
+			Name: git.DefaultRemoteName,
+			URLs: []string{g.url},
+		}); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	g.repo = repo
+	return nil
+}
+
+// List implements Provider.
+func (g *Git) List(ctx context.Context, prefix string) (map[string]string, error) {
+	if err := g.open(ctx); err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	prefix = strings.Trim(prefix, "/")
+	root := prefix
+	if root == "" {
+		root = "/"
+	}
+	err := util.Walk(g.fs, root, func(p string, info fs.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		data, err := util.ReadFile(g.fs, p)
+		if err != nil {
+			return err
+		}
+		result[strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(p)), "/")] = hashOf(data)
+		return nil
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return result, nil
+}
+
+// Get implements Provider.
+func (g *Git) Get(ctx context.Context, filePath string) (data []byte, version string, err error) {
+	if err = g.open(ctx); err != nil {
+		return nil, "", err
+	}
+	if data, err = util.ReadFile(g.fs, filePath); err != nil {
+		return nil, "", errs.NewWithCause("unable to read "+filePath+" from the repository", err)
+	}
+	return data, hashOf(data), nil
+}
+
+// Put implements Provider.
+func (g *Git) Put(ctx context.Context, filePath string, data []byte) (string, error) {
+	if err := g.open(ctx); err != nil {
+		return "", err
+	}
+	if err := g.fs.MkdirAll(path.Dir(filePath), 0o750); err != nil {
+		return "", errs.Wrap(err)
+	}
+	if err := util.WriteFile(g.fs, filePath, data, 0o640); err != nil {
+		return "", errs.NewWithCause("unable to write "+filePath+" to the repository", err)
+	}
+	g.modified = true
+	return hashOf(data), nil
+}
+
+// Finish implements Finisher. Any files that were stored are committed and pushed to the repository.
+func (g *Git) Finish(ctx context.Context) error {
+	if !g.modified {
+		return nil
+	}
+	wt, err := g.repo.Worktree()
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if err = wt.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return errs.Wrap(err)
+	}
+	if _, err = wt.Commit("Synchronized from GCS", &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "GCS",
+			Email: "gcs@gurpscharactersheet.com",
+			When:  time.Now(),
+		},
+	}); err != nil && !errors.Is(err, git.ErrEmptyCommit) {
+		return errs.Wrap(err)
+	}
+	if err = g.repo.PushContext(ctx, &git.PushOptions{Auth: g.auth}); err != nil &&
+		!errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return errs.NewWithCause("unable to push to "+g.url, err)
+	}
+	g.modified = false
+	return nil
+}
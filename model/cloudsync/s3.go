@@ -0,0 +1,240 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package cloudsync
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xio"
+)
+
+const s3TimeFormat = "20060102T150405Z"
+
+// S3 stores files in an Amazon S3 bucket, or in a bucket provided by any service compatible with its API. Requests
+// use path-style addressing, which all such services support.
+type S3 struct {
+	HTTP      *http.Client
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		ETag string `xml:"ETag"`
+	} `xml:"Contents"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+}
+
+// NewS3 creates a provider that stores files in the bucket at the endpoint, e.g. "https://s3.us-east-1.amazonaws.com".
+// If the region is empty, "us-east-1" is used.
+func NewS3(endpoint, region, bucket, accessKey, secretKey string) (*S3, error) {
+	u, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return nil, errs.NewWithCause("invalid S3 endpoint", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errs.New("S3 endpoint must use http or https")
+	}
+	if bucket = strings.TrimSpace(bucket); bucket == "" {
+		return nil, errs.New("an S3 bucket is required")
+	}
+	if region = strings.TrimSpace(region); region == "" {
+		region = "us-east-1"
+	}
+	return &S3{
+		endpoint:  u,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+	}, nil
+}
+
+// List implements Provider.
+func (s *S3) List(ctx context.Context, prefix string) (map[string]string, error) {
+	result := make(map[string]string)
+	query := url.Values{}
+	query.Set("list-type", "2")
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		query.Set("prefix", prefix+"/")
+	}
+	for {
+		rsp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.StatusCode != http.StatusOK {
+			xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+			return nil, errs.New("unable to list the S3 bucket -> " + rsp.Status)
+		}
+		var list s3ListResult
+		err = xml.NewDecoder(rsp.Body).Decode(&list)
+		xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+		if err != nil {
+			return nil, errs.NewWithCause("invalid response from S3", err)
+		}
+		for _, one := range list.Contents {
+			if !strings.HasSuffix(one.Key, "/") {
+				result[one.Key] = one.ETag
+			}
+		}
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			return result, nil
+		}
+		query.Set("continuation-token", list.NextContinuationToken)
+	}
+}
+
+// Get implements Provider.
+func (s *S3) Get(ctx context.Context, filePath string) (data []byte, version string, err error) {
+	var rsp *http.Response
+	if rsp, err = s.do(ctx, http.MethodGet, filePath, nil, nil); err != nil {
+		return nil, "", err
+	}
+	defer xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+	if rsp.StatusCode != http.StatusOK {
+		return nil, "", errs.New("unable to retrieve " + filePath + " from S3 -> " + rsp.Status)
+	}
+	if data, err = io.ReadAll(rsp.Body); err != nil {
+		return nil, "", errs.NewWithCause("unable to retrieve "+filePath+" from S3", err)
+	}
+	return data, rsp.Header.Get("ETag"), nil
+}
+
+// Put implements Provider.
+func (s *S3) Put(ctx context.Context, filePath string, data []byte) (string, error) {
+	rsp, err := s.do(ctx, http.MethodPut, filePath, nil, data)
+	if err != nil {
+		return "", err
+	}
+	xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+	if rsp.StatusCode != http.StatusOK {
+		return "", errs.New("unable to store " + filePath + " in S3 -> " + rsp.Status)
+	}
+	return rsp.Header.Get("ETag"), nil
+}
+
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, data []byte) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	u.RawPath = strings.TrimSuffix(s.endpoint.EscapedPath(), "/") + "/" + s3Escape(s.bucket, false) + "/" +
+		s3Escape(key, false)
+	u.RawQuery = s3CanonicalQuery(query)
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, errs.NewWithCause("unable to create S3 request", err)
+	}
+	s.sign(req, data)
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var rsp *http.Response
+	if rsp, err = client.Do(req); err != nil {
+		return nil, errs.NewWithCause("S3 request failed", err)
+	}
+	return rsp, nil
+}
+
+// sign the request using AWS Signature Version 4.
+func (s *S3) sign(req *http.Request, payload []byte) {
+	now := time.Now().UTC()
+	stamp := now.Format(s3TimeFormat)
+	day := stamp[:8]
+	payloadHash := hashOf(payload)
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + stamp + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hashOf([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.accessKey+"/"+scope+", SignedHeaders="+
+		signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3CanonicalQuery returns the query string in the canonical form required for signing, which is also a valid form for
+// sending.
+func s3CanonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(query))
+	for k := range query {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	parts := make([]string, 0, len(names))
+	for _, k := range names {
+		for _, v := range query[k] {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape percent-encodes everything other than the unreserved characters, as the signing process requires. Slashes
+// are left alone unless escapeSlash is true.
+func s3Escape(s string, escapeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var buffer strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == '~',
+			c == '/' && !escapeSlash:
+			buffer.WriteByte(c)
+		default:
+			buffer.WriteByte('%')
+			buffer.WriteByte(hexDigits[c>>4])
+			buffer.WriteByte(hexDigits[c&15])
+		}
+	}
+	return buffer.String()
+}
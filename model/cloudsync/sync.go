@@ -0,0 +1,403 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package cloudsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xio"
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+// Possible resolutions for a conflict.
+const (
+	// KeepLocal replaces the remote copy of the file with the local one.
+	KeepLocal Resolution = iota
+	// KeepRemote replaces the local copy of the file with the remote one.
+	KeepRemote
+	// KeepBoth keeps the local copy of the file in place and saves the remote one alongside it under a new name.
+	KeepBoth
+)
+
+// Resolution determines how a conflict is settled.
+type Resolution byte
+
+// Folder pairs a local folder with a location in the remote store.
+type Folder struct {
+	// Local is the path to the folder on this machine.
+	Local string
+	// Remote is the slash-separated path to the folder within the remote store.
+	Remote string
+	// Include, if not nil, is called with the slash-separated path of each file relative to the folder and returns
+	// true if the file should be synchronized. Hidden files and folders are always excluded.
+	Include func(relPath string) bool
+}
+
+// State records the state of each file at the end of the previous synchronization.
+type State struct {
+	Identity string                `json:"identity"`
+	Files    map[string]*FileState `json:"files,omitzero"`
+}
+
+// FileState records the state of a single file at the end of the previous synchronization.
+type FileState struct {
+	Hash    string `json:"hash"`
+	Version string `json:"version"`
+}
+
+// Conflict describes a file that has changed both locally and remotely since the previous synchronization.
+type Conflict struct {
+	// LocalPath is the path to the file on this machine.
+	LocalPath string
+	// RemotePath is the path to the file within the remote store.
+	RemotePath string
+	// Local holds the local content of the file.
+	Local []byte
+	// Remote holds the remote content of the file.
+	Remote []byte
+	// Version is the remote version of the file.
+	Version string
+	// Resolution determines how the conflict will be settled by Syncer.Resolve.
+	Resolution Resolution
+}
+
+// Result describes the outcome of a synchronization. The paths are those of the affected files on this machine.
+type Result struct {
+	Uploaded   []string
+	Downloaded []string
+	Skipped    []string
+	Conflicts  []*Conflict
+}
+
+// Syncer synchronizes local folders with a remote store.
+type Syncer struct {
+	Provider Provider
+	State    *State
+	// Busy, if not nil, is called with the local path of each file that needs to be transferred and returns true if
+	// the file is in use and should be left alone for now.
+	Busy func(localPath string) bool
+}
+
+// LoadState loads the synchronization state from the file. If the file doesn't exist or holds the state for a
+// different remote store, an empty state for the identified store is returned instead.
+func LoadState(filePath, identity string) (*State, error) {
+	var state State
+	if xos.FileExists(filePath) {
+		if err := jio.Load(nil, filePath, &state); err != nil {
+			return nil, err
+		}
+	}
+	if state.Identity != identity || state.Files == nil {
+		state = State{
+			Identity: identity,
+			Files:    make(map[string]*FileState),
+		}
+	}
+	return &state, nil
+}
+
+// Save the synchronization state to the file.
+func (s *State) Save(filePath string) error {
+	return jio.SaveToFile(filePath, s)
+}
+
+// Sync the folders with the remote store. Conflicts are returned in the result and must be settled with Resolve. If an
+// error is returned, the State may not reflect what actually reached the remote store and should be discarded rather
+// than saved.
+func (s *Syncer) Sync(ctx context.Context, folders ...*Folder) (*Result, error) {
+	var result Result
+	for _, f := range folders {
+		if err := s.syncFolder(ctx, f, &result); err != nil {
+			return &result, err
+		}
+	}
+	return &result, s.finish(ctx)
+}
+
+func (s *Syncer) syncFolder(ctx context.Context, f *Folder, result *Result) error {
+	local, err := scanLocal(f)
+	if err != nil {
+		return err
+	}
+	prefix := strings.Trim(f.Remote, "/")
+	var list map[string]string
+	if list, err = s.Provider.List(ctx, prefix); err != nil {
+		return err
+	}
+	remote := make(map[string]string, len(list))
+	for p, version := range list {
+		if rel, ok := strings.CutPrefix(p, prefix+"/"); ok && included(f, rel) {
+			remote[rel] = version
+		}
+	}
+	names := make([]string, 0, len(local)+len(remote))
+	for rel := range local {
+		names = append(names, rel)
+	}
+	for rel := range remote {
+		if _, exists := local[rel]; !exists {
+			names = append(names, rel)
+		}
+	}
+	slices.Sort(names)
+	for _, rel := range names {
+		localPath := filepath.Join(f.Local, filepath.FromSlash(rel))
+		remotePath := prefix + "/" + rel
+		hash, haveLocal := local[rel]
+		version, haveRemote := remote[rel]
+		prior := s.State.Files[remotePath]
+		localChanged := haveLocal && (prior == nil || prior.Hash != hash)
+		remoteChanged := haveRemote && (prior == nil || prior.Version != version)
+		if haveLocal && haveRemote && !localChanged && !remoteChanged {
+			continue
+		}
+		if s.Busy != nil && s.Busy(localPath) {
+			result.Skipped = append(result.Skipped, localPath)
+			continue
+		}
+		switch {
+		case haveLocal && (!haveRemote || !remoteChanged):
+			if err = s.upload(ctx, localPath, remotePath); err != nil {
+				return err
+			}
+			result.Uploaded = append(result.Uploaded, localPath)
+		case haveRemote && (!haveLocal || !localChanged):
+			if err = s.download(ctx, localPath, remotePath); err != nil {
+				return err
+			}
+			result.Downloaded = append(result.Downloaded, localPath)
+		default:
+			var c *Conflict
+			if c, err = s.compare(ctx, localPath, remotePath); err != nil {
+				return err
+			}
+			if c != nil {
+				result.Conflicts = append(result.Conflicts, c)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) upload(ctx context.Context, localPath, remotePath string) error {
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return errs.NewWithCause(localPath, err)
+	}
+	var version string
+	if version, err = s.Provider.Put(ctx, remotePath, data); err != nil {
+		return err
+	}
+	s.record(remotePath, data, version)
+	return nil
+}
+
+func (s *Syncer) download(ctx context.Context, localPath, remotePath string) error {
+	data, version, err := s.Provider.Get(ctx, remotePath)
+	if err != nil {
+		return err
+	}
+	if err = writeLocal(localPath, data); err != nil {
+		return err
+	}
+	s.record(remotePath, data, version)
+	return nil
+}
+
+// compare the two copies of a file that has changed on both sides, returning a conflict if their content differs. If
+// they are the same, both sides just happened to make the same change and the state is simply brought up to date.
+func (s *Syncer) compare(ctx context.Context, localPath, remotePath string) (*Conflict, error) {
+	local, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, errs.NewWithCause(localPath, err)
+	}
+	var remote []byte
+	var version string
+	if remote, version, err = s.Provider.Get(ctx, remotePath); err != nil {
+		return nil, err
+	}
+	if hashOf(local) == hashOf(remote) {
+		s.record(remotePath, local, version)
+		return nil, nil
+	}
+	return &Conflict{
+		LocalPath:  localPath,
+		RemotePath: remotePath,
+		Local:      local,
+		Remote:     remote,
+		Version:    version,
+	}, nil
+}
+
+// Resolve settles the conflicts according to their Resolution. As with Sync, the State should be discarded rather than
+// saved if an error is returned.
+func (s *Syncer) Resolve(ctx context.Context, conflicts ...*Conflict) (*Result, error) {
+	var result Result
+	for _, c := range conflicts {
+		switch c.Resolution {
+		case KeepRemote:
+			if err := writeLocal(c.LocalPath, c.Remote); err != nil {
+				return &result, err
+			}
+			s.record(c.RemotePath, c.Remote, c.Version)
+			result.Downloaded = append(result.Downloaded, c.LocalPath)
+		case KeepBoth:
+			copyPath := remoteCopyPath(c.LocalPath)
+			if err := writeLocal(copyPath, c.Remote); err != nil {
+				return &result, err
+			}
+			result.Downloaded = append(result.Downloaded, copyPath)
+			copyRemotePath := path.Join(path.Dir(c.RemotePath), filepath.Base(copyPath))
+			version, err := s.Provider.Put(ctx, copyRemotePath, c.Remote)
+			if err != nil {
+				return &result, err
+			}
+			s.record(copyRemotePath, c.Remote, version)
+			fallthrough
+		default:
+			version, err := s.Provider.Put(ctx, c.RemotePath, c.Local)
+			if err != nil {
+				return &result, err
+			}
+			s.record(c.RemotePath, c.Local, version)
+			result.Uploaded = append(result.Uploaded, c.LocalPath)
+		}
+	}
+	return &result, s.finish(ctx)
+}
+
+func (s *Syncer) finish(ctx context.Context) error {
+	if f, ok := s.Provider.(Finisher); ok {
+		return f.Finish(ctx)
+	}
+	return nil
+}
+
+func (s *Syncer) record(remotePath string, data []byte, version string) {
+	if s.State.Files == nil {
+		s.State.Files = make(map[string]*FileState)
+	}
+	s.State.Files[remotePath] = &FileState{
+		Hash:    hashOf(data),
+		Version: version,
+	}
+}
+
+// scanLocal returns the content hash of each file in the folder that is to be synchronized, keyed by its
+// slash-separated path relative to the folder. A folder that doesn't exist yet is treated as empty.
+func scanLocal(f *Folder) (map[string]string, error) {
+	result := make(map[string]string)
+	if !xos.IsDir(f.Local) {
+		return result, nil
+	}
+	err := filepath.WalkDir(f.Local, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == f.Local {
+			return nil
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		var rel string
+		if rel, err = filepath.Rel(f.Local, p); err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !included(f, rel) {
+			return nil
+		}
+		var hash string
+		if hash, err = hashFile(p); err != nil {
+			return err
+		}
+		result[rel] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, errs.NewWithCause(f.Local, err)
+	}
+	return result, nil
+}
+
+func included(f *Folder, relPath string) bool {
+	for part := range strings.SplitSeq(relPath, "/") {
+		if part == "" || strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return f.Include == nil || f.Include(relPath)
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer xio.CloseIgnoringErrors(f)
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func writeLocal(localPath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o750); err != nil {
+		return errs.NewWithCause(localPath, err)
+	}
+	if err := xos.WriteSafeFile(localPath, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}); err != nil {
+		return errs.NewWithCause(localPath, err)
+	}
+	return nil
+}
+
+// remoteCopyPath returns an unused path alongside the local file for holding the remote copy of it.
+func remoteCopyPath(localPath string) string {
+	ext := filepath.Ext(localPath)
+	base := strings.TrimSuffix(localPath, ext)
+	for i := 1; ; i++ {
+		suffix := " (remote copy)"
+		if i > 1 {
+			suffix = " (remote copy " + strconv.Itoa(i) + ")"
+		}
+		p := base + suffix + ext
+		if !xos.FileExists(p) {
+			return p
+		}
+	}
+}
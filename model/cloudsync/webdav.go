@@ -0,0 +1,230 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package cloudsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xio"
+)
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<propfind xmlns="DAV:"><prop><getetag/><resourcetype/></prop></propfind>`
+
+// WebDAV stores files on a WebDAV server.
+type WebDAV struct {
+	HTTP     *http.Client
+	base     *url.URL
+	username string
+	password string
+	folders  map[string]bool
+}
+
+type davMultiStatus struct {
+	Responses []davResponse `xml:"DAV: response"`
+}
+
+type davResponse struct {
+	Href     string        `xml:"DAV: href"`
+	PropStat []davPropStat `xml:"DAV: propstat"`
+}
+
+type davPropStat struct {
+	Status     string    `xml:"DAV: status"`
+	ETag       string    `xml:"DAV: prop>getetag"`
+	Collection *struct{} `xml:"DAV: prop>resourcetype>collection"`
+}
+
+// NewWebDAV creates a provider that stores files beneath the folder at the base URL.
+func NewWebDAV(baseURL, username, password string) (*WebDAV, error) {
+	u, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil {
+		return nil, errs.NewWithCause("invalid WebDAV URL", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errs.New("WebDAV URL must use http or https")
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+		u.RawPath = ""
+	}
+	return &WebDAV{
+		base:     u,
+		username: username,
+		password: password,
+		folders:  make(map[string]bool),
+	}, nil
+}
+
+// List implements Provider.
+func (w *WebDAV) List(ctx context.Context, prefix string) (map[string]string, error) {
+	result := make(map[string]string)
+	pending := []string{strings.Trim(prefix, "/")}
+	for len(pending) != 0 {
+		dir := pending[0]
+		pending = pending[1:]
+		responses, err := w.propfind(ctx, dir+"/", "1")
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range responses {
+			p, ok := w.relativePath(r.Href)
+			if !ok || p == dir {
+				continue
+			}
+			for _, ps := range r.PropStat {
+				if !strings.Contains(ps.Status, " 200 ") {
+					continue
+				}
+				if ps.Collection != nil {
+					pending = append(pending, p)
+				} else {
+					result[p] = ps.ETag
+				}
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// Get implements Provider.
+func (w *WebDAV) Get(ctx context.Context, filePath string) (data []byte, version string, err error) {
+	var rsp *http.Response
+	if rsp, err = w.do(ctx, http.MethodGet, filePath, nil, nil); err != nil {
+		return nil, "", err
+	}
+	defer xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+	if rsp.StatusCode != http.StatusOK {
+		return nil, "", errs.New("unable to retrieve " + filePath + " from the WebDAV server -> " + rsp.Status)
+	}
+	if data, err = io.ReadAll(rsp.Body); err != nil {
+		return nil, "", errs.NewWithCause("unable to retrieve "+filePath+" from the WebDAV server", err)
+	}
+	return data, rsp.Header.Get("ETag"), nil
+}
+
+// Put implements Provider.
+func (w *WebDAV) Put(ctx context.Context, filePath string, data []byte) (string, error) {
+	if err := w.makeFolders(ctx, path.Dir(filePath)); err != nil {
+		return "", err
+	}
+	rsp, err := w.do(ctx, http.MethodPut, filePath, bytes.NewReader(data), nil)
+	if err != nil {
+		return "", err
+	}
+	xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return "", errs.New("unable to store " + filePath + " on the WebDAV server -> " + rsp.Status)
+	}
+	if etag := rsp.Header.Get("ETag"); etag != "" {
+		return etag, nil
+	}
+	// Not all servers return the new ETag in response to a PUT, so ask for it.
+	var responses []davResponse
+	if responses, err = w.propfind(ctx, filePath, "0"); err != nil {
+		return "", err
+	}
+	for _, r := range responses {
+		for _, ps := range r.PropStat {
+			if ps.ETag != "" {
+				return ps.ETag, nil
+			}
+		}
+	}
+	return "", errs.New("the WebDAV server didn't provide a version for " + filePath)
+}
+
+func (w *WebDAV) makeFolders(ctx context.Context, dir string) error {
+	if dir == "." || dir == "/" || dir == "" || w.folders[dir] {
+		return nil
+	}
+	if err := w.makeFolders(ctx, path.Dir(dir)); err != nil {
+		return err
+	}
+	rsp, err := w.do(ctx, "MKCOL", dir+"/", nil, nil)
+	if err != nil {
+		return err
+	}
+	xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+	// 405 Method Not Allowed is returned when the folder already exists.
+	if (rsp.StatusCode < 200 || rsp.StatusCode > 299) && rsp.StatusCode != http.StatusMethodNotAllowed {
+		return errs.New("unable to create folder " + dir + " on the WebDAV server -> " + rsp.Status)
+	}
+	w.folders[dir] = true
+	return nil
+}
+
+func (w *WebDAV) propfind(ctx context.Context, target, depth string) ([]davResponse, error) {
+	rsp, err := w.do(ctx, "PROPFIND", target, strings.NewReader(propfindBody), map[string]string{
+		"Depth":        depth,
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if rsp.StatusCode != http.StatusMultiStatus {
+		return nil, errs.New("unable to list " + target + " on the WebDAV server -> " + rsp.Status)
+	}
+	var ms davMultiStatus
+	if err = xml.NewDecoder(rsp.Body).Decode(&ms); err != nil {
+		return nil, errs.NewWithCause("invalid response from the WebDAV server", err)
+	}
+	return ms.Responses, nil
+}
+
+func (w *WebDAV) do(ctx context.Context, method, target string, body io.Reader,
+	headers map[string]string) (*http.Response, error) {
+	u := w.base.JoinPath(strings.TrimPrefix(target, "/"))
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, errs.NewWithCause("unable to create WebDAV request", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if w.username != "" || w.password != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+	client := w.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var rsp *http.Response
+	if rsp, err = client.Do(req); err != nil {
+		return nil, errs.NewWithCause("WebDAV request failed", err)
+	}
+	return rsp, nil
+}
+
+// relativePath converts an href returned by the server into a path relative to the base URL.
+func (w *WebDAV) relativePath(href string) (string, bool) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	rel, ok := strings.CutPrefix(u.Path, w.base.Path)
+	if !ok {
+		return "", false
+	}
+	return strings.Trim(rel, "/"), true
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package diag records how long the application's more expensive operations take, so that they can be shown to the
+// user and attached to bug reports.
+//
+// Operations are identified by a short, stable key, such as "entity.recalculate", rather than by translated text, so
+// that their timings stay together regardless of the language in use and can be read by anyone handling a report.
+package diag
+
+import (
+	"archive/zip"
+	"encoding/json/v2"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+// Keys for the operations that are timed.
+const (
+	BatchExportKey  = "export.batch"
+	LibraryIndexKey = "library.index"
+	RecalculateKey  = "entity.recalculate"
+	SheetRebuildKey = "sheet.rebuild"
+)
+
+const (
+	// SlowThreshold is the duration at or above which an operation is considered slow.
+	SlowThreshold = 250 * time.Millisecond
+	// MaxSlowOperations is the number of slow operations that are remembered.
+	MaxSlowOperations = 50
+	// MetricsFileName is the name of the file holding the metrics within a bundle.
+	MetricsFileName = "metrics.json"
+)
+
+// Timing summarizes the recorded durations of an operation.
+type Timing struct {
+	Key     string
+	Count   int
+	Total   time.Duration
+	Longest time.Duration
+	Last    time.Duration
+}
+
+// Average returns the average duration of the operation.
+func (t Timing) Average() time.Duration {
+	if t.Count == 0 {
+		return 0
+	}
+	return t.Total / time.Duration(t.Count)
+}
+
+// SlowOperation records a single operation that took at least SlowThreshold to complete.
+type SlowOperation struct {
+	Key      string
+	When     time.Time
+	Duration time.Duration
+}
+
+// Memory holds a summary of the memory in use.
+type Memory struct {
+	HeapInUse   uint64 `json:"heap_in_use"`
+	HeapObjects uint64 `json:"heap_objects"`
+	System      uint64 `json:"system"`
+	TotalAlloc  uint64 `json:"total_alloc"`
+	GCCycles    uint32 `json:"gc_cycles"`
+	Goroutines  int    `json:"goroutines"`
+}
+
+var (
+	lock    sync.Mutex
+	timings = make(map[string]*Timing)
+	slow    []SlowOperation
+)
+
+// Track records the time elapsed since start for the operation. Typically called as:
+//
+//	defer diag.Track(diag.RecalculateKey, time.Now())
+func Track(key string, start time.Time) {
+	Record(key, time.Since(start))
+}
+
+// Record a duration for the operation.
+func Record(key string, duration time.Duration) {
+	lock.Lock()
+	defer lock.Unlock()
+	t, ok := timings[key]
+	if !ok {
+		t = &Timing{Key: key}
+		timings[key] = t
+	}
+	t.Count++
+	t.Total += duration
+	t.Last = duration
+	t.Longest = max(t.Longest, duration)
+	if duration >= SlowThreshold {
+		if len(slow) >= MaxSlowOperations {
+			slow = slices.Delete(slow, 0, len(slow)-MaxSlowOperations+1)
+		}
+		slow = append(slow, SlowOperation{
+			Key:      key,
+			When:     time.Now().Add(-duration),
+			Duration: duration,
+		})
+	}
+}
+
+// Timings returns the timings recorded so far, sorted by key.
+func Timings() []Timing {
+	lock.Lock()
+	defer lock.Unlock()
+	list := make([]Timing, 0, len(timings))
+	for _, t := range timings {
+		list = append(list, *t)
+	}
+	slices.SortFunc(list, func(a, b Timing) int { return strings.Compare(a.Key, b.Key) })
+	return list
+}
+
+// SlowOperations returns the slow operations that have been recorded, most recent first.
+func SlowOperations() []SlowOperation {
+	lock.Lock()
+	defer lock.Unlock()
+	list := slices.Clone(slow)
+	slices.Reverse(list)
+	return list
+}
+
+// Reset discards everything that has been recorded.
+func Reset() {
+	lock.Lock()
+	defer lock.Unlock()
+	timings = make(map[string]*Timing)
+	slow = nil
+}
+
+// CurrentMemory returns a summary of the memory currently in use.
+func CurrentMemory() Memory {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return Memory{
+		HeapInUse:   stats.HeapInuse,
+		HeapObjects: stats.HeapObjects,
+		System:      stats.Sys,
+		TotalAlloc:  stats.TotalAlloc,
+		GCCycles:    stats.NumGC,
+		Goroutines:  runtime.NumGoroutine(),
+	}
+}
+
+// Anonymizer removes the things that would identify the user from text, replacing their home directory with "~" and
+// their user name with "<user>".
+type Anonymizer struct {
+	replacer *strings.Replacer
+}
+
+// NewAnonymizer creates a new Anonymizer for the given home directory and user name. User names shorter than three
+// characters are left alone, as replacing them would mangle too much unrelated text.
+func NewAnonymizer(homeDir, userName string) *Anonymizer {
+	var pairs []string
+	if homeDir = strings.TrimRight(homeDir, `/\`); homeDir != "" {
+		pairs = append(pairs, homeDir, "~")
+		if slashed := filepath.ToSlash(homeDir); slashed != homeDir {
+			pairs = append(pairs, slashed, "~")
+		}
+	}
+	if len(userName) >= 3 {
+		pairs = append(pairs, userName, "<user>")
+	}
+	return &Anonymizer{replacer: strings.NewReplacer(pairs...)}
+}
+
+// Anonymize returns the text with identifying information removed.
+func (a *Anonymizer) Anonymize(text string) string {
+	return a.replacer.Replace(text)
+}
+
+// WriteBundle writes a zip archive containing the metrics, as JSON, along with the contents of the log files. Each
+// piece is passed through the anonymizer first. Log files that don't exist are skipped.
+func WriteBundle(w io.Writer, metrics any, logPaths []string, anonymizer *Anonymizer) error {
+	zw := zip.NewWriter(w)
+	data, err := json.Marshal(metrics, json.Deterministic(true))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if err = addToBundle(zw, MetricsFileName, anonymizer.Anonymize(string(data))); err != nil {
+		return err
+	}
+	for _, p := range logPaths {
+		if data, err = os.ReadFile(p); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return errs.Wrap(err)
+		}
+		if err = addToBundle(zw, filepath.Base(p), anonymizer.Anonymize(string(data))); err != nil {
+			return err
+		}
+	}
+	return errs.Wrap(zw.Close())
+}
+
+func addToBundle(zw *zip.Writer, name, content string) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	_, err = io.WriteString(w, content)
+	return errs.Wrap(err)
+}
+
+// WriteBundleFile writes a bundle to the file at filePath. See WriteBundle.
+func WriteBundleFile(filePath string, metrics any, logPaths []string, anonymizer *Anonymizer) error {
+	return xos.WriteSafeFile(filePath, func(w io.Writer) error {
+		return WriteBundle(w, metrics, logPaths, anonymizer)
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package diag_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/diag"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestRecord(t *testing.T) {
+	c := check.New(t)
+	diag.Reset()
+	defer diag.Reset()
+	diag.Record("b", 10*time.Millisecond)
+	diag.Record("a", 30*time.Millisecond)
+	diag.Record("a", diag.SlowThreshold)
+	timings := diag.Timings()
+	c.Equal(2, len(timings))
+	c.Equal("a", timings[0].Key)
+	c.Equal(2, timings[0].Count)
+	c.Equal(diag.SlowThreshold, timings[0].Longest)
+	c.Equal(diag.SlowThreshold, timings[0].Last)
+	c.Equal((30*time.Millisecond+diag.SlowThreshold)/2, timings[0].Average())
+	c.Equal("b", timings[1].Key)
+	slow := diag.SlowOperations()
+	c.Equal(1, len(slow))
+	c.Equal("a", slow[0].Key)
+
+	for i := range diag.MaxSlowOperations + 5 {
+		diag.Record("slow", diag.SlowThreshold+time.Duration(i))
+	}
+	slow = diag.SlowOperations()
+	c.Equal(diag.MaxSlowOperations, len(slow))
+	c.Equal(diag.SlowThreshold+time.Duration(diag.MaxSlowOperations+4), slow[0].Duration, "most recent first")
+}
+
+func TestAnonymize(t *testing.T) {
+	c := check.New(t)
+	a := diag.NewAnonymizer("/home/jsmith/", "jsmith")
+	c.Equal("opened ~/GCS/Library/x.gcs as <user>", a.Anonymize("opened /home/jsmith/GCS/Library/x.gcs as jsmith"))
+	c.Equal("a b", diag.NewAnonymizer("", "a").Anonymize("a b"))
+}
+
+func TestWriteBundle(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "gcs.log")
+	c.NoError(os.WriteFile(logPath, []byte("loaded "+dir+"/sheet.gcs\n"), 0o600))
+	var buffer bytes.Buffer
+	c.NoError(diag.WriteBundle(&buffer, map[string]int{"files": 3}, []string{logPath, logPath + ".missing"},
+		diag.NewAnonymizer(dir, "")))
+	r, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	c.NoError(err)
+	contents := make(map[string]string)
+	for _, f := range r.File {
+		var rc io.ReadCloser
+		rc, err = f.Open()
+		c.NoError(err)
+		var data []byte
+		data, err = io.ReadAll(rc)
+		c.NoError(err)
+		c.NoError(rc.Close())
+		contents[f.Name] = string(data)
+	}
+	c.Equal(map[string]string{
+		diag.MetricsFileName: `{"files":3}`,
+		"gcs.log":            "loaded ~/sheet.gcs\n",
+	}, contents)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package dice
+
+import (
+	"slices"
+	"sync"
+)
+
+// MaxHistory is the maximum number of results retained by a History.
+const MaxHistory = 500
+
+var globalHistory = &History{}
+
+// History holds the results of recent rolls, oldest first, and notifies listeners as new results are added.
+type History struct {
+	lock      sync.RWMutex
+	results   []*Result
+	listeners []func(*Result)
+}
+
+// GlobalHistory returns the history shared by the whole application.
+func GlobalHistory() *History {
+	return globalHistory
+}
+
+// Add a result to the history and notify the listeners.
+func (h *History) Add(r *Result) {
+	if r == nil {
+		return
+	}
+	h.lock.Lock()
+	h.results = append(h.results, r)
+	if len(h.results) > MaxHistory {
+		h.results = slices.Delete(h.results, 0, len(h.results)-MaxHistory)
+	}
+	listeners := slices.Clone(h.listeners)
+	h.lock.Unlock()
+	for _, listener := range listeners {
+		listener(r)
+	}
+}
+
+// Results returns a copy of the results, oldest first.
+func (h *History) Results() []*Result {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return slices.Clone(h.results)
+}
+
+// Clear removes all results from the history.
+func (h *History) Clear() {
+	h.lock.Lock()
+	h.results = nil
+	h.lock.Unlock()
+}
+
+// AddListener adds a function that will be called each time a result is added to the history.
+func (h *History) AddListener(listener func(*Result)) {
+	h.lock.Lock()
+	h.listeners = append(h.listeners, listener)
+	h.lock.Unlock()
+}
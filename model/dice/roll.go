@@ -0,0 +1,218 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package dice provides GURPS-specific dice rolls, such as success rolls against an effective level and damage rolls,
+// along with a history of the results.
+package dice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	rpgdice "github.com/richardwilkes/rpgtools/dice"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xrand"
+)
+
+// Possible roll kinds.
+const (
+	GenericRoll Kind = iota
+	SuccessRoll
+	DamageRoll
+	LocationRoll
+)
+
+// Kind identifies the type of roll that was made.
+type Kind byte
+
+// Result holds the outcome of a single roll.
+type Result struct {
+	When     time.Time
+	Who      string
+	What     string
+	Spec     string
+	Extra    string
+	Kind     Kind
+	Dice     []int
+	Total    int
+	Level    int
+	Margin   int
+	Success  bool
+	Critical bool
+}
+
+// RollSuccess performs a 3d6 success roll against the given effective level. If rnd is nil, a default randomizer will
+// be used.
+func RollSuccess(rnd xrand.Randomizer, who, what string, level int) *Result {
+	values, total := rollDice(rnd, &rpgdice.Dice{Count: 3, Sides: 6, Multiplier: 1}, false)
+	r := &Result{
+		When:   time.Now(),
+		Who:    who,
+		What:   what,
+		Spec:   "3d",
+		Kind:   SuccessRoll,
+		Dice:   values,
+		Total:  total,
+		Level:  level,
+		Margin: level - total,
+	}
+	r.Success, r.Critical = SuccessOutcome(total, level)
+	return r
+}
+
+// RollDamage performs a damage roll for the given damage text, which is expected to contain a dice specification,
+// such as "2d+1(2) cut". Any text following the dice specification is retained in the Extra field of the result. If
+// rnd is nil, a default randomizer will be used. Returns nil if no dice specification can be found.
+func RollDamage(rnd xrand.Randomizer, who, what, damage string, extraDiceFromModifiers bool) *Result {
+	start, end := rpgdice.ExtractDicePosition(damage)
+	if start == -1 {
+		return nil
+	}
+	d := rpgdice.New(damage[start:end])
+	values, total := rollDice(rnd, d, extraDiceFromModifiers)
+	return &Result{
+		When:  time.Now(),
+		Who:   who,
+		What:  what,
+		Spec:  d.StringExtra(extraDiceFromModifiers),
+		Extra: strings.TrimSpace(damage[end:]),
+		Kind:  DamageRoll,
+		Dice:  values,
+		Total: max(total, 0),
+	}
+}
+
+// RollSpec performs a generic roll of the given dice specification. If rnd is nil, a default randomizer will be used.
+func RollSpec(rnd xrand.Randomizer, who, what, spec string, extraDiceFromModifiers bool) *Result {
+	d := rpgdice.New(spec)
+	values, total := rollDice(rnd, d, extraDiceFromModifiers)
+	return &Result{
+		When:  time.Now(),
+		Who:   who,
+		What:  what,
+		Spec:  d.StringExtra(extraDiceFromModifiers),
+		Kind:  GenericRoll,
+		Dice:  values,
+		Total: total,
+	}
+}
+
+// RollLocation performs a roll of the given dice specification against a hit location table. The locate function is
+// called with the total rolled and should return a description of the location that was hit. If rnd is nil, a default
+// randomizer will be used.
+func RollLocation(rnd xrand.Randomizer, who, what, spec string, locate func(total int) string) *Result {
+	r := RollSpec(rnd, who, what, spec, false)
+	r.Kind = LocationRoll
+	r.Extra = locate(r.Total)
+	return r
+}
+
+func rollDice(rnd xrand.Randomizer, d *rpgdice.Dice, extraDiceFromModifiers bool) (values []int, total int) {
+	if rnd == nil {
+		rnd = xrand.New()
+	}
+	adjusted := *d
+	if extraDiceFromModifiers {
+		adjusted.ApplyExtraDiceFromModifiers()
+	}
+	adjusted.Normalize()
+	switch {
+	case adjusted.Sides > 1:
+		values = make([]int, adjusted.Count)
+		for i := range values {
+			values[i] = 1 + rnd.Intn(adjusted.Sides)
+			total += values[i]
+		}
+	case adjusted.Sides == 1:
+		total = adjusted.Count
+	}
+	return values, (total + adjusted.Modifier) * adjusted.Multiplier
+}
+
+// SuccessOutcome determines whether a 3d6 roll succeeded against the effective level, and whether the result was a
+// critical one, following the rules on p. B348.
+func SuccessOutcome(roll, level int) (success, critical bool) {
+	switch {
+	case roll <= 4:
+		return true, true
+	case roll == 5 && level >= 15, roll == 6 && level >= 16:
+		return true, true
+	case roll == 18:
+		return false, true
+	case roll == 17:
+		return false, level <= 15
+	case roll >= level+10:
+		return false, true
+	default:
+		return roll <= level, false
+	}
+}
+
+// DiceString returns the individual dice values, separated by '+'.
+func (r *Result) DiceString() string {
+	parts := make([]string, len(r.Dice))
+	for i, v := range r.Dice {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, "+")
+}
+
+// Outcome returns a description of the outcome of the roll.
+func (r *Result) Outcome() string {
+	switch r.Kind {
+	case SuccessRoll:
+		var text string
+		switch {
+		case r.Success && r.Critical:
+			text = i18n.Text("Critical success")
+		case r.Success:
+			text = i18n.Text("Success")
+		case r.Critical:
+			text = i18n.Text("Critical failure")
+		default:
+			text = i18n.Text("Failure")
+		}
+		if r.Margin < 0 {
+			return fmt.Sprintf(i18n.Text("%s by %d"), text, -r.Margin)
+		}
+		return fmt.Sprintf(i18n.Text("%s by %d"), text, r.Margin)
+	case DamageRoll:
+		if r.Extra != "" {
+			return strconv.Itoa(r.Total) + " " + r.Extra
+		}
+		return strconv.Itoa(r.Total)
+	case LocationRoll:
+		if r.Extra != "" {
+			return fmt.Sprintf(i18n.Text("%d: %s"), r.Total, r.Extra)
+		}
+		return strconv.Itoa(r.Total)
+	default:
+		return strconv.Itoa(r.Total)
+	}
+}
+
+func (r *Result) String() string {
+	var buffer strings.Builder
+	if r.Who != "" {
+		buffer.WriteString(r.Who)
+		buffer.WriteString(": ")
+	}
+	if r.What != "" {
+		buffer.WriteString(r.What)
+		buffer.WriteByte(' ')
+	}
+	if r.Kind == SuccessRoll {
+		fmt.Fprintf(&buffer, i18n.Text("(vs %d) rolled %d [%s]; %s"), r.Level, r.Total, r.DiceString(), r.Outcome())
+	} else {
+		fmt.Fprintf(&buffer, i18n.Text("(%s) rolled %s [%s]"), r.Spec, r.Outcome(), r.DiceString())
+	}
+	return buffer.String()
+}
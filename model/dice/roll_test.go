@@ -0,0 +1,82 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package dice_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+// fixedRandomizer returns the values it was given, in order, as zero-based die results.
+type fixedRandomizer struct {
+	values []int
+	next   int
+}
+
+func (r *fixedRandomizer) Intn(_ int) int {
+	v := r.values[r.next%len(r.values)]
+	r.next++
+	return v - 1
+}
+
+func TestSuccessOutcome(t *testing.T) {
+	c := check.New(t)
+	check := func(roll, level int, success, critical bool) {
+		t.Helper()
+		s, crit := dice.SuccessOutcome(roll, level)
+		c.Equal(success, s, "success for roll %d vs %d", roll, level)
+		c.Equal(critical, crit, "critical for roll %d vs %d", roll, level)
+	}
+	check(3, 1, true, true)
+	check(4, 2, true, true)
+	check(5, 14, true, false)
+	check(5, 15, true, true)
+	check(6, 15, true, false)
+	check(6, 16, true, true)
+	check(10, 10, true, false)
+	check(11, 10, false, false)
+	check(16, 6, false, true)
+	check(17, 15, false, true)
+	check(17, 16, false, false)
+	check(18, 30, false, true)
+}
+
+func TestRollSuccess(t *testing.T) {
+	c := check.New(t)
+	r := dice.RollSuccess(&fixedRandomizer{values: []int{2, 3, 4}}, "Bob", "Broadsword", 12)
+	c.Equal(9, r.Total)
+	c.Equal(3, r.Margin)
+	c.True(r.Success)
+	c.False(r.Critical)
+	c.Equal("2+3+4", r.DiceString())
+}
+
+func TestRollDamage(t *testing.T) {
+	c := check.New(t)
+	r := dice.RollDamage(&fixedRandomizer{values: []int{6, 1}}, "", "Broadsword", "2d+1(2) cut", false)
+	c.NotNil(r)
+	c.Equal(8, r.Total)
+	c.Equal("(2) cut", r.Extra)
+	c.Equal("8 (2) cut", r.Outcome())
+	c.Nil(dice.RollDamage(nil, "", "", "cut", false))
+}
+
+func TestRollLocation(t *testing.T) {
+	c := check.New(t)
+	r := dice.RollLocation(&fixedRandomizer{values: []int{3, 4, 2}}, "Bob", "Broadsword", "3d", func(total int) string {
+		c.Equal(9, total)
+		return "Torso"
+	})
+	c.Equal(dice.LocationRoll, r.Kind)
+	c.Equal("Torso", r.Extra)
+	c.Equal("9: Torso", r.Outcome())
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package discord provides the ability to post announcements to a Discord channel via a webhook.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xio"
+)
+
+// Embed colors.
+const (
+	NeutralColor  = 0x3498DB
+	SuccessColor  = 0x2ECC71
+	FailureColor  = 0xE74C3C
+	CriticalColor = 0xF1C40F
+	WarningColor  = 0xE67E22
+)
+
+// Username is the name the announcements are posted under.
+const Username = "GCS"
+
+// Client posts messages to a Discord webhook.
+type Client struct {
+	HTTP       *http.Client
+	WebhookURL string
+}
+
+// Message holds the data for a webhook post.
+type Message struct {
+	Username string   `json:"username,omitzero"`
+	Content  string   `json:"content,omitzero"`
+	Embeds   []*Embed `json:"embeds,omitzero"`
+}
+
+// Embed holds the data for a single embed within a message.
+type Embed struct {
+	Title       string        `json:"title,omitzero"`
+	Description string        `json:"description,omitzero"`
+	Timestamp   string        `json:"timestamp,omitzero"`
+	Fields      []*EmbedField `json:"fields,omitzero"`
+	Color       int           `json:"color,omitzero"`
+}
+
+// EmbedField holds a single name/value pair within an embed.
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitzero"`
+}
+
+// RollEmbed creates an embed describing the roll result.
+func RollEmbed(r *dice.Result) *Embed {
+	e := &Embed{
+		Title:     rollTitle(r),
+		Timestamp: r.When.UTC().Format(time.RFC3339),
+		Color:     NeutralColor,
+	}
+	if r.Kind == dice.SuccessRoll {
+		switch {
+		case r.Critical:
+			if r.Success {
+				e.Color = CriticalColor
+			} else {
+				e.Color = FailureColor
+			}
+		case r.Success:
+			e.Color = SuccessColor
+		default:
+			e.Color = FailureColor
+		}
+		e.Description = "**" + r.Outcome() + "**"
+		e.Fields = []*EmbedField{
+			{Name: i18n.Text("Roll"), Value: strconv.Itoa(r.Total), Inline: true},
+			{Name: i18n.Text("Target"), Value: strconv.Itoa(r.Level), Inline: true},
+			{Name: i18n.Text("Margin"), Value: fmt.Sprintf("%+d", r.Margin), Inline: true},
+		}
+	} else {
+		e.Description = "**" + r.Outcome() + "**"
+		e.Fields = []*EmbedField{{Name: i18n.Text("Dice"), Value: r.Spec, Inline: true}}
+	}
+	if len(r.Dice) != 0 {
+		e.Fields = append(e.Fields, &EmbedField{Name: i18n.Text("Individual Dice"), Value: r.DiceString(), Inline: true})
+	}
+	return e
+}
+
+func rollTitle(r *dice.Result) string {
+	var parts []string
+	if r.Who != "" {
+		parts = append(parts, r.Who)
+	}
+	if r.What != "" {
+		parts = append(parts, r.What)
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf(i18n.Text("Rolled %s"), r.Spec)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// ThresholdEmbed creates an embed announcing that a character's pool has fallen into a new threshold state.
+func ThresholdEmbed(who, pool, state, current, maximum string) *Embed {
+	title := pool
+	if who != "" {
+		title = who + ": " + pool
+	}
+	return &Embed{
+		Title:       title,
+		Description: fmt.Sprintf(i18n.Text("Now **%s**"), state),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Color:       WarningColor,
+		Fields:      []*EmbedField{{Name: pool, Value: current + " / " + maximum, Inline: true}},
+	}
+}
+
+// Enabled returns true if the client has been configured with a webhook URL.
+func (c *Client) Enabled() bool {
+	return strings.TrimSpace(c.WebhookURL) != ""
+}
+
+// Post the embeds to the webhook.
+func (c *Client) Post(ctx context.Context, embeds ...*Embed) error {
+	if !c.Enabled() {
+		return errs.New("no Discord webhook URL has been configured")
+	}
+	var buffer bytes.Buffer
+	if err := jio.Save(&buffer, &Message{Username: Username, Embeds: embeds}); err != nil {
+		return err
+	}
+	uri := strings.TrimSpace(c.WebhookURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, &buffer)
+	if err != nil {
+		return errs.NewWithCause("unable to create Discord webhook request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var rsp *http.Response
+	if rsp, err = client.Do(req); err != nil {
+		// Don't include the URL in the error, as it contains the webhook's secret token.
+		return errs.NewWithCause("Discord webhook request failed", err)
+	}
+	defer xio.DiscardAndCloseIgnoringErrors(rsp.Body)
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return errs.New("unexpected response code from Discord webhook -> " + rsp.Status)
+	}
+	return nil
+}
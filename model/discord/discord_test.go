@@ -0,0 +1,66 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package discord_test
+
+import (
+	"encoding/json/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/discord"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestRollEmbed(t *testing.T) {
+	c := check.New(t)
+	e := discord.RollEmbed(&dice.Result{
+		When:    time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Who:     "Bob",
+		What:    "Broadsword",
+		Spec:    "3d",
+		Kind:    dice.SuccessRoll,
+		Dice:    []int{2, 3, 4},
+		Total:   9,
+		Level:   12,
+		Margin:  3,
+		Success: true,
+	})
+	c.Equal("Bob: Broadsword", e.Title)
+	c.Equal(discord.SuccessColor, e.Color)
+	c.Equal("2025-03-01T12:00:00Z", e.Timestamp)
+	c.Equal(4, len(e.Fields))
+	c.Equal("+3", e.Fields[2].Value)
+	c.Equal("2+3+4", e.Fields[3].Value)
+
+	e = discord.RollEmbed(&dice.Result{Spec: "2d+1", Kind: dice.DamageRoll, Total: 8, Extra: "cut"})
+	c.Equal("Rolled 2d+1", e.Title)
+	c.Equal(discord.NeutralColor, e.Color)
+	c.Equal("**8 cut**", e.Description)
+}
+
+func TestPost(t *testing.T) {
+	c := check.New(t)
+	var msg discord.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.NoError(json.UnmarshalRead(r.Body, &msg))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := &discord.Client{WebhookURL: server.URL}
+	c.NoError(client.Post(t.Context(), discord.ThresholdEmbed("Bob", "HP", "Reeling", "3", "12")))
+	c.Equal(discord.Username, msg.Username)
+	c.Equal(1, len(msg.Embeds))
+	c.Equal("Bob: HP", msg.Embeds[0].Title)
+	c.Equal("3 / 12", msg.Embeds[0].Fields[0].Value)
+	c.HasError((&discord.Client{}).Post(t.Context()))
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/portrait"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+// AltPortrait holds an additional portrait for an entity, such as a full body image, a token or an alternate form.
+type AltPortrait struct {
+	Name  string        `json:"name,omitzero"`
+	Kind  portrait.Kind `json:"kind"`
+	Data  []byte        `json:"data,omitzero"`
+	image *unison.Image
+}
+
+// Title returns the name of the portrait, or the name of its kind if it has none.
+func (a *AltPortrait) Title() string {
+	if name := strings.TrimSpace(a.Name); name != "" {
+		return name
+	}
+	return a.Kind.String()
+}
+
+// Image returns the image for the portrait, if it has one.
+func (a *AltPortrait) Image() *unison.Image {
+	if a.image == nil && len(a.Data) != 0 {
+		var err error
+		if a.image, err = unison.NewImageFromBytes(a.Data, geom.NewPoint(0.5, 0.5)); err != nil {
+			errs.Log(errs.NewWithCause("unable to load portrait data", err), "name", a.Title())
+			a.image = nil
+			a.Data = nil
+			return nil
+		}
+	}
+	return a.image
+}
+
+// SetData replaces the image data of the portrait.
+func (a *AltPortrait) SetData(data []byte) {
+	a.Data = data
+	a.image = nil
+}
+
+// Clone creates a copy of this.
+func (a *AltPortrait) Clone() *AltPortrait {
+	clone := *a
+	return &clone
+}
+
+// ShownAltPortrait returns the additional portrait chosen for display, or nil if the primary portrait is shown.
+func (p *Profile) ShownAltPortrait() *AltPortrait {
+	if p.ShownPortrait > 0 && p.ShownPortrait <= len(p.AltPortraits) {
+		return p.AltPortraits[p.ShownPortrait-1]
+	}
+	return nil
+}
+
+// ShownPortraitData returns the image data of the portrait chosen for display.
+func (p *Profile) ShownPortraitData() []byte {
+	if alt := p.ShownAltPortrait(); alt != nil {
+		return alt.Data
+	}
+	return p.PortraitData
+}
+
+// SetShownPortraitData replaces the image data of the portrait chosen for display.
+func (p *Profile) SetShownPortraitData(data []byte) {
+	if alt := p.ShownAltPortrait(); alt != nil {
+		alt.SetData(data)
+		return
+	}
+	p.PortraitData = data
+	p.PortraitImage = nil
+}
+
+// PortraitChoices returns the titles of the portraits that may be chosen for display, starting with the primary
+// portrait. The index of a title is the value to use for ShownPortrait.
+func (p *Profile) PortraitChoices() []string {
+	choices := make([]string, 0, len(p.AltPortraits)+1)
+	choices = append(choices, i18n.Text("Portrait"))
+	for _, one := range p.AltPortraits {
+		choices = append(choices, one.Title())
+	}
+	return choices
+}
+
+// TokenPortraitData returns the image data best suited for creating a token: the first token portrait that has an
+// image, falling back to the portrait chosen for display and then to the primary portrait.
+func (p *Profile) TokenPortraitData() []byte {
+	for _, one := range p.AltPortraits {
+		if one.Kind == portrait.Token && len(one.Data) != 0 {
+			return one.Data
+		}
+	}
+	if data := p.ShownPortraitData(); len(data) != 0 {
+		return data
+	}
+	return p.PortraitData
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/armor"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// ArmorLayer holds the DR a single piece of equipped armor provides to a hit location.
+type ArmorLayer struct {
+	Equipment *Equipment
+	DR        map[string]int
+	Ignored   bool
+}
+
+// String implements fmt.Stringer.
+func (l *ArmorLayer) String() string {
+	var buffer strings.Builder
+	buffer.WriteString(l.Equipment.String())
+	var details []string
+	if l.Equipment.ArmorConstruction != armor.Unspecified {
+		details = append(details, l.Equipment.ArmorConstruction.String())
+	}
+	details = append(details, fmt.Sprintf(i18n.Text("DR %d"), l.DR[AllID]))
+	if l.Ignored {
+		details = append(details, i18n.Text("ignored"))
+	}
+	fmt.Fprintf(&buffer, " (%s)", strings.Join(details, ", "))
+	return buffer.String()
+}
+
+// HitLocationDR holds the DR for a hit location, along with the armor layers that contribute to it.
+type HitLocationDR struct {
+	Location *HitLocation
+	Depth    int
+	DR       string
+	Layers   []*ArmorLayer
+}
+
+// ArmorLayersSummary returns a description of the armor layers.
+func ArmorLayersSummary(layers []*ArmorLayer) string {
+	list := make([]string, 0, len(layers))
+	for _, layer := range layers {
+		list = append(list, layer.String())
+	}
+	return strings.Join(list, "; ")
+}
+
+// HitLocationDRs returns the DR for each hit location of the entity's body, including those in sub-tables.
+func (e *Entity) HitLocationDRs() []*HitLocationDR {
+	return e.appendHitLocationDRs(nil, BodyFor(e).Locations, 0)
+}
+
+func (e *Entity) appendHitLocationDRs(list []*HitLocationDR, locations []*HitLocation, depth int) []*HitLocationDR {
+	for _, loc := range locations {
+		list = append(list, &HitLocationDR{
+			Location: loc,
+			Depth:    depth,
+			DR:       loc.DisplayDR(e, nil),
+			Layers:   e.ArmorLayersFor(loc.LocID),
+		})
+		if loc.SubTable != nil {
+			list = e.appendHitLocationDRs(list, loc.SubTable.Locations, depth+1)
+		}
+	}
+	return list
+}
+
+// ArmorLayersFor returns the equipped armor covering the given hit location, in the order it was found. Rigid armor
+// can't be worn over other rigid armor, so when more than one rigid layer covers the location, only the one providing
+// the most DR counts and the others are marked as ignored.
+func (e *Entity) ArmorLayersFor(locationID string) []*ArmorLayer {
+	isTopLevel := e.isTopLevelLocation(locationID)
+	var layers []*ArmorLayer
+	byEquipment := make(map[*Equipment]*ArmorLayer)
+	for _, one := range e.features.drBonuses {
+		if strings.EqualFold(one.Specialization, PDSpecializationKey) {
+			continue
+		}
+		eqp, ok := one.Owner().(*Equipment)
+		if !ok || !one.coversLocation(locationID, isTopLevel) {
+			continue
+		}
+		layer, exists := byEquipment[eqp]
+		if !exists {
+			layer = &ArmorLayer{
+				Equipment: eqp,
+				DR:        make(map[string]int),
+			}
+			byEquipment[eqp] = layer
+			layers = append(layers, layer)
+		}
+		layer.DR[strings.ToLower(one.Specialization)] += fxp.AsInteger[int](one.AdjustedAmount())
+	}
+	var kept *ArmorLayer
+	for _, layer := range layers {
+		if layer.Equipment.ArmorConstruction != armor.Rigid {
+			continue
+		}
+		if kept == nil || layer.DR[AllID] > kept.DR[AllID] {
+			if kept != nil {
+				kept.Ignored = true
+			}
+			kept = layer
+		} else {
+			layer.Ignored = true
+		}
+	}
+	return layers
+}
+
+func (e *Entity) isTopLevelLocation(locationID string) bool {
+	for _, one := range e.SheetSettings.BodyType.Locations {
+		if one.LocID == locationID {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DRBonus) coversLocation(locationID string, isTopLevel bool) bool {
+	for _, loc := range d.Locations {
+		if (loc == AllID && isTopLevel) || strings.EqualFold(loc, locationID) {
+			return true
+		}
+	}
+	return false
+}
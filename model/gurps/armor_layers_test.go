@@ -0,0 +1,58 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/armor"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func newTestArmor(e *Entity, name string, dr int, construction armor.Construction) *Equipment {
+	eqp := NewEquipment(e, nil, false)
+	eqp.Name = name
+	eqp.ArmorConstruction = construction
+	bonus := NewDRBonus()
+	bonus.Amount = fxp.FromInteger(dr)
+	eqp.Features = Features{bonus}
+	return eqp
+}
+
+func TestArmorLayering(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	gambeson := newTestArmor(e, "Gambeson", 1, armor.Flexible)
+	mail := newTestArmor(e, "Mail", 4, armor.Flexible)
+	e.CarriedEquipment = []*Equipment{gambeson, mail}
+	e.Recalculate()
+	torso := BodyFor(e).LookupLocationByID(e, TorsoID)
+	c.NotNil(torso)
+	c.Equal(5, torso.DR(e, nil, nil)[AllID])
+
+	breastplate := newTestArmor(e, "Breastplate", 5, armor.Rigid)
+	corselet := newTestArmor(e, "Corselet", 3, armor.Rigid)
+	e.CarriedEquipment = append(e.CarriedEquipment, corselet, breastplate)
+	e.Recalculate()
+	c.Equal(10, torso.DR(e, nil, nil)[AllID])
+	layers := e.ArmorLayersFor(TorsoID)
+	c.Equal(4, len(layers))
+	for _, layer := range layers {
+		c.Equal(layer.Equipment == corselet, layer.Ignored, layer.Equipment.Name)
+	}
+	c.Contains(ArmorLayersSummary(layers), "Corselet (Rigid, DR 3, ignored)")
+
+	for _, one := range e.HitLocationDRs() {
+		if one.Location.LocID == TorsoID {
+			c.Equal("10", one.DR)
+		}
+	}
+}
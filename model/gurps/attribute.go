@@ -210,6 +210,28 @@ func CountThresholdOpMet(op threshold.Op, attributes *Attributes) int {
 	return total
 }
 
+// ApplyPoolThresholdEffects adds the attribute adjustments for the pool thresholds that are currently in effect to the
+// bonuses of the targeted attributes. The thresholds are all determined before any adjustments are made, so that the
+// result does not depend on the order the attributes are visited in.
+func ApplyPoolThresholdEffects(attributes *Attributes) {
+	var effects []*PoolThresholdEffect
+	for _, one := range attributes.Set {
+		if t := one.CurrentThreshold(); t != nil {
+			effects = append(effects, t.Effects...)
+		}
+	}
+	for _, effect := range effects {
+		if attr, ok := attributes.Set[effect.AttrID]; ok {
+			if def := attr.AttributeDef(); def != nil && !def.IsSeparator() {
+				attr.Bonus += effect.Amount
+				if !def.AllowsDecimal() {
+					attr.Bonus = attr.Bonus.Floor()
+				}
+			}
+		}
+	}
+}
+
 // Hash writes this object's contents into the hasher.
 func (a *Attribute) Hash(h hash.Hash) {
 	xhash.StringWithLen(h, a.AttrID)
@@ -0,0 +1,150 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/encumbrance"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// AttributeChangeEffect holds the value of something on the sheet before and after an attribute change.
+type AttributeChangeEffect struct {
+	Category string
+	Name     string
+	Before   string
+	After    string
+}
+
+// AttributeChangePreview holds the downstream effects of changing an attribute's value.
+type AttributeChangePreview struct {
+	Attribute  *Attribute
+	Value      fxp.Int
+	Adjustment fxp.Int
+	// Cost is the change in the number of points spent on the attribute.
+	Cost    fxp.Int
+	Effects []*AttributeChangeEffect
+}
+
+type attributeChangeSnapshotEntry struct {
+	category string
+	name     string
+	value    string
+}
+
+// PreviewAttributeChange returns what would change on the sheet if the attribute with the given ID were set to the
+// value. The entity is left as it was found. Returns nil if the attribute does not exist.
+func PreviewAttributeChange(entity *Entity, attrID string, value fxp.Int) *AttributeChangePreview {
+	if entity == nil {
+		return nil
+	}
+	attr, ok := entity.Attributes.Set[attrID]
+	if !ok || attr.AttributeDef() == nil {
+		return nil
+	}
+	saved := attr.Adjustment
+	beforeCost := attr.PointCost()
+	before := attributeChangeSnapshot(entity)
+	attr.SetMaximum(value)
+	entity.Recalculate()
+	preview := &AttributeChangePreview{
+		Attribute:  attr,
+		Value:      attr.Maximum(),
+		Adjustment: attr.Adjustment,
+		Cost:       attr.PointCost() - beforeCost,
+	}
+	after := attributeChangeSnapshot(entity)
+	attr.Adjustment = saved
+	entity.Recalculate()
+	beforeValues := make(map[string]string, len(before))
+	for _, one := range before {
+		beforeValues[one.category+"\x00"+one.name] = one.value
+	}
+	for _, one := range after {
+		if prior, exists := beforeValues[one.category+"\x00"+one.name]; exists && prior != one.value {
+			preview.Effects = append(preview.Effects, &AttributeChangeEffect{
+				Category: one.category,
+				Name:     one.name,
+				Before:   prior,
+				After:    one.value,
+			})
+		}
+	}
+	return preview
+}
+
+func attributeChangeSnapshot(entity *Entity) []attributeChangeSnapshotEntry {
+	var list []attributeChangeSnapshotEntry
+	add := func(category, name, value string) {
+		list = append(list, attributeChangeSnapshotEntry{category: category, name: name, value: value})
+	}
+	category := i18n.Text("Attributes")
+	for _, def := range SheetSettingsFor(entity).Attributes.List(true) {
+		if attr, ok := entity.Attributes.Set[def.ID()]; ok {
+			add(category, def.CombinedName(), attr.Maximum().String())
+		}
+	}
+	units := SheetSettingsFor(entity).DefaultWeightUnits
+	category = i18n.Text("Encumbrance")
+	add(category, i18n.Text("Basic Lift"), units.Format(entity.BasicLift()))
+	for _, enc := range encumbrance.Levels {
+		add(category, enc.String(), fmt.Sprintf(i18n.Text("Max Load %s, Move %d, Dodge %d"),
+			units.Format(entity.MaximumCarry(enc)), entity.Move(enc), entity.Dodge(enc)))
+	}
+	category = i18n.Text("Damage")
+	add(category, i18n.Text("Thrust"), entity.Thrust().String())
+	add(category, i18n.Text("Swing"), entity.Swing().String())
+	category = i18n.Text("Skills")
+	Traverse(func(s *Skill) bool {
+		add(category, s.String(), s.LevelData.LevelAsString(false))
+		return false
+	}, false, true, entity.Skills...)
+	category = i18n.Text("Spells")
+	Traverse(func(s *Spell) bool {
+		add(category, s.String(), s.LevelData.LevelAsString(false))
+		return false
+	}, false, true, entity.Spells...)
+	category = i18n.Text("Weapons")
+	weapons := append(entity.Weapons(true, false, true), entity.Weapons(false, false, true)...)
+	names := make([]string, len(weapons))
+	for i, w := range weapons {
+		names[i] = w.String()
+		if usage := w.UsageWithReplacements(); usage != "" {
+			names[i] += " (" + usage + ")"
+		}
+	}
+	indexes := make([]int, len(weapons))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	slices.SortStableFunc(indexes, func(a, b int) int { return cmp.Compare(names[a], names[b]) })
+	seen := make(map[string]int)
+	for _, i := range indexes {
+		name := names[i]
+		if count := seen[name]; count != 0 {
+			name += " #" + strconv.Itoa(count+1)
+		}
+		seen[names[i]]++
+		w := weapons[i]
+		add(category, name, fmt.Sprintf(i18n.Text("Level %s, Damage %s"), w.SkillLevel(nil).String(),
+			w.Damage.ResolvedDamage(nil)))
+	}
+	return list
+}
+
+// Apply sets the attribute to the previewed value.
+func (p *AttributeChangePreview) Apply() {
+	p.Attribute.Adjustment = p.Adjustment
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestPreviewAttributeChange(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	skill := NewSkill(e, nil, false)
+	skill.Name = "Broadsword"
+	skill.Points = fxp.One
+	e.Skills = []*Skill{skill}
+	e.Recalculate()
+
+	c.Nil(PreviewAttributeChange(e, "missing", fxp.Ten))
+
+	preview := PreviewAttributeChange(e, StrengthID, fxp.FromInteger(12))
+	c.NotNil(preview)
+	c.Equal(fxp.FromInteger(12), preview.Value)
+	c.Equal(fxp.Two, preview.Adjustment)
+	c.Equal(fxp.FromInteger(20), preview.Cost)
+	effects := make(map[string]*AttributeChangeEffect)
+	for _, one := range preview.Effects {
+		effects[one.Name] = one
+	}
+	c.NotNil(effects["Thrust"])
+	c.NotNil(effects["Basic Lift"])
+	c.Nil(effects[skill.String()])
+	c.Equal(fxp.Int(0), e.Attributes.Set[StrengthID].Adjustment)
+
+	preview = PreviewAttributeChange(e, DexterityID, fxp.FromInteger(11))
+	c.NotNil(preview)
+	var found *AttributeChangeEffect
+	for _, one := range preview.Effects {
+		if one.Name == skill.String() {
+			found = one
+		}
+	}
+	c.NotNil(found)
+	c.Equal("9", found.Before)
+	c.Equal("10", found.After)
+	c.Equal(fxp.Nine, skill.LevelData.Level)
+
+	preview.Apply()
+	e.Recalculate()
+	c.Equal(fxp.Ten, skill.LevelData.Level)
+}
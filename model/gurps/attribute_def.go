@@ -52,6 +52,7 @@ type AttributeDefData struct {
 	CostPerPoint        fxp.Int             `json:"cost_per_point,omitzero"`
 	CostAdjPercentPerSM fxp.Int             `json:"cost_adj_percent_per_sm,omitzero"`
 	Thresholds          []*PoolThreshold    `json:"thresholds,omitzero"`
+	ShowTracker         bool                `json:"show_tracker,omitzero"`
 }
 
 // MarshalJSONTo implements json.MarshalerTo.
@@ -187,6 +188,11 @@ func (a *AttributeDef) Pool() bool {
 	return a.Type == attribute.PoolSeparator || a.Type == attribute.Pool || a.Type == attribute.PoolRef
 }
 
+// ShowsTracker returns true if this is a pool that should also be shown as a tracker block on the sheet.
+func (a *AttributeDef) ShowsTracker() bool {
+	return a.ShowTracker && (a.Type == attribute.Pool || a.Type == attribute.PoolRef)
+}
+
 // AllowsDecimal returns true if the value can have a decimal point in it.
 func (a *AttributeDef) AllowsDecimal() bool {
 	return a.Type == attribute.Decimal || a.Type == attribute.DecimalRef
@@ -233,6 +239,7 @@ func (a *AttributeDef) Hash(h hash.Hash) {
 	for _, one := range a.Thresholds {
 		one.Hash(h)
 	}
+	xhash.Bool(h, a.ShowTracker)
 }
 
 // IsOpen returns true if this attribute is a separator and it is open.
@@ -168,6 +168,9 @@ func (a *AttributeDefs) ResetTargetKeyPrefixes(prefixProvider func() string) {
 		one.KeyPrefix = prefixProvider()
 		for _, threshold := range one.Thresholds {
 			threshold.KeyPrefix = prefixProvider()
+			for _, effect := range threshold.Effects {
+				effect.KeyPrefix = prefixProvider()
+			}
 		}
 	}
 }
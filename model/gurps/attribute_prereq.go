@@ -11,6 +11,7 @@ package gurps
 
 import (
 	"hash"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/criteria"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
@@ -76,19 +77,33 @@ func (p *AttributePrereq) Satisfied(entity *Entity, _ any, tooltip *xbytes.Inser
 	}
 	if !satisfied && tooltip != nil {
 		tooltip.WriteString(prefix)
-		tooltip.WriteString(HasText(p.Has))
-		tooltip.WriteByte(' ')
-		tooltip.WriteString(entity.ResolveAttributeName(p.Which))
-		if p.CombinedWith != "" {
-			tooltip.WriteByte('+')
-			tooltip.WriteString(entity.ResolveAttributeName(p.CombinedWith))
-		}
-		tooltip.WriteString(i18n.Text(" which "))
-		tooltip.WriteString(p.QualifierCriteria.String())
+		tooltip.WriteString(p.requirement(entity))
 	}
 	return satisfied
 }
 
+func (p *AttributePrereq) requirement(entity *Entity) string {
+	var buffer strings.Builder
+	buffer.WriteString(HasText(p.Has))
+	buffer.WriteByte(' ')
+	buffer.WriteString(entity.ResolveAttributeName(p.Which))
+	if p.CombinedWith != "" {
+		buffer.WriteByte('+')
+		buffer.WriteString(entity.ResolveAttributeName(p.CombinedWith))
+	}
+	buffer.WriteString(i18n.Text(" which "))
+	buffer.WriteString(p.QualifierCriteria.String())
+	return buffer.String()
+}
+
+func (p *AttributePrereq) explain(entity *Entity, _ any) (requirement, current string) {
+	value := entity.ResolveAttributeCurrent(p.Which)
+	if p.CombinedWith != "" {
+		value += entity.ResolveAttributeCurrent(p.CombinedWith)
+	}
+	return p.requirement(entity), value.String()
+}
+
 // Hash writes this object's contents into the hasher.
 func (p *AttributePrereq) Hash(h hash.Hash) {
 	if p == nil {
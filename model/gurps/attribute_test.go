@@ -0,0 +1,37 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestPoolThresholdEffects(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	fp := e.SheetSettings.Attributes.Set["fp"]
+	for _, threshold := range fp.Thresholds {
+		if threshold.State == "Tired" {
+			threshold.Effects = append(threshold.Effects, &PoolThresholdEffect{AttrID: "iq", Amount: -fxp.Two})
+		}
+	}
+	e.Recalculate()
+	c.Equal(fxp.Ten, e.Attributes.Set["iq"].Maximum(), "rested")
+	e.Attributes.Set["fp"].Damage = fxp.Eight
+	e.Recalculate()
+	c.Equal("Tired", e.Attributes.Set["fp"].CurrentThreshold().State, "threshold state")
+	c.Equal(fxp.Eight, e.Attributes.Set["iq"].Maximum(), "tired")
+	e.Attributes.Set["fp"].Damage = 0
+	e.Recalculate()
+	c.Equal(fxp.Ten, e.Attributes.Set["iq"].Maximum(), "rested again")
+}
@@ -56,6 +56,24 @@ func (a *Attributes) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 	return nil
 }
 
+// SyncWithDefs adds any attributes that are defined in the entity's sheet settings but are missing, removes any that
+// no longer have a definition, and updates the ordering to match the definitions.
+func (a *Attributes) SyncWithDefs(entity *Entity) {
+	defs := entity.SheetSettings.Attributes
+	for attrID, def := range defs.Set {
+		if attr, exists := a.Set[attrID]; exists {
+			attr.Order = def.Order
+		} else {
+			a.Set[attrID] = NewAttribute(entity, attrID, def.Order)
+		}
+	}
+	for attrID := range a.Set {
+		if _, exists := defs.Set[attrID]; !exists {
+			delete(a.Set, attrID)
+		}
+	}
+}
+
 // Clone a copy of this.
 func (a *Attributes) Clone(entity *Entity) *Attributes {
 	clone := &Attributes{Set: make(map[string]*Attribute)}
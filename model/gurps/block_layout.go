@@ -12,8 +12,11 @@ package gurps
 import (
 	"encoding/json/jsontext"
 	"encoding/json/v2"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/xstrings"
 )
 
@@ -21,32 +24,125 @@ import (
 const (
 	BlockLayoutReactionsKey            = "reactions"
 	BlockLayoutConditionalModifiersKey = "conditional_modifiers"
+	BlockLayoutReactionSummaryKey      = "reaction_summary"
+	BlockLayoutPoolTrackersKey         = "pool_trackers"
+	BlockLayoutWoundsKey               = "wounds"
+	BlockLayoutConditionsKey           = "conditions"
+	BlockLayoutGrapplingKey            = "grappling"
+	BlockLayoutFatigueKey              = "fatigue"
 	BlockLayoutMeleeKey                = "melee"
 	BlockLayoutRangedKey               = "ranged"
 	BlockLayoutTraitsKey               = "traits"
 	BlockLayoutSkillsKey               = "skills"
 	BlockLayoutSpellsKey               = "spells"
+	BlockLayoutGrimoireKey             = "grimoire"
 	BlockLayoutEquipmentKey            = "equipment"
 	BlockLayoutOtherEquipmentKey       = "other_equipment"
 	BlockLayoutNotesKey                = "notes"
 )
 
+// MaxBlockLayoutColumns is the maximum number of columns a block's content may flow into on a printed page.
+const MaxBlockLayoutColumns = 3
+
+// Options that may be attached to a block layout key. Each is separated from the key by a colon, e.g. "skills:2:keep".
+// A number sets the columns the block's content flows into on a printed page, while BlockLayoutMinimumOptionPrefix
+// followed by a number sets the entries a list block must have for it to be shown, e.g. "reactions:min=2".
+const (
+	blockLayoutOptionSeparator     = ":"
+	BlockLayoutKeepTogetherOption  = "keep"
+	BlockLayoutNewPageOption       = "newpage"
+	BlockLayoutMinimumOptionPrefix = "min="
+)
+
 var allBlockLayoutKeys = []string{
 	BlockLayoutReactionsKey,
 	BlockLayoutConditionalModifiersKey,
+	BlockLayoutReactionSummaryKey,
+	BlockLayoutPoolTrackersKey,
+	BlockLayoutWoundsKey,
+	BlockLayoutConditionsKey,
+	BlockLayoutGrapplingKey,
+	BlockLayoutFatigueKey,
 	BlockLayoutMeleeKey,
 	BlockLayoutRangedKey,
 	BlockLayoutTraitsKey,
 	BlockLayoutSkillsKey,
 	BlockLayoutSpellsKey,
+	BlockLayoutGrimoireKey,
 	BlockLayoutEquipmentKey,
 	BlockLayoutOtherEquipmentKey,
 	BlockLayoutNotesKey,
 }
 
+// BlockLayoutKeyTitle returns a human-readable title for the block layout key.
+func BlockLayoutKeyTitle(key string) string {
+	switch key {
+	case BlockLayoutReactionsKey:
+		return i18n.Text("Reactions")
+	case BlockLayoutConditionalModifiersKey:
+		return i18n.Text("Conditional Modifiers")
+	case BlockLayoutReactionSummaryKey:
+		return i18n.Text("Reaction Summary")
+	case BlockLayoutPoolTrackersKey:
+		return i18n.Text("Pool Trackers")
+	case BlockLayoutWoundsKey:
+		return i18n.Text("Wounds")
+	case BlockLayoutConditionsKey:
+		return i18n.Text("Conditions")
+	case BlockLayoutGrapplingKey:
+		return i18n.Text("Grappling")
+	case BlockLayoutFatigueKey:
+		return i18n.Text("Fatigue")
+	case BlockLayoutMeleeKey:
+		return i18n.Text("Melee Weapons")
+	case BlockLayoutRangedKey:
+		return i18n.Text("Ranged Weapons")
+	case BlockLayoutTraitsKey:
+		return i18n.Text("Traits")
+	case BlockLayoutSkillsKey:
+		return i18n.Text("Skills")
+	case BlockLayoutSpellsKey:
+		return i18n.Text("Spells")
+	case BlockLayoutGrimoireKey:
+		return i18n.Text("Grimoire")
+	case BlockLayoutEquipmentKey:
+		return i18n.Text("Carried Equipment")
+	case BlockLayoutOtherEquipmentKey:
+		return i18n.Text("Other Equipment")
+	case BlockLayoutNotesKey:
+		return i18n.Text("Notes")
+	default:
+		return key
+	}
+}
+
+// BlockLayoutKeySupportsColumns returns true if the content of the block with the given key may flow into multiple
+// columns on a printed page.
+func BlockLayoutKeySupportsColumns(key string) bool {
+	switch key {
+	case BlockLayoutSkillsKey, BlockLayoutSpellsKey, BlockLayoutEquipmentKey, BlockLayoutOtherEquipmentKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// BlockLayoutKeyIsList returns true if the block with the given key is a list of entries.
+func BlockLayoutKeyIsList(key string) bool {
+	switch key {
+	case BlockLayoutReactionsKey, BlockLayoutConditionalModifiersKey, BlockLayoutMeleeKey, BlockLayoutRangedKey,
+		BlockLayoutTraitsKey, BlockLayoutSkillsKey, BlockLayoutSpellsKey, BlockLayoutEquipmentKey,
+		BlockLayoutOtherEquipmentKey, BlockLayoutNotesKey:
+		return true
+	default:
+		return false
+	}
+}
+
 // BlockLayout holds the sheet's block layout.
 type BlockLayout struct {
-	Layout []string
+	Layout     []string
+	customKeys []string
 }
 
 // NewBlockLayout creates a new default BlockLayout.
@@ -56,10 +152,12 @@ func NewBlockLayout() *BlockLayout {
 	return &b
 }
 
-// NewBlockLayoutFromString creates a new BlockLayout from an input string.
-func NewBlockLayoutFromString(str string) (blockLayout *BlockLayout, inputWasValid bool) {
+// NewBlockLayoutFromString creates a new BlockLayout from an input string. The keys of any custom blocks that may be
+// placed in the layout should be provided.
+func NewBlockLayoutFromString(str string, customKeys ...string) (blockLayout *BlockLayout, inputWasValid bool) {
+	blockLayout = &BlockLayout{customKeys: slices.Clone(customKeys)}
 	var layout []string
-	remaining := CreateFullKeySet()
+	remaining := blockLayout.keySet()
 	inputWasValid = true
 	for _, line := range strings.Split(strings.ToLower(str), "\n") {
 		var parts []string
@@ -71,10 +169,13 @@ func NewBlockLayoutFromString(str string) (blockLayout *BlockLayout, inputWasVal
 				inputWasValid = false
 				break
 			}
-			part = mapOldLayoutKeys(part)
-			if remaining[part] {
-				delete(remaining, part)
-				parts = append(parts, part)
+			entry, valid := parseBlockLayoutPart(part)
+			if !valid {
+				inputWasValid = false
+			}
+			if remaining[entry.key] {
+				delete(remaining, entry.key)
+				parts = append(parts, entry.String())
 			} else {
 				inputWasValid = false
 			}
@@ -84,13 +185,14 @@ func NewBlockLayoutFromString(str string) (blockLayout *BlockLayout, inputWasVal
 		}
 	}
 	if len(remaining) != 0 {
-		for _, k := range allBlockLayoutKeys {
+		for _, k := range blockLayout.allKeys() {
 			if remaining[k] {
 				layout = append(layout, k)
 			}
 		}
 	}
-	return &BlockLayout{Layout: layout}, inputWasValid
+	blockLayout.Layout = layout
+	return blockLayout, inputWasValid
 }
 
 func mapOldLayoutKeys(key string) string {
@@ -100,17 +202,81 @@ func mapOldLayoutKeys(key string) string {
 	return key
 }
 
+// blockLayoutEntry holds a block layout key along with the options attached to it.
+type blockLayoutEntry struct {
+	key          string
+	columns      int
+	minimum      int
+	keepTogether bool
+	newPage      bool
+}
+
+// parseBlockLayoutPart splits a part of a layout line into its key and options. valid will be false if an option was
+// present that isn't permitted, in which case it is ignored.
+func parseBlockLayoutPart(part string) (entry blockLayoutEntry, valid bool) {
+	options := strings.Split(part, blockLayoutOptionSeparator)
+	entry.key = mapOldLayoutKeys(options[0])
+	entry.columns = 1
+	valid = true
+	for _, option := range options[1:] {
+		switch option {
+		case BlockLayoutKeepTogetherOption:
+			entry.keepTogether = true
+		case BlockLayoutNewPageOption:
+			entry.newPage = true
+		default:
+			if value, found := strings.CutPrefix(option, BlockLayoutMinimumOptionPrefix); found {
+				if minimum, err := strconv.Atoi(value); err == nil && minimum >= 0 && BlockLayoutKeyIsList(entry.key) {
+					entry.minimum = minimum
+				} else {
+					valid = false
+				}
+				continue
+			}
+			if columns, err := strconv.Atoi(option); err == nil && columns >= 1 && columns <= MaxBlockLayoutColumns &&
+				(columns == 1 || BlockLayoutKeySupportsColumns(entry.key)) {
+				entry.columns = columns
+			} else {
+				valid = false
+			}
+		}
+	}
+	return entry, valid
+}
+
+func (e blockLayoutEntry) String() string {
+	var buffer strings.Builder
+	buffer.WriteString(e.key)
+	if e.columns > 1 {
+		buffer.WriteString(blockLayoutOptionSeparator)
+		buffer.WriteString(strconv.Itoa(e.columns))
+	}
+	if e.minimum > 0 {
+		buffer.WriteString(blockLayoutOptionSeparator)
+		buffer.WriteString(BlockLayoutMinimumOptionPrefix)
+		buffer.WriteString(strconv.Itoa(e.minimum))
+	}
+	if e.keepTogether {
+		buffer.WriteString(blockLayoutOptionSeparator)
+		buffer.WriteString(BlockLayoutKeepTogetherOption)
+	}
+	if e.newPage {
+		buffer.WriteString(blockLayoutOptionSeparator)
+		buffer.WriteString(BlockLayoutNewPageOption)
+	}
+	return buffer.String()
+}
+
 // EnsureValidity checks the current settings for validity and if they aren't valid, makes them so.
 func (b *BlockLayout) EnsureValidity() {
 	var layout []string
-	remaining := CreateFullKeySet()
+	remaining := b.keySet()
 	for _, line := range b.Layout {
 		var parts []string
 		for _, part := range strings.Split(strings.ToLower(xstrings.CollapseSpaces(line)), " ") {
-			part = mapOldLayoutKeys(part)
-			if remaining[part] {
-				delete(remaining, part)
-				parts = append(parts, part)
+			if entry, _ := parseBlockLayoutPart(part); remaining[entry.key] {
+				delete(remaining, entry.key)
+				parts = append(parts, entry.String())
 				if len(parts) > 1 {
 					break
 				}
@@ -121,7 +287,7 @@ func (b *BlockLayout) EnsureValidity() {
 		}
 	}
 	if len(remaining) != 0 {
-		for _, k := range allBlockLayoutKeys {
+		for _, k := range b.allKeys() {
 			if remaining[k] {
 				layout = append(layout, k)
 			}
@@ -133,14 +299,13 @@ func (b *BlockLayout) EnsureValidity() {
 // ByRow breaks the layout down into rows.
 func (b *BlockLayout) ByRow() [][]string {
 	var layout [][]string
-	remaining := CreateFullKeySet()
+	remaining := b.keySet()
 	for _, line := range b.Layout {
 		var parts []string
 		for _, part := range strings.Split(strings.ToLower(xstrings.CollapseSpaces(line)), " ") {
-			part = mapOldLayoutKeys(part)
-			if remaining[part] {
-				delete(remaining, part)
-				parts = append(parts, part)
+			if entry, _ := parseBlockLayoutPart(part); remaining[entry.key] {
+				delete(remaining, entry.key)
+				parts = append(parts, entry.key)
 			}
 		}
 		if len(parts) != 0 {
@@ -148,7 +313,7 @@ func (b *BlockLayout) ByRow() [][]string {
 		}
 	}
 	if len(remaining) != 0 {
-		for _, k := range allBlockLayoutKeys {
+		for _, k := range b.allKeys() {
 			if remaining[k] {
 				layout = append(layout, []string{k})
 			}
@@ -157,15 +322,190 @@ func (b *BlockLayout) ByRow() [][]string {
 	return layout
 }
 
+// Columns returns the number of columns the content of the block with the given key flows into on a printed page.
+func (b *BlockLayout) Columns(key string) int {
+	return b.entry(key).columns
+}
+
+// SetColumns sets the number of columns the content of the block with the given key flows into on a printed page.
+// Blocks that don't support multiple columns are left alone.
+func (b *BlockLayout) SetColumns(key string, columns int) {
+	if columns > 1 && !BlockLayoutKeySupportsColumns(key) {
+		return
+	}
+	b.updateEntry(key, func(entry *blockLayoutEntry) { entry.columns = min(max(columns, 1), MaxBlockLayoutColumns) })
+}
+
+// MinimumEntries returns the number of entries the list block with the given key must have for it to be shown. A value
+// of 0 means it is shown regardless.
+func (b *BlockLayout) MinimumEntries(key string) int {
+	return b.entry(key).minimum
+}
+
+// SetMinimumEntries sets the number of entries the list block with the given key must have for it to be shown. Blocks
+// that aren't lists are left alone.
+func (b *BlockLayout) SetMinimumEntries(key string, minimum int) {
+	if !BlockLayoutKeyIsList(key) {
+		return
+	}
+	b.updateEntry(key, func(entry *blockLayoutEntry) { entry.minimum = max(minimum, 0) })
+}
+
+// KeepTogether returns true if the block with the given key should be moved to the next printed page rather than be
+// split across pages, when possible.
+func (b *BlockLayout) KeepTogether(key string) bool {
+	return b.entry(key).keepTogether
+}
+
+// SetKeepTogether sets whether the block with the given key should be kept together on a printed page.
+func (b *BlockLayout) SetKeepTogether(key string, keepTogether bool) {
+	b.updateEntry(key, func(entry *blockLayoutEntry) { entry.keepTogether = keepTogether })
+}
+
+// StartsNewPage returns true if the row holding the block with the given key should start a new printed page.
+func (b *BlockLayout) StartsNewPage(key string) bool {
+	return b.entry(key).newPage
+}
+
+// SetStartsNewPage sets whether the row holding the block with the given key should start a new printed page.
+func (b *BlockLayout) SetStartsNewPage(key string, newPage bool) {
+	b.updateEntry(key, func(entry *blockLayoutEntry) { entry.newPage = newPage })
+}
+
+func (b *BlockLayout) entry(key string) blockLayoutEntry {
+	for _, line := range b.Layout {
+		for _, part := range strings.Split(strings.ToLower(xstrings.CollapseSpaces(line)), " ") {
+			if entry, _ := parseBlockLayoutPart(part); entry.key == key {
+				return entry
+			}
+		}
+	}
+	return blockLayoutEntry{key: key, columns: 1}
+}
+
+func (b *BlockLayout) updateEntry(key string, update func(entry *blockLayoutEntry)) {
+	m := b.entriesByKey()
+	entry, ok := m[key]
+	if !ok {
+		return
+	}
+	update(&entry)
+	m[key] = entry
+	b.setRows(b.ByRow(), m)
+}
+
+func (b *BlockLayout) entriesByKey() map[string]blockLayoutEntry {
+	m := make(map[string]blockLayoutEntry)
+	for _, row := range b.ByRow() {
+		for _, key := range row {
+			m[key] = b.entry(key)
+		}
+	}
+	return m
+}
+
+// CustomKeys returns the keys of the custom blocks that may be placed in the layout.
+func (b *BlockLayout) CustomKeys() []string {
+	return b.customKeys
+}
+
+// SetCustomKeys sets the keys of the custom blocks that may be placed in the layout. Call EnsureValidity() afterward to
+// add new blocks to the layout and drop removed ones from it.
+func (b *BlockLayout) SetCustomKeys(keys []string) {
+	b.customKeys = slices.Clone(keys)
+}
+
+func (b *BlockLayout) keySet() map[string]bool {
+	m := CreateFullKeySet()
+	for _, one := range b.customKeys {
+		m[one] = true
+	}
+	return m
+}
+
+func (b *BlockLayout) allKeys() []string {
+	return append(slices.Clone(allBlockLayoutKeys), b.customKeys...)
+}
+
 func (b *BlockLayout) String() string {
 	var buffer strings.Builder
 	for _, row := range b.ByRow() {
-		buffer.WriteString(strings.Join(row, " "))
+		for i, key := range row {
+			if i != 0 {
+				buffer.WriteByte(' ')
+			}
+			buffer.WriteString(b.entry(key).String())
+		}
 		buffer.WriteByte('\n')
 	}
 	return strings.TrimSpace(buffer.String())
 }
 
+// MoveToNewRow moves the block with the given key into a row of its own, placed before the row at the given index of
+// ByRow(). An index at or past the end places the new row last.
+func (b *BlockLayout) MoveToNewRow(key string, row int) {
+	rows, ok := b.rowsWithout(key)
+	if !ok {
+		return
+	}
+	row = max(min(row, len(rows)), 0)
+	b.setRows(slices.Insert(rows, row, []string{key}), b.entriesByKey())
+}
+
+// MoveBeside moves the block with the given key into the same row as the block with the other key, which must not
+// already be sharing its row with a third block. If left is true, the block is placed to the left of the other block.
+func (b *BlockLayout) MoveBeside(key, other string, left bool) {
+	if key == other {
+		return
+	}
+	rows, ok := b.rowsWithout(key)
+	if !ok {
+		return
+	}
+	for i, row := range rows {
+		if len(row) == 1 && row[0] == other {
+			if left {
+				rows[i] = []string{key, other}
+			} else {
+				rows[i] = []string{other, key}
+			}
+			b.setRows(rows, b.entriesByKey())
+			return
+		}
+	}
+}
+
+// rowsWithout returns the rows of the layout with the key removed. Rows are left in place, even if empty, so that
+// their indexes still match those of ByRow().
+func (b *BlockLayout) rowsWithout(key string) (rows [][]string, found bool) {
+	rows = b.ByRow()
+	for i, row := range rows {
+		if j := slices.Index(row, key); j != -1 {
+			rows[i] = slices.Delete(row, j, j+1)
+			found = true
+		}
+	}
+	return rows, found
+}
+
+func (b *BlockLayout) setRows(rows [][]string, entries map[string]blockLayoutEntry) {
+	layout := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != 0 {
+			parts := make([]string, len(row))
+			for i, key := range row {
+				if entry, ok := entries[key]; ok {
+					parts[i] = entry.String()
+				} else {
+					parts[i] = key
+				}
+			}
+			layout = append(layout, strings.Join(parts, " "))
+		}
+	}
+	b.Layout = layout
+}
+
 // MarshalJSONTo implements json.MarshalerTo.
 func (b *BlockLayout) MarshalJSONTo(enc *jsontext.Encoder) error {
 	return json.MarshalEncode(enc, &b.Layout)
@@ -177,6 +517,17 @@ func (b *BlockLayout) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 	if err := json.UnmarshalDecode(dec, &b.Layout); err != nil {
 		return err
 	}
+	// The custom blocks aren't known yet, so keep any that are referenced. The owning SheetSettings will prune those
+	// that don't exist once it has loaded them.
+	b.customKeys = nil
+	for _, line := range b.Layout {
+		for _, part := range strings.Split(strings.ToLower(xstrings.CollapseSpaces(line)), " ") {
+			if entry, _ := parseBlockLayoutPart(part); strings.HasPrefix(entry.key, CustomBlockKeyPrefix) &&
+				!slices.Contains(b.customKeys, entry.key) {
+				b.customKeys = append(b.customKeys, entry.key)
+			}
+		}
+	}
 	if len(b.Layout) == 0 {
 		b.Reset()
 	} else {
@@ -190,6 +541,7 @@ func (b *BlockLayout) Clone() *BlockLayout {
 	clone := *b
 	clone.Layout = make([]string, len(b.Layout))
 	copy(clone.Layout, b.Layout)
+	clone.customKeys = slices.Clone(b.customKeys)
 	return &clone
 }
 
@@ -197,14 +549,22 @@ func (b *BlockLayout) Clone() *BlockLayout {
 func (b *BlockLayout) Reset() {
 	b.Layout = []string{
 		BlockLayoutReactionsKey + " " + BlockLayoutConditionalModifiersKey,
+		BlockLayoutReactionSummaryKey,
+		BlockLayoutPoolTrackersKey,
+		BlockLayoutWoundsKey,
+		BlockLayoutConditionsKey,
+		BlockLayoutGrapplingKey,
+		BlockLayoutFatigueKey,
 		BlockLayoutMeleeKey,
 		BlockLayoutRangedKey,
 		BlockLayoutTraitsKey + " " + BlockLayoutSkillsKey,
 		BlockLayoutSpellsKey,
+		BlockLayoutGrimoireKey,
 		BlockLayoutEquipmentKey,
 		BlockLayoutOtherEquipmentKey,
 		BlockLayoutNotesKey,
 	}
+	b.Layout = append(b.Layout, b.customKeys...)
 }
 
 // CreateFullKeySet creates a map that contains each of the possible block layout keys.
@@ -219,23 +579,24 @@ func CreateFullKeySet() map[string]bool {
 // HTMLGridTemplate returns the text for the HTML grid layout.
 func (b *BlockLayout) HTMLGridTemplate() string {
 	var buffer strings.Builder
-	remaining := CreateFullKeySet()
+	remaining := b.keySet()
 	for _, line := range b.Layout {
 		parts := strings.Split(strings.ToLower(xstrings.CollapseSpaces(line)), " ")
-		part := mapOldLayoutKeys(parts[0])
+		first, _ := parseBlockLayoutPart(parts[0])
+		part := first.key
 		if part != "" && remaining[part] {
 			delete(remaining, part)
 			if len(parts) > 1 {
-				if part2 := mapOldLayoutKeys(parts[1]); remaining[part2] {
-					delete(remaining, part2)
-					appendToGridTemplate(&buffer, part, part2)
+				if second, _ := parseBlockLayoutPart(parts[1]); remaining[second.key] {
+					delete(remaining, second.key)
+					appendToGridTemplate(&buffer, part, second.key)
 					continue
 				}
 			}
 			appendToGridTemplate(&buffer, part, part)
 		}
 	}
-	for _, k := range allBlockLayoutKeys {
+	for _, k := range b.allKeys() {
 		if remaining[k] {
 			appendToGridTemplate(&buffer, k, k)
 		}
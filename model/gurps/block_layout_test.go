@@ -0,0 +1,135 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestBlockLayoutMoves(t *testing.T) {
+	c := check.New(t)
+	b := NewBlockLayout()
+	rows := len(b.ByRow())
+
+	b.MoveToNewRow(BlockLayoutSkillsKey, 0)
+	c.Equal([]string{BlockLayoutSkillsKey}, b.ByRow()[0])
+	c.Equal(rows+1, len(b.ByRow()))
+	c.True(slices.ContainsFunc(b.ByRow(), func(row []string) bool {
+		return slices.Equal(row, []string{BlockLayoutTraitsKey})
+	}))
+
+	b.MoveBeside(BlockLayoutTraitsKey, BlockLayoutSkillsKey, true)
+	c.Equal([]string{BlockLayoutTraitsKey, BlockLayoutSkillsKey}, b.ByRow()[0])
+	c.Equal(rows, len(b.ByRow()))
+
+	// A row that already holds two blocks can't take a third
+	b.MoveBeside(BlockLayoutNotesKey, BlockLayoutSkillsKey, false)
+	c.Equal([]string{BlockLayoutTraitsKey, BlockLayoutSkillsKey}, b.ByRow()[0])
+
+	b.MoveToNewRow(BlockLayoutTraitsKey, 1000)
+	last := b.ByRow()[len(b.ByRow())-1]
+	c.Equal([]string{BlockLayoutTraitsKey}, last)
+	c.Equal([]string{BlockLayoutSkillsKey}, b.ByRow()[0])
+
+	layout, valid := NewBlockLayoutFromString(b.String())
+	c.True(valid)
+	c.Equal(b.Layout, layout.Layout)
+}
+
+func TestBlockLayoutColumns(t *testing.T) {
+	c := check.New(t)
+	b := NewBlockLayout()
+	c.Equal(1, b.Columns(BlockLayoutSkillsKey))
+
+	b.SetColumns(BlockLayoutSkillsKey, 2)
+	c.Equal(2, b.Columns(BlockLayoutSkillsKey))
+	c.True(slices.ContainsFunc(b.ByRow(), func(row []string) bool {
+		return slices.Equal(row, []string{BlockLayoutTraitsKey, BlockLayoutSkillsKey})
+	}), "rows are reported without the column counts")
+	c.True(slices.Contains(b.Layout, BlockLayoutTraitsKey+" "+BlockLayoutSkillsKey+":2"))
+
+	// Columns survive being moved around
+	b.MoveToNewRow(BlockLayoutSkillsKey, 0)
+	c.Equal(2, b.Columns(BlockLayoutSkillsKey))
+	c.Equal(BlockLayoutSkillsKey+":2", b.Layout[0])
+
+	// Only list blocks may have more than one column, and no more than the maximum
+	b.SetColumns(BlockLayoutNotesKey, 2)
+	c.Equal(1, b.Columns(BlockLayoutNotesKey))
+	b.SetColumns(BlockLayoutSpellsKey, 10)
+	c.Equal(MaxBlockLayoutColumns, b.Columns(BlockLayoutSpellsKey))
+
+	layout, valid := NewBlockLayoutFromString(b.String())
+	c.True(valid)
+	c.Equal(b.Layout, layout.Layout)
+
+	layout, valid = NewBlockLayoutFromString("skills:4\nnotes:2")
+	c.False(valid)
+	c.Equal(1, layout.Columns(BlockLayoutSkillsKey))
+	c.Equal(1, layout.Columns(BlockLayoutNotesKey))
+	c.Equal([]string{BlockLayoutSkillsKey}, layout.ByRow()[0])
+}
+
+func TestBlockLayoutPageBreaks(t *testing.T) {
+	c := check.New(t)
+	b := NewBlockLayout()
+	c.False(b.KeepTogether(BlockLayoutMeleeKey))
+	c.False(b.StartsNewPage(BlockLayoutMeleeKey))
+
+	b.SetKeepTogether(BlockLayoutMeleeKey, true)
+	b.SetStartsNewPage(BlockLayoutSkillsKey, true)
+	b.SetColumns(BlockLayoutSkillsKey, 3)
+	c.True(b.KeepTogether(BlockLayoutMeleeKey))
+	c.True(b.StartsNewPage(BlockLayoutSkillsKey))
+	c.Equal(3, b.Columns(BlockLayoutSkillsKey))
+	c.True(slices.Contains(b.Layout, BlockLayoutMeleeKey+":keep"))
+	c.True(slices.Contains(b.Layout, BlockLayoutTraitsKey+" "+BlockLayoutSkillsKey+":3:newpage"))
+
+	b.SetKeepTogether(BlockLayoutMeleeKey, false)
+	c.False(b.KeepTogether(BlockLayoutMeleeKey))
+	c.True(slices.Contains(b.Layout, BlockLayoutMeleeKey))
+
+	layout, valid := NewBlockLayoutFromString("wounds:newpage:keep\nnotes:bogus")
+	c.False(valid)
+	c.True(layout.KeepTogether(BlockLayoutWoundsKey))
+	c.True(layout.StartsNewPage(BlockLayoutWoundsKey))
+	c.Equal(BlockLayoutWoundsKey+":keep:newpage", layout.Layout[0])
+	c.Equal(BlockLayoutNotesKey, layout.Layout[1])
+	c.Equal([]string{BlockLayoutWoundsKey}, layout.ByRow()[0])
+}
+
+func TestBlockLayoutMinimumEntries(t *testing.T) {
+	c := check.New(t)
+	b := NewBlockLayout()
+	c.Equal(0, b.MinimumEntries(BlockLayoutSpellsKey))
+
+	b.SetMinimumEntries(BlockLayoutSpellsKey, 1)
+	b.SetMinimumEntries(BlockLayoutReactionsKey, 3)
+	c.Equal(1, b.MinimumEntries(BlockLayoutSpellsKey))
+	c.Equal(3, b.MinimumEntries(BlockLayoutReactionsKey))
+	c.Equal(BlockLayoutReactionsKey+":min=3 "+BlockLayoutConditionalModifiersKey, b.Layout[0])
+
+	// Only list blocks may be hidden based on their entries
+	b.SetMinimumEntries(BlockLayoutWoundsKey, 2)
+	c.Equal(0, b.MinimumEntries(BlockLayoutWoundsKey))
+
+	layout, valid := NewBlockLayoutFromString(b.String())
+	c.True(valid)
+	c.Equal(b.Layout, layout.Layout)
+
+	layout, valid = NewBlockLayoutFromString("skills:2:min=1:keep\nwounds:min=1\nnotes:min=x")
+	c.False(valid)
+	c.Equal(BlockLayoutSkillsKey+":2:min=1:keep", layout.Layout[0])
+	c.Equal(BlockLayoutWoundsKey, layout.Layout[1])
+	c.Equal(BlockLayoutNotesKey, layout.Layout[2])
+}
@@ -207,6 +207,19 @@ func (b *Body) UniqueHitLocations(entity *Entity) []*HitLocation {
 	return locations
 }
 
+// LocationForRoll returns the HitLocation in this table that corresponds to the given roll total, or nil if none does.
+// Sub-tables are not consulted.
+func (b *Body) LocationForRoll(total int) *HitLocation {
+	start := b.Roll.Minimum(false)
+	for _, loc := range b.Locations {
+		if loc.Slots > 0 && total >= start && total < start+loc.Slots {
+			return loc
+		}
+		start += loc.Slots
+	}
+	return nil
+}
+
 // LookupLocationByID returns the HitLocation that matches the given ID.
 func (b *Body) LookupLocationByID(entity *Entity, idStr string) *HitLocation {
 	if len(b.locationLookup) == 0 {
@@ -0,0 +1,26 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestLocationForRoll(t *testing.T) {
+	c := check.New(t)
+	b := FactoryBody()
+	c.Equal("skull", b.LocationForRoll(3).ID())
+	c.Equal("torso", b.LocationForRoll(10).ID())
+	c.Equal("neck", b.LocationForRoll(18).ID())
+	c.Nil(b.LocationForRoll(2))
+	c.Nil(b.LocationForRoll(19))
+}
@@ -14,7 +14,11 @@ import (
 	"encoding/json/v2"
 	"hash"
 	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
 
+	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/jio"
 	"github.com/richardwilkes/gcs/v5/model/kinds"
 	"github.com/richardwilkes/toolbox/v2/errs"
@@ -28,17 +32,24 @@ type Campaign struct {
 
 // CampaignData holds the campaign file data.
 type CampaignData struct {
-	Version       int            `json:"version"`
-	ID            tid.TID        `json:"id"`
-	SheetSettings *SheetSettings `json:"settings,omitzero"`
-	Traits        []*Trait       `json:"traits,omitzero"`
-	Skills        []*Skill       `json:"skills,omitzero"`
-	Spells        []*Spell       `json:"spells,omitzero"`
-	Equipment     []*Equipment   `json:"equipment,omitzero"`
-	Notes         []*Note        `json:"notes,omitzero"`
-	Templates     []*Template    `json:"templates,omitzero"`
-	Characters    []*Entity      `json:"characters,omitzero"`
-	Documents     []*Document    `json:"documents,omitzero"`
+	Version       int               `json:"version"`
+	ID            tid.TID           `json:"id"`
+	Name          string            `json:"name,omitzero"`
+	SheetSettings *SheetSettings    `json:"settings,omitzero"`
+	Members       []*CampaignMember `json:"members,omitzero"`
+	Libraries     []string          `json:"libraries,omitzero"`
+	Loot          string            `json:"loot,omitzero"`
+	Treasury      fxp.Int           `json:"treasury,omitzero"`
+	Notes         string            `json:"notes,omitzero"`
+	LockPassword  LockPassword      `json:"lock_password,omitzero"`
+}
+
+// CampaignMember holds a reference to a character sheet that is part of a campaign.
+type CampaignMember struct {
+	// Path is the path to the character sheet, relative to the campaign file when possible. It always uses forward
+	// slashes as the separator.
+	Path string `json:"path"`
+	NPC  bool   `json:"npc,omitzero"`
 }
 
 // NewCampaignFromFile loads a Campaign from a file.
@@ -50,6 +61,9 @@ func NewCampaignFromFile(fileSystem fs.FS, filePath string) (*Campaign, error) {
 	if err := jio.CheckVersion(campaign.Version); err != nil {
 		return nil, err
 	}
+	if campaign.SheetSettings == nil {
+		campaign.SheetSettings = GlobalSettings().SheetSettings().Clone(nil)
+	}
 	return &campaign, nil
 }
 
@@ -63,6 +77,95 @@ func NewCampaign() *Campaign {
 	}
 }
 
+// AddMember adds the character sheet at memberPath to the campaign, unless it is already a member. campaignPath is the
+// location of the campaign file and is used to store the member's path relative to it.
+func (c *Campaign) AddMember(campaignPath, memberPath string, npc bool) *CampaignMember {
+	p := relativeDocumentPath(campaignPath, memberPath)
+	for _, one := range c.Members {
+		if one.Path == p {
+			return one
+		}
+	}
+	member := &CampaignMember{Path: p, NPC: npc}
+	c.Members = append(c.Members, member)
+	return member
+}
+
+// PCs returns the members that are player characters.
+func (c *Campaign) PCs() []*CampaignMember {
+	var pcs []*CampaignMember
+	for _, one := range c.Members {
+		if !one.NPC {
+			pcs = append(pcs, one)
+		}
+	}
+	return pcs
+}
+
+// RemoveMember removes the member from the campaign.
+func (c *Campaign) RemoveMember(member *CampaignMember) {
+	c.Members = slices.DeleteFunc(c.Members, func(one *CampaignMember) bool { return one == member })
+}
+
+// ApplySettingsTo replaces the entity's sheet settings with a copy of the campaign's.
+func (c *Campaign) ApplySettingsTo(e *Entity) {
+	e.SheetSettings = c.SheetSettings.Clone(e)
+	e.SheetSettings.SetOwningEntity(e)
+	e.Recalculate()
+}
+
+// ApplySettingsToFile loads the character sheet at the given path, replaces its sheet settings with a copy of the
+// campaign's, then saves it.
+func (c *Campaign) ApplySettingsToFile(sheetPath string) error {
+	e, err := NewEntityFromFile(os.DirFS(filepath.Dir(sheetPath)), filepath.Base(sheetPath))
+	if err != nil {
+		return err
+	}
+	c.ApplySettingsTo(e)
+	return e.Save(sheetPath)
+}
+
+// SetSheetLock locks or unlocks the entity. Sheets locked this way are protected by the campaign's lock password, if
+// it has one, while unlocking them also removes any password they were protected by.
+func (c *Campaign) SetSheetLock(e *Entity, locked bool) {
+	e.Locked = locked
+	if locked {
+		e.LockPassword = c.LockPassword
+	} else {
+		e.LockPassword = ""
+	}
+}
+
+// SetSheetLockOnFile loads the character sheet at the given path, locks or unlocks it as SetSheetLock does, then saves
+// it.
+func (c *Campaign) SetSheetLockOnFile(sheetPath string, locked bool) error {
+	e, err := NewEntityFromFile(os.DirFS(filepath.Dir(sheetPath)), filepath.Base(sheetPath))
+	if err != nil {
+		return err
+	}
+	c.SetSheetLock(e, locked)
+	return e.Save(sheetPath)
+}
+
+// SetLootPath sets the loot sheet used as the party's shared loot list. campaignPath is the location of the campaign
+// file and is used to store the loot sheet's path relative to it. Pass an empty lootPath to clear it.
+func (c *Campaign) SetLootPath(campaignPath, lootPath string) {
+	if lootPath == "" {
+		c.Loot = ""
+	} else {
+		c.Loot = relativeDocumentPath(campaignPath, lootPath)
+	}
+}
+
+// LootPath returns the full path to the party's shared loot sheet, or an empty string if there isn't one.
+// campaignPath is the location of the campaign file.
+func (c *Campaign) LootPath(campaignPath string) string {
+	if c.Loot == "" {
+		return ""
+	}
+	return absoluteDocumentPath(campaignPath, c.Loot)
+}
+
 // Save the Campaign to a file as JSON.
 func (c *Campaign) Save(filePath string) error {
 	return jio.SaveToFile(filePath, c)
@@ -80,3 +183,27 @@ func (c *Campaign) Hash(h hash.Hash) {
 		errs.Log(err)
 	}
 }
+
+// AbsolutePath returns the full path to the member's character sheet. campaignPath is the location of the campaign file.
+func (m *CampaignMember) AbsolutePath(campaignPath string) string {
+	return absoluteDocumentPath(campaignPath, m.Path)
+}
+
+// relativeDocumentPath returns p relative to the directory holding the document at docPath, if possible, using forward
+// slashes as the separator.
+func relativeDocumentPath(docPath, p string) string {
+	if rel, err := filepath.Rel(filepath.Dir(docPath), p); err == nil {
+		p = rel
+	}
+	return filepath.ToSlash(p)
+}
+
+// absoluteDocumentPath returns the full path for p, which is relative to the directory holding the document at docPath
+// unless it is already absolute.
+func absoluteDocumentPath(docPath, p string) string {
+	p = filepath.FromSlash(p)
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(filepath.Dir(docPath), p)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestCampaignMembers(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	campaignPath := filepath.Join(dir, "game"+CampaignExt)
+	sheetPath := filepath.Join(dir, "pcs", "hero"+SheetExt)
+	campaign := NewCampaign()
+	member := campaign.AddMember(campaignPath, sheetPath, false)
+	c.Equal("pcs/hero"+SheetExt, member.Path)
+	c.Equal(sheetPath, member.AbsolutePath(campaignPath))
+	c.Equal(member, campaign.AddMember(campaignPath, sheetPath, true))
+	c.Equal(1, len(campaign.Members))
+	campaign.RemoveMember(member)
+	c.Equal(0, len(campaign.Members))
+}
+
+func TestCampaignApplySettingsToFile(t *testing.T) {
+	c := check.New(t)
+	sheetPath := filepath.Join(t.TempDir(), "hero"+SheetExt)
+	c.NoError(NewEntity().Save(sheetPath))
+	campaign := NewCampaign()
+	campaign.SheetSettings.UseHalfStatDefaults = !campaign.SheetSettings.UseHalfStatDefaults
+	c.NoError(campaign.ApplySettingsToFile(sheetPath))
+	e, err := NewEntityFromFile(os.DirFS(filepath.Dir(sheetPath)), filepath.Base(sheetPath))
+	c.NoError(err)
+	c.Equal(campaign.SheetSettings.UseHalfStatDefaults, e.SheetSettings.UseHalfStatDefaults)
+}
+
+func TestCampaignLootPath(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	campaignPath := filepath.Join(dir, "game"+CampaignExt)
+	lootPath := filepath.Join(dir, "party"+LootExt)
+	campaign := NewCampaign()
+	c.Equal("", campaign.LootPath(campaignPath))
+	campaign.SetLootPath(campaignPath, lootPath)
+	c.Equal("party"+LootExt, campaign.Loot)
+	c.Equal(lootPath, campaign.LootPath(campaignPath))
+	campaign.SetLootPath(campaignPath, "")
+	c.Equal("", campaign.Loot)
+}
+
+func TestCampaignSetSheetLockOnFile(t *testing.T) {
+	c := check.New(t)
+	sheetPath := filepath.Join(t.TempDir(), "hero"+SheetExt)
+	c.NoError(NewEntity().Save(sheetPath))
+	campaign := NewCampaign()
+	var err error
+	campaign.LockPassword, err = NewLockPassword("secret")
+	c.NoError(err)
+	c.NoError(campaign.SetSheetLockOnFile(sheetPath, true))
+	e, err := NewEntityFromFile(os.DirFS(filepath.Dir(sheetPath)), filepath.Base(sheetPath))
+	c.NoError(err)
+	c.True(e.Locked)
+	c.False(e.Unlock("wrong"))
+	c.True(e.Locked)
+	c.NoError(campaign.SetSheetLockOnFile(sheetPath, false))
+	e, err = NewEntityFromFile(os.DirFS(filepath.Dir(sheetPath)), filepath.Base(sheetPath))
+	c.NoError(err)
+	c.False(e.Locked)
+	c.Equal(LockPassword(""), e.LockPassword)
+}
@@ -51,6 +51,9 @@ type CellData struct {
 	UnsatisfiedReason string
 	TemplateInfo      string
 	InlineTag         string
+	LinkedSheet       string
+	CapacityFill      string
+	CapacityProblem   string
 }
 
 // ForSort returns a string that can be used to sort or search against for this data.
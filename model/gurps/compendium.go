@@ -0,0 +1,123 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"path/filepath"
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// Compendium holds the distinct traits, skills, spells, and equipment used by one or more entities, suitable for
+// saving as a set of library files.
+type Compendium struct {
+	Traits    []*Trait
+	Skills    []*Skill
+	Spells    []*Spell
+	Equipment []*Equipment
+}
+
+type compendiumItem[T any] interface {
+	NodeTypes
+	SrcProvider
+	Clone(from LibraryFile, owner DataOwner, parent T, preserveID bool) T
+}
+
+type compendiumKey struct {
+	source Source
+	hash   uint64
+}
+
+// NewCompendium creates a new Compendium from the items used by the entities. Containers are not included, only their
+// contents. Items that came from the same library source, or that are otherwise identical, are only included once.
+func NewCompendium(entities ...*Entity) *Compendium {
+	var c Compendium
+	traits := make(map[compendiumKey]bool)
+	skills := make(map[compendiumKey]bool)
+	spells := make(map[compendiumKey]bool)
+	equipment := make(map[compendiumKey]bool)
+	for _, entity := range entities {
+		c.Traits = addToCompendium(traits, c.Traits, entity.Traits...)
+		c.Skills = addToCompendium(skills, c.Skills, entity.Skills...)
+		c.Spells = addToCompendium(spells, c.Spells, entity.Spells...)
+		c.Equipment = addToCompendium(equipment, c.Equipment, entity.CarriedEquipment...)
+		c.Equipment = addToCompendium(equipment, c.Equipment, entity.OtherEquipment...)
+	}
+	sortCompendiumItems(c.Traits)
+	sortCompendiumItems(c.Skills)
+	sortCompendiumItems(c.Spells)
+	sortCompendiumItems(c.Equipment)
+	return &c
+}
+
+// Empty returns true if the compendium has no content.
+func (c *Compendium) Empty() bool {
+	return len(c.Traits) == 0 && len(c.Skills) == 0 && len(c.Spells) == 0 && len(c.Equipment) == 0
+}
+
+// Save writes a library file for each type of item the compendium contains into the directory, using baseName plus the
+// appropriate extension for each file's name. Returns the paths of the files that were written.
+func (c *Compendium) Save(dir, baseName string) ([]string, error) {
+	var paths []string
+	base := filepath.Join(dir, baseName)
+	if len(c.Traits) != 0 {
+		p := base + TraitsExt
+		if err := SaveTraits(c.Traits, p); err != nil {
+			return paths, err
+		}
+		paths = append(paths, p)
+	}
+	if len(c.Skills) != 0 {
+		p := base + SkillsExt
+		if err := SaveSkills(c.Skills, p); err != nil {
+			return paths, err
+		}
+		paths = append(paths, p)
+	}
+	if len(c.Spells) != 0 {
+		p := base + SpellsExt
+		if err := SaveSpells(c.Spells, p); err != nil {
+			return paths, err
+		}
+		paths = append(paths, p)
+	}
+	if len(c.Equipment) != 0 {
+		p := base + EquipmentExt
+		if err := SaveEquipment(c.Equipment, p); err != nil {
+			return paths, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+func addToCompendium[T compendiumItem[T]](seen map[compendiumKey]bool, list []T, data ...T) []T {
+	Traverse(func(one T) bool {
+		key := compendiumKey{source: one.GetSource()}
+		if key.source.IsZero() {
+			key.source = Source{}
+			key.hash = Hash64(one)
+		}
+		if !seen[key] {
+			seen[key] = true
+			var parent T
+			list = append(list, one.Clone(LibraryFile{}, nil, parent, false))
+		}
+		return false
+	}, false, true, data...)
+	return list
+}
+
+func sortCompendiumItems[T NodeTypes](list []T) {
+	slices.SortStableFunc(list, func(a, b T) int {
+		return xstrings.NaturalCmp(AsNode(a).String(), AsNode(b).String(), true)
+	})
+}
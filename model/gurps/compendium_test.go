@@ -0,0 +1,57 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestNewCompendium(t *testing.T) {
+	c := check.New(t)
+	src := Source{
+		LibraryFile: LibraryFile{
+			Library: "richardwilkes/gcs_master_library",
+			Path:    "Basic Set/Basic Set Traits.gct",
+		},
+		TID: "tAbc",
+	}
+
+	first := NewEntity()
+	container := NewTrait(first, nil, true)
+	container.Name = "Advantages"
+	luck := NewTrait(first, container, false)
+	luck.Name = "Luck"
+	luck.Source = src
+	container.Children = []*Trait{luck}
+	first.Traits = []*Trait{container}
+	custom := NewSkill(first, nil, false)
+	custom.Name = "Underwater Basket Weaving"
+	first.Skills = []*Skill{custom}
+
+	second := NewEntity()
+	otherLuck := NewTrait(second, nil, false)
+	otherLuck.Name = "Luck (renamed)"
+	otherLuck.Source = src
+	sameCustom := NewSkill(second, nil, false)
+	sameCustom.Name = custom.Name
+	second.Traits = []*Trait{otherLuck}
+	second.Skills = []*Skill{sameCustom}
+
+	compendium := NewCompendium(first, second)
+	c.False(compendium.Empty())
+	c.Equal(1, len(compendium.Traits))
+	c.Equal("Luck", compendium.Traits[0].Name)
+	c.Equal(src, compendium.Traits[0].Source)
+	c.Equal(1, len(compendium.Skills))
+	c.Equal(0, len(compendium.Spells))
+	c.True(NewCompendium(NewEntity()).Empty())
+}
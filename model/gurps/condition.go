@@ -0,0 +1,180 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"hash"
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/criteria"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/wsel"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xhash"
+	"github.com/zeebo/xxh3"
+)
+
+var (
+	_ Hashable     = &Condition{}
+	_ LeveledOwner = &Condition{}
+)
+
+// Condition holds a temporary effect on a character, such as being stunned, in shock, suffering from an affliction, or
+// being the subject of a spell. The features of a condition are applied to the character for as long as the condition
+// remains active.
+type Condition struct {
+	Name     string   `json:"name"`
+	Notes    string   `json:"notes,omitzero"`
+	Turns    int      `json:"turns,omitzero"`
+	Features Features `json:"features,omitzero"`
+}
+
+// NewCondition creates a new condition with no features. A turns value of 0 or less means the condition lasts until
+// removed.
+func NewCondition(name string, turns int) *Condition {
+	return &Condition{
+		Name:  name,
+		Turns: max(turns, 0),
+	}
+}
+
+// NewStunnedCondition creates a new condition for being stunned, which gives -4 to all active defenses (p. B420).
+func NewStunnedCondition() *Condition {
+	c := NewCondition(i18n.Text("Stunned"), 1)
+	c.AddAttributeModifier(DodgeID, -fxp.Four)
+	parry := NewWeaponParryBonus()
+	parry.SelectionType = wsel.WithName
+	parry.NameCriteria.Compare = criteria.AnyText
+	parry.Amount = -fxp.Four
+	block := NewWeaponBlockBonus()
+	block.SelectionType = wsel.WithName
+	block.NameCriteria.Compare = criteria.AnyText
+	block.Amount = -fxp.Four
+	c.Features = append(c.Features, parry, block)
+	return c
+}
+
+// NewShockCondition creates a new condition for shock, which applies the penalty to DX and IQ until the end of the
+// character's next turn (p. B419).
+func NewShockCondition(penalty int) *Condition {
+	if penalty > 0 {
+		penalty = -penalty
+	}
+	c := NewCondition(i18n.Text("Shock"), 1)
+	c.AddAttributeModifier(DexterityID, fxp.FromInteger(penalty))
+	c.AddAttributeModifier(IntelligenceID, fxp.FromInteger(penalty))
+	return c
+}
+
+// AddAttributeModifier adds a modifier to the given attribute.
+func (c *Condition) AddAttributeModifier(attrID string, amount fxp.Int) {
+	bonus := NewAttributeBonus(attrID)
+	bonus.Amount = amount
+	c.Features = append(c.Features, bonus)
+}
+
+// Clone creates a copy of this condition.
+func (c *Condition) Clone() *Condition {
+	other := *c
+	other.Features = c.Features.Clone()
+	return &other
+}
+
+// CloneConditionList creates a clone of the provided Condition list.
+func CloneConditionList(list []*Condition) []*Condition {
+	clone := make([]*Condition, len(list))
+	for i, one := range list {
+		clone[i] = one.Clone()
+	}
+	return clone
+}
+
+// HashConditions returns a hash value for the provided Condition list.
+func HashConditions(list []*Condition) uint64 {
+	h := xxh3.New()
+	xhash.Num64(h, len(list))
+	for _, one := range list {
+		one.Hash(h)
+	}
+	return h.Sum64()
+}
+
+// Hash writes this object's contents into the hasher.
+func (c *Condition) Hash(h hash.Hash) {
+	xhash.StringWithLen(h, c.Name)
+	xhash.StringWithLen(h, c.Notes)
+	xhash.Num64(h, c.Turns)
+	xhash.Num64(h, len(c.Features))
+	for _, f := range c.Features {
+		f.Hash(h)
+	}
+}
+
+// String implements fmt.Stringer.
+func (c *Condition) String() string {
+	return c.Name
+}
+
+// IsLeveled implements LeveledOwner.
+func (c *Condition) IsLeveled() bool {
+	return false
+}
+
+// CurrentLevel implements LeveledOwner.
+func (c *Condition) CurrentLevel() fxp.Int {
+	return 0
+}
+
+// Timed returns true if the condition expires on its own after a number of turns.
+func (c *Condition) Timed() bool {
+	return c.Turns > 0
+}
+
+// AddCondition adds a condition to the entity and recalculates.
+func (e *Entity) AddCondition(c *Condition) {
+	e.Conditions = append(e.Conditions, c)
+	e.Recalculate()
+}
+
+// RemoveCondition removes a condition from the entity and recalculates.
+func (e *Entity) RemoveCondition(c *Condition) {
+	if i := slices.Index(e.Conditions, c); i != -1 {
+		e.Conditions = slices.Delete(e.Conditions, i, i+1)
+		e.Recalculate()
+	}
+}
+
+// SetConditions replaces the entity's conditions and recalculates.
+func (e *Entity) SetConditions(list []*Condition) {
+	e.Conditions = CloneConditionList(list)
+	e.Recalculate()
+}
+
+// AdvanceConditions advances the timed conditions by the given number of turns, removing any that have expired. The
+// expired conditions are returned.
+func (e *Entity) AdvanceConditions(turns int) []*Condition {
+	if turns < 1 {
+		return nil
+	}
+	var expired []*Condition
+	e.Conditions = slices.DeleteFunc(e.Conditions, func(c *Condition) bool {
+		if !c.Timed() {
+			return false
+		}
+		c.Turns -= turns
+		if c.Turns <= 0 {
+			expired = append(expired, c)
+			return true
+		}
+		return false
+	})
+	e.Recalculate()
+	return expired
+}
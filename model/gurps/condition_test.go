@@ -0,0 +1,49 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestConditions(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.Recalculate()
+	dx := e.Attributes.Set[DexterityID].Current()
+	iq := e.Attributes.Set[IntelligenceID].Current()
+
+	shock := NewShockCondition(2)
+	e.AddCondition(shock)
+	c.Equal(dx-fxp.Two, e.Attributes.Set[DexterityID].Current())
+	c.Equal(iq-fxp.Two, e.Attributes.Set[IntelligenceID].Current())
+
+	spell := NewCondition("Might", 3)
+	spell.AddAttributeModifier(StrengthID, fxp.One)
+	e.AddCondition(spell)
+	lasting := NewCondition("Curse", 0)
+	e.AddCondition(lasting)
+
+	expired := e.AdvanceConditions(1)
+	c.Equal([]*Condition{shock}, expired)
+	c.Equal([]*Condition{spell, lasting}, e.Conditions)
+	c.Equal(2, spell.Turns)
+	c.Equal(dx, e.Attributes.Set[DexterityID].Current())
+
+	expired = e.AdvanceConditions(5)
+	c.Equal([]*Condition{spell}, expired)
+	c.Equal([]*Condition{lasting}, e.Conditions)
+
+	e.RemoveCondition(lasting)
+	c.Equal(0, len(e.Conditions))
+}
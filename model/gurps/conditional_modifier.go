@@ -32,14 +32,19 @@ var _ Node[*ConditionalModifier] = &ConditionalModifier{}
 const (
 	ConditionalModifierValueColumn = iota
 	ConditionalModifierDescriptionColumn
+	ConditionalModifierActiveColumn
 )
 
-// ConditionalModifier holds data for a reaction or conditional modifier.
+// ConditionalModifier holds data for a reaction or conditional modifier. Adjustable is true if at least one of the
+// sources applies its amount to an attribute while the modifier is Active.
 type ConditionalModifier struct {
-	TID     tid.TID
-	From    string
-	Amounts []fxp.Int
-	Sources []string
+	TID        tid.TID
+	From       string
+	Amounts    []fxp.Int
+	Sources    []string
+	Adjustable bool
+	Active     bool
+	entity     *Entity
 }
 
 // NewConditionalModifier creates a new ConditionalModifier.
@@ -76,6 +81,19 @@ func (c *ConditionalModifier) Compare(other *ConditionalModifier) int {
 	return result
 }
 
+// Entity returns the entity this conditional modifier was collected from, if any.
+func (c *ConditionalModifier) Entity() *Entity {
+	return c.entity
+}
+
+// SetActive toggles whether the amounts of the adjustable sources are applied to the character.
+func (c *ConditionalModifier) SetActive(active bool) {
+	c.Active = active
+	if c.entity != nil {
+		c.entity.SetConditionalModifierActive(c.From, active)
+	}
+}
+
 // GetSource returns the source of this data.
 func (c *ConditionalModifier) GetSource() Source {
 	return Source{}
@@ -111,9 +129,12 @@ func (c *ConditionalModifier) Hash(h hash.Hash) {
 // Clone implements Node.
 func (c *ConditionalModifier) Clone(_ LibraryFile, _ DataOwner, _ *ConditionalModifier, preserveID bool) *ConditionalModifier {
 	clone := &ConditionalModifier{
-		From:    c.From,
-		Amounts: slices.Clone(c.Amounts),
-		Sources: slices.Clone(c.Sources),
+		From:       c.From,
+		Amounts:    slices.Clone(c.Amounts),
+		Sources:    slices.Clone(c.Sources),
+		Adjustable: c.Adjustable,
+		Active:     c.Active,
+		entity:     c.entity,
 	}
 	if preserveID {
 		clone.TID = c.TID
@@ -185,6 +206,10 @@ func ConditionalModifiersHeaderData(columnID int) HeaderData {
 	case ConditionalModifierDescriptionColumn:
 		data.Title = i18n.Text("Condition")
 		data.Primary = true
+	case ConditionalModifierActiveColumn:
+		data.Title = HeaderCheckmark
+		data.TitleIsImageKey = true
+		data.Detail = i18n.Text("Whether the conditional modifier is currently in effect. While in effect, the modifier is applied to the attributes it affects.")
 	}
 	return data
 }
@@ -223,6 +248,13 @@ func (c *ConditionalModifier) CellData(columnID int, data *CellData) {
 	case ConditionalModifierDescriptionColumn:
 		data.Type = cell.Markdown
 		data.Primary = c.From
+	case ConditionalModifierActiveColumn:
+		if c.Adjustable {
+			data.Type = cell.Toggle
+			data.Checked = c.Active
+			data.Alignment = align.Middle
+			data.Tooltip = i18n.Text("Click to toggle whether the conditional modifier is currently in effect.")
+		}
 	case PageRefCellAlias:
 		data.Type = cell.PageRef
 	}
@@ -249,3 +281,22 @@ func (c *ConditionalModifier) FillWithNameableKeys(_, _ map[string]string) {
 // ApplyNameableKeys replaces any nameable keys found with the corresponding values in the provided map.
 func (c *ConditionalModifier) ApplyNameableKeys(_ map[string]string) {
 }
+
+// ConditionalModifierActive returns true if the conditional modifier for the situation has been toggled on.
+func (e *Entity) ConditionalModifierActive(situation string) bool {
+	return slices.Contains(e.ActiveModifiers, situation)
+}
+
+// SetConditionalModifierActive toggles the conditional modifier for the situation on or off and recalculates.
+func (e *Entity) SetConditionalModifierActive(situation string, active bool) {
+	if e.ConditionalModifierActive(situation) == active {
+		return
+	}
+	if active {
+		e.ActiveModifiers = append(e.ActiveModifiers, situation)
+		slices.Sort(e.ActiveModifiers)
+	} else {
+		e.ActiveModifiers = slices.DeleteFunc(e.ActiveModifiers, func(one string) bool { return one == situation })
+	}
+	e.Recalculate()
+}
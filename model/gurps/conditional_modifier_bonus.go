@@ -22,10 +22,12 @@ import (
 
 var _ Bonus = &ConditionalModifierBonus{}
 
-// ConditionalModifierBonus holds the data for a conditional modifier bonus.
+// ConditionalModifierBonus holds the data for a conditional modifier bonus. If AppliesTo is set to an attribute ID, the
+// bonus will be added to that attribute while the conditional modifier has been toggled on for the character.
 type ConditionalModifierBonus struct {
 	Type      feature.Type `json:"type"`
 	Situation string       `json:"situation,omitzero"`
+	AppliesTo string       `json:"applies_to,omitzero"`
 	LeveledAmount
 	BonusOwner `json:"-"`
 }
@@ -55,6 +57,15 @@ func (c *ConditionalModifierBonus) FillWithNameableKeys(m, existing map[string]s
 	nameable.Extract(c.Situation, m, existing)
 }
 
+// SituationWithReplacements returns the situation with any nameable keys from the owner replaced.
+func (c *ConditionalModifierBonus) SituationWithReplacements() string {
+	var replacements map[string]string
+	if na, ok := c.Owner().(nameable.Accesser); ok {
+		replacements = na.NameableReplacements()
+	}
+	return nameable.Apply(c.Situation, replacements)
+}
+
 // SetLeveledOwner implements Bonus.
 func (c *ConditionalModifierBonus) SetLeveledOwner(owner LeveledOwner) {
 	c.LeveledOwner = owner
@@ -73,5 +84,6 @@ func (c *ConditionalModifierBonus) Hash(h hash.Hash) {
 	}
 	xhash.Num8(h, c.Type)
 	xhash.StringWithLen(h, c.Situation)
+	xhash.StringWithLen(h, c.AppliesTo)
 	c.LeveledAmount.Hash(h)
 }
@@ -0,0 +1,51 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestConditionalModifierToggle(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Combat Reflexes"
+	bonus := NewConditionalModifierBonus()
+	bonus.Situation = "when surprised"
+	bonus.AppliesTo = DexterityID
+	bonus.Amount = fxp.Two
+	note := NewConditionalModifierBonus()
+	note.Situation = "to Fright Checks"
+	trait.Features = Features{bonus, note}
+	e.SetTraitList([]*Trait{trait})
+	e.Recalculate()
+	dx := e.Attributes.Current(DexterityID)
+
+	mods := e.ConditionalModifiers()
+	c.Equal(2, len(mods))
+	c.Equal("to Fright Checks", mods[0].From)
+	c.False(mods[0].Adjustable)
+	surprised := mods[1]
+	c.True(surprised.Adjustable)
+	c.False(surprised.Active)
+
+	surprised.SetActive(true)
+	c.True(e.ConditionalModifierActive("when surprised"))
+	c.Equal(dx+fxp.Two, e.Attributes.Current(DexterityID))
+	c.True(e.ConditionalModifiers()[1].Active)
+
+	e.SetConditionalModifierActive("when surprised", false)
+	c.Equal(dx, e.Attributes.Current(DexterityID))
+	c.Equal(0, len(e.ActiveModifiers))
+}
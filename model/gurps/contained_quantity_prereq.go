@@ -66,11 +66,7 @@ func (p *ContainedQuantityPrereq) Satisfied(_ *Entity, exclude any, tooltip *xby
 	satisfied := false
 	if eqp, ok := exclude.(*Equipment); ok {
 		if satisfied = !eqp.Container(); !satisfied {
-			var qty fxp.Int
-			for _, child := range eqp.Children {
-				qty += child.Quantity
-			}
-			satisfied = p.QualifierCriteria.Matches(qty)
+			satisfied = p.QualifierCriteria.Matches(containedQuantity(eqp))
 		}
 	}
 	if !p.Has {
@@ -78,13 +74,30 @@ func (p *ContainedQuantityPrereq) Satisfied(_ *Entity, exclude any, tooltip *xby
 	}
 	if !satisfied && tooltip != nil {
 		tooltip.WriteString(prefix)
-		tooltip.WriteString(HasText(p.Has))
-		tooltip.WriteString(i18n.Text(" a contained quantity which "))
-		tooltip.WriteString(p.QualifierCriteria.String())
+		tooltip.WriteString(p.requirement())
 	}
 	return satisfied
 }
 
+func (p *ContainedQuantityPrereq) requirement() string {
+	return HasText(p.Has) + i18n.Text(" a contained quantity which ") + p.QualifierCriteria.String()
+}
+
+func (p *ContainedQuantityPrereq) explain(_ *Entity, exclude any) (requirement, current string) {
+	if eqp, ok := exclude.(*Equipment); ok && eqp.Container() {
+		current = containedQuantity(eqp).String()
+	}
+	return p.requirement(), current
+}
+
+func containedQuantity(eqp *Equipment) fxp.Int {
+	var qty fxp.Int
+	for _, child := range eqp.Children {
+		qty += child.Quantity
+	}
+	return qty
+}
+
 // Hash writes this object's contents into the hasher.
 func (p *ContainedQuantityPrereq) Hash(h hash.Hash) {
 	if p == nil {
@@ -76,13 +76,23 @@ func (p *ContainedWeightPrereq) Satisfied(entity *Entity, exclude any, tooltip *
 	}
 	if !satisfied && tooltip != nil {
 		tooltip.WriteString(prefix)
-		tooltip.WriteString(HasText(p.Has))
-		tooltip.WriteString(i18n.Text(" a contained weight which "))
-		tooltip.WriteString(p.WeightCriteria.String())
+		tooltip.WriteString(p.requirement())
 	}
 	return satisfied
 }
 
+func (p *ContainedWeightPrereq) requirement() string {
+	return HasText(p.Has) + i18n.Text(" a contained weight which ") + p.WeightCriteria.String()
+}
+
+func (p *ContainedWeightPrereq) explain(entity *Entity, exclude any) (requirement, current string) {
+	if eqp, ok := exclude.(*Equipment); ok && eqp.Container() {
+		units := SheetSettingsFor(entity).DefaultWeightUnits
+		current = units.Format(eqp.ExtendedWeight(false, units) - eqp.AdjustedWeight(false, units))
+	}
+	return p.requirement(), current
+}
+
 // Hash writes this object's contents into the hasher.
 func (p *ContainedWeightPrereq) Hash(h hash.Hash) {
 	if p == nil {
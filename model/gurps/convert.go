@@ -44,172 +44,8 @@ func Convert(paths ...string) error {
 	list := slices.SortedFunc(maps.Keys(pathSet), func(a, b string) int { return xstrings.NaturalCmp(a, b, true) })
 	for _, p := range list {
 		fmt.Printf(i18n.Text("Processing %s\n"), p)
-		switch strings.ToLower(filepath.Ext(p)) {
-		case TraitsExt:
-			var data []*Trait
-			if data, err = NewTraitsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = SaveTraits(data, p); err != nil {
-				return err
-			}
-		case TraitModifiersExt:
-			var data []*TraitModifier
-			if data, err = NewTraitModifiersFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = SaveTraitModifiers(data, p); err != nil {
-				return err
-			}
-		case EquipmentExt:
-			var data []*Equipment
-			if data, err = NewEquipmentFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = SaveEquipment(data, p); err != nil {
-				return err
-			}
-		case EquipmentModifiersExt:
-			var data []*EquipmentModifier
-			if data, err = NewEquipmentModifiersFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = SaveEquipmentModifiers(data, p); err != nil {
-				return err
-			}
-		case LootExt:
-			var loot *Loot
-			if loot, err = NewLootFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = loot.Save(p); err != nil {
-				return err
-			}
-		case SkillsExt:
-			var data []*Skill
-			if data, err = NewSkillsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = SaveSkills(data, p); err != nil {
-				return err
-			}
-		case SpellsExt:
-			var data []*Spell
-			if data, err = NewSpellsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = SaveSpells(data, p); err != nil {
-				return err
-			}
-		case NotesExt:
-			var data []*Note
-			if data, err = NewNotesFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = SaveNotes(data, p); err != nil {
-				return err
-			}
-		case TemplatesExt:
-			var tmpl *Template
-			if tmpl, err = NewTemplateFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = tmpl.Save(p); err != nil {
-				return err
-			}
-		// TODO: Re-enable Campaign files
-		// case CampaignExt:
-		// 	var campaign *Campaign
-		// 	if campaign, err = NewCampaignFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-		// 		return err
-		// 	}
-		// 	if err = campaign.Save(p); err != nil {
-		// 		return err
-		// 	}
-		case SheetExt:
-			var entity *Entity
-			if entity, err = NewEntityFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = entity.Save(p); err != nil {
-				return err
-			}
-		case AncestryExt:
-			var data *Ancestry
-			if data, err = NewAncestryFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
-		case AttributesExt, AttributesExtAlt1, AttributesExtAlt2:
-			var data *AttributeDefs
-			if data, err = NewAttributeDefsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
-		case BodyExt, BodyExtAlt:
-			var data *Body
-			if data, err = NewBodyFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
-		case CalendarExt:
-			// Currently have no version info, so nothing to update
-		case ColorSettingsExt:
-			var data *colors.Colors
-			if data, err = colors.NewFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
-		case FontSettingsExt:
-			var data *fonts.Fonts
-			if data, err = fonts.NewFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
-		case GeneralSettingsExt:
-			var data *GeneralSettings
-			if data, err = NewGeneralSettingsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
-		case KeySettingsExt:
-			var data *KeyBindings
-			if data, err = NewKeyBindingsFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
-		case NamesExt:
-			// Currently have no version info, so nothing to update
-		case PageRefSettingsExt:
-			var data *PageRefs
-			if data, err = NewPageRefsFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
-		case SheetSettingsExt:
-			var data *SheetSettings
-			if data, err = NewSheetSettingsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
-				return err
-			}
-			if err = data.Save(p); err != nil {
-				return err
-			}
+		if err = convertFile(p); err != nil {
+			return err
 		}
 	}
 	if len(list) == 1 {
@@ -220,6 +56,202 @@ func Convert(paths ...string) error {
 	return nil
 }
 
+// convertFile converts a single GCS file to the current file format.
+func convertFile(p string) error {
+	var err error
+	switch strings.ToLower(filepath.Ext(p)) {
+	case TraitsExt:
+		var data []*Trait
+		if data, err = NewTraitsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = SaveTraits(data, p); err != nil {
+			return err
+		}
+	case TraitModifiersExt:
+		var data []*TraitModifier
+		if data, err = NewTraitModifiersFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = SaveTraitModifiers(data, p); err != nil {
+			return err
+		}
+	case EquipmentExt:
+		var data []*Equipment
+		if data, err = NewEquipmentFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = SaveEquipment(data, p); err != nil {
+			return err
+		}
+	case EquipmentModifiersExt:
+		var data []*EquipmentModifier
+		if data, err = NewEquipmentModifiersFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = SaveEquipmentModifiers(data, p); err != nil {
+			return err
+		}
+	case LootExt:
+		var loot *Loot
+		if loot, err = NewLootFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = loot.Save(p); err != nil {
+			return err
+		}
+	case SkillsExt:
+		var data []*Skill
+		if data, err = NewSkillsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = SaveSkills(data, p); err != nil {
+			return err
+		}
+	case SpellsExt:
+		var data []*Spell
+		if data, err = NewSpellsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = SaveSpells(data, p); err != nil {
+			return err
+		}
+	case NotesExt:
+		var data []*Note
+		if data, err = NewNotesFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = SaveNotes(data, p); err != nil {
+			return err
+		}
+	case TemplatesExt:
+		var tmpl *Template
+		if tmpl, err = NewTemplateFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = tmpl.Save(p); err != nil {
+			return err
+		}
+	case CampaignExt:
+		var campaign *Campaign
+		if campaign, err = NewCampaignFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = campaign.Save(p); err != nil {
+			return err
+		}
+	case VehicleExt:
+		var vehicle *Vehicle
+		if vehicle, err = NewVehicleFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = vehicle.Save(p); err != nil {
+			return err
+		}
+	case SheetExt:
+		var entity *Entity
+		if entity, err = NewEntityFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = entity.Save(p); err != nil {
+			return err
+		}
+	case AncestryExt:
+		var data *Ancestry
+		if data, err = NewAncestryFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case AttributesExt, AttributesExtAlt1, AttributesExtAlt2:
+		var data *AttributeDefs
+		if data, err = NewAttributeDefsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case BodyExt, BodyExtAlt:
+		var data *Body
+		if data, err = NewBodyFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case CalendarExt:
+		// Currently have no version info, so nothing to update
+	case ColorSettingsExt:
+		var data *colors.Colors
+		if data, err = colors.NewFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case FontSettingsExt:
+		var data *fonts.Fonts
+		if data, err = fonts.NewFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case GeneralSettingsExt:
+		var data *GeneralSettings
+		if data, err = NewGeneralSettingsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case KeySettingsExt:
+		var data *KeyBindings
+		if data, err = NewKeyBindingsFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case NamesExt:
+		// Currently have no version info, so nothing to update
+	case PageRefSettingsExt:
+		var data *PageRefs
+		if data, err = NewPageRefsFromFS(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case RulesetExt:
+		var data *Ruleset
+		if data, err = NewRulesetFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case SheetThemeExt:
+		var data *SheetTheme
+		if data, err = NewSheetThemeFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	case SheetSettingsExt:
+		var data *SheetSettings
+		if data, err = NewSheetSettingsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+			return err
+		}
+		if err = data.Save(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func convertWalker(pathSet, extSet map[string]struct{}) func(path string, d iofs.DirEntry, err error) error {
 	var f func(path string, d iofs.DirEntry, err error) error
 	visited := make(map[string]struct{})
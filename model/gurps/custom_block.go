@@ -0,0 +1,168 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// CustomBlockKeyPrefix is the prefix used for the block layout keys of custom blocks.
+const CustomBlockKeyPrefix = "custom_"
+
+// CustomBlockField holds a single labeled field within a custom block.
+type CustomBlockField struct {
+	Label string `json:"label,omitzero"`
+	// Value holds the text of the field, or the script expression used to produce it if Computed is true.
+	Value    string `json:"value,omitzero"`
+	Computed bool   `json:"computed,omitzero"`
+}
+
+// CustomBlock holds a user-defined block that can be placed in the sheet's block layout.
+type CustomBlock struct {
+	ID     string              `json:"id"`
+	Title  string              `json:"title,omitzero"`
+	Fields []*CustomBlockField `json:"fields,omitzero"`
+	Text   string              `json:"text,omitzero"`
+}
+
+// IsCustomBlockKey returns true if the block layout key refers to a custom block.
+func IsCustomBlockKey(key string) bool {
+	return strings.HasPrefix(key, CustomBlockKeyPrefix)
+}
+
+// Key returns the block layout key for this block.
+func (b *CustomBlock) Key() string {
+	return CustomBlockKeyPrefix + b.ID
+}
+
+// TitleOrDefault returns the title of the block, or a generic one if it has none.
+func (b *CustomBlock) TitleOrDefault() string {
+	if title := strings.TrimSpace(b.Title); title != "" {
+		return title
+	}
+	return i18n.Text("Custom Block")
+}
+
+// Empty returns true if the block has nothing to show.
+func (b *CustomBlock) Empty() bool {
+	return len(b.Fields) == 0 && strings.TrimSpace(b.Text) == ""
+}
+
+// Clone creates a copy of this.
+func (b *CustomBlock) Clone() *CustomBlock {
+	clone := *b
+	clone.Fields = make([]*CustomBlockField, len(b.Fields))
+	for i, one := range b.Fields {
+		field := *one
+		clone.Fields[i] = &field
+	}
+	return &clone
+}
+
+// ResolvedValue returns the value of the field, evaluating it as a script expression if the field is computed.
+func (f *CustomBlockField) ResolvedValue(entity *Entity) string {
+	if !f.Computed {
+		return f.Value
+	}
+	if strings.TrimSpace(f.Value) == "" {
+		return ""
+	}
+	return ResolveScript(entity, ScriptSelfProvider{}, f.Value)
+}
+
+// CustomBlockFor returns the custom block with the given block layout key, or nil if there is none.
+func (s *SheetSettings) CustomBlockFor(key string) *CustomBlock {
+	for _, one := range s.CustomBlocks {
+		if one.Key() == key {
+			return one
+		}
+	}
+	return nil
+}
+
+// CustomBlockKeys returns the block layout keys of the custom blocks.
+func (s *SheetSettings) CustomBlockKeys() []string {
+	keys := make([]string, 0, len(s.CustomBlocks))
+	for _, one := range s.CustomBlocks {
+		keys = append(keys, one.Key())
+	}
+	return keys
+}
+
+// AddCustomBlock adds the custom block, assigning it a unique ID based on its title, and places it at the end of the
+// block layout.
+func (s *SheetSettings) AddCustomBlock(block *CustomBlock) {
+	block.ID = SanitizeID(block.Title, true, s.customBlockIDs()...)
+	s.CustomBlocks = append(s.CustomBlocks, block)
+	s.syncCustomBlockLayout()
+}
+
+// RemoveCustomBlock removes the custom block with the given block layout key, along with its place in the block
+// layout.
+func (s *SheetSettings) RemoveCustomBlock(key string) {
+	for i, one := range s.CustomBlocks {
+		if one.Key() == key {
+			s.CustomBlocks = slices.Delete(s.CustomBlocks, i, i+1)
+			s.syncCustomBlockLayout()
+			return
+		}
+	}
+}
+
+// BlockTitle returns a human-readable title for the block layout key, including those of custom blocks.
+func (s *SheetSettings) BlockTitle(key string) string {
+	if block := s.CustomBlockFor(key); block != nil {
+		return block.TitleOrDefault()
+	}
+	return BlockLayoutKeyTitle(key)
+}
+
+func (s *SheetSettings) customBlockIDs() []string {
+	ids := make([]string, 0, len(s.CustomBlocks))
+	for _, one := range s.CustomBlocks {
+		ids = append(ids, one.ID)
+	}
+	return ids
+}
+
+// ensureCustomBlocksValid drops empty entries and ensures each has a unique ID.
+func (s *SheetSettings) ensureCustomBlocksValid() {
+	blocks := make([]*CustomBlock, 0, len(s.CustomBlocks))
+	ids := make([]string, 0, len(s.CustomBlocks))
+	for _, one := range s.CustomBlocks {
+		if one == nil {
+			continue
+		}
+		one.ID = SanitizeID(one.ID, true, ids...)
+		fields := make([]*CustomBlockField, 0, len(one.Fields))
+		for _, field := range one.Fields {
+			if field != nil {
+				fields = append(fields, field)
+			}
+		}
+		one.Fields = fields
+		ids = append(ids, one.ID)
+		blocks = append(blocks, one)
+	}
+	if len(blocks) == 0 {
+		blocks = nil
+	}
+	s.CustomBlocks = blocks
+}
+
+func (s *SheetSettings) syncCustomBlockLayout() {
+	if s.BlockLayout != nil {
+		s.BlockLayout.SetCustomKeys(s.CustomBlockKeys())
+		s.BlockLayout.EnsureValidity()
+	}
+}
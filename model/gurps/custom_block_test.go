@@ -0,0 +1,66 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"slices"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestCustomBlocks(t *testing.T) {
+	c := check.New(t)
+	s := FactorySheetSettings()
+	block := &CustomBlock{
+		Title: "Mana Reserve",
+		Fields: []*CustomBlockField{
+			{Label: "Stored", Value: "12"},
+			{Label: "Capacity", Value: "10 + 5", Computed: true},
+		},
+		Text: "Refills at dawn",
+	}
+	s.AddCustomBlock(block)
+	c.Equal("manareserve", block.ID)
+	key := block.Key()
+	c.True(IsCustomBlockKey(key))
+	c.Equal("Mana Reserve", s.BlockTitle(key))
+	rows := s.BlockLayout.ByRow()
+	c.Equal([]string{key}, rows[len(rows)-1])
+	c.Equal("12", block.Fields[0].ResolvedValue(nil))
+	c.Equal("15", block.Fields[1].ResolvedValue(nil))
+
+	// A second block with the same title gets its own ID
+	other := &CustomBlock{Title: "Mana Reserve"}
+	s.AddCustomBlock(other)
+	c.NotEqual(block.ID, other.ID)
+
+	s.BlockLayout.MoveBeside(key, BlockLayoutNotesKey, false)
+	data, err := json.Marshal(s)
+	c.NoError(err)
+	var loaded SheetSettings
+	c.NoError(json.Unmarshal(data, &loaded))
+	c.Equal(2, len(loaded.CustomBlocks))
+	c.True(slices.ContainsFunc(loaded.BlockLayout.ByRow(), func(row []string) bool {
+		return slices.Equal(row, []string{BlockLayoutNotesKey, key})
+	}))
+	layout, valid := NewBlockLayoutFromString(loaded.BlockLayout.String(), loaded.CustomBlockKeys()...)
+	c.True(valid)
+	c.Equal(loaded.BlockLayout.Layout, layout.Layout)
+
+	clone := s.Clone(nil)
+	clone.CustomBlocks[0].Fields[0].Value = "3"
+	c.Equal("12", block.Fields[0].Value)
+
+	s.RemoveCustomBlock(key)
+	c.Nil(s.CustomBlockFor(key))
+	c.False(slices.ContainsFunc(s.BlockLayout.ByRow(), func(row []string) bool { return slices.Contains(row, key) }))
+}
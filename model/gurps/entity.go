@@ -21,14 +21,17 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/richardwilkes/gcs/v5/model/criteria"
+	"github.com/richardwilkes/gcs/v5/model/diag"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/attribute"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/container"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/encumbrance"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/feature"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/progression"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/reaction"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/selfctrl"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/skillsel"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/stlimit"
@@ -70,22 +73,32 @@ func (pb *PointsBreakdown) Total() fxp.Int {
 
 // EntityData holds the Entity data that is written to disk.
 type EntityData struct {
-	Version          int             `json:"version"`
-	ID               tid.TID         `json:"id"`
-	TotalPoints      fxp.Int         `json:"total_points"`
-	PointsRecord     []*PointsRecord `json:"points_record,omitzero"`
-	Profile          Profile         `json:"profile"`
-	SheetSettings    *SheetSettings  `json:"settings,omitzero"`
-	Attributes       *Attributes     `json:"attributes,omitzero"`
-	Traits           []*Trait        `json:"traits,omitzero"`
-	Skills           []*Skill        `json:"skills,omitzero"`
-	Spells           []*Spell        `json:"spells,omitzero"`
-	CarriedEquipment []*Equipment    `json:"equipment,omitzero"`
-	OtherEquipment   []*Equipment    `json:"other_equipment,omitzero"`
-	Notes            []*Note         `json:"notes,omitzero"`
-	CreatedOn        jio.Time        `json:"created_date"`
-	ModifiedOn       jio.Time        `json:"modified_date"`
-	ThirdParty       map[string]any  `json:"third_party,omitzero"`
+	Version          int                   `json:"version"`
+	ID               tid.TID               `json:"id"`
+	TotalPoints      fxp.Int               `json:"total_points"`
+	PointsRecord     []*PointsRecord       `json:"points_record,omitzero"`
+	PointBudget      PointBudget           `json:"point_budget,omitzero"`
+	Profile          Profile               `json:"profile"`
+	SheetSettings    *SheetSettings        `json:"settings,omitzero"`
+	Attributes       *Attributes           `json:"attributes,omitzero"`
+	Traits           []*Trait              `json:"traits,omitzero"`
+	Skills           []*Skill              `json:"skills,omitzero"`
+	Spells           []*Spell              `json:"spells,omitzero"`
+	CarriedEquipment []*Equipment          `json:"equipment,omitzero"`
+	OtherEquipment   []*Equipment          `json:"other_equipment,omitzero"`
+	Notes            []*Note               `json:"notes,omitzero"`
+	Wounds           []*Wound              `json:"wounds,omitzero"`
+	Conditions       []*Condition          `json:"conditions,omitzero"`
+	ActiveModifiers  []string              `json:"active_conditional_modifiers,omitzero"`
+	Maneuver         string                `json:"-"`
+	Grapple          GrappleState          `json:"grapple,omitzero"`
+	FatigueLog       []*FatigueExpenditure `json:"fatigue_log,omitzero"`
+	Grimoire         Grimoire              `json:"grimoire,omitzero"`
+	Locked           bool                  `json:"locked,omitzero"`
+	LockPassword     LockPassword          `json:"lock_password,omitzero"`
+	CreatedOn        jio.Time              `json:"created_date"`
+	ModifiedOn       jio.Time              `json:"modified_date"`
+	ThirdParty       map[string]any        `json:"third_party,omitzero"`
 }
 
 type features struct {
@@ -120,6 +133,8 @@ type Entity struct {
 	basicLiftCache                 fxp.Weight
 	encumbranceLevelCache          encumbrance.Level
 	encumbranceLevelForSkillsCache encumbrance.Level
+	levelInputs                    levelInputs
+	scriptResolvingDepth           int
 }
 
 // NewEntityFromFile loads an Entity from a file.
@@ -191,6 +206,12 @@ func (e *Entity) Save(filePath string) error {
 
 // MarshalJSONTo implements json.MarshalerTo.
 func (e *Entity) MarshalJSONTo(enc *jsontext.Encoder) error {
+	// The maneuver isn't saved, so the calculated values that are saved must not reflect it either.
+	if e.Maneuver != "" {
+		maneuver := e.Maneuver
+		e.Maneuver = ""
+		defer func() { e.Maneuver = maneuver }()
+	}
 	e.Recalculate()
 	type calc struct {
 		Swing                 *dice.Dice `json:"swing"`
@@ -257,11 +278,7 @@ func (e *Entity) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 	if e.Version < noNeedForRewrapVersion {
 		e.SheetSettings.BodyType.Rewrap()
 	}
-	var total fxp.Int
-	for _, rec := range e.PointsRecord {
-		total += rec.Points
-	}
-	if total != e.TotalPoints {
+	if total := TotalAwardedPoints(e.PointsRecord); total != e.TotalPoints {
 		e.PointsRecord = append(e.PointsRecord, &PointsRecord{
 			Points: e.TotalPoints - total,
 			When:   jio.Now(),
@@ -289,6 +306,7 @@ func (e *Entity) Recalculate() {
 	if e == nil {
 		return
 	}
+	defer diag.Track(diag.RecalculateKey, time.Now())
 	e.ensureAttachments()
 	e.DiscardCaches()
 	e.SourceMatcher().PrepareHashes(e)
@@ -375,6 +393,11 @@ func (e *Entity) processFeatures() {
 		}, true, true, eqp.Modifiers...)
 		return false
 	}, false, false, e.CarriedEquipment...)
+	for _, c := range e.Conditions {
+		for _, f := range c.Features {
+			e.processFeature(c, nil, f, c)
+		}
+	}
 	e.LiftingStrengthBonus = e.AttributeBonusFor(StrengthID, stlimit.LiftingOnly, nil).Floor()
 	e.StrikingStrengthBonus = e.AttributeBonusFor(StrengthID, stlimit.StrikingOnly, nil).Floor()
 	e.ThrowingStrengthBonus = e.AttributeBonusFor(StrengthID, stlimit.ThrowingOnly, nil).Floor()
@@ -390,6 +413,8 @@ func (e *Entity) processFeatures() {
 			attr.CostReduction = 0
 		}
 	}
+	ApplyPoolThresholdEffects(e.Attributes)
+	e.applyGrapplePenalty()
 	e.Profile.Update(e)
 	if e.ResolveAttribute(DodgeID) == nil {
 		e.DodgeBonus = e.AttributeBonusFor(DodgeID, stlimit.None, nil).Floor()
@@ -477,7 +502,15 @@ func (e *Entity) processFeature(owner, subOwner fmt.Stringer, f Feature, leveled
 		e.features.traitBonuses = append(e.features.traitBonuses, actual)
 	case *WeaponBonus:
 		e.features.weaponBonuses = append(e.features.weaponBonuses, actual)
-	case *ConditionalModifierBonus, *ContainedWeightReduction, *ReactionBonus:
+	case *ConditionalModifierBonus:
+		if actual.AppliesTo != "" && e.ConditionalModifierActive(actual.SituationWithReplacements()) {
+			bonus := NewAttributeBonus(actual.AppliesTo)
+			bonus.LeveledAmount = actual.LeveledAmount
+			bonus.SetOwner(owner)
+			bonus.SetSubOwner(subOwner)
+			e.features.attributeBonuses = append(e.features.attributeBonuses, bonus)
+		}
+	case *ContainedWeightReduction, *ReactionBonus:
 		// Not collected at this stage
 	default:
 		errs.Log(errs.New("unhandled feature"), "type", f.FeatureType())
@@ -573,25 +606,41 @@ func (e *Entity) processPrereqs() {
 	Traverse(equipmentFunc, false, false, e.OtherEquipment...)
 }
 
-// UpdateSkills updates the levels of all skills.
+// UpdateSkills updates the levels of the skills whose inputs have changed since their levels were last updated.
+// Returns true if any of them changed.
 func (e *Entity) UpdateSkills() bool {
+	p := e.newLevelPass()
+	previous := e.levelInputs.skills
+	e.levelInputs.skills = make(map[*Skill]uint64, len(previous))
 	changed := false
 	Traverse(func(s *Skill) bool {
-		if s.UpdateLevel() {
-			changed = true
+		fingerprint := p.skillFingerprint(s)
+		if last, ok := previous[s]; !ok || last != fingerprint {
+			if s.UpdateLevel() {
+				changed = true
+			}
 		}
+		e.levelInputs.skills[s] = fingerprint
 		return false
 	}, false, true, e.Skills...)
 	return changed
 }
 
-// UpdateSpells updates the levels of all spells.
+// UpdateSpells updates the levels of the spells whose inputs have changed since their levels were last updated.
+// Returns true if any of them changed.
 func (e *Entity) UpdateSpells() bool {
+	p := e.newLevelPass()
+	previous := e.levelInputs.spells
+	e.levelInputs.spells = make(map[*Spell]uint64, len(previous))
 	changed := false
 	Traverse(func(s *Spell) bool {
-		if s.UpdateLevel() {
-			changed = true
+		fingerprint := p.spellFingerprint(s)
+		if last, ok := previous[s]; !ok || last != fingerprint {
+			if s.UpdateLevel() {
+				changed = true
+			}
 		}
+		e.levelInputs.spells[s] = fingerprint
 		return false
 	}, false, true, e.Spells...)
 	return changed
@@ -660,21 +709,26 @@ func calculateSingleTraitPoints(t *Trait, pb *PointsBreakdown) {
 	}
 }
 
-// WealthCarried returns the current wealth being carried.
+// WealthCarried returns the current wealth being carried. Carried equipment at locations that don't count toward the
+// carried value is left out.
 func (e *Entity) WealthCarried() fxp.Int {
 	var value fxp.Int
 	for _, one := range e.CarriedEquipment {
-		value += one.ExtendedValue()
+		value += one.ExtendedValueAtLocations(e.SheetSettings.EquipmentLocations.CountsTowardValue)
 	}
 	return value
 }
 
-// WealthNotCarried returns the current wealth not being carried.
+// WealthNotCarried returns the current wealth not being carried. This includes any carried equipment at locations that
+// don't count toward the carried value.
 func (e *Entity) WealthNotCarried() fxp.Int {
 	var value fxp.Int
 	for _, one := range e.OtherEquipment {
 		value += one.ExtendedValue()
 	}
+	for _, one := range e.CarriedEquipment {
+		value += one.ExtendedValue() - one.ExtendedValueAtLocations(e.SheetSettings.EquipmentLocations.CountsTowardValue)
+	}
 	return value
 }
 
@@ -810,25 +864,29 @@ func (e *Entity) AddDRBonusesFor(locationID string, tooltip *xbytes.InsertBuffer
 	if drMap == nil {
 		drMap = make(map[string]int)
 	}
-	isTopLevel := false
-	for _, one := range e.SheetSettings.BodyType.Locations {
-		if one.LocID == locationID {
-			isTopLevel = true
-			break
+	isTopLevel := e.isTopLevelLocation(locationID)
+	ignored := make(map[*Equipment]bool)
+	noted := make(map[*Equipment]bool)
+	for _, layer := range e.ArmorLayersFor(locationID) {
+		if layer.Ignored {
+			ignored[layer.Equipment] = true
 		}
 	}
 	for _, one := range e.features.drBonuses {
 		// Skip PD bonuses - they're handled separately by AddPDBonusesFor
-		if strings.EqualFold(one.Specialization, "PD") {
+		if strings.EqualFold(one.Specialization, "PD") || !one.coversLocation(locationID, isTopLevel) {
 			continue
 		}
-		for _, loc := range one.Locations {
-			if (loc == AllID && isTopLevel) || strings.EqualFold(loc, locationID) {
-				drMap[strings.ToLower(one.Specialization)] += fxp.AsInteger[int](one.AdjustedAmount())
-				one.AddToTooltip(tooltip)
-				break
+		if eqp, ok := one.Owner().(*Equipment); ok && ignored[eqp] {
+			if tooltip != nil && !noted[eqp] {
+				noted[eqp] = true
+				fmt.Fprintf(tooltip, i18n.Text("\n- %s [ignored, since rigid armor can't be worn over other rigid armor]"),
+					eqp.String())
 			}
+			continue
 		}
+		drMap[strings.ToLower(one.Specialization)] += fxp.AsInteger[int](one.AdjustedAmount())
+		one.AddToTooltip(tooltip)
 	}
 	return drMap
 }
@@ -885,7 +943,7 @@ func (e *Entity) SkillBonusFor(name, specialization string, tags []string, toolt
 			}
 		}
 	}
-	return total
+	return total + e.powerTalentBonusFor("", tags, tooltip)
 }
 
 // SkillPointBonusFor returns the total point bonus for the matching skill point bonuses.
@@ -920,7 +978,7 @@ func (e *Entity) SpellBonusFor(name, powerSource string, colleges, tags []string
 			bonus.AddToTooltip(tooltip)
 		}
 	}
-	return total
+	return total + e.powerTalentBonusFor(powerSource, tags, tooltip)
 }
 
 // SpellPointBonusFor returns the total point bonus for the matching spell point bonuses.
@@ -1095,11 +1153,34 @@ func (e *Entity) SkillNamed(name, specialization string, requirePoints bool, exc
 	return list
 }
 
-// Dodge returns the current Dodge value for the given Encumbrance.
+// CanDodge returns true if the current maneuver permits active defenses.
+func (e *Entity) CanDodge() bool {
+	_, allowed := e.CurrentManeuver().DefenseAdjustment(DodgeID, nil)
+	return allowed
+}
+
+// DodgeText returns the current Dodge value for the given Encumbrance as text, or "No" if the current maneuver permits
+// no active defense.
+func (e *Entity) DodgeText(enc encumbrance.Level) string {
+	if !e.CanDodge() {
+		return "No"
+	}
+	return strconv.Itoa(e.Dodge(enc))
+}
+
+// Dodge returns the current Dodge value for the given Encumbrance, or 0 if the current maneuver permits no active
+// defense.
 // If DodgeOverride is set (non-zero), it returns that value directly without calculation.
 // Note: PD (Passive Defense) does NOT affect base Dodge. PD is applied separately during
 // combat resolution when an active defense fails and only if armor covers the hit location.
 func (e *Entity) Dodge(enc encumbrance.Level) int {
+	if !e.CanDodge() {
+		return 0
+	}
+	return e.dodge(enc, true)
+}
+
+func (e *Entity) dodge(enc encumbrance.Level, applyManeuver bool) int {
 	settings := e.SheetSettings
 	// Check for manual override first
 	if settings != nil && settings.DodgeOverride != 0 {
@@ -1127,6 +1208,11 @@ func (e *Entity) Dodge(enc encumbrance.Level) int {
 		}
 	}
 	dodge += e.DodgeBonus
+	if applyManeuver {
+		if adj, allowed := e.CurrentManeuver().DefenseAdjustment(DodgeID, nil); allowed {
+			dodge += fxp.FromInteger(adj)
+		}
+	}
 	// NOTE: PD (Passive Defense) is NOT added to base Dodge. PD is a separate mechanic
 	// that applies during combat resolution when an active defense fails and only
 	// if the armor covers the hit location. PD would be handled in combat resolution logic.
@@ -1272,11 +1358,13 @@ func (e *Entity) WeightUnit() fxp.WeightUnit {
 	return e.SheetSettings.DefaultWeightUnits
 }
 
-// WeightCarried returns the carried weight.
+// WeightCarried returns the carried weight. Carried equipment at locations that don't count toward encumbrance is left
+// out.
 func (e *Entity) WeightCarried(forSkills bool) fxp.Weight {
 	var total fxp.Weight
 	for _, one := range e.CarriedEquipment {
-		total += one.ExtendedWeight(forSkills, e.SheetSettings.DefaultWeightUnits)
+		total += one.ExtendedWeightAtLocations(forSkills, e.SheetSettings.DefaultWeightUnits,
+			e.SheetSettings.EquipmentLocations.CountsTowardEncumbrance)
 	}
 	return total
 }
@@ -1557,67 +1645,71 @@ func (e *Entity) SetWeapons(_ bool, _ []*Weapon) {
 // Reactions returns the current set of reactions.
 func (e *Entity) Reactions() []*ConditionalModifier {
 	m := make(map[string]*ConditionalModifier)
+	e.collectReactions(func(_ reaction.Source, source, situation string, amt fxp.Int) {
+		if r, exists := m[situation]; exists {
+			r.Add(source, amt)
+		} else {
+			m[situation] = NewConditionalModifier(source, situation, amt)
+		}
+	})
+	list := make([]*ConditionalModifier, 0, len(m))
+	for _, v := range m {
+		list = append(list, v)
+	}
+	slices.SortFunc(list, func(a, b *ConditionalModifier) int { return a.Compare(b) })
+	return list
+}
+
+// reactionAdder receives a reaction modifier, along with the kind of thing it came from.
+type reactionAdder func(kind reaction.Source, source, situation string, amt fxp.Int)
+
+// collectReactions calls add for each reaction modifier the entity has.
+func (e *Entity) collectReactions(add reactionAdder) {
 	Traverse(func(t *Trait) bool {
+		kind := reactionSourceForTrait(t)
 		source := i18n.Text("from trait ") + t.String()
 		if !t.Container() {
-			e.reactionsFromFeatureList(source, t.Features, m)
+			e.reactionsFromFeatureList(kind, source, t.Features, add)
 		}
 		Traverse(func(mod *TraitModifier) bool {
-			e.reactionsFromFeatureList(source, mod.Features, m)
+			e.reactionsFromFeatureList(kind, source, mod.Features, add)
 			return false
 		}, true, true, t.Modifiers...)
 		if t.SelfControl != selfctrl.None && t.SelfControlAdj == selfctrl.ReactionPenalty {
-			amt := fxp.FromInteger(selfctrl.ReactionPenalty.Adjustment(t.SelfControl))
-			situation := fmt.Sprintf(i18n.Text("from others when %s is triggered"), t.String())
-			if r, exists := m[situation]; exists {
-				r.Add(source, amt)
-			} else {
-				m[situation] = NewConditionalModifier(source, situation, amt)
-			}
+			add(kind, source, fmt.Sprintf(i18n.Text("from others when %s is triggered"), t.String()),
+				fxp.FromInteger(selfctrl.ReactionPenalty.Adjustment(t.SelfControl)))
 		}
 		return false
 	}, true, false, e.Traits...)
 	Traverse(func(eqp *Equipment) bool {
 		if eqp.ReallyEquipped() {
 			source := i18n.Text("from equipment ") + eqp.NameWithReplacements()
-			e.reactionsFromFeatureList(source, eqp.Features, m)
+			e.reactionsFromFeatureList(reaction.Equipment, source, eqp.Features, add)
 			Traverse(func(mod *EquipmentModifier) bool {
-				e.reactionsFromFeatureList(source, mod.Features, m)
+				e.reactionsFromFeatureList(reaction.Equipment, source, mod.Features, add)
 				return false
 			}, true, true, eqp.Modifiers...)
 		}
 		return false
 	}, false, false, e.CarriedEquipment...)
 	Traverse(func(sk *Skill) bool {
-		e.reactionsFromFeatureList(i18n.Text("from skill ")+sk.String(), sk.Features, m)
+		e.reactionsFromFeatureList(reaction.Skill, i18n.Text("from skill ")+sk.String(), sk.Features, add)
 		return false
 	}, false, true, e.Skills...)
-	list := make([]*ConditionalModifier, 0, len(m))
-	for _, v := range m {
-		list = append(list, v)
-	}
-	slices.SortFunc(list, func(a, b *ConditionalModifier) int { return a.Compare(b) })
-	return list
 }
 
-func (e *Entity) reactionsFromFeatureList(source string, features Features, m map[string]*ConditionalModifier) {
+func (e *Entity) reactionsFromFeatureList(kind reaction.Source, source string, features Features, add reactionAdder) {
 	for _, f := range features {
 		bonus, ok := f.(*ReactionBonus)
 		if !ok {
 			continue
 		}
-		amt := bonus.AdjustedAmount()
 		var replacements map[string]string
 		var na nameable.Accesser
 		if na, ok = bonus.Owner().(nameable.Accesser); ok {
 			replacements = na.NameableReplacements()
 		}
-		situation := nameable.Apply(bonus.Situation, replacements)
-		if r, exists := m[situation]; exists {
-			r.Add(source, amt)
-		} else {
-			m[situation] = NewConditionalModifier(source, situation, amt)
-		}
+		add(kind, source, nameable.Apply(bonus.Situation, replacements), bonus.AdjustedAmount())
 	}
 }
 
@@ -1665,16 +1757,18 @@ func (e *Entity) conditionalModifiersFromFeatureList(source string, features Fea
 			continue
 		}
 		amt := bonus.AdjustedAmount()
-		var replacements map[string]string
-		var na nameable.Accesser
-		if na, ok = bonus.Owner().(nameable.Accesser); ok {
-			replacements = na.NameableReplacements()
-		}
-		situation := nameable.Apply(bonus.Situation, replacements)
-		if r, exists := m[situation]; exists {
+		situation := bonus.SituationWithReplacements()
+		r, exists := m[situation]
+		if exists {
 			r.Add(source, amt)
 		} else {
-			m[situation] = NewConditionalModifier(source, situation, amt)
+			r = NewConditionalModifier(source, situation, amt)
+			r.entity = e
+			r.Active = e.ConditionalModifierActive(situation)
+			m[situation] = r
+		}
+		if bonus.AppliesTo != "" {
+			r.Adjustable = true
 		}
 	}
 }
@@ -1718,6 +1812,26 @@ func (e *Entity) SetOtherEquipmentList(list []*Equipment) {
 	e.OtherEquipment = list
 }
 
+// AdoptEquipment attaches equipment that came from elsewhere, such as another sheet or a library, to this entity and
+// then recalculates, so that everything derived from the entity that carries it is immediately resolved against this
+// one. This includes the ST-based damage and minimum ST penalties of its weapons, as well as their skill defaults. The
+// equipment is expected to already be in one of the entity's equipment lists. Links to ammunition that this entity
+// doesn't have are dropped.
+func (e *Entity) AdoptEquipment(list ...*Equipment) {
+	for _, one := range list {
+		one.SetDataOwner(e)
+	}
+	Traverse(func(eqp *Equipment) bool {
+		if tid.IsValid(eqp.AmmoID) && eqp.ammunitionFor() == nil {
+			eqp.AmmoID = ""
+			eqp.AmmoLoaded = 0
+		}
+		return false
+	}, false, false, list...)
+	DiscardGlobalResolveCache()
+	e.Recalculate()
+}
+
 // SkillList implements ListProvider
 func (e *Entity) SkillList() []*Skill {
 	return e.Skills
@@ -1771,10 +1885,12 @@ func (e *Entity) Hash(h hash.Hash) {
 func (e *Entity) SetPointsRecord(record []*PointsRecord) {
 	e.PointsRecord = ClonePointsRecordList(record)
 	slices.SortFunc(e.PointsRecord, func(a, b *PointsRecord) int { return b.When.Compare(a.When) })
-	e.TotalPoints = 0
-	for _, rec := range record {
-		e.TotalPoints += rec.Points
-	}
+	e.TotalPoints = TotalAwardedPoints(record)
+}
+
+// AdvancementSummary returns a summary of the points earned vs. spent.
+func (e *Entity) AdvancementSummary() AdvancementSummary {
+	return SummarizeAdvancement(e.PointsRecord, e.PointsBreakdown().Total())
 }
 
 // SyncWithLibrarySources syncs the entity with the library sources.
@@ -1822,6 +1938,11 @@ func (e *Entity) PageSettings() *PageSettings {
 	return e.SheetSettings.Page
 }
 
+// PageScale implements PageScaler.
+func (e *Entity) PageScale() float32 {
+	return e.SheetSettings.ContentScale()
+}
+
 // PageTitle implements PageInfoProvider.
 func (e *Entity) PageTitle() string {
 	if e.SheetSettings.UseTitleInFooter {
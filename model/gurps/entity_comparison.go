@@ -0,0 +1,167 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// EntityComparisonRow holds one line of a side-by-side comparison of several entities. There is one value per entity,
+// in the order the entities were provided. A value is empty if the entity doesn't have the thing being compared.
+type EntityComparisonRow struct {
+	Category string
+	Name     string
+	Values   []string
+}
+
+// Differs returns true if the values are not all the same.
+func (r *EntityComparisonRow) Differs() bool {
+	for _, one := range r.Values[min(1, len(r.Values)):] {
+		if one != r.Values[0] {
+			return true
+		}
+	}
+	return false
+}
+
+type comparisonCollector struct {
+	rows     []*EntityComparisonRow
+	count    int
+	category string
+	index    map[string]*EntityComparisonRow
+}
+
+func (c *comparisonCollector) startCategory(category string) {
+	c.category = category
+	c.index = make(map[string]*EntityComparisonRow)
+}
+
+func (c *comparisonCollector) add(which int, key, name, value string) {
+	row, exists := c.index[key]
+	if !exists {
+		row = &EntityComparisonRow{
+			Category: c.category,
+			Name:     name,
+			Values:   make([]string, c.count),
+		}
+		c.index[key] = row
+		c.rows = append(c.rows, row)
+	}
+	row.Values[which] = value
+}
+
+// CompareEntities returns the rows of a side-by-side comparison of the entities' points, attributes, defenses and key
+// skills. The key skills are the skills any of the entities have spent points on, highest level first, limited to
+// maxKeySkills of them.
+func CompareEntities(entities []*Entity, maxKeySkills int) []*EntityComparisonRow {
+	c := &comparisonCollector{count: len(entities)}
+
+	c.startCategory(i18n.Text("Points"))
+	for i, e := range entities {
+		c.add(i, "total", i18n.Text("Total"), e.TotalPoints.String())
+		c.add(i, "unspent", i18n.Text("Unspent"), e.UnspentPoints().String())
+		pb := e.PointsBreakdown()
+		for _, one := range []struct {
+			key   string
+			name  string
+			value fxp.Int
+		}{
+			{"ancestry", i18n.Text("Ancestry"), pb.Ancestry},
+			{"attributes", i18n.Text("Attributes"), pb.Attributes},
+			{"advantages", i18n.Text("Advantages"), pb.Advantages},
+			{"disadvantages", i18n.Text("Disadvantages"), pb.Disadvantages},
+			{"quirks", i18n.Text("Quirks"), pb.Quirks},
+			{"skills", i18n.Text("Skills"), pb.Skills},
+			{"spells", i18n.Text("Spells"), pb.Spells},
+		} {
+			c.add(i, one.key, one.name, one.value.String())
+		}
+	}
+
+	c.startCategory(i18n.Text("Attributes"))
+	for i, e := range entities {
+		for _, def := range SheetSettingsFor(e).Attributes.List(true) {
+			if attr, ok := e.Attributes.Set[def.ID()]; ok {
+				c.add(i, def.ID(), def.CombinedName(), attr.Maximum().String())
+			}
+		}
+	}
+
+	c.startCategory(i18n.Text("Defenses"))
+	for i, e := range entities {
+		c.add(i, "dodge", i18n.Text("Dodge"), e.DodgeText(e.EncumbranceLevel(true)))
+		parry, block := bestActiveDefenses(e)
+		c.add(i, "parry", i18n.Text("Best Parry"), parry)
+		c.add(i, "block", i18n.Text("Best Block"), block)
+	}
+
+	c.startCategory(i18n.Text("Key Skills"))
+	levels := make([]map[string]*Skill, len(entities))
+	best := make(map[string]fxp.Int)
+	for i, e := range entities {
+		levels[i] = make(map[string]*Skill)
+		Traverse(func(s *Skill) bool {
+			if s.Points > 0 {
+				key := strings.ToLower(s.String())
+				if prior, exists := levels[i][key]; !exists || prior.LevelData.Level < s.LevelData.Level {
+					levels[i][key] = s
+				}
+				if level, exists := best[key]; !exists || level < s.LevelData.Level {
+					best[key] = s.LevelData.Level
+				}
+			}
+			return false
+		}, true, true, e.Skills...)
+	}
+	keys := make([]string, 0, len(best))
+	for key := range best {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if result := cmp.Compare(best[b], best[a]); result != 0 {
+			return result
+		}
+		return cmp.Compare(a, b)
+	})
+	if maxKeySkills >= 0 && len(keys) > maxKeySkills {
+		keys = keys[:maxKeySkills]
+	}
+	for _, key := range keys {
+		for i := range entities {
+			if s, exists := levels[i][key]; exists {
+				c.add(i, key, s.String(), s.LevelData.LevelAsString(false))
+			}
+		}
+	}
+	return c.rows
+}
+
+func bestActiveDefenses(e *Entity) (parry, block string) {
+	bestParry := fxp.Min
+	bestBlock := fxp.Min
+	parry = "-"
+	block = "-"
+	for _, w := range e.Weapons(true, false, true) {
+		if p := w.Parry.Resolve(w, nil); p.CanParry && p.Modifier > bestParry {
+			bestParry = p.Modifier
+			parry = p.String()
+		}
+		if b := w.Block.Resolve(w, nil); b.CanBlock && b.Modifier > bestBlock {
+			bestBlock = b.Modifier
+			block = b.String()
+		}
+	}
+	return parry, block
+}
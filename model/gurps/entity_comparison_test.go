@@ -0,0 +1,59 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestCompareEntities(t *testing.T) {
+	c := check.New(t)
+	first := NewEntity()
+	second := NewEntity()
+	for i, name := range []string{"Stealth", "Climbing", "Swimming"} {
+		skill := NewSkill(first, nil, false)
+		skill.Name = name
+		skill.Points = fxp.FromInteger(i + 1)
+		first.SetSkillList(append(first.Skills, skill))
+	}
+	skill := NewSkill(second, nil, false)
+	skill.Name = "Stealth"
+	skill.Points = fxp.Eight
+	second.SetSkillList([]*Skill{skill})
+	second.Attributes.Set[StrengthID].Adjustment = fxp.Two
+	first.Recalculate()
+	second.Recalculate()
+
+	rows := CompareEntities([]*Entity{first, second}, 2)
+	found := make(map[string]*EntityComparisonRow)
+	var skillRows []*EntityComparisonRow
+	for _, row := range rows {
+		c.Equal(2, len(row.Values))
+		found[row.Category+":"+row.Name] = row
+		if row.Category == "Key Skills" {
+			skillRows = append(skillRows, row)
+		}
+	}
+	c.NotNil(found["Points:Total"])
+	c.NotNil(found["Defenses:Dodge"])
+	c.False(found["Defenses:Dodge"].Differs())
+	st := found["Attributes:Strength (ST)"]
+	c.NotNil(st)
+	c.True(st.Differs())
+	c.Equal("10", st.Values[0])
+	c.Equal("12", st.Values[1])
+	c.Equal(2, len(skillRows))
+	c.Equal("Stealth", skillRows[0].Name)
+	c.True(skillRows[0].Differs())
+	c.Equal("", skillRows[1].Values[1])
+}
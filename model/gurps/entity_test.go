@@ -56,3 +56,25 @@ func TestEntityAttributeBonus(t *testing.T) {
 	c.Equal(fxp.Ten, e.Attributes.Current("st"), "ST; leveled +1 bonus, with 3 levels, for throwing only")
 	c.Equal(fxp.Three, e.ThrowingStrengthBonus, "Throwing ST Bonus; leveled +1 bonus, with 3 levels, for throwing only")
 }
+
+func TestEntityAdoptEquipment(t *testing.T) {
+	c := check.New(t)
+	weak := NewEntity()
+	strong := NewEntity()
+	strong.Attributes.Set[StrengthID].SetMaximum(fxp.FromInteger(18))
+	strong.Recalculate()
+
+	club := NewEquipment(weak, nil, false)
+	club.Weapons = []*Weapon{NewWeapon(club, true)}
+	club.AmmoID = club.TID
+	weak.CarriedEquipment = []*Equipment{club}
+	weak.Recalculate()
+	weakDamage := club.Weapons[0].Damage.ResolvedDamage(nil)
+
+	lent := club.Clone(LibraryFile{}, weak, nil, false)
+	strong.CarriedEquipment = append(strong.CarriedEquipment, lent)
+	strong.AdoptEquipment(lent)
+	c.Equal(strong, lent.Weapons[0].Entity())
+	c.NotEqual(weakDamage, lent.Weapons[0].Damage.ResolvedDamage(nil))
+	c.Equal("", string(lent.AmmoID), "link to ammunition the entity doesn't have")
+}
@@ -0,0 +1,95 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package armor
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// Possible values.
+const (
+	Unspecified Construction = iota
+	Flexible
+	Rigid
+)
+
+// LastConstruction is the last valid value.
+const LastConstruction Construction = Rigid
+
+// Constructions holds all possible values.
+var Constructions = []Construction{
+	Unspecified,
+	Flexible,
+	Rigid,
+}
+
+// Construction holds whether a piece of armor is flexible or rigid, which determines how it may be layered.
+type Construction byte
+
+// EnsureValid ensures this is of a known value.
+func (enum Construction) EnsureValid() Construction {
+	if enum <= Rigid {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum Construction) Key() string {
+	switch enum {
+	case Unspecified:
+		return "unspecified"
+	case Flexible:
+		return "flexible"
+	case Rigid:
+		return "rigid"
+	default:
+		return Construction(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum Construction) String() string {
+	switch enum {
+	case Unspecified:
+		return i18n.Text(`Unspecified`)
+	case Flexible:
+		return i18n.Text(`Flexible`)
+	case Rigid:
+		return i18n.Text(`Rigid`)
+	default:
+		return Construction(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum Construction) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *Construction) UnmarshalText(text []byte) error {
+	*enum = ExtractConstruction(string(text))
+	return nil
+}
+
+// ExtractConstruction extracts the value from a string.
+func ExtractConstruction(str string) Construction {
+	for _, enum := range Constructions {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package casting
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// Possible values.
+const (
+	Standard Mode = iota
+	Threshold
+	NoFatigue
+)
+
+// LastMode is the last valid value.
+const LastMode Mode = NoFatigue
+
+// Modes holds all possible values.
+var Modes = []Mode{
+	Standard,
+	Threshold,
+	NoFatigue,
+}
+
+// Mode holds the way the energy needed to cast spells is paid for.
+type Mode byte
+
+// EnsureValid ensures this is of a known value.
+func (enum Mode) EnsureValid() Mode {
+	if enum <= NoFatigue {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum Mode) Key() string {
+	switch enum {
+	case Standard:
+		return "standard"
+	case Threshold:
+		return "threshold"
+	case NoFatigue:
+		return "no_fatigue"
+	default:
+		return Mode(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum Mode) String() string {
+	switch enum {
+	case Standard:
+		return i18n.Text(`Standard (FP)`)
+	case Threshold:
+		return i18n.Text(`Threshold-Limited`)
+	case NoFatigue:
+		return i18n.Text(`No Fatigue Cost`)
+	default:
+		return Mode(0).String()
+	}
+}
+
+// AltString returns the alternate string.
+func (enum Mode) AltString() string {
+	switch enum {
+	case Standard:
+		return i18n.Text(`Spells are paid for with FP, as described on p. B236`)
+	case Threshold:
+		return i18n.Text(`Spells cost no FP; instead, their energy cost is added to a tally that risks calamity once it exceeds the caster's threshold, as described on p. T77`)
+	case NoFatigue:
+		return i18n.Text(`Spells cost no energy to cast or maintain`)
+	default:
+		return Mode(0).AltString()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum Mode) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *Mode) UnmarshalText(text []byte) error {
+	*enum = ExtractMode(string(text))
+	return nil
+}
+
+// ExtractMode extracts the value from a string.
+func ExtractMode(str string) Mode {
+	for _, enum := range Modes {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
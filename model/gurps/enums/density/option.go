@@ -0,0 +1,22 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package density
+
+// Scale returns the factor the content of a page is scaled by for this density.
+func (enum Option) Scale() float32 {
+	switch enum {
+	case Compact:
+		return 0.9
+	case Dense:
+		return 0.8
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,95 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package density
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// Possible values.
+const (
+	Comfortable Option = iota
+	Compact
+	Dense
+)
+
+// LastOption is the last valid value.
+const LastOption Option = Dense
+
+// Options holds all possible values.
+var Options = []Option{
+	Comfortable,
+	Compact,
+	Dense,
+}
+
+// Option holds how tightly the content of a sheet is packed onto its pages.
+type Option byte
+
+// EnsureValid ensures this is of a known value.
+func (enum Option) EnsureValid() Option {
+	if enum <= Dense {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum Option) Key() string {
+	switch enum {
+	case Comfortable:
+		return "comfortable"
+	case Compact:
+		return "compact"
+	case Dense:
+		return "dense"
+	default:
+		return Option(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum Option) String() string {
+	switch enum {
+	case Comfortable:
+		return i18n.Text(`Comfortable`)
+	case Compact:
+		return i18n.Text(`Compact`)
+	case Dense:
+		return i18n.Text(`Dense`)
+	default:
+		return Option(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum Option) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *Option) UnmarshalText(text []byte) error {
+	*enum = ExtractOption(string(text))
+	return nil
+}
+
+// ExtractOption extracts the value from a string.
+func ExtractOption(str string) Option {
+	for _, enum := range Options {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
@@ -22,6 +22,8 @@ const (
 	CharacterSheets Group = iota
 	CharacterTemplates
 	LootSheets
+	Campaigns
+	Vehicles
 	Editors
 	Images
 	Libraries
@@ -39,6 +41,8 @@ var Groups = []Group{
 	CharacterSheets,
 	CharacterTemplates,
 	LootSheets,
+	Campaigns,
+	Vehicles,
 	Editors,
 	Images,
 	Libraries,
@@ -68,6 +72,10 @@ func (enum Group) Key() string {
 		return "character_templates"
 	case LootSheets:
 		return "loot_sheets"
+	case Campaigns:
+		return "campaigns"
+	case Vehicles:
+		return "vehicles"
 	case Editors:
 		return "editors"
 	case Images:
@@ -96,6 +104,10 @@ func (enum Group) String() string {
 		return i18n.Text(`Character Templates`)
 	case LootSheets:
 		return i18n.Text(`Loot Sheets`)
+	case Campaigns:
+		return i18n.Text(`Campaigns`)
+	case Vehicles:
+		return i18n.Text(`Vehicles`)
 	case Editors:
 		return i18n.Text(`Editors`)
 	case Images:
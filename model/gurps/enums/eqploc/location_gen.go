@@ -0,0 +1,107 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package eqploc
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// Possible values.
+const (
+	Carried Location = iota
+	Equipped
+	Mount
+	Stashed
+	Bank
+)
+
+// LastLocation is the last valid value.
+const LastLocation Location = Bank
+
+// Locations holds all possible values.
+var Locations = []Location{
+	Carried,
+	Equipped,
+	Mount,
+	Stashed,
+	Bank,
+}
+
+// Location holds where a piece of carried equipment is currently kept.
+type Location byte
+
+// EnsureValid ensures this is of a known value.
+func (enum Location) EnsureValid() Location {
+	if enum <= Bank {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum Location) Key() string {
+	switch enum {
+	case Carried:
+		return "carried"
+	case Equipped:
+		return "equipped"
+	case Mount:
+		return "mount"
+	case Stashed:
+		return "stashed"
+	case Bank:
+		return "bank"
+	default:
+		return Location(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum Location) String() string {
+	switch enum {
+	case Carried:
+		return i18n.Text(`Carried`)
+	case Equipped:
+		return i18n.Text(`Equipped`)
+	case Mount:
+		return i18n.Text(`Mount/Vehicle`)
+	case Stashed:
+		return i18n.Text(`Stashed`)
+	case Bank:
+		return i18n.Text(`Bank`)
+	default:
+		return Location(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum Location) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *Location) UnmarshalText(text []byte) error {
+	*enum = ExtractLocation(string(text))
+	return nil
+}
+
+// ExtractLocation extracts the value from a string.
+func ExtractLocation(str string) Location {
+	for _, enum := range Locations {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package portrait
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// Possible values.
+const (
+	FullBody Kind = iota
+	Token
+	Alternate
+)
+
+// LastKind is the last valid value.
+const LastKind Kind = Alternate
+
+// Kinds holds all possible values.
+var Kinds = []Kind{
+	FullBody,
+	Token,
+	Alternate,
+}
+
+// Kind holds the kind of image an additional portrait holds.
+type Kind byte
+
+// EnsureValid ensures this is of a known value.
+func (enum Kind) EnsureValid() Kind {
+	if enum <= Alternate {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum Kind) Key() string {
+	switch enum {
+	case FullBody:
+		return "full_body"
+	case Token:
+		return "token"
+	case Alternate:
+		return "alternate"
+	default:
+		return Kind(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum Kind) String() string {
+	switch enum {
+	case FullBody:
+		return i18n.Text(`Full Body`)
+	case Token:
+		return i18n.Text(`Token`)
+	case Alternate:
+		return i18n.Text(`Alternate Form`)
+	default:
+		return Kind(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum Kind) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *Kind) UnmarshalText(text []byte) error {
+	*enum = ExtractKind(string(text))
+	return nil
+}
+
+// ExtractKind extracts the value from a string.
+func ExtractKind(str string) Kind {
+	for _, enum := range Kinds {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
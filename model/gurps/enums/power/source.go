@@ -0,0 +1,26 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package power
+
+// Modifier returns the percentage cost modifier applied to abilities drawing upon this source.
+func (enum Source) Modifier() int {
+	switch enum {
+	case Biological, Chi, Divine, Magical, Psionic, Super:
+		return -10
+	case Moral, Nature:
+		return -20
+	case Spirit:
+		return -25
+	case Cosmic:
+		return 50
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rpm
+
+// Energy returns the base energy cost of the effect.
+func (enum Effect) Energy() int {
+	switch enum {
+	case Sense:
+		return 2
+	case Strengthen:
+		return 3
+	case Restore:
+		return 4
+	case Control, Destroy:
+		return 5
+	case Create:
+		return 6
+	case Transform:
+		return 8
+	default:
+		return Sense.Energy()
+	}
+}
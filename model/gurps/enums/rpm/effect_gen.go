@@ -0,0 +1,119 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rpm
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// Possible values.
+const (
+	Sense Effect = iota
+	Strengthen
+	Restore
+	Control
+	Destroy
+	Create
+	Transform
+)
+
+// LastEffect is the last valid value.
+const LastEffect Effect = Transform
+
+// Effects holds all possible values.
+var Effects = []Effect{
+	Sense,
+	Strengthen,
+	Restore,
+	Control,
+	Destroy,
+	Create,
+	Transform,
+}
+
+// Effect holds the type of effect a Ritual Path Magic ritual produces.
+type Effect byte
+
+// EnsureValid ensures this is of a known value.
+func (enum Effect) EnsureValid() Effect {
+	if enum <= Transform {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum Effect) Key() string {
+	switch enum {
+	case Sense:
+		return "sense"
+	case Strengthen:
+		return "strengthen"
+	case Restore:
+		return "restore"
+	case Control:
+		return "control"
+	case Destroy:
+		return "destroy"
+	case Create:
+		return "create"
+	case Transform:
+		return "transform"
+	default:
+		return Effect(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum Effect) String() string {
+	switch enum {
+	case Sense:
+		return i18n.Text(`Sense`)
+	case Strengthen:
+		return i18n.Text(`Strengthen`)
+	case Restore:
+		return i18n.Text(`Restore`)
+	case Control:
+		return i18n.Text(`Control`)
+	case Destroy:
+		return i18n.Text(`Destroy`)
+	case Create:
+		return i18n.Text(`Create`)
+	case Transform:
+		return i18n.Text(`Transform`)
+	default:
+		return Effect(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum Effect) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *Effect) UnmarshalText(text []byte) error {
+	*enum = ExtractEffect(string(text))
+	return nil
+}
+
+// ExtractEffect extracts the value from a string.
+func ExtractEffect(str string) Effect {
+	for _, enum := range Effects {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
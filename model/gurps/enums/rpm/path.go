@@ -0,0 +1,18 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rpm
+
+import "github.com/richardwilkes/toolbox/v2/xstrings"
+
+// SkillName returns the name of the skill used for the path. This is not localized, as it must match the skill name
+// used in the data files.
+func (enum Path) SkillName() string {
+	return "Path of " + xstrings.FirstToUpper(enum.Key())
+}
@@ -0,0 +1,131 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rpm
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// Possible values.
+const (
+	Body Path = iota
+	Chance
+	Crossroads
+	Energy
+	Magic
+	Matter
+	Mind
+	Spirit
+	Undead
+)
+
+// LastPath is the last valid value.
+const LastPath Path = Undead
+
+// Paths holds all possible values.
+var Paths = []Path{
+	Body,
+	Chance,
+	Crossroads,
+	Energy,
+	Magic,
+	Matter,
+	Mind,
+	Spirit,
+	Undead,
+}
+
+// Path holds the Ritual Path Magic path a ritual effect draws upon.
+type Path byte
+
+// EnsureValid ensures this is of a known value.
+func (enum Path) EnsureValid() Path {
+	if enum <= Undead {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum Path) Key() string {
+	switch enum {
+	case Body:
+		return "body"
+	case Chance:
+		return "chance"
+	case Crossroads:
+		return "crossroads"
+	case Energy:
+		return "energy"
+	case Magic:
+		return "magic"
+	case Matter:
+		return "matter"
+	case Mind:
+		return "mind"
+	case Spirit:
+		return "spirit"
+	case Undead:
+		return "undead"
+	default:
+		return Path(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum Path) String() string {
+	switch enum {
+	case Body:
+		return i18n.Text(`Body`)
+	case Chance:
+		return i18n.Text(`Chance`)
+	case Crossroads:
+		return i18n.Text(`Crossroads`)
+	case Energy:
+		return i18n.Text(`Energy`)
+	case Magic:
+		return i18n.Text(`Magic`)
+	case Matter:
+		return i18n.Text(`Matter`)
+	case Mind:
+		return i18n.Text(`Mind`)
+	case Spirit:
+		return i18n.Text(`Spirit`)
+	case Undead:
+		return i18n.Text(`Undead`)
+	default:
+		return Path(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum Path) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *Path) UnmarshalText(text []byte) error {
+	*enum = ExtractPath(string(text))
+	return nil
+}
+
+// ExtractPath extracts the value from a string.
+func ExtractPath(str string) Path {
+	for _, enum := range Paths {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package study
+
+import "github.com/richardwilkes/gcs/v5/model/fxp"
+
+// Hours returns the number of study hours required per point.
+func (enum Level) Hours() fxp.Int {
+	switch enum.EnsureValid() {
+	case Level1:
+		return fxp.FromInteger(180)
+	case Level2:
+		return fxp.FromInteger(160)
+	case Level3:
+		return fxp.FromInteger(140)
+	case Level4:
+		return fxp.FromInteger(120)
+	default:
+		return fxp.FromInteger(200)
+	}
+}
@@ -20,8 +20,10 @@ import (
 	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/armor"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/cell"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/display"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/eqploc"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/srcstate"
 	"github.com/richardwilkes/gcs/v5/model/jio"
 	"github.com/richardwilkes/gcs/v5/model/kinds"
@@ -58,6 +60,7 @@ const (
 	EquipmentTagsColumn
 	EquipmentReferenceColumn
 	EquipmentLibSrcColumn
+	EquipmentLocationColumn
 )
 
 // Equipment holds a piece of equipment.
@@ -86,25 +89,32 @@ type EquipmentEditData struct {
 	Quantity     fxp.Int              `json:"quantity"`
 	Level        fxp.Int              `json:"level,omitzero"`
 	Uses         int                  `json:"uses,omitzero"`
-	Equipped     bool                 `json:"equipped,omitzero"`
+	Location     eqploc.Location      `json:"location,omitzero"`
+	AmmoID       tid.TID              `json:"ammo_id,omitzero"`
+	AmmoLoaded   fxp.Int              `json:"ammo_loaded,omitzero"`
 }
 
 // EquipmentSyncData holds the equipment sync data that is common to both containers and non-containers.
 type EquipmentSyncData struct {
-	Name                   string      `json:"description,omitzero"`
-	PageRef                string      `json:"reference,omitzero"`
-	PageRefHighlight       string      `json:"reference_highlight,omitzero"`
-	LocalNotes             string      `json:"local_notes,omitzero"`
-	TechLevel              string      `json:"tech_level,omitzero"`
-	LegalityClass          string      `json:"legality_class,omitzero"`
-	Tags                   []string    `json:"tags,omitzero"`
-	BaseValue              string      `json:"base_value,omitzero"`
-	BaseWeight             string      `json:"base_weight,omitzero"`
-	MaxUses                int         `json:"max_uses,omitzero"`
-	Prereq                 *PrereqList `json:"prereqs,omitzero"`
-	Weapons                []*Weapon   `json:"weapons,omitzero"`
-	Features               Features    `json:"features,omitzero"`
-	WeightIgnoredForSkills bool        `json:"ignore_weight_for_skills,omitzero"`
+	Name                   string             `json:"description,omitzero"`
+	PageRef                string             `json:"reference,omitzero"`
+	PageRefHighlight       string             `json:"reference_highlight,omitzero"`
+	LocalNotes             string             `json:"local_notes,omitzero"`
+	TechLevel              string             `json:"tech_level,omitzero"`
+	LegalityClass          string             `json:"legality_class,omitzero"`
+	Tags                   []string           `json:"tags,omitzero"`
+	BaseValue              string             `json:"base_value,omitzero"`
+	BaseWeight             string             `json:"base_weight,omitzero"`
+	MaxUses                int                `json:"max_uses,omitzero"`
+	Prereq                 *PrereqList        `json:"prereqs,omitzero"`
+	Weapons                []*Weapon          `json:"weapons,omitzero"`
+	Features               Features           `json:"features,omitzero"`
+	Volume                 fxp.Int            `json:"volume,omitzero"`
+	CapacityWeight         fxp.Weight         `json:"capacity_weight,omitzero"` // Only for containers
+	CapacityVolume         fxp.Int            `json:"capacity_volume,omitzero"` // Only for containers
+	ArmorConstruction      armor.Construction `json:"armor_construction,omitzero"`
+	WeightIgnoredForSkills bool               `json:"ignore_weight_for_skills,omitzero"`
+	Provenance             Provenance         `json:"provenance,omitzero"`
 }
 
 type equipmentListData struct {
@@ -143,7 +153,7 @@ func NewEquipment(owner DataOwner, parent *Equipment, container bool) *Equipment
 	e.Name = e.Kind()
 	e.LegalityClass = "4"
 	e.Quantity = fxp.One
-	e.Equipped = true
+	e.Location = eqploc.Equipped
 	e.parent = parent
 	e.owner = owner
 	e.SetOpen(container)
@@ -234,9 +244,12 @@ func (e *Equipment) MarshalJSONTo(enc *jsontext.Encoder) error {
 	defUnits := SheetSettingsFor(EntityFromNode(e)).DefaultWeightUnits
 	data := struct {
 		EquipmentData
-		Calc calc `json:"calc"`
+		// Equipped is still written so that older versions, which know nothing of the location, see the same state.
+		Equipped bool `json:"equipped,omitzero"`
+		Calc     calc `json:"calc"`
 	}{
 		EquipmentData: e.EquipmentData,
+		Equipped:      e.IsEquipped(),
 		Calc: calc{
 			Value:                   e.AdjustedValue(),
 			ExtendedValue:           e.ExtendedValue(),
@@ -268,6 +281,7 @@ func (e *Equipment) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 		Value      fxp.Int    `json:"value"`
 		Weight     fxp.Weight `json:"weight"`
 		IsOpen     bool       `json:"open"`
+		Equipped   bool       `json:"equipped"`
 	}
 	if err := json.UnmarshalDecode(dec, &localData); err != nil {
 		return err
@@ -279,6 +293,9 @@ func (e *Equipment) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 		setOpen = localData.IsOpen
 	}
 	e.EquipmentData = localData.EquipmentData
+	if localData.Equipped && e.Location == eqploc.Carried {
+		e.Location = eqploc.Equipped
+	}
 	if e.BaseValue == "" && localData.Value != 0 {
 		e.BaseValue = localData.Value.String()
 	}
@@ -325,9 +342,10 @@ func EquipmentHeaderData(columnID int, provider EquipmentListProvider, carried,
 				if _, ok := provider.(*Template); ok {
 					title = i18n.Text("Equipment")
 				}
+				locations := SheetSettingsFor(provider.DataOwner().OwningEntity()).EquipmentLocations
 				for _, one := range provider.CarriedEquipmentList() {
-					weight += one.ExtendedWeight(false, units)
-					value += one.ExtendedValue()
+					weight += one.ExtendedWeightAtLocations(false, units, locations.CountsTowardEncumbrance)
+					value += one.ExtendedValueAtLocations(locations.CountsTowardValue)
 				}
 				data.Title = fmt.Sprintf(i18n.Text("%s (%s; $%s)"), title, units.Format(weight), value.Comma())
 			} else {
@@ -379,6 +397,9 @@ func EquipmentHeaderData(columnID int, provider EquipmentListProvider, carried,
 		data.Title = HeaderDatabase
 		data.TitleIsImageKey = true
 		data.Detail = LibSrcTooltip()
+	case EquipmentLocationColumn:
+		data.Title = i18n.Text("Location")
+		data.Detail = i18n.Text("Where this piece of equipment is currently kept. The sheet settings control which locations count toward the carried weight and value.")
 	}
 	return data
 }
@@ -395,7 +416,7 @@ func (e *Equipment) CellData(columnID int, data *CellData) {
 	switch columnID {
 	case EquipmentEquippedColumn:
 		data.Type = cell.Toggle
-		data.Checked = e.Equipped
+		data.Checked = e.IsEquipped()
 		data.Alignment = align.Middle
 		data.Tooltip = i18n.Text("Click to toggle whether this piece of equipment is equipped or just carried. Items that are not equipped do not apply any features they may normally contribute to the character. Note that if a parent container is not equipped, none of its contents are considered to be equipped either and any checkmark here will be dimmed to reflect this.")
 		if !e.ReallyEquipped() {
@@ -411,6 +432,12 @@ func (e *Equipment) CellData(columnID int, data *CellData) {
 		data.Secondary = e.SecondaryText(func(option display.Option) bool { return option.Inline() })
 		data.UnsatisfiedReason = e.UnsatisfiedReason
 		data.Tooltip = e.SecondaryText(func(option display.Option) bool { return option.Tooltip() })
+		data.Tooltip = e.Provenance.AppendToTooltip(data.Tooltip)
+		defUnits := SheetSettingsFor(EntityFromNode(e)).DefaultWeightUnits
+		if percent, ok := e.CapacityFill(defUnits); ok {
+			data.CapacityFill = fmt.Sprintf(i18n.Text("%s%% full"), percent.Floor().Comma())
+			data.CapacityProblem = e.CapacityProblem(defUnits)
+		}
 	case EquipmentTLColumn:
 		data.Type = cell.Text
 		data.Primary = e.TechLevel
@@ -459,17 +486,21 @@ func (e *Equipment) CellData(columnID int, data *CellData) {
 				data.Tooltip += "\n" + e.Source.String()
 			}
 		}
+		data.Tooltip = e.Provenance.AppendToTooltip(data.Tooltip)
+	case EquipmentLocationColumn:
+		data.Type = cell.Text
+		data.Primary = e.Location.String()
 	}
 }
 
 // ReallyEquipped returns true if this equipment is equipped and has a quantity > 0 and all of its parents do too.
 func (e *Equipment) ReallyEquipped() bool {
-	if !e.Equipped || e.Quantity <= 0 {
+	if !e.IsEquipped() || e.Quantity <= 0 {
 		return false
 	}
 	p := e.parent
 	for p != nil {
-		if !p.Equipped || p.Quantity <= 0 {
+		if !p.IsEquipped() || p.Quantity <= 0 {
 			return false
 		}
 		p = p.parent
@@ -542,6 +573,12 @@ func (e *Equipment) SecondaryText(optionChecker func(display.Option) bool) strin
 			fmt.Fprintf(&localBuffer, i18n.Text("%s of %s uses left"), xstrings.CommaInt(e.Uses),
 				xstrings.CommaInt(e.MaxUses))
 		}
+		if status := e.AmmoStatus(); status != "" {
+			if localBuffer.Len() != 0 {
+				localBuffer.WriteString("; ")
+			}
+			localBuffer.WriteString(status)
+		}
 		if localNotes := e.ResolveLocalNotes(); localNotes != "" {
 			if localBuffer.Len() != 0 {
 				localBuffer.WriteString("; ")
@@ -661,6 +698,13 @@ func (e *Equipment) ExtendedWeight(forSkills bool, defUnits fxp.WeightUnit) fxp.
 
 // ExtendedWeightAdjustedForModifiers calculates the extended weight.
 func ExtendedWeightAdjustedForModifiers(equipment *Equipment, defUnits fxp.WeightUnit, qty fxp.Int, baseWeight fxp.Weight, modifiers []*EquipmentModifier, features Features, children []*Equipment, forSkills, weightIgnoredForSkills bool) fxp.Weight {
+	return extendedWeight(equipment, defUnits, qty, baseWeight, modifiers, features, children, forSkills,
+		weightIgnoredForSkills, nil)
+}
+
+// extendedWeight calculates the extended weight. If include is not nil, contents whose location it does not accept are
+// skipped.
+func extendedWeight(equipment *Equipment, defUnits fxp.WeightUnit, qty fxp.Int, baseWeight fxp.Weight, modifiers []*EquipmentModifier, features Features, children []*Equipment, forSkills, weightIgnoredForSkills bool, include func(eqploc.Location) bool) fxp.Weight {
 	if qty <= 0 {
 		return 0
 	}
@@ -671,7 +715,11 @@ func ExtendedWeightAdjustedForModifiers(equipment *Equipment, defUnits fxp.Weigh
 	if len(children) != 0 {
 		var contained fxp.Int
 		for _, one := range children {
-			contained += fxp.Int(one.ExtendedWeight(forSkills, defUnits))
+			if include == nil {
+				contained += fxp.Int(one.ExtendedWeight(forSkills, defUnits))
+			} else {
+				contained += fxp.Int(one.ExtendedWeightAtLocations(forSkills, defUnits, include))
+			}
 		}
 		var percentage, reduction fxp.Int
 		for _, one := range features {
@@ -847,6 +895,8 @@ func (e *Equipment) Kind() string {
 func (e *Equipment) ClearUnusedFieldsForType() {
 	if !e.Container() {
 		e.Children = nil
+		e.CapacityWeight = 0
+		e.CapacityVolume = 0
 	}
 }
 
@@ -875,6 +925,19 @@ func (e *Equipment) SyncWithSource() {
 	}
 }
 
+// SourceChanges returns the individual differences between this data and its source, if any. Each may be applied
+// separately, rather than syncing everything as SyncWithSource() does.
+func (e *Equipment) SourceChanges() []*SourceChange {
+	if !xreflect.IsNil(e.owner) {
+		if state, data := e.owner.SourceMatcher().Match(e); state == srcstate.Mismatched {
+			if other, ok := data.(*Equipment); ok {
+				return collectSourceChanges(&e.EquipmentSyncData, &other.EquipmentSyncData)
+			}
+		}
+	}
+	return nil
+}
+
 // Hash writes this object's contents into the hasher. Note that this only hashes the data that is considered to be
 // "source" data, i.e. not expected to be modified by the user after copying from a library.
 func (e *Equipment) Hash(h hash.Hash) {
@@ -904,7 +967,12 @@ func (e *EquipmentSyncData) hash(h hash.Hash) {
 	for _, feature := range e.Features {
 		feature.Hash(h)
 	}
+	xhash.Num64(h, e.Volume)
+	xhash.Num64(h, e.CapacityWeight)
+	xhash.Num64(h, e.CapacityVolume)
+	xhash.Num8(h, e.ArmorConstruction)
 	xhash.Bool(h, e.WeightIgnoredForSkills)
+	e.Provenance.hash(h)
 }
 
 // CopyFrom implements node.EditorData.
@@ -0,0 +1,83 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// HasCapacity returns true if this is a container with a weight or volume capacity.
+func (e *Equipment) HasCapacity() bool {
+	return e.Container() && (e.CapacityWeight > 0 || e.CapacityVolume > 0)
+}
+
+// ContentsWeight returns the weight of the container's contents. Unlike ExtendedWeight(), this does not apply any
+// contained weight reductions the container has, since those reduce the weight felt by the one carrying the container,
+// not the amount the container actually holds.
+func (e *Equipment) ContentsWeight(defUnits fxp.WeightUnit) fxp.Weight {
+	var weight fxp.Weight
+	for _, one := range e.Children {
+		weight += one.ExtendedWeight(false, defUnits)
+	}
+	return weight
+}
+
+// ContentsVolume returns the volume taken up by the container's contents. Contained containers count only their own
+// volume, since their contents are inside them.
+func (e *Equipment) ContentsVolume() fxp.Int {
+	var volume fxp.Int
+	for _, one := range e.Children {
+		if one.Quantity > 0 {
+			volume += one.Volume.Mul(one.Quantity)
+		}
+	}
+	return volume
+}
+
+// CapacityFill returns how full the container is, as a percentage of its capacity. When both a weight and a volume
+// capacity are set, the larger of the two percentages is returned. Returns false if the container has no capacity.
+func (e *Equipment) CapacityFill(defUnits fxp.WeightUnit) (percent fxp.Int, ok bool) {
+	if !e.HasCapacity() {
+		return 0, false
+	}
+	if e.CapacityWeight > 0 {
+		percent = fxp.Int(e.ContentsWeight(defUnits)).Mul(fxp.Hundred).Div(fxp.Int(e.CapacityWeight))
+	}
+	if e.CapacityVolume > 0 {
+		percent = max(percent, e.ContentsVolume().Mul(fxp.Hundred).Div(e.CapacityVolume))
+	}
+	return percent, true
+}
+
+// CapacityProblem returns a description of how the container's contents exceed its capacity, or an empty string if
+// they don't.
+func (e *Equipment) CapacityProblem(defUnits fxp.WeightUnit) string {
+	if !e.HasCapacity() {
+		return ""
+	}
+	var problems []string
+	if e.CapacityWeight > 0 {
+		if weight := e.ContentsWeight(defUnits); weight > e.CapacityWeight {
+			problems = append(problems, fmt.Sprintf(i18n.Text("Contents weigh %s, but it can hold no more than %s"),
+				defUnits.Format(weight), defUnits.Format(e.CapacityWeight)))
+		}
+	}
+	if e.CapacityVolume > 0 {
+		if volume := e.ContentsVolume(); volume > e.CapacityVolume {
+			problems = append(problems, fmt.Sprintf(i18n.Text("Contents take up %s, but it can hold no more than %s"),
+				volume.Comma(), e.CapacityVolume.Comma()))
+		}
+	}
+	return strings.Join(problems, "\n")
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestEquipmentCapacity(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	bag := NewEquipment(e, nil, true)
+	bag.BaseWeight = "1 lb"
+	rope := NewEquipment(e, bag, false)
+	rope.BaseWeight = "3 lb"
+	rope.Volume = fxp.Two
+	rope.Quantity = fxp.Two
+	bag.Children = []*Equipment{rope}
+
+	_, ok := bag.CapacityFill(fxp.Pound)
+	c.False(ok)
+	c.Equal("", bag.CapacityProblem(fxp.Pound))
+
+	bag.CapacityWeight = fxp.Weight(fxp.FromInteger(8))
+	percent, ok := bag.CapacityFill(fxp.Pound)
+	c.True(ok)
+	c.Equal(fxp.FromInteger(75), percent)
+	c.Equal("", bag.CapacityProblem(fxp.Pound))
+
+	bag.CapacityVolume = fxp.Two
+	percent, _ = bag.CapacityFill(fxp.Pound)
+	c.Equal(fxp.FromInteger(200), percent)
+	c.NotEqual("", bag.CapacityProblem(fxp.Pound))
+	bag.CapacityVolume = 0
+
+	// A container that ignores the weight of its contents still only holds so much
+	cwr := NewContainedWeightReduction()
+	cwr.Reduction = "100%"
+	bag.Features = Features{cwr}
+	c.Equal(fxp.Weight(fxp.One), bag.ExtendedWeight(false, fxp.Pound))
+	c.Equal(fxp.Weight(fxp.FromInteger(6)), bag.ContentsWeight(fxp.Pound))
+	bag.CapacityWeight = fxp.Weight(fxp.FromInteger(5))
+	percent, _ = bag.CapacityFill(fxp.Pound)
+	c.Equal(fxp.FromInteger(120), percent)
+	c.NotEqual("", bag.CapacityProblem(fxp.Pound))
+
+	bag.Children = nil
+	bag.TID = rope.TID
+	bag.ClearUnusedFieldsForType()
+	c.Equal(fxp.Weight(0), bag.CapacityWeight)
+	c.False(bag.HasCapacity())
+}
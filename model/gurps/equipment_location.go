@@ -0,0 +1,110 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/eqploc"
+)
+
+// EquipmentLocationSettings holds the settings that control which equipment locations count toward the carried weight
+// and value totals.
+type EquipmentLocationSettings struct {
+	ExcludedFromEncumbrance []eqploc.Location `json:"excluded_from_encumbrance,omitzero"`
+	ExcludedFromValue       []eqploc.Location `json:"excluded_from_value,omitzero"`
+}
+
+// NewEquipmentLocationSettings creates new EquipmentLocationSettings with factory defaults. Equipment that has been left
+// on a mount or vehicle, stashed, or placed in a bank doesn't count toward either total.
+func NewEquipmentLocationSettings() *EquipmentLocationSettings {
+	return &EquipmentLocationSettings{
+		ExcludedFromEncumbrance: []eqploc.Location{eqploc.Mount, eqploc.Stashed, eqploc.Bank},
+		ExcludedFromValue:       []eqploc.Location{eqploc.Mount, eqploc.Stashed, eqploc.Bank},
+	}
+}
+
+// Clone creates a copy of this.
+func (s *EquipmentLocationSettings) Clone() *EquipmentLocationSettings {
+	return &EquipmentLocationSettings{
+		ExcludedFromEncumbrance: slices.Clone(s.ExcludedFromEncumbrance),
+		ExcludedFromValue:       slices.Clone(s.ExcludedFromValue),
+	}
+}
+
+// CountsTowardEncumbrance returns true if equipment at the given location counts toward the carried weight.
+func (s *EquipmentLocationSettings) CountsTowardEncumbrance(loc eqploc.Location) bool {
+	return !slices.Contains(s.ExcludedFromEncumbrance, loc)
+}
+
+// SetCountsTowardEncumbrance sets whether equipment at the given location counts toward the carried weight.
+func (s *EquipmentLocationSettings) SetCountsTowardEncumbrance(loc eqploc.Location, counts bool) {
+	s.ExcludedFromEncumbrance = setLocationExcluded(s.ExcludedFromEncumbrance, loc, !counts)
+}
+
+// CountsTowardValue returns true if equipment at the given location counts toward the carried value.
+func (s *EquipmentLocationSettings) CountsTowardValue(loc eqploc.Location) bool {
+	return !slices.Contains(s.ExcludedFromValue, loc)
+}
+
+// SetCountsTowardValue sets whether equipment at the given location counts toward the carried value.
+func (s *EquipmentLocationSettings) SetCountsTowardValue(loc eqploc.Location, counts bool) {
+	s.ExcludedFromValue = setLocationExcluded(s.ExcludedFromValue, loc, !counts)
+}
+
+func setLocationExcluded(list []eqploc.Location, loc eqploc.Location, excluded bool) []eqploc.Location {
+	list = slices.DeleteFunc(list, func(one eqploc.Location) bool { return one == loc })
+	if excluded {
+		list = append(list, loc)
+		slices.Sort(list)
+	}
+	return list
+}
+
+// IsEquipped returns true if this equipment has been marked as equipped. See ReallyEquipped() for whether it is
+// actually being used.
+func (e *Equipment) IsEquipped() bool {
+	return e.Location == eqploc.Equipped
+}
+
+// SetEquipped sets whether this equipment is equipped or just carried.
+func (e *Equipment) SetEquipped(equipped bool) {
+	if equipped {
+		e.Location = eqploc.Equipped
+	} else {
+		e.Location = eqploc.Carried
+	}
+}
+
+// ExtendedValueAtLocations returns the extended value, skipping any equipment (including contents) whose location is
+// not accepted by include.
+func (e *Equipment) ExtendedValueAtLocations(include func(eqploc.Location) bool) fxp.Int {
+	if e.Quantity <= 0 || !include(e.Location) {
+		return 0
+	}
+	value := e.AdjustedValue()
+	if e.Container() {
+		for _, one := range e.Children {
+			value += one.ExtendedValueAtLocations(include)
+		}
+	}
+	return value.Mul(e.Quantity)
+}
+
+// ExtendedWeightAtLocations returns the extended weight, skipping any equipment (including contents) whose location is
+// not accepted by include.
+func (e *Equipment) ExtendedWeightAtLocations(forSkills bool, defUnits fxp.WeightUnit, include func(eqploc.Location) bool) fxp.Weight {
+	if !include(e.Location) {
+		return 0
+	}
+	return extendedWeight(e, defUnits, e.Quantity, e.ResolvedBaseWeight(), e.Modifiers, e.Features, e.Children,
+		forSkills, e.WeightIgnoredForSkills && e.ReallyEquipped(), include)
+}
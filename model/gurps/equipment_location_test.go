@@ -0,0 +1,83 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/eqploc"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestEquipmentLocationFromOldEquippedFlag(t *testing.T) {
+	c := check.New(t)
+	var eqp Equipment
+	c.NoError(json.Unmarshal([]byte(`{"id":"e1234567890abcdef","quantity":1,"equipped":true}`), &eqp))
+	c.Equal(eqploc.Equipped, eqp.Location)
+	c.True(eqp.IsEquipped())
+	c.NoError(json.Unmarshal([]byte(`{"id":"e1234567890abcdef","quantity":1}`), &eqp))
+	c.Equal(eqploc.Carried, eqp.Location)
+	c.NoError(json.Unmarshal([]byte(`{"id":"e1234567890abcdef","quantity":1,"location":"bank"}`), &eqp))
+	c.Equal(eqploc.Bank, eqp.Location)
+}
+
+func TestEquipmentLocationRoundTrip(t *testing.T) {
+	c := check.New(t)
+	var eqp Equipment
+	c.NoError(json.Unmarshal([]byte(`{"id":"e1234567890abcdef","quantity":1,"equipped":true}`), &eqp))
+	data, err := json.Marshal(&eqp)
+	c.NoError(err)
+	var fields map[string]any
+	c.NoError(json.Unmarshal(data, &fields))
+	c.Equal(true, fields["equipped"], "older versions still see the item as equipped")
+	c.Equal("equipped", fields["location"])
+	var reloaded Equipment
+	c.NoError(json.Unmarshal(data, &reloaded))
+	c.Equal(eqploc.Equipped, reloaded.Location)
+
+	eqp.Location = eqploc.Bank
+	data, err = json.Marshal(&eqp)
+	c.NoError(err)
+	fields = nil
+	c.NoError(json.Unmarshal(data, &fields))
+	_, ok := fields["equipped"]
+	c.False(ok)
+	c.Equal("bank", fields["location"])
+	reloaded = Equipment{}
+	c.NoError(json.Unmarshal(data, &reloaded))
+	c.Equal(eqploc.Bank, reloaded.Location)
+}
+
+func TestEquipmentLocationTotals(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	sword := NewEquipment(nil, nil, false)
+	sword.BaseWeight = "3 lb"
+	sword.BaseValue = "500"
+	tent := NewEquipment(nil, nil, false)
+	tent.BaseWeight = "20 lb"
+	tent.BaseValue = "100"
+	tent.Location = eqploc.Mount
+	GiveEquipment(e, LibraryFile{}, []*Equipment{sword, tent})
+
+	c.Equal(fxp.WeightFromInteger(3, fxp.Pound), e.WeightCarried(false))
+	c.Equal(fxp.FromInteger(500), e.WealthCarried())
+	c.Equal(fxp.FromInteger(100), e.WealthNotCarried())
+
+	e.SheetSettings.EquipmentLocations.SetCountsTowardEncumbrance(eqploc.Mount, true)
+	c.Equal(fxp.WeightFromInteger(23, fxp.Pound), e.WeightCarried(false))
+	c.Equal(fxp.FromInteger(500), e.WealthCarried())
+
+	e.SheetSettings.EquipmentLocations.SetCountsTowardValue(eqploc.Mount, true)
+	c.Equal(fxp.FromInteger(600), e.WealthCarried())
+	c.Equal(fxp.FromInteger(0), e.WealthNotCarried())
+}
@@ -85,11 +85,12 @@ type EquipmentModifierEditDataNonContainerOnly struct {
 
 // EquipmentModifierSyncData holds the EquipmentModifier sync data that is common to both containers and non-containers.
 type EquipmentModifierSyncData struct {
-	Name             string   `json:"name,omitzero"`
-	PageRef          string   `json:"reference,omitzero"`
-	PageRefHighlight string   `json:"reference_highlight,omitzero"`
-	LocalNotes       string   `json:"local_notes,omitzero"`
-	Tags             []string `json:"tags,omitzero"`
+	Name             string     `json:"name,omitzero"`
+	PageRef          string     `json:"reference,omitzero"`
+	PageRefHighlight string     `json:"reference_highlight,omitzero"`
+	LocalNotes       string     `json:"local_notes,omitzero"`
+	Tags             []string   `json:"tags,omitzero"`
+	Provenance       Provenance `json:"provenance,omitzero"`
 }
 
 // EquipmentModifierNonContainerSyncData holds the EquipmentModifier sync data that is only applicable to Equipment
@@ -320,6 +321,7 @@ func (e *EquipmentModifier) CellData(columnID int, data *CellData) {
 		data.Primary = e.NameWithReplacements()
 		data.Secondary = e.SecondaryText(func(option display.Option) bool { return option.Inline() })
 		data.Tooltip = e.SecondaryText(func(option display.Option) bool { return option.Tooltip() })
+		data.Tooltip = e.Provenance.AppendToTooltip(data.Tooltip)
 	case EquipmentModifierTechLevelColumn:
 		if !e.Container() {
 			data.Type = cell.Text
@@ -357,6 +359,7 @@ func (e *EquipmentModifier) CellData(columnID int, data *CellData) {
 				data.Tooltip += "\n" + e.Source.String()
 			}
 		}
+		data.Tooltip = e.Provenance.AppendToTooltip(data.Tooltip)
 	}
 }
 
@@ -770,6 +773,7 @@ func (e *EquipmentModifierSyncData) hash(h hash.Hash) {
 	for _, tag := range e.Tags {
 		xhash.StringWithLen(h, tag)
 	}
+	e.Provenance.hash(h)
 }
 
 func (e *EquipmentModifierNonContainerSyncData) hash(h hash.Hash) {
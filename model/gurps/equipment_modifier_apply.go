@@ -0,0 +1,52 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+)
+
+// EquipmentModifierPreview holds the combined value and weight of a set of equipment before and after an equipment
+// modifier is applied to each of them. The contents of containers are not included.
+type EquipmentModifierPreview struct {
+	ValueBefore  fxp.Int
+	ValueAfter   fxp.Int
+	WeightBefore fxp.Weight
+	WeightAfter  fxp.Weight
+}
+
+// PreviewEquipmentModifier returns how applying the modifier to each of the equipment would change their combined
+// value and weight, without altering them.
+func PreviewEquipmentModifier(equipment []*Equipment, modifier *EquipmentModifier, defUnits fxp.WeightUnit) EquipmentModifierPreview {
+	var preview EquipmentModifierPreview
+	for _, e := range equipment {
+		if e.Quantity <= 0 {
+			continue
+		}
+		modifiers := append(slices.Clone(e.Modifiers), modifier.Clone(LibraryFile{}, e.DataOwner(), nil, false))
+		value := e.ResolvedBaseValue()
+		preview.ValueBefore += ValueAdjustedForModifiers(e, value, e.Modifiers).Mul(e.Quantity)
+		preview.ValueAfter += ValueAdjustedForModifiers(e, value, modifiers).Mul(e.Quantity)
+		weight := e.ResolvedBaseWeight()
+		preview.WeightBefore += fxp.Weight(fxp.Int(WeightAdjustedForModifiers(e, weight, e.Modifiers, defUnits)).Mul(e.Quantity))
+		preview.WeightAfter += fxp.Weight(fxp.Int(WeightAdjustedForModifiers(e, weight, modifiers, defUnits)).Mul(e.Quantity))
+	}
+	return preview
+}
+
+// ApplyEquipmentModifier adds a copy of the modifier, along with any children it has, to each of the equipment. from
+// is the library the modifier came from, if any.
+func ApplyEquipmentModifier(equipment []*Equipment, from LibraryFile, modifier *EquipmentModifier) {
+	for _, e := range equipment {
+		e.Modifiers = append(e.Modifiers, modifier.Clone(from, e.DataOwner(), nil, false))
+	}
+}
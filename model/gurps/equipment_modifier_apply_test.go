@@ -0,0 +1,47 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestApplyEquipmentModifier(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	sword := NewEquipment(e, nil, false)
+	sword.BaseValue = "100"
+	sword.Quantity = fxp.Two
+	shield := NewEquipment(e, nil, false)
+	shield.BaseValue = "50"
+	equipment := []*Equipment{sword, shield}
+
+	fine := NewEquipmentModifier(nil, nil, false)
+	fine.Name = "Fine"
+	fine.CostAmount = "x2"
+
+	preview := PreviewEquipmentModifier(equipment, fine, fxp.Pound)
+	c.Equal(fxp.FromInteger(250), preview.ValueBefore)
+	c.Equal(fxp.FromInteger(500), preview.ValueAfter)
+	c.Equal(preview.WeightBefore, preview.WeightAfter)
+	c.Equal(0, len(sword.Modifiers))
+
+	ApplyEquipmentModifier(equipment, LibraryFile{}, fine)
+	for _, one := range equipment {
+		c.Equal(1, len(one.Modifiers))
+		c.Equal("Fine", one.Modifiers[0].Name)
+		c.NotEqual(fine, one.Modifiers[0])
+	}
+	c.NotEqual(sword.Modifiers[0], shield.Modifiers[0])
+	c.Equal(fxp.FromInteger(400), sword.ExtendedValue())
+}
@@ -79,14 +79,40 @@ func (p *EquippedEquipmentPrereq) Satisfied(entity *Entity, exclude any, tooltip
 	if !satisfied {
 		*hasEquipmentPenalty = true
 		if tooltip != nil {
-			fmt.Fprintf(tooltip, i18n.Text("%sHas equipment which is equipped and whose name %s %s"),
-				prefix, p.NameCriteria.String(replacements),
-				p.TagsCriteria.StringWithPrefix(replacements, i18n.Text("and at least one tag"), i18n.Text("and all tags")))
+			tooltip.WriteString(prefix)
+			tooltip.WriteString(p.requirement(replacements))
 		}
 	}
 	return satisfied
 }
 
+func (p *EquippedEquipmentPrereq) requirement(replacements map[string]string) string {
+	return fmt.Sprintf(i18n.Text("Has equipment which is equipped and whose name %s %s"),
+		p.NameCriteria.String(replacements),
+		p.TagsCriteria.StringWithPrefix(replacements, i18n.Text("and at least one tag"), i18n.Text("and all tags")))
+}
+
+func (p *EquippedEquipmentPrereq) explain(entity *Entity, exclude any) (requirement, current string) {
+	var replacements map[string]string
+	if na, ok := exclude.(nameable.Accesser); ok {
+		replacements = na.NameableReplacements()
+	}
+	current = i18n.Text("None")
+	Traverse(func(eqp *Equipment) bool {
+		if exclude == eqp || !p.NameCriteria.Matches(replacements, eqp.NameWithReplacements()) ||
+			!p.TagsCriteria.MatchesList(replacements, eqp.Tags...) {
+			return false
+		}
+		if eqp.ReallyEquipped() {
+			current = i18n.Text("Equipped")
+			return true
+		}
+		current = i18n.Text("Not equipped")
+		return false
+	}, false, false, entity.CarriedEquipment...)
+	return p.requirement(replacements), current
+}
+
 // Hash writes this object's contents into the hasher.
 func (p *EquippedEquipmentPrereq) Hash(h hash.Hash) {
 	if p == nil {
@@ -73,6 +73,7 @@ type exportedHitLocation struct {
 	Where     string
 	Penalty   int
 	DR        string
+	Armor     string
 	Notes     string
 	Depth     int
 }
@@ -114,6 +115,7 @@ type exportedSpell struct {
 	Notes             string
 	Rituals           string
 	UnsatisfiedReason string
+	UnmetPrereqs      []*PrereqExplanation
 	PageRef           string
 	Tags              []string
 	Depth             int
@@ -130,6 +132,7 @@ type exportedEquipment struct {
 	TechLevel         string
 	LegalityClass     string
 	UnsatisfiedReason string
+	UnmetPrereqs      []*PrereqExplanation
 	PageRef           string
 	Tags              []string
 	Depth             int
@@ -162,6 +165,7 @@ type exportedSkill struct {
 	ModifierNotes     string
 	Notes             string
 	UnsatisfiedReason string
+	UnmetPrereqs      []*PrereqExplanation
 	PageRef           string
 	Tags              []string
 	Depth             int
@@ -177,6 +181,7 @@ type exportedTrait struct {
 	ModifierNotes     string
 	Notes             string
 	UnsatisfiedReason string
+	UnmetPrereqs      []*PrereqExplanation
 	PageRef           string
 	Tags              []string
 	Depth             int
@@ -194,6 +199,31 @@ type exportedConditionalModifier struct {
 	Sources   []*exportedSource
 }
 
+type exportedReactionTotal struct {
+	Kind   string
+	Title  string
+	Amount fxp.Int
+}
+
+type exportedReactionGroup struct {
+	Group   string
+	Total   fxp.Int
+	Totals  []*exportedReactionTotal
+	Sources []*exportedSource
+}
+
+type exportedCustomBlockField struct {
+	Label string
+	Value string
+}
+
+type exportedCustomBlock struct {
+	Key    string
+	Title  string
+	Fields []*exportedCustomBlockField
+	Text   string
+}
+
 type exportedLift struct {
 	Basic         string
 	OneHanded     string
@@ -287,6 +317,8 @@ type exportedEntity struct {
 	BodyType                exportedBodyType
 	Reactions               []*exportedConditionalModifier
 	ConditionalModifiers    []*exportedConditionalModifier
+	ReactionSummary         []*exportedReactionGroup
+	CustomBlocks            []*exportedCustomBlock
 	Traits                  []*exportedTrait
 	Skills                  []*exportedSkill
 	Spells                  []*exportedSpell
@@ -296,6 +328,9 @@ type exportedEntity struct {
 	RangedWeapons           []*exportedRangedWeapon
 	GridTemplate            htmltmpl.CSS
 	Page                    exportedPage
+	// Entity provides access to the full entity model, including methods that compute values, for anything not
+	// already provided by the other fields.
+	Entity *Entity
 }
 
 // ExportSheets exports the files to a text representation.
@@ -318,7 +353,10 @@ func ExportSheets(templatePath string, fileList []string) error {
 	return nil
 }
 
-// Export an Entity to exportPath using the template found at templatePath.
+// Export an Entity to exportPath using the template found at templatePath. If the first line of the template is
+// "GCS HTML Template v1" or "GCS Text Template v1", the remainder is parsed with Go's html/template or text/template
+// package, respectively, and executed with the entity's exported data. Otherwise, the template is treated as a legacy
+// text template.
 func Export(entity *Entity, templatePath, exportPath string) error {
 	tmpl, err := os.ReadFile(templatePath)
 	if err != nil {
@@ -361,6 +399,7 @@ func createTemplateFuncs() texttmpl.FuncMap {
 		"numberFrom":    numberFrom,
 		"numberToFloat": fxp.AsFloat[float64],
 		"numberToInt":   fxp.AsInteger[int],
+		"pageRefLinks":  PageRefLinks,
 		"repeat":        strings.Repeat,
 		"replace":       strings.ReplaceAll,
 		"split":         strings.Split,
@@ -408,6 +447,7 @@ func export(entity *Entity, tmpl exporter, exportPath string) (err error) {
 	}()
 	pb := entity.PointsBreakdown()
 	data := &exportedEntity{
+		Entity:       entity,
 		Name:         entity.Profile.Name,
 		Player:       entity.Profile.PlayerName,
 		CreatedOn:    entity.CreatedOn.String(),
@@ -448,6 +488,8 @@ func export(entity *Entity, tmpl exporter, exportPath string) (err error) {
 		},
 		Reactions:            newExportedConditionalModifiers(entity.Reactions()),
 		ConditionalModifiers: newExportedConditionalModifiers(entity.ConditionalModifiers()),
+		ReactionSummary:      newExportedReactionSummary(entity.ReactionSummary()),
+		CustomBlocks:         newExportedCustomBlocks(entity),
 		Equipment: exportedAllEquipment{
 			Carried:       newExportedEquipment(entity, entity.CarriedEquipment, true),
 			CarriedValue:  entity.WealthCarried(),
@@ -514,6 +556,7 @@ func export(entity *Entity, tmpl exporter, exportPath string) (err error) {
 			ModifierNotes:     t.ModifierNotes(),
 			Notes:             t.Notes(),
 			UnsatisfiedReason: t.UnsatisfiedReason,
+			UnmetPrereqs:      unmetPrereqs(t.UnsatisfiedReason, t.PrereqExplanation),
 			PageRef:           t.PageRef,
 			Tags:              slices.Clone(t.Tags),
 			Depth:             t.Depth(),
@@ -540,6 +583,7 @@ func export(entity *Entity, tmpl exporter, exportPath string) (err error) {
 			ModifierNotes:     s.ModifierNotes(),
 			Notes:             s.Notes(),
 			UnsatisfiedReason: s.UnsatisfiedReason,
+			UnmetPrereqs:      unmetPrereqs(s.UnsatisfiedReason, s.PrereqExplanation),
 			PageRef:           s.PageRef,
 			Tags:              slices.Clone(s.Tags),
 			Depth:             s.Depth(),
@@ -573,6 +617,7 @@ func export(entity *Entity, tmpl exporter, exportPath string) (err error) {
 			Notes:             s.Notes(),
 			Rituals:           s.Rituals(),
 			UnsatisfiedReason: s.UnsatisfiedReason,
+			UnmetPrereqs:      unmetPrereqs(s.UnsatisfiedReason, s.PrereqExplanation),
 			PageRef:           s.PageRef,
 			Tags:              slices.Clone(s.Tags),
 			Depth:             s.Depth(),
@@ -690,6 +735,61 @@ func newExportedConditionalModifiers(list []*ConditionalModifier) []*exportedCon
 	return result
 }
 
+func newExportedReactionSummary(groups []*ReactionGroup) []*exportedReactionGroup {
+	kinds := ReactionSourceKinds(groups)
+	result := make([]*exportedReactionGroup, 0, len(groups))
+	for _, g := range groups {
+		r := &exportedReactionGroup{
+			Group:   g.Group,
+			Total:   g.Total(),
+			Totals:  make([]*exportedReactionTotal, 0, len(kinds)),
+			Sources: make([]*exportedSource, 0, len(g.Contributions)),
+		}
+		for _, kind := range kinds {
+			r.Totals = append(r.Totals, &exportedReactionTotal{
+				Kind:   kind.Key(),
+				Title:  kind.String(),
+				Amount: g.TotalFor(kind),
+			})
+		}
+		for _, one := range g.Contributions {
+			r.Sources = append(r.Sources, &exportedSource{
+				Source: one.Source,
+				Amount: one.Amount,
+			})
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+func newExportedCustomBlocks(entity *Entity) []*exportedCustomBlock {
+	result := make([]*exportedCustomBlock, 0, len(entity.SheetSettings.CustomBlocks))
+	for _, block := range entity.SheetSettings.CustomBlocks {
+		b := &exportedCustomBlock{
+			Key:    block.Key(),
+			Title:  block.TitleOrDefault(),
+			Fields: make([]*exportedCustomBlockField, 0, len(block.Fields)),
+			Text:   block.Text,
+		}
+		for _, field := range block.Fields {
+			b.Fields = append(b.Fields, &exportedCustomBlockField{
+				Label: field.Label,
+				Value: field.ResolvedValue(entity),
+			})
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+func unmetPrereqs(unsatisfiedReason string, explain func() *PrereqExplanation) []*PrereqExplanation {
+	if unsatisfiedReason == "" {
+		return nil
+	}
+	return explain().Failed()
+}
+
 func newExportedEquipment(entity *Entity, list []*Equipment, carried bool) []*exportedEquipment {
 	var result []*exportedEquipment
 	Traverse(func(e *Equipment) bool {
@@ -703,6 +803,7 @@ func newExportedEquipment(entity *Entity, list []*Equipment, carried bool) []*ex
 			TechLevel:         e.TechLevel,
 			LegalityClass:     e.LegalityClass,
 			UnsatisfiedReason: e.UnsatisfiedReason,
+			UnmetPrereqs:      unmetPrereqs(e.UnsatisfiedReason, e.PrereqExplanation),
 			PageRef:           e.PageRef,
 			Tags:              slices.Clone(e.Tags),
 			Depth:             e.Depth(),
@@ -755,6 +856,7 @@ func addToHitLocations(entity *Entity, locations []*exportedHitLocation, depth i
 		var tooltip xbytes.InsertBuffer
 		loc.DR = location.DisplayDR(entity, &tooltip)
 		loc.Notes = tooltip.String()
+		loc.Armor = ArmorLayersSummary(entity.ArmorLayersFor(location.LocID))
 		locations = append(locations, loc)
 		if location.SubTable != nil {
 			locations = addToHitLocations(entity, locations, depth+1, location.SubTable.Locations)
@@ -0,0 +1,37 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package export provides exporters that convert a character into the formats used by other tools. Unlike the
+// template-based exports, these produce their output directly from the character data.
+package export
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+func writeFile(path string, write func(w io.Writer) error) (err error) {
+	var f *os.File
+	if f, err = os.Create(path); err != nil {
+		return errs.Wrap(err)
+	}
+	buffer := bufio.NewWriter(f)
+	defer func() {
+		if flushErr := buffer.Flush(); flushErr != nil && err == nil {
+			err = errs.Wrap(flushErr)
+		}
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = errs.Wrap(closeErr)
+		}
+	}()
+	return write(buffer)
+}
@@ -0,0 +1,526 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/encumbrance"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+// FoundryExtension is the file extension used for Foundry VTT exports.
+const FoundryExtension = ".json"
+
+type foundryActor struct {
+	Name   string        `json:"name"`
+	Type   string        `json:"type"`
+	System foundrySystem `json:"system"`
+	Flags  foundryFlags  `json:"flags"`
+}
+
+type foundryFlags struct {
+	GCS struct {
+		ID tid.TID `json:"id"`
+	} `json:"gcs"`
+}
+
+type foundrySystem struct {
+	Attributes      map[string]*foundryAttribute   `json:"attributes"`
+	HP              *foundryPool                   `json:"HP,omitzero"`
+	FP              *foundryPool                   `json:"FP,omitzero"`
+	BasicSpeed      foundryValue                   `json:"basicspeed"`
+	BasicMove       foundryValue                   `json:"basicmove"`
+	CurrentMove     int                            `json:"currentmove"`
+	CurrentDodge    int                            `json:"currentdodge"`
+	Dodge           foundryDefense                 `json:"dodge"`
+	Thrust          string                         `json:"thrust"`
+	Swing           string                         `json:"swing"`
+	Traits          foundryTraits                  `json:"traits"`
+	Ads             map[string]*foundryTrait       `json:"ads"`
+	Skills          map[string]*foundrySkill       `json:"skills"`
+	Spells          map[string]*foundrySpell       `json:"spells"`
+	Equipment       foundryAllEquipment            `json:"equipment"`
+	Melee           map[string]*foundryMelee       `json:"melee"`
+	Ranged          map[string]*foundryRanged      `json:"ranged"`
+	Notes           map[string]*foundryNote        `json:"notes"`
+	HitLocations    map[string]*foundryHitLocation `json:"hitlocations"`
+	Encumbrance     map[string]*foundryEncumbrance `json:"encumbrance"`
+	Reactions       map[string]*foundryModifier    `json:"reactions"`
+	ConditionalMods map[string]*foundryModifier    `json:"conditionalmods"`
+}
+
+type foundryAttribute struct {
+	Import int `json:"import"`
+	Value  int `json:"value"`
+	Points int `json:"points"`
+}
+
+type foundryPool struct {
+	Value  int `json:"value"`
+	Max    int `json:"max"`
+	Points int `json:"points"`
+}
+
+type foundryValue struct {
+	Value  float64 `json:"value"`
+	Points int     `json:"points"`
+}
+
+type foundryDefense struct {
+	Value int    `json:"value"`
+	OTF   string `json:"otf"`
+}
+
+type foundryTraits struct {
+	Title        string `json:"title,omitzero"`
+	Player       string `json:"player,omitzero"`
+	Organization string `json:"organization,omitzero"`
+	Religion     string `json:"religion,omitzero"`
+	TechLevel    string `json:"techlevel,omitzero"`
+	Age          string `json:"age,omitzero"`
+	Birthday     string `json:"birthday,omitzero"`
+	Gender       string `json:"gender,omitzero"`
+	Height       string `json:"height,omitzero"`
+	Weight       string `json:"weight,omitzero"`
+	Eyes         string `json:"eyes,omitzero"`
+	Hair         string `json:"hair,omitzero"`
+	Skin         string `json:"skin,omitzero"`
+	Hand         string `json:"hand,omitzero"`
+	SizeMod      int    `json:"sizemod"`
+}
+
+type foundryTrait struct {
+	Name     string                   `json:"name"`
+	Points   int                      `json:"points"`
+	Notes    string                   `json:"notes,omitzero"`
+	PageRef  string                   `json:"pageref,omitzero"`
+	Contains map[string]*foundryTrait `json:"contains,omitzero"`
+}
+
+type foundrySkill struct {
+	Name          string                   `json:"name"`
+	Type          string                   `json:"type,omitzero"`
+	Import        int                      `json:"import"`
+	RelativeLevel string                   `json:"relativelevel,omitzero"`
+	Points        int                      `json:"points"`
+	Notes         string                   `json:"notes,omitzero"`
+	PageRef       string                   `json:"pageref,omitzero"`
+	OTF           string                   `json:"otf,omitzero"`
+	Contains      map[string]*foundrySkill `json:"contains,omitzero"`
+}
+
+type foundrySpell struct {
+	Name          string                   `json:"name"`
+	Class         string                   `json:"class,omitzero"`
+	College       string                   `json:"college,omitzero"`
+	Cost          string                   `json:"cost,omitzero"`
+	Maintain      string                   `json:"maintain,omitzero"`
+	CastingTime   string                   `json:"casttime,omitzero"`
+	Duration      string                   `json:"duration,omitzero"`
+	Resist        string                   `json:"resist,omitzero"`
+	Difficulty    string                   `json:"difficulty,omitzero"`
+	Import        int                      `json:"import"`
+	RelativeLevel string                   `json:"relativelevel,omitzero"`
+	Points        int                      `json:"points"`
+	Notes         string                   `json:"notes,omitzero"`
+	PageRef       string                   `json:"pageref,omitzero"`
+	OTF           string                   `json:"otf,omitzero"`
+	Contains      map[string]*foundrySpell `json:"contains,omitzero"`
+}
+
+type foundryAllEquipment struct {
+	Carried map[string]*foundryEquipment `json:"carried"`
+	Other   map[string]*foundryEquipment `json:"other"`
+}
+
+type foundryEquipment struct {
+	Name          string                       `json:"name"`
+	Count         float64                      `json:"count"`
+	Cost          float64                      `json:"cost"`
+	Weight        float64                      `json:"weight"`
+	CostSum       float64                      `json:"costsum"`
+	WeightSum     float64                      `json:"weightsum"`
+	TechLevel     string                       `json:"techlevel,omitzero"`
+	LegalityClass string                       `json:"legalityclass,omitzero"`
+	Uses          int                          `json:"uses,omitzero"`
+	MaxUses       int                          `json:"maxuses,omitzero"`
+	Equipped      bool                         `json:"equipped"`
+	Carried       bool                         `json:"carried"`
+	Notes         string                       `json:"notes,omitzero"`
+	PageRef       string                       `json:"pageref,omitzero"`
+	Contains      map[string]*foundryEquipment `json:"contains,omitzero"`
+}
+
+type foundryMelee struct {
+	Name      string `json:"name"`
+	Mode      string `json:"mode,omitzero"`
+	Import    int    `json:"import"`
+	Damage    string `json:"damage"`
+	Reach     string `json:"reach,omitzero"`
+	Parry     string `json:"parry,omitzero"`
+	Block     string `json:"block,omitzero"`
+	ST        string `json:"st,omitzero"`
+	Notes     string `json:"notes,omitzero"`
+	OTF       string `json:"otf"`
+	DamageOTF string `json:"damageotf"`
+	ParryOTF  string `json:"parryotf,omitzero"`
+	BlockOTF  string `json:"blockotf,omitzero"`
+}
+
+type foundryRanged struct {
+	Name      string `json:"name"`
+	Mode      string `json:"mode,omitzero"`
+	Import    int    `json:"import"`
+	Damage    string `json:"damage"`
+	Acc       string `json:"acc,omitzero"`
+	Range     string `json:"range,omitzero"`
+	RoF       string `json:"rof,omitzero"`
+	Shots     string `json:"shots,omitzero"`
+	Bulk      string `json:"bulk,omitzero"`
+	Recoil    string `json:"rcl,omitzero"`
+	ST        string `json:"st,omitzero"`
+	Notes     string `json:"notes,omitzero"`
+	OTF       string `json:"otf"`
+	DamageOTF string `json:"damageotf"`
+}
+
+type foundryNote struct {
+	Notes    string                  `json:"notes"`
+	PageRef  string                  `json:"pageref,omitzero"`
+	Contains map[string]*foundryNote `json:"contains,omitzero"`
+}
+
+type foundryHitLocation struct {
+	Where   string `json:"where"`
+	Roll    string `json:"roll"`
+	Penalty int    `json:"penalty"`
+	DR      string `json:"dr"`
+}
+
+type foundryEncumbrance struct {
+	Level   int    `json:"level"`
+	Key     string `json:"key"`
+	Weight  string `json:"weight"`
+	Move    int    `json:"move"`
+	Dodge   int    `json:"dodge"`
+	Current bool   `json:"current"`
+}
+
+type foundryModifier struct {
+	Modifier  int    `json:"modifier"`
+	Situation string `json:"situation"`
+}
+
+// ToFoundry exports the entity to path in the JSON format used by the GURPS Game Aid system for Foundry VTT.
+func ToFoundry(entity *gurps.Entity, path string) error {
+	return writeFile(path, func(w io.Writer) error { return WriteFoundry(w, entity) })
+}
+
+// WriteFoundry writes the entity to w in the JSON format used by the GURPS Game Aid system for Foundry VTT. Skills,
+// spells, attacks and defenses include OTF (On-the-Fly) formulas that the system can use to roll them. Since this will
+// cause the entity to be recalculated, it should only be called from the UI thread.
+func WriteFoundry(w io.Writer, entity *gurps.Entity) error {
+	entity.Recalculate()
+	return jio.Save(w, newFoundryActor(entity))
+}
+
+func newFoundryActor(entity *gurps.Entity) *foundryActor {
+	units := entity.SheetSettings.DefaultWeightUnits
+	currentEnc := entity.EncumbranceLevel(false)
+	actor := &foundryActor{
+		Name: entity.Profile.Name,
+		Type: "character",
+		System: foundrySystem{
+			Attributes:   make(map[string]*foundryAttribute),
+			CurrentMove:  entity.Move(currentEnc),
+			CurrentDodge: entity.Dodge(currentEnc),
+			Dodge: foundryDefense{
+				Value: entity.Dodge(currentEnc),
+				OTF:   "Dodge",
+			},
+			Thrust: entity.Thrust().String(),
+			Swing:  entity.Swing().String(),
+			Traits: foundryTraits{
+				Title:        entity.Profile.Title,
+				Player:       entity.Profile.PlayerName,
+				Organization: entity.Profile.Organization,
+				Religion:     entity.Profile.Religion,
+				TechLevel:    entity.Profile.TechLevel,
+				Age:          entity.Profile.Age,
+				Birthday:     entity.Profile.Birthday,
+				Gender:       entity.Profile.Gender,
+				Height:       entity.SheetSettings.DefaultLengthUnits.Format(entity.Profile.Height),
+				Weight:       units.Format(entity.Profile.Weight),
+				Eyes:         entity.Profile.Eyes,
+				Hair:         entity.Profile.Hair,
+				Skin:         entity.Profile.Skin,
+				Hand:         entity.Profile.Handedness,
+				SizeMod:      entity.Profile.AdjustedSizeModifier(),
+			},
+			Ads:    foundryTraitsFrom(entity.Traits),
+			Skills: foundrySkillsFrom(entity, entity.Skills),
+			Spells: foundrySpellsFrom(entity, entity.Spells),
+			Equipment: foundryAllEquipment{
+				Carried: foundryEquipmentFrom(units, entity.CarriedEquipment, true),
+				Other:   foundryEquipmentFrom(units, entity.OtherEquipment, false),
+			},
+			Melee:           make(map[string]*foundryMelee),
+			Ranged:          make(map[string]*foundryRanged),
+			Notes:           foundryNotesFrom(entity.Notes),
+			HitLocations:    make(map[string]*foundryHitLocation),
+			Encumbrance:     make(map[string]*foundryEncumbrance),
+			Reactions:       foundryModifiersFrom(entity.Reactions()),
+			ConditionalMods: foundryModifiersFrom(entity.ConditionalModifiers()),
+		},
+	}
+	actor.Flags.GCS.ID = entity.ID
+	for _, def := range entity.SheetSettings.Attributes.List(true) {
+		attr, ok := entity.Attributes.Set[def.DefID]
+		if !ok {
+			continue
+		}
+		points := fxp.AsInteger[int](attr.PointCost())
+		switch {
+		case def.DefID == gurps.BasicSpeedID:
+			actor.System.BasicSpeed = foundryValue{Value: fxp.AsFloat[float64](attr.Maximum()), Points: points}
+		case def.DefID == gurps.BasicMoveID:
+			actor.System.BasicMove = foundryValue{Value: fxp.AsFloat[float64](attr.Maximum()), Points: points}
+		case def.Pool():
+			pool := &foundryPool{
+				Value:  fxp.AsInteger[int](attr.Current()),
+				Max:    fxp.AsInteger[int](attr.Maximum()),
+				Points: points,
+			}
+			switch def.DefID {
+			case "hp":
+				actor.System.HP = pool
+			case "fp":
+				actor.System.FP = pool
+			}
+		case def.Primary():
+			value := fxp.AsInteger[int](attr.Maximum())
+			actor.System.Attributes[strings.ToUpper(def.DefID)] = &foundryAttribute{
+				Import: value,
+				Value:  value,
+				Points: points,
+			}
+		}
+	}
+	for i, wpn := range entity.Weapons(true, entity.SheetSettings.ShowAllWeapons, true) {
+		parry := wpn.Parry.Resolve(wpn, nil)
+		block := wpn.Block.Resolve(wpn, nil)
+		m := &foundryMelee{
+			Name:      wpn.String(),
+			Mode:      wpn.UsageWithReplacements(),
+			Import:    fxp.AsInteger[int](wpn.SkillLevel(nil)),
+			Damage:    wpn.Damage.ResolvedDamage(nil),
+			Reach:     wpn.Reach.Resolve(wpn, nil).String(),
+			Parry:     parry.String(),
+			Block:     block.String(),
+			ST:        wpn.Strength.Resolve(wpn, nil).String(),
+			Notes:     wpn.Notes(),
+			OTF:       foundryWeaponOTF("M", wpn),
+			DamageOTF: foundryWeaponOTF("D", wpn),
+		}
+		if parry.CanParry {
+			m.ParryOTF = foundryWeaponOTF("P", wpn)
+		}
+		if block.CanBlock {
+			m.BlockOTF = foundryWeaponOTF("B", wpn)
+		}
+		actor.System.Melee[foundryKey(i)] = m
+	}
+	for i, wpn := range entity.Weapons(false, entity.SheetSettings.ShowAllWeapons, true) {
+		actor.System.Ranged[foundryKey(i)] = &foundryRanged{
+			Name:      wpn.String(),
+			Mode:      wpn.UsageWithReplacements(),
+			Import:    fxp.AsInteger[int](wpn.SkillLevel(nil)),
+			Damage:    wpn.Damage.ResolvedDamage(nil),
+			Acc:       wpn.Accuracy.Resolve(wpn, nil).String(),
+			Range:     wpn.Range.Resolve(wpn, nil).String(true),
+			RoF:       wpn.RateOfFire.Resolve(wpn, nil).String(),
+			Shots:     wpn.Shots.Resolve(wpn, nil).String(),
+			Bulk:      wpn.Bulk.Resolve(wpn, nil).String(),
+			Recoil:    wpn.Recoil.Resolve(wpn, nil).String(),
+			ST:        wpn.Strength.Resolve(wpn, nil).String(),
+			Notes:     wpn.Notes(),
+			OTF:       foundryWeaponOTF("R", wpn),
+			DamageOTF: foundryWeaponOTF("D", wpn),
+		}
+	}
+	for i, loc := range gurps.BodyFor(entity).Locations {
+		actor.System.HitLocations[foundryKey(i)] = &foundryHitLocation{
+			Where:   loc.TableName,
+			Roll:    loc.RollRange,
+			Penalty: loc.HitPenalty,
+			DR:      loc.DisplayDR(entity, nil),
+		}
+	}
+	for i, enc := range encumbrance.Levels {
+		actor.System.Encumbrance[foundryKey(i)] = &foundryEncumbrance{
+			Level:   i,
+			Key:     "enc" + strconv.Itoa(i),
+			Weight:  units.Format(entity.MaximumCarry(enc)),
+			Move:    entity.Move(enc),
+			Dodge:   entity.Dodge(enc),
+			Current: enc == currentEnc,
+		}
+	}
+	return actor
+}
+
+func foundryKey(index int) string {
+	return fmt.Sprintf("%05d", index)
+}
+
+// foundryOTF returns an OTF formula of the given type for the named item. The name is always quoted, as the formula
+// parser requires it for names containing spaces or parentheses.
+func foundryOTF(kind, name string) string {
+	return kind + `:"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+}
+
+func foundryWeaponOTF(kind string, w *gurps.Weapon) string {
+	name := w.String()
+	if usage := w.UsageWithReplacements(); usage != "" {
+		name += " (" + usage + ")"
+	}
+	return foundryOTF(kind, name)
+}
+
+func foundryTraitsFrom(list []*gurps.Trait) map[string]*foundryTrait {
+	result := make(map[string]*foundryTrait)
+	for _, t := range list {
+		if !t.Enabled() {
+			continue
+		}
+		one := &foundryTrait{
+			Name:    t.String(),
+			Points:  fxp.AsInteger[int](t.AdjustedPoints()),
+			Notes:   t.Notes(),
+			PageRef: t.PageRef,
+		}
+		if t.Container() {
+			one.Contains = foundryTraitsFrom(t.Children)
+		}
+		result[foundryKey(len(result))] = one
+	}
+	return result
+}
+
+func foundrySkillsFrom(entity *gurps.Entity, list []*gurps.Skill) map[string]*foundrySkill {
+	result := make(map[string]*foundrySkill, len(list))
+	for _, s := range list {
+		one := &foundrySkill{
+			Name:    s.String(),
+			Points:  fxp.AsInteger[int](s.AdjustedPoints(nil)),
+			Notes:   s.Notes(),
+			PageRef: s.PageRef,
+		}
+		if s.Container() {
+			one.Contains = foundrySkillsFrom(entity, s.Children)
+		} else {
+			one.Type = s.Difficulty.Description(entity)
+			one.Import = fxp.AsInteger[int](s.LevelData.Level)
+			one.RelativeLevel = s.RelativeLevel()
+			one.OTF = foundryOTF("Sk", s.String())
+		}
+		result[foundryKey(len(result))] = one
+	}
+	return result
+}
+
+func foundrySpellsFrom(entity *gurps.Entity, list []*gurps.Spell) map[string]*foundrySpell {
+	result := make(map[string]*foundrySpell, len(list))
+	for _, s := range list {
+		one := &foundrySpell{
+			Name:    s.String(),
+			Points:  fxp.AsInteger[int](s.AdjustedPoints(nil)),
+			Notes:   s.Notes(),
+			PageRef: s.PageRef,
+		}
+		if s.Container() {
+			one.Contains = foundrySpellsFrom(entity, s.Children)
+		} else {
+			one.Class = s.ClassWithReplacements()
+			one.College = strings.Join(s.CollegeWithReplacements(), ", ")
+			one.Cost = s.CastingCostWithReplacements()
+			one.Maintain = s.MaintenanceCostWithReplacements()
+			one.CastingTime = s.CastingTimeWithReplacements()
+			one.Duration = s.DurationWithReplacements()
+			one.Resist = s.ResistWithReplacements()
+			one.Difficulty = s.Difficulty.Description(entity)
+			one.Import = fxp.AsInteger[int](s.LevelData.Level)
+			one.RelativeLevel = s.RelativeLevel()
+			one.OTF = foundryOTF("Sp", s.String())
+		}
+		result[foundryKey(len(result))] = one
+	}
+	return result
+}
+
+func foundryEquipmentFrom(units fxp.WeightUnit, list []*gurps.Equipment, carried bool) map[string]*foundryEquipment {
+	result := make(map[string]*foundryEquipment, len(list))
+	for _, e := range list {
+		one := &foundryEquipment{
+			Name:          e.String(),
+			Count:         fxp.AsFloat[float64](e.Quantity),
+			Cost:          fxp.AsFloat[float64](e.AdjustedValue()),
+			Weight:        fxp.AsFloat[float64](fxp.Int(e.AdjustedWeight(false, units))),
+			CostSum:       fxp.AsFloat[float64](e.ExtendedValue()),
+			WeightSum:     fxp.AsFloat[float64](fxp.Int(e.ExtendedWeight(false, units))),
+			TechLevel:     e.TechLevel,
+			LegalityClass: e.LegalityClass,
+			Uses:          e.Uses,
+			MaxUses:       e.MaxUses,
+			Equipped:      carried && e.ReallyEquipped(),
+			Carried:       carried,
+			Notes:         e.Notes(),
+			PageRef:       e.PageRef,
+		}
+		if e.Container() {
+			one.Contains = foundryEquipmentFrom(units, e.Children, carried)
+		}
+		result[foundryKey(len(result))] = one
+	}
+	return result
+}
+
+func foundryNotesFrom(list []*gurps.Note) map[string]*foundryNote {
+	result := make(map[string]*foundryNote, len(list))
+	for _, n := range list {
+		one := &foundryNote{
+			Notes:   n.String(),
+			PageRef: n.PageRef,
+		}
+		if n.Container() {
+			one.Contains = foundryNotesFrom(n.Children)
+		}
+		result[foundryKey(len(result))] = one
+	}
+	return result
+}
+
+func foundryModifiersFrom(list []*gurps.ConditionalModifier) map[string]*foundryModifier {
+	result := make(map[string]*foundryModifier, len(list))
+	for i, one := range list {
+		result[foundryKey(i)] = &foundryModifier{
+			Modifier:  fxp.AsInteger[int](one.Total()),
+			Situation: one.From,
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestFoundryOTF(t *testing.T) {
+	c := check.New(t)
+	c.Equal(`Sk:"Broadsword"`, foundryOTF("Sk", "Broadsword"))
+	c.Equal(`Sk:"Fast-Draw (Sword)"`, foundryOTF("Sk", "Fast-Draw (Sword)"))
+	c.Equal(`M:"The \"Big\" Stick"`, foundryOTF("M", `The "Big" Stick`))
+	c.Equal("00000", foundryKey(0))
+	c.Equal("00012", foundryKey(12))
+}
+
+func TestWriteFoundry(t *testing.T) {
+	c := check.New(t)
+	entity := gurps.NewEntity()
+	entity.Profile.Name = "Test"
+	var buffer bytes.Buffer
+	c.NoError(WriteFoundry(&buffer, entity))
+	var actor foundryActor
+	c.NoError(json.Unmarshal(buffer.Bytes(), &actor))
+	c.Equal("Test", actor.Name)
+	c.Equal("character", actor.Type)
+	c.Equal(entity.ID, actor.Flags.GCS.ID)
+	c.NotNil(actor.System.Attributes["ST"])
+	c.Equal(10, actor.System.Attributes["ST"].Value)
+	c.NotNil(actor.System.HP)
+	c.Equal(10, actor.System.HP.Max)
+	c.Equal("Dodge", actor.System.Dodge.OTF)
+	c.Equal(5, len(actor.System.Encumbrance))
+}
@@ -0,0 +1,678 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/display"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/encumbrance"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// HTMLExtension is the file extension used for HTML exports.
+const HTMLExtension = ".html"
+
+//go:embed html.tmpl
+var htmlTemplateText string
+
+type htmlSheet struct {
+	Title       string
+	PortraitURL template.URL
+	Profile     []htmlPair
+	Sections    []*htmlSection
+	Labels      htmlLabels
+}
+
+type htmlLabels struct {
+	ExpandAll   string
+	CollapseAll string
+}
+
+type htmlPair struct {
+	Label string
+	Value string
+}
+
+type htmlSection struct {
+	Title   string
+	Headers []htmlCell
+	Rows    []*htmlRow
+	Lines   []htmlPair
+	Text    []string
+}
+
+type htmlRow struct {
+	ID        string
+	ParentID  string
+	Depth     int
+	Container bool
+	Open      bool
+	Hidden    bool
+	Cells     []htmlCell
+}
+
+type htmlCell struct {
+	Text    string
+	Extra   []string
+	Tooltip string
+	Numeric bool
+}
+
+type htmlWriter struct {
+	entity   *gurps.Entity
+	sections []*htmlSection
+}
+
+// ToHTML exports the entity to path as a single, self-contained HTML file.
+func ToHTML(entity *gurps.Entity, path string) error {
+	return writeFile(path, func(w io.Writer) error { return WriteHTML(w, entity) })
+}
+
+// WriteHTML writes the entity to w as a single, self-contained HTML file, with all styling and scripting inline.
+// Containers can be expanded and collapsed, and notes are shown inline or as hover tooltips according to the sheet's
+// display settings. The blocks of the sheet are written in the order specified by the sheet's block layout. Since this
+// will cause the entity to be recalculated, it should only be called from the UI thread.
+func WriteHTML(w io.Writer, entity *gurps.Entity) error {
+	tmpl, err := template.New("").Parse(htmlTemplateText)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	entity.Recalculate()
+	h := &htmlWriter{entity: entity}
+	data := &htmlSheet{
+		Title: entity.Profile.Name,
+		Labels: htmlLabels{
+			ExpandAll:   i18n.Text("Expand All"),
+			CollapseAll: i18n.Text("Collapse All"),
+		},
+	}
+	if data.Title == "" {
+		data.Title = i18n.Text("Unnamed Character")
+	}
+	if len(entity.Profile.PortraitData) != 0 {
+		data.PortraitURL = template.URL("data:" + http.DetectContentType(entity.Profile.PortraitData) + ";base64," + base64.StdEncoding.EncodeToString(entity.Profile.PortraitData)) //nolint:gosec // This is a valid data URL
+	}
+	data.Profile = h.profile()
+	h.addAttributes()
+	for _, row := range entity.SheetSettings.BlockLayout.ByRow() {
+		for _, key := range row {
+			h.addBlock(key)
+		}
+	}
+	data.Sections = h.sections
+	if err = tmpl.Execute(w, data); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+func (h *htmlWriter) profile() []htmlPair {
+	e := h.entity
+	var list []htmlPair
+	for _, one := range []htmlPair{
+		{i18n.Text("Player"), e.Profile.PlayerName},
+		{i18n.Text("Title"), e.Profile.Title},
+		{i18n.Text("Organization"), e.Profile.Organization},
+		{i18n.Text("Religion"), e.Profile.Religion},
+		{i18n.Text("TL"), e.Profile.TechLevel},
+		{i18n.Text("Gender"), e.Profile.Gender},
+		{i18n.Text("Age"), e.Profile.Age},
+		{i18n.Text("Birthday"), e.Profile.Birthday},
+		{i18n.Text("Eyes"), e.Profile.Eyes},
+		{i18n.Text("Hair"), e.Profile.Hair},
+		{i18n.Text("Skin"), e.Profile.Skin},
+		{i18n.Text("Hand"), e.Profile.Handedness},
+		{i18n.Text("Height"), e.SheetSettings.DefaultLengthUnits.Format(e.Profile.Height)},
+		{i18n.Text("Weight"), e.SheetSettings.DefaultWeightUnits.Format(e.Profile.Weight)},
+		{i18n.Text("Points"), fmt.Sprintf(i18n.Text("%s (%s unspent)"), e.TotalPoints.Comma(),
+			e.UnspentPoints().Comma())},
+	} {
+		if one.Value != "" {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+func (h *htmlWriter) add(section *htmlSection) {
+	if len(section.Rows) != 0 || len(section.Lines) != 0 || len(section.Text) != 0 {
+		h.sections = append(h.sections, section)
+	}
+}
+
+func htmlHeaders(titles ...string) []htmlCell {
+	cells := make([]htmlCell, len(titles))
+	for i, title := range titles {
+		cells[i].Text = title
+	}
+	return cells
+}
+
+func htmlText(text string) htmlCell {
+	return htmlCell{Text: text}
+}
+
+func htmlNumber(text string) htmlCell {
+	return htmlCell{Text: text, Numeric: true}
+}
+
+// nameCell creates a cell for an item's name, placing the supplementary text either inline or in a tooltip, as
+// directed by the sheet's display settings.
+func (h *htmlWriter) nameCell(name, userDesc, modifierNotes, notes string) htmlCell {
+	settings := h.entity.SheetSettings
+	cell := htmlCell{Text: name}
+	var tooltip []string
+	for _, one := range []struct {
+		text   string
+		option display.Option
+	}{
+		{userDesc, settings.UserDescriptionDisplay},
+		{modifierNotes, settings.ModifiersDisplay},
+		{notes, settings.NotesDisplay},
+	} {
+		if one.text = strings.TrimSpace(one.text); one.text == "" {
+			continue
+		}
+		if one.option.Inline() {
+			cell.Extra = append(cell.Extra, one.text)
+		}
+		if one.option.Tooltip() {
+			tooltip = append(tooltip, one.text)
+		}
+	}
+	cell.Tooltip = strings.Join(tooltip, "\n\n")
+	return cell
+}
+
+func htmlTreeRows[T gurps.NodeTypes](rows []*htmlRow, list []T, parentID string, depth int, visible, onlyEnabled bool, cells func(T) []htmlCell) []*htmlRow {
+	for _, one := range list {
+		node := gurps.AsNode(one)
+		if onlyEnabled && !node.Enabled() {
+			continue
+		}
+		row := &htmlRow{
+			ID:        string(node.ID()),
+			ParentID:  parentID,
+			Depth:     depth,
+			Container: node.Container(),
+			Open:      node.IsOpen(),
+			Hidden:    !visible,
+			Cells:     cells(one),
+		}
+		rows = append(rows, row)
+		if node.HasChildren() {
+			rows = htmlTreeRows(rows, node.NodeChildren(), row.ID, depth+1, visible && row.Open, onlyEnabled, cells)
+		}
+	}
+	return rows
+}
+
+func (h *htmlWriter) addAttributes() {
+	e := h.entity
+	section := &htmlSection{
+		Title:   i18n.Text("Attributes"),
+		Headers: htmlHeaders(i18n.Text("Attribute"), i18n.Text("Value"), i18n.Text("Points")),
+	}
+	for _, def := range e.SheetSettings.Attributes.List(true) {
+		if def.Pool() || def.IsSeparator() {
+			continue
+		}
+		if attr, ok := e.Attributes.Set[def.DefID]; ok {
+			section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+				htmlText(def.CombinedName()),
+				htmlNumber(attr.Maximum().Comma()),
+				htmlNumber(attr.PointCost().Comma()),
+			}})
+		}
+	}
+	h.add(section)
+	h.add(&htmlSection{
+		Title: i18n.Text("Basic Damage"),
+		Lines: []htmlPair{
+			{i18n.Text("Thrust"), e.Thrust().String()},
+			{i18n.Text("Swing"), e.Swing().String()},
+		},
+	})
+	section = &htmlSection{
+		Title: i18n.Text("Encumbrance"),
+		Headers: htmlHeaders(i18n.Text("Level"), i18n.Text("Max Load"), i18n.Text("Move"),
+			i18n.Text("Dodge")),
+	}
+	current := e.EncumbranceLevel(false)
+	units := e.SheetSettings.DefaultWeightUnits
+	for _, enc := range encumbrance.Levels {
+		level := enc.String()
+		if enc == current {
+			level = "▶ " + level
+		}
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			htmlText(level),
+			htmlNumber(units.Format(e.MaximumCarry(enc))),
+			htmlNumber(strconv.Itoa(e.Move(enc))),
+			htmlNumber(e.DodgeText(enc)),
+		}})
+	}
+	h.add(section)
+	section = &htmlSection{
+		Title: i18n.Text("Hit Locations"),
+		Headers: htmlHeaders(i18n.Text("Roll"), i18n.Text("Location"), i18n.Text("Penalty"), i18n.Text("DR"),
+			i18n.Text("Armor")),
+	}
+	for _, one := range e.HitLocationDRs() {
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			htmlText(one.Location.RollRange),
+			{Text: strings.Repeat("\u2003", one.Depth) + one.Location.TableName, Tooltip: one.Location.Description},
+			htmlNumber(fmt.Sprintf("%+d", one.Location.HitPenalty)),
+			htmlNumber(one.DR),
+			htmlText(gurps.ArmorLayersSummary(one.Layers)),
+		}})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addBlock(key string) {
+	e := h.entity
+	switch key {
+	case gurps.BlockLayoutReactionsKey:
+		h.addModifiers(i18n.Text("Reactions"), e.Reactions())
+	case gurps.BlockLayoutConditionalModifiersKey:
+		h.addModifiers(i18n.Text("Conditional Modifiers"), e.ConditionalModifiers())
+	case gurps.BlockLayoutReactionSummaryKey:
+		h.addReactionSummary()
+	case gurps.BlockLayoutPoolTrackersKey:
+		h.addPools()
+	case gurps.BlockLayoutWoundsKey:
+		h.addWounds()
+	case gurps.BlockLayoutConditionsKey:
+		h.addConditions()
+	case gurps.BlockLayoutGrapplingKey:
+		h.addGrappling()
+	case gurps.BlockLayoutFatigueKey:
+		h.addFatigue()
+	case gurps.BlockLayoutMeleeKey:
+		h.addMeleeWeapons()
+	case gurps.BlockLayoutRangedKey:
+		h.addRangedWeapons()
+	case gurps.BlockLayoutTraitsKey:
+		h.addTraits()
+	case gurps.BlockLayoutSkillsKey:
+		h.addSkills()
+	case gurps.BlockLayoutSpellsKey:
+		h.addSpells()
+	case gurps.BlockLayoutGrimoireKey:
+		h.addGrimoire()
+	case gurps.BlockLayoutEquipmentKey:
+		h.addEquipment(fmt.Sprintf(i18n.Text("Carried Equipment (%s; $%s)"),
+			e.SheetSettings.DefaultWeightUnits.Format(e.WeightCarried(false)), e.WealthCarried().Comma()),
+			e.CarriedEquipment, true)
+	case gurps.BlockLayoutOtherEquipmentKey:
+		h.addEquipment(fmt.Sprintf(i18n.Text("Other Equipment ($%s)"), e.WealthNotCarried().Comma()),
+			e.OtherEquipment, false)
+	case gurps.BlockLayoutNotesKey:
+		h.addNotes()
+	default:
+		if block := e.SheetSettings.CustomBlockFor(key); block != nil {
+			h.addCustomBlock(block)
+		}
+	}
+}
+
+func (h *htmlWriter) addModifiers(title string, list []*gurps.ConditionalModifier) {
+	section := &htmlSection{
+		Title:   title,
+		Headers: htmlHeaders(i18n.Text("Modifier"), i18n.Text("Situation")),
+	}
+	for _, one := range list {
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			htmlNumber(one.Total().StringWithSign()),
+			{Text: one.From, Tooltip: strings.Join(one.Sources, "\n")},
+		}})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addReactionSummary() {
+	groups := h.entity.ReactionSummary()
+	kinds := gurps.ReactionSourceKinds(groups)
+	titles := make([]string, 0, len(kinds)+2)
+	titles = append(titles, i18n.Text("Group"))
+	for _, kind := range kinds {
+		titles = append(titles, kind.String())
+	}
+	titles = append(titles, i18n.Text("Total"))
+	section := &htmlSection{
+		Title:   i18n.Text("Reaction Summary"),
+		Headers: htmlHeaders(titles...),
+	}
+	for _, g := range groups {
+		cells := make([]htmlCell, 0, len(titles))
+		cells = append(cells, htmlText(g.Group))
+		for _, kind := range kinds {
+			cell := htmlNumber("")
+			if g.Has(kind) {
+				cell.Text = g.TotalFor(kind).StringWithSign()
+			}
+			cells = append(cells, cell)
+		}
+		sources := make([]string, 0, len(g.Contributions))
+		for _, one := range g.Contributions {
+			sources = append(sources, one.Amount.StringWithSign()+" "+one.Source)
+		}
+		total := htmlNumber(g.Total().StringWithSign())
+		total.Tooltip = strings.Join(sources, "\n")
+		cells = append(cells, total)
+		section.Rows = append(section.Rows, &htmlRow{Cells: cells})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addPools() {
+	e := h.entity
+	section := &htmlSection{
+		Title: i18n.Text("Pools"),
+		Headers: htmlHeaders(i18n.Text("Pool"), i18n.Text("Current"), i18n.Text("Maximum"),
+			i18n.Text("State")),
+	}
+	for _, def := range e.SheetSettings.Attributes.List(true) {
+		if !def.Pool() {
+			continue
+		}
+		if attr, ok := e.Attributes.Set[def.DefID]; ok {
+			state := htmlCell{}
+			if threshold := attr.CurrentThreshold(); threshold != nil {
+				state.Text = threshold.State
+				state.Tooltip = threshold.ResolveExplanation(attr)
+			}
+			section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+				htmlText(def.CombinedName()),
+				htmlNumber(attr.Current().Comma()),
+				htmlNumber(attr.Maximum().Comma()),
+				state,
+			}})
+		}
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addWounds() {
+	e := h.entity
+	section := &htmlSection{
+		Title: i18n.Text("Wounds"),
+		Headers: htmlHeaders(i18n.Text("Location"), i18n.Text("Type"), i18n.Text("Basic"), i18n.Text("DR"),
+			i18n.Text("Injury"), i18n.Text("Effects")),
+	}
+	body := gurps.BodyFor(e)
+	for _, w := range e.Wounds {
+		name := w.LocationID
+		if loc := body.LookupLocationByID(e, w.LocationID); loc != nil {
+			name = loc.ChoiceName
+		}
+		var effects []string
+		if w.Crippling {
+			effects = append(effects, i18n.Text("Crippling"))
+		}
+		if w.Bleeding {
+			effects = append(effects, i18n.Text("Bleeding"))
+		}
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			{Text: name, Tooltip: w.Notes},
+			htmlText(w.DamageType),
+			htmlNumber(strconv.Itoa(w.Basic)),
+			htmlNumber(strconv.Itoa(w.DR)),
+			htmlNumber(strconv.Itoa(w.Injury)),
+			htmlText(strings.Join(effects, ", ")),
+		}})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addConditions() {
+	section := &htmlSection{
+		Title:   i18n.Text("Conditions"),
+		Headers: htmlHeaders(i18n.Text("Condition"), i18n.Text("Turns")),
+	}
+	for _, c := range h.entity.Conditions {
+		turns := ""
+		if c.Timed() {
+			turns = strconv.Itoa(c.Turns)
+		}
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			h.nameCell(c.String(), "", "", c.Notes),
+			htmlNumber(turns),
+		}})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addGrappling() {
+	e := h.entity
+	if !e.TechnicalGrappling() {
+		return
+	}
+	h.add(&htmlSection{
+		Title: i18n.Text("Grappling"),
+		Lines: []htmlPair{
+			{i18n.Text("Trained ST"), e.TrainedST().Comma()},
+			{i18n.Text("Control"), e.ControlDamage().String() + " CP"},
+			{i18n.Text("CP Inflicted"), strconv.Itoa(e.Grapple.Inflicted)},
+			{i18n.Text("CP Suffered"), strconv.Itoa(e.Grapple.Suffered)},
+			{i18n.Text("DX Penalty"), strconv.Itoa(e.GrapplePenalty())},
+		},
+	})
+}
+
+func (h *htmlWriter) addFatigue() {
+	section := &htmlSection{
+		Title:   i18n.Text("Fatigue Expenditures"),
+		Headers: htmlHeaders(i18n.Text("Kind"), i18n.Text("Notes"), i18n.Text("FP")),
+	}
+	for _, one := range h.entity.FatigueLog {
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			htmlText(gurps.FatigueKindName(one.Kind)),
+			htmlText(one.Notes),
+			htmlNumber(strconv.Itoa(one.Amount)),
+		}})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addMeleeWeapons() {
+	e := h.entity
+	section := &htmlSection{
+		Title: i18n.Text("Melee Weapons"),
+		Headers: htmlHeaders(i18n.Text("Weapon"), i18n.Text("Usage"), i18n.Text("SL"), i18n.Text("Damage"),
+			i18n.Text("Reach"), i18n.Text("Parry"), i18n.Text("Block"), i18n.Text("ST")),
+	}
+	for _, w := range e.Weapons(true, e.SheetSettings.ShowAllWeapons, true) {
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			h.nameCell(w.String(), "", "", w.Notes()),
+			htmlText(w.UsageWithReplacements()),
+			htmlNumber(w.SkillLevel(nil).Comma()),
+			htmlText(w.Damage.ResolvedDamage(nil)),
+			htmlText(w.Reach.Resolve(w, nil).String()),
+			htmlText(w.Parry.Resolve(w, nil).String()),
+			htmlText(w.Block.Resolve(w, nil).String()),
+			htmlText(w.Strength.Resolve(w, nil).String()),
+		}})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addRangedWeapons() {
+	e := h.entity
+	section := &htmlSection{
+		Title: i18n.Text("Ranged Weapons"),
+		Headers: htmlHeaders(i18n.Text("Weapon"), i18n.Text("Usage"), i18n.Text("SL"), i18n.Text("Damage"),
+			i18n.Text("Acc"), i18n.Text("Range"), i18n.Text("RoF"), i18n.Text("Shots"), i18n.Text("Bulk"),
+			i18n.Text("Rcl"), i18n.Text("ST")),
+	}
+	for _, w := range e.Weapons(false, e.SheetSettings.ShowAllWeapons, true) {
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			h.nameCell(w.String(), "", "", w.Notes()),
+			htmlText(w.UsageWithReplacements()),
+			htmlNumber(w.SkillLevel(nil).Comma()),
+			htmlText(w.Damage.ResolvedDamage(nil)),
+			htmlText(w.Accuracy.Resolve(w, nil).String()),
+			htmlText(w.Range.Resolve(w, nil).String(true)),
+			htmlText(w.RateOfFire.Resolve(w, nil).String()),
+			htmlText(w.Shots.Resolve(w, nil).String()),
+			htmlText(w.Bulk.Resolve(w, nil).String()),
+			htmlText(w.Recoil.Resolve(w, nil).String()),
+			htmlText(w.Strength.Resolve(w, nil).String()),
+		}})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addTraits() {
+	h.add(&htmlSection{
+		Title:   i18n.Text("Traits"),
+		Headers: htmlHeaders(i18n.Text("Trait"), i18n.Text("Pts"), i18n.Text("Ref")),
+		Rows: htmlTreeRows(nil, h.entity.Traits, "", 0, true, true, func(t *gurps.Trait) []htmlCell {
+			return []htmlCell{
+				h.nameCell(t.String(), t.UserDescWithReplacements(), t.ModifierNotes(), t.Notes()),
+				htmlNumber(t.AdjustedPoints().Comma()),
+				htmlText(t.PageRef),
+			}
+		}),
+	})
+}
+
+func (h *htmlWriter) addSkills() {
+	h.add(&htmlSection{
+		Title: i18n.Text("Skills"),
+		Headers: htmlHeaders(i18n.Text("Skill"), i18n.Text("SL"), i18n.Text("RSL"), i18n.Text("Pts"),
+			i18n.Text("Ref")),
+		Rows: htmlTreeRows(nil, h.entity.Skills, "", 0, true, true, func(s *gurps.Skill) []htmlCell {
+			return []htmlCell{
+				h.nameCell(s.String(), "", s.ModifierNotes(), s.Notes()),
+				htmlNumber(s.CalculateLevel(nil).LevelAsString(s.Container())),
+				htmlText(s.RelativeLevel()),
+				htmlNumber(s.AdjustedPoints(nil).Comma()),
+				htmlText(s.PageRef),
+			}
+		}),
+	})
+}
+
+func (h *htmlWriter) addSpells() {
+	h.add(&htmlSection{
+		Title: i18n.Text("Spells"),
+		Headers: htmlHeaders(i18n.Text("Spell"), i18n.Text("Class"), i18n.Text("College"), i18n.Text("Cost"),
+			i18n.Text("Maintain"), i18n.Text("Time"), i18n.Text("Duration"), i18n.Text("SL"), i18n.Text("RSL"),
+			i18n.Text("Pts"), i18n.Text("Ref")),
+		Rows: htmlTreeRows(nil, h.entity.Spells, "", 0, true, true, func(s *gurps.Spell) []htmlCell {
+			cells := []htmlCell{
+				h.nameCell(s.String(), "", s.Rituals(), s.Notes()),
+				{}, {}, {}, {}, {}, {},
+				htmlNumber(s.CalculateLevel().LevelAsString(s.Container())),
+				htmlText(s.RelativeLevel()),
+				htmlNumber(s.AdjustedPoints(nil).Comma()),
+				htmlText(s.PageRef),
+			}
+			if !s.Container() {
+				cells[1] = htmlText(s.ClassWithReplacements())
+				cells[2] = htmlText(strings.Join(s.CollegeWithReplacements(), ", "))
+				cells[3] = htmlText(s.CastingCostWithReplacements())
+				cells[4] = htmlText(s.MaintenanceCostWithReplacements())
+				cells[5] = htmlText(s.CastingTimeWithReplacements())
+				cells[6] = htmlText(s.DurationWithReplacements())
+			}
+			return cells
+		}),
+	})
+}
+
+func (h *htmlWriter) addGrimoire() {
+	e := h.entity
+	if !e.RitualPathMagic() {
+		return
+	}
+	section := &htmlSection{
+		Title: i18n.Text("Grimoire"),
+		Headers: htmlHeaders(i18n.Text("Ritual"), i18n.Text("Effects"), i18n.Text("Modifiers"), i18n.Text("Energy"),
+			i18n.Text("Skill")),
+	}
+	for _, r := range e.Grimoire.Rituals {
+		section.Rows = append(section.Rows, &htmlRow{Cells: []htmlCell{
+			h.nameCell(r.Name, "", "", r.Notes),
+			htmlText(r.EffectsSummary()),
+			htmlText(r.ModifiersSummary()),
+			htmlNumber(strconv.Itoa(r.Energy())),
+			htmlNumber(e.RitualLevel(r).Floor().String()),
+		}})
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addEquipment(title string, list []*gurps.Equipment, carried bool) {
+	units := h.entity.SheetSettings.DefaultWeightUnits
+	h.add(&htmlSection{
+		Title: title,
+		Headers: htmlHeaders(i18n.Text("#"), i18n.Text("Item"), i18n.Text("Uses"), i18n.Text("TL"),
+			i18n.Text("LC"), i18n.Text("$"), i18n.Text("Wt"), i18n.Text("Σ $"), i18n.Text("Σ Wt"), i18n.Text("Ref")),
+		Rows: htmlTreeRows(nil, list, "", 0, true, false, func(e *gurps.Equipment) []htmlCell {
+			name := h.nameCell(e.String(), "", e.ModifierNotes(), e.Notes())
+			if carried && e.ReallyEquipped() {
+				name.Text = "✓ " + name.Text
+			}
+			uses := ""
+			if e.MaxUses > 0 {
+				uses = fmt.Sprintf("%d/%d", e.Uses, e.MaxUses)
+			}
+			return []htmlCell{
+				htmlNumber(e.Quantity.Comma()),
+				name,
+				htmlNumber(uses),
+				htmlText(e.TechLevel),
+				htmlText(e.LegalityClass),
+				htmlNumber(e.AdjustedValue().Comma()),
+				htmlNumber(units.Format(e.AdjustedWeight(false, units))),
+				htmlNumber(e.ExtendedValue().Comma()),
+				htmlNumber(units.Format(e.ExtendedWeight(false, units))),
+				htmlText(e.PageRef),
+			}
+		}),
+	})
+}
+
+func (h *htmlWriter) addCustomBlock(block *gurps.CustomBlock) {
+	section := &htmlSection{Title: block.TitleOrDefault()}
+	for _, field := range block.Fields {
+		section.Lines = append(section.Lines, htmlPair{Label: field.Label, Value: field.ResolvedValue(h.entity)})
+	}
+	if text := strings.TrimSpace(block.Text); text != "" {
+		section.Text = strings.Split(text, "\n")
+	}
+	h.add(section)
+}
+
+func (h *htmlWriter) addNotes() {
+	h.add(&htmlSection{
+		Title:   i18n.Text("Notes"),
+		Headers: htmlHeaders(i18n.Text("Note"), i18n.Text("Ref")),
+		Rows: htmlTreeRows(nil, h.entity.Notes, "", 0, true, true, func(n *gurps.Note) []htmlCell {
+			return []htmlCell{
+				htmlText(n.String()),
+				htmlText(n.PageRef),
+			}
+		}),
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/display"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestWriteHTML(t *testing.T) {
+	c := check.New(t)
+	entity := gurps.NewEntity()
+	entity.Profile.Name = "Test <Hero>"
+	container := gurps.NewTrait(entity, nil, true)
+	container.Name = "Package"
+	container.SetOpen(false)
+	child := gurps.NewTrait(entity, container, false)
+	child.Name = "Combat Reflexes"
+	child.LocalNotes = "Secret notes"
+	container.Children = []*gurps.Trait{child}
+	entity.SetTraitList([]*gurps.Trait{container})
+
+	entity.SheetSettings.NotesDisplay = display.Tooltip
+	var buffer strings.Builder
+	c.NoError(WriteHTML(&buffer, entity))
+	out := buffer.String()
+	c.HasPrefix(out, "<!DOCTYPE html>")
+	c.Contains(out, "<title>Test &lt;Hero&gt;</title>")
+	c.Contains(out, `data-parent="`+string(container.TID)+`"`)
+	c.Contains(out, `data-open="false"`)
+	c.Contains(out, `class="hidden"`)
+	c.Contains(out, `title="Secret notes"`)
+	c.NotContains(out, `<div class="extra">Secret notes</div>`)
+	c.Contains(out, "@media print")
+
+	entity.SheetSettings.NotesDisplay = display.Inline
+	buffer.Reset()
+	c.NoError(WriteHTML(&buffer, entity))
+	out = buffer.String()
+	c.Contains(out, `<div class="extra">Secret notes</div>`)
+	c.NotContains(out, `title="Secret notes"`)
+}
@@ -0,0 +1,546 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/encumbrance"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// MarkdownExtension is the file extension used for Markdown exports.
+const MarkdownExtension = ".md"
+
+type markdownWriter struct {
+	w      io.Writer
+	entity *gurps.Entity
+	err    error
+}
+
+// ToMarkdown exports the entity to path as a Markdown document.
+func ToMarkdown(entity *gurps.Entity, path string) error {
+	return writeFile(path, func(w io.Writer) error { return WriteMarkdown(w, entity) })
+}
+
+// WriteMarkdown writes the entity to w as a Markdown document. The blocks of the sheet are written in the order
+// specified by the sheet's block layout. Since this will cause the entity to be recalculated, it should only be called
+// from the UI thread.
+func WriteMarkdown(w io.Writer, entity *gurps.Entity) error {
+	entity.Recalculate()
+	m := &markdownWriter{
+		w:      w,
+		entity: entity,
+	}
+	m.writeHeader()
+	for _, row := range entity.SheetSettings.BlockLayout.ByRow() {
+		for _, key := range row {
+			m.writeBlock(key)
+		}
+	}
+	return m.err
+}
+
+func (m *markdownWriter) printf(format string, args ...any) {
+	if m.err == nil {
+		if _, err := fmt.Fprintf(m.w, format, args...); err != nil {
+			m.err = errs.Wrap(err)
+		}
+	}
+}
+
+func (m *markdownWriter) heading(level int, title string) {
+	m.printf("%s %s\n\n", strings.Repeat("#", level), markdownEscape(title))
+}
+
+func (m *markdownWriter) table(headers []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	m.printf("| %s |\n", strings.Join(headers, " | "))
+	m.printf("|%s\n", strings.Repeat(" --- |", len(headers)))
+	for _, row := range rows {
+		for i := range row {
+			row[i] = markdownEscape(row[i])
+		}
+		m.printf("| %s |\n", strings.Join(row, " | "))
+	}
+	m.printf("\n")
+}
+
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func markdownIndent(depth int) string {
+	return strings.Repeat("&emsp;", depth)
+}
+
+func markdownNameWithNotes(depth int, name, notes string) string {
+	if notes = strings.TrimSpace(notes); notes != "" {
+		name += "\n" + notes
+	}
+	return markdownIndent(depth) + name
+}
+
+func (m *markdownWriter) writeHeader() {
+	e := m.entity
+	units := e.SheetSettings.DefaultWeightUnits
+	name := e.Profile.Name
+	if name == "" {
+		name = i18n.Text("Unnamed Character")
+	}
+	m.heading(1, name)
+	for _, one := range []struct{ title, value string }{
+		{i18n.Text("Player"), e.Profile.PlayerName},
+		{i18n.Text("Title"), e.Profile.Title},
+		{i18n.Text("Organization"), e.Profile.Organization},
+		{i18n.Text("Religion"), e.Profile.Religion},
+		{i18n.Text("TL"), e.Profile.TechLevel},
+		{i18n.Text("Gender"), e.Profile.Gender},
+		{i18n.Text("Age"), e.Profile.Age},
+		{i18n.Text("Birthday"), e.Profile.Birthday},
+		{i18n.Text("Eyes"), e.Profile.Eyes},
+		{i18n.Text("Hair"), e.Profile.Hair},
+		{i18n.Text("Skin"), e.Profile.Skin},
+		{i18n.Text("Hand"), e.Profile.Handedness},
+		{i18n.Text("Height"), e.SheetSettings.DefaultLengthUnits.Format(e.Profile.Height)},
+		{i18n.Text("Weight"), units.Format(e.Profile.Weight)},
+	} {
+		if one.value != "" {
+			m.printf("- **%s:** %s\n", one.title, markdownEscape(one.value))
+		}
+	}
+	if sm := e.Profile.AdjustedSizeModifier(); sm != 0 {
+		m.printf("- **%s:** %+d\n", i18n.Text("Size Modifier"), sm)
+	}
+	m.printf("- **%s:** %s (%s %s)\n\n", i18n.Text("Points"), e.TotalPoints.Comma(), e.UnspentPoints().Comma(),
+		i18n.Text("unspent"))
+
+	m.heading(2, i18n.Text("Attributes"))
+	var rows [][]string
+	for _, def := range e.SheetSettings.Attributes.List(true) {
+		if def.Pool() || def.IsSeparator() {
+			continue
+		}
+		if attr, ok := e.Attributes.Set[def.DefID]; ok {
+			rows = append(rows, []string{def.CombinedName(), attr.Maximum().Comma(), attr.PointCost().Comma()})
+		}
+	}
+	m.table([]string{i18n.Text("Attribute"), i18n.Text("Value"), i18n.Text("Points")}, rows)
+	m.printf("**%s:** %s, **%s:** %s\n\n", i18n.Text("Thrust"), e.Thrust().String(), i18n.Text("Swing"),
+		e.Swing().String())
+
+	m.heading(2, i18n.Text("Encumbrance"))
+	rows = nil
+	current := e.EncumbranceLevel(false)
+	for _, enc := range encumbrance.Levels {
+		level := enc.String()
+		if enc == current {
+			level = "**" + level + "**"
+		}
+		rows = append(rows, []string{level, units.Format(e.MaximumCarry(enc)), strconv.Itoa(e.Move(enc)),
+			e.DodgeText(enc)})
+	}
+	m.table([]string{i18n.Text("Level"), i18n.Text("Max Load"), i18n.Text("Move"), i18n.Text("Dodge")}, rows)
+
+	m.heading(2, i18n.Text("Hit Locations"))
+	rows = nil
+	for _, one := range e.HitLocationDRs() {
+		rows = append(rows, []string{one.Location.RollRange, markdownIndent(one.Depth) + one.Location.TableName,
+			fmt.Sprintf("%+d", one.Location.HitPenalty), one.DR, gurps.ArmorLayersSummary(one.Layers)})
+	}
+	m.table([]string{i18n.Text("Roll"), i18n.Text("Location"), i18n.Text("Penalty"), i18n.Text("DR"),
+		i18n.Text("Armor")}, rows)
+}
+
+func (m *markdownWriter) writeBlock(key string) {
+	e := m.entity
+	switch key {
+	case gurps.BlockLayoutReactionsKey:
+		m.writeModifiers(i18n.Text("Reactions"), e.Reactions())
+	case gurps.BlockLayoutConditionalModifiersKey:
+		m.writeModifiers(i18n.Text("Conditional Modifiers"), e.ConditionalModifiers())
+	case gurps.BlockLayoutReactionSummaryKey:
+		m.writeReactionSummary()
+	case gurps.BlockLayoutPoolTrackersKey:
+		m.writePools()
+	case gurps.BlockLayoutWoundsKey:
+		m.writeWounds()
+	case gurps.BlockLayoutConditionsKey:
+		m.writeConditions()
+	case gurps.BlockLayoutGrapplingKey:
+		m.writeGrappling()
+	case gurps.BlockLayoutFatigueKey:
+		m.writeFatigue()
+	case gurps.BlockLayoutMeleeKey:
+		m.writeMeleeWeapons()
+	case gurps.BlockLayoutRangedKey:
+		m.writeRangedWeapons()
+	case gurps.BlockLayoutTraitsKey:
+		m.writeTraits()
+	case gurps.BlockLayoutSkillsKey:
+		m.writeSkills()
+	case gurps.BlockLayoutSpellsKey:
+		m.writeSpells()
+	case gurps.BlockLayoutGrimoireKey:
+		m.writeGrimoire()
+	case gurps.BlockLayoutEquipmentKey:
+		m.writeEquipment(fmt.Sprintf(i18n.Text("Carried Equipment (%s; $%s)"),
+			e.SheetSettings.DefaultWeightUnits.Format(e.WeightCarried(false)), e.WealthCarried().Comma()),
+			e.CarriedEquipment, true)
+	case gurps.BlockLayoutOtherEquipmentKey:
+		m.writeEquipment(fmt.Sprintf(i18n.Text("Other Equipment ($%s)"), e.WealthNotCarried().Comma()),
+			e.OtherEquipment, false)
+	case gurps.BlockLayoutNotesKey:
+		m.writeNotes()
+	default:
+		if block := e.SheetSettings.CustomBlockFor(key); block != nil {
+			m.writeCustomBlock(block)
+		}
+	}
+}
+
+func (m *markdownWriter) writeModifiers(title string, list []*gurps.ConditionalModifier) {
+	if len(list) == 0 {
+		return
+	}
+	m.heading(2, title)
+	rows := make([][]string, 0, len(list))
+	for _, one := range list {
+		rows = append(rows, []string{one.Total().StringWithSign(), one.From})
+	}
+	m.table([]string{i18n.Text("Modifier"), i18n.Text("Situation")}, rows)
+}
+
+func (m *markdownWriter) writeReactionSummary() {
+	groups := m.entity.ReactionSummary()
+	if len(groups) == 0 {
+		return
+	}
+	m.heading(2, i18n.Text("Reaction Summary"))
+	kinds := gurps.ReactionSourceKinds(groups)
+	headers := make([]string, 0, len(kinds)+2)
+	headers = append(headers, i18n.Text("Group"))
+	for _, kind := range kinds {
+		headers = append(headers, kind.String())
+	}
+	headers = append(headers, i18n.Text("Total"))
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		row := make([]string, 0, len(headers))
+		row = append(row, g.Group)
+		for _, kind := range kinds {
+			var value string
+			if g.Has(kind) {
+				value = g.TotalFor(kind).StringWithSign()
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, append(row, g.Total().StringWithSign()))
+	}
+	m.table(headers, rows)
+}
+
+func (m *markdownWriter) writePools() {
+	e := m.entity
+	var rows [][]string
+	for _, def := range e.SheetSettings.Attributes.List(true) {
+		if !def.Pool() {
+			continue
+		}
+		if attr, ok := e.Attributes.Set[def.DefID]; ok {
+			state := ""
+			if threshold := attr.CurrentThreshold(); threshold != nil {
+				state = threshold.State
+			}
+			rows = append(rows, []string{def.CombinedName(), attr.Current().Comma(), attr.Maximum().Comma(), state})
+		}
+	}
+	if len(rows) != 0 {
+		m.heading(2, i18n.Text("Pools"))
+		m.table([]string{i18n.Text("Pool"), i18n.Text("Current"), i18n.Text("Maximum"), i18n.Text("State")}, rows)
+	}
+}
+
+func (m *markdownWriter) writeWounds() {
+	e := m.entity
+	if len(e.Wounds) == 0 {
+		return
+	}
+	m.heading(2, i18n.Text("Wounds"))
+	body := gurps.BodyFor(e)
+	rows := make([][]string, 0, len(e.Wounds))
+	for _, w := range e.Wounds {
+		name := w.LocationID
+		if loc := body.LookupLocationByID(e, w.LocationID); loc != nil {
+			name = loc.ChoiceName
+		}
+		var effects []string
+		if w.Crippling {
+			effects = append(effects, i18n.Text("Crippling"))
+		}
+		if w.Bleeding {
+			effects = append(effects, i18n.Text("Bleeding"))
+		}
+		rows = append(rows, []string{name, w.DamageType, strconv.Itoa(w.Basic), strconv.Itoa(w.DR),
+			strconv.Itoa(w.Injury), strings.Join(effects, ", ")})
+	}
+	m.table([]string{i18n.Text("Location"), i18n.Text("Type"), i18n.Text("Basic"), i18n.Text("DR"),
+		i18n.Text("Injury"), i18n.Text("Effects")}, rows)
+}
+
+func (m *markdownWriter) writeConditions() {
+	e := m.entity
+	if len(e.Conditions) == 0 {
+		return
+	}
+	m.heading(2, i18n.Text("Conditions"))
+	rows := make([][]string, 0, len(e.Conditions))
+	for _, c := range e.Conditions {
+		turns := ""
+		if c.Timed() {
+			turns = strconv.Itoa(c.Turns)
+		}
+		rows = append(rows, []string{c.String(), turns, c.Notes})
+	}
+	m.table([]string{i18n.Text("Condition"), i18n.Text("Turns"), i18n.Text("Notes")}, rows)
+}
+
+func (m *markdownWriter) writeGrappling() {
+	e := m.entity
+	if !e.TechnicalGrappling() {
+		return
+	}
+	m.heading(2, i18n.Text("Grappling"))
+	m.printf("- **%s:** %s\n", i18n.Text("Trained ST"), e.TrainedST().Comma())
+	m.printf("- **%s:** %s CP\n", i18n.Text("Control"), e.ControlDamage().String())
+	m.printf("- **%s:** %d\n", i18n.Text("CP Inflicted"), e.Grapple.Inflicted)
+	m.printf("- **%s:** %d\n", i18n.Text("CP Suffered"), e.Grapple.Suffered)
+	m.printf("- **%s:** %d\n\n", i18n.Text("DX Penalty"), e.GrapplePenalty())
+}
+
+func (m *markdownWriter) writeFatigue() {
+	e := m.entity
+	if len(e.FatigueLog) == 0 {
+		return
+	}
+	m.heading(2, i18n.Text("Fatigue Expenditures"))
+	rows := make([][]string, 0, len(e.FatigueLog))
+	for _, one := range e.FatigueLog {
+		rows = append(rows, []string{gurps.FatigueKindName(one.Kind), one.Notes, strconv.Itoa(one.Amount)})
+	}
+	m.table([]string{i18n.Text("Kind"), i18n.Text("Notes"), i18n.Text("FP")}, rows)
+}
+
+func (m *markdownWriter) writeMeleeWeapons() {
+	e := m.entity
+	list := e.Weapons(true, e.SheetSettings.ShowAllWeapons, true)
+	if len(list) == 0 {
+		return
+	}
+	m.heading(2, i18n.Text("Melee Weapons"))
+	rows := make([][]string, 0, len(list))
+	for _, w := range list {
+		rows = append(rows, []string{
+			markdownNameWithNotes(0, w.String(), w.Notes()),
+			w.UsageWithReplacements(),
+			w.SkillLevel(nil).Comma(),
+			w.Damage.ResolvedDamage(nil),
+			w.Reach.Resolve(w, nil).String(),
+			w.Parry.Resolve(w, nil).String(),
+			w.Block.Resolve(w, nil).String(),
+			w.Strength.Resolve(w, nil).String(),
+		})
+	}
+	m.table([]string{i18n.Text("Weapon"), i18n.Text("Usage"), i18n.Text("SL"), i18n.Text("Damage"),
+		i18n.Text("Reach"), i18n.Text("Parry"), i18n.Text("Block"), i18n.Text("ST")}, rows)
+}
+
+func (m *markdownWriter) writeRangedWeapons() {
+	e := m.entity
+	list := e.Weapons(false, e.SheetSettings.ShowAllWeapons, true)
+	if len(list) == 0 {
+		return
+	}
+	m.heading(2, i18n.Text("Ranged Weapons"))
+	rows := make([][]string, 0, len(list))
+	for _, w := range list {
+		rows = append(rows, []string{
+			markdownNameWithNotes(0, w.String(), w.Notes()),
+			w.UsageWithReplacements(),
+			w.SkillLevel(nil).Comma(),
+			w.Damage.ResolvedDamage(nil),
+			w.Accuracy.Resolve(w, nil).String(),
+			w.Range.Resolve(w, nil).String(true),
+			w.RateOfFire.Resolve(w, nil).String(),
+			w.Shots.Resolve(w, nil).String(),
+			w.Bulk.Resolve(w, nil).String(),
+			w.Recoil.Resolve(w, nil).String(),
+			w.Strength.Resolve(w, nil).String(),
+		})
+	}
+	m.table([]string{i18n.Text("Weapon"), i18n.Text("Usage"), i18n.Text("SL"), i18n.Text("Damage"),
+		i18n.Text("Acc"), i18n.Text("Range"), i18n.Text("RoF"), i18n.Text("Shots"), i18n.Text("Bulk"),
+		i18n.Text("Rcl"), i18n.Text("ST")}, rows)
+}
+
+func (m *markdownWriter) writeTraits() {
+	var rows [][]string
+	gurps.Traverse(func(t *gurps.Trait) bool {
+		notes := t.Notes()
+		if modNotes := t.ModifierNotes(); modNotes != "" {
+			notes = strings.TrimSpace(modNotes + "\n" + notes)
+		}
+		rows = append(rows, []string{markdownNameWithNotes(t.Depth(), t.String(), notes),
+			t.AdjustedPoints().Comma(), t.PageRef})
+		return false
+	}, true, false, m.entity.Traits...)
+	if len(rows) != 0 {
+		m.heading(2, i18n.Text("Traits"))
+		m.table([]string{i18n.Text("Trait"), i18n.Text("Pts"), i18n.Text("Ref")}, rows)
+	}
+}
+
+func (m *markdownWriter) writeSkills() {
+	var rows [][]string
+	gurps.Traverse(func(s *gurps.Skill) bool {
+		rows = append(rows, []string{markdownNameWithNotes(s.Depth(), s.String(), s.Notes()),
+			s.CalculateLevel(nil).LevelAsString(s.Container()), s.RelativeLevel(),
+			s.AdjustedPoints(nil).Comma(), s.PageRef})
+		return false
+	}, true, false, m.entity.Skills...)
+	if len(rows) != 0 {
+		m.heading(2, i18n.Text("Skills"))
+		m.table([]string{i18n.Text("Skill"), i18n.Text("SL"), i18n.Text("RSL"), i18n.Text("Pts"), i18n.Text("Ref")},
+			rows)
+	}
+}
+
+func (m *markdownWriter) writeGrimoire() {
+	e := m.entity
+	if !e.RitualPathMagic() || len(e.Grimoire.Rituals) == 0 {
+		return
+	}
+	m.heading(2, i18n.Text("Grimoire"))
+	m.printf("- **%s:** %d\n", i18n.Text("Magery"), e.Magery())
+	m.printf("- **%s:** %s\n", i18n.Text("Thaumatology"), e.ThaumatologyLevel().Floor().String())
+	m.printf("- **%s:** %d\n\n", i18n.Text("Energy Gathered"), e.Grimoire.Gathered)
+	rows := make([][]string, 0, len(e.Grimoire.Rituals))
+	for _, r := range e.Grimoire.Rituals {
+		rows = append(rows, []string{r.Name, r.EffectsSummary(), r.ModifiersSummary(), strconv.Itoa(r.Energy()),
+			e.RitualLevel(r).Floor().String()})
+	}
+	m.table([]string{i18n.Text("Ritual"), i18n.Text("Effects"), i18n.Text("Modifiers"), i18n.Text("Energy"),
+		i18n.Text("Skill")}, rows)
+}
+
+func (m *markdownWriter) writeSpells() {
+	var rows [][]string
+	gurps.Traverse(func(s *gurps.Spell) bool {
+		row := []string{markdownNameWithNotes(s.Depth(), s.String(), s.Notes()), "", "", "", "", "", "",
+			s.CalculateLevel().LevelAsString(s.Container()), s.RelativeLevel(), s.AdjustedPoints(nil).Comma(),
+			s.PageRef}
+		if !s.Container() {
+			row[1] = s.ClassWithReplacements()
+			row[2] = strings.Join(s.CollegeWithReplacements(), ", ")
+			row[3] = s.CastingCostWithReplacements()
+			row[4] = s.MaintenanceCostWithReplacements()
+			row[5] = s.CastingTimeWithReplacements()
+			row[6] = s.DurationWithReplacements()
+		}
+		rows = append(rows, row)
+		return false
+	}, true, false, m.entity.Spells...)
+	if len(rows) != 0 {
+		m.heading(2, i18n.Text("Spells"))
+		m.table([]string{i18n.Text("Spell"), i18n.Text("Class"), i18n.Text("College"), i18n.Text("Cost"),
+			i18n.Text("Maintain"), i18n.Text("Time"), i18n.Text("Duration"), i18n.Text("SL"), i18n.Text("RSL"),
+			i18n.Text("Pts"), i18n.Text("Ref")}, rows)
+	}
+}
+
+func (m *markdownWriter) writeEquipment(title string, list []*gurps.Equipment, carried bool) {
+	units := m.entity.SheetSettings.DefaultWeightUnits
+	var rows [][]string
+	gurps.Traverse(func(e *gurps.Equipment) bool {
+		name := markdownNameWithNotes(e.Depth(), e.String(), e.Notes())
+		if carried && e.ReallyEquipped() {
+			name = "✓ " + name
+		}
+		uses := ""
+		if e.MaxUses > 0 {
+			uses = fmt.Sprintf("%d/%d", e.Uses, e.MaxUses)
+		}
+		rows = append(rows, []string{
+			e.Quantity.Comma(),
+			name,
+			uses,
+			e.TechLevel,
+			e.LegalityClass,
+			e.AdjustedValue().Comma(),
+			units.Format(e.AdjustedWeight(false, units)),
+			e.ExtendedValue().Comma(),
+			units.Format(e.ExtendedWeight(false, units)),
+			e.PageRef,
+		})
+		return false
+	}, false, false, list...)
+	if len(rows) != 0 {
+		m.heading(2, title)
+		m.table([]string{i18n.Text("#"), i18n.Text("Item"), i18n.Text("Uses"), i18n.Text("TL"), i18n.Text("LC"),
+			i18n.Text("$"), i18n.Text("Wt"), i18n.Text("Σ $"), i18n.Text("Σ Wt"), i18n.Text("Ref")}, rows)
+	}
+}
+
+func (m *markdownWriter) writeCustomBlock(block *gurps.CustomBlock) {
+	if block.Empty() {
+		return
+	}
+	m.heading(2, block.TitleOrDefault())
+	for _, field := range block.Fields {
+		m.printf("- **%s:** %s\n", markdownEscape(field.Label), markdownEscape(field.ResolvedValue(m.entity)))
+	}
+	if len(block.Fields) != 0 {
+		m.printf("\n")
+	}
+	if text := strings.TrimSpace(block.Text); text != "" {
+		m.printf("%s\n\n", text)
+	}
+}
+
+func (m *markdownWriter) writeNotes() {
+	var notes []*gurps.Note
+	gurps.Traverse(func(n *gurps.Note) bool {
+		notes = append(notes, n)
+		return false
+	}, true, false, m.entity.Notes...)
+	if len(notes) == 0 {
+		return
+	}
+	m.heading(2, i18n.Text("Notes"))
+	for _, n := range notes {
+		text := strings.TrimSpace(n.String())
+		if n.Container() {
+			m.heading(min(3+n.Depth(), 6), text)
+			continue
+		}
+		if n.PageRef != "" {
+			text += " (" + n.PageRef + ")"
+		}
+		m.printf("%s\n\n", text)
+	}
+}
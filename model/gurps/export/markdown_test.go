@@ -0,0 +1,53 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestMarkdownEscape(t *testing.T) {
+	c := check.New(t)
+	c.Equal(`a \| b`, markdownEscape(" a | b "))
+	c.Equal("line 1<br>line 2", markdownEscape("line 1\nline 2"))
+	c.Equal("&emsp;&emsp;Name<br>Notes", markdownEscape(markdownNameWithNotes(2, "Name", "Notes")))
+}
+
+func TestWriteMarkdownFollowsBlockLayout(t *testing.T) {
+	c := check.New(t)
+	entity := gurps.NewEntity()
+	entity.Profile.Name = "Test"
+	trait := gurps.NewTrait(entity, nil, false)
+	trait.Name = "Combat Reflexes"
+	entity.SetTraitList([]*gurps.Trait{trait})
+	skill := gurps.NewSkill(entity, nil, false)
+	skill.Name = "Broadsword"
+	entity.SetSkillList([]*gurps.Skill{skill})
+
+	entity.SheetSettings.BlockLayout.Layout = []string{gurps.BlockLayoutSkillsKey, gurps.BlockLayoutTraitsKey}
+	var buffer strings.Builder
+	c.NoError(WriteMarkdown(&buffer, entity))
+	out := buffer.String()
+	c.HasPrefix(out, "# Test\n")
+	skillsAt := strings.Index(out, "## Skills")
+	traitsAt := strings.Index(out, "## Traits")
+	c.True(skillsAt != -1 && traitsAt != -1 && skillsAt < traitsAt)
+	c.Contains(out, "| Combat Reflexes |")
+
+	entity.SheetSettings.BlockLayout.Layout = []string{gurps.BlockLayoutTraitsKey, gurps.BlockLayoutSkillsKey}
+	buffer.Reset()
+	c.NoError(WriteMarkdown(&buffer, entity))
+	out = buffer.String()
+	c.True(strings.Index(out, "## Traits") < strings.Index(out, "## Skills"))
+}
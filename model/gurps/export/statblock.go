@@ -0,0 +1,178 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// StatblockExtension is the file extension used for statblock exports.
+const StatblockExtension = ".txt"
+
+// DefaultStatblockTemplate is the template used for statblocks when no other has been configured. It is parsed with
+// Go's text/template package and is given a StatblockData.
+const DefaultStatblockTemplate = `{{.Name}}
+{{join .Attributes "; "}}
+Dodge {{.Dodge}}; Move {{.Move}}{{with .DR}}; DR {{.}}{{end}}{{with .LocationDR}}; {{join . ", "}}{{end}}
+{{- with .Traits}}
+Traits: {{join . "; "}}.{{end}}
+{{- with .Skills}}
+Skills: {{join . "; "}}.{{end}}
+{{- with .Spells}}
+Spells: {{join . "; "}}.{{end}}
+{{- with .Attacks}}
+Attacks: {{join . "; "}}.{{end}}
+`
+
+// StatblockOptions holds the options used to produce a statblock.
+type StatblockOptions struct {
+	Template       string
+	SkillThreshold int
+}
+
+// StatblockData holds the values made available to a statblock template.
+type StatblockData struct {
+	Name       string
+	Attributes []string
+	Dodge      int
+	Move       int
+	DR         string
+	LocationDR []string
+	Traits     []string
+	Skills     []string
+	Spells     []string
+	Attacks    []string
+}
+
+// StatblockOptionsFromSettings returns the statblock options from the global settings.
+func StatblockOptionsFromSettings() StatblockOptions {
+	general := gurps.GlobalSettings().General
+	return StatblockOptions{
+		Template:       general.StatblockTemplate,
+		SkillThreshold: general.StatblockSkillThreshold,
+	}
+}
+
+// ToStatblock exports the entity to path as a statblock, using the options from the global settings.
+func ToStatblock(entity *gurps.Entity, path string) error {
+	return writeFile(path, func(w io.Writer) error { return WriteStatblock(w, entity, StatblockOptionsFromSettings()) })
+}
+
+// Statblock returns the entity as a statblock. Since this will cause the entity to be recalculated, it should only be
+// called from the UI thread.
+func Statblock(entity *gurps.Entity, options StatblockOptions) (string, error) {
+	var buffer strings.Builder
+	if err := WriteStatblock(&buffer, entity, options); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// WriteStatblock writes the entity to w as a compact statblock, suitable for quick reference by a GM. Only skills and
+// spells at or above the skill threshold in the options are included. Since this will cause the entity to be
+// recalculated, it should only be called from the UI thread.
+func WriteStatblock(w io.Writer, entity *gurps.Entity, options StatblockOptions) error {
+	text := options.Template
+	if strings.TrimSpace(text) == "" {
+		text = DefaultStatblockTemplate
+	}
+	tmpl, err := template.New("statblock").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	entity.Recalculate()
+	if err = tmpl.Execute(w, NewStatblockData(entity, options.SkillThreshold)); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+// NewStatblockData collects the values for a statblock from the entity.
+func NewStatblockData(entity *gurps.Entity, skillThreshold int) *StatblockData {
+	enc := entity.EncumbranceLevel(false)
+	data := &StatblockData{
+		Name:  entity.Profile.Name,
+		Dodge: entity.Dodge(enc),
+		Move:  entity.Move(enc),
+	}
+	for _, def := range entity.SheetSettings.Attributes.List(true) {
+		if def.IsSeparator() {
+			continue
+		}
+		if attr, ok := entity.Attributes.Set[def.DefID]; ok {
+			data.Attributes = append(data.Attributes, def.Name+" "+attr.Maximum().String())
+		}
+	}
+	data.DR = "0"
+	if loc := gurps.BodyFor(entity).LookupLocationByID(entity, "torso"); loc != nil {
+		data.DR = loc.DisplayDR(entity, nil)
+	}
+	for _, one := range entity.HitLocationDRs() {
+		if one.DR != data.DR {
+			data.LocationDR = append(data.LocationDR, fmt.Sprintf(i18n.Text("%s DR %s"), one.Location.TableName, one.DR))
+		}
+	}
+	if data.DR == "0" {
+		data.DR = ""
+	}
+	gurps.Traverse(func(t *gurps.Trait) bool {
+		data.Traits = append(data.Traits, fmt.Sprintf("%s [%s]", t.String(), t.AdjustedPoints().String()))
+		return false
+	}, true, true, entity.Traits...)
+	threshold := fxp.FromInteger(skillThreshold)
+	gurps.Traverse(func(s *gurps.Skill) bool {
+		if level := s.CalculateLevel(nil).Level; level >= threshold {
+			data.Skills = append(data.Skills, s.String()+"-"+level.Floor().String())
+		}
+		return false
+	}, true, true, entity.Skills...)
+	gurps.Traverse(func(s *gurps.Spell) bool {
+		if level := s.CalculateLevel().Level; level >= threshold {
+			data.Spells = append(data.Spells, s.String()+"-"+level.Floor().String())
+		}
+		return false
+	}, true, true, entity.Spells...)
+	for _, w := range entity.Weapons(true, false, true) {
+		attack := fmt.Sprintf("%s (%s): %s", statblockWeaponName(w), w.SkillLevel(nil).Floor().String(),
+			w.Damage.ResolvedDamage(nil))
+		if reach := w.Reach.Resolve(w, nil).String(); reach != "" {
+			attack += ", Reach " + reach
+		}
+		data.Attacks = append(data.Attacks, attack)
+	}
+	for _, w := range entity.Weapons(false, false, true) {
+		attack := fmt.Sprintf("%s (%s): %s", statblockWeaponName(w), w.SkillLevel(nil).Floor().String(),
+			w.Damage.ResolvedDamage(nil))
+		if acc := w.Accuracy.Resolve(w, nil).String(); acc != "" {
+			attack += ", Acc " + acc
+		}
+		if rng := w.Range.Resolve(w, nil).String(true); rng != "" {
+			attack += ", Range " + rng
+		}
+		data.Attacks = append(data.Attacks, attack)
+	}
+	return data
+}
+
+func statblockWeaponName(w *gurps.Weapon) string {
+	name := w.String()
+	if usage := w.UsageWithReplacements(); usage != "" {
+		name += ", " + usage
+	}
+	return name
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestStatblock(t *testing.T) {
+	c := check.New(t)
+	entity := gurps.NewEntity()
+	entity.Profile.Name = "Guard"
+	good := gurps.NewSkill(entity, nil, false)
+	good.Name = "Broadsword"
+	good.Points = fxp.FromInteger(24)
+	poor := gurps.NewSkill(entity, nil, false)
+	poor.Name = "Cooking"
+	poor.Points = fxp.One
+	entity.SetSkillList([]*gurps.Skill{good, poor})
+
+	text, err := Statblock(entity, StatblockOptions{SkillThreshold: 12})
+	c.NoError(err)
+	c.HasPrefix(text, "Guard\n")
+	c.Contains(text, "Skills: Broadsword-")
+	c.NotContains(text, "Cooking")
+
+	text, err = Statblock(entity, StatblockOptions{
+		Template:       `{{.Name}}: {{join .Skills ", "}}`,
+		SkillThreshold: 1,
+	})
+	c.NoError(err)
+	c.HasPrefix(text, "Guard: Broadsword-")
+	c.Contains(text, ", Cooking-")
+
+	_, err = Statblock(entity, StatblockOptions{Template: "{{.Missing"})
+	c.HasError(err)
+}
@@ -0,0 +1,323 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/cell"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// File extensions used for table exports.
+const (
+	CSVExtension  = ".csv"
+	XLSXExtension = ".xlsx"
+)
+
+// Table holds the rows of a list, flattened and with each cell reduced to text, ready to be written to a spreadsheet.
+type Table struct {
+	Name    string
+	Headers []string
+	Rows    [][]string
+}
+
+var _ gurps.EquipmentListProvider = &equipmentListOwner{}
+
+// equipmentListOwner adapts a gurps.DataOwnerProvider so that it can be used to obtain equipment column headers.
+type equipmentListOwner struct {
+	gurps.DataOwnerProvider
+}
+
+func (e *equipmentListOwner) CarriedEquipmentList() []*gurps.Equipment     { return nil }
+func (e *equipmentListOwner) SetCarriedEquipmentList(_ []*gurps.Equipment) {}
+func (e *equipmentListOwner) OtherEquipmentList() []*gurps.Equipment       { return nil }
+func (e *equipmentListOwner) SetOtherEquipmentList(_ []*gurps.Equipment)   {}
+
+// SupportsTable returns true if TableFor can produce a Table for lists of the given type.
+func SupportsTable[T gurps.NodeTypes]() bool {
+	switch any([]T(nil)).(type) {
+	case []*gurps.Trait, []*gurps.Skill, []*gurps.Spell, []*gurps.Equipment:
+		return true
+	default:
+		return false
+	}
+}
+
+// TableFor returns a Table for the list, or nil if the list's type is not supported. Traits, skills, spells, and
+// equipment are supported. Computed columns, such as skill levels and extended equipment weights and values, are
+// included even when they would not normally be shown for the list.
+func TableFor[T gurps.NodeTypes](owner gurps.DataOwnerProvider, list []T) *Table {
+	switch data := any(list).(type) {
+	case []*gurps.Trait:
+		return newTable(i18n.Text("Traits"), data, gurps.TraitsHeaderData,
+			gurps.TraitDescriptionColumn,
+			gurps.TraitPointsColumn,
+			gurps.TraitTagsColumn,
+			gurps.TraitReferenceColumn,
+		)
+	case []*gurps.Skill:
+		return newTable(i18n.Text("Skills"), data, gurps.SkillsHeaderData,
+			gurps.SkillDescriptionColumn,
+			gurps.SkillDifficultyColumn,
+			gurps.SkillLevelColumn,
+			gurps.SkillRelativeLevelColumn,
+			gurps.SkillPointsColumn,
+			gurps.SkillTagsColumn,
+			gurps.SkillReferenceColumn,
+		)
+	case []*gurps.Spell:
+		return newTable(i18n.Text("Spells"), data, gurps.SpellsHeaderData,
+			gurps.SpellDescriptionColumn,
+			gurps.SpellResistColumn,
+			gurps.SpellClassColumn,
+			gurps.SpellCollegeColumn,
+			gurps.SpellCastCostColumn,
+			gurps.SpellMaintainCostColumn,
+			gurps.SpellCastTimeColumn,
+			gurps.SpellDurationColumn,
+			gurps.SpellDifficultyColumn,
+			gurps.SpellLevelColumn,
+			gurps.SpellRelativeLevelColumn,
+			gurps.SpellPointsColumn,
+			gurps.SpellTagsColumn,
+			gurps.SpellReferenceColumn,
+		)
+	case []*gurps.Equipment:
+		provider := &equipmentListOwner{DataOwnerProvider: owner}
+		return newTable(i18n.Text("Equipment"), data,
+			func(columnID int) gurps.HeaderData {
+				return gurps.EquipmentHeaderData(columnID, provider, false, false)
+			},
+			gurps.EquipmentEquippedColumn,
+			gurps.EquipmentQuantityColumn,
+			gurps.EquipmentDescriptionColumn,
+			gurps.EquipmentTLColumn,
+			gurps.EquipmentLCColumn,
+			gurps.EquipmentCostColumn,
+			gurps.EquipmentWeightColumn,
+			gurps.EquipmentExtendedCostColumn,
+			gurps.EquipmentExtendedWeightColumn,
+			gurps.EquipmentTagsColumn,
+			gurps.EquipmentReferenceColumn,
+		)
+	default:
+		return nil
+	}
+}
+
+func newTable[T gurps.NodeTypes](name string, list []T, headerData func(columnID int) gurps.HeaderData, columnIDs ...int) *Table {
+	t := &Table{Name: name}
+	for _, id := range columnIDs {
+		t.Headers = append(t.Headers, tableHeaderTitle(headerData(id)))
+	}
+	t.Headers = append(t.Headers, i18n.Text("Notes"))
+	gurps.Traverse(func(one T) bool {
+		node := gurps.AsNode(one)
+		row := make([]string, 0, len(t.Headers))
+		var notes string
+		for i, id := range columnIDs {
+			var data gurps.CellData
+			node.CellData(id, &data)
+			switch data.Type {
+			case cell.Toggle:
+				if data.Checked {
+					row = append(row, "✓")
+				} else {
+					row = append(row, "")
+				}
+			case cell.Text, cell.Tags, cell.PageRef, cell.Markdown:
+				row = append(row, data.Primary)
+				if i == 0 && data.Type == cell.Text {
+					notes = data.Secondary
+				}
+			default:
+				row = append(row, "")
+			}
+		}
+		t.Rows = append(t.Rows, append(row, notes))
+		return false
+	}, false, false, list...)
+	return t
+}
+
+func tableHeaderTitle(data gurps.HeaderData) string {
+	if !data.TitleIsImageKey {
+		return data.Title
+	}
+	switch data.Title {
+	case gurps.HeaderCheckmark:
+		return i18n.Text("Equipped")
+	case gurps.HeaderCoins:
+		return i18n.Text("Value")
+	case gurps.HeaderWeight:
+		return i18n.Text("Weight")
+	case gurps.HeaderStackedCoins:
+		return i18n.Text("Extended Value")
+	case gurps.HeaderStackedWeight:
+		return i18n.Text("Extended Weight")
+	case gurps.HeaderBookmark:
+		return i18n.Text("Reference")
+	default:
+		return data.Detail
+	}
+}
+
+// ToCSV exports the table to path as comma-separated values.
+func ToCSV(t *Table, path string) error {
+	return writeFile(path, func(w io.Writer) error { return WriteCSV(w, t) })
+}
+
+// WriteCSV writes the table to w as comma-separated values, with the headers as the first record.
+func WriteCSV(w io.Writer, t *Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Headers); err != nil {
+		return errs.Wrap(err)
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+// ToXLSX exports the table to path as an Office Open XML workbook.
+func ToXLSX(t *Table, path string) error {
+	return writeFile(path, func(w io.Writer) error { return WriteXLSX(w, t) })
+}
+
+// WriteXLSX writes the table to w as an Office Open XML workbook with a single worksheet. Cells whose text is a plain
+// number are stored as numbers so that they can be used in formulas.
+func WriteXLSX(w io.Writer, t *Table) error {
+	z := zip.NewWriter(w)
+	name := t.Name
+	if name == "" {
+		name = "Sheet1"
+	}
+	for _, part := range []struct {
+		path    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xlsxEscape(xlsxSheetName(name)))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/worksheets/sheet1.xml", xlsxWorksheet(t)},
+	} {
+		f, err := z.Create(part.path)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		if _, err = io.WriteString(f, part.content); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	return errs.Wrap(z.Close())
+}
+
+func xlsxWorksheet(t *Table) string {
+	var buffer strings.Builder
+	buffer.WriteString(xml.Header)
+	buffer.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	xlsxRow(&buffer, 1, t.Headers)
+	for i, row := range t.Rows {
+		xlsxRow(&buffer, i+2, row)
+	}
+	buffer.WriteString(`</sheetData></worksheet>`)
+	return buffer.String()
+}
+
+func xlsxRow(buffer *strings.Builder, rowNum int, cells []string) {
+	fmt.Fprintf(buffer, `<row r="%d">`, rowNum)
+	for i, text := range cells {
+		if text == "" {
+			continue
+		}
+		ref := xlsxColumnName(i) + strconv.Itoa(rowNum)
+		if v, ok := xlsxNumber(text); ok {
+			fmt.Fprintf(buffer, `<c r="%s"><v>%s</v></c>`, ref, v)
+		} else {
+			fmt.Fprintf(buffer, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref,
+				xlsxEscape(text))
+		}
+	}
+	buffer.WriteString(`</row>`)
+}
+
+// xlsxNumber returns the text as a spreadsheet number, if it is a plain number, possibly with thousands separators.
+func xlsxNumber(text string) (string, bool) {
+	text = strings.ReplaceAll(text, ",", "")
+	if strings.Trim(text, "+-.0123456789") != "" {
+		return "", false
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64), true
+}
+
+// xlsxColumnName returns the spreadsheet column name for the zero-based index, e.g. 0 -> A, 26 -> AA.
+func xlsxColumnName(index int) string {
+	var name []byte
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = append([]byte{byte('A' + (index-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// xlsxSheetName returns a name suitable for use as a worksheet name, which is limited to 31 characters and may not
+// contain some characters.
+func xlsxSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	return name
+}
+
+func xlsxEscape(text string) string {
+	var buffer strings.Builder
+	if err := xml.EscapeText(&buffer, []byte(text)); err != nil {
+		return ""
+	}
+	return buffer.String()
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
@@ -0,0 +1,89 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestXLSXColumnName(t *testing.T) {
+	c := check.New(t)
+	c.Equal("A", xlsxColumnName(0))
+	c.Equal("Z", xlsxColumnName(25))
+	c.Equal("AA", xlsxColumnName(26))
+	c.Equal("AZ", xlsxColumnName(51))
+	c.Equal("BA", xlsxColumnName(52))
+}
+
+func TestXLSXNumber(t *testing.T) {
+	c := check.New(t)
+	v, ok := xlsxNumber("1,250.5")
+	c.True(ok)
+	c.Equal("1250.5", v)
+	_, ok = xlsxNumber("Inf")
+	c.False(ok)
+	_, ok = xlsxNumber("3 lb")
+	c.False(ok)
+}
+
+func TestEquipmentTable(t *testing.T) {
+	c := check.New(t)
+	entity := gurps.NewEntity()
+	backpack := gurps.NewEquipment(entity, nil, true)
+	backpack.Name = "Backpack"
+	backpack.BaseValue = "60"
+	rope := gurps.NewEquipment(entity, backpack, false)
+	rope.Name = "Rope"
+	rope.Quantity = fxp.FromInteger(2)
+	rope.BaseValue = "5"
+	backpack.Children = []*gurps.Equipment{rope}
+	entity.SetCarriedEquipmentList([]*gurps.Equipment{backpack})
+
+	c.True(SupportsTable[*gurps.Equipment]())
+	c.False(SupportsTable[*gurps.Note]())
+	table := TableFor(entity, entity.CarriedEquipment)
+	c.NotNil(table)
+	c.Equal(2, len(table.Rows))
+	c.Contains(strings.Join(table.Headers, ","), "Extended Value")
+
+	var buffer bytes.Buffer
+	c.NoError(WriteCSV(&buffer, table))
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	c.Equal(3, len(lines))
+	c.Contains(lines[1], "Backpack")
+	c.Contains(lines[1], ",70,")
+	c.Contains(lines[2], "Rope")
+
+	buffer.Reset()
+	c.NoError(WriteXLSX(&buffer, table))
+	r, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	c.NoError(err)
+	var sheet string
+	for _, f := range r.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, openErr := f.Open()
+			c.NoError(openErr)
+			data, readErr := io.ReadAll(rc)
+			c.NoError(readErr)
+			c.NoError(rc.Close())
+			sheet = string(data)
+		}
+	}
+	c.Contains(sheet, `<t xml:space="preserve">Backpack</t>`)
+	c.Contains(sheet, `<v>70</v>`)
+}
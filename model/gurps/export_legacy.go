@@ -270,7 +270,7 @@ func (ex *legacyExporter) emitKey(key string) error {
 		}
 		ex.writeEncodedText(strconv.Itoa(dr))
 	case "CURRENT_DODGE":
-		ex.writeEncodedText(strconv.Itoa(ex.entity.Dodge(ex.entity.EncumbranceLevel(false))))
+		ex.writeEncodedText(ex.entity.DodgeText(ex.entity.EncumbranceLevel(false)))
 	case "CURRENT_MOVE":
 		ex.writeEncodedText(strconv.Itoa(ex.entity.Move(ex.entity.EncumbranceLevel(false))))
 	case "BEST_CURRENT_PARRY":
@@ -715,7 +715,7 @@ func (ex *legacyExporter) processEncumbranceLoop(buffer []byte) {
 			case "MOVE":
 				ex.writeEncodedText(strconv.Itoa(ex.entity.Move(enc)))
 			case "DODGE":
-				ex.writeEncodedText(strconv.Itoa(ex.entity.Dodge(enc)))
+				ex.writeEncodedText(ex.entity.DodgeText(enc))
 			default:
 				ex.unidentifiedKey(key)
 			}
@@ -742,6 +742,8 @@ func (ex *legacyExporter) processHitLocationLoop(buffer []byte) {
 				var tooltip xbytes.InsertBuffer
 				location.DisplayDR(ex.entity, &tooltip)
 				ex.writeEncodedText(tooltip.String())
+			case "ARMOR":
+				ex.writeEncodedText(ArmorLayersSummary(ex.entity.ArmorLayersFor(location.LocID)))
 			case "EQUIPMENT":
 				ex.writeEncodedText(strings.Join(ex.hitLocationEquipment(location), ", "))
 			case "EQUIPMENT_FORMATTED":
@@ -42,6 +42,10 @@ func TestTemplateFuncs(t *testing.T) {
 		{in: `{{.One.Add .OnePointOne}}`, out: "2.1"},
 		{in: `{{.One.Sub .OnePointOne}}`, out: "-0.1"},
 		{in: `{{(numberFrom 22).Add (numberFrom 44.4)}}`, out: "66.4"},
+		{
+			in:  `{{range pageRefLinks "B12, [SRD](https://example.com/srd)"}}{{.Title}}={{.URL}}{{end}}`,
+			out: "SRD=https://example.com/srd",
+		},
 	} {
 		tmpl, err := tmplBase.Parse(data.in)
 		c.NoError(err, "Test %d", i)
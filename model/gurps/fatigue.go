@@ -0,0 +1,253 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/casting"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xhash"
+	"github.com/zeebo/xxh3"
+)
+
+var _ Hashable = &FatigueExpenditure{}
+
+// Possible values for FatigueExpenditure.Kind.
+const (
+	SprintingFatigue    = "sprinting"
+	ExtraEffortFatigue  = "extra_effort"
+	SpellcastingFatigue = "spellcasting"
+	OtherFatigue        = "other"
+)
+
+// DefaultMagicThreshold is the threshold used for threshold-limited magic when no other has been set, per p. T77.
+const DefaultMagicThreshold = 30
+
+// ExtraEffortFeats holds the names of the uses of extra effort from pp. B356-357, each of which costs 1 FP.
+var ExtraEffortFeats = []string{
+	"Feat of Strength",
+	"Feverish Defense",
+	"Flurry of Blows",
+	"Giant Step",
+	"Heroic Charge",
+	"Mighty Blows",
+	"Rapid Strike",
+}
+
+// FatigueExpenditure holds information about a single expenditure of fatigue points.
+type FatigueExpenditure struct {
+	When   jio.Time `json:"when"`
+	Kind   string   `json:"kind"`
+	Notes  string   `json:"notes,omitzero"`
+	Amount int      `json:"amount"`
+	// Tally is true if the amount was added to the threshold tally rather than deducted from FP.
+	Tally bool `json:"tally,omitzero"`
+}
+
+// CloneFatigueLog creates a clone of the provided FatigueExpenditure list.
+func CloneFatigueLog(list []*FatigueExpenditure) []*FatigueExpenditure {
+	clone := make([]*FatigueExpenditure, len(list))
+	for i := range list {
+		one := *list[i]
+		clone[i] = &one
+	}
+	return clone
+}
+
+// HashFatigueLog returns a hash value for the provided FatigueExpenditure list.
+func HashFatigueLog(list []*FatigueExpenditure) uint64 {
+	h := xxh3.New()
+	xhash.Num64(h, len(list))
+	for _, one := range list {
+		one.Hash(h)
+	}
+	return h.Sum64()
+}
+
+// Hash writes this object's contents into the hasher.
+func (f *FatigueExpenditure) Hash(h hash.Hash) {
+	xhash.Num64(h, time.Time(f.When).UnixNano())
+	xhash.StringWithLen(h, f.Kind)
+	xhash.StringWithLen(h, f.Notes)
+	xhash.Num64(h, f.Amount)
+	xhash.Bool(h, f.Tally)
+}
+
+// FatigueKindName returns the localized name of a fatigue expenditure kind.
+func FatigueKindName(kind string) string {
+	switch kind {
+	case SprintingFatigue:
+		return i18n.Text("Sprinting")
+	case ExtraEffortFatigue:
+		return i18n.Text("Extra Effort")
+	case SpellcastingFatigue:
+		return i18n.Text("Spellcasting")
+	default:
+		return i18n.Text("Other")
+	}
+}
+
+// SprintingFatigueCost returns the FP cost of sprinting for the given number of seconds: 1 FP for every 15 seconds or
+// part thereof, per p. B354.
+func SprintingFatigueCost(seconds int) int {
+	if seconds <= 0 {
+		return 0
+	}
+	return (seconds + 14) / 15
+}
+
+// SpellEnergyReduction returns the reduction in energy cost to cast a spell known at the given level, per p. B237.
+func SpellEnergyReduction(level fxp.Int) int {
+	lvl := fxp.AsInteger[int](level.Floor())
+	if lvl < 15 {
+		return 0
+	}
+	return 1 + (lvl-15)/5
+}
+
+// CastingFatigueCost returns the FP needed to cast this spell, after the reduction for high skill has been applied.
+// Returns false if the casting cost does not start with a fixed number, such as when it varies. Returns zero if the
+// sheet's spells cost no fatigue.
+func (s *Spell) CastingFatigueCost() (cost int, ok bool) {
+	base, ok := leadingEnergyCost(s.CastingCostWithReplacements())
+	if !ok {
+		return 0, false
+	}
+	if entity := EntityFromNode(s); entity != nil && entity.SheetSettings.CastingMode == casting.NoFatigue {
+		return 0, true
+	}
+	return max(base-SpellEnergyReduction(s.LevelData.Level), 0), true
+}
+
+// CastingCostForDisplay returns the casting cost adjusted for the sheet's casting mode.
+func (s *Spell) CastingCostForDisplay() string {
+	return energyCostForDisplay(EntityFromNode(s), s.CastingCostWithReplacements())
+}
+
+// MaintenanceCostForDisplay returns the maintenance cost adjusted for the sheet's casting mode.
+func (s *Spell) MaintenanceCostForDisplay() string {
+	return energyCostForDisplay(EntityFromNode(s), s.MaintenanceCostWithReplacements())
+}
+
+// CastingModeTooltip returns a tooltip explaining how the spell's energy costs are paid for, or an empty string if they
+// are paid for with FP as usual.
+func (s *Spell) CastingModeTooltip() string {
+	if entity := EntityFromNode(s); entity != nil {
+		switch entity.SheetSettings.CastingMode {
+		case casting.Threshold:
+			return fmt.Sprintf(i18n.Text("Energy costs are added to the threshold tally (currently %d of %d)"),
+				entity.ThresholdTally(), entity.SheetSettings.MagicThreshold)
+		case casting.NoFatigue:
+			return i18n.Text("Spells cost no energy to cast or maintain")
+		default:
+		}
+	}
+	return ""
+}
+
+func energyCostForDisplay(entity *Entity, text string) string {
+	if entity != nil && entity.SheetSettings.CastingMode == casting.NoFatigue {
+		if _, ok := leadingEnergyCost(text); ok {
+			return "0"
+		}
+	}
+	return text
+}
+
+func leadingEnergyCost(text string) (int, bool) {
+	text = strings.TrimSpace(text)
+	i := 0
+	for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+		i++
+	}
+	cost, err := strconv.Atoi(text[:i])
+	if err != nil {
+		return 0, false
+	}
+	return cost, true
+}
+
+// FatiguePoints returns the entity's fatigue point pool, if it has one.
+func (e *Entity) FatiguePoints() *Attribute {
+	if e.Attributes == nil {
+		return nil
+	}
+	if attr, ok := e.Attributes.Set[fpAttrID]; ok {
+		return attr
+	}
+	return nil
+}
+
+// SpendFatigue records an expenditure of fatigue points and deducts it from the entity's FP pool. If the expenditure
+// moved the pool into a different threshold, the new threshold is returned. When the sheet uses threshold-limited magic,
+// spellcasting is added to the threshold tally instead and FP are left alone.
+func (e *Entity) SpendFatigue(kind, notes string, amount int) *PoolThreshold {
+	if amount <= 0 {
+		return nil
+	}
+	tally := kind == SpellcastingFatigue && e.SheetSettings.CastingMode == casting.Threshold
+	e.FatigueLog = append(e.FatigueLog, &FatigueExpenditure{
+		When:   jio.Now(),
+		Kind:   kind,
+		Notes:  notes,
+		Amount: amount,
+		Tally:  tally,
+	})
+	fp := e.FatiguePoints()
+	if fp == nil || tally {
+		return nil
+	}
+	before := fp.CurrentThreshold()
+	fp.Damage += fxp.FromInteger(amount)
+	if after := fp.CurrentThreshold(); after != nil && after != before {
+		return after
+	}
+	return nil
+}
+
+// SetFatigueLog replaces the entity's fatigue log. Fatigue points are not adjusted.
+func (e *Entity) SetFatigueLog(list []*FatigueExpenditure) {
+	e.FatigueLog = CloneFatigueLog(list)
+}
+
+// TotalFatigueSpent returns the total FP recorded in the fatigue log. Amounts added to the threshold tally are not
+// included.
+func (e *Entity) TotalFatigueSpent() int {
+	total := 0
+	for _, one := range e.FatigueLog {
+		if !one.Tally {
+			total += one.Amount
+		}
+	}
+	return total
+}
+
+// ThresholdTally returns the total energy added to the threshold tally in the fatigue log.
+func (e *Entity) ThresholdTally() int {
+	total := 0
+	for _, one := range e.FatigueLog {
+		if one.Tally {
+			total += one.Amount
+		}
+	}
+	return total
+}
+
+// ThresholdExceeded returns true if the sheet uses threshold-limited magic and the tally exceeds the threshold.
+func (e *Entity) ThresholdExceeded() bool {
+	return e.SheetSettings.CastingMode == casting.Threshold && e.ThresholdTally() > e.SheetSettings.MagicThreshold
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/casting"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestFatigueCosts(t *testing.T) {
+	c := check.New(t)
+	c.Equal(0, SprintingFatigueCost(0))
+	c.Equal(1, SprintingFatigueCost(1))
+	c.Equal(1, SprintingFatigueCost(15))
+	c.Equal(2, SprintingFatigueCost(16))
+	c.Equal(0, SpellEnergyReduction(fxp.FromInteger(14)))
+	c.Equal(1, SpellEnergyReduction(fxp.FromInteger(15)))
+	c.Equal(1, SpellEnergyReduction(fxp.FromInteger(19)))
+	c.Equal(2, SpellEnergyReduction(fxp.FromInteger(20)))
+}
+
+func TestSpendFatigue(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.Recalculate()
+	c.Nil(e.SpendFatigue(ExtraEffortFatigue, "Mighty Blows", 1))
+	c.Equal(fxp.Nine, e.FatiguePoints().Current())
+	threshold := e.SpendFatigue(SprintingFatigue, "", SprintingFatigueCost(90))
+	c.NotNil(threshold)
+	c.Equal("Tired", threshold.State)
+	c.Nil(e.SpendFatigue(OtherFatigue, "", 0))
+	c.Equal(2, len(e.FatigueLog))
+	c.Equal(7, e.TotalFatigueSpent())
+}
+
+func TestCastingModes(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	spell := NewSpell(e, nil, false)
+	spell.CastingCost = "3"
+	spell.MaintenanceCost = "2"
+	e.Spells = []*Spell{spell}
+	e.Recalculate()
+
+	e.SheetSettings.CastingMode = casting.Threshold
+	cost, ok := spell.CastingFatigueCost()
+	c.True(ok)
+	c.Equal(3, cost)
+	c.Nil(e.SpendFatigue(SpellcastingFatigue, spell.Name, cost))
+	c.Equal(fxp.Ten, e.FatiguePoints().Current())
+	c.Equal(0, e.TotalFatigueSpent())
+	c.Equal(3, e.ThresholdTally())
+	c.False(e.ThresholdExceeded())
+	e.SheetSettings.MagicThreshold = 2
+	c.True(e.ThresholdExceeded())
+
+	e.SheetSettings.CastingMode = casting.NoFatigue
+	cost, ok = spell.CastingFatigueCost()
+	c.True(ok)
+	c.Equal(0, cost)
+	c.Equal("0", spell.CastingCostForDisplay())
+	c.Equal("0", spell.MaintenanceCostForDisplay())
+	spell.CastingCost = "Varies"
+	c.Equal("Varies", spell.CastingCostForDisplay())
+}
@@ -37,6 +37,7 @@ const (
 	TemplatesExt          = ".gct"
 	TraitModifiersExt     = ".adm"
 	TraitsExt             = ".adq"
+	VehicleExt            = ".vehicle"
 	MarkdownExt           = ".md"
 )
 
@@ -55,7 +56,9 @@ const (
 	KeySettingsExt     = ".keys"
 	NamesExt           = ".names"
 	PageRefSettingsExt = ".refs"
+	RulesetExt         = ".ruleset"
 	SheetSettingsExt   = ".sheet"
+	SheetThemeExt      = ".theme"
 	WebSettingsExt     = ".web"
 )
 
@@ -151,7 +154,9 @@ func GCSSecondaryExtensions() []string {
 		KeySettingsExt,
 		NamesExt,
 		PageRefSettingsExt,
+		RulesetExt,
 		SheetSettingsExt,
+		SheetThemeExt,
 		WebSettingsExt,
 	}
 }
@@ -0,0 +1,157 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"regexp"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// TextReplacer replaces occurrences of a piece of text, or of a regular expression, with other text.
+type TextReplacer struct {
+	pattern     *regexp.Regexp
+	replacement string
+	useRegex    bool
+}
+
+// NewTextReplacer creates a new TextReplacer. When useRegex is true, find is a regular expression and the replacement
+// may refer to its capture groups using $1, ${name}, etc.
+func NewTextReplacer(find, replacement string, useRegex, caseSensitive bool) (*TextReplacer, error) {
+	if find == "" {
+		return nil, errs.New(i18n.Text("nothing to find"))
+	}
+	expr := find
+	if !useRegex {
+		expr = regexp.QuoteMeta(find)
+	}
+	if !caseSensitive {
+		expr = "(?i)" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &TextReplacer{
+		pattern:     pattern,
+		replacement: replacement,
+		useRegex:    useRegex,
+	}, nil
+}
+
+// Replace returns the text with all matches replaced, along with the number of matches found.
+func (r *TextReplacer) Replace(text string) (result string, count int) {
+	count = len(r.pattern.FindAllStringIndex(text, -1))
+	if count == 0 {
+		return text, 0
+	}
+	if r.useRegex {
+		return r.pattern.ReplaceAllString(text, r.replacement), count
+	}
+	return r.pattern.ReplaceAllLiteralString(text, r.replacement), count
+}
+
+// TextReplacement holds a single field on a sheet that contains the text being searched for.
+type TextReplacement struct {
+	Kind   string
+	Item   string
+	Field  string
+	Before string
+	After  string
+	Count  int
+	target *string
+}
+
+// Apply sets the field to the replaced text.
+func (t *TextReplacement) Apply() {
+	*t.target = t.After
+}
+
+// Revert sets the field back to the original text.
+func (t *TextReplacement) Revert() {
+	*t.target = t.Before
+}
+
+// FindTextReplacements returns the replacements that would be made to the names, notes and user descriptions of the
+// traits, skills, spells, equipment, modifiers and notes of the entity. While the entity is locked, the names and
+// specializations of traits, skills, spells and their modifiers are build data and so are left alone. Nothing is
+// changed until Apply() is called on the results.
+func FindTextReplacements(entity *Entity, replacer *TextReplacer) []*TextReplacement {
+	if entity == nil || replacer == nil {
+		return nil
+	}
+	var list []*TextReplacement
+	check := func(kind, item, field string, target *string) {
+		if after, count := replacer.Replace(*target); count != 0 {
+			list = append(list, &TextReplacement{
+				Kind:   kind,
+				Item:   item,
+				Field:  field,
+				Before: *target,
+				After:  after,
+				Count:  count,
+				target: target,
+			})
+		}
+	}
+	checkBuildData := func(kind, item, field string, target *string) {
+		if !entity.Locked {
+			check(kind, item, field, target)
+		}
+	}
+	nameField := i18n.Text("Name")
+	notesField := i18n.Text("Notes")
+	Traverse(func(t *Trait) bool {
+		kind := i18n.Text("Trait")
+		checkBuildData(kind, t.Name, nameField, &t.Name)
+		check(kind, t.Name, notesField, &t.LocalNotes)
+		check(kind, t.Name, i18n.Text("User Description"), &t.UserDesc)
+		Traverse(func(mod *TraitModifier) bool {
+			kind = i18n.Text("Trait Modifier")
+			checkBuildData(kind, mod.Name, nameField, &mod.Name)
+			check(kind, mod.Name, notesField, &mod.LocalNotes)
+			return false
+		}, false, false, t.Modifiers...)
+		return false
+	}, false, false, entity.Traits...)
+	Traverse(func(s *Skill) bool {
+		kind := i18n.Text("Skill")
+		checkBuildData(kind, s.Name, nameField, &s.Name)
+		checkBuildData(kind, s.Name, i18n.Text("Specialization"), &s.Specialization)
+		check(kind, s.Name, notesField, &s.LocalNotes)
+		return false
+	}, false, false, entity.Skills...)
+	Traverse(func(s *Spell) bool {
+		kind := i18n.Text("Spell")
+		checkBuildData(kind, s.Name, nameField, &s.Name)
+		check(kind, s.Name, notesField, &s.LocalNotes)
+		return false
+	}, false, false, entity.Spells...)
+	for _, equipment := range [][]*Equipment{entity.CarriedEquipment, entity.OtherEquipment} {
+		Traverse(func(e *Equipment) bool {
+			kind := i18n.Text("Equipment")
+			check(kind, e.Name, nameField, &e.Name)
+			check(kind, e.Name, notesField, &e.LocalNotes)
+			Traverse(func(mod *EquipmentModifier) bool {
+				kind = i18n.Text("Equipment Modifier")
+				check(kind, mod.Name, nameField, &mod.Name)
+				check(kind, mod.Name, notesField, &mod.LocalNotes)
+				return false
+			}, false, false, e.Modifiers...)
+			return false
+		}, false, false, equipment...)
+	}
+	Traverse(func(n *Note) bool {
+		check(i18n.Text("Note"), n.String(), i18n.Text("Text"), &n.MarkDown)
+		return false
+	}, false, false, entity.Notes...)
+	return list
+}
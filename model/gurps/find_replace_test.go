@@ -0,0 +1,83 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestTextReplacer(t *testing.T) {
+	c := check.New(t)
+	_, err := NewTextReplacer("", "x", false, false)
+	c.HasError(err)
+	_, err = NewTextReplacer("(", "x", true, false)
+	c.HasError(err)
+
+	r, err := NewTextReplacer("a.b", "$1", false, true)
+	c.NoError(err)
+	result, count := r.Replace("a.b axb A.B")
+	c.Equal("$1 axb A.B", result)
+	c.Equal(1, count)
+
+	r, err = NewTextReplacer("a.b", "-", false, false)
+	c.NoError(err)
+	result, count = r.Replace("a.b axb A.B")
+	c.Equal("- axb -", result)
+	c.Equal(2, count)
+
+	r, err = NewTextReplacer(`(\w+) of (\w+)`, "$2's $1", true, true)
+	c.NoError(err)
+	result, count = r.Replace("Temple of Mitra")
+	c.Equal("Mitra's Temple", result)
+	c.Equal(1, count)
+}
+
+func TestFindTextReplacements(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Clerical Investment (Mitra)"
+	trait.UserDesc = "Ordained by the priests of Mitra"
+	mod := NewTraitModifier(e, nil, false)
+	mod.Name = "Blessed"
+	trait.Modifiers = []*TraitModifier{mod}
+	e.Traits = []*Trait{trait}
+	skill := NewSkill(e, nil, false)
+	skill.Name = "Theology"
+	skill.Specialization = "Mitra"
+	e.Skills = []*Skill{skill}
+	note := NewNote(e, nil, false)
+	note.MarkDown = "The cult of mitra is outlawed."
+	e.Notes = []*Note{note}
+
+	r, err := NewTextReplacer("Mitra", "Ishtar", false, true)
+	c.NoError(err)
+	list := FindTextReplacements(e, r)
+	c.Equal(3, len(list))
+	c.Equal("Mitra", skill.Specialization)
+	for _, one := range list {
+		one.Apply()
+	}
+	c.Equal("Clerical Investment (Ishtar)", trait.Name)
+	c.Equal("Ordained by the priests of Ishtar", trait.UserDesc)
+	c.Equal("Ishtar", skill.Specialization)
+	c.Equal("The cult of mitra is outlawed.", note.MarkDown)
+	for _, one := range list {
+		one.Revert()
+	}
+	c.Equal("Clerical Investment (Mitra)", trait.Name)
+	c.Equal("Mitra", skill.Specialization)
+
+	r, err = NewTextReplacer("mitra", "Ishtar", false, false)
+	c.NoError(err)
+	c.Equal(4, len(FindTextReplacements(e, r)))
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"unicode"
+)
+
+// FuzzyMatch reports whether all of the characters in pattern appear in text in the same order, ignoring case and
+// spaces in the pattern, along with a score for how good the match is. Higher scores are better: characters that
+// immediately follow the previous match or that start a word score the most. An empty pattern matches everything with a
+// score of 0.
+func FuzzyMatch(pattern, text string) (score int, matched bool) {
+	target := []rune(text)
+	pos := 0
+	last := -2
+	for _, ch := range pattern {
+		if unicode.IsSpace(ch) {
+			continue
+		}
+		ch = unicode.ToLower(ch)
+		for pos < len(target) && unicode.ToLower(target[pos]) != ch {
+			pos++
+		}
+		if pos == len(target) {
+			return 0, false
+		}
+		score++
+		switch {
+		case pos == last+1:
+			score += 5
+		case pos == 0 || !unicode.IsLetter(target[pos-1]) && !unicode.IsDigit(target[pos-1]):
+			score += 3
+		}
+		last = pos
+		pos++
+	}
+	return score, true
+}
@@ -60,16 +60,29 @@ const (
 	AutoColWidthMin            = 50
 	AutoColWidthMax            = 9999
 	MaximumAutoColWidthDef     = 800
+	StatblockSkillThresholdMin = 1
+	StatblockSkillThresholdMax = 99
+	StatblockSkillThresholdDef = 12
 )
 
 const currentGeneralSettingsVersion = 1
 
+// DefaultTokenRingColor is the color used for the ring around exported tokens when none has been chosen.
+var DefaultTokenRingColor = unison.RGB(64, 64, 64)
+
 // GeneralSettings holds general settings for a sheet.
 type GeneralSettings struct {
 	DefaultPlayerName           string           `json:"default_player_name,omitzero"`
 	DefaultTechLevel            string           `json:"default_tech_level,omitzero"`
 	CalendarName                string           `json:"calendar_ref,omitzero"`
 	ExternalPDFCmdLine          string           `json:"external_pdf_cmd_line,omitzero"`
+	DiscordWebhookURL           string           `json:"discord_webhook_url,omitzero"`
+	StatblockTemplate           string           `json:"statblock_template,omitzero"`
+	APIServerToken              string           `json:"api_server_token,omitzero"`
+	ExportTemplatesDir          string           `json:"export_templates_dir,omitzero"`
+	PluginsDir                  string           `json:"plugins_dir,omitzero"`
+	SessionAddress              string           `json:"session_address,omitzero"`
+	SessionPlayerName           string           `json:"session_player_name,omitzero"`
 	InitialPoints               fxp.Int          `json:"initial_points"`
 	TooltipDelay                fxp.Int          `json:"tooltip_delay"`
 	TooltipDismissal            fxp.Int          `json:"tooltip_dismissal"`
@@ -86,12 +99,21 @@ type GeneralSettings struct {
 	MaximumAutoColWidth         int              `json:"maximum_auto_col_width"`
 	ImageResolution             int              `json:"image_resolution"`
 	MonitorResolution           int              `json:"monitor_resolution,omitzero"`
+	StatblockSkillThreshold     int              `json:"statblock_skill_threshold,omitzero"`
+	APIServerPort               int              `json:"api_server_port,omitzero"`
+	SessionPort                 int              `json:"session_port,omitzero"`
 	PDFAutoScaling              autoscale.Option `json:"pdf_auto_scaling,omitzero"`
+	TokenRingColor              unison.Color     `json:"token_ring_color,omitzero"`
 	AutoFillProfile             bool             `json:"auto_fill_profile"`
 	AutoAddNaturalAttacks       bool             `json:"add_natural_attacks"`
 	GroupContainersOnSort       bool             `json:"group_containers_on_sort"`
 	InitialFieldClickSelectsAll bool             `json:"initial_field_click_selects_all"`
 	RestoreWorkspaceOnStart     bool             `json:"restore_workspace_on_start"`
+	SnapshotOnSave              bool             `json:"snapshot_on_save,omitzero"`
+	FillablePDFExport           bool             `json:"fillable_pdf_export,omitzero"`
+	DiscordAnnounceRolls        bool             `json:"discord_announce_rolls,omitzero"`
+	DiscordAnnounceThresholds   bool             `json:"discord_announce_thresholds,omitzero"`
+	APIServerEnabled            bool             `json:"api_server_enabled,omitzero"`
 }
 
 // NewGeneralSettings creates settings with factory defaults.
@@ -114,7 +136,9 @@ func NewGeneralSettings() *GeneralSettings {
 		InitialImageUIScale:        InitialImageUIScaleDef,
 		MaximumAutoColWidth:        MaximumAutoColWidthDef,
 		ImageResolution:            ImageResolutionDef,
+		StatblockSkillThreshold:    StatblockSkillThresholdDef,
 		PDFAutoScaling:             InitialPDFAutoScaling,
+		TokenRingColor:             DefaultTokenRingColor,
 		AutoFillProfile:            true,
 		AutoAddNaturalAttacks:      true,
 		RestoreWorkspaceOnStart:    true,
@@ -217,6 +241,11 @@ func (s *GeneralSettings) EnsureValidity() {
 		InitialImageUIScaleDef)
 	s.MaximumAutoColWidth = fxp.ResetIfOutOfRange(s.MaximumAutoColWidth, AutoColWidthMin, AutoColWidthMax,
 		MaximumAutoColWidthDef)
+	s.StatblockSkillThreshold = fxp.ResetIfOutOfRange(s.StatblockSkillThreshold, StatblockSkillThresholdMin,
+		StatblockSkillThresholdMax, StatblockSkillThresholdDef)
 	s.PDFAutoScaling = s.PDFAutoScaling.EnsureValid()
+	if s.TokenRingColor == 0 {
+		s.TokenRingColor = DefaultTokenRingColor
+	}
 	s.UpdateToolTipTiming()
 }
@@ -0,0 +1,239 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/picker"
+	"github.com/richardwilkes/toolbox/v2/xrand"
+)
+
+// GeneratorRules holds the randomization rules used when generating a character from a template.
+type GeneratorRules struct {
+	// Disadvantages holds the traits that random disadvantages are chosen from. Only those with a negative point cost
+	// are considered.
+	Disadvantages []*Trait
+	// DisadvantagesFrom identifies the library file the disadvantages were loaded from, if any.
+	DisadvantagesFrom LibraryFile
+	// AttributePoints is the number of points to spread randomly across the primary attributes.
+	AttributePoints fxp.Int
+	// DisadvantagePoints is the maximum number of points worth of disadvantages to add.
+	DisadvantagePoints fxp.Int
+	// RandomizeProfile causes the name and other profile fields to be filled in using the ancestry's randomizers.
+	RandomizeProfile bool
+}
+
+type generatorItem[T any] interface {
+	NodeTypes
+	Clone(from LibraryFile, owner DataOwner, parent T, preserveID bool) T
+}
+
+type pointAllocatable interface {
+	RawPoints() fxp.Int
+	SetRawPoints(points fxp.Int) bool
+}
+
+// GenerateCharacter creates a new character from the template, making any choices the template asks for at random and
+// then applying the rules.
+func GenerateCharacter(tmpl *Template, rules *GeneratorRules, rnd xrand.Randomizer) *Entity {
+	e := NewEntity()
+	if tmpl.BodyType != nil {
+		e.SheetSettings.BodyType = tmpl.BodyType.Clone(e, nil)
+	}
+	e.SetTraitList(resolvePickersRandomly(rnd, cloneForGenerator(e, tmpl.Traits)))
+	e.SetSkillList(resolvePickersRandomly(rnd, cloneForGenerator(e, tmpl.Skills)))
+	e.SetSpellList(resolvePickersRandomly(rnd, cloneForGenerator(e, tmpl.Spells)))
+	e.SetCarriedEquipmentList(cloneForGenerator(e, tmpl.Equipment))
+	e.SetNoteList(cloneForGenerator(e, tmpl.Notes))
+	addRandomDisadvantages(e, rules, rnd)
+	e.Recalculate()
+	spreadAttributePoints(e, rules.AttributePoints, rnd)
+	e.Recalculate()
+	if rules.RandomizeProfile {
+		e.Profile.ApplyRandomizers(e)
+	}
+	return e
+}
+
+func cloneForGenerator[T generatorItem[T]](e *Entity, list []T) []T {
+	result := make([]T, 0, len(list))
+	var parent T
+	for _, one := range list {
+		result = append(result, one.Clone(LibraryFile{}, e, parent, false))
+	}
+	return result
+}
+
+func resolvePickersRandomly[T NodeTypes](rnd xrand.Randomizer, list []T) []T {
+	result := make([]T, 0, len(list))
+	for _, one := range list {
+		result = append(result, resolvePickerRandomly(rnd, one)...)
+	}
+	return result
+}
+
+func resolvePickerRandomly[T NodeTypes](rnd xrand.Randomizer, row T) []T {
+	n := AsNode(row)
+	if !n.Container() {
+		return []T{row}
+	}
+	children := n.NodeChildren()
+	tpp, ok := n.(TemplatePickerProvider)
+	if !ok || tpp.TemplatePickerData().IsZero() {
+		rowChildren := resolvePickersRandomly(rnd, children)
+		n.SetChildren(rowChildren)
+		setNodeParents(rowChildren, row)
+		return []T{row}
+	}
+	include := randomPickerSelection(rnd, tpp.TemplatePickerData(), children)
+	rowChildren := make([]T, 0, len(children))
+	for i, child := range children {
+		if include[i] {
+			rowChildren = append(rowChildren, resolvePickerRandomly(rnd, child)...)
+		}
+	}
+	setNodeParents(rowChildren, n.Parent())
+	return rowChildren
+}
+
+func setNodeParents[T NodeTypes](list []T, parent T) {
+	for _, one := range list {
+		AsNode(one).SetParent(parent)
+	}
+}
+
+// randomPickerSelection returns which of the children should be included to satisfy the template picker.
+func randomPickerSelection[T NodeTypes](rnd xrand.Randomizer, tp *TemplatePicker, children []T) []bool {
+	include := make([]bool, len(children))
+	order := randomPermutation(rnd, len(children))
+	switch tp.Type {
+	case picker.Count:
+		var valid []int
+		for count := 0; count <= len(children); count++ {
+			if tp.Qualifier.Matches(fxp.FromInteger(count)) {
+				valid = append(valid, count)
+			}
+		}
+		if len(valid) != 0 {
+			for _, i := range order[:valid[rnd.Intn(len(valid))]] {
+				include[i] = true
+			}
+		}
+	case picker.Points:
+		var total fxp.Int
+		for _, i := range order {
+			if points := TemplatePickerPoints(children[i]); total+points <= tp.Qualifier.Qualifier {
+				include[i] = true
+				total += points
+			}
+		}
+		for _, i := range order {
+			if tp.Qualifier.Matches(total) {
+				break
+			}
+			if !include[i] {
+				include[i] = true
+				total += TemplatePickerPoints(children[i])
+			}
+		}
+	case picker.Allocate:
+		var total fxp.Int
+		var allocatable []pointAllocatable
+		for i, child := range children {
+			include[i] = true
+			total += TemplatePickerPoints(child)
+			if one, ok := any(child).(pointAllocatable); ok && !AsNode(child).Container() {
+				allocatable = append(allocatable, one)
+			}
+		}
+		if len(allocatable) != 0 {
+			for remaining := tp.Qualifier.Qualifier - total; remaining >= fxp.One; remaining -= fxp.One {
+				one := allocatable[rnd.Intn(len(allocatable))]
+				one.SetRawPoints(one.RawPoints() + fxp.One)
+			}
+		}
+	case picker.Optional:
+		if rnd.Intn(2) == 0 {
+			for i := range include {
+				include[i] = true
+			}
+		}
+	default:
+	}
+	return include
+}
+
+func randomPermutation(rnd xrand.Randomizer, n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := rnd.Intn(i + 1)
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+// addRandomDisadvantages adds disadvantages chosen at random from the rules, up to the point budget. Disadvantages
+// whose name matches a trait the character already has are skipped.
+func addRandomDisadvantages(e *Entity, rules *GeneratorRules, rnd xrand.Randomizer) {
+	if rules.DisadvantagePoints <= 0 || len(rules.Disadvantages) == 0 {
+		return
+	}
+	have := make(map[string]bool)
+	Traverse(func(t *Trait) bool {
+		have[t.Name] = true
+		return false
+	}, false, true, e.Traits...)
+	var candidates []*Trait
+	Traverse(func(t *Trait) bool {
+		if t.AdjustedPoints() < 0 {
+			candidates = append(candidates, t)
+		}
+		return false
+	}, true, true, rules.Disadvantages...)
+	traits := e.Traits
+	var spent fxp.Int
+	for _, i := range randomPermutation(rnd, len(candidates)) {
+		t := candidates[i]
+		cost := -t.AdjustedPoints()
+		if have[t.Name] || spent+cost > rules.DisadvantagePoints {
+			continue
+		}
+		have[t.Name] = true
+		spent += cost
+		traits = append(traits, t.Clone(rules.DisadvantagesFrom, e, nil, false))
+	}
+	e.SetTraitList(traits)
+}
+
+// spreadAttributePoints raises the primary attributes at random, one level at a time, until no further level can be
+// bought with the remaining points.
+func spreadAttributePoints(e *Entity, points fxp.Int, rnd xrand.Randomizer) {
+	var attrs []*Attribute
+	for _, attr := range e.Attributes.List() {
+		if def := attr.AttributeDef(); def != nil && !def.IsSeparator() && def.Primary() {
+			attrs = append(attrs, attr)
+		}
+	}
+	for len(attrs) != 0 {
+		i := rnd.Intn(len(attrs))
+		attr := attrs[i]
+		before := attr.PointCost()
+		attr.Adjustment += fxp.One
+		if cost := attr.PointCost() - before; cost > 0 && cost <= points {
+			points -= cost
+		} else {
+			attr.Adjustment -= fxp.One
+			attrs = append(attrs[:i], attrs[i+1:]...)
+		}
+	}
+}
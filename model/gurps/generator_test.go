@@ -0,0 +1,79 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/criteria"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/picker"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+type firstChoiceRandomizer struct{}
+
+func (firstChoiceRandomizer) Intn(_ int) int {
+	return 0
+}
+
+func TestGenerateCharacter(t *testing.T) {
+	c := check.New(t)
+	tmpl := NewTemplate()
+	choices := NewTrait(nil, nil, true)
+	choices.Name = "Pick One"
+	choices.TemplatePicker = &TemplatePicker{
+		Type:      picker.Count,
+		Qualifier: criteria.Number{NumberData: criteria.NumberData{Compare: criteria.EqualsNumber, Qualifier: fxp.One}},
+	}
+	for _, name := range []string{"Luck", "Fit", "Charisma"} {
+		trait := NewTrait(nil, choices, false)
+		trait.Name = name
+		trait.BasePoints = fxp.Five
+		choices.Children = append(choices.Children, trait)
+	}
+	tmpl.Traits = []*Trait{choices}
+
+	allocate := NewSkill(nil, nil, true)
+	allocate.Name = "Allocate"
+	allocate.TemplatePicker = &TemplatePicker{
+		Type:      picker.Allocate,
+		Qualifier: criteria.Number{NumberData: criteria.NumberData{Compare: criteria.EqualsNumber, Qualifier: fxp.Four}},
+	}
+	for _, name := range []string{"Stealth", "Climbing"} {
+		skill := NewSkill(nil, allocate, false)
+		skill.Name = name
+		skill.Points = fxp.One
+		allocate.Children = append(allocate.Children, skill)
+	}
+	tmpl.Skills = []*Skill{allocate}
+
+	bad := NewTrait(nil, nil, false)
+	bad.Name = "Bad Temper"
+	bad.BasePoints = -fxp.Ten
+	worse := NewTrait(nil, nil, false)
+	worse.Name = "Berserk"
+	worse.BasePoints = -fxp.Ten
+	rules := &GeneratorRules{
+		Disadvantages:      []*Trait{bad, worse},
+		AttributePoints:    fxp.FromInteger(25),
+		DisadvantagePoints: fxp.FromInteger(15),
+	}
+
+	e := GenerateCharacter(tmpl, rules, firstChoiceRandomizer{})
+	c.Equal(2, len(e.Traits))
+	c.False(e.Traits[0].Container())
+	c.Equal(fxp.Five, e.Traits[0].AdjustedPoints())
+	c.Equal(-fxp.Ten, e.Traits[1].AdjustedPoints())
+	c.Equal(2, len(e.Skills))
+	c.Equal(fxp.Four, e.Skills[0].RawPoints()+e.Skills[1].RawPoints())
+	spent := e.PointsBreakdown().Attributes
+	c.True(spent > fxp.Ten && spent <= fxp.FromInteger(25))
+}
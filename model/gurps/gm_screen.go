@@ -0,0 +1,90 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/attribute"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// GMScreenValue holds a labeled value shown in a GM screen block.
+type GMScreenValue struct {
+	Label string
+	Value string
+}
+
+// GMScreenBlock holds the condensed details of a character that a GM needs at hand during play.
+type GMScreenBlock struct {
+	Name      string
+	Player    string
+	Pools     []GMScreenValue
+	Defenses  []GMScreenValue
+	Skills    []GMScreenValue
+	Reactions []GMScreenValue
+}
+
+// NewGMScreenBlock creates a GM screen block for the entity, listing up to maxSkills of the skills with the highest
+// levels.
+func NewGMScreenBlock(e *Entity, maxSkills int) *GMScreenBlock {
+	b := &GMScreenBlock{
+		Name:   e.Profile.Name,
+		Player: e.Profile.PlayerName,
+	}
+	for _, def := range SheetSettingsFor(e).Attributes.List(true) {
+		if def.Type != attribute.Pool {
+			continue
+		}
+		if attr, ok := e.Attributes.Set[def.ID()]; ok {
+			b.Pools = append(b.Pools, GMScreenValue{
+				Label: def.Name,
+				Value: fmt.Sprintf(i18n.Text("%s of %s"), attr.Current().String(), attr.Maximum().String()),
+			})
+		}
+	}
+	parry, block := bestActiveDefenses(e)
+	b.Defenses = []GMScreenValue{
+		{Label: i18n.Text("Dodge"), Value: e.DodgeText(e.EncumbranceLevel(true))},
+		{Label: i18n.Text("Parry"), Value: parry},
+		{Label: i18n.Text("Block"), Value: block},
+	}
+	for _, s := range TopSkills(e, maxSkills) {
+		b.Skills = append(b.Skills, GMScreenValue{Label: s.String(), Value: s.LevelData.LevelAsString(false)})
+	}
+	for _, r := range e.Reactions() {
+		b.Reactions = append(b.Reactions, GMScreenValue{Label: r.From, Value: r.Total().StringWithSign()})
+	}
+	return b
+}
+
+// TopSkills returns up to limit of the enabled skills the entity has spent points on, highest level first.
+func TopSkills(e *Entity, limit int) []*Skill {
+	var list []*Skill
+	Traverse(func(s *Skill) bool {
+		if s.Points > 0 {
+			list = append(list, s)
+		}
+		return false
+	}, true, true, e.Skills...)
+	slices.SortStableFunc(list, func(a, b *Skill) int {
+		if result := cmp.Compare(b.LevelData.Level, a.LevelData.Level); result != 0 {
+			return result
+		}
+		return xstrings.NaturalCmp(a.String(), b.String(), true)
+	})
+	if limit >= 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
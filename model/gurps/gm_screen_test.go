@@ -0,0 +1,50 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestGMScreenBlock(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.Profile.Name = "Sir Roland"
+	var skills []*Skill
+	for i, name := range []string{"Stealth", "Broadsword", "Riding", "Cooking"} {
+		skill := NewSkill(e, nil, false)
+		skill.Name = name
+		skill.Points = fxp.FromInteger(1 << i)
+		skills = append(skills, skill)
+	}
+	unlearned := NewSkill(e, nil, false)
+	unlearned.Name = "Swimming"
+	e.SetSkillList(append(skills, unlearned))
+	e.Attributes.Set["hp"].Damage = fxp.Three
+	e.Recalculate()
+
+	b := NewGMScreenBlock(e, 3)
+	c.Equal("Sir Roland", b.Name)
+	c.Equal(3, len(b.Skills))
+	c.Equal("Cooking", b.Skills[0].Label)
+	c.Equal("Riding", b.Skills[1].Label)
+	c.Equal(3, len(b.Defenses))
+	var hp string
+	for _, one := range b.Pools {
+		if one.Label == "HP" {
+			hp = one.Value
+		}
+	}
+	c.Equal("7 of 10", hp)
+	c.Equal(4, len(TopSkills(e, -1)))
+}
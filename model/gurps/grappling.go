@@ -0,0 +1,99 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/rpgtools/dice"
+)
+
+// GrapplingSkillNames holds the names of the skills that contribute to Trained ST under the Technical Grappling rules.
+var GrapplingSkillNames = []string{"Wrestling", "Sumo Wrestling", "Judo"}
+
+// GrappleState holds the Control Points (CP) involved in any grapple the character is currently part of, for use with
+// the Technical Grappling rules.
+type GrappleState struct {
+	Inflicted int `json:"cp_inflicted,omitzero"`
+	Suffered  int `json:"cp_suffered,omitzero"`
+}
+
+// TechnicalGrappling returns true if the Technical Grappling rules are enabled for this entity.
+func (e *Entity) TechnicalGrappling() bool {
+	return e.SheetSettings != nil && e.SheetSettings.TechnicalGrappling
+}
+
+// TrainedST returns the character's Trained ST, which is their ST plus the amount by which their best grappling skill
+// exceeds DX.
+func (e *Entity) TrainedST() fxp.Int {
+	st := e.StrikingStrength()
+	dx := e.ResolveAttributeCurrent(DexterityID)
+	var bonus fxp.Int
+	for _, name := range GrapplingSkillNames {
+		if sk := e.BestSkillNamed(name, "", true, nil); sk != nil {
+			bonus = max(bonus, sk.LevelData.Level-dx)
+		}
+	}
+	return (st + bonus.Floor()).Max(0)
+}
+
+// ControlDamage returns the dice rolled for Control Points inflicted when grappling, which are based on thrust damage
+// for Trained ST.
+func (e *Entity) ControlDamage() *dice.Dice {
+	return e.ThrustFor(fxp.AsInteger[int](e.TrainedST()))
+}
+
+// GrapplePenalty returns the DX penalty from the Control Points the character has suffered: -1 for every full tenth of
+// their ST.
+func (e *Entity) GrapplePenalty() int {
+	if !e.TechnicalGrappling() || e.Grapple.Suffered <= 0 {
+		return 0
+	}
+	per := max(fxp.AsInteger[int](e.ResolveAttributeCurrent(StrengthID).Div(fxp.Ten).Floor()), 1)
+	return -(e.Grapple.Suffered / per)
+}
+
+func (e *Entity) applyGrapplePenalty() {
+	if penalty := e.GrapplePenalty(); penalty != 0 {
+		if attr, ok := e.Attributes.Set[DexterityID]; ok {
+			attr.Bonus += fxp.FromInteger(penalty)
+		}
+	}
+}
+
+// IsGrapple returns true if this is a melee weapon used for grappling, i.e. one that uses a grappling skill or has a
+// usage that mentions grappling.
+func (w *Weapon) IsGrapple() bool {
+	if !w.IsMelee() {
+		return false
+	}
+	if strings.Contains(strings.ToLower(w.UsageWithReplacements()), "grapple") {
+		return true
+	}
+	replacements := w.NameableReplacements()
+	for _, def := range w.Defaults {
+		if slices.Contains(GrapplingSkillNames, def.NameWithReplacements(replacements)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ControlDamage returns the Control Points this weapon inflicts when Technical Grappling is in use, or an empty string
+// if it does not apply.
+func (w *Weapon) ControlDamage() string {
+	entity := w.Entity()
+	if entity == nil || !entity.TechnicalGrappling() || !w.IsGrapple() {
+		return ""
+	}
+	return entity.ControlDamage().String() + " CP"
+}
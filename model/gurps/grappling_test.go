@@ -0,0 +1,35 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestGrapplePenalty(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.Recalculate()
+	dx := e.Attributes.Set[DexterityID].Current()
+	c.Equal(fxp.Ten, e.TrainedST())
+
+	e.Grapple.Suffered = 3
+	e.Recalculate()
+	c.Equal(0, e.GrapplePenalty())
+	c.Equal(dx, e.Attributes.Set[DexterityID].Current())
+
+	e.SheetSettings.TechnicalGrappling = true
+	e.Recalculate()
+	c.Equal(-3, e.GrapplePenalty())
+	c.Equal(dx-fxp.Three, e.Attributes.Set[DexterityID].Current())
+}
@@ -0,0 +1,421 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package importer
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/kinds"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+// FoundryExtension is the file extension used by Foundry VTT actor exports.
+const FoundryExtension = ".json"
+
+// foundryNumber accepts both JSON numbers and strings, since the GURPS Game Aid system is not consistent about which it
+// uses.
+type foundryNumber fxp.Int
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (n *foundryNumber) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	v, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	*n = foundryNumber(parseNumber(strings.Trim(string(v), `"`)))
+	return nil
+}
+
+func (n foundryNumber) fxp() fxp.Int {
+	return fxp.Int(n)
+}
+
+type foundryActor struct {
+	Name   string         `json:"name"`
+	System *foundrySystem `json:"system"`
+	Data   *foundrySystem `json:"data"` // Used by versions of Foundry prior to v10
+	Flags  struct {
+		GCS struct {
+			ID tid.TID `json:"id"`
+		} `json:"gcs"`
+	} `json:"flags"`
+}
+
+type foundrySystem struct {
+	Attributes map[string]*foundryPool     `json:"attributes"`
+	HP         *foundryPool                `json:"HP"`
+	FP         *foundryPool                `json:"FP"`
+	BasicSpeed *foundryPool                `json:"basicspeed"`
+	BasicMove  *foundryPool                `json:"basicmove"`
+	Traits     foundryProfile              `json:"traits"`
+	Ads        map[string]*foundryItem     `json:"ads"`
+	Skills     map[string]*foundryItem     `json:"skills"`
+	Spells     map[string]*foundryItem     `json:"spells"`
+	Equipment  foundryAllEquipment         `json:"equipment"`
+	Notes      map[string]*foundryNoteItem `json:"notes"`
+}
+
+type foundryPool struct {
+	Value foundryNumber `json:"value"`
+	Max   foundryNumber `json:"max"`
+}
+
+type foundryProfile struct {
+	Title        string `json:"title"`
+	Player       string `json:"player"`
+	Organization string `json:"organization"`
+	Religion     string `json:"religion"`
+	TechLevel    string `json:"techlevel"`
+	Age          string `json:"age"`
+	Birthday     string `json:"birthday"`
+	Gender       string `json:"gender"`
+	Height       string `json:"height"`
+	Weight       string `json:"weight"`
+	Eyes         string `json:"eyes"`
+	Hair         string `json:"hair"`
+	Skin         string `json:"skin"`
+	Hand         string `json:"hand"`
+}
+
+type foundryItem struct {
+	Name       string                  `json:"name"`
+	Type       string                  `json:"type"`
+	Difficulty string                  `json:"difficulty"`
+	Points     foundryNumber           `json:"points"`
+	Count      foundryNumber           `json:"count"`
+	Cost       foundryNumber           `json:"cost"`
+	Weight     foundryNumber           `json:"weight"`
+	Uses       foundryNumber           `json:"uses"`
+	Equipped   bool                    `json:"equipped"`
+	Notes      string                  `json:"notes"`
+	PageRef    string                  `json:"pageref"`
+	Contains   map[string]*foundryItem `json:"contains"`
+}
+
+type foundryAllEquipment struct {
+	Carried map[string]*foundryItem `json:"carried"`
+	Other   map[string]*foundryItem `json:"other"`
+}
+
+type foundryNoteItem struct {
+	Notes    string                      `json:"notes"`
+	PageRef  string                      `json:"pageref"`
+	Contains map[string]*foundryNoteItem `json:"contains"`
+}
+
+// foundryList returns the values of the map in key order, which is the order the GURPS Game Aid system uses for
+// display.
+func foundryList[T any](m map[string]*T) []*T {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	list := make([]*T, 0, len(keys))
+	for _, k := range keys {
+		if one := m[k]; one != nil {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+// splitSpecialization splits a trailing parenthetical off of a name, e.g. "Guns (Pistol)".
+func splitSpecialization(name string) (base, specialization string) {
+	name = strings.TrimSpace(name)
+	if strings.HasSuffix(name, ")") {
+		if i := strings.LastIndex(name, " ("); i > 0 {
+			return name[:i], name[i+2 : len(name)-1]
+		}
+	}
+	return name, ""
+}
+
+// splitLevel splits a trailing level off of a name, e.g. "Acute Hearing 2".
+func splitLevel(name string) (base string, level fxp.Int) {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexByte(name, ' '); i > 0 {
+		if v, err := fxp.FromString(name[i+1:]); err == nil {
+			return strings.TrimSpace(name[:i]), v
+		}
+	}
+	return name, 0
+}
+
+// ImportFoundryFile imports a Foundry VTT actor exported from the GURPS Game Aid system.
+func ImportFoundryFile(filePath string, catalog *Catalog) (*Result, error) {
+	return readFile(filePath, func(data []byte) (*Result, error) { return ImportFoundry(data, catalog) })
+}
+
+// ImportFoundry imports the JSON data of a Foundry VTT actor exported from the GURPS Game Aid system. Traits, skills,
+// spells, and equipment are matched by name against the catalog and, when found, the full library definition is used.
+// Items that cannot be matched are created from the data in the file, tagged with ReviewTag, and listed in the result.
+// Current HP, FP, and equipment uses are carried over, so changes made during play are preserved.
+func ImportFoundry(data []byte, catalog *Catalog) (*Result, error) {
+	if catalog == nil {
+		catalog = NewCatalog()
+	}
+	var actor foundryActor
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\ufeff")), &actor); err != nil {
+		return nil, errs.NewWithCause(i18n.Text("invalid Foundry VTT actor file"), err)
+	}
+	sys := actor.System
+	if sys == nil {
+		sys = actor.Data
+	}
+	if sys == nil {
+		return nil, errs.New(i18n.Text("invalid Foundry VTT actor file: no system data"))
+	}
+	entity := gurps.NewEntity()
+	result := &Result{Entity: entity}
+	if tid.IsKindAndValid(actor.Flags.GCS.ID, kinds.Entity) {
+		// Preserve the ID of sheets that were originally exported from GCS, so round-trips remain identifiable.
+		entity.ID = actor.Flags.GCS.ID
+	}
+	p := &entity.Profile
+	p.Name = strings.TrimSpace(actor.Name)
+	p.Title = sys.Traits.Title
+	p.PlayerName = sys.Traits.Player
+	p.Organization = sys.Traits.Organization
+	p.Religion = sys.Traits.Religion
+	p.TechLevel = sys.Traits.TechLevel
+	p.Age = sys.Traits.Age
+	p.Birthday = sys.Traits.Birthday
+	p.Gender = sys.Traits.Gender
+	p.Eyes = sys.Traits.Eyes
+	p.Hair = sys.Traits.Hair
+	p.Skin = sys.Traits.Skin
+	p.Handedness = sys.Traits.Hand
+	if sys.Traits.Height != "" {
+		p.Height = fxp.LengthFromStringForced(sys.Traits.Height, entity.SheetSettings.DefaultLengthUnits)
+	}
+	if sys.Traits.Weight != "" {
+		p.Weight = fxp.WeightFromStringForced(sys.Traits.Weight, entity.SheetSettings.DefaultWeightUnits)
+	}
+
+	entity.SetTraitList(result.foundryTraits(catalog, nil, foundryList(sys.Ads)))
+	entity.SetSkillList(result.foundrySkills(catalog, nil, foundryList(sys.Skills)))
+	entity.SetSpellList(result.foundrySpells(catalog, nil, foundryList(sys.Spells)))
+	entity.SetCarriedEquipmentList(result.foundryEquipment(catalog, nil, foundryList(sys.Equipment.Carried)))
+	entity.SetOtherEquipmentList(result.foundryEquipment(catalog, nil, foundryList(sys.Equipment.Other)))
+	entity.SetNoteList(foundryNotes(entity, nil, foundryList(sys.Notes)))
+
+	// Attributes are set last, since bonuses from the traits affect how the adjustments must be calculated. They are
+	// set in definition order, since the base values of some attributes depend on others.
+	entity.Recalculate()
+	pools := make(map[string]*foundryPool)
+	for k, v := range sys.Attributes {
+		if v != nil {
+			pools[strings.ToLower(k)] = v
+		}
+	}
+	for id, v := range map[string]*foundryPool{
+		"hp":               sys.HP,
+		"fp":               sys.FP,
+		gurps.BasicSpeedID: sys.BasicSpeed,
+		gurps.BasicMoveID:  sys.BasicMove,
+	} {
+		if v != nil {
+			pools[id] = v
+		}
+	}
+	for _, def := range entity.SheetSettings.Attributes.List(true) {
+		pool, ok := pools[def.DefID]
+		if !ok {
+			continue
+		}
+		attr, exists := entity.Attributes.Set[def.DefID]
+		if !exists {
+			continue
+		}
+		maximum := pool.Max.fxp()
+		if !def.Pool() || maximum == 0 {
+			maximum = pool.Value.fxp()
+		}
+		if maximum > 0 {
+			attr.SetMaximum(maximum)
+			entity.Recalculate()
+		}
+		if def.Pool() {
+			if damage := attr.Maximum() - pool.Value.fxp(); damage > 0 {
+				attr.Damage = damage
+			}
+		}
+	}
+	entity.SetUnspentPoints(0)
+	return result, nil
+}
+
+func (r *Result) foundryTraits(catalog *Catalog, parent *gurps.Trait, list []*foundryItem) []*gurps.Trait {
+	traits := make([]*gurps.Trait, 0, len(list))
+	for _, one := range list {
+		var t *gurps.Trait
+		if len(one.Contains) != 0 {
+			t = gurps.NewTrait(r.Entity, parent, true)
+			t.Name = strings.TrimSpace(one.Name)
+			t.Children = r.foundryTraits(catalog, t, foundryList(one.Contains))
+		} else {
+			t = catalog.Trait(r.Entity, one.Name)
+			if t == nil {
+				if name, level := splitLevel(one.Name); level > 0 {
+					if t = catalog.Trait(r.Entity, name); t != nil && t.IsLeveled() {
+						t.Levels = level
+					}
+				}
+			}
+			if t == nil {
+				t = gurps.NewTrait(r.Entity, parent, false)
+				t.Name = strings.TrimSpace(one.Name)
+				t.BasePoints = one.Points.fxp()
+				t.Tags = append(t.Tags, ReviewTag)
+				r.flag(i18n.Text("Trait"), t.Name, i18n.Text("not found in the libraries"))
+			} else {
+				t.SetParent(parent)
+			}
+		}
+		if t.LocalNotes == "" {
+			t.LocalNotes = one.Notes
+		}
+		if t.PageRef == "" {
+			t.PageRef = one.PageRef
+		}
+		traits = append(traits, t)
+	}
+	return traits
+}
+
+func (r *Result) foundrySkills(catalog *Catalog, parent *gurps.Skill, list []*foundryItem) []*gurps.Skill {
+	skills := make([]*gurps.Skill, 0, len(list))
+	for _, one := range list {
+		var s *gurps.Skill
+		if len(one.Contains) != 0 {
+			s = gurps.NewSkill(r.Entity, parent, true)
+			s.Name = strings.TrimSpace(one.Name)
+			s.Children = r.foundrySkills(catalog, s, foundryList(one.Contains))
+		} else {
+			name, spec := splitSpecialization(one.Name)
+			if s = catalog.Skill(r.Entity, name, spec); s == nil {
+				s = catalog.Skill(r.Entity, one.Name, "")
+			}
+			if s == nil {
+				s = gurps.NewSkill(r.Entity, parent, false)
+				s.Name = name
+				s.Specialization = spec
+				if diff, ok := parseDifficulty(one.Type); ok {
+					s.Difficulty = diff
+				}
+				s.Tags = append(s.Tags, ReviewTag)
+				r.flag(i18n.Text("Skill"), one.Name, i18n.Text("not found in the libraries"))
+			} else {
+				s.SetParent(parent)
+			}
+			s.Points = one.Points.fxp()
+		}
+		if s.LocalNotes == "" {
+			s.LocalNotes = one.Notes
+		}
+		if s.PageRef == "" {
+			s.PageRef = one.PageRef
+		}
+		skills = append(skills, s)
+	}
+	return skills
+}
+
+func (r *Result) foundrySpells(catalog *Catalog, parent *gurps.Spell, list []*foundryItem) []*gurps.Spell {
+	spells := make([]*gurps.Spell, 0, len(list))
+	for _, one := range list {
+		var s *gurps.Spell
+		if len(one.Contains) != 0 {
+			s = gurps.NewSpell(r.Entity, parent, true)
+			s.Name = strings.TrimSpace(one.Name)
+			s.Children = r.foundrySpells(catalog, s, foundryList(one.Contains))
+		} else {
+			if s = catalog.Spell(r.Entity, one.Name); s == nil {
+				s = gurps.NewSpell(r.Entity, parent, false)
+				s.Name = strings.TrimSpace(one.Name)
+				if diff, ok := parseDifficulty(one.Difficulty); ok {
+					s.Difficulty = diff
+				}
+				s.Tags = append(s.Tags, ReviewTag)
+				r.flag(i18n.Text("Spell"), s.Name, i18n.Text("not found in the libraries"))
+			} else {
+				s.SetParent(parent)
+			}
+			s.Points = one.Points.fxp()
+		}
+		if s.LocalNotes == "" {
+			s.LocalNotes = one.Notes
+		}
+		if s.PageRef == "" {
+			s.PageRef = one.PageRef
+		}
+		spells = append(spells, s)
+	}
+	return spells
+}
+
+func (r *Result) foundryEquipment(catalog *Catalog, parent *gurps.Equipment, list []*foundryItem) []*gurps.Equipment {
+	units := r.Entity.SheetSettings.DefaultWeightUnits
+	equipment := make([]*gurps.Equipment, 0, len(list))
+	for _, one := range list {
+		e := catalog.Equipment(r.Entity, one.Name)
+		if e == nil {
+			e = gurps.NewEquipment(r.Entity, parent, len(one.Contains) != 0)
+			e.Name = strings.TrimSpace(one.Name)
+			// The cost and weight in the export include the contents of containers, so only the first level is kept
+			// here and the contents are added back as children below.
+			if len(one.Contains) == 0 {
+				e.BaseValue = one.Cost.fxp().String()
+				e.BaseWeight = units.Format(fxp.Weight(one.Weight.fxp()))
+			}
+			e.Tags = append(e.Tags, ReviewTag)
+			r.flag(i18n.Text("Equipment"), e.Name, i18n.Text("not found in the libraries"))
+		} else {
+			e.SetParent(parent)
+		}
+		if len(one.Contains) != 0 {
+			e.Children = r.foundryEquipment(catalog, e, foundryList(one.Contains))
+		}
+		e.Quantity = one.Count.fxp()
+		e.Uses = fxp.AsInteger[int](one.Uses.fxp())
+		e.SetEquipped(one.Equipped)
+		if e.LocalNotes == "" {
+			e.LocalNotes = one.Notes
+		}
+		if e.PageRef == "" {
+			e.PageRef = one.PageRef
+		}
+		equipment = append(equipment, e)
+	}
+	return equipment
+}
+
+func foundryNotes(entity *gurps.Entity, parent *gurps.Note, list []*foundryNoteItem) []*gurps.Note {
+	notes := make([]*gurps.Note, 0, len(list))
+	for _, one := range list {
+		n := gurps.NewNote(entity, parent, len(one.Contains) != 0)
+		n.MarkDown = one.Notes
+		n.PageRef = one.PageRef
+		if len(one.Contains) != 0 {
+			n.Children = foundryNotes(entity, n, foundryList(one.Contains))
+		}
+		notes = append(notes, n)
+	}
+	return notes
+}
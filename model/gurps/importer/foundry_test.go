@@ -0,0 +1,85 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package importer
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+const foundrySample = `{
+  "name": "Dame Round Trip",
+  "type": "character",
+  "system": {
+    "attributes": {
+      "ST": {"import": 13, "value": 13, "points": 30},
+      "IQ": {"import": "11", "value": "11", "points": 20}
+    },
+    "HP": {"value": 9, "max": 13},
+    "FP": {"value": 10, "max": 10},
+    "traits": {"player": "Sam"},
+    "ads": {
+      "00001": {"name": "Odd Habit", "points": -1},
+      "00000": {"name": "Acute Hearing 2", "points": 4}
+    },
+    "skills": {
+      "00000": {"name": "Guns (Pistol)", "type": "DX/E", "points": 2}
+    },
+    "equipment": {
+      "carried": {
+        "00000": {"name": "Canteen", "count": 1, "cost": 10, "weight": 1, "uses": 3, "equipped": true}
+      }
+    }
+  }
+}`
+
+func TestImportFoundry(t *testing.T) {
+	c := check.New(t)
+	catalog := NewCatalog()
+	lib := gurps.NewTrait(nil, nil, false)
+	lib.Name = "Acute Hearing"
+	lib.CanLevel = true
+	lib.PointsPerLevel = fxp.FromInteger(2)
+	catalog.AddTraits(gurps.LibraryFile{}, []*gurps.Trait{lib})
+
+	result, err := ImportFoundry([]byte(foundrySample), catalog)
+	c.NoError(err)
+	entity := result.Entity
+	c.Equal("Dame Round Trip", entity.Profile.Name)
+	c.Equal("Sam", entity.Profile.PlayerName)
+	c.Equal(fxp.FromInteger(13), entity.Attributes.Set["st"].Maximum())
+	c.Equal(fxp.FromInteger(11), entity.Attributes.Set["iq"].Maximum())
+	hp := entity.Attributes.Set["hp"]
+	c.Equal(fxp.FromInteger(13), hp.Maximum())
+	c.Equal(fxp.FromInteger(9), hp.Current())
+
+	c.Equal(2, len(entity.Traits))
+	c.Equal("Acute Hearing", entity.Traits[0].Name)
+	c.Equal(fxp.FromInteger(2), entity.Traits[0].Levels)
+	c.False(gurps.HasTag(ReviewTag, entity.Traits[0].Tags))
+	c.Equal("Odd Habit", entity.Traits[1].Name)
+	c.True(gurps.HasTag(ReviewTag, entity.Traits[1].Tags))
+
+	c.Equal(1, len(entity.Skills))
+	c.Equal("Guns", entity.Skills[0].Name)
+	c.Equal("Pistol", entity.Skills[0].Specialization)
+
+	c.Equal(1, len(entity.CarriedEquipment))
+	c.Equal(3, entity.CarriedEquipment[0].Uses)
+	c.Equal("10", entity.CarriedEquipment[0].BaseValue)
+
+	c.Equal(3, len(result.Unmatched))
+
+	_, err = ImportFoundry([]byte(`{"name": "Nobody"}`), catalog)
+	c.HasError(err)
+}
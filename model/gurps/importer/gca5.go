@@ -0,0 +1,337 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// GCA5Extension is the file extension used by GURPS Character Assistant 5 character files.
+const GCA5Extension = ".gca5"
+
+// gca5AttrIDs maps the names GCA5 uses for the standard attributes to the IDs GCS uses.
+var gca5AttrIDs = map[string]string{
+	"st":             "st",
+	"dx":             "dx",
+	"iq":             "iq",
+	"ht":             "ht",
+	"will":           "will",
+	"perception":     "per",
+	"per":            "per",
+	"hit points":     "hp",
+	"hp":             "hp",
+	"fatigue points": "fp",
+	"fp":             "fp",
+	"basic speed":    "basic_speed",
+	"basic move":     "basic_move",
+}
+
+type gca5File struct {
+	XMLName   xml.Name      `xml:"gca5"`
+	Character gca5Character `xml:"character"`
+}
+
+type gca5Character struct {
+	Name          string     `xml:"name"`
+	Player        string     `xml:"player"`
+	UnspentPoints string     `xml:"unspentpoints"`
+	Vitals        gca5Vitals `xml:"vitals"`
+	Traits        gca5Traits `xml:"traits"`
+}
+
+type gca5Vitals struct {
+	Age    string `xml:"age"`
+	Height string `xml:"height"`
+	Weight string `xml:"weight"`
+}
+
+type gca5Traits struct {
+	Attributes    []*gca5Trait `xml:"attributes>trait"`
+	Cultures      []*gca5Trait `xml:"cultures>trait"`
+	Languages     []*gca5Trait `xml:"languages>trait"`
+	Advantages    []*gca5Trait `xml:"advantages>trait"`
+	Perks         []*gca5Trait `xml:"perks>trait"`
+	Disadvantages []*gca5Trait `xml:"disadvantages>trait"`
+	Quirks        []*gca5Trait `xml:"quirks>trait"`
+	Features      []*gca5Trait `xml:"features>trait"`
+	Skills        []*gca5Trait `xml:"skills>trait"`
+	Spells        []*gca5Trait `xml:"spells>trait"`
+	Equipment     []*gca5Trait `xml:"equipment>trait"`
+}
+
+type gca5Trait struct {
+	Name      string          `xml:"name"`
+	NameExt   string          `xml:"nameext"`
+	Level     string          `xml:"level"`
+	Points    string          `xml:"points"`
+	Score     string          `xml:"score"`
+	Count     string          `xml:"count"`
+	Type      string          `xml:"type"`
+	Calcs     gca5Calcs       `xml:"calcs"`
+	Ref       gca5Ref         `xml:"ref"`
+	Modifiers []*gca5Modifier `xml:"modifiers>modifier"`
+}
+
+type gca5Calcs struct {
+	Points     string `xml:"points"`
+	Score      string `xml:"score"`
+	Type       string `xml:"type"`
+	BaseCost   string `xml:"basecost"`
+	BaseWeight string `xml:"baseweight"`
+}
+
+type gca5Ref struct {
+	Page string `xml:"page"`
+	Type string `xml:"type"`
+}
+
+type gca5Modifier struct {
+	Name    string `xml:"name"`
+	NameExt string `xml:"nameext"`
+	Value   string `xml:"value"`
+}
+
+func (t *gca5Trait) fullName() string {
+	return gca5FullName(t.Name, t.NameExt)
+}
+
+func (t *gca5Trait) points() fxp.Int {
+	return parseNumber(t.Points, t.Calcs.Points)
+}
+
+func (t *gca5Trait) score() fxp.Int {
+	return parseNumber(t.Score, t.Calcs.Score)
+}
+
+// difficulty returns the attribute & difficulty of a skill or spell.
+func (t *gca5Trait) difficulty() (gurps.AttributeDifficulty, bool) {
+	return parseDifficulty(t.Type, t.Calcs.Type, t.Ref.Type)
+}
+
+func gca5FullName(name, ext string) string {
+	name = strings.TrimSpace(name)
+	if ext = strings.TrimSpace(ext); ext != "" {
+		return name + " (" + ext + ")"
+	}
+	return name
+}
+
+// ImportGCA5File imports a GURPS Character Assistant 5 character file.
+func ImportGCA5File(filePath string, catalog *Catalog) (*Result, error) {
+	return readFile(filePath, func(data []byte) (*Result, error) { return ImportGCA5(data, catalog) })
+}
+
+// ImportGCA5 imports the XML data of a GURPS Character Assistant 5 character file. Traits, skills, spells, and
+// equipment are matched by name against the catalog and, when found, the full library definition is used. Items that
+// cannot be matched are created from the data in the file, tagged with ReviewTag, and listed in the result.
+func ImportGCA5(data []byte, catalog *Catalog) (*Result, error) {
+	if catalog == nil {
+		catalog = NewCatalog()
+	}
+	var file gca5File
+	decoder := xml.NewDecoder(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := decoder.Decode(&file); err != nil {
+		return nil, errs.NewWithCause(i18n.Text("invalid GCA5 character file"), err)
+	}
+	ch := &file.Character
+	entity := gurps.NewEntity()
+	result := &Result{Entity: entity}
+	entity.Profile.Name = strings.TrimSpace(ch.Name)
+	entity.Profile.PlayerName = strings.TrimSpace(ch.Player)
+	entity.Profile.Age = strings.TrimSpace(ch.Vitals.Age)
+	if ch.Vitals.Height != "" {
+		entity.Profile.Height = fxp.LengthFromStringForced(ch.Vitals.Height, fxp.FeetAndInches)
+	}
+	if ch.Vitals.Weight != "" {
+		entity.Profile.Weight = fxp.WeightFromStringForced(ch.Vitals.Weight, fxp.Pound)
+	}
+
+	var traits []*gurps.Trait
+	for _, list := range [][]*gca5Trait{
+		ch.Traits.Cultures,
+		ch.Traits.Languages,
+		ch.Traits.Advantages,
+		ch.Traits.Perks,
+		ch.Traits.Disadvantages,
+		ch.Traits.Quirks,
+		ch.Traits.Features,
+	} {
+		for _, one := range list {
+			traits = append(traits, result.trait(catalog, one))
+		}
+	}
+	entity.SetTraitList(traits)
+
+	skills := make([]*gurps.Skill, 0, len(ch.Traits.Skills))
+	for _, one := range ch.Traits.Skills {
+		skills = append(skills, result.skill(catalog, one))
+	}
+	entity.SetSkillList(skills)
+
+	spells := make([]*gurps.Spell, 0, len(ch.Traits.Spells))
+	for _, one := range ch.Traits.Spells {
+		spells = append(spells, result.spell(catalog, one))
+	}
+	entity.SetSpellList(spells)
+
+	equipment := make([]*gurps.Equipment, 0, len(ch.Traits.Equipment))
+	for _, one := range ch.Traits.Equipment {
+		equipment = append(equipment, result.equipment(catalog, one))
+	}
+	entity.SetCarriedEquipmentList(equipment)
+
+	// Attributes are set last, since bonuses from the traits affect how the adjustments must be calculated. They are
+	// set in definition order, since the base values of some attributes depend on others.
+	entity.Recalculate()
+	scores := make(map[string]fxp.Int)
+	for _, one := range ch.Traits.Attributes {
+		if id, ok := gca5AttrIDs[strings.ToLower(strings.TrimSpace(one.Name))]; ok {
+			if score := one.score(); score > 0 {
+				scores[id] = score
+			}
+		}
+	}
+	for _, def := range entity.SheetSettings.Attributes.List(true) {
+		if score, ok := scores[def.DefID]; ok {
+			if attr, exists := entity.Attributes.Set[def.DefID]; exists {
+				attr.SetMaximum(score)
+				entity.Recalculate()
+			}
+		}
+	}
+	entity.SetUnspentPoints(parseNumber(ch.UnspentPoints))
+	return result, nil
+}
+
+func (r *Result) flag(kind, name, reason string) {
+	r.Unmatched = append(r.Unmatched, fmt.Sprintf("%s: %s (%s)", kind, name, reason))
+}
+
+func (r *Result) trait(catalog *Catalog, in *gca5Trait) *gurps.Trait {
+	name := in.fullName()
+	t := catalog.Trait(r.Entity, name)
+	if t == nil {
+		t = catalog.Trait(r.Entity, in.Name)
+	}
+	if t == nil {
+		t = gurps.NewTrait(r.Entity, nil, false)
+		t.Name = name
+		t.BasePoints = in.points()
+		t.PageRef = in.Ref.Page
+		t.Tags = append(t.Tags, ReviewTag)
+		r.flag(i18n.Text("Trait"), name, i18n.Text("not found in the libraries"))
+		// The points recorded by GCA5 already include the effect of any modifiers, so they are added disabled in
+		// order to preserve them for review without altering the cost.
+		for _, m := range in.Modifiers {
+			mod := gurps.NewTraitModifier(r.Entity, nil, false)
+			mod.Name = gca5FullName(m.Name, m.NameExt)
+			mod.CostAdj = strings.TrimSpace(m.Value)
+			mod.Disabled = true
+			t.Modifiers = append(t.Modifiers, mod)
+		}
+		return t
+	}
+	if level := parseNumber(in.Level); level > 0 && t.IsLeveled() {
+		t.Levels = level
+	}
+	applied := make(map[string]*gca5Modifier, len(in.Modifiers))
+	for _, m := range in.Modifiers {
+		applied[catalogKey(gca5FullName(m.Name, m.NameExt), "")] = m
+	}
+	gurps.Traverse(func(mod *gurps.TraitModifier) bool {
+		key := catalogKey(mod.Name, "")
+		if _, ok := applied[key]; ok {
+			mod.Disabled = false
+			delete(applied, key)
+		} else {
+			mod.Disabled = true
+		}
+		return false
+	}, false, true, t.Modifiers...)
+	for _, m := range in.Modifiers {
+		if _, ok := applied[catalogKey(gca5FullName(m.Name, m.NameExt), "")]; !ok {
+			continue
+		}
+		mod := gurps.NewTraitModifier(r.Entity, nil, false)
+		mod.Name = gca5FullName(m.Name, m.NameExt)
+		mod.CostAdj = strings.TrimSpace(m.Value)
+		mod.Tags = append(mod.Tags, ReviewTag)
+		t.Modifiers = append(t.Modifiers, mod)
+		if !gurps.HasTag(ReviewTag, t.Tags) {
+			t.Tags = append(t.Tags, ReviewTag)
+		}
+		r.flag(i18n.Text("Trait Modifier"), name+": "+mod.Name, i18n.Text("not found on the library trait"))
+	}
+	return t
+}
+
+func (r *Result) skill(catalog *Catalog, in *gca5Trait) *gurps.Skill {
+	s := catalog.Skill(r.Entity, in.Name, in.NameExt)
+	if s == nil {
+		s = gurps.NewSkill(r.Entity, nil, false)
+		s.Name = strings.TrimSpace(in.Name)
+		s.Specialization = strings.TrimSpace(in.NameExt)
+		if diff, ok := in.difficulty(); ok {
+			s.Difficulty = diff
+		}
+		s.PageRef = in.Ref.Page
+		s.Tags = append(s.Tags, ReviewTag)
+		r.flag(i18n.Text("Skill"), in.fullName(), i18n.Text("not found in the libraries"))
+	}
+	s.Points = in.points()
+	return s
+}
+
+func (r *Result) spell(catalog *Catalog, in *gca5Trait) *gurps.Spell {
+	s := catalog.Spell(r.Entity, in.Name)
+	if s == nil {
+		s = gurps.NewSpell(r.Entity, nil, false)
+		s.Name = strings.TrimSpace(in.Name)
+		if diff, ok := in.difficulty(); ok {
+			s.Difficulty = diff
+		}
+		s.PageRef = in.Ref.Page
+		s.Tags = append(s.Tags, ReviewTag)
+		r.flag(i18n.Text("Spell"), in.fullName(), i18n.Text("not found in the libraries"))
+	}
+	s.Points = in.points()
+	return s
+}
+
+func (r *Result) equipment(catalog *Catalog, in *gca5Trait) *gurps.Equipment {
+	name := in.fullName()
+	e := catalog.Equipment(r.Entity, name)
+	if e == nil {
+		e = catalog.Equipment(r.Entity, in.Name)
+	}
+	if e == nil {
+		e = gurps.NewEquipment(r.Entity, nil, false)
+		e.Name = name
+		e.BaseValue = strings.TrimSpace(in.Calcs.BaseCost)
+		e.BaseWeight = strings.TrimSpace(in.Calcs.BaseWeight)
+		e.PageRef = in.Ref.Page
+		e.Tags = append(e.Tags, ReviewTag)
+		r.flag(i18n.Text("Equipment"), name, i18n.Text("not found in the libraries"))
+	}
+	if count := parseNumber(in.Count); count > 0 {
+		e.Quantity = count
+	}
+	return e
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package importer
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/difficulty"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+const gca5Sample = `<?xml version="1.0" encoding="utf-8"?>
+<gca5>
+  <character>
+    <name>Sir Test</name>
+    <player>Pat</player>
+    <traits>
+      <attributes>
+        <trait><name>ST</name><score>12</score></trait>
+        <trait><name>DX</name><calcs><score>11</score></calcs></trait>
+      </attributes>
+      <advantages>
+        <trait>
+          <name>Combat Reflexes</name>
+          <points>15</points>
+          <modifiers><modifier><name>Homebrew</name><value>+10%</value></modifier></modifiers>
+        </trait>
+        <trait><name>Lucky Hat</name><points>5</points></trait>
+      </advantages>
+      <skills>
+        <trait><name>Guns</name><nameext>Pistol</nameext><type>DX/E</type><points>4</points></trait>
+      </skills>
+      <equipment>
+        <trait><name>Rope</name><count>2</count><calcs><basecost>5</basecost><baseweight>1.5 lb</baseweight></calcs></trait>
+      </equipment>
+    </traits>
+  </character>
+</gca5>`
+
+func TestImportGCA5(t *testing.T) {
+	c := check.New(t)
+	catalog := NewCatalog()
+	lib := gurps.NewTrait(nil, nil, false)
+	lib.Name = "Combat Reflexes"
+	lib.BasePoints = fxp.FromInteger(15)
+	catalog.AddTraits(gurps.LibraryFile{}, []*gurps.Trait{lib})
+
+	result, err := ImportGCA5([]byte(gca5Sample), catalog)
+	c.NoError(err)
+	entity := result.Entity
+	c.Equal("Sir Test", entity.Profile.Name)
+	c.Equal("Pat", entity.Profile.PlayerName)
+	c.Equal(fxp.FromInteger(12), entity.Attributes.Set["st"].Maximum())
+	c.Equal(fxp.FromInteger(11), entity.Attributes.Set["dx"].Maximum())
+
+	c.Equal(2, len(entity.Traits))
+	matched := entity.Traits[0]
+	c.Equal("Combat Reflexes", matched.Name)
+	c.True(gurps.HasTag(ReviewTag, matched.Tags))
+	c.Equal(1, len(matched.Modifiers))
+	c.Equal("+10%", matched.Modifiers[0].CostAdj)
+	unmatched := entity.Traits[1]
+	c.Equal(fxp.FromInteger(5), unmatched.BasePoints)
+	c.True(gurps.HasTag(ReviewTag, unmatched.Tags))
+
+	c.Equal(1, len(entity.Skills))
+	c.Equal("Pistol", entity.Skills[0].Specialization)
+	c.Equal("dx", entity.Skills[0].Difficulty.Attribute)
+	c.Equal(difficulty.Easy, entity.Skills[0].Difficulty.Difficulty)
+
+	c.Equal(1, len(entity.CarriedEquipment))
+	c.Equal(fxp.FromInteger(2), entity.CarriedEquipment[0].Quantity)
+	c.Equal("5", entity.CarriedEquipment[0].BaseValue)
+
+	c.Equal(4, len(result.Unmatched))
+
+	_, err = ImportGCA5([]byte("<gca5><character>"), catalog)
+	c.HasError(err)
+}
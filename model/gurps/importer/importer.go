@@ -0,0 +1,215 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package importer provides conversion of characters from the file formats of other programs into entities.
+package importer
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/difficulty"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+// ReviewTag is added to imported items that could not be matched against the libraries, so that they can be found and
+// reviewed by hand.
+const ReviewTag = "Needs Review"
+
+// Result holds the outcome of an import.
+type Result struct {
+	Entity *gurps.Entity
+	// Unmatched holds a description of each imported item that could not be matched against the libraries.
+	Unmatched []string
+}
+
+type catalogEntry[T gurps.NodeTypes] struct {
+	from gurps.LibraryFile
+	item T
+}
+
+// Catalog provides lookup of library items by name, so that imported items can be replaced with their full library
+// definitions.
+type Catalog struct {
+	traits    map[string]catalogEntry[*gurps.Trait]
+	skills    map[string]catalogEntry[*gurps.Skill]
+	spells    map[string]catalogEntry[*gurps.Spell]
+	equipment map[string]catalogEntry[*gurps.Equipment]
+}
+
+// NewCatalog creates a new, empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		traits:    make(map[string]catalogEntry[*gurps.Trait]),
+		skills:    make(map[string]catalogEntry[*gurps.Skill]),
+		spells:    make(map[string]catalogEntry[*gurps.Spell]),
+		equipment: make(map[string]catalogEntry[*gurps.Equipment]),
+	}
+}
+
+// NewCatalogFromLibraries creates a new Catalog populated with the traits, skills, spells, and equipment found in the
+// libraries. Files that cannot be loaded are skipped.
+func NewCatalogFromLibraries(libraries gurps.Libraries) *Catalog {
+	c := NewCatalog()
+	for _, lib := range libraries.List() {
+		fileSystem := os.DirFS(lib.Path())
+		_ = fs.WalkDir(fileSystem, ".", func(p string, d fs.DirEntry, err error) error { //nolint:errcheck // Intentionally ignored the error result
+			if err != nil {
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() && p != "." {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			from := gurps.LibraryFile{Library: lib.Key(), Path: p}
+			switch strings.ToLower(path.Ext(p)) {
+			case gurps.TraitsExt:
+				if list, loadErr := gurps.NewTraitsFromFile(fileSystem, p); loadErr == nil {
+					c.AddTraits(from, list)
+				}
+			case gurps.SkillsExt:
+				if list, loadErr := gurps.NewSkillsFromFile(fileSystem, p); loadErr == nil {
+					c.AddSkills(from, list)
+				}
+			case gurps.SpellsExt:
+				if list, loadErr := gurps.NewSpellsFromFile(fileSystem, p); loadErr == nil {
+					c.AddSpells(from, list)
+				}
+			case gurps.EquipmentExt:
+				if list, loadErr := gurps.NewEquipmentFromFile(fileSystem, p); loadErr == nil {
+					c.AddEquipment(from, list)
+				}
+			}
+			return nil
+		})
+	}
+	return c
+}
+
+func catalogKey(name, specialization string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if specialization = strings.ToLower(strings.TrimSpace(specialization)); specialization != "" {
+		key += " (" + specialization + ")"
+	}
+	return key
+}
+
+func addToCatalog[T gurps.NodeTypes](m map[string]catalogEntry[T], from gurps.LibraryFile, list []T, key func(T) string) {
+	gurps.Traverse(func(one T) bool {
+		if k := key(one); k != "" {
+			if _, exists := m[k]; !exists {
+				m[k] = catalogEntry[T]{from: from, item: one}
+			}
+		}
+		return false
+	}, false, true, list...)
+}
+
+// AddTraits adds the non-container traits in the list to the catalog. Earlier additions take precedence over later
+// ones with the same name.
+func (c *Catalog) AddTraits(from gurps.LibraryFile, list []*gurps.Trait) {
+	addToCatalog(c.traits, from, list, func(t *gurps.Trait) string { return catalogKey(t.Name, "") })
+}
+
+// AddSkills adds the non-container skills in the list to the catalog. Earlier additions take precedence over later
+// ones with the same name and specialization.
+func (c *Catalog) AddSkills(from gurps.LibraryFile, list []*gurps.Skill) {
+	addToCatalog(c.skills, from, list, func(s *gurps.Skill) string { return catalogKey(s.Name, s.Specialization) })
+}
+
+// AddSpells adds the non-container spells in the list to the catalog. Earlier additions take precedence over later
+// ones with the same name.
+func (c *Catalog) AddSpells(from gurps.LibraryFile, list []*gurps.Spell) {
+	addToCatalog(c.spells, from, list, func(s *gurps.Spell) string { return catalogKey(s.Name, "") })
+}
+
+// AddEquipment adds the non-container equipment in the list to the catalog. Earlier additions take precedence over
+// later ones with the same name.
+func (c *Catalog) AddEquipment(from gurps.LibraryFile, list []*gurps.Equipment) {
+	addToCatalog(c.equipment, from, list, func(e *gurps.Equipment) string { return catalogKey(e.Name, "") })
+}
+
+// Trait returns a copy of the trait with the given name, owned by the entity, or nil if there is no match.
+func (c *Catalog) Trait(entity *gurps.Entity, name string) *gurps.Trait {
+	if entry, ok := c.traits[catalogKey(name, "")]; ok {
+		return entry.item.Clone(entry.from, entity, nil, false)
+	}
+	return nil
+}
+
+// Skill returns a copy of the skill with the given name and specialization, owned by the entity, or nil if there is no
+// match.
+func (c *Catalog) Skill(entity *gurps.Entity, name, specialization string) *gurps.Skill {
+	if entry, ok := c.skills[catalogKey(name, specialization)]; ok {
+		return entry.item.Clone(entry.from, entity, nil, false)
+	}
+	return nil
+}
+
+// Spell returns a copy of the spell with the given name, owned by the entity, or nil if there is no match.
+func (c *Catalog) Spell(entity *gurps.Entity, name string) *gurps.Spell {
+	if entry, ok := c.spells[catalogKey(name, "")]; ok {
+		return entry.item.Clone(entry.from, entity, nil, false)
+	}
+	return nil
+}
+
+// Equipment returns a copy of the equipment with the given name, owned by the entity, or nil if there is no match.
+func (c *Catalog) Equipment(entity *gurps.Entity, name string) *gurps.Equipment {
+	if entry, ok := c.equipment[catalogKey(name, "")]; ok {
+		return entry.item.Clone(entry.from, entity, nil, false)
+	}
+	return nil
+}
+
+// parseNumber returns the first of the values that can be parsed as a number, or zero if none can be.
+func parseNumber(values ...string) fxp.Int {
+	for _, one := range values {
+		if one = strings.TrimSpace(one); one != "" {
+			if v, err := fxp.FromString(one); err == nil {
+				return v
+			}
+		}
+	}
+	return 0
+}
+
+// parseDifficulty returns the attribute & difficulty from the first of the values that is in the form used by most
+// programs for display, e.g. "DX/A".
+func parseDifficulty(values ...string) (gurps.AttributeDifficulty, bool) {
+	for _, one := range values {
+		if parts := strings.SplitN(strings.TrimSpace(one), "/", 2); len(parts) == 2 {
+			diff := strings.TrimSpace(parts[1])
+			if level := difficulty.ExtractLevel(diff); strings.EqualFold(level.Key(), diff) {
+				return gurps.AttributeDifficulty{
+					Attribute:  strings.ToLower(strings.TrimSpace(parts[0])),
+					Difficulty: level,
+				}, true
+			}
+		}
+	}
+	return gurps.AttributeDifficulty{}, false
+}
+
+func readFile(filePath string, reader func(data []byte) (*Result, error)) (*Result, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return reader(data)
+}
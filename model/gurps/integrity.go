@@ -0,0 +1,106 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/merge"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+// IntegrityIssue describes a value stored in a character sheet file that disagrees with the value calculated from the
+// rest of the sheet.
+type IntegrityIssue struct {
+	// Row describes the entry holding the value, e.g. ["traits", "Luck"]. It is empty for values of the sheet itself.
+	Row []string
+	// Field is the key of the value within the entry's calculated values, e.g. "points". It is empty when the entry
+	// has no calculated values stored at all.
+	Field string
+	// Stored holds the value found in the file as JSON and is empty if it was missing.
+	Stored jsontext.Value
+	// Calculated holds the value calculated from scratch as JSON.
+	Calculated jsontext.Value
+}
+
+// CheckSheetIntegrity recalculates every derived value of the character sheet file from scratch, returning those whose
+// stored values disagree. Files written by older versions, or edited by hand, may hold values like these.
+func CheckSheetIntegrity(fileSystem fs.FS, filePath string) ([]*IntegrityIssue, error) {
+	stored, err := fs.ReadFile(fileSystem, filePath)
+	if err != nil {
+		return nil, errs.NewWithCause(filePath, err)
+	}
+	stored = bytes.TrimPrefix(stored, []byte("\xef\xbb\xbf"))
+	var e *Entity
+	if e, err = NewEntityFromFile(fileSystem, filePath); err != nil {
+		return nil, err
+	}
+	return CheckIntegrity(stored, e)
+}
+
+// CheckIntegrity compares the values held in stored, the contents of a character sheet file, with those calculated for
+// e, which must have been loaded from it.
+func CheckIntegrity(stored []byte, e *Entity) ([]*IntegrityIssue, error) {
+	calculated, err := json.Marshal(e)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var changes []*merge.Change
+	if changes, err = merge.DiffCalculated(stored, calculated); err != nil {
+		return nil, err
+	}
+	issues := make([]*IntegrityIssue, 0, len(changes)+1)
+	// Loading reconciles the points record with the total points, so the record must be checked against the file.
+	var record struct {
+		TotalPoints  fxp.Int         `json:"total_points"`
+		PointsRecord []*PointsRecord `json:"points_record"`
+	}
+	if err = json.Unmarshal(stored, &record); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if awarded := TotalAwardedPoints(record.PointsRecord); awarded != record.TotalPoints {
+		issues = append(issues, &IntegrityIssue{
+			Field:      "points_record",
+			Stored:     jsontext.Value(awarded.String()),
+			Calculated: jsontext.Value(record.TotalPoints.String()),
+		})
+	}
+	for _, one := range changes {
+		issue := &IntegrityIssue{
+			Row:        one.Path,
+			Stored:     one.Before,
+			Calculated: one.After,
+		}
+		if i := slices.Index(one.Path, "calc"); i != -1 {
+			issue.Row = one.Path[:i]
+			if i+1 < len(one.Path) {
+				issue.Field = one.Path[i+1]
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// RepairSheetIntegrity rewrites the character sheet file with its derived values recalculated from scratch.
+func RepairSheetIntegrity(filePath string) error {
+	e, err := NewEntityFromFile(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
+	return e.Save(filePath)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSheetIntegrity(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	sheetPath := filepath.Join(dir, "hero"+SheetExt)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Luck"
+	trait.BasePoints = fxp.Fifteen
+	e.SetTraitList([]*Trait{trait})
+	c.NoError(e.Save(sheetPath))
+
+	issues, err := CheckSheetIntegrity(os.DirFS(dir), filepath.Base(sheetPath))
+	c.NoError(err)
+	c.Equal(0, len(issues))
+
+	data, err := os.ReadFile(sheetPath)
+	c.NoError(err)
+	c.True(bytes.Contains(data, []byte(`"points": 15`)))
+	c.NoError(os.WriteFile(sheetPath, bytes.Replace(data, []byte(`"points": 15`), []byte(`"points": 12`), 1), 0o640))
+	issues, err = CheckSheetIntegrity(os.DirFS(dir), filepath.Base(sheetPath))
+	c.NoError(err)
+	c.Equal(1, len(issues))
+	c.Equal([]string{"traits", "Luck"}, issues[0].Row)
+	c.Equal("points", issues[0].Field)
+	c.Equal("12", string(issues[0].Stored))
+	c.Equal("15", string(issues[0].Calculated))
+
+	c.NoError(RepairSheetIntegrity(sheetPath))
+	issues, err = CheckSheetIntegrity(os.DirFS(dir), filepath.Base(sheetPath))
+	c.NoError(err)
+	c.Equal(0, len(issues))
+}
@@ -14,14 +14,20 @@ import (
 	"encoding/json/jsontext"
 	"encoding/json/v2"
 	"io/fs"
+	"runtime"
 	"slices"
 
 	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/xos"
 	"github.com/richardwilkes/toolbox/v2/xreflect"
 	"github.com/richardwilkes/toolbox/v2/xstrings"
 	"github.com/richardwilkes/unison"
 )
 
+// keyBindingsPlatformKey is the key used to record the platform a set of key bindings was written on. It is stored
+// alongside the bindings themselves so that older versions, which ignore unknown IDs, can still read the file.
+const keyBindingsPlatformKey = "platform"
+
 var factoryBindings = make(map[string]*Binding)
 
 // KeyBindings holds a set of key bindings.
@@ -68,6 +74,20 @@ func CurrentBindings() []*Binding {
 	return list
 }
 
+// ConflictingBindings returns the bindings, other than the one with the given ID, that currently use the key binding.
+func ConflictingBindings(id string, binding unison.KeyBinding) []*Binding {
+	if binding.KeyCode.IsZero() {
+		return nil
+	}
+	var list []*Binding
+	for _, one := range CurrentBindings() {
+		if one.ID != id && one.KeyBinding == binding {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
 // NewKeyBindingsFromFS creates a new set of key bindings from a file. Any missing values will be filled in with
 // defaults.
 func NewKeyBindingsFromFS(fileSystem fs.FS, filePath string) (*KeyBindings, error) {
@@ -95,25 +115,54 @@ func (b *KeyBindings) Save(filePath string) error {
 
 // MarshalJSONTo implements json.MarshalerTo.
 func (b *KeyBindings) MarshalJSONTo(enc *jsontext.Encoder) error {
-	data := make(map[string]unison.KeyBinding, len(b.data))
+	data := make(map[string]string, len(b.data)+1)
 	for k, v := range b.data {
 		if info, ok := factoryBindings[k]; ok && info.KeyBinding != v {
-			data[k] = v
+			data[k] = v.Key()
 		}
 	}
+	if len(data) != 0 {
+		data[keyBindingsPlatformKey] = runtime.GOOS
+	}
 	return json.MarshalEncode(enc, &data)
 }
 
 // UnmarshalJSONFrom implements json.UnmarshalerFrom.
 func (b *KeyBindings) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
-	m := make(map[string]unison.KeyBinding, len(factoryBindings))
-	if err := json.UnmarshalDecode(dec, &m); err != nil {
+	var raw map[string]string
+	if err := json.UnmarshalDecode(dec, &raw); err != nil {
 		return err
 	}
-	b.data = m
+	platform, ok := raw[keyBindingsPlatformKey]
+	if !ok {
+		platform = runtime.GOOS
+	}
+	delete(raw, keyBindingsPlatformKey)
+	// The command key on macOS fills the role the control key has elsewhere, so swap them when the bindings were written
+	// on the other kind of platform.
+	swap := (platform == xos.MacOS) != (runtime.GOOS == xos.MacOS)
+	b.data = make(map[string]unison.KeyBinding, len(raw))
+	for k, v := range raw {
+		binding := unison.KeyBindingFromKey(v)
+		if swap {
+			binding.Modifiers = swapCommandAndControl(binding.Modifiers)
+		}
+		b.data[k] = binding
+	}
 	return nil
 }
 
+func swapCommandAndControl(mods unison.Modifiers) unison.Modifiers {
+	result := mods &^ (unison.CommandModifier | unison.ControlModifier)
+	if mods.CommandDown() {
+		result |= unison.ControlModifier
+	}
+	if mods.ControlDown() {
+		result |= unison.CommandModifier
+	}
+	return result
+}
+
 // MakeCurrent applies these key bindings to the current key bindings set.
 func (b *KeyBindings) MakeCurrent() {
 	var actions []*unison.Action
@@ -0,0 +1,62 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+)
+
+func TestKeyBindingsFromOtherPlatform(t *testing.T) {
+	c := check.New(t)
+	RegisterKeyBinding("test.keymap.one", &unison.Action{Title: "One"})
+	RegisterKeyBinding("test.keymap.two", &unison.Action{Title: "Two"})
+	other := xos.MacOS
+	if runtime.GOOS == xos.MacOS {
+		other = "linux"
+	}
+	fileSystem := fstest.MapFS{
+		"other.keys": {
+			Data: []byte(`{"platform":"` + other + `","test.keymap.one":"cmd+shift+K","test.keymap.two":"alt+J"}`),
+		},
+		"legacy.keys": {Data: []byte(`{"test.keymap.one":"cmd+K"}`)},
+	}
+
+	b, err := NewKeyBindingsFromFS(fileSystem, "other.keys")
+	c.NoError(err)
+	one := b.Current("test.keymap.one")
+	c.Equal(unison.KeyK, one.KeyCode)
+	if other == xos.MacOS {
+		c.Equal(unison.ControlModifier|unison.ShiftModifier, one.Modifiers)
+	} else {
+		c.Equal(unison.CommandModifier|unison.ShiftModifier, one.Modifiers)
+	}
+	c.Equal(unison.OptionModifier, b.Current("test.keymap.two").Modifiers)
+
+	b, err = NewKeyBindingsFromFS(fileSystem, "legacy.keys")
+	c.NoError(err)
+	c.Equal(unison.CommandModifier, b.Current("test.keymap.one").Modifiers, "files without a platform are used as-is")
+}
+
+func TestConflictingBindings(t *testing.T) {
+	c := check.New(t)
+	binding := unison.KeyBinding{KeyCode: unison.KeyF12, Modifiers: unison.OptionModifier}
+	RegisterKeyBinding("test.conflict.one", &unison.Action{Title: "One", KeyBinding: binding})
+	RegisterKeyBinding("test.conflict.two", &unison.Action{Title: "Two", KeyBinding: binding})
+	conflicts := ConflictingBindings("test.conflict.one", binding)
+	c.Equal(1, len(conflicts))
+	c.Equal("test.conflict.two", conflicts[0].ID)
+	c.Equal(0, len(ConflictingBindings("test.conflict.one", unison.KeyBinding{})))
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/merge"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xslices"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// legacyRenamedKeys holds the top-level keys of older files that were renamed, mapped to their current names.
+var legacyRenamedKeys = map[string]string{
+	"advantages": "traits",
+}
+
+// legacyRowRenamedKeys holds, for each "type" that rows in older files identified themselves with, the keys that were
+// renamed, mapped to their current names. Containers used the same type with a "_container" suffix.
+var legacyRowRenamedKeys = map[string]map[string]string{
+	"advantage":          {"notes": "local_notes"},
+	"modifier":           {"notes": "local_notes"},
+	"skill":              {"notes": "local_notes"},
+	"technique":          {"notes": "local_notes"},
+	"spell":              {"notes": "local_notes"},
+	"ritual_magic_spell": {"notes": "local_notes"},
+	"equipment":          {"notes": "local_notes"},
+	"eqp_modifier":       {"notes": "local_notes"},
+	"note":               {"text": "markdown"},
+	"melee_weapon":       nil,
+	"ranged_weapon":      nil,
+}
+
+// LegacyConversion describes the conversion of a file written by an older version to the current data format.
+type LegacyConversion struct {
+	// Path is the path of the file.
+	Path string
+	// Version is the data version the file was written with.
+	Version int
+	// Dropped holds the values that were present in the file but are absent once converted.
+	Dropped []*merge.Change
+	// Altered holds the values that are present both before and after conversion, but differ.
+	Altered []*merge.Change
+	// Err is set if the file could not be converted, in which case it was left untouched.
+	Err error
+}
+
+// ConvertLegacyFiles converts the library and sheet files found within the directory that were written by an older
+// version to the current data format, reporting the values each one lost or had altered along the way. Files already
+// in the current format are left untouched and are not reported.
+func ConvertLegacyFiles(dir string) ([]*LegacyConversion, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, errs.NewWithCause(dir, err)
+	}
+	pathSet := make(map[string]struct{})
+	f := convertWalker(pathSet, xslices.Set(GCSExtensions()))
+	_ = filepath.WalkDir(dir, f) //nolint:errcheck // We want to continue on even if there was an error
+	list := slices.SortedFunc(maps.Keys(pathSet), func(a, b string) int { return xstrings.NaturalCmp(a, b, true) })
+	var results []*LegacyConversion
+	for _, p := range list {
+		if result := convertLegacyFile(p); result != nil {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// convertLegacyFile converts the file if it was written by an older version, returning nil if it was not.
+func convertLegacyFile(p string) *LegacyConversion {
+	result := &LegacyConversion{Path: p}
+	before, err := os.ReadFile(p)
+	if err != nil {
+		result.Err = errs.Wrap(err)
+		return result
+	}
+	before = bytes.TrimPrefix(before, []byte("\xef\xbb\xbf"))
+	var content map[string]jsontext.Value
+	if err = json.Unmarshal(before, &content); err != nil {
+		result.Err = errs.NewWithCause(InvalidFileData(), err)
+		return result
+	}
+	if v, ok := content["version"]; ok {
+		if err = json.Unmarshal(v, &result.Version); err != nil {
+			result.Err = errs.NewWithCause(InvalidFileData(), err)
+			return result
+		}
+	}
+	if result.Version >= jio.CurrentDataVersion {
+		return nil
+	}
+	if err = convertFile(p); err != nil {
+		result.Err = err
+		return result
+	}
+	var after []byte
+	if after, err = os.ReadFile(p); err != nil {
+		result.Err = errs.Wrap(err)
+		return result
+	}
+	// Older files and the rows within them identified themselves with a "type" that is now implied by their extension
+	// or ID, and used some keys that have since been renamed or folded into others. None of that is worth reporting, so
+	// the original is adjusted to match before comparing.
+	for k, v := range content {
+		if content[k], err = normalizeLegacyValue(v); err != nil {
+			result.Err = errs.NewWithCause(InvalidFileData(), err)
+			return result
+		}
+	}
+	delete(content, "type")
+	for from, to := range legacyRenamedKeys {
+		if v, ok := content[from]; ok {
+			if _, exists := content[to]; !exists {
+				content[to] = v
+				delete(content, from)
+			}
+		}
+	}
+	if before, err = json.Marshal(content, json.Deterministic(true)); err != nil {
+		result.Err = errs.Wrap(err)
+		return result
+	}
+	var changes []*merge.Change
+	if changes, err = merge.DiffIgnoringIDs(before, after); err != nil {
+		result.Err = err
+		return result
+	}
+	for _, one := range changes {
+		switch {
+		case len(one.Before) == 0:
+			// Values added by the conversion were not present before, so nothing was lost.
+		case len(one.After) == 0:
+			result.Dropped = append(result.Dropped, one)
+		default:
+			result.Altered = append(result.Altered, one)
+		}
+	}
+	return result
+}
+
+// normalizeLegacyValue returns the value with any rows within it adjusted by normalizeLegacyRow.
+func normalizeLegacyValue(value jsontext.Value) (jsontext.Value, error) {
+	switch value.Kind() {
+	case '{':
+		var obj map[string]jsontext.Value
+		if err := json.Unmarshal(value, &obj); err != nil {
+			return nil, err
+		}
+		for k, v := range obj {
+			var err error
+			if obj[k], err = normalizeLegacyValue(v); err != nil {
+				return nil, err
+			}
+		}
+		if err := normalizeLegacyRow(obj); err != nil {
+			return nil, err
+		}
+		return json.Marshal(obj, json.Deterministic(true))
+	case '[':
+		var list []jsontext.Value
+		if err := json.Unmarshal(value, &list); err != nil {
+			return nil, err
+		}
+		for i, v := range list {
+			var err error
+			if list[i], err = normalizeLegacyValue(v); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(list, json.Deterministic(true))
+	default:
+		return value, nil
+	}
+}
+
+// normalizeLegacyRow adjusts an object from an older file that identifies itself as a row with a "type" the same way
+// loading it does, but without altering any values, so that only real losses and alterations are reported. Objects
+// without such a type are left alone.
+func normalizeLegacyRow(obj map[string]jsontext.Value) error {
+	v, ok := obj["type"]
+	if !ok {
+		return nil
+	}
+	var rowType string
+	if json.Unmarshal(v, &rowType) != nil {
+		// Not a row, as its type isn't a string.
+		return nil
+	}
+	renames, ok := legacyRowRenamedKeys[strings.TrimSuffix(rowType, containerKeyPostfix)]
+	if !ok {
+		return nil
+	}
+	// The type is now implied by the ID, and whether a container is open is no longer kept in the file.
+	delete(obj, "type")
+	delete(obj, "open")
+	for from, to := range renames {
+		if value, exists := obj[from]; exists {
+			if _, exists = obj[to]; !exists {
+				obj[to] = value
+			}
+			delete(obj, from)
+		}
+	}
+	var tags, categories []string
+	if value, exists := obj["tags"]; exists {
+		if err := json.Unmarshal(value, &tags); err != nil {
+			return err
+		}
+	}
+	if value, exists := obj["categories"]; exists {
+		if err := json.Unmarshal(value, &categories); err != nil {
+			return err
+		}
+		delete(obj, "categories")
+	}
+	if rowType == "advantage" || rowType == "advantage"+containerKeyPostfix {
+		// Older traits used flags for what are now tags.
+		for flag, name := range map[string]string{
+			"mental":       i18n.Text("Mental"),
+			"physical":     i18n.Text("Physical"),
+			"social":       i18n.Text("Social"),
+			"exotic":       i18n.Text("Exotic"),
+			"supernatural": i18n.Text("Supernatural"),
+		} {
+			if value, exists := obj[flag]; exists {
+				var set bool
+				if err := json.Unmarshal(value, &set); err != nil {
+					return err
+				}
+				if set && !slices.Contains(tags, name) {
+					tags = append(tags, name)
+				}
+				delete(obj, flag)
+			}
+		}
+	}
+	tags = convertOldCategoriesToTags(tags, categories)
+	if len(tags) != 0 {
+		slices.Sort(tags)
+		data, err := json.Marshal(tags)
+		if err != nil {
+			return err
+		}
+		obj["tags"] = data
+	}
+	return nil
+}
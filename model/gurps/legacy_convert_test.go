@@ -0,0 +1,85 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestConvertLegacyFiles(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	legacyPath := filepath.Join(dir, "old", "legacy"+TraitsExt)
+	c.NoError(os.MkdirAll(filepath.Dir(legacyPath), 0o750))
+	c.NoError(os.WriteFile(legacyPath, []byte(`{
+	"type": "advantage_list",
+	"version": 2,
+	"rows": [
+		{
+			"id": "4a3b2c1d-0000-4000-8000-000000000001",
+			"type": "advantage",
+			"name": "Luck",
+			"base_points": 15,
+			"categories": ["Advantage"],
+			"mental": true,
+			"notes": "Rerolls",
+			"lost_in_conversion": "gone"
+		},
+		{
+			"id": "4a3b2c1d-0000-4000-8000-000000000002",
+			"type": "advantage_container",
+			"name": "Gifts",
+			"open": true,
+			"children": [
+				{
+					"id": "4a3b2c1d-0000-4000-8000-000000000003",
+					"type": "advantage",
+					"name": "Charisma",
+					"categories": ["Social / Advantage"]
+				}
+			]
+		}
+	]
+}`), 0o640))
+	trait := NewTrait(nil, nil, false)
+	trait.Name = "Fit"
+	trait.BasePoints = fxp.Five
+	currentPath := filepath.Join(dir, "current"+TraitsExt)
+	c.NoError(SaveTraits([]*Trait{trait}, currentPath))
+	c.NoError(os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a GCS file"), 0o640))
+
+	results, err := ConvertLegacyFiles(dir)
+	c.NoError(err)
+	c.Equal(1, len(results), "files already in the current format are not reported")
+	result := results[0]
+	c.Equal("legacy"+TraitsExt, filepath.Base(result.Path))
+	c.Equal(2, result.Version)
+	c.NoError(result.Err)
+	c.Equal(1, len(result.Dropped), "renamed and retyped values are not reported")
+	c.Equal([]string{"rows", "Luck", "lost_in_conversion"}, result.Dropped[0].Path)
+	c.Equal(0, len(result.Altered))
+
+	traits, err := NewTraitsFromFile(os.DirFS(filepath.Dir(legacyPath)), filepath.Base(legacyPath))
+	c.NoError(err)
+	c.Equal(2, len(traits))
+	c.Equal([]string{"Advantage", "Mental"}, traits[0].Tags)
+	c.Equal("Rerolls", traits[0].LocalNotes)
+	c.Equal(1, len(traits[1].Children))
+	c.Equal([]string{"Advantage", "Social"}, traits[1].Children[0].Tags)
+
+	results, err = ConvertLegacyFiles(dir)
+	c.NoError(err)
+	c.Equal(0, len(results), "converted files are not converted again")
+}
@@ -9,13 +9,38 @@
 
 package gurps
 
-import "github.com/richardwilkes/gcs/v5/model/fxp"
+import (
+	"strings"
 
-// Level provides a level & relative level pair, plus a tooltip.
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// Level provides a level & relative level pair, plus a tooltip. Resolution, when set, describes the chain of defaults
+// the level was calculated from.
 type Level struct {
 	Level         fxp.Int
 	RelativeLevel fxp.Int
 	Tooltip       string
+	Resolution    string
+}
+
+// FullTooltip returns the tooltip for the level, preceded by the default resolution path, if any.
+func (l Level) FullTooltip() string {
+	var buffer strings.Builder
+	if l.Resolution != "" {
+		buffer.WriteString(i18n.Text("Defaults from: "))
+		buffer.WriteString(l.Resolution)
+	}
+	if l.Tooltip != "" {
+		if buffer.Len() != 0 {
+			buffer.WriteString("\n\n")
+		}
+		buffer.WriteString(IncludesModifiersFrom())
+		buffer.WriteByte(':')
+		buffer.WriteString(l.Tooltip)
+	}
+	return buffer.String()
 }
 
 // LevelAsString returns the level as a string.
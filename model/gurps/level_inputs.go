@@ -0,0 +1,215 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"hash"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/nameable"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xhash"
+	"github.com/zeebo/xxh3"
+)
+
+// levelInputs tracks what the level of each skill and spell was last calculated from, so that recalculating an entity
+// only needs to calculate the levels whose inputs have changed since then. The inputs are split into those shared by
+// every skill and spell (attributes, bonuses, encumbrance, settings and the like), the item's own data, and the
+// current state of any other skills the item defaults to, which are found by name.
+type levelInputs struct {
+	skills map[*Skill]uint64
+	spells map[*Spell]uint64
+}
+
+// levelPass holds the data needed to fingerprint the inputs of each skill and spell during one update of their levels.
+type levelPass struct {
+	shared   uint64
+	own      map[*Skill]uint64
+	byName   map[string][]*Skill
+	allSkill uint64
+}
+
+func (e *Entity) newLevelPass() *levelPass {
+	p := &levelPass{
+		own:    make(map[*Skill]uint64),
+		byName: make(map[string][]*Skill),
+	}
+	h := xxh3.New()
+	Traverse(func(s *Skill) bool {
+		p.own[s] = skillOwnHash(s)
+		key := strings.ToLower(s.NameWithReplacements())
+		p.byName[key] = append(p.byName[key], s)
+		xhash.Num64(h, p.own[s])
+		return false
+	}, false, true, e.Skills...)
+	p.allSkill = h.Sum64()
+	h.Reset()
+	e.hashSharedLevelInputs(h)
+	// Whether one skill may default to another also depends on what the skills in between default to, so any change to
+	// the names or defaults of any skill is treated as a change for all of them.
+	Traverse(func(s *Skill) bool {
+		xhash.StringWithLen(h, s.NameWithReplacements())
+		xhash.StringWithLen(h, s.SpecializationWithReplacements())
+		xhash.Num64(h, len(s.Defaults))
+		for _, def := range s.Defaults {
+			xhash.StringWithLen(h, def.DefaultType)
+			xhash.StringWithLen(h, def.NameWithReplacements(s.Replacements))
+			xhash.StringWithLen(h, def.SpecializationWithReplacements(s.Replacements))
+		}
+		return false
+	}, false, true, e.Skills...)
+	p.shared = h.Sum64()
+	return p
+}
+
+// hashSharedLevelInputs writes the inputs that the level of any skill or spell may depend on into the hasher.
+func (e *Entity) hashSharedLevelInputs(h hash.Hash) {
+	if e.SheetSettings != nil {
+		if err := json.MarshalWrite(h, e.SheetSettings, json.Deterministic(true)); err != nil {
+			errs.Log(err)
+		}
+	}
+	xhash.StringWithLen(h, e.Profile.TechLevel)
+	for _, attr := range e.Attributes.List() {
+		xhash.StringWithLen(h, attr.AttrID)
+		xhash.Num64(h, attr.Current())
+		xhash.Num64(h, attr.Maximum())
+	}
+	xhash.Num8(h, uint8(e.EncumbranceLevel(true)))
+	xhash.Num64(h, e.DodgeBonus)
+	xhash.Num64(h, e.ParryBonus)
+	xhash.Num64(h, e.BlockBonus)
+	for _, list := range [][]Bonus{
+		asBonuses(e.features.skillBonuses),
+		asBonuses(e.features.skillPointBonuses),
+		asBonuses(e.features.spellBonuses),
+		asBonuses(e.features.spellPointBonuses),
+	} {
+		xhash.Num64(h, len(list))
+		for _, bonus := range list {
+			hashBonusForLevels(h, bonus)
+		}
+	}
+	Traverse(func(t *Trait) bool {
+		if t.PowerTalent {
+			xhash.StringWithLen(h, t.String())
+			xhash.StringWithLen(h, t.EffectivePowerSource().String())
+			xhash.Num64(h, t.TalentLevel())
+		}
+		return false
+	}, true, true, e.Traits...)
+}
+
+func asBonuses[T Bonus](list []T) []Bonus {
+	result := make([]Bonus, len(list))
+	for i, one := range list {
+		result[i] = one
+	}
+	return result
+}
+
+// hashBonusForLevels writes what a bonus contributes to skill and spell levels into the hasher: its criteria, its
+// amount, and the owner, which supplies both the substitutions used when matching and the text added to tooltips.
+func hashBonusForLevels(h hash.Hash, bonus Bonus) {
+	bonus.Hash(h)
+	xhash.Num64(h, bonus.AdjustedAmount())
+	owner := bonus.Owner()
+	if owner != nil {
+		xhash.StringWithLen(h, owner.String())
+	}
+	if na, ok := owner.(nameable.Accesser); ok {
+		hashReplacements(h, na.NameableReplacements())
+	}
+}
+
+// skillOwnHash returns a hash of the skill's own data that its level is calculated from.
+func skillOwnHash(s *Skill) uint64 {
+	h := xxh3.New()
+	s.Hash(h)
+	hashReplacements(h, s.Replacements)
+	if !s.Container() {
+		xhash.Num64(h, s.Points)
+		if s.TechLevel != nil {
+			xhash.StringWithLen(h, *s.TechLevel)
+		} else {
+			xhash.Num8(h, uint8(255))
+		}
+	}
+	return h.Sum64()
+}
+
+func hashReplacements(h hash.Hash, replacements map[string]string) {
+	keys := slices.Sorted(maps.Keys(replacements))
+	xhash.Num64(h, len(keys))
+	for _, k := range keys {
+		xhash.StringWithLen(h, k)
+		xhash.StringWithLen(h, replacements[k])
+	}
+}
+
+// hashSkillsNamed writes the current state of the skills with the given name, other than the one being fingerprinted,
+// into the hasher.
+func (p *levelPass) hashSkillsNamed(h hash.Hash, name string, self *Skill) {
+	for _, one := range p.byName[strings.ToLower(name)] {
+		if one == self {
+			continue
+		}
+		xhash.Num64(h, p.own[one])
+		xhash.Num64(h, one.LevelData.Level)
+		xhash.Num64(h, one.LevelData.RelativeLevel)
+		if one.DefaultedFrom != nil {
+			xhash.Num64(h, one.DefaultedFrom.AdjLevel)
+			xhash.Num64(h, one.DefaultedFrom.Points)
+		}
+	}
+}
+
+// skillFingerprint returns a hash of everything the skill's level is calculated from.
+func (p *levelPass) skillFingerprint(s *Skill) uint64 {
+	h := xxh3.New()
+	xhash.Num64(h, p.shared)
+	xhash.Num64(h, p.own[s])
+	defaults := slices.Clone(s.Defaults)
+	if s.TechniqueDefault != nil {
+		defaults = append(defaults, s.TechniqueDefault)
+	}
+	defaults = append(defaults, s.TechniqueAlternateDefaults...)
+	for _, def := range defaults {
+		if def != nil && def.SkillBased() {
+			p.hashSkillsNamed(h, def.NameWithReplacements(s.Replacements), s)
+		}
+	}
+	return h.Sum64()
+}
+
+// spellFingerprint returns a hash of everything the spell's level is calculated from.
+func (p *levelPass) spellFingerprint(s *Spell) uint64 {
+	h := xxh3.New()
+	xhash.Num64(h, p.shared)
+	s.Hash(h)
+	hashReplacements(h, s.Replacements)
+	if !s.Container() {
+		xhash.Num64(h, s.Points)
+		xhash.Num64(h, s.PrereqCount)
+		if s.IsRitualMagic() {
+			// The ritual skill is looked up with a specialization for each college, so rather than trying to mirror
+			// that here, any change to any skill is treated as a change to the spell.
+			xhash.Num64(h, p.allSkill)
+			Traverse(func(sk *Skill) bool {
+				xhash.Num64(h, sk.LevelData.Level)
+				return false
+			}, false, true, EntityFromNode(s).Skills...)
+		}
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestEntityRecalculatesChangedLevels(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	broadsword := NewSkill(e, nil, false)
+	broadsword.Name = "Broadsword"
+	shortsword := NewSkill(e, nil, false)
+	shortsword.Name = "Shortsword"
+	shortsword.Points = 0
+	shortsword.Defaults = []*SkillDefault{{DefaultType: SkillID, Name: "Broadsword", Modifier: -fxp.Two}}
+	knife := NewSkill(e, nil, false)
+	knife.Name = "Knife"
+	e.Skills = []*Skill{broadsword, shortsword, knife}
+	e.Recalculate()
+	c.Equal(fxp.Nine, broadsword.LevelData.Level)
+	c.Equal(fxp.Seven, shortsword.LevelData.Level)
+	c.Equal(fxp.Nine, knife.LevelData.Level)
+
+	// Mark the knife's level so that we can tell whether it was calculated again
+	knife.LevelData.Tooltip = "unchanged"
+
+	broadsword.Points = fxp.Four
+	e.Recalculate()
+	c.Equal(fxp.Eleven, broadsword.LevelData.Level, "the edited skill")
+	c.Equal(fxp.Nine, shortsword.LevelData.Level, "a skill that defaults to the edited skill")
+	c.Equal("unchanged", knife.LevelData.Tooltip, "an unrelated skill")
+
+	bonus := NewSkillBonus()
+	bonus.NameCriteria.Qualifier = "Knife"
+	trait := NewTrait(e, nil, false)
+	trait.Features = append(trait.Features, bonus)
+	e.Traits = append(e.Traits, trait)
+	e.Recalculate()
+	c.Equal(fxp.Ten, knife.LevelData.Level, "a skill affected by a new bonus")
+	c.NotEqual("unchanged", knife.LevelData.Tooltip)
+
+	e.Attributes.Set[DexterityID].SetMaximum(fxp.Eleven)
+	e.Recalculate()
+	c.Equal(fxp.Twelve, broadsword.LevelData.Level, "a change to an attribute")
+	c.Equal(fxp.Ten, shortsword.LevelData.Level)
+	c.Equal(fxp.Eleven, knife.LevelData.Level)
+}
@@ -238,38 +238,38 @@ func (l *Library) VersionOnDisk() string {
 	return strings.TrimSpace(string(bytes.SplitN(data, []byte{'\n'}, 2)[0]))
 }
 
+// StagedRelease holds a library release that has been downloaded, but not yet installed.
+type StagedRelease struct {
+	lib     *Library
+	dir     string
+	version string
+}
+
 // Download the release onto the local disk.
 func (l *Library) Download(ctx context.Context, client *http.Client, release Release) error {
-	p := l.Path()
-	tmpDir, err := os.MkdirTemp(filepath.Dir(p), filepath.Base(p)+"_*")
+	staged, err := l.Stage(ctx, client, release)
 	if err != nil {
-		return errs.NewWithCause("unable to create temporary directory", err)
-	}
-	if err = os.Remove(tmpDir); err != nil {
-		return errs.NewWithCause("unable to remove temporary directory:\n"+tmpDir, err)
+		return err
 	}
-	if err = os.Rename(p, tmpDir); err != nil {
-		return errs.NewWithCause("unable to move old directory aside:\n"+p+"\n"+tmpDir, err)
+	return staged.Install()
+}
+
+// Stage downloads the release into a temporary directory alongside the library, so that its contents can be examined
+// before being installed. Either Install() or Discard() must be called on the result.
+func (l *Library) Stage(ctx context.Context, client *http.Client, release Release) (*StagedRelease, error) {
+	p := l.Path()
+	root, err := os.MkdirTemp(filepath.Dir(p), filepath.Base(p)+"_*")
+	if err != nil {
+		return nil, errs.NewWithCause("unable to create temporary directory", err)
 	}
 	success := false
 	defer func() {
-		if success {
-			if err = os.RemoveAll(tmpDir); err != nil {
-				errs.Log(errs.NewWithCause("unable to remove the old data", err), "dir", tmpDir)
-			}
-		} else {
-			if err = os.RemoveAll(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
-				errs.Log(errs.NewWithCause("unable to remove the failed download data", err), "dir", p)
-			}
-			if err = os.Rename(tmpDir, p); err != nil {
-				errs.Log(errs.NewWithCause("unable to move the old directory back into place", err), "old", tmpDir, "new", p)
+		if !success {
+			if err = os.RemoveAll(root); err != nil {
+				errs.Log(errs.NewWithCause("unable to remove the failed download data", err), "dir", root)
 			}
 		}
 	}()
-	if err = os.MkdirAll(p, 0o750); err != nil {
-		return errs.NewWithCause("unable to create directory "+p, err)
-	}
-	root := filepath.Clean(p)
 	rootWithTrailingSep := root
 	if !strings.HasSuffix(rootWithTrailingSep, string(filepath.Separator)) {
 		rootWithTrailingSep += string(filepath.Separator)
@@ -279,7 +279,7 @@ func (l *Library) Download(ctx context.Context, client *http.Client, release Rel
 		var hash string
 		if hash, err = downloadLatestCommit(ctx, "https://github.com/"+l.GitHubAccountName+"/"+l.RepoName+".git",
 			l.AccessToken, mfs); err != nil {
-			return err
+			return nil, err
 		}
 		// use hash that was actually downloaded, in case a commit occurred between our original check and the download
 		release.Version = hash
@@ -294,7 +294,7 @@ func (l *Library) Download(ctx context.Context, client *http.Client, release Rel
 			if !strings.EqualFold("Library", parts[0]) {
 				return nil
 			}
-			fullPath := filepath.Join(p, parts[1])
+			fullPath := filepath.Join(root, parts[1])
 			if !strings.HasPrefix(fullPath, rootWithTrailingSep) {
 				return errs.Newf("path outside of destination directory is not permitted: %s", fullPath)
 			}
@@ -310,17 +310,17 @@ func (l *Library) Download(ctx context.Context, client *http.Client, release Rel
 			}
 			return nil
 		}); err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		var data []byte
 		data, err = l.downloadRelease(ctx, client, release)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		var zr *zip.Reader
 		if zr, err = zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
-			return errs.NewWithCause("unable to open archive "+release.ZipFileURL, err)
+			return nil, errs.NewWithCause("unable to open archive "+release.ZipFileURL, err)
 		}
 		for _, f := range zr.File {
 			fi := f.FileInfo()
@@ -335,27 +335,88 @@ func (l *Library) Download(ctx context.Context, client *http.Client, release Rel
 				}
 				fullPath := filepath.Join(root, parts[2])
 				if !strings.HasPrefix(fullPath, rootWithTrailingSep) {
-					return errs.Newf("path outside of destination directory is not permitted: %s", fullPath)
+					return nil, errs.Newf("path outside of destination directory is not permitted: %s", fullPath)
 				}
 				parent := filepath.Dir(fullPath)
 				if err = os.MkdirAll(parent, 0o750); err != nil {
-					return errs.NewWithCause("unable to create directory "+parent, err)
+					return nil, errs.NewWithCause("unable to create directory "+parent, err)
 				}
 				if err = l.extractFile(f, fullPath); err != nil {
-					return errs.NewWithCause("unable to create file "+fullPath, err)
+					return nil, errs.NewWithCause("unable to create file "+fullPath, err)
 				}
 			}
 		}
 	}
 	f := filepath.Join(root, releaseFile)
 	if err = os.WriteFile(f, []byte(release.Version+"\n"), 0o640); err != nil {
-		return errs.NewWithCause("unable to write version file "+f, err)
+		return nil, errs.NewWithCause("unable to write version file "+f, err)
 	}
-	current := l.VersionOnDisk()
-	l.lock.Lock()
-	l.current = current
-	l.lock.Unlock()
 	success = true
+	return &StagedRelease{lib: l, dir: root, version: release.Version}, nil
+}
+
+// Library returns the library the release was staged for.
+func (s *StagedRelease) Library() *Library {
+	return s.lib
+}
+
+// Dir returns the directory holding the staged release.
+func (s *StagedRelease) Dir() string {
+	return s.dir
+}
+
+// Version returns the version of the staged release.
+func (s *StagedRelease) Version() string {
+	return s.version
+}
+
+// Discard removes the staged release without installing it.
+func (s *StagedRelease) Discard() {
+	if err := os.RemoveAll(s.dir); err != nil {
+		errs.Log(errs.NewWithCause("unable to remove the staged data", err), "dir", s.dir)
+	}
+}
+
+// Install replaces the library's existing content with the staged release.
+func (s *StagedRelease) Install() error {
+	p := s.lib.Path()
+	tmpDir, err := os.MkdirTemp(filepath.Dir(p), filepath.Base(p)+"_*")
+	if err != nil {
+		s.Discard()
+		return errs.NewWithCause("unable to create temporary directory", err)
+	}
+	if err = os.Remove(tmpDir); err != nil {
+		s.Discard()
+		return errs.NewWithCause("unable to remove temporary directory:\n"+tmpDir, err)
+	}
+	hadOld := true
+	if err = os.Rename(p, tmpDir); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			s.Discard()
+			return errs.NewWithCause("unable to move old directory aside:\n"+p+"\n"+tmpDir, err)
+		}
+		hadOld = false
+	}
+	if err = os.Rename(s.dir, p); err != nil {
+		s.Discard()
+		if !hadOld {
+			return errs.NewWithCause("unable to move new directory into place:\n"+s.dir+"\n"+p, err)
+		}
+		if restoreErr := os.Rename(tmpDir, p); restoreErr != nil {
+			errs.Log(errs.NewWithCause("unable to move the old directory back into place", restoreErr), "old", tmpDir,
+				"new", p)
+		}
+		return errs.NewWithCause("unable to move new directory into place:\n"+s.dir+"\n"+p, err)
+	}
+	if hadOld {
+		if err = os.RemoveAll(tmpDir); err != nil {
+			errs.Log(errs.NewWithCause("unable to remove the old data", err), "dir", tmpDir)
+		}
+	}
+	current := s.lib.VersionOnDisk()
+	s.lib.lock.Lock()
+	s.lib.current = current
+	s.lib.lock.Unlock()
 	return nil
 }
 
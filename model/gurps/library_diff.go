@@ -0,0 +1,191 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/toolbox/v2/xreflect"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// LibraryItem describes an item within a library file.
+type LibraryItem struct {
+	Path string
+	ID   tid.TID
+	Name string
+	Hash uint64
+}
+
+// LibraryDiff holds the differences between two versions of a library's content.
+type LibraryDiff struct {
+	Added   []*LibraryItem
+	Removed []*LibraryItem
+	// Changed holds the new version of each item whose content differs between the two versions.
+	Changed []*LibraryItem
+}
+
+// IndexLibraryItems returns the items found in the library files within fileSystem, keyed by their IDs.
+func IndexLibraryItems(fileSystem fs.FS) map[tid.TID]*LibraryItem {
+	index := make(map[tid.TID]*LibraryItem)
+	_ = fs.WalkDir(fileSystem, ".", func(p string, d fs.DirEntry, err error) error { //nolint:errcheck // We want to continue on even if there was an error
+		if err != nil {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() && p != "." {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fi := FileInfoFor(p)
+		if fi == nil || len(fi.Extensions) == 0 {
+			return nil
+		}
+		dir, file := path.Split(p)
+		sub := fileSystem
+		if dir != "" {
+			if sub, err = fs.Sub(fileSystem, path.Clean(dir)); err != nil {
+				return nil
+			}
+		}
+		for id, one := range libraryFileHashes(sub, file, fi.Extensions[0]) {
+			if _, exists := index[id]; !exists {
+				index[id] = &LibraryItem{
+					Path: p,
+					ID:   id,
+					Name: fmt.Sprint(one.Data),
+					Hash: one.Hash,
+				}
+			}
+		}
+		return nil
+	})
+	return index
+}
+
+// DiffLibraryItems returns the differences between two indexes created by IndexLibraryItems().
+func DiffLibraryItems(before, after map[tid.TID]*LibraryItem) *LibraryDiff {
+	var diff LibraryDiff
+	for id, item := range after {
+		if old, exists := before[id]; !exists {
+			diff.Added = append(diff.Added, item)
+		} else if old.Hash != item.Hash {
+			diff.Changed = append(diff.Changed, item)
+		}
+	}
+	for id, item := range before {
+		if _, exists := after[id]; !exists {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+	for _, list := range [][]*LibraryItem{diff.Added, diff.Removed, diff.Changed} {
+		slices.SortFunc(list, func(a, b *LibraryItem) int {
+			if result := xstrings.NaturalCmp(a.Path, b.Path, true); result != 0 {
+				return result
+			}
+			return xstrings.NaturalCmp(a.Name, b.Name, true)
+		})
+	}
+	return &diff
+}
+
+// Empty returns true if there are no differences.
+func (d *LibraryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ReferencedBy returns the changed and removed items that the provider's data was sourced from, for the library with
+// the given key.
+func (d *LibraryDiff) ReferencedBy(libraryKey string, provider ListProvider) []*LibraryItem {
+	wanted := make(map[tid.TID]*LibraryItem, len(d.Changed)+len(d.Removed))
+	for _, list := range [][]*LibraryItem{d.Changed, d.Removed} {
+		for _, one := range list {
+			wanted[one.ID] = one
+		}
+	}
+	var result []*LibraryItem
+	visitSourced(provider, func(src *Source, _ func()) {
+		if src.Library == libraryKey {
+			if item, ok := wanted[src.TID]; ok {
+				result = append(result, item)
+				delete(wanted, src.TID)
+			}
+		}
+	})
+	return result
+}
+
+// SyncReferences syncs the provider's data that was sourced from the changed items of the library with the given key.
+// Other data is left alone.
+func (d *LibraryDiff) SyncReferences(libraryKey string, provider ListProvider) {
+	changed := make(map[tid.TID]struct{}, len(d.Changed))
+	for _, one := range d.Changed {
+		changed[one.ID] = struct{}{}
+	}
+	if owner := provider.DataOwner(); !xreflect.IsNil(owner) {
+		owner.SourceMatcher().PrepareHashes(provider)
+	}
+	visitSourced(provider, func(src *Source, sync func()) {
+		if src.Library == libraryKey {
+			if _, ok := changed[src.TID]; ok {
+				sync()
+			}
+		}
+	})
+}
+
+// visitSourced calls f with a pointer to the source of each piece of the provider's data that has one, along with a
+// function that will sync that data with its source.
+func visitSourced(provider ListProvider, f func(src *Source, sync func())) {
+	visit := func(src *Source, sync func()) {
+		if !src.IsZero() {
+			f(src, sync)
+		}
+	}
+	Traverse(func(t *Trait) bool {
+		visit(&t.Source, t.SyncWithSource)
+		Traverse(func(mod *TraitModifier) bool {
+			visit(&mod.Source, mod.SyncWithSource)
+			return false
+		}, false, false, t.Modifiers...)
+		return false
+	}, false, false, provider.TraitList()...)
+	Traverse(func(s *Skill) bool {
+		visit(&s.Source, s.SyncWithSource)
+		return false
+	}, false, false, provider.SkillList()...)
+	Traverse(func(s *Spell) bool {
+		visit(&s.Source, s.SyncWithSource)
+		return false
+	}, false, false, provider.SpellList()...)
+	for _, list := range [][]*Equipment{provider.CarriedEquipmentList(), provider.OtherEquipmentList()} {
+		Traverse(func(e *Equipment) bool {
+			visit(&e.Source, e.SyncWithSource)
+			Traverse(func(mod *EquipmentModifier) bool {
+				visit(&mod.Source, mod.SyncWithSource)
+				return false
+			}, false, false, e.Modifiers...)
+			return false
+		}, false, false, list...)
+	}
+	Traverse(func(n *Note) bool {
+		visit(&n.Source, n.SyncWithSource)
+		return false
+	}, false, false, provider.NoteList()...)
+}
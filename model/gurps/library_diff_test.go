@@ -0,0 +1,53 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+func TestDiffLibraryItems(t *testing.T) {
+	c := check.New(t)
+	item := func(id, name string, hash uint64) *LibraryItem {
+		return &LibraryItem{Path: "Traits.adq", ID: tid.TID(id), Name: name, Hash: hash}
+	}
+	before := map[tid.TID]*LibraryItem{
+		"a": item("a", "Same", 1),
+		"b": item("b", "Changed", 2),
+		"c": item("c", "Removed", 3),
+	}
+	after := map[tid.TID]*LibraryItem{
+		"a": item("a", "Same", 1),
+		"b": item("b", "Changed", 20),
+		"d": item("d", "Added", 4),
+	}
+	diff := DiffLibraryItems(before, after)
+	c.False(diff.Empty())
+	c.Equal(1, len(diff.Added))
+	c.Equal("Added", diff.Added[0].Name)
+	c.Equal(1, len(diff.Removed))
+	c.Equal("Removed", diff.Removed[0].Name)
+	c.Equal(1, len(diff.Changed))
+	c.Equal(uint64(20), diff.Changed[0].Hash)
+	c.True(DiffLibraryItems(after, after).Empty())
+
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Source = Source{LibraryFile: LibraryFile{Library: "lib", Path: "Traits.adq"}, TID: "b"}
+	other := NewTrait(e, nil, false)
+	other.Source = Source{LibraryFile: LibraryFile{Library: "other", Path: "Traits.adq"}, TID: "c"}
+	e.SetTraitList([]*Trait{trait, other})
+	refs := diff.ReferencedBy("lib", e)
+	c.Equal(1, len(refs))
+	c.Equal("Changed", refs[0].Name)
+}
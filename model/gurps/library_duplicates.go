@@ -0,0 +1,279 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"cmp"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// DuplicateItem describes an item within a library that may be a duplicate of another.
+type DuplicateItem struct {
+	Source
+	Kind     string
+	Name     string
+	FilePath string
+}
+
+// DuplicateGroup holds a set of library items that appear to be duplicates of each other.
+type DuplicateGroup struct {
+	Kind  string
+	Name  string
+	Items []*DuplicateItem
+	// Exact is true if every item in the group has the same name, ignoring case. When false, the names only matched
+	// after normalizing their word order, punctuation, and plurals.
+	Exact bool
+}
+
+// FindLibraryDuplicates scans the libraries for items of the same kind whose names match, either exactly or after
+// normalization. Containers and the modifiers embedded within traits and equipment are not considered.
+func FindLibraryDuplicates(libs []*Library) []*DuplicateGroup {
+	candidates := make(map[string][]*DuplicateItem)
+	for _, lib := range libs {
+		root := lib.Path()
+		key := lib.Key()
+		fileSystem := os.DirFS(root)
+		_ = fs.WalkDir(fileSystem, ".", func(p string, d fs.DirEntry, err error) error { //nolint:errcheck // We want to continue on even if there was an error
+			if err != nil {
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() && p != "." {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			fi := FileInfoFor(p)
+			if fi == nil || len(fi.Extensions) == 0 {
+				return nil
+			}
+			dir, file := path.Split(p)
+			sub := fileSystem
+			if dir != "" {
+				if sub, err = fs.Sub(fileSystem, path.Clean(dir)); err != nil {
+					return nil
+				}
+			}
+			libFile := LibraryFile{Library: key, Path: p}
+			filePath := filepath.Join(root, filepath.FromSlash(p))
+			add := func(id tid.TID, kind, name string) {
+				item := &DuplicateItem{
+					Source:   Source{LibraryFile: libFile, TID: id},
+					Kind:     kind,
+					Name:     name,
+					FilePath: filePath,
+				}
+				k := kind + "\x00" + duplicateNameKey(name)
+				candidates[k] = append(candidates[k], item)
+			}
+			switch fi.Extensions[0] {
+			case TraitsExt:
+				if data, loadErr := NewTraitsFromFile(sub, file); loadErr == nil {
+					collectDuplicateCandidates(add, data)
+				}
+			case TraitModifiersExt:
+				if data, loadErr := NewTraitModifiersFromFile(sub, file); loadErr == nil {
+					collectDuplicateCandidates(add, data)
+				}
+			case SkillsExt:
+				if data, loadErr := NewSkillsFromFile(sub, file); loadErr == nil {
+					collectDuplicateCandidates(add, data)
+				}
+			case SpellsExt:
+				if data, loadErr := NewSpellsFromFile(sub, file); loadErr == nil {
+					collectDuplicateCandidates(add, data)
+				}
+			case EquipmentExt:
+				if data, loadErr := NewEquipmentFromFile(sub, file); loadErr == nil {
+					collectDuplicateCandidates(add, data)
+				}
+			case EquipmentModifiersExt:
+				if data, loadErr := NewEquipmentModifiersFromFile(sub, file); loadErr == nil {
+					collectDuplicateCandidates(add, data)
+				}
+			}
+			return nil
+		})
+	}
+	var groups []*DuplicateGroup
+	for _, items := range candidates {
+		if len(items) < 2 {
+			continue
+		}
+		slices.SortFunc(items, func(a, b *DuplicateItem) int {
+			if result := cmp.Compare(a.Library, b.Library); result != 0 {
+				return result
+			}
+			if result := xstrings.NaturalCmp(a.Path, b.Path, true); result != 0 {
+				return result
+			}
+			return xstrings.NaturalCmp(a.Name, b.Name, true)
+		})
+		group := &DuplicateGroup{
+			Kind:  items[0].Kind,
+			Name:  items[0].Name,
+			Items: items,
+			Exact: true,
+		}
+		exact := strings.ToLower(strings.TrimSpace(items[0].Name))
+		for _, item := range items[1:] {
+			if strings.ToLower(strings.TrimSpace(item.Name)) != exact {
+				group.Exact = false
+				break
+			}
+		}
+		groups = append(groups, group)
+	}
+	slices.SortFunc(groups, func(a, b *DuplicateGroup) int {
+		if result := cmp.Compare(a.Kind, b.Kind); result != 0 {
+			return result
+		}
+		return xstrings.NaturalCmp(a.Name, b.Name, true)
+	})
+	return groups
+}
+
+func collectDuplicateCandidates[T NodeTypes](add func(id tid.TID, kind, name string), data []T) {
+	Traverse(func(one T) bool {
+		node := AsNode(one)
+		add(node.ID(), node.Kind(), node.String())
+		return false
+	}, false, true, data...)
+}
+
+// duplicateNameKey returns a normalized form of the name that ignores case, punctuation, word order, and simple
+// plurals.
+func duplicateNameKey(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			words[i] = word[:len(word)-1]
+		}
+	}
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// Merge removes every item in the group other than keep from its library file. Returns a map of the sources that were
+// removed to the source of keep, suitable for passing to RetargetSources().
+func (g *DuplicateGroup) Merge(keep *DuplicateItem) (map[Source]Source, error) {
+	replacements := make(map[Source]Source)
+	byFile := make(map[string]map[tid.TID]bool)
+	for _, item := range g.Items {
+		if item.Source == keep.Source {
+			continue
+		}
+		ids, ok := byFile[item.FilePath]
+		if !ok {
+			ids = make(map[tid.TID]bool)
+			byFile[item.FilePath] = ids
+		}
+		ids[item.TID] = true
+		replacements[item.Source] = keep.Source
+	}
+	for filePath, ids := range byFile {
+		if err := removeFromLibraryFile(filePath, ids); err != nil {
+			return replacements, err
+		}
+	}
+	return replacements, nil
+}
+
+func removeFromLibraryFile(filePath string, ids map[tid.TID]bool) error {
+	fileSystem := os.DirFS(filepath.Dir(filePath))
+	file := filepath.Base(filePath)
+	fi := FileInfoFor(filePath)
+	if fi == nil || len(fi.Extensions) == 0 {
+		return errs.New("unexpected file type: " + filePath)
+	}
+	switch fi.Extensions[0] {
+	case TraitsExt:
+		data, err := NewTraitsFromFile(fileSystem, file)
+		if err != nil {
+			return err
+		}
+		return SaveTraits(removeNodesByID(data, ids), filePath)
+	case TraitModifiersExt:
+		data, err := NewTraitModifiersFromFile(fileSystem, file)
+		if err != nil {
+			return err
+		}
+		return SaveTraitModifiers(removeNodesByID(data, ids), filePath)
+	case SkillsExt:
+		data, err := NewSkillsFromFile(fileSystem, file)
+		if err != nil {
+			return err
+		}
+		return SaveSkills(removeNodesByID(data, ids), filePath)
+	case SpellsExt:
+		data, err := NewSpellsFromFile(fileSystem, file)
+		if err != nil {
+			return err
+		}
+		return SaveSpells(removeNodesByID(data, ids), filePath)
+	case EquipmentExt:
+		data, err := NewEquipmentFromFile(fileSystem, file)
+		if err != nil {
+			return err
+		}
+		return SaveEquipment(removeNodesByID(data, ids), filePath)
+	case EquipmentModifiersExt:
+		data, err := NewEquipmentModifiersFromFile(fileSystem, file)
+		if err != nil {
+			return err
+		}
+		return SaveEquipmentModifiers(removeNodesByID(data, ids), filePath)
+	default:
+		return errs.New("unexpected file type: " + filePath)
+	}
+}
+
+func removeNodesByID[T NodeTypes](list []T, ids map[tid.TID]bool) []T {
+	result := make([]T, 0, len(list))
+	for _, one := range list {
+		node := AsNode(one)
+		if ids[node.ID()] {
+			continue
+		}
+		if node.HasChildren() {
+			node.SetChildren(removeNodesByID(node.NodeChildren(), ids))
+		}
+		result = append(result, one)
+	}
+	return result
+}
+
+// RetargetSources changes the source of any of the provider's data that matches a key in replacements to the
+// corresponding value. Returns true if anything was changed.
+func RetargetSources(provider ListProvider, replacements map[Source]Source) bool {
+	changed := false
+	visitSourced(provider, func(src *Source, _ func()) {
+		if to, ok := replacements[*src]; ok {
+			*src = to
+			changed = true
+		}
+	})
+	return changed
+}
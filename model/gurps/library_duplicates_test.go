@@ -0,0 +1,60 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestFindLibraryDuplicates(t *testing.T) {
+	c := check.New(t)
+	lib := &Library{GitHubAccountName: "someone", RepoName: "library", PathOnDisk: t.TempDir()}
+	newTrait := func(name string) *Trait {
+		trait := NewTrait(nil, nil, false)
+		trait.Name = name
+		return trait
+	}
+	first := newTrait("Combat Reflexes")
+	second := newTrait("Reflexes, Combat")
+	firstPath := filepath.Join(lib.PathOnDisk, "First"+TraitsExt)
+	c.NoError(SaveTraits([]*Trait{first, newTrait("Luck")}, firstPath))
+	c.NoError(SaveTraits([]*Trait{second}, filepath.Join(lib.PathOnDisk, "Second"+TraitsExt)))
+
+	groups := FindLibraryDuplicates([]*Library{lib})
+	c.Equal(1, len(groups))
+	c.False(groups[0].Exact)
+	c.Equal(2, len(groups[0].Items))
+	keep := groups[0].Items[0]
+	c.Equal(first.ID(), keep.TID)
+	c.Equal("First"+TraitsExt, keep.Path)
+
+	e := NewEntity()
+	ref := NewTrait(e, nil, false)
+	ref.Source = groups[0].Items[1].Source
+	e.SetTraitList([]*Trait{ref})
+	replacements, err := groups[0].Merge(keep)
+	c.NoError(err)
+	c.True(RetargetSources(e, replacements))
+	c.Equal(keep.Source, ref.Source)
+	c.False(RetargetSources(e, replacements))
+
+	remaining, err := NewTraitsFromFile(os.DirFS(lib.PathOnDisk), "Second"+TraitsExt)
+	c.NoError(err)
+	c.Equal(0, len(remaining))
+	c.Equal(0, len(FindLibraryDuplicates([]*Library{lib})))
+
+	c.Equal(duplicateNameKey("Combat Reflexes"), duplicateNameKey("reflexes (combat)"))
+	c.Equal(duplicateNameKey("Dagger"), duplicateNameKey("Daggers"))
+	c.NotEqual(duplicateNameKey("Glass"), duplicateNameKey("Glas"))
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xbytes"
+)
+
+// Maneuver holds the modifiers that a combat maneuver applies to a character's attacks and defenses for the turn.
+type Maneuver struct {
+	ID           string
+	Name         string
+	Page         string
+	MeleeHit     int
+	RangedHit    int
+	MaxSkill     int
+	DamagePerDie int
+	Dodge        int
+	Parry        int
+	Block        int
+	NoDefense    bool
+	Description  string
+}
+
+// Maneuvers holds the maneuvers that can be selected for a turn. The first entry is the default, which has no effect.
+var Maneuvers = []*Maneuver{
+	{
+		ID:          "",
+		Name:        i18n.Text("No Maneuver"),
+		Description: i18n.Text("No maneuver has been selected for this turn"),
+	},
+	{
+		ID:          "attack",
+		Name:        i18n.Text("Attack"),
+		Page:        "B365",
+		Description: i18n.Text("Attack with a ready weapon"),
+	},
+	{
+		ID:          "aoa_determined",
+		Name:        i18n.Text("All-Out Attack (Determined)"),
+		Page:        "B365",
+		MeleeHit:    4,
+		RangedHit:   1,
+		NoDefense:   true,
+		Description: i18n.Text("+4 to hit in melee or +1 to hit at range; no active defenses"),
+	},
+	{
+		ID:           "aoa_strong",
+		Name:         i18n.Text("All-Out Attack (Strong)"),
+		Page:         "B365",
+		DamagePerDie: 1,
+		NoDefense:    true,
+		Description:  i18n.Text("+2 damage or +1 per die, whichever is better, in melee; no active defenses"),
+	},
+	{
+		ID:          "aoa_double",
+		Name:        i18n.Text("All-Out Attack (Double)"),
+		Page:        "B365",
+		NoDefense:   true,
+		Description: i18n.Text("Two melee attacks; no active defenses"),
+	},
+	{
+		ID:          "committed_determined",
+		Name:        i18n.Text("Committed Attack (Determined)"),
+		Page:        "MA99",
+		MeleeHit:    2,
+		Dodge:       -2,
+		Parry:       -2,
+		Block:       -2,
+		Description: i18n.Text("+2 to hit in melee; -2 to active defenses"),
+	},
+	{
+		ID:           "defensive_attack",
+		Name:         i18n.Text("Defensive Attack"),
+		Page:         "MA100",
+		DamagePerDie: -1,
+		Parry:        1,
+		Block:        1,
+		Description:  i18n.Text("-2 damage or -1 per die, whichever is worse, in melee; +1 to parry and block"),
+	},
+	{
+		ID:          "move_and_attack",
+		Name:        i18n.Text("Move and Attack"),
+		Page:        "B365",
+		MeleeHit:    -4,
+		RangedHit:   -2,
+		MaxSkill:    9,
+		Description: i18n.Text("-4 to hit in melee (effective skill no better than 9) or -2 at range; no retreat"),
+	},
+	{
+		ID:          "aod_dodge",
+		Name:        i18n.Text("All-Out Defense (Increased Dodge)"),
+		Page:        "B366",
+		Dodge:       2,
+		Description: i18n.Text("+2 to dodge; no attacks"),
+	},
+	{
+		ID:          "aod_parry",
+		Name:        i18n.Text("All-Out Defense (Increased Parry)"),
+		Page:        "B366",
+		Parry:       2,
+		Description: i18n.Text("+2 to parry; no attacks"),
+	},
+	{
+		ID:          "aod_block",
+		Name:        i18n.Text("All-Out Defense (Increased Block)"),
+		Page:        "B366",
+		Block:       2,
+		Description: i18n.Text("+2 to block; no attacks"),
+	},
+}
+
+// ManeuverFor returns the maneuver with the given ID, or the default maneuver if not found.
+func ManeuverFor(id string) *Maneuver {
+	for _, one := range Maneuvers {
+		if one.ID == id {
+			return one
+		}
+	}
+	return Maneuvers[0]
+}
+
+// String implements fmt.Stringer.
+func (m *Maneuver) String() string {
+	return m.Name
+}
+
+// CurrentManeuver returns the maneuver currently selected for the entity. Since a maneuver only applies to the turn in
+// progress, it isn't saved with the entity.
+func (e *Entity) CurrentManeuver() *Maneuver {
+	return ManeuverFor(e.Maneuver)
+}
+
+// SkillAdjustment returns the adjusted skill level for an attack with the weapon under this maneuver.
+func (m *Maneuver) SkillAdjustment(w *Weapon, level fxp.Int, tooltip *xbytes.InsertBuffer) fxp.Int {
+	adj := m.MeleeHit
+	if w.IsRanged() {
+		adj = m.RangedHit
+	}
+	if adj != 0 {
+		level += fxp.FromInteger(adj)
+	}
+	if m.MaxSkill != 0 && !w.IsRanged() {
+		level = level.Min(fxp.FromInteger(m.MaxSkill))
+	}
+	if tooltip != nil && (adj != 0 || m.MaxSkill != 0) {
+		m.addToTooltip(tooltip, adj)
+	}
+	return level
+}
+
+// DamageAdjustment returns the adjustment to the damage of an attack with the weapon under this maneuver, given the
+// number of dice of damage being rolled.
+func (m *Maneuver) DamageAdjustment(w *Weapon, dieCount int, tooltip *xbytes.InsertBuffer) int {
+	if m.DamagePerDie == 0 || !w.IsMelee() {
+		return 0
+	}
+	adj := m.DamagePerDie * max(dieCount, 2)
+	if tooltip != nil {
+		m.addToTooltip(tooltip, adj)
+	}
+	return adj
+}
+
+// DefenseAdjustment returns the adjustment to the given defense under this maneuver, which should be one of DodgeID,
+// ParryID, or BlockID. If the defense is not permitted at all, allowed will be false.
+func (m *Maneuver) DefenseAdjustment(defenseID string, tooltip *xbytes.InsertBuffer) (adj int, allowed bool) {
+	if m.NoDefense {
+		return 0, false
+	}
+	switch defenseID {
+	case DodgeID:
+		adj = m.Dodge
+	case ParryID:
+		adj = m.Parry
+	case BlockID:
+		adj = m.Block
+	}
+	if adj != 0 && tooltip != nil {
+		m.addToTooltip(tooltip, adj)
+	}
+	return adj, true
+}
+
+func (m *Maneuver) addToTooltip(tooltip *xbytes.InsertBuffer, adj int) {
+	tooltip.WriteByte('\n')
+	tooltip.WriteString(m.Name)
+	tooltip.WriteString(" [")
+	tooltip.WriteString(fxp.FromInteger(adj).StringWithSign())
+	tooltip.WriteByte(']')
+}
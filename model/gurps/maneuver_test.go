@@ -0,0 +1,76 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"strconv"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestManeuverDefenses(t *testing.T) {
+	c := check.New(t)
+	c.Equal(Maneuvers[0], ManeuverFor("unknown"))
+
+	adj, allowed := ManeuverFor("aoa_determined").DefenseAdjustment(ParryID, nil)
+	c.Equal(0, adj)
+	c.False(allowed)
+
+	adj, allowed = ManeuverFor("committed_determined").DefenseAdjustment(DodgeID, nil)
+	c.Equal(-2, adj)
+	c.True(allowed)
+
+	adj, allowed = ManeuverFor("aod_block").DefenseAdjustment(BlockID, nil)
+	c.Equal(2, adj)
+	c.True(allowed)
+
+	e := NewEntity()
+	e.Recalculate()
+	dodge := e.Dodge(e.EncumbranceLevel(false))
+	e.Maneuver = "aod_dodge"
+	c.Equal(dodge+2, e.Dodge(e.EncumbranceLevel(false)))
+}
+
+func TestManeuverWithoutDefense(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.Recalculate()
+	enc := e.EncumbranceLevel(false)
+	dodge := e.Dodge(enc)
+	c.True(e.CanDodge())
+	c.Equal(strconv.Itoa(dodge), e.DodgeText(enc))
+
+	e.Maneuver = "aoa_determined"
+	c.False(e.CanDodge())
+	c.Equal(0, e.Dodge(enc), "no active defense is permitted")
+	c.Equal("No", e.DodgeText(enc))
+	c.Equal(dodge, e.dodge(enc, false), "skills that default to Dodge aren't affected")
+}
+
+func TestManeuverIsNotSaved(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.Recalculate()
+	plain, err := json.Marshal(e)
+	c.NoError(err)
+
+	e.Maneuver = "aoa_determined"
+	data, err := json.Marshal(e)
+	c.NoError(err)
+	c.Equal("aoa_determined", e.Maneuver, "saving leaves the maneuver in place")
+	c.Equal(string(plain), string(data), "neither the maneuver nor its effects are saved")
+
+	var loaded Entity
+	c.NoError(json.Unmarshal(data, &loaded))
+	c.Equal("", loaded.Maneuver)
+	c.True(loaded.CanDodge())
+}
@@ -40,3 +40,8 @@ func LibSrcTooltip() string {
 func ModifierEnabledTooltip() string {
 	return i18n.Text("Whether this modifier is enabled. Modifiers that are not enabled do not apply any features they may normally contribute.")
 }
+
+// SheetLocked returns a message indicating that the sheet is locked, so its build data may not be changed.
+func SheetLocked() string {
+	return i18n.Text("The sheet is locked. Its traits, skills, spells, attributes and points may not be changed until it is unlocked.")
+}
@@ -65,9 +65,10 @@ type NoteEditData struct {
 
 // NoteSyncData holds the note sync data that is common to both containers and non-containers.
 type NoteSyncData struct {
-	MarkDown         string `json:"markdown,omitzero"`
-	PageRef          string `json:"reference,omitzero"`
-	PageRefHighlight string `json:"reference_highlight,omitzero"`
+	MarkDown         string     `json:"markdown,omitzero"`
+	PageRef          string     `json:"reference,omitzero"`
+	PageRefHighlight string     `json:"reference_highlight,omitzero"`
+	Provenance       Provenance `json:"provenance,omitzero"`
 }
 
 type noteListData struct {
@@ -289,6 +290,7 @@ func (n *Note) CellData(columnID int, data *CellData) {
 				data.Tooltip += "\n" + n.Source.String()
 			}
 		}
+		data.Tooltip = n.Provenance.AppendToTooltip(data.Tooltip)
 	}
 }
 
@@ -407,6 +409,7 @@ func (n *NoteSyncData) hash(h hash.Hash) {
 	xhash.StringWithLen(h, n.MarkDown)
 	xhash.StringWithLen(h, n.PageRef)
 	xhash.StringWithLen(h, n.PageRefHighlight)
+	n.Provenance.hash(h)
 }
 
 // CopyFrom implements node.EditorData.
@@ -0,0 +1,144 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/threat"
+	"github.com/richardwilkes/toolbox/v2/xrand"
+)
+
+// minimumNPCAttribute is the lowest an attribute will be lowered to when scaling an NPC down.
+var minimumNPCAttribute = fxp.Eight
+
+// npcStat is an attribute or skill that can be raised or lowered a level at a time when scaling an NPC.
+type npcStat struct {
+	raise   func() bool
+	lower   func() bool
+	restore func()
+}
+
+// NPCPoints returns the points an NPC of the given threat level should be built on, relative to the party's points.
+func NPCPoints(level threat.Level, partyPoints fxp.Int) fxp.Int {
+	var multiplier fxp.Int
+	switch level {
+	case threat.Fodder:
+		multiplier = fxp.Quarter
+	case threat.Minion:
+		multiplier = fxp.Half
+	case threat.Elite:
+		multiplier = fxp.OneAndAHalf
+	case threat.Boss:
+		multiplier = fxp.TwoAndAHalf
+	default:
+		multiplier = fxp.One
+	}
+	return partyPoints.Mul(multiplier).Floor()
+}
+
+// GenerateNPC creates a new NPC from the archetype template, making any choices it asks for at random, then scales it
+// to the target points.
+func GenerateNPC(archetype *Template, targetPoints fxp.Int, rnd xrand.Randomizer) *Entity {
+	e := GenerateCharacter(archetype, &GeneratorRules{RandomizeProfile: true}, rnd)
+	ScaleToPoints(e, targetPoints)
+	e.TotalPoints = targetPoints
+	return e
+}
+
+// ScaleToPoints raises or lowers ST, DX, and skill levels a level at a time, taking turns between them, until the
+// points spent come as close to the target as they can without going over.
+func ScaleToPoints(e *Entity, targetPoints fxp.Int) {
+	var stats []*npcStat
+	for _, id := range []string{StrengthID, DexterityID} {
+		if attr, ok := e.Attributes.Set[id]; ok && attr.AttributeDef() != nil {
+			stats = append(stats, attributeNPCStat(attr))
+		}
+	}
+	Traverse(func(s *Skill) bool {
+		stats = append(stats, skillNPCStat(s))
+		return false
+	}, false, true, e.Skills...)
+	spent := e.PointsBreakdown().Total()
+	if spent < targetPoints {
+		for len(stats) != 0 {
+			for i := 0; i < len(stats); {
+				stat := stats[i]
+				if stat.raise() {
+					if after := e.PointsBreakdown().Total(); after <= targetPoints {
+						spent = after
+						i++
+						continue
+					}
+					stat.restore()
+				}
+				stats = slices.Delete(stats, i, i+1)
+			}
+		}
+	} else {
+		slices.Reverse(stats)
+		for spent > targetPoints && len(stats) != 0 {
+			for i := 0; i < len(stats) && spent > targetPoints; {
+				if stats[i].lower() {
+					spent = e.PointsBreakdown().Total()
+					i++
+					continue
+				}
+				stats = slices.Delete(stats, i, i+1)
+			}
+		}
+	}
+	e.Recalculate()
+}
+
+func attributeNPCStat(attr *Attribute) *npcStat {
+	var saved fxp.Int
+	return &npcStat{
+		raise: func() bool {
+			saved = attr.Adjustment
+			attr.Adjustment += fxp.One
+			return true
+		},
+		lower: func() bool {
+			if attr.Maximum() <= minimumNPCAttribute {
+				return false
+			}
+			saved = attr.Adjustment
+			attr.Adjustment -= fxp.One
+			return true
+		},
+		restore: func() { attr.Adjustment = saved },
+	}
+}
+
+func skillNPCStat(s *Skill) *npcStat {
+	var saved fxp.Int
+	return &npcStat{
+		raise: func() bool {
+			saved = s.Points
+			s.IncrementSkillLevel()
+			return s.Points != saved
+		},
+		lower: func() bool {
+			if s.Points <= fxp.One {
+				return false
+			}
+			saved = s.Points
+			s.DecrementSkillLevel()
+			if s.Points < fxp.One {
+				s.SetRawPoints(saved)
+				return false
+			}
+			return s.Points != saved
+		},
+		restore: func() { s.SetRawPoints(saved) },
+	}
+}
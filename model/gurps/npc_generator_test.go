@@ -0,0 +1,51 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/threat"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestNPCPoints(t *testing.T) {
+	c := check.New(t)
+	c.Equal(fxp.FromInteger(37), NPCPoints(threat.Fodder, fxp.OneHundredFifty))
+	c.Equal(fxp.FromInteger(75), NPCPoints(threat.Minion, fxp.OneHundredFifty))
+	c.Equal(fxp.OneHundredFifty, NPCPoints(threat.Peer, fxp.OneHundredFifty))
+	c.Equal(fxp.FromInteger(225), NPCPoints(threat.Elite, fxp.OneHundredFifty))
+	c.Equal(fxp.FromInteger(375), NPCPoints(threat.Boss, fxp.OneHundredFifty))
+}
+
+func TestGenerateNPC(t *testing.T) {
+	c := check.New(t)
+	archetype := NewTemplate()
+	skill := NewSkill(nil, nil, false)
+	skill.Name = "Broadsword"
+	skill.Points = fxp.Four
+	archetype.Skills = []*Skill{skill}
+
+	e := GenerateNPC(archetype, fxp.Hundred, firstChoiceRandomizer{})
+	spent := e.PointsBreakdown().Total()
+	c.True(spent <= fxp.Hundred)
+	c.True(spent > fxp.Hundred-fxp.Ten)
+	c.Equal(fxp.Hundred, e.TotalPoints)
+	c.True(e.Attributes.Set[StrengthID].Maximum() > fxp.Ten)
+	c.True(e.Attributes.Set[DexterityID].Maximum() > fxp.Ten)
+	c.True(e.Skills[0].Points > fxp.Four)
+
+	skill.Points = fxp.Twenty
+	e = GenerateNPC(archetype, 0, firstChoiceRandomizer{})
+	c.True(e.PointsBreakdown().Total() <= 0)
+	c.True(e.Skills[0].Points >= fxp.One)
+	c.True(e.Skills[0].Points < fxp.Twenty)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// PageRefKeyUsage describes how often a page reference key is used.
+type PageRefKeyUsage struct {
+	Key   string
+	Count int
+}
+
+// ScanPageRefKeys returns the page reference keys used by the items within the library files found within the
+// directories, sorted by key.
+func ScanPageRefKeys(dirs ...string) []*PageRefKeyUsage {
+	counts := make(map[string]int)
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error { //nolint:errcheck // We want to continue on even if there was an error
+			if err != nil {
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") && p != dir {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if fi := FileInfoFor(p); fi != nil && len(fi.Extensions) != 0 {
+				countPageRefKeysInLibraryFile(counts, os.DirFS(filepath.Dir(p)), filepath.Base(p), fi.Extensions[0])
+			}
+			return nil
+		})
+	}
+	return PageRefKeyUsageFromCounts(counts)
+}
+
+// PageRefKeyUsageFromCounts converts a map of page reference keys to their counts into a list sorted by key.
+func PageRefKeyUsageFromCounts(counts map[string]int) []*PageRefKeyUsage {
+	list := make([]*PageRefKeyUsage, 0, len(counts))
+	for k, v := range counts {
+		list = append(list, &PageRefKeyUsage{
+			Key:   k,
+			Count: v,
+		})
+	}
+	slices.SortFunc(list, func(a, b *PageRefKeyUsage) int { return xstrings.NaturalCmp(a.Key, b.Key, true) })
+	return list
+}
+
+func countPageRefKeysInLibraryFile(counts map[string]int, dir fs.FS, file, ext string) {
+	switch ext {
+	case TraitsExt:
+		if data, err := NewTraitsFromFile(dir, file); err == nil {
+			CountPageRefKeys(counts, data...)
+		}
+	case TraitModifiersExt:
+		if data, err := NewTraitModifiersFromFile(dir, file); err == nil {
+			CountPageRefKeys(counts, data...)
+		}
+	case SkillsExt:
+		if data, err := NewSkillsFromFile(dir, file); err == nil {
+			CountPageRefKeys(counts, data...)
+		}
+	case SpellsExt:
+		if data, err := NewSpellsFromFile(dir, file); err == nil {
+			CountPageRefKeys(counts, data...)
+		}
+	case EquipmentExt:
+		if data, err := NewEquipmentFromFile(dir, file); err == nil {
+			CountPageRefKeys(counts, data...)
+		}
+	case EquipmentModifiersExt:
+		if data, err := NewEquipmentModifiersFromFile(dir, file); err == nil {
+			CountPageRefKeys(counts, data...)
+		}
+	case NotesExt:
+		if data, err := NewNotesFromFile(dir, file); err == nil {
+			CountPageRefKeys(counts, data...)
+		}
+	}
+}
+
+// CountPageRefKeys adds the page reference keys used by the nodes, along with their children and modifiers, to counts.
+func CountPageRefKeys[T NodeTypes](counts map[string]int, data ...T) {
+	Traverse(func(one T) bool {
+		var refs string
+		switch n := any(one).(type) {
+		case *Trait:
+			refs = n.PageRef
+			CountPageRefKeys(counts, n.Modifiers...)
+		case *TraitModifier:
+			refs = n.PageRef
+		case *Skill:
+			refs = n.PageRef
+		case *Spell:
+			refs = n.PageRef
+		case *Equipment:
+			refs = n.PageRef
+			CountPageRefKeys(counts, n.Modifiers...)
+		case *EquipmentModifier:
+			refs = n.PageRef
+		case *Note:
+			refs = n.PageRef
+		}
+		for _, ref := range ExtractPageReferences(refs) {
+			if key := PageRefKey(ref); key != "" {
+				counts[key]++
+			}
+		}
+		return false
+	}, false, false, data...)
+}
+
+// MatchPDFsToPageRefKeys attempts to match each of the PDF paths to one of the page reference keys, using the names
+// of the books the keys refer to. A name may contain several alternatives separated by " OR ". A path is matched to
+// the key whose name is the longest one found within the path's file name. Returns a map of keys to paths.
+func MatchPDFsToPageRefKeys(paths []string, names map[string]string) map[string]string {
+	matches := make(map[string]string)
+	for _, p := range paths {
+		base := normalizeForPageRefMatch(xfilepath.BaseName(p))
+		var bestKey string
+		bestLen := 0
+		for key, name := range names {
+			for _, alt := range strings.Split(name, " OR ") {
+				if alt = normalizeForPageRefMatch(alt); alt != "" && strings.Contains(base, alt) &&
+					(len(alt) > bestLen || (len(alt) == bestLen && key < bestKey)) {
+					bestKey = key
+					bestLen = len(alt)
+				}
+			}
+		}
+		if bestKey != "" {
+			if _, exists := matches[bestKey]; !exists {
+				matches[bestKey] = p
+			}
+		}
+	}
+	return matches
+}
+
+func normalizeForPageRefMatch(s string) string {
+	var buffer strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			buffer.WriteRune(r)
+		}
+	}
+	return buffer.String()
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestScanPageRefKeys(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	newTrait := func(ref string) *Trait {
+		trait := NewTrait(nil, nil, false)
+		trait.PageRef = ref
+		return trait
+	}
+	withModifier := newTrait("B30")
+	modifier := NewTraitModifier(nil, nil, false)
+	modifier.PageRef = "PU2"
+	withModifier.Modifiers = []*TraitModifier{modifier}
+	c.NoError(SaveTraits([]*Trait{newTrait("B20,MA12"), withModifier}, filepath.Join(dir, "One"+TraitsExt)))
+	c.NoError(SaveTraits([]*Trait{newTrait("MA40; https://example.com/page1")},
+		filepath.Join(dir, ".hidden", "Two"+TraitsExt)))
+	note := NewNote(nil, nil, false)
+	note.PageRef = "MA15"
+	c.NoError(SaveNotes([]*Note{note}, filepath.Join(dir, "sub", "Three"+NotesExt)))
+
+	usage := ScanPageRefKeys(dir)
+	c.Equal(3, len(usage))
+	c.Equal(PageRefKeyUsage{Key: "B", Count: 2}, *usage[0])
+	c.Equal(PageRefKeyUsage{Key: "MA", Count: 2}, *usage[1])
+	c.Equal(PageRefKeyUsage{Key: "PU", Count: 1}, *usage[2])
+}
+
+func TestMatchPDFsToPageRefKeys(t *testing.T) {
+	c := check.New(t)
+	names := map[string]string{
+		"B":   "Basic Set: Characters OR Basic Set: Campaigns",
+		"M":   "Magic",
+		"MDS": "Magic: Death Spells",
+		"MA":  "Martial Arts",
+	}
+	matches := MatchPDFsToPageRefKeys([]string{
+		"/books/GURPS Martial Arts.pdf",
+		"/books/gurps_magic_death_spells.pdf",
+		"/books/GURPS-Magic.pdf",
+		"/books/Basic Set - Campaigns.pdf",
+		"/books/Unrelated.pdf",
+	}, names)
+	c.Equal(map[string]string{
+		"B":   "/books/Basic Set - Campaigns.pdf",
+		"M":   "/books/GURPS-Magic.pdf",
+		"MDS": "/books/gurps_magic_death_spells.pdf",
+		"MA":  "/books/GURPS Martial Arts.pdf",
+	}, matches)
+}
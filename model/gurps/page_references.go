@@ -14,11 +14,13 @@ import (
 	"encoding/json/v2"
 	"io/fs"
 	"slices"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/jio"
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/xos"
 	"github.com/richardwilkes/toolbox/v2/xstrings"
+	"github.com/richardwilkes/unison"
 )
 
 // PageRefs holds a set of page references.
@@ -110,3 +112,93 @@ func (p *PageRefs) List() []*PageRef {
 	slices.SortFunc(list, func(a, b *PageRef) int { return xstrings.NaturalCmp(a.ID, b.ID, true) })
 	return list
 }
+
+// PageRefLink holds a page reference that refers to a location outside of the application.
+type PageRefLink struct {
+	Title string
+	URL   string
+}
+
+// ExtractPageReferences extracts any page references from the string. Separators within a markdown link, such as
+// "[SRD, Combat](https://example.com/srd)", are ignored.
+func ExtractPageReferences(s string) []string {
+	var list []string
+	depth := 0
+	start := 0
+	add := func(one string) {
+		if one = strings.TrimSpace(one); one != "" {
+			list = append(list, one)
+		}
+	}
+	for i, ch := range s {
+		switch ch {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',', ';':
+			if depth == 0 {
+				add(s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(s[start:])
+	return list
+}
+
+// ParsePageRefLink parses a page reference written as a markdown link, such as "[SRD](https://example.com/srd)",
+// returning the link's title and target. If the page reference isn't a markdown link, the title will be empty and the
+// target will be the page reference itself.
+func ParsePageRefLink(ref string) (title, target string) {
+	if strings.HasPrefix(ref, "[") && strings.HasSuffix(ref, ")") {
+		if i := strings.Index(ref, "]("); i != -1 {
+			return strings.TrimSpace(ref[1:i]), strings.TrimSpace(ref[i+2 : len(ref)-1])
+		}
+	}
+	return "", ref
+}
+
+// PageRefLinks returns the page references within the string that refer to URLs. Links without a title are given
+// their URL as the title.
+func PageRefLinks(s string) []PageRefLink {
+	var links []PageRefLink
+	for _, ref := range ExtractPageReferences(s) {
+		title, target := ParsePageRefLink(ref)
+		if unison.HasURLPrefix(target) {
+			if title == "" {
+				title = target
+			}
+			links = append(links, PageRefLink{
+				Title: title,
+				URL:   target,
+			})
+		}
+	}
+	return links
+}
+
+// SplitPageRef splits a page reference, such as "B161", into its key and page number.
+func SplitPageRef(ref string) (key, page string) {
+	i := len(ref)
+	for i > 0 && ref[i-1] >= '0' && ref[i-1] <= '9' {
+		i--
+	}
+	return ref[:i], ref[i:]
+}
+
+// PageRefKey returns the key of the page reference, such as "B" for "B161". Returns an empty string if the page
+// reference doesn't refer to a page within a PDF, such as for URLs and markdown references.
+func PageRefKey(ref string) string {
+	_, ref = ParsePageRefLink(ref)
+	if unison.HasURLPrefix(ref) || strings.HasPrefix(ref, "md:") {
+		return ""
+	}
+	key, page := SplitPageRef(ref)
+	if page == "" {
+		return ""
+	}
+	return key
+}
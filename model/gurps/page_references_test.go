@@ -0,0 +1,45 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestPageRefKeys(t *testing.T) {
+	c := check.New(t)
+	c.Equal([]string{"B161", "MA12"}, ExtractPageReferences(" B161, MA12; "))
+	key, page := SplitPageRef("MA12")
+	c.Equal("MA", key)
+	c.Equal("12", page)
+	c.Equal("B", PageRefKey("B161"))
+	c.Equal("", PageRefKey("B"))
+	c.Equal("", PageRefKey("https://example.com/page1"))
+	c.Equal("", PageRefKey("[SRD](https://example.com/page1)"))
+	c.Equal("", PageRefKey("md:Notes"))
+}
+
+func TestPageRefLinks(t *testing.T) {
+	c := check.New(t)
+	c.Equal([]string{"B161", "[SRD, Combat](https://example.com/srd?a=1,2)", "md:Notes"},
+		ExtractPageReferences("B161, [SRD, Combat](https://example.com/srd?a=1,2); md:Notes"))
+	title, target := ParsePageRefLink("[ SRD ](https://example.com/srd)")
+	c.Equal("SRD", title)
+	c.Equal("https://example.com/srd", target)
+	title, target = ParsePageRefLink("B161")
+	c.Equal("", title)
+	c.Equal("B161", target)
+	c.Equal([]PageRefLink{
+		{Title: "SRD", URL: "https://example.com/srd"},
+		{Title: "https://example.com/wiki", URL: "https://example.com/wiki"},
+	}, PageRefLinks("B161, [SRD](https://example.com/srd), https://example.com/wiki, [Notes](md:Notes)"))
+}
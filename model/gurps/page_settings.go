@@ -83,6 +83,12 @@ type PageInfoProvider interface {
 	ListProvider
 }
 
+// PageScaler is an optional interface a PageInfoProvider may implement to have the content of its pages drawn at
+// something other than their normal size.
+type PageScaler interface {
+	PageScale() float32
+}
+
 // PaperSize holds details about a standard paper size.
 type PaperSize struct {
 	Name   string
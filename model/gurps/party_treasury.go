@@ -0,0 +1,85 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+// CurrencyTag is the tag used to identify the equipment that holds a character's money.
+const CurrencyTag = "Currency"
+
+// SplitCurrency divides the amount into the given number of shares. Shares are whole cents; any cents left over after
+// an even split are handed out one per share, starting with the first, so that the shares always add up to the amount.
+func SplitCurrency(amount fxp.Int, shares int) []fxp.Int {
+	if shares < 1 {
+		return nil
+	}
+	cents := fxp.AsInteger[int64](amount.Div(fxp.OneHundredth))
+	count := int64(shares)
+	base := cents / count
+	remainder := cents % count
+	result := make([]fxp.Int, shares)
+	var total fxp.Int
+	for i := range result {
+		share := base
+		if int64(i) < remainder {
+			share++
+		}
+		result[i] = fxp.FromInteger(share).Mul(fxp.OneHundredth)
+		total += result[i]
+	}
+	result[0] += amount - total
+	return result
+}
+
+// GiveEquipment adds copies of the equipment to the entity's carried equipment. from is the library the equipment came
+// from, if any.
+func GiveEquipment(e *Entity, from LibraryFile, equipment []*Equipment) {
+	list := make([]*Equipment, 0, len(equipment))
+	for _, one := range equipment {
+		list = append(list, one.Clone(from, e, nil, false))
+	}
+	e.CarriedEquipment = append(e.CarriedEquipment, list...)
+	e.AdoptEquipment(list...)
+}
+
+// AddCurrency adds the amount to the entity's money. Money is held in the first top-level carried equipment tagged
+// with CurrencyTag, which is created if it doesn't yet exist. Each unit has a value of 1, so the quantity is the amount
+// of money held.
+func AddCurrency(e *Entity, amount fxp.Int) {
+	money := CurrencyEquipment(e)
+	if money == nil {
+		money = NewEquipment(e, nil, false)
+		money.Name = i18n.Text("Money")
+		money.Tags = []string{CurrencyTag}
+		money.BaseValue = "1"
+		money.Quantity = 0
+		e.CarriedEquipment = append(e.CarriedEquipment, money)
+	}
+	money.Quantity += amount
+	e.Recalculate()
+}
+
+// CurrencyEquipment returns the equipment that holds the entity's money, or nil if there isn't any.
+func CurrencyEquipment(e *Entity) *Equipment {
+	for _, one := range e.CarriedEquipment {
+		if !one.Container() && slices.ContainsFunc(one.Tags, func(tag string) bool {
+			return strings.EqualFold(tag, CurrencyTag)
+		}) {
+			return one
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSplitCurrency(t *testing.T) {
+	c := check.New(t)
+	c.Equal(0, len(SplitCurrency(fxp.FromInteger(100), 0)))
+	shares := SplitCurrency(fxp.FromInteger(100), 3)
+	c.Equal(3, len(shares))
+	c.Equal(fxp.FromStringForced("33.34"), shares[0])
+	c.Equal(fxp.FromStringForced("33.33"), shares[1])
+	c.Equal(fxp.FromStringForced("33.33"), shares[2])
+	shares = SplitCurrency(fxp.FromInteger(90), 3)
+	for _, share := range shares {
+		c.Equal(fxp.FromInteger(30), share)
+	}
+}
+
+func TestAddCurrency(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	AddCurrency(e, fxp.FromInteger(25))
+	money := CurrencyEquipment(e)
+	c.NotNil(money)
+	c.Equal(fxp.FromInteger(25), money.Quantity)
+	AddCurrency(e, fxp.FromInteger(10))
+	c.Equal(1, len(e.CarriedEquipment))
+	c.Equal(fxp.FromInteger(35), money.Quantity)
+	c.Equal(fxp.FromInteger(35), money.ExtendedValue())
+}
+
+func TestGiveEquipment(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	item := NewEquipment(nil, nil, false)
+	item.BaseWeight = "2 lb"
+	item.Quantity = fxp.FromInteger(3)
+	GiveEquipment(e, LibraryFile{}, []*Equipment{item})
+	c.Equal(1, len(e.CarriedEquipment))
+	c.Equal(e, e.CarriedEquipment[0].DataOwner().OwningEntity())
+	c.Equal(fxp.WeightFromInteger(6, fxp.Pound), e.WeightCarried(false))
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+// PDFHighlight holds a highlighted area on a page of a PDF, along with an optional note. The area is stored as
+// fractions of the page's width and height, so that it doesn't depend on the resolution the page was rendered at.
+type PDFHighlight struct {
+	Note   string  `json:"note,omitzero"`
+	Page   int     `json:"page"`
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+// NewPDFHighlight creates a new highlight for the area of the page, which has the given size. The area is clipped to
+// the page. Returns nil if the page has no size.
+func NewPDFHighlight(page int, area geom.Rect, pageSize geom.Size) *PDFHighlight {
+	if pageSize.Width <= 0 || pageSize.Height <= 0 {
+		return nil
+	}
+	area = area.Intersect(geom.Rect{Size: pageSize})
+	return &PDFHighlight{
+		Page:   page,
+		X:      area.X / pageSize.Width,
+		Y:      area.Y / pageSize.Height,
+		Width:  area.Width / pageSize.Width,
+		Height: area.Height / pageSize.Height,
+	}
+}
+
+// Rect returns the area of the highlight on a page of the given size.
+func (h *PDFHighlight) Rect(pageSize geom.Size) geom.Rect {
+	return geom.NewRect(h.X*pageSize.Width, h.Y*pageSize.Height, h.Width*pageSize.Width, h.Height*pageSize.Height)
+}
+
+// HighlightsForPage returns the highlights on the given page.
+func (p *PDFInfo) HighlightsForPage(page int) []*PDFHighlight {
+	var list []*PDFHighlight
+	for _, one := range p.Highlights {
+		if one.Page == page {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+// HighlightAt returns the most recently added highlight on the page that contains the point, or nil.
+func (p *PDFInfo) HighlightAt(page int, pt geom.Point, pageSize geom.Size) *PDFHighlight {
+	for i := len(p.Highlights) - 1; i >= 0; i-- {
+		if one := p.Highlights[i]; one.Page == page && pt.In(one.Rect(pageSize)) {
+			return one
+		}
+	}
+	return nil
+}
+
+// AddHighlight adds a highlight. Highlights without an area are ignored.
+func (p *PDFInfo) AddHighlight(highlight *PDFHighlight) {
+	if highlight != nil && highlight.Width > 0 && highlight.Height > 0 {
+		p.Highlights = append(p.Highlights, highlight)
+	}
+}
+
+// RemoveHighlight removes a highlight.
+func (p *PDFInfo) RemoveHighlight(highlight *PDFHighlight) {
+	p.Highlights = slices.DeleteFunc(p.Highlights, func(one *PDFHighlight) bool { return one == highlight })
+}
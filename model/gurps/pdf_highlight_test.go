@@ -0,0 +1,48 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+func TestPDFHighlights(t *testing.T) {
+	c := check.New(t)
+	pageSize := geom.NewSize(200, 400)
+	h := NewPDFHighlight(3, geom.NewRect(50, 100, 100, 40), pageSize)
+	c.Equal(float32(0.25), h.X)
+	c.Equal(float32(0.25), h.Y)
+	c.Equal(float32(0.5), h.Width)
+	c.Equal(float32(0.1), h.Height)
+
+	// The same highlight maps onto a page rendered at twice the resolution
+	c.Equal(geom.NewRect(100, 200, 200, 80), h.Rect(pageSize.Mul(2)))
+
+	// Areas are clipped to the page
+	clipped := NewPDFHighlight(0, geom.NewRect(150, -20, 100, 40), pageSize)
+	c.Equal(geom.NewRect(150, 0, 50, 20), clipped.Rect(pageSize))
+	c.Nil(NewPDFHighlight(0, geom.NewRect(0, 0, 10, 10), geom.Size{}))
+
+	var info PDFInfo
+	info.AddHighlight(h)
+	info.AddHighlight(clipped)
+	info.AddHighlight(NewPDFHighlight(3, geom.NewRect(500, 500, 10, 10), pageSize)) // Entirely off the page
+	c.Equal(2, len(info.Highlights))
+	c.Equal(1, len(info.HighlightsForPage(3)))
+	c.Equal(0, len(info.HighlightsForPage(1)))
+	c.True(info.HighlightAt(3, geom.NewPoint(60, 110), pageSize) == h)
+	c.Nil(info.HighlightAt(0, geom.NewPoint(60, 110), pageSize))
+	info.RemoveHighlight(h)
+	c.Equal(1, len(info.Highlights))
+	c.Nil(info.HighlightAt(3, geom.NewPoint(60, 110), pageSize))
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// Plugin constants.
+const (
+	PluginManifestName = "plugin.json"
+	PluginTimeout      = 2 * time.Minute
+)
+
+// Plugin commands. A plugin's executable is invoked with one of these as its first argument:
+//
+//	export <exporter id> <sheet.gcs> <output path>  writes the sheet to the output path in the exporter's format
+//	import <importer id> <input path> <sheet.gcs>   converts the input file into a GCS sheet
+//	panel <panel id> <sheet.gcs>                    writes markdown describing the sheet to stdout
+//
+// The executable is run with its working directory set to the plugin's directory. A non-zero exit status is treated as
+// a failure and anything written to stderr is reported to the user.
+const (
+	PluginExportCmd = "export"
+	PluginImportCmd = "import"
+	PluginPanelCmd  = "panel"
+)
+
+// PluginExporter describes an export format provided by a plugin.
+type PluginExporter struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Extension string `json:"extension"`
+}
+
+// PluginImporter describes an import format provided by a plugin.
+type PluginImporter struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Extensions []string `json:"extensions"`
+}
+
+// PluginPanel describes a sheet panel provided by a plugin.
+type PluginPanel struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Plugin holds the manifest of a third-party plugin.
+type Plugin struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version,omitzero"`
+	Command   string            `json:"command"`
+	Exporters []*PluginExporter `json:"exporters,omitzero"`
+	Importers []*PluginImporter `json:"importers,omitzero"`
+	Panels    []*PluginPanel    `json:"panels,omitzero"`
+	dir       string
+}
+
+// DiscoverPlugins looks for plugins in the immediate sub-directories of dir. Each plugin must have a manifest file
+// named "plugin.json". Plugins with invalid manifests are logged and skipped. The result is sorted by name.
+func DiscoverPlugins(dir string) []*Plugin {
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			errs.Log(err, "dir", dir)
+		}
+		return nil
+	}
+	var list []*Plugin
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		pluginDir := filepath.Join(dir, entry.Name())
+		var p Plugin
+		if err = jio.Load(nil, filepath.Join(pluginDir, PluginManifestName), &p); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				errs.Log(err, "plugin", pluginDir)
+			}
+			continue
+		}
+		p.dir = pluginDir
+		if err = p.Validate(); err != nil {
+			errs.Log(err, "plugin", pluginDir)
+			continue
+		}
+		list = append(list, &p)
+	}
+	slices.SortFunc(list, func(a, b *Plugin) int { return xstrings.NaturalCmp(a.Name, b.Name, true) })
+	return list
+}
+
+// Validate checks the plugin's manifest for problems.
+func (p *Plugin) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errs.New("plugin name is missing")
+	}
+	if strings.TrimSpace(p.Command) == "" {
+		return errs.New("plugin command is missing")
+	}
+	for _, one := range p.Exporters {
+		if one == nil || one.ID == "" || one.Extension == "" {
+			return errs.New("plugin exporters require an id and extension")
+		}
+	}
+	for _, one := range p.Importers {
+		if one == nil || one.ID == "" || len(one.Extensions) == 0 {
+			return errs.New("plugin importers require an id and at least one extension")
+		}
+	}
+	for _, one := range p.Panels {
+		if one == nil || one.ID == "" {
+			return errs.New("plugin panels require an id")
+		}
+	}
+	return nil
+}
+
+// Dir returns the directory the plugin was loaded from.
+func (p *Plugin) Dir() string {
+	return p.dir
+}
+
+// CommandPath returns the path to the plugin's executable. Relative paths are resolved against the plugin's directory.
+func (p *Plugin) CommandPath() string {
+	if filepath.IsAbs(p.Command) {
+		return p.Command
+	}
+	return filepath.Join(p.dir, p.Command)
+}
+
+// PluginSheetData returns the entity in the form handed to plugins. Since the entity may only be used from the UI
+// thread, this should be called there before running the plugin from another goroutine.
+func PluginSheetData(entity *Entity) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := jio.Save(&buffer, entity); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Export writes the sheet data, as returned by PluginSheetData(), to outPath using the given exporter.
+func (p *Plugin) Export(ctx context.Context, sheetData []byte, exporterID, outPath string) error {
+	return p.withSheetFile(sheetData, func(sheetPath string) error {
+		_, err := p.run(ctx, PluginExportCmd, exporterID, sheetPath, outPath)
+		return err
+	})
+}
+
+// Import converts the file at inPath into an entity using the given importer.
+func (p *Plugin) Import(ctx context.Context, importerID, inPath string) (*Entity, error) {
+	tmpDir, err := os.MkdirTemp("", "gcs_plugin_*")
+	if err != nil {
+		return nil, errs.NewWithCause("unable to create temporary directory", err)
+	}
+	defer removePluginTempDir(tmpDir)
+	sheetPath := filepath.Join(tmpDir, "import"+SheetExt)
+	if _, err = p.run(ctx, PluginImportCmd, importerID, inPath, sheetPath); err != nil {
+		return nil, err
+	}
+	var entity *Entity
+	if entity, err = NewEntityFromFile(os.DirFS(tmpDir), filepath.Base(sheetPath)); err != nil {
+		return nil, err
+	}
+	entity.Recalculate()
+	return entity, nil
+}
+
+// Panel returns the markdown content the given panel produces for the sheet data, as returned by PluginSheetData().
+func (p *Plugin) Panel(ctx context.Context, sheetData []byte, panelID string) (string, error) {
+	var content string
+	err := p.withSheetFile(sheetData, func(sheetPath string) error {
+		out, err := p.run(ctx, PluginPanelCmd, panelID, sheetPath)
+		content = string(out)
+		return err
+	})
+	return content, err
+}
+
+func (p *Plugin) withSheetFile(sheetData []byte, f func(sheetPath string) error) error {
+	tmpDir, err := os.MkdirTemp("", "gcs_plugin_*")
+	if err != nil {
+		return errs.NewWithCause("unable to create temporary directory", err)
+	}
+	defer removePluginTempDir(tmpDir)
+	sheetPath := filepath.Join(tmpDir, "sheet"+SheetExt)
+	if err = os.WriteFile(sheetPath, sheetData, 0o640); err != nil {
+		return errs.Wrap(err)
+	}
+	return f(sheetPath)
+}
+
+func removePluginTempDir(dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		errs.Log(errs.NewWithCause("unable to remove the temporary plugin data", err), "dir", dir)
+	}
+}
+
+// run runs the plugin's executable with the arguments, stopping it if ctx is canceled or PluginTimeout elapses.
+func (p *Plugin) run(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, PluginTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.CommandPath(), args...)
+	cmd.Dir = p.dir
+	// Don't wait indefinitely for any processes the plugin started to release its output once it has been stopped.
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, errs.Wrap(ctxErr)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errs.NewWithCause(msg, err)
+		}
+		return nil, errs.Wrap(err)
+	}
+	return stdout.Bytes(), nil
+}
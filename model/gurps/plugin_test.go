@@ -0,0 +1,98 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestDiscoverPlugins(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	write := func(sub, content string) {
+		p := filepath.Join(dir, sub)
+		c.NoError(os.MkdirAll(p, 0o750))
+		c.NoError(os.WriteFile(filepath.Join(p, PluginManifestName), []byte(content), 0o640))
+	}
+	write("zeta", `{"name":"Zeta","command":"zeta","panels":[{"id":"summary","title":"Summary"}]}`)
+	write("alpha", `{"name":"Alpha","command":"/usr/bin/alpha",
+"exporters":[{"id":"txt","title":"Text","extension":".txt"}],
+"importers":[{"id":"xml","title":"XML","extensions":[".xml"]}]}`)
+	write("broken", `{"name":"Broken"}`)
+	write(".hidden", `{"name":"Hidden","command":"hidden"}`)
+	c.NoError(os.MkdirAll(filepath.Join(dir, "empty"), 0o750))
+
+	plugins := DiscoverPlugins(dir)
+	c.Equal(2, len(plugins))
+	c.Equal("Alpha", plugins[0].Name)
+	c.Equal("/usr/bin/alpha", plugins[0].CommandPath())
+	c.Equal(1, len(plugins[0].Exporters))
+	c.Equal(".txt", plugins[0].Exporters[0].Extension)
+	c.Equal([]string{".xml"}, plugins[0].Importers[0].Extensions)
+	c.Equal("Zeta", plugins[1].Name)
+	c.Equal(filepath.Join(dir, "zeta", "zeta"), plugins[1].CommandPath())
+	c.Equal("summary", plugins[1].Panels[0].ID)
+
+	c.Equal(0, len(DiscoverPlugins("")))
+	c.Equal(0, len(DiscoverPlugins(filepath.Join(dir, "missing"))))
+}
+
+func TestPluginPanel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	c := check.New(t)
+	dir := t.TempDir()
+	c.NoError(os.WriteFile(filepath.Join(dir, "run.sh"), []byte(`#!/bin/sh
+if [ "$1" = "panel" ] && [ -f "$3" ]; then
+	echo "# $2"
+	exit 0
+fi
+echo "bad request" >&2
+exit 1
+`), 0o750))
+	p := &Plugin{Name: "Test", Command: "run.sh", dir: dir}
+	data, err := PluginSheetData(NewEntity())
+	c.NoError(err)
+	content, err := p.Panel(context.Background(), data, "summary")
+	c.NoError(err)
+	c.Equal("# summary\n", content)
+
+	err = p.Export(context.Background(), data, "txt", filepath.Join(dir, "out.txt"))
+	c.HasError(err)
+	c.Contains(err.Error(), "bad request")
+}
+
+func TestPluginCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	c := check.New(t)
+	dir := t.TempDir()
+	c.NoError(os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o750))
+	p := &Plugin{Name: "Test", Command: "run.sh", dir: dir}
+	data, err := PluginSheetData(NewEntity())
+	c.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = p.Panel(ctx, data, "summary")
+	c.HasError(err)
+	c.True(errors.Is(err, context.Canceled))
+	c.True(time.Since(start) < 10*time.Second)
+}
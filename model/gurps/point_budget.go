@@ -0,0 +1,46 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+)
+
+// PointBudget holds the point targets a player has planned for each category of a character. A target of zero means
+// no target has been set for that category. Targets for disadvantages are normally negative, matching the way their
+// points are reported.
+type PointBudget struct {
+	Attributes    fxp.Int `json:"attributes,omitzero"`
+	Advantages    fxp.Int `json:"advantages,omitzero"`
+	Disadvantages fxp.Int `json:"disadvantages,omitzero"`
+	Skills        fxp.Int `json:"skills,omitzero"`
+}
+
+// IsZero returns true if no targets have been set.
+func (b PointBudget) IsZero() bool {
+	return b == PointBudget{}
+}
+
+// PointBudgetExceeded returns true if a target has been set and the points spent exceed it. The magnitudes are
+// compared, so that a target of -40 for disadvantages is exceeded by -50.
+func PointBudgetExceeded(spent, target fxp.Int) bool {
+	return target != 0 && spent.Abs() > target.Abs()
+}
+
+// PointBudgetProgress returns how much of the target has been spent, from 0 to 1. Returns 0 if no target has been set.
+func PointBudgetProgress(spent, target fxp.Int) float64 {
+	if target == 0 {
+		return 0
+	}
+	if spent.Abs() >= target.Abs() {
+		return 1
+	}
+	return fxp.AsFloat[float64](spent.Abs()) / fxp.AsFloat[float64](target.Abs())
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestPointBudget(t *testing.T) {
+	c := check.New(t)
+	c.True(PointBudget{}.IsZero())
+	c.False(PointBudget{Skills: fxp.Ten}.IsZero())
+
+	c.False(PointBudgetExceeded(fxp.Hundred, 0))
+	c.False(PointBudgetExceeded(fxp.Ten, fxp.Ten))
+	c.True(PointBudgetExceeded(fxp.Eleven, fxp.Ten))
+	c.False(PointBudgetExceeded(-fxp.Ten, -fxp.Twenty))
+	c.True(PointBudgetExceeded(-fxp.Thirty, -fxp.Twenty))
+
+	c.Equal(0.0, PointBudgetProgress(fxp.Ten, 0))
+	c.Equal(0.5, PointBudgetProgress(fxp.Ten, fxp.Twenty))
+	c.Equal(0.5, PointBudgetProgress(-fxp.Ten, -fxp.Twenty))
+	c.Equal(1.0, PointBudgetProgress(fxp.Thirty, fxp.Twenty))
+}
@@ -14,11 +14,58 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/jio"
 )
 
-// PointsRecord holds information about when and why points were adjusted.
+// PointsRecord holds information about when and why points were adjusted. Records are awards unless Spend is set, in
+// which case they note points that were spent rather than earned and do not contribute to the total points.
 type PointsRecord struct {
 	When   jio.Time `json:"when"`
 	Points fxp.Int  `json:"points"`
 	Reason string   `json:"reason,omitzero"`
+	Spend  bool     `json:"spend,omitzero"`
+}
+
+// AdvancementSummary holds the earned vs. spent totals for a character.
+type AdvancementSummary struct {
+	// Earned is the total of the awards in the points record.
+	Earned fxp.Int
+	// Spent is the number of points actually spent on the character.
+	Spent fxp.Int
+	// LoggedSpent is the total of the spends noted in the points record.
+	LoggedSpent fxp.Int
+}
+
+// SummarizeAdvancement creates a summary of the points record, reconciled against the points actually spent.
+func SummarizeAdvancement(record []*PointsRecord, spent fxp.Int) AdvancementSummary {
+	summary := AdvancementSummary{Spent: spent}
+	for _, rec := range record {
+		if rec.Spend {
+			summary.LoggedSpent += rec.Points
+		} else {
+			summary.Earned += rec.Points
+		}
+	}
+	return summary
+}
+
+// Unspent returns the number of points earned but not yet spent.
+func (s AdvancementSummary) Unspent() fxp.Int {
+	return s.Earned - s.Spent
+}
+
+// Unlogged returns the number of points that have been spent without a corresponding spend in the points record. A
+// negative value means more spends were logged than were actually made.
+func (s AdvancementSummary) Unlogged() fxp.Int {
+	return s.Spent - s.LoggedSpent
+}
+
+// TotalAwardedPoints returns the total of the awards in the record.
+func TotalAwardedPoints(record []*PointsRecord) fxp.Int {
+	var total fxp.Int
+	for _, rec := range record {
+		if !rec.Spend {
+			total += rec.Points
+		}
+	}
+	return total
 }
 
 // ClonePointsRecordList creates a clone of the provided PointsRecord list.
@@ -0,0 +1,32 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSummarizeAdvancement(t *testing.T) {
+	c := check.New(t)
+	record := []*PointsRecord{
+		{Points: fxp.Hundred, Reason: "Initial points"},
+		{Points: fxp.Five, Reason: "Session 1"},
+		{Points: fxp.Four, Reason: "Raised Stealth", Spend: true},
+	}
+	c.Equal(fxp.FromInteger(105), TotalAwardedPoints(record))
+	summary := SummarizeAdvancement(record, fxp.Hundred)
+	c.Equal(fxp.FromInteger(105), summary.Earned)
+	c.Equal(fxp.Four, summary.LoggedSpent)
+	c.Equal(fxp.Five, summary.Unspent())
+	c.Equal(fxp.FromInteger(96), summary.Unlogged())
+}
@@ -30,10 +30,18 @@ type PoolThreshold struct {
 
 // PoolThresholdData holds the data that will be serialized for the PoolThreshold.
 type PoolThresholdData struct {
-	State       string         `json:"state"`
-	Value       string         `json:"value"`
-	Explanation string         `json:"explanation,omitzero"`
-	Ops         []threshold.Op `json:"ops,omitzero"`
+	State       string                 `json:"state"`
+	Value       string                 `json:"value"`
+	Explanation string                 `json:"explanation,omitzero"`
+	Ops         []threshold.Op         `json:"ops,omitzero"`
+	Effects     []*PoolThresholdEffect `json:"effects,omitzero"`
+}
+
+// PoolThresholdEffect holds an adjustment to an attribute that is applied while its PoolThreshold is in effect.
+type PoolThresholdEffect struct {
+	AttrID    string  `json:"attr_id"`
+	Amount    fxp.Int `json:"amount"`
+	KeyPrefix string  `json:"-"`
 }
 
 // MarshalJSONTo implements json.MarshalerTo.
@@ -113,6 +121,13 @@ func (p *PoolThreshold) Clone() *PoolThreshold {
 		clone.Ops = make([]threshold.Op, len(p.Ops))
 		copy(clone.Ops, p.Ops)
 	}
+	if p.Effects != nil {
+		clone.Effects = make([]*PoolThresholdEffect, len(p.Effects))
+		for i, one := range p.Effects {
+			effect := *one
+			clone.Effects[i] = &effect
+		}
+	}
 	return &clone
 }
 
@@ -155,6 +170,11 @@ func (p *PoolThreshold) Hash(h hash.Hash) {
 	for _, one := range p.Ops {
 		xhash.Num8(h, one)
 	}
+	xhash.Num64(h, len(p.Effects))
+	for _, one := range p.Effects {
+		xhash.StringWithLen(h, one.AttrID)
+		xhash.Num64(h, one.Amount)
+	}
 }
 
 func (p *PoolThreshold) String() string {
@@ -0,0 +1,151 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/power"
+	"github.com/richardwilkes/toolbox/v2/xbytes"
+)
+
+// PowerGroup holds the abilities and Talents that draw upon a single power source.
+type PowerGroup struct {
+	Source    power.Source
+	Abilities []*Trait
+	Talents   []*Trait
+}
+
+// NewPowerModifier creates the power modifier for abilities drawing upon the given source, or returns nil if the source
+// doesn't have one.
+func NewPowerModifier(source power.Source) *TraitModifier {
+	percent := source.Modifier()
+	if percent == 0 {
+		return nil
+	}
+	mod := NewTraitModifier(nil, nil, false)
+	mod.Name = source.String()
+	mod.CostAdj = fmt.Sprintf("%+d%%", percent)
+	return mod
+}
+
+// WithPowerModifier returns the modifiers with the power modifier for the source added, unless the source doesn't have
+// one or the modifiers already contain one with the source's name. The modifiers passed in are not altered.
+func WithPowerModifier(modifiers []*TraitModifier, source power.Source) []*TraitModifier {
+	if mod := powerModifierFor(modifiers, source); mod != nil {
+		return append(slices.Clip(modifiers), mod)
+	}
+	return modifiers
+}
+
+func powerModifierFor(modifiers []*TraitModifier, source power.Source) *TraitModifier {
+	if source == power.None {
+		return nil
+	}
+	found := false
+	Traverse(func(mod *TraitModifier) bool {
+		found = strings.EqualFold(mod.NameWithReplacements(), source.String())
+		return found
+	}, false, true, modifiers...)
+	if found {
+		return nil
+	}
+	return NewPowerModifier(source)
+}
+
+// EffectivePowerSource returns the power source of this trait, or the one inherited from its closest parent that has
+// one.
+func (t *Trait) EffectivePowerSource() power.Source {
+	for p := t; p != nil; p = p.parent {
+		if p.PowerSource != power.None {
+			return p.PowerSource
+		}
+	}
+	return power.None
+}
+
+// TalentLevel returns the level of this trait when used as a power Talent. Talents without levels count as level 1.
+func (t *Trait) TalentLevel() fxp.Int {
+	if t.IsLeveled() {
+		return t.CurrentLevel()
+	}
+	return fxp.One
+}
+
+// powerTalentBonusFor returns the bonus from the entity's Talents to an ability with the given power source and tags.
+// An ability is related to a Talent when its power source or one of its tags matches the name of the Talent's source.
+func (e *Entity) powerTalentBonusFor(powerSource string, tags []string, tooltip *xbytes.InsertBuffer) fxp.Int {
+	var total fxp.Int
+	Traverse(func(t *Trait) bool {
+		if t.PowerTalent {
+			if source := t.EffectivePowerSource(); source != power.None &&
+				(strings.EqualFold(powerSource, source.String()) || HasTag(source.String(), tags)) {
+				level := t.TalentLevel()
+				total += level
+				if tooltip != nil {
+					fmt.Fprintf(tooltip, "\n%s [%s]", t.String(), level.StringWithSign())
+				}
+			}
+		}
+		return false
+	}, true, true, e.Traits...)
+	return total
+}
+
+// PowersBySource returns the entity's enabled abilities and Talents that have a power source, grouped by that source.
+func (e *Entity) PowersBySource() []*PowerGroup {
+	groups := make(map[power.Source]*PowerGroup)
+	Traverse(func(t *Trait) bool {
+		if source := t.EffectivePowerSource(); source != power.None {
+			group, ok := groups[source]
+			if !ok {
+				group = &PowerGroup{Source: source}
+				groups[source] = group
+			}
+			if t.PowerTalent {
+				group.Talents = append(group.Talents, t)
+			} else {
+				group.Abilities = append(group.Abilities, t)
+			}
+		}
+		return false
+	}, true, true, e.Traits...)
+	list := make([]*PowerGroup, 0, len(groups))
+	for _, source := range power.Sources {
+		if group, ok := groups[source]; ok {
+			list = append(list, group)
+		}
+	}
+	return list
+}
+
+// Points returns the total points spent on the group's abilities and Talents.
+func (g *PowerGroup) Points() fxp.Int {
+	var total fxp.Int
+	for _, one := range g.Abilities {
+		total += one.AdjustedPoints()
+	}
+	for _, one := range g.Talents {
+		total += one.AdjustedPoints()
+	}
+	return total
+}
+
+// TalentLevel returns the total level of the group's Talents.
+func (g *PowerGroup) TalentLevel() fxp.Int {
+	var total fxp.Int
+	for _, one := range g.Talents {
+		total += one.TalentLevel()
+	}
+	return total
+}
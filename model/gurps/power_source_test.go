@@ -0,0 +1,67 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/power"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestPowerSources(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	magic := NewTrait(e, nil, true)
+	magic.Name = "Wizardry"
+	magic.PowerSource = power.Magical
+	bolt := NewTrait(e, magic, false)
+	bolt.Name = "Innate Attack"
+	bolt.BasePoints = fxp.Twenty
+	talent := NewTrait(e, magic, false)
+	talent.Name = "Magical Talent"
+	talent.PowerTalent = true
+	talent.CanLevel = true
+	talent.Levels = fxp.Two
+	talent.PointsPerLevel = fxp.Five
+	magic.Children = []*Trait{bolt, talent}
+	psi := NewTrait(e, nil, false)
+	psi.Name = "Telekinesis"
+	psi.BasePoints = fxp.Ten
+	psi.PowerSource = power.Psionic
+	psi.Modifiers = []*TraitModifier{NewPowerModifier(power.Psionic)}
+	e.Traits = []*Trait{magic, psi}
+
+	c.Equal(power.Magical, bolt.EffectivePowerSource())
+	c.Equal(fxp.FromInteger(18), bolt.AdjustedPoints())
+	c.Equal(fxp.Ten, talent.AdjustedPoints())
+	c.Equal(fxp.Nine, psi.AdjustedPoints())
+	c.Contains(magic.ModifierNotes(), "Magical")
+
+	spellcraft := NewSkill(e, nil, false)
+	spellcraft.Name = "Spellcraft"
+	spellcraft.Points = fxp.One
+	spellcraft.Tags = []string{"Magical"}
+	other := NewSkill(e, nil, false)
+	other.Name = "Other"
+	other.Points = fxp.One
+	e.Skills = []*Skill{spellcraft, other}
+	e.Recalculate()
+	c.Equal(other.LevelData.Level+fxp.Two, spellcraft.LevelData.Level)
+
+	groups := e.PowersBySource()
+	c.Equal(2, len(groups))
+	c.Equal(power.Magical, groups[0].Source)
+	c.Equal([]*Trait{bolt}, groups[0].Abilities)
+	c.Equal(fxp.Two, groups[0].TalentLevel())
+	c.Equal(fxp.FromInteger(28), groups[0].Points())
+	c.Equal(power.Psionic, groups[1].Source)
+}
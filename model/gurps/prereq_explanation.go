@@ -0,0 +1,155 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/criteria"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xbytes"
+)
+
+// PrereqExplanation holds the result of evaluating a prerequisite, along with the results for any prerequisites it
+// contains.
+type PrereqExplanation struct {
+	Requirement string
+	// Current describes what the entity currently has that is relevant to the requirement. May be empty.
+	Current   string
+	Satisfied bool
+	Children  []*PrereqExplanation
+}
+
+type prereqExplainer interface {
+	explain(entity *Entity, exclude any) (requirement, current string)
+}
+
+// ExplainPrereq evaluates the prerequisite against the entity and returns the trace of that evaluation.
+func ExplainPrereq(entity *Entity, exclude any, p Prereq) *PrereqExplanation {
+	var eqpPenalty bool
+	explanation := &PrereqExplanation{Satisfied: p.Satisfied(entity, exclude, nil, "", &eqpPenalty)}
+	switch one := p.(type) {
+	case *PrereqList:
+		if one.WhenTL.Compare != criteria.AnyNumber {
+			tl, _, _ := ExtractTechLevel(entity.Profile.TechLevel)
+			if !one.WhenTL.Compare.Matches(one.WhenTL.Qualifier, max(tl, 0)) {
+				explanation.Requirement = fmt.Sprintf(i18n.Text("Only applies when the tech level %s"),
+					one.WhenTL.String())
+				explanation.Current = entity.Profile.TechLevel
+				return explanation
+			}
+		}
+		if one.All {
+			explanation.Requirement = i18n.Text("Requires all of:")
+		} else {
+			explanation.Requirement = i18n.Text("Requires at least one of:")
+		}
+		for _, child := range one.Prereqs {
+			explanation.Children = append(explanation.Children, ExplainPrereq(entity, exclude, child))
+		}
+	case prereqExplainer:
+		explanation.Requirement, explanation.Current = one.explain(entity, exclude)
+	}
+	return explanation
+}
+
+// Failed returns the unsatisfied requirements that are not lists of other requirements.
+func (x *PrereqExplanation) Failed() []*PrereqExplanation {
+	if x == nil || x.Satisfied {
+		return nil
+	}
+	if len(x.Children) == 0 {
+		return []*PrereqExplanation{x}
+	}
+	var list []*PrereqExplanation
+	for _, child := range x.Children {
+		list = append(list, child.Failed()...)
+	}
+	return list
+}
+
+// String returns an indented, multi-line description of the explanation.
+func (x *PrereqExplanation) String() string {
+	var buffer strings.Builder
+	x.write(&buffer, 0)
+	return buffer.String()
+}
+
+func (x *PrereqExplanation) write(buffer *strings.Builder, depth int) {
+	if buffer.Len() != 0 {
+		buffer.WriteByte('\n')
+	}
+	buffer.WriteString(strings.Repeat("\t", depth))
+	if x.Satisfied {
+		buffer.WriteString("✓ ")
+	} else {
+		buffer.WriteString("✗ ")
+	}
+	buffer.WriteString(x.Requirement)
+	if x.Current != "" {
+		fmt.Fprintf(buffer, i18n.Text(" (currently: %s)"), x.Current)
+	}
+	for _, child := range x.Children {
+		child.write(buffer, depth+1)
+	}
+}
+
+// PrereqExplanation returns the trace of the evaluation of the trait's prerequisites, or nil if it has none.
+func (t *Trait) PrereqExplanation() *PrereqExplanation {
+	return explainPrereqsFor(EntityFromNode(t), t, t.Prereq, nil)
+}
+
+// PrereqExplanation returns the trace of the evaluation of the skill's prerequisites, or nil if it has none.
+func (s *Skill) PrereqExplanation() *PrereqExplanation {
+	if s.Container() {
+		return nil
+	}
+	return explainPrereqsFor(EntityFromNode(s), s, s.Prereq, s.TechniqueSatisfied)
+}
+
+// PrereqExplanation returns the trace of the evaluation of the spell's prerequisites, or nil if it has none.
+func (s *Spell) PrereqExplanation() *PrereqExplanation {
+	if s.Container() {
+		return nil
+	}
+	return explainPrereqsFor(EntityFromNode(s), s, s.Prereq, s.RitualMagicSatisfied)
+}
+
+// PrereqExplanation returns the trace of the evaluation of the equipment's prerequisites, or nil if it has none.
+func (e *Equipment) PrereqExplanation() *PrereqExplanation {
+	return explainPrereqsFor(EntityFromNode(e), e, e.Prereq, nil)
+}
+
+// explainPrereqsFor explains the prerequisites in the list. 'extra', if not nil, checks additional requirements that are
+// not part of the list, such as those of techniques; these are only included in the explanation when they fail.
+func explainPrereqsFor(entity *Entity, owner any, list *PrereqList, extra func(tooltip *xbytes.InsertBuffer, prefix string) bool) *PrereqExplanation {
+	if entity == nil {
+		return nil
+	}
+	var explanation *PrereqExplanation
+	if list != nil && len(list.Prereqs) != 0 {
+		explanation = ExplainPrereq(entity, owner, list)
+	}
+	if extra != nil {
+		var tooltip xbytes.InsertBuffer
+		if !extra(&tooltip, "") {
+			failed := &PrereqExplanation{Requirement: tooltip.String()}
+			if explanation == nil {
+				return failed
+			}
+			return &PrereqExplanation{
+				Requirement: i18n.Text("Requires all of:"),
+				Children:    []*PrereqExplanation{explanation, failed},
+			}
+		}
+	}
+	return explanation
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestPrereqExplanation(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	skill := NewSkill(e, nil, false)
+	skill.Name = "Broadsword"
+	skill.Points = fxp.One
+	e.Skills = []*Skill{skill}
+
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Weapon Master"
+	trait.Prereq = NewPrereqList()
+	attr := NewAttributePrereq(e)
+	attr.Which = DexterityID
+	attr.QualifierCriteria.Qualifier = fxp.FromInteger(12)
+	attr.Parent = trait.Prereq
+	sp := NewSkillPrereq()
+	sp.NameCriteria.Qualifier = "Broadsword"
+	sp.Parent = trait.Prereq
+	trait.Prereq.Prereqs = []Prereq{attr, sp}
+	e.Traits = []*Trait{trait}
+	e.Recalculate()
+
+	c.NotEqual("", trait.UnsatisfiedReason)
+	explanation := trait.PrereqExplanation()
+	c.NotNil(explanation)
+	c.False(explanation.Satisfied)
+	c.Equal(2, len(explanation.Children))
+	c.False(explanation.Children[0].Satisfied)
+	c.Equal("10", explanation.Children[0].Current)
+	c.True(explanation.Children[1].Satisfied)
+	c.Contains(explanation.Children[1].Current, "Broadsword")
+	failed := explanation.Failed()
+	c.Equal(1, len(failed))
+	c.Equal(explanation.Children[0], failed[0])
+	c.Contains(explanation.String(), "(currently: 10)")
+
+	e.Attributes.Set[DexterityID].Adjustment = fxp.Two
+	e.Recalculate()
+	c.Equal("", trait.UnsatisfiedReason)
+	c.True(trait.PrereqExplanation().Satisfied)
+	c.Nil(skill.PrereqExplanation())
+}
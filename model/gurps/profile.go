@@ -38,24 +38,36 @@ type ProfileRandom struct {
 // Profile holds the profile information for an NPC.
 type Profile struct {
 	ProfileRandom
-	PlayerName        string        `json:"player_name,omitzero"`
-	Title             string        `json:"title,omitzero"`
-	Organization      string        `json:"organization,omitzero"`
-	Religion          string        `json:"religion,omitzero"`
-	TechLevel         string        `json:"tech_level,omitzero"`
-	PortraitData      []byte        `json:"portrait,omitzero"`
-	PortraitImage     *unison.Image `json:"-"`
-	SizeModifier      int           `json:"SM,omitzero"`
-	SizeModifierBonus fxp.Int       `json:"-"`
+	PlayerName        string         `json:"player_name,omitzero"`
+	Title             string         `json:"title,omitzero"`
+	Organization      string         `json:"organization,omitzero"`
+	Religion          string         `json:"religion,omitzero"`
+	TechLevel         string         `json:"tech_level,omitzero"`
+	PortraitData      []byte         `json:"portrait,omitzero"`
+	PortraitImage     *unison.Image  `json:"-"`
+	AltPortraits      []*AltPortrait `json:"alt_portraits,omitzero"`
+	ShownPortrait     int            `json:"shown_portrait,omitzero"`
+	SizeModifier      int            `json:"SM,omitzero"`
+	SizeModifierBonus fxp.Int        `json:"-"`
+	SizeFromHeight    bool           `json:"-"`
 }
 
 // Update any derived values.
 func (p *Profile) Update(entity *Entity) {
 	p.SizeModifierBonus = entity.AttributeBonusFor(SizeModifierID, stlimit.None, nil)
+	p.SizeFromHeight = entity.SheetSettings.UseHeightBasedSizeModifier
 }
 
-// Portrait returns the portrait image, if there is one.
+// Portrait returns the portrait image currently chosen for display, if there is one.
 func (p *Profile) Portrait() *unison.Image {
+	if alt := p.ShownAltPortrait(); alt != nil {
+		return alt.Image()
+	}
+	return p.PrimaryPortrait()
+}
+
+// PrimaryPortrait returns the primary portrait image, if there is one.
+func (p *Profile) PrimaryPortrait() *unison.Image {
 	if p.PortraitImage == nil && len(p.PortraitData) != 0 {
 		var err error
 		if p.PortraitImage, err = unison.NewImageFromBytes(p.PortraitData, geom.NewPoint(0.5, 0.5)); err != nil {
@@ -75,10 +87,16 @@ func (p *Profile) CanExportPortrait() bool {
 
 // PortraitExtension returns the extension for the portrait image.
 func (p *Profile) PortraitExtension() string {
-	if len(p.PortraitData) == 0 {
+	return PortraitDataExtension(p.PortraitData)
+}
+
+// PortraitDataExtension returns the extension for the portrait image data, or an empty string if the format isn't
+// recognized.
+func PortraitDataExtension(data []byte) string {
+	if len(data) == 0 {
 		return ""
 	}
-	switch http.DetectContentType(p.PortraitData) {
+	switch http.DetectContentType(data) {
 	case "image/webp":
 		return ".webp"
 	case "image/png":
@@ -99,18 +117,37 @@ func (p *Profile) ExportPortrait(filePath string) error {
 	return errs.Wrap(os.WriteFile(filePath, p.PortraitData, 0o640))
 }
 
+// BaseSizeModifier returns the size modifier prior to any bonuses. If the size modifier is being derived from the
+// height, the stored size modifier is ignored.
+func (p *Profile) BaseSizeModifier() int {
+	if p.SizeFromHeight {
+		return SizeModifierForHeight(p.Height)
+	}
+	return p.SizeModifier
+}
+
 // AdjustedSizeModifier returns the adjusted size modifier.
 func (p *Profile) AdjustedSizeModifier() int {
-	return p.SizeModifier + fxp.AsInteger[int](p.SizeModifierBonus)
+	return p.BaseSizeModifier() + fxp.AsInteger[int](p.SizeModifierBonus)
 }
 
-// SetAdjustedSizeModifier sets the adjusted size modifier.
+// SetAdjustedSizeModifier sets the adjusted size modifier. Has no effect if the size modifier is being derived from
+// the height.
 func (p *Profile) SetAdjustedSizeModifier(value int) {
-	if value != p.AdjustedSizeModifier() {
+	if !p.SizeFromHeight && value != p.AdjustedSizeModifier() {
 		p.SizeModifier = value - fxp.AsInteger[int](p.SizeModifierBonus)
 	}
 }
 
+// SizeModifierForHeight returns the size modifier for the given height, using the Size and Speed/Range Table (B19). A
+// height of zero or less is treated as unknown and yields a size modifier of 0.
+func SizeModifierForHeight(height fxp.Length) int {
+	if height <= 0 {
+		return 0
+	}
+	return ssrtInchesToValue(fxp.Int(height), true)
+}
+
 // AutoFill fills in the default profile entries.
 func (p *Profile) AutoFill(entity *Entity) {
 	generalSettings := GlobalSettings().GeneralSettings()
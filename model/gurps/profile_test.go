@@ -0,0 +1,43 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSizeModifierForHeight(t *testing.T) {
+	c := check.New(t)
+	c.Equal(0, SizeModifierForHeight(0), "unknown height")
+	c.Equal(-3, SizeModifierForHeight(fxp.LengthFromInteger(2, fxp.Feet)), "2 ft")
+	c.Equal(-2, SizeModifierForHeight(fxp.LengthFromInteger(3, fxp.Feet)), "3 ft")
+	c.Equal(-1, SizeModifierForHeight(fxp.LengthFromInteger(4, fxp.Feet)), "4 ft")
+	c.Equal(0, SizeModifierForHeight(fxp.LengthFromInteger(66, fxp.Inch)), "5 ft 6 in")
+	c.Equal(0, SizeModifierForHeight(fxp.LengthFromInteger(6, fxp.Feet)), "6 ft")
+	c.Equal(1, SizeModifierForHeight(fxp.LengthFromInteger(7, fxp.Feet)), "7 ft")
+	c.Equal(4, SizeModifierForHeight(fxp.LengthFromInteger(10, fxp.Yard)), "10 yd")
+}
+
+func TestHeightBasedSizeModifier(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.Profile.SizeModifier = 2
+	e.Profile.Height = fxp.LengthFromInteger(7, fxp.Feet)
+	e.Recalculate()
+	c.Equal(2, e.Profile.AdjustedSizeModifier(), "stored size modifier")
+	e.SheetSettings.UseHeightBasedSizeModifier = true
+	e.Recalculate()
+	c.Equal(1, e.Profile.AdjustedSizeModifier(), "size modifier from height")
+	e.Profile.SetAdjustedSizeModifier(5)
+	c.Equal(1, e.Profile.AdjustedSizeModifier(), "setting ignored while derived from height")
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"hash"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xhash"
+)
+
+// Provenance holds information about who created a library item and where it came from.
+type Provenance struct {
+	Author     string `json:"author,omitzero"`
+	License    string `json:"license,omitzero"`
+	SourceBook string `json:"source_book,omitzero"`
+	Version    string `json:"version,omitzero"`
+}
+
+// String returns a multi-line description of the provenance, or an empty string if nothing has been set.
+func (p Provenance) String() string {
+	var buffer strings.Builder
+	add := func(title, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			if buffer.Len() != 0 {
+				buffer.WriteByte('\n')
+			}
+			buffer.WriteString(title)
+			buffer.WriteString(value)
+		}
+	}
+	add(i18n.Text("Author: "), p.Author)
+	add(i18n.Text("License: "), p.License)
+	add(i18n.Text("Source Book: "), p.SourceBook)
+	add(i18n.Text("Version: "), p.Version)
+	return buffer.String()
+}
+
+// AppendToTooltip returns the tooltip with the provenance description appended to it.
+func (p Provenance) AppendToTooltip(tooltip string) string {
+	desc := p.String()
+	switch {
+	case desc == "":
+		return tooltip
+	case tooltip == "":
+		return desc
+	default:
+		return tooltip + "\n---\n" + desc
+	}
+}
+
+func (p *Provenance) hash(h hash.Hash) {
+	xhash.StringWithLen(h, p.Author)
+	xhash.StringWithLen(h, p.License)
+	xhash.StringWithLen(h, p.SourceBook)
+	xhash.StringWithLen(h, p.Version)
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestProvenance(t *testing.T) {
+	c := check.New(t)
+	var p Provenance
+	c.Equal("", p.String())
+	c.Equal("tip", p.AppendToTooltip("tip"))
+
+	p.Author = "Jane Doe"
+	p.Version = " 2 "
+	c.Equal("Author: Jane Doe\nVersion: 2", p.String())
+	c.Equal("Author: Jane Doe\nVersion: 2", p.AppendToTooltip(""))
+	c.Equal("tip\n---\nAuthor: Jane Doe\nVersion: 2", p.AppendToTooltip("tip"))
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/reaction"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// ReactionContribution holds a single reaction modifier that applies to a group.
+type ReactionContribution struct {
+	Kind   reaction.Source
+	Source string
+	Amount fxp.Int
+}
+
+// ReactionGroup holds the reaction modifiers that apply to a particular target group.
+type ReactionGroup struct {
+	Group         string
+	Contributions []*ReactionContribution
+}
+
+// Total returns the sum of all reaction modifiers for the group.
+func (g *ReactionGroup) Total() fxp.Int {
+	var total fxp.Int
+	for _, one := range g.Contributions {
+		total += one.Amount
+	}
+	return total
+}
+
+// TotalFor returns the sum of the reaction modifiers for the group that come from the given kind of source.
+func (g *ReactionGroup) TotalFor(kind reaction.Source) fxp.Int {
+	var total fxp.Int
+	for _, one := range g.Contributions {
+		if one.Kind == kind {
+			total += one.Amount
+		}
+	}
+	return total
+}
+
+// Has returns true if the group has any reaction modifiers that come from the given kind of source.
+func (g *ReactionGroup) Has(kind reaction.Source) bool {
+	return slices.ContainsFunc(g.Contributions, func(one *ReactionContribution) bool { return one.Kind == kind })
+}
+
+// ReactionSummary returns the reaction modifiers the entity has, grouped by the target group they apply to.
+func (e *Entity) ReactionSummary() []*ReactionGroup {
+	m := make(map[string]*ReactionGroup)
+	e.collectReactions(func(kind reaction.Source, source, situation string, amt fxp.Int) {
+		g, exists := m[situation]
+		if !exists {
+			g = &ReactionGroup{Group: situation}
+			m[situation] = g
+		}
+		g.Contributions = append(g.Contributions, &ReactionContribution{
+			Kind:   kind,
+			Source: source,
+			Amount: amt,
+		})
+	})
+	list := make([]*ReactionGroup, 0, len(m))
+	for _, g := range m {
+		list = append(list, g)
+	}
+	slices.SortFunc(list, func(a, b *ReactionGroup) int { return xstrings.NaturalCmp(a.Group, b.Group, true) })
+	return list
+}
+
+// ReactionSourceKinds returns the kinds of sources that contribute to at least one of the groups, in their natural
+// order.
+func ReactionSourceKinds(groups []*ReactionGroup) []reaction.Source {
+	var kinds []reaction.Source
+	for _, kind := range reaction.Sources {
+		if slices.ContainsFunc(groups, func(g *ReactionGroup) bool { return g.Has(kind) }) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+func reactionSourceForTrait(t *Trait) reaction.Source {
+	name := strings.ToLower(t.NameWithReplacements())
+	switch {
+	case strings.HasPrefix(name, "appearance"):
+		return reaction.Appearance
+	case strings.HasPrefix(name, "status"):
+		return reaction.Status
+	case strings.HasPrefix(name, "reputation"):
+		return reaction.Reputation
+	default:
+		return reaction.Trait
+	}
+}
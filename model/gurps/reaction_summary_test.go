@@ -0,0 +1,63 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/reaction"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestReactionSummary(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	var traits []*Trait
+	for _, one := range []struct {
+		name      string
+		situation string
+		amount    fxp.Int
+	}{
+		{name: "Appearance (Attractive)", situation: "from others", amount: fxp.Two},
+		{name: "Status 2", situation: "from others", amount: fxp.Two},
+		{name: "Status 2", situation: "from nobles", amount: fxp.One},
+		{name: "Reputation (Brave)", situation: "from soldiers", amount: fxp.Three},
+		{name: "Odious Personal Habit", situation: "from others", amount: -fxp.One},
+	} {
+		trait := NewTrait(e, nil, false)
+		trait.Name = one.name
+		bonus := NewReactionBonus()
+		bonus.Situation = one.situation
+		bonus.Amount = one.amount
+		trait.Features = Features{bonus}
+		traits = append(traits, trait)
+	}
+	e.SetTraitList(traits)
+	e.Recalculate()
+
+	groups := e.ReactionSummary()
+	c.Equal(3, len(groups))
+	c.Equal("from nobles", groups[0].Group)
+	c.Equal(fxp.One, groups[0].Total())
+	others := groups[1]
+	c.Equal("from others", others.Group)
+	c.Equal(3, len(others.Contributions))
+	c.Equal(fxp.Three, others.Total())
+	c.Equal(fxp.Two, others.TotalFor(reaction.Appearance))
+	c.Equal(fxp.Two, others.TotalFor(reaction.Status))
+	c.Equal(-fxp.One, others.TotalFor(reaction.Trait))
+	c.False(others.Has(reaction.Reputation))
+	c.Equal("from soldiers", groups[2].Group)
+	c.Equal(fxp.Three, groups[2].TotalFor(reaction.Reputation))
+	c.Equal([]reaction.Source{reaction.Appearance, reaction.Status, reaction.Reputation, reaction.Trait},
+		ReactionSourceKinds(groups))
+	c.Equal(3, len(e.Reactions()))
+}
@@ -0,0 +1,344 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"hash"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/difficulty"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/rpm"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xhash"
+	"github.com/zeebo/xxh3"
+)
+
+// Names used by the Ritual Path Magic rules.
+const (
+	ThaumatologySkillName = "Thaumatology"
+	MageryTraitName       = "Magery"
+)
+
+// pathSkillDefaultModifier is the modifier for a path skill's default from Thaumatology.
+const pathSkillDefaultModifier = -6
+
+// RitualEffect holds a single effect of a Ritual Path Magic ritual.
+type RitualEffect struct {
+	Effect  rpm.Effect `json:"effect"`
+	Path    rpm.Path   `json:"path"`
+	Greater bool       `json:"greater,omitzero"`
+}
+
+// RitualModifier holds a modifier to a Ritual Path Magic ritual, such as Area, Damage, or Duration, along with the
+// energy it adds to the ritual's cost.
+type RitualModifier struct {
+	Name   string `json:"name"`
+	Energy int    `json:"energy,omitzero"`
+}
+
+// Ritual holds a Ritual Path Magic ritual, built from its effects and modifiers.
+type Ritual struct {
+	Name      string            `json:"name"`
+	Notes     string            `json:"notes,omitzero"`
+	Effects   []*RitualEffect   `json:"effects,omitzero"`
+	Modifiers []*RitualModifier `json:"modifiers,omitzero"`
+}
+
+// Grimoire holds the rituals a character knows, along with the energy they have gathered so far for the next ritual.
+type Grimoire struct {
+	Rituals  []*Ritual `json:"rituals,omitzero"`
+	Gathered int       `json:"gathered,omitzero"`
+}
+
+// String implements fmt.Stringer.
+func (e *RitualEffect) String() string {
+	if e.Greater {
+		return fmt.Sprintf(i18n.Text("Greater %s %s"), e.Effect.String(), e.Path.String())
+	}
+	return fmt.Sprintf(i18n.Text("Lesser %s %s"), e.Effect.String(), e.Path.String())
+}
+
+// String implements fmt.Stringer.
+func (m *RitualModifier) String() string {
+	return fmt.Sprintf("%s (%s)", m.Name, fxp.FromInteger(m.Energy).StringWithSign())
+}
+
+// String implements fmt.Stringer.
+func (r *Ritual) String() string {
+	return r.Name
+}
+
+// EffectsSummary returns a description of the ritual's effects.
+func (r *Ritual) EffectsSummary() string {
+	list := make([]string, 0, len(r.Effects))
+	for _, one := range r.Effects {
+		list = append(list, one.String())
+	}
+	return strings.Join(list, " + ")
+}
+
+// ModifiersSummary returns a description of the ritual's modifiers.
+func (r *Ritual) ModifiersSummary() string {
+	list := make([]string, 0, len(r.Modifiers))
+	for _, one := range r.Modifiers {
+		list = append(list, one.String())
+	}
+	return strings.Join(list, ", ")
+}
+
+// GreaterEffects returns the number of greater effects in the ritual.
+func (r *Ritual) GreaterEffects() int {
+	count := 0
+	for _, one := range r.Effects {
+		if one.Greater {
+			count++
+		}
+	}
+	return count
+}
+
+// BaseEnergy returns the energy cost of the ritual's effects and modifiers, before the multiplier for greater effects is
+// applied.
+func (r *Ritual) BaseEnergy() int {
+	total := 0
+	for _, one := range r.Effects {
+		total += one.Effect.Energy()
+	}
+	for _, one := range r.Modifiers {
+		total += one.Energy
+	}
+	return max(total, 0)
+}
+
+// EnergyMultiplier returns the multiplier applied to the ritual's base energy cost: 1, plus 2 for each greater effect.
+func (r *Ritual) EnergyMultiplier() int {
+	return 1 + 2*r.GreaterEffects()
+}
+
+// Energy returns the total energy cost of the ritual.
+func (r *Ritual) Energy() int {
+	return r.BaseEnergy() * r.EnergyMultiplier()
+}
+
+// EnergyDescription returns a description of how the ritual's energy cost was arrived at.
+func (r *Ritual) EnergyDescription() string {
+	parts := make([]string, 0, len(r.Effects)+len(r.Modifiers))
+	for _, one := range r.Effects {
+		parts = append(parts, fmt.Sprintf("%s %d", one.Effect.String(), one.Effect.Energy()))
+	}
+	for _, one := range r.Modifiers {
+		parts = append(parts, fmt.Sprintf("%s %d", one.Name, one.Energy))
+	}
+	desc := strings.Join(parts, " + ")
+	if multiplier := r.EnergyMultiplier(); multiplier > 1 {
+		desc = fmt.Sprintf(i18n.Text("(%s) × %d for greater effects"), desc, multiplier)
+	}
+	return desc + " = " + strconv.Itoa(r.Energy())
+}
+
+// Paths returns the paths used by the ritual's effects, without duplicates.
+func (r *Ritual) Paths() []rpm.Path {
+	var paths []rpm.Path
+	for _, one := range r.Effects {
+		if !slices.Contains(paths, one.Path) {
+			paths = append(paths, one.Path)
+		}
+	}
+	return paths
+}
+
+// Clone creates a copy of this ritual.
+func (r *Ritual) Clone() *Ritual {
+	other := *r
+	other.Effects = make([]*RitualEffect, len(r.Effects))
+	for i, one := range r.Effects {
+		effect := *one
+		other.Effects[i] = &effect
+	}
+	other.Modifiers = make([]*RitualModifier, len(r.Modifiers))
+	for i, one := range r.Modifiers {
+		modifier := *one
+		other.Modifiers[i] = &modifier
+	}
+	return &other
+}
+
+// Hash writes this object's contents into the hasher.
+func (r *Ritual) Hash(h hash.Hash) {
+	xhash.StringWithLen(h, r.Name)
+	xhash.StringWithLen(h, r.Notes)
+	xhash.Num64(h, len(r.Effects))
+	for _, one := range r.Effects {
+		xhash.Num8(h, one.Effect)
+		xhash.Num8(h, one.Path)
+		xhash.Bool(h, one.Greater)
+	}
+	xhash.Num64(h, len(r.Modifiers))
+	for _, one := range r.Modifiers {
+		xhash.StringWithLen(h, one.Name)
+		xhash.Num64(h, one.Energy)
+	}
+}
+
+// Clone creates a copy of this grimoire.
+func (g *Grimoire) Clone() Grimoire {
+	other := Grimoire{Gathered: g.Gathered}
+	if len(g.Rituals) != 0 {
+		other.Rituals = make([]*Ritual, len(g.Rituals))
+		for i, one := range g.Rituals {
+			other.Rituals[i] = one.Clone()
+		}
+	}
+	return other
+}
+
+// Hash returns a hash value for the grimoire.
+func (g *Grimoire) Hash() uint64 {
+	h := xxh3.New()
+	xhash.Num64(h, g.Gathered)
+	xhash.Num64(h, len(g.Rituals))
+	for _, one := range g.Rituals {
+		one.Hash(h)
+	}
+	return h.Sum64()
+}
+
+// SetGrimoire replaces the entity's grimoire with a copy of the one provided.
+func (e *Entity) SetGrimoire(g Grimoire) {
+	e.Grimoire = g.Clone()
+}
+
+// RitualPathMagic returns true if the Ritual Path Magic rules are enabled for this entity.
+func (e *Entity) RitualPathMagic() bool {
+	return e.SheetSettings != nil && e.SheetSettings.RitualPathMagic
+}
+
+// Magery returns the character's level of Magery, or 0 if they don't have it.
+func (e *Entity) Magery() int {
+	level := 0
+	Traverse(func(t *Trait) bool {
+		if strings.EqualFold(t.NameWithReplacements(), MageryTraitName) {
+			if t.IsLeveled() {
+				level = max(level, fxp.AsInteger[int](t.CurrentLevel().Floor()))
+			}
+		}
+		return false
+	}, true, false, e.Traits...)
+	return level
+}
+
+// ThaumatologyLevel returns the character's Thaumatology skill level, or 0 if they don't have the skill.
+func (e *Entity) ThaumatologyLevel() fxp.Int {
+	if sk := e.BestSkillNamed(ThaumatologySkillName, "", false, nil); sk != nil {
+		return sk.LevelData.Level.Max(0)
+	}
+	return 0
+}
+
+// RitualPathLevel returns the character's skill with the given path. Path skills may not exceed Thaumatology, and
+// default to Thaumatology-6 when the character doesn't have the path skill.
+func (e *Entity) RitualPathLevel(path rpm.Path) fxp.Int {
+	thaumatology := e.ThaumatologyLevel()
+	level := (thaumatology + fxp.FromInteger(pathSkillDefaultModifier)).Max(0)
+	if sk := e.BestSkillNamed(path.SkillName(), "", false, nil); sk != nil {
+		level = level.Max(sk.LevelData.Level)
+	}
+	return level.Min(thaumatology)
+}
+
+// RitualLevel returns the character's skill for casting the ritual, which is the lowest of the path skills it uses, or
+// Thaumatology if it doesn't use any paths.
+func (e *Entity) RitualLevel(r *Ritual) fxp.Int {
+	paths := r.Paths()
+	if len(paths) == 0 {
+		return e.ThaumatologyLevel()
+	}
+	level := fxp.Max
+	for _, path := range paths {
+		level = level.Min(e.RitualPathLevel(path))
+	}
+	return level
+}
+
+// RitualGatheringTime returns the time each energy gathering roll takes for a caster with the given level of Magery.
+func RitualGatheringTime(magery int) string {
+	switch {
+	case magery <= 0:
+		return i18n.Text("5 minutes")
+	case magery == 1:
+		return i18n.Text("30 seconds")
+	case magery == 2:
+		return i18n.Text("10 seconds")
+	case magery == 3:
+		return i18n.Text("5 seconds")
+	case magery == 4:
+		return i18n.Text("2 seconds")
+	default:
+		return i18n.Text("1 second")
+	}
+}
+
+// RitualEnergyGathered returns the energy gathered by a single gathering roll with the given outcome. A success gathers
+// energy equal to the caster's Magery (minimum 1) and a critical success gathers 3 more.
+func RitualEnergyGathered(magery int, success, critical bool) int {
+	if !success {
+		return 0
+	}
+	energy := max(magery, 1)
+	if critical {
+		energy += 3
+	}
+	return energy
+}
+
+// GatherRitualEnergy applies the outcome of an energy gathering roll to the grimoire, returning the energy gathered. A
+// critical failure loses all of the energy gathered so far.
+func (e *Entity) GatherRitualEnergy(success, critical bool) int {
+	if !success && critical {
+		e.Grimoire.Gathered = 0
+		return 0
+	}
+	energy := RitualEnergyGathered(e.Magery(), success, critical)
+	e.Grimoire.Gathered += energy
+	return energy
+}
+
+// NewRitualPathSkill creates a new skill for the given Ritual Path Magic path, which is IQ/Very Hard and defaults to
+// Thaumatology-6.
+func NewRitualPathSkill(owner DataOwner, path rpm.Path) *Skill {
+	sk := NewSkill(owner, nil, false)
+	sk.Name = path.SkillName()
+	sk.Difficulty.Attribute = AttributeIDFor(EntityFromNode(sk), IntelligenceID)
+	sk.Difficulty.Difficulty = difficulty.VeryHard
+	sk.Defaults = []*SkillDefault{{
+		DefaultType: SkillID,
+		Name:        ThaumatologySkillName,
+		Modifier:    fxp.FromInteger(pathSkillDefaultModifier),
+	}}
+	return sk
+}
+
+// MissingRitualPaths returns the paths used by the rituals in the grimoire for which the character has no path skill.
+func (e *Entity) MissingRitualPaths() []rpm.Path {
+	var missing []rpm.Path
+	for _, r := range e.Grimoire.Rituals {
+		for _, path := range r.Paths() {
+			if !slices.Contains(missing, path) && len(e.SkillNamed(path.SkillName(), "", false, nil)) == 0 {
+				missing = append(missing, path)
+			}
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
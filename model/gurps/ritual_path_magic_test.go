@@ -0,0 +1,74 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/rpm"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestRitualEnergy(t *testing.T) {
+	c := check.New(t)
+	r := &Ritual{
+		Name: "Sleep",
+		Effects: []*RitualEffect{
+			{Effect: rpm.Control, Path: rpm.Mind, Greater: true},
+			{Effect: rpm.Sense, Path: rpm.Body},
+		},
+		Modifiers: []*RitualModifier{{Name: "Duration", Energy: 1}},
+	}
+	c.Equal(8, r.BaseEnergy())
+	c.Equal(3, r.EnergyMultiplier())
+	c.Equal(24, r.Energy())
+	c.Contains(r.EnergyDescription(), "= 24")
+	c.Equal([]rpm.Path{rpm.Mind, rpm.Body}, r.Paths())
+
+	other := r.Clone()
+	other.Effects[0].Greater = false
+	c.True(r.Effects[0].Greater)
+	c.Equal(8, other.Energy())
+}
+
+func TestRitualPathLevels(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.SheetSettings.RitualPathMagic = true
+	thaumatology := NewSkill(e, nil, false)
+	thaumatology.Name = ThaumatologySkillName
+	thaumatology.Points = fxp.Eight
+	mind := NewRitualPathSkill(e, rpm.Mind)
+	mind.Points = fxp.Thirty
+	e.Skills = []*Skill{thaumatology, mind}
+	e.Grimoire.Rituals = []*Ritual{{
+		Name: "Sleep",
+		Effects: []*RitualEffect{
+			{Effect: rpm.Control, Path: rpm.Mind},
+			{Effect: rpm.Sense, Path: rpm.Body},
+		},
+	}}
+	e.Recalculate()
+	level := e.ThaumatologyLevel()
+	c.True(level > 0)
+	c.Equal(level, e.RitualPathLevel(rpm.Mind))
+	c.Equal(level-fxp.Six, e.RitualPathLevel(rpm.Body))
+	c.Equal(level-fxp.Six, e.RitualLevel(e.Grimoire.Rituals[0]))
+	c.Equal([]rpm.Path{rpm.Body}, e.MissingRitualPaths())
+
+	c.Equal(0, e.Magery())
+	c.Equal(1, e.GatherRitualEnergy(true, false))
+	c.Equal(4, e.GatherRitualEnergy(true, true))
+	c.Equal(0, e.GatherRitualEnergy(false, false))
+	c.Equal(5, e.Grimoire.Gathered)
+	e.GatherRitualEnergy(false, true)
+	c.Equal(0, e.Grimoire.Gathered)
+}
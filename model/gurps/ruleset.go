@@ -0,0 +1,174 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"io/fs"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/progression"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+// RulesetParts holds flags indicating which portions of a Ruleset should be applied.
+type RulesetParts struct {
+	Options            bool
+	SkillModifiers     bool
+	DodgeCustomization bool
+	Attributes         bool
+	BodyType           bool
+}
+
+// AllRulesetParts returns a RulesetParts with every part enabled.
+func AllRulesetParts() RulesetParts {
+	return RulesetParts{
+		Options:            true,
+		SkillModifiers:     true,
+		DodgeCustomization: true,
+		Attributes:         true,
+		BodyType:           true,
+	}
+}
+
+// RulesetData holds the Ruleset data that is written to disk.
+type RulesetData struct {
+	Version                         int                `json:"version"`
+	Name                            string             `json:"name,omitzero"`
+	Notes                           string             `json:"notes,omitzero"`
+	DamageProgression               progression.Option `json:"damage_progression"`
+	UseMultiplicativeModifiers      bool               `json:"use_multiplicative_modifiers,omitzero"`
+	UseModifyingDicePlusAdds        bool               `json:"use_modifying_dice_plus_adds,omitzero"`
+	UseHalfStatDefaults             bool               `json:"use_half_stat_defaults,omitzero"`
+	UseHeightBasedSizeModifier      bool               `json:"use_height_based_size_modifier,omitzero"`
+	UseSkillModifierAdjustments     bool               `json:"use_skill_modifier_adjustments,omitzero"`
+	EasySkillModifierOverride       fxp.Int            `json:"easy_skill_modifier_override,omitzero"`
+	AverageSkillModifierOverride    fxp.Int            `json:"average_skill_modifier_override,omitzero"`
+	HardSkillModifierOverride       fxp.Int            `json:"hard_skill_modifier_override,omitzero"`
+	VeryHardSkillModifierOverride   fxp.Int            `json:"very_hard_skill_modifier_override,omitzero"`
+	EasySkillModifierAdjustment     fxp.Int            `json:"easy_skill_modifier_adjustment,omitzero"`
+	AverageSkillModifierAdjustment  fxp.Int            `json:"average_skill_modifier_adjustment,omitzero"`
+	HardSkillModifierAdjustment     fxp.Int            `json:"hard_skill_modifier_adjustment,omitzero"`
+	VeryHardSkillModifierAdjustment fxp.Int            `json:"very_hard_skill_modifier_adjustment,omitzero"`
+	UseBasicMoveForDodge            bool               `json:"use_basic_move_for_dodge,omitzero"`
+	IncludeDodgeFlatBonus           bool               `json:"include_dodge_flat_bonus,omitzero"`
+	IncludePDArmor                  bool               `json:"include_pd_armor,omitzero"`
+	IncludePDShields                bool               `json:"include_pd_shields,omitzero"`
+	UsePassiveDefense               bool               `json:"use_passive_defense,omitzero"`
+	DodgeOverride                   fxp.Int            `json:"dodge_override,omitzero"`
+	Attributes                      *AttributeDefs     `json:"attributes,omitzero"`
+	BodyType                        *Body              `json:"body_type,omitzero"`
+}
+
+// Ruleset holds a set of house rules extracted from SheetSettings, so that they can be shared between sheets.
+type Ruleset struct {
+	RulesetData
+}
+
+// NewRulesetFromSheetSettings creates a new Ruleset from the house rules contained in the given SheetSettings.
+func NewRulesetFromSheetSettings(s *SheetSettings) *Ruleset {
+	return &Ruleset{
+		RulesetData: RulesetData{
+			Version:                         jio.CurrentDataVersion,
+			DamageProgression:               s.DamageProgression,
+			UseMultiplicativeModifiers:      s.UseMultiplicativeModifiers,
+			UseModifyingDicePlusAdds:        s.UseModifyingDicePlusAdds,
+			UseHalfStatDefaults:             s.UseHalfStatDefaults,
+			UseHeightBasedSizeModifier:      s.UseHeightBasedSizeModifier,
+			UseSkillModifierAdjustments:     s.UseSkillModifierAdjustments,
+			EasySkillModifierOverride:       s.EasySkillModifierOverride,
+			AverageSkillModifierOverride:    s.AverageSkillModifierOverride,
+			HardSkillModifierOverride:       s.HardSkillModifierOverride,
+			VeryHardSkillModifierOverride:   s.VeryHardSkillModifierOverride,
+			EasySkillModifierAdjustment:     s.EasySkillModifierAdjustment,
+			AverageSkillModifierAdjustment:  s.AverageSkillModifierAdjustment,
+			HardSkillModifierAdjustment:     s.HardSkillModifierAdjustment,
+			VeryHardSkillModifierAdjustment: s.VeryHardSkillModifierAdjustment,
+			UseBasicMoveForDodge:            s.UseBasicMoveForDodge,
+			IncludeDodgeFlatBonus:           s.IncludeDodgeFlatBonus,
+			IncludePDArmor:                  s.IncludePDArmor,
+			IncludePDShields:                s.IncludePDShields,
+			UsePassiveDefense:               s.UsePassiveDefense,
+			DodgeOverride:                   s.DodgeOverride,
+			Attributes:                      s.Attributes.Clone(),
+			BodyType:                        s.BodyType.Clone(nil, nil),
+		},
+	}
+}
+
+// NewRulesetFromFile loads a Ruleset from a file.
+func NewRulesetFromFile(fileSystem fs.FS, filePath string) (*Ruleset, error) {
+	var r Ruleset
+	if err := jio.Load(fileSystem, filePath, &r.RulesetData); err != nil {
+		return nil, errs.NewWithCause(InvalidFileData(), err)
+	}
+	if err := jio.CheckVersion(r.Version); err != nil {
+		return nil, err
+	}
+	r.EnsureValidity()
+	return &r, nil
+}
+
+// EnsureValidity checks the current settings for validity and if they aren't valid, makes them so.
+func (r *Ruleset) EnsureValidity() {
+	r.DamageProgression = r.DamageProgression.EnsureValid()
+	if r.Attributes == nil {
+		r.Attributes = FactoryAttributeDefs()
+	}
+	if r.BodyType == nil {
+		r.BodyType = FactoryBody()
+	}
+}
+
+// ApplyTo applies the requested parts of the Ruleset to the SheetSettings.
+func (r *Ruleset) ApplyTo(s *SheetSettings, parts RulesetParts) {
+	if parts.Options {
+		s.DamageProgression = r.DamageProgression
+		s.UseMultiplicativeModifiers = r.UseMultiplicativeModifiers
+		s.UseModifyingDicePlusAdds = r.UseModifyingDicePlusAdds
+		s.UseHalfStatDefaults = r.UseHalfStatDefaults
+		s.UseHeightBasedSizeModifier = r.UseHeightBasedSizeModifier
+	}
+	if parts.SkillModifiers {
+		s.UseSkillModifierAdjustments = r.UseSkillModifierAdjustments
+		s.EasySkillModifierOverride = r.EasySkillModifierOverride
+		s.AverageSkillModifierOverride = r.AverageSkillModifierOverride
+		s.HardSkillModifierOverride = r.HardSkillModifierOverride
+		s.VeryHardSkillModifierOverride = r.VeryHardSkillModifierOverride
+		s.EasySkillModifierAdjustment = r.EasySkillModifierAdjustment
+		s.AverageSkillModifierAdjustment = r.AverageSkillModifierAdjustment
+		s.HardSkillModifierAdjustment = r.HardSkillModifierAdjustment
+		s.VeryHardSkillModifierAdjustment = r.VeryHardSkillModifierAdjustment
+	}
+	if parts.DodgeCustomization {
+		s.UseBasicMoveForDodge = r.UseBasicMoveForDodge
+		s.IncludeDodgeFlatBonus = r.IncludeDodgeFlatBonus
+		s.IncludePDArmor = r.IncludePDArmor
+		s.IncludePDShields = r.IncludePDShields
+		s.UsePassiveDefense = r.UsePassiveDefense
+		s.ShowPDColumn = r.UsePassiveDefense
+		s.DodgeOverride = r.DodgeOverride
+	}
+	if parts.Attributes {
+		s.Attributes = r.Attributes.Clone()
+		if s.Entity != nil {
+			s.Entity.Attributes.SyncWithDefs(s.Entity)
+		}
+	}
+	if parts.BodyType {
+		s.BodyType = r.BodyType.Clone(s.Entity, nil)
+	}
+}
+
+// Save writes the Ruleset to the file as JSON.
+func (r *Ruleset) Save(filePath string) error {
+	r.Version = jio.CurrentDataVersion
+	return jio.SaveToFile(filePath, &r.RulesetData)
+}
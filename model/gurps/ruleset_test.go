@@ -0,0 +1,50 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestRulesetApplyTo(t *testing.T) {
+	c := check.New(t)
+	src := FactorySheetSettings()
+	src.UseMultiplicativeModifiers = true
+	src.UsePassiveDefense = true
+	src.HardSkillModifierOverride = -fxp.One
+	ruleset := NewRulesetFromSheetSettings(src)
+
+	dst := FactorySheetSettings()
+	ruleset.ApplyTo(dst, RulesetParts{Options: true})
+	c.True(dst.UseMultiplicativeModifiers, "options applied")
+	c.False(dst.UsePassiveDefense, "dodge customization not applied")
+	c.Equal(fxp.Int(0), dst.HardSkillModifierOverride, "skill modifiers not applied")
+
+	ruleset.ApplyTo(dst, AllRulesetParts())
+	c.True(dst.UsePassiveDefense, "dodge customization applied")
+	c.True(dst.ShowPDColumn, "PD column follows passive defense")
+	c.Equal(-fxp.One, dst.HardSkillModifierOverride, "skill modifiers applied")
+}
+
+func TestRulesetApplyToEntityAttributes(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	src := FactorySheetSettings()
+	delete(src.Attributes.Set, "will")
+	ruleset := NewRulesetFromSheetSettings(src)
+	ruleset.ApplyTo(e.SheetSettings, RulesetParts{Attributes: true})
+	_, exists := e.Attributes.Set["will"]
+	c.False(exists, "attribute removed from entity")
+	_, exists = e.Attributes.Set["st"]
+	c.True(exists, "remaining attribute retained")
+}
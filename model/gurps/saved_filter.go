@@ -0,0 +1,116 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+)
+
+// SavedFilter holds a named set of criteria for filtering the rows of a list table. Pinned filters are offered as smart
+// groups above the table.
+type SavedFilter struct {
+	Name       string   `json:"name"`
+	Text       string   `json:"text,omitzero"`
+	NamesOnly  bool     `json:"names_only,omitzero"`
+	Tags       []string `json:"tags,omitzero"`
+	TechLevel  string   `json:"tech_level,omitzero"`
+	PointRange bool     `json:"point_range,omitzero"`
+	MinPoints  fxp.Int  `json:"min_points,omitzero"`
+	MaxPoints  fxp.Int  `json:"max_points,omitzero"`
+	Pinned     bool     `json:"pinned,omitzero"`
+}
+
+// Clone creates a copy of this.
+func (f *SavedFilter) Clone() *SavedFilter {
+	clone := *f
+	clone.Tags = slices.Clone(f.Tags)
+	return &clone
+}
+
+// MatchesCriteria returns true if the data satisfies the tag, tech level and point criteria of the filter. The text
+// criteria is not checked, as that depends on how the data is being displayed. Data that doesn't carry a tech level or
+// points never satisfies a filter that requires them.
+func (f *SavedFilter) MatchesCriteria(data any) bool {
+	if len(f.Tags) != 0 {
+		tagged, ok := data.(interface{ TagList() []string })
+		if !ok {
+			return false
+		}
+		tags := tagged.TagList()
+		for _, tag := range f.Tags {
+			if !slices.ContainsFunc(tags, func(one string) bool { return TagMatches(tag, one) }) {
+				return false
+			}
+		}
+	}
+	if tl := strings.TrimSpace(f.TechLevel); tl != "" {
+		itemTL, ok := filterTechLevel(data)
+		if !ok || !strings.EqualFold(strings.TrimSpace(itemTL), tl) {
+			return false
+		}
+	}
+	if f.PointRange {
+		points, ok := filterPoints(data)
+		if !ok || points < f.MinPoints || points > f.MaxPoints {
+			return false
+		}
+	}
+	return true
+}
+
+func filterTechLevel(data any) (string, bool) {
+	switch item := data.(type) {
+	case *Equipment:
+		return item.TechLevel, true
+	case *EquipmentModifier:
+		return item.TechLevel, true
+	case *Skill:
+		if item.TechLevel != nil {
+			return *item.TechLevel, true
+		}
+	case *Spell:
+		if item.TechLevel != nil {
+			return *item.TechLevel, true
+		}
+	}
+	return "", false
+}
+
+func filterPoints(data any) (fxp.Int, bool) {
+	switch item := data.(type) {
+	case *Trait:
+		return item.AdjustedPoints(), true
+	case *Skill:
+		return item.AdjustedPoints(nil), true
+	case *Spell:
+		return item.AdjustedPoints(nil), true
+	}
+	return 0, false
+}
+
+// SavedFiltersFor returns the saved filters for list tables with the given key, which is the file extension of the list.
+func (s *Settings) SavedFiltersFor(key string) []*SavedFilter {
+	return s.SavedFilters[key]
+}
+
+// SetSavedFiltersFor sets the saved filters for list tables with the given key, which is the file extension of the list.
+func (s *Settings) SetSavedFiltersFor(key string, filters []*SavedFilter) {
+	if len(filters) == 0 {
+		delete(s.SavedFilters, key)
+		return
+	}
+	if s.SavedFilters == nil {
+		s.SavedFilters = make(map[string][]*SavedFilter)
+	}
+	s.SavedFilters[key] = filters
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSavedFilterCriteria(t *testing.T) {
+	c := check.New(t)
+	sword := NewEquipment(nil, nil, false)
+	sword.Tags = []string{"Weapon", "Melee Weapon"}
+	sword.TechLevel = "3"
+	ring := NewEquipment(nil, nil, false)
+	ring.Tags = []string{"Magic Item"}
+
+	filter := &SavedFilter{Tags: []string{"Weapon"}}
+	c.True(filter.MatchesCriteria(sword))
+	c.False(filter.MatchesCriteria(ring))
+
+	filter = &SavedFilter{TechLevel: " 3 "}
+	c.True(filter.MatchesCriteria(sword))
+	c.False(filter.MatchesCriteria(ring))
+
+	trait := NewTrait(nil, nil, false)
+	trait.BasePoints = fxp.FromInteger(15)
+	filter = &SavedFilter{PointRange: true, MinPoints: fxp.FromInteger(10), MaxPoints: fxp.FromInteger(20)}
+	c.True(filter.MatchesCriteria(trait))
+	trait.BasePoints = fxp.FromInteger(25)
+	c.False(filter.MatchesCriteria(trait))
+	c.False(filter.MatchesCriteria(sword))
+}
+
+func TestSettingsSavedFilters(t *testing.T) {
+	c := check.New(t)
+	var s Settings
+	c.Nil(s.SavedFiltersFor(EquipmentExt))
+	filters := []*SavedFilter{{Name: "Weapons", Tags: []string{"Weapon"}, Pinned: true}}
+	s.SetSavedFiltersFor(EquipmentExt, filters)
+	c.Equal(filters, s.SavedFiltersFor(EquipmentExt))
+	clone := filters[0].Clone()
+	clone.Tags[0] = "Armor"
+	c.Equal("Weapon", filters[0].Tags[0])
+	s.SetSavedFiltersFor(EquipmentExt, nil)
+	c.Nil(s.SavedFiltersFor(EquipmentExt))
+}
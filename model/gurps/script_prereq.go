@@ -16,6 +16,7 @@ import (
 
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/prereq"
 	"github.com/richardwilkes/gcs/v5/model/nameable"
+	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/xbytes"
 	"github.com/richardwilkes/toolbox/v2/xhash"
 )
@@ -97,3 +98,11 @@ func (s *ScriptPrereq) Satisfied(entity *Entity, exclude any, tooltip *xbytes.In
 	}
 	return true
 }
+
+func (s *ScriptPrereq) explain(entity *Entity, exclude any) (requirement, current string) {
+	var buffer xbytes.InsertBuffer
+	if !s.Satisfied(entity, exclude, &buffer, "", nil) {
+		return buffer.String(), ""
+	}
+	return i18n.Text("Script conditions are met"), ""
+}
@@ -34,19 +34,10 @@ var (
 	embeddedScriptRegex = regexp.MustCompile(`(?s)` + scriptStart + `.*?` + scriptEnd)
 	scriptCache         = make(map[string]*goja.Program)
 	globalResolveCache  = make(map[scriptResolveKey]string)
-	vmPool              = sync.Pool{New: func() any {
-		vm := goja.New()
-		vm.SetFieldNameMapper(scriptNameMapper{})
-		vm.SetParserOptions(parser.WithDisableSourceMaps)
-		mustSet(vm, "console", scriptConsole{})
-		mustSet(vm, "dice", scriptDice{})
-		mustSet(vm, "iff", scriptIff)
-		mustSet(vm, "measure", scriptMeasurement{})
-		mustSet(vm, "Math.exp2", math.Exp2)
-		mustSet(vm, "signedValue", scriptSigned)
-		mustSet(vm, "formatNum", scriptFormatNum)
-		return vm
-	}}
+	vmPool              = sync.Pool{New: func() any { return newScriptVM() }}
+	// scriptLock guards scriptCache and globalResolveCache, since entities may be loaded and recalculated on
+	// background goroutines.
+	scriptLock sync.Mutex
 )
 
 // ScriptSelfProvider is a provider for the "self" variable in scripts.
@@ -76,11 +67,27 @@ type ScriptArg struct {
 
 // DiscardGlobalResolveCache clears the global resolve cache.
 func DiscardGlobalResolveCache() {
+	scriptLock.Lock()
+	defer scriptLock.Unlock()
 	if len(globalResolveCache) != 0 {
 		globalResolveCache = make(map[scriptResolveKey]string)
 	}
 }
 
+func newScriptVM() *goja.Runtime {
+	vm := goja.New()
+	vm.SetFieldNameMapper(scriptNameMapper{})
+	vm.SetParserOptions(parser.WithDisableSourceMaps)
+	mustSet(vm, "console", scriptConsole{})
+	mustSet(vm, "dice", scriptDice{})
+	mustSet(vm, "iff", scriptIff)
+	mustSet(vm, "measure", scriptMeasurement{})
+	mustSet(vm, "Math.exp2", math.Exp2)
+	mustSet(vm, "signedValue", scriptSigned)
+	mustSet(vm, "formatNum", scriptFormatNum)
+	return vm
+}
+
 func mustSet(vm *goja.Runtime, name string, value any) {
 	if err := vm.Set(name, value); err != nil {
 		panic(errs.Newf("failed to set %s: %s", name, err.Error()))
@@ -136,25 +143,25 @@ func ResolveToWeight(entity *Entity, selfProvider ScriptSelfProvider, text strin
 
 const maximumAllowedResolvingDepth = 20
 
+// scriptResolvingDepth tracks the depth of scripts that aren't resolved for an entity. Those that are use the entity's
+// own counter, so that separate entities may be worked on concurrently.
 var scriptResolvingDepth = 0
 
 // ResolveScript will process a script.
 func ResolveScript(entity *Entity, selfProvider ScriptSelfProvider, text string) string {
-	scriptResolvingDepth++
+	depth := &scriptResolvingDepth
+	if entity != nil {
+		depth = &entity.scriptResolvingDepth
+	}
+	*depth++
 	defer func() {
-		scriptResolvingDepth--
+		*depth--
 	}()
-	if scriptResolvingDepth > maximumAllowedResolvingDepth {
+	if *depth > maximumAllowedResolvingDepth {
 		return "script resolution exceeded maximum depth (possible circular reference)"
 	}
-	var resolveCache map[scriptResolveKey]string
-	if entity == nil {
-		resolveCache = globalResolveCache
-	} else {
-		resolveCache = entity.scriptCache
-	}
 	key := scriptResolveKey{id: selfProvider.ResolveID(), text: text}
-	if cached, exists := resolveCache[key]; exists {
+	if cached, exists := lookupResolvedScript(entity, key); exists {
 		return cached
 	}
 	var result string
@@ -197,16 +204,39 @@ func ResolveScript(entity *Entity, selfProvider ScriptSelfProvider, text string)
 	} else {
 		xos.SafeCall(func() { result = v.String() }, func(panicErr error) { result = panicErr.Error() })
 	}
-	resolveCache[key] = result
+	storeResolvedScript(entity, key, result)
 	return result
 }
 
+func lookupResolvedScript(entity *Entity, key scriptResolveKey) (string, bool) {
+	if entity != nil {
+		result, exists := entity.scriptCache[key]
+		return result, exists
+	}
+	scriptLock.Lock()
+	defer scriptLock.Unlock()
+	result, exists := globalResolveCache[key]
+	return result, exists
+}
+
+func storeResolvedScript(entity *Entity, key scriptResolveKey, result string) {
+	if entity != nil {
+		entity.scriptCache[key] = result
+		return
+	}
+	scriptLock.Lock()
+	globalResolveCache[key] = result
+	scriptLock.Unlock()
+}
+
 // runScript compiles and runs a script with the provided arguments. A timeout of 0 or less means no timeout.
 // The script should be a valid JavaScript function body, and it will be wrapped in an anonymous function to avoid
 // polluting the global scope. The arguments will be set as global variables in the script's context. The return value
 // is the result of the script execution, or an error if it fails.
 func runScript(timeout time.Duration, text string, args ...ScriptArg) (goja.Value, error) {
+	scriptLock.Lock()
 	program, exists := scriptCache[text]
+	scriptLock.Unlock()
 	if !exists {
 		jsBytes, err := json.Marshal(text)
 		if err != nil {
@@ -216,7 +246,9 @@ func runScript(timeout time.Duration, text string, args ...ScriptArg) (goja.Valu
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile script: %w", err)
 		}
+		scriptLock.Lock()
 		scriptCache[text] = program
+		scriptLock.Unlock()
 	}
 	vm, ok := vmPool.Get().(*goja.Runtime)
 	if !ok {
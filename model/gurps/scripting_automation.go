@@ -0,0 +1,399 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/attribute"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+// Automation script constants.
+const (
+	AutomationScriptsDirName = "Scripts"
+	AutomationScriptExt      = ".js"
+)
+
+// AutomationResult holds the results of running an automation script.
+type AutomationResult struct {
+	// Value is the value of the last statement executed by the script.
+	Value string
+	// Output holds the messages written with console.log() and console.error().
+	Output []string
+	// Issues holds the problems reported with report().
+	Issues []string
+	// Modified is true if the script changed the entity.
+	Modified bool
+}
+
+type automationConsole struct {
+	result *AutomationResult
+}
+
+func (c automationConsole) Log(args ...any) {
+	c.result.Output = append(c.result.Output, makeLogMsg(args...))
+}
+
+func (c automationConsole) Error(args ...any) {
+	c.result.Output = append(c.result.Output, "ERROR: "+makeLogMsg(args...))
+}
+
+// scriptRecordField describes a field of a node that an automation script may read and, if set is not nil, change.
+// set returns true if the value changed.
+type scriptRecordField[T NodeTypes] struct {
+	name    string
+	applies func(T) bool
+	get     func(T) any
+	set     func(T, goja.Value) bool
+}
+
+// RunAutomationScript runs a script that may inspect and modify the entity. In addition to the "entity" object that the
+// scripts embedded in fields have access to, these scripts have a "sheet" object for making changes and a report()
+// function for reporting problems, such as failed house rule validations. Console output is captured rather than
+// logged. A timeout of 0 or less means no timeout. The entity is recalculated if the script changed it.
+func RunAutomationScript(entity *Entity, text string, timeout time.Duration) (*AutomationResult, error) {
+	if entity == nil {
+		return nil, errs.New("no entity")
+	}
+	result := &AutomationResult{}
+	vm := newScriptVM()
+	mustSet(vm, "console", automationConsole{result: result})
+	mustSet(vm, "report", func(call goja.FunctionCall) goja.Value {
+		result.Issues = append(result.Issues, callArgAsString(call, 0))
+		return goja.Undefined()
+	})
+	mustSet(vm, "entity", newScriptEntity(vm, entity))
+	mustSet(vm, "sheet", newScriptSheet(vm, entity, result))
+	for _, attr := range entity.Attributes.List() {
+		if def := attr.AttributeDef(); def != nil && !def.IsSeparator() {
+			mustSet(vm, "$"+attr.AttrID, newScriptAttribute(vm, attr))
+		}
+	}
+	if timeout > 0 {
+		defer time.AfterFunc(timeout, func() { vm.Interrupt("timeout") }).Stop()
+	}
+	var v goja.Value
+	var err error
+	xos.SafeCall(func() { v, err = vm.RunString(text) }, func(panicErr error) { err = panicErr })
+	if result.Modified {
+		entity.Recalculate()
+	}
+	if err != nil {
+		var interruptedErr *goja.InterruptedError
+		if errors.As(err, &interruptedErr) {
+			return result, fmt.Errorf("script execution timed out (limited to %v)", timeout)
+		}
+		return result, err
+	}
+	if v != nil && !goja.IsUndefined(v) && !goja.IsNull(v) {
+		result.Value = v.String()
+	}
+	return result, nil
+}
+
+// newScriptSheet creates the "sheet" object given to automation scripts. It provides:
+//
+//	setAttribute(id, value)  sets the current value of an attribute; for pools, this adjusts the damage taken
+//	editProfile(fn)          calls fn with the profile; changes to its fields are applied afterward
+//	editTraits(fn)           calls fn for each trait, including those within containers
+//	editSkills(fn)           calls fn for each skill
+//	editSpells(fn)           calls fn for each spell
+//	editEquipment(fn)        calls fn for each piece of equipment, carried first, then other
+//	editNotes(fn)            calls fn for each note
+//
+// Each record passed to an edit function has read-only "id", "kind" and "container" fields along with the fields that
+// apply to its type. Changes made to the record are applied once fn returns. While the entity is locked, editTraits,
+// editSkills, editSpells and setAttribute for anything other than a pool throw an exception.
+func newScriptSheet(r *goja.Runtime, entity *Entity, result *AutomationResult) *goja.Object {
+	m := make(map[string]func() goja.Value)
+	m["setAttribute"] = func() goja.Value {
+		return r.ToValue(func(call goja.FunctionCall) goja.Value {
+			attr := entity.Attributes.Find(callArgAsTrimmedString(call, 0))
+			if attr == nil {
+				return r.ToValue(false)
+			}
+			value := fxp.FromFloat(call.Argument(1).ToFloat())
+			def := attr.AttributeDef()
+			pool := def != nil && (def.Type == attribute.Pool || def.Type == attribute.PoolRef)
+			if !pool {
+				checkScriptUnlocked(r, entity)
+			}
+			if attr.Current() == value {
+				return r.ToValue(true)
+			}
+			if pool {
+				attr.Damage = (attr.Maximum() - value).Max(0)
+			} else {
+				attr.SetMaximum(value)
+			}
+			result.Modified = true
+			return r.ToValue(true)
+		})
+	}
+	m["editProfile"] = func() goja.Value {
+		return r.ToValue(func(call goja.FunctionCall) goja.Value {
+			fn := scriptCallableArg(r, call, 0)
+			p := &entity.Profile
+			fields := []*string{
+				&p.Name, &p.PlayerName, &p.Title, &p.Organization, &p.Religion, &p.TechLevel, &p.Gender, &p.Age,
+				&p.Birthday, &p.Eyes, &p.Hair, &p.Skin, &p.Handedness,
+			}
+			names := []string{
+				"name", "playerName", "title", "organization", "religion", "techLevel", "gender", "age", "birthday",
+				"eyes", "hair", "skin", "handedness",
+			}
+			obj := r.NewObject()
+			for i, name := range names {
+				mustSetOnObject(obj, name, *fields[i])
+			}
+			if _, err := fn(goja.Undefined(), obj); err != nil {
+				panic(err)
+			}
+			for i, name := range names {
+				if s := obj.Get(name).String(); s != *fields[i] {
+					*fields[i] = s
+					result.Modified = true
+				}
+			}
+			return goja.Undefined()
+		})
+	}
+	m["editTraits"] = func() goja.Value {
+		return r.ToValue(func(call goja.FunctionCall) goja.Value {
+			checkScriptUnlocked(r, entity)
+			editScriptRecords(r, call, result, traitRecordFields(), entity.Traits...)
+			return goja.Undefined()
+		})
+	}
+	m["editSkills"] = func() goja.Value {
+		return r.ToValue(func(call goja.FunctionCall) goja.Value {
+			checkScriptUnlocked(r, entity)
+			editScriptRecords(r, call, result, skillRecordFields(), entity.Skills...)
+			return goja.Undefined()
+		})
+	}
+	m["editSpells"] = func() goja.Value {
+		return r.ToValue(func(call goja.FunctionCall) goja.Value {
+			checkScriptUnlocked(r, entity)
+			editScriptRecords(r, call, result, spellRecordFields(), entity.Spells...)
+			return goja.Undefined()
+		})
+	}
+	m["editEquipment"] = func() goja.Value {
+		return r.ToValue(func(call goja.FunctionCall) goja.Value {
+			editScriptRecords(r, call, result, equipmentRecordFields(true), entity.CarriedEquipment...)
+			editScriptRecords(r, call, result, equipmentRecordFields(false), entity.OtherEquipment...)
+			return goja.Undefined()
+		})
+	}
+	m["editNotes"] = func() goja.Value {
+		return r.ToValue(func(call goja.FunctionCall) goja.Value {
+			editScriptRecords(r, call, result, noteRecordFields(), entity.Notes...)
+			return goja.Undefined()
+		})
+	}
+	return r.NewDynamicObject(NewScriptObject(r, m))
+}
+
+// editScriptRecords calls the script function passed as the first argument with a record for each of the nodes and
+// their children, then applies any changes the function made to the records.
+func editScriptRecords[T NodeTypes](r *goja.Runtime, call goja.FunctionCall, result *AutomationResult,
+	fields []*scriptRecordField[T], data ...T) {
+	fn := scriptCallableArg(r, call, 0)
+	Traverse(func(one T) bool {
+		node := AsNode(one)
+		obj := r.NewObject()
+		mustSetOnObject(obj, "id", node.ID())
+		mustSetOnObject(obj, "kind", node.Kind())
+		mustSetOnObject(obj, "container", node.Container())
+		for _, f := range fields {
+			if f.applies == nil || f.applies(one) {
+				mustSetOnObject(obj, f.name, f.get(one))
+			}
+		}
+		if _, err := fn(goja.Undefined(), obj); err != nil {
+			panic(err)
+		}
+		for _, f := range fields {
+			if f.set != nil && (f.applies == nil || f.applies(one)) && f.set(one, obj.Get(f.name)) {
+				result.Modified = true
+			}
+		}
+		return false
+	}, false, false, data...)
+}
+
+// checkScriptUnlocked throws a script exception if the entity is locked, since its build data may not be changed.
+func checkScriptUnlocked(r *goja.Runtime, entity *Entity) {
+	if entity.Locked {
+		panic(r.NewGoError(errors.New(SheetLocked())))
+	}
+}
+
+func scriptCallableArg(r *goja.Runtime, call goja.FunctionCall, index int) goja.Callable {
+	fn, ok := goja.AssertFunction(call.Argument(index))
+	if !ok {
+		panic(r.NewTypeError("argument %d must be a function", index+1))
+	}
+	return fn
+}
+
+func mustSetOnObject(obj *goja.Object, name string, value any) {
+	if err := obj.Set(name, value); err != nil {
+		panic(errs.Newf("failed to set %s: %s", name, err.Error()))
+	}
+}
+
+func scriptStringField[T NodeTypes](name string, field func(T) *string) *scriptRecordField[T] {
+	return &scriptRecordField[T]{
+		name: name,
+		get:  func(one T) any { return *field(one) },
+		set: func(one T, v goja.Value) bool {
+			ptr := field(one)
+			if s := v.String(); s != *ptr {
+				*ptr = s
+				return true
+			}
+			return false
+		},
+	}
+}
+
+func scriptNumberField[T NodeTypes](name string, applies func(T) bool, field func(T) *fxp.Int) *scriptRecordField[T] {
+	return &scriptRecordField[T]{
+		name:    name,
+		applies: applies,
+		get:     func(one T) any { return fxp.AsFloat[float64](*field(one)) },
+		set: func(one T, v goja.Value) bool {
+			ptr := field(one)
+			if value := fxp.FromFloat(v.ToFloat()); value != *ptr {
+				*ptr = value
+				return true
+			}
+			return false
+		},
+	}
+}
+
+func scriptTagsField[T NodeTypes](field func(T) *[]string) *scriptRecordField[T] {
+	return &scriptRecordField[T]{
+		name: "tags",
+		get:  func(one T) any { return slices.Clone(*field(one)) },
+		set: func(one T, v goja.Value) bool {
+			var tags []string
+			switch list := v.Export().(type) {
+			case []string:
+				tags = slices.Clone(list)
+			case []any:
+				for _, tag := range list {
+					tags = append(tags, fmt.Sprint(tag))
+				}
+			}
+			ptr := field(one)
+			if !slices.Equal(tags, *ptr) {
+				*ptr = tags
+				return true
+			}
+			return false
+		},
+	}
+}
+
+func notContainer[T NodeTypes](one T) bool {
+	return !AsNode(one).Container()
+}
+
+func traitRecordFields() []*scriptRecordField[*Trait] {
+	return []*scriptRecordField[*Trait]{
+		scriptStringField("name", func(t *Trait) *string { return &t.Name }),
+		scriptStringField("notes", func(t *Trait) *string { return &t.LocalNotes }),
+		scriptStringField("pageRef", func(t *Trait) *string { return &t.PageRef }),
+		scriptTagsField(func(t *Trait) *[]string { return &t.Tags }),
+		{
+			name: "enabled",
+			get:  func(t *Trait) any { return !t.Disabled },
+			set: func(t *Trait, v goja.Value) bool {
+				if disabled := !v.ToBoolean(); disabled != t.Disabled {
+					t.Disabled = disabled
+					return true
+				}
+				return false
+			},
+		},
+		scriptNumberField("basePoints", notContainer[*Trait], func(t *Trait) *fxp.Int { return &t.BasePoints }),
+		scriptNumberField("level", func(t *Trait) bool { return !t.Container() && t.CanLevel },
+			func(t *Trait) *fxp.Int { return &t.Levels }),
+		{
+			name: "points",
+			get:  func(t *Trait) any { return fxp.AsFloat[float64](t.AdjustedPoints()) },
+		},
+	}
+}
+
+func skillRecordFields() []*scriptRecordField[*Skill] {
+	return []*scriptRecordField[*Skill]{
+		scriptStringField("name", func(s *Skill) *string { return &s.Name }),
+		scriptStringField("notes", func(s *Skill) *string { return &s.LocalNotes }),
+		scriptStringField("pageRef", func(s *Skill) *string { return &s.PageRef }),
+		scriptTagsField(func(s *Skill) *[]string { return &s.Tags }),
+		scriptStringField("specialization", func(s *Skill) *string { return &s.Specialization }),
+		scriptNumberField("points", notContainer[*Skill], func(s *Skill) *fxp.Int { return &s.Points }),
+		{
+			name:    "level",
+			applies: notContainer[*Skill],
+			get:     func(s *Skill) any { return fxp.AsFloat[float64](s.LevelData.Level) },
+		},
+	}
+}
+
+func spellRecordFields() []*scriptRecordField[*Spell] {
+	return []*scriptRecordField[*Spell]{
+		scriptStringField("name", func(s *Spell) *string { return &s.Name }),
+		scriptStringField("notes", func(s *Spell) *string { return &s.LocalNotes }),
+		scriptStringField("pageRef", func(s *Spell) *string { return &s.PageRef }),
+		scriptTagsField(func(s *Spell) *[]string { return &s.Tags }),
+		scriptNumberField("points", notContainer[*Spell], func(s *Spell) *fxp.Int { return &s.Points }),
+		{
+			name:    "level",
+			applies: notContainer[*Spell],
+			get:     func(s *Spell) any { return fxp.AsFloat[float64](s.LevelData.Level) },
+		},
+	}
+}
+
+func equipmentRecordFields(carried bool) []*scriptRecordField[*Equipment] {
+	return []*scriptRecordField[*Equipment]{
+		scriptStringField("name", func(e *Equipment) *string { return &e.Name }),
+		scriptStringField("notes", func(e *Equipment) *string { return &e.LocalNotes }),
+		scriptStringField("pageRef", func(e *Equipment) *string { return &e.PageRef }),
+		scriptTagsField(func(e *Equipment) *[]string { return &e.Tags }),
+		scriptStringField("techLevel", func(e *Equipment) *string { return &e.TechLevel }),
+		scriptStringField("legalityClass", func(e *Equipment) *string { return &e.LegalityClass }),
+		scriptNumberField("quantity", nil, func(e *Equipment) *fxp.Int { return &e.Quantity }),
+		{
+			name: "carried",
+			get:  func(_ *Equipment) any { return carried },
+		},
+	}
+}
+
+func noteRecordFields() []*scriptRecordField[*Note] {
+	return []*scriptRecordField[*Note]{
+		scriptStringField("text", func(n *Note) *string { return &n.MarkDown }),
+		scriptStringField("pageRef", func(n *Note) *string { return &n.PageRef }),
+	}
+}
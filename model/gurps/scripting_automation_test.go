@@ -0,0 +1,83 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestRunAutomationScript(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	luck := NewTrait(e, nil, false)
+	luck.Name = "Luck"
+	luck.BasePoints = fxp.FromInteger(15)
+	fit := NewTrait(e, nil, false)
+	fit.Name = "Fit"
+	fit.BasePoints = fxp.Five
+	fit.Tags = []string{"Physical"}
+	e.SetTraitList([]*Trait{luck, fit})
+
+	result, err := RunAutomationScript(e, `
+sheet.editTraits(t => {
+	t.tags = t.tags.concat(["House Rule"]);
+	if (t.name === "Luck") {
+		t.basePoints = 10;
+	}
+});
+sheet.setAttribute("st", 12);
+sheet.editProfile(p => { p.name = "Tester"; });
+if (entity.traits.length > 1) {
+	report("too many traits");
+}
+console.log("done", entity.traits.length);
+"ok";
+`, time.Second)
+	c.NoError(err)
+	c.True(result.Modified)
+	c.Equal("ok", result.Value)
+	c.Equal([]string{"done 2"}, result.Output)
+	c.Equal([]string{"too many traits"}, result.Issues)
+	c.Equal([]string{"House Rule"}, luck.Tags)
+	c.Equal([]string{"Physical", "House Rule"}, fit.Tags)
+	c.Equal(fxp.Ten, luck.BasePoints)
+	c.Equal(fxp.Five, fit.BasePoints)
+	c.Equal(fxp.FromInteger(12), e.Attributes.Current("st"))
+	c.Equal("Tester", e.Profile.Name)
+
+	result, err = RunAutomationScript(e, `sheet.editTraits(t => { t.points = 100; }); sheet.editSkills(s => {});`,
+		time.Second)
+	c.NoError(err)
+	c.False(result.Modified)
+	c.Equal(fxp.Ten, luck.BasePoints)
+
+	_, err = RunAutomationScript(e, `sheet.editTraits(5);`, time.Second)
+	c.HasError(err)
+
+	_, err = RunAutomationScript(e, `while (true) {}`, 10*time.Millisecond)
+	c.HasError(err)
+}
+
+func TestRunAutomationScriptSetsPoolCurrent(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	hp := e.Attributes.Find("hp")
+	maximum := hp.Maximum()
+	result, err := RunAutomationScript(e, `sheet.setAttribute("hp", $hp.maximum - 3);`, time.Second)
+	c.NoError(err)
+	c.True(result.Modified)
+	c.Equal(maximum, hp.Maximum(), "the maximum is left alone")
+	c.Equal(maximum-fxp.Three, hp.Current())
+	c.Equal(fxp.Three, hp.Damage)
+}
@@ -0,0 +1,484 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/richardwilkes/gcs/v5/model/diag"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+const currentSearchIndexVersion = 1
+
+// Indexes into searchEntry.Fields.
+const (
+	searchNameField = iota
+	searchNotesField
+	searchTagsField
+	searchVTTNotesField
+	searchFieldCount
+)
+
+// SearchHit describes an item that matched a search.
+type SearchHit struct {
+	Kind string
+	Name string
+	ID   tid.TID
+	// FilePath is the file the item was found in, if any.
+	FilePath string
+	// Matched describes the part of the item that matched.
+	Matched string
+}
+
+// SearchIndexStatus describes the contents of a SearchIndex.
+type SearchIndexStatus struct {
+	Files int
+	Items int
+	Built time.Time
+}
+
+type searchEntry struct {
+	Kind   string                   `json:"kind"`
+	Name   string                   `json:"name"`
+	ID     tid.TID                  `json:"id"`
+	Fields [searchFieldCount]string `json:"fields"`
+}
+
+type searchFile struct {
+	ModTime time.Time      `json:"mod_time"`
+	Entries []*searchEntry `json:"entries,omitzero"`
+}
+
+type searchIndexData struct {
+	Version int                    `json:"version"`
+	Built   time.Time              `json:"built"`
+	Files   map[string]*searchFile `json:"files,omitzero"`
+}
+
+type indexedEntry struct {
+	path  string
+	entry *searchEntry
+}
+
+type searchPosting struct {
+	ref   *indexedEntry
+	field int
+}
+
+// SearchIndex holds an inverted index of the words within the items of library files so that they can be searched
+// quickly. Files are only re-read when they have changed on disk, and the index may be saved and loaded to avoid
+// re-reading them on each launch.
+type SearchIndex struct {
+	lock     sync.Mutex
+	data     searchIndexData
+	postings map[string][]searchPosting
+	words    []string
+}
+
+// SearchIndexPath returns the path to the file the library search index is stored in.
+func SearchIndexPath() string {
+	return filepath.Join(filepath.Dir(SettingsPath), xos.AppCmdName+"_search_index.json")
+}
+
+// NewSearchIndex creates a new, empty, SearchIndex.
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{data: searchIndexData{
+		Version: currentSearchIndexVersion,
+		Files:   make(map[string]*searchFile),
+	}}
+}
+
+// Load the index from a file. An index written by a different version is ignored.
+func (s *SearchIndex) Load(filePath string) error {
+	var data searchIndexData
+	if err := jio.Load(nil, filePath, &data); err != nil {
+		return err
+	}
+	if data.Version != currentSearchIndexVersion {
+		return nil
+	}
+	if data.Files == nil {
+		data.Files = make(map[string]*searchFile)
+	}
+	s.lock.Lock()
+	s.data = data
+	s.postings = nil
+	s.lock.Unlock()
+	return nil
+}
+
+// Save the index to a file.
+func (s *SearchIndex) Save(filePath string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return jio.SaveToFile(filePath, &s.data)
+}
+
+// Status returns the current status of the index.
+func (s *SearchIndex) Status() SearchIndexStatus {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	status := SearchIndexStatus{
+		Files: len(s.data.Files),
+		Built: s.data.Built,
+	}
+	for _, f := range s.data.Files {
+		status.Items += len(f.Entries)
+	}
+	return status
+}
+
+// Rebuild discards the current contents of the index and then calls Refresh.
+func (s *SearchIndex) Rebuild(progress func(done, total int), dirs ...string) {
+	s.lock.Lock()
+	s.data.Files = make(map[string]*searchFile)
+	s.data.Built = time.Time{}
+	s.postings = nil
+	s.lock.Unlock()
+	s.Refresh(progress, dirs...)
+}
+
+// Refresh updates the index from the library files found within the directories. If progress is not nil, it is called
+// before each file is examined and once more when all of them have been. Returns true if anything changed.
+func (s *SearchIndex) Refresh(progress func(done, total int), dirs ...string) bool {
+	defer diag.Track(diag.LibraryIndexKey, time.Now())
+	type candidate struct {
+		path    string
+		ext     string
+		modTime time.Time
+	}
+	var candidates []candidate
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error { //nolint:errcheck // We want to continue on even if there was an error
+			if err != nil {
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") && p != dir {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			fi := FileInfoFor(p)
+			if fi == nil || len(fi.Extensions) == 0 {
+				return nil
+			}
+			var info fs.FileInfo
+			if info, err = d.Info(); err != nil {
+				return nil
+			}
+			candidates = append(candidates, candidate{
+				path:    p,
+				ext:     fi.Extensions[0],
+				modTime: info.ModTime(),
+			})
+			return nil
+		})
+	}
+	seen := make(map[string]struct{}, len(candidates))
+	changed := false
+	for i, one := range candidates {
+		if progress != nil {
+			progress(i, len(candidates))
+		}
+		seen[one.path] = struct{}{}
+		s.lock.Lock()
+		existing, ok := s.data.Files[one.path]
+		s.lock.Unlock()
+		if ok && existing.ModTime.Equal(one.modTime) {
+			continue
+		}
+		entries := indexLibraryFile(os.DirFS(filepath.Dir(one.path)), filepath.Base(one.path), one.ext)
+		s.lock.Lock()
+		s.data.Files[one.path] = &searchFile{
+			ModTime: one.modTime,
+			Entries: entries,
+		}
+		s.lock.Unlock()
+		changed = true
+	}
+	if progress != nil {
+		progress(len(candidates), len(candidates))
+	}
+	s.lock.Lock()
+	for p := range s.data.Files {
+		if _, ok := seen[p]; !ok {
+			delete(s.data.Files, p)
+			changed = true
+		}
+	}
+	if changed || s.data.Built.IsZero() {
+		changed = true
+		s.data.Built = time.Now()
+		s.postings = nil
+	}
+	s.lock.Unlock()
+	return changed
+}
+
+// Search returns the indexed items that have a word starting with each of the words in the text within their name,
+// notes, tags, or VTT notes. Case is ignored.
+func (s *SearchIndex) Search(text string) []*SearchHit {
+	words := searchWords(text)
+	if len(words) == 0 {
+		return nil
+	}
+	s.lock.Lock()
+	if s.postings == nil {
+		s.buildPostings()
+	}
+	var candidates map[*indexedEntry]int
+	for i, word := range words {
+		matches := make(map[*indexedEntry]int)
+		for j, _ := slices.BinarySearch(s.words, word); j < len(s.words) && strings.HasPrefix(s.words[j], word); j++ {
+			for _, p := range s.postings[s.words[j]] {
+				if field, ok := matches[p.ref]; !ok || p.field < field {
+					matches[p.ref] = p.field
+				}
+			}
+		}
+		if i == 0 {
+			candidates = matches
+		} else {
+			maps.DeleteFunc(candidates, func(ref *indexedEntry, _ int) bool {
+				_, ok := matches[ref]
+				return !ok
+			})
+		}
+		if len(candidates) == 0 {
+			break
+		}
+	}
+	s.lock.Unlock()
+	hits := make([]*SearchHit, 0, len(candidates))
+	for ref, field := range candidates {
+		hits = append(hits, ref.entry.hit(ref.path, field))
+	}
+	SortSearchHits(hits)
+	return hits
+}
+
+func (s *SearchIndex) buildPostings() {
+	s.postings = make(map[string][]searchPosting)
+	for p, f := range s.data.Files {
+		for _, entry := range f.Entries {
+			ref := &indexedEntry{
+				path:  p,
+				entry: entry,
+			}
+			for field, text := range entry.Fields {
+				for _, word := range searchWords(text) {
+					list := s.postings[word]
+					if len(list) == 0 || list[len(list)-1].ref != ref {
+						s.postings[word] = append(list, searchPosting{
+							ref:   ref,
+							field: field,
+						})
+					}
+				}
+			}
+		}
+	}
+	s.words = slices.Sorted(maps.Keys(s.postings))
+}
+
+// SearchNodes appends to hits the nodes, along with their children and modifiers, that have a word starting with each
+// of the words in the text within their name, notes, tags, or VTT notes. Case is ignored.
+func SearchNodes[T NodeTypes](hits []*SearchHit, filePath, text string, data ...T) []*SearchHit {
+	words := searchWords(text)
+	if len(words) == 0 {
+		return hits
+	}
+	for _, entry := range indexNodes(nil, data...) {
+		if field := entry.match(words); field != -1 {
+			hits = append(hits, entry.hit(filePath, field))
+		}
+	}
+	return hits
+}
+
+// SearchNodesInProvider calls SearchNodes for each of the lists the provider supports.
+func SearchNodesInProvider(hits []*SearchHit, filePath, text string, provider any) []*SearchHit {
+	if p, ok := provider.(TraitListProvider); ok {
+		hits = SearchNodes(hits, filePath, text, p.TraitList()...)
+	}
+	if p, ok := provider.(SkillListProvider); ok {
+		hits = SearchNodes(hits, filePath, text, p.SkillList()...)
+	}
+	if p, ok := provider.(SpellListProvider); ok {
+		hits = SearchNodes(hits, filePath, text, p.SpellList()...)
+	}
+	if p, ok := provider.(EquipmentListProvider); ok {
+		hits = SearchNodes(hits, filePath, text, p.CarriedEquipmentList()...)
+		hits = SearchNodes(hits, filePath, text, p.OtherEquipmentList()...)
+	}
+	if p, ok := provider.(NoteListProvider); ok {
+		hits = SearchNodes(hits, filePath, text, p.NoteList()...)
+	}
+	return hits
+}
+
+// SortSearchHits sorts the hits by kind, then name, then file path.
+func SortSearchHits(hits []*SearchHit) {
+	slices.SortFunc(hits, CompareSearchHits)
+}
+
+// CompareSearchHits compares two hits by kind, then name, then file path.
+func CompareSearchHits(a, b *SearchHit) int {
+	if result := xstrings.NaturalCmp(a.Kind, b.Kind, true); result != 0 {
+		return result
+	}
+	if result := xstrings.NaturalCmp(a.Name, b.Name, true); result != 0 {
+		return result
+	}
+	return xstrings.NaturalCmp(a.FilePath, b.FilePath, true)
+}
+
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// match returns the index of the field the first word was found in, or -1 if one or more of the words could not be
+// found in any field.
+func (e *searchEntry) match(words []string) int {
+	var fieldWords [searchFieldCount][]string
+	for i, text := range e.Fields {
+		fieldWords[i] = searchWords(text)
+	}
+	first := -1
+	for i, word := range words {
+		found := -1
+		for field, list := range fieldWords {
+			if slices.ContainsFunc(list, func(one string) bool { return strings.HasPrefix(one, word) }) {
+				found = field
+				break
+			}
+		}
+		if found == -1 {
+			return -1
+		}
+		if i == 0 {
+			first = found
+		}
+	}
+	return first
+}
+
+func (e *searchEntry) hit(filePath string, field int) *SearchHit {
+	var matched string
+	switch field {
+	case searchNameField:
+		matched = i18n.Text("Name")
+	case searchNotesField:
+		matched = i18n.Text("Notes")
+	case searchTagsField:
+		matched = i18n.Text("Tags")
+	default:
+		matched = i18n.Text("VTT Notes")
+	}
+	return &SearchHit{
+		Kind:     e.Kind,
+		Name:     e.Name,
+		ID:       e.ID,
+		FilePath: filePath,
+		Matched:  matched,
+	}
+}
+
+func indexLibraryFile(dir fs.FS, file, ext string) []*searchEntry {
+	switch ext {
+	case TraitsExt:
+		if data, err := NewTraitsFromFile(dir, file); err == nil {
+			return indexNodes(nil, data...)
+		}
+	case TraitModifiersExt:
+		if data, err := NewTraitModifiersFromFile(dir, file); err == nil {
+			return indexNodes(nil, data...)
+		}
+	case SkillsExt:
+		if data, err := NewSkillsFromFile(dir, file); err == nil {
+			return indexNodes(nil, data...)
+		}
+	case SpellsExt:
+		if data, err := NewSpellsFromFile(dir, file); err == nil {
+			return indexNodes(nil, data...)
+		}
+	case EquipmentExt:
+		if data, err := NewEquipmentFromFile(dir, file); err == nil {
+			return indexNodes(nil, data...)
+		}
+	case EquipmentModifiersExt:
+		if data, err := NewEquipmentModifiersFromFile(dir, file); err == nil {
+			return indexNodes(nil, data...)
+		}
+	case NotesExt:
+		if data, err := NewNotesFromFile(dir, file); err == nil {
+			return indexNodes(nil, data...)
+		}
+	}
+	return nil
+}
+
+func indexNodes[T NodeTypes](entries []*searchEntry, data ...T) []*searchEntry {
+	Traverse(func(one T) bool {
+		node := AsNode(one)
+		entry := &searchEntry{
+			Kind: node.Kind(),
+			Name: node.String(),
+			ID:   node.ID(),
+		}
+		var notes, vttNotes string
+		switch n := any(one).(type) {
+		case *Trait:
+			notes, vttNotes = n.LocalNotes, n.VTTNotes
+			entries = indexNodes(entries, n.Modifiers...)
+		case *TraitModifier:
+			notes, vttNotes = n.LocalNotes, n.VTTNotes
+		case *Skill:
+			notes, vttNotes = n.LocalNotes, n.VTTNotes
+		case *Spell:
+			notes, vttNotes = n.LocalNotes, n.VTTNotes
+		case *Equipment:
+			notes, vttNotes = n.LocalNotes, n.VTTNotes
+			entries = indexNodes(entries, n.Modifiers...)
+		case *EquipmentModifier:
+			notes, vttNotes = n.LocalNotes, n.VTTNotes
+		case *Note:
+			notes = n.MarkDown
+		}
+		entry.Fields[searchNameField] = entry.Name
+		entry.Fields[searchNotesField] = notes
+		if tags := tagsOf(one); tags != nil {
+			entry.Fields[searchTagsField] = CombineTags(*tags)
+		}
+		entry.Fields[searchVTTNotesField] = vttNotes
+		entries = append(entries, entry)
+		return false
+	}, false, false, data...)
+	return entries
+}
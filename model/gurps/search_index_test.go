@@ -0,0 +1,129 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSearchNodes(t *testing.T) {
+	c := check.New(t)
+	container := NewTrait(nil, nil, true)
+	container.Name = "Magery"
+	child := NewTrait(nil, container, false)
+	child.Name = "Fire Talent"
+	child.Tags = []string{"DF/Wizard"}
+	child.VTTNotes = "flame"
+	container.Children = []*Trait{child}
+	note := NewNote(nil, nil, false)
+	note.MarkDown = "Wizards keep their flames close"
+
+	hits := SearchNodes(nil, "a.gct", "MAGERY", container)
+	c.Equal(1, len(hits))
+	c.Equal("Magery", hits[0].Name)
+	c.Equal(container.ID(), hits[0].ID)
+
+	hits = SearchNodes(nil, "a.gct", "wizard", container)
+	c.Equal(1, len(hits))
+	c.Equal("Fire Talent", hits[0].Name)
+	c.Equal("Tags", hits[0].Matched)
+
+	hits = SearchNodes(hits, "b.gcn", "flame", container)
+	hits = SearchNodes(hits, "b.gcn", "flame", note)
+	c.Equal(3, len(hits))
+	c.Equal("VTT Notes", hits[1].Matched)
+	c.Equal("Notes", hits[2].Matched)
+
+	hits = SearchNodes(nil, "a.gct", "fire tal", container)
+	c.Equal(1, len(hits))
+	c.Equal(0, len(SearchNodes(nil, "a.gct", "fire wand", container)))
+	c.Equal(0, len(SearchNodes(nil, "a.gct", "  ", container)))
+}
+
+func TestSearchIndex(t *testing.T) {
+	c := check.New(t)
+	if FileInfoFor("a"+TraitsExt) == nil {
+		(&FileInfo{Name: "Traits", Extensions: []string{TraitsExt}, IsGCSData: true}).Register()
+	}
+	dir := t.TempDir()
+	writeTraits := func(name string, modTime time.Time, traits ...*Trait) string {
+		p := filepath.Join(dir, name+TraitsExt)
+		c.NoError(SaveTraits(traits, p))
+		c.NoError(os.Chtimes(p, modTime, modTime))
+		return p
+	}
+	newTrait := func(name, notes string, tags ...string) *Trait {
+		trait := NewTrait(nil, nil, false)
+		trait.Name = name
+		trait.LocalNotes = notes
+		trait.Tags = tags
+		return trait
+	}
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	firePath := writeTraits("fire", start, newTrait("Fire Talent", "Adds to fire spells", "Wizard"))
+	waterPath := writeTraits("water", start, newTrait("Water Talent", "Wizards of the deep", "Mage"))
+
+	index := NewSearchIndex()
+	c.True(index.Refresh(nil, dir))
+	c.Equal(2, index.Status().Files)
+	c.False(index.Refresh(nil, dir), "unchanged files are not re-read")
+
+	// Each word must be found, but the field reported is the lowest one the first word was found in.
+	hits := index.Search("fire wiz")
+	c.Equal(1, len(hits))
+	c.Equal("Fire Talent", hits[0].Name)
+	c.Equal(firePath, hits[0].FilePath)
+	c.Equal("Name", hits[0].Matched)
+	hits = index.Search("wiz TAL")
+	c.Equal(2, len(hits))
+	c.Equal("Fire Talent", hits[0].Name)
+	c.Equal("Tags", hits[0].Matched)
+	c.Equal("Water Talent", hits[1].Name)
+	c.Equal("Notes", hits[1].Matched)
+	c.Equal(0, len(index.Search("fire mage")))
+
+	indexPath := filepath.Join(t.TempDir(), "search_index.json")
+	c.NoError(index.Save(indexPath))
+	loaded := NewSearchIndex()
+	c.NoError(loaded.Load(indexPath))
+	status := loaded.Status()
+	c.Equal(index.Status().Files, status.Files)
+	c.Equal(index.Status().Items, status.Items)
+	c.True(index.Status().Built.Equal(status.Built))
+	c.Equal(2, len(loaded.Search("talent")))
+	c.False(loaded.Refresh(nil, dir), "the modification times survive the round trip")
+
+	writeTraits("fire", start.Add(time.Minute), newTrait("Flame Talent", "Adds to fire spells", "Wizard"))
+	c.True(loaded.Refresh(nil, dir))
+	hits = loaded.Search("talent")
+	c.Equal(2, len(hits))
+	c.Equal("Flame Talent", hits[0].Name)
+	c.Equal("Water Talent", hits[1].Name)
+
+	c.NoError(os.Remove(waterPath))
+	c.True(loaded.Refresh(nil, dir))
+	c.Equal(1, loaded.Status().Files)
+	c.Equal(0, len(loaded.Search("water")))
+
+	c.Equal(1, len(index.Search("water")))
+	writeTraits("fire", start.Add(2*time.Minute), newTrait("Ice Talent", "", "Wizard"))
+	var done, total int
+	index.Rebuild(func(d, n int) { done, total = d, n }, dir)
+	c.Equal(1, total)
+	c.Equal(total, done)
+	c.Equal(0, len(index.Search("water")), "postings for removed files are discarded")
+	c.Equal(0, len(index.Search("fire")), "postings for replaced items are discarded")
+	c.Equal(1, len(index.Search("ice")))
+}
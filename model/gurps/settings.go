@@ -64,36 +64,41 @@ type NavNodeInfo struct {
 	LastUsed int64   `json:"last"`
 }
 
-// PDFInfo holds IDs and last opened timestamp for a PDF's table of contents.
+// PDFInfo holds IDs and last opened timestamp for a PDF's table of contents, along with any highlights the user has
+// placed within it.
 type PDFInfo struct {
 	TOC        map[string]map[int]tid.TID `json:"toc,omitzero"`
+	Highlights []*PDFHighlight            `json:"highlights,omitzero"`
 	LastOpened int64                      `json:"last"`
 }
 
 // Settings holds the application settings.
 type Settings struct {
-	LastSeenGCSVersion string                     `json:"last_seen_gcs_version,omitzero"`
-	General            *GeneralSettings           `json:"general,omitzero"`
-	LibrarySet         Libraries                  `json:"libraries,omitzero"`
-	LibraryExplorer    NavigatorSettings          `json:"library_explorer"`
-	ThemeMode          thememode.Enum             `json:"theme_mode"`
-	RecentFiles        []string                   `json:"recent_files,omitzero"`
-	DeepSearch         []string                   `json:"deep_search,omitzero"`
-	LastDirs           map[string]string          `json:"last_dirs,omitzero"`
-	ColumnSizing       map[string]map[int]float32 `json:"column_sizing,omitzero"`
-	PageRefs           PageRefs                   `json:"page_refs,omitzero"`
-	KeyBindings        KeyBindings                `json:"key_bindings,omitzero"`
-	WorkspaceFrame     *geom.Rect                 `json:"workspace_frame,omitzero"`
-	TopDockState       *unison.DockState          `json:"top_dock_state,omitzero"`
-	DocDockState       *unison.DockState          `json:"doc_dock_state,omitzero"`
-	Colors             colors.Colors              `json:"theme_colors"`
-	Fonts              fonts.Fonts                `json:"fonts"`
-	Sheet              *SheetSettings             `json:"sheet_settings,omitzero"`
-	OpenInWindow       []dgroup.Group             `json:"open_in_window,omitzero"`
-	Closed             map[string]int64           `json:"closed,omitzero"`
-	PDFs               map[string]*PDFInfo        `json:"pdfs,omitzero"`
-	LootGenMinValue    fxp.Int                    `json:"loot_gen_min_value"`
-	LootGenMaxValue    fxp.Int                    `json:"loot_gen_max_value"`
+	LastSeenGCSVersion string                      `json:"last_seen_gcs_version,omitzero"`
+	General            *GeneralSettings            `json:"general,omitzero"`
+	LibrarySet         Libraries                   `json:"libraries,omitzero"`
+	LibraryExplorer    NavigatorSettings           `json:"library_explorer"`
+	ThemeMode          thememode.Enum              `json:"theme_mode"`
+	RecentFiles        []string                    `json:"recent_files,omitzero"`
+	DeepSearch         []string                    `json:"deep_search,omitzero"`
+	LastDirs           map[string]string           `json:"last_dirs,omitzero"`
+	ColumnSizing       map[string]map[int]float32  `json:"column_sizing,omitzero"`
+	SavedFilters       map[string][]*SavedFilter   `json:"saved_filters,omitzero"`
+	PageRefs           PageRefs                    `json:"page_refs,omitzero"`
+	KeyBindings        KeyBindings                 `json:"key_bindings,omitzero"`
+	WorkspaceFrame     *geom.Rect                  `json:"workspace_frame,omitzero"`
+	TopDockState       *unison.DockState           `json:"top_dock_state,omitzero"`
+	DocDockState       *unison.DockState           `json:"doc_dock_state,omitzero"`
+	Workspaces         map[string]*WorkspaceLayout `json:"workspaces,omitzero"`
+	LaunchWorkspace    string                      `json:"launch_workspace,omitzero"`
+	Colors             colors.Colors               `json:"theme_colors"`
+	Fonts              fonts.Fonts                 `json:"fonts"`
+	Sheet              *SheetSettings              `json:"sheet_settings,omitzero"`
+	OpenInWindow       []dgroup.Group              `json:"open_in_window,omitzero"`
+	Closed             map[string]int64            `json:"closed,omitzero"`
+	PDFs               map[string]*PDFInfo         `json:"pdfs,omitzero"`
+	LootGenMinValue    fxp.Int                     `json:"loot_gen_min_value"`
+	LootGenMaxValue    fxp.Int                     `json:"loot_gen_max_value"`
 }
 
 // IDer defines the methods required of objects that have an ID.
@@ -160,7 +165,7 @@ func (s *Settings) Save() error {
 		}
 	}
 	for k, v := range s.PDFs {
-		if v.LastOpened < cutoff {
+		if v.LastOpened < cutoff && len(v.Highlights) == 0 {
 			delete(s.PDFs, k)
 		}
 	}
@@ -168,6 +173,22 @@ func (s *Settings) Save() error {
 	return jio.SaveToFile(SettingsPath, s)
 }
 
+// ReloadSettings replaces the global settings with those currently stored in the settings file, such as after the file
+// has been replaced by another copy of it.
+func ReloadSettings() error {
+	var loaded Settings
+	if err := jio.Load(nil, SettingsPath, &loaded); err != nil {
+		return err
+	}
+	s := GlobalSettings()
+	*s = loaded
+	s.EnsureValidity()
+	unison.SetThemeMode(s.ThemeMode)
+	s.Colors.MakeCurrent()
+	s.Fonts.MakeCurrent()
+	return nil
+}
+
 // ToColumnCutoff converts a unix timestamp (in seconds) to a column cutoff value.
 func ToColumnCutoff(in int64) float32 {
 	return float32(in / (60 * 60 * 24))
@@ -210,6 +231,14 @@ func (s *Settings) EnsureValidity() {
 		s.Sheet.EnsureValidity()
 	}
 	s.OpenInWindow = SanitizeDockableGroups(s.OpenInWindow)
+	for k, v := range s.Workspaces {
+		if v == nil || strings.TrimSpace(k) == "" {
+			delete(s.Workspaces, k)
+		}
+	}
+	if _, exists := s.Workspaces[s.LaunchWorkspace]; !exists {
+		s.LaunchWorkspace = ""
+	}
 }
 
 // SanitizeDockableGroups returns the list of valid dockable groups from the passed-in list, in sorted order.
@@ -347,15 +376,21 @@ func SetClosedState(key string, closed bool) bool {
 	return true
 }
 
-// IDForPDFTOC returns the ID for the specified PDF TOC entry.
-func IDForPDFTOC(pdfPath, title string, pageNum int) tid.TID {
-	settings := GlobalSettings()
-	pi, ok := settings.PDFs[pdfPath]
+// PDFInfoFor returns the information for the specified PDF, creating it if needed, and marks it as recently used.
+func (s *Settings) PDFInfoFor(pdfPath string) *PDFInfo {
+	pi, ok := s.PDFs[pdfPath]
 	if !ok {
 		pi = &PDFInfo{}
-		settings.PDFs[pdfPath] = pi
+		s.PDFs[pdfPath] = pi
 	}
 	pi.LastOpened = time.Now().Unix()
+	return pi
+}
+
+// IDForPDFTOC returns the ID for the specified PDF TOC entry.
+func IDForPDFTOC(pdfPath, title string, pageNum int) tid.TID {
+	settings := GlobalSettings()
+	pi := settings.PDFInfoFor(pdfPath)
 	if pi.TOC == nil {
 		pi.TOC = make(map[string]map[int]tid.TID)
 	}
@@ -0,0 +1,104 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/merge"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xio"
+)
+
+// SheetChange records a value that was changed in a character sheet between one save and the next.
+type SheetChange struct {
+	When time.Time `json:"when"`
+	// Path describes where the value is located, e.g. ["traits", "Acute Vision", "levels"].
+	Path []string `json:"path"`
+	// Before and After hold the value as JSON. Before is empty for things that were added and After is empty for things
+	// that were removed.
+	Before jsontext.Value `json:"before,omitzero"`
+	After  jsontext.Value `json:"after,omitzero"`
+}
+
+// SheetChangeLogPath returns the path of the change log for the sheet at the given path. Like the snapshot directory,
+// the file sits alongside the sheet and is hidden.
+func SheetChangeLogPath(sheetPath string) string {
+	return filepath.Join(filepath.Dir(sheetPath), "."+filepath.Base(sheetPath)+".changes")
+}
+
+// RecordSheetChanges appends the differences between two saved versions of a sheet, given as JSON, to the change log
+// of the sheet at the given path. The log is only ever added to, so it holds every change made since it was started,
+// regardless of what has been undone or restored in the meantime.
+func RecordSheetChanges(sheetPath string, before, after []byte) error {
+	changes, err := merge.Diff(before, after)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	now := time.Now()
+	var buffer bytes.Buffer
+	for _, one := range changes {
+		if err = json.MarshalWrite(&buffer, &SheetChange{
+			When:   now,
+			Path:   one.Path,
+			Before: one.Before,
+			After:  one.After,
+		}); err != nil {
+			return errs.Wrap(err)
+		}
+		buffer.WriteByte('\n')
+	}
+	logPath := SheetChangeLogPath(sheetPath)
+	var f *os.File
+	if f, err = os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640); err != nil {
+		return errs.NewWithCause(logPath, err)
+	}
+	if _, err = f.Write(buffer.Bytes()); err != nil {
+		xio.CloseIgnoringErrors(f)
+		return errs.NewWithCause(logPath, err)
+	}
+	if err = f.Close(); err != nil {
+		return errs.NewWithCause(logPath, err)
+	}
+	return nil
+}
+
+// SheetChanges returns the changes recorded for the sheet at the given path, oldest first. If the log ends with a
+// partially written entry, the entries before it are still returned.
+func SheetChanges(sheetPath string) ([]*SheetChange, error) {
+	logPath := SheetChangeLogPath(sheetPath)
+	f, err := os.Open(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errs.NewWithCause(logPath, err)
+	}
+	defer xio.CloseIgnoringErrors(f)
+	dec := jsontext.NewDecoder(f)
+	var changes []*SheetChange
+	for {
+		var one SheetChange
+		if err = json.UnmarshalDecode(dec, &one); err != nil {
+			if !errors.Is(err, io.EOF) {
+				errs.Log(err, "path", logPath)
+			}
+			return changes, nil
+		}
+		changes = append(changes, &one)
+	}
+}
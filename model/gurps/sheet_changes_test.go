@@ -0,0 +1,52 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSheetChanges(t *testing.T) {
+	c := check.New(t)
+	sheetPath := filepath.Join(t.TempDir(), "test"+SheetExt)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Luck"
+	trait.BasePoints = fxp.Fifteen
+	e.SetTraitList([]*Trait{trait})
+	first, err := json.Marshal(e)
+	c.NoError(err)
+	c.NoError(RecordSheetChanges(sheetPath, first, first))
+	_, err = os.Stat(SheetChangeLogPath(sheetPath))
+	c.True(os.IsNotExist(err), "nothing is written when nothing changed")
+
+	e.Profile.Name = "Alice"
+	second, err := json.Marshal(e)
+	c.NoError(err)
+	c.NoError(RecordSheetChanges(sheetPath, first, second))
+	e.SetTraitList(nil)
+	third, err := json.Marshal(e)
+	c.NoError(err)
+	c.NoError(RecordSheetChanges(sheetPath, second, third))
+
+	changes, err := SheetChanges(sheetPath)
+	c.NoError(err)
+	c.Equal(2, len(changes))
+	c.Equal([]string{"profile", "name"}, changes[0].Path)
+	c.Equal(`"Alice"`, string(changes[0].After))
+	c.Equal([]string{"traits", "Luck"}, changes[1].Path)
+	c.Equal(0, len(changes[1].After))
+}
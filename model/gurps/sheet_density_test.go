@@ -0,0 +1,46 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/density"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSheetContentScale(t *testing.T) {
+	c := check.New(t)
+	s := FactorySheetSettings()
+	c.Equal(100, s.EffectiveFontScale())
+	c.Equal(float32(1), s.ContentScale())
+
+	s.Density = density.Dense
+	c.Equal(float32(0.8), s.ContentScale())
+	s.FontScale = 50
+	c.Equal(float32(0.4), s.ContentScale())
+
+	data, err := json.Marshal(s)
+	c.NoError(err)
+	var loaded SheetSettings
+	c.NoError(json.Unmarshal(data, &loaded))
+	c.Equal(density.Dense, loaded.Density)
+	c.Equal(50, loaded.FontScale)
+
+	s.FontScale = 1000
+	s.Density = density.Option(99)
+	s.EnsureValidity()
+	c.Equal(SheetFontScaleMax, s.FontScale)
+	c.Equal(density.Comfortable, s.Density)
+	s.FontScale = 0
+	s.EnsureValidity()
+	c.Equal(0, s.FontScale, "an unset font scale is left alone")
+}
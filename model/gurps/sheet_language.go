@@ -0,0 +1,99 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+	"github.com/richardwilkes/unison"
+)
+
+// sheetLanguageFamilies holds the font families that suit each language a sheet may be written in, in order of
+// preference. Families that are commonly installed on each platform are listed so that one is usually available.
+var sheetLanguageFamilies = map[string][]string{
+	"ar":      {"Geeza Pro", "Segoe UI", "Noto Sans Arabic", "Noto Naskh Arabic", "DejaVu Sans"},
+	"fa":      {"Geeza Pro", "Segoe UI", "Noto Sans Arabic", "Noto Naskh Arabic", "DejaVu Sans"},
+	"he":      {"Arial Hebrew", "Segoe UI", "Noto Sans Hebrew", "DejaVu Sans"},
+	"ja":      {"Hiragino Sans", "Yu Gothic", "Meiryo", "Noto Sans CJK JP", "Noto Sans JP"},
+	"ko":      {"Apple SD Gothic Neo", "Malgun Gothic", "Noto Sans CJK KR", "Noto Sans KR"},
+	"zh-Hans": {"PingFang SC", "Microsoft YaHei", "Noto Sans CJK SC", "Noto Sans SC", "WenQuanYi Micro Hei"},
+	"zh-Hant": {"PingFang TC", "Microsoft JhengHei", "Noto Sans CJK TC", "Noto Sans TC"},
+}
+
+// SheetLanguages returns the codes of the languages that may be chosen for a sheet, preceded by the empty code used
+// when no language has been chosen.
+func SheetLanguages() []string {
+	codes := slices.Collect(maps.Keys(sheetLanguageFamilies))
+	slices.SortFunc(codes, func(a, b string) int {
+		return xstrings.NaturalCmp(SheetLanguageName(a), SheetLanguageName(b), true)
+	})
+	return append([]string{""}, codes...)
+}
+
+// SheetLanguageName returns the name of the language with the given code.
+func SheetLanguageName(code string) string {
+	switch code {
+	case "":
+		return i18n.Text("Unspecified")
+	case "ar":
+		return i18n.Text("Arabic")
+	case "fa":
+		return i18n.Text("Persian")
+	case "he":
+		return i18n.Text("Hebrew")
+	case "ja":
+		return i18n.Text("Japanese")
+	case "ko":
+		return i18n.Text("Korean")
+	case "zh-Hans":
+		return i18n.Text("Chinese (Simplified)")
+	case "zh-Hant":
+		return i18n.Text("Chinese (Traditional)")
+	default:
+		return code
+	}
+}
+
+// SheetLanguageFontFamily returns the first of the font families that suit the language that is installed, or an empty
+// string if none are.
+func SheetLanguageFontFamily(code string) string {
+	installed := unison.FontFamilies()
+	for _, family := range sheetLanguageFamilies[code] {
+		if slices.Contains(installed, family) {
+			return family
+		}
+	}
+	return ""
+}
+
+// ApplyLanguageFonts temporarily switches the page fonts to the font family that suits the sheet's language, if one
+// was chosen and is installed, keeping their size, weight and style. Call the returned function to put the originals
+// back. Since fonts affect layout, this should be in effect both while laying out and while drawing.
+func (s *SheetSettings) ApplyLanguageFonts() (restore func()) {
+	family := SheetLanguageFontFamily(s.Language)
+	if family == "" {
+		return func() {}
+	}
+	saved := make(map[*unison.IndirectFont]unison.Font)
+	for _, one := range SheetThemeFonts() {
+		saved[one.Font] = one.Font.Font
+		fd := one.Font.Descriptor()
+		fd.Family = family
+		one.Font.Font = fd.Font()
+	}
+	return func() {
+		for f, font := range saved {
+			f.Font = font
+		}
+	}
+}
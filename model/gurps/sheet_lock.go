@@ -0,0 +1,88 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+const (
+	lockPasswordIterations = 100_000
+	lockPasswordSaltSize   = 16
+	lockPasswordKeySize    = 32
+)
+
+// LockPassword holds a salted hash of the password needed to unlock a locked character sheet. An empty LockPassword
+// means no password is needed.
+type LockPassword string
+
+// NewLockPassword returns the LockPassword for the given password. An empty password results in an empty LockPassword.
+func NewLockPassword(password string) (LockPassword, error) {
+	if password == "" {
+		return "", nil
+	}
+	salt := make([]byte, lockPasswordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errs.Wrap(err)
+	}
+	key, err := lockPasswordKey(password, salt)
+	if err != nil {
+		return "", err
+	}
+	return LockPassword(hex.EncodeToString(salt) + ":" + hex.EncodeToString(key)), nil
+}
+
+func lockPasswordKey(password string, salt []byte) ([]byte, error) {
+	key, err := pbkdf2.Key(sha256.New, password, salt, lockPasswordIterations, lockPasswordKeySize)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return key, nil
+}
+
+// Matches returns true if the password is the one this LockPassword was created from. An empty LockPassword matches
+// any password.
+func (p LockPassword) Matches(password string) bool {
+	if p == "" {
+		return true
+	}
+	saltText, keyText, ok := strings.Cut(string(p), ":")
+	if !ok {
+		return false
+	}
+	salt, err := hex.DecodeString(saltText)
+	if err != nil {
+		return false
+	}
+	var expected, key []byte
+	if expected, err = hex.DecodeString(keyText); err != nil {
+		return false
+	}
+	if key, err = lockPasswordKey(password, salt); err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
+
+// Unlock the entity, returning false if the password doesn't match the one it was locked with.
+func (e *Entity) Unlock(password string) bool {
+	if !e.LockPassword.Matches(password) {
+		return false
+	}
+	e.Locked = false
+	return true
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestLockPassword(t *testing.T) {
+	c := check.New(t)
+	p, err := NewLockPassword("")
+	c.NoError(err)
+	c.Equal(LockPassword(""), p)
+	c.True(p.Matches("anything"))
+
+	p, err = NewLockPassword("secret")
+	c.NoError(err)
+	c.True(p.Matches("secret"))
+	c.False(p.Matches("Secret"))
+	c.False(p.Matches(""))
+	other, err := NewLockPassword("secret")
+	c.NoError(err)
+	c.NotEqual(p, other, "each password gets its own salt")
+	c.False(LockPassword("garbage").Matches("secret"))
+
+	e := NewEntity()
+	e.Locked = true
+	e.LockPassword = p
+	c.False(e.Unlock("wrong"))
+	c.True(e.Locked)
+	c.True(e.Unlock("secret"))
+	c.False(e.Locked)
+}
+
+func TestLockedEntityRejectsBuildDataEdits(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Luck"
+	trait.BasePoints = fxp.FromInteger(15)
+	e.SetTraitList([]*Trait{trait})
+	e.Locked = true
+	st := e.Attributes.Current("st")
+	hp := e.Attributes.Find("hp")
+
+	for _, script := range []string{
+		`sheet.setAttribute("st", 15);`,
+		`sheet.editTraits(t => { t.basePoints = 1; });`,
+		`sheet.editSkills(s => {});`,
+		`sheet.editSpells(s => {});`,
+	} {
+		_, err := RunAutomationScript(e, script, time.Second)
+		c.HasError(err, script)
+	}
+	c.Equal(st, e.Attributes.Current("st"))
+	c.Equal(fxp.FromInteger(15), trait.BasePoints)
+
+	result, err := RunAutomationScript(e, `sheet.setAttribute("hp", $hp.maximum - 2);
+sheet.editProfile(p => { p.name = "Tester"; });`, time.Second)
+	c.NoError(err, "play-time changes are still permitted")
+	c.True(result.Modified)
+	c.Equal(fxp.Two, hp.Damage)
+	c.Equal("Tester", e.Profile.Name)
+}
+
+func TestLockedEntityKeepsLockWhenRestoringSnapshot(t *testing.T) {
+	c := check.New(t)
+	sheetPath := filepath.Join(t.TempDir(), "test"+SheetExt)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Luck"
+	e.SetTraitList([]*Trait{trait})
+	snapshot, err := TakeSnapshot(e, sheetPath)
+	c.NoError(err)
+
+	password, err := NewLockPassword("secret")
+	c.NoError(err)
+	e.Locked = true
+	e.LockPassword = password
+	e.SetTraitList(nil)
+	c.NoError(snapshot.RestoreInto(e))
+	c.Equal(1, len(e.Traits))
+	c.True(e.Locked, "restoring a snapshot taken before the lock must not unlock the sheet")
+	c.Equal(password, e.LockPassword)
+	c.False(e.Unlock(""))
+}
+
+func TestLockedEntityLimitsFindReplace(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Patron (Mitra)"
+	trait.LocalNotes = "Mitra answers rarely"
+	mod := NewTraitModifier(e, nil, false)
+	mod.Name = "Mitra's favor"
+	trait.Modifiers = []*TraitModifier{mod}
+	e.Traits = []*Trait{trait}
+	skill := NewSkill(e, nil, false)
+	skill.Name = "Theology"
+	skill.Specialization = "Mitra"
+	e.Skills = []*Skill{skill}
+	spell := NewSpell(e, nil, false)
+	spell.Name = "Mitra's Light"
+	e.Spells = []*Spell{spell}
+	note := NewNote(e, nil, false)
+	note.MarkDown = "Mitra"
+	e.Notes = []*Note{note}
+	r, err := NewTextReplacer("Mitra", "Ishtar", false, true)
+	c.NoError(err)
+	c.Equal(6, len(FindTextReplacements(e, r)))
+
+	e.Locked = true
+	list := FindTextReplacements(e, r)
+	c.Equal(2, len(list), "only the notes may change while locked")
+	for _, one := range list {
+		one.Apply()
+	}
+	c.Equal("Patron (Mitra)", trait.Name)
+	c.Equal("Mitra's favor", mod.Name)
+	c.Equal("Mitra", skill.Specialization)
+	c.Equal("Mitra's Light", spell.Name)
+	c.Equal("Ishtar answers rarely", trait.LocalNotes)
+	c.Equal("Ishtar", note.MarkDown)
+}
@@ -15,11 +15,20 @@ import (
 	"io/fs"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/casting"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/density"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/display"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/progression"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/study"
 	"github.com/richardwilkes/gcs/v5/model/jio"
 )
 
+// Limits for SheetSettings.FontScale, which is a percentage.
+const (
+	SheetFontScaleMin = 50
+	SheetFontScaleMax = 200
+)
+
 // SheetSettingsResponder defines the method required to be notified of updates to the SheetSettings.
 type SheetSettingsResponder interface {
 	// SheetSettingsUpdated will be called when the SheetSettings have been updated. The provided Entity will be nil if
@@ -52,11 +61,19 @@ type SheetSettingsData struct {
 	HideTLColumn                  bool               `json:"hide_tl_column,omitzero"`
 	HideLCColumn                  bool               `json:"hide_lc_column,omitzero"`
 	HidePageRefColumn             bool               `json:"hide_page_ref_column,omitzero"`
+	ShowHitLocationColumn         bool               `json:"show_hit_location_column,omitzero"`
+	ShowEquipmentLocationColumn   bool               `json:"show_equipment_location_column,omitzero"`
 	UseTitleInFooter              bool               `json:"use_title_in_footer,omitzero"`
 	ExcludeUnspentPointsFromTotal bool               `json:"exclude_unspent_points_from_total,omitzero"`
 	ShowLiftingSTDamage           bool               `json:"show_lifting_st_damage,omitzero"`
 	ShowIQBasedDamage             bool               `json:"show_iq_based_damage,omitzero"`
+	UseHeightBasedSizeModifier    bool               `json:"use_height_based_size_modifier,omitzero"`
 	UseSkillModifierAdjustments   bool               `json:"use_skill_modifier_adjustments,omitzero"`
+	TechnicalGrappling            bool               `json:"technical_grappling,omitzero"`
+	RitualPathMagic               bool               `json:"ritual_path_magic,omitzero"`
+	CastingMode                   casting.Mode       `json:"casting_mode,omitzero"`
+	MagicThreshold                int                `json:"magic_threshold,omitzero"`
+	StudyTracking                 study.Tracking     `json:"study_tracking,omitzero"`
 	EasySkillModifierOverride             fxp.Int            `json:"easy_skill_modifier_override,omitzero"`
 	AverageSkillModifierOverride          fxp.Int            `json:"average_skill_modifier_override,omitzero"`
 	HardSkillModifierOverride             fxp.Int            `json:"hard_skill_modifier_override,omitzero"`
@@ -72,6 +89,13 @@ type SheetSettingsData struct {
 	UsePassiveDefense                    bool               `json:"use_passive_defense,omitzero"` // GURPS 3e optional rule: PD applies when active defense fails (also shows PD column)
 	ShowPDColumn                         bool               `json:"show_pd_column,omitzero"`      // DEPRECATED: Automatically synced with UsePassiveDefense in EnsureValidity(). Kept for backward compatibility with old character sheets.
 	DodgeOverride                        fxp.Int            `json:"dodge_override,omitzero"`
+	EquipmentLocations                   *EquipmentLocationSettings `json:"equipment_locations,omitzero"`
+	TableColumns                         map[string]*TableColumns   `json:"table_columns,omitzero"`
+	CustomBlocks                         []*CustomBlock             `json:"custom_blocks,omitzero"`
+	Theme                                string                     `json:"theme,omitzero"`
+	Density                              density.Option             `json:"density,omitzero"`
+	FontScale                            int                        `json:"font_scale,omitzero"`
+	Language                             string                     `json:"language,omitzero"`
 }
 
 // SheetSettings holds sheet settings.
@@ -96,7 +120,9 @@ func FactorySheetSettings() *SheetSettings {
 			BlockLayout:            NewBlockLayout(),
 			Attributes:             FactoryAttributeDefs(),
 			BodyType:               FactoryBody(),
+			EquipmentLocations:     NewEquipmentLocationSettings(),
 			DamageProgression:      progression.BasicSet,
+			MagicThreshold:         DefaultMagicThreshold,
 			DefaultLengthUnits:     fxp.FeetAndInches,
 			DefaultWeightUnits:     fxp.Pound,
 			UserDescriptionDisplay: display.Tooltip,
@@ -141,24 +167,39 @@ func (s *SheetSettings) EnsureValidity() {
 	} else {
 		s.Page.EnsureValidity()
 	}
+	s.ensureCustomBlocksValid()
 	if s.BlockLayout == nil {
 		s.BlockLayout = NewBlockLayout()
-	} else {
-		s.BlockLayout.EnsureValidity()
 	}
+	s.syncCustomBlockLayout()
 	if s.Attributes == nil {
 		s.Attributes = FactoryAttributeDefs()
 	}
 	if s.BodyType == nil {
 		s.BodyType = FactoryBody()
 	}
+	if s.EquipmentLocations == nil {
+		s.EquipmentLocations = NewEquipmentLocationSettings()
+	}
 	s.DamageProgression = s.DamageProgression.EnsureValid()
+	s.CastingMode = s.CastingMode.EnsureValid()
+	if s.MagicThreshold <= 0 {
+		s.MagicThreshold = DefaultMagicThreshold
+	}
+	s.StudyTracking = s.StudyTracking.EnsureValid()
 	s.DefaultLengthUnits = s.DefaultLengthUnits.EnsureValid()
 	s.DefaultWeightUnits = s.DefaultWeightUnits.EnsureValid()
 	s.UserDescriptionDisplay = s.UserDescriptionDisplay.EnsureValid()
 	s.ModifiersDisplay = s.ModifiersDisplay.EnsureValid()
 	s.NotesDisplay = s.NotesDisplay.EnsureValid()
 	s.SkillLevelAdjDisplay = s.SkillLevelAdjDisplay.EnsureValid()
+	s.Density = s.Density.EnsureValid()
+	if s.FontScale != 0 {
+		s.FontScale = min(max(s.FontScale, SheetFontScaleMin), SheetFontScaleMax)
+	}
+	if _, ok := sheetLanguageFamilies[s.Language]; !ok {
+		s.Language = ""
+	}
 	// Ensure GURPS 4E defaults for dodge calculation fields
 	// This handles backward compatibility for character sheets created before dodge customization was added.
 	// We use a conservative heuristic: only set defaults if BOTH dodge fields AND skill modifier fields
@@ -216,15 +257,61 @@ func (s *SheetSettings) Clone(entity *Entity) *SheetSettings {
 	clone.BlockLayout = s.BlockLayout.Clone()
 	clone.Attributes = s.Attributes.Clone()
 	clone.BodyType = s.BodyType.Clone(entity, nil)
+	clone.EquipmentLocations = s.EquipmentLocations.Clone()
+	if s.TableColumns != nil {
+		clone.TableColumns = make(map[string]*TableColumns, len(s.TableColumns))
+		for k, v := range s.TableColumns {
+			clone.TableColumns[k] = v.Clone()
+		}
+	}
+	if s.CustomBlocks != nil {
+		clone.CustomBlocks = make([]*CustomBlock, len(s.CustomBlocks))
+		for i, one := range s.CustomBlocks {
+			clone.CustomBlocks[i] = one.Clone()
+		}
+	}
 	return &clone
 }
 
+// ColumnsFor returns the column customizations for the table with the given key, or nil if there are none. The key is
+// one of the BlockLayout keys.
+func (s *SheetSettings) ColumnsFor(key string) *TableColumns {
+	return s.TableColumns[key]
+}
+
+// SetColumnsFor sets the column customizations for the table with the given key. The key is one of the BlockLayout
+// keys.
+func (s *SheetSettings) SetColumnsFor(key string, columns *TableColumns) {
+	if columns.Empty() {
+		delete(s.TableColumns, key)
+		return
+	}
+	if s.TableColumns == nil {
+		s.TableColumns = make(map[string]*TableColumns)
+	}
+	s.TableColumns[key] = columns
+}
+
 // SetOwningEntity sets the owning entity and configures any sub-components as needed.
 func (s *SheetSettings) SetOwningEntity(entity *Entity) {
 	s.Entity = entity
 	s.BodyType.Update(entity)
 }
 
+// EffectiveFontScale returns the font scale percentage, substituting 100 if none has been set.
+func (s *SheetSettings) EffectiveFontScale() int {
+	if s.FontScale == 0 {
+		return 100
+	}
+	return s.FontScale
+}
+
+// ContentScale returns the factor that the content of a sheet's pages should be scaled by, combining the density and
+// the font scale. The page size and margins are not affected, so values less than 1 fit more onto each page.
+func (s *SheetSettings) ContentScale() float32 {
+	return s.Density.Scale() * float32(s.EffectiveFontScale()) / 100
+}
+
 // Save writes the settings to the file as JSON.
 func (s *SheetSettings) Save(filePath string) error {
 	return jio.SaveToFile(filePath, s)
@@ -0,0 +1,236 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+const snapshotTimeLayout = "2006-01-02T15-04-05.000"
+
+// SheetSnapshot identifies a saved copy of a character sheet.
+type SheetSnapshot struct {
+	Path string
+	When time.Time
+}
+
+// SnapshotDir returns the directory that snapshots of the sheet at the given path are stored in. The directory sits
+// alongside the sheet and is hidden, so that it won't show up in the library navigator.
+func SnapshotDir(sheetPath string) string {
+	return filepath.Join(filepath.Dir(sheetPath), "."+filepath.Base(sheetPath)+".snapshots")
+}
+
+// TakeSnapshot saves a copy of the entity as a snapshot for the sheet at the given path.
+func TakeSnapshot(e *Entity, sheetPath string) (*SheetSnapshot, error) {
+	dir := SnapshotDir(sheetPath)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	now := time.Now()
+	snapshot := &SheetSnapshot{
+		Path: filepath.Join(dir, now.Format(snapshotTimeLayout)+SheetExt),
+		When: now,
+	}
+	if err := e.Save(snapshot.Path); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Snapshots returns the snapshots available for the sheet at the given path, newest first.
+func Snapshots(sheetPath string) ([]*SheetSnapshot, error) {
+	dir := SnapshotDir(sheetPath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errs.Wrap(err)
+	}
+	snapshots := make([]*SheetSnapshot, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, SheetExt) {
+			continue
+		}
+		var when time.Time
+		if when, err = time.ParseInLocation(snapshotTimeLayout, strings.TrimSuffix(name, SheetExt),
+			time.Local); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, &SheetSnapshot{
+			Path: filepath.Join(dir, name),
+			When: when,
+		})
+	}
+	slices.SortFunc(snapshots, func(a, b *SheetSnapshot) int { return b.When.Compare(a.When) })
+	return snapshots, nil
+}
+
+// String implements fmt.Stringer.
+func (s *SheetSnapshot) String() string {
+	return s.When.Format(time.DateTime)
+}
+
+// Load the entity stored in the snapshot.
+func (s *SheetSnapshot) Load() (*Entity, error) {
+	return NewEntityFromFile(os.DirFS(filepath.Dir(s.Path)), filepath.Base(s.Path))
+}
+
+// RestoreInto replaces the contents of the entity with the contents of the snapshot. The entity's lock state is kept,
+// so restoring a snapshot taken before the sheet was locked doesn't unlock it.
+func (s *SheetSnapshot) RestoreInto(e *Entity) error {
+	if _, err := s.Load(); err != nil {
+		return err
+	}
+	locked := e.Locked
+	lockPassword := e.LockPassword
+	defer func() {
+		e.Locked = locked
+		e.LockPassword = lockPassword
+	}()
+	e.DiscardCaches()
+	if err := jio.Load(os.DirFS(filepath.Dir(s.Path)), filepath.Base(s.Path), e); err != nil {
+		return errs.NewWithCause(InvalidFileData(), err)
+	}
+	return nil
+}
+
+// Delete the snapshot.
+func (s *SheetSnapshot) Delete() error {
+	if err := os.Remove(s.Path); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+// EntityDifference describes a single difference between two versions of an entity. Before is empty for things that
+// were added and After is empty for things that were removed.
+type EntityDifference struct {
+	Category string
+	Name     string
+	Before   string
+	After    string
+}
+
+type diffEntry struct {
+	key   string
+	name  string
+	value string
+}
+
+// DiffEntities returns the differences between two versions of an entity, in the points, attributes, traits, skills,
+// spells, and equipment.
+func DiffEntities(before, after *Entity) []*EntityDifference {
+	var diffs []*EntityDifference
+	add := func(category, name, beforeValue, afterValue string) {
+		if beforeValue != afterValue {
+			diffs = append(diffs, &EntityDifference{
+				Category: category,
+				Name:     name,
+				Before:   beforeValue,
+				After:    afterValue,
+			})
+		}
+	}
+
+	category := i18n.Text("Points")
+	add(category, i18n.Text("Total"), before.TotalPoints.String(), after.TotalPoints.String())
+	add(category, i18n.Text("Unspent"), before.UnspentPoints().String(), after.UnspentPoints().String())
+	pbBefore := before.PointsBreakdown()
+	pbAfter := after.PointsBreakdown()
+	for _, one := range []struct {
+		name          string
+		before, after fxp.Int
+	}{
+		{i18n.Text("Ancestry"), pbBefore.Ancestry, pbAfter.Ancestry},
+		{i18n.Text("Attributes"), pbBefore.Attributes, pbAfter.Attributes},
+		{i18n.Text("Advantages"), pbBefore.Advantages, pbAfter.Advantages},
+		{i18n.Text("Disadvantages"), pbBefore.Disadvantages, pbAfter.Disadvantages},
+		{i18n.Text("Quirks"), pbBefore.Quirks, pbAfter.Quirks},
+		{i18n.Text("Skills"), pbBefore.Skills, pbAfter.Skills},
+		{i18n.Text("Spells"), pbBefore.Spells, pbAfter.Spells},
+	} {
+		add(category, one.name, one.before.String(), one.after.String())
+	}
+
+	diffValues(add, i18n.Text("Attributes"), attributeDiffEntries(before), attributeDiffEntries(after))
+	traitValue := func(t *Trait) string {
+		if t.IsLeveled() {
+			return fmt.Sprintf(i18n.Text("Level %s"), t.CurrentLevel().String())
+		}
+		return fmt.Sprintf(i18n.Text("%s pts"), t.AdjustedPoints().String())
+	}
+	diffValues(add, i18n.Text("Traits"), nodeDiffEntries(before.Traits, traitValue),
+		nodeDiffEntries(after.Traits, traitValue))
+	skillLevel := func(s *Skill) string { return s.LevelData.LevelAsString(s.Container()) }
+	diffValues(add, i18n.Text("Skills"), nodeDiffEntries(before.Skills, skillLevel),
+		nodeDiffEntries(after.Skills, skillLevel))
+	spellLevel := func(s *Spell) string { return s.LevelData.LevelAsString(s.Container()) }
+	diffValues(add, i18n.Text("Spells"), nodeDiffEntries(before.Spells, spellLevel),
+		nodeDiffEntries(after.Spells, spellLevel))
+	quantity := func(e *Equipment) string { return e.Quantity.String() }
+	diffValues(add, i18n.Text("Equipment"), nodeDiffEntries(before.CarriedEquipment, quantity),
+		nodeDiffEntries(after.CarriedEquipment, quantity))
+	diffValues(add, i18n.Text("Other Equipment"), nodeDiffEntries(before.OtherEquipment, quantity),
+		nodeDiffEntries(after.OtherEquipment, quantity))
+	return diffs
+}
+
+func diffValues(add func(category, name, beforeValue, afterValue string), category string, before, after []diffEntry) {
+	afterMap := make(map[string]diffEntry, len(after))
+	for _, one := range after {
+		afterMap[one.key] = one
+	}
+	beforeKeys := make(map[string]bool, len(before))
+	for _, b := range before {
+		beforeKeys[b.key] = true
+		if a, exists := afterMap[b.key]; exists {
+			add(category, a.name, b.value, a.value)
+		} else {
+			add(category, b.name, b.value, "")
+		}
+	}
+	for _, a := range after {
+		if !beforeKeys[a.key] {
+			add(category, a.name, "", a.value)
+		}
+	}
+}
+
+func attributeDiffEntries(e *Entity) []diffEntry {
+	var entries []diffEntry
+	for _, attr := range e.Attributes.List() {
+		if def := attr.AttributeDef(); def != nil && !def.IsSeparator() {
+			entries = append(entries, diffEntry{key: attr.AttrID, name: def.Name, value: attr.Maximum().String()})
+		}
+	}
+	return entries
+}
+
+func nodeDiffEntries[T NodeTypes](list []T, value func(T) string) []diffEntry {
+	var entries []diffEntry
+	Traverse(func(one T) bool {
+		node := AsNode(one)
+		entries = append(entries, diffEntry{key: string(node.ID()), name: node.String(), value: value(one)})
+		return false
+	}, false, true, list...)
+	return entries
+}
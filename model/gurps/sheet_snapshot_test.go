@@ -0,0 +1,59 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSheetSnapshots(t *testing.T) {
+	c := check.New(t)
+	sheetPath := filepath.Join(t.TempDir(), "test"+SheetExt)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Luck"
+	trait.BasePoints = fxp.Fifteen
+	e.SetTraitList([]*Trait{trait})
+	e.Recalculate()
+	snapshot, err := TakeSnapshot(e, sheetPath)
+	c.NoError(err)
+	list, err := Snapshots(sheetPath)
+	c.NoError(err)
+	c.Equal(1, len(list))
+	c.Equal(snapshot.Path, list[0].Path)
+
+	before, err := list[0].Load()
+	c.NoError(err)
+	c.Equal(0, len(DiffEntities(before, e)))
+
+	skill := NewSkill(e, nil, false)
+	skill.Name = "Stealth"
+	skill.Points = fxp.Four
+	e.SetSkillList([]*Skill{skill})
+	e.SetTraitList(nil)
+	e.Recalculate()
+	found := make(map[string]*EntityDifference)
+	for _, diff := range DiffEntities(before, e) {
+		found[diff.Category+":"+diff.Name] = diff
+	}
+	c.NotNil(found["Traits:Luck"])
+	c.Equal("", found["Traits:Luck"].After)
+	c.NotNil(found["Skills:Stealth"])
+	c.Equal("", found["Skills:Stealth"].Before)
+	c.NotNil(found["Points:Skills"])
+
+	c.NoError(list[0].RestoreInto(e))
+	c.Equal(1, len(e.Traits))
+	c.Equal(0, len(e.Skills))
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"io/fs"
+	"maps"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/colors"
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/unison"
+)
+
+var sheetThemeCache = make(map[string]*SheetTheme)
+
+// SheetThemeData holds the SheetTheme data that is written to disk.
+type SheetThemeData struct {
+	Version int                              `json:"version"`
+	Colors  map[string]unison.ThemeColor     `json:"colors,omitzero"`
+	Fonts   map[string]unison.FontDescriptor `json:"fonts,omitzero"`
+}
+
+// SheetTheme holds a set of colors and fonts used when rendering a sheet. Only the colors and fonts present in the theme
+// are overridden; everything else comes from the application's current settings. Each color has both a light and a
+// dark variant, so a theme follows the application's color mode.
+type SheetTheme struct {
+	SheetThemeData
+}
+
+// SheetThemeColors returns the theme colors that a SheetTheme may override.
+func SheetThemeColors() []*colors.ThemedColor {
+	var list []*colors.ThemedColor
+	for _, one := range colors.Current() {
+		if isSheetThemeColorID(one.ID) {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+// SheetThemeFonts returns the theme fonts that a SheetTheme may override.
+func SheetThemeFonts() []*fonts.ThemedFont {
+	var list []*fonts.ThemedFont
+	for _, one := range fonts.CurrentFonts() {
+		if isSheetThemeFontID(one.ID) {
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+func isSheetThemeColorID(id string) bool {
+	switch id {
+	case "surface", "header", "banding":
+		return true
+	default:
+		return strings.HasPrefix(id, "tint_")
+	}
+}
+
+func isSheetThemeFontID(id string) bool {
+	return strings.HasPrefix(id, "page.")
+}
+
+// AvailableSheetThemes scans the libraries and returns the available sheet themes.
+func AvailableSheetThemes(libraries Libraries) []*NamedFileSet {
+	return ScanForNamedFileSets(embeddedFS, "embedded_data", true, libraries, SheetThemeExt)
+}
+
+// LookupSheetTheme returns the SheetTheme with the given name, or nil if it can't be found. Results are cached until
+// DiscardSheetThemeCache() is called.
+func LookupSheetTheme(name string, libraries Libraries) *SheetTheme {
+	if name == "" {
+		return nil
+	}
+	if theme, ok := sheetThemeCache[name]; ok {
+		return theme
+	}
+	var theme *SheetTheme
+outer:
+	for _, lib := range AvailableSheetThemes(libraries) {
+		for _, one := range lib.List {
+			if one.Name == name {
+				var err error
+				if theme, err = NewSheetThemeFromFile(one.FileSystem, one.FilePath); err != nil {
+					errs.Log(err, "path", one.FilePath)
+					continue
+				}
+				break outer
+			}
+		}
+	}
+	sheetThemeCache[name] = theme
+	return theme
+}
+
+// DiscardSheetThemeCache discards any cached sheet themes, forcing them to be reloaded the next time they are needed.
+func DiscardSheetThemeCache() {
+	clear(sheetThemeCache)
+}
+
+// NewSheetTheme creates a new, empty SheetTheme.
+func NewSheetTheme() *SheetTheme {
+	var t SheetTheme
+	t.Version = jio.CurrentDataVersion
+	t.EnsureValidity()
+	return &t
+}
+
+// NewSheetThemeFromFile loads a SheetTheme from a file.
+func NewSheetThemeFromFile(fileSystem fs.FS, filePath string) (*SheetTheme, error) {
+	var t SheetTheme
+	if err := jio.Load(fileSystem, filePath, &t.SheetThemeData); err != nil {
+		return nil, errs.NewWithCause(InvalidFileData(), err)
+	}
+	if err := jio.CheckVersion(t.Version); err != nil {
+		return nil, err
+	}
+	t.EnsureValidity()
+	return &t, nil
+}
+
+// EnsureValidity checks the current settings for validity and if they aren't valid, makes them so.
+func (t *SheetTheme) EnsureValidity() {
+	if t.Colors == nil {
+		t.Colors = make(map[string]unison.ThemeColor)
+	}
+	if t.Fonts == nil {
+		t.Fonts = make(map[string]unison.FontDescriptor)
+	}
+	maps.DeleteFunc(t.Colors, func(id string, _ unison.ThemeColor) bool { return !isSheetThemeColorID(id) })
+	maps.DeleteFunc(t.Fonts, func(id string, fd unison.FontDescriptor) bool {
+		return !isSheetThemeFontID(id) || fd.Size <= 0
+	})
+}
+
+// Clone creates a copy of this.
+func (t *SheetTheme) Clone() *SheetTheme {
+	clone := *t
+	clone.Colors = maps.Clone(t.Colors)
+	clone.Fonts = maps.Clone(t.Fonts)
+	return &clone
+}
+
+// Save writes the SheetTheme to the file as JSON.
+func (t *SheetTheme) Save(filePath string) error {
+	t.Version = jio.CurrentDataVersion
+	return jio.SaveToFile(filePath, &t.SheetThemeData)
+}
+
+// Color returns the color the theme uses for the given theme color.
+func (t *SheetTheme) Color(c *colors.ThemedColor) unison.ThemeColor {
+	if clr, ok := t.Colors[c.ID]; ok {
+		return clr
+	}
+	return *c.Color
+}
+
+// Font returns the font descriptor the theme uses for the given theme font.
+func (t *SheetTheme) Font(f *fonts.ThemedFont) unison.FontDescriptor {
+	if fd, ok := t.Fonts[f.ID]; ok {
+		return fd
+	}
+	return f.Font.Descriptor()
+}
+
+// ApplyColors temporarily replaces the current theme colors with those from this theme. Call the returned function to
+// put the original colors back.
+func (t *SheetTheme) ApplyColors() (restore func()) {
+	saved := make(map[*unison.ThemeColor]unison.ThemeColor, len(t.Colors))
+	for _, one := range SheetThemeColors() {
+		if clr, ok := t.Colors[one.ID]; ok {
+			saved[one.Color] = *one.Color
+			*one.Color = clr
+		}
+	}
+	return func() {
+		for tc, clr := range saved {
+			*tc = clr
+		}
+	}
+}
+
+// Apply temporarily replaces the current theme colors and fonts with those from this theme. Call the returned function
+// to put the originals back. Since fonts affect layout, this should be in effect both while laying out and while
+// drawing.
+func (t *SheetTheme) Apply() (restore func()) {
+	restoreColors := t.ApplyColors()
+	saved := make(map[*unison.IndirectFont]unison.Font, len(t.Fonts))
+	for _, one := range SheetThemeFonts() {
+		if fd, ok := t.Fonts[one.ID]; ok {
+			saved[one.Font] = one.Font.Font
+			one.Font.Font = fd.Font()
+		}
+	}
+	return func() {
+		for f, font := range saved {
+			f.Font = font
+		}
+		restoreColors()
+	}
+}
+
+// ResolvedTheme returns the SheetTheme chosen for these settings, or nil if none was chosen or it can't be found.
+func (s *SheetSettings) ResolvedTheme() *SheetTheme {
+	return LookupSheetTheme(s.Theme, GlobalSettings().Libraries())
+}
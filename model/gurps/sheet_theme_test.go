@@ -0,0 +1,64 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/colors"
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/unison"
+)
+
+func TestBuiltInSheetThemes(t *testing.T) {
+	c := check.New(t)
+	count := 0
+	for _, set := range AvailableSheetThemes(nil) {
+		for _, one := range set.List {
+			theme, err := NewSheetThemeFromFile(one.FileSystem, one.FilePath)
+			c.NoError(err, one.Name)
+			c.NotEqual(0, len(theme.Colors), one.Name)
+			count++
+		}
+	}
+	c.True(count > 0)
+	DiscardSheetThemeCache()
+	theme := LookupSheetTheme("Parchment", nil)
+	c.NotNil(theme)
+	c.True(theme == LookupSheetTheme("Parchment", nil), "lookups are cached")
+	c.Nil(LookupSheetTheme("No Such Theme", nil))
+	c.Nil(LookupSheetTheme("", nil))
+}
+
+func TestSheetThemeApplyColors(t *testing.T) {
+	c := check.New(t)
+	theme := NewSheetTheme()
+	override := unison.ThemeColor{Light: unison.RGB(1, 2, 3), Dark: unison.RGB(4, 5, 6)}
+	theme.Colors["header"] = override
+	theme.Colors["focus"] = override
+	theme.Fonts["page.field.primary"] = unison.FontDescriptor{}
+	theme.EnsureValidity()
+	_, exists := theme.Colors["focus"]
+	c.False(exists, "only sheet colors may be overridden")
+	c.Equal(0, len(theme.Fonts), "invalid font descriptors are dropped")
+
+	data, err := json.Marshal(&theme.SheetThemeData)
+	c.NoError(err)
+	var loaded SheetTheme
+	c.NoError(json.Unmarshal(data, &loaded.SheetThemeData))
+	c.Equal(override, loaded.Colors["header"])
+
+	original := *colors.Header
+	restore := theme.ApplyColors()
+	c.Equal(override, *colors.Header)
+	restore()
+	c.Equal(original, *colors.Header)
+}
@@ -43,6 +43,7 @@ var (
 	_ SkillAdjustmentProvider[*Skill] = &Skill{}
 	_ TemplatePickerProvider          = &Skill{}
 	_ EditorData[*Skill]              = &SkillEditData{}
+	_ StudyTracker                    = &Skill{}
 )
 
 // Columns that can be used with the skill method .CellData()
@@ -91,15 +92,17 @@ type SkillNonContainerOnlyEditData struct {
 	DefaultedFrom    *SkillDefault `json:"defaulted_from,omitzero"`
 	Study            []*Study      `json:"study,omitzero"`
 	StudyHoursNeeded study.Level   `json:"study_hours_needed,omitzero"`
+	StudyPointsSpent fxp.Int       `json:"study_points_spent,omitzero"`
 }
 
 // SkillSyncData holds the skill sync data that is common to both containers and non-containers.
 type SkillSyncData struct {
-	Name             string   `json:"name,omitzero"`
-	PageRef          string   `json:"reference,omitzero"`
-	PageRefHighlight string   `json:"reference_highlight,omitzero"`
-	LocalNotes       string   `json:"local_notes,omitzero"`
-	Tags             []string `json:"tags,omitzero"`
+	Name             string     `json:"name,omitzero"`
+	PageRef          string     `json:"reference,omitzero"`
+	PageRefHighlight string     `json:"reference_highlight,omitzero"`
+	LocalNotes       string     `json:"local_notes,omitzero"`
+	Tags             []string   `json:"tags,omitzero"`
+	Provenance       Provenance `json:"provenance,omitzero"`
 }
 
 // SkillNonContainerOnlySyncData holds the Skill sync data that is only applicable to skills that aren't containers.
@@ -109,6 +112,7 @@ type SkillNonContainerOnlySyncData struct {
 	EncumbrancePenaltyMultiplier fxp.Int             `json:"encumbrance_penalty_multiplier,omitzero"`
 	Defaults                     []*SkillDefault     `json:"defaults,omitzero"`
 	TechniqueDefault             *SkillDefault       `json:"default,omitzero"`
+	TechniqueAlternateDefaults   []*SkillDefault     `json:"alternate_defaults,omitzero"`
 	TechniqueLimitModifier       *fxp.Int            `json:"limit,omitzero"`
 	Prereq                       *PrereqList         `json:"prereqs,omitzero"`
 	Weapons                      []*Weapon           `json:"weapons,omitzero"`
@@ -406,6 +410,7 @@ func (s *Skill) CellData(columnID int, data *CellData) {
 		data.Secondary = s.SecondaryText(func(option display.Option) bool { return option.Inline() })
 		data.UnsatisfiedReason = s.UnsatisfiedReason
 		data.Tooltip = s.SecondaryText(func(option display.Option) bool { return option.Tooltip() })
+		data.Tooltip = s.Provenance.AppendToTooltip(data.Tooltip)
 		data.TemplateInfo = s.TemplatePicker.String()
 	case SkillDifficultyColumn:
 		if !s.Container() {
@@ -428,9 +433,7 @@ func (s *Skill) CellData(columnID int, data *CellData) {
 			data.Type = cell.Text
 			level := s.CalculateLevel(nil)
 			data.Primary = level.LevelAsString(s.Container())
-			if level.Tooltip != "" {
-				data.Tooltip = IncludesModifiersFrom() + ":" + level.Tooltip
-			}
+			data.Tooltip = level.FullTooltip()
 			data.Alignment = align.End
 		}
 	case SkillRelativeLevelColumn:
@@ -438,9 +441,7 @@ func (s *Skill) CellData(columnID int, data *CellData) {
 			data.Type = cell.Text
 			data.Primary = FormatRelativeSkill(EntityFromNode(s), s.IsTechnique(), s.Difficulty,
 				s.AdjustedRelativeLevel())
-			if tooltip := s.CalculateLevel(nil).Tooltip; tooltip != "" {
-				data.Tooltip = IncludesModifiersFrom() + ":" + tooltip
-			}
+			data.Tooltip = s.CalculateLevel(nil).FullTooltip()
 		}
 	case SkillPointsColumn:
 		data.Type = cell.Text
@@ -461,6 +462,7 @@ func (s *Skill) CellData(columnID int, data *CellData) {
 				data.Tooltip += "\n" + s.Source.String()
 			}
 		}
+		data.Tooltip = s.Provenance.AppendToTooltip(data.Tooltip)
 	}
 }
 
@@ -572,7 +574,7 @@ func (s *Skill) SecondaryText(optionChecker func(display.Option) bool) string {
 	}
 	if optionChecker(prefs.NotesDisplay) {
 		AppendStringOntoNewLine(&buffer, strings.TrimSpace(s.Notes()))
-		AppendStringOntoNewLine(&buffer, StudyHoursProgressText(ResolveStudyHours(s.Study), s.StudyHoursNeeded, false))
+		AppendStringOntoNewLine(&buffer, StudyProgressNote(EntityFromNode(s), s))
 	}
 	addTooltipForSkillLevelAdj(optionChecker, prefs, s.LevelData, &buffer)
 	return buffer.String()
@@ -740,8 +742,8 @@ func (s *Skill) CalculateLevel(excludes map[string]bool) Level {
 	points := s.AdjustedPoints(nil)
 	if s.IsTechnique() {
 		return CalculateTechniqueLevel(EntityFromNode(s), s.Replacements, s.NameWithReplacements(),
-			s.SpecializationWithReplacements(), s.Tags, s.TechniqueDefault, s.Difficulty.Difficulty, points, true,
-			s.TechniqueLimitModifier, excludes)
+			s.SpecializationWithReplacements(), s.Tags, s.TechniqueDefault, s.TechniqueAlternateDefaults,
+			s.Difficulty.Difficulty, points, true, s.TechniqueLimitModifier, excludes)
 	}
 	return CalculateSkillLevel(EntityFromNode(s), s.NameWithReplacements(), s.SpecializationWithReplacements(), s.Tags,
 		s.DefaultedFrom, s.Difficulty, points, s.EncumbrancePenaltyMultiplier)
@@ -885,10 +887,30 @@ func CalculateSkillLevel(e *Entity, name, specialization string, tags []string,
 	}
 }
 
-// CalculateTechniqueLevel returns the calculated level for a technique.
-func CalculateTechniqueLevel(e *Entity, replacements map[string]string, name, specialization string, tags []string, def *SkillDefault, diffLevel difficulty.Level, points fxp.Int, requirePoints bool, limitModifier *fxp.Int, excludes map[string]bool) Level {
+// CalculateTechniqueLevel returns the calculated level for a technique. The technique uses whichever of its default and
+// alternate defaults yields the highest level. Since callers add the primary default's modifier back onto the relative
+// level, the relative level returned is adjusted to account for any difference in modifier when an alternate default is
+// chosen.
+func CalculateTechniqueLevel(e *Entity, replacements map[string]string, name, specialization string, tags []string, def *SkillDefault, alternateDefs []*SkillDefault, diffLevel difficulty.Level, points fxp.Int, requirePoints bool, limitModifier *fxp.Int, excludes map[string]bool) Level {
+	best := calculateTechniqueLevelFrom(e, replacements, name, specialization, tags, def, diffLevel, points,
+		requirePoints, limitModifier, maps.Clone(excludes))
+	for _, alt := range alternateDefs {
+		if alt == nil {
+			continue
+		}
+		if level := calculateTechniqueLevelFrom(e, replacements, name, specialization, tags, alt, diffLevel, points,
+			requirePoints, limitModifier, maps.Clone(excludes)); level.Level > best.Level {
+			level.RelativeLevel += alt.Modifier - def.Modifier
+			best = level
+		}
+	}
+	return best
+}
+
+func calculateTechniqueLevelFrom(e *Entity, replacements map[string]string, name, specialization string, tags []string, def *SkillDefault, diffLevel difficulty.Level, points fxp.Int, requirePoints bool, limitModifier *fxp.Int, excludes map[string]bool) Level {
 	var tooltip xbytes.InsertBuffer
 	var relativeLevel fxp.Int
+	var resolution string
 	level := fxp.Min
 	if e != nil {
 		if def.DefaultType == SkillID {
@@ -911,7 +933,9 @@ func CalculateTechniqueLevel(e *Entity, replacements map[string]string, name, sp
 					if sk.TechniqueDefault != nil &&
 						(sk.TechniqueDefault.NameWithReplacements(replacements) != name ||
 							sk.TechniqueDefault.SpecializationWithReplacements(replacements) != specialization) {
-						level = sk.CalculateLevel(excludes).Level
+						skLevel := sk.CalculateLevel(excludes)
+						level = skLevel.Level
+						resolution = skLevel.Resolution
 					}
 				} else {
 					if sk.DefaultedFrom == nil ||
@@ -927,6 +951,12 @@ func CalculateTechniqueLevel(e *Entity, replacements map[string]string, name, sp
 		}
 		if level != fxp.Min {
 			baseLevel := level
+			step := fmt.Sprintf("%s [%s]", def.FullName(e, replacements), baseLevel.Floor().String())
+			if resolution != "" {
+				resolution = step + " → " + resolution
+			} else {
+				resolution = step
+			}
 			level += def.Modifier
 			if diffLevel == difficulty.Hard {
 				points -= fxp.One
@@ -950,6 +980,7 @@ func CalculateTechniqueLevel(e *Entity, replacements map[string]string, name, sp
 		Level:         level,
 		RelativeLevel: relativeLevel,
 		Tooltip:       tooltip.String(),
+		Resolution:    resolution,
 	}
 }
 
@@ -1071,6 +1102,16 @@ func (s *Skill) TechniqueSatisfied(tooltip *xbytes.InsertBuffer, prefix string)
 		return true
 	}
 	e := EntityFromNode(s)
+	for _, alt := range s.TechniqueAlternateDefaults {
+		if !alt.SkillBased() {
+			return true
+		}
+		if sk := e.BestSkillNamed(alt.NameWithReplacements(s.Replacements),
+			alt.SpecializationWithReplacements(s.Replacements), false, nil); sk != nil &&
+			(sk.IsTechnique() || sk.Points > 0) {
+			return true
+		}
+	}
 	sk := e.BestSkillNamed(s.TechniqueDefault.NameWithReplacements(s.Replacements),
 		s.TechniqueDefault.SpecializationWithReplacements(s.Replacements), false, nil)
 	satisfied := sk != nil && (sk.IsTechnique() || sk.Points > 0)
@@ -1134,8 +1175,17 @@ func (s *Skill) Notes() string {
 // ModifierNotes returns the notes due to modifiers.
 func (s *Skill) ModifierNotes() string {
 	if s.IsTechnique() {
-		return i18n.Text("Default: ") + s.TechniqueDefault.FullName(EntityFromNode(s), s.Replacements) +
-			s.TechniqueDefault.ModifierAsString()
+		e := EntityFromNode(s)
+		var buffer strings.Builder
+		buffer.WriteString(i18n.Text("Default: "))
+		buffer.WriteString(s.TechniqueDefault.FullName(e, s.Replacements))
+		buffer.WriteString(s.TechniqueDefault.ModifierAsString())
+		for _, alt := range s.TechniqueAlternateDefaults {
+			buffer.WriteString(i18n.Text(" or "))
+			buffer.WriteString(alt.FullName(e, s.Replacements))
+			buffer.WriteString(alt.ModifierAsString())
+		}
+		return buffer.String()
 	}
 	if s.Difficulty.Difficulty != difficulty.Wildcard {
 		defSkill := s.DefaultSkill()
@@ -1173,6 +1223,9 @@ func (s *Skill) FillWithNameableKeys(m, existing map[string]string) {
 	if s.TechniqueDefault != nil {
 		s.TechniqueDefault.FillWithNameableKeys(m, existing)
 	}
+	for _, one := range s.TechniqueAlternateDefaults {
+		one.FillWithNameableKeys(m, existing)
+	}
 	for _, one := range s.Defaults {
 		one.FillWithNameableKeys(m, existing)
 	}
@@ -1259,6 +1312,7 @@ func (s *Skill) ClearUnusedFieldsForType() {
 			s.Defaults = nil
 		} else {
 			s.TechniqueDefault = nil
+			s.TechniqueAlternateDefaults = nil
 			s.TechniqueLimitModifier = nil
 		}
 	}
@@ -1301,6 +1355,7 @@ func (s *Skill) SyncWithSource() {
 							s.TechniqueDefault.Specialization = ""
 						}
 					}
+					s.TechniqueAlternateDefaults = cloneSkillDefaults(other.TechniqueAlternateDefaults)
 					if other.TechniqueLimitModifier != nil {
 						mod := *other.TechniqueLimitModifier
 						s.TechniqueLimitModifier = &mod
@@ -1314,6 +1369,25 @@ func (s *Skill) SyncWithSource() {
 	}
 }
 
+// SourceChanges returns the individual differences between this data and its source, if any. Each may be applied
+// separately, rather than syncing everything as SyncWithSource() does.
+func (s *Skill) SourceChanges() []*SourceChange {
+	if !xreflect.IsNil(s.owner) {
+		if state, data := s.owner.SourceMatcher().Match(s); state == srcstate.Mismatched {
+			if other, ok := data.(*Skill); ok {
+				changes := collectSourceChanges(&s.SkillSyncData, &other.SkillSyncData)
+				if s.Container() {
+					return append(changes, collectSourceChanges(&s.SkillContainerOnlySyncData,
+						&other.SkillContainerOnlySyncData)...)
+				}
+				return append(changes, collectSourceChanges(&s.SkillNonContainerOnlySyncData,
+					&other.SkillNonContainerOnlySyncData)...)
+			}
+		}
+	}
+	return nil
+}
+
 // Hash writes this object's contents into the hasher. Note that this only hashes the data that is considered to be
 // "source" data, i.e. not expected to be modified by the user after copying from a library.
 func (s *Skill) Hash(h hash.Hash) {
@@ -1334,6 +1408,7 @@ func (s *SkillSyncData) hash(h hash.Hash) {
 	for _, tag := range s.Tags {
 		xhash.StringWithLen(h, tag)
 	}
+	s.Provenance.hash(h)
 }
 
 func (s *SkillContainerOnlySyncData) hash(h hash.Hash) {
@@ -1353,6 +1428,10 @@ func (s *SkillNonContainerOnlySyncData) hash(h hash.Hash) {
 	} else {
 		xhash.Num8(h, uint8(255))
 	}
+	xhash.Num64(h, len(s.TechniqueAlternateDefaults))
+	for _, one := range s.TechniqueAlternateDefaults {
+		one.Hash(h)
+	}
 	if s.TechniqueLimitModifier != nil {
 		xhash.Num64(h, *s.TechniqueLimitModifier)
 	} else {
@@ -1393,6 +1472,7 @@ func (s *SkillEditData) copyFrom(other *SkillEditData, isContainer, isApply, isT
 	}
 	s.Defaults = nil
 	s.TechniqueDefault = nil
+	s.TechniqueAlternateDefaults = nil
 	s.TechniqueLimitModifier = nil
 	if isTechnique {
 		if other.TechniqueDefault != nil {
@@ -1403,6 +1483,7 @@ func (s *SkillEditData) copyFrom(other *SkillEditData, isContainer, isApply, isT
 				s.TechniqueDefault.Specialization = ""
 			}
 		}
+		s.TechniqueAlternateDefaults = cloneSkillDefaults(other.TechniqueAlternateDefaults)
 		if other.TechniqueLimitModifier != nil {
 			mod := *other.TechniqueLimitModifier
 			s.TechniqueLimitModifier = &mod
@@ -1425,3 +1506,13 @@ func (s *SkillEditData) copyFrom(other *SkillEditData, isContainer, isApply, isT
 	}
 	s.TemplatePicker = other.TemplatePicker.Clone()
 }
+
+// StudyProgress implements StudyTracker.
+func (s *Skill) StudyProgress() StudyProgress {
+	return NewStudyProgress(s.Study, s.StudyHoursNeeded, s.StudyPointsSpent)
+}
+
+// SetStudyPointsSpent implements StudyTracker.
+func (s *Skill) SetStudyPointsSpent(points fxp.Int) {
+	s.StudyPointsSpent = points
+}
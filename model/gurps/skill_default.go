@@ -52,6 +52,18 @@ func (s *SkillDefault) CloneWithoutLevelOrPoints() *SkillDefault {
 	return &clone
 }
 
+func cloneSkillDefaults(list []*SkillDefault) []*SkillDefault {
+	if len(list) == 0 {
+		return nil
+	}
+	result := make([]*SkillDefault, len(list))
+	for i, def := range list {
+		def2 := *def
+		result[i] = &def2
+	}
+	return result
+}
+
 // Equivalent returns true if this can be considered equivalent to other.
 func (s *SkillDefault) Equivalent(replacements map[string]string, other *SkillDefault) bool {
 	return other != nil &&
@@ -182,7 +194,7 @@ func (s *SkillDefault) SkillLevelFast(entity *Entity, replacements map[string]st
 	}
 	switch s.Type() {
 	case DodgeID:
-		level := entity.Dodge(entity.EncumbranceLevel(false))
+		level := entity.dodge(entity.EncumbranceLevel(false), false)
 		if ruleOf20 && level > 20 {
 			level = 20
 		}
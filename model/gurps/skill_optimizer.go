@@ -0,0 +1,250 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+const (
+	maxSkillPlans          = 5
+	maxSkillPlanBoost      = 3
+	maxSkillPlanIncrements = 50
+)
+
+// SkillTarget holds a skill and the level it should reach.
+type SkillTarget struct {
+	Skill *Skill
+	Level fxp.Int
+}
+
+// SkillPlanStep holds a single change made by a SkillPlan. Exactly one of Skill, Trait or Attribute will be set.
+type SkillPlanStep struct {
+	Skill     *Skill
+	Trait     *Trait
+	Attribute *Attribute
+	// Value is the new raw points for a skill, the new number of levels for a trait or the new adjustment for an
+	// attribute.
+	Value fxp.Int
+	Cost  fxp.Int
+}
+
+// SkillPlan holds a set of changes that bring skills to their target levels.
+type SkillPlan struct {
+	Steps []*SkillPlanStep
+	Cost  fxp.Int
+	// Affordable is true if the cost is covered by the budget the plan was created with.
+	Affordable bool
+}
+
+// SuggestSkillPlans returns the cheapest ways found to bring the skills to their target levels, cheapest first. Besides
+// spending points on the skills directly, plans that first raise the attribute a skill is based on, or the level of a
+// Talent that adds to one of the skills, are considered. Existing defaults and bonuses are accounted for, since skill
+// levels are calculated as usual. Skill points are never lowered.
+func SuggestSkillPlans(entity *Entity, targets []*SkillTarget, budget fxp.Int) []*SkillPlan {
+	if entity == nil || len(targets) == 0 {
+		return nil
+	}
+	baseline := planSkillPoints(targets, nil)
+	var plans []*SkillPlan
+	// Plans that raise an attribute or Talent are only worth suggesting if they are cheaper than buying the skills alone.
+	addIfCheaper := func(plan *SkillPlan) {
+		if plan != nil && (baseline == nil || plan.Cost < baseline.Cost) {
+			plans = append(plans, plan)
+		}
+	}
+	for _, attr := range skillPlanAttributes(entity, targets) {
+		saved := attr.Adjustment
+		before := attr.PointCost()
+		for i := 1; i <= maxSkillPlanBoost; i++ {
+			attr.Adjustment = saved + fxp.FromInteger(i)
+			entity.Recalculate()
+			addIfCheaper(planSkillPoints(targets, &SkillPlanStep{
+				Attribute: attr,
+				Value:     attr.Adjustment,
+				Cost:      attr.PointCost() - before,
+			}))
+		}
+		attr.Adjustment = saved
+	}
+	for _, t := range skillPlanTalents(entity) {
+		saved := t.Levels
+		before := t.AdjustedPoints()
+		for i := 1; i <= maxSkillPlanBoost; i++ {
+			t.Levels = saved + fxp.FromInteger(i)
+			entity.Recalculate()
+			addIfCheaper(planSkillPoints(targets, &SkillPlanStep{
+				Trait: t,
+				Value: t.Levels,
+				Cost:  t.AdjustedPoints() - before,
+			}))
+		}
+		t.Levels = saved
+	}
+	entity.Recalculate()
+	if baseline != nil {
+		plans = append(plans, baseline)
+	}
+	slices.SortStableFunc(plans, func(a, b *SkillPlan) int {
+		if result := cmp.Compare(a.Cost, b.Cost); result != 0 {
+			return result
+		}
+		return len(a.Steps) - len(b.Steps)
+	})
+	if len(plans) > maxSkillPlans {
+		plans = plans[:maxSkillPlans]
+	}
+	for _, plan := range plans {
+		plan.Affordable = plan.Cost <= budget
+	}
+	return plans
+}
+
+func planSkillPoints(targets []*SkillTarget, boost *SkillPlanStep) *SkillPlan {
+	plan := &SkillPlan{}
+	if boost != nil {
+		plan.Steps = append(plan.Steps, boost)
+		plan.Cost = boost.Cost
+	}
+	for _, target := range targets {
+		points, ok := pointsForSkillLevel(target.Skill, target.Level)
+		if !ok {
+			return nil
+		}
+		if points != target.Skill.Points {
+			cost := points - target.Skill.Points
+			plan.Steps = append(plan.Steps, &SkillPlanStep{
+				Skill: target.Skill,
+				Value: points,
+				Cost:  cost,
+			})
+			plan.Cost += cost
+		}
+	}
+	return plan
+}
+
+func pointsForSkillLevel(s *Skill, level fxp.Int) (fxp.Int, bool) {
+	saved := s.Points
+	defer s.SetRawPoints(saved)
+	current := s.CalculateLevel(nil).Level
+	for i := 0; current < level; i++ {
+		if i == maxSkillPlanIncrements {
+			return 0, false
+		}
+		s.IncrementSkillLevel()
+		next := s.CalculateLevel(nil).Level
+		if next <= current {
+			return 0, false
+		}
+		current = next
+	}
+	return s.Points, true
+}
+
+func skillPlanAttributes(entity *Entity, targets []*SkillTarget) []*Attribute {
+	var list []*Attribute
+	for _, target := range targets {
+		if attr, ok := entity.Attributes.Set[target.Skill.Difficulty.Attribute]; ok && !slices.Contains(list, attr) {
+			list = append(list, attr)
+		}
+	}
+	return list
+}
+
+func skillPlanTalents(entity *Entity) []*Trait {
+	var list []*Trait
+	Traverse(func(t *Trait) bool {
+		if !t.IsLeveled() {
+			return false
+		}
+		if t.PowerTalent || slices.ContainsFunc(t.Features, func(f Feature) bool {
+			_, ok := f.(*SkillBonus)
+			return ok
+		}) {
+			list = append(list, t)
+		}
+		return false
+	}, true, true, entity.Traits...)
+	return list
+}
+
+// Apply the plan's changes.
+func (p *SkillPlan) Apply() {
+	for _, step := range p.Steps {
+		switch {
+		case step.Skill != nil:
+			step.Skill.SetRawPoints(step.Value)
+		case step.Trait != nil:
+			step.Trait.Levels = step.Value
+		case step.Attribute != nil:
+			step.Attribute.Adjustment = step.Value
+		}
+	}
+}
+
+// String returns a description of the plan.
+func (p *SkillPlan) String() string {
+	parts := make([]string, 0, len(p.Steps))
+	for _, step := range p.Steps {
+		parts = append(parts, step.String())
+	}
+	if len(parts) == 0 {
+		return i18n.Text("The target levels have already been reached")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// String returns a description of the step.
+func (s *SkillPlanStep) String() string {
+	switch {
+	case s.Skill != nil:
+		return fmt.Sprintf(i18n.Text("%s to %s pts (%s)"), s.Skill.String(), s.Value.String(),
+			s.Cost.StringWithSign())
+	case s.Trait != nil:
+		return fmt.Sprintf(i18n.Text("%s to level %s (%s)"), s.Trait.NameWithReplacements(), s.Value.String(),
+			s.Cost.StringWithSign())
+	case s.Attribute != nil:
+		name := s.Attribute.AttrID
+		if def := s.Attribute.AttributeDef(); def != nil {
+			name = def.Name
+		}
+		return fmt.Sprintf(i18n.Text("%s adjustment to %s (%s)"), name, s.Value.StringWithSign(),
+			s.Cost.StringWithSign())
+	default:
+		return ""
+	}
+}
+
+// Inverse returns a plan that restores the values changed by this plan's steps to what they are now.
+func (p *SkillPlan) Inverse() *SkillPlan {
+	inverse := &SkillPlan{Steps: make([]*SkillPlanStep, 0, len(p.Steps))}
+	for _, step := range p.Steps {
+		one := *step
+		switch {
+		case step.Skill != nil:
+			one.Value = step.Skill.Points
+		case step.Trait != nil:
+			one.Value = step.Trait.Levels
+		case step.Attribute != nil:
+			one.Value = step.Attribute.Adjustment
+		}
+		one.Cost = -step.Cost
+		inverse.Steps = append(inverse.Steps, &one)
+	}
+	inverse.Cost = -p.Cost
+	return inverse
+}
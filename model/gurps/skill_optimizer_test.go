@@ -0,0 +1,60 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSuggestSkillPlans(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	var targets []*SkillTarget
+	for i := range 6 {
+		s := NewSkill(e, nil, false)
+		s.Name = "Skill " + strconv.Itoa(i)
+		s.Points = fxp.One
+		e.Skills = append(e.Skills, s)
+		targets = append(targets, &SkillTarget{Skill: s, Level: fxp.FromInteger(14)})
+	}
+	e.Recalculate()
+	c.Equal(fxp.Nine, e.Skills[0].LevelData.Level)
+
+	plans := SuggestSkillPlans(e, targets[:1], fxp.Ten)
+	c.Equal(1, len(plans))
+	c.Equal(fxp.FromInteger(15), plans[0].Cost)
+	c.False(plans[0].Affordable)
+
+	plans = SuggestSkillPlans(e, targets, fxp.FromInteger(100))
+	c.True(len(plans) > 1)
+	best := plans[0]
+	c.NotNil(best.Steps[0].Attribute)
+	c.Equal(DexterityID, best.Steps[0].Attribute.AttrID)
+	c.True(best.Cost < fxp.FromInteger(90))
+	c.True(best.Affordable)
+	c.Equal(fxp.FromInteger(90), plans[len(plans)-1].Cost)
+	c.Equal(fxp.Int(0), e.Attributes.Set[DexterityID].Adjustment)
+	c.Equal(fxp.One, e.Skills[0].Points)
+
+	inverse := best.Inverse()
+	best.Apply()
+	e.Recalculate()
+	for _, target := range targets {
+		c.True(target.Skill.LevelData.Level >= target.Level, target.Skill.Name)
+	}
+	inverse.Apply()
+	e.Recalculate()
+	c.Equal(fxp.Int(0), e.Attributes.Set[DexterityID].Adjustment)
+	c.Equal(fxp.Nine, e.Skills[0].LevelData.Level)
+}
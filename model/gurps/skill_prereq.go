@@ -10,7 +10,9 @@
 package gurps
 
 import (
+	"fmt"
 	"hash"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/criteria"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/prereq"
@@ -93,27 +95,59 @@ func (p *SkillPrereq) Satisfied(entity *Entity, exclude any, tooltip *xbytes.Ins
 	}
 	if !satisfied && tooltip != nil {
 		tooltip.WriteString(prefix)
-		tooltip.WriteString(HasText(p.Has))
-		tooltip.WriteString(i18n.Text(" a skill whose name "))
-		tooltip.WriteString(p.NameCriteria.String(replacements))
+		tooltip.WriteString(p.requirement(replacements, techLevel != nil))
+	}
+	return satisfied
+}
+
+func (p *SkillPrereq) requirement(replacements map[string]string, matchTechLevel bool) string {
+	var buffer strings.Builder
+	buffer.WriteString(HasText(p.Has))
+	buffer.WriteString(i18n.Text(" a skill whose name "))
+	buffer.WriteString(p.NameCriteria.String(replacements))
+	if p.SpecializationCriteria.Compare != criteria.AnyText {
+		buffer.WriteString(i18n.Text(", specialization "))
+		buffer.WriteString(p.SpecializationCriteria.String(replacements))
+		buffer.WriteByte(',')
+	}
+	if !matchTechLevel {
+		buffer.WriteString(i18n.Text(" and level "))
+		buffer.WriteString(p.LevelCriteria.String())
+	} else {
 		if p.SpecializationCriteria.Compare != criteria.AnyText {
-			tooltip.WriteString(i18n.Text(", specialization "))
-			tooltip.WriteString(p.SpecializationCriteria.String(replacements))
-			tooltip.WriteByte(',')
+			buffer.WriteByte(',')
 		}
-		if techLevel == nil {
-			tooltip.WriteString(i18n.Text(" and level "))
-			tooltip.WriteString(p.LevelCriteria.String())
-		} else {
-			if p.SpecializationCriteria.Compare != criteria.AnyText {
-				tooltip.WriteByte(',')
-			}
-			tooltip.WriteString(i18n.Text(" level "))
-			tooltip.WriteString(p.LevelCriteria.String())
-			tooltip.WriteString(i18n.Text(" and tech level matches"))
+		buffer.WriteString(i18n.Text(" level "))
+		buffer.WriteString(p.LevelCriteria.String())
+		buffer.WriteString(i18n.Text(" and tech level matches"))
+	}
+	return buffer.String()
+}
+
+func (p *SkillPrereq) explain(entity *Entity, exclude any) (requirement, current string) {
+	var replacements map[string]string
+	if na, ok := exclude.(nameable.Accesser); ok {
+		replacements = na.NameableReplacements()
+	}
+	matchTechLevel := false
+	if sk, ok := exclude.(*Skill); ok {
+		matchTechLevel = sk.TechLevel != nil
+	}
+	var best *Skill
+	Traverse(func(sk *Skill) bool {
+		if exclude != sk && p.NameCriteria.Matches(replacements, sk.NameWithReplacements()) &&
+			p.SpecializationCriteria.Matches(replacements, sk.SpecializationWithReplacements()) &&
+			(best == nil || sk.LevelData.Level > best.LevelData.Level) {
+			best = sk
 		}
+		return false
+	}, false, true, entity.Skills...)
+	if best == nil {
+		current = i18n.Text("None")
+	} else {
+		current = fmt.Sprintf(i18n.Text("%s at %s"), best.String(), best.LevelData.Level.String())
 	}
-	return satisfied
+	return p.requirement(replacements, matchTechLevel), current
 }
 
 // Hash writes this object's contents into the hasher.
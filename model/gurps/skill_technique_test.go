@@ -0,0 +1,57 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func newTestSkill(e *Entity, name string, points fxp.Int) *Skill {
+	sk := NewSkill(e, nil, false)
+	sk.Name = name
+	sk.Points = points
+	return sk
+}
+
+func TestTechniqueDefaultChains(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	broadsword := newTestSkill(e, "Broadsword", fxp.Four)
+	shortsword := newTestSkill(e, "Shortsword", fxp.Eight)
+	stopThrust := NewTechnique(e, nil, "Broadsword")
+	stopThrust.Name = "Stop Thrust"
+	stopThrust.TechniqueDefault.Modifier = -fxp.Two
+	feint := NewTechnique(e, nil, "Stop Thrust")
+	feint.Name = "Feint"
+	e.Skills = []*Skill{broadsword, shortsword, stopThrust, feint}
+	e.Recalculate()
+	c.Equal(fxp.FromInteger(11), broadsword.LevelData.Level)
+	c.Equal(fxp.FromInteger(12), shortsword.LevelData.Level)
+	c.Equal(fxp.FromInteger(10), stopThrust.LevelData.Level)
+	c.Equal("Broadsword [11]", stopThrust.LevelData.Resolution)
+
+	stopThrust.TechniqueAlternateDefaults = []*SkillDefault{{
+		DefaultType: SkillID,
+		Name:        "Shortsword",
+		Modifier:    -fxp.One,
+	}}
+	e.Recalculate()
+	c.Equal(fxp.FromInteger(12), stopThrust.LevelData.Level)
+	c.Equal(fxp.Int(0), stopThrust.AdjustedRelativeLevel())
+	c.Equal("Shortsword [12]", stopThrust.LevelData.Resolution)
+	c.Contains(stopThrust.ModifierNotes(), " or Shortsword")
+	c.Contains(stopThrust.CalculateLevel(nil).FullTooltip(), "Shortsword [12]")
+
+	c.Equal(fxp.FromInteger(13), feint.LevelData.Level)
+	c.Equal("Stop Thrust [12] → Shortsword [12]", feint.LevelData.Resolution)
+}
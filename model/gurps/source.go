@@ -10,6 +10,7 @@
 package gurps
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"time"
@@ -135,62 +136,60 @@ func (sm *SrcMatcher) PrepareHashes(provider ListProvider) {
 			}
 			delete(sm.libHashes, libFile)
 		}
-		var srcData libSrcData
-		srcData.timestamp = modTime
-		srcData.dataHashes = make(map[tid.TID]HashAndData)
-		dir := os.DirFS(filepath.Dir(p))
-		file := filepath.Base(p)
 		fi := FileInfoFor(p)
 		if fi == nil || len(fi.Extensions) == 0 {
 			continue
 		}
-		switch fi.Extensions[0] {
-		case TraitsExt:
-			var data []*Trait
-			if data, err = NewTraitsFromFile(dir, file); err == nil {
-				NodesToHashesByID(srcData.dataHashes, data...)
-				Traverse(func(t *Trait) bool {
-					NodesToHashesByID(srcData.dataHashes, t.Modifiers...)
-					return false
-				}, false, false, data...)
-			}
-		case TraitModifiersExt:
-			var data []*TraitModifier
-			if data, err = NewTraitModifiersFromFile(dir, file); err == nil {
-				NodesToHashesByID(srcData.dataHashes, data...)
-			}
-		case SkillsExt:
-			var data []*Skill
-			if data, err = NewSkillsFromFile(dir, file); err == nil {
-				NodesToHashesByID(srcData.dataHashes, data...)
-			}
-		case SpellsExt:
-			var data []*Spell
-			if data, err = NewSpellsFromFile(dir, file); err == nil {
-				NodesToHashesByID(srcData.dataHashes, data...)
-			}
-		case EquipmentExt:
-			var data []*Equipment
-			if data, err = NewEquipmentFromFile(dir, file); err == nil {
-				NodesToHashesByID(srcData.dataHashes, data...)
-				Traverse(func(e *Equipment) bool {
-					NodesToHashesByID(srcData.dataHashes, e.Modifiers...)
-					return false
-				}, false, false, data...)
-			}
-		case EquipmentModifiersExt:
-			var data []*EquipmentModifier
-			if data, err = NewEquipmentModifiersFromFile(dir, file); err == nil {
-				NodesToHashesByID(srcData.dataHashes, data...)
-			}
-		case NotesExt:
-			var data []*Note
-			if data, err = NewNotesFromFile(dir, file); err == nil {
-				NodesToHashesByID(srcData.dataHashes, data...)
-			}
+		sm.libHashes[libFile] = libSrcData{
+			timestamp:  modTime,
+			dataHashes: libraryFileHashes(os.DirFS(filepath.Dir(p)), filepath.Base(p), fi.Extensions[0]),
+		}
+	}
+}
+
+// libraryFileHashes returns the hashes of the items in a library file, keyed by their IDs. Files that cannot be loaded
+// produce an empty result.
+func libraryFileHashes(dir fs.FS, file, ext string) map[tid.TID]HashAndData {
+	result := make(map[tid.TID]HashAndData)
+	switch ext {
+	case TraitsExt:
+		if data, err := NewTraitsFromFile(dir, file); err == nil {
+			NodesToHashesByID(result, data...)
+			Traverse(func(t *Trait) bool {
+				NodesToHashesByID(result, t.Modifiers...)
+				return false
+			}, false, false, data...)
+		}
+	case TraitModifiersExt:
+		if data, err := NewTraitModifiersFromFile(dir, file); err == nil {
+			NodesToHashesByID(result, data...)
+		}
+	case SkillsExt:
+		if data, err := NewSkillsFromFile(dir, file); err == nil {
+			NodesToHashesByID(result, data...)
+		}
+	case SpellsExt:
+		if data, err := NewSpellsFromFile(dir, file); err == nil {
+			NodesToHashesByID(result, data...)
+		}
+	case EquipmentExt:
+		if data, err := NewEquipmentFromFile(dir, file); err == nil {
+			NodesToHashesByID(result, data...)
+			Traverse(func(e *Equipment) bool {
+				NodesToHashesByID(result, e.Modifiers...)
+				return false
+			}, false, false, data...)
+		}
+	case EquipmentModifiersExt:
+		if data, err := NewEquipmentModifiersFromFile(dir, file); err == nil {
+			NodesToHashesByID(result, data...)
+		}
+	case NotesExt:
+		if data, err := NewNotesFromFile(dir, file); err == nil {
+			NodesToHashesByID(result, data...)
 		}
-		sm.libHashes[libFile] = srcData
 	}
+	return result
 }
 
 // Match returns the source state of the given data.
@@ -0,0 +1,134 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// SourceChangesProvider defines the methods needed for data that can report the individual differences between
+// itself and its source.
+type SourceChangesProvider interface {
+	SourceChanges() []*SourceChange
+}
+
+// SourceChange holds a single field that differs between a piece of data and its source.
+type SourceChange struct {
+	Field   string
+	Current string
+	Source  string
+	apply   func()
+}
+
+// Apply replaces the field's current value with the value from the source.
+func (c *SourceChange) Apply() {
+	c.apply()
+}
+
+// collectSourceChanges compares the exported fields of two structs of the same type, which must be passed as
+// pointers, returning a SourceChange for each field that differs.
+func collectSourceChanges(current, source any) []*SourceChange {
+	cv := reflect.ValueOf(current).Elem()
+	sv := reflect.ValueOf(source).Elem()
+	t := cv.Type()
+	var changes []*SourceChange
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		target := cv.Field(i)
+		value := sv.Field(i)
+		cur := sourceChangeText(target)
+		src := sourceChangeText(value)
+		if cur == src {
+			continue
+		}
+		changes = append(changes, &SourceChange{
+			Field:   xstrings.CapitalizeWords(strings.ReplaceAll(key, "_", " ")),
+			Current: cur,
+			Source:  src,
+			apply:   func() { target.Set(reflect.ValueOf(cloneSourceValue(value.Interface()))) },
+		})
+	}
+	return changes
+}
+
+func sourceChangeText(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return ""
+		}
+	default:
+		if v.IsZero() {
+			return ""
+		}
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
+
+func cloneSourceValue(v any) any {
+	switch x := v.(type) {
+	case []string:
+		return slices.Clone(x)
+	case CollegeList:
+		return slices.Clone(x)
+	case *PrereqList:
+		return x.CloneResolvingEmpty(false, true)
+	case []*Weapon:
+		return CloneWeapons(x, false)
+	case Features:
+		return x.Clone()
+	case *TemplatePicker:
+		return x.Clone()
+	case []*SkillDefault:
+		if len(x) == 0 {
+			return x
+		}
+		list := make([]*SkillDefault, len(x))
+		for i, def := range x {
+			def2 := *def
+			list[i] = &def2
+		}
+		return list
+	case *SkillDefault:
+		if x == nil {
+			return x
+		}
+		def := *x
+		return &def
+	case *fxp.Int:
+		if x == nil {
+			return x
+		}
+		value := *x
+		return &value
+	default:
+		return v
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestCollectSourceChanges(t *testing.T) {
+	c := check.New(t)
+	current := TraitNonContainerSyncData{BasePoints: fxp.FromInteger(10), CanLevel: true}
+	source := TraitNonContainerSyncData{BasePoints: fxp.FromInteger(15), CanLevel: true, RoundCostDown: true}
+	changes := collectSourceChanges(&current, &source)
+	c.Equal(2, len(changes))
+	c.Equal("Base Points", changes[0].Field)
+	c.Equal("10", changes[0].Current)
+	c.Equal("15", changes[0].Source)
+	c.Equal("Round Down", changes[1].Field)
+	c.Equal("", changes[1].Current)
+
+	changes[0].Apply()
+	c.Equal(fxp.FromInteger(15), current.BasePoints)
+	c.False(current.RoundCostDown)
+
+	tags := TraitSyncData{Name: "Same", Tags: []string{"a"}}
+	sourceTags := TraitSyncData{Name: "Same", Tags: []string{"a", "b"}}
+	changes = collectSourceChanges(&tags, &sourceTags)
+	c.Equal(1, len(changes))
+	changes[0].Apply()
+	c.Equal([]string{"a", "b"}, tags.Tags)
+	sourceTags.Tags[0] = "z"
+	c.Equal("a", tags.Tags[0])
+}
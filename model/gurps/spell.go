@@ -44,6 +44,7 @@ var (
 	_ SkillAdjustmentProvider[*Spell] = &Spell{}
 	_ TemplatePickerProvider          = &Spell{}
 	_ EditorData[*Spell]              = &SpellEditData{}
+	_ StudyTracker                    = &Spell{}
 )
 
 // Columns that can be used with the spell method .CellData()
@@ -100,15 +101,17 @@ type SpellNonContainerOnlyEditData struct {
 	Points           fxp.Int     `json:"points,omitzero"`
 	Study            []*Study    `json:"study,omitzero"`
 	StudyHoursNeeded study.Level `json:"study_hours_needed,omitzero"`
+	StudyPointsSpent fxp.Int     `json:"study_points_spent,omitzero"`
 }
 
 // SpellSyncData holds the spell sync data that is common to both containers and non-containers.
 type SpellSyncData struct {
-	Name             string   `json:"name,omitzero"`
-	PageRef          string   `json:"reference,omitzero"`
-	PageRefHighlight string   `json:"reference_highlight,omitzero"`
-	LocalNotes       string   `json:"local_notes,omitzero"`
-	Tags             []string `json:"tags,omitzero"`
+	Name             string     `json:"name,omitzero"`
+	PageRef          string     `json:"reference,omitzero"`
+	PageRefHighlight string     `json:"reference_highlight,omitzero"`
+	LocalNotes       string     `json:"local_notes,omitzero"`
+	Tags             []string   `json:"tags,omitzero"`
+	Provenance       Provenance `json:"provenance,omitzero"`
 }
 
 // SpellNonContainerOnlySyncData holds the spell sync data that is only applicable to traits that aren't containers.
@@ -437,6 +440,7 @@ func (s *Spell) CellData(columnID int, data *CellData) {
 		data.Secondary = s.SecondaryText(func(option display.Option) bool { return option.Inline() })
 		data.UnsatisfiedReason = s.UnsatisfiedReason
 		data.Tooltip = s.SecondaryText(func(option display.Option) bool { return option.Tooltip() })
+		data.Tooltip = s.Provenance.AppendToTooltip(data.Tooltip)
 		data.TemplateInfo = s.TemplatePicker.String()
 	case SpellResistColumn:
 		if !s.Container() {
@@ -456,12 +460,14 @@ func (s *Spell) CellData(columnID int, data *CellData) {
 	case SpellCastCostColumn:
 		if !s.Container() {
 			data.Type = cell.Text
-			data.Primary = s.CastingCostWithReplacements()
+			data.Primary = s.CastingCostForDisplay()
+			data.Tooltip = s.CastingModeTooltip()
 		}
 	case SpellMaintainCostColumn:
 		if !s.Container() {
 			data.Type = cell.Text
-			data.Primary = s.MaintenanceCostWithReplacements()
+			data.Primary = s.MaintenanceCostForDisplay()
+			data.Tooltip = s.CastingModeTooltip()
 		}
 	case SpellCastTimeColumn:
 		if !s.Container() {
@@ -537,8 +543,8 @@ func (s *Spell) CellData(columnID int, data *CellData) {
 			var buffer strings.Builder
 			addPartToBuffer(&buffer, i18n.Text("Resistance"), s.ResistWithReplacements())
 			addPartToBuffer(&buffer, i18n.Text("Class"), s.ClassWithReplacements())
-			addPartToBuffer(&buffer, i18n.Text("Cast"), s.CastingCostWithReplacements())
-			addPartToBuffer(&buffer, i18n.Text("Maintain"), s.MaintenanceCostWithReplacements())
+			addPartToBuffer(&buffer, i18n.Text("Cast"), s.CastingCostForDisplay())
+			addPartToBuffer(&buffer, i18n.Text("Maintain"), s.MaintenanceCostForDisplay())
 			addPartToBuffer(&buffer, i18n.Text("Time"), s.CastingTimeWithReplacements())
 			addPartToBuffer(&buffer, i18n.Text("Duration"), s.DurationWithReplacements())
 			addPartToBuffer(&buffer, i18n.Text("College"), strings.Join(s.CollegeWithReplacements(), ", "))
@@ -573,6 +579,7 @@ func (s *Spell) CellData(columnID int, data *CellData) {
 				data.Tooltip += "\n" + s.Source.String()
 			}
 		}
+		data.Tooltip = s.Provenance.AppendToTooltip(data.Tooltip)
 	}
 }
 
@@ -779,13 +786,13 @@ func determineRitualMagicSkillLevelForCollege(e *Entity, name, college, ritualSk
 		def.Name = ""
 	}
 	var limit fxp.Int
-	skillLevel := CalculateTechniqueLevel(e, nil, name, college, tags, def, diff.Difficulty, points, false,
+	skillLevel := CalculateTechniqueLevel(e, nil, name, college, tags, def, nil, diff.Difficulty, points, false,
 		&limit, nil)
 	// CalculateTechniqueLevel() does not add the default skill modifier to the relative level, only to the final level
 	skillLevel.RelativeLevel += def.Modifier
 	def.Specialization = ""
 	def.Modifier -= fxp.Six
-	fallback := CalculateTechniqueLevel(e, nil, name, college, tags, def, diff.Difficulty, points, false,
+	fallback := CalculateTechniqueLevel(e, nil, name, college, tags, def, nil, diff.Difficulty, points, false,
 		&limit, nil)
 	fallback.RelativeLevel += def.Modifier
 	if skillLevel.Level >= fallback.Level {
@@ -920,7 +927,7 @@ func (s *Spell) SecondaryText(optionChecker func(display.Option) bool) string {
 	if optionChecker(prefs.NotesDisplay) {
 		AppendStringOntoNewLine(&buffer, strings.TrimSpace(s.Notes()))
 		AppendStringOntoNewLine(&buffer, s.Rituals())
-		AppendStringOntoNewLine(&buffer, StudyHoursProgressText(ResolveStudyHours(s.Study), s.StudyHoursNeeded, false))
+		AppendStringOntoNewLine(&buffer, StudyProgressNote(EntityFromNode(s), s))
 	}
 	addTooltipForSkillLevelAdj(optionChecker, prefs, s.LevelData, &buffer)
 	return buffer.String()
@@ -1157,6 +1164,25 @@ func (s *Spell) SyncWithSource() {
 	}
 }
 
+// SourceChanges returns the individual differences between this data and its source, if any. Each may be applied
+// separately, rather than syncing everything as SyncWithSource() does.
+func (s *Spell) SourceChanges() []*SourceChange {
+	if !xreflect.IsNil(s.owner) {
+		if state, data := s.owner.SourceMatcher().Match(s); state == srcstate.Mismatched {
+			if other, ok := data.(*Spell); ok {
+				changes := collectSourceChanges(&s.SpellSyncData, &other.SpellSyncData)
+				if s.Container() {
+					return append(changes, collectSourceChanges(&s.SkillContainerOnlySyncData,
+						&other.SkillContainerOnlySyncData)...)
+				}
+				return append(changes, collectSourceChanges(&s.SpellNonContainerOnlySyncData,
+					&other.SpellNonContainerOnlySyncData)...)
+			}
+		}
+	}
+	return nil
+}
+
 // Hash writes this object's contents into the hasher. Note that this only hashes the data that is considered to be
 // "source" data, i.e. not expected to be modified by the user after copying from a library.
 func (s *Spell) Hash(h hash.Hash) {
@@ -1177,6 +1203,7 @@ func (s *SpellSyncData) hash(h hash.Hash) {
 	for _, tag := range s.Tags {
 		xhash.StringWithLen(h, tag)
 	}
+	s.Provenance.hash(h)
 }
 
 func (s *SpellNonContainerOnlySyncData) hash(h hash.Hash) {
@@ -1231,3 +1258,13 @@ func (s *SpellEditData) copyFrom(other *SpellEditData, isContainer, isApply bool
 	}
 	s.TemplatePicker = s.TemplatePicker.Clone()
 }
+
+// StudyProgress implements StudyTracker.
+func (s *Spell) StudyProgress() StudyProgress {
+	return NewStudyProgress(s.Study, s.StudyHoursNeeded, s.StudyPointsSpent)
+}
+
+// SetStudyPointsSpent implements StudyTracker.
+func (s *Spell) SetStudyPointsSpent(points fxp.Int) {
+	s.StudyPointsSpent = points
+}
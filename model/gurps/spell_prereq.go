@@ -11,6 +11,8 @@ package gurps
 
 import (
 	"hash"
+	"strconv"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/criteria"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
@@ -76,6 +78,18 @@ func (p *SpellPrereq) Satisfied(entity *Entity, exclude any, tooltip *xbytes.Ins
 	if na, ok := exclude.(nameable.Accesser); ok {
 		replacements = na.NameableReplacements()
 	}
+	satisfied := p.QuantityCriteria.Matches(fxp.FromInteger(p.count(entity, exclude, replacements)))
+	if !p.Has {
+		satisfied = !satisfied
+	}
+	if !satisfied && tooltip != nil {
+		tooltip.WriteString(prefix)
+		tooltip.WriteString(p.requirement(replacements))
+	}
+	return satisfied
+}
+
+func (p *SpellPrereq) count(entity *Entity, exclude any, replacements map[string]string) int {
 	var techLevel *string
 	if sp, ok := exclude.(*Spell); ok {
 		techLevel = sp.TechLevel
@@ -120,38 +134,44 @@ func (p *SpellPrereq) Satisfied(entity *Entity, exclude any, tooltip *xbytes.Ins
 	if p.SubType == spellcmp.CollegeCount {
 		count = len(colleges)
 	}
-	satisfied := p.QuantityCriteria.Matches(fxp.FromInteger(count))
-	if !p.Has {
-		satisfied = !satisfied
+	return count
+}
+
+func (p *SpellPrereq) requirement(replacements map[string]string) string {
+	var buffer strings.Builder
+	buffer.WriteString(HasText(p.Has))
+	buffer.WriteByte(' ')
+	buffer.WriteString(p.QuantityCriteria.AltString())
+	if p.QuantityCriteria.Qualifier == fxp.One {
+		buffer.WriteString(i18n.Text(" spell "))
+	} else {
+		buffer.WriteString(i18n.Text(" spells "))
 	}
-	if !satisfied && tooltip != nil {
-		tooltip.WriteString(prefix)
-		tooltip.WriteString(HasText(p.Has))
-		tooltip.WriteByte(' ')
-		tooltip.WriteString(p.QuantityCriteria.AltString())
-		if p.QuantityCriteria.Qualifier == fxp.One {
-			tooltip.WriteString(i18n.Text(" spell "))
-		} else {
-			tooltip.WriteString(i18n.Text(" spells "))
-		}
+	switch p.SubType {
+	case spellcmp.Any:
+		buffer.WriteString(i18n.Text("of any kind"))
+	case spellcmp.CollegeCount:
+		buffer.WriteString(i18n.Text("from different colleges"))
+	default:
 		switch p.SubType {
-		case spellcmp.Any:
-			tooltip.WriteString(i18n.Text("of any kind"))
-		case spellcmp.CollegeCount:
-			tooltip.WriteString(i18n.Text("from different colleges"))
-		default:
-			switch p.SubType {
-			case spellcmp.Name:
-				tooltip.WriteString(i18n.Text("whose name "))
-			case spellcmp.Tag:
-				tooltip.WriteString(i18n.Text("whose tag "))
-			case spellcmp.College:
-				tooltip.WriteString(i18n.Text("whose college "))
-			}
-			tooltip.WriteString(p.QualifierCriteria.String(replacements))
+		case spellcmp.Name:
+			buffer.WriteString(i18n.Text("whose name "))
+		case spellcmp.Tag:
+			buffer.WriteString(i18n.Text("whose tag "))
+		case spellcmp.College:
+			buffer.WriteString(i18n.Text("whose college "))
 		}
+		buffer.WriteString(p.QualifierCriteria.String(replacements))
 	}
-	return satisfied
+	return buffer.String()
+}
+
+func (p *SpellPrereq) explain(entity *Entity, exclude any) (requirement, current string) {
+	var replacements map[string]string
+	if na, ok := exclude.(nameable.Accesser); ok {
+		replacements = na.NameableReplacements()
+	}
+	return p.requirement(replacements), strconv.Itoa(p.count(entity, exclude, replacements))
 }
 
 // Hash writes this object's contents into the hasher.
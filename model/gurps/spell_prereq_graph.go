@@ -0,0 +1,196 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/criteria"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/spellcmp"
+	"github.com/richardwilkes/gcs/v5/model/nameable"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// SpellPrereqNode holds a single spell within a SpellPrereqGraph.
+type SpellPrereqNode struct {
+	Name    string
+	College string
+	// Spell is nil when the node represents a prerequisite that isn't present.
+	Spell   *Spell
+	Prereqs []*SpellPrereqNode
+	// Depth is the length of the longest chain of prerequisites leading to this spell.
+	Depth int
+	// Unmet is true if the spell's prerequisites are not all satisfied.
+	Unmet bool
+}
+
+// SpellPrereqCluster holds the nodes of a SpellPrereqGraph that belong to a single college.
+type SpellPrereqCluster struct {
+	College string
+	Nodes   []*SpellPrereqNode
+}
+
+// SpellPrereqGraph holds the graph of prerequisites between a set of spells.
+type SpellPrereqGraph struct {
+	Clusters []*SpellPrereqCluster
+	MaxDepth int
+}
+
+// Missing returns true if the node represents a prerequisite that isn't present.
+func (n *SpellPrereqNode) Missing() bool {
+	return n.Spell == nil
+}
+
+// SpellPrereqNames returns the names of the spells the spell requires by name.
+func SpellPrereqNames(s *Spell) []string {
+	var names []string
+	if s.Container() || s.Prereq == nil {
+		return names
+	}
+	replacements := s.NameableReplacements()
+	var collect func(list *PrereqList)
+	collect = func(list *PrereqList) {
+		for _, one := range list.Prereqs {
+			switch p := one.(type) {
+			case *PrereqList:
+				collect(p)
+			case *SpellPrereq:
+				if p.Has && p.SubType == spellcmp.Name && p.QualifierCriteria.Compare == criteria.IsText {
+					if name := strings.TrimSpace(nameable.Apply(p.QualifierCriteria.Qualifier, replacements)); name != "" &&
+						!slices.ContainsFunc(names, func(existing string) bool { return strings.EqualFold(existing, name) }) {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+	}
+	collect(s.Prereq)
+	return names
+}
+
+// NewSpellPrereqGraph creates the graph of prerequisites between the spells. Prerequisites that aren't among the spells
+// are added as missing nodes. Spells are considered to have unmet prerequisites if they have an unsatisfied reason or
+// are missing one of their prerequisites.
+func NewSpellPrereqGraph(spells []*Spell) *SpellPrereqGraph {
+	nodes := make(map[string]*SpellPrereqNode)
+	var order []*SpellPrereqNode
+	add := func(name string, s *Spell) *SpellPrereqNode {
+		key := strings.ToLower(name)
+		if node, ok := nodes[key]; ok {
+			if node.Spell == nil && s != nil {
+				node.Spell = s
+			}
+			return node
+		}
+		node := &SpellPrereqNode{Name: name, Spell: s}
+		nodes[key] = node
+		order = append(order, node)
+		return node
+	}
+	Traverse(func(s *Spell) bool {
+		add(s.NameWithReplacements(), s)
+		return false
+	}, false, true, spells...)
+	for _, node := range slices.Clone(order) {
+		if colleges := node.Spell.CollegeWithReplacements(); len(colleges) != 0 {
+			node.College = colleges[0]
+		}
+		node.Unmet = node.Spell.UnsatisfiedReason != ""
+		for _, name := range SpellPrereqNames(node.Spell) {
+			prereq := add(name, nil)
+			if prereq.Missing() {
+				prereq.College = node.College
+				node.Unmet = true
+			}
+			node.Prereqs = append(node.Prereqs, prereq)
+		}
+	}
+	g := &SpellPrereqGraph{}
+	visiting := make(map[*SpellPrereqNode]bool)
+	done := make(map[*SpellPrereqNode]bool)
+	var depthOf func(node *SpellPrereqNode) int
+	depthOf = func(node *SpellPrereqNode) int {
+		if done[node] || visiting[node] {
+			return node.Depth
+		}
+		visiting[node] = true
+		for _, prereq := range node.Prereqs {
+			node.Depth = max(node.Depth, depthOf(prereq)+1)
+		}
+		delete(visiting, node)
+		done[node] = true
+		return node.Depth
+	}
+	clusters := make(map[string]*SpellPrereqCluster)
+	for _, node := range order {
+		g.MaxDepth = max(g.MaxDepth, depthOf(node))
+		cluster, ok := clusters[node.College]
+		if !ok {
+			cluster = &SpellPrereqCluster{College: node.College}
+			clusters[node.College] = cluster
+			g.Clusters = append(g.Clusters, cluster)
+		}
+		cluster.Nodes = append(cluster.Nodes, node)
+	}
+	slices.SortFunc(g.Clusters, func(a, b *SpellPrereqCluster) int {
+		if a.College == "" || b.College == "" {
+			return strings.Compare(b.College, a.College)
+		}
+		return xstrings.NaturalCmp(a.College, b.College, true)
+	})
+	for _, cluster := range g.Clusters {
+		slices.SortFunc(cluster.Nodes, func(a, b *SpellPrereqNode) int {
+			if a.Depth != b.Depth {
+				return a.Depth - b.Depth
+			}
+			return xstrings.NaturalCmp(a.Name, b.Name, true)
+		})
+	}
+	return g
+}
+
+// Title returns the title of the cluster.
+func (c *SpellPrereqCluster) Title() string {
+	if c.College == "" {
+		return i18n.Text("No College")
+	}
+	return c.College
+}
+
+// MissingSpellPrereqs returns the spells that must be added for all of the spell's prerequisites, and theirs in turn, to
+// be present among the spells. The lookup is used to find the missing spells by name; the names it cannot find are
+// returned separately. Spells are returned in an order where prerequisites come before the spells that need them.
+func MissingSpellPrereqs(spells []*Spell, target *Spell, lookup func(name string) *Spell) (found []*Spell, notFound []string) {
+	present := make(map[string]bool)
+	Traverse(func(s *Spell) bool {
+		present[strings.ToLower(s.NameWithReplacements())] = true
+		return false
+	}, false, true, spells...)
+	var visit func(s *Spell)
+	visit = func(s *Spell) {
+		for _, name := range SpellPrereqNames(s) {
+			key := strings.ToLower(name)
+			if present[key] {
+				continue
+			}
+			present[key] = true
+			if sp := lookup(name); sp != nil {
+				visit(sp)
+				found = append(found, sp)
+			} else {
+				notFound = append(notFound, name)
+			}
+		}
+	}
+	visit(target)
+	return found, notFound
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func newTestSpell(name, college string, prereqs ...string) *Spell {
+	s := NewSpell(nil, nil, false)
+	s.Name = name
+	s.College = CollegeList{college}
+	s.Prereq = NewPrereqList()
+	for _, one := range prereqs {
+		p := NewSpellPrereq()
+		p.QualifierCriteria.Qualifier = one
+		p.Parent = s.Prereq
+		s.Prereq.Prereqs = append(s.Prereq.Prereqs, p)
+	}
+	return s
+}
+
+func TestSpellPrereqGraph(t *testing.T) {
+	c := check.New(t)
+	light := newTestSpell("Light", "Light")
+	continual := newTestSpell("Continual Light", "Light", "Light")
+	flash := newTestSpell("Flash", "Light", "Continual Light")
+	fireball := newTestSpell("Fireball", "Fire", "Create Fire", "Shape Fire")
+	c.Equal([]string{"Create Fire", "Shape Fire"}, SpellPrereqNames(fireball))
+
+	g := NewSpellPrereqGraph([]*Spell{flash, light, continual, fireball})
+	c.Equal(2, g.MaxDepth)
+	c.Equal(2, len(g.Clusters))
+	c.Equal("Fire", g.Clusters[0].College)
+	c.Equal(3, len(g.Clusters[0].Nodes))
+	c.Equal("Light", g.Clusters[1].College)
+	names := make([]string, 0, len(g.Clusters[1].Nodes))
+	for _, node := range g.Clusters[1].Nodes {
+		names = append(names, node.Name)
+		c.False(node.Unmet, node.Name)
+		c.Equal(node.Name == "Flash", node.Depth == 2, node.Name)
+	}
+	c.Equal("Light, Continual Light, Flash", strings.Join(names, ", "))
+	for _, node := range g.Clusters[0].Nodes {
+		c.Equal(node.Name != "Fireball", node.Missing(), node.Name)
+		c.Equal(node.Name == "Fireball", node.Unmet, node.Name)
+	}
+
+	library := map[string]*Spell{
+		"create fire": newTestSpell("Create Fire", "Fire", "Ignite Fire"),
+		"ignite fire": newTestSpell("Ignite Fire", "Fire"),
+	}
+	found, notFound := MissingSpellPrereqs([]*Spell{fireball}, fireball,
+		func(name string) *Spell { return library[strings.ToLower(name)] })
+	c.Equal(2, len(found))
+	c.Equal("Ignite Fire", found[0].Name)
+	c.Equal("Create Fire", found[1].Name)
+	c.Equal([]string{"Shape Fire"}, notFound)
+}
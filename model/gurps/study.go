@@ -11,6 +11,7 @@ package gurps
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/study"
@@ -57,3 +58,98 @@ func StudyHoursProgressText(hours fxp.Int, needed study.Level, force bool) strin
 	}
 	return "_" + s + "._"
 }
+
+// StudyTracker is implemented by data that can earn the points spent on it through study.
+type StudyTracker interface {
+	// StudyProgress returns the progress made through study.
+	StudyProgress() StudyProgress
+	// SetStudyPointsSpent sets the number of points earned through study that have been spent.
+	SetStudyPointsSpent(points fxp.Int)
+}
+
+// StudyProgress holds the progress made towards earning points through study, per B292.
+type StudyProgress struct {
+	Hours  fxp.Int
+	Needed study.Level
+	Spent  fxp.Int
+}
+
+// NewStudyProgress creates a new StudyProgress.
+func NewStudyProgress(s []*Study, needed study.Level, spent fxp.Int) StudyProgress {
+	return StudyProgress{
+		Hours:  ResolveStudyHours(s),
+		Needed: needed,
+		Spent:  spent,
+	}
+}
+
+// Earned returns the number of whole points earned through study.
+func (p StudyProgress) Earned() fxp.Int {
+	if p.Hours <= 0 {
+		return 0
+	}
+	return p.Hours.Div(p.Needed.Hours()).Floor()
+}
+
+// Available returns the number of points earned through study that have not been spent yet.
+func (p StudyProgress) Available() fxp.Int {
+	return (p.Earned() - p.Spent).Max(0)
+}
+
+// HoursTowardNextPoint returns the number of study hours accumulated toward the next point.
+func (p StudyProgress) HoursTowardNextPoint() fxp.Int {
+	if p.Hours <= 0 {
+		return 0
+	}
+	return p.Hours - p.Earned().Mul(p.Needed.Hours())
+}
+
+// NextPointFraction returns how far along the study toward the next point is, from 0 to 1.
+func (p StudyProgress) NextPointFraction() float32 {
+	return fxp.AsFloat[float32](p.HoursTowardNextPoint().Div(p.Needed.Hours()))
+}
+
+// String returns a description of the progress.
+func (p StudyProgress) String() string {
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, i18n.Text("Earned %s points through study, %s unspent"), p.Earned().Comma(),
+		p.Available().Comma())
+	fmt.Fprintf(&buffer, i18n.Text("; %s of %s hours toward the next point"), p.HoursTowardNextPoint().Comma(),
+		p.Needed.Hours().Comma())
+	return buffer.String()
+}
+
+// StudyProgressNote returns the study note to display with the target, or an empty string.
+func StudyProgressNote(entity *Entity, target StudyTracker) string {
+	p := target.StudyProgress()
+	if SheetSettingsFor(entity).StudyTracking == study.Off {
+		return StudyHoursProgressText(p.Hours, p.Needed, false)
+	}
+	if p.Hours <= 0 && p.Spent <= 0 {
+		return ""
+	}
+	return "_" + p.String() + "._"
+}
+
+// ChargeStudyPoints records a change of delta in the points spent on the target against the points it has earned
+// through study. Increases are paid for with unspent study points; when study is being enforced, an increase that can't
+// be fully paid for is refused and false is returned. Decreases refund study points, up to the number that have been
+// spent. Nothing is recorded when study tracking is off.
+func ChargeStudyPoints(entity *Entity, target StudyTracker, delta fxp.Int) bool {
+	tracking := SheetSettingsFor(entity).StudyTracking
+	if tracking == study.Off || delta == 0 {
+		return true
+	}
+	p := target.StudyProgress()
+	if delta > 0 {
+		available := p.Available()
+		if available < delta {
+			if tracking == study.Enforce {
+				return false
+			}
+			delta = available
+		}
+	}
+	target.SetStudyPointsSpent((p.Spent + delta).Max(0))
+	return true
+}
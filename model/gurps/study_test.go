@@ -0,0 +1,61 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/study"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestStudyProgress(t *testing.T) {
+	c := check.New(t)
+	p := NewStudyProgress([]*Study{
+		{Type: study.Teacher, Hours: fxp.FromInteger(300)},
+		{Type: study.Self, Hours: fxp.FromInteger(200)},
+	}, study.Standard, fxp.One)
+	c.Equal(fxp.FromInteger(400), p.Hours)
+	c.Equal(fxp.Two, p.Earned())
+	c.Equal(fxp.One, p.Available())
+	c.Equal(fxp.Int(0), p.HoursTowardNextPoint())
+
+	p = NewStudyProgress([]*Study{{Type: study.Teacher, Hours: fxp.FromInteger(150)}}, study.Level4, 0)
+	c.Equal(fxp.One, p.Earned())
+	c.Equal(fxp.FromInteger(30), p.HoursTowardNextPoint())
+	c.Equal(float32(0.25), p.NextPointFraction())
+}
+
+func TestChargeStudyPoints(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	s := NewSkill(e, nil, false)
+	s.Name = "Broadsword"
+	s.Points = fxp.One
+	s.Study = []*Study{{Type: study.Teacher, Hours: fxp.FromInteger(200)}}
+	e.Skills = []*Skill{s}
+
+	c.True(ChargeStudyPoints(e, s, fxp.One))
+	c.Equal(fxp.Int(0), s.StudyPointsSpent)
+
+	e.SheetSettings.StudyTracking = study.Enforce
+	c.False(ChargeStudyPoints(e, s, fxp.Two))
+	c.Equal(fxp.Int(0), s.StudyPointsSpent)
+	c.True(ChargeStudyPoints(e, s, fxp.One))
+	c.Equal(fxp.One, s.StudyPointsSpent)
+	c.False(ChargeStudyPoints(e, s, fxp.One))
+	c.True(ChargeStudyPoints(e, s, -fxp.Two))
+	c.Equal(fxp.Int(0), s.StudyPointsSpent)
+
+	e.SheetSettings.StudyTracking = study.Display
+	c.True(ChargeStudyPoints(e, s, fxp.Two))
+	c.Equal(fxp.One, s.StudyPointsSpent)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"maps"
+	"slices"
+)
+
+// TableColumns holds the user's customizations of the columns shown in one of the tables on a sheet. Columns are
+// identified by the column IDs used with .CellData().
+type TableColumns struct {
+	Order  []int           `json:"order,omitzero"`
+	Hidden []int           `json:"hidden,omitzero"`
+	Added  []int           `json:"added,omitzero"`
+	Widths map[int]float32 `json:"widths,omitzero"`
+}
+
+// Clone creates a copy of this.
+func (t *TableColumns) Clone() *TableColumns {
+	if t == nil {
+		return nil
+	}
+	return &TableColumns{
+		Order:  slices.Clone(t.Order),
+		Hidden: slices.Clone(t.Hidden),
+		Added:  slices.Clone(t.Added),
+		Widths: maps.Clone(t.Widths),
+	}
+}
+
+// Empty returns true if no customizations have been made.
+func (t *TableColumns) Empty() bool {
+	return t == nil || (len(t.Order) == 0 && len(t.Hidden) == 0 && len(t.Added) == 0 && len(t.Widths) == 0)
+}
+
+// Arrange returns the available columns in the order the user has chosen, whether or not they are visible. Columns the
+// user hasn't placed are put after the column that precedes them in the available list.
+func (t *TableColumns) Arrange(available []int) []int {
+	result := make([]int, 0, len(available))
+	if t != nil {
+		for _, id := range t.Order {
+			if slices.Contains(available, id) && !slices.Contains(result, id) {
+				result = append(result, id)
+			}
+		}
+	}
+	for i, id := range available {
+		if !slices.Contains(result, id) {
+			pos := 0
+			if i > 0 {
+				pos = slices.Index(result, available[i-1]) + 1
+			}
+			result = slices.Insert(result, pos, id)
+		}
+	}
+	return result
+}
+
+// Visible returns the columns to show, in order. defaults holds the columns that are shown unless the user has hidden
+// them. The required column is always shown.
+func (t *TableColumns) Visible(available, defaults []int, required int) []int {
+	return slices.DeleteFunc(t.Arrange(available), func(id int) bool {
+		return id != required && !t.Shown(id, slices.Contains(defaults, id))
+	})
+}
+
+// Shown returns true if the column should be shown. byDefault should be true if the column is normally shown.
+func (t *TableColumns) Shown(id int, byDefault bool) bool {
+	if t == nil {
+		return byDefault
+	}
+	if byDefault {
+		return !slices.Contains(t.Hidden, id)
+	}
+	return slices.Contains(t.Added, id)
+}
+
+// SetShown sets whether the column should be shown. byDefault should be true if the column is normally shown.
+func (t *TableColumns) SetShown(id int, byDefault, shown bool) {
+	t.Hidden = slices.DeleteFunc(t.Hidden, func(one int) bool { return one == id })
+	t.Added = slices.DeleteFunc(t.Added, func(one int) bool { return one == id })
+	switch {
+	case byDefault && !shown:
+		t.Hidden = append(t.Hidden, id)
+	case !byDefault && shown:
+		t.Added = append(t.Added, id)
+	}
+}
+
+// Width returns the fixed width for the column, or 0 if it should be sized automatically.
+func (t *TableColumns) Width(id int) float32 {
+	if t == nil {
+		return 0
+	}
+	return t.Widths[id]
+}
+
+// SetWidth sets the fixed width for the column. Pass 0 to have it sized automatically.
+func (t *TableColumns) SetWidth(id int, width float32) {
+	if width <= 0 {
+		delete(t.Widths, id)
+		return
+	}
+	if t.Widths == nil {
+		t.Widths = make(map[int]float32)
+	}
+	t.Widths[id] = width
+}
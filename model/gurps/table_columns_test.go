@@ -0,0 +1,70 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestTableColumns(t *testing.T) {
+	c := check.New(t)
+	available := []int{1, 2, 3, 4, 5}
+	defaults := []int{1, 2, 4}
+
+	var columns *TableColumns
+	c.True(columns.Empty())
+	c.Equal(available, columns.Arrange(available))
+	c.Equal(defaults, columns.Visible(available, defaults, 1))
+
+	columns = &TableColumns{}
+	columns.SetShown(3, false, true)
+	columns.SetShown(2, true, false)
+	columns.SetShown(1, true, false)
+	c.Equal([]int{1, 3, 4}, columns.Visible(available, defaults, 1))
+
+	columns.Order = []int{4, 1}
+	c.Equal([]int{4, 5, 1, 2, 3}, columns.Arrange(available))
+	c.Equal([]int{4, 1, 3}, columns.Visible(available, defaults, 1))
+	columns.Order = []int{5, 4, 3, 2, 1}
+	c.Equal([]int{5, 6, 4, 3, 2, 1}, columns.Arrange([]int{1, 2, 3, 4, 5, 6}))
+
+	columns.SetWidth(3, 40)
+	c.Equal(float32(40), columns.Width(3))
+	columns.SetWidth(3, 0)
+	c.Equal(float32(0), columns.Width(3))
+	c.False(columns.Empty())
+}
+
+func TestSheetSettingsColumns(t *testing.T) {
+	c := check.New(t)
+	s := FactorySheetSettings()
+	c.Nil(s.ColumnsFor(BlockLayoutSkillsKey))
+	columns := &TableColumns{}
+	columns.SetShown(SkillTagsColumn, false, true)
+	columns.SetWidth(SkillPointsColumn, 30)
+	s.SetColumnsFor(BlockLayoutSkillsKey, columns)
+
+	data, err := json.Marshal(s)
+	c.NoError(err)
+	var loaded SheetSettings
+	c.NoError(json.Unmarshal(data, &loaded))
+	c.Equal([]int{SkillTagsColumn}, loaded.ColumnsFor(BlockLayoutSkillsKey).Added)
+	c.Equal(float32(30), loaded.ColumnsFor(BlockLayoutSkillsKey).Width(SkillPointsColumn))
+
+	clone := s.Clone(nil)
+	clone.ColumnsFor(BlockLayoutSkillsKey).SetWidth(SkillPointsColumn, 0)
+	c.Equal(float32(30), s.ColumnsFor(BlockLayoutSkillsKey).Width(SkillPointsColumn))
+
+	s.SetColumnsFor(BlockLayoutSkillsKey, &TableColumns{})
+	c.Nil(s.ColumnsFor(BlockLayoutSkillsKey))
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// TagSeparator separates the levels of a hierarchical tag, e.g. "DF/Wizard".
+const TagSeparator = "/"
+
+// TagMatches returns true if the tag is the same as the filter, or is a descendant of it in the tag hierarchy. Case is
+// ignored.
+func TagMatches(filter, tag string) bool {
+	filter = strings.TrimSpace(filter)
+	tag = strings.TrimSpace(tag)
+	if strings.EqualFold(filter, tag) {
+		return true
+	}
+	prefix := filter + TagSeparator
+	return len(tag) > len(prefix) && strings.EqualFold(prefix, tag[:len(prefix)])
+}
+
+// ExpandTagHierarchy returns the tags, plus each of their ancestors in the tag hierarchy, sorted.
+func ExpandTagHierarchy(tags []string) []string {
+	set := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		parts := strings.Split(tag, TagSeparator)
+		for i := range parts {
+			if one := strings.TrimSpace(strings.Join(parts[:i+1], TagSeparator)); one != "" {
+				set[one] = struct{}{}
+			}
+		}
+	}
+	return slices.SortedFunc(maps.Keys(set), func(a, b string) int { return xstrings.NaturalCmp(a, b, true) })
+}
+
+// RenameTag replaces 'from' with 'to' in the tags, including the 'from' portion of any descendants of 'from' in the
+// tag hierarchy. If 'to' is already present, the two are merged. Returns the updated tags and true if anything changed.
+func RenameTag(tags []string, from, to string) ([]string, bool) {
+	from = strings.TrimSpace(from)
+	to = strings.TrimSpace(to)
+	if from == "" || to == "" || from == to {
+		return tags, false
+	}
+	changed := false
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if TagMatches(from, tag) {
+			tag = to + strings.TrimSpace(tag)[len(from):]
+			changed = true
+		}
+		if !slices.ContainsFunc(result, func(one string) bool { return strings.EqualFold(one, tag) }) {
+			result = append(result, tag)
+		}
+	}
+	if !changed {
+		return tags, false
+	}
+	return result, true
+}
+
+// CountTags adds the number of times each tag is used by the nodes, and their children and modifiers, to counts.
+func CountTags[T NodeTypes](counts map[string]int, data ...T) {
+	Traverse(func(node T) bool {
+		if tags := tagsOf(node); tags != nil {
+			for _, tag := range *tags {
+				counts[tag]++
+			}
+		}
+		switch n := any(node).(type) {
+		case *Trait:
+			CountTags(counts, n.Modifiers...)
+		case *Equipment:
+			CountTags(counts, n.Modifiers...)
+		}
+		return false
+	}, false, false, data...)
+}
+
+// RenameTagInNodes calls RenameTag for the nodes, and their children and modifiers. Returns true if anything changed.
+func RenameTagInNodes[T NodeTypes](from, to string, data ...T) bool {
+	changed := false
+	Traverse(func(node T) bool {
+		if tags := tagsOf(node); tags != nil {
+			var updated bool
+			if *tags, updated = RenameTag(*tags, from, to); updated {
+				changed = true
+			}
+		}
+		switch n := any(node).(type) {
+		case *Trait:
+			if RenameTagInNodes(from, to, n.Modifiers...) {
+				changed = true
+			}
+		case *Equipment:
+			if RenameTagInNodes(from, to, n.Modifiers...) {
+				changed = true
+			}
+		}
+		return false
+	}, false, false, data...)
+	return changed
+}
+
+// CountTagsInProvider calls CountTags for each of the lists the provider supports.
+func CountTagsInProvider(counts map[string]int, provider any) {
+	if p, ok := provider.(TraitListProvider); ok {
+		CountTags(counts, p.TraitList()...)
+	}
+	if p, ok := provider.(SkillListProvider); ok {
+		CountTags(counts, p.SkillList()...)
+	}
+	if p, ok := provider.(SpellListProvider); ok {
+		CountTags(counts, p.SpellList()...)
+	}
+	if p, ok := provider.(EquipmentListProvider); ok {
+		CountTags(counts, p.CarriedEquipmentList()...)
+		CountTags(counts, p.OtherEquipmentList()...)
+	}
+}
+
+// RenameTagInProvider calls RenameTagInNodes for each of the lists the provider supports. Returns true if anything
+// changed.
+func RenameTagInProvider(from, to string, provider any) bool {
+	changed := false
+	if p, ok := provider.(TraitListProvider); ok && RenameTagInNodes(from, to, p.TraitList()...) {
+		changed = true
+	}
+	if p, ok := provider.(SkillListProvider); ok && RenameTagInNodes(from, to, p.SkillList()...) {
+		changed = true
+	}
+	if p, ok := provider.(SpellListProvider); ok && RenameTagInNodes(from, to, p.SpellList()...) {
+		changed = true
+	}
+	if p, ok := provider.(EquipmentListProvider); ok {
+		if RenameTagInNodes(from, to, p.CarriedEquipmentList()...) {
+			changed = true
+		}
+		if RenameTagInNodes(from, to, p.OtherEquipmentList()...) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func tagsOf(data any) *[]string {
+	switch n := data.(type) {
+	case *Trait:
+		return &n.Tags
+	case *TraitModifier:
+		return &n.Tags
+	case *Skill:
+		return &n.Tags
+	case *Spell:
+		return &n.Tags
+	case *Equipment:
+		return &n.Tags
+	case *EquipmentModifier:
+		return &n.Tags
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestTagMatches(t *testing.T) {
+	c := check.New(t)
+	c.True(TagMatches("DF", "df"))
+	c.True(TagMatches("DF", "DF/Wizard"))
+	c.True(TagMatches("df/wizard", "DF/Wizard"))
+	c.False(TagMatches("DF", "DFRPG"))
+	c.False(TagMatches("DF/Wizard", "DF"))
+}
+
+func TestExpandTagHierarchy(t *testing.T) {
+	c := check.New(t)
+	c.Equal([]string{"Advantage", "DF", "DF/Cleric", "DF/Wizard"},
+		ExpandTagHierarchy([]string{"DF/Wizard", "Advantage", "DF/Cleric"}))
+}
+
+func TestRenameTag(t *testing.T) {
+	c := check.New(t)
+	tags, changed := RenameTag([]string{"DF/Wizard", "Advantage"}, "DF", "Dungeon Fantasy")
+	c.True(changed)
+	c.Equal([]string{"Dungeon Fantasy/Wizard", "Advantage"}, tags)
+
+	tags, changed = RenameTag([]string{"Perk", "Perks"}, "Perks", "Perk")
+	c.True(changed)
+	c.Equal([]string{"Perk"}, tags)
+
+	_, changed = RenameTag([]string{"Advantage"}, "DF", "Dungeon Fantasy")
+	c.False(changed)
+
+	trait := NewTrait(nil, nil, false)
+	trait.Tags = []string{"Old"}
+	mod := NewTraitModifier(nil, nil, false)
+	mod.Tags = []string{"Old/Sub"}
+	trait.Modifiers = []*TraitModifier{mod}
+	counts := make(map[string]int)
+	CountTags(counts, trait)
+	c.Equal(map[string]int{"Old": 1, "Old/Sub": 1}, counts)
+	c.True(RenameTagInNodes("Old", "New", trait))
+	c.Equal([]string{"New"}, trait.Tags)
+	c.Equal([]string{"New/Sub"}, mod.Tags)
+}
@@ -58,6 +58,14 @@ func (t *TemplatePicker) String() string {
 			points = i18n.Text("point")
 		}
 		return fmt.Sprintf(i18n.Text("Pick %s %s worth"), t.Qualifier.AltString(), points)
+	case picker.Allocate:
+		points := i18n.Text("points")
+		if t.Qualifier.Qualifier == fxp.One {
+			points = i18n.Text("point")
+		}
+		return fmt.Sprintf(i18n.Text("Allocate %s %s"), t.Qualifier.AltString(), points)
+	case picker.Optional:
+		return i18n.Text("Optional")
 	default:
 		return ""
 	}
@@ -68,3 +76,35 @@ func (t *TemplatePicker) Hash(h hash.Hash) {
 	xhash.Num8(h, t.Type)
 	t.Qualifier.Hash(h)
 }
+
+// TemplatePickerPoints returns the number of points the node contributes when being chosen by a template picker.
+// Containers that have a points-based template picker requiring an exact amount contribute that amount.
+func TemplatePickerPoints(node any) fxp.Int {
+	switch n := node.(type) {
+	case *Skill:
+		if points, ok := fixedTemplatePickerPoints(n.Container(), n.TemplatePicker); ok {
+			return points
+		}
+		return n.RawPoints()
+	case *Spell:
+		if points, ok := fixedTemplatePickerPoints(n.Container(), n.TemplatePicker); ok {
+			return points
+		}
+		return n.RawPoints()
+	case *Trait:
+		if points, ok := fixedTemplatePickerPoints(n.Container(), n.TemplatePicker); ok {
+			return points
+		}
+		return n.AdjustedPoints()
+	default:
+		return 0
+	}
+}
+
+func fixedTemplatePickerPoints(container bool, tp *TemplatePicker) (fxp.Int, bool) {
+	if container && tp != nil && (tp.Type == picker.Points || tp.Type == picker.Allocate) &&
+		tp.Qualifier.Compare == criteria.EqualsNumber {
+		return tp.Qualifier.Qualifier, true
+	}
+	return 0, false
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif"  // Register the GIF decoder
+	_ "image/jpeg" // Register the JPEG decoder
+	"image/png"
+	"math"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	_ "golang.org/x/image/bmp" // Register the BMP decoder
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Register the WEBP decoder
+)
+
+// TokenSize is the width and height, in pixels, of the token images created by CreateTokenPNG.
+const TokenSize = 512
+
+// tokenRingWidth is the width, in pixels, of the ring drawn around the edge of a token.
+const tokenRingWidth = TokenSize / 16
+
+// CreateTokenPNG creates a round token image in PNG format, suitable for use in a virtual tabletop. The largest
+// centered square of the image is scaled to fit, clipped to a circle and surrounded by a ring of the given color.
+func CreateTokenPNG(imageData []byte, ringColor color.Color) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return nil, errs.NewWithCause("unable to decode image", err)
+	}
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	if side < 1 {
+		return nil, errs.New("image is empty")
+	}
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	scaled := image.NewNRGBA(image.Rect(0, 0, TokenSize, TokenSize))
+	draw.CatmullRom.Scale(scaled, scaled.Rect, src, image.Rect(x, y, x+side, y+side), draw.Src, nil)
+
+	ring, ok := color.NRGBAModel.Convert(ringColor).(color.NRGBA)
+	if !ok {
+		return nil, errs.New("unable to convert ring color")
+	}
+	dst := image.NewNRGBA(scaled.Rect)
+	const radius = TokenSize / 2.0
+	for py := range TokenSize {
+		for px := range TokenSize {
+			dx := float64(px) + 0.5 - radius
+			dy := float64(py) + 0.5 - radius
+			dist := math.Hypot(dx, dy)
+			// Coverage of the circle as a whole, anti-aliased across the outer edge
+			coverage := clamp01(radius - dist + 0.5)
+			if coverage == 0 {
+				continue
+			}
+			// How much of the pixel belongs to the ring rather than the image beneath it
+			inRing := clamp01(dist - (radius - tokenRingWidth) + 0.5)
+			c := blendNRGBA(scaled.NRGBAAt(px, py), ring, inRing)
+			c.A = uint8(math.Round(float64(c.A) * coverage))
+			dst.SetNRGBA(px, py, c)
+		}
+	}
+	var buffer bytes.Buffer
+	if err = png.Encode(&buffer, dst); err != nil {
+		return nil, errs.NewWithCause("unable to encode token", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func clamp01(v float64) float64 {
+	return max(min(v, 1), 0)
+}
+
+// blendNRGBA places over on top of under with the given amount of opacity, as if over was drawn on top of under.
+func blendNRGBA(under, over color.NRGBA, amount float64) color.NRGBA {
+	overA := float64(over.A) / 255 * amount
+	underA := float64(under.A) / 255
+	outA := overA + underA*(1-overA)
+	if outA == 0 {
+		return color.NRGBA{}
+	}
+	mix := func(u, o uint8) uint8 {
+		return uint8(math.Round((float64(o)*overA + float64(u)*underA*(1-overA)) / outA))
+	}
+	return color.NRGBA{
+		R: mix(under.R, over.R),
+		G: mix(under.G, over.G),
+		B: mix(under.B, over.B),
+		A: uint8(math.Round(outA * 255)),
+	}
+}
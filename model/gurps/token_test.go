@@ -0,0 +1,76 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/portrait"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestCreateTokenPNG(t *testing.T) {
+	c := check.New(t)
+	src := image.NewNRGBA(image.Rect(0, 0, 300, 200))
+	for y := range 200 {
+		for x := range 300 {
+			src.SetNRGBA(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var buffer bytes.Buffer
+	c.NoError(png.Encode(&buffer, src))
+	data, err := CreateTokenPNG(buffer.Bytes(), color.NRGBA{B: 255, A: 255})
+	c.NoError(err)
+	img, err := png.Decode(bytes.NewReader(data))
+	c.NoError(err)
+	c.Equal(image.Rect(0, 0, TokenSize, TokenSize), img.Bounds())
+	_, _, _, a := img.At(0, 0).RGBA()
+	c.Equal(uint32(0), a)
+	c.Equal(color.NRGBA{B: 255, A: 255}, color.NRGBAModel.Convert(img.At(TokenSize/2, tokenRingWidth/2)))
+	center, ok := color.NRGBAModel.Convert(img.At(TokenSize/2, TokenSize/2)).(color.NRGBA)
+	c.True(ok)
+	c.Equal(uint8(255), center.A)
+	c.True(center.R > center.B, "center should show the image rather than the ring")
+
+	_, err = CreateTokenPNG([]byte("not an image"), color.Black)
+	c.HasError(err)
+}
+
+func TestAltPortraits(t *testing.T) {
+	c := check.New(t)
+	var p Profile
+	c.Nil(p.TokenPortraitData())
+	p.PortraitData = []byte{1}
+	p.AltPortraits = []*AltPortrait{
+		{Kind: portrait.FullBody, Data: []byte{2}},
+		{Name: "Wolf", Kind: portrait.Alternate, Data: []byte{3}},
+	}
+	c.Equal([]string{"Portrait", "Full Body", "Wolf"}, p.PortraitChoices())
+	c.Equal([]byte{1}, p.ShownPortraitData())
+	c.Equal([]byte{1}, p.TokenPortraitData())
+
+	p.ShownPortrait = 2
+	c.Equal([]byte{3}, p.ShownPortraitData())
+	c.Equal([]byte{3}, p.TokenPortraitData())
+	p.SetShownPortraitData([]byte{4})
+	c.Equal([]byte{4}, p.AltPortraits[1].Data)
+	c.Equal([]byte{1}, p.PortraitData)
+
+	p.AltPortraits = append(p.AltPortraits, &AltPortrait{Kind: portrait.Token, Data: []byte{5}})
+	c.Equal([]byte{5}, p.TokenPortraitData())
+
+	p.ShownPortrait = 10
+	c.Nil(p.ShownAltPortrait())
+	c.Equal([]byte{1}, p.ShownPortraitData())
+}
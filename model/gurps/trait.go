@@ -26,6 +26,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/display"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/emweight"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/frequency"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/power"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/selfctrl"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/srcstate"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/study"
@@ -47,6 +48,7 @@ var (
 	_ TemplatePickerProvider = &Trait{}
 	_ LeveledOwner           = &Trait{}
 	_ EditorData[*Trait]     = &TraitEditData{}
+	_ StudyTracker           = &Trait{}
 )
 
 // Columns that can be used with the trait method .CellData()
@@ -94,6 +96,8 @@ type TraitNonContainerOnlyEditData struct {
 	Levels           fxp.Int     `json:"levels,omitzero"`
 	Study            []*Study    `json:"study,omitzero"`
 	StudyHoursNeeded study.Level `json:"study_hours_needed,omitzero"`
+	StudyPointsSpent fxp.Int     `json:"study_points_spent,omitzero"`
+	LinkedSheet      string      `json:"linked_sheet,omitzero"`
 }
 
 // TraitSyncData holds the Trait sync data that is common to both containers and non-containers.
@@ -105,6 +109,8 @@ type TraitSyncData struct {
 	Tags             []string            `json:"tags,omitzero"`
 	Prereq           *PrereqList         `json:"prereqs,omitzero"`
 	SelfControlAdj   selfctrl.Adjustment `json:"cr_adj,omitzero"`
+	PowerSource      power.Source        `json:"power_source,omitzero"`
+	Provenance       Provenance          `json:"provenance,omitzero"`
 }
 
 // TraitNonContainerSyncData holds the Trait sync data that is only applicable to traits that aren't containers.
@@ -115,6 +121,7 @@ type TraitNonContainerSyncData struct {
 	Features       Features  `json:"features,omitzero"`
 	RoundCostDown  bool      `json:"round_down,omitzero"`
 	CanLevel       bool      `json:"can_level,omitzero"`
+	PowerTalent    bool      `json:"power_talent,omitzero"`
 }
 
 // TraitContainerSyncData holds the Trait sync data that is only applicable to traits that are containers.
@@ -394,6 +401,7 @@ func (t *Trait) CellData(columnID int, data *CellData) {
 		data.Disabled = t.EffectivelyDisabled()
 		data.UnsatisfiedReason = t.UnsatisfiedReason
 		data.Tooltip = t.SecondaryText(func(option display.Option) bool { return option.Tooltip() })
+		data.Tooltip = t.Provenance.AppendToTooltip(data.Tooltip)
 		if tooltip.Len() != 0 {
 			t := i18n.Text("Trait level adjustments:\n") + strings.ReplaceAll(tooltip.String(), "\n", "\n- ")
 			if data.Tooltip == "" {
@@ -415,6 +423,8 @@ func (t *Trait) CellData(columnID int, data *CellData) {
 				data.InlineTag = i18n.Text("Meta")
 			default:
 			}
+		} else {
+			data.LinkedSheet = t.LinkedSheet
 		}
 	case TraitPointsColumn:
 		data.Type = cell.Text
@@ -442,6 +452,7 @@ func (t *Trait) CellData(columnID int, data *CellData) {
 				data.Tooltip += "\n" + t.Source.String()
 			}
 		}
+		data.Tooltip = t.Provenance.AppendToTooltip(data.Tooltip)
 	}
 }
 
@@ -538,8 +549,16 @@ func (t *Trait) AdjustedPoints() fxp.Int {
 	return points
 }
 
-// AllModifiers returns the modifiers plus any inherited from parents.
+// AllModifiers returns the modifiers plus any inherited from parents, along with the power modifier for the trait's
+// power source, if any. Talents don't receive the power modifier.
 func (t *Trait) AllModifiers() []*TraitModifier {
+	if t.PowerTalent {
+		return t.inheritedModifiers()
+	}
+	return WithPowerModifier(t.inheritedModifiers(), t.EffectivePowerSource())
+}
+
+func (t *Trait) inheritedModifiers() []*TraitModifier {
 	all := make([]*TraitModifier, len(t.Modifiers))
 	copy(all, t.Modifiers)
 	p := t.parent
@@ -696,6 +715,14 @@ func (t *Trait) ModifierNotes() string {
 		buffer.WriteString(mod.FullDescription())
 		return false
 	}, true, true, t.Modifiers...)
+	if t.PowerSource != power.None && !t.PowerTalent {
+		if mod := powerModifierFor(t.inheritedModifiers(), t.PowerSource); mod != nil {
+			if buffer.Len() != 0 {
+				buffer.WriteString("; ")
+			}
+			buffer.WriteString(mod.FullDescription())
+		}
+	}
 	if buffer.Len() != 0 {
 		lines = append(lines, buffer.String())
 	}
@@ -719,7 +746,7 @@ func (t *Trait) SecondaryText(optionChecker func(display.Option) bool) string {
 	}
 	if optionChecker(settings.NotesDisplay) {
 		AppendStringOntoNewLine(&buffer, strings.TrimSpace(t.Notes()))
-		AppendStringOntoNewLine(&buffer, StudyHoursProgressText(ResolveStudyHours(t.Study), t.StudyHoursNeeded, false))
+		AppendStringOntoNewLine(&buffer, StudyProgressNote(EntityFromNode(t), t))
 	}
 	return buffer.String()
 }
@@ -918,6 +945,24 @@ func (t *Trait) SyncWithSource() {
 	}
 }
 
+// SourceChanges returns the individual differences between this data and its source, if any. Each may be applied
+// separately, rather than syncing everything as SyncWithSource() does.
+func (t *Trait) SourceChanges() []*SourceChange {
+	if !xreflect.IsNil(t.owner) {
+		if state, data := t.owner.SourceMatcher().Match(t); state == srcstate.Mismatched {
+			if other, ok := data.(*Trait); ok {
+				changes := collectSourceChanges(&t.TraitSyncData, &other.TraitSyncData)
+				if t.Container() {
+					return append(changes, collectSourceChanges(&t.TraitContainerSyncData, &other.TraitContainerSyncData)...)
+				}
+				return append(changes, collectSourceChanges(&t.TraitNonContainerSyncData,
+					&other.TraitNonContainerSyncData)...)
+			}
+		}
+	}
+	return nil
+}
+
 // Hash writes this object's contents into the hasher. Note that this only hashes the data that is considered to be
 // "source" data, i.e. not expected to be modified by the user after copying from a library.
 func (t *Trait) Hash(h hash.Hash) {
@@ -939,7 +984,9 @@ func (t *TraitSyncData) hash(h hash.Hash) {
 		xhash.StringWithLen(h, tag)
 	}
 	xhash.Num8(h, t.SelfControlAdj)
+	xhash.Num8(h, t.PowerSource)
 	t.Prereq.Hash(h)
+	t.Provenance.hash(h)
 }
 
 func (t *TraitNonContainerSyncData) hash(h hash.Hash) {
@@ -955,6 +1002,7 @@ func (t *TraitNonContainerSyncData) hash(h hash.Hash) {
 	}
 	xhash.Bool(h, t.RoundCostDown)
 	xhash.Bool(h, t.CanLevel)
+	xhash.Bool(h, t.PowerTalent)
 }
 
 func (t *TraitContainerSyncData) hash(h hash.Hash) {
@@ -995,3 +1043,13 @@ func (t *TraitEditData) copyFrom(owner DataOwner, other *TraitEditData, isApply
 	}
 	t.TemplatePicker = t.TemplatePicker.Clone()
 }
+
+// StudyProgress implements StudyTracker.
+func (t *Trait) StudyProgress() StudyProgress {
+	return NewStudyProgress(t.Study, t.StudyHoursNeeded, t.StudyPointsSpent)
+}
+
+// SetStudyPointsSpent implements StudyTracker.
+func (t *Trait) SetStudyPointsSpent(points fxp.Int) {
+	t.StudyPointsSpent = points
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+var linkedSheetPercentRegex = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+
+// LinkedSheetPath returns the path to the character sheet the trait is linked to, such as the sheet for an Ally,
+// Dependent, or Familiar. Relative paths are resolved against baseDir. Returns an empty string if the trait isn't
+// linked to a sheet.
+func (t *Trait) LinkedSheetPath(baseDir string) string {
+	p := strings.TrimSpace(t.LinkedSheet)
+	if p == "" || t.Container() {
+		return ""
+	}
+	p = filepath.FromSlash(p)
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(baseDir, p)
+	}
+	return filepath.Clean(p)
+}
+
+// LoadLinkedSheet loads the character sheet the trait is linked to. Relative paths are resolved against baseDir.
+func (t *Trait) LoadLinkedSheet(baseDir string) (*Entity, error) {
+	p := t.LinkedSheetPath(baseDir)
+	if p == "" {
+		return nil, os.ErrNotExist
+	}
+	return NewEntityFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p))
+}
+
+// LinkedSheetPercent returns the largest percentage of the owner's points the linked character may be built on. This
+// is taken from the first enabled modifier whose name contains a percentage, such as "Built on 50%". Returns false if
+// none of the modifiers specify one.
+func (t *Trait) LinkedSheetPercent() (percent fxp.Int, ok bool) {
+	Traverse(func(mod *TraitModifier) bool {
+		if match := linkedSheetPercentRegex.FindStringSubmatch(mod.NameWithReplacements()); match != nil {
+			var err error
+			if percent, err = fxp.FromString(match[1]); err == nil {
+				ok = true
+				return true
+			}
+		}
+		return false
+	}, true, true, t.Modifiers...)
+	return percent, ok
+}
+
+// LinkedSheetProblem returns a description of why the linked character's point total isn't permitted by the trait, or
+// an empty string if it is.
+func (t *Trait) LinkedSheetProblem(linked *Entity) string {
+	e := EntityFromNode(t)
+	if e == nil || linked == nil || e.TotalPoints <= 0 {
+		return ""
+	}
+	percent, ok := t.LinkedSheetPercent()
+	if !ok {
+		return ""
+	}
+	if allowed := e.TotalPoints.Mul(percent).Div(fxp.Hundred); linked.TotalPoints > allowed {
+		return fmt.Sprintf(i18n.Text("%s is built on %s points, but may be built on no more than %s points (%s%% of %s)"),
+			linked.Profile.Name, linked.TotalPoints.Comma(), allowed.Comma(), percent.Comma(), e.TotalPoints.Comma())
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestTraitLinkedSheet(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	e := NewEntity()
+	e.TotalPoints = fxp.Hundred
+	ally := NewTrait(e, nil, false)
+	ally.Name = "Ally"
+	e.Traits = append(e.Traits, ally)
+	c.Equal("", ally.LinkedSheetPath(dir))
+
+	ally.LinkedSheet = "allies/sidekick" + SheetExt
+	sheetPath := filepath.Join(dir, "allies", "sidekick"+SheetExt)
+	c.Equal(sheetPath, ally.LinkedSheetPath(dir))
+
+	linked := NewEntity()
+	linked.Profile.Name = "Sidekick"
+	linked.TotalPoints = fxp.Seventy
+	_, ok := ally.LinkedSheetPercent()
+	c.False(ok)
+	c.Equal("", ally.LinkedSheetProblem(linked))
+
+	mod := NewTraitModifier(e, nil, false)
+	mod.Name = "Built on 50%"
+	ally.Modifiers = append(ally.Modifiers, mod)
+	percent, ok := ally.LinkedSheetPercent()
+	c.True(ok)
+	c.Equal(fxp.Fifty, percent)
+	c.NotEqual("", ally.LinkedSheetProblem(linked))
+
+	linked.TotalPoints = fxp.Fifty
+	c.Equal("", ally.LinkedSheetProblem(linked))
+
+	mod.Disabled = true
+	_, ok = ally.LinkedSheetPercent()
+	c.False(ok)
+}
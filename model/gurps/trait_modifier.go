@@ -95,11 +95,12 @@ type TraitModifierEditDataNonContainerOnly struct {
 
 // TraitModifierSyncData holds the TraitModifier sync data that is common to both containers and non-containers.
 type TraitModifierSyncData struct {
-	Name             string   `json:"name,omitzero"`
-	PageRef          string   `json:"reference,omitzero"`
-	PageRefHighlight string   `json:"reference_highlight,omitzero"`
-	LocalNotes       string   `json:"local_notes,omitzero"`
-	Tags             []string `json:"tags,omitzero"`
+	Name             string     `json:"name,omitzero"`
+	PageRef          string     `json:"reference,omitzero"`
+	PageRefHighlight string     `json:"reference_highlight,omitzero"`
+	LocalNotes       string     `json:"local_notes,omitzero"`
+	Tags             []string   `json:"tags,omitzero"`
+	Provenance       Provenance `json:"provenance,omitzero"`
 }
 
 // TraitModifierNonContainerSyncData holds the TraitModifier sync data that is only applicable to TraitModifiers that
@@ -332,6 +333,7 @@ func (t *TraitModifier) CellData(columnID int, data *CellData) {
 		data.Primary = t.NameWithReplacements()
 		data.Secondary = t.SecondaryText(func(option display.Option) bool { return option.Inline() })
 		data.Tooltip = t.SecondaryText(func(option display.Option) bool { return option.Tooltip() })
+		data.Tooltip = t.Provenance.AppendToTooltip(data.Tooltip)
 	case TraitModifierCostColumn:
 		if !t.Container() {
 			data.Type = cell.Text
@@ -359,6 +361,7 @@ func (t *TraitModifier) CellData(columnID int, data *CellData) {
 				data.Tooltip += "\n" + t.Source.String()
 			}
 		}
+		data.Tooltip = t.Provenance.AppendToTooltip(data.Tooltip)
 	}
 }
 
@@ -665,6 +668,7 @@ func (t *TraitModifierSyncData) hash(h hash.Hash) {
 	for _, tag := range t.Tags {
 		xhash.StringWithLen(h, tag)
 	}
+	t.Provenance.hash(h)
 }
 
 func (t *TraitModifierNonContainerSyncData) hash(h hash.Hash) {
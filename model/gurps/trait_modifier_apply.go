@@ -0,0 +1,75 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// TraitModifierMatch holds a trait modifier that can be applied, along with where it came from.
+type TraitModifierMatch struct {
+	Modifier *TraitModifier
+	// From is the library the modifier came from, if any.
+	From LibraryFile
+	// Source is a description of where the modifier came from, suitable for display.
+	Source string
+	score  int
+}
+
+// SearchTraitModifiers returns the candidates that fuzzy-match the text, best matches first, up to limit results (0
+// for no limit). Modifiers that are already present on each of the traits are left out.
+func SearchTraitModifiers(traits []*Trait, candidates []*TraitModifierMatch, text string, limit int) []*TraitModifierMatch {
+	var list []*TraitModifierMatch
+	for _, one := range candidates {
+		name := one.Modifier.String()
+		score, ok := FuzzyMatch(text, name)
+		if !ok {
+			if score, ok = FuzzyMatch(text, name+" "+one.Modifier.LocalNotesWithReplacements()); !ok {
+				continue
+			}
+			score--
+		}
+		if len(traits) != 0 && !slices.ContainsFunc(traits, func(t *Trait) bool { return !traitHasModifierNamed(t, name) }) {
+			continue
+		}
+		match := *one
+		match.score = score
+		list = append(list, &match)
+	}
+	slices.SortStableFunc(list, func(a, b *TraitModifierMatch) int {
+		if result := cmp.Compare(b.score, a.score); result != 0 {
+			return result
+		}
+		return strings.Compare(a.Modifier.String(), b.Modifier.String())
+	})
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+func traitHasModifierNamed(t *Trait, name string) bool {
+	found := false
+	Traverse(func(mod *TraitModifier) bool {
+		found = mod.String() == name
+		return found
+	}, false, true, t.Modifiers...)
+	return found
+}
+
+// ApplyTraitModifier adds a copy of the modifier, along with any children it has, to each of the traits. from is the
+// library the modifier came from, if any.
+func ApplyTraitModifier(traits []*Trait, from LibraryFile, modifier *TraitModifier) {
+	for _, t := range traits {
+		t.Modifiers = append(t.Modifiers, modifier.Clone(from, t.DataOwner(), nil, false))
+	}
+}
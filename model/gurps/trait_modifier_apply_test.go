@@ -0,0 +1,66 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	c := check.New(t)
+	_, ok := FuzzyMatch("acc", "Accessibility")
+	c.True(ok)
+	_, ok = FuzzyMatch("xyz", "Accessibility")
+	c.False(ok)
+	score, ok := FuzzyMatch("", "Anything")
+	c.True(ok)
+	c.Equal(0, score)
+	prefix, _ := FuzzyMatch("ra", "Ranged")
+	scattered, _ := FuzzyMatch("ra", "Reduced Armor")
+	c.True(prefix > scattered)
+	wordStart, _ := FuzzyMatch("ro", "Reduced Only")
+	middle, _ := FuzzyMatch("ro", "Reducedo")
+	c.True(wordStart > middle)
+}
+
+func TestSearchTraitModifiers(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	trait := NewTrait(e, nil, false)
+	trait.Name = "Magery"
+	existing := NewTraitModifier(e, nil, false)
+	existing.Name = "One College Only"
+	trait.Modifiers = []*TraitModifier{existing}
+	e.Traits = []*Trait{trait}
+
+	var candidates []*TraitModifierMatch
+	for _, name := range []string{"One College Only", "Accessibility", "Costs Fatigue", "Reduced Range"} {
+		mod := NewTraitModifier(nil, nil, false)
+		mod.Name = name
+		candidates = append(candidates, &TraitModifierMatch{Modifier: mod, Source: "Test"})
+	}
+
+	matches := SearchTraitModifiers([]*Trait{trait}, candidates, "", 0)
+	c.Equal(3, len(matches))
+	matches = SearchTraitModifiers([]*Trait{trait}, candidates, "rr", 0)
+	c.Equal(1, len(matches))
+	c.Equal("Reduced Range", matches[0].Modifier.Name)
+	matches = SearchTraitModifiers([]*Trait{trait}, candidates, "c", 1)
+	c.Equal(1, len(matches))
+	c.Equal("Costs Fatigue", matches[0].Modifier.Name)
+	c.Equal(4, len(SearchTraitModifiers(nil, candidates, "", 0)))
+
+	ApplyTraitModifier([]*Trait{trait}, LibraryFile{}, candidates[1].Modifier)
+	c.Equal(2, len(trait.Modifiers))
+	c.Equal("Accessibility", trait.Modifiers[1].Name)
+	c.True(trait.Modifiers[1] != candidates[1].Modifier)
+}
@@ -10,7 +10,9 @@
 package gurps
 
 import (
+	"fmt"
 	"hash"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/criteria"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
@@ -74,6 +76,21 @@ func (p *TraitPrereq) Satisfied(entity *Entity, exclude any, tooltip *xbytes.Ins
 		replacements = na.NameableReplacements()
 	}
 	satisfied := false
+	p.traverseMatches(entity, exclude, replacements, func(_ *Trait, levels fxp.Int) bool {
+		satisfied = p.LevelCriteria.Matches(levels)
+		return satisfied
+	})
+	if !p.Has {
+		satisfied = !satisfied
+	}
+	if !satisfied && tooltip != nil {
+		tooltip.WriteString(prefix)
+		tooltip.WriteString(p.requirement(replacements))
+	}
+	return satisfied
+}
+
+func (p *TraitPrereq) traverseMatches(entity *Entity, exclude any, replacements map[string]string, f func(t *Trait, levels fxp.Int) bool) {
 	Traverse(func(t *Trait) bool {
 		if exclude == t || !p.NameCriteria.Matches(replacements, t.NameWithReplacements()) {
 			return false
@@ -89,26 +106,36 @@ func (p *TraitPrereq) Satisfied(entity *Entity, exclude any, tooltip *xbytes.Ins
 		if t.IsLeveled() {
 			levels = t.CurrentLevel()
 		}
-		satisfied = p.LevelCriteria.Matches(levels)
-		return satisfied
+		return f(t, levels)
 	}, true, false, entity.Traits...)
-	if !p.Has {
-		satisfied = !satisfied
+}
+
+func (p *TraitPrereq) requirement(replacements map[string]string) string {
+	var buffer strings.Builder
+	buffer.WriteString(HasText(p.Has))
+	buffer.WriteString(i18n.Text(" a trait whose name "))
+	buffer.WriteString(p.NameCriteria.String(replacements))
+	if p.NotesCriteria.Compare != criteria.AnyText {
+		buffer.WriteString(i18n.Text(", notes "))
+		buffer.WriteString(p.NotesCriteria.String(replacements))
+		buffer.WriteByte(',')
 	}
-	if !satisfied && tooltip != nil {
-		tooltip.WriteString(prefix)
-		tooltip.WriteString(HasText(p.Has))
-		tooltip.WriteString(i18n.Text(" a trait whose name "))
-		tooltip.WriteString(p.NameCriteria.String(replacements))
-		if p.NotesCriteria.Compare != criteria.AnyText {
-			tooltip.WriteString(i18n.Text(", notes "))
-			tooltip.WriteString(p.NotesCriteria.String(replacements))
-			tooltip.WriteByte(',')
-		}
-		tooltip.WriteString(i18n.Text(" and level "))
-		tooltip.WriteString(p.LevelCriteria.String())
+	buffer.WriteString(i18n.Text(" and level "))
+	buffer.WriteString(p.LevelCriteria.String())
+	return buffer.String()
+}
+
+func (p *TraitPrereq) explain(entity *Entity, exclude any) (requirement, current string) {
+	var replacements map[string]string
+	if na, ok := exclude.(nameable.Accesser); ok {
+		replacements = na.NameableReplacements()
 	}
-	return satisfied
+	current = i18n.Text("None")
+	p.traverseMatches(entity, exclude, replacements, func(t *Trait, levels fxp.Int) bool {
+		current = fmt.Sprintf(i18n.Text("%s at level %s"), t.NameWithReplacements(), levels.String())
+		return true
+	})
+	return p.requirement(replacements), current
 }
 
 // Hash writes this object's contents into the hasher.
@@ -0,0 +1,286 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"hash"
+	"io/fs"
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/kinds"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+)
+
+var (
+	_ ListProvider     = &Vehicle{}
+	_ DataOwner        = &Vehicle{}
+	_ Hashable         = &Vehicle{}
+	_ PageInfoProvider = &Vehicle{}
+)
+
+// Vehicle holds the data for a vehicle.
+type Vehicle struct {
+	VehicleData
+	srcMatcher *SrcMatcher
+}
+
+// VehicleData holds the Vehicle data that is written to disk.
+type VehicleData struct {
+	Version    int            `json:"version"`
+	ID         tid.TID        `json:"id"`
+	Name       string         `json:"name,omitzero"`
+	TechLevel  string         `json:"tech_level,omitzero"`
+	ST         fxp.Int        `json:"st,omitzero"`
+	HP         fxp.Int        `json:"hp,omitzero"`
+	Handling   fxp.Int        `json:"handling,omitzero"`
+	Stability  fxp.Int        `json:"stability,omitzero"`
+	HT         string         `json:"ht,omitzero"`
+	Move       string         `json:"move,omitzero"`
+	LWt        fxp.Int        `json:"lwt,omitzero"`
+	Load       fxp.Int        `json:"load,omitzero"`
+	SM         fxp.Int        `json:"sm,omitzero"`
+	Occupancy  string         `json:"occupancy,omitzero"`
+	DR         string         `json:"dr,omitzero"`
+	Locations  string         `json:"locations,omitzero"`
+	Range      string         `json:"range,omitzero"`
+	Cost       fxp.Int        `json:"cost,omitzero"`
+	Crew       []*VehicleCrew `json:"crew,omitzero"`
+	Equipment  []*Equipment   `json:"equipment,omitzero"`
+	Notes      []*Note        `json:"notes,omitzero"`
+	ModifiedOn jio.Time       `json:"modified_date"`
+}
+
+// VehicleCrew holds a reference to the character sheet of a member of a vehicle's crew.
+type VehicleCrew struct {
+	// Path is the path to the character sheet, relative to the vehicle file when possible. It always uses forward
+	// slashes as the separator.
+	Path string `json:"path"`
+	// Name is the name of the character at the time they were added to the crew. It is kept so that the crew can be
+	// shown even when the character sheet isn't available.
+	Name string `json:"name,omitzero"`
+	Role string `json:"role,omitzero"`
+}
+
+// NewVehicleFromFile loads a Vehicle from a file.
+func NewVehicleFromFile(fileSystem fs.FS, filePath string) (*Vehicle, error) {
+	var v Vehicle
+	if err := jio.Load(fileSystem, filePath, &v); err != nil {
+		return nil, errs.NewWithCause(InvalidFileData(), err)
+	}
+	if err := jio.CheckVersion(v.Version); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// NewVehicle creates a new Vehicle.
+func NewVehicle() *Vehicle {
+	var v Vehicle
+	v.ID = tid.MustNewTID(kinds.Vehicle)
+	v.ST = fxp.Ten
+	v.HP = fxp.Ten
+	v.HT = "10"
+	v.ModifiedOn = jio.Now()
+	return &v
+}
+
+// Save the Vehicle to a file as JSON.
+func (v *Vehicle) Save(filePath string) error {
+	return jio.SaveToFile(filePath, v)
+}
+
+// MarshalJSONTo implements json.MarshalerTo.
+func (v *Vehicle) MarshalJSONTo(enc *jsontext.Encoder) error {
+	v.EnsureAttachments()
+	v.Version = jio.CurrentDataVersion
+	return json.MarshalEncode(enc, &v.VehicleData)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom.
+func (v *Vehicle) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	v.VehicleData = VehicleData{}
+	if err := json.UnmarshalDecode(dec, &v.VehicleData); err != nil {
+		return err
+	}
+	if !tid.IsKindAndValid(v.ID, kinds.Vehicle) {
+		v.ID = tid.MustNewTID(kinds.Vehicle)
+	}
+	v.EnsureAttachments()
+	return nil
+}
+
+// OwningEntity implements DataOwner.
+func (v *Vehicle) OwningEntity() *Entity {
+	return nil
+}
+
+// SourceMatcher implements DataOwner.
+func (v *Vehicle) SourceMatcher() *SrcMatcher {
+	if v.srcMatcher == nil {
+		v.srcMatcher = &SrcMatcher{}
+	}
+	return v.srcMatcher
+}
+
+// Hash implements Hashable.
+func (v *Vehicle) Hash(h hash.Hash) {
+	saved := v.ModifiedOn
+	v.ModifiedOn = jio.Time{}
+	defer func() { v.ModifiedOn = saved }()
+	if err := json.MarshalWrite(h, v, json.Deterministic(true)); err != nil {
+		errs.Log(err)
+	}
+}
+
+// EnsureAttachments ensures that all attachments have their data owner set properly.
+func (v *Vehicle) EnsureAttachments() {
+	for _, one := range v.Equipment {
+		one.SetDataOwner(v)
+	}
+	for _, one := range v.Notes {
+		one.SetDataOwner(v)
+	}
+}
+
+// SyncWithLibrarySources syncs the vehicle with the library sources.
+func (v *Vehicle) SyncWithLibrarySources() {
+	Traverse(func(item *Equipment) bool {
+		item.SyncWithSource()
+		Traverse(func(modifier *EquipmentModifier) bool {
+			modifier.SyncWithSource()
+			return false
+		}, false, false, item.Modifiers...)
+		return false
+	}, false, false, v.Equipment...)
+	Traverse(func(note *Note) bool {
+		note.SyncWithSource()
+		return false
+	}, false, false, v.Notes...)
+}
+
+// AddCrew adds the character sheet at crewPath to the vehicle's crew, unless it is already aboard. vehiclePath is the
+// location of the vehicle file and is used to store the crew member's path relative to it.
+func (v *Vehicle) AddCrew(vehiclePath, crewPath, name, role string) *VehicleCrew {
+	p := relativeDocumentPath(vehiclePath, crewPath)
+	for _, one := range v.Crew {
+		if one.Path == p {
+			return one
+		}
+	}
+	if name == "" {
+		name = xfilepath.BaseName(crewPath)
+	}
+	crew := &VehicleCrew{Path: p, Name: name, Role: role}
+	v.Crew = append(v.Crew, crew)
+	return crew
+}
+
+// RemoveCrew removes the crew member from the vehicle.
+func (v *Vehicle) RemoveCrew(crew *VehicleCrew) {
+	v.Crew = slices.DeleteFunc(v.Crew, func(one *VehicleCrew) bool { return one == crew })
+}
+
+// DataOwner implements ListProvider.
+func (v *Vehicle) DataOwner() DataOwner {
+	return v
+}
+
+// WeightUnit returns the weight unit to use for display.
+func (v *Vehicle) WeightUnit() fxp.WeightUnit {
+	return GlobalSettings().SheetSettings().DefaultWeightUnits
+}
+
+// OtherEquipmentList implements ListProvider.
+func (v *Vehicle) OtherEquipmentList() []*Equipment {
+	return v.Equipment
+}
+
+// SetOtherEquipmentList implements ListProvider.
+func (v *Vehicle) SetOtherEquipmentList(list []*Equipment) {
+	v.Equipment = list
+}
+
+// NoteList implements ListProvider.
+func (v *Vehicle) NoteList() []*Note {
+	return v.Notes
+}
+
+// SetNoteList implements ListProvider.
+func (v *Vehicle) SetNoteList(list []*Note) {
+	v.Notes = list
+}
+
+// CarriedEquipmentList implements ListProvider.
+func (v *Vehicle) CarriedEquipmentList() []*Equipment {
+	return nil
+}
+
+// SetCarriedEquipmentList implements ListProvider.
+func (v *Vehicle) SetCarriedEquipmentList(_ []*Equipment) {
+}
+
+// SkillList implements ListProvider.
+func (v *Vehicle) SkillList() []*Skill {
+	return nil
+}
+
+// SetSkillList implements ListProvider.
+func (v *Vehicle) SetSkillList(_ []*Skill) {
+}
+
+// SpellList implements ListProvider.
+func (v *Vehicle) SpellList() []*Spell {
+	return nil
+}
+
+// SetSpellList implements ListProvider.
+func (v *Vehicle) SetSpellList(_ []*Spell) {
+}
+
+// TraitList implements ListProvider.
+func (v *Vehicle) TraitList() []*Trait {
+	return nil
+}
+
+// SetTraitList implements ListProvider.
+func (v *Vehicle) SetTraitList(_ []*Trait) {
+}
+
+// PageTitle implements PageInfoProvider.
+func (v *Vehicle) PageTitle() string {
+	return v.Name
+}
+
+// PageKeywords implements PageInfoProvider.
+func (v *Vehicle) PageKeywords() string {
+	return "GCS Vehicle Sheet"
+}
+
+// ModifiedOnString implements PageInfoProvider.
+func (v *Vehicle) ModifiedOnString() string {
+	return v.ModifiedOn.String()
+}
+
+// PageSettings implements PageInfoProvider.
+func (v *Vehicle) PageSettings() *PageSettings {
+	return GlobalSettings().SheetSettings().Page
+}
+
+// AbsolutePath returns the full path to the crew member's character sheet. vehiclePath is the location of the vehicle
+// file.
+func (c *VehicleCrew) AbsolutePath(vehiclePath string) string {
+	return absoluteDocumentPath(vehiclePath, c.Path)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestVehicleCrew(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	vehiclePath := filepath.Join(dir, "truck"+VehicleExt)
+	sheetPath := filepath.Join(dir, "pcs", "driver"+SheetExt)
+	vehicle := NewVehicle()
+	crew := vehicle.AddCrew(vehiclePath, sheetPath, "", "Driver")
+	c.Equal("pcs/driver"+SheetExt, crew.Path)
+	c.Equal("driver", crew.Name)
+	c.Equal(sheetPath, crew.AbsolutePath(vehiclePath))
+	c.Equal(crew, vehicle.AddCrew(vehiclePath, sheetPath, "Bob", "Gunner"))
+	c.Equal(1, len(vehicle.Crew))
+	vehicle.RemoveCrew(crew)
+	c.Equal(0, len(vehicle.Crew))
+}
+
+func TestVehicleSaveAndLoad(t *testing.T) {
+	c := check.New(t)
+	dir := t.TempDir()
+	vehiclePath := filepath.Join(dir, "truck"+VehicleExt)
+	vehicle := NewVehicle()
+	vehicle.Name = "Truck"
+	vehicle.Handling = -fxp.Two
+	vehicle.Stability = fxp.Four
+	vehicle.Move = "2/40"
+	vehicle.AddCrew(vehiclePath, filepath.Join(dir, "driver"+SheetExt), "Alex", "Driver")
+	c.NoError(vehicle.Save(vehiclePath))
+	loaded, err := NewVehicleFromFile(os.DirFS(dir), filepath.Base(vehiclePath))
+	c.NoError(err)
+	c.Equal(vehicle.ID, loaded.ID)
+	c.Equal(vehicle.Name, loaded.Name)
+	c.Equal(vehicle.Handling, loaded.Handling)
+	c.Equal(vehicle.Stability, loaded.Stability)
+	c.Equal(vehicle.Move, loaded.Move)
+	c.Equal(1, len(loaded.Crew))
+	c.Equal("Alex", loaded.Crew[0].Name)
+	c.Equal("Driver", loaded.Crew[0].Role)
+}
@@ -61,6 +61,7 @@ const (
 	WeaponShotsColumn
 	WeaponBulkColumn
 	WeaponRecoilColumn
+	WeaponHitLocationColumn
 )
 
 // WeaponOwner defines the methods required of a Weapon owner.
@@ -493,6 +494,7 @@ func (w *Weapon) SkillLevel(tooltip *xbytes.InsertBuffer) fxp.Int {
 	if best == fxp.Min {
 		return 0
 	}
+	best = entity.CurrentManeuver().SkillAdjustment(w, best, primaryTooltip)
 	AppendBufferOntoNewLine(tooltip, primaryTooltip)
 	if best < 0 {
 		best = 0
@@ -858,6 +860,9 @@ func WeaponHeaderData(columnID int, melee, forPage bool) HeaderData {
 		data.Title = i18n.Text("Bulk")
 	case WeaponRecoilColumn:
 		data.Title = i18n.Text("Recoil")
+	case WeaponHitLocationColumn:
+		data.Title = i18n.Text("Loc")
+		data.Detail = i18n.Text("Hit Location; click to roll a random hit location")
 	}
 	return data
 }
@@ -887,6 +892,7 @@ func (w *Weapon) CellData(columnID int, data *CellData) {
 		data.Primary = w.Block.Resolve(w, &buffer).String()
 	case WeaponDamageColumn:
 		data.Primary = w.Damage.ResolvedDamage(&buffer)
+		data.Secondary = w.ControlDamage()
 	case WeaponReachColumn:
 		reach := w.Reach.Resolve(w, &buffer)
 		data.Primary = reach.String()
@@ -915,6 +921,10 @@ func (w *Weapon) CellData(columnID int, data *CellData) {
 		recoil := w.Recoil.Resolve(w, &buffer)
 		data.Primary = recoil.String()
 		data.Tooltip = recoil.Tooltip()
+	case WeaponHitLocationColumn:
+		data.Primary = i18n.Text("Random")
+		data.Alignment = align.Middle
+		data.Tooltip = w.HitLocationPenalties()
 	case PageRefCellAlias:
 		data.Type = cell.PageRef
 	}
@@ -926,6 +936,23 @@ func (w *Weapon) CellData(columnID int, data *CellData) {
 	}
 }
 
+// HitLocationPenalties returns a description of the to-hit penalty and resulting effective skill level for attacks
+// against each of the hit locations in the owning entity's body type.
+func (w *Weapon) HitLocationPenalties() string {
+	entity := w.Entity()
+	if entity == nil {
+		return ""
+	}
+	level := w.SkillLevel(nil)
+	var buffer strings.Builder
+	buffer.WriteString(i18n.Text("Effective skill by hit location:"))
+	for _, loc := range BodyFor(entity).UniqueHitLocations(entity) {
+		penalty := fxp.FromInteger(loc.HitPenalty)
+		fmt.Fprintf(&buffer, "\n%s [%s]: %s", loc.ChoiceName, penalty.StringWithSign(), (level + penalty).Max(0).String())
+	}
+	return buffer.String()
+}
+
 // CopyFrom implements node.EditorData.
 func (w *Weapon) CopyFrom(t *Weapon) {
 	*w = *t.Clone(LibraryFile{}, t.DataOwner(), nil, false)
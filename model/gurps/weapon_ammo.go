@@ -0,0 +1,163 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+// OwningEquipment returns the equipment this weapon belongs to, or nil if it doesn't belong to equipment.
+func (w *Weapon) OwningEquipment() *Equipment {
+	if eqp, ok := w.Owner.(*Equipment); ok {
+		return eqp
+	}
+	return nil
+}
+
+// Ammunition returns the equipment consumed when this weapon is fired, or nil if ammunition isn't being tracked for it.
+// Ranged weapons use the ammunition linked to their equipment. Thrown weapons without a link use up the equipment
+// itself.
+func (w *Weapon) Ammunition() *Equipment {
+	if !w.IsRanged() {
+		return nil
+	}
+	eqp := w.OwningEquipment()
+	if eqp == nil {
+		return nil
+	}
+	if tid.IsValid(eqp.AmmoID) {
+		return eqp.ammunitionFor()
+	}
+	if w.Shots.Resolve(w, nil).Thrown {
+		return eqp
+	}
+	return nil
+}
+
+func (e *Equipment) ammunitionFor() *Equipment {
+	entity := EntityFromNode(e)
+	if entity == nil {
+		return nil
+	}
+	var ammo *Equipment
+	Traverse(func(one *Equipment) bool {
+		if one.TID == e.AmmoID {
+			ammo = one
+			return true
+		}
+		return false
+	}, false, true, entity.CarriedEquipment...)
+	return ammo
+}
+
+// AmmoCapacity returns the number of shots the weapon holds once loaded, or zero if the weapon doesn't need to be
+// reloaded between shots.
+func (w *Weapon) AmmoCapacity() fxp.Int {
+	shots := w.Shots.Resolve(w, nil)
+	if shots.Thrown {
+		return 0
+	}
+	return shots.Count + shots.InChamber
+}
+
+// AmmoLoaded returns the number of shots currently loaded into the weapon.
+func (w *Weapon) AmmoLoaded() fxp.Int {
+	eqp := w.OwningEquipment()
+	if eqp == nil {
+		return 0
+	}
+	loaded := eqp.AmmoLoaded.Min(w.AmmoCapacity())
+	if ammo := w.Ammunition(); ammo != nil {
+		loaded = loaded.Min(ammo.Quantity)
+	}
+	return loaded.Max(0)
+}
+
+// CanUseAmmo returns true if ammunition is being tracked for this weapon and some remains.
+func (w *Weapon) CanUseAmmo() bool {
+	ammo := w.Ammunition()
+	return ammo != nil && ammo.Quantity > 0
+}
+
+// UseAmmo consumes the ammunition for a single shot from the weapon. Returns true if the weapon can be fired, along with
+// a warning, if any, to be shown to the user. A weapon with no ammunition tracking can always be fired.
+func (w *Weapon) UseAmmo() (fired bool, warning string) {
+	ammo := w.Ammunition()
+	if ammo == nil {
+		return true, ""
+	}
+	if ammo.Quantity <= 0 {
+		return false, fmt.Sprintf(i18n.Text("%s is out of ammunition (%s)."), w.String(), ammo.String())
+	}
+	eqp := w.OwningEquipment()
+	if capacity := w.AmmoCapacity(); capacity > 0 {
+		loaded := w.AmmoLoaded()
+		if loaded <= 0 {
+			return false, fmt.Sprintf(i18n.Text("%s must be reloaded before it can be fired again. %s"), w.String(),
+				w.ReloadDescription())
+		}
+		eqp.AmmoLoaded = loaded - fxp.One
+	}
+	ammo.Quantity = (ammo.Quantity - fxp.One).Max(0)
+	if ammo.Quantity == 0 {
+		if ammo == eqp {
+			return true, fmt.Sprintf(i18n.Text("That was the last %s."), ammo.String())
+		}
+		return true, fmt.Sprintf(i18n.Text("That was the last of the %s."), ammo.String())
+	}
+	return true, ""
+}
+
+// NeedsReload returns true if the weapon tracks a loaded count and it isn't full, while ammunition remains to load.
+func (w *Weapon) NeedsReload() bool {
+	capacity := w.AmmoCapacity()
+	if capacity <= 0 {
+		return false
+	}
+	ammo := w.Ammunition()
+	return ammo != nil && w.AmmoLoaded() < capacity.Min(ammo.Quantity)
+}
+
+// Reload fills the weapon from its ammunition, up to its capacity. Returns false if there was nothing to reload.
+func (w *Weapon) Reload() bool {
+	if !w.NeedsReload() {
+		return false
+	}
+	w.OwningEquipment().AmmoLoaded = w.AmmoCapacity().Min(w.Ammunition().Quantity)
+	return true
+}
+
+// ReloadDescription returns a description of how long it takes to reload the weapon, based on its Shots notation.
+func (w *Weapon) ReloadDescription() string {
+	shots := w.Shots.Resolve(w, nil)
+	switch {
+	case shots.ReloadTime <= 0:
+		return ""
+	case shots.ReloadTimeIsPerShot:
+		return fmt.Sprintf(i18n.Text("Reloading takes %s seconds per shot."), shots.ReloadTime.Comma())
+	default:
+		return fmt.Sprintf(i18n.Text("Reloading takes %s seconds."), shots.ReloadTime.Comma())
+	}
+}
+
+// AmmoStatus returns a description of the ammunition loaded into the equipment's ranged weapons, or an empty string if
+// none of them track a loaded count.
+func (e *Equipment) AmmoStatus() string {
+	for _, w := range e.Weapons {
+		if capacity := w.AmmoCapacity(); capacity > 0 && w.Ammunition() != nil {
+			return fmt.Sprintf(i18n.Text("%s of %s loaded"), w.AmmoLoaded().Comma(), capacity.Comma())
+		}
+	}
+	return ""
+}
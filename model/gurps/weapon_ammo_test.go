@@ -0,0 +1,81 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestWeaponAmmo(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	gun := NewEquipment(e, nil, false)
+	ammo := NewEquipment(e, nil, false)
+	ammo.Quantity = fxp.Three
+	w := NewWeapon(gun, false)
+	w.Shots = ParseWeaponShots("2(3)")
+	gun.Weapons = []*Weapon{w}
+	e.CarriedEquipment = []*Equipment{gun, ammo}
+
+	c.Nil(w.Ammunition())
+	fired, warning := w.UseAmmo()
+	c.True(fired)
+	c.Equal("", warning)
+
+	gun.AmmoID = ammo.TID
+	c.Equal(ammo, w.Ammunition())
+	c.Equal(fxp.Two, w.AmmoCapacity())
+	fired, warning = w.UseAmmo()
+	c.False(fired)
+	c.Contains(warning, "3 seconds")
+	c.True(w.NeedsReload())
+	c.True(w.Reload())
+	c.Equal(fxp.Two, w.AmmoLoaded())
+
+	for range 2 {
+		fired, _ = w.UseAmmo()
+		c.True(fired)
+	}
+	c.Equal(fxp.One, ammo.Quantity)
+	fired, _ = w.UseAmmo()
+	c.False(fired)
+	c.True(w.Reload())
+	c.Equal(fxp.One, w.AmmoLoaded())
+	fired, warning = w.UseAmmo()
+	c.True(fired)
+	c.NotEqual("", warning)
+	c.Equal(fxp.Int(0), ammo.Quantity)
+	c.False(w.NeedsReload())
+	c.False(w.CanUseAmmo())
+	fired, warning = w.UseAmmo()
+	c.False(fired)
+	c.Contains(warning, "out of ammunition")
+}
+
+func TestWeaponAmmoThrown(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	knife := NewEquipment(e, nil, false)
+	knife.Quantity = fxp.Two
+	w := NewWeapon(knife, false)
+	w.Shots = ParseWeaponShots("T(1)")
+	knife.Weapons = []*Weapon{w}
+	e.CarriedEquipment = []*Equipment{knife}
+
+	c.Equal(knife, w.Ammunition())
+	c.Equal(fxp.Int(0), w.AmmoCapacity())
+	c.False(w.NeedsReload())
+	fired, _ := w.UseAmmo()
+	c.True(fired)
+	c.Equal(fxp.One, knife.Quantity)
+}
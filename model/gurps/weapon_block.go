@@ -111,6 +111,11 @@ func (wb WeaponBlock) Resolve(w *Weapon, modifiersTooltip *xbytes.InsertBuffer)
 				if percentModifier != 0 {
 					result.Modifier += result.Modifier.Mul(percentModifier).Div(fxp.Hundred).Floor()
 				}
+				if adj, allowed := entity.CurrentManeuver().DefenseAdjustment(BlockID, modifiersTooltip); allowed {
+					result.Modifier += fxp.FromInteger(adj)
+				} else {
+					result.CanBlock = false
+				}
 				result.Modifier = result.Modifier.Max(0).Floor()
 			} else {
 				result.Modifier = 0
@@ -338,6 +338,7 @@ func (w *WeaponDamage) ResolvedDamage(tooltip *xbytes.InsertBuffer) string {
 		}
 		base.Modifier += fxp.AsInteger[int](amt)
 	}
+	base.Modifier += entity.CurrentManeuver().DamageAdjustment(w.Owner, base.Count, tooltip)
 	if percentDamageBonus != 0 {
 		base = adjustDiceForPercentBonus(base, percentDamageBonus)
 	}
@@ -121,6 +121,11 @@ func (wp WeaponParry) Resolve(w *Weapon, modifiersTooltip *xbytes.InsertBuffer)
 				if percentModifier != 0 {
 					result.Modifier += result.Modifier.Mul(percentModifier).Div(fxp.Hundred).Floor()
 				}
+				if adj, allowed := entity.CurrentManeuver().DefenseAdjustment(ParryID, modifiersTooltip); allowed {
+					result.Modifier += fxp.FromInteger(adj)
+				} else {
+					result.CanParry = false
+				}
 				result.Modifier = result.Modifier.Max(0).Floor()
 			} else {
 				result.Modifier = 0
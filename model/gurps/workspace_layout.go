@@ -0,0 +1,33 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+	"github.com/richardwilkes/unison"
+)
+
+// WorkspaceLayout holds a snapshot of the open dockables, how they were arranged and how far each had been scrolled.
+type WorkspaceLayout struct {
+	TopDockState    *unison.DockState     `json:"top_dock_state,omitzero"`
+	DocDockState    *unison.DockState     `json:"doc_dock_state,omitzero"`
+	ScrollPositions map[string]geom.Point `json:"scroll_positions,omitzero"`
+}
+
+// WorkspaceNames returns the names of the saved workspaces, sorted.
+func (s *Settings) WorkspaceNames() []string {
+	names := make([]string, 0, len(s.Workspaces))
+	for k := range s.Workspaces {
+		names = append(names, k)
+	}
+	xstrings.SortStringsNaturalAscending(names)
+	return names
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"hash"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/xhash"
+	"github.com/zeebo/xxh3"
+)
+
+var _ Hashable = &Wound{}
+
+// WoundDamageTypes holds the damage types that can be chosen when recording a wound.
+var WoundDamageTypes = []string{"cr", "cut", "imp", "pi-", "pi", "pi+", "pi++", "burn", "cor", "tox", "fat"}
+
+// Wound holds information about a single hit suffered by a character.
+type Wound struct {
+	When       jio.Time `json:"when"`
+	LocationID string   `json:"location"`
+	DamageType string   `json:"type"`
+	Notes      string   `json:"notes,omitzero"`
+	Basic      int      `json:"basic"`
+	DR         int      `json:"dr,omitzero"`
+	Injury     int      `json:"injury"`
+	Crippling  bool     `json:"crippling,omitzero"`
+	Bleeding   bool     `json:"bleeding,omitzero"`
+}
+
+// CloneWoundList creates a clone of the provided Wound list.
+func CloneWoundList(list []*Wound) []*Wound {
+	clone := make([]*Wound, len(list))
+	for i := range list {
+		wound := *list[i]
+		clone[i] = &wound
+	}
+	return clone
+}
+
+// HashWounds returns a hash value for the provided Wound list.
+func HashWounds(list []*Wound) uint64 {
+	h := xxh3.New()
+	xhash.Num64(h, len(list))
+	for _, one := range list {
+		one.Hash(h)
+	}
+	return h.Sum64()
+}
+
+// Hash writes this object's contents into the hasher.
+func (w *Wound) Hash(h hash.Hash) {
+	xhash.Num64(h, time.Time(w.When).UnixNano())
+	xhash.StringWithLen(h, w.LocationID)
+	xhash.StringWithLen(h, w.DamageType)
+	xhash.StringWithLen(h, w.Notes)
+	xhash.Num64(h, w.Basic)
+	xhash.Num64(h, w.DR)
+	xhash.Num64(h, w.Injury)
+	xhash.Bool(h, w.Crippling)
+	xhash.Bool(h, w.Bleeding)
+}
+
+// WoundingMultiplier returns the wounding multiplier for the damage type when striking the given hit location, per the
+// rules on p. B379 and pp. B398-400.
+func WoundingMultiplier(locationID, damageType string) fxp.Int {
+	var mult fxp.Int
+	switch damageType {
+	case "pi-":
+		mult = fxp.Half
+	case "cut", "pi+":
+		mult = fxp.OneAndAHalf
+	case "imp", "pi++":
+		mult = fxp.Two
+	default:
+		mult = fxp.One
+	}
+	switch locationID {
+	case "skull", "eye":
+		if damageType != "tox" && damageType != "fat" {
+			mult = fxp.Four
+		}
+	case "neck":
+		switch damageType {
+		case "cr", "cor":
+			mult = fxp.OneAndAHalf
+		case "cut":
+			mult = fxp.Two
+		}
+	case "vitals":
+		if damageType == "imp" || strings.HasPrefix(damageType, "pi") {
+			mult = fxp.Three
+		}
+	case "arm", "leg", "hand", "foot":
+		if damageType == "imp" || damageType == "pi+" || damageType == "pi++" {
+			mult = fxp.One
+		}
+	}
+	return mult
+}
+
+// NewWound creates a new wound for a hit of the given basic damage against the hit location. The DR for the location
+// is applied automatically, along with the wounding multiplier, and the crippling and bleeding flags are determined.
+func (e *Entity) NewWound(locationID, damageType string, basic int) *Wound {
+	w := &Wound{
+		When:       jio.Now(),
+		LocationID: locationID,
+		DamageType: damageType,
+		Basic:      max(basic, 0),
+	}
+	if loc := BodyFor(e).LookupLocationByID(e, locationID); loc != nil {
+		drMap := loc.DR(e, nil, nil)
+		w.DR = drMap[AllID]
+		if damageType != "" {
+			w.DR += drMap[damageType]
+		}
+	}
+	if penetrating := w.Basic - w.DR; penetrating > 0 {
+		w.Injury = max(fxp.AsInteger[int]((fxp.FromInteger(penetrating).Mul(WoundingMultiplier(locationID,
+			damageType))).Floor()), 1)
+	}
+	if w.Injury > 0 {
+		hp := e.hitPoints()
+		switch locationID {
+		case "arm", "leg":
+			w.Crippling = fxp.FromInteger(w.Injury) > hp.Div(fxp.Two)
+		case "hand", "foot":
+			w.Crippling = fxp.FromInteger(w.Injury) > hp.Div(fxp.Three)
+		case "eye":
+			w.Crippling = fxp.FromInteger(w.Injury) > hp.Div(fxp.Ten)
+		}
+		switch damageType {
+		case "cut", "imp", "pi-", "pi", "pi+", "pi++":
+			w.Bleeding = true
+		}
+	}
+	return w
+}
+
+func (e *Entity) hitPoints() fxp.Int {
+	if attr, ok := e.Attributes.Set[hpAttrID]; ok {
+		return attr.Maximum()
+	}
+	return fxp.Ten
+}
+
+// RecordWound adds the wound to the entity and applies its injury to the entity's hit points.
+func (e *Entity) RecordWound(w *Wound) {
+	e.Wounds = append(e.Wounds, w)
+	if attr, ok := e.Attributes.Set[hpAttrID]; ok {
+		attr.Damage += fxp.FromInteger(w.Injury)
+	}
+}
+
+// SetWounds replaces the entity's wounds. Hit points are not adjusted.
+func (e *Entity) SetWounds(wounds []*Wound) {
+	e.Wounds = CloneWoundList(wounds)
+}
+
+// ShockPenalty returns the shock penalty to DX and IQ resulting from the most recently recorded wound, per p. B419.
+func (e *Entity) ShockPenalty() int {
+	if len(e.Wounds) == 0 {
+		return 0
+	}
+	injury := e.Wounds[len(e.Wounds)-1].Injury
+	if injury <= 0 {
+		return 0
+	}
+	per := max(fxp.AsInteger[int](e.hitPoints().Div(fxp.Ten).Floor()), 1)
+	return -min(max(injury/per, 1), 4)
+}
+
+// CrippledLocationIDs returns the IDs of the hit locations that have been crippled by wounds.
+func (e *Entity) CrippledLocationIDs() []string {
+	var list []string
+	seen := make(map[string]bool)
+	for _, w := range e.Wounds {
+		if w.Crippling && !seen[w.LocationID] {
+			seen[w.LocationID] = true
+			list = append(list, w.LocationID)
+		}
+	}
+	return list
+}
+
+// Bleeding returns true if any wound is bleeding.
+func (e *Entity) Bleeding() bool {
+	for _, w := range e.Wounds {
+		if w.Bleeding {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package gurps
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestWoundingMultiplier(t *testing.T) {
+	c := check.New(t)
+	c.Equal(fxp.OneAndAHalf, WoundingMultiplier("torso", "cut"))
+	c.Equal(fxp.Two, WoundingMultiplier("torso", "imp"))
+	c.Equal(fxp.Four, WoundingMultiplier("skull", "cr"))
+	c.Equal(fxp.One, WoundingMultiplier("skull", "tox"))
+	c.Equal(fxp.Three, WoundingMultiplier("vitals", "pi"))
+	c.Equal(fxp.Two, WoundingMultiplier("neck", "cut"))
+	c.Equal(fxp.One, WoundingMultiplier("arm", "imp"))
+}
+
+func TestRecordWound(t *testing.T) {
+	c := check.New(t)
+	e := NewEntity()
+	e.Recalculate()
+	w := e.NewWound("torso", "cut", 5)
+	c.Equal(0, w.DR)
+	c.Equal(7, w.Injury)
+	c.True(w.Bleeding)
+	c.False(w.Crippling)
+	e.RecordWound(w)
+	c.Equal(fxp.Three, e.Attributes.Set["hp"].Current())
+	c.Equal(-4, e.ShockPenalty())
+
+	w = e.NewWound("arm", "cr", 6)
+	c.Equal(6, w.Injury)
+	c.True(w.Crippling)
+	c.False(w.Bleeding)
+	e.RecordWound(w)
+	c.Equal([]string{"arm"}, e.CrippledLocationIDs())
+
+	c.Equal(0, e.NewWound("torso", "cr", 0).Injury)
+}
@@ -38,6 +38,7 @@ const (
 	TraitContainer             = 'T'
 	TraitModifier              = 'm'
 	TraitModifierContainer     = 'M'
+	Vehicle                    = 'V'
 	WeaponMelee                = 'w'
 	WeaponRanged               = 'W'
 )
@@ -0,0 +1,173 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package merge
+
+import (
+	"encoding/json/jsontext"
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+// calcKey is the key of the values that a document calculates from the rest of its contents.
+const calcKey = "calc"
+
+const (
+	diffInputs      = iota // Report everything except derived values
+	diffCalculated         // Report only the values held within calculated values
+	diffAll                // Report everything, as we are within a calculated value
+	diffIgnoringIDs        // Report everything except derived values and IDs, matching list entries by label
+)
+
+// Change describes a value that differs between two copies of a JSON document.
+type Change struct {
+	// Path describes where the value is located, in the same form as Conflict.Path.
+	Path []string
+	// Before and After hold the value from each copy of the document. A nil value means the value is absent from that
+	// copy.
+	Before jsontext.Value
+	After  jsontext.Value
+}
+
+// Diff returns the changes made to a JSON document between before and after. Objects, along with lists whose entries
+// all carry an "id", are compared the same way Merge combines them, so each change is reported at the value that
+// changed rather than as a change to everything containing it. Values that are recalculated whenever a document is
+// saved are ignored.
+func Diff(before, after []byte) ([]*Change, error) {
+	return diffDocuments(before, after, diffInputs)
+}
+
+// DiffCalculated returns the differences between the calculated values held in before and after, ignoring everything
+// else. This is the reverse of Diff and is used to find calculated values that are out of date.
+func DiffCalculated(before, after []byte) ([]*Change, error) {
+	return diffDocuments(before, after, diffCalculated)
+}
+
+// DiffIgnoringIDs returns the changes made to a JSON document between before and after, as Diff does, but for documents
+// whose IDs were replaced along the way. IDs are ignored and list entries are instead matched up by their labels, in
+// order, so an entry is only reported as removed if no entry with the same label remains.
+func DiffIgnoringIDs(before, after []byte) ([]*Change, error) {
+	return diffDocuments(before, after, diffIgnoringIDs)
+}
+
+func diffDocuments(before, after []byte, mode int) ([]*Change, error) {
+	var b, a *node
+	var err error
+	if b, err = parse(before); err != nil {
+		return nil, errs.NewWithCause("unable to parse the earlier copy", err)
+	}
+	if a, err = parse(after); err != nil {
+		return nil, errs.NewWithCause("unable to parse the later copy", err)
+	}
+	var changes []*Change
+	diff(&changes, nil, "", b, a, mode)
+	return changes, nil
+}
+
+func diff(changes *[]*Change, path []string, key string, b, a *node, mode int) {
+	switch {
+	case mode == diffCalculated && key == calcKey:
+		mode = diffAll
+	case derivedKeys[key] && mode != diffAll:
+		return
+	case key == "id" && mode == diffIgnoringIDs:
+		return
+	}
+	if b.equal(a) {
+		return
+	}
+	// A missing list is treated as an empty one, so that entries are still reported one by one when the last of them
+	// is removed or the first of them is added.
+	if mode == diffIgnoringIDs {
+		if (b == nil || b.kind == '[') && (a == nil || a.kind == '[') && b.labeled() && a.labeled() {
+			diffListsByLabel(changes, path, b, a, mode)
+			return
+		}
+	} else if (b == nil || b.kind == '[') && (a == nil || a.kind == '[') && b.keyed() && a.keyed() {
+		diffLists(changes, path, b, a, mode)
+		return
+	}
+	// Lists whose entries can't be told apart by their IDs are matched up by position when looking for calculated
+	// values, as the entries will only differ in those.
+	if mode == diffCalculated && b != nil && a != nil && b.kind == '[' && a.kind == '[' && len(b.items) == len(a.items) {
+		for i, item := range a.items {
+			diff(changes, append(path, item.label()), "", b.items[i], item, mode)
+		}
+		return
+	}
+	if b != nil && a != nil && b.kind == '{' && a.kind == '{' {
+		keys := slices.Clone(a.keys)
+		for _, k := range b.keys {
+			if _, exists := a.fields[k]; !exists {
+				keys = append(keys, k)
+			}
+		}
+		for _, k := range keys {
+			diff(changes, append(path, k), k, b.fields[k], a.fields[k], mode)
+		}
+		return
+	}
+	if mode == diffCalculated {
+		return
+	}
+	*changes = append(*changes, &Change{
+		Path:   slices.Clone(path),
+		Before: b.value(),
+		After:  a.value(),
+	})
+}
+
+func diffLists(changes *[]*Change, path []string, b, a *node, mode int) {
+	before := b.byID()
+	after := a.byID()
+	var items []*node
+	if a != nil {
+		items = a.items
+	}
+	for _, item := range items {
+		diff(changes, append(path, item.label()), "", before[item.id()], item, mode)
+	}
+	if b == nil {
+		return
+	}
+	for _, item := range b.items {
+		if _, exists := after[item.id()]; !exists {
+			diff(changes, append(path, item.label()), "", item, nil, mode)
+		}
+	}
+}
+
+func diffListsByLabel(changes *[]*Change, path []string, b, a *node, mode int) {
+	var before, after []*node
+	if b != nil {
+		before = b.items
+	}
+	if a != nil {
+		after = a.items
+	}
+	matched := make([]bool, len(before))
+	for _, item := range after {
+		var prior *node
+		label := item.label()
+		for i, one := range before {
+			if !matched[i] && one.label() == label {
+				matched[i] = true
+				prior = one
+				break
+			}
+		}
+		diff(changes, append(path, label), "", prior, item, mode)
+	}
+	for i, item := range before {
+		if !matched[i] {
+			diff(changes, append(path, item.label()), "", item, nil, mode)
+		}
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package merge performs a three-way merge of two edited copies of a JSON document against the copy they both started
+// from, as happens when two people edit the same character sheet or template and then combine their work.
+//
+// Objects are merged field by field. Lists whose entries all carry an "id", such as a sheet's traits, skills and
+// equipment along with their children, are merged entry by entry, matching entries by their ID rather than their
+// position, so that entries added, removed or edited on either side combine cleanly. Any other value that was changed
+// differently on both sides is reported as a Conflict, which must be resolved before the result is written out.
+package merge
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"slices"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+// Resolution determines which side's value is used for a Conflict.
+type Resolution byte
+
+// Possible Resolution values.
+const (
+	UseOurs Resolution = iota
+	UseTheirs
+)
+
+// derivedKeys holds the keys of values that are recalculated or updated whenever a document is saved. Differences in
+// them are not worth asking about, so our side's value is always used.
+var derivedKeys = map[string]bool{
+	"calc":          true,
+	"modified_date": true,
+	"version":       true,
+}
+
+// labelKeys holds the keys whose values are used, in order of preference, to describe a list entry.
+var labelKeys = []string{"name", "description", "title", "text", "attr_id"}
+
+// Conflict describes a value that was changed differently on both sides.
+type Conflict struct {
+	// Path describes where the value is located, e.g. ["traits", "Acute Vision", "levels"].
+	Path []string
+	// Base, Ours and Theirs hold the value from each copy of the document. A nil value means the value is absent from
+	// that copy.
+	Base       jsontext.Value
+	Ours       jsontext.Value
+	Theirs     jsontext.Value
+	Resolution Resolution
+}
+
+// Result holds the outcome of a merge.
+type Result struct {
+	root *node
+	// Conflicts holds the values that could not be merged automatically. Each defaults to UseOurs.
+	Conflicts []*Conflict
+	// Applied holds the number of changes made on their side that were carried over into the result.
+	Applied int
+}
+
+type node struct {
+	kind     jsontext.Kind
+	keys     []string
+	fields   map[string]*node
+	items    []*node
+	raw      jsontext.Value
+	conflict *Conflict
+	ours     *node
+	theirs   *node
+}
+
+// Merge the changes made in ours and theirs since base.
+func Merge(base, ours, theirs []byte) (*Result, error) {
+	var b, o, t *node
+	var err error
+	if b, err = parse(base); err != nil {
+		return nil, errs.NewWithCause("unable to parse the common ancestor", err)
+	}
+	if o, err = parse(ours); err != nil {
+		return nil, errs.NewWithCause("unable to parse our copy", err)
+	}
+	if t, err = parse(theirs); err != nil {
+		return nil, errs.NewWithCause("unable to parse their copy", err)
+	}
+	var r Result
+	r.root = r.merge(nil, "", b, o, t)
+	return &r, nil
+}
+
+// Bytes returns the merged document, using the current resolution of each conflict.
+func (r *Result) Bytes() ([]byte, error) {
+	var buffer bytes.Buffer
+	enc := jsontext.NewEncoder(&buffer, jsontext.WithIndent("\t"))
+	if n := r.root.resolved(); n != nil {
+		if err := n.encode(enc); err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+func (r *Result) merge(path []string, key string, b, o, t *node) *node {
+	switch {
+	case o.equal(t):
+		return o
+	case b.equal(o):
+		r.Applied++
+		return t
+	case b.equal(t):
+		return o
+	}
+	if o != nil && t != nil && o.kind == t.kind {
+		switch o.kind {
+		case '{':
+			if b != nil && b.kind != '{' {
+				b = nil
+			}
+			return r.mergeObjects(path, b, o, t)
+		case '[':
+			if b != nil && b.kind != '[' {
+				b = nil
+			}
+			if b.keyed() && o.keyed() && t.keyed() {
+				return r.mergeLists(path, b, o, t)
+			}
+		default:
+		}
+	}
+	if derivedKeys[key] {
+		return o
+	}
+	c := &Conflict{
+		Path:   slices.Clone(path),
+		Base:   b.value(),
+		Ours:   o.value(),
+		Theirs: t.value(),
+	}
+	r.Conflicts = append(r.Conflicts, c)
+	return &node{conflict: c, ours: o, theirs: t}
+}
+
+func (r *Result) mergeObjects(path []string, b, o, t *node) *node {
+	merged := &node{
+		kind:   '{',
+		fields: make(map[string]*node),
+	}
+	keys := slices.Clone(o.keys)
+	for _, k := range t.keys {
+		if _, exists := o.fields[k]; !exists {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		if n := r.merge(append(path, k), k, b.field(k), o.fields[k], t.fields[k]); n != nil {
+			merged.keys = append(merged.keys, k)
+			merged.fields[k] = n
+		}
+	}
+	return merged
+}
+
+func (r *Result) mergeLists(path []string, b, o, t *node) *node {
+	base := b.byID()
+	ours := o.byID()
+	theirs := t.byID()
+	order := make([]string, 0, len(o.items)+len(t.items))
+	for _, item := range o.items {
+		order = append(order, item.id())
+	}
+	// Entries only present on their side are placed after the entry that precedes them there.
+	for i, item := range t.items {
+		id := item.id()
+		if _, exists := ours[id]; exists {
+			continue
+		}
+		at := 0
+		for j := i - 1; j >= 0; j-- {
+			if k := slices.Index(order, t.items[j].id()); k != -1 {
+				at = k + 1
+				break
+			}
+		}
+		order = slices.Insert(order, at, id)
+	}
+	merged := &node{kind: '['}
+	for _, id := range order {
+		bi := base[id]
+		oi := ours[id]
+		ti := theirs[id]
+		label := id
+		for _, one := range []*node{oi, ti, bi} {
+			if one != nil {
+				label = one.label()
+				break
+			}
+		}
+		if n := r.merge(append(path, label), "", bi, oi, ti); n != nil {
+			merged.items = append(merged.items, n)
+		}
+	}
+	return merged
+}
+
+func parse(data []byte) (*node, error) {
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	n, err := decode(dec)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return n, nil
+}
+
+func decode(dec *jsontext.Decoder) (*node, error) {
+	switch dec.PeekKind() {
+	case '{':
+		if _, err := dec.ReadToken(); err != nil {
+			return nil, err
+		}
+		n := &node{
+			kind:   '{',
+			fields: make(map[string]*node),
+		}
+		for dec.PeekKind() != '}' {
+			tok, err := dec.ReadToken()
+			if err != nil {
+				return nil, err
+			}
+			key := tok.String()
+			var child *node
+			if child, err = decode(dec); err != nil {
+				return nil, err
+			}
+			if _, exists := n.fields[key]; !exists {
+				n.keys = append(n.keys, key)
+			}
+			n.fields[key] = child
+		}
+		if _, err := dec.ReadToken(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case '[':
+		if _, err := dec.ReadToken(); err != nil {
+			return nil, err
+		}
+		n := &node{kind: '['}
+		for dec.PeekKind() != ']' {
+			child, err := decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, child)
+		}
+		if _, err := dec.ReadToken(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	default:
+		v, err := dec.ReadValue()
+		if err != nil {
+			return nil, err
+		}
+		return &node{
+			kind: v.Kind(),
+			raw:  v.Clone(),
+		}, nil
+	}
+}
+
+func (n *node) encode(enc *jsontext.Encoder) error {
+	switch n.kind {
+	case '{':
+		if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+			return err
+		}
+		for _, k := range n.keys {
+			child := n.fields[k].resolved()
+			if child == nil {
+				continue
+			}
+			if err := enc.WriteToken(jsontext.String(k)); err != nil {
+				return err
+			}
+			if err := child.encode(enc); err != nil {
+				return err
+			}
+		}
+		return enc.WriteToken(jsontext.EndObject)
+	case '[':
+		if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+			return err
+		}
+		for _, item := range n.items {
+			if child := item.resolved(); child != nil {
+				if err := child.encode(enc); err != nil {
+					return err
+				}
+			}
+		}
+		return enc.WriteToken(jsontext.EndArray)
+	default:
+		return enc.WriteValue(n.raw)
+	}
+}
+
+// resolved returns the node to use in place of this one, which will be nil if the value should be omitted.
+func (n *node) resolved() *node {
+	if n == nil || n.conflict == nil {
+		return n
+	}
+	if n.conflict.Resolution == UseTheirs {
+		return n.theirs.resolved()
+	}
+	return n.ours.resolved()
+}
+
+// value returns the node as compact JSON, or nil if the node is nil.
+func (n *node) value() jsontext.Value {
+	if n == nil {
+		return nil
+	}
+	var buffer bytes.Buffer
+	if err := n.encode(jsontext.NewEncoder(&buffer)); err != nil {
+		return nil
+	}
+	return jsontext.Value(bytes.TrimSpace(buffer.Bytes()))
+}
+
+func (n *node) equal(other *node) bool {
+	if n == nil || other == nil {
+		return n == other
+	}
+	if n.kind != other.kind {
+		return false
+	}
+	switch n.kind {
+	case '{':
+		if len(n.fields) != len(other.fields) {
+			return false
+		}
+		for k, v := range n.fields {
+			if !v.equal(other.fields[k]) {
+				return false
+			}
+		}
+		return true
+	case '[':
+		return slices.EqualFunc(n.items, other.items, func(a, b *node) bool { return a.equal(b) })
+	default:
+		return bytes.Equal(n.raw, other.raw)
+	}
+}
+
+func (n *node) field(key string) *node {
+	if n == nil {
+		return nil
+	}
+	return n.fields[key]
+}
+
+func (n *node) str(key string) string {
+	var s string
+	if f := n.field(key); f != nil && f.kind == '"' {
+		if err := json.Unmarshal(f.raw, &s); err != nil {
+			return ""
+		}
+	}
+	return s
+}
+
+func (n *node) id() string {
+	return n.str("id")
+}
+
+func (n *node) label() string {
+	for _, k := range labelKeys {
+		if s := n.str(k); s != "" {
+			return s
+		}
+	}
+	return n.id()
+}
+
+// keyed returns true if the node is nil or is a list whose entries are all objects with unique IDs.
+func (n *node) keyed() bool {
+	if n == nil {
+		return true
+	}
+	if n.kind != '[' {
+		return false
+	}
+	seen := make(map[string]bool, len(n.items))
+	for _, item := range n.items {
+		if item.kind != '{' {
+			return false
+		}
+		id := item.id()
+		if id == "" || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// labeled returns true if the node is nil or is a list whose entries are all objects with a label.
+func (n *node) labeled() bool {
+	if n == nil {
+		return true
+	}
+	if n.kind != '[' {
+		return false
+	}
+	for _, item := range n.items {
+		if item.kind != '{' || item.label() == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *node) byID() map[string]*node {
+	m := make(map[string]*node)
+	if n != nil {
+		for _, item := range n.items {
+			m[item.id()] = item
+		}
+	}
+	return m
+}
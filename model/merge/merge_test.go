@@ -0,0 +1,261 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package merge_test
+
+import (
+	"encoding/json/v2"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/merge"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+const base = `{
+	"version": 5,
+	"id": "E1",
+	"profile": {"name": "Hero", "height": "6'"},
+	"traits": [
+		{"id": "T1", "name": "Acute Vision", "levels": 1},
+		{"id": "T2", "name": "Fit"},
+		{"id": "T3", "name": "Luck", "children": [{"id": "T4", "name": "Extra"}]}
+	],
+	"modified_date": "2025-01-01T00:00:00Z"
+}`
+
+type doc struct {
+	Profile struct {
+		Name   string `json:"name"`
+		Height string `json:"height"`
+		Weight string `json:"weight"`
+	} `json:"profile"`
+	Traits []*item `json:"traits"`
+}
+
+type item struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Levels   int     `json:"levels"`
+	Children []*item `json:"children"`
+}
+
+func TestMergeWithoutConflicts(t *testing.T) {
+	c := check.New(t)
+	ours := `{
+	"version": 5,
+	"id": "E1",
+	"profile": {"name": "Hero", "height": "6'2\""},
+	"traits": [
+		{"id": "T1", "name": "Acute Vision", "levels": 1},
+		{"id": "T3", "name": "Luck", "children": [{"id": "T4", "name": "Extra"}, {"id": "T6", "name": "Ours"}]},
+		{"id": "T2", "name": "Fit"}
+	],
+	"modified_date": "2025-02-01T00:00:00Z"
+}`
+	theirs := `{
+	"version": 5,
+	"id": "E1",
+	"profile": {"name": "Hero", "height": "6'", "weight": "180 lb"},
+	"traits": [
+		{"id": "T1", "name": "Acute Vision", "levels": 2},
+		{"id": "T5", "name": "Wealth"},
+		{"id": "T3", "name": "Luck", "children": [{"id": "T4", "name": "Extra"}]}
+	],
+	"modified_date": "2025-03-01T00:00:00Z"
+}`
+	result, err := merge.Merge([]byte(base), []byte(ours), []byte(theirs))
+	c.NoError(err)
+	c.Equal(0, len(result.Conflicts))
+	var d doc
+	decode(c, result, &d)
+	c.Equal("6'2\"", d.Profile.Height)
+	c.Equal("180 lb", d.Profile.Weight)
+	c.Equal(3, len(d.Traits))
+	c.Equal("T1", d.Traits[0].ID)
+	c.Equal(2, d.Traits[0].Levels)
+	c.Equal("T5", d.Traits[1].ID, "their new trait follows the one that precedes it on their side")
+	c.Equal("T3", d.Traits[2].ID, "their removal of T2 is carried over")
+	c.Equal(2, len(d.Traits[2].Children))
+	c.Equal("T6", d.Traits[2].Children[1].ID)
+}
+
+func TestMergeConflicts(t *testing.T) {
+	c := check.New(t)
+	ours := `{
+	"version": 5,
+	"id": "E1",
+	"profile": {"name": "Sir Hero", "height": "6'"},
+	"traits": [
+		{"id": "T1", "name": "Acute Vision", "levels": 3},
+		{"id": "T2", "name": "Very Fit"},
+		{"id": "T3", "name": "Luck", "children": [{"id": "T4", "name": "Extra"}]}
+	],
+	"modified_date": "2025-02-01T00:00:00Z"
+}`
+	theirs := `{
+	"version": 5,
+	"id": "E1",
+	"profile": {"name": "Lady Hero", "height": "6'"},
+	"traits": [
+		{"id": "T1", "name": "Acute Vision", "levels": 2},
+		{"id": "T3", "name": "Luck", "children": [{"id": "T4", "name": "Extra"}]}
+	],
+	"modified_date": "2025-03-01T00:00:00Z"
+}`
+	result, err := merge.Merge([]byte(base), []byte(ours), []byte(theirs))
+	c.NoError(err)
+	c.Equal(3, len(result.Conflicts))
+	c.Equal([]string{"profile", "name"}, result.Conflicts[0].Path)
+	c.Equal([]string{"traits", "Acute Vision", "levels"}, result.Conflicts[1].Path)
+	c.Equal(`3`, string(result.Conflicts[1].Ours))
+	c.Equal(`2`, string(result.Conflicts[1].Theirs))
+	c.Equal([]string{"traits", "Very Fit"}, result.Conflicts[2].Path)
+	c.Equal(0, len(result.Conflicts[2].Theirs), "they removed the trait that we changed")
+
+	var d doc
+	decode(c, result, &d)
+	c.Equal("Sir Hero", d.Profile.Name)
+	c.Equal(3, d.Traits[0].Levels)
+	c.Equal(3, len(d.Traits))
+
+	for _, one := range result.Conflicts {
+		one.Resolution = merge.UseTheirs
+	}
+	decode(c, result, &d)
+	c.Equal("Lady Hero", d.Profile.Name)
+	c.Equal(2, d.Traits[0].Levels)
+	c.Equal(2, len(d.Traits))
+	c.Equal("T3", d.Traits[1].ID)
+}
+
+func TestMergeInvalid(t *testing.T) {
+	c := check.New(t)
+	_, err := merge.Merge([]byte(base), []byte(`{"broken": `), []byte(base))
+	c.HasError(err)
+}
+
+func TestDiff(t *testing.T) {
+	c := check.New(t)
+	after := `{
+	"version": 5,
+	"id": "E1",
+	"profile": {"name": "Hero", "height": "6'2\""},
+	"traits": [
+		{"id": "T1", "name": "Acute Vision", "levels": 2},
+		{"id": "T3", "name": "Luck", "children": [{"id": "T4", "name": "Extra"}, {"id": "T6", "name": "More"}]},
+		{"id": "T5", "name": "Wealth"}
+	],
+	"modified_date": "2025-02-01T00:00:00Z"
+}`
+	changes, err := merge.Diff([]byte(base), []byte(after))
+	c.NoError(err)
+	c.Equal(5, len(changes))
+	c.Equal([]string{"profile", "height"}, changes[0].Path)
+	c.Equal(`"6'"`, string(changes[0].Before))
+	c.Equal(`"6'2\""`, string(changes[0].After))
+	c.Equal([]string{"traits", "Acute Vision", "levels"}, changes[1].Path)
+	c.Equal(`1`, string(changes[1].Before))
+	c.Equal(`2`, string(changes[1].After))
+	c.Equal([]string{"traits", "Luck", "children", "More"}, changes[2].Path)
+	c.Equal(0, len(changes[2].Before), "an added entry")
+	c.Equal([]string{"traits", "Wealth"}, changes[3].Path)
+	c.Equal([]string{"traits", "Fit"}, changes[4].Path)
+	c.Equal(0, len(changes[4].After), "a removed entry")
+
+	changes, err = merge.Diff([]byte(base),
+		[]byte(`{"version": 5, "id": "E1", "profile": {"name": "Hero", "height": "6'"}}`))
+	c.NoError(err)
+	c.Equal(3, len(changes), "removing every entry still reports each of them")
+	c.Equal([]string{"traits", "Luck"}, changes[2].Path)
+
+	changes, err = merge.Diff([]byte(base), []byte(base))
+	c.NoError(err)
+	c.Equal(0, len(changes))
+}
+
+func decode(c check.Checker, result *merge.Result, d *doc) {
+	data, err := result.Bytes()
+	c.NoError(err)
+	*d = doc{}
+	c.NoError(json.Unmarshal(data, d))
+}
+
+func TestDiffCalculated(t *testing.T) {
+	c := check.New(t)
+	stored := `{
+	"version": 4,
+	"id": "E1",
+	"traits": [
+		{"id": "T1", "name": "Acute Vision", "levels": 2, "calc": {"points": 4}},
+		{"id": "T2", "name": "Luck", "calc": {"points": 15}},
+		{"id": "T3", "name": "Fit"}
+	],
+	"attributes": [{"attr_id": "st", "calc": {"value": 10, "points": 0}}],
+	"modified_date": "2024-01-01T00:00:00Z"
+}`
+	calculated := `{
+	"version": 5,
+	"id": "E1",
+	"traits": [
+		{"id": "T1", "name": "Acute Vision", "levels": 2, "calc": {"points": 4}},
+		{"id": "T2", "name": "Luck", "calc": {"points": 10}},
+		{"id": "T3", "name": "Fit", "calc": {"points": 5}}
+	],
+	"attributes": [{"attr_id": "st", "calc": {"value": 11, "points": 10}}],
+	"modified_date": "2025-01-01T00:00:00Z"
+}`
+	changes, err := merge.DiffCalculated([]byte(stored), []byte(calculated))
+	c.NoError(err)
+	c.Equal(4, len(changes))
+	c.Equal([]string{"traits", "Luck", "calc", "points"}, changes[0].Path)
+	c.Equal(`15`, string(changes[0].Before))
+	c.Equal(`10`, string(changes[0].After))
+	c.Equal([]string{"traits", "Fit", "calc"}, changes[1].Path)
+	c.Equal(0, len(changes[1].Before), "a calculated value that wasn't stored")
+	c.Equal([]string{"attributes", "st", "calc", "value"}, changes[2].Path, "unkeyed lists are matched by position")
+	c.Equal([]string{"attributes", "st", "calc", "points"}, changes[3].Path)
+
+	changes, err = merge.DiffCalculated([]byte(calculated), []byte(calculated))
+	c.NoError(err)
+	c.Equal(0, len(changes))
+}
+
+func TestDiffIgnoringIDs(t *testing.T) {
+	c := check.New(t)
+	before := `{
+	"version": 2,
+	"id": "1e0b5c4a-0b52-4a5f-9b8e-8d1f0c7e2a11",
+	"traits": [
+		{"id": "a6f3", "name": "Luck", "categories": ["Advantage"], "base_points": 15},
+		{"id": "b7c2", "name": "Fit", "base_points": 5},
+		{"id": "c8d1", "name": "Fit", "base_points": 5}
+	]
+}`
+	after := `{
+	"version": 5,
+	"id": "Ej3kLm9pQr",
+	"traits": [
+		{"id": "tA1b2C3d4E", "name": "Luck", "tags": ["Advantage"], "base_points": 15},
+		{"id": "tF5g6H7i8J", "name": "Fit", "base_points": 5}
+	]
+}`
+	changes, err := merge.DiffIgnoringIDs([]byte(before), []byte(after))
+	c.NoError(err)
+	c.Equal(3, len(changes))
+	c.Equal([]string{"traits", "Luck", "tags"}, changes[0].Path)
+	c.Equal(0, len(changes[0].Before))
+	c.Equal([]string{"traits", "Luck", "categories"}, changes[1].Path)
+	c.Equal(0, len(changes[1].After))
+	c.Equal([]string{"traits", "Fit"}, changes[2].Path, "only one of the two entries with the same label was kept")
+	c.Equal(0, len(changes[2].After))
+
+	changes, err = merge.Diff([]byte(before), []byte(after))
+	c.NoError(err)
+	c.Equal(6, len(changes), "without ignoring IDs, every entry looks replaced")
+}
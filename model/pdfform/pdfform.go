@@ -0,0 +1,464 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package pdfform adds fillable form fields, links, and bookmarks to an existing PDF.
+package pdfform
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+const (
+	maxFontSize   = 10
+	textInset     = 2
+	multilineFlag = 1 << 12
+)
+
+var (
+	objectRegex    = regexp.MustCompile(`(?:^|[\r\n])(\d+)\s+(\d+)\s+obj\b`)
+	refRegex       = regexp.MustCompile(`(\d+)\s+(\d+)\s+R`)
+	sizeRegex      = regexp.MustCompile(`/Size\s+(\d+)`)
+	rootRegex      = regexp.MustCompile(`/Root\s+(\d+\s+\d+\s+R)`)
+	infoRegex      = regexp.MustCompile(`/Info\s+(\d+\s+\d+\s+R)`)
+	idRegex        = regexp.MustCompile(`/ID\s*(\[[^\]]*\])`)
+	startXRefRegex = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`)
+	pagesRegex     = regexp.MustCompile(`/Pages\s+(\d+\s+\d+\s+R)`)
+	kidsRegex      = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+	mediaBoxRegex  = regexp.MustCompile(`/MediaBox\s*\[\s*([-\d.]+)\s+([-\d.]+)\s+([-\d.]+)\s+([-\d.]+)\s*\]`)
+	annotsRegex    = regexp.MustCompile(`/Annots\s*\[`)
+)
+
+// Field holds a text field to be added to a page.
+type Field struct {
+	Name  string
+	Value string
+	// Rect is in points, with the origin at the top-left corner of the page.
+	Rect      geom.Rect
+	Page      int
+	Multiline bool
+}
+
+// Link holds a region of a page that opens a URI when clicked.
+type Link struct {
+	URI string
+	// Rect is in points, with the origin at the top-left corner of the page.
+	Rect geom.Rect
+	Page int
+}
+
+// Bookmark holds an entry for the document outline.
+type Bookmark struct {
+	Title string
+	Page  int
+	// Y is the vertical position on the page to show, in points from the top of the page.
+	Y float32
+}
+
+// Additions holds the items to be added to a PDF.
+type Additions struct {
+	Fields    []*Field
+	Links     []*Link
+	Bookmarks []*Bookmark
+}
+
+type ref struct {
+	num int
+	gen int
+}
+
+func (r ref) String() string {
+	return fmt.Sprintf("%d %d R", r.num, r.gen)
+}
+
+type page struct {
+	ref      ref
+	mediaBox [4]float32
+}
+
+type pdfDoc struct {
+	data    []byte
+	offsets map[int]int
+	gens    map[int]int
+	pages   []*page
+	annots  map[int][]ref
+	nextNum int
+	updates map[ref]string
+}
+
+// Add appends an incremental update to the PDF data that adds the fields as fillable text fields, the links as link
+// annotations, and the bookmarks as the document outline, returning the new PDF data. The PDF must use a classic
+// cross-reference table rather than a cross-reference stream.
+func Add(data []byte, additions *Additions) ([]byte, error) {
+	if len(additions.Fields) == 0 && len(additions.Links) == 0 && len(additions.Bookmarks) == 0 {
+		return data, nil
+	}
+	trailerStart := bytes.LastIndex(data, []byte("trailer"))
+	m := startXRefRegex.FindSubmatch(data)
+	if trailerStart == -1 || m == nil {
+		return nil, errs.New("unsupported PDF structure")
+	}
+	prevXRef := string(m[1])
+	trailer := string(data[trailerStart:])
+	doc := &pdfDoc{
+		data:    data,
+		offsets: make(map[int]int),
+		gens:    make(map[int]int),
+		annots:  make(map[int][]ref),
+		updates: make(map[ref]string),
+	}
+	sm := sizeRegex.FindStringSubmatch(trailer)
+	rm := rootRegex.FindStringSubmatch(trailer)
+	if sm == nil || rm == nil {
+		return nil, errs.New("unsupported PDF trailer")
+	}
+	var err error
+	if doc.nextNum, err = strconv.Atoi(sm[1]); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	for _, one := range objectRegex.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[one[2]:one[3]])) //nolint:errcheck // The regex guarantees digits
+		gen, _ := strconv.Atoi(string(data[one[4]:one[5]])) //nolint:errcheck // The regex guarantees digits
+		doc.offsets[num] = one[1]
+		doc.gens[num] = gen
+	}
+	root := parseRef(rm[1])
+	catalog, err := doc.body(root)
+	if err != nil {
+		return nil, err
+	}
+	pm := pagesRegex.FindStringSubmatch(catalog)
+	if pm == nil {
+		return nil, errs.New("unable to locate PDF pages")
+	}
+	if doc.pages, err = doc.collectPages(parseRef(pm[1]), [4]float32{0, 0, 612, 792}, nil, 0); err != nil {
+		return nil, err
+	}
+	if len(additions.Fields) != 0 {
+		if catalog, err = doc.addFields(catalog, additions.Fields); err != nil {
+			return nil, err
+		}
+	}
+	for _, link := range additions.Links {
+		if err = doc.addLink(link); err != nil {
+			return nil, err
+		}
+	}
+	if len(additions.Bookmarks) != 0 {
+		if catalog, err = doc.addBookmarks(catalog, additions.Bookmarks); err != nil {
+			return nil, err
+		}
+	}
+	if err = doc.updatePageAnnotations(); err != nil {
+		return nil, err
+	}
+	doc.updates[root] = catalog
+	return doc.write(prevXRef, rm[1], infoRegex.FindStringSubmatch(trailer), idRegex.FindStringSubmatch(trailer)), nil
+}
+
+func (d *pdfDoc) pageFor(index int, what string) (*page, error) {
+	if index < 0 || index >= len(d.pages) {
+		return nil, errs.Newf("no page %d for %s", index, what)
+	}
+	return d.pages[index], nil
+}
+
+func (d *pdfDoc) addFields(catalog string, fields []*Field) (string, error) {
+	if strings.Contains(catalog, "/AcroForm") {
+		return "", errs.New("PDF already has a form")
+	}
+	fontRef := d.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	fieldRefs := make([]string, 0, len(fields))
+	names := make(map[string]int)
+	for _, f := range fields {
+		pg, err := d.pageFor(f.Page, "field "+strconv.Quote(f.Name))
+		if err != nil {
+			return "", err
+		}
+		name := f.Name
+		if count := names[name]; count != 0 {
+			name = fmt.Sprintf("%s_%d", name, count+1)
+		}
+		names[f.Name]++
+		r := d.addField(f, name, pg, fontRef)
+		d.annots[f.Page] = append(d.annots[f.Page], r)
+		fieldRefs = append(fieldRefs, r.String())
+	}
+	acroForm := d.add(fmt.Sprintf("<< /Fields [%s] /DA (/Helv 0 Tf 0 g) /DR << /Font << /Helv %s >> >> >>",
+		strings.Join(fieldRefs, " "), fontRef))
+	return insertIntoDict(catalog, "/AcroForm "+acroForm.String())
+}
+
+func (d *pdfDoc) addLink(link *Link) error {
+	pg, err := d.pageFor(link.Page, "link "+strconv.Quote(link.URI))
+	if err != nil {
+		return err
+	}
+	x1, y1, x2, y2 := pg.pdfRect(link.Rect)
+	d.annots[link.Page] = append(d.annots[link.Page], d.add(fmt.Sprintf("<< /Type /Annot /Subtype /Link /F 4 "+
+		"/Rect [%s %s %s %s] /Border [0 0 0] /A << /S /URI /URI %s >> >>", num(x1), num(y1), num(x2), num(y2),
+		pdfString(link.URI))))
+	return nil
+}
+
+func (d *pdfDoc) addBookmarks(catalog string, bookmarks []*Bookmark) (string, error) {
+	if strings.Contains(catalog, "/Outlines") {
+		return "", errs.New("PDF already has an outline")
+	}
+	outlines := ref{num: d.nextNum}
+	d.nextNum++
+	first := d.nextNum
+	last := first + len(bookmarks) - 1
+	d.nextNum += len(bookmarks)
+	for i, bookmark := range bookmarks {
+		pg, err := d.pageFor(bookmark.Page, "bookmark "+strconv.Quote(bookmark.Title))
+		if err != nil {
+			return "", err
+		}
+		var siblings string
+		if i != 0 {
+			siblings = fmt.Sprintf(" /Prev %d 0 R", first+i-1)
+		}
+		if first+i != last {
+			siblings += fmt.Sprintf(" /Next %d 0 R", first+i+1)
+		}
+		d.updates[ref{num: first + i}] = fmt.Sprintf("<< /Title %s /Parent %s%s /Dest [%s /XYZ %s %s null] >>",
+			pdfString(bookmark.Title), outlines, siblings, pg.ref, num(pg.mediaBox[0]), num(pg.mediaBox[3]-bookmark.Y))
+	}
+	d.updates[outlines] = fmt.Sprintf("<< /Type /Outlines /First %d 0 R /Last %d 0 R /Count %d >>", first, last,
+		len(bookmarks))
+	var err error
+	if catalog, err = insertIntoDict(catalog, "/Outlines "+outlines.String()); err != nil {
+		return "", err
+	}
+	if !strings.Contains(catalog, "/PageMode") {
+		catalog, err = insertIntoDict(catalog, "/PageMode /UseOutlines")
+	}
+	return catalog, err
+}
+
+func (d *pdfDoc) updatePageAnnotations() error {
+	for i, refs := range d.annots {
+		pg := d.pages[i]
+		body, err := d.body(pg.ref)
+		if err != nil {
+			return err
+		}
+		list := make([]string, len(refs))
+		for j, r := range refs {
+			list[j] = r.String()
+		}
+		if loc := annotsRegex.FindStringIndex(body); loc != nil {
+			body = body[:loc[1]] + strings.Join(list, " ") + " " + body[loc[1]:]
+		} else if strings.Contains(body, "/Annots") {
+			return errs.New("unsupported PDF page annotations")
+		} else if body, err = insertIntoDict(body, "/Annots ["+strings.Join(list, " ")+"]"); err != nil {
+			return err
+		}
+		d.updates[pg.ref] = body
+	}
+	return nil
+}
+
+// pdfRect converts a rectangle with a top-left origin into the page's coordinate system.
+func (p *page) pdfRect(r geom.Rect) (x1, y1, x2, y2 float32) {
+	x1 = p.mediaBox[0] + r.X
+	y2 = p.mediaBox[3] - r.Y
+	return x1, y2 - r.Height, x1 + r.Width, y2
+}
+
+func parseRef(s string) ref {
+	var r ref
+	if m := refRegex.FindStringSubmatch(s); m != nil {
+		r.num, _ = strconv.Atoi(m[1]) //nolint:errcheck // The regex guarantees digits
+		r.gen, _ = strconv.Atoi(m[2]) //nolint:errcheck // The regex guarantees digits
+	}
+	return r
+}
+
+func (d *pdfDoc) body(r ref) (string, error) {
+	offset, ok := d.offsets[r.num]
+	if !ok {
+		return "", errs.Newf("unable to locate PDF object %d", r.num)
+	}
+	end := bytes.Index(d.data[offset:], []byte("endobj"))
+	if end == -1 {
+		return "", errs.Newf("PDF object %d is not terminated", r.num)
+	}
+	body := strings.TrimSpace(string(d.data[offset : offset+end]))
+	if strings.Contains(body, "stream") {
+		return "", errs.Newf("PDF object %d is not a dictionary", r.num)
+	}
+	return body, nil
+}
+
+func (d *pdfDoc) collectPages(r ref, mediaBox [4]float32, pages []*page, depth int) ([]*page, error) {
+	if depth > 32 {
+		return nil, errs.New("PDF page tree is too deep")
+	}
+	body, err := d.body(r)
+	if err != nil {
+		return nil, err
+	}
+	if m := mediaBoxRegex.FindStringSubmatch(body); m != nil {
+		for i := range mediaBox {
+			var v float64
+			if v, err = strconv.ParseFloat(m[i+1], 32); err != nil {
+				return nil, errs.Wrap(err)
+			}
+			mediaBox[i] = float32(v)
+		}
+	}
+	if m := kidsRegex.FindStringSubmatch(body); m != nil {
+		for _, kid := range refRegex.FindAllString(m[1], -1) {
+			if pages, err = d.collectPages(parseRef(kid), mediaBox, pages, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return pages, nil
+	}
+	return append(pages, &page{ref: r, mediaBox: mediaBox}), nil
+}
+
+func (d *pdfDoc) add(body string) ref {
+	r := ref{num: d.nextNum}
+	d.nextNum++
+	d.updates[r] = body
+	return r
+}
+
+func (d *pdfDoc) addField(f *Field, name string, pg *page, fontRef ref) ref {
+	x1, y1, x2, y2 := pg.pdfRect(f.Rect)
+	fontSize := min(maxFontSize, f.Rect.Height*0.7)
+	if f.Multiline {
+		fontSize = maxFontSize
+	}
+	da := fmt.Sprintf("/Helv %s Tf 0 g", num(fontSize))
+	appearance := d.addAppearance(f, da, fontSize, fontRef)
+	var flags string
+	if f.Multiline {
+		flags = fmt.Sprintf(" /Ff %d", multilineFlag)
+	}
+	return d.add(fmt.Sprintf("<< /Type /Annot /Subtype /Widget /FT /Tx /F 4 /T %s /V %s /Rect [%s %s %s %s] /P %s /DA %s "+
+		"/MK << /BG [1 1 1] >> /AP << /N %s >>%s >>", pdfString(name), pdfString(f.Value), num(x1), num(y1), num(x2),
+		num(y2), pg.ref, pdfString(da), appearance, flags))
+}
+
+func (d *pdfDoc) addAppearance(f *Field, da string, fontSize float32, fontRef ref) ref {
+	w := f.Rect.Width
+	h := f.Rect.Height
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, "/Tx BMC\nq 1 1 1 rg 0 0 %s %s re f Q\n", num(w), num(h))
+	if f.Value != "" {
+		fmt.Fprintf(&buffer, "q BT %s ", da)
+		if f.Multiline {
+			fmt.Fprintf(&buffer, "%s TL %d %s Td", num(fontSize*1.15), textInset, num(h-textInset-fontSize))
+			for i, line := range strings.Split(f.Value, "\n") {
+				if i != 0 {
+					buffer.WriteString(" T*")
+				}
+				fmt.Fprintf(&buffer, " %s Tj", pdfString(line))
+			}
+		} else {
+			fmt.Fprintf(&buffer, "%d %s Td %s Tj", textInset, num((h-fontSize)/2+fontSize*0.22), pdfString(f.Value))
+		}
+		buffer.WriteString(" ET Q\n")
+	}
+	buffer.WriteString("EMC")
+	content := buffer.String()
+	return d.add(fmt.Sprintf("<< /Type /XObject /Subtype /Form /BBox [0 0 %s %s] /Resources << /Font << /Helv %s >> >> "+
+		"/Length %d >>\nstream\n%s\nendstream", num(w), num(h), fontRef, len(content), content))
+}
+
+func (d *pdfDoc) write(prevXRef, root string, info, id []string) []byte {
+	var buffer bytes.Buffer
+	buffer.Write(d.data)
+	if !bytes.HasSuffix(d.data, []byte("\n")) {
+		buffer.WriteByte('\n')
+	}
+	refs := make([]ref, 0, len(d.updates))
+	for r := range d.updates {
+		refs = append(refs, r)
+	}
+	slices.SortFunc(refs, func(a, b ref) int { return a.num - b.num })
+	offsets := make([]int, len(refs))
+	for i, r := range refs {
+		offsets[i] = buffer.Len()
+		fmt.Fprintf(&buffer, "%d %d obj\n%s\nendobj\n", r.num, r.gen, d.updates[r])
+	}
+	xref := buffer.Len()
+	buffer.WriteString("xref\n")
+	for i := 0; i < len(refs); {
+		j := i + 1
+		for j < len(refs) && refs[j].num == refs[j-1].num+1 {
+			j++
+		}
+		fmt.Fprintf(&buffer, "%d %d\n", refs[i].num, j-i)
+		for k := i; k < j; k++ {
+			fmt.Fprintf(&buffer, "%010d %05d n \n", offsets[k], refs[k].gen)
+		}
+		i = j
+	}
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root %s", d.nextNum, root)
+	if info != nil {
+		fmt.Fprintf(&buffer, " /Info %s", info[1])
+	}
+	if id != nil {
+		fmt.Fprintf(&buffer, " /ID %s", id[1])
+	}
+	fmt.Fprintf(&buffer, " /Prev %s >>\nstartxref\n%d\n%%%%EOF\n", prevXRef, xref)
+	return buffer.Bytes()
+}
+
+func insertIntoDict(body, entry string) (string, error) {
+	i := strings.LastIndex(body, ">>")
+	if !strings.HasPrefix(body, "<<") || i == -1 {
+		return "", errs.New("unexpected PDF object structure")
+	}
+	return body[:i] + " " + entry + " " + body[i:], nil
+}
+
+func num(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', 2, 32)
+}
+
+// pdfString returns the text as a PDF literal string. Characters outside of Latin-1 are replaced, since the fields use
+// a font with the standard encoding.
+func pdfString(s string) string {
+	var buffer strings.Builder
+	buffer.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			buffer.WriteByte('\\')
+			buffer.WriteRune(r)
+		case r == '\n':
+			buffer.WriteString(`\n`)
+		case r == '\r':
+			buffer.WriteString(`\r`)
+		case r < ' ':
+			buffer.WriteByte(' ')
+		case r < 0x7f:
+			buffer.WriteRune(r)
+		case r <= 0xff:
+			fmt.Fprintf(&buffer, `\%03o`, r)
+		default:
+			buffer.WriteByte('?')
+		}
+	}
+	buffer.WriteByte(')')
+	return buffer.String()
+}
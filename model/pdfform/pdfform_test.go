@@ -0,0 +1,127 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package pdfform_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/pdfform"
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+func minimalPDF() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buffer.Len()
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buffer.Len()
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buffer.Bytes()
+}
+
+func TestAddTextFields(t *testing.T) {
+	c := check.New(t)
+	original := minimalPDF()
+	data, err := pdfform.Add(original, &pdfform.Additions{
+		Fields: []*pdfform.Field{
+			{Name: "hp", Value: "12", Rect: geom.NewRect(100, 50, 40, 14)},
+			{Name: "hp", Value: "(3)", Rect: geom.NewRect(100, 70, 40, 14)},
+			{Name: "notes", Value: "line 1\nline 2", Rect: geom.NewRect(36, 500, 540, 200), Multiline: true},
+		},
+	})
+	c.NoError(err)
+	c.True(bytes.HasPrefix(data, original))
+	update := string(data[len(original):])
+	c.Contains(update, "/AcroForm")
+	c.Contains(update, "/Annots [")
+	c.Contains(update, "/T (hp)")
+	c.Contains(update, "/T (hp_2)")
+	c.Contains(update, `/V (\(3\))`)
+	c.Contains(update, "/Rect [100.00 728.00 140.00 742.00]")
+	c.Contains(update, "/Ff 4096")
+	c.Contains(update, "/Prev ")
+
+	// Every entry in the new cross-reference section must point at the start of its object.
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindStringSubmatch(update)
+	c.NotNil(m)
+	xref, err := strconv.Atoi(m[1])
+	c.NoError(err)
+	c.True(bytes.HasPrefix(data[xref:], []byte("xref\n")))
+	sectionRegex := regexp.MustCompile(`(?m)^(\d+) (\d+)\n((?:\d{10} \d{5} n \n)+)`)
+	section := sectionRegex.FindAllStringSubmatch(string(data[xref:]), -1)
+	c.NotEqual(0, len(section))
+	for _, sub := range section {
+		start, _ := strconv.Atoi(sub[1]) //nolint:errcheck // The regex guarantees digits
+		for i, entry := range regexp.MustCompile(`(\d{10}) \d{5} n \n`).FindAllStringSubmatch(sub[3], -1) {
+			offset, _ := strconv.Atoi(entry[1]) //nolint:errcheck // The regex guarantees digits
+			c.True(bytes.HasPrefix(data[offset:], fmt.Appendf(nil, "%d 0 obj\n", start+i)))
+		}
+	}
+
+	// Adding fields to an already updated document is not supported.
+	_, err = pdfform.Add(data, &pdfform.Additions{
+		Fields: []*pdfform.Field{{Name: "x", Rect: geom.NewRect(0, 0, 10, 10)}},
+	})
+	c.HasError(err)
+
+	_, err = pdfform.Add(original, &pdfform.Additions{Fields: []*pdfform.Field{{Name: "x", Page: 1}}})
+	c.HasError(err)
+}
+
+func TestAddLinksAndBookmarks(t *testing.T) {
+	c := check.New(t)
+	original := minimalPDF()
+	data, err := pdfform.Add(original, &pdfform.Additions{
+		Links: []*pdfform.Link{
+			{URI: "file:///books/Basic%20Set.pdf#page=161", Rect: geom.NewRect(500, 100, 30, 12)},
+		},
+		Bookmarks: []*pdfform.Bookmark{
+			{Title: "Skills", Y: 200},
+			{Title: "Spells", Y: 400},
+		},
+	})
+	c.NoError(err)
+	update := string(data[len(original):])
+	c.NotContains(update, "/AcroForm")
+	c.Contains(update, "/Subtype /Link")
+	c.Contains(update, "/URI (file:///books/Basic%20Set.pdf#page=161)")
+	c.Contains(update, "/Rect [500.00 680.00 530.00 692.00]")
+	c.Contains(update, "/Annots [")
+	c.Contains(update, "/Type /Outlines")
+	c.Contains(update, "/Count 2")
+	c.Contains(update, "/PageMode /UseOutlines")
+	c.Contains(update, "/Title (Skills)")
+	c.Contains(update, "/Dest [3 0 R /XYZ 0.00 592.00 null]")
+	c.Contains(update, "/Dest [3 0 R /XYZ 0.00 392.00 null]")
+
+	unchanged, err := pdfform.Add(original, &pdfform.Additions{})
+	c.NoError(err)
+	c.Equal(original, unchanged)
+
+	_, err = pdfform.Add(original, &pdfform.Additions{Bookmarks: []*pdfform.Bookmark{{Title: "x", Page: 2}}})
+	c.HasError(err)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package recovery keeps copies of documents with unsaved changes in a directory of their own, so that the changes
+// can be offered back to the user should the application exit without saving them.
+//
+// Each document is stored as two files: the data, in the same format the document would normally be saved in, and a
+// small manifest describing where the document came from. The directory is emptied when the application exits
+// normally, so anything found in it at launch was left behind by a crash.
+package recovery
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+const manifestExt = ".recovery"
+
+// Entry describes one recovered document.
+type Entry struct {
+	// ID uniquely identifies the entry within the store.
+	ID string `json:"id"`
+	// OriginalPath is the path the document was loaded from, or the path it would be given if it has never been saved.
+	OriginalPath string `json:"original_path"`
+	// Title is the title the document was displayed with.
+	Title string `json:"title,omitzero"`
+	// Saved is when the copy was made.
+	Saved jio.Time `json:"saved"`
+	// Untitled is true if the document has never been saved.
+	Untitled bool `json:"untitled,omitzero"`
+}
+
+// Store holds recovered documents in a directory.
+type Store struct {
+	Dir string
+}
+
+// NewID returns a new identifier for an entry.
+func NewID() string {
+	var buffer [8]byte
+	_, _ = rand.Read(buffer[:]) //nolint:errcheck // crypto/rand.Read never returns an error
+	return hex.EncodeToString(buffer[:])
+}
+
+// DataPath returns the path of the file holding the entry's data.
+func (s *Store) DataPath(entry *Entry) string {
+	return filepath.Join(s.Dir, entry.ID+filepath.Ext(entry.OriginalPath))
+}
+
+func (s *Store) manifestPath(id string) string {
+	return filepath.Join(s.Dir, id+manifestExt)
+}
+
+// Save a copy of a document. The saver is called to write the document's data to the path it is given. The manifest
+// is only written once the data has been written successfully.
+func (s *Store) Save(entry *Entry, saver func(filePath string) error) error {
+	if err := os.MkdirAll(s.Dir, 0o750); err != nil {
+		return errs.NewWithCause(s.Dir, err)
+	}
+	if err := saver(s.DataPath(entry)); err != nil {
+		return err
+	}
+	return jio.SaveToFile(s.manifestPath(entry.ID), entry)
+}
+
+// Remove the entry with the given ID, if present.
+func (s *Store) Remove(id string) {
+	p := s.manifestPath(id)
+	var entry Entry
+	if err := jio.Load(nil, p, &entry); err == nil {
+		removeIgnoringMissing(s.DataPath(&entry))
+	}
+	removeIgnoringMissing(p)
+}
+
+// List returns the entries in the store, most recently saved first. Entries whose data is missing are omitted.
+func (s *Store) List() ([]*Entry, error) {
+	dirEntries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errs.NewWithCause(s.Dir, err)
+	}
+	var list []*Entry
+	for _, one := range dirEntries {
+		name := one.Name()
+		if one.IsDir() || !strings.HasSuffix(name, manifestExt) {
+			continue
+		}
+		var entry Entry
+		if err = jio.Load(nil, filepath.Join(s.Dir, name), &entry); err != nil {
+			errs.Log(err, "path", filepath.Join(s.Dir, name))
+			continue
+		}
+		if entry.ID+manifestExt != name || !xos.FileExists(s.DataPath(&entry)) {
+			continue
+		}
+		list = append(list, &entry)
+	}
+	slices.SortFunc(list, func(a, b *Entry) int { return b.Saved.Compare(a.Saved) })
+	return list, nil
+}
+
+// Clear removes everything from the store.
+func (s *Store) Clear() error {
+	if err := os.RemoveAll(s.Dir); err != nil {
+		return errs.NewWithCause(s.Dir, err)
+	}
+	return nil
+}
+
+func removeIgnoringMissing(p string) {
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		errs.Log(err, "path", p)
+	}
+}
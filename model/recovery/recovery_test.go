@@ -0,0 +1,91 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package recovery_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/recovery"
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+func TestStore(t *testing.T) {
+	c := check.New(t)
+	store := &recovery.Store{Dir: filepath.Join(t.TempDir(), "recovery")}
+
+	list, err := store.List()
+	c.NoError(err)
+	c.Equal(0, len(list))
+
+	writer := func(content string) func(string) error {
+		return func(filePath string) error { return os.WriteFile(filePath, []byte(content), 0o640) }
+	}
+	older := &recovery.Entry{
+		ID:           recovery.NewID(),
+		OriginalPath: "/sheets/hero.gcs",
+		Title:        "hero",
+		Saved:        jio.Time(time.Now().Add(-time.Minute)),
+	}
+	c.NoError(store.Save(older, writer("hero")))
+	newer := &recovery.Entry{
+		ID:           recovery.NewID(),
+		OriginalPath: "Unnamed Character.gcs",
+		Saved:        jio.Now(),
+		Untitled:     true,
+	}
+	c.NoError(store.Save(newer, writer("unnamed")))
+	c.Equal(".gcs", filepath.Ext(store.DataPath(older)))
+
+	list, err = store.List()
+	c.NoError(err)
+	c.Equal(2, len(list))
+	c.Equal(newer.ID, list[0].ID)
+	c.True(list[0].Untitled)
+	c.Equal(older.ID, list[1].ID)
+	c.Equal("/sheets/hero.gcs", list[1].OriginalPath)
+	data, err := os.ReadFile(store.DataPath(list[1]))
+	c.NoError(err)
+	c.Equal("hero", string(data))
+
+	// Saving again replaces the previous copy.
+	c.NoError(store.Save(older, writer("hero v2")))
+	data, err = os.ReadFile(store.DataPath(older))
+	c.NoError(err)
+	c.Equal("hero v2", string(data))
+
+	// A failed save leaves no entry behind.
+	failed := &recovery.Entry{ID: recovery.NewID(), OriginalPath: "/sheets/broken.gcs", Saved: jio.Now()}
+	c.HasError(store.Save(failed, func(string) error { return errs.New("failed") }))
+	list, err = store.List()
+	c.NoError(err)
+	c.Equal(2, len(list))
+
+	store.Remove(newer.ID)
+	c.False(fileExists(store.DataPath(newer)))
+	list, err = store.List()
+	c.NoError(err)
+	c.Equal(1, len(list))
+	c.Equal(older.ID, list[0].ID)
+
+	c.NoError(store.Clear())
+	list, err = store.List()
+	c.NoError(err)
+	c.Equal(0, len(list))
+}
+
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package restapi
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+// Event types.
+const (
+	SheetOpenedEvent      = "sheet_opened"
+	SheetClosedEvent      = "sheet_closed"
+	AttributeChangedEvent = "attribute_changed"
+	ItemAddedEvent        = "item_added"
+	ItemRemovedEvent      = "item_removed"
+	RollEvent             = "roll"
+)
+
+// Item kinds.
+const (
+	TraitItem          = "trait"
+	SkillItem          = "skill"
+	SpellItem          = "spell"
+	EquipmentItem      = "equipment"
+	OtherEquipmentItem = "other_equipment"
+	NoteItem           = "note"
+)
+
+// ItemInfo holds the identifying information for an item on a sheet.
+type ItemInfo struct {
+	ID   tid.TID `json:"id"`
+	Kind string  `json:"kind"`
+	Name string  `json:"name"`
+}
+
+// Event describes a change to an open sheet or a roll. Attribute events carry both the previous and the new state of
+// the attribute; item events carry the item that was added or removed. Roll events carry the roll and aren't tied to a
+// sheet.
+type Event struct {
+	Type      string         `json:"type"`
+	When      time.Time      `json:"when"`
+	Sheet     SheetInfo      `json:"sheet,omitzero"`
+	Attribute *AttributeInfo `json:"attribute,omitzero"`
+	Previous  *AttributeInfo `json:"previous,omitzero"`
+	Item      *ItemInfo      `json:"item,omitzero"`
+	Roll      *RollResult    `json:"roll,omitzero"`
+}
+
+// NewRollEvent returns the event announcing the roll.
+func NewRollEvent(r *dice.Result) *Event {
+	return &Event{Type: RollEvent, When: r.When, Roll: NewRollResult(r)}
+}
+
+// Snapshot holds the parts of an entity's state that are reported through events.
+type Snapshot struct {
+	info       SheetInfo
+	attributes []*AttributeInfo
+	items      []*ItemInfo
+}
+
+// TakeSnapshot captures the current state of the entity.
+func TakeSnapshot(entity *gurps.Entity) *Snapshot {
+	s := &Snapshot{
+		info:       *newSheetInfo(entity),
+		attributes: attributesOf(entity, false),
+	}
+	s.items = appendItems(s.items, TraitItem, entity.Traits)
+	s.items = appendItems(s.items, SkillItem, entity.Skills)
+	s.items = appendItems(s.items, SpellItem, entity.Spells)
+	s.items = appendItems(s.items, EquipmentItem, entity.CarriedEquipment)
+	s.items = appendItems(s.items, OtherEquipmentItem, entity.OtherEquipment)
+	s.items = appendItems(s.items, NoteItem, entity.Notes)
+	slices.SortFunc(s.items, compareItems)
+	return s
+}
+
+func appendItems[T gurps.NodeTypes](list []*ItemInfo, kind string, in []T) []*ItemInfo {
+	gurps.Traverse(func(node T) bool {
+		list = append(list, &ItemInfo{
+			ID:   gurps.AsNode(node).ID(),
+			Kind: kind,
+			Name: node.String(),
+		})
+		return false
+	}, false, false, in...)
+	return list
+}
+
+func compareItems(a, b *ItemInfo) int {
+	if result := cmp.Compare(a.Kind, b.Kind); result != 0 {
+		return result
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// OpenedEvent returns the event announcing that the sheet captured by the snapshot was opened.
+func (s *Snapshot) OpenedEvent() *Event {
+	return &Event{Type: SheetOpenedEvent, When: time.Now(), Sheet: s.info}
+}
+
+// ClosedEvent returns the event announcing that the sheet captured by the snapshot was closed.
+func (s *Snapshot) ClosedEvent() *Event {
+	return &Event{Type: SheetClosedEvent, When: time.Now(), Sheet: s.info}
+}
+
+// Changes returns the events that describe how the state has changed from this snapshot to the next one.
+func (s *Snapshot) Changes(next *Snapshot) []*Event {
+	now := time.Now()
+	var events []*Event
+	previous := make(map[string]*AttributeInfo, len(s.attributes))
+	for _, attr := range s.attributes {
+		previous[attr.ID] = attr
+	}
+	for _, attr := range next.attributes {
+		if prev, ok := previous[attr.ID]; !ok || prev.Current != attr.Current || prev.Maximum != attr.Maximum ||
+			prev.Threshold != attr.Threshold {
+			events = append(events, &Event{
+				Type:      AttributeChangedEvent,
+				When:      now,
+				Sheet:     next.info,
+				Attribute: attr,
+				Previous:  prev,
+			})
+		}
+	}
+	i := 0
+	j := 0
+	for i < len(s.items) || j < len(next.items) {
+		var result int
+		switch {
+		case i == len(s.items):
+			result = 1
+		case j == len(next.items):
+			result = -1
+		default:
+			result = compareItems(s.items[i], next.items[j])
+		}
+		switch {
+		case result < 0:
+			events = append(events, &Event{Type: ItemRemovedEvent, When: now, Sheet: next.info, Item: s.items[i]})
+			i++
+		case result > 0:
+			events = append(events, &Event{Type: ItemAddedEvent, When: now, Sheet: next.info, Item: next.items[j]})
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return events
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package restapi_test
+
+import (
+	"encoding/json/v2"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/restapi"
+	"github.com/richardwilkes/toolbox/v2/check"
+	"golang.org/x/net/websocket"
+)
+
+func TestSnapshotChanges(t *testing.T) {
+	c := check.New(t)
+	entity := gurps.NewEntity()
+	before := restapi.TakeSnapshot(entity)
+	c.Equal(0, len(before.Changes(restapi.TakeSnapshot(entity))))
+
+	hp := entity.Attributes.Find("hp")
+	hp.Damage = fxp.Two
+	trait := gurps.NewTrait(entity, nil, false)
+	trait.Name = "Luck"
+	entity.Traits = append(entity.Traits, trait)
+	entity.Recalculate()
+	after := restapi.TakeSnapshot(entity)
+	events := before.Changes(after)
+	c.Equal(2, len(events))
+	c.Equal(restapi.AttributeChangedEvent, events[0].Type)
+	c.Equal("hp", events[0].Attribute.ID)
+	c.Equal(hp.Maximum(), events[0].Previous.Current)
+	c.Equal(hp.Maximum()-fxp.Two, events[0].Attribute.Current)
+	c.Equal(restapi.ItemAddedEvent, events[1].Type)
+	c.Equal(trait.TID, events[1].Item.ID)
+	c.Equal(restapi.TraitItem, events[1].Item.Kind)
+	c.Equal("Luck", events[1].Item.Name)
+
+	entity.Traits = nil
+	events = after.Changes(restapi.TakeSnapshot(entity))
+	c.Equal(1, len(events))
+	c.Equal(restapi.ItemRemovedEvent, events[0].Type)
+	c.Equal(trait.TID, events[0].Item.ID)
+	c.Equal(entity.ID, events[0].Sheet.ID)
+}
+
+func TestEventFeed(t *testing.T) {
+	c := check.New(t)
+	entity := gurps.NewEntity()
+	api := &restapi.Server{
+		Token:   "secret",
+		Invoke:  func(f func()) { f() },
+		Sheets:  func() []*gurps.Entity { return []*gurps.Entity{entity} },
+		Changed: func(*gurps.Entity) {},
+		Rolled:  func(*dice.Result) {},
+	}
+	server := httptest.NewServer(api.Handler())
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/events"
+
+	_, err := websocket.Dial(url, "", "http://localhost/")
+	c.HasError(err)
+
+	conn, err := websocket.Dial(url+"?token=secret", "", "http://localhost/")
+	c.NoError(err)
+	defer func() { _ = conn.Close() }() //nolint:errcheck // Don't care
+
+	// The subscription is registered asynchronously after the handshake, so keep publishing until the event arrives.
+	event := restapi.TakeSnapshot(entity).OpenedEvent()
+	var data []byte
+	for range 50 {
+		api.Publish(event)
+		c.NoError(conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond)))
+		if err = websocket.Message.Receive(conn, &data); err == nil {
+			break
+		}
+	}
+	c.NoError(err)
+	var received restapi.Event
+	c.NoError(json.Unmarshal(data, &received))
+	c.Equal(restapi.SheetOpenedEvent, received.Type)
+	c.Equal(entity.ID, received.Sheet.ID)
+}
+
+func TestRollEvent(t *testing.T) {
+	c := check.New(t)
+	result := &dice.Result{When: time.Now(), Who: "Bob", What: "Broadsword", Spec: "3d", Kind: dice.SuccessRoll,
+		Dice: []int{2, 3, 4}, Total: 9, Level: 12, Margin: 3, Success: true}
+	data, err := json.Marshal(restapi.NewRollEvent(result))
+	c.NoError(err)
+	var received map[string]any
+	c.NoError(json.Unmarshal(data, &received))
+	c.Equal(restapi.RollEvent, received["type"])
+	_, hasSheet := received["sheet"]
+	c.False(hasSheet, "rolls aren't tied to a sheet")
+	var event restapi.Event
+	c.NoError(json.Unmarshal(data, &event))
+	c.Equal("Bob", event.Roll.Who)
+	c.Equal(9, event.Roll.Total)
+	c.Equal(result.String(), event.Roll.Text)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package restapi
+
+import (
+	"encoding/json/v2"
+	"net/http"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"golang.org/x/net/websocket"
+)
+
+const subscriberBufferSize = 256
+
+type subscriber struct {
+	sheet  tid.TID
+	events chan []byte
+}
+
+// Publish sends the events to the clients connected to the event feed. Clients that aren't keeping up are
+// disconnected rather than allowed to block the caller.
+func (s *Server) Publish(events ...*Event) {
+	s.feedLock.Lock()
+	defer s.feedLock.Unlock()
+	if len(s.subscribers) == 0 {
+		return
+	}
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			errs.Log(err)
+			continue
+		}
+		for sub := range s.subscribers {
+			if sub.sheet != "" && sub.sheet != event.Sheet.ID {
+				continue
+			}
+			select {
+			case sub.events <- data:
+			default:
+				delete(s.subscribers, sub)
+				close(sub.events)
+			}
+		}
+	}
+}
+
+func (s *Server) eventFeed() http.Handler {
+	return websocket.Server{
+		// The token check has already been done and dashboards are frequently loaded from local files, which report a
+		// "null" origin, so accept any origin.
+		Handshake: func(_ *websocket.Config, _ *http.Request) error { return nil },
+		Handler:   s.streamEvents,
+	}
+}
+
+func (s *Server) streamEvents(conn *websocket.Conn) {
+	sub := &subscriber{
+		sheet:  tid.TID(conn.Request().URL.Query().Get("sheet")),
+		events: make(chan []byte, subscriberBufferSize),
+	}
+	s.subscribe(sub)
+	defer s.unsubscribe(sub)
+	// Clients aren't expected to send anything, but reading is how we find out that they've gone away.
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		var discard []byte
+		for websocket.Message.Receive(conn, &discard) == nil { //nolint:revive // Intentionally empty
+		}
+	}()
+	for {
+		select {
+		case data, ok := <-sub.events:
+			if !ok {
+				return
+			}
+			if err := websocket.Message.Send(conn, string(data)); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+func (s *Server) subscribe(sub *subscriber) {
+	s.feedLock.Lock()
+	defer s.feedLock.Unlock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[*subscriber]struct{})
+	}
+	s.subscribers[sub] = struct{}{}
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.feedLock.Lock()
+	defer s.feedLock.Unlock()
+	if _, exists := s.subscribers[sub]; exists {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (s *Server) disconnectSubscribers() {
+	s.feedLock.Lock()
+	defer s.feedLock.Unlock()
+	for sub := range s.subscribers {
+		close(sub.events)
+	}
+	s.subscribers = nil
+}
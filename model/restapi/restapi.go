@@ -0,0 +1,520 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package restapi provides a local HTTP server that exposes the open character sheets to companion applications, such
+// as stream overlays and VTT bridges. Every request must carry the configured token, either as a bearer token or, for
+// clients such as browsers that can't set headers on WebSocket connections, as the "token" query parameter.
+//
+// Changes to the open sheets, along with every roll made, are pushed to clients connected to the WebSocket at
+// /api/events as JSON-encoded Event objects, one per message. Adding a "sheet" query parameter limits the events to
+// those for the sheet with that ID. The complete sheet, in the same form as a .gcs file, is available from
+// /api/sheets/{id}/data.
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json/v2"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+// DefaultPort is the port the server listens on when none has been configured.
+const DefaultPort = 13323
+
+const maxRequestSize = 64 * 1024
+
+// SheetInfo holds the identifying information for an open sheet.
+type SheetInfo struct {
+	ID     tid.TID `json:"id"`
+	Name   string  `json:"name"`
+	Player string  `json:"player,omitzero"`
+}
+
+// AttributeInfo holds the state of a single attribute or pool.
+type AttributeInfo struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	FullName  string  `json:"full_name,omitzero"`
+	Pool      bool    `json:"pool,omitzero"`
+	Maximum   fxp.Int `json:"maximum"`
+	Current   fxp.Int `json:"current"`
+	Threshold string  `json:"threshold,omitzero"`
+}
+
+// SkillInfo holds the state of a single skill.
+type SkillInfo struct {
+	ID             tid.TID `json:"id"`
+	Name           string  `json:"name"`
+	Specialization string  `json:"specialization,omitzero"`
+	Level          fxp.Int `json:"level"`
+	RelativeLevel  string  `json:"relative_level,omitzero"`
+}
+
+// Sheet holds the state of an open sheet.
+type Sheet struct {
+	SheetInfo
+	Attributes []*AttributeInfo `json:"attributes"`
+	Skills     []*SkillInfo     `json:"skills"`
+}
+
+// AttributeUpdate holds the changes to make to an attribute. Only pools may have their current value changed. The
+// maximum may not be changed while the sheet is locked.
+type AttributeUpdate struct {
+	Current *fxp.Int `json:"current,omitzero"`
+	Maximum *fxp.Int `json:"maximum,omitzero"`
+}
+
+// RollRequest describes a roll to make on behalf of a sheet. If Dice is set, that dice specification is rolled.
+// Otherwise, a success roll is made against the named skill, the attribute, or the explicit level, in that order of
+// preference. Modifier is added to the effective level of success rolls.
+type RollRequest struct {
+	What      string `json:"what,omitzero"`
+	Dice      string `json:"dice,omitzero"`
+	Skill     string `json:"skill,omitzero"`
+	Attribute string `json:"attribute,omitzero"`
+	Level     *int   `json:"level,omitzero"`
+	Modifier  int    `json:"modifier,omitzero"`
+}
+
+// RollResult holds the outcome of a roll.
+type RollResult struct {
+	When     time.Time `json:"when"`
+	Who      string    `json:"who,omitzero"`
+	What     string    `json:"what,omitzero"`
+	Spec     string    `json:"spec"`
+	Text     string    `json:"text"`
+	Outcome  string    `json:"outcome"`
+	Dice     []int     `json:"dice,omitzero"`
+	Total    int       `json:"total"`
+	Level    int       `json:"level,omitzero"`
+	Margin   int       `json:"margin,omitzero"`
+	Success  bool      `json:"success,omitzero"`
+	Critical bool      `json:"critical,omitzero"`
+}
+
+// Server exposes the open sheets over HTTP. The callbacks are required. Sheets and Changed are only called from within
+// Invoke.
+type Server struct {
+	// Token must be presented by clients as a bearer token. The server refuses all requests if it is empty.
+	Token string
+	// Invoke calls f on the thread that owns the sheets and waits for it to finish.
+	Invoke func(f func())
+	// Sheets returns the entities of the open sheets.
+	Sheets func() []*gurps.Entity
+	// Changed is called after a request modifies an entity.
+	Changed func(entity *gurps.Entity)
+	// Rolled is called with each roll made through the API.
+	Rolled      func(r *dice.Result)
+	server      *http.Server
+	feedLock    sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// NewToken returns a new random token suitable for use by the server.
+func NewToken() string {
+	var buffer [16]byte
+	_, _ = rand.Read(buffer[:]) //nolint:errcheck // crypto/rand.Read never returns an error
+	return hex.EncodeToString(buffer[:])
+}
+
+// Start listening on the loopback interface at the given port.
+func (s *Server) Start(port int) error {
+	if s.server != nil {
+		return errs.New("server already started")
+	}
+	listener, err := net.Listen("tcp4", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil {
+		return errs.NewWithCause("unable to listen on port "+strconv.Itoa(port), err)
+	}
+	s.server = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func(server *http.Server) {
+		if err2 := server.Serve(listener); err2 != nil && !errors.Is(err2, http.ErrServerClosed) {
+			errs.Log(err2)
+		}
+	}(s.server)
+	return nil
+}
+
+// Stop the server, if it was started.
+func (s *Server) Stop(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	server := s.server
+	s.server = nil
+	// Shutdown doesn't wait on or close hijacked connections, so the event feed must be torn down separately.
+	s.disconnectSubscribers()
+	return server.Shutdown(ctx)
+}
+
+// Handler returns the http.Handler that services the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/sheets", s.listSheets)
+	mux.HandleFunc("GET /api/sheets/{id}", s.getSheet)
+	mux.HandleFunc("GET /api/sheets/{id}/data", s.getSheetData)
+	mux.HandleFunc("GET /api/sheets/{id}/attributes", s.getAttributes(false))
+	mux.HandleFunc("GET /api/sheets/{id}/pools", s.getAttributes(true))
+	mux.HandleFunc("PATCH /api/sheets/{id}/attributes/{attr}", s.updateAttribute)
+	mux.HandleFunc("GET /api/sheets/{id}/skills", s.getSkills)
+	mux.HandleFunc("POST /api/sheets/{id}/rolls", s.roll)
+	mux.Handle("GET /api/events", s.eventFeed())
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Browser-based clients, such as the Foundry VTT companion module, are served from other origins. Since every
+		// request must carry the token and no cookies are involved, allowing any origin exposes nothing further.
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.Token == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get("token")
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.Token)) == 1
+}
+
+func (s *Server) listSheets(w http.ResponseWriter, _ *http.Request) {
+	var list []*SheetInfo
+	s.Invoke(func() {
+		for _, entity := range s.Sheets() {
+			list = append(list, newSheetInfo(entity))
+		}
+	})
+	if list == nil {
+		list = []*SheetInfo{}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) getSheet(w http.ResponseWriter, r *http.Request) {
+	var sheet *Sheet
+	s.Invoke(func() {
+		if entity := s.lookup(r); entity != nil {
+			sheet = &Sheet{
+				SheetInfo:  *newSheetInfo(entity),
+				Attributes: attributesOf(entity, false),
+				Skills:     skillsOf(entity),
+			}
+		}
+	})
+	if sheet == nil {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, sheet)
+}
+
+func (s *Server) getSheetData(w http.ResponseWriter, r *http.Request) {
+	var buffer bytes.Buffer
+	found := false
+	var err error
+	s.Invoke(func() {
+		if entity := s.lookup(r); entity != nil {
+			found = true
+			err = jio.Save(&buffer, entity)
+		}
+	})
+	switch {
+	case !found:
+		http.NotFound(w, r)
+	case err != nil:
+		errs.Log(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(buffer.Bytes()) //nolint:errcheck // Nothing useful can be done if the client has gone away
+	}
+}
+
+func (s *Server) getAttributes(poolsOnly bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var list []*AttributeInfo
+		found := false
+		s.Invoke(func() {
+			if entity := s.lookup(r); entity != nil {
+				found = true
+				list = attributesOf(entity, poolsOnly)
+			}
+		})
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+	}
+}
+
+func (s *Server) getSkills(w http.ResponseWriter, r *http.Request) {
+	var list []*SkillInfo
+	found := false
+	s.Invoke(func() {
+		if entity := s.lookup(r); entity != nil {
+			found = true
+			list = skillsOf(entity)
+		}
+	})
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) updateAttribute(w http.ResponseWriter, r *http.Request) {
+	var update AttributeUpdate
+	if err := json.UnmarshalRead(r.Body, &update); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	var info *AttributeInfo
+	var problem string
+	status := http.StatusBadRequest
+	s.Invoke(func() {
+		entity := s.lookup(r)
+		if entity == nil {
+			return
+		}
+		attr := entity.Attributes.Find(r.PathValue("attr"))
+		if attr == nil {
+			return
+		}
+		def := attr.AttributeDef()
+		if def == nil || def.IsSeparator() {
+			return
+		}
+		if update.Current != nil && !def.Pool() {
+			problem = "only pools may have their current value changed"
+			return
+		}
+		if update.Maximum != nil && entity.Locked {
+			problem = "the sheet is locked, so only the current value of pools may be changed"
+			status = http.StatusForbidden
+			return
+		}
+		if update.Maximum != nil {
+			attr.SetMaximum(*update.Maximum)
+		}
+		if update.Current != nil {
+			attr.Damage = (attr.Maximum() - *update.Current).Max(0)
+		}
+		if update.Maximum != nil || update.Current != nil {
+			entity.Recalculate()
+			s.Changed(entity)
+		}
+		info = newAttributeInfo(attr, def)
+	})
+	switch {
+	case problem != "":
+		http.Error(w, problem, status)
+	case info == nil:
+		http.NotFound(w, r)
+	default:
+		writeJSON(w, http.StatusOK, info)
+	}
+}
+
+func (s *Server) roll(w http.ResponseWriter, r *http.Request) {
+	var req RollRequest
+	if err := json.UnmarshalRead(r.Body, &req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	var result *dice.Result
+	var problem string
+	found := false
+	s.Invoke(func() {
+		entity := s.lookup(r)
+		if entity == nil {
+			return
+		}
+		found = true
+		who := entity.Profile.Name
+		switch {
+		case req.Dice != "":
+			result = dice.RollSpec(nil, who, req.What, req.Dice,
+				gurps.SheetSettingsFor(entity).UseModifyingDicePlusAdds)
+		case req.Skill != "":
+			skill := findSkill(entity, req.Skill)
+			if skill == nil {
+				problem = "no such skill"
+				return
+			}
+			result = dice.RollSuccess(nil, who, whatOrDefault(req.What, skill.String()),
+				fxp.AsInteger[int](skill.LevelData.Level.Floor())+req.Modifier)
+		case req.Attribute != "":
+			attr := entity.Attributes.Find(req.Attribute)
+			if attr == nil {
+				problem = "no such attribute"
+				return
+			}
+			name := attr.AttrID
+			if def := attr.AttributeDef(); def != nil {
+				name = def.Name
+			}
+			result = dice.RollSuccess(nil, who, whatOrDefault(req.What, name),
+				fxp.AsInteger[int](attr.Current().Floor())+req.Modifier)
+		case req.Level != nil:
+			result = dice.RollSuccess(nil, who, req.What, *req.Level+req.Modifier)
+		default:
+			problem = "one of dice, skill, attribute, or level is required"
+			return
+		}
+		s.Rolled(result)
+	})
+	switch {
+	case !found:
+		http.NotFound(w, r)
+	case problem != "":
+		http.Error(w, problem, http.StatusBadRequest)
+	default:
+		writeJSON(w, http.StatusOK, NewRollResult(result))
+	}
+}
+
+func (s *Server) lookup(r *http.Request) *gurps.Entity {
+	id := tid.TID(r.PathValue("id"))
+	for _, entity := range s.Sheets() {
+		if entity.ID == id {
+			return entity
+		}
+	}
+	return nil
+}
+
+func newSheetInfo(entity *gurps.Entity) *SheetInfo {
+	return &SheetInfo{
+		ID:     entity.ID,
+		Name:   entity.Profile.Name,
+		Player: entity.Profile.PlayerName,
+	}
+}
+
+func attributesOf(entity *gurps.Entity, poolsOnly bool) []*AttributeInfo {
+	list := make([]*AttributeInfo, 0)
+	for _, attr := range entity.Attributes.List() {
+		def := attr.AttributeDef()
+		if def == nil || def.IsSeparator() || (poolsOnly && !def.Pool()) {
+			continue
+		}
+		list = append(list, newAttributeInfo(attr, def))
+	}
+	return list
+}
+
+func newAttributeInfo(attr *gurps.Attribute, def *gurps.AttributeDef) *AttributeInfo {
+	info := &AttributeInfo{
+		ID:       attr.AttrID,
+		Name:     def.Name,
+		FullName: def.FullName,
+		Pool:     def.Pool(),
+		Maximum:  attr.Maximum(),
+		Current:  attr.Current(),
+	}
+	if t := attr.CurrentThreshold(); t != nil {
+		info.Threshold = t.State
+	}
+	return info
+}
+
+func skillsOf(entity *gurps.Entity) []*SkillInfo {
+	list := make([]*SkillInfo, 0)
+	gurps.Traverse(func(skill *gurps.Skill) bool {
+		list = append(list, &SkillInfo{
+			ID:             skill.TID,
+			Name:           skill.Name,
+			Specialization: skill.Specialization,
+			Level:          skill.LevelData.Level,
+			RelativeLevel:  skill.RelativeLevel(),
+		})
+		return false
+	}, true, true, entity.Skills...)
+	return list
+}
+
+func findSkill(entity *gurps.Entity, name string) *gurps.Skill {
+	var found *gurps.Skill
+	gurps.Traverse(func(skill *gurps.Skill) bool {
+		if strings.EqualFold(skill.String(), name) || strings.EqualFold(skill.Name, name) ||
+			string(skill.TID) == name {
+			found = skill
+			return true
+		}
+		return false
+	}, true, true, entity.Skills...)
+	return found
+}
+
+func whatOrDefault(what, def string) string {
+	if what != "" {
+		return what
+	}
+	return def
+}
+
+// NewRollResult creates a RollResult from a dice result.
+func NewRollResult(r *dice.Result) *RollResult {
+	return &RollResult{
+		When:     r.When,
+		Who:      r.Who,
+		What:     r.What,
+		Spec:     r.Spec,
+		Text:     r.String(),
+		Outcome:  r.Outcome(),
+		Dice:     r.Dice,
+		Total:    r.Total,
+		Level:    r.Level,
+		Margin:   r.Margin,
+		Success:  r.Success,
+		Critical: r.Critical,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := jio.Save(w, data); err != nil {
+		errs.Log(err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package restapi_test
+
+import (
+	"encoding/json/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/restapi"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestServer(t *testing.T) {
+	c := check.New(t)
+	entity := gurps.NewEntity()
+	entity.Profile.Name = "Bob"
+	var changed *gurps.Entity
+	var rolled *dice.Result
+	server := httptest.NewServer((&restapi.Server{
+		Token:   "secret",
+		Invoke:  func(f func()) { f() },
+		Sheets:  func() []*gurps.Entity { return []*gurps.Entity{entity} },
+		Changed: func(e *gurps.Entity) { changed = e },
+		Rolled:  func(r *dice.Result) { rolled = r },
+	}).Handler())
+	defer server.Close()
+
+	request := func(method, path, token, body string) *http.Response {
+		req, err := http.NewRequestWithContext(t.Context(), method, server.URL+path, strings.NewReader(body))
+		c.NoError(err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rsp, err := http.DefaultClient.Do(req)
+		c.NoError(err)
+		t.Cleanup(func() { _ = rsp.Body.Close() }) //nolint:errcheck // Don't care
+		return rsp
+	}
+
+	rsp := request(http.MethodOptions, "/api/sheets", "", "")
+	c.Equal(http.StatusNoContent, rsp.StatusCode, "preflight requests don't carry the token")
+	c.Equal("*", rsp.Header.Get("Access-Control-Allow-Origin"))
+	c.True(strings.Contains(rsp.Header.Get("Access-Control-Allow-Headers"), "Authorization"))
+
+	c.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/sheets", "", "").StatusCode)
+	c.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/sheets", "wrong", "").StatusCode)
+
+	rsp = request(http.MethodGet, "/api/sheets", "secret", "")
+	c.Equal(http.StatusOK, rsp.StatusCode)
+	c.Equal("*", rsp.Header.Get("Access-Control-Allow-Origin"))
+	var sheets []*restapi.SheetInfo
+	c.NoError(json.UnmarshalRead(rsp.Body, &sheets))
+	c.Equal(1, len(sheets))
+	c.Equal(entity.ID, sheets[0].ID)
+	c.Equal("Bob", sheets[0].Name)
+
+	c.Equal(http.StatusNotFound, request(http.MethodGet, "/api/sheets/missing", "secret", "").StatusCode)
+
+	rsp = request(http.MethodGet, "/api/sheets/"+string(entity.ID)+"/data", "secret", "")
+	c.Equal(http.StatusOK, rsp.StatusCode)
+	loaded := gurps.NewEntity()
+	c.NoError(json.UnmarshalRead(rsp.Body, loaded))
+	c.Equal(entity.ID, loaded.ID)
+	c.Equal("Bob", loaded.Profile.Name)
+	c.Equal(http.StatusNotFound, request(http.MethodGet, "/api/sheets/missing/data", "secret", "").StatusCode)
+
+	rsp = request(http.MethodPatch, "/api/sheets/"+string(entity.ID)+"/attributes/hp", "secret", `{"current":3}`)
+	c.Equal(http.StatusOK, rsp.StatusCode)
+	var attr restapi.AttributeInfo
+	c.NoError(json.UnmarshalRead(rsp.Body, &attr))
+	c.True(attr.Pool)
+	c.Equal(fxp.Three, attr.Current)
+	c.Equal(fxp.Three, entity.Attributes.Current("hp"))
+	c.Equal(entity, changed)
+
+	rsp = request(http.MethodPatch, "/api/sheets/"+string(entity.ID)+"/attributes/st", "secret", `{"current":3}`)
+	c.Equal(http.StatusBadRequest, rsp.StatusCode)
+
+	entity.Locked = true
+	st := entity.Attributes.Current("st")
+	rsp = request(http.MethodPatch, "/api/sheets/"+string(entity.ID)+"/attributes/st", "secret", `{"maximum":15}`)
+	c.Equal(http.StatusForbidden, rsp.StatusCode)
+	c.Equal(st, entity.Attributes.Current("st"))
+	rsp = request(http.MethodPatch, "/api/sheets/"+string(entity.ID)+"/attributes/hp", "secret", `{"current":4}`)
+	c.Equal(http.StatusOK, rsp.StatusCode, "pools may still be used while the sheet is locked")
+	c.Equal(fxp.Four, entity.Attributes.Current("hp"))
+	entity.Locked = false
+
+	rsp = request(http.MethodPost, "/api/sheets/"+string(entity.ID)+"/rolls", "secret",
+		`{"attribute":"dx","modifier":-2}`)
+	c.Equal(http.StatusOK, rsp.StatusCode)
+	var roll restapi.RollResult
+	c.NoError(json.UnmarshalRead(rsp.Body, &roll))
+	c.NotNil(rolled)
+	c.Equal(fxp.AsInteger[int](entity.Attributes.Current("dx"))-2, roll.Level)
+	c.Equal("Bob", roll.Who)
+
+	rsp = request(http.MethodPost, "/api/sheets/"+string(entity.ID)+"/rolls", "secret", `{}`)
+	c.Equal(http.StatusBadRequest, rsp.StatusCode)
+}
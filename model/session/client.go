@@ -0,0 +1,83 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package session
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"golang.org/x/net/websocket"
+)
+
+const dialTimeout = 10 * time.Second
+
+// Client runs a player's side of a session. The callbacks are required and are only called from within Invoke.
+type Client struct {
+	// Invoke calls f on the thread that owns the UI. It must not wait for f to finish and must preserve the order of
+	// the calls made to it.
+	Invoke func(f func())
+	// Received is called with each message the GM sends.
+	Received func(msg *Message)
+	// Disconnected is called once the connection to the GM has closed. err is nil if it closed normally.
+	Disconnected func(err error)
+	link         *link
+}
+
+// Join the session hosted at address, which may omit the port if the host is using the default one.
+func (c *Client) Join(address, player, password string) error {
+	if c.link != nil {
+		return errs.New("already joined a session")
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, strconv.Itoa(DefaultPort))
+	}
+	query := url.Values{}
+	query.Set("player", player)
+	query.Set("password", password)
+	target := url.URL{
+		Scheme:   "ws",
+		Host:     address,
+		Path:     Path,
+		RawQuery: query.Encode(),
+	}
+	config, err := websocket.NewConfig(target.String(), "http://"+address+"/")
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	config.Dialer = &net.Dialer{Timeout: dialTimeout}
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return errs.NewWithCause("unable to join the session at "+address, err)
+	}
+	l := newLink(conn)
+	c.link = l
+	go func() {
+		err2 := l.read(func(msg *Message) { c.Invoke(func() { c.Received(msg) }) })
+		c.Invoke(func() { c.Disconnected(err2) })
+	}()
+	return nil
+}
+
+// Send queues the message for delivery to the GM.
+func (c *Client) Send(msg *Message) {
+	if c.link != nil {
+		c.link.send(msg)
+	}
+}
+
+// Leave the session.
+func (c *Client) Leave() {
+	if c.link != nil {
+		c.link.close()
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package session
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"golang.org/x/net/websocket"
+)
+
+// Player is a participant connected to a hosted session.
+type Player struct {
+	Name    string
+	Address string
+	link    *link
+}
+
+// Send queues the message for delivery to the player.
+func (p *Player) Send(msg *Message) {
+	p.link.send(msg)
+}
+
+// Host runs the GM's side of a session. The callbacks are required and are only called from within Invoke.
+type Host struct {
+	// Password must be presented by players when they join. If empty, anyone who can reach the host may join.
+	Password string
+	// Invoke calls f on the thread that owns the UI. It must not wait for f to finish and must preserve the order of
+	// the calls made to it.
+	Invoke func(f func())
+	// Joined is called when a player joins the session.
+	Joined func(p *Player)
+	// Left is called when a player leaves the session.
+	Left func(p *Player)
+	// Received is called with each message a player sends.
+	Received func(p *Player, msg *Message)
+	server   *http.Server
+	lock     sync.Mutex
+	players  map[*Player]struct{}
+}
+
+// Start listening for players on all network interfaces at the given port.
+func (h *Host) Start(port int) error {
+	if h.server != nil {
+		return errs.New("session already started")
+	}
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return errs.NewWithCause("unable to listen on port "+strconv.Itoa(port), err)
+	}
+	h.server = &http.Server{
+		Handler:           h.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func(server *http.Server) {
+		if err2 := server.Serve(listener); err2 != nil && !errors.Is(err2, http.ErrServerClosed) {
+			errs.Log(err2)
+		}
+	}(h.server)
+	return nil
+}
+
+// Stop the session, disconnecting all players.
+func (h *Host) Stop(ctx context.Context) error {
+	if h.server == nil {
+		return nil
+	}
+	server := h.server
+	h.server = nil
+	// Shutdown doesn't wait on or close hijacked connections, so the players must be disconnected separately.
+	h.lock.Lock()
+	for p := range h.players {
+		p.link.close()
+	}
+	h.lock.Unlock()
+	return server.Shutdown(ctx)
+}
+
+// Handler returns the http.Handler that players connect to.
+func (h *Host) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET "+Path, websocket.Server{
+		Handshake: h.handshake,
+		Handler:   h.serve,
+	})
+	return mux
+}
+
+func (h *Host) handshake(_ *websocket.Config, r *http.Request) error {
+	query := r.URL.Query()
+	if strings.TrimSpace(query.Get("player")) == "" {
+		return errs.New("player name is required")
+	}
+	if h.Password != "" && subtle.ConstantTimeCompare([]byte(query.Get("password")), []byte(h.Password)) != 1 {
+		return errs.New("incorrect password")
+	}
+	return nil
+}
+
+func (h *Host) serve(conn *websocket.Conn) {
+	r := conn.Request()
+	p := &Player{
+		Name:    strings.TrimSpace(r.URL.Query().Get("player")),
+		Address: r.RemoteAddr,
+		link:    newLink(conn),
+	}
+	h.lock.Lock()
+	if h.players == nil {
+		h.players = make(map[*Player]struct{})
+	}
+	h.players[p] = struct{}{}
+	h.lock.Unlock()
+	h.Invoke(func() { h.Joined(p) })
+	if err := p.link.read(func(msg *Message) { h.Invoke(func() { h.Received(p, msg) }) }); err != nil {
+		errs.Log(err, "player", p.Name)
+	}
+	h.lock.Lock()
+	delete(h.players, p)
+	h.lock.Unlock()
+	h.Invoke(func() { h.Left(p) })
+}
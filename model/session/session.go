@@ -0,0 +1,160 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package session provides shared play sessions over the local network. A GM hosts a session and players join it,
+// sharing their character sheets. The GM sees copies of the players' sheets, receives the results of their rolls, and
+// may push damage and conditions to them.
+//
+// Players connect to the host's WebSocket at /session, passing their name and the session password as the "player"
+// and "password" query parameters. Each WebSocket message holds a single JSON-encoded Message.
+package session
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/restapi"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"golang.org/x/net/websocket"
+)
+
+// DefaultPort is the port a session is hosted on when none has been configured.
+const DefaultPort = 13324
+
+// Path is the path of the session's WebSocket on the host.
+const Path = "/session"
+
+const (
+	maxPayloadSize = 64 * 1024 * 1024
+	queueSize      = 64
+)
+
+// Message types sent from a player to the GM.
+const (
+	// SheetMsg carries the full content of a sheet the player is sharing, in Entity.
+	SheetMsg = "sheet"
+	// SheetClosedMsg reports that the player is no longer sharing the sheet identified by Sheet.
+	SheetClosedMsg = "sheet_closed"
+	// RollMsg carries the result of a roll the player made, in Roll.
+	RollMsg = "roll"
+)
+
+// Message types sent from the GM to a player.
+const (
+	// DamageMsg asks the player to record a hit, described by Damage, against the sheet identified by Sheet.
+	DamageMsg = "damage"
+	// ConditionMsg asks the player to add Condition to the sheet identified by Sheet.
+	ConditionMsg = "condition"
+)
+
+// Damage describes a hit to be recorded against a character.
+type Damage struct {
+	Location string `json:"location"`
+	Type     string `json:"type"`
+	Basic    int    `json:"basic"`
+}
+
+// Message is the unit of communication between the GM and the players.
+type Message struct {
+	Type      string              `json:"type"`
+	Sheet     tid.TID             `json:"sheet,omitzero"`
+	Entity    *gurps.Entity       `json:"entity,omitzero"`
+	Roll      *restapi.RollResult `json:"roll,omitzero"`
+	Damage    *Damage             `json:"damage,omitzero"`
+	Condition *gurps.Condition    `json:"condition,omitzero"`
+}
+
+// link manages the flow of messages over a single WebSocket connection. Outgoing messages are queued so that senders
+// never block on the network.
+type link struct {
+	conn      *websocket.Conn
+	out       chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newLink(conn *websocket.Conn) *link {
+	conn.MaxPayloadBytes = maxPayloadSize
+	l := &link{
+		conn: conn,
+		out:  make(chan []byte, queueSize),
+		done: make(chan struct{}),
+	}
+	go l.write()
+	return l
+}
+
+// send queues the message for delivery. The message is encoded before returning, since the entity it may hold is only
+// safe to access from the caller's thread. If the other side isn't keeping up, the connection is closed rather than
+// allowing the queue to grow without bound.
+func (l *link) send(msg *Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	select {
+	case <-l.done:
+	case l.out <- data:
+	default:
+		errs.Log(errs.New("session peer isn't keeping up; disconnecting"))
+		l.close()
+	}
+}
+
+func (l *link) write() {
+	for {
+		select {
+		case <-l.done:
+			return
+		case data := <-l.out:
+			if err := websocket.Message.Send(l.conn, string(data)); err != nil {
+				l.close()
+				return
+			}
+		}
+	}
+}
+
+// read delivers each incoming message to the receiver until the connection closes. A connection closed normally
+// returns nil.
+func (l *link) read(receiver func(msg *Message)) error {
+	defer l.close()
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(l.conn, &data); err != nil {
+			select {
+			case <-l.done:
+				return nil
+			default:
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errs.Wrap(err)
+		}
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			errs.Log(errs.NewWithCause("invalid session message", err))
+			continue
+		}
+		receiver(&msg)
+	}
+}
+
+func (l *link) close() {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		_ = l.conn.Close() //nolint:errcheck // Don't care
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package session_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/session"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestSession(t *testing.T) {
+	c := check.New(t)
+	joined := make(chan *session.Player, 1)
+	left := make(chan *session.Player, 1)
+	fromPlayer := make(chan *session.Message, 4)
+	host := &session.Host{
+		Password: "secret",
+		Invoke:   func(f func()) { f() },
+		Joined:   func(p *session.Player) { joined <- p },
+		Left:     func(p *session.Player) { left <- p },
+		Received: func(_ *session.Player, msg *session.Message) { fromPlayer <- msg },
+	}
+	server := httptest.NewServer(host.Handler())
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	fromGM := make(chan *session.Message, 4)
+	disconnected := make(chan error, 1)
+	client := &session.Client{
+		Invoke:       func(f func()) { f() },
+		Received:     func(msg *session.Message) { fromGM <- msg },
+		Disconnected: func(err error) { disconnected <- err },
+	}
+	c.HasError(client.Join(address, "Alice", "wrong"))
+	c.HasError(client.Join(address, "", "secret"))
+	c.NoError(client.Join(address, "Alice", "secret"))
+	player := <-joined
+	c.Equal("Alice", player.Name)
+
+	entity := gurps.NewEntity()
+	entity.Profile.Name = "Bob"
+	client.Send(&session.Message{Type: session.SheetMsg, Entity: entity})
+	msg := <-fromPlayer
+	c.Equal(session.SheetMsg, msg.Type)
+	c.NotNil(msg.Entity)
+	c.Equal(entity.ID, msg.Entity.ID)
+	c.Equal("Bob", msg.Entity.Profile.Name)
+
+	player.Send(&session.Message{
+		Type:   session.DamageMsg,
+		Sheet:  entity.ID,
+		Damage: &session.Damage{Location: "torso", Type: "cut", Basic: 5},
+	})
+	msg = <-fromGM
+	c.Equal(session.DamageMsg, msg.Type)
+	c.Equal(entity.ID, msg.Sheet)
+	c.Equal(5, msg.Damage.Basic)
+
+	client.Leave()
+	c.NoError(<-disconnected)
+	c.Equal(player, <-left)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package textlayout
+
+import "unicode"
+
+// Offsets from a letter's isolated presentation form to its other forms.
+const (
+	isolatedForm = iota
+	finalForm
+	initialForm
+	medialForm
+)
+
+const (
+	lam     = 0x0644
+	tatweel = 0x0640
+)
+
+// arabicLetter describes how a letter joins to its neighbors and where its presentation forms start.
+type arabicLetter struct {
+	isolated rune
+	// dual is true for letters that join on both sides. All others only join to the letter before them.
+	dual bool
+}
+
+// arabicLetters maps the Arabic letters, including the additional ones used by Persian and Urdu, to their presentation
+// forms. Each letter's forms are consecutive: isolated, final, then initial and medial for those that join on both
+// sides.
+var arabicLetters = map[rune]arabicLetter{
+	0x0622: {0xFE81, false}, // Alef with madda above
+	0x0623: {0xFE83, false}, // Alef with hamza above
+	0x0624: {0xFE85, false}, // Waw with hamza above
+	0x0625: {0xFE87, false}, // Alef with hamza below
+	0x0626: {0xFE89, true},  // Yeh with hamza above
+	0x0627: {0xFE8D, false}, // Alef
+	0x0628: {0xFE8F, true},  // Beh
+	0x0629: {0xFE93, false}, // Teh marbuta
+	0x062A: {0xFE95, true},  // Teh
+	0x062B: {0xFE99, true},  // Theh
+	0x062C: {0xFE9D, true},  // Jeem
+	0x062D: {0xFEA1, true},  // Hah
+	0x062E: {0xFEA5, true},  // Khah
+	0x062F: {0xFEA9, false}, // Dal
+	0x0630: {0xFEAB, false}, // Thal
+	0x0631: {0xFEAD, false}, // Reh
+	0x0632: {0xFEAF, false}, // Zain
+	0x0633: {0xFEB1, true},  // Seen
+	0x0634: {0xFEB5, true},  // Sheen
+	0x0635: {0xFEB9, true},  // Sad
+	0x0636: {0xFEBD, true},  // Dad
+	0x0637: {0xFEC1, true},  // Tah
+	0x0638: {0xFEC5, true},  // Zah
+	0x0639: {0xFEC9, true},  // Ain
+	0x063A: {0xFECD, true},  // Ghain
+	0x0641: {0xFED1, true},  // Feh
+	0x0642: {0xFED5, true},  // Qaf
+	0x0643: {0xFED9, true},  // Kaf
+	0x0644: {0xFEDD, true},  // Lam
+	0x0645: {0xFEE1, true},  // Meem
+	0x0646: {0xFEE5, true},  // Noon
+	0x0647: {0xFEE9, true},  // Heh
+	0x0648: {0xFEED, false}, // Waw
+	0x0649: {0xFEEF, false}, // Alef maksura
+	0x064A: {0xFEF1, true},  // Yeh
+	0x067E: {0xFB56, true},  // Peh
+	0x0686: {0xFB7A, true},  // Tcheh
+	0x0698: {0xFB8A, false}, // Jeh
+	0x06A9: {0xFB8E, true},  // Keheh
+	0x06AF: {0xFB92, true},  // Gaf
+	0x06CC: {0xFBFC, true},  // Farsi yeh
+}
+
+// lamAlefLigatures maps the alefs that combine with a preceding lam to the isolated form of the resulting ligature. The
+// final form follows it.
+var lamAlefLigatures = map[rune]rune{
+	0x0622: 0xFEF5,
+	0x0623: 0xFEF7,
+	0x0625: 0xFEF9,
+	0x0627: 0xFEFB,
+}
+
+// shapeArabic replaces the Arabic letters in the logically ordered runes with the presentation form appropriate for
+// their position within a word.
+func shapeArabic(runes []rune) []rune {
+	result := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		letter, ok := arabicLetters[r]
+		if !ok {
+			result = append(result, r)
+			continue
+		}
+		joinsBefore := joinsForward(runes, i)
+		next := nextLetter(runes, i)
+		if r == lam && next != -1 {
+			if ligature, isLigature := lamAlefLigatures[runes[next]]; isLigature {
+				if joinsBefore {
+					ligature += finalForm
+				}
+				result = append(result, ligature)
+				// Keep any marks that sat between the lam and the alef
+				result = append(result, runes[i+1:next]...)
+				i = next
+				continue
+			}
+		}
+		joinsAfter := false
+		if letter.dual && next != -1 {
+			_, joinsAfter = arabicLetters[runes[next]]
+			joinsAfter = joinsAfter || runes[next] == tatweel
+		}
+		form := isolatedForm
+		switch {
+		case joinsBefore && joinsAfter:
+			form = medialForm
+		case joinsBefore:
+			form = finalForm
+		case joinsAfter:
+			form = initialForm
+		}
+		result = append(result, letter.isolated+rune(form))
+	}
+	return result
+}
+
+// joinsForward returns true if the letter before the one at index i, ignoring any marks, connects to the letter after
+// it.
+func joinsForward(runes []rune, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		if unicode.Is(unicode.Mn, runes[j]) {
+			continue
+		}
+		if runes[j] == tatweel {
+			return true
+		}
+		letter, ok := arabicLetters[runes[j]]
+		return ok && letter.dual
+	}
+	return false
+}
+
+// nextLetter returns the index of the rune after the one at index i, ignoring any marks, or -1 if there isn't one.
+func nextLetter(runes []rune, i int) int {
+	for j := i + 1; j < len(runes); j++ {
+		if !unicode.Is(unicode.Mn, runes[j]) {
+			return j
+		}
+	}
+	return -1
+}
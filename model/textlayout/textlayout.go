@@ -0,0 +1,223 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package textlayout prepares text for drawing by a renderer that places glyphs strictly left to right, one rune at a
+// time. It provides the line break opportunities needed to wrap Chinese and Japanese text, which doesn't separate its
+// words with spaces, and converts a line of text from logical to visual order so that right-to-left scripts such as
+// Hebrew and Arabic read correctly, substituting the contextual letter forms Arabic needs along the way.
+//
+// The reordering is a simplified form of the Unicode Bidirectional Algorithm: explicit embedding and isolate controls
+// are ignored, which is sufficient for the short runs of text found on a sheet.
+package textlayout
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+const (
+	noBreakBefore = "!),.:;?]}¢°’”‰′″℃、。〉》」』】〕〗〙〛〜ぁぃぅぇぉっゃゅょゎゕゖ゛゜ゝゞァィゥェォッャュョヮヵヶ・ーヽヾ！），．：；？］｝｡｣､･ｰ々…‥"
+	noBreakAfter  = "([{£¥‘“〈《「『【〔〖〘〚（［｛｢￡￥"
+)
+
+// CanBreakBefore returns true if a line may be broken between prev and next. As with the default wrapping, a line may
+// be broken after whitespace or a slash. A line may also be broken on either side of a Chinese or Japanese character,
+// except where that would start a line with closing punctuation or end one with opening punctuation.
+func CanBreakBefore(prev, next rune) bool {
+	if unicode.IsSpace(next) {
+		return false
+	}
+	if unicode.IsSpace(prev) || prev == '/' || prev == '\\' {
+		return true
+	}
+	if !breaksAroundCharacter(prev) && !breaksAroundCharacter(next) {
+		return false
+	}
+	return !strings.ContainsRune(noBreakBefore, next) && !strings.ContainsRune(noBreakAfter, prev)
+}
+
+// breaksAroundCharacter returns true if the rune is from a script that is written without spaces between its words.
+// Korean is deliberately excluded, since it separates its words with spaces.
+func breaksAroundCharacter(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0x3000 && r <= 0x303F) || // CJK Symbols and Punctuation
+		(r >= 0xFF00 && r <= 0xFFEF) // Halfwidth and Fullwidth Forms
+}
+
+// IsRightToLeft returns true if the first strongly directional character in the text belongs to a right-to-left
+// script.
+func IsRightToLeft(text string) bool {
+	for _, r := range text {
+		switch class(r) {
+		case bidi.L:
+			return false
+		case bidi.R, bidi.AL:
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// Visual returns the single line of text in visual order, ready to be drawn left to right. Text without any
+// right-to-left characters is returned unchanged.
+func Visual(line string) string {
+	runes := []rune(line)
+	hasRTL := false
+	for _, r := range runes {
+		if c := class(r); c == bidi.R || c == bidi.AL {
+			hasRTL = true
+			break
+		}
+	}
+	if !hasRTL {
+		return line
+	}
+	runes = shapeArabic(runes)
+	levels := resolveLevels(runes, IsRightToLeft(line))
+	reorder(runes, levels)
+	return string(runes)
+}
+
+func class(r rune) bidi.Class {
+	props, _ := bidi.LookupRune(r)
+	return props.Class()
+}
+
+// resolveLevels assigns an embedding level to each rune: even levels are drawn left to right and odd levels right to
+// left.
+func resolveLevels(runes []rune, rtl bool) []int {
+	var base int
+	if rtl {
+		base = 1
+	}
+	levels := make([]int, len(runes))
+	// strong holds the direction each rune takes on: 'L', 'R', 'N' (number) or 0 for neutrals still to be resolved.
+	strong := make([]byte, len(runes))
+	lastStrong := byte('L')
+	if rtl {
+		lastStrong = 'R'
+	}
+	for i, r := range runes {
+		switch class(r) {
+		case bidi.L:
+			strong[i] = 'L'
+			lastStrong = 'L'
+		case bidi.R, bidi.AL:
+			strong[i] = 'R'
+			lastStrong = 'R'
+		case bidi.EN, bidi.AN:
+			// Numbers are always drawn left to right, but in a right-to-left context they stay in place relative to
+			// the text around them.
+			if lastStrong == 'R' {
+				strong[i] = 'N'
+			} else {
+				strong[i] = 'L'
+			}
+		case bidi.NSM:
+			if i > 0 {
+				strong[i] = strong[i-1]
+			}
+		default:
+		}
+	}
+	// Neutrals take on the direction of the text around them if it agrees on both sides, otherwise that of the line.
+	for i := 0; i < len(runes); {
+		if strong[i] != 0 {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && strong[j] == 0 {
+			j++
+		}
+		before := byte('L')
+		if rtl {
+			before = 'R'
+		}
+		if i > 0 {
+			before = direction(strong[i-1])
+		}
+		after := before
+		if j < len(runes) {
+			after = direction(strong[j])
+		}
+		dir := byte('L')
+		if before == after {
+			dir = before
+		} else if rtl {
+			dir = 'R'
+		}
+		for k := i; k < j; k++ {
+			strong[k] = dir
+		}
+		i = j
+	}
+	for i, s := range strong {
+		switch s {
+		case 'R':
+			levels[i] = base | 1
+		case 'N':
+			levels[i] = (base | 1) + 1
+		default:
+			levels[i] = (base + 1) &^ 1
+		}
+	}
+	return levels
+}
+
+// direction returns the direction a resolved rune presents to the neutrals next to it. Numbers act as right-to-left.
+func direction(s byte) byte {
+	if s == 'L' {
+		return 'L'
+	}
+	return 'R'
+}
+
+// reorder reverses each run of runes at or above each level, from the highest level down to the lowest odd level,
+// mirroring any brackets that end up drawn right to left.
+func reorder(runes []rune, levels []int) {
+	highest := 0
+	lowestOdd := -1
+	for _, lvl := range levels {
+		highest = max(highest, lvl)
+		if lvl&1 == 1 && (lowestOdd == -1 || lvl < lowestOdd) {
+			lowestOdd = lvl
+		}
+	}
+	if lowestOdd == -1 {
+		return
+	}
+	for i, lvl := range levels {
+		if lvl&1 == 1 {
+			if props, _ := bidi.LookupRune(runes[i]); props.IsBracket() {
+				runes[i] = []rune(bidi.ReverseString(string(runes[i])))[0]
+			}
+		}
+	}
+	for lvl := highest; lvl >= lowestOdd; lvl-- {
+		for i := 0; i < len(runes); {
+			if levels[i] < lvl {
+				i++
+				continue
+			}
+			j := i
+			for j < len(runes) && levels[j] >= lvl {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				runes[a], runes[b] = runes[b], runes[a]
+				levels[a], levels[b] = levels[b], levels[a]
+			}
+			i = j
+		}
+	}
+}
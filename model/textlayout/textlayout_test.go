@@ -0,0 +1,59 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package textlayout_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/textlayout"
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestCanBreakBefore(t *testing.T) {
+	c := check.New(t)
+	c.True(textlayout.CanBreakBefore(' ', 'b'))
+	c.False(textlayout.CanBreakBefore('a', ' '))
+	c.False(textlayout.CanBreakBefore('a', 'b'))
+	c.True(textlayout.CanBreakBefore('/', 'b'))
+	c.True(textlayout.CanBreakBefore('剣', '術'))
+	c.True(textlayout.CanBreakBefore('a', '剣'))
+	c.True(textlayout.CanBreakBefore('け', 'ん'))
+	c.False(textlayout.CanBreakBefore('術', '。'), "closing punctuation may not start a line")
+	c.False(textlayout.CanBreakBefore('「', '剣'), "opening punctuation may not end a line")
+	c.False(textlayout.CanBreakBefore('검', '술'), "Korean breaks between words")
+}
+
+func TestVisual(t *testing.T) {
+	c := check.New(t)
+	c.Equal("Broadsword", textlayout.Visual("Broadsword"))
+	c.Equal("剣術", textlayout.Visual("剣術"))
+	c.Equal("םולש", textlayout.Visual("שלום"))
+	c.Equal("Skill: םולש", textlayout.Visual("Skill: שלום"))
+	c.Equal("12 םולש", textlayout.Visual("שלום 12"), "numbers keep their own order")
+	c.Equal("(בא)", textlayout.Visual("(אב)"), "brackets are mirrored")
+	c.Equal("ABC ד גב א", textlayout.Visual("א בג ד ABC"))
+}
+
+func TestArabicShaping(t *testing.T) {
+	c := check.New(t)
+	// Seen + Lam + Alef + Meem: initial seen, lam-alef ligature in its final form, isolated meem
+	c.Equal("ﻡﻼﺳ", textlayout.Visual("سلام"))
+	// Beh + Beh: initial then final
+	c.Equal("ﺐﺑ", textlayout.Visual("بب"))
+	// Dal doesn't join to what follows it, so the beh after it is isolated
+	c.Equal("ﺏﺩ", textlayout.Visual("دب"))
+}
+
+func TestIsRightToLeft(t *testing.T) {
+	c := check.New(t)
+	c.False(textlayout.IsRightToLeft("123 Broadsword"))
+	c.True(textlayout.IsRightToLeft("12 שלום"))
+	c.False(textlayout.IsRightToLeft(""))
+}
@@ -0,0 +1,353 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+// Package translation provides the text lookup used for the user interface in place of the one built into the i18n
+// package, so that translations can be edited while the application is running.
+//
+// Translations come from two places: the localization files shipped with the application and a directory of
+// overrides the user has made. Both use the i18n file format, so an override file can be contributed back as is.
+package translation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xio"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+)
+
+// Table maps the original text to its translation.
+type Table map[string]string
+
+// Read a table in the i18n file format.
+func Read(r io.Reader) (Table, error) {
+	t := make(Table)
+	var key, value string
+	var hasKey, hasValue bool
+	store := func() {
+		if hasKey && hasValue {
+			if _, exists := t[key]; !exists {
+				t[key] = value
+			}
+		}
+		hasKey = false
+		hasValue = false
+	}
+	s := bufio.NewScanner(r)
+	lineNum := 0
+	for s.Scan() {
+		lineNum++
+		line := s.Text()
+		var target *string
+		var has *bool
+		switch {
+		case strings.HasPrefix(line, "k:"):
+			if hasValue {
+				store()
+			}
+			target = &key
+			has = &hasKey
+		case strings.HasPrefix(line, "v:"):
+			if !hasKey {
+				return nil, errs.Newf("value with no key on line %d", lineNum)
+			}
+			target = &value
+			has = &hasValue
+		default:
+			continue
+		}
+		var buffer string
+		if _, err := fmt.Sscanf(line[2:], "%q", &buffer); err != nil {
+			return nil, errs.NewWithCausef(err, "invalid text on line %d", lineNum)
+		}
+		if *has {
+			*target += "\n" + buffer
+		} else {
+			*target = buffer
+			*has = true
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	store()
+	return t, nil
+}
+
+// ReadFile reads a table from the file at the given path.
+func ReadFile(filePath string) (Table, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	defer xio.CloseIgnoringErrors(f)
+	return Read(f)
+}
+
+// Write the table in the i18n file format, sorted by the original text.
+func (t Table) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	keys := slices.Sorted(maps.Keys(t))
+	for i, key := range keys {
+		if i != 0 {
+			if _, err := bw.WriteString("\n"); err != nil {
+				return errs.Wrap(err)
+			}
+		}
+		for _, line := range strings.Split(key, "\n") {
+			if _, err := fmt.Fprintf(bw, "k:%q\n", line); err != nil {
+				return errs.Wrap(err)
+			}
+		}
+		for _, line := range strings.Split(t[key], "\n") {
+			if _, err := fmt.Fprintf(bw, "v:%q\n", line); err != nil {
+				return errs.Wrap(err)
+			}
+		}
+	}
+	return errs.Wrap(bw.Flush())
+}
+
+// WriteFile writes the table to the file at the given path.
+func (t Table) WriteFile(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if err = t.Write(f); err != nil {
+		xio.CloseIgnoringErrors(f)
+		return err
+	}
+	return errs.Wrap(f.Close())
+}
+
+// Code returns the normalized form of a language, as used for the base name of its localization file. For example,
+// "de-DE.UTF-8" becomes "de_de_utf_8".
+func Code(language string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(language), "-", "_"), ".", "_")
+}
+
+// Hierarchy returns the codes to search for a language, from the most specific to the least. For example, "de_DE"
+// yields "de_de" and then "de".
+func Hierarchy(language string) []string {
+	code := Code(language)
+	if code == "" {
+		return nil
+	}
+	var list []string
+	for {
+		list = append(list, code)
+		i := strings.LastIndex(code, "_")
+		if i == -1 {
+			return list
+		}
+		code = code[:i]
+	}
+}
+
+// Catalog holds the translations available to the application.
+type Catalog struct {
+	lock      sync.RWMutex
+	base      map[string]Table
+	overrides map[string]Table
+	seen      map[string]struct{}
+}
+
+// NewCatalog creates a new, empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		base:      make(map[string]Table),
+		overrides: make(map[string]Table),
+		seen:      make(map[string]struct{}),
+	}
+}
+
+// LoadDir loads each localization file found in the directory, either as the shipped translations or as overrides.
+// A missing directory is not an error.
+func (c *Catalog) LoadDir(dir string, overrides bool) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errs.Wrap(err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != i18n.Extension {
+			continue
+		}
+		var t Table
+		if t, err = ReadFile(filepath.Join(dir, name)); err != nil {
+			return errs.NewWithCause(name, err)
+		}
+		c.Set(strings.TrimSuffix(name, i18n.Extension), t, overrides)
+	}
+	return nil
+}
+
+// Set replaces the shipped translations or the overrides for a language.
+func (c *Catalog) Set(language string, t Table, overrides bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if overrides {
+		c.overrides[Code(language)] = t
+	} else {
+		c.base[Code(language)] = t
+	}
+}
+
+// Text returns the translation of the text into the current i18n.Language, falling back to the languages in
+// i18n.Languages and then to the text itself. An override always takes precedence over a shipped translation for the
+// same language. This is suitable for passing to i18n.SetLocalizer().
+func (c *Catalog) Text(text string) string {
+	c.lock.RLock()
+	_, seen := c.seen[text]
+	result, found := c.lookup(text, i18n.Language)
+	for i := 0; !found && i < len(i18n.Languages); i++ {
+		result, found = c.lookup(text, i18n.Languages[i])
+	}
+	c.lock.RUnlock()
+	if !seen {
+		c.lock.Lock()
+		c.seen[text] = struct{}{}
+		c.lock.Unlock()
+	}
+	if found {
+		return result
+	}
+	return text
+}
+
+// Shipped returns the translation the application provides for the text in the language, without regard to any
+// overrides, or an empty string if there isn't one.
+func (c *Catalog) Shipped(language, text string) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, code := range Hierarchy(language) {
+		if value, ok := c.base[code][text]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+func (c *Catalog) lookup(text, language string) (string, bool) {
+	for _, code := range Hierarchy(language) {
+		if value, ok := c.overrides[code][text]; ok {
+			return value, true
+		}
+		if value, ok := c.base[code][text]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Languages returns the codes of the languages that have either shipped translations or overrides, sorted.
+func (c *Catalog) Languages() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	set := make(map[string]struct{}, len(c.base)+len(c.overrides))
+	for code := range c.base {
+		set[code] = struct{}{}
+	}
+	for code := range c.overrides {
+		set[code] = struct{}{}
+	}
+	return slices.Sorted(maps.Keys(set))
+}
+
+// Keys returns the original text of each string that can be translated: those the application has asked to be
+// translated so far, plus any that some translation already provides. They are sorted naturally.
+func (c *Catalog) Keys() []string {
+	c.lock.RLock()
+	set := maps.Clone(c.seen)
+	for _, tables := range []map[string]Table{c.base, c.overrides} {
+		for _, t := range tables {
+			for key := range t {
+				set[key] = struct{}{}
+			}
+		}
+	}
+	c.lock.RUnlock()
+	delete(set, "")
+	keys := slices.Collect(maps.Keys(set))
+	xstrings.SortStringsNaturalAscending(keys)
+	return keys
+}
+
+// Override returns the override for the text in the language, if there is one.
+func (c *Catalog) Override(language, text string) (string, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	value, ok := c.overrides[Code(language)][text]
+	return value, ok
+}
+
+// SetOverride sets the override for the text in the language. An empty value removes the override.
+func (c *Catalog) SetOverride(language, text, value string) {
+	code := Code(language)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := c.overrides[code]
+	if value == "" {
+		delete(t, text)
+		return
+	}
+	if t == nil {
+		t = make(Table)
+		c.overrides[code] = t
+	}
+	t[text] = value
+}
+
+// Overrides returns a copy of the overrides for the language.
+func (c *Catalog) Overrides(language string) Table {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return maps.Clone(c.overrides[Code(language)])
+}
+
+// Merged returns the complete translation for exactly the language given: the shipped translations with the
+// overrides applied on top of them.
+func (c *Catalog) Merged(language string) Table {
+	code := Code(language)
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	t := make(Table, len(c.base[code])+len(c.overrides[code]))
+	maps.Copy(t, c.base[code])
+	maps.Copy(t, c.overrides[code])
+	return t
+}
+
+// SaveOverrides writes the overrides for the language into the directory, removing the file if there are none.
+func (c *Catalog) SaveOverrides(dir, language string) error {
+	filePath := filepath.Join(dir, Code(language)+i18n.Extension)
+	t := c.Overrides(language)
+	if len(t) == 0 {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return errs.Wrap(err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return errs.Wrap(err)
+	}
+	return t.WriteFile(filePath)
+}
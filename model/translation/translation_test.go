@@ -0,0 +1,97 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package translation_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model/translation"
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+)
+
+func TestReadWrite(t *testing.T) {
+	c := check.New(t)
+	table, err := translation.Read(strings.NewReader(`k:"Skills"
+v:"Fertigkeiten"
+
+k:"Line one"
+k:"line two"
+v:"Zeile eins"
+v:"Zeile \"zwei\""
+`))
+	c.NoError(err)
+	c.Equal(translation.Table{
+		"Skills":             "Fertigkeiten",
+		"Line one\nline two": "Zeile eins\nZeile \"zwei\"",
+	}, table)
+
+	var buffer bytes.Buffer
+	c.NoError(table.Write(&buffer))
+	again, err := translation.Read(&buffer)
+	c.NoError(err)
+	c.Equal(table, again)
+
+	_, err = translation.Read(strings.NewReader(`v:"orphan"`))
+	c.HasError(err)
+}
+
+func TestHierarchy(t *testing.T) {
+	c := check.New(t)
+	c.Equal([]string{"de_de_utf_8", "de_de_utf", "de_de", "de"}, translation.Hierarchy("de-DE.UTF-8"))
+	c.Equal(0, len(translation.Hierarchy("")))
+}
+
+func TestCatalog(t *testing.T) {
+	c := check.New(t)
+	savedLanguage := i18n.Language
+	savedLanguages := i18n.Languages
+	defer func() {
+		i18n.Language = savedLanguage
+		i18n.Languages = savedLanguages
+	}()
+	i18n.Languages = nil
+
+	dir := t.TempDir()
+	c.NoError(translation.Table{"Skills": "Fertigkeiten", "Spells": "Zauber"}.WriteFile(filepath.Join(dir, "de.i18n")))
+	catalog := translation.NewCatalog()
+	c.NoError(catalog.LoadDir(dir, false))
+	c.NoError(catalog.LoadDir(filepath.Join(dir, "missing"), true))
+	c.Equal([]string{"de"}, catalog.Languages())
+
+	i18n.Language = "de_AT"
+	c.Equal("Fertigkeiten", catalog.Text("Skills"))
+	c.Equal("Traits", catalog.Text("Traits"))
+
+	catalog.SetOverride("de", "Spells", "Sprüche")
+	catalog.SetOverride("de_at", "Traits", "Eigenschaften")
+	c.Equal("Sprüche", catalog.Text("Spells"))
+	c.Equal("Eigenschaften", catalog.Text("Traits"))
+	c.Equal("Zauber", catalog.Shipped("de", "Spells"))
+	c.Equal([]string{"de", "de_at"}, catalog.Languages())
+	c.Equal([]string{"Skills", "Spells", "Traits"}, catalog.Keys())
+	c.Equal(translation.Table{"Skills": "Fertigkeiten", "Spells": "Sprüche"}, catalog.Merged("de"))
+
+	overrides := filepath.Join(dir, "overrides")
+	c.NoError(catalog.SaveOverrides(overrides, "de"))
+	reloaded, err := translation.ReadFile(filepath.Join(overrides, "de.i18n"))
+	c.NoError(err)
+	c.Equal(translation.Table{"Spells": "Sprüche"}, reloaded)
+
+	catalog.SetOverride("de", "Spells", "")
+	c.Equal("Zauber", catalog.Text("Spells"))
+	c.NoError(catalog.SaveOverrides(overrides, "de"))
+	_, err = os.Stat(filepath.Join(overrides, "de.i18n"))
+	c.True(os.IsNotExist(err))
+}
@@ -140,6 +140,10 @@ var (
 	gcsTraitsData string
 	GCSTraits     = unison.MustSVGFromContentString(gcsTraitsData)
 
+	//go:embed gcs_vehicle.svg
+	gcsVehicleData string
+	GCSVehicle     = unison.MustSVGFromContentString(gcsVehicleData)
+
 	//go:embed gears.svg
 	gearsData string
 	Gears     = unison.MustSVGFromContentString(gearsData)
@@ -176,6 +180,10 @@ var (
 	linkData string
 	Link     = unison.MustSVGFromContentString(linkData)
 
+	//go:embed lock.svg
+	lockData string
+	Lock     = unison.MustSVGFromContentString(lockData)
+
 	//go:embed markdown_file.svg
 	markdownFileData string
 	MarkdownFile     = unison.MustSVGFromContentString(markdownFileData)
@@ -280,6 +288,10 @@ var (
 	trashData string
 	Trash     = unison.MustSVGFromContentString(trashData)
 
+	//go:embed unlock.svg
+	unlockData string
+	Unlock     = unison.MustSVGFromContentString(unlockData)
+
 	//go:embed magic_wand.svg
 	magicWandData string
 	MagicWand     = unison.MustSVGFromContentString(magicWandData)
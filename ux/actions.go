@@ -15,6 +15,8 @@ import (
 	"path/filepath"
 
 	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/export"
+	"github.com/richardwilkes/gcs/v5/model/gurps/importer"
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/xos"
 	"github.com/richardwilkes/unison"
@@ -28,47 +30,75 @@ var licenseMarkdownContent string
 
 // These actions are registered for key bindings.
 var (
-	addNaturalAttacksAction        *unison.Action
-	applyTemplateAction            *unison.Action
-	clearPortraitAction            *unison.Action
-	clearSourceAction              *unison.Action
-	cloneSheetAction               *unison.Action
-	closeTabAction                 *unison.Action
-	colorSettingsAction            *unison.Action
-	convertToContainerAction       *unison.Action
-	convertToNonContainerAction    *unison.Action
-	copyToSheetAction              *unison.Action
-	copyToTemplateAction           *unison.Action
-	decreaseEquipmentLevelAction   *unison.Action
-	decreaseSkillLevelAction       *unison.Action
-	decreaseTechLevelAction        *unison.Action
-	decreaseUsesAction             *unison.Action
-	decrementAction                *unison.Action
-	defaultAttributeSettingsAction *unison.Action
-	defaultBodyTypeSettingsAction  *unison.Action
-	defaultSheetSettingsAction     *unison.Action
-	dockUnDockAction               *unison.Action
-	duplicateAction                *unison.Action
-	exportAsJPEGAction             *unison.Action
-	exportAsPDFAction              *unison.Action
-	exportAsPNGAction              *unison.Action
-	exportAsWEBPAction             *unison.Action
-	exportPortraitAction           *unison.Action
-	fontSettingsAction             *unison.Action
-	generalSettingsAction          *unison.Action
-	increaseEquipmentLevelAction   *unison.Action
-	increaseSkillLevelAction       *unison.Action
-	increaseTechLevelAction        *unison.Action
-	increaseUsesAction             *unison.Action
-	incrementAction                *unison.Action
-	jumpToSearchFilterAction       *unison.Action
-	menuKeySettingsAction          *unison.Action
-	moveToCarriedEquipmentAction   *unison.Action
-	moveToOtherEquipmentAction     *unison.Action
-	// TODO: Re-enable Campaign files
-	// newCampaignAction                   *unison.Action
+	addNaturalAttacksAction             *unison.Action
+	applyEquipmentModifierAction        *unison.Action
+	applyTemplateAction                 *unison.Action
+	applyTraitModifierAction            *unison.Action
+	buildCompendiumAction               *unison.Action
+	clearPortraitAction                 *unison.Action
+	clearSourceAction                   *unison.Action
+	checkIntegrityAction                *unison.Action
+	cloneSheetAction                    *unison.Action
+	closeTabAction                      *unison.Action
+	cloudSyncAction                     *unison.Action
+	cloudSyncSettingsAction             *unison.Action
+	colorSettingsAction                 *unison.Action
+	commandPaletteAction                *unison.Action
+	compareSheetsAction                 *unison.Action
+	convertToContainerAction            *unison.Action
+	convertToNonContainerAction         *unison.Action
+	copyToSheetAction                   *unison.Action
+	copyToTemplateAction                *unison.Action
+	decreaseEquipmentLevelAction        *unison.Action
+	decreaseSkillLevelAction            *unison.Action
+	decreaseTechLevelAction             *unison.Action
+	decreaseUsesAction                  *unison.Action
+	decrementAction                     *unison.Action
+	defaultAttributeSettingsAction      *unison.Action
+	defaultBodyTypeSettingsAction       *unison.Action
+	defaultRulesetAction                *unison.Action
+	defaultSheetSettingsAction          *unison.Action
+	diagnosticsAction                   *unison.Action
+	diceRollerAction                    *unison.Action
+	dockUnDockAction                    *unison.Action
+	duplicateAction                     *unison.Action
+	exportAsFoundryAction               *unison.Action
+	exportAsHTMLAction                  *unison.Action
+	exportAsJPEGAction                  *unison.Action
+	exportAsMarkdownAction              *unison.Action
+	exportAsPDFAction                   *unison.Action
+	exportAsPNGAction                   *unison.Action
+	exportAsStatblockAction             *unison.Action
+	exportAsSVGAction                   *unison.Action
+	exportAsWEBPAction                  *unison.Action
+	exportPortraitAction                *unison.Action
+	exportTokenAction                   *unison.Action
+	findReplaceAction                   *unison.Action
+	fontSettingsAction                  *unison.Action
+	generalSettingsAction               *unison.Action
+	globalSearchAction                  *unison.Action
+	hostSessionAction                   *unison.Action
+	importFoundryAction                 *unison.Action
+	importGCA5Action                    *unison.Action
+	increaseEquipmentLevelAction        *unison.Action
+	increaseSkillLevelAction            *unison.Action
+	increaseTechLevelAction             *unison.Action
+	increaseUsesAction                  *unison.Action
+	incrementAction                     *unison.Action
+	joinSessionAction                   *unison.Action
+	jumpToSearchFilterAction            *unison.Action
+	languageSettingsAction              *unison.Action
+	legacyConversionAction              *unison.Action
+	libraryDuplicatesAction             *unison.Action
+	manageWorkspacesAction              *unison.Action
+	menuKeySettingsAction               *unison.Action
+	mergeSheetsAction                   *unison.Action
+	moveToCarriedEquipmentAction        *unison.Action
+	moveToOtherEquipmentAction          *unison.Action
+	newCampaignAction                   *unison.Action
 	newCarriedEquipmentAction           *unison.Action
 	newCarriedEquipmentContainerAction  *unison.Action
+	newCharacterFromGeneratorAction     *unison.Action
 	newCharacterSheetAction             *unison.Action
 	newCharacterTemplateAction          *unison.Action
 	newLootSheetAction                  *unison.Action
@@ -78,6 +108,7 @@ var (
 	newEquipmentModifiersLibraryAction  *unison.Action
 	newMarkdownFileAction               *unison.Action
 	newMeleeWeaponAction                *unison.Action
+	newNPCFromArchetypeAction           *unison.Action
 	newNoteAction                       *unison.Action
 	newNoteContainerAction              *unison.Action
 	newNotesLibraryAction               *unison.Action
@@ -99,18 +130,24 @@ var (
 	newTraitModifierAction              *unison.Action
 	newTraitModifiersLibraryAction      *unison.Action
 	newTraitsLibraryAction              *unison.Action
+	newVehicleSheetAction               *unison.Action
 	openAction                          *unison.Action
 	openEachPageReferenceAction         *unison.Action
 	openEditorAction                    *unison.Action
 	openOnePageReferenceAction          *unison.Action
+	optimizeSkillLevelsAction           *unison.Action
 	pageRefMappingsAction               *unison.Action
 	perSheetAttributeSettingsAction     *unison.Action
 	perSheetBodyTypeSettingsAction      *unison.Action
+	perSheetRulesetAction               *unison.Action
 	perSheetSettingsAction              *unison.Action
 	printAction                         *unison.Action
 	redoAction                          *unison.Action
+	reloadWeaponAction                  *unison.Action
+	reviewSourceChangesAction           *unison.Action
 	saveAction                          *unison.Action
 	saveAsAction                        *unison.Action
+	saveWorkspaceAction                 *unison.Action
 	scale100Action                      *unison.Action
 	scale200Action                      *unison.Action
 	scale25Action                       *unison.Action
@@ -123,10 +160,16 @@ var (
 	scaleDefaultAction                  *unison.Action
 	scaleDownAction                     *unison.Action
 	scaleUpAction                       *unison.Action
+	scriptConsoleAction                 *unison.Action
+	sheetThemesAction                   *unison.Action
+	spellPrereqGraphAction              *unison.Action
 	syncWithSourceAction                *unison.Action
 	swapDefaultsAction                  *unison.Action
+	tagManagerAction                    *unison.Action
 	toggleStateAction                   *unison.Action
 	undoAction                          *unison.Action
+	undoHistoryAction                   *unison.Action
+	useAmmoAction                       *unison.Action
 )
 
 // These actions aren't registered for key bindings.
@@ -157,6 +200,12 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	applyEquipmentModifierAction = registerKeyBindableAction("apply.eqm", &unison.Action{
+		ID:              ApplyEquipmentModifierItemID,
+		Title:           i18n.Text("Apply Equipment Modifier…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	applyTemplateAction = registerKeyBindableAction("apply.template", &unison.Action{
 		ID:              ApplyTemplateItemID,
 		Title:           i18n.Text("Apply Template to Character Sheet"),
@@ -164,6 +213,24 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	applyTraitModifierAction = registerKeyBindableAction("apply.adm", &unison.Action{
+		ID:              ApplyTraitModifierItemID,
+		Title:           i18n.Text("Apply Trait Modifier…"),
+		KeyBinding:      unison.KeyBinding{KeyCode: unison.KeyL, Modifiers: unison.ShiftModifier | unison.OSMenuCmdModifier()},
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
+	buildCompendiumAction = registerKeyBindableAction("build.compendium", &unison.Action{
+		ID:              BuildCompendiumItemID,
+		Title:           i18n.Text("Build Compendium from Sheets…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { BuildCompendium() },
+	})
+	checkIntegrityAction = registerKeyBindableAction("check.integrity", &unison.Action{
+		ID:              CheckIntegrityItemID,
+		Title:           i18n.Text("Check Point Totals & Calculated Values…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	cloneSheetAction = registerKeyBindableAction("clone.sheet", &unison.Action{
 		ID:              CloneSheetItemID,
 		Title:           i18n.Text("Clone Character Sheet & Re-Randomize Fields"),
@@ -183,6 +250,12 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	exportTokenAction = registerKeyBindableAction("export.token", &unison.Action{
+		ID:              ExportTokenItemID,
+		Title:           i18n.Text("Export Token…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	clearPortraitAction = registerKeyBindableAction("clear.portrait", &unison.Action{
 		ID:              ClearPortraitItemID,
 		Title:           i18n.Text("Clear Portrait"),
@@ -225,11 +298,33 @@ func registerActions() {
 			}
 		},
 	})
+	cloudSyncAction = registerKeyBindableAction("cloud_sync", &unison.Action{
+		ID:              CloudSyncItemID,
+		Title:           i18n.Text("Sync with Cloud"),
+		EnabledCallback: func(_ *unison.Action, _ any) bool { return !cloudSyncRunning },
+		ExecuteCallback: func(_ *unison.Action, _ any) { SyncWithCloud() },
+	})
+	cloudSyncSettingsAction = registerKeyBindableAction("settings.cloud_sync", &unison.Action{
+		ID:              CloudSyncSettingsItemID,
+		Title:           i18n.Text("Cloud Sync…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowCloudSyncSettings() },
+	})
 	colorSettingsAction = registerKeyBindableAction("settings.colors", &unison.Action{
 		ID:              ColorSettingsItemID,
 		Title:           i18n.Text("Colors…"),
 		ExecuteCallback: func(_ *unison.Action, _ any) { ShowColorSettings() },
 	})
+	commandPaletteAction = registerKeyBindableAction("command_palette", &unison.Action{
+		ID:              CommandPaletteItemID,
+		Title:           i18n.Text("Command Palette…"),
+		KeyBinding:      unison.KeyBinding{KeyCode: unison.KeyP, Modifiers: unison.ShiftModifier | unison.OSMenuCmdModifier()},
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowCommandPalette() },
+	})
+	compareSheetsAction = registerKeyBindableAction("compare_sheets", &unison.Action{
+		ID:              CompareSheetsItemID,
+		Title:           i18n.Text("Compare Sheets…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowSheetComparison() },
+	})
 	convertToContainerAction = registerKeyBindableAction("convert.to_container", &unison.Action{
 		ID:              ConvertToContainerItemID,
 		Title:           i18n.Text("Convert to Container"),
@@ -300,12 +395,27 @@ func registerActions() {
 		Title:           i18n.Text("Default Body Type…"),
 		ExecuteCallback: func(_ *unison.Action, _ any) { ShowBodySettings(&globalBodySettingsOwner{}) },
 	})
+	defaultRulesetAction = registerKeyBindableAction("settings.ruleset.default", &unison.Action{
+		ID:              DefaultRulesetItemID,
+		Title:           i18n.Text("Default Ruleset…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowRuleset(nil) },
+	})
 	defaultSheetSettingsAction = registerKeyBindableAction("settings.sheet.default", &unison.Action{
 		ID:              DefaultSheetSettingsItemID,
 		Title:           i18n.Text("Default Sheet Settings…"),
 		KeyBinding:      unison.KeyBinding{KeyCode: unison.KeyComma, Modifiers: unison.OSMenuCmdModifier()},
 		ExecuteCallback: func(_ *unison.Action, _ any) { ShowSheetSettings(nil) },
 	})
+	diagnosticsAction = registerKeyBindableAction("diagnostics", &unison.Action{
+		ID:              DiagnosticsItemID,
+		Title:           i18n.Text("Diagnostics"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowDiagnostics() },
+	})
+	diceRollerAction = registerKeyBindableAction("dice_roller", &unison.Action{
+		ID:              DiceRollerItemID,
+		Title:           i18n.Text("Dice Roller"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowRoller() },
+	})
 	dockUnDockAction = registerKeyBindableAction("dock_undock", &unison.Action{
 		ID:              DockUnDockItemID,
 		Title:           i18n.Text("Undock From Workspace"),
@@ -320,6 +430,46 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	exportAsFoundryAction = registerKeyBindableAction("export.foundry", &unison.Action{
+		ID:              ExportAsFoundryItemID,
+		Title:           i18n.Text("Foundry VTT (GURPS Game Aid)"),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if sheet := ActiveSheet(); sheet != nil {
+				ExportSheet(sheet, export.FoundryExtension, export.ToFoundry)
+			}
+		},
+	})
+	exportAsHTMLAction = registerKeyBindableAction("export.html", &unison.Action{
+		ID:              ExportAsHTMLItemID,
+		Title:           i18n.Text("HTML"),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if sheet := ActiveSheet(); sheet != nil {
+				ExportSheet(sheet, export.HTMLExtension, export.ToHTML)
+			}
+		},
+	})
+	exportAsMarkdownAction = registerKeyBindableAction("export.markdown", &unison.Action{
+		ID:              ExportAsMarkdownItemID,
+		Title:           i18n.Text("Markdown"),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if sheet := ActiveSheet(); sheet != nil {
+				ExportSheet(sheet, export.MarkdownExtension, export.ToMarkdown)
+			}
+		},
+	})
+	exportAsStatblockAction = registerKeyBindableAction("export.statblock", &unison.Action{
+		ID:              ExportAsStatblockItemID,
+		Title:           i18n.Text("Statblock (Text)"),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if sheet := ActiveSheet(); sheet != nil {
+				ExportSheet(sheet, export.StatblockExtension, export.ToStatblock)
+			}
+		},
+	})
 	exportAsJPEGAction = registerKeyBindableAction("export.jpeg", &unison.Action{
 		ID:              ExportAsJPEGItemID,
 		Title:           i18n.Text("JPEG"),
@@ -329,7 +479,7 @@ func registerActions() {
 	exportAsPDFAction = registerKeyBindableAction("export.pdf", &unison.Action{
 		ID:              ExportAsPDFItemID,
 		Title:           i18n.Text("PDF"),
-		KeyBinding:      unison.KeyBinding{KeyCode: unison.KeyP, Modifiers: unison.ShiftModifier | unison.OSMenuCmdModifier()},
+		KeyBinding:      unison.KeyBinding{KeyCode: unison.KeyP, Modifiers: unison.ShiftModifier | unison.OptionModifier | unison.OSMenuCmdModifier()},
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
@@ -339,12 +489,24 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	exportAsSVGAction = registerKeyBindableAction("export.svg", &unison.Action{
+		ID:              ExportAsSVGItemID,
+		Title:           i18n.Text("SVG"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	exportAsWEBPAction = registerKeyBindableAction("export.webp", &unison.Action{
 		ID:              ExportAsWEBPItemID,
 		Title:           i18n.Text("WEBP"),
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	joinSessionAction = registerKeyBindableAction("session.join", &unison.Action{
+		ID:              JoinSessionItemID,
+		Title:           i18n.Text("Join Session…"),
+		EnabledCallback: func(_ *unison.Action, _ any) bool { return activeSession == nil },
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowJoinSessionDialog() },
+	})
 	jumpToSearchFilterAction = registerKeyBindableAction("jump-to-search", &unison.Action{
 		ID:              JumpToSearchFilterItemID,
 		Title:           i18n.Text("Jump to Search/Filter Field"),
@@ -352,6 +514,13 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	findReplaceAction = registerKeyBindableAction("find.replace", &unison.Action{
+		ID:              FindReplaceItemID,
+		Title:           i18n.Text("Find and Replace…"),
+		KeyBinding:      unison.KeyBinding{KeyCode: unison.KeyH, Modifiers: unison.ShiftModifier | unison.OSMenuCmdModifier()},
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	fontSettingsAction = registerKeyBindableAction("settings.fonts", &unison.Action{
 		ID:              FontSettingsItemID,
 		Title:           i18n.Text("Fonts…"),
@@ -362,6 +531,18 @@ func registerActions() {
 		Title:           i18n.Text("General Settings…"),
 		ExecuteCallback: func(_ *unison.Action, _ any) { ShowGeneralSettings() },
 	})
+	globalSearchAction = registerKeyBindableAction("global_search", &unison.Action{
+		ID:              GlobalSearchItemID,
+		Title:           i18n.Text("Global Search…"),
+		KeyBinding:      unison.KeyBinding{KeyCode: unison.KeyF, Modifiers: unison.ShiftModifier | unison.OSMenuCmdModifier()},
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowGlobalSearch() },
+	})
+	hostSessionAction = registerKeyBindableAction("session.host", &unison.Action{
+		ID:              HostSessionItemID,
+		Title:           i18n.Text("Host Session…"),
+		EnabledCallback: func(_ *unison.Action, _ any) bool { return activeSession == nil },
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowHostSessionDialog() },
+	})
 	increaseEquipmentLevelAction = registerKeyBindableAction("inc.eqp.lvl", &unison.Action{
 		ID:              IncrementEquipmentLevelItemID,
 		Title:           i18n.Text("Increase Equipment Level"),
@@ -396,11 +577,36 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	languageSettingsAction = registerKeyBindableAction("settings.languages", &unison.Action{
+		ID:              LanguageSettingsItemID,
+		Title:           i18n.Text("Languages…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowLanguageSettings() },
+	})
+	legacyConversionAction = registerKeyBindableAction("legacy_conversion", &unison.Action{
+		ID:              LegacyConversionItemID,
+		Title:           i18n.Text("Convert Older Files in Folder…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowLegacyConversion() },
+	})
+	libraryDuplicatesAction = registerKeyBindableAction("library_duplicates", &unison.Action{
+		ID:              LibraryDuplicatesItemID,
+		Title:           i18n.Text("Find Library Duplicates…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowLibraryDuplicates() },
+	})
+	manageWorkspacesAction = registerKeyBindableAction("workspaces.manage", &unison.Action{
+		ID:              ManageWorkspacesItemID,
+		Title:           i18n.Text("Manage Workspaces…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowManageWorkspaces() },
+	})
 	menuKeySettingsAction = registerKeyBindableAction("settings.keys", &unison.Action{
 		ID:              MenuKeySettingsItemID,
 		Title:           i18n.Text("Menu Keys…"),
 		ExecuteCallback: func(_ *unison.Action, _ any) { ShowMenuKeySettings() },
 	})
+	mergeSheetsAction = registerKeyBindableAction("merge_sheets", &unison.Action{
+		ID:              MergeSheetsItemID,
+		Title:           i18n.Text("Merge Sheets…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowSheetMerge() },
+	})
 	moveToCarriedEquipmentAction = registerKeyBindableAction("move.to.carried", &unison.Action{
 		ID:              MoveToCarriedEquipmentItemID,
 		Title:           i18n.Text("Move to Carried Equipment"),
@@ -427,6 +633,11 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	newCharacterFromGeneratorAction = registerKeyBindableAction("new.char.generator", &unison.Action{
+		ID:              NewSheetFromGeneratorItemID,
+		Title:           i18n.Text("New from Generator…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { NewSheetFromGenerator() },
+	})
 	newCharacterSheetAction = registerKeyBindableAction("new.char.sheet", &unison.Action{
 		ID:         NewSheetItemID,
 		Title:      i18n.Text("New Character Sheet"),
@@ -450,14 +661,20 @@ func registerActions() {
 			DisplayNewDockable(NewLootSheet("untitled"+gurps.LootExt, gurps.NewLoot()))
 		},
 	})
-	// TODO: Re-enable Campaign files
-	// newCampaignAction = registerKeyBindableAction("new.campaign", &unison.Action{
-	// 	ID:    NewCampaignItemID,
-	// 	Title: i18n.Text("New Campaign"),
-	// 	ExecuteCallback: func(_ *unison.Action, _ any) {
-	// 		DisplayNewDockable(NewCampaign("untitled"+gurps.CampaignExt, gurps.NewCampaign()))
-	// 	},
-	// })
+	newCampaignAction = registerKeyBindableAction("new.campaign", &unison.Action{
+		ID:    NewCampaignItemID,
+		Title: i18n.Text("New Campaign"),
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			DisplayNewDockable(NewCampaign("untitled"+gurps.CampaignExt, gurps.NewCampaign()))
+		},
+	})
+	newVehicleSheetAction = registerKeyBindableAction("new.vehicle", &unison.Action{
+		ID:    NewVehicleSheetItemID,
+		Title: i18n.Text("New Vehicle Sheet"),
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			DisplayNewDockable(NewVehicleSheet("untitled"+gurps.VehicleExt, gurps.NewVehicle()))
+		},
+	})
 	newEquipmentContainerModifierAction = registerKeyBindableAction("new.eqm.container", &unison.Action{
 		ID:              NewEquipmentContainerModifierItemID,
 		Title:           i18n.Text("New Equipment Modifier Container"),
@@ -505,6 +722,11 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	newNPCFromArchetypeAction = registerKeyBindableAction("new.npc.archetype", &unison.Action{
+		ID:              NewNPCFromArchetypeItemID,
+		Title:           i18n.Text("New NPC from Archetype…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { NewNPCFromArchetype() },
+	})
 	newNoteAction = registerKeyBindableAction("new.not", &unison.Action{
 		ID:              NewNoteItemID,
 		Title:           i18n.Text("New Note"),
@@ -645,6 +867,20 @@ func registerActions() {
 			DisplayNewDockable(NewTraitTableDockable("Traits"+gurps.TraitsExt, nil))
 		},
 	})
+	importFoundryAction = registerKeyBindableAction("import.foundry", &unison.Action{
+		ID:    ImportFromFoundryItemID,
+		Title: i18n.Text("Foundry VTT (GURPS Game Aid)…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			ImportSheet(importer.ImportFoundryFile, importer.FoundryExtension)
+		},
+	})
+	importGCA5Action = registerKeyBindableAction("import.gca5", &unison.Action{
+		ID:    ImportFromGCA5ItemID,
+		Title: i18n.Text("GURPS Character Assistant 5…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			ImportSheet(importer.ImportGCA5File, importer.GCA5Extension)
+		},
+	})
 	openAction = registerKeyBindableAction("open", &unison.Action{
 		ID:         OpenItemID,
 		Title:      i18n.Text("Open…"),
@@ -686,6 +922,12 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	optimizeSkillLevelsAction = registerKeyBindableAction("optimize.sl", &unison.Action{
+		ID:              OptimizeSkillLevelsItemID,
+		Title:           i18n.Text("Optimize Skill Levels…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	pageRefMappingsAction = registerKeyBindableAction("settings.pagerefs", &unison.Action{
 		ID:              PageRefMappingsItemID,
 		Title:           i18n.Text("Page Reference Mappings…"),
@@ -711,6 +953,16 @@ func registerActions() {
 			}
 		},
 	})
+	perSheetRulesetAction = registerKeyBindableAction("settings.ruleset.per_sheet", &unison.Action{
+		ID:              PerSheetRulesetItemID,
+		Title:           i18n.Text("Ruleset…"),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if s := ActiveSheet(); s != nil {
+				ShowRuleset(s)
+			}
+		},
+	})
 	perSheetSettingsAction = registerKeyBindableAction("settings.sheet.per_sheet", &unison.Action{
 		ID:              PerSheetSettingsItemID,
 		Title:           i18n.Text("Sheet Settings…"),
@@ -751,6 +1003,18 @@ func registerActions() {
 			}
 		},
 	})
+	reloadWeaponAction = registerKeyBindableAction("reload.weapon", &unison.Action{
+		ID:              ReloadWeaponItemID,
+		Title:           i18n.Text("Reload Weapon"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
+	reviewSourceChangesAction = registerKeyBindableAction("review.sync", &unison.Action{
+		ID:              ReviewSourceChangesItemID,
+		Title:           i18n.Text("Review Changes from Source…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	saveAction = registerKeyBindableAction("save", &unison.Action{
 		ID:              SaveItemID,
 		Title:           i18n.Text("Save"),
@@ -765,6 +1029,11 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	saveWorkspaceAction = registerKeyBindableAction("workspaces.save", &unison.Action{
+		ID:              SaveWorkspaceItemID,
+		Title:           i18n.Text("Save Workspace…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { SaveWorkspace() },
+	})
 	scale25Action = registerKeyBindableAction("scale.25", &unison.Action{
 		ID:              Scale25ItemID,
 		Title:           i18n.Text("25% Scale"),
@@ -849,6 +1118,22 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	scriptConsoleAction = registerKeyBindableAction("script_console", &unison.Action{
+		ID:              ScriptConsoleItemID,
+		Title:           i18n.Text("Script Console"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowScriptConsole() },
+	})
+	sheetThemesAction = registerKeyBindableAction("settings.sheet_themes", &unison.Action{
+		ID:              SheetThemesItemID,
+		Title:           i18n.Text("Sheet Themes…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowSheetThemeEditor() },
+	})
+	spellPrereqGraphAction = registerKeyBindableAction("spell_prereq_graph", &unison.Action{
+		ID:              SpellPrereqGraphItemID,
+		Title:           i18n.Text("Spell Prerequisite Graph"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	syncWithSourceAction = registerKeyBindableAction("clear.sync", &unison.Action{
 		ID:              SyncWithSourceItemID,
 		Title:           i18n.Text("Sync with Source"),
@@ -862,6 +1147,11 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	tagManagerAction = registerKeyBindableAction("tag_manager", &unison.Action{
+		ID:              TagManagerItemID,
+		Title:           i18n.Text("Tag Manager"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowTagManager() },
+	})
 	toggleStateAction = registerKeyBindableAction("toggle", &unison.Action{
 		ID:              ToggleStateItemID,
 		Title:           i18n.Text("Toggle State"),
@@ -891,6 +1181,21 @@ func registerActions() {
 			}
 		},
 	})
+	undoHistoryAction = registerKeyBindableAction("undo_history", &unison.Action{
+		ID:    UndoHistoryItemID,
+		Title: i18n.Text("Undo History"),
+		EnabledCallback: func(_ *unison.Action, _ any) bool {
+			target, ok := ActiveDockable().(undoHistoryTarget)
+			return ok && target.UndoManager() != nil
+		},
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowUndoHistory() },
+	})
+	useAmmoAction = registerKeyBindableAction("use.ammo", &unison.Action{
+		ID:              UseAmmoItemID,
+		Title:           i18n.Text("Use Ammo"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 
 	// Actions that may not be assigned a key binding
 	checkForAppUpdatesAction = &unison.Action{
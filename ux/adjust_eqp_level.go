@@ -76,7 +76,7 @@ func adjustEquipmentLevel(owner Rebuildable, table *unison.Table[*Node[*gurps.Eq
 			} else {
 				name = increaseEquipmentLevelAction.Title
 			}
-			mgr.Add(&unison.UndoEdit[*adjustEquipmentLevelList]{
+			addUndo(mgr, &unison.UndoEdit[*adjustEquipmentLevelList]{
 				ID:         unison.NextUndoID(),
 				EditName:   name,
 				UndoFunc:   func(edit *unison.UndoEdit[*adjustEquipmentLevelList]) { edit.BeforeData.Apply() },
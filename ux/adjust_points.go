@@ -34,19 +34,27 @@ func (a *adjustRawPointsList[T]) Finish() {
 }
 
 type rawPointsAdjuster[T gurps.NodeTypes] struct {
-	Target gurps.RawPointsAdjuster[T]
-	Points fxp.Int
+	Target           gurps.RawPointsAdjuster[T]
+	Points           fxp.Int
+	StudyPointsSpent fxp.Int
 }
 
 func newRawPointsAdjuster[T gurps.NodeTypes](target gurps.RawPointsAdjuster[T]) *rawPointsAdjuster[T] {
-	return &rawPointsAdjuster[T]{
+	a := &rawPointsAdjuster[T]{
 		Target: target,
 		Points: target.RawPoints(),
 	}
+	if tracker, ok := target.(gurps.StudyTracker); ok {
+		a.StudyPointsSpent = tracker.StudyProgress().Spent
+	}
+	return a
 }
 
 func (a *rawPointsAdjuster[T]) Apply() {
 	a.Target.SetRawPoints(a.Points)
+	if tracker, ok := a.Target.(gurps.StudyTracker); ok {
+		tracker.SetStudyPointsSpent(a.StudyPointsSpent)
+	}
 }
 
 func canAdjustRawPoints[T gurps.NodeTypes](table *unison.Table[*Node[T]], increment bool) bool {
@@ -63,10 +71,11 @@ func canAdjustRawPoints[T gurps.NodeTypes](table *unison.Table[*Node[T]], increm
 func adjustRawPoints[T gurps.NodeTypes](owner Rebuildable, table *unison.Table[*Node[T]], increment bool) {
 	before := &adjustRawPointsList[T]{Owner: owner}
 	after := &adjustRawPointsList[T]{Owner: owner}
+	refused := false
 	for _, row := range table.SelectedRows(false) {
 		if provider, ok := any(row.Data()).(gurps.RawPointsAdjuster[T]); ok {
 			if increment || provider.RawPoints() > 0 {
-				before.List = append(before.List, newRawPointsAdjuster(provider))
+				adjuster := newRawPointsAdjuster(provider)
 				rawPts := provider.RawPoints()
 				pts := rawPts.Floor()
 				if increment {
@@ -74,11 +83,21 @@ func adjustRawPoints[T gurps.NodeTypes](owner Rebuildable, table *unison.Table[*
 				} else if rawPts == pts {
 					pts -= fxp.One
 				}
-				provider.SetRawPoints(pts.Max(0))
+				pts = pts.Max(0)
+				if tracker, isTracker := provider.(gurps.StudyTracker); isTracker &&
+					!gurps.ChargeStudyPoints(gurps.EntityFromNode(provider), tracker, pts-rawPts) {
+					refused = true
+					continue
+				}
+				before.List = append(before.List, adjuster)
+				provider.SetRawPoints(pts)
 				after.List = append(after.List, newRawPointsAdjuster(provider))
 			}
 		}
 	}
+	if refused {
+		warnStudyPointsNeeded()
+	}
 	if len(before.List) > 0 {
 		if mgr := unison.UndoManagerFor(table); mgr != nil {
 			var name string
@@ -87,7 +106,7 @@ func adjustRawPoints[T gurps.NodeTypes](owner Rebuildable, table *unison.Table[*
 			} else {
 				name = i18n.Text("Decrement Points")
 			}
-			mgr.Add(&unison.UndoEdit[*adjustRawPointsList[T]]{
+			addUndo(mgr, &unison.UndoEdit[*adjustRawPointsList[T]]{
 				ID:         unison.NextUndoID(),
 				EditName:   name,
 				UndoFunc:   func(edit *unison.UndoEdit[*adjustRawPointsList[T]]) { edit.BeforeData.Apply() },
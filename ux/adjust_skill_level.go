@@ -28,19 +28,30 @@ func canAdjustSkillLevel[T gurps.NodeTypes](table *unison.Table[*Node[T]], incre
 func adjustSkillLevel[T gurps.NodeTypes](owner Rebuildable, table *unison.Table[*Node[T]], increment bool) {
 	before := &adjustRawPointsList[T]{Owner: owner}
 	after := &adjustRawPointsList[T]{Owner: owner}
+	refused := false
 	for _, row := range table.SelectedRows(false) {
 		if provider, ok := any(row.Data()).(gurps.SkillAdjustmentProvider[T]); ok {
 			if increment || provider.RawPoints() > 0 {
-				before.List = append(before.List, newRawPointsAdjuster(provider))
+				adjuster := newRawPointsAdjuster(provider)
 				if increment {
 					provider.IncrementSkillLevel()
 				} else {
 					provider.DecrementSkillLevel()
 				}
+				if tracker, isTracker := provider.(gurps.StudyTracker); isTracker &&
+					!gurps.ChargeStudyPoints(gurps.EntityFromNode(provider), tracker, provider.RawPoints()-adjuster.Points) {
+					adjuster.Apply()
+					refused = true
+					continue
+				}
+				before.List = append(before.List, adjuster)
 				after.List = append(after.List, newRawPointsAdjuster(provider))
 			}
 		}
 	}
+	if refused {
+		warnStudyPointsNeeded()
+	}
 	if len(before.List) > 0 {
 		if mgr := unison.UndoManagerFor(table); mgr != nil {
 			var name string
@@ -49,7 +60,7 @@ func adjustSkillLevel[T gurps.NodeTypes](owner Rebuildable, table *unison.Table[
 			} else {
 				name = decreaseSkillLevelAction.Title
 			}
-			mgr.Add(&unison.UndoEdit[*adjustRawPointsList[T]]{
+			addUndo(mgr, &unison.UndoEdit[*adjustRawPointsList[T]]{
 				ID:         unison.NextUndoID(),
 				EditName:   name,
 				UndoFunc:   func(edit *unison.UndoEdit[*adjustRawPointsList[T]]) { edit.BeforeData.Apply() },
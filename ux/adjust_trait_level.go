@@ -36,19 +36,22 @@ func (a *adjustTraitLevelList) Finish() {
 }
 
 type traitLevelAdjuster struct {
-	Target *gurps.Trait
-	Levels fxp.Int
+	Target           *gurps.Trait
+	Levels           fxp.Int
+	StudyPointsSpent fxp.Int
 }
 
 func newTraitLevelAdjuster(target *gurps.Trait) *traitLevelAdjuster {
 	return &traitLevelAdjuster{
-		Target: target,
-		Levels: target.Levels,
+		Target:           target,
+		Levels:           target.Levels,
+		StudyPointsSpent: target.StudyPointsSpent,
 	}
 }
 
 func (a *traitLevelAdjuster) Apply() {
 	a.Target.Levels = a.Levels
+	a.Target.StudyPointsSpent = a.StudyPointsSpent
 }
 
 func canAdjustTraitLevel(table *unison.Table[*Node[*gurps.Trait]], increment bool) bool {
@@ -65,10 +68,12 @@ func canAdjustTraitLevel(table *unison.Table[*Node[*gurps.Trait]], increment boo
 func adjustTraitLevel(owner Rebuildable, table *unison.Table[*Node[*gurps.Trait]], increment bool) {
 	before := &adjustTraitLevelList{Owner: owner}
 	after := &adjustTraitLevelList{Owner: owner}
+	refused := false
 	for _, row := range table.SelectedRows(false) {
 		if t := row.Data(); t != nil && t.IsLeveled() {
 			if increment || t.Levels > 0 {
-				before.List = append(before.List, newTraitLevelAdjuster(t))
+				adjuster := newTraitLevelAdjuster(t)
+				points := t.AdjustedPoints()
 				original := t.Levels
 				levels := original.Floor()
 				if increment {
@@ -77,10 +82,19 @@ func adjustTraitLevel(owner Rebuildable, table *unison.Table[*Node[*gurps.Trait]
 					levels -= fxp.One
 				}
 				t.Levels = levels.Max(0)
+				if !gurps.ChargeStudyPoints(gurps.EntityFromNode(t), t, t.AdjustedPoints()-points) {
+					adjuster.Apply()
+					refused = true
+					continue
+				}
+				before.List = append(before.List, adjuster)
 				after.List = append(after.List, newTraitLevelAdjuster(t))
 			}
 		}
 	}
+	if refused {
+		warnStudyPointsNeeded()
+	}
 	if len(before.List) > 0 {
 		if mgr := unison.UndoManagerFor(table); mgr != nil {
 			var name string
@@ -89,7 +103,7 @@ func adjustTraitLevel(owner Rebuildable, table *unison.Table[*Node[*gurps.Trait]
 			} else {
 				name = i18n.Text("Decrement Level")
 			}
-			mgr.Add(&unison.UndoEdit[*adjustTraitLevelList]{
+			addUndo(mgr, &unison.UndoEdit[*adjustTraitLevelList]{
 				ID:         unison.NextUndoID(),
 				EditName:   name,
 				UndoFunc:   func(edit adjustTraitLevelListUndoEdit) { edit.BeforeData.Apply() },
@@ -0,0 +1,145 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/restapi"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/unison"
+)
+
+// Only accessed from the UI thread.
+var (
+	apiServer          *restapi.Server
+	apiServerPort      int
+	apiServerSnapshots = make(map[*gurps.Entity]*restapi.Snapshot)
+)
+
+// UpdateAPIServer starts, stops, or restarts the local API server so that it matches the current settings.
+func UpdateAPIServer() {
+	gs := gurps.GlobalSettings().General
+	port := apiServerPortFromSettings()
+	if apiServer != nil {
+		if gs.APIServerEnabled && apiServer.Token == gs.APIServerToken && apiServerPort == port {
+			return
+		}
+		// Requests that are waiting on the UI thread can't complete while we're blocking it, so don't wait long.
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if err := apiServer.Stop(ctx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			errs.Log(err)
+		}
+		cancel()
+		apiServer = nil
+		clear(apiServerSnapshots)
+		slog.Info("stopped local API server")
+	}
+	if !gs.APIServerEnabled || gs.APIServerToken == "" {
+		return
+	}
+	server := &restapi.Server{
+		Token:   gs.APIServerToken,
+		Invoke:  invokeTaskAndWait,
+		Sheets:  openSheetEntities,
+		Changed: apiServerChangedEntity,
+		Rolled:  RecordRoll,
+	}
+	if err := server.Start(port); err != nil {
+		errs.Log(err)
+		return
+	}
+	apiServer = server
+	apiServerPort = port
+	PublishAPIServerChanges(nil)
+	slog.Info("started local API server", "port", port)
+}
+
+// installAPIServerRollFeed arranges for every roll to be published to the clients connected to the local API server's
+// event feed, such as the Foundry VTT companion module.
+func installAPIServerRollFeed() {
+	dice.GlobalHistory().AddListener(func(r *dice.Result) {
+		if apiServer != nil {
+			apiServer.Publish(restapi.NewRollEvent(r))
+		}
+	})
+}
+
+func apiServerPortFromSettings() int {
+	if port := gurps.GlobalSettings().General.APIServerPort; port > 0 {
+		return port
+	}
+	return restapi.DefaultPort
+}
+
+func invokeTaskAndWait(f func()) {
+	done := make(chan struct{})
+	unison.InvokeTask(func() {
+		defer close(done)
+		f()
+	})
+	<-done
+}
+
+func openSheetEntities() []*gurps.Entity {
+	sheets := OpenSheets(nil)
+	list := make([]*gurps.Entity, 0, len(sheets))
+	for _, sheet := range sheets {
+		list = append(list, sheet.Entity())
+	}
+	return list
+}
+
+func apiServerChangedEntity(entity *gurps.Entity) {
+	for _, sheet := range OpenSheets(nil) {
+		if sheet.Entity() == entity {
+			sheet.Rebuild(true)
+			MarkModified(sheet)
+			return
+		}
+	}
+}
+
+// PublishAPIServerChanges sends events describing the changes made to the entity since the last call, along with any
+// sheets that have been opened or closed, to the clients connected to the local API server's event feed. Pass nil to
+// only look for sheets that have been opened or closed.
+func PublishAPIServerChanges(changed *gurps.Entity) {
+	if apiServer == nil {
+		return
+	}
+	var events []*restapi.Event
+	open := make(map[*gurps.Entity]bool)
+	for _, entity := range openSheetEntities() {
+		open[entity] = true
+		prev, exists := apiServerSnapshots[entity]
+		switch {
+		case !exists:
+			snapshot := restapi.TakeSnapshot(entity)
+			apiServerSnapshots[entity] = snapshot
+			events = append(events, snapshot.OpenedEvent())
+		case entity == changed:
+			next := restapi.TakeSnapshot(entity)
+			apiServerSnapshots[entity] = next
+			events = append(events, prev.Changes(next)...)
+		}
+	}
+	for entity, snapshot := range apiServerSnapshots {
+		if !open[entity] {
+			delete(apiServerSnapshots, entity)
+			events = append(events, snapshot.ClosedEvent())
+		}
+	}
+	apiServer.Publish(events...)
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/unison"
+)
+
+type applyEquipmentModifierList struct {
+	Owner Rebuildable
+	List  []*equipmentModifiersAdjuster
+}
+
+func (a *applyEquipmentModifierList) Apply() {
+	for _, one := range a.List {
+		one.Apply()
+	}
+	a.Finish()
+}
+
+func (a *applyEquipmentModifierList) Finish() {
+	gurps.EntityFromNode(a.List[0].Target).Recalculate()
+	MarkModified(a.Owner)
+}
+
+type equipmentModifiersAdjuster struct {
+	Target    *gurps.Equipment
+	Modifiers []*gurps.EquipmentModifier
+}
+
+func newEquipmentModifiersAdjuster(target *gurps.Equipment) *equipmentModifiersAdjuster {
+	return &equipmentModifiersAdjuster{
+		Target:    target,
+		Modifiers: slices.Clone(target.Modifiers),
+	}
+}
+
+func (a *equipmentModifiersAdjuster) Apply() {
+	a.Target.Modifiers = slices.Clone(a.Modifiers)
+}
+
+// modifierLibraryFile is a modifier library file that modifiers can be chosen from.
+type modifierLibraryFile struct {
+	title string
+	path  string
+}
+
+func (f *modifierLibraryFile) String() string {
+	return f.title
+}
+
+// equipmentModifierChoice is a modifier, or a container of modifiers to be applied together, that can be chosen from
+// an equipment modifier library file.
+type equipmentModifierChoice struct {
+	modifier *gurps.EquipmentModifier
+	depth    int
+}
+
+func (c *equipmentModifierChoice) String() string {
+	return strings.Repeat("    ", c.depth) + c.modifier.String()
+}
+
+func canApplyEquipmentModifier(table *unison.Table[*Node[*gurps.Equipment]]) bool {
+	return table.HasSelection()
+}
+
+func applyEquipmentModifier(owner Rebuildable, table *unison.Table[*Node[*gurps.Equipment]]) {
+	var equipment []*gurps.Equipment
+	for _, row := range table.SelectedRows(false) {
+		if eqp := row.Data(); eqp != nil {
+			equipment = append(equipment, eqp)
+		}
+	}
+	if len(equipment) == 0 {
+		return
+	}
+	from, modifier := promptForEquipmentModifier(equipment)
+	if modifier == nil {
+		return
+	}
+	before := &applyEquipmentModifierList{Owner: owner}
+	after := &applyEquipmentModifierList{Owner: owner}
+	for _, eqp := range equipment {
+		before.List = append(before.List, newEquipmentModifiersAdjuster(eqp))
+	}
+	gurps.ApplyEquipmentModifier(equipment, from, modifier)
+	for _, eqp := range equipment {
+		after.List = append(after.List, newEquipmentModifiersAdjuster(eqp))
+	}
+	if mgr := unison.UndoManagerFor(table); mgr != nil {
+		addUndo(mgr, &unison.UndoEdit[*applyEquipmentModifierList]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Apply Equipment Modifier"),
+			UndoFunc:   func(edit *unison.UndoEdit[*applyEquipmentModifierList]) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit *unison.UndoEdit[*applyEquipmentModifierList]) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	after.Finish()
+}
+
+func promptForEquipmentModifier(equipment []*gurps.Equipment) (gurps.LibraryFile, *gurps.EquipmentModifier) {
+	files := availableModifierFiles(gurps.EquipmentModifiersExt)
+	if len(files) == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("No equipment modifiers are available"),
+			i18n.Text("None of the libraries contain an equipment modifiers file."))
+		return gurps.LibraryFile{}, nil
+	}
+	defUnits := gurps.SheetSettingsFor(gurps.EntityFromNode(equipment[0])).DefaultWeightUnits
+	var choice *equipmentModifierChoice
+	var dialog *unison.Dialog
+
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	var valueField, weightField *NonEditableField
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Library"), false))
+	filePopup := unison.NewPopupMenu[*modifierLibraryFile]()
+	for _, one := range files {
+		filePopup.AddItem(one)
+	}
+	panel.AddChild(filePopup)
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Modifier"), false))
+	modifierPopup := unison.NewPopupMenu[*equipmentModifierChoice]()
+	modifierPopup.Tooltip = newWrappedTooltip(i18n.Text(
+		"Choosing a container applies all of the modifiers within it as a package"))
+	modifierPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[*equipmentModifierChoice]) {
+		choice, _ = popup.Selected()
+		valueField.Sync()
+		weightField.Sync()
+		if dialog != nil {
+			dialog.Button(unison.ModalResponseOK).SetEnabled(choice != nil)
+		}
+	}
+	panel.AddChild(modifierPopup)
+
+	filePopup.SelectionChangedCallback = func(popup *unison.PopupMenu[*modifierLibraryFile]) {
+		modifierPopup.RemoveAllItems()
+		if file, ok := popup.Selected(); ok {
+			if modifiers, err := gurps.NewEquipmentModifiersFromFile(os.DirFS(filepath.Dir(file.path)),
+				filepath.Base(file.path)); err != nil {
+				Workspace.ErrorHandler(i18n.Text("Unable to load equipment modifiers"), err)
+			} else {
+				addEquipmentModifierChoices(modifierPopup, modifiers, 0)
+			}
+		}
+		if modifierPopup.ItemCount() != 0 {
+			modifierPopup.SelectIndex(0)
+		} else {
+			modifierPopup.SelectionChangedCallback(modifierPopup)
+		}
+	}
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Value"), false))
+	valueField = NewNonEditableField(func(field *NonEditableField) {
+		if choice == nil {
+			field.SetTitle("")
+		} else {
+			preview := gurps.PreviewEquipmentModifier(equipment, choice.modifier, defUnits)
+			field.SetTitle(fmt.Sprintf(i18n.Text("$%s → $%s"), preview.ValueBefore.Comma(),
+				preview.ValueAfter.Comma()))
+		}
+		field.MarkForLayoutAndRedraw()
+	})
+	panel.AddChild(valueField)
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Weight"), false))
+	weightField = NewNonEditableField(func(field *NonEditableField) {
+		if choice == nil {
+			field.SetTitle("")
+		} else {
+			preview := gurps.PreviewEquipmentModifier(equipment, choice.modifier, defUnits)
+			field.SetTitle(fmt.Sprintf(i18n.Text("%s → %s"), defUnits.Format(preview.WeightBefore),
+				defUnits.Format(preview.WeightAfter)))
+		}
+		field.MarkForLayoutAndRedraw()
+	})
+	panel.AddChild(weightField)
+
+	note := unison.NewLabel()
+	note.SetTitle(fmt.Sprintf(i18n.Text("Totals for the %d selected items, not including their contents"),
+		len(equipment)))
+	note.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	panel.AddChild(note)
+
+	filePopup.SelectIndex(0)
+
+	var err error
+	if dialog, err = unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()}); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create equipment modifier dialog"), err)
+		return gurps.LibraryFile{}, nil
+	}
+	dialog.Button(unison.ModalResponseOK).SetEnabled(choice != nil)
+	if dialog.RunModal() != unison.ModalResponseOK || choice == nil {
+		return gurps.LibraryFile{}, nil
+	}
+	file, _ := filePopup.Selected()
+	return libraryFileForPath(file.path), choice.modifier
+}
+
+func addEquipmentModifierChoices(popup *unison.PopupMenu[*equipmentModifierChoice], modifiers []*gurps.EquipmentModifier, depth int) {
+	for _, one := range modifiers {
+		popup.AddItem(&equipmentModifierChoice{modifier: one, depth: depth})
+		if one.Container() {
+			addEquipmentModifierChoices(popup, one.Children, depth+1)
+		}
+	}
+}
+
+// availableModifierFiles returns the modifier files with the given extension found within the libraries.
+func availableModifierFiles(ext string) []*modifierLibraryFile {
+	var list []*modifierLibraryFile
+	for _, lib := range gurps.GlobalSettings().Libraries().List() {
+		root := lib.Path()
+		_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error { //nolint:errcheck // Intentionally ignored the error result
+			if err != nil {
+				return nil
+			}
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ext) {
+				title := xfilepath.TrimExtension(d.Name())
+				if rel, relErr := filepath.Rel(root, p); relErr == nil {
+					title = xfilepath.TrimExtension(filepath.ToSlash(rel))
+				}
+				list = append(list, &modifierLibraryFile{
+					title: lib.Title + ": " + title,
+					path:  p,
+				})
+			}
+			return nil
+		})
+	}
+	return list
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+const maxTraitModifierSearchResults = 200
+
+type applyTraitModifierList struct {
+	Owner Rebuildable
+	List  []*traitModifiersAdjuster
+}
+
+func (a *applyTraitModifierList) Apply() {
+	for _, one := range a.List {
+		one.Apply()
+	}
+	a.Finish()
+}
+
+func (a *applyTraitModifierList) Finish() {
+	gurps.EntityFromNode(a.List[0].Target).Recalculate()
+	MarkModified(a.Owner)
+}
+
+type traitModifiersAdjuster struct {
+	Target    *gurps.Trait
+	Modifiers []*gurps.TraitModifier
+}
+
+func newTraitModifiersAdjuster(target *gurps.Trait) *traitModifiersAdjuster {
+	return &traitModifiersAdjuster{
+		Target:    target,
+		Modifiers: slices.Clone(target.Modifiers),
+	}
+}
+
+func (a *traitModifiersAdjuster) Apply() {
+	a.Target.Modifiers = slices.Clone(a.Modifiers)
+}
+
+// traitModifierChoice is a trait modifier, or a container of modifiers to be applied together, found by a search.
+type traitModifierChoice struct {
+	match *gurps.TraitModifierMatch
+}
+
+func (c *traitModifierChoice) String() string {
+	return c.match.Modifier.String() + " — " + c.match.Source
+}
+
+func canApplyTraitModifier(table *unison.Table[*Node[*gurps.Trait]]) bool {
+	return table.HasSelection()
+}
+
+func applyTraitModifier(owner Rebuildable, table *unison.Table[*Node[*gurps.Trait]]) {
+	var traits []*gurps.Trait
+	for _, row := range table.SelectedRows(false) {
+		if t := row.Data(); t != nil {
+			traits = append(traits, t)
+		}
+	}
+	if len(traits) == 0 {
+		return
+	}
+	match := promptForTraitModifier(traits)
+	if match == nil {
+		return
+	}
+	before := &applyTraitModifierList{Owner: owner}
+	after := &applyTraitModifierList{Owner: owner}
+	for _, t := range traits {
+		before.List = append(before.List, newTraitModifiersAdjuster(t))
+	}
+	gurps.ApplyTraitModifier(traits, match.From, match.Modifier)
+	for _, t := range traits {
+		after.List = append(after.List, newTraitModifiersAdjuster(t))
+	}
+	if mgr := unison.UndoManagerFor(table); mgr != nil {
+		addUndo(mgr, &unison.UndoEdit[*applyTraitModifierList]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Apply Trait Modifier"),
+			UndoFunc:   func(edit *unison.UndoEdit[*applyTraitModifierList]) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit *unison.UndoEdit[*applyTraitModifierList]) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	after.Finish()
+}
+
+func promptForTraitModifier(traits []*gurps.Trait) *gurps.TraitModifierMatch {
+	candidates := availableTraitModifiers()
+	if len(candidates) == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("No trait modifiers are available"),
+			i18n.Text("None of the libraries contain a trait modifiers file."))
+		return nil
+	}
+	var dialog *unison.Dialog
+	list := unison.NewList[*traitModifierChoice]()
+	list.SetAllowMultipleSelection(false)
+	list.NewSelectionCallback = func() {
+		if dialog != nil {
+			dialog.Button(unison.ModalResponseOK).SetEnabled(list.Selection.Count() != 0)
+		}
+	}
+	list.DoubleClickCallback = func() {
+		if dialog != nil && list.Selection.Count() != 0 {
+			dialog.Button(unison.ModalResponseOK).Click()
+		}
+	}
+	search := func(text string) {
+		list.Clear()
+		for _, one := range gurps.SearchTraitModifiers(traits, candidates, text, maxTraitModifierSearchResults) {
+			list.Append(&traitModifierChoice{match: one})
+		}
+		if list.Count() != 0 {
+			list.Select(false, 0)
+		} else if list.NewSelectionCallback != nil {
+			list.NewSelectionCallback()
+		}
+		list.MarkForLayoutRecursivelyUpward()
+		list.MarkForRedraw()
+	}
+	field := NewSearchField(i18n.Text("Search trait modifiers"), func(_, after *unison.FieldState) { search(after.Text) })
+	field.KeyDownCallback = func(keyCode unison.KeyCode, mod unison.Modifiers, repeat bool) bool {
+		if mod == 0 && (keyCode == unison.KeyUp || keyCode == unison.KeyDown) && list.Count() != 0 {
+			index := list.Selection.FirstSet()
+			if keyCode == unison.KeyUp {
+				index = max(index-1, 0)
+			} else {
+				index = min(index+1, list.Count()-1)
+			}
+			list.Select(false, index)
+			list.ScrollRectIntoView(list.RowRect(index))
+			return true
+		}
+		return field.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{}, geom.NewUniformInsets(1), false))
+	scroll.SetContent(list, behavior.Fill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: geom.Size{Width: 400, Height: 300},
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(field)
+	panel.AddChild(scroll)
+	note := unison.NewLabel()
+	note.SetTitle(i18n.Text("Choosing a container applies all of the modifiers within it as a package"))
+	panel.AddChild(note)
+	search("")
+
+	var err error
+	if dialog, err = unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()}); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create trait modifier dialog"), err)
+		return nil
+	}
+	dialog.Button(unison.ModalResponseOK).SetEnabled(list.Selection.Count() != 0)
+	field.RequestFocus()
+	if dialog.RunModal() != unison.ModalResponseOK || list.Selection.Count() == 0 {
+		return nil
+	}
+	return list.DataAtIndex(list.Selection.FirstSet()).match
+}
+
+// availableTraitModifiers returns the trait modifiers found within the libraries.
+func availableTraitModifiers() []*gurps.TraitModifierMatch {
+	var list []*gurps.TraitModifierMatch
+	for _, file := range availableModifierFiles(gurps.TraitModifiersExt) {
+		modifiers, err := gurps.NewTraitModifiersFromFile(os.DirFS(filepath.Dir(file.path)), filepath.Base(file.path))
+		if err != nil {
+			errs.Log(err, "path", file.path)
+			continue
+		}
+		from := libraryFileForPath(file.path)
+		gurps.Traverse(func(mod *gurps.TraitModifier) bool {
+			list = append(list, &gurps.TraitModifierMatch{
+				Modifier: mod,
+				From:     from,
+				Source:   file.title,
+			})
+			return false
+		}, false, false, modifiers...)
+	}
+	return list
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
 	"github.com/richardwilkes/unison/enums/paintstyle"
 )
 
@@ -113,7 +114,7 @@ func (p *attrDefSettingsPanel) deleteAttrDef() {
 	undo.BeforeData = p.dockable.defs.Clone()
 	delete(p.dockable.defs.Set, p.def.DefID)
 	undo.AfterData = p.dockable.defs.Clone()
-	p.dockable.UndoManager().Add(undo)
+	addUndo(p.dockable.UndoManager(), undo)
 	p.dockable.MarkModified(nil)
 }
 
@@ -215,6 +216,14 @@ func (p *attrDefSettingsPanel) createContent() *unison.Panel {
 	}
 
 	if p.def.Type == attribute.Pool || p.def.Type == attribute.PoolRef {
+		content.AddChild(unison.NewPanel())
+		trackerCheckBox := NewCheckBox(p.dockable.targetMgr, p.def.KeyPrefix+"tracker",
+			i18n.Text("Also show as a tracker block on the sheet"),
+			func() check.Enum { return check.FromBool(p.def.ShowTracker) },
+			func(state check.Enum) { p.def.ShowTracker = state == check.On })
+		trackerCheckBox.Tooltip = newWrappedTooltip(i18n.Text("When checked, this pool will also be shown in its own tracker block, which is positioned with the pool_trackers key in the block layout"))
+		content.AddChild(trackerCheckBox)
+
 		p.poolPanel = newPoolSettingsPanel(p.dockable, p.def)
 		content.AddChild(p.poolPanel)
 	} else {
@@ -258,18 +258,21 @@ func (a *AttrPanel) rebuild(attrs *gurps.AttributeDefs) {
 							return attr.Current()
 						},
 						func(v fxp.Int) { attr.Damage = (attr.Maximum() - v).Max(0) }, fxp.Min, attr.Maximum(), true)
+					markAsPDFFormField(currentField.Field, attr.AttrID)
 					a.AddChild(currentField)
 
 					a.AddChild(NewPageLabel(i18n.Text("of")))
 
 					if def.Type == attribute.Pool {
-						a.AddChild(NewDecimalPageField(a.targetMgr, a.prefix+attr.AttrID+":max",
+						maxField := NewDecimalPageField(a.targetMgr, a.prefix+attr.AttrID+":max",
 							i18n.Text("Point Pool Maximum"), func() fxp.Int { return attr.Maximum() },
 							func(v fxp.Int) {
 								attr.SetMaximum(v)
 								currentField.SetMinMax(currentField.Min(), v)
 								currentField.Sync()
-							}, fxp.Min, fxp.Max, true))
+							}, fxp.Min, fxp.Max, true)
+						markAsBuildData(a.entity, maxField)
+						a.AddChild(maxField)
 					} else {
 						a.AddChild(NewNonEditablePageFieldEnd(func(field *NonEditablePageField) {
 							field.SetTitle(attr.Maximum().String())
@@ -305,17 +308,32 @@ func (a *AttrPanel) rebuild(attrs *gurps.AttributeDefs) {
 						a.AddChild(field)
 					} else {
 						a.AddChild(a.createPointsField(attr))
+						var field unison.Paneler
 						if def.AllowsDecimal() {
-							a.AddChild(NewDecimalPageField(a.targetMgr, a.prefix+attr.AttrID, def.CombinedName(),
+							field = NewDecimalPageField(a.targetMgr, a.prefix+attr.AttrID, def.CombinedName(),
 								func() fxp.Int { return attr.Maximum() },
-								func(v fxp.Int) { attr.SetMaximum(v) }, fxp.Min, fxp.Max, true))
+								func(v fxp.Int) { attr.SetMaximum(v) }, fxp.Min, fxp.Max, true)
 						} else {
-							a.AddChild(NewIntegerPageField(a.targetMgr, a.prefix+attr.AttrID, def.CombinedName(),
+							field = NewIntegerPageField(a.targetMgr, a.prefix+attr.AttrID, def.CombinedName(),
 								func() int { return fxp.AsInteger[int](attr.Maximum().Floor()) },
-								func(v int) { attr.SetMaximum(fxp.FromInteger(v)) }, fxp.AsInteger[int](fxp.Min.Floor()), fxp.AsInteger[int](fxp.Max.Floor()), false, true))
+								func(v int) { attr.SetMaximum(fxp.FromInteger(v)) }, fxp.AsInteger[int](fxp.Min.Floor()), fxp.AsInteger[int](fxp.Max.Floor()), false, true)
 						}
+						markAsBuildData(a.entity, field)
+						a.AddChild(field)
 					}
-					a.AddChild(NewPageLabel(def.CombinedName()))
+					name := NewPageLabel(def.CombinedName())
+					if !def.AllowsDecimal() {
+						name.Tooltip = newWrappedTooltip(fmt.Sprintf(i18n.Text("Click to roll against %s"), def.CombinedName()))
+						name.MouseDownCallback = func(_ geom.Point, button, clickCount int, _ unison.Modifiers) bool {
+							if button != unison.ButtonLeft || clickCount != 1 {
+								return false
+							}
+							RollSuccessFor(a.entity, def.CombinedName(), attr.Current())
+							return true
+						}
+						name.UpdateCursorCallback = func(_ geom.Point) *unison.Cursor { return unison.PointingCursor() }
+					}
+					a.AddChild(name)
 				}
 			}
 		}
@@ -334,12 +352,26 @@ func (a *AttrPanel) createPointsField(attr *gurps.Attribute) unison.Paneler {
 			MarkForLayoutWithinDockable(f.AsPanel())
 		}
 		if def := attr.AttributeDef(); def != nil {
-			f.Tooltip = newWrappedTooltip(fmt.Sprintf(i18n.Text("Points spent on %s"), def.CombinedName()))
+			tip := fmt.Sprintf(i18n.Text("Points spent on %s"), def.CombinedName())
+			if canPreviewAttributeChange(def) {
+				tip += "\n" + i18n.Text("Click to preview the effects of changing it")
+			}
+			f.Tooltip = newWrappedTooltip(tip)
 		}
 	})
 	field.Font = fonts.PageFieldSecondary
 	field.OnBackgroundInk = dimmedPointsColor
 	field.SetTitle(field.Text.String())
+	if canPreviewAttributeChange(attr.AttributeDef()) {
+		field.MouseDownCallback = func(_ geom.Point, button, clickCount int, _ unison.Modifiers) bool {
+			if button != unison.ButtonLeft || clickCount != 1 {
+				return false
+			}
+			ShowAttributeChangePreview(unison.AncestorOrSelf[Rebuildable](a), a.entity, attr.AttrID)
+			return true
+		}
+		field.UpdateCursorCallback = func(_ geom.Point) *unison.Cursor { return unison.PointingCursor() }
+	}
 	return field
 }
 
@@ -0,0 +1,193 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/attribute"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+type attributeChangeUndoEdit = *unison.UndoEdit[*attributeChangeAdjuster]
+
+type attributeChangeAdjuster struct {
+	Owner      Rebuildable
+	Attribute  *gurps.Attribute
+	Adjustment fxp.Int
+}
+
+func (a *attributeChangeAdjuster) Apply() {
+	a.Attribute.Adjustment = a.Adjustment
+	a.Owner.Rebuild(true)
+}
+
+type attributeChangeChoice struct {
+	def *gurps.AttributeDef
+}
+
+func (c *attributeChangeChoice) String() string {
+	return c.def.CombinedName()
+}
+
+func canPreviewAttributeChange(def *gurps.AttributeDef) bool {
+	return def != nil && !def.IsSeparator() && !def.Pool() && def.Type != attribute.IntegerRef &&
+		def.Type != attribute.DecimalRef
+}
+
+// ShowAttributeChangePreview shows what changing an attribute would do to the rest of the sheet before committing to
+// it. The attribute with the given ID is initially selected, if possible.
+func ShowAttributeChangePreview(owner Rebuildable, entity *gurps.Entity, attrID string) {
+	popup := unison.NewPopupMenu[*attributeChangeChoice]()
+	for _, def := range gurps.SheetSettingsFor(entity).Attributes.List(true) {
+		if canPreviewAttributeChange(def) {
+			if _, ok := entity.Attributes.Set[def.ID()]; ok {
+				choice := &attributeChangeChoice{def: def}
+				popup.AddItem(choice)
+				if def.ID() == attrID {
+					popup.Select(choice)
+				}
+			}
+		}
+	}
+	if popup.ItemCount() == 0 {
+		return
+	}
+	if _, ok := popup.Selected(); !ok {
+		popup.SelectIndex(0)
+	}
+	var attr *gurps.Attribute
+	var value fxp.Int
+	var preview *gurps.AttributeChangePreview
+
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Attribute"), false))
+	content.AddChild(popup)
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("New Value"), false))
+	effectsPanel := unison.NewPanel()
+	effectsPanel.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	costField := NewNonEditableField(func(field *NonEditableField) {
+		if preview == nil {
+			field.SetTitle("")
+		} else {
+			field.SetTitle(fmt.Sprintf(i18n.Text("%s → %s (%s pts)"), attr.Maximum().String(),
+				preview.Value.String(), preview.Cost.StringWithSign()))
+		}
+		field.MarkForLayoutAndRedraw()
+	})
+	update := func() {
+		preview = gurps.PreviewAttributeChange(entity, attr.AttrID, value)
+		costField.Sync()
+		rebuildAttributeChangeEffects(effectsPanel, preview)
+	}
+	valueField := NewDecimalField(nil, "", i18n.Text("New Value"), func() fxp.Int { return value },
+		func(v fxp.Int) {
+			value = v
+			update()
+		}, fxp.Min, fxp.Max, false, false)
+	content.AddChild(valueField)
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Change"), false))
+	content.AddChild(costField)
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[*attributeChangeChoice]) {
+		if choice, ok := p.Selected(); ok {
+			attr = entity.Attributes.Set[choice.def.ID()]
+			value = attr.Maximum()
+			valueField.Sync()
+			update()
+		}
+	}
+	popup.SelectionChangedCallback(popup)
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(effectsPanel, behavior.Fill, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:    2,
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		SizeHint: geom.Size{Width: 500, Height: 300},
+		HGrab:    true,
+		VGrab:    true,
+	})
+	content.AddChild(scroll)
+
+	applyButton := unison.NewOKButtonInfo()
+	applyButton.Title = i18n.Text("Apply")
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		content, []*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), applyButton},
+		unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK || preview == nil || preview.Adjustment == attr.Adjustment {
+		return
+	}
+	if entity.Locked {
+		unison.ErrorDialogWithMessage(fmt.Sprintf(i18n.Text("Unable to change %s"), attr.AttributeDef().CombinedName()),
+			gurps.SheetLocked())
+		return
+	}
+	before := &attributeChangeAdjuster{Owner: owner, Attribute: attr, Adjustment: attr.Adjustment}
+	after := &attributeChangeAdjuster{Owner: owner, Attribute: attr, Adjustment: preview.Adjustment}
+	if mgr := unison.UndoManagerFor(owner); mgr != nil {
+		addUndo(mgr, &unison.UndoEdit[*attributeChangeAdjuster]{
+			ID:         unison.NextUndoID(),
+			EditName:   fmt.Sprintf(i18n.Text("Change %s"), attr.AttributeDef().CombinedName()),
+			UndoFunc:   func(edit attributeChangeUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit attributeChangeUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	after.Apply()
+}
+
+func rebuildAttributeChangeEffects(panel *unison.Panel, preview *gurps.AttributeChangePreview) {
+	panel.RemoveAllChildren()
+	if preview == nil || len(preview.Effects) == 0 {
+		label := unison.NewLabel()
+		label.SetTitle(i18n.Text("Nothing else on the sheet would change"))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 4})
+		panel.AddChild(label)
+	} else {
+		for _, header := range []string{i18n.Text("Category"), i18n.Text("Name"), i18n.Text("Before"), i18n.Text("After")} {
+			label := unison.NewLabel()
+			label.Font = fonts.PageLabelSecondary
+			label.SetTitle(header)
+			panel.AddChild(label)
+		}
+		for _, effect := range preview.Effects {
+			for _, text := range []string{effect.Category, effect.Name, effect.Before, effect.After} {
+				label := unison.NewLabel()
+				label.SetTitle(text)
+				panel.AddChild(label)
+			}
+		}
+	}
+	panel.MarkForLayoutRecursivelyUpward()
+	panel.MarkForRedraw()
+}
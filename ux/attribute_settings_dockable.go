@@ -202,7 +202,7 @@ func (d *attributeSettingsDockable) addToStartToolbar(toolbar *unison.Panel) {
 		p := newAttrDefSettingsPanel(d, attrDef)
 		d.content.AddChild(p)
 		undo.AfterData = d.defs.Clone()
-		d.UndoManager().Add(undo)
+		addUndo(d.UndoManager(), undo)
 		d.MarkModified(nil)
 		d.MarkForLayoutAndRedraw()
 		d.ValidateLayout()
@@ -253,7 +253,7 @@ func (d *attributeSettingsDockable) reset() {
 	}
 	d.defs.ResetTargetKeyPrefixes(d.targetMgr.NextPrefix)
 	undo.AfterData = d.defs.Clone()
-	d.UndoManager().Add(undo)
+	addUndo(d.UndoManager(), undo)
 	d.sync()
 }
 
@@ -312,7 +312,7 @@ func (d *attributeSettingsDockable) load(fileSystem fs.FS, filePath string) erro
 	}
 	d.defs = replacements
 	undo.AfterData = replacements.Clone()
-	d.UndoManager().Add(undo)
+	addUndo(d.UndoManager(), undo)
 	d.sync()
 	return nil
 }
@@ -329,18 +329,7 @@ func (d *attributeSettingsDockable) apply() {
 	}
 	entity := d.owner.Entity()
 	entity.SheetSettings.Attributes = d.defs.Clone()
-	for attrID, def := range entity.SheetSettings.Attributes.Set {
-		if attr, exists := entity.Attributes.Set[attrID]; exists {
-			attr.Order = def.Order
-		} else {
-			entity.Attributes.Set[attrID] = gurps.NewAttribute(entity, attrID, def.Order)
-		}
-	}
-	for attrID := range entity.Attributes.Set {
-		if _, exists := d.defs.Set[attrID]; !exists {
-			delete(entity.Attributes.Set, attrID)
-		}
-	}
+	entity.Attributes.SyncWithDefs(entity)
 	for _, one := range AllDockables() {
 		if s, ok := one.(gurps.SheetSettingsResponder); ok {
 			s.SheetSettingsUpdated(entity, true)
@@ -462,7 +451,7 @@ func (d *attributeSettingsDockable) dataDragDrop(_ geom.Point, data map[string]a
 				}
 				undo.AfterData = d.defs.Clone()
 				d.applyAttrDefs(undo.AfterData)
-				d.UndoManager().Add(undo)
+				addUndo(d.UndoManager(), undo)
 				d.MarkModified(nil)
 				d.MarkForLayoutAndRedraw()
 			}
@@ -0,0 +1,239 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/recovery"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+const autosaveInterval = time.Minute
+
+var (
+	autosaveIDs     = make(map[autosavable]string)
+	autosaveStopped bool
+)
+
+// autosavable is implemented by file-backed dockables whose unsaved changes can be copied to the recovery directory and
+// later restored from it.
+type autosavable interface {
+	FileBackedDockable
+	// autosaver returns a function that writes the current content of the dockable to a file.
+	autosaver() func(filePath string) error
+	// markRecovered makes a dockable that was loaded from the recovery directory refer to the file it was recovered
+	// for, and to be considered modified.
+	markRecovered(filePath string, untitled bool)
+}
+
+func recoveryStore() *recovery.Store {
+	return &recovery.Store{Dir: filepath.Join(filepath.Dir(gurps.SettingsPath), xos.AppCmdName+"_recovery")}
+}
+
+// offerRecovery checks for documents left behind by a previous run that didn't exit normally and offers to reopen
+// them, then begins autosaving.
+func offerRecovery() {
+	store := recoveryStore()
+	entries, err := store.List()
+	if err != nil {
+		errs.Log(err)
+	}
+	if len(entries) != 0 {
+		for _, entry := range askWhichDocumentsToRecover(store, entries) {
+			recoverDocument(store, entry)
+		}
+		if err = store.Clear(); err != nil {
+			errs.Log(err)
+		}
+	}
+	autosave()
+}
+
+func recoverDocument(store *recovery.Store, entry *recovery.Entry) {
+	dataPath := store.DataPath(entry)
+	d, err := gurps.FileInfoFor(dataPath).Load(dataPath, 0)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to recover ")+entry.Title, err)
+		return
+	}
+	a, ok := d.(autosavable)
+	if !ok {
+		return
+	}
+	untitled := entry.Untitled
+	if !untitled {
+		// The original may have been reopened when the workspace was restored. If it hasn't been changed since, the
+		// recovered copy takes its place; otherwise, the recovered copy must be saved elsewhere.
+		if existing := LocateFileBackedDockable(entry.OriginalPath); existing != nil {
+			untitled = existing.Modified() || !AttemptCloseForDockable(existing)
+		}
+	}
+	a.markRecovered(entry.OriginalPath, untitled)
+	DisplayNewDockable(d)
+}
+
+func askWhichDocumentsToRecover(store *recovery.Store, entries []*recovery.Entry) []*recovery.Entry {
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.SetTitle(fmt.Sprintf(i18n.Text("%s did not exit normally and these documents had unsaved changes."),
+		xos.AppName))
+	content.AddChild(label)
+	label = unison.NewLabel()
+	label.SetTitle(i18n.Text("Choose the ones to reopen. Any that aren't reopened will be discarded."))
+	content.AddChild(label)
+
+	grid := unison.NewPanel()
+	grid.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	grid.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	for _, header := range []string{i18n.Text("Document"), i18n.Text("Autosaved"), i18n.Text("Preview")} {
+		label = unison.NewLabel()
+		label.Font = fonts.PageLabelSecondary
+		label.SetTitle(header)
+		grid.AddChild(label)
+	}
+	selected := make(map[*recovery.Entry]bool, len(entries))
+	for _, entry := range entries {
+		selected[entry] = true
+		title := entry.Title
+		if title == "" {
+			title = filepath.Base(entry.OriginalPath)
+		}
+		checkBox := NewCheckBox(nil, "", title,
+			func() check.Enum { return check.FromBool(selected[entry]) },
+			func(state check.Enum) { selected[entry] = state == check.On })
+		if entry.Untitled {
+			checkBox.Tooltip = newWrappedTooltip(i18n.Text("Never saved"))
+		} else {
+			checkBox.Tooltip = newWrappedTooltip(entry.OriginalPath)
+		}
+		grid.AddChild(checkBox)
+
+		label = unison.NewLabel()
+		label.SetTitle(time.Time(entry.Saved).Format(time.DateTime))
+		grid.AddChild(label)
+
+		label = unison.NewLabel()
+		if data, err := os.ReadFile(store.DataPath(entry)); err != nil {
+			label.SetTitle(i18n.Text("Unreadable"))
+		} else {
+			label.SetTitle(describeDocumentCopy(entry.OriginalPath, data, time.Time{}))
+		}
+		grid.AddChild(label)
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(grid, behavior.Fill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		SizeHint: geom.Size{Width: 700, Height: 300},
+		HGrab:    true,
+		VGrab:    true,
+	})
+	content.AddChild(scroll)
+
+	okButton := unison.NewOKButtonInfo()
+	okButton.Title = i18n.Text("Reopen")
+	cancelButton := unison.NewCancelButtonInfo()
+	cancelButton.Title = i18n.Text("Discard All")
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		content, []*unison.DialogButtonInfo{cancelButton, okButton}, unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return nil
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return nil
+	}
+	list := make([]*recovery.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if selected[entry] {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// autosave copies the modified documents to the recovery directory, then schedules itself to run again.
+func autosave() {
+	if autosaveStopped {
+		return
+	}
+	store := recoveryStore()
+	open := make(map[autosavable]bool)
+	for _, d := range AllDockables() {
+		a, ok := d.(autosavable)
+		if !ok {
+			continue
+		}
+		open[a] = true
+		id, exists := autosaveIDs[a]
+		if !a.Modified() {
+			if exists {
+				store.Remove(id)
+				delete(autosaveIDs, a)
+			}
+			continue
+		}
+		if !exists {
+			id = recovery.NewID()
+			autosaveIDs[a] = id
+		}
+		p := a.BackingFilePath()
+		if err := store.Save(&recovery.Entry{
+			ID:           id,
+			OriginalPath: p,
+			Title:        a.Title(),
+			Saved:        jio.Now(),
+			Untitled:     !xos.FileExists(p),
+		}, a.autosaver()); err != nil {
+			errs.Log(err, "path", p)
+		}
+	}
+	for a, id := range autosaveIDs {
+		if !open[a] {
+			store.Remove(id)
+			delete(autosaveIDs, a)
+		}
+	}
+	unison.InvokeTaskAfter(autosave, autosaveInterval)
+}
+
+// stopAutosave stops autosaving and removes the recovery directory. It should only be called once the user has had
+// the chance to save or discard every modified document.
+func stopAutosave() {
+	autosaveStopped = true
+	clear(autosaveIDs)
+	if err := recoveryStore().Clear(); err != nil {
+		errs.Log(err)
+	}
+}
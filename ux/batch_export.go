@@ -0,0 +1,233 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/diag"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/export"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+	"github.com/richardwilkes/unison"
+)
+
+// batchRenderer renders the pages for an export on the UI thread. It may return a function that completes the export
+// from any goroutine.
+type batchRenderer func(p *pageExporter, filePath string) (finish func() error, err error)
+
+type batchExporter struct {
+	ext string
+	// render is set for formats that need the page rendering code, and therefore a running UI toolkit.
+	render batchRenderer
+	write  func(entity *gurps.Entity, filePath string) error
+}
+
+var batchExporters = map[string]batchExporter{
+	"pdf":       {ext: ".pdf", render: (*pageExporter).renderPDFFile},
+	"png":       {ext: ".png", render: renderedOnUIThread((*pageExporter).exportAsPNGs)},
+	"webp":      {ext: ".webp", render: renderedOnUIThread((*pageExporter).exportAsWEBPs)},
+	"jpeg":      {ext: ".jpeg", render: renderedOnUIThread((*pageExporter).exportAsJPEGs)},
+	"svg":       {ext: ".svg", render: renderedOnUIThread((*pageExporter).exportAsSVGs)},
+	"html":      {ext: export.HTMLExtension, write: export.ToHTML},
+	"markdown":  {ext: export.MarkdownExtension, write: export.ToMarkdown},
+	"foundry":   {ext: export.FoundryExtension, write: export.ToFoundry},
+	"statblock": {ext: export.StatblockExtension, write: export.ToStatblock},
+}
+
+// renderedOnUIThread adapts an export that must be done entirely on the UI thread.
+func renderedOnUIThread(export func(p *pageExporter, filePath string) error) batchRenderer {
+	return func(p *pageExporter, filePath string) (func() error, error) {
+		return nil, export(p, filePath)
+	}
+}
+
+// BatchExportFormats returns the formats supported by BatchExport.
+func BatchExportFormats() []string {
+	return slices.Sorted(maps.Keys(batchExporters))
+}
+
+// BatchExport exports the character sheets found in paths to the given format without opening any windows. Directories
+// are traversed recursively. The exported files are placed in outputDir, or next to their source if outputDir is
+// empty. Images are rendered at the given resolution, or at the image export resolution from the settings if it is
+// zero. Formats that require page rendering start the UI toolkit to do so and exit the process when finished, rather
+// than returning.
+func BatchExport(format, outputDir string, resolution int, paths []string) error {
+	exporter, ok := batchExporters[strings.ToLower(format)]
+	if !ok {
+		return errs.New(fmt.Sprintf(i18n.Text("unsupported export format: %s (must be one of: %s)"), format,
+			strings.Join(BatchExportFormats(), ", ")))
+	}
+	if resolution != 0 && (resolution < gurps.ImageResolutionMin || resolution > gurps.ImageResolutionMax) {
+		return errs.New(fmt.Sprintf(i18n.Text("resolution must be between %d and %d"), gurps.ImageResolutionMin,
+			gurps.ImageResolutionMax))
+	}
+	list, err := collectSheetPaths(paths)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return errs.New(i18n.Text("No files to process."))
+	}
+	if outputDir != "" {
+		if err = os.MkdirAll(outputDir, 0o750); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	if exporter.render == nil {
+		return batchExport(exporter, outputDir, resolution, list)
+	}
+	unison.Start(unison.StartupFinishedCallback(func() {
+		xos.ExitIfErr(batchExport(exporter, outputDir, resolution, list))
+		xos.Exit(0)
+	})) // Never returns
+	return nil
+}
+
+// batchExport exports each sheet in the list. Sheets are loaded ahead of time on background goroutines and, once any
+// pages have been rendered on the calling goroutine, the remaining work and the writing of the file is also done in the
+// background, so that the rendering of one sheet overlaps with the loading and writing of others.
+func batchExport(exporter batchExporter, outputDir string, resolution int, list []string) error {
+	defer diag.Track(diag.BatchExportKey, time.Now())
+	workers := runtime.NumCPU()
+	loader := newBatchSheetLoader(list, workers)
+	var failed atomic.Int32
+	report := func(one string, err error) {
+		if err != nil {
+			failed.Add(1)
+			fmt.Fprintf(os.Stderr, i18n.Text("Unable to export %s: %v\n"), one, err)
+		}
+	}
+	var wg sync.WaitGroup
+	finishing := make(chan struct{}, workers)
+	for i, one := range list {
+		fmt.Printf(i18n.Text("Processing %s\n"), one)
+		dir := outputDir
+		if dir == "" {
+			dir = filepath.Dir(one)
+		}
+		target := filepath.Join(dir, xfilepath.BaseName(one)+exporter.ext)
+		entity, err := loader.sheet(i)
+		var finish func() error
+		if err == nil {
+			if exporter.render != nil {
+				pe := newPageExporter(entity)
+				if resolution != 0 {
+					pe.resolution = resolution
+				}
+				finish, err = exporter.render(pe, target)
+			} else {
+				finish = func() error { return exporter.write(entity, target) }
+			}
+		}
+		if err != nil || finish == nil {
+			report(one, err)
+			continue
+		}
+		finishing <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-finishing }()
+			report(one, finish())
+		})
+	}
+	wg.Wait()
+	if count := int(failed.Load()); count != 0 {
+		return errs.New(fmt.Sprintf(i18n.Text("%d of %d files failed to export"), count, len(list)))
+	}
+	return nil
+}
+
+type batchSheet struct {
+	entity *gurps.Entity
+	err    error
+}
+
+// batchSheetLoader loads sheets on background goroutines, keeping a limited number of them ready ahead of when they
+// are needed.
+type batchSheetLoader struct {
+	loaded []chan batchSheet
+	ahead  chan struct{}
+}
+
+func newBatchSheetLoader(list []string, workers int) *batchSheetLoader {
+	l := &batchSheetLoader{
+		loaded: make([]chan batchSheet, len(list)),
+		ahead:  make(chan struct{}, 2*workers),
+	}
+	for i := range l.loaded {
+		l.loaded[i] = make(chan batchSheet, 1)
+	}
+	next := make(chan int)
+	go func() {
+		for i := range list {
+			l.ahead <- struct{}{}
+			next <- i
+		}
+		close(next)
+	}()
+	for range workers {
+		go func() {
+			for i := range next {
+				entity, err := gurps.NewEntityFromFile(os.DirFS(filepath.Dir(list[i])), filepath.Base(list[i]))
+				l.loaded[i] <- batchSheet{entity: entity, err: err}
+			}
+		}()
+	}
+	return l
+}
+
+// sheet waits for the sheet at the given index in the list to be loaded and returns it. Sheets must be requested in
+// order.
+func (l *batchSheetLoader) sheet(index int) (*gurps.Entity, error) {
+	result := <-l.loaded[index]
+	<-l.ahead
+	return result.entity, result.err
+}
+
+func collectSheetPaths(paths []string) ([]string, error) {
+	paths, err := xfilepath.UniquePaths(paths...)
+	if err != nil {
+		return nil, err
+	}
+	pathSet := make(map[string]struct{})
+	for _, p := range paths {
+		_ = filepath.WalkDir(p, func(filePath string, d fs.DirEntry, walkErr error) error { //nolint:errcheck // We want to continue on even if there was an error
+			if walkErr != nil {
+				return nil
+			}
+			name := d.Name()
+			if strings.HasPrefix(name, ".") && filePath != p {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(name), gurps.SheetExt) {
+				pathSet[filePath] = struct{}{}
+			}
+			return nil
+		})
+	}
+	return slices.SortedFunc(maps.Keys(pathSet), func(a, b string) int { return xstrings.NaturalCmp(a, b, true) }), nil
+}
@@ -0,0 +1,445 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+const blockLayoutDragDataKey = "block_layout"
+
+var (
+	_ GroupedCloser                = &blockLayoutDockable{}
+	_ gurps.SheetSettingsResponder = &blockLayoutDockable{}
+)
+
+type blockLayoutDragData struct {
+	owner EntityPanel
+	key   string
+}
+
+// blockColumns is the number of columns a block's content flows into on a printed page, as offered in a popup menu.
+type blockColumns int
+
+func (c blockColumns) String() string {
+	if c == 1 {
+		return i18n.Text("1 Column")
+	}
+	return fmt.Sprintf(i18n.Text("%d Columns"), int(c))
+}
+
+// blockMinimum is the number of entries a list block must have for it to be shown, as offered in a popup menu.
+type blockMinimum int
+
+func (m blockMinimum) String() string {
+	switch m {
+	case 0:
+		return i18n.Text("No Condition")
+	case 1:
+		return i18n.Text("Hide When Empty")
+	default:
+		return fmt.Sprintf(i18n.Text("Hide Below %d Entries"), int(m))
+	}
+}
+
+type blockLayoutDockable struct {
+	unison.Panel
+	owner      EntityPanel
+	content    *unison.Panel
+	rows       [][]string
+	inDragOver bool
+	insertRow  int
+	besideKey  string
+	besideLeft bool
+}
+
+// ShowBlockLayoutEditor shows a visual editor for the block layout. Pass in nil to edit the defaults or a sheet to edit
+// the sheet's. Changes are applied immediately, so the sheet acts as a live preview.
+func ShowBlockLayoutEditor(owner EntityPanel) {
+	if Activate(func(d unison.Dockable) bool {
+		if e, ok := d.AsPanel().Self.(*blockLayoutDockable); ok && owner == e.owner {
+			return true
+		}
+		return false
+	}) {
+		return
+	}
+	d := &blockLayoutDockable{
+		owner:     owner,
+		insertRow: -1,
+	}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing * 2,
+	})
+	d.content.DataDragOverCallback = d.dataDragOver
+	d.content.DataDragExitCallback = d.dataDragExit
+	d.content.DataDragDropCallback = d.dataDragDrop
+	d.content.DrawOverCallback = d.drawOver
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.content, behavior.Fill, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	d.rebuild()
+	PlaceInDock(d, dgroup.Settings, false)
+}
+
+func (d *blockLayoutDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+
+	resetButton := unison.NewSVGButton(svg.Reset)
+	resetButton.Tooltip = newWrappedTooltip(i18n.Text("Reset the block layout to the factory arrangement"))
+	resetButton.ClickCallback = func() {
+		layout := d.settings().BlockLayout.Clone()
+		layout.Reset()
+		d.apply(layout)
+	}
+	toolbar.AddChild(resetButton)
+
+	label := unison.NewLabel()
+	label.SetTitle(i18n.Text("Drag a block between rows to give it a row of its own, or onto either end of a block to place it beside that block"))
+	toolbar.AddChild(label)
+
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *blockLayoutDockable) settings() *gurps.SheetSettings {
+	if d.owner != nil {
+		return d.owner.Entity().SheetSettings
+	}
+	return gurps.GlobalSettings().Sheet
+}
+
+func (d *blockLayoutDockable) rebuild() {
+	d.rows = d.settings().BlockLayout.ByRow()
+	d.content.RemoveAllChildren()
+	for _, row := range d.rows {
+		rowPanel := unison.NewPanel()
+		rowPanel.SetLayout(&unison.FlexLayout{
+			Columns:      2,
+			HSpacing:     unison.StdHSpacing * 2,
+			EqualColumns: true,
+		})
+		rowPanel.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		for _, key := range row {
+			tile := d.createTile(key)
+			if len(row) == 1 {
+				tile.SetLayoutData(&unison.FlexLayoutData{
+					HSpan:  2,
+					HAlign: align.Fill,
+					HGrab:  true,
+				})
+			}
+			rowPanel.AddChild(tile)
+		}
+		d.content.AddChild(rowPanel)
+	}
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *blockLayoutDockable) createTile(key string) *unison.Panel {
+	tile := unison.NewPanel()
+	tile.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.NewUniformInsets(1), false), unison.NewEmptyBorder(unison.StdInsets())))
+	flex := &unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	}
+	tile.SetLayout(flex)
+	tile.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	tile.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	tile.AddChild(NewDragHandle(map[string]any{blockLayoutDragDataKey: &blockLayoutDragData{
+		owner: d.owner,
+		key:   key,
+	}}))
+	label := unison.NewLabel()
+	label.SetTitle(d.settings().BlockTitle(key))
+	label.Tooltip = newWrappedTooltip(key)
+	label.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	tile.AddChild(label)
+	if gurps.BlockLayoutKeySupportsColumns(key) {
+		tile.AddChild(d.createColumnsPopup(key))
+	}
+	if gurps.BlockLayoutKeyIsList(key) {
+		tile.AddChild(d.createMinimumPopup(key))
+	}
+	layout := d.settings().BlockLayout
+	tile.AddChild(d.createOptionCheckBox(key, i18n.Text("Keep Together"),
+		i18n.Text("Move the block to the next printed page rather than split it across pages, when possible"),
+		layout.KeepTogether(key), (*gurps.BlockLayout).SetKeepTogether))
+	tile.AddChild(d.createOptionCheckBox(key, i18n.Text("New Page"),
+		i18n.Text("Start a new printed page with the row holding this block"),
+		layout.StartsNewPage(key), (*gurps.BlockLayout).SetStartsNewPage))
+	flex.Columns = len(tile.Children())
+	return tile
+}
+
+func (d *blockLayoutDockable) createColumnsPopup(key string) *unison.PopupMenu[blockColumns] {
+	popup := unison.NewPopupMenu[blockColumns]()
+	for i := 1; i <= gurps.MaxBlockLayoutColumns; i++ {
+		popup.AddItem(blockColumns(i))
+	}
+	popup.Select(blockColumns(d.settings().BlockLayout.Columns(key)))
+	popup.Tooltip = newWrappedTooltip(i18n.Text("The number of columns the block's content flows into on the printed page"))
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[blockColumns]) {
+		if item, ok := p.Selected(); ok {
+			layout := d.settings().BlockLayout.Clone()
+			layout.SetColumns(key, int(item))
+			d.apply(layout)
+		}
+	}
+	return popup
+}
+
+func (d *blockLayoutDockable) createMinimumPopup(key string) *unison.PopupMenu[blockMinimum] {
+	popup := unison.NewPopupMenu[blockMinimum]()
+	current := blockMinimum(d.settings().BlockLayout.MinimumEntries(key))
+	for i := range blockMinimum(6) {
+		popup.AddItem(i)
+	}
+	if current >= 6 {
+		popup.AddItem(current)
+	}
+	popup.Select(current)
+	popup.Tooltip = newWrappedTooltip(i18n.Text("Hide the block when it doesn't have enough entries, so it doesn't take up space on the sheet"))
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[blockMinimum]) {
+		if item, ok := p.Selected(); ok {
+			layout := d.settings().BlockLayout.Clone()
+			layout.SetMinimumEntries(key, int(item))
+			d.apply(layout)
+		}
+	}
+	return popup
+}
+
+func (d *blockLayoutDockable) createOptionCheckBox(key, title, tooltip string, checked bool, set func(layout *gurps.BlockLayout, key string, on bool)) *unison.CheckBox {
+	checkbox := unison.NewCheckBox()
+	checkbox.SetTitle(title)
+	checkbox.Tooltip = newWrappedTooltip(tooltip)
+	checkbox.State = check.FromBool(checked)
+	checkbox.ClickCallback = func() {
+		layout := d.settings().BlockLayout.Clone()
+		set(layout, key, checkbox.State == check.On)
+		d.apply(layout)
+	}
+	checkbox.SetLayoutData(&unison.FlexLayoutData{VAlign: align.Middle})
+	return checkbox
+}
+
+func (d *blockLayoutDockable) apply(layout *gurps.BlockLayout) {
+	d.settings().BlockLayout = layout
+	var entity *gurps.Entity
+	if d.owner != nil {
+		entity = d.owner.Entity()
+	}
+	for _, one := range AllDockables() {
+		if s, ok := one.(*sheetSettingsDockable); ok && s.owner == d.owner {
+			s.syncBlockLayoutField()
+		}
+		if s, ok := one.(gurps.SheetSettingsResponder); ok {
+			s.SheetSettingsUpdated(entity, true)
+		}
+	}
+}
+
+func (d *blockLayoutDockable) dataDragOver(where geom.Point, data map[string]any) bool {
+	prevInDragOver := d.inDragOver
+	prevInsertRow := d.insertRow
+	prevBesideKey := d.besideKey
+	prevBesideLeft := d.besideLeft
+	d.inDragOver = false
+	d.insertRow = -1
+	d.besideKey = ""
+	if dd, ok := data[blockLayoutDragDataKey].(*blockLayoutDragData); ok && dd.owner == d.owner {
+		d.content.ScrollRectIntoView(geom.NewRect(where.X, where.Y-16, 1, 1))
+		d.content.ScrollRectIntoView(geom.NewRect(where.X, where.Y+16, 1, 1))
+		children := d.content.Children()
+		for i, child := range children {
+			rect := child.FrameRect()
+			if where.Y > rect.Bottom() && i < len(children)-1 {
+				continue
+			}
+			d.inDragOver = true
+			if row := d.rows[i]; len(row) == 1 && row[0] != dd.key && where.In(rect) {
+				third := rect.Width / 3
+				switch {
+				case where.X < rect.X+third:
+					d.besideKey = row[0]
+					d.besideLeft = true
+				case where.X > rect.Right()-third:
+					d.besideKey = row[0]
+					d.besideLeft = false
+				}
+			}
+			if d.besideKey == "" {
+				if where.Y < rect.CenterY() {
+					d.insertRow = i
+				} else {
+					d.insertRow = i + 1
+				}
+			}
+			break
+		}
+	}
+	if prevInDragOver != d.inDragOver || prevInsertRow != d.insertRow || prevBesideKey != d.besideKey ||
+		prevBesideLeft != d.besideLeft {
+		d.MarkForRedraw()
+	}
+	return true
+}
+
+func (d *blockLayoutDockable) dataDragExit() {
+	d.inDragOver = false
+	d.insertRow = -1
+	d.besideKey = ""
+	d.MarkForRedraw()
+}
+
+func (d *blockLayoutDockable) dataDragDrop(_ geom.Point, data map[string]any) {
+	if d.inDragOver {
+		if dd, ok := data[blockLayoutDragDataKey].(*blockLayoutDragData); ok {
+			layout := d.settings().BlockLayout.Clone()
+			if d.besideKey != "" {
+				layout.MoveBeside(dd.key, d.besideKey, d.besideLeft)
+			} else if d.insertRow != -1 {
+				layout.MoveToNewRow(dd.key, d.insertRow)
+			}
+			d.apply(layout)
+		}
+	}
+	d.dataDragExit()
+}
+
+func (d *blockLayoutDockable) drawOver(gc *unison.Canvas, rect geom.Rect) {
+	if !d.inDragOver {
+		return
+	}
+	paint := unison.ThemeWarning.Paint(gc, rect, paintstyle.Stroke)
+	paint.SetStrokeWidth(2)
+	children := d.content.Children()
+	if d.besideKey != "" {
+		for i, row := range d.rows {
+			if len(row) == 1 && row[0] == d.besideKey {
+				r := children[i].FrameRect()
+				x := r.Right() - 1
+				if d.besideLeft {
+					x = r.X + 1
+				}
+				gc.DrawLine(geom.NewPoint(x, r.Y), geom.NewPoint(x, r.Bottom()), paint)
+				break
+			}
+		}
+		return
+	}
+	if d.insertRow != -1 && len(children) != 0 {
+		var y float32
+		if d.insertRow < len(children) {
+			y = children[d.insertRow].FrameRect().Y - unison.StdVSpacing
+		} else {
+			y = children[len(children)-1].FrameRect().Bottom() + unison.StdVSpacing
+		}
+		gc.DrawLine(geom.NewPoint(rect.X, y), geom.NewPoint(rect.Right(), y), paint)
+	}
+}
+
+// SheetSettingsUpdated implements gurps.SheetSettingsResponder.
+func (d *blockLayoutDockable) SheetSettingsUpdated(entity *gurps.Entity, blockLayout bool) {
+	if blockLayout && ((d.owner == nil && entity == nil) || (d.owner != nil && d.owner.Entity() == entity)) {
+		d.rebuild()
+	}
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *blockLayoutDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Settings,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *blockLayoutDockable) Title() string {
+	if d.owner != nil {
+		return i18n.Text("Block Layout: ") + d.owner.Entity().Profile.Name
+	}
+	return i18n.Text("Default Block Layout")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *blockLayoutDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *blockLayoutDockable) Modified() bool {
+	return false
+}
+
+// CloseWithGroup implements GroupedCloser.
+func (d *blockLayoutDockable) CloseWithGroup(other unison.Paneler) bool {
+	return d.owner != nil && d.owner == other
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *blockLayoutDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *blockLayoutDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
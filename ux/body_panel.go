@@ -227,6 +227,12 @@ func (p *BodyPanel) addTable(bodyType *gurps.Body, depth int) {
 			if detail := tooltip.String(); detail != "" {
 				tip += ":" + detail
 			}
+			if layers := p.entity.ArmorLayersFor(location.LocID); len(layers) != 0 {
+				tip += "\n\n" + i18n.Text("**Armor layers:**")
+				for _, layer := range layers {
+					tip += "\n- " + layer.String()
+				}
+			}
 			f.Tooltip = newMarkdownTooltip(tip, "")
 			MarkForLayoutWithinDockable(f)
 		})
@@ -158,7 +158,7 @@ func (d *bodySettingsDockable) prepareUndo(title string) *unison.UndoEdit[*gurps
 
 func (d *bodySettingsDockable) finishAndPostUndo(undo *unison.UndoEdit[*gurps.Body]) {
 	undo.AfterData = d.body.Clone(d.Entity(), nil)
-	d.UndoManager().Add(undo)
+	addUndo(d.UndoManager(), undo)
 }
 
 func (d *bodySettingsDockable) applyBodyType(bodyType *gurps.Body) {
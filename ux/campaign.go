@@ -10,30 +10,48 @@
 package ux
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/toolbox/v2/xos"
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/weight"
 )
 
 var (
-	_ FileBackedDockable = &Campaign{}
-	_ unison.TabCloser   = &Campaign{}
-	_ KeyedDockable      = &Campaign{}
+	_ FileBackedDockable         = &Campaign{}
+	_ unison.TabCloser           = &Campaign{}
+	_ KeyedDockable              = &Campaign{}
+	_ unison.UndoManagerProvider = &Campaign{}
+	_ ModifiableRoot             = &Campaign{}
 )
 
 // Campaign holds the view for a GURPS campaign.
 type Campaign struct {
 	unison.Panel
 	path              string
+	undoMgr           *unison.UndoManager
 	toolbar           *unison.Panel
 	scroll            *unison.ScrollPanel
 	content           *unison.Panel
+	members           *unison.Panel
+	libraries         *unison.Panel
+	lootPanel         *unison.Panel
+	treasuryField     *DecimalField
 	campaign          *gurps.Campaign
 	hash              uint64
 	scale             int
@@ -55,6 +73,7 @@ func NewCampaignFromFile(filePath string) (unison.Dockable, error) {
 func NewCampaign(filePath string, campaign *gurps.Campaign) *Campaign {
 	c := &Campaign{
 		path:              filePath,
+		undoMgr:           unison.NewUndoManager(100, func(err error) { errs.Log(err) }),
 		scroll:            unison.NewScrollPanel(),
 		campaign:          campaign,
 		hash:              gurps.Hash64(campaign),
@@ -71,7 +90,7 @@ func NewCampaign(filePath string, campaign *gurps.Campaign) *Campaign {
 		c.RequestFocus()
 		return false
 	}
-	c.scroll.SetContent(c.createContent(), behavior.Unmodified, behavior.Unmodified)
+	c.scroll.SetContent(c.createContent(), behavior.HintedFill, behavior.Fill)
 	c.scroll.SetLayoutData(&unison.FlexLayoutData{
 		HAlign: align.Fill,
 		VAlign: align.Fill,
@@ -100,6 +119,65 @@ func NewCampaign(filePath string, campaign *gurps.Campaign) *Campaign {
 			c.scroll,
 		),
 	)
+
+	addPCButton := unison.NewButton()
+	addPCButton.SetTitle(i18n.Text("Add PC…"))
+	addPCButton.Tooltip = newWrappedTooltip(i18n.Text("Add player character sheets to the campaign"))
+	addPCButton.ClickCallback = func() { c.addMembers(false) }
+	c.toolbar.AddChild(addPCButton)
+
+	addNPCButton := unison.NewButton()
+	addNPCButton.SetTitle(i18n.Text("Add NPC…"))
+	addNPCButton.Tooltip = newWrappedTooltip(i18n.Text("Add non-player character sheets to the campaign"))
+	addNPCButton.ClickCallback = func() { c.addMembers(true) }
+	c.toolbar.AddChild(addNPCButton)
+
+	compareButton := unison.NewButton()
+	compareButton.SetTitle(i18n.Text("Compare Members"))
+	compareButton.Tooltip = newWrappedTooltip(i18n.Text("Show the members of the campaign side by side"))
+	compareButton.ClickCallback = c.compareMembers
+	c.toolbar.AddChild(compareButton)
+
+	gmScreenButton := unison.NewButton()
+	gmScreenButton.SetTitle(i18n.Text("Export GM Screen…"))
+	gmScreenButton.Tooltip = newWrappedTooltip(i18n.Text(
+		"Export a summary of each member, four to a page, using the paper settings of the campaign's sheet settings"))
+	gmScreenButton.ClickCallback = c.exportGMScreen
+	c.toolbar.AddChild(gmScreenButton)
+
+	loadSettingsButton := unison.NewButton()
+	loadSettingsButton.SetTitle(i18n.Text("Load Sheet Settings…"))
+	loadSettingsButton.Tooltip = newWrappedTooltip(i18n.Text("Load the campaign's shared sheet settings from a file"))
+	loadSettingsButton.ClickCallback = c.loadSheetSettings
+	c.toolbar.AddChild(loadSettingsButton)
+
+	applySettingsButton := unison.NewButton()
+	applySettingsButton.SetTitle(i18n.Text("Apply Settings to All"))
+	applySettingsButton.Tooltip = newWrappedTooltip(i18n.Text(
+		"Replace the sheet settings of every member of the campaign with the campaign's shared sheet settings"))
+	applySettingsButton.ClickCallback = c.applySettingsToAll
+	c.toolbar.AddChild(applySettingsButton)
+
+	lockPasswordButton := unison.NewButton()
+	lockPasswordButton.SetTitle(i18n.Text("Lock Password…"))
+	lockPasswordButton.Tooltip = newWrappedTooltip(i18n.Text(
+		"Set the password players must enter to unlock member sheets that were locked by the campaign"))
+	lockPasswordButton.ClickCallback = c.setLockPassword
+	c.toolbar.AddChild(lockPasswordButton)
+
+	lockButton := unison.NewButton()
+	lockButton.SetTitle(i18n.Text("Lock All"))
+	lockButton.Tooltip = newWrappedTooltip(i18n.Text(
+		"Lock the sheet of every member of the campaign, protecting them with the campaign's lock password, if any"))
+	lockButton.ClickCallback = func() { c.setLockOnAll(true) }
+	c.toolbar.AddChild(lockButton)
+
+	unlockButton := unison.NewButton()
+	unlockButton.SetTitle(i18n.Text("Unlock All"))
+	unlockButton.Tooltip = newWrappedTooltip(i18n.Text("Unlock the sheet of every member of the campaign"))
+	unlockButton.ClickCallback = func() { c.setLockOnAll(false) }
+	c.toolbar.AddChild(unlockButton)
+
 	c.toolbar.SetLayout(&unison.FlexLayout{
 		Columns:  len(c.toolbar.Children()),
 		HSpacing: unison.StdHSpacing,
@@ -107,6 +185,12 @@ func NewCampaign(filePath string, campaign *gurps.Campaign) *Campaign {
 
 	c.AddChild(c.toolbar)
 	c.AddChild(c.scroll)
+
+	c.InstallCmdHandlers(SaveItemID, func(_ any) bool { return c.Modified() }, func(_ any) { c.save(false) })
+	c.InstallCmdHandlers(SaveAsItemID, unison.AlwaysEnabled, func(_ any) { c.save(true) })
+	hasMembers := func(_ any) bool { return len(c.campaign.Members) != 0 }
+	c.InstallCmdHandlers(ExportAsPDFItemID, hasMembers, func(_ any) { c.exportGMScreen() })
+	c.InstallCmdHandlers(PrintItemID, hasMembers, func(_ any) { c.printGMScreen() })
 	return c
 }
 
@@ -117,9 +201,546 @@ func (c *Campaign) DockKey() string {
 
 func (c *Campaign) createContent() unison.Paneler {
 	c.content = unison.NewPanel()
+	c.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	c.content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	title := i18n.Text("Name")
+	c.content.AddChild(NewFieldLeadingLabel(title, false))
+	nameField := NewStringField(nil, "", title,
+		func() string { return c.campaign.Name },
+		func(value string) {
+			c.campaign.Name = value
+			MarkModified(c)
+		})
+	nameField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	c.content.AddChild(nameField)
+
+	title = i18n.Text("GM Notes")
+	c.content.AddChild(NewFieldInteriorLeadingLabel(title, false))
+	notesField := NewMultiLineStringField(nil, "", title,
+		func() string { return c.campaign.Notes },
+		func(value string) {
+			c.campaign.Notes = value
+			MarkModified(c)
+		})
+	notesField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	c.content.AddChild(notesField)
+
+	c.content.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("Members"), false))
+	c.members = unison.NewPanel()
+	c.members.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	c.members.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	c.content.AddChild(c.members)
+	c.rebuildMembers()
+
+	c.content.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("Party Loot"), false))
+	c.lootPanel = unison.NewPanel()
+	c.lootPanel.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing,
+		VAlign:   align.Middle,
+	})
+	c.content.AddChild(c.lootPanel)
+	c.rebuildLoot()
+
+	title = i18n.Text("Party Treasury")
+	c.content.AddChild(NewFieldLeadingLabel(title, false))
+	treasury := unison.NewPanel()
+	treasury.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VAlign:   align.Middle,
+	})
+	c.treasuryField = NewDecimalField(nil, "", title,
+		func() fxp.Int { return c.campaign.Treasury },
+		func(value fxp.Int) {
+			c.campaign.Treasury = value
+			MarkModified(c)
+		}, 0, fxp.TenMillionMinusOne, false, false)
+	c.treasuryField.Tooltip = newWrappedTooltip(i18n.Text("Money held by the party that has not yet been divided up"))
+	treasury.AddChild(c.treasuryField)
+	splitButton := unison.NewButton()
+	splitButton.SetTitle(i18n.Text("Split Among PCs…"))
+	splitButton.Tooltip = newWrappedTooltip(i18n.Text(
+		"Divide the party treasury evenly among the player characters, adding each share to their money"))
+	splitButton.ClickCallback = c.splitTreasury
+	treasury.AddChild(splitButton)
+	c.content.AddChild(treasury)
+
+	c.content.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("Libraries"), false))
+	c.libraries = unison.NewPanel()
+	c.libraries.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	c.content.AddChild(c.libraries)
+	c.rebuildLibraries()
 	return c.content
 }
 
+func (c *Campaign) rebuildMembers() {
+	c.members.RemoveAllChildren()
+	if len(c.campaign.Members) == 0 {
+		label := unison.NewLabel()
+		label.SetTitle(i18n.Text("No characters have been added to this campaign"))
+		c.members.AddChild(label)
+	}
+	for _, member := range c.campaign.Members {
+		c.members.AddChild(c.createMemberRow(member))
+	}
+	c.members.MarkForLayoutRecursivelyUpward()
+	c.members.MarkForRedraw()
+}
+
+func (c *Campaign) createMemberRow(member *gurps.CampaignMember) *unison.Panel {
+	memberPath := member.AbsolutePath(c.path)
+	exists := xos.FileExists(memberPath)
+	row := unison.NewPanel()
+	row.SetLayout(&unison.FlexLayout{
+		Columns:  5,
+		HSpacing: unison.StdHSpacing,
+		VAlign:   align.Middle,
+	})
+	row.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+
+	openButton := unison.NewSVGButton(svg.OpenFolder)
+	openButton.Tooltip = newWrappedTooltip(i18n.Text("Open"))
+	openButton.SetEnabled(exists)
+	openButton.ClickCallback = func() { OpenFile(memberPath, 0) }
+	row.AddChild(openButton)
+
+	label := unison.NewLabel()
+	name := xfilepath.BaseName(memberPath)
+	if !exists {
+		name = fmt.Sprintf(i18n.Text("%s (missing)"), name)
+	}
+	label.SetTitle(name)
+	label.Tooltip = newWrappedTooltip(memberPath)
+	label.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	row.AddChild(label)
+
+	rolePopup := unison.NewPopupMenu[string]()
+	pc := i18n.Text("PC")
+	npc := i18n.Text("NPC")
+	rolePopup.AddItem(pc, npc)
+	if member.NPC {
+		rolePopup.Select(npc)
+	} else {
+		rolePopup.Select(pc)
+	}
+	rolePopup.SelectionChangedCallback = func(popup *unison.PopupMenu[string]) {
+		if item, ok := popup.Selected(); ok {
+			member.NPC = item == npc
+			MarkModified(c)
+		}
+	}
+	row.AddChild(rolePopup)
+
+	useSettingsButton := unison.NewSVGButton(svg.Settings)
+	useSettingsButton.Tooltip = newWrappedTooltip(i18n.Text(
+		"Use this character's sheet settings as the campaign's shared sheet settings"))
+	useSettingsButton.SetEnabled(exists)
+	useSettingsButton.ClickCallback = func() { c.useSettingsFrom(memberPath) }
+	row.AddChild(useSettingsButton)
+
+	removeButton := unison.NewSVGButton(svg.Trash)
+	removeButton.Tooltip = newWrappedTooltip(i18n.Text("Remove from the campaign"))
+	removeButton.ClickCallback = func() {
+		c.campaign.RemoveMember(member)
+		c.rebuildMembers()
+		MarkModified(c)
+	}
+	row.AddChild(removeButton)
+
+	if exists {
+		dropping := false
+		row.DataDragOverCallback = func(_ geom.Point, data map[string]any) bool {
+			_, dropping = data[equipmentDragKey]
+			row.MarkForRedraw()
+			return dropping
+		}
+		row.DataDragExitCallback = func() {
+			dropping = false
+			row.MarkForRedraw()
+		}
+		row.DataDragDropCallback = func(_ geom.Point, data map[string]any) {
+			dropping = false
+			row.MarkForRedraw()
+			if dragData, ok := data[equipmentDragKey].(*unison.TableDragData[*Node[*gurps.Equipment]]); ok {
+				c.giveEquipment(memberPath, dragData)
+			}
+		}
+		row.DrawOverCallback = func(gc *unison.Canvas, _ geom.Rect) {
+			if dropping {
+				r := row.ContentRect(true)
+				paint := unison.ThemeWarning.Paint(gc, r, paintstyle.Fill)
+				paint.SetColorFilter(unison.Alpha30Filter())
+				gc.DrawRect(r, paint)
+			}
+		}
+	}
+	return row
+}
+
+func (c *Campaign) rebuildLoot() {
+	c.lootPanel.RemoveAllChildren()
+	lootPath := c.campaign.LootPath(c.path)
+	exists := lootPath != "" && xos.FileExists(lootPath)
+
+	label := unison.NewLabel()
+	switch {
+	case lootPath == "":
+		label.SetTitle(i18n.Text("None"))
+	case exists:
+		label.SetTitle(xfilepath.BaseName(lootPath))
+	default:
+		label.SetTitle(fmt.Sprintf(i18n.Text("%s (missing)"), xfilepath.BaseName(lootPath)))
+	}
+	label.Tooltip = newWrappedTooltip(i18n.Text(
+		"The loot sheet holding the party's shared loot. Drag equipment from it onto a member to hand it over."))
+	c.lootPanel.AddChild(label)
+
+	chooseButton := unison.NewButton()
+	chooseButton.SetTitle(i18n.Text("Choose…"))
+	chooseButton.ClickCallback = c.chooseLoot
+	c.lootPanel.AddChild(chooseButton)
+
+	openButton := unison.NewSVGButton(svg.OpenFolder)
+	openButton.Tooltip = newWrappedTooltip(i18n.Text("Open"))
+	openButton.SetEnabled(exists)
+	openButton.ClickCallback = func() { OpenFile(lootPath, 0) }
+	c.lootPanel.AddChild(openButton)
+
+	clearButton := unison.NewSVGButton(svg.Trash)
+	clearButton.Tooltip = newWrappedTooltip(i18n.Text("Clear"))
+	clearButton.SetEnabled(lootPath != "")
+	clearButton.ClickCallback = func() {
+		c.campaign.SetLootPath(c.path, "")
+		c.rebuildLoot()
+		MarkModified(c)
+	}
+	c.lootPanel.AddChild(clearButton)
+	c.lootPanel.MarkForLayoutRecursivelyUpward()
+	c.lootPanel.MarkForRedraw()
+}
+
+func (c *Campaign) rebuildLibraries() {
+	c.libraries.RemoveAllChildren()
+	installed := make(map[string]bool)
+	for _, lib := range gurps.GlobalSettings().Libraries().List() {
+		key := lib.Key()
+		installed[key] = true
+		box := unison.NewCheckBox()
+		box.SetTitle(lib.Title)
+		box.Tooltip = newWrappedTooltip(key)
+		box.State = check.FromBool(slices.Contains(c.campaign.Libraries, key))
+		box.ClickCallback = func() {
+			if box.State == check.On {
+				if !slices.Contains(c.campaign.Libraries, key) {
+					c.campaign.Libraries = append(c.campaign.Libraries, key)
+				}
+			} else {
+				c.campaign.Libraries = slices.DeleteFunc(c.campaign.Libraries, func(one string) bool {
+					return one == key
+				})
+			}
+			MarkModified(c)
+		}
+		c.libraries.AddChild(box)
+	}
+	for _, key := range c.campaign.Libraries {
+		if !installed[key] {
+			label := unison.NewLabel()
+			label.Font = &unison.DynamicFont{
+				Resolver: func() unison.FontDescriptor {
+					desc := unison.DefaultLabelTheme.Font.Descriptor()
+					desc.Weight = weight.Bold
+					return desc
+				},
+			}
+			label.OnBackgroundInk = unison.ThemeError
+			label.SetTitle(fmt.Sprintf(i18n.Text("%s (not installed)"), key))
+			c.libraries.AddChild(label)
+		}
+	}
+}
+
+func (c *Campaign) requireSaved() bool {
+	if c.needsSaveAsPrompt {
+		unison.WarningDialogWithMessage(i18n.Text("The campaign must be saved first"),
+			i18n.Text("Files are referenced relative to the campaign file, so it must be saved before any can be added."))
+		return false
+	}
+	return true
+}
+
+func (c *Campaign) addMembers(npc bool) {
+	if !c.requireSaved() {
+		return
+	}
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(true)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.SheetExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	dialog.SetInitialDirectory(filepath.Dir(c.path))
+	if dialog.RunModal() {
+		for _, p := range dialog.Paths() {
+			c.campaign.AddMember(c.path, p, npc)
+		}
+		c.rebuildMembers()
+		MarkModified(c)
+	}
+}
+
+func (c *Campaign) chooseLoot() {
+	if !c.requireSaved() {
+		return
+	}
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.LootExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	dialog.SetInitialDirectory(filepath.Dir(c.path))
+	if dialog.RunModal() {
+		c.campaign.SetLootPath(c.path, dialog.Path())
+		c.rebuildLoot()
+		MarkModified(c)
+	}
+}
+
+// giveEquipment hands the dragged equipment to the member. Equipment dragged from a loot sheet is removed from it.
+func (c *Campaign) giveEquipment(memberPath string, dragData *unison.TableDragData[*Node[*gurps.Equipment]]) {
+	equipment := make([]*gurps.Equipment, 0, len(dragData.Rows))
+	for _, row := range dragData.Rows {
+		equipment = append(equipment, row.Data())
+	}
+	if len(equipment) == 0 {
+		return
+	}
+	from := libraryFileFromTable(dragData.Table)
+	editName := i18n.Text("Give Loot")
+	if err := c.updateMember(memberPath, editName, func(e *gurps.Entity) {
+		gurps.GiveEquipment(e, from, equipment)
+	}); err != nil {
+		Workspace.ErrorHandler(fmt.Sprintf(i18n.Text("Unable to give loot to %s"), xfilepath.BaseName(memberPath)), err)
+		return
+	}
+	if loot, ok := unison.Ancestor[unison.Dockable](dragData.Table).(*LootSheet); ok {
+		loot.removeEquipment(editName, equipment)
+	}
+}
+
+func (c *Campaign) splitTreasury() {
+	pcs := c.campaign.PCs()
+	if len(pcs) == 0 || c.campaign.Treasury <= 0 {
+		return
+	}
+	shares := gurps.SplitCurrency(c.campaign.Treasury, len(pcs))
+	var buffer strings.Builder
+	for i, member := range pcs {
+		fmt.Fprintf(&buffer, "%s: %s\n", xfilepath.BaseName(member.Path), shares[i].Comma())
+	}
+	if unison.QuestionDialog(i18n.Text("Split the party treasury among the player characters?"),
+		buffer.String()) != unison.ModalResponseOK {
+		return
+	}
+	var remaining fxp.Int
+	for i, member := range pcs {
+		memberPath := member.AbsolutePath(c.path)
+		if err := c.updateMember(memberPath, i18n.Text("Receive Treasure"), func(e *gurps.Entity) {
+			gurps.AddCurrency(e, shares[i])
+		}); err != nil {
+			remaining += shares[i]
+			Workspace.ErrorHandler(fmt.Sprintf(i18n.Text("Unable to give treasure to %s"),
+				xfilepath.BaseName(memberPath)), err)
+		}
+	}
+	c.campaign.Treasury = remaining
+	c.treasuryField.Sync()
+	MarkModified(c)
+}
+
+// updateMember applies update to the member's character. If the member's sheet is open, the change is made there and
+// can be undone; otherwise the sheet file is loaded, updated, and saved.
+func (c *Campaign) updateMember(memberPath, editName string, update func(e *gurps.Entity)) error {
+	if sheet := c.openSheetFor(memberPath); sheet != nil {
+		sheet.updateTables(editName, func() { update(sheet.entity) })
+		return nil
+	}
+	e, err := gurps.NewEntityFromFile(os.DirFS(filepath.Dir(memberPath)), filepath.Base(memberPath))
+	if err != nil {
+		return err
+	}
+	update(e)
+	return e.Save(memberPath)
+}
+
+func (c *Campaign) useSettingsFrom(sheetPath string) {
+	var entity *gurps.Entity
+	if sheet := c.openSheetFor(sheetPath); sheet != nil {
+		entity = sheet.entity
+	} else {
+		var err error
+		if entity, err = gurps.NewEntityFromFile(os.DirFS(filepath.Dir(sheetPath)), filepath.Base(sheetPath)); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to load character sheet"), err)
+			return
+		}
+	}
+	c.campaign.SheetSettings = entity.SheetSettings.Clone(nil)
+	MarkModified(c)
+}
+
+func (c *Campaign) loadSheetSettings() {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.SheetSettingsExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.SettingsLastDirKey))
+	if dialog.RunModal() {
+		p := dialog.Path()
+		global.SetLastDir(gurps.SettingsLastDirKey, filepath.Dir(p))
+		s, err := gurps.NewSheetSettingsFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p))
+		if err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to load sheet settings"), err)
+			return
+		}
+		c.campaign.SheetSettings = s
+		MarkModified(c)
+	}
+}
+
+func (c *Campaign) applySettingsToAll() {
+	if len(c.campaign.Members) == 0 {
+		return
+	}
+	if unison.QuestionDialog(i18n.Text("Apply the campaign's sheet settings to all members?"),
+		i18n.Text(`Sheets that are open will be updated and marked as modified.
+Sheets that are not open will be updated and saved.`)) != unison.ModalResponseOK {
+		return
+	}
+	for _, member := range c.campaign.Members {
+		memberPath := member.AbsolutePath(c.path)
+		if sheet := c.openSheetFor(memberPath); sheet != nil {
+			c.campaign.ApplySettingsTo(sheet.entity)
+			for _, one := range AllDockables() {
+				if responder, ok := one.(gurps.SheetSettingsResponder); ok {
+					responder.SheetSettingsUpdated(sheet.entity, true)
+				}
+			}
+			continue
+		}
+		if err := c.campaign.ApplySettingsToFile(memberPath); err != nil {
+			Workspace.ErrorHandler(fmt.Sprintf(i18n.Text("Unable to apply settings to %s"),
+				xfilepath.BaseName(memberPath)), err)
+		}
+	}
+}
+
+func (c *Campaign) setLockPassword() {
+	var password string
+	if !askForLockPassword(i18n.Text("Set Password"), &password) {
+		return
+	}
+	lockPassword, err := gurps.NewLockPassword(password)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to set the lock password"), err)
+		return
+	}
+	c.campaign.LockPassword = lockPassword
+	MarkModified(c)
+}
+
+func (c *Campaign) setLockOnAll(locked bool) {
+	if len(c.campaign.Members) == 0 {
+		return
+	}
+	var title string
+	if locked {
+		title = i18n.Text("Lock the sheets of all members?")
+	} else {
+		title = i18n.Text("Unlock the sheets of all members?")
+	}
+	if unison.QuestionDialog(title, i18n.Text(`Sheets that are open will be updated and marked as modified.
+Sheets that are not open will be updated and saved.`)) != unison.ModalResponseOK {
+		return
+	}
+	for _, member := range c.campaign.Members {
+		memberPath := member.AbsolutePath(c.path)
+		if sheet := c.openSheetFor(memberPath); sheet != nil {
+			c.campaign.SetSheetLock(sheet.entity, locked)
+			if locked {
+				sheet.undoMgr.Clear()
+			}
+			sheet.Rebuild(true)
+			MarkModified(sheet)
+			continue
+		}
+		if err := c.campaign.SetSheetLockOnFile(memberPath, locked); err != nil {
+			Workspace.ErrorHandler(fmt.Sprintf(i18n.Text("Unable to change the lock on %s"),
+				xfilepath.BaseName(memberPath)), err)
+		}
+	}
+}
+
+func (c *Campaign) compareMembers() {
+	if len(c.campaign.Members) == 0 {
+		return
+	}
+	paths := make([]string, len(c.campaign.Members))
+	for i, member := range c.campaign.Members {
+		paths[i] = member.AbsolutePath(c.path)
+	}
+	ShowSheetComparison(paths...)
+}
+
+func (c *Campaign) openSheetFor(sheetPath string) *Sheet {
+	for _, sheet := range OpenSheets(nil) {
+		if sheet.BackingFilePath() == sheetPath {
+			return sheet
+		}
+	}
+	return nil
+}
+
+// UndoManager implements undo.Provider
+func (c *Campaign) UndoManager() *unison.UndoManager {
+	return c.undoMgr
+}
+
+// MarkModified implements widget.ModifiableRoot.
+func (c *Campaign) MarkModified(_ unison.Paneler) {
+	UpdateTitleForDockable(c)
+}
+
 // TitleIcon implements ux.FileBackedDockable
 func (c *Campaign) TitleIcon(suggestedSize geom.Size) unison.Drawable {
 	return &unison.DrawableSVG{
@@ -186,3 +807,14 @@ func (c *Campaign) save(forceSaveAs bool) bool {
 	}
 	return success
 }
+
+func (c *Campaign) autosaver() func(filePath string) error {
+	return c.campaign.Save
+}
+
+func (c *Campaign) markRecovered(filePath string, untitled bool) {
+	c.path = filePath
+	c.needsSaveAsPrompt = untitled
+	c.hash = 0 // Force it to be recognized as unsaved
+	UpdateTitleForDockable(c)
+}
@@ -59,7 +59,7 @@ func NewCheckBox(targetMgr *TargetMgr, targetKey, title string, get func() check
 					MarkModified(self)
 				}, c.get())
 				undo.AfterData = c.State
-				mgr.Add(undo)
+				addUndo(mgr, undo)
 			}
 			c.set(c.State)
 			if c.OnSet != nil {
@@ -0,0 +1,486 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"context"
+	"encoding/json/v2"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/cloudsync"
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+const cloudSyncTimeout = 5 * time.Minute
+
+var cloudSyncRunning bool
+
+func cloudSyncConfigPath() string {
+	return filepath.Join(filepath.Dir(gurps.SettingsPath), xos.AppCmdName+"_cloud_sync.json")
+}
+
+func cloudSyncStatePath() string {
+	return filepath.Join(filepath.Dir(gurps.SettingsPath), xos.AppCmdName+"_cloud_sync_state.json")
+}
+
+// ShowCloudSyncSettings displays a dialog for configuring the remote store that settings and sheets are synchronized
+// with.
+func ShowCloudSyncSettings() {
+	cfg, err := cloudsync.LoadConfig(cloudSyncConfigPath())
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load the cloud sync settings"), err)
+		return
+	}
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	kinds := []string{"", cloudsync.WebDAVKind, cloudsync.S3Kind, cloudsync.GitKind}
+	kindTitles := []string{
+		i18n.Text("None"),
+		i18n.Text("WebDAV"),
+		i18n.Text("Amazon S3 (or compatible)"),
+		i18n.Text("Git"),
+	}
+	providerLabel := i18n.Text("Provider")
+	content.AddChild(NewFieldLeadingLabel(providerLabel, false))
+	providerPopup := unison.NewPopupMenu[string]()
+	providerPopup.AddItem(kindTitles...)
+	providerPopup.SelectIndex(0)
+	for i, kind := range kinds {
+		if kind == cfg.Kind {
+			providerPopup.SelectIndex(i)
+		}
+	}
+	content.AddChild(providerPopup)
+
+	urlLabel := i18n.Text("URL")
+	content.AddChild(NewFieldLeadingLabel(urlLabel, false))
+	urlField := NewStringField(nil, "", urlLabel, func() string { return cfg.URL },
+		func(s string) { cfg.URL = strings.TrimSpace(s) })
+	urlField.Tooltip = newWrappedTooltip(i18n.Text(`For WebDAV, the URL of the folder to store files in. For S3, the endpoint URL, such as https://s3.us-east-1.amazonaws.com. For Git, the HTTPS URL of the repository.`))
+	urlField.SetMinimumTextWidthUsing("https://s3.us-east-1.amazonaws.com/mmmmmmmmmm")
+	content.AddChild(urlField)
+
+	userLabel := i18n.Text("User")
+	content.AddChild(NewFieldLeadingLabel(userLabel, false))
+	userField := NewStringField(nil, "", userLabel, func() string { return cfg.Username },
+		func(s string) { cfg.Username = strings.TrimSpace(s) })
+	userField.Tooltip = newWrappedTooltip(i18n.Text("The WebDAV or Git user name, or the S3 access key ID"))
+	content.AddChild(userField)
+
+	passwordLabel := i18n.Text("Password")
+	content.AddChild(NewFieldLeadingLabel(passwordLabel, false))
+	passwordField := NewStringField(nil, "", passwordLabel, func() string { return cfg.Password },
+		func(s string) { cfg.Password = s })
+	passwordField.ObscurementRune = '•'
+	passwordField.Tooltip = newWrappedTooltip(
+		i18n.Text("The WebDAV password, the S3 secret access key, or the Git access token"))
+	content.AddChild(passwordField)
+
+	regionLabel := i18n.Text("Region")
+	content.AddChild(NewFieldLeadingLabel(regionLabel, false))
+	regionField := NewStringField(nil, "", regionLabel, func() string { return cfg.Region },
+		func(s string) { cfg.Region = strings.TrimSpace(s) })
+	regionField.Watermark = "us-east-1"
+	content.AddChild(regionField)
+
+	bucketLabel := i18n.Text("Bucket")
+	content.AddChild(NewFieldLeadingLabel(bucketLabel, false))
+	bucketField := NewStringField(nil, "", bucketLabel, func() string { return cfg.Bucket },
+		func(s string) { cfg.Bucket = strings.TrimSpace(s) })
+	content.AddChild(bucketField)
+
+	content.AddChild(unison.NewPanel())
+	settingsCheckBox := NewCheckBox(nil, "", i18n.Text("Synchronize settings"),
+		func() check.Enum { return check.FromBool(cfg.Settings) },
+		func(state check.Enum) { cfg.Settings = state == check.On })
+	settingsCheckBox.Tooltip = newWrappedTooltip(i18n.Text("Synchronize the settings file along with the sheet folders"))
+	content.AddChild(settingsCheckBox)
+
+	foldersLabel := i18n.Text("Sheet Folders")
+	content.AddChild(NewFieldLeadingLabel(foldersLabel, false))
+	foldersField := NewMultiLineStringField(nil, "", foldersLabel,
+		func() string { return strings.Join(cfg.Folders, "\n") },
+		func(s string) {
+			cfg.Folders = cfg.Folders[:0]
+			for one := range strings.SplitSeq(s, "\n") {
+				if one = strings.TrimSpace(one); one != "" {
+					cfg.Folders = append(cfg.Folders, one)
+				}
+			}
+		})
+	foldersField.Tooltip = newWrappedTooltip(i18n.Text(`The folders to synchronize, one per line. All files within them are synchronized, other than hidden ones. Folders are matched up with those on other machines by name, so a folder may live in a different place on each machine.`))
+	foldersField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	content.AddChild(foldersField)
+
+	content.AddChild(unison.NewPanel())
+	addFolderButton := unison.NewButton()
+	addFolderButton.SetTitle(i18n.Text("Add Folder…"))
+	addFolderButton.ClickCallback = func() {
+		dialog := unison.NewOpenDialog()
+		dialog.SetAllowsMultipleSelection(false)
+		dialog.SetResolvesAliases(true)
+		dialog.SetCanChooseDirectories(true)
+		dialog.SetCanChooseFiles(false)
+		dialog.SetInitialDirectory(gurps.GlobalSettings().LastDir(gurps.DefaultLastDirKey))
+		if dialog.RunModal() {
+			text := strings.TrimSpace(foldersField.Text())
+			if text != "" {
+				text += "\n"
+			}
+			foldersField.SetText(text + dialog.Path())
+		}
+	}
+	content.AddChild(addFolderButton)
+
+	adjustForKind := func() {
+		isS3 := kinds[providerPopup.SelectedIndex()] == cloudsync.S3Kind
+		regionField.SetEnabled(isS3)
+		bucketField.SetEnabled(isS3)
+	}
+	providerPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[string]) {
+		cfg.Kind = kinds[popup.SelectedIndex()]
+		adjustForKind()
+	}
+	adjustForKind()
+
+	okButton := unison.NewOKButtonInfo()
+	okButton.Title = i18n.Text("Save")
+	dialog, err := unison.NewDialog(&unison.DrawableSVG{
+		SVG:  svg.Download,
+		Size: geom.Size{Width: 48, Height: 48},
+	}, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), okButton},
+		unison.FloatingWindowOption(), unison.NotResizableWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	if err = cfg.Save(cloudSyncConfigPath()); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to save the cloud sync settings"), err)
+	}
+}
+
+// SyncWithCloud synchronizes the settings and sheet folders with the configured remote store. Files that are open are
+// left alone, and files that have changed both locally and remotely are presented to the user to decide between.
+func SyncWithCloud() {
+	if cloudSyncRunning {
+		return
+	}
+	cfg, err := cloudsync.LoadConfig(cloudSyncConfigPath())
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load the cloud sync settings"), err)
+		return
+	}
+	folders := cloudSyncFolders(cfg)
+	if !cfg.Enabled() || len(folders) == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("Cloud sync hasn't been set up"),
+			i18n.Text("Choose a provider and at least one thing to synchronize in the cloud sync settings first."))
+		return
+	}
+	var provider cloudsync.Provider
+	if provider, err = cfg.NewProvider(); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to synchronize"), err)
+		return
+	}
+	var state *cloudsync.State
+	if state, err = cloudsync.LoadState(cloudSyncStatePath(), cfg.Identity()); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to synchronize"), err)
+		return
+	}
+	if cfg.Settings {
+		// Make sure the file reflects what is currently in use.
+		if err = gurps.GlobalSettings().Save(); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to save the settings"), err)
+			return
+		}
+	}
+	open := make(map[string]bool)
+	for _, d := range AllDockables() {
+		if fbd, ok := d.(FileBackedDockable); ok {
+			open[filepath.Clean(fbd.BackingFilePath())] = true
+		}
+	}
+	syncer := &cloudsync.Syncer{
+		Provider: provider,
+		State:    state,
+		Busy:     func(localPath string) bool { return open[filepath.Clean(localPath)] },
+	}
+	cloudSyncRunning = true
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cloudSyncTimeout)
+		defer cancel()
+		result, err2 := syncer.Sync(ctx, folders...)
+		unison.InvokeTask(func() { finishCloudSync(syncer, result, err2) })
+	}()
+}
+
+func cloudSyncFolders(cfg *cloudsync.Config) []*cloudsync.Folder {
+	var folders []*cloudsync.Folder
+	if cfg.Settings {
+		settingsName := filepath.Base(gurps.SettingsPath)
+		folders = append(folders, &cloudsync.Folder{
+			Local:   filepath.Dir(gurps.SettingsPath),
+			Remote:  "settings",
+			Include: func(relPath string) bool { return relPath == settingsName },
+		})
+	}
+	used := make(map[string]bool)
+	for _, dir := range cfg.Folders {
+		name := filepath.Base(dir)
+		remote := "sheets/" + name
+		for i := 2; used[remote]; i++ {
+			remote = fmt.Sprintf("sheets/%s %d", name, i)
+		}
+		used[remote] = true
+		folders = append(folders, &cloudsync.Folder{
+			Local:  dir,
+			Remote: remote,
+		})
+	}
+	return folders
+}
+
+func finishCloudSync(syncer *cloudsync.Syncer, result *cloudsync.Result, err error) {
+	if err != nil {
+		cloudSyncRunning = false
+		Workspace.ErrorHandler(i18n.Text("Unable to synchronize"), err)
+		return
+	}
+	saveCloudSyncState(syncer)
+	applyCloudSyncDownloads(result.Downloaded)
+	if len(result.Conflicts) == 0 || !askForCloudSyncResolutions(result.Conflicts) {
+		cloudSyncRunning = false
+		showCloudSyncSummary(result, len(result.Conflicts))
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cloudSyncTimeout)
+		defer cancel()
+		resolved, err2 := syncer.Resolve(ctx, result.Conflicts...)
+		unison.InvokeTask(func() {
+			cloudSyncRunning = false
+			if err2 != nil {
+				Workspace.ErrorHandler(i18n.Text("Unable to resolve the conflicts"), err2)
+				return
+			}
+			saveCloudSyncState(syncer)
+			applyCloudSyncDownloads(resolved.Downloaded)
+			result.Uploaded = append(result.Uploaded, resolved.Uploaded...)
+			result.Downloaded = append(result.Downloaded, resolved.Downloaded...)
+			showCloudSyncSummary(result, 0)
+		})
+	}()
+}
+
+func saveCloudSyncState(syncer *cloudsync.Syncer) {
+	if err := syncer.State.Save(cloudSyncStatePath()); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to save the cloud sync state"), err)
+	}
+}
+
+func applyCloudSyncDownloads(paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	settingsPath := filepath.Clean(gurps.SettingsPath)
+	for _, p := range paths {
+		if filepath.Clean(p) == settingsPath {
+			if err := gurps.ReloadSettings(); err != nil {
+				Workspace.ErrorHandler(i18n.Text("Unable to load the synchronized settings"), err)
+				break
+			}
+			s := gurps.GlobalSettings()
+			s.KeyBindings.MakeCurrent()
+			s.General.UpdateToolTipTiming()
+			unison.ThemeChanged()
+			break
+		}
+	}
+	Workspace.Navigator.EventuallyReload()
+}
+
+// askForCloudSyncResolutions presents the conflicts to the user so they can choose which copy of each file to keep.
+// Returns false if the user declined to resolve them for now.
+func askForCloudSyncResolutions(conflicts []*cloudsync.Conflict) bool {
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.SetTitle(i18n.Text("These files were changed both here and elsewhere since they were last synchronized."))
+	content.AddChild(label)
+	label = unison.NewLabel()
+	label.SetTitle(i18n.Text("Choose which copy of each to keep. Keeping both saves the remote copy alongside this one."))
+	content.AddChild(label)
+
+	grid := unison.NewPanel()
+	grid.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	grid.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	for _, header := range []string{i18n.Text("File"), i18n.Text("This Machine"), i18n.Text("Remote"), i18n.Text("Keep")} {
+		label = unison.NewLabel()
+		label.Font = fonts.PageLabelSecondary
+		label.SetTitle(header)
+		grid.AddChild(label)
+	}
+	keepLocal := i18n.Text("This Machine's")
+	keepRemote := i18n.Text("Remote")
+	keepBoth := i18n.Text("Both")
+	for _, c := range conflicts {
+		label = unison.NewLabel()
+		label.SetTitle(c.RemotePath)
+		label.Tooltip = newWrappedTooltip(c.LocalPath)
+		grid.AddChild(label)
+
+		var modified time.Time
+		if fi, err := os.Stat(c.LocalPath); err == nil {
+			modified = fi.ModTime()
+		}
+		label = unison.NewLabel()
+		label.SetTitle(describeDocumentCopy(c.LocalPath, c.Local, modified))
+		grid.AddChild(label)
+
+		label = unison.NewLabel()
+		label.SetTitle(describeDocumentCopy(c.LocalPath, c.Remote, time.Time{}))
+		grid.AddChild(label)
+
+		popup := unison.NewPopupMenu[string]()
+		popup.AddItem(keepLocal, keepRemote, keepBoth)
+		popup.Select(keepBoth)
+		c.Resolution = cloudsync.KeepBoth
+		popup.SelectionChangedCallback = func(p *unison.PopupMenu[string]) {
+			if item, ok := p.Selected(); ok {
+				switch item {
+				case keepLocal:
+					c.Resolution = cloudsync.KeepLocal
+				case keepRemote:
+					c.Resolution = cloudsync.KeepRemote
+				default:
+					c.Resolution = cloudsync.KeepBoth
+				}
+			}
+		}
+		grid.AddChild(popup)
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(grid, behavior.Fill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		SizeHint: geom.Size{Width: 700, Height: 300},
+		HGrab:    true,
+		VGrab:    true,
+	})
+	content.AddChild(scroll)
+
+	okButton := unison.NewOKButtonInfo()
+	okButton.Title = i18n.Text("Resolve")
+	cancelButton := unison.NewCancelButtonInfo()
+	cancelButton.Title = i18n.Text("Later")
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		content, []*unison.DialogButtonInfo{cancelButton, okButton}, unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return false
+	}
+	return dialog.RunModal() == unison.ModalResponseOK
+}
+
+// describeDocumentCopy returns a short description of one copy of a file. For sheets, this includes the character's
+// name, points and when it was last modified, to help the user tell copies apart.
+func describeDocumentCopy(localPath string, data []byte, modified time.Time) string {
+	if strings.EqualFold(filepath.Ext(localPath), gurps.SheetExt) {
+		var sheet struct {
+			Profile struct {
+				Name string `json:"name"`
+			} `json:"profile"`
+			TotalPoints fxp.Int  `json:"total_points"`
+			ModifiedOn  jio.Time `json:"modified_date"`
+		}
+		if err := json.Unmarshal(data, &sheet); err == nil {
+			return fmt.Sprintf(i18n.Text("%s, %s pts, modified %s"), sheet.Profile.Name, sheet.TotalPoints.Comma(),
+				sheet.ModifiedOn.String())
+		}
+	}
+	if modified.IsZero() {
+		return fmt.Sprintf(i18n.Text("%d bytes"), len(data))
+	}
+	return fmt.Sprintf(i18n.Text("%d bytes, modified %s"), len(data), modified.Format(time.DateTime))
+}
+
+func showCloudSyncSummary(result *cloudsync.Result, unresolved int) {
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, i18n.Text("Uploaded: %d\nDownloaded: %d"), len(result.Uploaded), len(result.Downloaded))
+	if unresolved != 0 {
+		fmt.Fprintf(&buffer, i18n.Text("\nConflicts left for later: %d"), unresolved)
+	}
+	if len(result.Skipped) != 0 {
+		buffer.WriteString(i18n.Text("\n\nThese files are open and were skipped. Close them and synchronize again:"))
+		for _, p := range result.Skipped {
+			buffer.WriteString("\n")
+			buffer.WriteString(p)
+		}
+	}
+	label := unison.NewLabel()
+	label.SetTitle(i18n.Text("Synchronization complete"))
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	content.AddChild(label)
+	for line := range strings.SplitSeq(buffer.String(), "\n") {
+		label = unison.NewLabel()
+		label.SetTitle(line)
+		content.AddChild(label)
+	}
+	dialog, err := unison.NewDialog(&unison.DrawableSVG{
+		SVG:  svg.Download,
+		Size: geom.Size{Width: 48, Height: 48},
+	}, unison.DefaultDialogTheme.QuestionIconInk, content, []*unison.DialogButtonInfo{unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption(), unison.NotResizableWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	dialog.RunModal()
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+const commandPalettePathSeparator = " › "
+
+// paletteChoice is a command, or an open dockable to switch to, offered by the command palette.
+type paletteChoice struct {
+	title  string
+	detail string
+	run    func()
+	score  int
+}
+
+func (c *paletteChoice) String() string {
+	if c.detail == "" {
+		return c.title
+	}
+	return c.title + " — " + c.detail
+}
+
+// ShowCommandPalette shows a searchable list of the available menu commands and open dockables, running the one chosen.
+func ShowCommandPalette() {
+	wnd := unison.ActiveWindow()
+	var focus *unison.Panel
+	if wnd != nil {
+		focus = wnd.Focus()
+	}
+	candidates := commandPaletteChoices()
+	var dialog *unison.Dialog
+	list := unison.NewList[*paletteChoice]()
+	list.SetAllowMultipleSelection(false)
+	list.NewSelectionCallback = func() {
+		if dialog != nil {
+			dialog.Button(unison.ModalResponseOK).SetEnabled(list.Selection.Count() != 0)
+		}
+	}
+	list.DoubleClickCallback = func() {
+		if dialog != nil && list.Selection.Count() != 0 {
+			dialog.Button(unison.ModalResponseOK).Click()
+		}
+	}
+	search := func(text string) {
+		list.Clear()
+		list.Append(searchCommandPalette(candidates, text)...)
+		if list.Count() != 0 {
+			list.Select(false, 0)
+		} else if list.NewSelectionCallback != nil {
+			list.NewSelectionCallback()
+		}
+		list.MarkForLayoutRecursivelyUpward()
+		list.MarkForRedraw()
+	}
+	field := NewSearchField(i18n.Text("Search commands and open documents"),
+		func(_, after *unison.FieldState) { search(after.Text) })
+	field.KeyDownCallback = func(keyCode unison.KeyCode, mod unison.Modifiers, repeat bool) bool {
+		if mod == 0 && list.Count() != 0 {
+			index := list.Selection.FirstSet()
+			switch keyCode {
+			case unison.KeyUp:
+				index = max(index-1, 0)
+			case unison.KeyDown:
+				index = min(index+1, list.Count()-1)
+			case unison.KeyPageUp:
+				index = 0
+			case unison.KeyPageDown:
+				index = list.Count() - 1
+			default:
+				return field.DefaultKeyDown(keyCode, mod, repeat)
+			}
+			list.Select(false, index)
+			list.ScrollRectIntoView(list.RowRect(index))
+			return true
+		}
+		return field.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{}, geom.NewUniformInsets(1), false))
+	scroll.SetContent(list, behavior.Fill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: geom.Size{Width: 500, Height: 400},
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(field)
+	panel.AddChild(scroll)
+	search("")
+
+	var err error
+	if dialog, err = unison.NewDialog(nil, nil, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfoWithTitle(i18n.Text("Run"))},
+	); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create command palette"), err)
+		return
+	}
+	dialog.Button(unison.ModalResponseOK).SetEnabled(list.Selection.Count() != 0)
+	field.RequestFocus()
+	if dialog.RunModal() != unison.ModalResponseOK || list.Selection.Count() == 0 {
+		return
+	}
+	choice := list.DataAtIndex(list.Selection.FirstSet())
+	// Commands that are routed to the focus need the window the palette was opened from to be active again.
+	if wnd != nil && wnd.IsValid() {
+		wnd.ToFront()
+		if focus != nil {
+			focus.RequestFocus()
+		}
+	}
+	unison.InvokeTask(choice.run)
+}
+
+// commandPaletteChoices returns the open dockables, followed by the menu commands that are currently enabled.
+func commandPaletteChoices() []*paletteChoice {
+	var list []*paletteChoice
+	goTo := i18n.Text("Go To")
+	for _, d := range AllDockables() {
+		list = append(list, &paletteChoice{
+			title:  goTo + commandPalettePathSeparator + d.Title(),
+			detail: d.Tooltip(),
+			run:    func() { ActivateDockable(d) },
+		})
+	}
+	paths := menuItemPaths()
+	commands := make([]*paletteChoice, 0, 256)
+	for _, b := range gurps.CurrentBindings() {
+		action := b.Action
+		if action.ID == CommandPaletteItemID || !action.Enabled(nil) {
+			continue
+		}
+		title, ok := paths[action.ID]
+		if !ok {
+			title = strings.TrimSuffix(action.Title, "…")
+		}
+		commands = append(commands, &paletteChoice{
+			title:  title,
+			detail: action.KeyBinding.String(),
+			run:    func() { action.Execute(nil) },
+		})
+	}
+	slices.SortStableFunc(commands, func(a, b *paletteChoice) int { return xstrings.NaturalCmp(a.title, b.title, true) })
+	return append(list, commands...)
+}
+
+// menuItemPaths returns the location of each item in the menu bar, keyed by the item's ID, e.g. "File › Save As".
+func menuItemPaths() map[int]string {
+	m := make(map[int]string)
+	if Workspace.Window != nil {
+		if bar := unison.DefaultMenuFactory().BarForWindowNoCreate(Workspace.Window); bar != nil {
+			collectMenuItemPaths(m, bar, "")
+		}
+	}
+	return m
+}
+
+func collectMenuItemPaths(m map[int]string, menu unison.Menu, prefix string) {
+	for i := range menu.Count() {
+		item := menu.ItemAtIndex(i)
+		if item == nil || item.IsSeparator() {
+			continue
+		}
+		path := prefix + strings.TrimSuffix(item.Title(), "…")
+		if sub := item.SubMenu(); sub != nil {
+			collectMenuItemPaths(m, sub, path+commandPalettePathSeparator)
+			continue
+		}
+		if _, exists := m[item.ID()]; !exists {
+			m[item.ID()] = path
+		}
+	}
+}
+
+// searchCommandPalette returns the choices that fuzzy match the text, best matches first.
+func searchCommandPalette(candidates []*paletteChoice, text string) []*paletteChoice {
+	var list []*paletteChoice
+	for _, one := range candidates {
+		score, ok := gurps.FuzzyMatch(text, one.title)
+		if !ok {
+			if score, ok = gurps.FuzzyMatch(text, one.String()); !ok {
+				continue
+			}
+			score--
+		}
+		one.score = score
+		list = append(list, one)
+	}
+	slices.SortStableFunc(list, func(a, b *paletteChoice) int { return cmp.Compare(b.score, a.score) })
+	return list
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/unison"
+)
+
+// BuildCompendium asks the user for one or more character sheets, then writes library files containing just the
+// traits, skills, spells, and equipment those sheets use.
+func BuildCompendium() {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(true)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.SheetExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	if !dialog.RunModal() {
+		return
+	}
+	paths := dialog.Paths()
+	global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(paths[0]))
+	entities := make([]*gurps.Entity, 0, len(paths))
+	for _, p := range paths {
+		entity, err := gurps.NewEntityFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p))
+		if err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to load character sheet:\n")+p, err)
+			return
+		}
+		entities = append(entities, entity)
+	}
+	compendium := gurps.NewCompendium(entities...)
+	if compendium.Empty() {
+		unison.WarningDialogWithMessage(i18n.Text("Nothing to include"),
+			i18n.Text("The selected character sheets don't contain any traits, skills, spells, or equipment."))
+		return
+	}
+
+	saveDialog := unison.NewSaveDialog()
+	saveDialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	saveDialog.SetInitialFileName(i18n.Text("Compendium"))
+	if !saveDialog.RunModal() {
+		return
+	}
+	savePath := saveDialog.Path()
+	dir := filepath.Dir(savePath)
+	global.SetLastDir(gurps.DefaultLastDirKey, dir)
+	baseName := xfilepath.SanitizeName(strings.TrimSuffix(filepath.Base(savePath), filepath.Ext(savePath)))
+	written, err := compendium.Save(dir, baseName)
+	Workspace.Navigator.EventuallyReload()
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to save compendium"), err)
+		return
+	}
+	OpenFiles(written)
+}
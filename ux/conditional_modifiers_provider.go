@@ -105,6 +105,7 @@ func (p *condModProvider) SyncHeader(_ []unison.TableColumnHeader[*Node[*gurps.C
 
 func (p *condModProvider) ColumnIDs() []int {
 	return []int{
+		gurps.ConditionalModifierActiveColumn,
 		gurps.ConditionalModifierValueColumn,
 		gurps.ConditionalModifierDescriptionColumn,
 	}
@@ -0,0 +1,354 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/feature"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var _ pageHelper = &ConditionsPanel{}
+
+// ConditionsPanel holds the list of conditions currently affecting a character.
+type ConditionsPanel struct {
+	unison.Panel
+	entity    *gurps.Entity
+	editable  bool
+	hash      uint64
+	drawStart int
+	drawEnd   int
+}
+
+// NewConditionsPanel creates a new conditions panel. If editable is true, controls for advancing turns and removing
+// conditions will be included.
+func NewConditionsPanel(entity *gurps.Entity, editable bool) *ConditionsPanel {
+	p := &ConditionsPanel{
+		entity:   entity,
+		editable: editable,
+		drawEnd:  1,
+	}
+	p.Self = p
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: i18n.Text("Conditions")},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(2, 1))))
+	p.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	p.rebuild()
+	return p
+}
+
+// HasConditions returns true if at least one condition is active.
+func (p *ConditionsPanel) HasConditions() bool {
+	return len(p.entity.Conditions) != 0
+}
+
+func (p *ConditionsPanel) columns() int {
+	if p.editable {
+		return 4
+	}
+	return 3
+}
+
+func (p *ConditionsPanel) rebuild() {
+	p.hash = gurps.HashConditions(p.entity.Conditions)
+	p.RemoveAllChildren()
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  p.columns(),
+		HSpacing: 4,
+	})
+	for _, title := range []string{
+		i18n.Text("Condition"),
+		i18n.Text("Turns"),
+		i18n.Text("Effects"),
+	} {
+		label := NewPageLabel(title)
+		label.Font = fonts.PageLabelSecondary
+		p.AddChild(label)
+	}
+	if p.editable {
+		b := unison.NewSVGButton(svg.Forward)
+		b.Tooltip = newWrappedTooltip(i18n.Text("Advance one turn, removing any conditions that expire"))
+		b.ClickCallback = func() {
+			editConditions(p, p.entity, i18n.Text("Next Turn"), func() { p.entity.AdvanceConditions(1) })
+		}
+		p.AddChild(b)
+	}
+	for _, c := range p.entity.Conditions {
+		name := NewPageLabel(c.Name)
+		if c.Notes != "" {
+			name.Tooltip = newWrappedTooltip(c.Notes)
+		}
+		p.AddChild(name)
+		turns := "∞"
+		if c.Timed() {
+			turns = strconv.Itoa(c.Turns)
+		}
+		turnsLabel := NewPageLabelEnd(turns)
+		turnsLabel.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+		p.AddChild(turnsLabel)
+		effects := NewPageLabel(conditionEffects(p.entity, c))
+		effects.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		p.AddChild(effects)
+		if p.editable {
+			p.AddChild(p.createRemoveButton(c))
+		}
+	}
+}
+
+func (p *ConditionsPanel) createRemoveButton(c *gurps.Condition) *unison.Button {
+	b := unison.NewSVGButton(svg.Trash)
+	b.Tooltip = newWrappedTooltip(i18n.Text("Remove this condition"))
+	b.ClickCallback = func() {
+		editConditions(p, p.entity, i18n.Text("Remove Condition"), func() { p.entity.RemoveCondition(c) })
+	}
+	return b
+}
+
+func conditionEffects(entity *gurps.Entity, c *gurps.Condition) string {
+	parts := make([]string, 0, len(c.Features))
+	for _, f := range c.Features {
+		switch actual := f.(type) {
+		case *gurps.AttributeBonus:
+			name := actual.Attribute
+			if def := gurps.AttributeDefsFor(entity).Set[actual.Attribute]; def != nil {
+				name = def.Name
+			} else if actual.Attribute == gurps.DodgeID {
+				name = i18n.Text("Dodge")
+			}
+			parts = append(parts, fmt.Sprintf("%s %s", name, actual.Amount.StringWithSign()))
+		case *gurps.WeaponBonus:
+			switch actual.Type {
+			case feature.WeaponParryBonus:
+				parts = append(parts, fmt.Sprintf(i18n.Text("Parry %s"), actual.Amount.StringWithSign()))
+			case feature.WeaponBlockBonus:
+				parts = append(parts, fmt.Sprintf(i18n.Text("Block %s"), actual.Amount.StringWithSign()))
+			default:
+				parts = append(parts, actual.Type.String())
+			}
+		default:
+			parts = append(parts, f.FeatureType().String())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Sync the panel to the current data.
+func (p *ConditionsPanel) Sync() {
+	if gurps.HashConditions(p.entity.Conditions) != p.hash {
+		p.rebuild()
+	}
+	MarkForLayoutWithinDockable(p)
+}
+
+// OverheadHeight implements pageHelper.
+func (p *ConditionsPanel) OverheadHeight() float32 {
+	return 0
+}
+
+// RowHeights implements pageHelper. The conditions are always kept together, so they are reported as a single row.
+func (p *ConditionsPanel) RowHeights() []float32 {
+	return []float32{p.FrameRect().Height}
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *ConditionsPanel) CurrentDrawRowRange() (start, endBefore int) {
+	return p.drawStart, p.drawEnd
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *ConditionsPanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+}
+
+func editConditions(src unison.Paneler, entity *gurps.Entity, name string, edit func()) {
+	owner := unison.AncestorOrSelf[Rebuildable](src)
+	before := gurps.CloneConditionList(entity.Conditions)
+	edit()
+	after := gurps.CloneConditionList(entity.Conditions)
+	if mgr := unison.UndoManagerFor(src); mgr != nil && owner != nil {
+		addUndo(mgr, &unison.UndoEdit[[]*gurps.Condition]{
+			ID:       unison.NextUndoID(),
+			EditName: name,
+			UndoFunc: func(e *unison.UndoEdit[[]*gurps.Condition]) {
+				entity.SetConditions(e.BeforeData)
+				owner.Rebuild(true)
+			},
+			RedoFunc: func(e *unison.UndoEdit[[]*gurps.Condition]) {
+				entity.SetConditions(e.AfterData)
+				owner.Rebuild(true)
+			},
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	if owner != nil {
+		owner.Rebuild(true)
+	}
+}
+
+type conditionKind int
+
+const (
+	stunnedConditionKind conditionKind = iota
+	shockConditionKind
+	afflictionConditionKind
+	spellConditionKind
+	otherConditionKind
+)
+
+var conditionKinds = []conditionKind{
+	stunnedConditionKind,
+	shockConditionKind,
+	afflictionConditionKind,
+	spellConditionKind,
+	otherConditionKind,
+}
+
+func (k conditionKind) String() string {
+	switch k {
+	case stunnedConditionKind:
+		return i18n.Text("Stunned")
+	case shockConditionKind:
+		return i18n.Text("Shock")
+	case afflictionConditionKind:
+		return i18n.Text("Affliction")
+	case spellConditionKind:
+		return i18n.Text("Spell")
+	default:
+		return i18n.Text("Other")
+	}
+}
+
+// ShowAddConditionDialog asks for the details of a new condition and applies it to the sheet's character.
+func ShowAddConditionDialog(sheet *Sheet) {
+	entity := sheet.Entity()
+	if c := askForCondition(entity); c != nil {
+		editConditions(sheet, entity, i18n.Text("Add Condition"), func() { entity.AddCondition(c) })
+	}
+}
+
+// askForCondition asks for the details of a new condition for the entity. Returns nil if the user cancels.
+func askForCondition(entity *gurps.Entity) *gurps.Condition {
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	kind := stunnedConditionKind
+	name := kind.String()
+	turns := 1
+	attrID := gurps.DexterityID
+	amount := -fxp.One
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Kind"), false))
+	kindPopup := addPopup(content, conditionKinds, &kind)
+
+	nameLabel := i18n.Text("Name")
+	content.AddChild(NewFieldLeadingLabel(nameLabel, false))
+	nameField := NewStringField(nil, "", nameLabel, func() string { return name }, func(s string) { name = s })
+	content.AddChild(nameField)
+
+	turnsLabel := i18n.Text("Turns")
+	content.AddChild(NewFieldLeadingLabel(turnsLabel, false))
+	turnsField := NewIntegerField(nil, "", turnsLabel, func() int { return turns }, func(v int) { turns = v }, 0,
+		9999, false, false)
+	turnsField.Tooltip = newWrappedTooltip(i18n.Text("The number of turns the condition lasts. Use 0 for a condition that lasts until removed."))
+	content.AddChild(turnsField)
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Modifies"), false))
+	attrPopup := addAttributeChoicePopup(content, entity, "", &attrID, gurps.DodgeFlag)
+
+	amountLabel := i18n.Text("Amount")
+	content.AddChild(NewFieldLeadingLabel(amountLabel, false))
+	amountField := NewDecimalField(nil, "", amountLabel, func() fxp.Int { return amount },
+		func(v fxp.Int) { amount = v }, fxp.Min, fxp.Max, true, false)
+	content.AddChild(amountField)
+
+	adjustForKind := func() {
+		attrPopup.SetEnabled(kind > shockConditionKind)
+		amountField.SetEnabled(kind != stunnedConditionKind)
+	}
+	kindPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[conditionKind]) {
+		if k, ok := popup.Selected(); ok {
+			kind = k
+			if kind <= shockConditionKind {
+				name = kind.String()
+				turns = 1
+			} else {
+				name = ""
+				turns = 0
+			}
+			if kind == shockConditionKind {
+				amount = fxp.FromInteger(min(entity.ShockPenalty(), -1))
+			}
+			nameField.Sync()
+			turnsField.Sync()
+			amountField.Sync()
+			adjustForKind()
+		}
+	}
+	adjustForKind()
+
+	icon := &unison.DrawableSVG{
+		SVG:  svg.MagicWand,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption(), unison.NotResizableWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return nil
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return nil
+	}
+	var c *gurps.Condition
+	switch kind {
+	case stunnedConditionKind:
+		c = gurps.NewStunnedCondition()
+	case shockConditionKind:
+		c = gurps.NewShockCondition(fxp.AsInteger[int](amount))
+	default:
+		c = gurps.NewCondition(kind.String(), 0)
+		if amount != 0 {
+			c.AddAttributeModifier(attrID, amount)
+		}
+	}
+	if name = strings.TrimSpace(name); name != "" {
+		c.Name = name
+	}
+	c.Turns = turns
+	return c
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+// ShowCustomBlockEditor shows a dialog for editing the title, fields and text of a custom block, updating the block in
+// place. Returns true if the user accepted the changes.
+func ShowCustomBlockEditor(block *gurps.CustomBlock) bool {
+	working := block.Clone()
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	titleLabel := i18n.Text("Title")
+	content.AddChild(NewFieldLeadingLabel(titleLabel, false))
+	titleField := NewStringField(nil, "", titleLabel, func() string { return working.Title },
+		func(s string) { working.Title = s })
+	titleField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	content.AddChild(titleField)
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Fields"), false))
+	fields := newRitualListPanel()
+	addField := func(field *gurps.CustomBlockField) {
+		row := newRitualRowPanel(4)
+		labelLabel := i18n.Text("Label")
+		labelField := NewStringField(nil, "", labelLabel, func() string { return field.Label },
+			func(s string) { field.Label = s })
+		labelField.Watermark = labelLabel
+		labelField.SetMinimumTextWidthUsing("Remaining Charges")
+		row.AddChild(labelField)
+		valueLabel := i18n.Text("Value")
+		valueField := NewStringField(nil, "", valueLabel, func() string { return field.Value },
+			func(s string) { field.Value = s })
+		valueField.Watermark = valueLabel
+		valueField.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		row.AddChild(valueField)
+		computed := unison.NewCheckBox()
+		computed.SetTitle(i18n.Text("Computed"))
+		computed.Tooltip = newWrappedTooltip(i18n.Text(`When checked, the value is a script expression that is evaluated to produce the field's contents, e.g. "$st.value * 2"`))
+		computed.State = check.FromBool(field.Computed)
+		computed.ClickCallback = func() { field.Computed = computed.State == check.On }
+		row.AddChild(computed)
+		row.AddChild(newRitualRemoveButton(i18n.Text("Remove this field"), func() {
+			if i := slices.Index(working.Fields, field); i != -1 {
+				working.Fields = slices.Delete(working.Fields, i, i+1)
+			}
+			row.RemoveFromParent()
+			MarkRootAncestorForLayoutRecursively(fields)
+		}))
+		fields.AddChild(row)
+	}
+	for _, one := range working.Fields {
+		addField(one)
+	}
+	content.AddChild(newRitualAddButton(i18n.Text("Add a field"), func() {
+		field := &gurps.CustomBlockField{}
+		working.Fields = append(working.Fields, field)
+		addField(field)
+		MarkRootAncestorForLayoutRecursively(fields)
+	}))
+	content.AddChild(unison.NewPanel())
+	content.AddChild(fields)
+
+	textLabel := i18n.Text("Text")
+	content.AddChild(NewFieldLeadingLabel(textLabel, false))
+	textField := NewMultiLineStringField(nil, "", textLabel, func() string { return working.Text },
+		func(s string) { working.Text = s })
+	textField.Tooltip = newWrappedTooltip(i18n.Text("Free text shown beneath the fields. Markdown is supported."))
+	textField.SetLayoutData(&unison.FlexLayoutData{
+		MinSize: geom.NewSize(400, 0),
+		HAlign:  align.Fill,
+		HGrab:   true,
+	})
+	content.AddChild(textField)
+
+	icon := &unison.DrawableSVG{
+		SVG:  svg.GCSSheet,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return false
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return false
+	}
+	working.Title = strings.TrimSpace(working.Title)
+	*block = *working
+	return true
+}
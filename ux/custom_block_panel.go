@@ -0,0 +1,133 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var _ pageHelper = &CustomBlockPanel{}
+
+// CustomBlockPanel holds the contents of a user-defined block.
+type CustomBlockPanel struct {
+	unison.Panel
+	entity    *gurps.Entity
+	targetMgr *TargetMgr
+	prefix    string
+	key       string
+	border    *TitledBorder
+	drawStart int
+	drawEnd   int
+}
+
+// NewCustomBlockPanel creates a new custom block panel for the block with the given block layout key. If targetMgr is
+// nil, the fields will not be editable.
+func NewCustomBlockPanel(entity *gurps.Entity, key string, targetMgr *TargetMgr) *CustomBlockPanel {
+	p := &CustomBlockPanel{
+		entity:    entity,
+		targetMgr: targetMgr,
+		key:       key,
+		border:    &TitledBorder{},
+		drawEnd:   1,
+	}
+	if targetMgr != nil {
+		p.prefix = targetMgr.NextPrefix()
+	}
+	p.Self = p
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewCompoundBorder(p.border, unison.NewEmptyBorder(geom.NewSymmetricInsets(2, 1))))
+	p.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	p.rebuild()
+	return p
+}
+
+func (p *CustomBlockPanel) block() *gurps.CustomBlock {
+	return p.entity.SheetSettings.CustomBlockFor(p.key)
+}
+
+// HasContent returns true if the block still exists and has something to show.
+func (p *CustomBlockPanel) HasContent() bool {
+	block := p.block()
+	return block != nil && !block.Empty()
+}
+
+func (p *CustomBlockPanel) rebuild() {
+	p.RemoveAllChildren()
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: 4,
+	})
+	block := p.block()
+	if block == nil {
+		return
+	}
+	p.border.Title = block.TitleOrDefault()
+	for i, field := range block.Fields {
+		p.AddChild(NewPageLabelEnd(field.Label))
+		if field.Computed || p.targetMgr == nil {
+			value := NewNonEditablePageField(func(f *NonEditablePageField) {
+				if text := field.ResolvedValue(p.entity); text != f.Text.String() {
+					f.SetTitle(text)
+					MarkForLayoutWithinDockable(f)
+				}
+			})
+			value.SetLayoutData(&unison.FlexLayoutData{
+				HAlign: align.Fill,
+				HGrab:  true,
+			})
+			p.AddChild(value)
+		} else {
+			p.AddChild(NewStringPageField(p.targetMgr, p.prefix+strconv.Itoa(i), field.Label,
+				func() string { return field.Value },
+				func(s string) { field.Value = s }))
+		}
+	}
+	if text := strings.TrimSpace(block.Text); text != "" {
+		for _, line := range strings.Split(text, "\n") {
+			label := NewPageLabel(line)
+			label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+			p.AddChild(label)
+		}
+	}
+}
+
+// OverheadHeight implements pageHelper.
+func (p *CustomBlockPanel) OverheadHeight() float32 {
+	return 0
+}
+
+// RowHeights implements pageHelper. The block is always kept together, so it is reported as a single row.
+func (p *CustomBlockPanel) RowHeights() []float32 {
+	return []float32{p.FrameRect().Height}
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *CustomBlockPanel) CurrentDrawRowRange() (start, endBefore int) {
+	return p.drawStart, p.drawEnd
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *CustomBlockPanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+}
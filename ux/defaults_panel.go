@@ -30,7 +30,7 @@ type defaultsPanel struct {
 	defaults *[]*gurps.SkillDefault
 }
 
-func newDefaultsPanel(entity *gurps.Entity, defaults *[]*gurps.SkillDefault) *defaultsPanel {
+func newDefaultsPanel(entity *gurps.Entity, title string, defaults *[]*gurps.SkillDefault) *defaultsPanel {
 	p := &defaultsPanel{
 		entity:   entity,
 		defaults: defaults,
@@ -48,7 +48,7 @@ func newDefaultsPanel(entity *gurps.Entity, defaults *[]*gurps.SkillDefault) *de
 	})
 	p.SetBorder(unison.NewCompoundBorder(
 		&TitledBorder{
-			Title: i18n.Text("Defaults"),
+			Title: title,
 			Font:  unison.LabelFont,
 		},
 		unison.NewEmptyBorder(geom.NewUniformInsets(2))))
@@ -161,9 +161,18 @@ func (d *DescriptionPanel) createColumn2() *unison.Panel {
 	column := createColumn()
 
 	title := i18n.Text("Height")
-	heightField := NewHeightPageField(d.targetMgr, descriptionPanelHeightFieldRefKey, title, d.entity,
+	var heightField *LengthField
+	heightField = NewHeightPageField(d.targetMgr, descriptionPanelHeightFieldRefKey, title, d.entity,
 		func() fxp.Length { return d.entity.Profile.Height },
-		func(v fxp.Length) { d.entity.Profile.Height = v }, 0, fxp.Length(fxp.Max), true)
+		func(v fxp.Length) {
+			d.entity.Profile.Height = v
+			// When the size modifier is derived from the height, everything that keys off of it needs to be synced.
+			if d.entity.Profile.SizeFromHeight {
+				delete(heightField.ClientData(), SkipDeepSync)
+			} else {
+				heightField.ClientData()[SkipDeepSync] = true
+			}
+		}, 0, fxp.Length(fxp.Max), true)
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the height using the current ancestry"), func() {
 			d.entity.Profile.Height = d.entity.Ancestry().RandomHeight(d.entity, d.entity.Profile.Gender, d.entity.Profile.Height)
@@ -190,6 +199,7 @@ func (d *DescriptionPanel) createColumn2() *unison.Panel {
 		func() int { return d.entity.Profile.AdjustedSizeModifier() },
 		func(v int) { d.entity.Profile.SetAdjustedSizeModifier(v) }, -99, 99, true, false)
 	field.HAlign = align.Start
+	field.Tooltip = newWrappedTooltip(i18n.Text("When the sheet settings derive the Size Modifier from height, edits here are ignored"))
 	column.AddChild(field)
 
 	title = i18n.Text("TL")
@@ -0,0 +1,379 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/diag"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+const (
+	diagnosticsColumns   = 5
+	diagnosticsBundleExt = ".zip"
+)
+
+var (
+	_ unison.Dockable  = &diagnosticsDockable{}
+	_ unison.TabCloser = &diagnosticsDockable{}
+)
+
+// diagnosticsMetrics is what is written into a diagnostic bundle. It intentionally holds no paths or names.
+type diagnosticsMetrics struct {
+	Version        string                 `json:"version"`
+	OS             string                 `json:"os"`
+	Arch           string                 `json:"arch"`
+	CPUs           int                    `json:"cpus"`
+	Captured       time.Time              `json:"captured"`
+	Memory         diag.Memory            `json:"memory"`
+	Libraries      int                    `json:"libraries"`
+	IndexedFiles   int                    `json:"indexed_files"`
+	IndexedItems   int                    `json:"indexed_items"`
+	OpenDockables  int                    `json:"open_dockables"`
+	Timings        []diagnosticsTiming    `json:"timings,omitempty"`
+	SlowOperations []diagnosticsSlowEntry `json:"slow_operations,omitempty"`
+}
+
+type diagnosticsTiming struct {
+	Key     string `json:"key"`
+	Count   int    `json:"count"`
+	Average string `json:"average"`
+	Longest string `json:"longest"`
+	Last    string `json:"last"`
+}
+
+type diagnosticsSlowEntry struct {
+	Key      string    `json:"key"`
+	When     time.Time `json:"when"`
+	Duration string    `json:"duration"`
+}
+
+// diagnosticsDockable shows how long recent operations took and how much memory is in use, so that performance
+// problems can be investigated and reported.
+type diagnosticsDockable struct {
+	unison.Panel
+	content *unison.Panel
+	scroll  *unison.ScrollPanel
+}
+
+// ShowDiagnostics shows the diagnostics.
+func ShowDiagnostics() {
+	if Activate(func(d unison.Dockable) bool {
+		_, ok := d.AsPanel().Self.(*diagnosticsDockable)
+		return ok
+	}) {
+		return
+	}
+	d := &diagnosticsDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  diagnosticsColumns,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.scroll = unison.NewScrollPanel()
+	d.scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+	d.scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	d.AddChild(d.createToolbar())
+	d.AddChild(d.scroll)
+	d.rebuild()
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *diagnosticsDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+
+	refreshButton := unison.NewSVGButton(svg.Reset)
+	refreshButton.Tooltip = newWrappedTooltip(i18n.Text("Refresh"))
+	refreshButton.ClickCallback = d.rebuild
+	toolbar.AddChild(refreshButton)
+
+	clearButton := unison.NewSVGButton(svg.Trash)
+	clearButton.Tooltip = newWrappedTooltip(i18n.Text("Forget the timings recorded so far"))
+	clearButton.ClickCallback = func() {
+		diag.Reset()
+		d.rebuild()
+	}
+	toolbar.AddChild(clearButton)
+
+	saveButton := unison.NewSVGButton(svg.Download)
+	saveButton.Tooltip = newWrappedTooltip(i18n.Text(`Save a diagnostic bundle to attach to a bug report.
+The bundle contains the log files and the information shown here, with your home directory and user name removed.`))
+	saveButton.ClickCallback = d.saveBundle
+	toolbar.AddChild(saveButton)
+
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *diagnosticsDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	m := collectDiagnosticsMetrics()
+
+	d.addHeader(i18n.Text("Memory"), 0)
+	d.addValue(i18n.Text("Heap In Use"), formatDiagnosticsBytes(m.Memory.HeapInUse))
+	d.addValue(i18n.Text("Heap Objects"), fmt.Sprint(m.Memory.HeapObjects))
+	d.addValue(i18n.Text("Obtained From System"), formatDiagnosticsBytes(m.Memory.System))
+	d.addValue(i18n.Text("Total Allocated"), formatDiagnosticsBytes(m.Memory.TotalAlloc))
+	d.addValue(i18n.Text("Garbage Collections"), fmt.Sprint(m.Memory.GCCycles))
+	d.addValue(i18n.Text("Goroutines"), fmt.Sprint(m.Memory.Goroutines))
+
+	d.addHeader(i18n.Text("Libraries"), unison.StdVSpacing*2)
+	d.addValue(i18n.Text("Libraries"), fmt.Sprint(m.Libraries))
+	d.addValue(i18n.Text("Indexed Files"), fmt.Sprint(m.IndexedFiles))
+	d.addValue(i18n.Text("Indexed Items"), fmt.Sprint(m.IndexedItems))
+	d.addValue(i18n.Text("Open Tabs"), fmt.Sprint(m.OpenDockables))
+
+	d.addHeader(i18n.Text("Timings"), unison.StdVSpacing*2)
+	if len(m.Timings) == 0 {
+		d.addNote(i18n.Text("Nothing has been timed yet."))
+	} else {
+		d.addRow(true, i18n.Text("Operation"), i18n.Text("Count"), i18n.Text("Average"), i18n.Text("Longest"),
+			i18n.Text("Last"))
+		for _, t := range m.Timings {
+			d.addRow(false, diagnosticsOperationTitle(t.Key), fmt.Sprint(t.Count), t.Average, t.Longest, t.Last)
+		}
+	}
+
+	d.addHeader(fmt.Sprintf(i18n.Text("Operations Taking %v or Longer"), diag.SlowThreshold), unison.StdVSpacing*2)
+	if len(m.SlowOperations) == 0 {
+		d.addNote(i18n.Text("None."))
+	} else {
+		d.addRow(true, i18n.Text("Operation"), i18n.Text("Started"), i18n.Text("Duration"))
+		for _, one := range m.SlowOperations {
+			d.addRow(false, diagnosticsOperationTitle(one.Key), one.When.Local().Format(time.TimeOnly), one.Duration)
+		}
+	}
+	d.scroll.MarkForLayoutAndRedraw()
+}
+
+func (d *diagnosticsDockable) addHeader(text string, topMargin float32) {
+	label := unison.NewLabel()
+	label.Font = diagnosticsBoldFont()
+	label.SetTitle(text)
+	if topMargin > 0 {
+		label.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: topMargin}))
+	}
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: diagnosticsColumns})
+	d.content.AddChild(label)
+}
+
+func (d *diagnosticsDockable) addValue(title, value string) {
+	d.content.AddChild(NewFieldLeadingLabel(title, false))
+	label := unison.NewLabel()
+	label.SetTitle(value)
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: diagnosticsColumns - 1})
+	d.content.AddChild(label)
+}
+
+func (d *diagnosticsDockable) addNote(text string) {
+	label := unison.NewLabel()
+	label.SetTitle(text)
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: diagnosticsColumns})
+	d.content.AddChild(label)
+}
+
+func (d *diagnosticsDockable) addRow(header bool, cells ...string) {
+	for i, text := range cells {
+		label := unison.NewLabel()
+		if header {
+			label.Font = diagnosticsBoldFont()
+		}
+		label.SetTitle(text)
+		data := &unison.FlexLayoutData{HAlign: align.End}
+		if i == 0 {
+			data.HAlign = align.Start
+		}
+		if i == len(cells)-1 {
+			data.HSpan = diagnosticsColumns - i
+			data.HAlign = align.Start
+		}
+		label.SetLayoutData(data)
+		d.content.AddChild(label)
+	}
+}
+
+func (d *diagnosticsDockable) saveBundle() {
+	dialog := unison.NewSaveDialog()
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	dialog.SetAllowedExtensions(diagnosticsBundleExt)
+	if !dialog.RunModal() {
+		return
+	}
+	p, ok := unison.ValidateSaveFilePath(dialog.Path(), diagnosticsBundleExt, false)
+	if !ok {
+		return
+	}
+	global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(p))
+	if err := diag.WriteBundleFile(p, collectDiagnosticsMetrics(), diagnosticsLogPaths(),
+		newDiagnosticsAnonymizer()); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to save diagnostic bundle"), err)
+	}
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *diagnosticsDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Info,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *diagnosticsDockable) Title() string {
+	return i18n.Text("Diagnostics")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *diagnosticsDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *diagnosticsDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *diagnosticsDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *diagnosticsDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
+
+func collectDiagnosticsMetrics() *diagnosticsMetrics {
+	status := librarySearchIndex().Status()
+	m := &diagnosticsMetrics{
+		Version:       xos.AppVersion,
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		CPUs:          runtime.NumCPU(),
+		Captured:      time.Now(),
+		Memory:        diag.CurrentMemory(),
+		Libraries:     len(gurps.GlobalSettings().Libraries()),
+		IndexedFiles:  status.Files,
+		IndexedItems:  status.Items,
+		OpenDockables: len(AllDockables()),
+	}
+	for _, t := range diag.Timings() {
+		m.Timings = append(m.Timings, diagnosticsTiming{
+			Key:     t.Key,
+			Count:   t.Count,
+			Average: formatDiagnosticsDuration(t.Average()),
+			Longest: formatDiagnosticsDuration(t.Longest),
+			Last:    formatDiagnosticsDuration(t.Last),
+		})
+	}
+	for _, one := range diag.SlowOperations() {
+		m.SlowOperations = append(m.SlowOperations, diagnosticsSlowEntry{
+			Key:      one.Key,
+			When:     one.When,
+			Duration: formatDiagnosticsDuration(one.Duration),
+		})
+	}
+	return m
+}
+
+// diagnosticsLogPaths returns the paths to the current log file and any older ones that have been rotated out.
+func diagnosticsLogPaths() []string {
+	if PathToLog == "" {
+		return nil
+	}
+	paths, err := filepath.Glob(PathToLog + "*")
+	if err != nil {
+		return []string{PathToLog}
+	}
+	return paths
+}
+
+func newDiagnosticsAnonymizer() *diag.Anonymizer {
+	var homeDir, userName string
+	if u, err := user.Current(); err == nil {
+		homeDir = u.HomeDir
+		// On Windows, the user name is prefixed with the domain
+		userName = u.Username[strings.LastIndexByte(u.Username, '\\')+1:]
+	}
+	if homeDir == "" {
+		homeDir, _ = os.UserHomeDir() //nolint:errcheck // An empty home directory is fine
+	}
+	return diag.NewAnonymizer(homeDir, userName)
+}
+
+func diagnosticsBoldFont() unison.Font {
+	return &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			desc := unison.DefaultLabelTheme.Font.Descriptor()
+			desc.Weight = weight.Bold
+			return desc
+		},
+	}
+}
+
+func diagnosticsOperationTitle(key string) string {
+	switch key {
+	case diag.BatchExportKey:
+		return i18n.Text("Batch export")
+	case diag.LibraryIndexKey:
+		return i18n.Text("Update library search index")
+	case diag.RecalculateKey:
+		return i18n.Text("Recalculate a sheet or template")
+	case diag.SheetRebuildKey:
+		return i18n.Text("Redisplay a sheet")
+	default:
+		return key
+	}
+}
+
+func formatDiagnosticsDuration(d time.Duration) string {
+	return d.Round(10 * time.Microsecond).String()
+}
+
+func formatDiagnosticsBytes(n uint64) string {
+	return fmt.Sprintf(i18n.Text("%.1f MB"), float64(n)/(1024*1024))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/discord"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+const discordRequestTimeout = 10 * time.Second
+
+// Only accessed from the UI thread. Holds the index of the last known threshold for each pool of each open entity, with
+// lower indexes being worse states.
+var discordThresholdStates = make(map[tid.TID]map[string]int)
+
+func installDiscordAnnouncer() {
+	dice.GlobalHistory().AddListener(func(r *dice.Result) {
+		if gurps.GlobalSettings().General.DiscordAnnounceRolls {
+			postToDiscord(discord.RollEmbed(r))
+		}
+	})
+}
+
+func postToDiscord(embeds ...*discord.Embed) {
+	client := &discord.Client{WebhookURL: gurps.GlobalSettings().General.DiscordWebhookURL}
+	if !client.Enabled() {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), discordRequestTimeout)
+		defer cancel()
+		if err := client.Post(ctx, embeds...); err != nil {
+			errs.Log(err)
+		}
+	}()
+}
+
+// CheckDiscordThresholds announces any of the entity's pools that have fallen into a worse threshold state since the
+// last check, if enabled.
+func CheckDiscordThresholds(entity *gurps.Entity) {
+	if entity == nil {
+		return
+	}
+	states, ok := discordThresholdStates[entity.ID]
+	if !ok {
+		states = make(map[string]int)
+		discordThresholdStates[entity.ID] = states
+	}
+	announce := gurps.GlobalSettings().General.DiscordAnnounceThresholds
+	var embeds []*discord.Embed
+	for _, attr := range entity.Attributes.List() {
+		def := attr.AttributeDef()
+		if def == nil || !def.Pool() {
+			continue
+		}
+		index := len(def.Thresholds)
+		threshold := attr.CurrentThreshold()
+		if threshold != nil {
+			index = slices.Index(def.Thresholds, threshold)
+		}
+		previous, known := states[attr.AttrID]
+		states[attr.AttrID] = index
+		if announce && known && index < previous && threshold != nil {
+			embeds = append(embeds, discord.ThresholdEmbed(entity.Profile.Name, def.Name, threshold.State,
+				attr.Current().String(), attr.Maximum().String()))
+		}
+	}
+	if len(embeds) != 0 {
+		postToDiscord(embeds...)
+	}
+}
+
+// forgetDiscordThresholds discards the threshold states recorded for the entity, which should be done once its sheet
+// has been closed.
+func forgetDiscordThresholds(entity *gurps.Entity) {
+	delete(discordThresholdStates, entity.ID)
+}
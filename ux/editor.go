@@ -361,7 +361,7 @@ func (e *editor[N, D]) apply() {
 	if mgr := unison.UndoManagerFor(e.owner); mgr != nil {
 		owner := e.owner
 		target := e.target
-		mgr.Add(&unison.UndoEdit[D]{
+		addUndo(mgr, &unison.UndoEdit[D]{
 			ID:       unison.NextUndoID(),
 			EditName: fmt.Sprintf(i18n.Text("%s Changes"), gurps.AsNode(target).Kind()),
 			UndoFunc: func(edit *unison.UndoEdit[D]) {
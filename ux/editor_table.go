@@ -33,6 +33,9 @@ func newEditorTable[T gurps.NodeTypes](parent *unison.Panel, provider TableProvi
 	table.InstallCmdHandlers(SyncWithSourceItemID,
 		func(_ any) bool { return HasSelectionAndNotFiltered(table) },
 		func(_ any) { SyncWithSourceForSelection(table) })
+	table.InstallCmdHandlers(ReviewSourceChangesItemID,
+		func(_ any) bool { return CanReviewSourceChangesForSelection(table) },
+		func(_ any) { ReviewSourceChangesForSelection(table) })
 	table.InstallCmdHandlers(ClearSourceItemID,
 		func(_ any) bool { return HasSelectionAndNotFiltered(table) },
 		func(_ any) { ClearSourceFromSelection(table) })
@@ -180,7 +180,7 @@ func (p *EncumbrancePanel) createMoveField(enc encumbrance.Level, rowColor *encR
 
 func (p *EncumbrancePanel) createDodgeField(enc encumbrance.Level, rowColor *encRowColor) *NonEditablePageField {
 	field := NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
-		if text := strconv.Itoa(p.entity.Dodge(enc)); text != f.Text.String() {
+		if text := p.entity.DodgeText(enc); text != f.Text.String() {
 			f.SetTitle(text)
 			MarkForLayoutWithinDockable(f)
 		}
@@ -10,13 +10,17 @@
 package ux
 
 import (
+	"slices"
 	"strconv"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/armor"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/eqploc"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/prereq"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
 	"github.com/richardwilkes/unison"
 )
 
@@ -34,9 +38,10 @@ func EditEquipment(owner Rebuildable, equipment *gurps.Equipment, carried bool)
 				&e.editorData.LegalityClass)
 			qtyLabel := i18n.Text("Quantity")
 			if carried {
-				wrapper := addFlowWrapper(content, qtyLabel, 2)
+				wrapper := addFlowWrapper(content, qtyLabel, 3)
 				addDecimalField(wrapper, nil, "", qtyLabel, "", &e.editorData.Quantity, 0, fxp.Max-1)
-				addCheckBox(wrapper, i18n.Text("Equipped"), &e.editorData.Equipped)
+				wrapper.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("Location"), false))
+				addPopup(wrapper, eqploc.Locations, &e.editorData.Location)
 			} else {
 				addLabelAndDecimalField(content, nil, "", qtyLabel, "", &e.editorData.Quantity, 0, fxp.Max-1)
 			}
@@ -91,17 +96,39 @@ func EditEquipment(owner Rebuildable, equipment *gurps.Equipment, carried bool)
 			}))
 			content.AddChild(unison.NewPanel())
 			addCheckBox(content, i18n.Text("Ignore weight for skills"), &e.editorData.WeightIgnoredForSkills)
+			addLabelAndDecimalField(content, nil, "", i18n.Text("Volume"),
+				i18n.Text("The space one of these takes up within a container, in the same units used for container volume capacities"),
+				&e.editorData.Volume, 0, fxp.Max-1)
+			if e.target.Container() {
+				capacityLabel := i18n.Text("Capacity")
+				wrapper := addFlowWrapper(content, capacityLabel, 3)
+				addWeightField(wrapper, nil, "", i18n.Text("Weight Capacity"),
+					i18n.Text("The weight of contents the container can hold. Contained weight reductions do not reduce the weight counted against this. Leave at 0 for no limit."),
+					entity, &e.editorData.CapacityWeight, false)
+				volumeCapacityLabel := i18n.Text("Volume")
+				wrapper.AddChild(NewFieldInteriorLeadingLabel(volumeCapacityLabel, false))
+				addDecimalField(wrapper, nil, "", i18n.Text("Volume Capacity"),
+					i18n.Text("The total volume of contents the container can hold. Leave at 0 for no limit."),
+					&e.editorData.CapacityVolume, 0, fxp.Max-1)
+			}
 			usesLabel := i18n.Text("Uses Left")
 			wrapper := addFlowWrapper(content, usesLabel, 3)
 			usesField := addIntegerField(wrapper, nil, "", usesLabel, "", &e.editorData.Uses, 0, 9999999)
 			maxUsesLabel := i18n.Text("Maximum Uses")
 			wrapper.AddChild(NewFieldInteriorLeadingLabel(maxUsesLabel, false))
 			addIntegerField(wrapper, nil, "", maxUsesLabel, "", &e.editorData.MaxUses, 0, 9999999)
+			addLabelAndPopup(content, i18n.Text("Armor Construction"),
+				i18n.Text("Whether this armor is flexible or rigid. Rigid armor can't be worn over other rigid armor, so when more than one rigid layer covers a hit location, only the one providing the most DR counts."),
+				armor.Constructions, &e.editorData.ArmorConstruction)
+			if carried && entity != nil {
+				addAmmunitionPopup(content, entity, e.target, &e.editorData.AmmoID, &e.editorData.AmmoLoaded)
+			}
 			addLabelAndDecimalField(content, nil, "", i18n.Text("Rated ST"), i18n.Text("Equipment with a rated ST use this value instead of the user's ST"), &e.editorData.RatedST, 0, fxp.Max)
 			addLabelAndDecimalField(content, nil, "", i18n.Text("Level"), i18n.Text("Level can be used with features and modifiers that have per-level effects"), &e.editorData.Level, 0, fxp.Max)
 			addTagsLabelAndField(content, &e.editorData.Tags)
 			addPageRefLabelAndField(content, &e.editorData.PageRef)
 			addPageRefHighlightLabelAndField(content, &e.editorData.PageRefHighlight)
+			addProvenanceLabelsAndFields(content, &e.editorData.Provenance)
 			addSourceFields(content, &e.target.SourcedID)
 			adjustFieldBlank(usesField, e.editorData.MaxUses <= 0)
 			content.AddChild(newPrereqPanel(entity, &e.editorData.Prereq, prereq.TypesForEquipment))
@@ -130,3 +157,31 @@ func cloneEquipmentWithOverlay(e *gurps.Equipment, overlay *gurps.EquipmentEditD
 	clone.EquipmentEditData = *overlay
 	return clone
 }
+
+func addAmmunitionPopup(content *unison.Panel, entity *gurps.Entity, target *gurps.Equipment, ammoID *tid.TID, loaded *fxp.Int) {
+	ammoLabel := i18n.Text("Ammunition")
+	wrapper := addFlowWrapper(content, ammoLabel, 3)
+	popup := unison.NewPopupMenu[string]()
+	popup.AddItem(i18n.Text("None"))
+	ids := []tid.TID{""}
+	gurps.Traverse(func(one *gurps.Equipment) bool {
+		if one.TID != target.TID {
+			popup.AddItem(one.String())
+			ids = append(ids, one.TID)
+		}
+		return false
+	}, false, true, entity.CarriedEquipment...)
+	popup.SelectIndex(max(slices.Index(ids, *ammoID), 0))
+	popup.Tooltip = newWrappedTooltip(i18n.Text("The carried equipment used up when this equipment's ranged weapons are fired. Thrown weapons without ammunition use up the equipment itself."))
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[string]) {
+		if i := p.SelectedIndex(); i >= 0 && i < len(ids) {
+			*ammoID = ids[i]
+			MarkModified(wrapper)
+		}
+	}
+	wrapper.AddChild(popup)
+	loadedLabel := i18n.Text("Loaded")
+	wrapper.AddChild(NewFieldInteriorLeadingLabel(loadedLabel, false))
+	addDecimalField(wrapper, nil, "", loadedLabel,
+		i18n.Text("The number of shots currently loaded into this equipment's ranged weapons"), loaded, 0, fxp.Max-1)
+}
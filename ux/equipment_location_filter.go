@@ -0,0 +1,43 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/eqploc"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+func (s *Sheet) createEquipmentLocationFilterPopup() *unison.PopupMenu[string] {
+	popup := unison.NewPopupMenu[string]()
+	popup.AddItem(i18n.Text("All Locations"))
+	for _, one := range eqploc.Locations {
+		popup.AddItem(one.String())
+	}
+	popup.SelectIndex(0)
+	popup.Tooltip = newWrappedTooltip(i18n.Text("Only show carried equipment at this location"))
+	popup.SelectionChangedCallback = func(_ *unison.PopupMenu[string]) { s.Rebuild(true) }
+	return popup
+}
+
+// applyEquipmentLocationFilter hides the carried equipment that isn't at the location chosen in the toolbar. Must be
+// called whenever the carried equipment list is synced, since filtering works from a snapshot of the rows.
+func (s *Sheet) applyEquipmentLocationFilter() {
+	if s.equipmentLocationPopup == nil || s.CarriedEquipment == nil {
+		return
+	}
+	var filter func(row *Node[*gurps.Equipment]) bool
+	if i := s.equipmentLocationPopup.SelectedIndex(); i > 0 && i <= len(eqploc.Locations) {
+		loc := eqploc.Locations[i-1]
+		filter = func(row *Node[*gurps.Equipment]) bool { return row.Data().Location != loc }
+	}
+	s.CarriedEquipment.Table.ApplyFilter(filter)
+}
@@ -115,14 +115,24 @@ func (p *equipmentProvider) DropShouldMoveData(from, to *unison.Table[*Node[*gur
 }
 
 func (p *equipmentProvider) ProcessDropData(from, to *unison.Table[*Node[*gurps.Equipment]]) {
-	if p.carried && from != to {
-		for _, row := range to.SelectedRows(true) {
-			if equipmentRow, ok := any(row).(*Node[*gurps.Equipment]); ok {
+	if from == to {
+		return
+	}
+	var list []*gurps.Equipment
+	for _, row := range to.SelectedRows(true) {
+		if equipmentRow, ok := any(row).(*Node[*gurps.Equipment]); ok {
+			if p.carried {
 				gurps.Traverse(func(e *gurps.Equipment) bool {
-					e.Equipped = true
+					e.SetEquipped(true)
 					return false
 				}, false, false, equipmentRow.Data())
 			}
+			list = append(list, equipmentRow.Data())
+		}
+	}
+	if owner := p.DataOwner(); !xreflect.IsNil(owner) && len(list) != 0 {
+		if entity := owner.OwningEntity(); entity != nil {
+			entity.AdoptEquipment(list...)
 		}
 	}
 }
@@ -181,7 +191,14 @@ func (p *equipmentProvider) SyncHeader(headers []unison.TableColumnHeader[*Node[
 }
 
 func (p *equipmentProvider) ColumnIDs() []int {
-	columnIDs := make([]int, 0, 11)
+	if p.forPage {
+		return customizedColumnIDs(p)
+	}
+	return p.defaultColumnIDs()
+}
+
+func (p *equipmentProvider) defaultColumnIDs() []int {
+	columnIDs := make([]int, 0, 12)
 	if p.forPage && p.carried {
 		columnIDs = append(columnIDs, gurps.EquipmentEquippedColumn)
 	}
@@ -197,6 +214,9 @@ func (p *equipmentProvider) ColumnIDs() []int {
 			sheetSettings = gurps.GlobalSettings().SheetSettings()
 		}
 	}
+	if p.forPage && p.carried && sheetSettings != nil && sheetSettings.ShowEquipmentLocationColumn {
+		columnIDs = append(columnIDs, gurps.EquipmentLocationColumn)
+	}
 	if p.forPage && sheetSettings != nil {
 		if !sheetSettings.HideTLColumn {
 			columnIDs = append(columnIDs, gurps.EquipmentTLColumn)
@@ -232,6 +252,32 @@ func (p *equipmentProvider) ColumnIDs() []int {
 	return columnIDs
 }
 
+func (p *equipmentProvider) availableColumnIDs() []int {
+	columnIDs := make([]int, 0, 13)
+	if p.carried {
+		columnIDs = append(columnIDs, gurps.EquipmentEquippedColumn)
+	}
+	columnIDs = append(columnIDs, gurps.EquipmentQuantityColumn, gurps.EquipmentDescriptionColumn)
+	if p.carried {
+		columnIDs = append(columnIDs, gurps.EquipmentLocationColumn)
+	}
+	return append(columnIDs,
+		gurps.EquipmentTLColumn,
+		gurps.EquipmentLCColumn,
+		gurps.EquipmentCostColumn,
+		gurps.EquipmentWeightColumn,
+		gurps.EquipmentExtendedCostColumn,
+		gurps.EquipmentExtendedWeightColumn,
+		gurps.EquipmentTagsColumn,
+		gurps.EquipmentReferenceColumn,
+		gurps.EquipmentLibSrcColumn,
+	)
+}
+
+func (p *equipmentProvider) columnHeaderData(columnID int) gurps.HeaderData {
+	return gurps.EquipmentHeaderData(columnID, p.provider, p.carried, p.forPage)
+}
+
 func (p *equipmentProvider) HierarchyColumnID() int {
 	return gurps.EquipmentDescriptionColumn
 }
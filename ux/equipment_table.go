@@ -85,5 +85,8 @@ func NewEquipmentTableDockable(filePath string, equipment []*gurps.Equipment) *T
 	d.InstallCmdHandlers(DecrementEquipmentLevelItemID,
 		func(_ any) bool { return canAdjustEquipmentLevel(d.table, -fxp.One) },
 		func(_ any) { adjustEquipmentLevel(d, d.table, -fxp.One) })
+	d.InstallCmdHandlers(ApplyEquipmentModifierItemID,
+		func(_ any) bool { return canApplyEquipmentModifier(d.table) },
+		func(_ any) { applyEquipmentModifier(d, d.table) })
 	return d
 }
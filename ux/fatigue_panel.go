@@ -0,0 +1,407 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/casting"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var (
+	_ pageHelper = &FatiguePanel{}
+
+	fatigueKinds = []string{
+		gurps.SprintingFatigue,
+		gurps.ExtraEffortFatigue,
+		gurps.SpellcastingFatigue,
+		gurps.OtherFatigue,
+	}
+)
+
+// FatiguePanel holds the log of fatigue points a character has spent on extended effort.
+type FatiguePanel struct {
+	unison.Panel
+	entity    *gurps.Entity
+	editable  bool
+	hash      uint64
+	drawStart int
+	drawEnd   int
+}
+
+// NewFatiguePanel creates a new fatigue panel. If editable is true, buttons for removing log entries will be included.
+func NewFatiguePanel(entity *gurps.Entity, editable bool) *FatiguePanel {
+	p := &FatiguePanel{
+		entity:   entity,
+		editable: editable,
+		drawEnd:  1,
+	}
+	p.Self = p
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: i18n.Text("Fatigue Expenditures")},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(2, 1))))
+	p.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	p.rebuild()
+	return p
+}
+
+// HasExpenditures returns true if at least one fatigue expenditure has been logged.
+func (p *FatiguePanel) HasExpenditures() bool {
+	return len(p.entity.FatigueLog) != 0
+}
+
+func (p *FatiguePanel) columns() int {
+	if p.editable {
+		return 4
+	}
+	return 3
+}
+
+func (p *FatiguePanel) rebuild() {
+	p.hash = gurps.HashFatigueLog(p.entity.FatigueLog)
+	p.RemoveAllChildren()
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  p.columns(),
+		HSpacing: 4,
+	})
+	for _, title := range []string{
+		i18n.Text("Kind"),
+		i18n.Text("Notes"),
+		i18n.Text("FP"),
+	} {
+		label := NewPageLabel(title)
+		label.Font = fonts.PageLabelSecondary
+		p.AddChild(label)
+	}
+	if p.editable {
+		p.AddChild(unison.NewPanel())
+	}
+	for _, one := range p.entity.FatigueLog {
+		kindName := gurps.FatigueKindName(one.Kind)
+		if one.Tally {
+			kindName = fmt.Sprintf(i18n.Text("%s (Tally)"), kindName)
+		}
+		p.AddChild(NewPageLabel(kindName))
+		notesLabel := NewPageLabel(one.Notes)
+		notesLabel.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		p.AddChild(notesLabel)
+		amountLabel := NewPageLabelEnd(strconv.Itoa(one.Amount))
+		amountLabel.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+		p.AddChild(amountLabel)
+		if p.editable {
+			p.AddChild(p.createRemoveButton(one))
+		}
+	}
+	summary := NewPageLabel(p.summary())
+	summary.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  p.columns(),
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.AddChild(summary)
+}
+
+func (p *FatiguePanel) summary() string {
+	text := p.fpSummary()
+	if p.entity.SheetSettings.CastingMode == casting.Threshold {
+		text += fmt.Sprintf(i18n.Text("; Tally %d of %d"), p.entity.ThresholdTally(),
+			p.entity.SheetSettings.MagicThreshold)
+		if p.entity.ThresholdExceeded() {
+			text += " " + i18n.Text("(threshold exceeded)")
+		}
+	}
+	return text
+}
+
+func (p *FatiguePanel) fpSummary() string {
+	fp := p.entity.FatiguePoints()
+	if fp == nil {
+		return fmt.Sprintf(i18n.Text("Total spent: %d"), p.entity.TotalFatigueSpent())
+	}
+	text := fmt.Sprintf(i18n.Text("Total spent: %d; FP %s of %s"), p.entity.TotalFatigueSpent(),
+		fp.Current().Comma(), fp.Maximum().Comma())
+	if threshold := fp.CurrentThreshold(); threshold != nil {
+		text += "; " + threshold.State
+		if explanation := threshold.ResolveExplanation(fp); explanation != "" {
+			text += " (" + explanation + ")"
+		}
+	}
+	return text
+}
+
+func (p *FatiguePanel) createRemoveButton(one *gurps.FatigueExpenditure) *unison.Button {
+	b := unison.NewSVGButton(svg.Trash)
+	if one.Tally {
+		b.Tooltip = newWrappedTooltip(i18n.Text("Remove this entry and its energy from the threshold tally"))
+	} else {
+		b.Tooltip = newWrappedTooltip(i18n.Text("Remove this entry and restore the fatigue points it cost"))
+	}
+	b.ClickCallback = func() {
+		editFatigue(p, p.entity, i18n.Text("Remove Fatigue Expenditure"), func() {
+			if i := slices.Index(p.entity.FatigueLog, one); i != -1 {
+				p.entity.FatigueLog = slices.Delete(p.entity.FatigueLog, i, i+1)
+				if fp := p.entity.FatiguePoints(); fp != nil && !one.Tally {
+					fp.Damage = (fp.Damage - fxp.FromInteger(one.Amount)).Max(0)
+				}
+			}
+		})
+	}
+	return b
+}
+
+// Sync the panel to the current data.
+func (p *FatiguePanel) Sync() {
+	if gurps.HashFatigueLog(p.entity.FatigueLog) != p.hash {
+		p.rebuild()
+	} else if children := p.Children(); len(children) != 0 {
+		if label, ok := children[len(children)-1].Self.(*unison.Label); ok {
+			label.SetTitle(p.summary())
+		}
+	}
+	MarkForLayoutWithinDockable(p)
+}
+
+// OverheadHeight implements pageHelper.
+func (p *FatiguePanel) OverheadHeight() float32 {
+	return 0
+}
+
+// RowHeights implements pageHelper. The log is always kept together, so it is reported as a single row.
+func (p *FatiguePanel) RowHeights() []float32 {
+	return []float32{p.FrameRect().Height}
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *FatiguePanel) CurrentDrawRowRange() (start, endBefore int) {
+	return p.drawStart, p.drawEnd
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *FatiguePanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+}
+
+type fatigueSnapshot struct {
+	log      []*gurps.FatigueExpenditure
+	fpDamage fxp.Int
+}
+
+func captureFatigue(entity *gurps.Entity) *fatigueSnapshot {
+	s := &fatigueSnapshot{log: gurps.CloneFatigueLog(entity.FatigueLog)}
+	if fp := entity.FatiguePoints(); fp != nil {
+		s.fpDamage = fp.Damage
+	}
+	return s
+}
+
+func (s *fatigueSnapshot) apply(entity *gurps.Entity) {
+	entity.SetFatigueLog(s.log)
+	if fp := entity.FatiguePoints(); fp != nil {
+		fp.Damage = s.fpDamage
+	}
+}
+
+func editFatigue(src unison.Paneler, entity *gurps.Entity, name string, edit func()) {
+	owner := unison.AncestorOrSelf[Rebuildable](src)
+	before := captureFatigue(entity)
+	edit()
+	after := captureFatigue(entity)
+	if mgr := unison.UndoManagerFor(src); mgr != nil && owner != nil {
+		addUndo(mgr, &unison.UndoEdit[*fatigueSnapshot]{
+			ID:       unison.NextUndoID(),
+			EditName: name,
+			UndoFunc: func(e *unison.UndoEdit[*fatigueSnapshot]) {
+				e.BeforeData.apply(entity)
+				owner.Rebuild(true)
+			},
+			RedoFunc: func(e *unison.UndoEdit[*fatigueSnapshot]) {
+				e.AfterData.apply(entity)
+				owner.Rebuild(true)
+			},
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	if owner != nil {
+		owner.Rebuild(true)
+	}
+}
+
+// ShowSpendFatigueDialog asks for the details of an extended effort by the sheet's character, deducts the resulting
+// fatigue points and warns if doing so has moved the character into a new FP threshold.
+func ShowSpendFatigueDialog(sheet *Sheet) {
+	entity := sheet.Entity()
+	kinds := fatigueKinds
+	if entity.SheetSettings.CastingMode == casting.NoFatigue {
+		kinds = slices.DeleteFunc(slices.Clone(kinds), func(kind string) bool { return kind == gurps.SpellcastingFatigue })
+	}
+	var spells []*gurps.Spell
+	gurps.Traverse(func(s *gurps.Spell) bool {
+		if _, ok := s.CastingFatigueCost(); ok {
+			spells = append(spells, s)
+		}
+		return false
+	}, true, true, entity.Spells...)
+
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Kind"), false))
+	kindPopup := unison.NewPopupMenu[string]()
+	for _, kind := range kinds {
+		kindPopup.AddItem(gurps.FatigueKindName(kind))
+	}
+	kindPopup.SelectIndex(0)
+	content.AddChild(kindPopup)
+
+	seconds := 15
+	secondsLabel := i18n.Text("Seconds")
+	content.AddChild(NewFieldLeadingLabel(secondsLabel, false))
+	var amountField *IntegerField
+	amount := 1
+	recompute := func() {
+		switch kinds[max(kindPopup.SelectedIndex(), 0)] {
+		case gurps.SprintingFatigue:
+			amount = gurps.SprintingFatigueCost(seconds)
+		case gurps.SpellcastingFatigue:
+			amount = 0
+		default:
+			amount = 1
+		}
+		if amountField != nil {
+			amountField.Sync()
+		}
+	}
+	secondsField := NewIntegerField(nil, "", secondsLabel, func() int { return seconds }, func(v int) {
+		seconds = v
+		recompute()
+	}, 1, 9999, false, false)
+	secondsField.Tooltip = newWrappedTooltip(i18n.Text("Sprinting costs 1 FP for every 15 seconds or part thereof"))
+	content.AddChild(secondsField)
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Feat"), false))
+	featPopup := unison.NewPopupMenu[string]()
+	for _, one := range gurps.ExtraEffortFeats {
+		featPopup.AddItem(one)
+	}
+	featPopup.SelectIndex(0)
+	content.AddChild(featPopup)
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Spell"), false))
+	spellPopup := unison.NewPopupMenu[string]()
+	for _, one := range spells {
+		spellPopup.AddItem(one.String())
+	}
+	if len(spells) != 0 {
+		spellPopup.SelectIndex(0)
+	}
+	content.AddChild(spellPopup)
+
+	notes := ""
+	notesLabel := i18n.Text("Notes")
+	content.AddChild(NewFieldLeadingLabel(notesLabel, false))
+	content.AddChild(NewStringField(nil, "", notesLabel, func() string { return notes },
+		func(s string) { notes = s }))
+
+	amountLabel := i18n.Text("FP")
+	content.AddChild(NewFieldLeadingLabel(amountLabel, false))
+	amountField = NewIntegerField(nil, "", amountLabel, func() int { return amount },
+		func(v int) { amount = v }, 0, 9999, false, false)
+	if entity.SheetSettings.CastingMode == casting.Threshold {
+		amountField.Tooltip = newWrappedTooltip(i18n.Text("Spellcasting is added to the threshold tally rather than deducted from FP"))
+	}
+	content.AddChild(amountField)
+
+	spellCost := func() {
+		if i := spellPopup.SelectedIndex(); i >= 0 && i < len(spells) {
+			amount, _ = spells[i].CastingFatigueCost()
+			amountField.Sync()
+		}
+	}
+	adjustForKind := func() {
+		kind := kinds[max(kindPopup.SelectedIndex(), 0)]
+		secondsField.SetEnabled(kind == gurps.SprintingFatigue)
+		featPopup.SetEnabled(kind == gurps.ExtraEffortFatigue)
+		spellPopup.SetEnabled(kind == gurps.SpellcastingFatigue && len(spells) != 0)
+		recompute()
+		if kind == gurps.SpellcastingFatigue {
+			spellCost()
+		}
+	}
+	kindPopup.SelectionChangedCallback = func(_ *unison.PopupMenu[string]) { adjustForKind() }
+	spellPopup.SelectionChangedCallback = func(_ *unison.PopupMenu[string]) { spellCost() }
+	adjustForKind()
+
+	icon := &unison.DrawableSVG{
+		SVG:  svg.Weight,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption(), unison.NotResizableWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK || amount <= 0 {
+		return
+	}
+	kind := kinds[max(kindPopup.SelectedIndex(), 0)]
+	if notes = strings.TrimSpace(notes); notes == "" {
+		switch kind {
+		case gurps.SprintingFatigue:
+			notes = fmt.Sprintf(i18n.Text("%d seconds"), seconds)
+		case gurps.ExtraEffortFatigue:
+			notes, _ = featPopup.Selected()
+		case gurps.SpellcastingFatigue:
+			notes, _ = spellPopup.Selected()
+		}
+	}
+	var threshold *gurps.PoolThreshold
+	editFatigue(sheet, entity, i18n.Text("Spend Fatigue"), func() {
+		threshold = entity.SpendFatigue(kind, notes, amount)
+	})
+	if kind == gurps.SpellcastingFatigue && entity.ThresholdExceeded() {
+		unison.WarningDialogWithMessage(i18n.Text("The threshold has been exceeded"),
+			fmt.Sprintf(i18n.Text("The tally of %d exceeds the threshold of %d; a calamity check is required (p. T77)."),
+				entity.ThresholdTally(), entity.SheetSettings.MagicThreshold))
+	}
+	if threshold != nil {
+		unison.WarningDialogWithMessage(fmt.Sprintf(i18n.Text("Fatigue has reached the %s threshold"),
+			threshold.State), threshold.ResolveExplanation(entity.FatiguePoints()))
+	}
+}
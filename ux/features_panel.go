@@ -196,6 +196,13 @@ func (p *featuresPanel) createConditionalModifierPanel(f *gurps.ConditionalModif
 		HGrab:  true,
 	})
 	panel.AddChild(field)
+	panel.AddChild(unison.NewPanel())
+	wrapper := unison.NewPanel()
+	wrapper.AddChild(NewFieldLeadingLabel(i18n.Text("When in effect, applies to"), false))
+	popup := addAttributeChoicePopup(wrapper, p.entity, "", &f.AppliesTo,
+		gurps.BlankFlag|gurps.DodgeFlag|gurps.ParryFlag|gurps.BlockFlag)
+	popup.Tooltip = newWrappedTooltip(i18n.Text("If set, the modifier can be toggled on from the sheet to temporarily apply it to the chosen value"))
+	p.addWrapperAtIndex(panel, wrapper, -1, true)
 	return panel, focus
 }
 
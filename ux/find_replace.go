@@ -0,0 +1,208 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+type textReplacementUndoEdit = *unison.UndoEdit[*textReplacementList]
+
+type textReplacementList struct {
+	Owner  *Sheet
+	List   []*gurps.TextReplacement
+	Revert bool
+}
+
+func (t *textReplacementList) Apply() {
+	for _, one := range t.List {
+		if t.Revert {
+			one.Revert()
+		} else {
+			one.Apply()
+		}
+	}
+	t.Owner.Rebuild(true)
+}
+
+// ShowFindReplace shows a dialog that finds text within the names, notes and user descriptions of the items on the sheet
+// and replaces it.
+func ShowFindReplace(sheet *Sheet) {
+	var findText, replaceText string
+	var useRegex, caseSensitive bool
+	var list []*gurps.TextReplacement
+	var dialog *unison.Dialog
+
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	resultsPanel := unison.NewPanel()
+	resultsPanel.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	status := unison.NewLabel()
+	status.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	update := func() {
+		list = nil
+		switch replacer, err := gurps.NewTextReplacer(findText, replaceText, useRegex, caseSensitive); {
+		case findText == "":
+			status.SetTitle(i18n.Text("Enter the text to find"))
+		case err != nil:
+			status.SetTitle(fmt.Sprintf(i18n.Text("Invalid regular expression: %s"), err.Error()))
+		default:
+			list = gurps.FindTextReplacements(sheet.entity, replacer)
+			count := 0
+			for _, one := range list {
+				count += one.Count
+			}
+			if sheet.entity.Locked {
+				status.SetTitle(fmt.Sprintf(i18n.Text("%d matches in %d fields (names are left alone while the sheet is locked)"),
+					count, len(list)))
+			} else {
+				status.SetTitle(fmt.Sprintf(i18n.Text("%d matches in %d fields"), count, len(list)))
+			}
+		}
+		rebuildTextReplacements(resultsPanel, list)
+		if dialog != nil {
+			dialog.Button(unison.ModalResponseOK).SetEnabled(len(list) != 0)
+		}
+		status.MarkForLayoutAndRedraw()
+	}
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Find"), false))
+	findField := NewStringField(nil, "", i18n.Text("Find"), func() string { return findText },
+		func(s string) {
+			findText = s
+			update()
+		})
+	content.AddChild(findField)
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Replace"), false))
+	content.AddChild(NewStringField(nil, "", i18n.Text("Replace"), func() string { return replaceText },
+		func(s string) {
+			replaceText = s
+			update()
+		}))
+	content.AddChild(unison.NewPanel())
+	options := unison.NewPanel()
+	options.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing * 2,
+	})
+	regexCheckbox := unison.NewCheckBox()
+	regexCheckbox.SetTitle(i18n.Text("Use regular expression"))
+	regexCheckbox.ClickCallback = func() {
+		useRegex = regexCheckbox.State == check.On
+		update()
+	}
+	options.AddChild(regexCheckbox)
+	caseCheckbox := unison.NewCheckBox()
+	caseCheckbox.SetTitle(i18n.Text("Match case"))
+	caseCheckbox.ClickCallback = func() {
+		caseSensitive = caseCheckbox.State == check.On
+		update()
+	}
+	options.AddChild(caseCheckbox)
+	content.AddChild(options)
+	content.AddChild(status)
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(resultsPanel, behavior.Fill, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:    2,
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		SizeHint: geom.Size{Width: 600, Height: 300},
+		HGrab:    true,
+		VGrab:    true,
+	})
+	content.AddChild(scroll)
+	update()
+
+	replaceButton := unison.NewOKButtonInfo()
+	replaceButton.Title = i18n.Text("Replace All")
+	var err error
+	if dialog, err = unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		content, []*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), replaceButton},
+		unison.FloatingWindowOption()); err != nil {
+		errs.Log(err)
+		return
+	}
+	dialog.Button(unison.ModalResponseOK).SetEnabled(false)
+	findField.RequestFocus()
+	if dialog.RunModal() != unison.ModalResponseOK || len(list) == 0 {
+		return
+	}
+	before := &textReplacementList{Owner: sheet, List: list, Revert: true}
+	after := &textReplacementList{Owner: sheet, List: list}
+	if mgr := unison.UndoManagerFor(sheet); mgr != nil {
+		addUndo(mgr, &unison.UndoEdit[*textReplacementList]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Find and Replace"),
+			UndoFunc:   func(edit textReplacementUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit textReplacementUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	after.Apply()
+}
+
+func rebuildTextReplacements(panel *unison.Panel, list []*gurps.TextReplacement) {
+	panel.RemoveAllChildren()
+	if len(list) != 0 {
+		for _, header := range []string{i18n.Text("Item"), i18n.Text("Field"), i18n.Text("Before"), i18n.Text("After")} {
+			label := unison.NewLabel()
+			label.Font = fonts.PageLabelSecondary
+			label.SetTitle(header)
+			panel.AddChild(label)
+		}
+		for _, one := range list {
+			for _, text := range []string{
+				fmt.Sprintf("%s: %s", one.Kind, one.Item), one.Field, summarizeReplacementText(one.Before),
+				summarizeReplacementText(one.After),
+			} {
+				label := unison.NewLabel()
+				label.SetTitle(text)
+				panel.AddChild(label)
+			}
+		}
+	}
+	panel.MarkForLayoutRecursivelyUpward()
+	panel.MarkForRedraw()
+}
+
+func summarizeReplacementText(text string) string {
+	const maxRunes = 60
+	runes := []rune(text)
+	for i, r := range runes {
+		if r == '\n' || r == '\r' {
+			runes[i] = ' '
+		}
+	}
+	if len(runes) > maxRunes {
+		return string(runes[:maxRunes-1]) + "…"
+	}
+	return string(runes)
+}
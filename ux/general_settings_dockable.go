@@ -12,6 +12,7 @@ package ux
 import (
 	"cmp"
 	"errors"
+	"fmt"
 	"io/fs"
 	"maps"
 	"os"
@@ -19,11 +20,13 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/autoscale"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/restapi"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
@@ -50,6 +53,8 @@ type generalSettingsDockable struct {
 	groupContainersOnSortCheckbox   *CheckBox
 	initialClickSelectsAllCheckbox  *CheckBox
 	restoreWorkspaceOnStartCheckbox *CheckBox
+	snapshotOnSaveCheckbox          *CheckBox
+	fillablePDFExportCheckbox       *CheckBox
 	deepSearchableCheckbox          []*CheckBox
 	openInWindowCheckbox            []*CheckBox
 	pointsField                     *DecimalField
@@ -70,7 +75,18 @@ type generalSettingsDockable struct {
 	tooltipDismissalField           *DecimalField
 	scrollWheelMultiplierField      *DecimalField
 	externalPDFCmdlineField         *StringField
+	exportTemplatesDirField         *StringField
+	pluginsDirField                 *StringField
 	localeField                     *StringField
+	discordWebhookField             *StringField
+	discordRollsCheckbox            *CheckBox
+	discordThresholdsCheckbox       *CheckBox
+	apiServerEnabledCheckbox        *CheckBox
+	apiServerPortField              *IntegerField
+	apiServerTokenField             *StringField
+	statblockThresholdField         *IntegerField
+	statblockTemplateField          *StringField
+	searchIndexStatusField          *NonEditableField
 }
 
 // ShowGeneralSettings the General Settings window.
@@ -176,8 +192,14 @@ func (d *generalSettingsDockable) initContent(content *unison.Panel) {
 	d.createPathInfoField(content, i18n.Text("Settings Path"), gurps.SettingsPath)
 	d.createPathInfoField(content, i18n.Text("Translations Path"), i18n.Dir)
 	d.createPathInfoField(content, i18n.Text("Log Path"), PathToLog)
+	d.createSearchIndexField(content)
 	d.createExternalPDFCmdLineField(content)
+	d.createExportTemplatesDirField(content)
+	d.createPluginsDirField(content)
 	d.createLocaleField(content)
+	d.createDiscordBlock(content)
+	d.createAPIServerBlock(content)
+	d.createStatblockBlock(content)
 	d.createDeepSearchCheckboxes(content)
 	d.createOpenInWindowCheckboxes(content)
 }
@@ -252,6 +274,30 @@ func (d *generalSettingsDockable) createCheckboxBlock(content *unison.Panel) {
 	d.initialClickSelectsAllCheckbox.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
 	content.AddChild(NewFieldLeadingLabel("", false))
 	content.AddChild(d.initialClickSelectsAllCheckbox)
+
+	d.snapshotOnSaveCheckbox = NewCheckBox(nil, "", i18n.Text("Take a snapshot of character sheets when saving"),
+		func() check.Enum {
+			return check.FromBool(gurps.GlobalSettings().General.SnapshotOnSave)
+		},
+		func(state check.Enum) {
+			gurps.GlobalSettings().General.SnapshotOnSave = state == check.On
+		})
+	d.snapshotOnSaveCheckbox.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	content.AddChild(NewFieldLeadingLabel("", false))
+	content.AddChild(d.snapshotOnSaveCheckbox)
+
+	d.fillablePDFExportCheckbox = NewCheckBox(nil, "", i18n.Text("Add fillable fields when exporting sheets to PDF"),
+		func() check.Enum {
+			return check.FromBool(gurps.GlobalSettings().General.FillablePDFExport)
+		},
+		func(state check.Enum) {
+			gurps.GlobalSettings().General.FillablePDFExport = state == check.On
+		})
+	d.fillablePDFExportCheckbox.Tooltip = newWrappedTooltip(i18n.Text(
+		`Exported PDFs will include form fields for the current value of hit points, fatigue points, and other pools, plus a notes area in the free space at the end of the last page. All other values remain static text.`))
+	d.fillablePDFExportCheckbox.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	content.AddChild(NewFieldLeadingLabel("", false))
+	content.AddChild(d.fillablePDFExportCheckbox)
 }
 
 func (d *generalSettingsDockable) createInitialPointsFields(content *unison.Panel) {
@@ -393,6 +439,35 @@ func (d *generalSettingsDockable) createPathInfoField(content *unison.Panel, tit
 	content.AddChild(addButton)
 }
 
+func (d *generalSettingsDockable) createSearchIndexField(content *unison.Panel) {
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Search Index"), false))
+	d.searchIndexStatusField = NewNonEditableField(func(field *NonEditableField) {
+		switch status := librarySearchIndex().Status(); {
+		case librarySearchIndexBusy:
+			field.SetTitle(i18n.Text("Indexing libraries…"))
+		case status.Built.IsZero():
+			field.SetTitle(i18n.Text("Not yet built"))
+		default:
+			field.SetTitle(fmt.Sprintf(i18n.Text("%d items in %d files, updated %s"), status.Items, status.Files,
+				status.Built.Local().Format(time.DateTime)))
+		}
+	})
+	d.searchIndexStatusField.Tooltip = newWrappedTooltip(i18n.Text(`The index used by Global Search to quickly find items within the libraries.
+It is brought up to date automatically when a library is updated.`))
+	content.AddChild(d.searchIndexStatusField)
+	rebuildButton := unison.NewSVGButton(svg.Reset)
+	rebuildButton.Tooltip = newWrappedTooltip(i18n.Text("Rebuild the search index from scratch"))
+	rebuildButton.ClickCallback = func() {
+		updateLibrarySearchIndex(true)
+		d.searchIndexStatusField.Sync()
+	}
+	content.AddChild(rebuildButton)
+}
+
+func (d *generalSettingsDockable) searchIndexUpdated() {
+	d.searchIndexStatusField.Sync()
+}
+
 func (d *generalSettingsDockable) createExternalPDFCmdLineField(content *unison.Panel) {
 	title := i18n.Text("External PDF Viewer")
 	content.AddChild(NewFieldLeadingLabel(title, false))
@@ -416,6 +491,76 @@ In most cases, you'll want to surround the $FILE variable with quotes.`))
 	content.AddChild(d.externalPDFCmdlineField)
 }
 
+func (d *generalSettingsDockable) createExportTemplatesDirField(content *unison.Panel) {
+	title := i18n.Text("Export Templates")
+	content.AddChild(NewFieldLeadingLabel(title, false))
+	d.exportTemplatesDirField = NewStringField(nil, "", title,
+		func() string { return gurps.GlobalSettings().General.ExportTemplatesDir },
+		func(s string) { gurps.GlobalSettings().General.ExportTemplatesDir = strings.TrimSpace(s) })
+	d.exportTemplatesDirField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	d.exportTemplatesDirField.Watermark = i18n.Text("Directory containing your own export templates")
+	d.exportTemplatesDirField.Tooltip = newWrappedTooltip(i18n.Text(`Each file in this directory is listed in the File > Export To menu under User Templates. The exported file will have the same extension as the template.
+
+If the first line of a template is "GCS Text Template v1" or "GCS HTML Template v1", the rest of the file is a Go text/template or html/template, respectively. The character's data is available as fields such as .Name, .Attributes, .Traits, .Skills, .Spells, .Equipment, and .MeleeWeapons, while .Entity provides the full character model, including its computed values.`))
+	content.AddChild(d.exportTemplatesDirField)
+	button := unison.NewSVGButton(svg.OpenFolder)
+	button.Tooltip = newWrappedTooltip(i18n.Text("Choose directory"))
+	button.ClickCallback = func() {
+		dialog := unison.NewOpenDialog()
+		dialog.SetAllowsMultipleSelection(false)
+		dialog.SetResolvesAliases(true)
+		dialog.SetCanChooseDirectories(true)
+		dialog.SetCanChooseFiles(false)
+		dir := gurps.GlobalSettings().General.ExportTemplatesDir
+		if !xos.IsDir(dir) {
+			dir = gurps.GlobalSettings().LastDir(gurps.DefaultLastDirKey)
+		}
+		dialog.SetInitialDirectory(dir)
+		if dialog.RunModal() {
+			d.exportTemplatesDirField.SetText(dialog.Path())
+		}
+	}
+	content.AddChild(button)
+}
+
+func (d *generalSettingsDockable) createPluginsDirField(content *unison.Panel) {
+	title := i18n.Text("Plugins")
+	content.AddChild(NewFieldLeadingLabel(title, false))
+	d.pluginsDirField = NewStringField(nil, "", title,
+		func() string { return gurps.GlobalSettings().General.PluginsDir },
+		func(s string) { gurps.GlobalSettings().General.PluginsDir = strings.TrimSpace(s) })
+	d.pluginsDirField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	d.pluginsDirField.Watermark = i18n.Text("Directory containing third-party plugins")
+	d.pluginsDirField.Tooltip = newWrappedTooltip(i18n.Text(`Each sub-directory of this directory that contains a plugin.json manifest is loaded as a plugin. A plugin names an executable to run and may provide export formats, which are listed in the File > Export To menu, import formats, which are listed in the File > Import From menu, and sheet panels, which are listed in the Window > Plugin Panels menu.
+
+The executable is passed the command (export, import or panel), the id of the format or panel, and the paths of the files to work with. Sheets are exchanged as GCS sheet files. Panels write markdown to their standard output.`))
+	content.AddChild(d.pluginsDirField)
+	button := unison.NewSVGButton(svg.OpenFolder)
+	button.Tooltip = newWrappedTooltip(i18n.Text("Choose directory"))
+	button.ClickCallback = func() {
+		dialog := unison.NewOpenDialog()
+		dialog.SetAllowsMultipleSelection(false)
+		dialog.SetResolvesAliases(true)
+		dialog.SetCanChooseDirectories(true)
+		dialog.SetCanChooseFiles(false)
+		dir := gurps.GlobalSettings().General.PluginsDir
+		if !xos.IsDir(dir) {
+			dir = gurps.GlobalSettings().LastDir(gurps.DefaultLastDirKey)
+		}
+		dialog.SetInitialDirectory(dir)
+		if dialog.RunModal() {
+			d.pluginsDirField.SetText(dialog.Path())
+		}
+	}
+	content.AddChild(button)
+}
+
 func (d *generalSettingsDockable) createLocaleField(content *unison.Panel) {
 	title := i18n.Text("Interface Locale")
 	content.AddChild(NewFieldLeadingLabel(title, false))
@@ -432,6 +577,175 @@ func (d *generalSettingsDockable) createLocaleField(content *unison.Panel) {
 	content.AddChild(d.localeField)
 }
 
+func (d *generalSettingsDockable) createDiscordBlock(content *unison.Panel) {
+	content.AddChild(unison.NewLabel())
+	panel := unison.NewPanel()
+	panel.SetBorder(unison.NewCompoundBorder(&TitledBorder{
+		Title: i18n.Text("Discord"),
+		Font:  unison.DefaultLabelTheme.Font,
+	},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(unison.StdHSpacing,
+			unison.StdVSpacing))))
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Webhook"), false))
+	d.discordWebhookField = NewStringField(nil, "", i18n.Text("Discord Webhook URL"),
+		func() string { return gurps.GlobalSettings().General.DiscordWebhookURL },
+		func(s string) { gurps.GlobalSettings().General.DiscordWebhookURL = strings.TrimSpace(s) })
+	d.discordWebhookField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	d.discordWebhookField.ObscurementRune = '•'
+	d.discordWebhookField.Tooltip = newWrappedTooltip(i18n.Text(`The webhook URL for the campaign channel, as provided by the channel's Integrations settings in Discord`))
+	panel.AddChild(d.discordWebhookField)
+
+	d.discordRollsCheckbox = NewCheckBox(nil, "", i18n.Text("Announce dice rolls"),
+		func() check.Enum {
+			return check.FromBool(gurps.GlobalSettings().General.DiscordAnnounceRolls)
+		},
+		func(state check.Enum) {
+			gurps.GlobalSettings().General.DiscordAnnounceRolls = state == check.On
+		})
+	panel.AddChild(unison.NewPanel())
+	panel.AddChild(d.discordRollsCheckbox)
+
+	d.discordThresholdsCheckbox = NewCheckBox(nil, "",
+		i18n.Text("Announce when a character's pools fall to a worse threshold state"),
+		func() check.Enum {
+			return check.FromBool(gurps.GlobalSettings().General.DiscordAnnounceThresholds)
+		},
+		func(state check.Enum) {
+			gurps.GlobalSettings().General.DiscordAnnounceThresholds = state == check.On
+		})
+	panel.AddChild(unison.NewPanel())
+	panel.AddChild(d.discordThresholdsCheckbox)
+	content.AddChild(panel)
+}
+
+func (d *generalSettingsDockable) createAPIServerBlock(content *unison.Panel) {
+	content.AddChild(unison.NewLabel())
+	panel := unison.NewPanel()
+	panel.SetBorder(unison.NewCompoundBorder(&TitledBorder{
+		Title: i18n.Text("Local API Server"),
+		Font:  unison.DefaultLabelTheme.Font,
+	},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(unison.StdHSpacing,
+			unison.StdVSpacing))))
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+
+	d.apiServerEnabledCheckbox = NewCheckBox(nil, "", i18n.Text("Allow companion applications to access the open sheets"),
+		func() check.Enum {
+			return check.FromBool(gurps.GlobalSettings().General.APIServerEnabled)
+		},
+		func(state check.Enum) {
+			gs := gurps.GlobalSettings().General
+			gs.APIServerEnabled = state == check.On
+			if gs.APIServerEnabled && gs.APIServerToken == "" {
+				gs.APIServerToken = restapi.NewToken()
+				SetFieldValue(d.apiServerTokenField.Field, gs.APIServerToken)
+			}
+			UpdateAPIServer()
+		})
+	d.apiServerEnabledCheckbox.Tooltip = newWrappedTooltip(i18n.Text(`When enabled, an HTTP server listens on the local machine only. Stream overlays, companion apps, and VTT bridges, such as the GCS Bridge module for Foundry VTT, may use it to read the open sheets, follow their changes and every roll made, change attributes and pools, and make rolls. Each request must include the token as a bearer token in its Authorization header.`))
+	panel.AddChild(unison.NewPanel())
+	panel.AddChild(WrapWithSpan(2, d.apiServerEnabledCheckbox))
+
+	title := i18n.Text("Port")
+	panel.AddChild(NewFieldLeadingLabel(title, false))
+	d.apiServerPortField = NewIntegerField(nil, "", i18n.Text("Local API Server Port"),
+		apiServerPortFromSettings,
+		func(v int) {
+			gurps.GlobalSettings().General.APIServerPort = v
+			UpdateAPIServer()
+		},
+		1024, 65535, false, false)
+	panel.AddChild(WrapWithSpan(2, d.apiServerPortField))
+
+	title = i18n.Text("Token")
+	panel.AddChild(NewFieldLeadingLabel(title, false))
+	d.apiServerTokenField = NewStringField(nil, "", i18n.Text("Local API Server Token"),
+		func() string { return gurps.GlobalSettings().General.APIServerToken },
+		func(s string) {
+			gurps.GlobalSettings().General.APIServerToken = strings.TrimSpace(s)
+			UpdateAPIServer()
+		})
+	d.apiServerTokenField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	d.apiServerTokenField.ObscurementRune = '•'
+	d.apiServerTokenField.Tooltip = newWrappedTooltip(i18n.Text("The token clients must present to use the server"))
+	panel.AddChild(d.apiServerTokenField)
+	button := unison.NewSVGButton(svg.Randomize)
+	button.Tooltip = newWrappedTooltip(i18n.Text("Generate a new token"))
+	button.ClickCallback = func() { d.apiServerTokenField.SetText(restapi.NewToken()) }
+	panel.AddChild(button)
+	content.AddChild(panel)
+}
+
+func (d *generalSettingsDockable) createStatblockBlock(content *unison.Panel) {
+	content.AddChild(unison.NewLabel())
+	panel := unison.NewPanel()
+	panel.SetBorder(unison.NewCompoundBorder(&TitledBorder{
+		Title: i18n.Text("Statblock"),
+		Font:  unison.DefaultLabelTheme.Font,
+	},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(unison.StdHSpacing,
+			unison.StdVSpacing))))
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+
+	title := i18n.Text("Skill Threshold")
+	panel.AddChild(NewFieldLeadingLabel(title, false))
+	d.statblockThresholdField = NewIntegerField(nil, "", title,
+		func() int { return gurps.GlobalSettings().General.StatblockSkillThreshold },
+		func(v int) { gurps.GlobalSettings().General.StatblockSkillThreshold = v },
+		gurps.StatblockSkillThresholdMin, gurps.StatblockSkillThresholdMax, false, false)
+	d.statblockThresholdField.Tooltip = newWrappedTooltip(i18n.Text("Skills and spells below this level are left out of statblocks"))
+	panel.AddChild(d.statblockThresholdField)
+
+	title = i18n.Text("Template")
+	panel.AddChild(NewFieldLeadingLabel(title, false))
+	d.statblockTemplateField = NewMultiLineStringField(nil, "", title,
+		func() string { return gurps.GlobalSettings().General.StatblockTemplate },
+		func(s string) { gurps.GlobalSettings().General.StatblockTemplate = s })
+	d.statblockTemplateField.Watermark = i18n.Text("Leave empty to use the default template")
+	d.statblockTemplateField.Tooltip = newWrappedTooltip(i18n.Text(`A Go text/template used to produce statblocks. The fields available are .Name, .Attributes, .Dodge, .Move, .DR, .Traits, .Skills, .Spells, and .Attacks. Lists may be combined with the join function, e.g. {{join .Skills "; "}}`))
+	d.statblockTemplateField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	panel.AddChild(d.statblockTemplateField)
+	content.AddChild(panel)
+}
+
 func (d *generalSettingsDockable) createDeepSearchCheckboxes(content *unison.Panel) {
 	content.AddChild(unison.NewLabel())
 	panel := unison.NewPanel()
@@ -543,6 +857,8 @@ func (d *generalSettingsDockable) sync() {
 	SetCheckBoxState(d.groupContainersOnSortCheckbox, gs.GroupContainersOnSort)
 	SetCheckBoxState(d.autoAddNaturalAttacksCheckbox, gs.AutoAddNaturalAttacks)
 	SetCheckBoxState(d.initialClickSelectsAllCheckbox, gs.InitialFieldClickSelectsAll)
+	SetCheckBoxState(d.snapshotOnSaveCheckbox, gs.SnapshotOnSave)
+	SetCheckBoxState(d.fillablePDFExportCheckbox, gs.FillablePDFExport)
 	d.pointsField.SetText(gs.InitialPoints.String())
 	d.techLevelField.SetText(gs.DefaultTechLevel)
 	d.calendarPopup.Select(gs.CalendarRef(s.Libraries()).Name)
@@ -561,7 +877,17 @@ func (d *generalSettingsDockable) sync() {
 	d.tooltipDismissalField.SetText(gs.TooltipDismissal.String())
 	d.scrollWheelMultiplierField.SetText(gs.ScrollWheelMultiplier.String())
 	SetFieldValue(d.externalPDFCmdlineField.Field, gs.ExternalPDFCmdLine)
+	SetFieldValue(d.exportTemplatesDirField.Field, gs.ExportTemplatesDir)
+	SetFieldValue(d.pluginsDirField.Field, gs.PluginsDir)
 	SetFieldValue(d.localeField.Field, languageSetting)
+	SetFieldValue(d.discordWebhookField.Field, gs.DiscordWebhookURL)
+	SetCheckBoxState(d.discordRollsCheckbox, gs.DiscordAnnounceRolls)
+	SetCheckBoxState(d.discordThresholdsCheckbox, gs.DiscordAnnounceThresholds)
+	SetCheckBoxState(d.apiServerEnabledCheckbox, gs.APIServerEnabled)
+	d.apiServerPortField.SetText(strconv.Itoa(apiServerPortFromSettings()))
+	SetFieldValue(d.apiServerTokenField.Field, gs.APIServerToken)
+	d.statblockThresholdField.SetText(strconv.Itoa(gs.StatblockSkillThreshold))
+	SetFieldValue(d.statblockTemplateField.Field, gs.StatblockTemplate)
 	for _, box := range d.deepSearchableCheckbox {
 		if extAny, ok := box.ClientData()["ext"]; ok {
 			if ext, ok2 := extAny.(string); ok2 {
@@ -576,6 +902,7 @@ func (d *generalSettingsDockable) sync() {
 			}
 		}
 	}
+	UpdateAPIServer()
 	d.MarkForRedraw()
 }
 
@@ -594,6 +921,14 @@ func (d *generalSettingsDockable) save(filePath string) error {
 }
 
 func (d *generalSettingsDockable) willClose() bool {
+	setLanguageSetting(languageSetting)
+	return true
+}
+
+// setLanguageSetting applies the language setting and saves it to disk. An empty setting means the system locale is
+// used.
+func setLanguageSetting(setting string) {
+	languageSetting = setting
 	filePath := languageSettingPath()
 	if languageSetting == "" {
 		i18n.Language = i18n.Locale()
@@ -606,11 +941,12 @@ func (d *generalSettingsDockable) willClose() bool {
 			errs.Log(err, "path", filePath)
 		}
 	}
-	return true
 }
 
-// LoadLanguageSetting loads the language setting from disk, if present, and applies it.
+// LoadLanguageSetting loads the language setting from disk, if present, and applies it, along with any changes the
+// user has made to the translations.
 func LoadLanguageSetting() {
+	installTranslations()
 	if data, err := os.ReadFile(languageSettingPath()); err == nil {
 		if s := strings.TrimSpace(strings.SplitN(strings.TrimSpace(string(data)), "\n", 2)[0]); len(s) > 1 && len(s) < 20 {
 			i18n.Language = s
@@ -0,0 +1,134 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xrand"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+// NewSheetFromGenerator asks the user for a template and the randomization rules to use, then generates a new
+// character sheet from them.
+func NewSheetFromGenerator() {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.TemplatesExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	if !dialog.RunModal() {
+		return
+	}
+	templatePath := dialog.Path()
+	global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(templatePath))
+	tmpl, err := gurps.NewTemplateFromFile(os.DirFS(filepath.Dir(templatePath)), filepath.Base(templatePath))
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load template"), err)
+		return
+	}
+	rules := &gurps.GeneratorRules{RandomizeProfile: true}
+	disadvantagesPath, ok := promptForGeneratorRules(rules)
+	if !ok {
+		return
+	}
+	if disadvantagesPath != "" {
+		if rules.Disadvantages, err = gurps.NewTraitsFromFile(os.DirFS(filepath.Dir(disadvantagesPath)),
+			filepath.Base(disadvantagesPath)); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to load disadvantages"), err)
+			return
+		}
+		rules.DisadvantagesFrom = libraryFileForPath(disadvantagesPath)
+	}
+	e := gurps.GenerateCharacter(tmpl, rules, xrand.New())
+	DisplayNewDockable(NewSheet(e.Profile.Name+gurps.SheetExt, e))
+}
+
+func promptForGeneratorRules(rules *gurps.GeneratorRules) (disadvantagesPath string, ok bool) {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Attribute Points"), false))
+	attrField := NewDecimalField(nil, "", "", func() fxp.Int { return rules.AttributePoints },
+		func(value fxp.Int) { rules.AttributePoints = value }, 0, fxp.MaxBasePoints, false, false)
+	attrField.Tooltip = newWrappedTooltip(i18n.Text("The points to spread at random across the primary attributes"))
+	panel.AddChild(attrField)
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Disadvantage Points"), false))
+	disadField := NewDecimalField(nil, "", "", func() fxp.Int { return rules.DisadvantagePoints },
+		func(value fxp.Int) { rules.DisadvantagePoints = value }, 0, fxp.MaxBasePoints, false, false)
+	disadField.Tooltip = newWrappedTooltip(i18n.Text("The most points worth of disadvantages to choose at random"))
+	panel.AddChild(disadField)
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Disadvantages From"), false))
+	wrapper := unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VAlign:   align.Middle,
+	})
+	fileLabel := unison.NewLabel()
+	fileLabel.SetTitle(i18n.Text("None"))
+	chooseButton := unison.NewButton()
+	chooseButton.SetTitle(i18n.Text("Choose…"))
+	chooseButton.ClickCallback = func() {
+		openDialog := unison.NewOpenDialog()
+		openDialog.SetAllowsMultipleSelection(false)
+		openDialog.SetResolvesAliases(true)
+		openDialog.SetAllowedExtensions(gurps.TraitsExt)
+		openDialog.SetCanChooseDirectories(false)
+		openDialog.SetCanChooseFiles(true)
+		if disadvantagesPath != "" {
+			openDialog.SetInitialDirectory(filepath.Dir(disadvantagesPath))
+		} else {
+			openDialog.SetInitialDirectory(gurps.GlobalSettings().Libraries().Master().Path())
+		}
+		if openDialog.RunModal() {
+			disadvantagesPath = openDialog.Path()
+			fileLabel.SetTitle(filepath.Base(disadvantagesPath))
+			fileLabel.Tooltip = newWrappedTooltip(disadvantagesPath)
+			wrapper.MarkForLayoutRecursivelyUpward()
+			wrapper.MarkForRedraw()
+		}
+	}
+	wrapper.AddChild(chooseButton)
+	wrapper.AddChild(fileLabel)
+	panel.AddChild(wrapper)
+
+	panel.AddChild(unison.NewPanel())
+	randomizeBox := unison.NewCheckBox()
+	randomizeBox.SetTitle(i18n.Text("Randomize the name and description"))
+	randomizeBox.State = check.FromBool(rules.RandomizeProfile)
+	randomizeBox.ClickCallback = func() { rules.RandomizeProfile = randomizeBox.State == check.On }
+	panel.AddChild(randomizeBox)
+
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()})
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create generator dialog"), err)
+		return "", false
+	}
+	ok = dialog.RunModal() == unison.ModalResponseOK
+	return disadvantagesPath, ok
+}
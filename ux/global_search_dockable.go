@@ -0,0 +1,362 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+const globalSearchMaxResults = 500
+
+var (
+	_ unison.Dockable  = &GlobalSearchDockable{}
+	_ unison.TabCloser = &GlobalSearchDockable{}
+)
+
+var (
+	librarySearchIndexOnce    sync.Once
+	librarySearchIndexData    *gurps.SearchIndex
+	librarySearchIndexBusy    bool
+	librarySearchIndexPending bool
+	librarySearchIndexRebuild bool
+	// These are updated from the goroutine doing the indexing
+	librarySearchIndexDone            atomic.Int64
+	librarySearchIndexTotal           atomic.Int64
+	librarySearchIndexProgressPending atomic.Bool
+)
+
+// searchIndexWatcher is implemented by dockables that want to know when the library search index has been updated.
+type searchIndexWatcher interface {
+	searchIndexUpdated()
+}
+
+// searchHolder defines the methods a dockable must provide for its content to be included in a global search.
+type searchHolder interface {
+	unison.Dockable
+	BackingFilePath() string
+	searchNodes(hits []*gurps.SearchHit, text string) []*gurps.SearchHit
+	locateNode(id tid.TID) bool
+}
+
+type globalSearchResult struct {
+	hit    *gurps.SearchHit
+	holder searchHolder
+}
+
+// GlobalSearchDockable searches the names, notes, tags, and VTT notes of the items in all libraries and open documents.
+type GlobalSearchDockable struct {
+	unison.Panel
+	searchField *unison.Field
+	statusLabel *unison.Label
+	content     *unison.Panel
+}
+
+// ShowGlobalSearch shows the global search.
+func ShowGlobalSearch() {
+	var existing *GlobalSearchDockable
+	if Activate(func(d unison.Dockable) bool {
+		var ok bool
+		existing, ok = d.AsPanel().Self.(*GlobalSearchDockable)
+		return ok
+	}) {
+		existing.searchField.RequestFocus()
+		existing.searchField.SelectAll()
+		return
+	}
+	d := &GlobalSearchDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	PlaceInDock(d, dgroup.Editors, false)
+	d.searchField.RequestFocus()
+	updateLibrarySearchIndex(false)
+	d.rebuild()
+}
+
+func (d *GlobalSearchDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	refreshButton := unison.NewSVGButton(svg.Reset)
+	refreshButton.Tooltip = newWrappedTooltip(i18n.Text("Re-read any library files that have changed on disk"))
+	refreshButton.ClickCallback = func() {
+		updateLibrarySearchIndex(false)
+		d.rebuild()
+	}
+	toolbar.AddChild(refreshButton)
+	d.searchField = NewSearchField(i18n.Text("Search names, notes, tags & VTT notes"),
+		func(_, _ *unison.FieldState) { d.rebuild() })
+	toolbar.AddChild(d.searchField)
+	d.statusLabel = unison.NewLabel()
+	toolbar.AddChild(d.statusLabel)
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *GlobalSearchDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	results := d.search(d.searchField.Text())
+	switch {
+	case librarySearchIndexBusy:
+		d.setStatus(i18n.Text("Indexing libraries…"))
+	case len(results) > globalSearchMaxResults:
+		d.setStatus(fmt.Sprintf(i18n.Text("Showing the first %d of %d matches"), globalSearchMaxResults,
+			len(results)))
+		results = results[:globalSearchMaxResults]
+	case len(results) == 1:
+		d.setStatus(i18n.Text("1 match"))
+	default:
+		d.setStatus(fmt.Sprintf(i18n.Text("%d matches"), len(results)))
+	}
+	lastKind := ""
+	for _, result := range results {
+		if result.hit.Kind != lastKind {
+			lastKind = result.hit.Kind
+			d.content.AddChild(d.createHeader(lastKind, len(d.content.Children()) != 0))
+		}
+		d.content.AddChild(d.createResultLabel(result))
+	}
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *GlobalSearchDockable) search(text string) []*globalSearchResult {
+	var results []*globalSearchResult
+	openPaths := make(map[string]bool)
+	for _, one := range AllDockables() {
+		if holder, ok := one.(searchHolder); ok {
+			openPaths[holder.BackingFilePath()] = true
+			for _, hit := range holder.searchNodes(nil, text) {
+				results = append(results, &globalSearchResult{
+					hit:    hit,
+					holder: holder,
+				})
+			}
+		}
+	}
+	for _, hit := range librarySearchIndex().Search(text) {
+		// Open documents may have unsaved changes, so prefer the hits found within them.
+		if !openPaths[hit.FilePath] {
+			results = append(results, &globalSearchResult{hit: hit})
+		}
+	}
+	slices.SortFunc(results, func(a, b *globalSearchResult) int { return gurps.CompareSearchHits(a.hit, b.hit) })
+	return results
+}
+
+func (d *GlobalSearchDockable) searchIndexUpdated() {
+	d.rebuild()
+}
+
+func (d *GlobalSearchDockable) setStatus(text string) {
+	d.statusLabel.SetTitle(text)
+	d.statusLabel.Parent().MarkForLayoutAndRedraw()
+}
+
+func (d *GlobalSearchDockable) createHeader(text string, addTopMargin bool) *unison.Label {
+	label := unison.NewLabel()
+	label.Font = &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			desc := unison.DefaultLabelTheme.Font.Descriptor()
+			desc.Weight = weight.Bold
+			return desc
+		},
+	}
+	label.SetTitle(text)
+	if addTopMargin {
+		label.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+	}
+	return label
+}
+
+func (d *GlobalSearchDockable) createResultLabel(result *globalSearchResult) *unison.Label {
+	label := unison.NewLabel()
+	label.SetTitle(fmt.Sprintf(i18n.Text("%s — %s (matched %s)"), result.hit.Name,
+		filepath.Base(result.hit.FilePath), result.hit.Matched))
+	label.Tooltip = newWrappedTooltip(fmt.Sprintf(i18n.Text("%s\n\nDouble-click to open and locate this item."),
+		result.hit.FilePath))
+	label.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: unison.StdHSpacing * 2}))
+	label.MouseDownCallback = func(_ geom.Point, _, clickCount int, _ unison.Modifiers) bool {
+		if clickCount == 2 {
+			d.open(result)
+		}
+		return true
+	}
+	return label
+}
+
+func (d *GlobalSearchDockable) open(result *globalSearchResult) {
+	holder := result.holder
+	if holder == nil {
+		dockable, _ := OpenFile(result.hit.FilePath, 0)
+		var ok bool
+		if holder, ok = dockable.(searchHolder); !ok {
+			return
+		}
+	} else {
+		ActivateDockable(holder)
+	}
+	holder.locateNode(result.hit.ID)
+}
+
+func librarySearchIndex() *gurps.SearchIndex {
+	librarySearchIndexOnce.Do(func() {
+		librarySearchIndexData = gurps.NewSearchIndex()
+		if err := librarySearchIndexData.Load(gurps.SearchIndexPath()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs.Log(err)
+		}
+	})
+	return librarySearchIndexData
+}
+
+// updateLibrarySearchIndex brings the library search index up to date in the background, re-reading only those files
+// that have changed since they were last indexed unless rebuild is true. Dockables that implement searchIndexWatcher
+// are notified once the update completes.
+func updateLibrarySearchIndex(rebuild bool) {
+	if librarySearchIndexBusy {
+		librarySearchIndexPending = true
+		librarySearchIndexRebuild = librarySearchIndexRebuild || rebuild
+		return
+	}
+	librarySearchIndexBusy = true
+	librarySearchIndexDone.Store(0)
+	librarySearchIndexTotal.Store(0)
+	updateLibrarySearchIndexProgress()
+	libs := gurps.GlobalSettings().Libraries().List()
+	dirs := make([]string, 0, len(libs))
+	for _, lib := range libs {
+		dirs = append(dirs, lib.Path())
+	}
+	go func() {
+		progress := func(done, total int) {
+			librarySearchIndexDone.Store(int64(done))
+			librarySearchIndexTotal.Store(int64(total))
+			if !librarySearchIndexProgressPending.Swap(true) {
+				unison.InvokeTaskAfter(func() {
+					librarySearchIndexProgressPending.Store(false)
+					updateLibrarySearchIndexProgress()
+				}, 100*time.Millisecond)
+			}
+		}
+		index := librarySearchIndex()
+		changed := true
+		if rebuild {
+			index.Rebuild(progress, dirs...)
+		} else {
+			changed = index.Refresh(progress, dirs...)
+		}
+		if changed {
+			if err := index.Save(gurps.SearchIndexPath()); err != nil {
+				errs.Log(err)
+			}
+		}
+		unison.InvokeTask(func() {
+			librarySearchIndexBusy = false
+			updateLibrarySearchIndexProgress()
+			if librarySearchIndexPending {
+				pendingRebuild := librarySearchIndexRebuild
+				librarySearchIndexPending = false
+				librarySearchIndexRebuild = false
+				updateLibrarySearchIndex(pendingRebuild)
+			}
+			for _, one := range AllDockables() {
+				if watcher, ok := one.(searchIndexWatcher); ok {
+					watcher.searchIndexUpdated()
+				}
+			}
+		})
+	}()
+}
+
+// updateLibrarySearchIndexProgress updates the progress shown for the library search index in the navigator.
+func updateLibrarySearchIndexProgress() {
+	if Workspace.Navigator != nil {
+		Workspace.Navigator.showIndexProgress(librarySearchIndexBusy, int(librarySearchIndexDone.Load()),
+			int(librarySearchIndexTotal.Load()))
+	}
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *GlobalSearchDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Database,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *GlobalSearchDockable) Title() string {
+	return i18n.Text("Global Search")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *GlobalSearchDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *GlobalSearchDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *GlobalSearchDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *GlobalSearchDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
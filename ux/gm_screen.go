@@ -0,0 +1,252 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+// The GM screen lays out the blocks 2-up across and 2-up down, so that each member gets a quarter of a page.
+const (
+	gmScreenColumns   = 2
+	gmScreenRows      = 2
+	gmScreenSpacing   = 4
+	gmScreenTopSkills = 8
+)
+
+var _ unison.PageProvider = &gmScreenExporter{}
+
+type gmScreenExporter struct {
+	title    string
+	settings *gurps.PageSettings
+	pages    []*unison.Panel
+}
+
+func newGMScreenExporter(title string, settings *gurps.PageSettings, blocks []*gurps.GMScreenBlock) *gmScreenExporter {
+	p := &gmScreenExporter{
+		title:    title,
+		settings: settings,
+	}
+	pageSize := p.PageSize()
+	insets := geom.Insets{
+		Top:    settings.TopMargin.Pixels(),
+		Left:   settings.LeftMargin.Pixels(),
+		Bottom: settings.BottomMargin.Pixels(),
+		Right:  settings.RightMargin.Pixels(),
+	}
+	blockSize := geom.Size{
+		Width: (pageSize.Width - (insets.Left + insets.Right + gmScreenSpacing*(gmScreenColumns-1))) /
+			gmScreenColumns,
+		Height: (pageSize.Height - (insets.Top + insets.Bottom + gmScreenSpacing*(gmScreenRows-1))) / gmScreenRows,
+	}
+	var page *unison.Panel
+	for i, block := range blocks {
+		if i%(gmScreenColumns*gmScreenRows) == 0 {
+			page = unison.NewPanel()
+			page.SetBorder(unison.NewEmptyBorder(insets))
+			page.SetLayout(&unison.FlexLayout{
+				Columns:      gmScreenColumns,
+				HSpacing:     gmScreenSpacing,
+				VSpacing:     gmScreenSpacing,
+				EqualColumns: true,
+			})
+			page.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+				gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+			}
+			p.pages = append(p.pages, page)
+		}
+		panel := newGMScreenBlockPanel(block)
+		panel.SetLayoutData(&unison.FlexLayoutData{
+			MinSize:  blockSize,
+			SizeHint: blockSize,
+			HAlign:   align.Fill,
+			VAlign:   align.Start,
+		})
+		page.AddChild(panel)
+	}
+	for _, one := range p.pages {
+		one.SetFrameRect(geom.Rect{Size: pageSize})
+		one.MarkForLayoutRecursively()
+		one.ValidateLayout()
+	}
+	return p
+}
+
+func newGMScreenBlockPanel(block *gurps.GMScreenBlock) *unison.Panel {
+	title := block.Name
+	if title == "" {
+		title = i18n.Text("Unnamed")
+	}
+	if block.Player != "" {
+		title = fmt.Sprintf(i18n.Text("%s (%s)"), title, block.Player)
+	}
+	panel := unison.NewPanel()
+	panel.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: title},
+		unison.NewEmptyBorder(geom.NewUniformInsets(2))))
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: 4,
+	})
+	panel.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	addGMScreenSection(panel, i18n.Text("Pools"), block.Pools)
+	addGMScreenSection(panel, i18n.Text("Defenses"), block.Defenses)
+	addGMScreenSection(panel, i18n.Text("Top Skills"), block.Skills)
+	addGMScreenSection(panel, i18n.Text("Reactions"), block.Reactions)
+	return panel
+}
+
+// addGMScreenSection adds a header and the values beneath it, two to a row.
+func addGMScreenSection(panel *unison.Panel, title string, values []gurps.GMScreenValue) {
+	if len(values) == 0 {
+		return
+	}
+	panel.AddChild(NewPageHeader(title, 4))
+	for _, one := range values {
+		panel.AddChild(NewPageLabel(one.Label))
+		panel.AddChild(NewPageLabelEnd(one.Value))
+	}
+	if len(values)%2 != 0 {
+		spacer := unison.NewPanel()
+		spacer.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+		panel.AddChild(spacer)
+	}
+}
+
+// HasPage implements unison.PageProvider.
+func (p *gmScreenExporter) HasPage(pageNumber int) bool {
+	return pageNumber > 0 && pageNumber <= len(p.pages)
+}
+
+// PageSize implements unison.PageProvider.
+func (p *gmScreenExporter) PageSize() geom.Size {
+	w, h := p.settings.Orientation.Dimensions(gurps.MustParsePageSize(p.settings.Size))
+	return geom.NewSize(w.Pixels(), h.Pixels())
+}
+
+// DrawPage implements unison.PageProvider.
+func (p *gmScreenExporter) DrawPage(canvas *unison.Canvas, pageNumber int) error {
+	if pageNumber > 0 && pageNumber <= len(p.pages) {
+		page := p.pages[pageNumber-1]
+		page.Draw(canvas, page.ContentRect(true))
+		return nil
+	}
+	return errs.New("invalid page number")
+}
+
+func (p *gmScreenExporter) exportAsPDF(stream unison.Stream) error {
+	savedColorMode := saveTheme()
+	defer restoreTheme(savedColorMode)
+	return unison.CreatePDF(stream, &unison.PDFMetaData{
+		Title:           p.title,
+		Author:          xos.CurrentUserName(),
+		Subject:         p.title,
+		Creator:         xos.AppName,
+		RasterDPI:       300,
+		EncodingQuality: 101,
+	}, p)
+}
+
+func (p *gmScreenExporter) exportAsPDFBytes() ([]byte, error) {
+	stream := unison.NewMemoryStream()
+	defer stream.Close()
+	if err := p.exportAsPDF(stream); err != nil {
+		return nil, err
+	}
+	return stream.Bytes(), nil
+}
+
+func (p *gmScreenExporter) exportAsPDFFile(filePath string) error {
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errs.Wrap(err)
+	}
+	stream, err := unison.NewFileStream(filePath)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	return p.exportAsPDF(stream)
+}
+
+// gmScreenExporter creates an exporter for the GM screen summary of the campaign's members, using the paper settings of
+// the campaign. Returns nil if the campaign has no members that could be loaded.
+func (c *Campaign) gmScreenExporter() *gmScreenExporter {
+	blocks := make([]*gurps.GMScreenBlock, 0, len(c.campaign.Members))
+	for _, member := range c.campaign.Members {
+		memberPath := member.AbsolutePath(c.path)
+		var entity *gurps.Entity
+		if sheet := c.openSheetFor(memberPath); sheet != nil {
+			entity = sheet.entity
+		} else {
+			var err error
+			if entity, err = gurps.NewEntityFromFile(os.DirFS(filepath.Dir(memberPath)),
+				filepath.Base(memberPath)); err != nil {
+				Workspace.ErrorHandler(fmt.Sprintf(i18n.Text("Unable to load %s"), xfilepath.BaseName(memberPath)), err)
+				continue
+			}
+		}
+		blocks = append(blocks, gurps.NewGMScreenBlock(entity, gmScreenTopSkills))
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	settings := c.campaign.SheetSettings
+	if settings == nil {
+		settings = gurps.GlobalSettings().SheetSettings()
+	}
+	return newGMScreenExporter(fmt.Sprintf(i18n.Text("%s GM Screen"), c.Title()), settings.Page, blocks)
+}
+
+func (c *Campaign) exportGMScreen() {
+	exporter := c.gmScreenExporter()
+	if exporter == nil {
+		return
+	}
+	dialog := unison.NewSaveDialog()
+	dialog.SetInitialDirectory(filepath.Dir(c.path))
+	dialog.SetAllowedExtensions("pdf")
+	dialog.SetInitialFileName(xfilepath.SanitizeName(exporter.title))
+	if dialog.RunModal() {
+		if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), "pdf", false); ok {
+			gurps.GlobalSettings().SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(filePath))
+			if err := exporter.exportAsPDFFile(filePath); err != nil {
+				Workspace.ErrorHandler(i18n.Text("Unable to export the GM screen!"), err)
+			}
+		}
+	}
+}
+
+func (c *Campaign) printGMScreen() {
+	exporter := c.gmScreenExporter()
+	if exporter == nil {
+		return
+	}
+	data, err := exporter.exportAsPDFBytes()
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create print data!"), err)
+		return
+	}
+	printPDF(exporter.title, data)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"strconv"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+)
+
+var _ pageHelper = &GrapplingPanel{}
+
+// GrapplingPanel holds the Technical Grappling information for a character.
+type GrapplingPanel struct {
+	unison.Panel
+	entity    *gurps.Entity
+	targetMgr *TargetMgr
+	prefix    string
+	drawStart int
+	drawEnd   int
+}
+
+// NewGrapplingPanel creates a new grappling panel.
+func NewGrapplingPanel(entity *gurps.Entity, targetMgr *TargetMgr) *GrapplingPanel {
+	p := &GrapplingPanel{
+		entity:    entity,
+		targetMgr: targetMgr,
+		prefix:    targetMgr.NextPrefix(),
+		drawEnd:   1,
+	}
+	p.Self = p
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: 4,
+	})
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: i18n.Text("Grappling")},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(2, 1))))
+	p.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) { drawBandedBackground(p, gc, rect, 0, 4, nil) }
+
+	p.addValue(i18n.Text("Trained ST"), func() string { return p.entity.TrainedST().String() })
+	p.addValue(i18n.Text("Control"), func() string { return p.entity.ControlDamage().String() + " CP" })
+
+	p.AddChild(NewIntegerPageField(p.targetMgr, p.prefix+"cp_inflicted", i18n.Text("Control Points Inflicted"),
+		func() int { return p.entity.Grapple.Inflicted },
+		func(v int) { p.entity.Grapple.Inflicted = v }, 0, 9999, false, false))
+	p.AddChild(NewPageLabel(i18n.Text("CP Inflicted")))
+	p.AddChild(NewIntegerPageField(p.targetMgr, p.prefix+"cp_suffered", i18n.Text("Control Points Suffered"),
+		func() int { return p.entity.Grapple.Suffered },
+		func(v int) { p.entity.Grapple.Suffered = v }, 0, 9999, false, false))
+	p.AddChild(NewPageLabel(i18n.Text("CP Suffered")))
+
+	p.addValue(i18n.Text("DX Penalty"), func() string { return strconv.Itoa(p.entity.GrapplePenalty()) })
+	return p
+}
+
+func (p *GrapplingPanel) addValue(title string, f func() string) {
+	p.AddChild(NewNonEditablePageFieldEnd(func(field *NonEditablePageField) {
+		field.SetTitle(f())
+		MarkForLayoutWithinDockable(field)
+	}))
+	p.AddChild(NewPageLabel(title))
+}
+
+// Enabled returns true if the Technical Grappling rules are in use and the panel should be shown.
+func (p *GrapplingPanel) Enabled() bool {
+	return p.entity.TechnicalGrappling()
+}
+
+// OverheadHeight implements pageHelper.
+func (p *GrapplingPanel) OverheadHeight() float32 {
+	return 0
+}
+
+// RowHeights implements pageHelper. The panel is always kept together, so it is reported as a single row.
+func (p *GrapplingPanel) RowHeights() []float32 {
+	return []float32{p.FrameRect().Height}
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *GrapplingPanel) CurrentDrawRowRange() (start, endBefore int) {
+	return p.drawStart, p.drawEnd
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *GrapplingPanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+}
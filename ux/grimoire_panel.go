@@ -0,0 +1,500 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/rpm"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var _ pageHelper = &GrimoirePanel{}
+
+// GrimoirePanel holds the Ritual Path Magic rituals a character knows, along with the energy they have gathered.
+type GrimoirePanel struct {
+	unison.Panel
+	entity    *gurps.Entity
+	editable  bool
+	hash      uint64
+	drawStart int
+	drawEnd   int
+}
+
+// NewGrimoirePanel creates a new grimoire panel. If editable is true, controls for building rituals and gathering energy
+// will be included.
+func NewGrimoirePanel(entity *gurps.Entity, editable bool) *GrimoirePanel {
+	p := &GrimoirePanel{
+		entity:   entity,
+		editable: editable,
+		drawEnd:  1,
+	}
+	p.Self = p
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: i18n.Text("Grimoire")},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(2, 1))))
+	p.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	p.rebuild()
+	return p
+}
+
+// Enabled returns true if the Ritual Path Magic rules are in use and the panel should be shown.
+func (p *GrimoirePanel) Enabled() bool {
+	return p.entity.RitualPathMagic()
+}
+
+func (p *GrimoirePanel) columns() int {
+	if p.editable {
+		return 5
+	}
+	return 4
+}
+
+func (p *GrimoirePanel) rebuild() {
+	p.hash = p.entity.Grimoire.Hash()
+	p.RemoveAllChildren()
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  p.columns(),
+		HSpacing: 4,
+	})
+	p.AddChild(p.createSummary())
+	for _, title := range []string{
+		i18n.Text("Ritual"),
+		i18n.Text("Effects"),
+		i18n.Text("Energy"),
+		i18n.Text("Skill"),
+	} {
+		label := NewPageLabel(title)
+		label.Font = fonts.PageLabelSecondary
+		p.AddChild(label)
+	}
+	if p.editable {
+		p.AddChild(unison.NewPanel())
+	}
+	for _, r := range p.entity.Grimoire.Rituals {
+		name := NewPageLabel(r.Name)
+		var tips []string
+		if r.Notes != "" {
+			tips = append(tips, r.Notes)
+		}
+		if modifiers := r.ModifiersSummary(); modifiers != "" {
+			tips = append(tips, i18n.Text("Modifiers: ")+modifiers)
+		}
+		if len(tips) != 0 {
+			name.Tooltip = newWrappedTooltip(strings.Join(tips, "\n"))
+		}
+		p.AddChild(name)
+		effects := NewPageLabel(r.EffectsSummary())
+		effects.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		p.AddChild(effects)
+		energy := NewPageLabelEnd(strconv.Itoa(r.Energy()))
+		energy.Tooltip = newWrappedTooltip(r.EnergyDescription())
+		energy.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+		p.AddChild(energy)
+		skill := NewPageLabelEnd(p.entity.RitualLevel(r).Floor().String())
+		skill.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+		p.AddChild(skill)
+		if p.editable {
+			p.AddChild(p.createRitualButtons(r))
+		}
+	}
+}
+
+func (p *GrimoirePanel) createSummary() *unison.Panel {
+	columns := 2
+	if p.editable {
+		columns = 5
+	}
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  columns,
+		HSpacing: 4,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  p.columns(),
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	magery := p.entity.Magery()
+	panel.AddChild(NewPageLabel(fmt.Sprintf(i18n.Text("Magery %d, Thaumatology %s, %s per gathering roll"), magery,
+		p.entity.ThaumatologyLevel().Floor().String(), gurps.RitualGatheringTime(magery))))
+	gathered := NewPageLabel(fmt.Sprintf(i18n.Text("Energy Gathered: %d"), p.entity.Grimoire.Gathered))
+	gathered.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.End,
+		HGrab:  true,
+	})
+	panel.AddChild(gathered)
+	if p.editable {
+		addButton := unison.NewSVGButton(svg.CircledAdd)
+		addButton.Tooltip = newWrappedTooltip(i18n.Text("Build a new ritual"))
+		addButton.ClickCallback = func() {
+			r := &gurps.Ritual{Name: i18n.Text("Ritual")}
+			if ShowRitualBuilder(r) {
+				editGrimoire(p, p.entity, i18n.Text("Add Ritual"), func() {
+					p.entity.Grimoire.Rituals = append(p.entity.Grimoire.Rituals, r)
+				})
+			}
+		}
+		panel.AddChild(addButton)
+		resetButton := unison.NewSVGButton(svg.Reset)
+		resetButton.Tooltip = newWrappedTooltip(i18n.Text("Clear the energy gathered"))
+		resetButton.SetEnabled(p.entity.Grimoire.Gathered != 0)
+		resetButton.ClickCallback = func() {
+			editGrimoire(p, p.entity, i18n.Text("Clear Energy"), func() { p.entity.Grimoire.Gathered = 0 })
+		}
+		panel.AddChild(resetButton)
+		pathsButton := unison.NewSVGButton(svg.GCSSkills)
+		pathsButton.Tooltip = newWrappedTooltip(i18n.Text("Add the path skills used by these rituals that are missing from the sheet"))
+		pathsButton.SetEnabled(len(p.entity.MissingRitualPaths()) != 0)
+		pathsButton.ClickCallback = p.addMissingPathSkills
+		panel.AddChild(pathsButton)
+	}
+	return panel
+}
+
+func (p *GrimoirePanel) createRitualButtons(r *gurps.Ritual) *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: 4,
+	})
+	gatherButton := unison.NewSVGButton(svg.Randomize)
+	gatherButton.Tooltip = newWrappedTooltip(i18n.Text("Roll to gather energy for this ritual"))
+	gatherButton.ClickCallback = func() { p.gatherEnergy(r) }
+	panel.AddChild(gatherButton)
+	editButton := unison.NewSVGButton(svg.Edit)
+	editButton.Tooltip = newWrappedTooltip(i18n.Text("Edit this ritual"))
+	editButton.ClickCallback = func() {
+		edited := r.Clone()
+		if ShowRitualBuilder(edited) {
+			editGrimoire(p, p.entity, i18n.Text("Edit Ritual"), func() { *r = *edited })
+		}
+	}
+	panel.AddChild(editButton)
+	removeButton := unison.NewSVGButton(svg.Trash)
+	removeButton.Tooltip = newWrappedTooltip(i18n.Text("Remove this ritual"))
+	removeButton.ClickCallback = func() {
+		editGrimoire(p, p.entity, i18n.Text("Remove Ritual"), func() {
+			if i := slices.Index(p.entity.Grimoire.Rituals, r); i != -1 {
+				p.entity.Grimoire.Rituals = slices.Delete(p.entity.Grimoire.Rituals, i, i+1)
+			}
+		})
+	}
+	panel.AddChild(removeButton)
+	return panel
+}
+
+func (p *GrimoirePanel) gatherEnergy(r *gurps.Ritual) {
+	result := dice.RollSuccess(nil, rollerNameFor(p.entity),
+		fmt.Sprintf(i18n.Text("Gather energy for %s"), r.Name), fxp.AsInteger[int](p.entity.RitualLevel(r).Floor()))
+	var gathered int
+	editGrimoire(p, p.entity, i18n.Text("Gather Energy"), func() {
+		gathered = p.entity.GatherRitualEnergy(result.Success, result.Critical)
+	})
+	switch {
+	case result.Success:
+		result.What += fmt.Sprintf(i18n.Text(" (gathered %d, now %d of %d)"), gathered, p.entity.Grimoire.Gathered,
+			r.Energy())
+	case result.Critical:
+		result.What += i18n.Text(" (botched, all gathered energy lost)")
+	}
+	RecordRoll(result)
+}
+
+func (p *GrimoirePanel) addMissingPathSkills() {
+	sheet := unison.AncestorOrSelf[*Sheet](p)
+	if sheet == nil || sheet.Skills == nil {
+		return
+	}
+	missing := p.entity.MissingRitualPaths()
+	skills := make([]*gurps.Skill, 0, len(missing))
+	for _, path := range missing {
+		skills = append(skills, gurps.NewRitualPathSkill(p.entity, path))
+	}
+	InsertItems(sheet, sheet.Skills.Table, p.entity.SkillList, p.entity.SetSkillList,
+		func(_ *unison.Table[*Node[*gurps.Skill]]) []*Node[*gurps.Skill] {
+			return sheet.Skills.provider.RootRows()
+		}, skills...)
+}
+
+// Sync the panel to the current data.
+func (p *GrimoirePanel) Sync() {
+	if p.entity.Grimoire.Hash() != p.hash {
+		p.rebuild()
+	}
+	MarkForLayoutWithinDockable(p)
+}
+
+// OverheadHeight implements pageHelper.
+func (p *GrimoirePanel) OverheadHeight() float32 {
+	return 0
+}
+
+// RowHeights implements pageHelper. The grimoire is always kept together, so it is reported as a single row.
+func (p *GrimoirePanel) RowHeights() []float32 {
+	return []float32{p.FrameRect().Height}
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *GrimoirePanel) CurrentDrawRowRange() (start, endBefore int) {
+	return p.drawStart, p.drawEnd
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *GrimoirePanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+}
+
+func editGrimoire(src unison.Paneler, entity *gurps.Entity, name string, edit func()) {
+	owner := unison.AncestorOrSelf[Rebuildable](src)
+	before := entity.Grimoire.Clone()
+	edit()
+	after := entity.Grimoire.Clone()
+	if mgr := unison.UndoManagerFor(src); mgr != nil && owner != nil {
+		addUndo(mgr, &unison.UndoEdit[gurps.Grimoire]{
+			ID:       unison.NextUndoID(),
+			EditName: name,
+			UndoFunc: func(e *unison.UndoEdit[gurps.Grimoire]) {
+				entity.SetGrimoire(e.BeforeData)
+				owner.Rebuild(true)
+			},
+			RedoFunc: func(e *unison.UndoEdit[gurps.Grimoire]) {
+				entity.SetGrimoire(e.AfterData)
+				owner.Rebuild(true)
+			},
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	if owner != nil {
+		owner.Rebuild(true)
+	}
+}
+
+// ShowRitualBuilder shows a dialog for building a ritual from its effects and modifiers, updating the ritual in place.
+// Returns true if the user accepted the changes.
+func ShowRitualBuilder(r *gurps.Ritual) bool {
+	working := r.Clone()
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	nameLabel := i18n.Text("Name")
+	content.AddChild(NewFieldLeadingLabel(nameLabel, false))
+	nameField := NewStringField(nil, "", nameLabel, func() string { return working.Name },
+		func(s string) { working.Name = s })
+	nameField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	content.AddChild(nameField)
+
+	notesLabel := i18n.Text("Notes")
+	content.AddChild(NewFieldLeadingLabel(notesLabel, false))
+	content.AddChild(NewMultiLineStringField(nil, "", notesLabel, func() string { return working.Notes },
+		func(s string) { working.Notes = s }))
+
+	totalLabel := unison.NewLabel()
+	update := func() {
+		totalLabel.SetTitle(working.EnergyDescription())
+		totalLabel.MarkForLayoutAndRedraw()
+		MarkRootAncestorForLayoutRecursively(totalLabel)
+	}
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Effects"), false))
+	effects := newRitualListPanel()
+	addEffect := func(effect *gurps.RitualEffect) {
+		row := newRitualRowPanel(4)
+		effectPopup := unison.NewPopupMenu[rpm.Effect]()
+		for _, one := range rpm.Effects {
+			effectPopup.AddItem(one)
+		}
+		effectPopup.Select(effect.Effect)
+		effectPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[rpm.Effect]) {
+			if item, ok := popup.Selected(); ok {
+				effect.Effect = item
+				update()
+			}
+		}
+		row.AddChild(effectPopup)
+		pathPopup := unison.NewPopupMenu[rpm.Path]()
+		for _, one := range rpm.Paths {
+			pathPopup.AddItem(one)
+		}
+		pathPopup.Select(effect.Path)
+		pathPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[rpm.Path]) {
+			if item, ok := popup.Selected(); ok {
+				effect.Path = item
+				update()
+			}
+		}
+		row.AddChild(pathPopup)
+		greater := unison.NewCheckBox()
+		greater.SetTitle(i18n.Text("Greater"))
+		greater.State = check.FromBool(effect.Greater)
+		greater.ClickCallback = func() {
+			effect.Greater = greater.State == check.On
+			update()
+		}
+		row.AddChild(greater)
+		row.AddChild(newRitualRemoveButton(i18n.Text("Remove this effect"), func() {
+			if i := slices.Index(working.Effects, effect); i != -1 {
+				working.Effects = slices.Delete(working.Effects, i, i+1)
+			}
+			row.RemoveFromParent()
+			update()
+		}))
+		effects.AddChild(row)
+	}
+	for _, one := range working.Effects {
+		addEffect(one)
+	}
+	content.AddChild(newRitualAddButton(i18n.Text("Add an effect"), func() {
+		effect := &gurps.RitualEffect{}
+		working.Effects = append(working.Effects, effect)
+		addEffect(effect)
+		update()
+	}))
+	content.AddChild(unison.NewPanel())
+	content.AddChild(effects)
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Modifiers"), false))
+	modifiers := newRitualListPanel()
+	addModifier := func(modifier *gurps.RitualModifier) {
+		row := newRitualRowPanel(3)
+		modifierNameLabel := i18n.Text("Modifier")
+		modifierName := NewStringField(nil, "", modifierNameLabel, func() string { return modifier.Name },
+			func(s string) {
+				modifier.Name = s
+				update()
+			})
+		modifierName.Watermark = modifierNameLabel
+		modifierName.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		row.AddChild(modifierName)
+		row.AddChild(NewIntegerField(nil, "", i18n.Text("Energy"), func() int { return modifier.Energy },
+			func(v int) {
+				modifier.Energy = v
+				update()
+			}, -9999, 9999, true, false))
+		row.AddChild(newRitualRemoveButton(i18n.Text("Remove this modifier"), func() {
+			if i := slices.Index(working.Modifiers, modifier); i != -1 {
+				working.Modifiers = slices.Delete(working.Modifiers, i, i+1)
+			}
+			row.RemoveFromParent()
+			update()
+		}))
+		modifiers.AddChild(row)
+	}
+	for _, one := range working.Modifiers {
+		addModifier(one)
+	}
+	content.AddChild(newRitualAddButton(i18n.Text("Add a modifier"), func() {
+		modifier := &gurps.RitualModifier{}
+		working.Modifiers = append(working.Modifiers, modifier)
+		addModifier(modifier)
+		update()
+	}))
+	content.AddChild(unison.NewPanel())
+	content.AddChild(modifiers)
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Energy"), false))
+	content.AddChild(totalLabel)
+	update()
+
+	icon := &unison.DrawableSVG{
+		SVG:  svg.GCSSpells,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return false
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return false
+	}
+	if working.Name = strings.TrimSpace(working.Name); working.Name == "" {
+		working.Name = i18n.Text("Ritual")
+	}
+	*r = *working
+	return true
+}
+
+func newRitualListPanel() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	return panel
+}
+
+func newRitualRowPanel(columns int) *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  columns,
+		HSpacing: unison.StdHSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	return panel
+}
+
+func newRitualAddButton(tooltip string, add func()) *unison.Button {
+	b := unison.NewSVGButton(svg.CircledAdd)
+	b.Tooltip = newWrappedTooltip(tooltip)
+	b.ClickCallback = add
+	return b
+}
+
+func newRitualRemoveButton(tooltip string, remove func()) *unison.Button {
+	b := unison.NewSVGButton(svg.Trash)
+	b.Tooltip = newWrappedTooltip(tooltip)
+	b.ClickCallback = remove
+	return b
+}
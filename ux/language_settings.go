@@ -0,0 +1,294 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/translation"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/toolbox/v2/xio"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+)
+
+const (
+	languageSettingsMaxRows     = 200
+	languageSettingsKeyMaxWidth = 60
+)
+
+var translations = translation.NewCatalog()
+
+type localeChoice string
+
+func (c localeChoice) String() string {
+	if c == "" {
+		return fmt.Sprintf(i18n.Text("System Default (%s)"), i18n.Locale())
+	}
+	return string(c)
+}
+
+type languageSettingsDockable struct {
+	SettingsDockable
+	content     *unison.Panel
+	rows        *unison.Panel
+	localePopup *unison.PopupMenu[localeChoice]
+	searchField *unison.Field
+	statusLabel *unison.Label
+	language    string
+}
+
+// ShowLanguageSettings shows the Language settings.
+func ShowLanguageSettings() {
+	if Activate(func(d unison.Dockable) bool {
+		_, ok := d.AsPanel().Self.(*languageSettingsDockable)
+		return ok
+	}) {
+		return
+	}
+	d := &languageSettingsDockable{language: defaultTranslationLanguage()}
+	d.Self = d
+	d.TabTitle = i18n.Text("Languages")
+	d.TabIcon = svg.Settings
+	d.Extensions = []string{i18n.Extension}
+	d.ExportFileName = func() string { return d.language }
+	d.Loader = d.load
+	d.Saver = d.save
+	d.Resetter = d.reset
+	d.Setup(d.addToStartToolbar, nil, d.initContent)
+}
+
+func (d *languageSettingsDockable) addToStartToolbar(toolbar *unison.Panel) {
+	d.searchField = NewSearchField(i18n.Text("Search original and translated text"),
+		func(_, _ *unison.FieldState) { d.rebuild() })
+	toolbar.AddChild(d.searchField)
+	d.statusLabel = unison.NewLabel()
+	d.statusLabel.SetLayoutData(&unison.FlexLayoutData{VAlign: align.Middle})
+	d.statusLabel.Tooltip = newWrappedTooltip(i18n.Text("Text appears here once GCS has displayed it, or when a translation already provides it. Leave a translation blank to use the one that comes with GCS."))
+	toolbar.AddChild(d.statusLabel)
+}
+
+func (d *languageSettingsDockable) initContent(content *unison.Panel) {
+	d.content = content
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.createLocalePopup()
+	d.createLanguageField()
+	d.rows = unison.NewPanel()
+	d.rows.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.rows.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	d.content.AddChild(d.rows)
+	d.rebuild()
+}
+
+func (d *languageSettingsDockable) createLocalePopup() {
+	d.content.AddChild(NewFieldLeadingLabel(i18n.Text("Interface Locale"), false))
+	d.localePopup = unison.NewPopupMenu[localeChoice]()
+	d.fillLocalePopup()
+	d.localePopup.Tooltip = newWrappedTooltip(i18n.Text(`The locale to use when presenting text in the user interface. Windows and menus created from now on use it right away. Those that are already open keep their current text until GCS is restarted.`))
+	d.localePopup.SelectionChangedCallback = func(p *unison.PopupMenu[localeChoice]) {
+		if item, ok := p.Selected(); ok && string(item) != languageSetting {
+			setLanguageSetting(string(item))
+		}
+	}
+	d.content.AddChild(d.localePopup)
+}
+
+func (d *languageSettingsDockable) fillLocalePopup() {
+	d.localePopup.RemoveAllItems()
+	d.localePopup.AddItem("")
+	languages := translations.Languages()
+	if languageSetting != "" && !slices.Contains(languages, languageSetting) {
+		languages = append(languages, languageSetting)
+	}
+	for _, one := range languages {
+		d.localePopup.AddItem(localeChoice(one))
+	}
+	d.localePopup.Select(localeChoice(languageSetting))
+}
+
+func (d *languageSettingsDockable) createLanguageField() {
+	title := i18n.Text("Translating")
+	d.content.AddChild(NewFieldLeadingLabel(title, false))
+	field := NewStringField(nil, "", title,
+		func() string { return d.language },
+		func(s string) {
+			if s = translation.Code(strings.TrimSpace(s)); s != "" && s != d.language {
+				d.language = s
+				d.rebuild()
+			}
+		})
+	field.Tooltip = newWrappedTooltip(i18n.Text(`The language whose translations are being edited, such as "de" or "pt_br". Enter a new one to start translating into a language GCS doesn't provide yet.`))
+	field.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	d.content.AddChild(field)
+}
+
+func (d *languageSettingsDockable) rebuild() {
+	d.rows.RemoveAllChildren()
+	filter := strings.ToLower(strings.TrimSpace(d.searchField.Text()))
+	count := 0
+	for _, key := range translations.Keys() {
+		shipped := translations.Shipped(d.language, key)
+		override, _ := translations.Override(d.language, key)
+		if filter != "" && !strings.Contains(strings.ToLower(key), filter) &&
+			!strings.Contains(strings.ToLower(shipped), filter) && !strings.Contains(strings.ToLower(override), filter) {
+			continue
+		}
+		count++
+		if count <= languageSettingsMaxRows {
+			d.createRow(key, shipped)
+		}
+	}
+	if count > languageSettingsMaxRows {
+		d.statusLabel.SetTitle(fmt.Sprintf(i18n.Text("Showing the first %d of %d strings"), languageSettingsMaxRows,
+			count))
+	} else {
+		d.statusLabel.SetTitle(fmt.Sprintf(i18n.Text("%d strings"), count))
+	}
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *languageSettingsDockable) createRow(key, shipped string) {
+	label := NewFieldLeadingLabel(xstrings.Truncate(strings.ReplaceAll(key, "\n", " "), languageSettingsKeyMaxWidth,
+		true), false)
+	label.Tooltip = newWrappedTooltip(key)
+	d.rows.AddChild(label)
+	get := func() string {
+		s, _ := translations.Override(d.language, key)
+		return s
+	}
+	set := func(s string) {
+		translations.SetOverride(d.language, key, s)
+		d.saveOverrides()
+	}
+	var field *StringField
+	if strings.Contains(key, "\n") {
+		field = NewMultiLineStringField(nil, "", key, get, set)
+	} else {
+		field = NewStringField(nil, "", key, get, set)
+	}
+	if shipped != "" {
+		field.Watermark = shipped
+	} else {
+		field.Watermark = key
+	}
+	field.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	d.rows.AddChild(field)
+}
+
+func (d *languageSettingsDockable) saveOverrides() {
+	dir := translationOverridesDir()
+	if err := translations.SaveOverrides(dir, d.language); err != nil {
+		errs.Log(err, "dir", dir, "language", d.language)
+	}
+}
+
+func (d *languageSettingsDockable) reset() {
+	for key := range translations.Overrides(d.language) {
+		translations.SetOverride(d.language, key, "")
+	}
+	d.saveOverrides()
+	d.rebuild()
+}
+
+func (d *languageSettingsDockable) load(fileSystem fs.FS, filePath string) error {
+	f, err := fileSystem.Open(filePath)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer xio.CloseIgnoringErrors(f)
+	var t translation.Table
+	if t, err = translation.Read(f); err != nil {
+		return err
+	}
+	for key, value := range t {
+		if value != translations.Shipped(d.language, key) {
+			translations.SetOverride(d.language, key, value)
+		}
+	}
+	d.saveOverrides()
+	d.fillLocalePopup()
+	d.rebuild()
+	return nil
+}
+
+func (d *languageSettingsDockable) save(filePath string) error {
+	return translations.Merged(d.language).WriteFile(filePath)
+}
+
+// defaultTranslationLanguage returns the most specific form of the current language for which translations exist, or
+// the least specific form if there are none.
+func defaultTranslationLanguage() string {
+	hierarchy := translation.Hierarchy(i18n.Language)
+	if len(hierarchy) == 0 {
+		return "en"
+	}
+	known := translations.Languages()
+	for _, code := range hierarchy {
+		if slices.Contains(known, code) {
+			return code
+		}
+	}
+	return hierarchy[len(hierarchy)-1]
+}
+
+// installTranslations loads the translations that come with GCS along with any changes the user has made to them and
+// makes them the source of all translated text.
+func installTranslations() {
+	if i18n.Dir == "" {
+		if exePath, err := os.Executable(); err == nil {
+			if exePath, err = filepath.EvalSymlinks(exePath); err == nil {
+				i18n.Dir = xfilepath.TrimExtension(exePath) + "_i18n"
+			}
+		}
+	}
+	if i18n.Dir != "" {
+		if err := translations.LoadDir(i18n.Dir, false); err != nil {
+			errs.Log(err, "dir", i18n.Dir)
+		}
+	}
+	if dir := translationOverridesDir(); dir != i18n.Dir {
+		if err := translations.LoadDir(dir, true); err != nil {
+			errs.Log(err, "dir", dir)
+		}
+	}
+	i18n.SetLocalizer(translations.Text)
+}
+
+func translationOverridesDir() string {
+	return filepath.Join(xos.AppDataDir(true), xos.AppCmdName+"_i18n")
+}
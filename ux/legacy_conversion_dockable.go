@@ -0,0 +1,277 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/merge"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+// legacyConversionMaxRows is the maximum number of dropped or altered values shown for a single file.
+const legacyConversionMaxRows = 200
+
+var (
+	_ unison.Dockable  = &LegacyConversionDockable{}
+	_ unison.TabCloser = &LegacyConversionDockable{}
+)
+
+// LegacyConversionDockable converts the library and sheet files within a directory that were written by older versions
+// to the current data format, then reports the values that were dropped or altered in each.
+type LegacyConversionDockable struct {
+	unison.Panel
+	statusLabel *unison.Label
+	content     *unison.Panel
+	dir         string
+	results     []*gurps.LegacyConversion
+	converting  bool
+}
+
+// ShowLegacyConversion asks for a directory, then converts the files within it that were written by older versions to
+// the current data format and shows a report of the results.
+func ShowLegacyConversion() {
+	var d *LegacyConversionDockable
+	Activate(func(dockable unison.Dockable) bool {
+		var ok bool
+		d, ok = dockable.AsPanel().Self.(*LegacyConversionDockable)
+		return ok
+	})
+	if d == nil {
+		d = &LegacyConversionDockable{}
+		d.Self = d
+		d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+		d.content = unison.NewPanel()
+		d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+		d.content.SetLayout(&unison.FlexLayout{
+			Columns:  1,
+			VSpacing: unison.StdVSpacing,
+		})
+
+		scroll := unison.NewScrollPanel()
+		scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+		scroll.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			VAlign: align.Fill,
+			HGrab:  true,
+			VGrab:  true,
+		})
+
+		d.AddChild(d.createToolbar())
+		d.AddChild(scroll)
+		PlaceInDock(d, dgroup.Editors, false)
+		d.rebuild()
+	}
+	d.chooseDirectory()
+}
+
+func (d *LegacyConversionDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	chooseButton := unison.NewSVGButton(svg.OpenFolder)
+	chooseButton.Tooltip = newWrappedTooltip(i18n.Text("Choose a folder to convert"))
+	chooseButton.ClickCallback = d.chooseDirectory
+	toolbar.AddChild(chooseButton)
+	d.statusLabel = unison.NewLabel()
+	toolbar.AddChild(d.statusLabel)
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *LegacyConversionDockable) chooseDirectory() {
+	if d.converting {
+		return
+	}
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetCanChooseDirectories(true)
+	dialog.SetCanChooseFiles(false)
+	dir := d.dir
+	if !xos.IsDir(dir) {
+		dir = gurps.GlobalSettings().LastDir(gurps.DefaultLastDirKey)
+	}
+	dialog.SetInitialDirectory(dir)
+	if !dialog.RunModal() {
+		return
+	}
+	if unison.QuestionDialog(fmt.Sprintf(i18n.Text("Convert the files in %s?"), dialog.Path()),
+		i18n.Text(`Library and sheet files written by older versions will be rewritten in the current format, which older
+versions may not be able to read. This cannot be undone.`)) != unison.ModalResponseOK {
+		return
+	}
+	d.convert(dialog.Path())
+}
+
+func (d *LegacyConversionDockable) convert(dir string) {
+	d.dir = dir
+	d.results = nil
+	d.converting = true
+	d.rebuild()
+	go func() {
+		results, err := gurps.ConvertLegacyFiles(dir)
+		unison.InvokeTask(func() {
+			d.converting = false
+			d.results = results
+			d.rebuild()
+			if err != nil {
+				Workspace.ErrorHandler(i18n.Text("Unable to convert the files"), err)
+			}
+			if len(results) != 0 {
+				Workspace.Navigator.EventuallyReload()
+				updateLibrarySearchIndex(false)
+			}
+		})
+	}()
+}
+
+func (d *LegacyConversionDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	switch {
+	case d.converting:
+		d.setStatus(fmt.Sprintf(i18n.Text("Converting the files in %s…"), d.dir))
+	case d.dir == "":
+		d.setStatus(i18n.Text("No folder has been converted"))
+	default:
+		failed := 0
+		for _, result := range d.results {
+			if result.Err != nil {
+				failed++
+			}
+		}
+		d.setStatus(fmt.Sprintf(i18n.Text("Converted %d of %d older files in %s"), len(d.results)-failed,
+			len(d.results), d.dir))
+		for _, result := range d.results {
+			d.content.AddChild(d.createResultPanel(result, len(d.content.Children()) != 0))
+		}
+	}
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *LegacyConversionDockable) setStatus(text string) {
+	d.statusLabel.SetTitle(text)
+	d.statusLabel.Parent().MarkForLayoutAndRedraw()
+}
+
+func (d *LegacyConversionDockable) createResultPanel(result *gurps.LegacyConversion, addTopMargin bool) *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	if addTopMargin {
+		panel.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+	}
+
+	name := result.Path
+	if rel, err := filepath.Rel(d.dir, result.Path); err == nil {
+		name = rel
+	}
+	header := unison.NewLabel()
+	header.Font = sheetHistoryBoldFont()
+	header.SetTitle(fmt.Sprintf(i18n.Text("%s (data version %d)"), name, result.Version))
+	header.Tooltip = newWrappedTooltip(result.Path)
+	header.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+	panel.AddChild(header)
+
+	summary := unison.NewLabel()
+	summary.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: unison.StdHSpacing * 2}))
+	summary.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+	panel.AddChild(summary)
+	switch {
+	case result.Err != nil:
+		summary.SetTitle(fmt.Sprintf(i18n.Text("Not converted: %s"), result.Err.Error()))
+		return panel
+	case len(result.Dropped) == 0 && len(result.Altered) == 0:
+		summary.SetTitle(i18n.Text("Converted without dropping or altering anything"))
+		return panel
+	default:
+		summary.SetTitle(fmt.Sprintf(i18n.Text("Converted, dropping %d values and altering %d"), len(result.Dropped),
+			len(result.Altered)))
+	}
+
+	shown := 0
+	for _, list := range [][]*merge.Change{result.Dropped, result.Altered} {
+		for _, change := range list {
+			if shown == legacyConversionMaxRows {
+				more := unison.NewLabel()
+				more.SetTitle(fmt.Sprintf(i18n.Text("…and %d more"),
+					len(result.Dropped)+len(result.Altered)-legacyConversionMaxRows))
+				more.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: unison.StdHSpacing * 2}))
+				more.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+				panel.AddChild(more)
+				return panel
+			}
+			shown++
+			where := unison.NewLabel()
+			where.SetTitle(strings.Join(change.Path, " › "))
+			where.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: unison.StdHSpacing * 2}))
+			panel.AddChild(where)
+			panel.AddChild(newChangeLogValueLabel(change.Before, i18n.Text("(none)")))
+			panel.AddChild(newChangeLogValueLabel(change.After, i18n.Text("(dropped)")))
+		}
+	}
+	return panel
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *LegacyConversionDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.MagicWand,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *LegacyConversionDockable) Title() string {
+	return i18n.Text("Legacy File Conversion")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *LegacyConversionDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *LegacyConversionDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *LegacyConversionDockable) MayAttemptClose() bool {
+	return !d.converting
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *LegacyConversionDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+var (
+	_ unison.Dockable  = &libraryDiffDockable{}
+	_ unison.TabCloser = &libraryDiffDockable{}
+)
+
+type libraryDiffSheet struct {
+	sheet    *Sheet
+	checkbox *unison.CheckBox
+}
+
+type libraryDiffDockable struct {
+	unison.Panel
+	staged *gurps.StagedRelease
+	diff   *gurps.LibraryDiff
+	sheets []*libraryDiffSheet
+	done   bool
+}
+
+func newLibraryDiffDockable(staged *gurps.StagedRelease, diff *gurps.LibraryDiff) *libraryDiffDockable {
+	d := &libraryDiffDockable{
+		staged: staged,
+		diff:   diff,
+	}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	content := unison.NewPanel()
+	content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	lib := staged.Library()
+	content.AddChild(d.createHeader(fmt.Sprintf(i18n.Text("Updating %s to %s"), lib.Title,
+		filterVersion(staged.Version())), 0))
+	d.addSection(content, i18n.Text("Added"), diff.Added)
+	d.addSection(content, i18n.Text("Removed"), diff.Removed)
+	d.addSection(content, i18n.Text("Changed"), diff.Changed)
+	d.addSheets(content)
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(content, behavior.HintedFill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	return d
+}
+
+func (d *libraryDiffDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	applyButton := unison.NewButton()
+	applyButton.SetTitle(i18n.Text("Apply Update"))
+	applyButton.Tooltip = newWrappedTooltip(i18n.Text(`Replaces the library's content with the new version, then syncs
+the checked sheets with the changed items`))
+	applyButton.ClickCallback = d.apply
+	toolbar.AddChild(applyButton)
+	cancelButton := unison.NewButton()
+	cancelButton.SetTitle(i18n.Text("Cancel"))
+	cancelButton.Tooltip = newWrappedTooltip(i18n.Text("Discards the new version, leaving the library as it was"))
+	cancelButton.ClickCallback = func() { d.AttemptClose() }
+	toolbar.AddChild(cancelButton)
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	return toolbar
+}
+
+func (d *libraryDiffDockable) createHeader(text string, topMargin float32) *unison.Label {
+	label := unison.NewLabel()
+	label.Font = &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			desc := unison.DefaultLabelTheme.Font.Descriptor()
+			desc.Weight = weight.Bold
+			return desc
+		},
+	}
+	label.SetTitle(text)
+	if topMargin > 0 {
+		label.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: topMargin}))
+	}
+	return label
+}
+
+func (d *libraryDiffDockable) addSection(content *unison.Panel, title string, items []*gurps.LibraryItem) {
+	content.AddChild(d.createHeader(fmt.Sprintf(i18n.Text("%s (%d)"), title, len(items)), unison.StdVSpacing*2))
+	for _, item := range items {
+		content.AddChild(d.createItemLabel(item))
+	}
+}
+
+func (d *libraryDiffDockable) createItemLabel(item *gurps.LibraryItem) *unison.Label {
+	label := unison.NewLabel()
+	label.SetTitle(fmt.Sprintf("%s — %s", item.Name, item.Path))
+	label.Tooltip = newWrappedTooltip(string(item.ID))
+	label.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: unison.StdHSpacing * 2}))
+	return label
+}
+
+func (d *libraryDiffDockable) addSheets(content *unison.Panel) {
+	libKey := d.staged.Library().Key()
+	for _, one := range AllDockables() {
+		sheet, ok := one.(*Sheet)
+		if !ok {
+			continue
+		}
+		refs := d.diff.ReferencedBy(libKey, sheet.Entity())
+		if len(refs) == 0 {
+			continue
+		}
+		if len(d.sheets) == 0 {
+			content.AddChild(d.createHeader(i18n.Text("Open sheets that use changed or removed items"),
+				unison.StdVSpacing*2))
+		}
+		checkbox := unison.NewCheckBox()
+		checkbox.SetTitle(fmt.Sprintf(i18n.Text("Sync %s (%d items)"), sheet.Title(), len(refs)))
+		checkbox.State = check.On
+		content.AddChild(checkbox)
+		for _, ref := range refs {
+			content.AddChild(d.createItemLabel(ref))
+		}
+		d.sheets = append(d.sheets, &libraryDiffSheet{
+			sheet:    sheet,
+			checkbox: checkbox,
+		})
+	}
+}
+
+func (d *libraryDiffDockable) apply() {
+	d.done = true
+	if installStagedLibrary(d.staged) {
+		libKey := d.staged.Library().Key()
+		for _, one := range d.sheets {
+			if one.checkbox.State == check.On {
+				one.sheet.updateTables(syncWithSourceAction.Title,
+					func() { d.diff.SyncReferences(libKey, one.sheet.Entity()) })
+			}
+		}
+	}
+	d.AttemptClose()
+}
+
+func (d *libraryDiffDockable) Title() string {
+	return fmt.Sprintf(i18n.Text("Update %s"), d.staged.Library().Title)
+}
+
+func (d *libraryDiffDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Download,
+		Size: suggestedSize,
+	}
+}
+
+func (d *libraryDiffDockable) Tooltip() string {
+	return ""
+}
+
+func (d *libraryDiffDockable) Modified() bool {
+	return false
+}
+
+func (d *libraryDiffDockable) MayAttemptClose() bool {
+	return true
+}
+
+func (d *libraryDiffDockable) AttemptClose() bool {
+	if !d.done {
+		d.done = true
+		d.staged.Discard()
+		Workspace.Navigator.EventuallyReload()
+	}
+	return AttemptCloseForDockable(d)
+}
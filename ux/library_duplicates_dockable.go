@@ -0,0 +1,249 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+var (
+	_ unison.Dockable  = &LibraryDuplicatesDockable{}
+	_ unison.TabCloser = &LibraryDuplicatesDockable{}
+)
+
+// sourceRetargeter defines the methods a dockable must provide for its library references to be updated when library
+// duplicates are merged.
+type sourceRetargeter interface {
+	unison.Dockable
+	retargetSources(replacements map[gurps.Source]gurps.Source)
+}
+
+// LibraryDuplicatesDockable lists the items in the libraries that appear to be duplicates of each other and allows them
+// to be merged.
+type LibraryDuplicatesDockable struct {
+	unison.Panel
+	statusLabel *unison.Label
+	content     *unison.Panel
+	groups      []*gurps.DuplicateGroup
+	scanning    bool
+}
+
+// ShowLibraryDuplicates shows the library duplicates finder.
+func ShowLibraryDuplicates() {
+	if Activate(func(d unison.Dockable) bool {
+		_, ok := d.AsPanel().Self.(*LibraryDuplicatesDockable)
+		return ok
+	}) {
+		return
+	}
+	d := &LibraryDuplicatesDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	PlaceInDock(d, dgroup.Editors, false)
+	d.scan()
+}
+
+func (d *LibraryDuplicatesDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	refreshButton := unison.NewSVGButton(svg.Reset)
+	refreshButton.Tooltip = newWrappedTooltip(i18n.Text("Scan the libraries again"))
+	refreshButton.ClickCallback = d.scan
+	toolbar.AddChild(refreshButton)
+	d.statusLabel = unison.NewLabel()
+	toolbar.AddChild(d.statusLabel)
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *LibraryDuplicatesDockable) scan() {
+	if d.scanning {
+		return
+	}
+	d.scanning = true
+	d.rebuild()
+	libs := gurps.GlobalSettings().Libraries().List()
+	go func() {
+		groups := gurps.FindLibraryDuplicates(libs)
+		unison.InvokeTask(func() {
+			d.scanning = false
+			d.groups = groups
+			d.rebuild()
+		})
+	}()
+}
+
+func (d *LibraryDuplicatesDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	switch {
+	case d.scanning:
+		d.setStatus(i18n.Text("Scanning libraries…"))
+	case len(d.groups) == 1:
+		d.setStatus(i18n.Text("1 set of possible duplicates"))
+	default:
+		d.setStatus(fmt.Sprintf(i18n.Text("%d sets of possible duplicates"), len(d.groups)))
+	}
+	if !d.scanning {
+		for _, group := range d.groups {
+			d.content.AddChild(d.createGroupPanel(group, len(d.content.Children()) != 0))
+		}
+	}
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *LibraryDuplicatesDockable) setStatus(text string) {
+	d.statusLabel.SetTitle(text)
+	d.statusLabel.Parent().MarkForLayoutAndRedraw()
+}
+
+func (d *LibraryDuplicatesDockable) createGroupPanel(group *gurps.DuplicateGroup, addTopMargin bool) *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	if addTopMargin {
+		panel.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+	}
+
+	header := unison.NewLabel()
+	header.Font = &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			desc := unison.DefaultLabelTheme.Font.Descriptor()
+			desc.Weight = weight.Bold
+			return desc
+		},
+	}
+	title := fmt.Sprintf(i18n.Text("%s: %s"), group.Kind, group.Name)
+	if !group.Exact {
+		title += i18n.Text(" (similar names)")
+	}
+	header.SetTitle(title)
+	panel.AddChild(header)
+
+	radioGroup := unison.NewGroup()
+	var keep *gurps.DuplicateItem
+	for i, item := range group.Items {
+		rb := unison.NewRadioButton()
+		rb.SetTitle(fmt.Sprintf(i18n.Text("%s — %s: %s"), item.Name, item.Library, item.Path))
+		rb.Tooltip = newWrappedTooltip(item.FilePath)
+		rb.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: unison.StdHSpacing * 2}))
+		rb.ClickCallback = func() { keep = item }
+		radioGroup.Add(rb)
+		if i == 0 {
+			radioGroup.Select(rb)
+			keep = item
+		}
+		panel.AddChild(rb)
+	}
+
+	button := unison.NewButton()
+	button.SetTitle(i18n.Text("Merge into Selected"))
+	button.Tooltip = newWrappedTooltip(i18n.Text(`Removes the other items from their libraries and updates any open sheets,
+templates, and loot sheets that refer to them to refer to the selected item instead.`))
+	button.SetLayoutData(&unison.FlexLayoutData{HAlign: align.Start})
+	button.ClickCallback = func() { d.merge(group, keep) }
+	panel.AddChild(button)
+	return panel
+}
+
+func (d *LibraryDuplicatesDockable) merge(group *gurps.DuplicateGroup, keep *gurps.DuplicateItem) {
+	if unison.QuestionDialog(fmt.Sprintf(i18n.Text("Merge %d items into %s?"), len(group.Items), keep.Name),
+		i18n.Text("The other items will be removed from their library files. This cannot be undone.")) !=
+		unison.ModalResponseOK {
+		return
+	}
+	replacements, err := group.Merge(keep)
+	if len(replacements) != 0 {
+		for _, one := range AllDockables() {
+			if retargeter, ok := one.(sourceRetargeter); ok {
+				retargeter.retargetSources(replacements)
+			}
+		}
+	}
+	Workspace.Navigator.EventuallyReload()
+	updateLibrarySearchIndex(false)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to merge library items"), err)
+	}
+	d.scan()
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *LibraryDuplicatesDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Copy,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *LibraryDuplicatesDockable) Title() string {
+	return i18n.Text("Library Duplicates")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *LibraryDuplicatesDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *LibraryDuplicatesDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *LibraryDuplicatesDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *LibraryDuplicatesDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
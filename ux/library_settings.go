@@ -215,7 +215,7 @@ func (d *librarySettingsDockable) addNote(parent *unison.Panel, note string) {
 	fd.Slant = slant.Italic
 	fd.Size--
 	font := fd.Font()
-	for _, line := range unison.NewTextWrappedLines(note, &unison.TextDecoration{
+	for _, line := range newTextWrappedLines(note, &unison.TextDecoration{
 		Font:            font,
 		OnBackgroundInk: unison.DefaultLabelTheme.OnBackgroundInk,
 	}, 400) {
@@ -13,6 +13,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -89,28 +90,46 @@ documents from the library are open.`))
 	frame = frame.Align()
 	wnd.SetFrameRect(unison.BestDisplayForRect(frame).FitRectOnto(frame))
 	wnd.ToFront()
-	go performLibraryUpdate(wnd, lib, rel, &err)
+	var staged *gurps.StagedRelease
+	go performLibraryUpdate(wnd, lib, rel, &staged, &err)
 	wnd.RunModal()
 	if err != nil {
 		Workspace.ErrorHandler(i18n.Text("Unable to update"), err)
+		Workspace.Navigator.EventuallyReload()
 		return false
 	}
+	diff := gurps.DiffLibraryItems(gurps.IndexLibraryItems(os.DirFS(lib.Path())),
+		gurps.IndexLibraryItems(os.DirFS(staged.Dir())))
+	if diff.Empty() {
+		return installStagedLibrary(staged)
+	}
+	DisplayNewDockable(newLibraryDiffDockable(staged, diff))
 	return true
 }
 
-//nolint:gocritic // We need to return the error, but can't return it directly thanks to using a goroutine
-func performLibraryUpdate(wnd *unison.Window, lib *gurps.Library, rel gurps.Release, err *error) {
-	defer finishLibraryUpdate(wnd, lib)
+//nolint:gocritic // We need to return the results, but can't return them directly thanks to using a goroutine
+func performLibraryUpdate(wnd *unison.Window, lib *gurps.Library, rel gurps.Release, staged **gurps.StagedRelease,
+	err *error) {
+	defer wnd.StopModal(unison.ModalResponseOK)
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 	lib.StopAllWatches()
-	*err = lib.Download(ctx, &http.Client{}, rel)
+	*staged, *err = lib.Stage(ctx, &http.Client{}, rel)
 }
 
-func finishLibraryUpdate(wnd *unison.Window, lib *gurps.Library) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
-	lib.CheckForAvailableUpgrade(ctx, &http.Client{})
+func installStagedLibrary(staged *gurps.StagedRelease) bool {
+	err := staged.Install()
 	Workspace.Navigator.EventuallyReload()
-	wnd.StopModal(unison.ModalResponseOK)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to update"), err)
+		return false
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		staged.Library().CheckForAvailableUpgrade(ctx, &http.Client{})
+		unison.InvokeTask(Workspace.Navigator.EventuallyReload)
+	}()
+	updateLibrarySearchIndex(false)
+	return true
 }
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
@@ -22,6 +23,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
 	"github.com/richardwilkes/toolbox/v2/xfilepath"
 	"github.com/richardwilkes/toolbox/v2/xrand"
 	"github.com/richardwilkes/unison"
@@ -409,6 +411,17 @@ func (l *LootSheet) save(forceSaveAs bool) bool {
 	return success
 }
 
+func (l *LootSheet) autosaver() func(filePath string) error {
+	return l.loot.Save
+}
+
+func (l *LootSheet) markRecovered(filePath string, untitled bool) {
+	l.path = filePath
+	l.needsSaveAsPrompt = untitled
+	l.hash = 0 // Force it to be recognized as unsaved
+	UpdateTitleForDockable(l)
+}
+
 type lootTablesUndoData struct {
 	equipment *TableUndoEditData[*gurps.Equipment]
 	notes     *TableUndoEditData[*gurps.Note]
@@ -426,25 +439,64 @@ func (l *lootTablesUndoData) Apply() {
 	l.notes.Apply()
 }
 
+func (l *LootSheet) countTags(counts map[string]int) {
+	gurps.CountTagsInProvider(counts, l.loot)
+}
+
+func (l *LootSheet) renameTag(from, to string) {
+	l.updateTables(i18n.Text("Rename Tag"), func() { gurps.RenameTagInProvider(from, to, l.loot) })
+}
+
+func (l *LootSheet) retargetSources(replacements map[gurps.Source]gurps.Source) {
+	l.updateTables(i18n.Text("Merge Library Duplicates"), func() { gurps.RetargetSources(l.loot, replacements) })
+}
+
+func (l *LootSheet) searchNodes(hits []*gurps.SearchHit, text string) []*gurps.SearchHit {
+	return gurps.SearchNodesInProvider(hits, l.BackingFilePath(), text, l.loot)
+}
+
+func (l *LootSheet) locateNode(id tid.TID) bool {
+	return locateTableRowByID(l.Equipment.Table, id) || locateTableRowByID(l.Notes.Table, id)
+}
+
 func (l *LootSheet) syncWithAllSources() {
+	l.updateTables(syncWithSourceAction.Title, l.loot.SyncWithLibrarySources)
+}
+
+// removeEquipment removes the equipment from the loot sheet, recording an undo for the change.
+func (l *LootSheet) removeEquipment(editName string, equipment []*gurps.Equipment) {
+	l.updateTables(editName, func() {
+		for _, one := range equipment {
+			remove := func(item *gurps.Equipment) bool { return item == one }
+			if parent := one.Parent(); parent != nil {
+				parent.SetChildren(slices.DeleteFunc(parent.NodeChildren(), remove))
+			} else {
+				l.loot.Equipment = slices.DeleteFunc(l.loot.Equipment, remove)
+			}
+		}
+	})
+}
+
+// updateTables calls update to modify the list data, then refreshes the tables and records an undo for the change.
+func (l *LootSheet) updateTables(editName string, update func()) {
 	var undo *unison.UndoEdit[*lootTablesUndoData]
 	mgr := unison.UndoManagerFor(l)
 	if mgr != nil {
 		undo = &unison.UndoEdit[*lootTablesUndoData]{
 			ID:         unison.NextUndoID(),
-			EditName:   syncWithSourceAction.Title,
+			EditName:   editName,
 			UndoFunc:   func(e *unison.UndoEdit[*lootTablesUndoData]) { e.BeforeData.Apply() },
 			RedoFunc:   func(e *unison.UndoEdit[*lootTablesUndoData]) { e.AfterData.Apply() },
 			AbsorbFunc: func(_ *unison.UndoEdit[*lootTablesUndoData], _ unison.Undoable) bool { return false },
 			BeforeData: newLootTablesUndoData(l),
 		}
 	}
-	l.loot.SyncWithLibrarySources()
+	update()
 	l.Equipment.Table.SyncToModel()
 	l.Notes.Table.SyncToModel()
 	if mgr != nil && undo != nil {
 		undo.AfterData = newLootTablesUndoData(l)
-		mgr.Add(undo)
+		addUndo(mgr, undo)
 	}
 	l.Rebuild(true)
 }
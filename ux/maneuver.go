@@ -0,0 +1,67 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+func (s *Sheet) createManeuverPopup() *unison.PopupMenu[*gurps.Maneuver] {
+	popup := unison.NewPopupMenu[*gurps.Maneuver]()
+	for _, one := range gurps.Maneuvers {
+		popup.AddItem(one)
+	}
+	popup.Select(s.entity.CurrentManeuver())
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[*gurps.Maneuver]) {
+		m, ok := p.Selected()
+		if !ok || m.ID == s.entity.Maneuver {
+			return
+		}
+		before := s.entity.Maneuver
+		s.entity.Maneuver = m.ID
+		addUndo(s.undoMgr, &unison.UndoEdit[string]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Change Maneuver"),
+			UndoFunc:   func(e *unison.UndoEdit[string]) { s.applyManeuver(e.BeforeData) },
+			RedoFunc:   func(e *unison.UndoEdit[string]) { s.applyManeuver(e.AfterData) },
+			BeforeData: before,
+			AfterData:  m.ID,
+		})
+		s.applyManeuver(m.ID)
+	}
+	s.syncManeuverTooltip(popup)
+	return popup
+}
+
+func (s *Sheet) applyManeuver(id string) {
+	s.entity.Maneuver = id
+	s.Rebuild(true)
+}
+
+func (s *Sheet) syncManeuverPopup() {
+	if s.maneuverPopup == nil {
+		return
+	}
+	s.maneuverPopup.Select(s.entity.CurrentManeuver())
+	s.syncManeuverTooltip(s.maneuverPopup)
+}
+
+func (s *Sheet) syncManeuverTooltip(popup *unison.PopupMenu[*gurps.Maneuver]) {
+	m := s.entity.CurrentManeuver()
+	text := m.Description
+	if m.Page != "" {
+		text = fmt.Sprintf(i18n.Text("%s (%s)"), text, m.Page)
+	}
+	popup.Tooltip = newWrappedTooltip(fmt.Sprintf(i18n.Text("Maneuver for this turn: %s"), text))
+}
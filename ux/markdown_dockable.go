@@ -326,6 +326,17 @@ func (d *MarkdownDockable) save(forceSaveAs bool) bool {
 	return success
 }
 
+func (d *MarkdownDockable) autosaver() func(filePath string) error {
+	return d.saveData
+}
+
+func (d *MarkdownDockable) markRecovered(filePath string, untitled bool) {
+	d.path = filePath
+	d.needsSaveAsPrompt = untitled
+	d.original = "" // Force it to be recognized as unsaved
+	UpdateTitleForDockable(d)
+}
+
 func (d *MarkdownDockable) saveData(filePath string) error {
 	dirPath := filepath.Dir(filePath)
 	if err := os.MkdirAll(dirPath, 0o750); err != nil {
@@ -12,6 +12,7 @@ package ux
 import (
 	"fmt"
 	"io/fs"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/svg"
@@ -73,11 +74,25 @@ func (d *menuKeySettingsDockable) sync() {
 func (d *menuKeySettingsDockable) fill() {
 	for _, b := range gurps.CurrentBindings() {
 		d.createBindingButton(b)
-		d.content.AddChild(NewFieldTrailingLabel(b.Action.Title, false))
+		label := NewFieldTrailingLabel(b.Action.Title, false)
+		if conflicts := gurps.ConflictingBindings(b.ID, b.KeyBinding); len(conflicts) != 0 {
+			label.OnBackgroundInk = unison.ThemeError
+			label.Tooltip = newWrappedTooltip(fmt.Sprintf(i18n.Text("%s is also used by %s"), b.KeyBinding,
+				bindingTitles(conflicts)))
+		}
+		d.content.AddChild(label)
 		d.createResetField(b)
 	}
 }
 
+func bindingTitles(list []*gurps.Binding) string {
+	titles := make([]string, len(list))
+	for i, one := range list {
+		titles[i] = strings.TrimSuffix(one.Action.Title, "…")
+	}
+	return strings.Join(titles, ", ")
+}
+
 func (d *menuKeySettingsDockable) createBindingButton(binding *gurps.Binding) {
 	b := unison.NewButton()
 	b.Font = unison.KeyboardFont
@@ -137,12 +152,22 @@ func (d *menuKeySettingsDockable) createBindingButton(binding *gurps.Binding) {
 				localBinding = unison.KeyBinding{}
 				fallthrough
 			case unison.ModalResponseOK:
-				binding.KeyBinding = localBinding
 				g := gurps.GlobalSettings()
+				conflicts := gurps.ConflictingBindings(binding.ID, localBinding)
+				if len(conflicts) != 0 {
+					if unison.QuestionDialog(fmt.Sprintf(i18n.Text("%s is already used by %s"), localBinding,
+						bindingTitles(conflicts)), i18n.Text("Remove it from there and use it here instead?")) !=
+						unison.ModalResponseOK {
+						return
+					}
+					for _, one := range conflicts {
+						g.KeyBindings.Set(one.ID, unison.KeyBinding{})
+					}
+				}
+				binding.KeyBinding = localBinding
 				g.KeyBindings.Set(binding.ID, localBinding)
 				g.KeyBindings.MakeCurrent()
-				b.SetTitle(localBinding.String())
-				b.MarkForRedraw()
+				d.sync()
 			default:
 			}
 		}
@@ -158,11 +183,7 @@ func (d *menuKeySettingsDockable) createResetField(binding *gurps.Binding) {
 			g := gurps.GlobalSettings()
 			g.KeyBindings.ResetOne(binding.ID)
 			g.KeyBindings.MakeCurrent()
-			binding.KeyBinding = g.KeyBindings.Current(binding.ID)
-			parent := b.Parent()
-			if other, ok := parent.Children()[parent.IndexOfChild(b)-1].Self.(*unison.Button); ok {
-				other.SetTitle(binding.KeyBinding.String())
-			}
+			d.sync()
 		}
 	}
 	b.SetLayoutData(&unison.FlexLayoutData{
@@ -14,6 +14,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 
@@ -29,9 +30,12 @@ import (
 // Menu, Item & Action IDs
 const (
 	NewSheetItemID = unison.UserBaseID + iota
+	NewSheetFromGeneratorItemID
+	NewNPCFromArchetypeItemID
 	NewTemplateItemID
 	NewLootSheetItemID
 	NewCampaignItemID
+	NewVehicleSheetItemID
 	NewTraitsLibraryItemID
 	NewTraitModifiersLibraryItemID
 	NewEquipmentLibraryItemID
@@ -39,10 +43,14 @@ const (
 	NewNotesLibraryItemID
 	NewSkillsLibraryItemID
 	NewSpellsLibraryItemID
+	BuildCompendiumItemID
 	NewMarkdownFileItemID
 	OpenItemID
 	CloseTabID
 	RecentFilesMenuID
+	ImportFromMenuID
+	ImportFromGCA5ItemID
+	ImportFromFoundryItemID
 	SaveItemID
 	SaveAsItemID
 	ExportToMenuID
@@ -50,15 +58,23 @@ const (
 	ExportAsWEBPItemID
 	ExportAsPNGItemID
 	ExportAsJPEGItemID
+	ExportAsSVGItemID
+	ExportAsFoundryItemID
+	ExportAsHTMLItemID
+	ExportAsMarkdownItemID
+	ExportAsStatblockItemID
 	PrintItemID
 	UndoItemID
 	RedoItemID
 	DuplicateItemID
 	ExportPortraitItemID
+	ExportTokenItemID
 	ClearPortraitItemID
 	ClearSourceItemID
 	SyncWithSourceItemID
+	ReviewSourceChangesItemID
 	JumpToSearchFilterItemID
+	FindReplaceItemID
 	ConvertToContainerItemID
 	ConvertToNonContainerItemID
 	ToggleStateItemID
@@ -68,10 +84,15 @@ const (
 	DecrementUsesItemID
 	IncrementSkillLevelItemID
 	DecrementSkillLevelItemID
+	OptimizeSkillLevelsItemID
 	IncrementTechLevelItemID
 	DecrementTechLevelItemID
 	IncrementEquipmentLevelItemID
 	DecrementEquipmentLevelItemID
+	ApplyEquipmentModifierItemID
+	ApplyTraitModifierItemID
+	UseAmmoItemID
+	ReloadWeaponItemID
 	SwapDefaultsItemID
 	MoveToOtherEquipmentItemID
 	MoveToCarriedEquipmentItemID
@@ -89,13 +110,16 @@ const (
 	PerSheetSettingsItemID
 	PerSheetAttributeSettingsItemID
 	PerSheetBodyTypeSettingsItemID
+	PerSheetRulesetItemID
 	DefaultSheetSettingsItemID
 	DefaultAttributeSettingsItemID
 	DefaultBodyTypeSettingsItemID
+	DefaultRulesetItemID
 	GeneralSettingsItemID
 	PageRefMappingsItemID
 	ColorSettingsItemID
 	FontSettingsItemID
+	SheetThemesItemID
 	MenuKeySettingsItemID
 	SponsorGCSDevelopmentItemID
 	MakeDonationItemID
@@ -120,6 +144,29 @@ const (
 	Scale500ItemID
 	Scale600ItemID
 	DockUnDockItemID
+	DiceRollerItemID
+	TagManagerItemID
+	GlobalSearchItemID
+	LibraryDuplicatesItemID
+	SpellPrereqGraphItemID
+	CompareSheetsItemID
+	ScriptsMenuID
+	ScriptConsoleItemID
+	PluginPanelsMenuID
+	HostSessionItemID
+	JoinSessionItemID
+	CloudSyncSettingsItemID
+	CloudSyncItemID
+	MergeSheetsItemID
+	WorkspacesMenuID
+	SaveWorkspaceItemID
+	ManageWorkspacesItemID
+	CommandPaletteItemID
+	LanguageSettingsItemID
+	DiagnosticsItemID
+	UndoHistoryItemID
+	CheckIntegrityItemID
+	LegacyConversionItemID
 
 	FirstNonContainerMarker // Keep this block grouped together
 	NewCarriedEquipmentItemID
@@ -151,8 +198,13 @@ const (
 	NewMeleeWeaponItemID
 	NewRangedWeaponItemID
 
-	RecentFieldBaseItemID  = NewRangedWeaponItemID + 500
-	ExportToTextBaseItemID = RecentFieldBaseItemID + 500
+	RecentFieldBaseItemID     = NewRangedWeaponItemID + 500
+	ExportToTextBaseItemID    = RecentFieldBaseItemID + 500
+	RunScriptBaseItemID       = ExportToTextBaseItemID + 500
+	PluginExportBaseItemID    = RunScriptBaseItemID + 500
+	PluginImportBaseItemID    = PluginExportBaseItemID + 500
+	PluginPanelBaseItemID     = PluginImportBaseItemID + 500
+	SwitchWorkspaceBaseItemID = PluginPanelBaseItemID + 500
 )
 
 var registerKeyBindingsOnce sync.Once
@@ -179,7 +231,8 @@ func SetupMenuBar(wnd *unison.Window) {
 		f := bar.Factory()
 		i := s.insertMenu(bar, bar.Item(unison.EditMenuID).Index()+1, s.createItemMenu(f))
 		i = s.insertMenu(bar, i, s.createSettingsMenu(f))
-		s.insertMenu(bar, i, s.createViewMenu(f))
+		i = s.insertMenu(bar, i, s.createViewMenu(f))
+		s.insertMenu(bar, i, f.NewMenu(ScriptsMenuID, i18n.Text("Scripts"), s.scriptsUpdater))
 		s.setupWindowMenu(bar)
 		s.setupHelpMenu(bar)
 	})
@@ -191,10 +244,12 @@ func (s menuBarScope) setupFileMenu(bar unison.Menu) {
 	f := bar.Factory()
 	m := bar.Menu(unison.FileMenuID)
 	i := s.insertMenuItem(m, 0, newCharacterSheetAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, newCharacterFromGeneratorAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, newNPCFromArchetypeAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newCharacterTemplateAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newLootSheetAction.NewMenuItem(f))
-	// TODO: Re-enable Campaign files
-	// i = s.insertMenuItem(m, i, newCampaignAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, newCampaignAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, newVehicleSheetAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newMarkdownFileAction.NewMenuItem(f))
 
 	i = s.insertMenuSeparator(m, i)
@@ -205,10 +260,12 @@ func (s menuBarScope) setupFileMenu(bar unison.Menu) {
 	i = s.insertMenuItem(m, i, newEquipmentLibraryAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newEquipmentModifiersLibraryAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newNotesLibraryAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, buildCompendiumAction.NewMenuItem(f))
 
 	i = s.insertMenuSeparator(m, i)
 	i = s.insertMenuItem(m, i, openAction.NewMenuItem(f))
-	s.insertMenu(m, i, f.NewMenu(RecentFilesMenuID, i18n.Text("Recent Files"), s.recentFilesUpdater))
+	i = s.insertMenu(m, i, f.NewMenu(RecentFilesMenuID, i18n.Text("Recent Files"), s.recentFilesUpdater))
+	s.insertMenu(m, i, f.NewMenu(ImportFromMenuID, i18n.Text("Import From…"), s.importFromUpdater))
 
 	i = m.Item(unison.CloseItemID).Index()
 	m.RemoveItem(i)
@@ -218,6 +275,7 @@ func (s menuBarScope) setupFileMenu(bar unison.Menu) {
 	i = s.insertMenuItem(m, i, saveAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, saveAsAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, exportPortraitAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, exportTokenAction.NewMenuItem(f))
 	i = s.insertMenu(m, i, f.NewMenu(ExportToMenuID, i18n.Text("Export To…"), s.exportToUpdater))
 
 	i = s.insertMenuSeparator(m, i)
@@ -230,6 +288,7 @@ func (s menuBarScope) setupEditMenu(bar unison.Menu) {
 
 	i := s.insertMenuItem(m, 0, undoAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, redoAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, undoHistoryAction.NewMenuItem(f))
 	s.insertMenuSeparator(m, i)
 
 	deleteIndex := m.Item(unison.DeleteItemID).Index()
@@ -241,6 +300,7 @@ func (s menuBarScope) setupEditMenu(bar unison.Menu) {
 
 	i = s.insertMenuSeparator(m, i)
 	i = s.insertMenuItem(m, i, jumpToSearchFilterAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, findReplaceAction.NewMenuItem(f))
 
 	i = s.insertMenuSeparator(m, i)
 	i = s.insertMenuItem(m, i, moveToCarriedEquipmentAction.NewMenuItem(f))
@@ -250,6 +310,7 @@ func (s menuBarScope) setupEditMenu(bar unison.Menu) {
 	i = s.insertMenuItem(m, i, applyTemplateAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newSheetFromTemplateAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, cloneSheetAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, checkIntegrityAction.NewMenuItem(f))
 
 	i = s.insertMenuSeparator(m, i)
 	i = s.insertMenuItem(m, i, incrementAction.NewMenuItem(f))
@@ -258,10 +319,15 @@ func (s menuBarScope) setupEditMenu(bar unison.Menu) {
 	i = s.insertMenuItem(m, i, decreaseUsesAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, increaseSkillLevelAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, decreaseSkillLevelAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, optimizeSkillLevelsAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, increaseTechLevelAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, decreaseTechLevelAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, increaseEquipmentLevelAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, decreaseEquipmentLevelAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, applyEquipmentModifierAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, applyTraitModifierAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, useAmmoAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, reloadWeaponAction.NewMenuItem(f))
 
 	i = s.insertMenuSeparator(m, i)
 	i = s.insertMenuItem(m, i, toggleStateAction.NewMenuItem(f))
@@ -271,6 +337,7 @@ func (s menuBarScope) setupEditMenu(bar unison.Menu) {
 
 	i = s.insertMenuSeparator(m, i)
 	i = s.insertMenuItem(m, i, syncWithSourceAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, reviewSourceChangesAction.NewMenuItem(f))
 	s.insertMenuItem(m, i, clearSourceAction.NewMenuItem(f))
 }
 
@@ -320,16 +387,23 @@ func (s menuBarScope) createSettingsMenu(f unison.MenuFactory) unison.Menu {
 	m.InsertItem(-1, perSheetSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, perSheetAttributeSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, perSheetBodyTypeSettingsAction.NewMenuItem(f))
+	m.InsertItem(-1, perSheetRulesetAction.NewMenuItem(f))
 	m.InsertSeparator(-1, false)
 	m.InsertItem(-1, defaultSheetSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, defaultAttributeSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, defaultBodyTypeSettingsAction.NewMenuItem(f))
+	m.InsertItem(-1, defaultRulesetAction.NewMenuItem(f))
 	m.InsertSeparator(-1, false)
 	m.InsertItem(-1, generalSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, pageRefMappingsAction.NewMenuItem(f))
 	m.InsertItem(-1, colorSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, fontSettingsAction.NewMenuItem(f))
+	m.InsertItem(-1, sheetThemesAction.NewMenuItem(f))
 	m.InsertItem(-1, menuKeySettingsAction.NewMenuItem(f))
+	m.InsertItem(-1, languageSettingsAction.NewMenuItem(f))
+	m.InsertSeparator(-1, false)
+	m.InsertItem(-1, cloudSyncSettingsAction.NewMenuItem(f))
+	m.InsertItem(-1, cloudSyncAction.NewMenuItem(f))
 	return m
 }
 
@@ -356,6 +430,19 @@ func (s menuBarScope) setupWindowMenu(bar unison.Menu) {
 	f := bar.Factory()
 	m := bar.Menu(unison.WindowMenuID)
 	s.insertMenuItem(m, -1, dockUnDockAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, diceRollerAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, tagManagerAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, globalSearchAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, commandPaletteAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, libraryDuplicatesAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, legacyConversionAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, spellPrereqGraphAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, compareSheetsAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, mergeSheetsAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, hostSessionAction.NewMenuItem(f))
+	s.insertMenuItem(m, -1, joinSessionAction.NewMenuItem(f))
+	s.insertMenu(m, -1, f.NewMenu(PluginPanelsMenuID, i18n.Text("Plugin Panels"), s.pluginPanelsUpdater))
+	s.insertMenu(m, -1, f.NewMenu(WorkspacesMenuID, i18n.Text("Workspaces"), s.workspacesUpdater))
 }
 
 func (s menuBarScope) setupHelpMenu(bar unison.Menu) {
@@ -370,6 +457,7 @@ func (s menuBarScope) setupHelpMenu(bar unison.Menu) {
 	m.InsertItem(-1, checkForAppUpdatesAction.NewMenuItem(f))
 	m.InsertItem(-1, releaseNotesAction.NewMenuItem(f))
 	m.InsertItem(-1, licenseAction.NewMenuItem(f))
+	m.InsertItem(-1, diagnosticsAction.NewMenuItem(f))
 	m.InsertSeparator(-1, false)
 	m.InsertItem(-1, webSiteAction.NewMenuItem(f))
 	m.InsertItem(-1, mailingListAction.NewMenuItem(f))
@@ -411,39 +499,171 @@ func (s menuBarScope) exportToUpdater(menu unison.Menu) {
 	menu.InsertItem(-1, exportAsWEBPAction.NewMenuItem(factory))
 	menu.InsertItem(-1, exportAsPNGAction.NewMenuItem(factory))
 	menu.InsertItem(-1, exportAsJPEGAction.NewMenuItem(factory))
+	menu.InsertItem(-1, exportAsSVGAction.NewMenuItem(factory))
+	menu.InsertSeparator(-1, false)
+	menu.InsertItem(-1, exportAsFoundryAction.NewMenuItem(factory))
+	menu.InsertItem(-1, exportAsHTMLAction.NewMenuItem(factory))
+	menu.InsertItem(-1, exportAsMarkdownAction.NewMenuItem(factory))
+	menu.InsertItem(-1, exportAsStatblockAction.NewMenuItem(factory))
 	menu.InsertSeparator(-1, false)
+	fixedCount := menu.Count()
 	index := 0
 	for _, lib := range gurps.GlobalSettings().Libraries().List() {
-		dir := lib.Path()
-		entries, err := fs.ReadDir(os.DirFS(dir), outputTemplatesDirName)
-		if err != nil {
-			if !errors.Is(err, fs.ErrNotExist) {
-				errs.Log(err, "dir", dir)
-			}
-			continue
-		}
-		list := make([]string, 0, len(entries))
-		for _, entry := range entries {
-			name := entry.Name()
-			fullPath := filepath.Join(dir, outputTemplatesDirName, name)
-			if !strings.HasPrefix(name, ".") && xos.FileExists(fullPath) {
-				list = append(list, fullPath)
+		list, ok := s.exportTemplatePaths(filepath.Join(lib.Path(), outputTemplatesDirName))
+		if ok && (len(list) > 0 || lib.IsMaster()) {
+			s.appendDisabledMenuItem(menu, lib.Title)
+			for _, one := range list {
+				menu.InsertItem(-1, s.createExportToTextAction(index, one).NewMenuItem(factory))
+				index++
 			}
 		}
-		if len(list) > 0 || lib.IsMaster() {
-			s.appendDisabledMenuItem(menu, lib.Title)
-			xstrings.SortStringsNaturalAscending(list)
+	}
+	if dir := gurps.GlobalSettings().General.ExportTemplatesDir; dir != "" {
+		if list, ok := s.exportTemplatePaths(dir); ok && len(list) > 0 {
+			s.appendDisabledMenuItem(menu, i18n.Text("User Templates"))
 			for _, one := range list {
 				menu.InsertItem(-1, s.createExportToTextAction(index, one).NewMenuItem(factory))
 				index++
 			}
 		}
 	}
-	if menu.Count() == 2 {
+	index = 0
+	for _, plugin := range gurps.DiscoverPlugins(gurps.GlobalSettings().General.PluginsDir) {
+		if len(plugin.Exporters) > 0 {
+			s.appendDisabledMenuItem(menu, plugin.Name)
+			for _, exporter := range plugin.Exporters {
+				menu.InsertItem(-1, s.createPluginExportAction(index, plugin, exporter).NewMenuItem(factory))
+				index++
+			}
+		}
+	}
+	if menu.Count() == fixedCount {
 		s.appendDisabledMenuItem(menu, i18n.Text("No export templates available"))
 	}
 }
 
+func (s menuBarScope) importFromUpdater(menu unison.Menu) {
+	menu.RemoveAll()
+	factory := menu.Factory()
+	menu.InsertItem(-1, importGCA5Action.NewMenuItem(factory))
+	menu.InsertItem(-1, importFoundryAction.NewMenuItem(factory))
+	index := 0
+	for _, plugin := range gurps.DiscoverPlugins(gurps.GlobalSettings().General.PluginsDir) {
+		if len(plugin.Importers) > 0 {
+			if index == 0 {
+				menu.InsertSeparator(-1, false)
+			}
+			s.appendDisabledMenuItem(menu, plugin.Name)
+			for _, imp := range plugin.Importers {
+				menu.InsertItem(-1, s.createPluginImportAction(index, plugin, imp).NewMenuItem(factory))
+				index++
+			}
+		}
+	}
+}
+
+func (s menuBarScope) pluginPanelsUpdater(menu unison.Menu) {
+	menu.RemoveAll()
+	factory := menu.Factory()
+	index := 0
+	for _, plugin := range gurps.DiscoverPlugins(gurps.GlobalSettings().General.PluginsDir) {
+		if len(plugin.Panels) > 0 {
+			s.appendDisabledMenuItem(menu, plugin.Name)
+			for _, panel := range plugin.Panels {
+				menu.InsertItem(-1, s.createPluginPanelAction(index, plugin, panel).NewMenuItem(factory))
+				index++
+			}
+		}
+	}
+	if menu.Count() == 0 {
+		s.appendDisabledMenuItem(menu, i18n.Text("No plugin panels available"))
+	}
+}
+
+func (s menuBarScope) createPluginExportAction(index int, plugin *gurps.Plugin,
+	exporter *gurps.PluginExporter) *unison.Action {
+	return &unison.Action{
+		ID:              PluginExportBaseItemID + index,
+		Title:           "    " + pluginItemTitle(exporter.Title, exporter.ID),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if sheet := ActiveSheet(); sheet != nil {
+				exportWithPlugin(sheet, plugin, exporter)
+			}
+		},
+	}
+}
+
+func (s menuBarScope) createPluginImportAction(index int, plugin *gurps.Plugin,
+	imp *gurps.PluginImporter) *unison.Action {
+	return &unison.Action{
+		ID:              PluginImportBaseItemID + index,
+		Title:           "    " + pluginItemTitle(imp.Title, imp.ID) + "…",
+		ExecuteCallback: func(_ *unison.Action, _ any) { importWithPlugin(plugin, imp) },
+	}
+}
+
+func (s menuBarScope) createPluginPanelAction(index int, plugin *gurps.Plugin,
+	panel *gurps.PluginPanel) *unison.Action {
+	return &unison.Action{
+		ID:              PluginPanelBaseItemID + index,
+		Title:           "    " + pluginItemTitle(panel.Title, panel.ID),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if sheet := ActiveSheet(); sheet != nil {
+				ShowPluginPanel(sheet, plugin, panel)
+			}
+		},
+	}
+}
+
+func (s menuBarScope) workspacesUpdater(menu unison.Menu) {
+	menu.RemoveAll()
+	factory := menu.Factory()
+	menu.InsertItem(-1, saveWorkspaceAction.NewMenuItem(factory))
+	menu.InsertItem(-1, manageWorkspacesAction.NewMenuItem(factory))
+	names := gurps.GlobalSettings().WorkspaceNames()
+	if len(names) != 0 {
+		menu.InsertSeparator(-1, false)
+		for i, name := range names {
+			menu.InsertItem(-1, (&unison.Action{
+				ID:              SwitchWorkspaceBaseItemID + i,
+				Title:           name,
+				ExecuteCallback: func(_ *unison.Action, _ any) { SwitchToWorkspace(name) },
+			}).NewMenuItem(factory))
+		}
+	}
+}
+
+func pluginItemTitle(title, id string) string {
+	if title != "" {
+		return title
+	}
+	return id
+}
+
+// exportTemplatePaths returns the paths of the export templates in dir, sorted by name. Returns false if the directory
+// could not be read.
+func (s menuBarScope) exportTemplatePaths(dir string) ([]string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			errs.Log(err, "dir", dir)
+		}
+		return nil, false
+	}
+	list := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		fullPath := filepath.Join(dir, name)
+		if !strings.HasPrefix(name, ".") && xos.FileExists(fullPath) {
+			list = append(list, fullPath)
+		}
+	}
+	xstrings.SortStringsNaturalAscending(list)
+	return list, true
+}
+
 func (s menuBarScope) createExportToTextAction(index int, path string) *unison.Action {
 	return &unison.Action{
 		ID:              ExportToTextBaseItemID + index,
@@ -470,6 +690,52 @@ func (s menuBarScope) createExportToTextAction(index int, path string) *unison.A
 	}
 }
 
+func (s menuBarScope) scriptsUpdater(menu unison.Menu) {
+	menu.RemoveAll()
+	factory := menu.Factory()
+	menu.InsertItem(-1, scriptConsoleAction.NewMenuItem(factory))
+	menu.InsertSeparator(-1, false)
+	fixedCount := menu.Count()
+	index := 0
+	for _, lib := range gurps.GlobalSettings().Libraries().List() {
+		list, ok := s.exportTemplatePaths(filepath.Join(lib.Path(), gurps.AutomationScriptsDirName))
+		if !ok {
+			continue
+		}
+		list = slices.DeleteFunc(list, func(one string) bool {
+			return !strings.EqualFold(filepath.Ext(one), gurps.AutomationScriptExt)
+		})
+		if len(list) > 0 {
+			s.appendDisabledMenuItem(menu, lib.Title)
+			for _, one := range list {
+				menu.InsertItem(-1, s.createRunScriptAction(index, one).NewMenuItem(factory))
+				index++
+			}
+		}
+	}
+	if menu.Count() == fixedCount {
+		s.appendDisabledMenuItem(menu, i18n.Text("No scripts available"))
+	}
+}
+
+func (s menuBarScope) createRunScriptAction(index int, path string) *unison.Action {
+	return &unison.Action{
+		ID:              RunScriptBaseItemID + index,
+		Title:           "    " + xfilepath.TrimExtension(filepath.Base(path)),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if sheet := ActiveSheet(); sheet != nil {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					Workspace.ErrorHandler(i18n.Text("Unable to load script"), err)
+					return
+				}
+				runAutomationScript(sheet, xfilepath.TrimExtension(filepath.Base(path)), string(data))
+			}
+		},
+	}
+}
+
 func (s menuBarScope) insertMenuSeparator(parent unison.Menu, atIndex int) int {
 	parent.InsertSeparator(atIndex, false)
 	return atIndex + 1
@@ -513,10 +779,15 @@ func AppendDefaultContextMenuItems(list []ContextMenuItem) []ContextMenuItem {
 		ContextMenuItem{decreaseUsesAction.Title, DecrementUsesItemID},
 		ContextMenuItem{increaseSkillLevelAction.Title, IncrementSkillLevelItemID},
 		ContextMenuItem{decreaseSkillLevelAction.Title, DecrementSkillLevelItemID},
+		ContextMenuItem{optimizeSkillLevelsAction.Title, OptimizeSkillLevelsItemID},
 		ContextMenuItem{increaseTechLevelAction.Title, IncrementTechLevelItemID},
 		ContextMenuItem{decreaseTechLevelAction.Title, DecrementTechLevelItemID},
 		ContextMenuItem{increaseEquipmentLevelAction.Title, IncrementEquipmentLevelItemID},
 		ContextMenuItem{decreaseEquipmentLevelAction.Title, DecrementEquipmentLevelItemID},
+		ContextMenuItem{applyEquipmentModifierAction.Title, ApplyEquipmentModifierItemID},
+		ContextMenuItem{applyTraitModifierAction.Title, ApplyTraitModifierItemID},
+		ContextMenuItem{useAmmoAction.Title, UseAmmoItemID},
+		ContextMenuItem{reloadWeaponAction.Title, ReloadWeaponItemID},
 		ContextMenuItem{"", -1},
 		ContextMenuItem{toggleStateAction.Title, ToggleStateItemID},
 		ContextMenuItem{swapDefaultsAction.Title, SwapDefaultsItemID},
@@ -527,6 +798,7 @@ func AppendDefaultContextMenuItems(list []ContextMenuItem) []ContextMenuItem {
 		ContextMenuItem{openEachPageReferenceAction.Title, OpenEachPageReferenceItemID},
 		ContextMenuItem{"", -1},
 		ContextMenuItem{syncWithSourceAction.Title, SyncWithSourceItemID},
+		ContextMenuItem{reviewSourceChangesAction.Title, ReviewSourceChangesItemID},
 		ContextMenuItem{clearSourceAction.Title, ClearSourceItemID},
 	)
 }
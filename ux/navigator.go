@@ -69,6 +69,8 @@ type Navigator struct {
 	libraryReleaseNotesButton *unison.Button
 	configLibraryButton       *unison.Button
 	favoriteButton            *unison.Button
+	indexPanel                *unison.Panel
+	indexProgress             *unison.ProgressBar
 	scroll                    *unison.ScrollPanel
 	table                     *unison.Table[*NavigatorNode]
 	tokens                    []*gurps.MonitorToken
@@ -272,6 +274,49 @@ func (n *Navigator) setupToolBar() {
 	})
 	n.toolbar.AddChild(first)
 	n.toolbar.AddChild(second)
+	n.createIndexProgress()
+}
+
+// createIndexProgress creates the panel shown at the bottom of the toolbar while the library search index is being
+// brought up to date. It is only added to the toolbar when needed.
+func (n *Navigator) createIndexProgress() {
+	label := unison.NewLabel()
+	label.SetTitle(i18n.Text("Indexing libraries…"))
+	label.SetLayoutData(&unison.FlexLayoutData{VAlign: align.Middle})
+	n.indexProgress = unison.NewProgressBar(0)
+	n.indexProgress.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	n.indexPanel = unison.NewPanel()
+	n.indexPanel.Tooltip = newWrappedTooltip(i18n.Text("Global Search will include the items in the libraries once this completes"))
+	n.indexPanel.AddChild(label)
+	n.indexPanel.AddChild(n.indexProgress)
+	n.indexPanel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	n.indexPanel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+}
+
+func (n *Navigator) showIndexProgress(busy bool, done, total int) {
+	if !busy {
+		if n.indexPanel.Parent() != nil {
+			n.indexPanel.RemoveFromParent()
+			n.MarkForLayoutAndRedraw()
+		}
+		return
+	}
+	if n.indexPanel.Parent() == nil {
+		n.toolbar.AddChild(n.indexPanel)
+		n.MarkForLayoutAndRedraw()
+	}
+	n.indexProgress.SetMaximum(float32(total))
+	n.indexProgress.SetCurrent(float32(done))
 }
 
 // InitialFocus causes the navigator to focus its initial component.
@@ -379,8 +424,8 @@ func (n *Navigator) closeSelection(selection []*NavigatorNode) bool {
 	for _, row := range selection {
 		p := row.Path()
 		if row.IsDirectory() {
-			if len(row.children) != 0 {
-				if !n.closeSelection(row.children) {
+			if children := row.Children(); len(children) != 0 {
+				if !n.closeSelection(children) {
 					return false
 				}
 			}
@@ -606,6 +651,7 @@ func (n *Navigator) mouseDown(where geom.Point, button, clickCount int, mod unis
 				switch filepath.Ext(p) {
 				case gurps.SheetExt:
 					cm.InsertItem(-1, cloneSheetMenuItem(f, &id, p))
+					cm.InsertItem(-1, copyStatblockMenuItem(f, &id, p))
 					cm.InsertSeparator(-1, true)
 				case gurps.TemplatesExt:
 					cm.InsertItem(-1, newSheetFromTemplateMenuItem(f, &id, p))
@@ -655,6 +701,15 @@ func cloneSheetMenuItem(f unison.MenuFactory, id *int, sheetPath string) unison.
 		})
 }
 
+func copyStatblockMenuItem(f unison.MenuFactory, id *int, sheetPath string) unison.MenuItem {
+	useID := *id
+	*id++
+	return f.NewItem(unison.PopupMenuTemporaryBaseID+useID, i18n.Text("Copy Statblock"),
+		unison.KeyBinding{}, nil, func(_ unison.MenuItem) {
+			CopyStatblock(sheetPath)
+		})
+}
+
 func newSheetFromTemplateMenuItem(f unison.MenuFactory, id *int, templatePath string) unison.MenuItem {
 	useID := *id
 	*id++
@@ -1007,9 +1062,18 @@ func (n *Navigator) search(text string, rows []*NavigatorNode) {
 								prepareForContentCache(data.Notes),
 							}, "\n"))
 						}
-					// TODO: Re-enable Campaign files
-					// case gurps.CampaignExt:
-					// TODO: Implement
+					case gurps.CampaignExt:
+						if data, err := gurps.NewCampaignFromFile(dir, fileName); err == nil {
+							content = n.addToContentCache(p, strings.ToLower(data.Name+"\n"+data.Notes))
+						}
+					case gurps.VehicleExt:
+						if data, err := gurps.NewVehicleFromFile(dir, fileName); err == nil {
+							content = n.addToContentCache(p, strings.Join([]string{
+								strings.ToLower(data.Name),
+								prepareForContentCache(data.Equipment),
+								prepareForContentCache(data.Notes),
+							}, "\n"))
+						}
 					case gurps.TraitModifiersExt:
 						if data, err := gurps.NewTraitModifiersFromFile(dir, fileName); err == nil {
 							content = n.addToContentCache(p, prepareForContentCache(data))
@@ -1102,7 +1166,8 @@ func (n *Navigator) accumulateDisclosedPaths(rows []*NavigatorNode, disclosedPat
 		if row.IsOpen() {
 			disclosedPaths = append(disclosedPaths, row.Path())
 		}
-		disclosedPaths = n.accumulateDisclosedPaths(row.Children(), disclosedPaths)
+		// Children that haven't been read from disk yet will pick up their open state when they are, so skip them
+		disclosedPaths = n.accumulateDisclosedPaths(row.children, disclosedPaths)
 	}
 	return disclosedPaths
 }
@@ -1122,7 +1187,7 @@ func (n *Navigator) applyDisclosedPaths(rows []*NavigatorNode, paths map[string]
 		if row.IsOpen() != open {
 			row.SetOpen(open)
 		}
-		n.applyDisclosedPaths(row.Children(), paths)
+		n.applyDisclosedPaths(row.children, paths)
 	}
 }
 
@@ -1172,6 +1237,10 @@ func DisplayNewDockable(dockable unison.Dockable) {
 		if children := dockable.AsPanel().Children(); len(children) > 1 {
 			FocusFirstContent(children[0], children[1])
 		}
+		if _, ok := dockable.(*Sheet); ok {
+			PublishAPIServerChanges(nil)
+			QueueSessionSync(nil)
+		}
 	}()
 	if fbd, ok := dockable.(FileBackedDockable); ok {
 		var group *dgroup.Group
@@ -1192,10 +1261,12 @@ func DisplayNewDockable(dockable unison.Dockable) {
 		case fi.Extensions[0] == gurps.LootExt:
 			g := dgroup.LootSheets
 			group = &g
-		// TODO: Re-enable Campaign files
-		// case fi.Extensions[0] == gurps.CampaignExt:
-		// 	g := dgroup.Campaigns
-		// 	group = &g
+		case fi.Extensions[0] == gurps.CampaignExt:
+			g := dgroup.Campaigns
+			group = &g
+		case fi.Extensions[0] == gurps.VehicleExt:
+			g := dgroup.Vehicles
+			group = &g
 		case fi.Extensions[0] == gurps.TraitsExt,
 			fi.Extensions[0] == gurps.TraitModifiersExt,
 			fi.Extensions[0] == gurps.EquipmentExt,
@@ -40,18 +40,17 @@ type NavigatorNode struct {
 	library                  *gurps.Library
 	parent                   *NavigatorNode
 	children                 []*NavigatorNode
+	populated                bool
 	updateCellReleaseVersion string
 	updateCellCache          *updatableLibraryCell
 }
 
 // NewFavoritesNode creates the Favorites node.
 func NewFavoritesNode(nav *Navigator) *NavigatorNode {
-	n := &NavigatorNode{
+	return &NavigatorNode{
 		id:  "00000000000000000",
 		nav: nav,
 	}
-	n.Refresh()
-	return n
 }
 
 // NewLibraryNode creates a new library node.
@@ -68,13 +67,11 @@ func NewLibraryNode(nav *Navigator, lib *gurps.Library) *NavigatorNode {
 		}
 		id = lib.ID
 	}
-	n := &NavigatorNode{
+	return &NavigatorNode{
 		id:      id,
 		nav:     nav,
 		library: lib,
 	}
-	n.Refresh()
-	return n
 }
 
 // NewDirectoryNode creates a new DirectoryNode.
@@ -89,15 +86,13 @@ func NewDirectoryNode(nav *Navigator, lib *gurps.Library, dirPath string, parent
 	} else {
 		pathForID = "_" + pathForID
 	}
-	n := &NavigatorNode{
+	return &NavigatorNode{
 		id:      gurps.IDForNavNode(pathForID, kinds.NavigatorDirectory),
 		path:    dirPath,
 		nav:     nav,
 		library: lib,
 		parent:  parent,
 	}
-	n.Refresh()
-	return n
 }
 
 // NewFileNode creates a new FileNode.
@@ -134,8 +129,12 @@ func (n *NavigatorNode) CanHaveChildren() bool {
 	return !n.IsFile()
 }
 
-// Children implements unison.TableRowData.
+// Children implements unison.TableRowData. The contents of a library or directory aren't read from disk until they are
+// first asked for, so that large libraries don't slow down the creation of the tree.
 func (n *NavigatorNode) Children() []*NavigatorNode {
+	if !n.populated && n.CanHaveChildren() {
+		n.Refresh()
+	}
 	return n.children
 }
 
@@ -298,6 +297,8 @@ func (n *NavigatorNode) Path() string {
 
 // Refresh the contents of this node.
 func (n *NavigatorNode) Refresh() {
+	n.populated = true
+	n.children = nil
 	switch {
 	case n.IsFavorites():
 		type fav struct {
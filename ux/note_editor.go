@@ -96,6 +96,7 @@ func initNoteEditor(e *editor[*gurps.Note, *gurps.NoteEditData], content *unison
 
 	addPageRefLabelAndField(content, &e.editorData.PageRef)
 	addPageRefHighlightLabelAndField(content, &e.editorData.PageRefHighlight)
+	addProvenanceLabelsAndFields(content, &e.editorData.Provenance)
 	addSourceFields(content, &e.target.SourcedID)
 
 	label = unison.NewLabel()
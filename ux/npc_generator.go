@@ -0,0 +1,109 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/threat"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xrand"
+	"github.com/richardwilkes/unison"
+)
+
+// NewNPCFromArchetype asks the user for an archetype template and how tough the NPC should be, then generates a new
+// character sheet scaled to match.
+func NewNPCFromArchetype() {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.TemplatesExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	if !dialog.RunModal() {
+		return
+	}
+	archetypePath := dialog.Path()
+	global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(archetypePath))
+	archetype, err := gurps.NewTemplateFromFile(os.DirFS(filepath.Dir(archetypePath)), filepath.Base(archetypePath))
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load archetype"), err)
+		return
+	}
+	targetPoints, ok := promptForNPCPoints()
+	if !ok {
+		return
+	}
+	e := gurps.GenerateNPC(archetype, targetPoints, xrand.New())
+	DisplayNewDockable(NewSheet(e.Profile.Name+gurps.SheetExt, e))
+}
+
+func promptForNPCPoints() (targetPoints fxp.Int, ok bool) {
+	partyPoints := gurps.GlobalSettings().General.InitialPoints
+	level := threat.Peer
+	targetPoints = gurps.NPCPoints(level, partyPoints)
+
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	var targetField *DecimalField
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Party Points"), false))
+	partyField := NewDecimalField(nil, "", "", func() fxp.Int { return partyPoints },
+		func(value fxp.Int) {
+			partyPoints = value
+			targetPoints = gurps.NPCPoints(level, partyPoints)
+			targetField.Sync()
+		}, 0, fxp.MaxBasePoints, false, false)
+	partyField.Tooltip = newWrappedTooltip(i18n.Text("The points the player characters are built on"))
+	panel.AddChild(partyField)
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Threat Level"), false))
+	threatPopup := unison.NewPopupMenu[threat.Level]()
+	for _, one := range threat.Levels {
+		threatPopup.AddItem(one)
+	}
+	threatPopup.Select(level)
+	threatPopup.Tooltip = newWrappedTooltip(level.AltString())
+	threatPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[threat.Level]) {
+		if item, selected := popup.Selected(); selected {
+			level = item
+			popup.Tooltip = newWrappedTooltip(level.AltString())
+			targetPoints = gurps.NPCPoints(level, partyPoints)
+			targetField.Sync()
+		}
+	}
+	panel.AddChild(threatPopup)
+
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Target Points"), false))
+	targetField = NewDecimalField(nil, "", "", func() fxp.Int { return targetPoints },
+		func(value fxp.Int) { targetPoints = value }, 0, fxp.MaxBasePoints, false, false)
+	targetField.Tooltip = newWrappedTooltip(i18n.Text(
+		"The points the NPC will be built on. ST, DX, and skill levels are raised or lowered to match."))
+	panel.AddChild(targetField)
+
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()})
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create NPC generator dialog"), err)
+		return 0, false
+	}
+	ok = dialog.RunModal() == unison.ModalResponseOK
+	return targetPoints, ok
+}
@@ -172,7 +172,7 @@ func (f *NumericField[T]) modified(before, after *unison.FieldState) {
 					self.setWithoutUndo(data, true)
 				}, before)
 			undo.AfterData = after
-			mgr.Add(undo)
+			addUndo(mgr, undo)
 		}
 	}
 	f.adjustForText()
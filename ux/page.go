@@ -42,6 +42,7 @@ func NewPage(infoProvider gurps.PageInfoProvider) *Page {
 		},
 	}
 	p.Self = p
+	p.SetScale(p.contentScale())
 	p.lastInsets = p.insets()
 	p.SetBorder(unison.NewEmptyBorder(p.lastInsets))
 	p.SetLayout(p)
@@ -57,11 +58,13 @@ func (p *Page) LayoutSizes(_ *unison.Panel, _ geom.Size) (minSize, prefSize, max
 		p.lastInsets = insets
 		p.SetBorder(unison.NewEmptyBorder(insets))
 	}
-	prefSize.Width = w.Pixels()
+	// The page size is fixed, so when the content is scaled, the logical size must be adjusted in the opposite direction
+	scale := p.Scale()
+	prefSize.Width = w.Pixels() / scale.X
 	if p.Force {
-		prefSize.Height = h.Pixels()
+		prefSize.Height = h.Pixels() / scale.Y
 	} else {
-		_, size, _ := p.flex.LayoutSizes(p.AsPanel(), geom.Size{Width: w.Pixels()})
+		_, size, _ := p.flex.LayoutSizes(p.AsPanel(), geom.Size{Width: prefSize.Width})
 		prefSize.Height = size.Height
 	}
 	return prefSize, prefSize, prefSize
@@ -72,6 +75,24 @@ func (p *Page) PerformLayout(_ *unison.Panel) {
 	p.flex.PerformLayout(p.AsPanel())
 }
 
+// Sync the content scale with the current settings.
+func (p *Page) Sync() {
+	if scale := p.contentScale(); scale != p.Scale() {
+		p.SetScale(scale)
+		p.MarkForLayoutRecursivelyUpward()
+		p.MarkForRedraw()
+	}
+}
+
+func (p *Page) contentScale() geom.Point {
+	if scaler, ok := p.infoProvider.(gurps.PageScaler); ok {
+		if scale := scaler.PageScale(); scale > 0 {
+			return geom.NewPoint(scale, scale)
+		}
+	}
+	return geom.NewPoint(1, 1)
+}
+
 // ApplyPreferredSize to this panel.
 func (p *Page) ApplyPreferredSize() {
 	r := p.FrameRect()
@@ -83,11 +104,12 @@ func (p *Page) ApplyPreferredSize() {
 
 func (p *Page) insets() geom.Insets {
 	pageSettings := p.infoProvider.PageSettings()
+	scale := p.Scale()
 	insets := geom.Insets{
-		Top:    pageSettings.TopMargin.Pixels(),
-		Left:   pageSettings.LeftMargin.Pixels(),
-		Bottom: pageSettings.BottomMargin.Pixels(),
-		Right:  pageSettings.RightMargin.Pixels(),
+		Top:    pageSettings.TopMargin.Pixels() / scale.Y,
+		Left:   pageSettings.LeftMargin.Pixels() / scale.X,
+		Bottom: pageSettings.BottomMargin.Pixels() / scale.Y,
+		Right:  pageSettings.RightMargin.Pixels() / scale.X,
 	}
 	height := fonts.PageFooterSecondary.LineHeight()
 	insets.Bottom += xmath.Ceil(max(fonts.PageFooterPrimary.LineHeight(), height) + height)
@@ -12,15 +12,18 @@ package ux
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/pdfform"
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/toolbox/v2/i18n"
@@ -43,6 +46,8 @@ type pageExporter struct {
 	targetMgr   *TargetMgr
 	pages       []*Page
 	currentPage int
+	resolution  int
+	theme       *gurps.SheetTheme
 }
 
 // ExportDockable is an interface for dockables that can be exported to a file.
@@ -58,6 +63,7 @@ func InstallExportCmdHandlers(dockable ExportDockable) {
 	p.InstallCmdHandlers(ExportAsWEBPItemID, unison.AlwaysEnabled, func(_ any) { ExportPage("webp", dockable) })
 	p.InstallCmdHandlers(ExportAsPNGItemID, unison.AlwaysEnabled, func(_ any) { ExportPage("png", dockable) })
 	p.InstallCmdHandlers(ExportAsJPEGItemID, unison.AlwaysEnabled, func(_ any) { ExportPage("jpeg", dockable) })
+	p.InstallCmdHandlers(ExportAsSVGItemID, unison.AlwaysEnabled, func(_ any) { ExportPage("svg", dockable) })
 	p.InstallCmdHandlers(PrintItemID, unison.AlwaysEnabled, func(_ any) { Print(dockable) })
 }
 
@@ -69,9 +75,14 @@ func Print(dockable ExportDockable) {
 		Workspace.ErrorHandler(i18n.Text("Unable to create print data!"), err)
 		return
 	}
+	printPDF(p.PageTitle(), data)
+}
+
+// printPDF asks the user to choose a printer and then prints the PDF data to it.
+func printPDF(title string, data []byte) {
 	dialog := printMgr.NewJobDialog(lastPrinter, "application/pdf", nil)
 	if dialog.RunModal() {
-		go doPrint(p.PageTitle(), dialog.Printer(), dialog.JobAttributes(), data)
+		go doPrint(title, dialog.Printer(), dialog.JobAttributes(), data)
 	}
 	if p := dialog.Printer(); p != nil {
 		lastPrinter = p.PrinterID
@@ -86,7 +97,7 @@ func doPrint(title string, printer *printing.Printer, jobAttributes *printing.Jo
 	}
 }
 
-// ExportPage exports the given dockable to the specified file type, one of "pdf", "webp", "png", or "jpeg".
+// ExportPage exports the given dockable to the specified file type, one of "pdf", "webp", "png", "jpeg", or "svg".
 func ExportPage(ext string, dockable ExportDockable) {
 	if tmplDockable, ok := dockable.(*Template); ok {
 		tmplDockable.template.ExplicitPageTitle = tmplDockable.Title()
@@ -112,6 +123,8 @@ func ExportPage(ext string, dockable ExportDockable) {
 				err = exporter.exportAsPNGs(filePath)
 			case "jpeg":
 				err = exporter.exportAsJPEGs(filePath)
+			case "svg":
+				err = exporter.exportAsSVGs(filePath)
 			default:
 				err = errs.New("unsupported export format: " + ext)
 			}
@@ -123,7 +136,15 @@ func ExportPage(ext string, dockable ExportDockable) {
 }
 
 func newPageExporter(provider gurps.PageInfoProvider) *pageExporter {
-	p := &pageExporter{provider: provider}
+	p := &pageExporter{
+		provider:   provider,
+		resolution: gurps.GlobalSettings().General.ImageResolution,
+	}
+	if entity, ok := provider.(*gurps.Entity); ok {
+		p.theme = entity.SheetSettings.ResolvedTheme()
+	}
+	// The theme's fonts must be in effect while the pages are laid out, not just while they are drawn
+	defer p.applyTheme()()
 	p.targetMgr = NewTargetMgr(p)
 	pageSize := p.PageSize()
 	r := geom.Rect{Size: pageSize}
@@ -134,13 +155,17 @@ func newPageExporter(provider gurps.PageInfoProvider) *pageExporter {
 		page, _, _ = createPageTopBlock(p.entity, p.targetMgr)
 	case *gurps.Loot:
 		page = createLootTopBlock(kind, p.targetMgr)
+	case *gurps.Vehicle:
+		page, _ = createVehicleTopBlock(kind, p.targetMgr, nil)
 	default:
 		page = NewPage(p.provider)
 	}
 	p.AddChild(page)
 	p.pages = append(p.pages, page)
-	for _, col := range gurps.SheetSettingsFor(p.entity).BlockLayout.ByRow() {
+	blockLayout := gurps.SheetSettingsFor(p.entity).BlockLayout
+	for _, col := range blockLayout.ByRow() {
 		startAt := make(map[string]int)
+		breakBefore := slices.ContainsFunc(col, blockLayout.StartsNewPage)
 		for {
 			rowPanel := unison.NewPanel()
 			rowPanel.SetLayoutData(&unison.FlexLayoutData{
@@ -151,35 +176,75 @@ func newPageExporter(provider gurps.PageInfoProvider) *pageExporter {
 				switch c {
 				case gurps.BlockLayoutReactionsKey:
 					if p.entity != nil {
-						addRowPanel(rowPanel, NewReactionsPageList(p.entity), gurps.BlockLayoutReactionsKey, startAt)
+						addRowPanel(rowPanel, NewReactionsPageList(p.entity), c, blockLayout.MinimumEntries(c), startAt)
 					}
 				case gurps.BlockLayoutConditionalModifiersKey:
 					if p.entity != nil {
-						addRowPanel(rowPanel, NewConditionalModifiersPageList(p.entity),
-							gurps.BlockLayoutConditionalModifiersKey, startAt)
+						addRowPanel(rowPanel, NewConditionalModifiersPageList(p.entity), c,
+							blockLayout.MinimumEntries(c), startAt)
+					}
+				case gurps.BlockLayoutReactionSummaryKey:
+					if p.entity != nil {
+						addReactionSummaryRowPanel(rowPanel, NewReactionSummaryPanel(p.entity), startAt)
+					}
+				case gurps.BlockLayoutPoolTrackersKey:
+					if p.entity != nil {
+						addPoolTrackersRowPanel(rowPanel, NewPoolTrackersPanel(p.entity, p.targetMgr), startAt)
+					}
+				case gurps.BlockLayoutWoundsKey:
+					if p.entity != nil {
+						addWoundsRowPanel(rowPanel, NewWoundsPanel(p.entity, false), startAt)
+					}
+				case gurps.BlockLayoutConditionsKey:
+					if p.entity != nil {
+						addConditionsRowPanel(rowPanel, NewConditionsPanel(p.entity, false), startAt)
+					}
+				case gurps.BlockLayoutGrapplingKey:
+					if p.entity != nil {
+						addGrapplingRowPanel(rowPanel, NewGrapplingPanel(p.entity, p.targetMgr), startAt)
+					}
+				case gurps.BlockLayoutFatigueKey:
+					if p.entity != nil {
+						addFatigueRowPanel(rowPanel, NewFatiguePanel(p.entity, false), startAt)
 					}
 				case gurps.BlockLayoutMeleeKey:
 					if p.entity != nil {
-						addRowPanel(rowPanel, NewMeleeWeaponsPageList(p.entity), gurps.BlockLayoutMeleeKey, startAt)
+						addRowPanel(rowPanel, NewMeleeWeaponsPageList(p.entity), c, blockLayout.MinimumEntries(c),
+							startAt)
 					}
 				case gurps.BlockLayoutRangedKey:
 					if p.entity != nil {
-						addRowPanel(rowPanel, NewRangedWeaponsPageList(p.entity), gurps.BlockLayoutRangedKey, startAt)
+						addRowPanel(rowPanel, NewRangedWeaponsPageList(p.entity), c, blockLayout.MinimumEntries(c),
+							startAt)
 					}
 				case gurps.BlockLayoutTraitsKey:
-					addRowPanel(rowPanel, NewTraitsPageList(p, provider), gurps.BlockLayoutTraitsKey, startAt)
+					addRowPanel(rowPanel, NewTraitsPageList(p, provider), c, blockLayout.MinimumEntries(c), startAt)
 				case gurps.BlockLayoutSkillsKey:
-					addRowPanel(rowPanel, NewSkillsPageList(p, provider), gurps.BlockLayoutSkillsKey, startAt)
+					addColumnsRowPanel(rowPanel, blockLayout.Columns(c),
+						func() *PageList[*gurps.Skill] { return NewSkillsPageList(p, provider) }, c,
+						blockLayout.MinimumEntries(c), startAt)
 				case gurps.BlockLayoutSpellsKey:
-					addRowPanel(rowPanel, NewSpellsPageList(p, provider), gurps.BlockLayoutSpellsKey, startAt)
+					addColumnsRowPanel(rowPanel, blockLayout.Columns(c),
+						func() *PageList[*gurps.Spell] { return NewSpellsPageList(p, provider) }, c,
+						blockLayout.MinimumEntries(c), startAt)
+				case gurps.BlockLayoutGrimoireKey:
+					if p.entity != nil {
+						addGrimoireRowPanel(rowPanel, NewGrimoirePanel(p.entity, false), startAt)
+					}
 				case gurps.BlockLayoutEquipmentKey:
-					addRowPanel(rowPanel, NewCarriedEquipmentPageList(p, provider), gurps.BlockLayoutEquipmentKey,
-						startAt)
+					addColumnsRowPanel(rowPanel, blockLayout.Columns(c),
+						func() *PageList[*gurps.Equipment] { return NewCarriedEquipmentPageList(p, provider) }, c,
+						blockLayout.MinimumEntries(c), startAt)
 				case gurps.BlockLayoutOtherEquipmentKey:
-					addRowPanel(rowPanel, NewOtherEquipmentPageList(p, provider), gurps.BlockLayoutOtherEquipmentKey,
-						startAt)
+					addColumnsRowPanel(rowPanel, blockLayout.Columns(c),
+						func() *PageList[*gurps.Equipment] { return NewOtherEquipmentPageList(p, provider) }, c,
+						blockLayout.MinimumEntries(c), startAt)
 				case gurps.BlockLayoutNotesKey:
-					addRowPanel(rowPanel, NewNotesPageList(p, provider), gurps.BlockLayoutNotesKey, startAt)
+					addRowPanel(rowPanel, NewNotesPageList(p, provider), c, blockLayout.MinimumEntries(c), startAt)
+				default:
+					if p.entity != nil && gurps.IsCustomBlockKey(c) {
+						addCustomBlockRowPanel(rowPanel, NewCustomBlockPanel(p.entity, c, nil), startAt)
+					}
 				}
 			}
 			children := rowPanel.Children()
@@ -192,6 +257,14 @@ func newPageExporter(provider gurps.PageInfoProvider) *pageExporter {
 				HAlign:       align.Fill,
 				EqualColumns: true,
 			})
+			if breakBefore {
+				breakBefore = false
+				if len(page.Children()) != 0 {
+					page = NewPage(p.provider)
+					p.AddChild(page)
+					p.pages = append(p.pages, page)
+				}
+			}
 			page.AddChild(rowPanel)
 			page.SetFrameRect(r)
 			page.MarkForLayoutRecursively()
@@ -201,7 +274,7 @@ func newPageExporter(provider gurps.PageInfoProvider) *pageExporter {
 			if excess <= 0 {
 				break // Not extending off the page, so move to the next row
 			}
-			remaining := (pageSize.Height - page.insets().Bottom) - rowPanel.FrameRect().Y
+			remaining := (pageSize.Height/page.Scale().Y - page.insets().Bottom) - rowPanel.FrameRect().Y
 			startNewPage := false
 			data := make([]*pageState, len(children))
 			for i, child := range children {
@@ -209,9 +282,15 @@ func newPageExporter(provider gurps.PageInfoProvider) *pageExporter {
 				if remaining < data[i].minimum {
 					startNewPage = true
 				}
+				if remaining < data[i].needed && page.IndexOfChild(rowPanel) > 0 &&
+					blockLayout.KeepTogether(data[i].key()) {
+					// The block asked not to be split and there is a fresh page it may fit on
+					startNewPage = true
+				}
 			}
 			if startNewPage {
-				// At least one of the columns can't fit at least the header plus the next row, so start a new page
+				// At least one of the columns can't fit at least the header plus the next row, or a block that is to
+				// be kept together doesn't fit, so start a new page
 				page.RemoveChild(rowPanel)
 				page = NewPage(p.provider)
 				p.AddChild(page)
@@ -224,10 +303,15 @@ func newPageExporter(provider gurps.PageInfoProvider) *pageExporter {
 				if excess = pref.Height - pageSize.Height; excess <= 0 {
 					break // Not extending off the page, so move to the next row
 				}
-				remaining = (pageSize.Height - page.insets().Bottom) - rowPanel.FrameRect().Y
+				remaining = (pageSize.Height/page.Scale().Y - page.insets().Bottom) - rowPanel.FrameRect().Y
 			}
 			startNewPage = false
+			columns := make(map[string][]*pageState)
 			for _, one := range data {
+				if blockLayout.Columns(one.key()) > 1 {
+					columns[one.key()] = append(columns[one.key()], one)
+					continue
+				}
 				allowed := remaining - one.overhead
 				start, endBefore := one.helper.CurrentDrawRowRange()
 				startAt[one.key()] = len(one.heights) // Assume all remaining fit
@@ -250,6 +334,12 @@ func newPageExporter(provider gurps.PageInfoProvider) *pageExporter {
 					}
 				}
 			}
+			for key, list := range columns {
+				var overflowed bool
+				if startAt[key], overflowed = flowColumns(list, remaining); overflowed {
+					startNewPage = true
+				}
+			}
 			if startNewPage {
 				// We've filled the page, so add another
 				page = NewPage(p.provider)
@@ -280,6 +370,7 @@ type pageState struct {
 	current  float32
 	overhead float32
 	minimum  float32
+	needed   float32
 	heights  []float32
 }
 
@@ -297,10 +388,14 @@ func newPageState(child unison.Paneler) *pageState {
 		heights:  helper.RowHeights(),
 	}
 	state.minimum = state.overhead
-	start, _ := state.helper.CurrentDrawRowRange()
+	start, endBefore := state.helper.CurrentDrawRowRange()
 	if len(state.heights) > start {
 		state.minimum += state.heights[start] + 1
 	}
+	state.needed = state.overhead
+	for i := start; i < min(endBefore, len(state.heights)); i++ {
+		state.needed += state.heights[i] + 1
+	}
 	return state
 }
 
@@ -311,16 +406,139 @@ func (s *pageState) key() string {
 	return ""
 }
 
-func addRowPanel[T gurps.NodeTypes](rowPanel *unison.Panel, list *PageList[T], key string, startAtMap map[string]int) {
+// addRowPanel adds the list to the row panel, provided it has rows remaining to be shown. A list that hasn't been
+// started yet is also skipped if it has fewer than the minimum number of entries.
+func addRowPanel[T gurps.NodeTypes](rowPanel *unison.Panel, list *PageList[T], key string, minimum int, startAtMap map[string]int) {
 	list.ClientData()[pageKey] = key
 	count := list.RowCount()
 	startAt := startAtMap[key]
-	if count > startAt {
+	if count > startAt && (startAt != 0 || list.Table.RootRowCount() >= minimum) {
 		list.SetDrawRowRange(startAt, count)
 		rowPanel.AddChild(list)
 	}
 }
 
+// addColumnsRowPanel adds the list created by the create function to the row panel, split into the given number of
+// columns. The remaining rows are divided evenly between the columns, which is what will be shown if they all fit on
+// the page. If they don't, flowColumns() will adjust them.
+func addColumnsRowPanel[T gurps.NodeTypes](rowPanel *unison.Panel, columns int, create func() *PageList[T], key string, minimum int, startAtMap map[string]int) {
+	list := create()
+	if columns < 2 {
+		addRowPanel(rowPanel, list, key, minimum, startAtMap)
+		return
+	}
+	count := list.RowCount()
+	start := startAtMap[key]
+	if start == 0 && list.Table.RootRowCount() < minimum {
+		return
+	}
+	perColumn := (count - start + columns - 1) / columns
+	for i := 0; i < columns && start < count; i++ {
+		if i != 0 {
+			list = create()
+		}
+		list.ClientData()[pageKey] = key
+		end := min(start+perColumn, count)
+		list.SetDrawRowRange(start, end)
+		rowPanel.AddChild(list)
+		start = end
+	}
+}
+
+// flowColumns fills the columns of a multi-column block in order, each picking up where the prior one left off, so
+// that as many rows as possible fit into the remaining height. If the evenly divided rows already fit, they are left
+// alone. Returns the row the next page should start at and whether there were rows that didn't fit.
+func flowColumns(columns []*pageState, remaining float32) (next int, overflowed bool) {
+	total := len(columns[0].heights)
+	fits := true
+	for _, one := range columns {
+		allowed := remaining - one.overhead
+		start, endBefore := one.helper.CurrentDrawRowRange()
+		for i := start; i < endBefore && fits; i++ {
+			allowed -= one.heights[i] + 1
+			fits = allowed >= 0
+		}
+	}
+	if fits {
+		_, endBefore := columns[len(columns)-1].helper.CurrentDrawRowRange()
+		return endBefore, endBefore < total
+	}
+	next, _ = columns[0].helper.CurrentDrawRowRange()
+	for _, one := range columns {
+		allowed := remaining - one.overhead
+		end := next
+		for end < total {
+			if allowed -= one.heights[end] + 1; allowed < 0 {
+				break
+			}
+			end++
+		}
+		if end == next && end < total {
+			// As with single column blocks, a row too large to fit on its own is allowed to flow off the end
+			end++
+		}
+		one.helper.SetDrawRowRange(next, end)
+		next = end
+	}
+	return next, next < total
+}
+
+func addPoolTrackersRowPanel(rowPanel *unison.Panel, panel *PoolTrackersPanel, startAtMap map[string]int) {
+	panel.ClientData()[pageKey] = gurps.BlockLayoutPoolTrackersKey
+	if panel.HasTrackers() && startAtMap[gurps.BlockLayoutPoolTrackersKey] == 0 {
+		rowPanel.AddChild(panel)
+	}
+}
+
+func addWoundsRowPanel(rowPanel *unison.Panel, panel *WoundsPanel, startAtMap map[string]int) {
+	panel.ClientData()[pageKey] = gurps.BlockLayoutWoundsKey
+	if panel.HasWounds() && startAtMap[gurps.BlockLayoutWoundsKey] == 0 {
+		rowPanel.AddChild(panel)
+	}
+}
+
+func addConditionsRowPanel(rowPanel *unison.Panel, panel *ConditionsPanel, startAtMap map[string]int) {
+	panel.ClientData()[pageKey] = gurps.BlockLayoutConditionsKey
+	if panel.HasConditions() && startAtMap[gurps.BlockLayoutConditionsKey] == 0 {
+		rowPanel.AddChild(panel)
+	}
+}
+
+func addGrapplingRowPanel(rowPanel *unison.Panel, panel *GrapplingPanel, startAtMap map[string]int) {
+	panel.ClientData()[pageKey] = gurps.BlockLayoutGrapplingKey
+	if panel.Enabled() && startAtMap[gurps.BlockLayoutGrapplingKey] == 0 {
+		rowPanel.AddChild(panel)
+	}
+}
+
+func addFatigueRowPanel(rowPanel *unison.Panel, panel *FatiguePanel, startAtMap map[string]int) {
+	panel.ClientData()[pageKey] = gurps.BlockLayoutFatigueKey
+	if panel.HasExpenditures() && startAtMap[gurps.BlockLayoutFatigueKey] == 0 {
+		rowPanel.AddChild(panel)
+	}
+}
+
+func addReactionSummaryRowPanel(rowPanel *unison.Panel, panel *ReactionSummaryPanel, startAtMap map[string]int) {
+	panel.ClientData()[pageKey] = gurps.BlockLayoutReactionSummaryKey
+	if panel.HasReactions() && startAtMap[gurps.BlockLayoutReactionSummaryKey] == 0 {
+		rowPanel.AddChild(panel)
+	}
+}
+
+func addCustomBlockRowPanel(rowPanel *unison.Panel, panel *CustomBlockPanel, startAtMap map[string]int) {
+	panel.ClientData()[pageKey] = panel.key
+	if panel.HasContent() && startAtMap[panel.key] == 0 {
+		rowPanel.AddChild(panel)
+	}
+}
+
+func addGrimoireRowPanel(rowPanel *unison.Panel, panel *GrimoirePanel, startAtMap map[string]int) {
+	panel.ClientData()[pageKey] = gurps.BlockLayoutGrimoireKey
+	if panel.Enabled() && startAtMap[gurps.BlockLayoutGrimoireKey] == 0 {
+		rowPanel.AddChild(panel)
+	}
+}
+
 func (p *pageExporter) exportAsPDFBytes() ([]byte, error) {
 	stream := unison.NewMemoryStream()
 	defer stream.Close()
@@ -331,20 +549,37 @@ func (p *pageExporter) exportAsPDFBytes() ([]byte, error) {
 }
 
 func (p *pageExporter) exportAsPDFFile(filePath string) error {
-	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
-		return errs.Wrap(err)
-	}
-	stream, err := unison.NewFileStream(filePath)
+	finish, err := p.renderPDFFile(filePath)
 	if err != nil {
 		return err
 	}
-	defer stream.Close()
-	return p.exportAsPDF(stream)
+	return finish()
+}
+
+// renderPDFFile renders the pages, returning a function that finishes the PDF and writes it to the file. Only the
+// rendering needs to be done on the UI thread, so the returned function may be called from any goroutine.
+func (p *pageExporter) renderPDFFile(filePath string) (finish func() error, err error) {
+	if err = os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, errs.Wrap(err)
+	}
+	var data []byte
+	if data, err = p.exportAsPDFBytes(); err != nil {
+		return nil, err
+	}
+	additions := p.pdfAdditions()
+	return func() error {
+		result, addErr := pdfform.Add(data, additions)
+		if addErr != nil {
+			return addErr
+		}
+		return errs.Wrap(os.WriteFile(filePath, result, 0o640))
+	}, nil
 }
 
 func (p *pageExporter) exportAsPDF(stream unison.Stream) error {
-	savedColorMode := p.saveTheme()
-	defer p.restoreTheme(savedColorMode)
+	savedColorMode := saveTheme()
+	defer restoreTheme(savedColorMode)
+	defer p.applyTheme()()
 	title := p.provider.PageTitle()
 	return unison.CreatePDF(stream, &unison.PDFMetaData{
 		Title:           title,
@@ -375,16 +610,35 @@ func (p *pageExporter) exportAsJPEGs(filePathBase string) error {
 	})
 }
 
+// exportAsSVGs exports each page as an SVG file. Since the page rendering code can only produce raster output, each SVG
+// holds the page rasterized at the image export resolution, sized to the physical dimensions of the page.
+func (p *pageExporter) exportAsSVGs(filePathBase string) error {
+	size := p.PageSize()
+	return p.exportAsImages(filePathBase, ".svg", func(img *unison.Image) ([]byte, error) {
+		data, err := img.ToPNG(6)
+		if err != nil {
+			return nil, err
+		}
+		var buffer bytes.Buffer
+		fmt.Fprintf(&buffer, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%[1]gpt" height="%[2]gpt" viewBox="0 0 %[1]g %[2]g">
+<image width="%[1]g" height="%[2]g" href="data:image/png;base64,%[3]s"/>
+</svg>
+`, size.Width, size.Height, base64.StdEncoding.EncodeToString(data))
+		return buffer.Bytes(), nil
+	})
+}
+
 func (p *pageExporter) exportAsImages(filePathBase, extension string, f func(img *unison.Image) ([]byte, error)) error {
 	filePathBase = strings.TrimSuffix(filePathBase, extension)
-	savedColorMode := p.saveTheme()
-	defer p.restoreTheme(savedColorMode)
-	resolution := gurps.GlobalSettings().General.ImageResolution
+	savedColorMode := saveTheme()
+	defer restoreTheme(savedColorMode)
+	defer p.applyTheme()()
 	pageNumber := 1
 	for p.HasPage(pageNumber) {
 		size := p.PageSize()
 		var drawErr error
-		img, err := unison.NewImageFromDrawing(int(size.Width), int(size.Height), resolution, func(c *unison.Canvas) {
+		img, err := unison.NewImageFromDrawing(int(size.Width), int(size.Height), p.resolution, func(c *unison.Canvas) {
 			drawErr = p.DrawPage(c, pageNumber)
 		})
 		if err != nil {
@@ -405,7 +659,8 @@ func (p *pageExporter) exportAsImages(filePathBase, extension string, f func(img
 	return nil
 }
 
-func (p *pageExporter) saveTheme() thememode.Enum {
+// saveTheme switches to the light theme for exporting and returns the theme mode that was in use.
+func saveTheme() thememode.Enum {
 	savedColorMode := unison.CurrentThemeMode()
 	unison.SetThemeMode(thememode.Light)
 	unison.ThemeChanged()
@@ -413,12 +668,30 @@ func (p *pageExporter) saveTheme() thememode.Enum {
 	return savedColorMode
 }
 
-func (p *pageExporter) restoreTheme(colorMode thememode.Enum) {
+// restoreTheme restores the theme mode after exporting.
+func restoreTheme(colorMode thememode.Enum) {
 	unison.SetThemeMode(colorMode)
 	unison.ThemeChanged()
 	unison.RebuildDynamicColors()
 }
 
+// applyTheme applies the fonts suited to the sheet's language along with the colors and fonts of the sheet's theme, if
+// any, and returns a function that restores the previous ones. Fonts chosen by the theme take precedence.
+func (p *pageExporter) applyTheme() (restore func()) {
+	restoreLanguage := func() {}
+	if entity, ok := p.provider.(*gurps.Entity); ok {
+		restoreLanguage = entity.SheetSettings.ApplyLanguageFonts()
+	}
+	if p.theme == nil {
+		return restoreLanguage
+	}
+	restoreTheme := p.theme.Apply()
+	return func() {
+		restoreTheme()
+		restoreLanguage()
+	}
+}
+
 // HasPage implements unison.PageProvider.
 func (p *pageExporter) HasPage(pageNumber int) bool {
 	p.currentPage = pageNumber
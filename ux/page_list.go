@@ -10,10 +10,13 @@
 package ux
 
 import (
+	"slices"
+
 	"github.com/richardwilkes/gcs/v5/model/colors"
 	"github.com/richardwilkes/gcs/v5/model/fonts"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/cell"
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/tid"
@@ -29,9 +32,10 @@ var (
 // PageList holds a list for a sheet page.
 type PageList[T gurps.NodeTypes] struct {
 	unison.Panel
-	tableHeader *unison.TableHeader[*Node[T]]
-	Table       *unison.Table[*Node[T]]
-	provider    TableProvider[T]
+	tableHeader    *unison.TableHeader[*Node[T]]
+	Table          *unison.Table[*Node[T]]
+	provider       TableProvider[T]
+	defaultColumns []unison.ColumnInfo
 }
 
 // NewTraitsPageList creates the traits page list.
@@ -40,6 +44,9 @@ func NewTraitsPageList(owner Rebuildable, provider gurps.ListProvider) *PageList
 	p.installToggleDisabledHandler(owner)
 	p.installIncrementLevelHandler(owner)
 	p.installDecrementLevelHandler(owner)
+	p.InstallCmdHandlers(ApplyTraitModifierItemID,
+		func(_ any) bool { return canApplyTraitModifier(p.Table) },
+		func(_ any) { applyTraitModifier(owner, p.Table) })
 	InstallTintFunc(p, colors.TintTraits)
 	return p
 }
@@ -76,6 +83,9 @@ func NewSkillsPageList(owner Rebuildable, provider gurps.ListProvider) *PageList
 	p.installDecrementPointsHandler(owner)
 	p.installIncrementSkillHandler(owner)
 	p.installDecrementSkillHandler(owner)
+	p.InstallCmdHandlers(OptimizeSkillLevelsItemID,
+		func(_ any) bool { return canOptimizeSkillLevels(p.Table) },
+		func(_ any) { ShowSkillOptimizer(owner, p.Table) })
 	p.installIncrementTechLevelHandler(owner)
 	p.installDecrementTechLevelHandler(owner)
 	InstallTintFunc(p, colors.TintSkills)
@@ -126,6 +136,12 @@ func NewMeleeWeaponsPageList(entity *gurps.Entity) *PageList[*gurps.Weapon] {
 func NewRangedWeaponsPageList(entity *gurps.Entity) *PageList[*gurps.Weapon] {
 	p := newPageList(nil, NewWeaponsProvider(entity, false, true))
 	InstallTintFunc(p, colors.TintRanged)
+	p.InstallCmdHandlers(UseAmmoItemID,
+		func(_ any) bool { return canUseAmmo(p.Table) },
+		func(_ any) { useAmmo(p, p.Table) })
+	p.InstallCmdHandlers(ReloadWeaponItemID,
+		func(_ any) bool { return canReloadWeapon(p.Table) },
+		func(_ any) { reloadWeapon(p, p.Table) })
 	return p
 }
 
@@ -134,15 +150,20 @@ func newPageList[T gurps.NodeTypes](owner Rebuildable, provider TableProvider[T]
 	table.ClientData()[WorkingDirKey] = WorkingDirProvider(owner)
 	table.RefKey = provider.RefKey()
 	p := &PageList[T]{
-		tableHeader: header,
-		Table:       table,
-		provider:    provider,
+		tableHeader:    header,
+		Table:          table,
+		provider:       provider,
+		defaultColumns: slices.Clone(table.Columns),
 	}
 	p.Self = p
 	p.SetLayout(&unison.FlexLayout{Columns: 1})
 	p.SetBorder(unison.NewLineBorder(header.BackgroundInk, geom.Size{}, geom.NewUniformInsets(1), false))
 
 	p.Table.PreventUserColumnResize = true
+	if c, ok := provider.(columnCustomizer); ok {
+		installColumnChooser(header, c)
+		p.applyColumnWidths()
+	}
 	p.Table.SyncToModel()
 	p.AddChild(p.tableHeader)
 	p.AddChild(p.Table)
@@ -160,6 +181,9 @@ func newPageList[T gurps.NodeTypes](owner Rebuildable, provider TableProvider[T]
 		table.InstallCmdHandlers(SyncWithSourceItemID,
 			func(_ any) bool { return HasSelectionAndNotFiltered(p.Table) },
 			func(_ any) { SyncWithSourceForSelection(p.Table) })
+		table.InstallCmdHandlers(ReviewSourceChangesItemID,
+			func(_ any) bool { return CanReviewSourceChangesForSelection(p.Table) },
+			func(_ any) { ReviewSourceChangesForSelection(p.Table) })
 		table.InstallCmdHandlers(ClearSourceItemID,
 			func(_ any) bool { return HasSelectionAndNotFiltered(p.Table) },
 			func(_ any) { ClearSourceFromSelection(p.Table) })
@@ -229,7 +253,7 @@ func moveSelectedEquipment(from, to *unison.Table[*Node[*gurps.Equipment]]) {
 	CopyRowsTo(to, from.SelectedRows(true), nil, false)
 	DeleteSelection(from, false)
 	undo.AfterData = NewTableDragUndoEditData(from, to)
-	mgr.Add(undo)
+	addUndo(mgr, undo)
 }
 
 func (p *PageList[T]) installOpenPageReferenceHandlers() {
@@ -316,6 +340,9 @@ func installEquipmentLevelHandlers(p *PageList[*gurps.Equipment], owner Rebuilda
 	p.InstallCmdHandlers(DecrementEquipmentLevelItemID,
 		func(_ any) bool { return canAdjustEquipmentLevel(p.Table, -fxp.One) },
 		func(_ any) { adjustEquipmentLevel(owner, p.Table, -fxp.One) })
+	p.InstallCmdHandlers(ApplyEquipmentModifierItemID,
+		func(_ any) bool { return canApplyEquipmentModifier(p.Table) },
+		func(_ any) { applyEquipmentModifier(owner, p.Table) })
 }
 
 func (p *PageList[T]) installContainerConversionHandlers(owner Rebuildable) {
@@ -403,6 +430,7 @@ func (p *PageList[T]) ApplyNoteState(closed bool) {
 func (p *PageList[T]) Sync() {
 	p.provider.SyncHeader(p.tableHeader.ColumnHeaders)
 	selection := p.RecordSelection()
+	p.applyColumnWidths()
 	p.Table.SyncToModel()
 	p.ApplySelection(selection)
 	p.Table.NeedsLayout = true
@@ -443,3 +471,28 @@ func (p *PageList[T]) CurrentDrawRowRange() (start, endBefore int) {
 func (p *PageList[T]) SetDrawRowRange(start, endBefore int) {
 	p.Table.SetDrawRowRange(start, endBefore)
 }
+
+// pageRefCells returns the first page reference of each page reference cell within the row range that will be drawn,
+// along with the cell's rectangle in root coordinates.
+func (p *PageList[T]) pageRefCells() []pageRefCell {
+	start, endBefore := p.Table.CurrentDrawRowRange()
+	// The rows prior to the start aren't drawn, so the remaining rows shift up to take their place
+	offset := p.Table.RowFrame(start).Y - p.Table.RowFrame(0).Y
+	var cells []pageRefCell
+	for row := start; row < endBefore; row++ {
+		node := gurps.AsNode(p.Table.RowFromIndex(row).Data())
+		for col := range p.Table.Columns {
+			var data gurps.CellData
+			node.CellData(p.Table.Columns[col].ID, &data)
+			if data.Type != cell.PageRef {
+				continue
+			}
+			if refs := gurps.ExtractPageReferences(data.Primary); len(refs) != 0 {
+				r := p.Table.CellFrame(row, col)
+				r.Y -= offset
+				cells = append(cells, pageRefCell{rect: p.Table.RectToRoot(r), ref: refs[0]})
+			}
+		}
+	}
+	return cells
+}
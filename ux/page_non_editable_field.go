@@ -11,6 +11,7 @@ package ux
 
 import (
 	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/textlayout"
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/align"
@@ -61,6 +62,11 @@ func newNonEditablePageField(syncer func(*NonEditablePageField), hAlign align.En
 	return f
 }
 
+// SetTitle sets the text of the field, putting it into visual order so that right-to-left scripts are drawn correctly.
+func (f *NonEditablePageField) SetTitle(text string) {
+	f.Label.SetTitle(textlayout.Visual(text))
+}
+
 // Sync the field to the current value.
 func (f *NonEditablePageField) Sync() {
 	f.syncer(f)
@@ -0,0 +1,165 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/pdfform"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison"
+)
+
+const (
+	// pdfFormFieldKey is the client data key used to mark a page field that should become a fillable field when
+	// exporting to PDF. The value is the name to give the form field.
+	pdfFormFieldKey       = "pdf_form_field"
+	pdfNotesFieldName     = "notes"
+	pdfNotesMinimumHeight = 72
+	pdfNotesGap           = 8
+)
+
+// markAsPDFFormField marks the field as one that should become a fillable field when exporting to PDF.
+func markAsPDFFormField(field *unison.Field, name string) {
+	field.ClientData()[pdfFormFieldKey] = name
+}
+
+type pageRefCell struct {
+	rect geom.Rect
+	ref  string
+}
+
+type pageRefCellProvider interface {
+	pageRefCells() []pageRefCell
+}
+
+// pdfAdditions returns the bookmarks, links, and, if enabled, the fillable fields to add to an exported PDF.
+func (p *pageExporter) pdfAdditions() *pdfform.Additions {
+	additions := &pdfform.Additions{
+		Bookmarks: p.pdfBookmarks(),
+		Links:     p.pdfLinks(),
+	}
+	if p.entity != nil && gurps.GlobalSettings().General.FillablePDFExport {
+		additions.Fields = p.pdfFormFields()
+	}
+	return additions
+}
+
+// pdfBookmarks returns a bookmark for the first appearance of each block in the pages.
+func (p *pageExporter) pdfBookmarks() []*pdfform.Bookmark {
+	sheetSettings := gurps.SheetSettingsFor(p.entity)
+	seen := make(map[string]bool)
+	var bookmarks []*pdfform.Bookmark
+	for i, page := range p.pages {
+		origin := page.PointToRoot(geom.Point{})
+		for _, row := range page.Children() {
+			for _, block := range row.Children() {
+				if key, ok := block.ClientData()[pageKey].(string); ok && !seen[key] {
+					seen[key] = true
+					bookmarks = append(bookmarks, &pdfform.Bookmark{
+						Title: sheetSettings.BlockTitle(key),
+						Page:  i,
+						Y:     block.PointToRoot(geom.Point{}).Y - origin.Y,
+					})
+				}
+			}
+		}
+	}
+	return bookmarks
+}
+
+// pdfLinks returns a link for each page reference that can be resolved to something outside of the application.
+func (p *pageExporter) pdfLinks() []*pdfform.Link {
+	var links []*pdfform.Link
+	for i, page := range p.pages {
+		links = collectPDFLinks(page.AsPanel(), i, page.PointToRoot(geom.Point{}), links)
+	}
+	return links
+}
+
+func collectPDFLinks(panel *unison.Panel, index int, origin geom.Point, links []*pdfform.Link) []*pdfform.Link {
+	if provider, ok := panel.Self.(pageRefCellProvider); ok {
+		for _, one := range provider.pageRefCells() {
+			if uri := pageReferenceURI(one.ref); uri != "" {
+				r := one.rect
+				r.Point = r.Point.Sub(origin)
+				links = append(links, &pdfform.Link{
+					URI:  uri,
+					Rect: r,
+					Page: index,
+				})
+			}
+		}
+		return links
+	}
+	for _, child := range panel.Children() {
+		links = collectPDFLinks(child, index, origin, links)
+	}
+	return links
+}
+
+func (p *pageExporter) pdfFormFields() []*pdfform.Field {
+	var fields []*pdfform.Field
+	for i, page := range p.pages {
+		origin := page.PointToRoot(geom.Point{})
+		fields = collectPDFFormFields(page.AsPanel(), i, origin, fields)
+	}
+	if len(p.pages) != 0 {
+		if f := p.pdfNotesField(len(p.pages) - 1); f != nil {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func collectPDFFormFields(panel *unison.Panel, index int, origin geom.Point, fields []*pdfform.Field) []*pdfform.Field {
+	if name, ok := panel.ClientData()[pdfFormFieldKey].(string); ok {
+		if field, ok2 := panel.Self.(interface{ Text() string }); ok2 {
+			r := panel.RectToRoot(panel.ContentRect(true))
+			r.Point = r.Point.Sub(origin)
+			return append(fields, &pdfform.Field{
+				Name:  name,
+				Value: field.Text(),
+				Rect:  r,
+				Page:  index,
+			})
+		}
+	}
+	for _, child := range panel.Children() {
+		fields = collectPDFFormFields(child, index, origin, fields)
+	}
+	return fields
+}
+
+// pdfNotesField returns a multi-line field covering the unused space at the bottom of the page, or nil if there isn't
+// enough room for one.
+func (p *pageExporter) pdfNotesField(pageIndex int) *pdfform.Field {
+	page := p.pages[pageIndex]
+	insets := page.insets()
+	r := page.ContentRect(true)
+	r.X += insets.Left
+	r.Width -= insets.Left + insets.Right
+	bottom := r.Bottom() - insets.Bottom
+	r.Y += insets.Top
+	for _, child := range page.Children() {
+		r.Y = max(r.Y, child.FrameRect().Bottom()+pdfNotesGap)
+	}
+	r.Height = bottom - r.Y
+	r = page.RectToRoot(r)
+	if r.Height < pdfNotesMinimumHeight || r.Width <= 0 {
+		return nil
+	}
+	r.Point = r.Point.Sub(page.PointToRoot(geom.Point{}))
+	return &pdfform.Field{
+		Name:      pdfNotesFieldName,
+		Rect:      r,
+		Page:      pageIndex,
+		Multiline: true,
+	}
+}
@@ -19,7 +19,7 @@ func CanOpenPageRef[T gurps.NodeTypes](table *unison.Table[*Node[T]]) bool {
 	for _, row := range table.SelectedRows(false) {
 		var data gurps.CellData
 		gurps.AsNode(row.Data()).CellData(gurps.PageRefCellAlias, &data)
-		if len(ExtractPageReferences(data.Primary)) != 0 {
+		if len(gurps.ExtractPageReferences(data.Primary)) != 0 {
 			return true
 		}
 	}
@@ -32,7 +32,7 @@ func OpenPageRef[T gurps.NodeTypes](table *unison.Table[*Node[T]]) {
 	for _, row := range table.SelectedRows(false) {
 		var data gurps.CellData
 		gurps.AsNode(row.Data()).CellData(gurps.PageRefCellAlias, &data)
-		for _, one := range ExtractPageReferences(data.Primary) {
+		for _, one := range gurps.ExtractPageReferences(data.Primary) {
 			OpenPageReference(one, data.Secondary, promptCtx)
 			break
 		}
@@ -45,7 +45,7 @@ func OpenEachPageRef[T gurps.NodeTypes](table *unison.Table[*Node[T]]) {
 	for _, row := range table.SelectedRows(false) {
 		var data gurps.CellData
 		gurps.AsNode(row.Data()).CellData(gurps.PageRefCellAlias, &data)
-		for _, one := range ExtractPageReferences(data.Primary) {
+		for _, one := range gurps.ExtractPageReferences(data.Primary) {
 			if OpenPageReference(one, data.Secondary, promptCtx) {
 				return
 			}
@@ -0,0 +1,227 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+type pageRefMappingCandidate struct {
+	key    string
+	name   string
+	count  int
+	path   string
+	offset int
+	button *unison.Button
+}
+
+// assignPageRefMappings scans the libraries for page reference keys that have no mapping and lets the user assign PDFs
+// and page offsets to them all at once. If requestedKey isn't empty, it is included even if none of the libraries use
+// it. Returns the response code of the dialog, which will be unison.ModalResponseDiscard if the user chose to skip
+// the assignment.
+func assignPageRefMappings(requestedKey string) int {
+	global := gurps.GlobalSettings()
+	libs := global.LibrarySet.List()
+	dirs := make([]string, 0, len(libs))
+	for _, lib := range libs {
+		dirs = append(dirs, lib.Path())
+	}
+	counts := make(map[string]int)
+	for _, one := range gurps.ScanPageRefKeys(dirs...) {
+		counts[one.Key] = one.Count
+	}
+	if requestedKey != "" {
+		if _, exists := counts[requestedKey]; !exists {
+			counts[requestedKey] = 0
+		}
+	}
+	var candidates []*pageRefMappingCandidate
+	for _, one := range gurps.PageRefKeyUsageFromCounts(counts) {
+		if global.PageRefs.Lookup(one.Key) == nil {
+			candidates = append(candidates, &pageRefMappingCandidate{
+				key:   one.Key,
+				name:  PageRefKeyToName(one.Key),
+				count: one.Count,
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("Nothing to assign"),
+			i18n.Text("Every page reference key used within your libraries already has a mapping."))
+		return unison.ModalResponseCancel
+	}
+
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	header := unison.NewPanel()
+	header.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	header.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	if requestedKey != "" {
+		label := unison.NewLabel()
+		label.SetTitle(fmt.Sprintf(i18n.Text(`There is no valid mapping for page reference key "%s".`), requestedKey))
+		header.AddChild(label)
+	}
+	label := unison.NewLabel()
+	label.SetTitle(i18n.Text("Choose the PDFs to use for the page reference keys below."))
+	header.AddChild(label)
+	label = unison.NewLabel()
+	label.SetTitle(i18n.Text("Keys without a PDF will be left unmapped."))
+	header.AddChild(label)
+	content.AddChild(header)
+	grid := unison.NewPanel()
+	grid.SetLayout(&unison.FlexLayout{
+		Columns:  5,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	chooseButton := unison.NewButton()
+	chooseButton.SetTitle(i18n.Text("Choose PDFs…"))
+	chooseButton.Tooltip = newWrappedTooltip(i18n.Text(`Choose several PDFs at once. Each one will be assigned to the key whose book name appears in its file name.`))
+	chooseButton.ClickCallback = func() {
+		names := make(map[string]string)
+		for _, one := range candidates {
+			if one.name != "" {
+				names[one.key] = one.name
+			}
+		}
+		matches := gurps.MatchPDFsToPageRefKeys(choosePDFPaths(true), names)
+		for _, one := range candidates {
+			if p, ok := matches[one.key]; ok {
+				one.setPath(p)
+			}
+		}
+		grid.MarkForLayoutAndRedraw()
+	}
+	chooseButton.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.End,
+		VAlign: align.Middle,
+	})
+	content.AddChild(chooseButton)
+
+	for _, title := range []string{i18n.Text("Key"), i18n.Text("Uses"), i18n.Text("Book"), i18n.Text("Page Offset"),
+		i18n.Text("PDF")} {
+		columnHeader := unison.NewLabel()
+		columnHeader.Font = fonts.PageLabelSecondary
+		columnHeader.SetTitle(title)
+		grid.AddChild(columnHeader)
+	}
+	for _, one := range candidates {
+		one.addToGrid(grid)
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(grid, behavior.Fill, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:    2,
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		SizeHint: geom.Size{Width: 600, Height: 300},
+		HGrab:    true,
+		VGrab:    true,
+	})
+	content.AddChild(scroll)
+
+	buttons := []*unison.DialogButtonInfo{unison.NewCancelButtonInfo()}
+	if requestedKey != "" {
+		skipButton := unison.NewNoButtonInfo()
+		skipButton.Title = i18n.Text("Skip")
+		skipButton.KeyCodes = nil
+		buttons = append(buttons, skipButton)
+	}
+	buttons = append(buttons, unison.NewOKButtonInfoWithTitle(i18n.Text("Assign")))
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		content, buttons, unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return unison.ModalResponseCancel
+	}
+	result := dialog.RunModal()
+	if result == unison.ModalResponseOK {
+		for _, one := range candidates {
+			if one.path != "" {
+				global.PageRefs.Set(&gurps.PageRef{
+					ID:     one.key,
+					Path:   one.path,
+					Offset: one.offset,
+				})
+			}
+		}
+		RefreshPageRefMappingsView()
+	}
+	return result
+}
+
+func (c *pageRefMappingCandidate) addToGrid(grid *unison.Panel) {
+	key := unison.NewLabel()
+	key.SetTitle(c.key)
+	grid.AddChild(key)
+
+	count := unison.NewLabel()
+	count.HAlign = align.End
+	count.SetTitle(strconv.Itoa(c.count))
+	count.SetLayoutData(&unison.FlexLayoutData{HAlign: align.Fill})
+	grid.AddChild(count)
+
+	name := unison.NewLabel()
+	name.SetTitle(c.name)
+	grid.AddChild(name)
+
+	offset := NewIntegerField(nil, "", i18n.Text("Page Offset"),
+		func() int { return c.offset },
+		func(v int) { c.offset = v }, -9999, 9999, true, false)
+	grid.AddChild(offset)
+
+	c.button = unison.NewButton()
+	c.button.ClickCallback = func() {
+		if paths := choosePDFPaths(false); len(paths) != 0 {
+			c.setPath(paths[0])
+			grid.MarkForLayoutAndRedraw()
+		}
+	}
+	c.button.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	c.setPath(c.path)
+	grid.AddChild(c.button)
+}
+
+func (c *pageRefMappingCandidate) setPath(p string) {
+	c.path = p
+	if p == "" {
+		c.button.SetTitle(i18n.Text("Choose…"))
+		c.button.Tooltip = nil
+	} else {
+		c.button.SetTitle(filepath.Base(p))
+		c.button.Tooltip = newWrappedTooltip(p)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"net/url"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -34,19 +35,11 @@ type pageRefMappingsDockable struct {
 	content *unison.Panel
 }
 
-// ExtractPageReferences extracts any page references from the string.
-func ExtractPageReferences(s string) []string {
-	var list []string
-	for _, one := range strings.FieldsFunc(s, func(ch rune) bool { return ch == ',' || ch == ';' }) {
-		if one = strings.TrimSpace(one); one != "" {
-			list = append(list, one)
-		}
-	}
-	return list
-}
-
-// OpenPageReference opens the given page reference. Returns true if the the user asked to cancel further processing.
+// OpenPageReference opens the given page reference, which may be a PDF page reference, a URL, a markdown reference, or
+// a markdown link to either a URL or a markdown reference. Returns true if the the user asked to cancel further
+// processing.
 func OpenPageReference(ref, highlight string, promptContext map[string]bool) bool {
+	_, ref = gurps.ParsePageRefLink(ref)
 	switch {
 	case unison.HasURLPrefix(ref):
 		if err := xos.OpenBrowser(ref); err != nil {
@@ -91,50 +84,32 @@ func openPDFPageReference(ref, highlight string, promptContext map[string]bool)
 	if promptContext == nil {
 		promptContext = make(map[string]bool)
 	}
-	i := len(ref) - 1
-	for i >= 0 {
-		ch := ref[i]
-		if ch >= '0' && ch <= '9' {
-			i--
-		} else {
-			i++
-			break
-		}
-	}
-	if i > 0 {
-		page, err := strconv.Atoi(ref[i:])
+	if key, pageStr := gurps.SplitPageRef(ref); key != "" {
+		page, err := strconv.Atoi(pageStr)
 		if err != nil {
 			unison.ErrorDialogWithMessage(i18n.Text("Unable to open ")+ref, i18n.Text("Does it exist?"))
 			return false
 		}
-		key := ref[:i]
 		s := gurps.GlobalSettings()
 		pageRef := s.PageRefs.Lookup(key)
 		if pageRef == nil && !promptContext[key] {
-			pdfName := PageRefKeyToName(key)
-			if pdfName != "" {
-				pdfName = fmt.Sprintf(i18n.Text("\nThis key is normally mapped to a PDF named:\n%s"), pdfName)
-			}
-			switch unison.YesNoCancelDialog(fmt.Sprintf(i18n.Text(`There is no valid mapping for page reference key "%s".
-Would you like to create one by choosing a PDF to map to this key?`), key), pdfName) {
-			case unison.ModalResponseDiscard:
-				promptContext[key] = true
+			switch assignPageRefMappings(key) {
 			case unison.ModalResponseOK:
-				pageRef = askUserForPageRefPath(key, 0)
+				pageRef = s.PageRefs.Lookup(key)
 			case unison.ModalResponseCancel:
 				return true
 			}
+			if pageRef == nil {
+				promptContext[key] = true
+			}
 		}
 		if pageRef != nil {
 			if strings.TrimSpace(s.General.ExternalPDFCmdLine) == "" {
 				pageNum := page + pageRef.Offset - 1 // The pdf package uses 0 for the first page, not 1
-				if d, wasOpen := OpenFile(pageRef.Path, pageNum); d != nil {
+				if d, _ := OpenFile(pageRef.Path, pageNum); d != nil {
 					if pdfDockable, ok := d.(*PDFDockable); ok {
 						pdfDockable.SetSearchText(highlight)
 						pdfDockable.LoadPage(pageNum)
-						if !wasOpen {
-							pdfDockable.ClearHistory()
-						}
 					}
 				}
 			} else {
@@ -145,6 +120,40 @@ Would you like to create one by choosing a PDF to map to this key?`), key), pdfN
 	return false
 }
 
+// pageReferenceURI returns a URI that opens the page reference outside of the application, or an empty string if the
+// page reference can't be resolved without asking the user for a mapping.
+func pageReferenceURI(ref string) string {
+	_, ref = gurps.ParsePageRefLink(ref)
+	switch {
+	case unison.HasURLPrefix(ref):
+		return ref
+	case strings.HasPrefix(ref, "md:"):
+		return ""
+	}
+	key, pageStr := gurps.SplitPageRef(ref)
+	if key == "" {
+		return ""
+	}
+	page, err := strconv.Atoi(pageStr)
+	if err != nil {
+		return ""
+	}
+	pageRef := gurps.GlobalSettings().PageRefs.Lookup(key)
+	if pageRef == nil {
+		return ""
+	}
+	filePath := filepath.ToSlash(pageRef.Path)
+	if !strings.HasPrefix(filePath, "/") {
+		filePath = "/" + filePath // Windows paths need a leading slash in a file URI
+	}
+	u := url.URL{
+		Scheme:   "file",
+		Path:     filePath,
+		Fragment: "page=" + strconv.Itoa(page+pageRef.Offset),
+	}
+	return u.String()
+}
+
 func openExternalPDF(filePath string, pageNum int) {
 	errTitle := i18n.Text("Unable to use external PDF command line")
 	input := strings.TrimSpace(gurps.GlobalSettings().General.ExternalPDFCmdLine)
@@ -175,8 +184,24 @@ func openExternalPDF(filePath string, pageNum int) {
 }
 
 func askUserForPageRefPath(key string, offset int) *gurps.PageRef {
+	paths := choosePDFPaths(false)
+	if len(paths) == 0 {
+		return nil
+	}
+	pageRef := &gurps.PageRef{
+		ID:     key,
+		Path:   paths[0],
+		Offset: offset,
+	}
+	gurps.GlobalSettings().PageRefs.Set(pageRef)
+	RefreshPageRefMappingsView()
+	return pageRef
+}
+
+// choosePDFPaths asks the user to choose one or more PDFs. Returns nil if the user cancels.
+func choosePDFPaths(multiple bool) []string {
 	dialog := unison.NewOpenDialog()
-	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetAllowsMultipleSelection(multiple)
 	dialog.SetResolvesAliases(true)
 	dialog.SetAllowedExtensions("pdf")
 	dialog.SetCanChooseDirectories(false)
@@ -186,16 +211,11 @@ func askUserForPageRefPath(key string, offset int) *gurps.PageRef {
 	if !dialog.RunModal() {
 		return nil
 	}
-	p := dialog.Path()
-	global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(p))
-	pageRef := &gurps.PageRef{
-		ID:     key,
-		Path:   p,
-		Offset: offset,
+	paths := dialog.Paths()
+	if len(paths) != 0 {
+		global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(paths[0]))
 	}
-	gurps.GlobalSettings().PageRefs.Set(pageRef)
-	RefreshPageRefMappingsView()
-	return pageRef
+	return paths
 }
 
 // RefreshPageRefMappingsView causes the Page References Mappings view to be refreshed if it is open.
@@ -235,6 +255,11 @@ func (d *pageRefMappingsDockable) addToStartToolbar(toolbar *unison.Panel) {
 	helpButton.Tooltip = newWrappedTooltip(i18n.Text("Help"))
 	helpButton.ClickCallback = func() { HandleLink(nil, "md:User%20Guide/Page%20References") }
 	toolbar.AddChild(helpButton)
+
+	scanButton := unison.NewSVGButton(svg.MagicWand)
+	scanButton.Tooltip = newWrappedTooltip(i18n.Text("Scan libraries for unmapped page references"))
+	scanButton.ClickCallback = func() { assignPageRefMappings("") }
+	toolbar.AddChild(scanButton)
 }
 
 func (d *pageRefMappingsDockable) initContent(content *unison.Panel) {
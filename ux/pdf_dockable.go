@@ -26,6 +26,7 @@ import (
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/behavior"
 	"github.com/richardwilkes/unison/enums/blendmode"
+	"github.com/richardwilkes/unison/enums/check"
 	"github.com/richardwilkes/unison/enums/filtermode"
 	"github.com/richardwilkes/unison/enums/mipmapmode"
 	"github.com/richardwilkes/unison/enums/paintstyle"
@@ -61,6 +62,7 @@ type PDFDockable struct {
 	autoScalingPopup       *unison.PopupMenu[autoscale.Option]
 	searchField            *unison.Field
 	matchesLabel           *unison.Label
+	highlightCheckBox      *unison.CheckBox
 	sideBarButton          *unison.Button
 	backButton             *unison.Button
 	forwardButton          *unison.Button
@@ -71,13 +73,14 @@ type PDFDockable struct {
 	page                   *PDFPage
 	link                   *PDFLink
 	rolloverRect           geom.Rect
+	pendingHighlight       geom.Rect
 	scale                  int
-	historyPos             int
-	history                []int
 	dragStart              geom.Point
 	dragOrigin             geom.Point
+	highlightStart         geom.Point
 	autoScaling            autoscale.Option
 	inDrag                 bool
+	inHighlight            bool
 	noUpdate               bool
 	adjustTableSizePending bool
 	needDockableResize     bool
@@ -272,6 +275,12 @@ func (d *PDFDockable) createToolbar() *unison.Panel {
 	d.matchesLabel.Tooltip = newWrappedTooltip(i18n.Text("Number of matches found"))
 	second.AddChild(d.matchesLabel)
 
+	d.highlightCheckBox = unison.NewCheckBox()
+	d.highlightCheckBox.SetTitle(i18n.Text("Highlight"))
+	d.highlightCheckBox.Tooltip = newWrappedTooltip(i18n.Text(
+		`When checked, dragging over the page creates a highlight rather than scrolling, and clicking on a highlight edits its note. Right-click on a highlight for more options.`))
+	second.AddChild(d.highlightCheckBox)
+
 	second.SetLayout(&unison.FlexLayout{
 		Columns:  len(second.Children()),
 		HSpacing: unison.StdHSpacing,
@@ -342,6 +351,7 @@ func (d *PDFDockable) createContent() {
 	d.docPanel.MouseDragCallback = d.mouseDrag
 	d.docPanel.MouseUpCallback = d.mouseUp
 	d.docPanel.UpdateCursorCallback = d.updateCursor
+	d.docPanel.UpdateTooltipCallback = d.updateTooltip
 	d.docPanel.SetFocusable(true)
 
 	d.docScroll = unison.NewScrollPanel()
@@ -368,14 +378,6 @@ func (d *PDFDockable) createContent() {
 	})
 }
 
-// ClearHistory clears the existing history.
-func (d *PDFDockable) ClearHistory() {
-	d.history = nil
-	d.historyPos = 0
-	d.backButton.SetEnabled(false)
-	d.forwardButton.SetEnabled(false)
-}
-
 // SetSearchText sets the search text and updates the display.
 func (d *PDFDockable) SetSearchText(text string) {
 	d.searchField.SetText(text)
@@ -396,20 +398,19 @@ func (d *PDFDockable) toggleSideBar() {
 	}
 }
 
-// Back moves back in history one step.
+// Back moves back in history one step, which may be to a page in another PDF.
 func (d *PDFDockable) Back() {
-	if d.historyPos > 0 {
-		d.historyPos--
-		d.LoadPage(d.history[d.historyPos])
-	}
+	movePDFHistory(-1)
 }
 
-// Forward moves forward in history one step.
+// Forward moves forward in history one step, which may be to a page in another PDF.
 func (d *PDFDockable) Forward() {
-	if d.historyPos < len(d.history)-1 {
-		d.historyPos++
-		d.LoadPage(d.history[d.historyPos])
-	}
+	movePDFHistory(1)
+}
+
+func (d *PDFDockable) syncHistoryButtons() {
+	d.backButton.SetEnabled(canMovePDFHistory(-1))
+	d.forwardButton.SetEnabled(canMovePDFHistory(1))
 }
 
 // LoadPage loads the specified page.
@@ -456,23 +457,9 @@ func (d *PDFDockable) pageLoaded() {
 	}
 
 	pageNumber := d.page.PageNumber
-	if d.history == nil {
-		d.history = append(d.history, pageNumber)
-		d.historyPos = 0
-	} else if d.history[d.historyPos] != pageNumber {
-		d.historyPos++
-		if d.historyPos < len(d.history) {
-			if d.history[d.historyPos] != pageNumber {
-				d.history[d.historyPos] = pageNumber
-				d.history = d.history[:d.historyPos+1]
-			}
-		} else {
-			d.history = append(d.history, pageNumber)
-		}
-	}
+	recordPDFHistory(d.path, pageNumber)
+	d.syncHistoryButtons()
 	lastPageNumber := d.pdf.PageCount() - 1
-	d.backButton.SetEnabled(d.historyPos > 0)
-	d.forwardButton.SetEnabled(d.historyPos < len(d.history)-1)
 	d.firstPageButton.SetEnabled(pageNumber != 0)
 	d.previousPageButton.SetEnabled(pageNumber > 0)
 	d.nextPageButton.SetEnabled(pageNumber < lastPageNumber)
@@ -526,16 +513,32 @@ func (d *PDFDockable) updateCursor(pt geom.Point) *unison.Cursor {
 	return unison.ArrowCursor()
 }
 
-func (d *PDFDockable) mouseDown(where geom.Point, _, _ int, _ unison.Modifiers) bool {
+func (d *PDFDockable) mouseDown(where geom.Point, button, _ int, _ unison.Modifiers) bool {
+	d.docPanel.RequestFocus()
+	if button == unison.ButtonRight {
+		if highlight := d.highlightAt(where); highlight != nil {
+			d.showHighlightContextMenu(where, highlight)
+		}
+		return true
+	}
+	if d.highlightCheckBox.State == check.On {
+		d.highlightStart = where
+		d.inHighlight = true
+		return true
+	}
 	d.dragStart = d.docPanel.PointToRoot(where)
 	d.dragOrigin.X, d.dragOrigin.Y = d.docScroll.Position()
 	d.inDrag = !d.checkForLinkAt(where)
-	d.docPanel.RequestFocus()
 	d.UpdateCursorNow()
 	return true
 }
 
 func (d *PDFDockable) mouseDrag(where geom.Point, _ int, _ unison.Modifiers) bool {
+	if d.inHighlight {
+		d.pendingHighlight = d.highlightDragRect(where)
+		d.MarkForRedraw()
+		return true
+	}
 	if d.inDrag {
 		pt := d.dragStart.Sub(d.docPanel.PointToRoot(where)).Add(d.dragOrigin)
 		d.docScroll.SetPosition(pt.X, pt.Y)
@@ -551,6 +554,10 @@ func (d *PDFDockable) mouseMove(where geom.Point, _ unison.Modifiers) bool {
 }
 
 func (d *PDFDockable) mouseUp(where geom.Point, button int, _ unison.Modifiers) bool {
+	if d.inHighlight {
+		d.finishHighlight(where)
+		return true
+	}
 	if d.inDrag {
 		d.inDrag = false
 		d.UpdateCursorNow()
@@ -569,6 +576,7 @@ func (d *PDFDockable) mouseUp(where geom.Point, button int, _ unison.Modifiers)
 
 func (d *PDFDockable) focusChangeInHierarchy(_, _ *unison.Panel) {
 	d.pdf.RequestRenderPriority()
+	d.syncHistoryButtons()
 }
 
 func (d *PDFDockable) keyDown(keyCode unison.KeyCode, _ unison.Modifiers, _ bool) bool {
@@ -633,6 +641,7 @@ func (d *PDFDockable) draw(gc *unison.Canvas, dirty geom.Rect) {
 			FilterMode:     filtermode.Linear,
 			MipMapMode:     mipmapmode.Linear,
 		}, nil)
+		d.drawHighlights(gc)
 		if len(d.page.Matches) != 0 {
 			p := unison.NewPaint()
 			p.SetStyle(paintstyle.Fill)
@@ -0,0 +1,139 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/blendmode"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+const minPDFHighlightSize = 4
+
+var pdfHighlightColor = unison.RGB(255, 235, 90)
+
+func (d *PDFDockable) pageSize() geom.Size {
+	if d.page == nil || d.page.Image == nil {
+		return geom.Size{}
+	}
+	return d.page.Image.LogicalSize()
+}
+
+func (d *PDFDockable) highlightAt(where geom.Point) *gurps.PDFHighlight {
+	if d.page == nil || d.page.Image == nil {
+		return nil
+	}
+	return gurps.GlobalSettings().PDFInfoFor(d.path).HighlightAt(d.page.PageNumber, where.Div(scaleAdj), d.pageSize())
+}
+
+func (d *PDFDockable) highlightDragRect(where geom.Point) geom.Rect {
+	start := d.highlightStart.Div(scaleAdj)
+	end := where.Div(scaleAdj)
+	return geom.NewRect(min(start.X, end.X), min(start.Y, end.Y), max(start.X, end.X)-min(start.X, end.X),
+		max(start.Y, end.Y)-min(start.Y, end.Y))
+}
+
+func (d *PDFDockable) finishHighlight(where geom.Point) {
+	d.inHighlight = false
+	d.pendingHighlight = geom.Rect{}
+	r := d.highlightDragRect(where)
+	if r.Width < minPDFHighlightSize || r.Height < minPDFHighlightSize {
+		// Treat a click as a request to edit the note of an existing highlight
+		if highlight := d.highlightAt(where); highlight != nil {
+			d.editHighlightNote(highlight)
+		}
+	} else if d.page != nil && d.page.Image != nil {
+		info := gurps.GlobalSettings().PDFInfoFor(d.path)
+		info.AddHighlight(gurps.NewPDFHighlight(d.page.PageNumber, r, d.pageSize()))
+	}
+	d.MarkForRedraw()
+}
+
+func (d *PDFDockable) editHighlightNote(highlight *gurps.PDFHighlight) {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.SetTitle(i18n.Text("Highlight Note"))
+	panel.AddChild(label)
+	field := unison.NewMultiLineField()
+	field.SetText(highlight.Note)
+	field.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: geom.NewSize(300, 80),
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	panel.AddChild(field)
+	if unison.QuestionDialogWithPanel(panel) == unison.ModalResponseOK {
+		highlight.Note = field.Text()
+	}
+}
+
+func (d *PDFDockable) showHighlightContextMenu(where geom.Point, highlight *gurps.PDFHighlight) {
+	f := unison.DefaultMenuFactory()
+	cm := f.NewMenu(unison.PopupMenuTemporaryBaseID|unison.ContextMenuIDFlag, "", nil)
+	cm.InsertItem(-1, f.NewItem(unison.PopupMenuTemporaryBaseID+1, i18n.Text("Edit Note…"), unison.KeyBinding{}, nil,
+		func(_ unison.MenuItem) { d.editHighlightNote(highlight) }))
+	cm.InsertItem(-1, f.NewItem(unison.PopupMenuTemporaryBaseID+2, i18n.Text("Remove Highlight"), unison.KeyBinding{},
+		nil, func(_ unison.MenuItem) {
+			gurps.GlobalSettings().PDFInfoFor(d.path).RemoveHighlight(highlight)
+			d.MarkForRedraw()
+		}))
+	d.FlushDrawing()
+	cm.Popup(geom.Rect{
+		Point: d.docPanel.PointToRoot(where),
+		Size: geom.Size{
+			Width:  1,
+			Height: 1,
+		},
+	}, 0)
+	cm.Dispose()
+}
+
+func (d *PDFDockable) updateTooltip(where geom.Point, _ geom.Rect) geom.Rect {
+	highlight := d.highlightAt(where)
+	if highlight == nil || highlight.Note == "" {
+		d.docPanel.Tooltip = nil
+		return geom.Rect{}
+	}
+	d.docPanel.Tooltip = newWrappedTooltip(highlight.Note)
+	r := highlight.Rect(d.pageSize())
+	r.Point = r.Point.Mul(scaleAdj)
+	r.Size = r.Size.Mul(scaleAdj)
+	return d.docPanel.RectToRoot(r)
+}
+
+// drawHighlights draws the highlights for the current page. The canvas must already be scaled to the page image.
+func (d *PDFDockable) drawHighlights(gc *unison.Canvas) {
+	highlights := gurps.GlobalSettings().PDFInfoFor(d.path).HighlightsForPage(d.page.PageNumber)
+	if len(highlights) == 0 && d.pendingHighlight.Empty() {
+		return
+	}
+	p := unison.NewPaint()
+	p.SetStyle(paintstyle.Fill)
+	p.SetBlendMode(blendmode.Modulate)
+	p.SetColor(pdfHighlightColor)
+	pageSize := d.pageSize()
+	for _, one := range highlights {
+		gc.DrawRect(one.Rect(pageSize), p)
+	}
+	if !d.pendingHighlight.Empty() {
+		gc.DrawRect(d.pendingHighlight, p)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+const maxPDFHistory = 100
+
+type pdfHistoryEntry struct {
+	path string
+	page int
+}
+
+// pdfHistory holds the pages visited in the PDF viewers. It is shared between them, so that moving back and forward
+// can return to a page reference that was opened in another document.
+var pdfHistory struct {
+	entries []pdfHistoryEntry
+	pos     int
+}
+
+func recordPDFHistory(path string, page int) {
+	entry := pdfHistoryEntry{path: path, page: page}
+	h := &pdfHistory
+	if len(h.entries) == 0 {
+		h.entries = append(h.entries, entry)
+		h.pos = 0
+		return
+	}
+	if h.entries[h.pos] == entry {
+		return
+	}
+	h.pos++
+	if h.pos < len(h.entries) {
+		if h.entries[h.pos] != entry {
+			h.entries[h.pos] = entry
+			h.entries = h.entries[:h.pos+1]
+		}
+	} else {
+		h.entries = append(h.entries, entry)
+	}
+	if excess := len(h.entries) - maxPDFHistory; excess > 0 {
+		h.entries = h.entries[excess:]
+		h.pos -= excess
+	}
+}
+
+func canMovePDFHistory(delta int) bool {
+	pos := pdfHistory.pos + delta
+	return pos >= 0 && pos < len(pdfHistory.entries)
+}
+
+func movePDFHistory(delta int) {
+	if !canMovePDFHistory(delta) {
+		return
+	}
+	pdfHistory.pos += delta
+	entry := pdfHistory.entries[pdfHistory.pos]
+	if d, _ := OpenFile(entry.path, entry.page); d != nil {
+		if pdfDockable, ok := d.(*PDFDockable); ok {
+			pdfDockable.LoadPage(entry.page)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/gurps/importer"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+var (
+	_ unison.Dockable  = &PluginPanelDockable{}
+	_ unison.TabCloser = &PluginPanelDockable{}
+)
+
+// PluginPanelDockable shows the content a plugin's panel produces for a sheet.
+type PluginPanelDockable struct {
+	unison.Panel
+	sheet    *Sheet
+	plugin   *gurps.Plugin
+	panel    *gurps.PluginPanel
+	markdown *unison.Markdown
+	cancel   context.CancelFunc
+}
+
+// ShowPluginPanel shows the plugin's panel for the sheet, refreshing it if it is already open.
+func ShowPluginPanel(sheet *Sheet, plugin *gurps.Plugin, panel *gurps.PluginPanel) {
+	var existing *PluginPanelDockable
+	if Activate(func(d unison.Dockable) bool {
+		if one, ok := d.AsPanel().Self.(*PluginPanelDockable); ok && one.sheet == sheet &&
+			one.plugin.Dir() == plugin.Dir() && one.panel.ID == panel.ID {
+			existing = one
+			return true
+		}
+		return false
+	}) {
+		existing.refresh()
+		return
+	}
+	d := &PluginPanelDockable{
+		sheet:  sheet,
+		plugin: plugin,
+		panel:  panel,
+	}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.markdown = unison.NewMarkdown(true)
+	d.markdown.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(20)))
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.markdown, behavior.Fill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	refreshButton := unison.NewSVGButton(svg.Reset)
+	refreshButton.Tooltip = newWrappedTooltip(i18n.Text("Refresh the panel from the current state of the sheet"))
+	refreshButton.ClickCallback = d.refresh
+	toolbar.AddChild(refreshButton)
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+
+	d.AddChild(toolbar)
+	d.AddChild(scroll)
+	d.refresh()
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+// refresh runs the plugin against the current state of the sheet, replacing any run still in progress. The plugin is
+// run on its own goroutine, so a message saying so is shown until it finishes.
+func (d *PluginPanelDockable) refresh() {
+	d.stop()
+	data, err := gurps.PluginSheetData(d.sheet.Entity())
+	if err != nil {
+		d.setContent(d.failureContent(err))
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.setContent(fmt.Sprintf(i18n.Text("*Waiting for the %s plugin…*"), d.plugin.Name))
+	go func() {
+		content, panelErr := d.plugin.Panel(ctx, data, d.panel.ID)
+		unison.InvokeTask(func() {
+			if ctx.Err() != nil {
+				// Replaced by a later refresh or closed while running.
+				return
+			}
+			d.stop()
+			if panelErr != nil {
+				content = d.failureContent(panelErr)
+			}
+			d.setContent(content)
+		})
+	}()
+}
+
+func (d *PluginPanelDockable) stop() {
+	if d.cancel != nil {
+		d.cancel()
+		d.cancel = nil
+	}
+}
+
+func (d *PluginPanelDockable) failureContent(err error) string {
+	return fmt.Sprintf(i18n.Text("Unable to obtain the panel content from the %s plugin:\n\n```\n%s\n```"),
+		d.plugin.Name, err.Error())
+}
+
+func (d *PluginPanelDockable) setContent(content string) {
+	d.markdown.SetContent(content, 0)
+	d.MarkForLayoutAndRedraw()
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *PluginPanelDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.MarkdownFile,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *PluginPanelDockable) Title() string {
+	return fmt.Sprintf(i18n.Text("%s: %s"), d.panelTitle(), d.sheet.Title())
+}
+
+// Tooltip implements unison.Dockable.
+func (d *PluginPanelDockable) Tooltip() string {
+	return fmt.Sprintf(i18n.Text("Provided by the %s plugin"), d.plugin.Name)
+}
+
+// Modified implements unison.Dockable.
+func (d *PluginPanelDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *PluginPanelDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *PluginPanelDockable) AttemptClose() bool {
+	if !AttemptCloseForDockable(d) {
+		return false
+	}
+	d.stop()
+	return true
+}
+
+func (d *PluginPanelDockable) panelTitle() string {
+	if d.panel.Title != "" {
+		return d.panel.Title
+	}
+	return d.panel.ID
+}
+
+func exportWithPlugin(sheet *Sheet, plugin *gurps.Plugin, exporter *gurps.PluginExporter) {
+	dialog := unison.NewSaveDialog()
+	settings := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(settings.LastDir(gurps.DefaultLastDirKey))
+	dialog.SetAllowedExtensions(exporter.Extension)
+	dialog.SetInitialFileName(xfilepath.SanitizeName(xfilepath.BaseName(sheet.BackingFilePath())))
+	if dialog.RunModal() {
+		if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), exporter.Extension, false); ok {
+			settings.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(filePath))
+			data, err := gurps.PluginSheetData(sheet.Entity())
+			if err != nil {
+				Workspace.ErrorHandler(i18n.Text("Export failed"), err)
+				return
+			}
+			runPlugin(plugin, func(ctx context.Context) error {
+				return plugin.Export(ctx, data, exporter.ID, filePath)
+			}, func(err error) {
+				if err != nil {
+					Workspace.ErrorHandler(i18n.Text("Export failed"), err)
+				}
+			})
+		}
+	}
+}
+
+func importWithPlugin(plugin *gurps.Plugin, imp *gurps.PluginImporter) {
+	filePath, ok := chooseFileToImport(imp.Extensions...)
+	if !ok {
+		return
+	}
+	var entity *gurps.Entity
+	runPlugin(plugin, func(ctx context.Context) error {
+		var err error
+		entity, err = plugin.Import(ctx, imp.ID, filePath)
+		return err
+	}, func(err error) {
+		var result *importer.Result
+		if err == nil {
+			result = &importer.Result{Entity: entity}
+		}
+		showImportedSheet(filePath, result, err)
+	})
+}
+
+// runPlugin calls task on its own goroutine, showing a window with a progress bar and a button to cancel the task in
+// the meantime. Once the task finishes, done is called with its result on the UI thread, unless it was canceled.
+func runPlugin(plugin *gurps.Plugin, task func(ctx context.Context) error, done func(err error)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	wnd, err := newPluginProgressWindow(plugin, cancel)
+	if err != nil {
+		cancel()
+		done(err)
+		return
+	}
+	go func() {
+		taskErr := task(ctx)
+		unison.InvokeTask(func() {
+			canceled := ctx.Err() != nil
+			cancel()
+			wnd.Dispose()
+			if !canceled {
+				done(taskErr)
+			}
+		})
+	}()
+}
+
+func newPluginProgressWindow(plugin *gurps.Plugin, cancel context.CancelFunc) (*unison.Window, error) {
+	var frame geom.Rect
+	if focused := unison.ActiveWindow(); focused != nil {
+		frame = focused.FrameRect()
+	} else {
+		frame = unison.PrimaryDisplay().Usable
+	}
+	wnd, err := unison.NewWindow(plugin.Name, unison.FloatingWindowOption(), unison.NotResizableWindowOption(),
+		unison.UndecoratedWindowOption(), unison.TransientWindowOption())
+	if err != nil {
+		return nil, err
+	}
+	content := unison.NewPanel()
+	content.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.NewUniformInsets(1), false), unison.NewEmptyBorder(geom.NewUniformInsets(2*unison.StdHSpacing))))
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.SetTitle(fmt.Sprintf(i18n.Text("Waiting for the %s plugin…"), plugin.Name))
+	content.AddChild(label)
+	progress := unison.NewProgressBar(0)
+	progress.SetLayoutData(&unison.FlexLayoutData{
+		MinSize: geom.Size{Width: 500},
+		HAlign:  align.Fill,
+		HGrab:   true,
+	})
+	content.AddChild(progress)
+	cancelButton := unison.NewButton()
+	cancelButton.SetTitle(i18n.Text("Cancel"))
+	cancelButton.ClickCallback = func() {
+		cancelButton.SetEnabled(false)
+		label.SetTitle(i18n.Text("Canceling…"))
+		cancel()
+	}
+	cancelButton.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+	content.AddChild(cancelButton)
+	wnd.SetContent(content)
+	wnd.Pack()
+	wndFrame := wnd.FrameRect()
+	frame.Y += (frame.Height - wndFrame.Height) / 3
+	frame.Height = wndFrame.Height
+	frame.X += (frame.Width - wndFrame.Width) / 2
+	frame.Width = wndFrame.Width
+	frame = frame.Align()
+	wnd.SetFrameRect(unison.BestDisplayForRect(frame).FitRectOnto(frame))
+	wnd.ToFront()
+	return wnd, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type pointBudgetUndoEdit = *unison.UndoEdit[*pointBudgetAdjuster]
+
+type pointBudgetAdjuster struct {
+	Owner  Rebuildable
+	Entity *gurps.Entity
+	Budget gurps.PointBudget
+}
+
+func (a *pointBudgetAdjuster) Apply() {
+	a.Entity.PointBudget = a.Budget
+	MarkModified(a.Owner)
+}
+
+func displayPointBudgetPlanner(owner Rebuildable, entity *gurps.Entity) {
+	budget := entity.PointBudget
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	addPointBudgetField(panel, i18n.Text("Attributes"), &budget.Attributes)
+	addPointBudgetField(panel, i18n.Text("Advantages"), &budget.Advantages)
+	addPointBudgetField(panel, i18n.Text("Disadvantages"), &budget.Disadvantages)
+	addPointBudgetField(panel, i18n.Text("Skills"), &budget.Skills)
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()})
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create point budget dialog"), err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK || budget == entity.PointBudget {
+		return
+	}
+	before := &pointBudgetAdjuster{Owner: owner, Entity: entity, Budget: entity.PointBudget}
+	after := &pointBudgetAdjuster{Owner: owner, Entity: entity, Budget: budget}
+	if mgr := unison.UndoManagerFor(owner); mgr != nil {
+		addUndo(mgr, &unison.UndoEdit[*pointBudgetAdjuster]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Point Budget"),
+			UndoFunc:   func(edit pointBudgetUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit pointBudgetUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	after.Apply()
+}
+
+func addPointBudgetField(panel *unison.Panel, title string, target *fxp.Int) {
+	panel.AddChild(NewFieldLeadingLabel(title, false))
+	field := NewDecimalField(nil, "", title, func() fxp.Int { return *target },
+		func(value fxp.Int) { *target = value }, -fxp.MaxBasePoints, fxp.MaxBasePoints, false, false)
+	field.Tooltip = newWrappedTooltip(i18n.Text("The number of points you plan to spend in this category. Use 0 to not track it."))
+	panel.AddChild(field)
+}
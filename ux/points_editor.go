@@ -26,6 +26,7 @@ import (
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/align"
 	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
 )
 
 var (
@@ -47,6 +48,7 @@ type pointsEditor struct {
 	applyButton      *unison.Button
 	cancelButton     *unison.Button
 	content          *unison.Panel
+	summary          *unison.Label
 	before           []*gurps.PointsRecord
 	current          []*gurps.PointsRecord
 	promptForSave    bool
@@ -83,10 +85,11 @@ func displayPointsEditor(owner Rebuildable, entity *gurps.Entity) {
 	e.undoMgr = unison.NewUndoManager(100, func(err error) { errs.Log(err) })
 	e.SetLayout(&unison.FlexLayout{Columns: 1})
 	e.AddChild(e.createToolbar())
+	e.AddChild(e.createSummary())
 	e.content = unison.NewPanel()
 	e.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
 	e.content.SetLayout(&unison.FlexLayout{
-		Columns:  5,
+		Columns:  6,
 		HSpacing: unison.StdHSpacing,
 		VSpacing: unison.StdVSpacing,
 	})
@@ -121,7 +124,7 @@ func displayPointsEditor(owner Rebuildable, entity *gurps.Entity) {
 	scroller.Content().AsPanel().ValidateScrollRoot()
 	PlaceInDock(e, dgroup.Editors, false)
 	if children := e.content.Children(); len(children) != 0 {
-		children[3].RequestFocus()
+		children[4].RequestFocus()
 	}
 }
 
@@ -174,6 +177,32 @@ func (e *pointsEditor) createToolbar() unison.Paneler {
 	return toolbar
 }
 
+func (e *pointsEditor) createSummary() unison.Paneler {
+	e.summary = unison.NewLabel()
+	e.summary.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	e.summary.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	e.updateSummary()
+	return e.summary
+}
+
+func (e *pointsEditor) updateSummary() {
+	summary := gurps.SummarizeAdvancement(e.current, e.entity.PointsBreakdown().Total())
+	text := fmt.Sprintf(i18n.Text("Earned: %s   Spent: %s   Unspent: %s"), summary.Earned.String(),
+		summary.Spent.String(), summary.Unspent().String())
+	if unlogged := summary.Unlogged(); unlogged != 0 {
+		text += fmt.Sprintf(i18n.Text("   (%s spent without a logged entry)"), unlogged.String())
+	}
+	if text != e.summary.String() {
+		e.summary.SetTitle(text)
+		e.summary.MarkForLayoutRecursivelyUpward()
+		e.summary.MarkForRedraw()
+	}
+}
+
 func (e *pointsEditor) initContent() {
 	for _, rec := range e.current {
 		e.createRow(rec, -1)
@@ -224,6 +253,18 @@ func (e *pointsEditor) createRow(rec *gurps.PointsRecord, index int) {
 		index++
 	}
 
+	spend := NewCheckBox(nil, "", i18n.Text("Spent"),
+		func() check.Enum { return check.FromBool(rec.Spend) },
+		func(value check.Enum) {
+			rec.Spend = value == check.On
+			MarkModified(e.content)
+		})
+	spend.Tooltip = newWrappedTooltip(i18n.Text("Check if this entry records points that were spent, rather than awarded"))
+	e.content.AddChildAtIndex(spend, index)
+	if index != -1 {
+		index++
+	}
+
 	reasonText := i18n.Text("Reason")
 	reason := NewStringField(nil, "", reasonText,
 		func() string { return rec.Reason },
@@ -250,7 +291,7 @@ func (e *pointsEditor) addEntry() {
 	e.content.Pack()
 	e.content.MarkForRedraw()
 	MarkModified(e.content)
-	e.content.Children()[2].RequestFocus()
+	e.content.Children()[3].RequestFocus()
 }
 
 func (e *pointsEditor) removeEntry(rec *gurps.PointsRecord) {
@@ -259,8 +300,8 @@ func (e *pointsEditor) removeEntry(rec *gurps.PointsRecord) {
 			continue
 		}
 		e.current = slices.Delete(e.current, i, i+1)
-		i *= 5
-		for j := 4; j >= 0; j-- {
+		i *= 6
+		for j := 5; j >= 0; j-- {
 			e.content.RemoveChildAtIndex(i + j)
 		}
 		e.content.Pack()
@@ -338,6 +379,7 @@ func (e *pointsEditor) Modified() bool {
 }
 
 func (e *pointsEditor) MarkModified(_ unison.Paneler) {
+	e.updateSummary()
 	UpdateTitleForDockable(e)
 	DeepSync(e)
 }
@@ -400,7 +442,7 @@ func (e *pointsEditor) applyWithoutFocusNext() {
 	owner := e.owner
 	entity := e.entity
 	if mgr := unison.UndoManagerFor(owner); mgr != nil {
-		mgr.Add(&unison.UndoEdit[[]*gurps.PointsRecord]{
+		addUndo(mgr, &unison.UndoEdit[[]*gurps.PointsRecord]{
 			ID:       unison.NextUndoID(),
 			EditName: i18n.Text("Point Record Changes"),
 			UndoFunc: func(edit *unison.UndoEdit[[]*gurps.PointsRecord]) {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/richardwilkes/gcs/v5/model/colors"
 	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/v2/geom"
@@ -33,9 +34,16 @@ type PointsPanel struct {
 	ptsList      *unison.Panel
 	unspentField *NonEditablePageField
 	unspentLabel *unison.Label
+	budgetRows   []*pointBudgetRow
 	overSpent    int8
 }
 
+type pointBudgetRow struct {
+	row    int
+	spent  func() fxp.Int
+	target func() fxp.Int
+}
+
 // NewPointsPanel creates a new points panel.
 func NewPointsPanel(entity *gurps.Entity, targetMgr *TargetMgr) *PointsPanel {
 	p := &PointsPanel{
@@ -67,7 +75,7 @@ func NewPointsPanel(entity *gurps.Entity, targetMgr *TargetMgr) *PointsPanel {
 
 	hdri := unison.NewPanel()
 	hdri.SetLayout(&unison.FlexLayout{
-		Columns:  2,
+		Columns:  4,
 		HSpacing: 4,
 	})
 	hdri.SetLayoutData(&unison.FlexLayoutData{HAlign: align.Middle})
@@ -96,7 +104,30 @@ func NewPointsPanel(entity *gurps.Entity, targetMgr *TargetMgr) *PointsPanel {
 	editButton.ClickCallback = func() {
 		displayPointsEditor(unison.AncestorOrSelf[Rebuildable](p), p.entity)
 	}
+	markAsBuildData(p.entity, editButton)
 	hdri.AddChild(editButton)
+	budgetButton := unison.NewSVGButton(svg.Calculator)
+	budgetButton.OnBackgroundInk = colors.OnHeader
+	budgetButton.OnSelectionInk = colors.OnHeader
+	budgetButton.Font = fonts.PageLabelPrimary
+	budgetButton.Tooltip = newWrappedTooltip(i18n.Text("Plan a point budget for each category"))
+	if dsvg, ok := budgetButton.Drawable.(*unison.DrawableSVG); ok {
+		dsvg.Size = geom.NewSize(height, height)
+	}
+	budgetButton.ClickCallback = func() {
+		displayPointBudgetPlanner(unison.AncestorOrSelf[Rebuildable](p), p.entity)
+	}
+	hdri.AddChild(budgetButton)
+	studyButton := unison.NewSVGButton(svg.Bookmark)
+	studyButton.OnBackgroundInk = colors.OnHeader
+	studyButton.OnSelectionInk = colors.OnHeader
+	studyButton.Font = fonts.PageLabelPrimary
+	studyButton.Tooltip = newWrappedTooltip(i18n.Text("Show the training log"))
+	if dsvg, ok := studyButton.Drawable.(*unison.DrawableSVG); ok {
+		dsvg.Size = geom.NewSize(height, height)
+	}
+	studyButton.ClickCallback = func() { ShowTrainingLog(p.entity) }
+	hdri.AddChild(studyButton)
 	p.AddChild(hdr)
 
 	p.ptsList = unison.NewPanel()
@@ -130,6 +161,7 @@ func NewPointsPanel(entity *gurps.Entity, targetMgr *TargetMgr) *PointsPanel {
 			}
 			return ink
 		})
+		p.drawBudgetProgress(gc, rect)
 	}
 
 	p.unspentField = NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
@@ -146,36 +178,24 @@ func NewPointsPanel(entity *gurps.Entity, targetMgr *TargetMgr) *PointsPanel {
 			MarkForLayoutWithinDockable(f)
 		}
 	}), i18n.Text("Ancestry"), i18n.Text("Total points spent on an ancestry package"))
-	p.addPointsField(NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
-		if text := p.entity.PointsBreakdown().Attributes.String(); text != f.Text.String() {
-			f.SetTitle(text)
-			MarkForLayoutWithinDockable(f)
-		}
-	}), i18n.Text("Attributes"), i18n.Text("Total points spent on attributes"))
-	p.addPointsField(NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
-		if text := p.entity.PointsBreakdown().Advantages.String(); text != f.Text.String() {
-			f.SetTitle(text)
-			MarkForLayoutWithinDockable(f)
-		}
-	}), i18n.Text("Advantages"), i18n.Text("Total points spent on advantages"))
-	p.addPointsField(NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
-		if text := p.entity.PointsBreakdown().Disadvantages.String(); text != f.Text.String() {
-			f.SetTitle(text)
-			MarkForLayoutWithinDockable(f)
-		}
-	}), i18n.Text("Disadvantages"), i18n.Text("Total points spent on disadvantages"))
+	p.addBudgetedPointsField(i18n.Text("Attributes"), i18n.Text("Total points spent on attributes"),
+		func() fxp.Int { return p.entity.PointsBreakdown().Attributes },
+		func() fxp.Int { return p.entity.PointBudget.Attributes })
+	p.addBudgetedPointsField(i18n.Text("Advantages"), i18n.Text("Total points spent on advantages"),
+		func() fxp.Int { return p.entity.PointsBreakdown().Advantages },
+		func() fxp.Int { return p.entity.PointBudget.Advantages })
+	p.addBudgetedPointsField(i18n.Text("Disadvantages"), i18n.Text("Total points spent on disadvantages"),
+		func() fxp.Int { return p.entity.PointsBreakdown().Disadvantages },
+		func() fxp.Int { return p.entity.PointBudget.Disadvantages })
 	p.addPointsField(NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
 		if text := p.entity.PointsBreakdown().Quirks.String(); text != f.Text.String() {
 			f.SetTitle(text)
 			MarkForLayoutWithinDockable(f)
 		}
 	}), i18n.Text("Quirks"), i18n.Text("Total points spent on quirks"))
-	p.addPointsField(NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
-		if text := p.entity.PointsBreakdown().Skills.String(); text != f.Text.String() {
-			f.SetTitle(text)
-			MarkForLayoutWithinDockable(f)
-		}
-	}), i18n.Text("Skills"), i18n.Text("Total points spent on skills"))
+	p.addBudgetedPointsField(i18n.Text("Skills"), i18n.Text("Total points spent on skills"),
+		func() fxp.Int { return p.entity.PointsBreakdown().Skills },
+		func() fxp.Int { return p.entity.PointBudget.Skills })
 	p.addPointsField(NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
 		if text := p.entity.PointsBreakdown().Spells.String(); text != f.Text.String() {
 			f.SetTitle(text)
@@ -197,6 +217,64 @@ func (p *PointsPanel) addPointsField(field *NonEditablePageField, title, tooltip
 	return label
 }
 
+func (p *PointsPanel) addBudgetedPointsField(title, tooltip string, spent, target func() fxp.Int) {
+	var field *NonEditablePageField
+	var label *unison.Label
+	lastTip := tooltip
+	field = NewNonEditablePageFieldEnd(func(f *NonEditablePageField) {
+		if text := spent().String(); text != f.Text.String() {
+			f.SetTitle(text)
+			MarkForLayoutWithinDockable(f)
+		}
+		if tip := pointBudgetTooltip(tooltip, spent(), target()); tip != lastTip {
+			lastTip = tip
+			field.Tooltip = newWrappedTooltip(tip)
+			label.Tooltip = field.Tooltip
+		}
+	})
+	p.budgetRows = append(p.budgetRows, &pointBudgetRow{
+		row:    len(p.ptsList.Children()) / 2,
+		spent:  spent,
+		target: target,
+	})
+	label = p.addPointsField(field, title, tooltip)
+}
+
+func pointBudgetTooltip(tooltip string, spent, target fxp.Int) string {
+	switch {
+	case target == 0:
+		return tooltip
+	case gurps.PointBudgetExceeded(spent, target):
+		return fmt.Sprintf(i18n.Text("%s\n\nWarning: exceeds the planned budget of %s by %s"), tooltip, target.String(),
+			(spent.Abs() - target.Abs()).String())
+	default:
+		return fmt.Sprintf(i18n.Text("%s\n\n%s of the planned budget of %s spent"), tooltip, spent.String(),
+			target.String())
+	}
+}
+
+// drawBudgetProgress draws a thin progress bar along the bottom of each row that has a budget target set.
+func (p *PointsPanel) drawBudgetProgress(gc *unison.Canvas, rect geom.Rect) {
+	children := p.ptsList.Children()
+	for _, one := range p.budgetRows {
+		target := one.target()
+		if target == 0 || one.row*2+1 >= len(children) {
+			continue
+		}
+		spent := one.spent()
+		r := children[one.row*2].FrameRect().Union(children[one.row*2+1].FrameRect())
+		r.X = rect.X
+		r.Y = r.Bottom() - 2
+		r.Height = 2
+		r.Width = rect.Width * float32(gurps.PointBudgetProgress(spent, target))
+		ink := unison.ThemeFocus
+		if gurps.PointBudgetExceeded(spent, target) {
+			ink = unison.ThemeError
+		}
+		gc.DrawRect(r, ink.Paint(gc, r, paintstyle.Fill))
+	}
+}
+
 func (p *PointsPanel) adjustUnspent() {
 	if p.unspentLabel != nil {
 		last := p.overSpent
@@ -70,7 +70,7 @@ func (p *poolSettingsPanel) addThreshold() {
 		}
 	}
 	undo.AfterData = clonePoolThresholds(p.def.Thresholds)
-	p.dockable.UndoManager().Add(undo)
+	addUndo(p.dockable.UndoManager(), undo)
 	p.dockable.MarkModified(nil)
 	p.MarkForLayoutRecursivelyUpward()
 	p.dockable.ValidateLayout()
@@ -101,7 +101,7 @@ func (p *poolSettingsPanel) deleteThreshold(target *thresholdSettingsPanel) {
 	undo.BeforeData = clonePoolThresholds(p.def.Thresholds)
 	p.def.Thresholds = slices.Delete(p.def.Thresholds, i, i+1)
 	undo.AfterData = clonePoolThresholds(p.def.Thresholds)
-	p.dockable.UndoManager().Add(undo)
+	addUndo(p.dockable.UndoManager(), undo)
 	p.dockable.MarkModified(nil)
 }
 
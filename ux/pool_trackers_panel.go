@@ -0,0 +1,247 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/colors"
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/attribute"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var _ pageHelper = &PoolTrackersPanel{}
+
+// PoolTrackersPanel holds the tracker blocks for the point pools that have been marked to be shown as trackers.
+type PoolTrackersPanel struct {
+	unison.Panel
+	entity    *gurps.Entity
+	targetMgr *TargetMgr
+	prefix    string
+	hash      uint64
+	count     int
+	drawStart int
+	drawEnd   int
+	syncers   []func()
+}
+
+// NewPoolTrackersPanel creates a new pool trackers panel.
+func NewPoolTrackersPanel(entity *gurps.Entity, targetMgr *TargetMgr) *PoolTrackersPanel {
+	p := &PoolTrackersPanel{
+		entity:    entity,
+		targetMgr: targetMgr,
+		prefix:    targetMgr.NextPrefix(),
+		drawEnd:   1,
+	}
+	p.Self = p
+	p.SetLayout(&unison.FlexLayout{
+		Columns:      2,
+		HSpacing:     1,
+		VSpacing:     1,
+		HAlign:       align.Fill,
+		VAlign:       align.Fill,
+		EqualColumns: true,
+	})
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.hash = gurps.Hash64(gurps.SheetSettingsFor(entity).Attributes)
+	p.rebuild()
+	return p
+}
+
+// HasTrackers returns true if at least one tracker is present.
+func (p *PoolTrackersPanel) HasTrackers() bool {
+	return p.count > 0
+}
+
+func (p *PoolTrackersPanel) trackedDefs() []*gurps.AttributeDef {
+	var list []*gurps.AttributeDef
+	for _, def := range gurps.SheetSettingsFor(p.entity).Attributes.List(false) {
+		if def.ShowsTracker() {
+			if _, ok := p.entity.Attributes.Set[def.ID()]; ok {
+				list = append(list, def)
+			}
+		}
+	}
+	return list
+}
+
+func (p *PoolTrackersPanel) rebuild() {
+	p.RemoveAllChildren()
+	p.syncers = nil
+	defs := p.trackedDefs()
+	p.count = len(defs)
+	for _, def := range defs {
+		p.AddChild(p.createTracker(def, p.entity.Attributes.Set[def.ID()]))
+	}
+	if layout, ok := p.Layout().(*unison.FlexLayout); ok {
+		layout.Columns = max(min(len(defs), 2), 1)
+	}
+}
+
+func (p *PoolTrackersPanel) createTracker(def *gurps.AttributeDef, attr *gurps.Attribute) *unison.Panel {
+	tracker := unison.NewPanel()
+	tracker.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: 4,
+	})
+	tracker.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	tracker.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: def.ResolveFullName()},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(2, 1))))
+	tracker.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	InstallTintFunc(tracker, colors.TintPools)
+
+	var currentField *DecimalField
+	currentField = NewDecimalPageField(p.targetMgr, p.prefix+attr.AttrID+":cur",
+		i18n.Text("Point Pool Current"), func() fxp.Int {
+			if currentField != nil {
+				currentField.SetMinMax(currentField.Min(), attr.Maximum())
+			}
+			return attr.Current()
+		},
+		func(v fxp.Int) { attr.Damage = (attr.Maximum() - v).Max(0) }, fxp.Min, attr.Maximum(), true)
+	markAsPDFFormField(currentField.Field, attr.AttrID)
+	tracker.AddChild(currentField)
+
+	tracker.AddChild(NewPageLabel(i18n.Text("of")))
+
+	if def.Type == attribute.Pool {
+		maxField := NewDecimalPageField(p.targetMgr, p.prefix+attr.AttrID+":max",
+			i18n.Text("Point Pool Maximum"), func() fxp.Int { return attr.Maximum() },
+			func(v fxp.Int) {
+				attr.SetMaximum(v)
+				currentField.SetMinMax(currentField.Min(), v)
+				currentField.Sync()
+			}, fxp.Min, fxp.Max, true)
+		markAsBuildData(p.entity, maxField)
+		tracker.AddChild(maxField)
+	} else {
+		tracker.AddChild(NewNonEditablePageFieldEnd(func(field *NonEditablePageField) {
+			field.SetTitle(attr.Maximum().String())
+		}))
+	}
+
+	state := NewPageLabel("")
+	state.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	tracker.AddChild(state)
+
+	gauge := unison.NewPanel()
+	gauge.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  4,
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	gauge.SetSizer(func(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+		height := max(fonts.PageLabelPrimary.LineHeight()/2, 6)
+		return geom.NewSize(40, height), geom.NewSize(40, height), geom.NewSize(unison.DefaultMaxSize, height)
+	})
+	gauge.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) { drawPoolTrackerGauge(gc, rect, def, attr) }
+	tracker.AddChild(gauge)
+
+	syncState := func() {
+		state.Text = unison.NewSmallCapsText("", &unison.TextDecoration{
+			Font:            fonts.PageLabelPrimary,
+			OnBackgroundInk: unison.ThemeOnSurface,
+		})
+		state.Tooltip = nil
+		gauge.Tooltip = nil
+		if threshold := attr.CurrentThreshold(); threshold != nil {
+			state.Text = unison.NewSmallCapsText("["+threshold.State+"]", &unison.TextDecoration{
+				Font:            fonts.PageLabelPrimary,
+				OnBackgroundInk: unison.ThemeOnSurface,
+			})
+			if threshold.Explanation != "" {
+				state.Tooltip = newMarkdownTooltip(threshold.ResolveExplanation(attr), "")
+				gauge.Tooltip = state.Tooltip
+			}
+		}
+		gauge.MarkForRedraw()
+	}
+	syncState()
+	p.syncers = append(p.syncers, syncState)
+	return tracker
+}
+
+func drawPoolTrackerGauge(gc *unison.Canvas, rect geom.Rect, def *gurps.AttributeDef, attr *gurps.Attribute) {
+	rect = rect.Inset(geom.NewUniformInsets(1))
+	gc.DrawRect(rect, unison.ThemeSurface.Paint(gc, rect, paintstyle.Fill))
+	maximum := attr.Maximum()
+	if maximum > 0 {
+		current := attr.Current().Max(0).Min(maximum)
+		fill := rect
+		fill.Width = rect.Width * fxp.AsFloat[float32](current.Div(maximum))
+		gc.DrawRect(fill, unison.ThemeFocus.Paint(gc, fill, paintstyle.Fill))
+		paint := unison.ThemeOnSurface.Paint(gc, rect, paintstyle.Stroke)
+		paint.SetStrokeWidth(1)
+		for _, threshold := range def.Thresholds {
+			value := threshold.Threshold(attr)
+			if value <= 0 || value >= maximum {
+				continue
+			}
+			x := rect.X + rect.Width*fxp.AsFloat[float32](value.Div(maximum))
+			gc.DrawLine(geom.NewPoint(x, rect.Y), geom.NewPoint(x, rect.Bottom()), paint)
+		}
+	}
+	paint := unison.ThemeSurfaceEdge.Paint(gc, rect, paintstyle.Stroke)
+	paint.SetStrokeWidth(1)
+	gc.DrawRect(rect, paint)
+}
+
+// Sync the panel to the current data.
+func (p *PoolTrackersPanel) Sync() {
+	if h := gurps.Hash64(gurps.SheetSettingsFor(p.entity).Attributes); h != p.hash {
+		p.hash = h
+		p.rebuild()
+	} else {
+		for _, syncer := range p.syncers {
+			syncer()
+		}
+	}
+	MarkForLayoutWithinDockable(p)
+}
+
+// OverheadHeight implements pageHelper.
+func (p *PoolTrackersPanel) OverheadHeight() float32 {
+	return 0
+}
+
+// RowHeights implements pageHelper. The trackers are always kept together, so they are reported as a single row.
+func (p *PoolTrackersPanel) RowHeights() []float32 {
+	return []float32{p.FrameRect().Height}
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *PoolTrackersPanel) CurrentDrawRowRange() (start, endBefore int) {
+	return p.drawStart, p.drawEnd
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *PoolTrackersPanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+}
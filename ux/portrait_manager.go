@@ -0,0 +1,140 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/portrait"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+const portraitThumbnailSize = 48
+
+// ShowManagePortraits shows a dialog for adding, removing and changing the additional portraits of the sheet's entity.
+func ShowManagePortraits(sheet *Sheet) {
+	profile := &sheet.entity.Profile
+	working := make([]*gurps.AltPortrait, len(profile.AltPortraits))
+	for i, one := range profile.AltPortraits {
+		working[i] = one.Clone()
+	}
+	var shown *gurps.AltPortrait
+	if profile.ShownPortrait > 0 && profile.ShownPortrait <= len(working) {
+		shown = working[profile.ShownPortrait-1]
+	}
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Additional Portraits"), false))
+	list := newRitualListPanel()
+	addPortrait := func(alt *gurps.AltPortrait) {
+		row := newRitualRowPanel(5)
+		kindPopup := unison.NewPopupMenu[portrait.Kind]()
+		for _, one := range portrait.Kinds {
+			kindPopup.AddItem(one)
+		}
+		kindPopup.Select(alt.Kind)
+		kindPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[portrait.Kind]) {
+			if item, ok := popup.Selected(); ok {
+				alt.Kind = item
+			}
+		}
+		row.AddChild(kindPopup)
+		nameLabel := i18n.Text("Name")
+		nameField := NewStringField(nil, "", nameLabel, func() string { return alt.Name },
+			func(s string) { alt.Name = s })
+		nameField.Watermark = nameLabel
+		nameField.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		row.AddChild(nameField)
+		row.AddChild(newPortraitThumbnail(alt))
+		setImage := unison.NewButton()
+		setImage.SetTitle(i18n.Text("Set Image…"))
+		setImage.ClickCallback = func() {
+			if file := choosePortraitFile(); file != "" {
+				if data := loadPortraitData(file); data != nil {
+					alt.SetData(data)
+					row.MarkForRedraw()
+				}
+			}
+		}
+		row.AddChild(setImage)
+		row.AddChild(newRitualRemoveButton(i18n.Text("Remove this portrait"), func() {
+			if i := slices.Index(working, alt); i != -1 {
+				working = slices.Delete(working, i, i+1)
+			}
+			row.RemoveFromParent()
+			MarkRootAncestorForLayoutRecursively(list)
+		}))
+		list.AddChild(row)
+	}
+	for _, one := range working {
+		addPortrait(one)
+	}
+	content.AddChild(list)
+	content.AddChild(newRitualAddButton(i18n.Text("Add a portrait"), func() {
+		alt := &gurps.AltPortrait{Kind: portrait.Alternate}
+		working = append(working, alt)
+		addPortrait(alt)
+		MarkRootAncestorForLayoutRecursively(list)
+	}))
+
+	icon := &unison.DrawableSVG{
+		SVG:  svg.GCSSheet,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	sheet.changePortraits(i18n.Text("Manage Portraits"), func(p *gurps.Profile) {
+		p.AltPortraits = working
+		// Keep showing the same portrait if it wasn't removed
+		p.ShownPortrait = slices.Index(working, shown) + 1
+	})
+}
+
+func newPortraitThumbnail(alt *gurps.AltPortrait) *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: geom.NewSize(portraitThumbnailSize, portraitThumbnailSize),
+		VAlign:   align.Middle,
+	})
+	panel.DrawCallback = func(gc *unison.Canvas, _ geom.Rect) {
+		r := panel.ContentRect(false)
+		gc.DrawRect(r, unison.ThemeBelowSurface.Paint(gc, r, paintstyle.Fill))
+		if img := alt.Image(); img != nil {
+			size := img.LogicalSize()
+			scale := min(r.Width/size.Width, r.Height/size.Height)
+			width := size.Width * scale
+			height := size.Height * scale
+			img.DrawInRect(gc, geom.NewRect(r.X+(r.Width-width)/2, r.Y+(r.Height-height)/2, width, height), nil, nil)
+		}
+	}
+	return panel
+}
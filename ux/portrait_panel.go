@@ -23,6 +23,7 @@ import (
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/xhttp"
 	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/check"
 	"github.com/richardwilkes/unison/enums/filtermode"
 	"github.com/richardwilkes/unison/enums/imgfmt"
 	"github.com/richardwilkes/unison/enums/mipmapmode"
@@ -36,15 +37,20 @@ const maxPortraitDimension = 400
 type PortraitPanel struct {
 	unison.Panel
 	entity      *gurps.Entity
+	border      *TitledBorder
 	mouseIsOver bool
 }
 
 // NewPortraitPanel creates a new portrait panel.
 func NewPortraitPanel(entity *gurps.Entity) *PortraitPanel {
-	p := &PortraitPanel{entity: entity}
+	p := &PortraitPanel{
+		entity: entity,
+		border: &TitledBorder{},
+	}
 	p.Self = p
 	p.SetLayoutData(&unison.FlexLayoutData{VSpan: 2})
-	p.SetBorder(&TitledBorder{Title: i18n.Text("Portrait")})
+	p.SetBorder(p.border)
+	p.Sync()
 	p.DrawCallback = p.drawSelf
 	p.FileDropCallback = p.fileDrop
 	p.MouseDownCallback = p.mouseDown
@@ -92,7 +98,8 @@ func (p *PortraitPanel) drawSelf(gc *unison.Canvas, _ geom.Rect) {
 	}
 	if p.mouseIsOver {
 		gc.DrawRect(r, unison.Black.SetAlphaIntensity(0.3).Paint(gc, r, paintstyle.Fill))
-		text := unison.NewTextWrappedLines(i18n.Text("Drop an image here or double-click to change the portrait"),
+		text := newTextWrappedLines(
+			i18n.Text("Drop an image here or double-click to change the portrait. Right-click for more portraits."),
 			&unison.TextDecoration{
 				Font:            fonts.PageFieldPrimary,
 				OnBackgroundInk: unison.White,
@@ -113,28 +120,67 @@ func (p *PortraitPanel) drawSelf(gc *unison.Canvas, _ geom.Rect) {
 
 // Sync the panel to the current data.
 func (p *PortraitPanel) Sync() {
-	// Nothing to do
+	choices := p.entity.Profile.PortraitChoices()
+	title := choices[0]
+	if i := p.entity.Profile.ShownPortrait; i > 0 && i < len(choices) {
+		title = choices[i]
+	}
+	if p.border.Title != title {
+		p.border.Title = title
+		p.MarkForRedraw()
+	}
 }
 
-func (p *PortraitPanel) mouseDown(_ geom.Point, button, clickCount int, _ unison.Modifiers) bool {
-	if button == unison.ButtonLeft && clickCount == 2 {
-		d := unison.NewOpenDialog()
-		d.SetAllowsMultipleSelection(false)
-		d.SetResolvesAliases(true)
-		d.SetAllowedExtensions(imgfmt.AllReadableExtensions()...)
-		d.SetCanChooseDirectories(false)
-		d.SetCanChooseFiles(true)
-		global := gurps.GlobalSettings()
-		d.SetInitialDirectory(global.LastDir(gurps.ImagesLastDirKey))
-		if d.RunModal() {
-			file := d.Path()
-			global.SetLastDir(gurps.ImagesLastDirKey, filepath.Dir(file))
+func (p *PortraitPanel) mouseDown(where geom.Point, button, clickCount int, _ unison.Modifiers) bool {
+	switch {
+	case button == unison.ButtonLeft && clickCount == 2:
+		if file := choosePortraitFile(); file != "" {
 			p.fileDrop([]string{file})
 		}
+	case button == unison.ButtonRight && clickCount == 1:
+		p.showContextMenu(where)
 	}
 	return true
 }
 
+func (p *PortraitPanel) showContextMenu(where geom.Point) {
+	sheet := unison.Ancestor[*Sheet](p)
+	if sheet == nil {
+		return
+	}
+	f := unison.DefaultMenuFactory()
+	cm := f.NewMenu(unison.PopupMenuTemporaryBaseID|unison.ContextMenuIDFlag, "", nil)
+	id := 1
+	for i, choice := range p.entity.Profile.PortraitChoices() {
+		item := f.NewItem(unison.PopupMenuTemporaryBaseID+id, choice, unison.KeyBinding{}, nil, func(_ unison.MenuItem) {
+			if p.entity.Profile.ShownPortrait != i {
+				sheet.changePortraits(i18n.Text("Show Portrait"), func(profile *gurps.Profile) {
+					profile.ShownPortrait = i
+				})
+			}
+		})
+		item.SetCheckState(check.FromBool(p.entity.Profile.ShownPortrait == i))
+		cm.InsertItem(-1, item)
+		id++
+	}
+	cm.InsertSeparator(-1, true)
+	cm.InsertItem(-1, f.NewItem(unison.PopupMenuTemporaryBaseID+id, i18n.Text("Manage Portraits…"),
+		unison.KeyBinding{}, nil, func(_ unison.MenuItem) { ShowManagePortraits(sheet) }))
+	id++
+	cm.InsertItem(-1, f.NewItem(unison.PopupMenuTemporaryBaseID+id, exportTokenAction.Title, unison.KeyBinding{},
+		func(_ unison.MenuItem) bool { return sheet.canExportToken(nil) },
+		func(_ unison.MenuItem) { sheet.exportToken(nil) }))
+	p.FlushDrawing()
+	cm.Popup(geom.Rect{
+		Point: p.PointToRoot(where),
+		Size: geom.Size{
+			Width:  1,
+			Height: 1,
+		},
+	}, 0)
+	cm.Dispose()
+}
+
 func (p *PortraitPanel) fileDrop(files []string) {
 	for _, f := range files {
 		p.processFileDrop(f)
@@ -142,27 +188,50 @@ func (p *PortraitPanel) fileDrop(files []string) {
 }
 
 func (p *PortraitPanel) processFileDrop(f string) {
+	data := loadPortraitData(f)
+	if data == nil {
+		return
+	}
+	if sheet := unison.Ancestor[*Sheet](p); sheet != nil {
+		sheet.changePortraits(i18n.Text("Set Portrait"), func(profile *gurps.Profile) {
+			profile.SetShownPortraitData(data)
+		})
+	}
+}
+
+// choosePortraitFile asks the user to pick an image file, returning its path, or an empty string if none was chosen.
+func choosePortraitFile() string {
+	d := unison.NewOpenDialog()
+	d.SetAllowsMultipleSelection(false)
+	d.SetResolvesAliases(true)
+	d.SetAllowedExtensions(imgfmt.AllReadableExtensions()...)
+	d.SetCanChooseDirectories(false)
+	d.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	d.SetInitialDirectory(global.LastDir(gurps.ImagesLastDirKey))
+	if !d.RunModal() {
+		return ""
+	}
+	file := d.Path()
+	global.SetLastDir(gurps.ImagesLastDirKey, filepath.Dir(file))
+	return file
+}
+
+// loadPortraitData loads the image at the given path or URL and converts it for use as a portrait. Returns nil if that
+// couldn't be done.
+func loadPortraitData(f string) []byte {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 	data, err := xhttp.RetrieveData(ctx, nil, f)
 	if err != nil {
 		errs.Log(errs.NewWithCause("unable to load", err), "file", f)
-		return
+		return nil
 	}
 	if data, err = convertForPortraitUse(data); err != nil {
 		errs.Log(err, "file", f)
-		return
+		return nil
 	}
-	sheet := unison.Ancestor[*Sheet](p)
-	sheet.undoMgr.Add(&unison.UndoEdit[[]byte]{
-		ID:         unison.NextUndoID(),
-		EditName:   i18n.Text("Set Portrait"),
-		UndoFunc:   func(edit *unison.UndoEdit[[]byte]) { sheet.updatePortrait(edit.BeforeData) },
-		RedoFunc:   func(edit *unison.UndoEdit[[]byte]) { sheet.updatePortrait(edit.AfterData) },
-		BeforeData: sheet.entity.Profile.PortraitData,
-		AfterData:  data,
-	})
-	sheet.updatePortrait(data)
+	return data
 }
 
 func convertForPortraitUse(imageData []byte) ([]byte, error) {
@@ -0,0 +1,93 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+)
+
+// ShowPowersBySource shows the sheet's abilities and Talents grouped by their power source.
+func ShowPowersBySource(sheet *Sheet) {
+	groups := sheet.Entity().PowersBySource()
+	if len(groups) == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("No Powers"),
+			i18n.Text("None of the traits on this sheet have been given a power source."))
+		return
+	}
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing * 4,
+		VSpacing: unison.StdVSpacing,
+	})
+	for i, group := range groups {
+		if i != 0 {
+			sep := unison.NewSeparator()
+			sep.SetLayoutData(&unison.FlexLayoutData{
+				HSpan:  2,
+				HAlign: align.Fill,
+				VAlign: align.Middle,
+				HGrab:  true,
+			})
+			content.AddChild(sep)
+		}
+		header := unison.NewLabel()
+		header.Font = unison.SystemFont
+		header.SetTitle(fmt.Sprintf(i18n.Text("%s (%s%%)"), group.Source.String(),
+			fxp.FromInteger(group.Source.Modifier()).StringWithSign()))
+		content.AddChild(header)
+		addPowerPointsLabel(content, group.Points(), unison.SystemFont)
+		for _, one := range group.Abilities {
+			addPowerNameLabel(content, one.String())
+			addPowerPointsLabel(content, one.AdjustedPoints(), nil)
+		}
+		for _, one := range group.Talents {
+			addPowerNameLabel(content, fmt.Sprintf(i18n.Text("%s (Talent %s)"), one.String(),
+				one.TalentLevel().StringWithSign()))
+			addPowerPointsLabel(content, one.AdjustedPoints(), nil)
+		}
+	}
+	icon := &unison.DrawableSVG{
+		SVG:  svg.GCSTraits,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewOKButtonInfo()}, unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	dialog.RunModal()
+}
+
+func addPowerNameLabel(parent *unison.Panel, text string) {
+	label := unison.NewLabel()
+	label.SetTitle(text)
+	label.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: unison.StdHSpacing * 2}))
+	parent.AddChild(label)
+}
+
+func addPowerPointsLabel(parent *unison.Panel, points fxp.Int, font unison.Font) {
+	label := unison.NewLabel()
+	if font != nil {
+		label.Font = font
+	}
+	label.SetTitle(fmt.Sprintf(i18n.Text("%s pts"), points.String()))
+	label.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+	parent.AddChild(label)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+)
+
+type prereqExplainer interface {
+	fmt.Stringer
+	PrereqExplanation() *gurps.PrereqExplanation
+}
+
+// ShowPrereqExplanation shows which of the target's prerequisites have been met and which have not, along with what the
+// character currently has for each of them. Does nothing if the target has no prerequisites to explain.
+func ShowPrereqExplanation(target any) {
+	explainer, ok := target.(prereqExplainer)
+	if !ok {
+		return
+	}
+	explanation := explainer.PrereqExplanation()
+	if explanation == nil {
+		return
+	}
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing * 4,
+		VSpacing: unison.StdVSpacing,
+	})
+	title := unison.NewLabel()
+	title.Font = unison.SystemFont
+	title.SetTitle(fmt.Sprintf(i18n.Text("Prerequisites for %s"), explainer.String()))
+	title.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	content.AddChild(title)
+	for _, header := range []string{i18n.Text("Requirement"), i18n.Text("Current")} {
+		label := unison.NewLabel()
+		label.Font = fonts.PageLabelSecondary
+		label.SetTitle(header)
+		content.AddChild(label)
+	}
+	addPrereqExplanationRows(content, explanation, 0)
+	icon := &unison.DrawableSVG{
+		SVG:  unison.TriangleExclamationSVG,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewOKButtonInfo()}, unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	dialog.RunModal()
+}
+
+func addPrereqExplanationRows(parent *unison.Panel, explanation *gurps.PrereqExplanation, depth int) {
+	requirement := unison.NewLabel()
+	requirement.SetTitle(explanation.Requirement)
+	height := requirement.Font.LineHeight()
+	if explanation.Satisfied {
+		requirement.Drawable = &unison.DrawableSVG{
+			SVG:  unison.CheckmarkSVG,
+			Size: geom.NewSize(height, height),
+		}
+	} else {
+		requirement.Drawable = &unison.DrawableSVG{
+			SVG:  unison.CircledXSVG,
+			Size: geom.NewSize(height, height),
+		}
+		requirement.OnBackgroundInk = unison.ThemeError
+	}
+	requirement.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: float32(depth) * unison.StdHSpacing * 4}))
+	parent.AddChild(requirement)
+	current := unison.NewLabel()
+	current.SetTitle(explanation.Current)
+	current.SetLayoutData(&unison.FlexLayoutData{HAlign: align.Start})
+	parent.AddChild(current)
+	for _, child := range explanation.Children {
+		addPrereqExplanationRows(parent, child, depth+1)
+	}
+}
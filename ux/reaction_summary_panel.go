@@ -0,0 +1,138 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var _ pageHelper = &ReactionSummaryPanel{}
+
+// ReactionSummaryPanel holds the totals of the reaction modifiers a character has, broken down by the group they apply
+// to and the kind of thing they come from.
+type ReactionSummaryPanel struct {
+	unison.Panel
+	entity    *gurps.Entity
+	groups    []*gurps.ReactionGroup
+	drawStart int
+	drawEnd   int
+}
+
+// NewReactionSummaryPanel creates a new reaction summary panel.
+func NewReactionSummaryPanel(entity *gurps.Entity) *ReactionSummaryPanel {
+	p := &ReactionSummaryPanel{
+		entity:  entity,
+		drawEnd: 1,
+	}
+	p.Self = p
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: i18n.Text("Reaction Summary")},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(2, 1))))
+	p.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	p.rebuild()
+	return p
+}
+
+// HasReactions returns true if the character has any reaction modifiers and the panel should be shown.
+func (p *ReactionSummaryPanel) HasReactions() bool {
+	return len(p.groups) != 0
+}
+
+func (p *ReactionSummaryPanel) rebuild() {
+	p.groups = p.entity.ReactionSummary()
+	kinds := gurps.ReactionSourceKinds(p.groups)
+	p.RemoveAllChildren()
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  len(kinds) + 2,
+		HSpacing: 4,
+	})
+	p.addHeader(i18n.Text("Group"), align.Start)
+	for _, kind := range kinds {
+		p.addHeader(kind.String(), align.End)
+	}
+	p.addHeader(i18n.Text("Total"), align.End)
+	for _, g := range p.groups {
+		group := NewPageLabel(g.Group)
+		group.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		p.AddChild(group)
+		for _, kind := range kinds {
+			var value string
+			if g.Has(kind) {
+				value = g.TotalFor(kind).StringWithSign()
+			}
+			p.addValue(value, "")
+		}
+		sources := make([]string, 0, len(g.Contributions))
+		for _, one := range g.Contributions {
+			sources = append(sources, one.Amount.StringWithSign()+" "+one.Source)
+		}
+		p.addValue(g.Total().StringWithSign(), strings.Join(sources, "\n"))
+	}
+}
+
+func (p *ReactionSummaryPanel) addHeader(title string, hAlign align.Enum) {
+	label := NewPageLabel(title)
+	label.Font = fonts.PageLabelSecondary
+	label.SetLayoutData(&unison.FlexLayoutData{HAlign: hAlign})
+	p.AddChild(label)
+}
+
+func (p *ReactionSummaryPanel) addValue(value, tooltip string) {
+	label := NewPageLabelEnd(value)
+	if tooltip != "" {
+		label.Tooltip = newWrappedTooltip(tooltip)
+	}
+	label.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+	p.AddChild(label)
+}
+
+// Sync the panel to the current data.
+func (p *ReactionSummaryPanel) Sync() {
+	p.rebuild()
+	MarkForLayoutWithinDockable(p)
+}
+
+// OverheadHeight implements pageHelper.
+func (p *ReactionSummaryPanel) OverheadHeight() float32 {
+	return 0
+}
+
+// RowHeights implements pageHelper. The summary is always kept together, so it is reported as a single row.
+func (p *ReactionSummaryPanel) RowHeights() []float32 {
+	return []float32{p.FrameRect().Height}
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *ReactionSummaryPanel) CurrentDrawRowRange() (start, endBefore int) {
+	return p.drawStart, p.drawEnd
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *ReactionSummaryPanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+}
@@ -0,0 +1,337 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+var (
+	_ unison.Dockable            = &RollerDockable{}
+	_ unison.UndoManagerProvider = &RollerDockable{}
+	_ unison.TabCloser           = &RollerDockable{}
+
+	rollerListenerOnce sync.Once
+)
+
+// RollerDockable holds the dice roller, which shows the history of rolls made within the application and allows ad hoc
+// rolls to be made.
+type RollerDockable struct {
+	unison.Panel
+	undoMgr *unison.UndoManager
+	content *unison.Panel
+	scroll  *unison.ScrollPanel
+	scale   int
+	spec    string
+}
+
+// ShowRoller shows the dice roller.
+func ShowRoller() {
+	if Activate(func(d unison.Dockable) bool {
+		_, ok := d.AsPanel().Self.(*RollerDockable)
+		return ok
+	}) {
+		return
+	}
+	newRollerDockable()
+}
+
+// RecordRoll adds the result to the roll history, opening the dice roller if it isn't already open.
+func RecordRoll(r *dice.Result) {
+	if r == nil {
+		return
+	}
+	installRollerListener()
+	dice.GlobalHistory().Add(r)
+	for _, d := range AllDockables() {
+		if _, ok := d.(*RollerDockable); ok {
+			return
+		}
+	}
+	newRollerDockable()
+}
+
+// RollSuccessFor performs a success roll against the effective level on behalf of the entity and records it.
+func RollSuccessFor(entity *gurps.Entity, what string, level fxp.Int) {
+	RecordRoll(dice.RollSuccess(nil, rollerNameFor(entity), what, fxp.AsInteger[int](level.Floor())))
+}
+
+// RollDamageFor performs a damage roll on behalf of the entity and records it.
+func RollDamageFor(entity *gurps.Entity, what, damage string) {
+	RecordRoll(dice.RollDamage(nil, rollerNameFor(entity), what, damage,
+		gurps.SheetSettingsFor(entity).UseModifyingDicePlusAdds))
+}
+
+// RollHitLocationFor rolls a random hit location for an attack with the weapon and records it, along with the
+// effective skill level for an attack against that location. Sub-tables are rolled on as needed.
+func RollHitLocationFor(w *gurps.Weapon) {
+	entity := w.Entity()
+	if entity == nil {
+		return
+	}
+	level := w.SkillLevel(nil)
+	body := gurps.BodyFor(entity)
+	for body != nil && body.Roll != nil {
+		var loc *gurps.HitLocation
+		RecordRoll(dice.RollLocation(nil, rollerNameFor(entity), weaponRollName(w), body.Roll.String(),
+			func(total int) string {
+				if loc = body.LocationForRoll(total); loc == nil {
+					return i18n.Text("no location")
+				}
+				return fmt.Sprintf(i18n.Text("%s, effective skill %s"), loc.TableName,
+					(level + fxp.FromInteger(loc.HitPenalty)).Max(0).String())
+			}))
+		if loc == nil {
+			break
+		}
+		body = loc.SubTable
+	}
+}
+
+func rollerNameFor(entity *gurps.Entity) string {
+	if entity == nil {
+		return ""
+	}
+	return entity.Profile.Name
+}
+
+func installRollerListener() {
+	rollerListenerOnce.Do(func() {
+		dice.GlobalHistory().AddListener(func(_ *dice.Result) {
+			unison.InvokeTask(func() {
+				for _, d := range AllDockables() {
+					if roller, ok := d.(*RollerDockable); ok {
+						roller.rebuild()
+					}
+				}
+			})
+		})
+	})
+}
+
+func newRollerDockable() {
+	installRollerListener()
+	d := &RollerDockable{scale: gurps.GlobalSettings().General.InitialEditorUIScale}
+	d.Self = d
+	d.undoMgr = unison.NewUndoManager(100, func(err error) { errs.Log(err) })
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.rebuild()
+
+	d.scroll = unison.NewScrollPanel()
+	d.scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+	d.scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(d.scroll)
+	d.content.ValidateScrollRoot()
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *RollerDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+
+	toolbar.AddChild(NewDefaultInfoPop())
+	toolbar.AddChild(
+		NewScaleField(
+			gurps.InitialUIScaleMin,
+			gurps.InitialUIScaleMax,
+			func() int { return gurps.GlobalSettings().General.InitialEditorUIScale },
+			func() int { return d.scale },
+			func(scale int) { d.scale = scale },
+			nil,
+			false,
+			false,
+			d.scroll,
+		),
+	)
+
+	specField := NewStringField(nil, "", i18n.Text("Dice"),
+		func() string { return d.spec },
+		func(s string) { d.spec = strings.TrimSpace(s) })
+	specField.SetMinimumTextWidthUsing("10d+10")
+	specField.Tooltip = newWrappedTooltip(i18n.Text(`The dice to roll, e.g. "3d", "2d+1" or "1d6x3"`))
+	specField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	rollButton := unison.NewSVGButton(svg.Randomize)
+	rollButton.Tooltip = newWrappedTooltip(i18n.Text("Roll the dice"))
+	rollButton.ClickCallback = d.rollSpec
+	original := specField.KeyDownCallback
+	specField.KeyDownCallback = func(keyCode unison.KeyCode, mod unison.Modifiers, repeat bool) bool {
+		if keyCode == unison.KeyReturn || keyCode == unison.KeyNumPadEnter {
+			rollButton.Click()
+			return true
+		}
+		return original(keyCode, mod, repeat)
+	}
+	toolbar.AddChild(specField)
+	toolbar.AddChild(rollButton)
+
+	clearButton := unison.NewSVGButton(svg.Trash)
+	clearButton.Tooltip = newWrappedTooltip(i18n.Text("Clear the roll history"))
+	clearButton.ClickCallback = func() {
+		dice.GlobalHistory().Clear()
+		for _, one := range AllDockables() {
+			if roller, ok := one.(*RollerDockable); ok {
+				roller.rebuild()
+			}
+		}
+	}
+	toolbar.AddChild(clearButton)
+
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	return toolbar
+}
+
+func (d *RollerDockable) rollSpec() {
+	if d.spec == "" {
+		return
+	}
+	var entity *gurps.Entity
+	if sheet := ActiveSheet(); sheet != nil {
+		entity = sheet.Entity()
+	}
+	RecordRoll(dice.RollSpec(nil, rollerNameFor(entity), "", d.spec,
+		gurps.SheetSettingsFor(entity).UseModifyingDicePlusAdds))
+}
+
+func (d *RollerDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	results := dice.GlobalHistory().Results()
+	if len(results) == 0 {
+		label := unison.NewLabel()
+		label.OnBackgroundInk = unison.ThemeOnSurface
+		label.SetTitle(i18n.Text("Click on a skill level, attribute, or weapon damage on a sheet to roll it."))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+		d.content.AddChild(label)
+	}
+	for i := len(results) - 1; i >= 0; i-- {
+		r := results[i]
+		when := unison.NewLabel()
+		when.Font = fonts.FieldSecondary
+		when.OnBackgroundInk = unison.ThemeOnSurface
+		when.SetTitle(r.When.Format("15:04:05"))
+		when.Tooltip = newWrappedTooltip(r.When.Format("2006-01-02 15:04:05"))
+		when.SetLayoutData(&unison.FlexLayoutData{VAlign: align.Middle})
+		d.content.AddChild(when)
+
+		text := unison.NewLabel()
+		text.OnBackgroundInk = unison.ThemeOnSurface
+		if r.Kind == dice.SuccessRoll {
+			text.Font = &unison.DynamicFont{
+				Resolver: func() unison.FontDescriptor {
+					desc := unison.DefaultLabelTheme.Font.Descriptor()
+					if r.Critical {
+						desc.Weight = weight.Bold
+					}
+					return desc
+				},
+			}
+			if !r.Success {
+				text.OnBackgroundInk = unison.ThemeError
+			}
+		}
+		text.SetTitle(r.String())
+		text.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			VAlign: align.Middle,
+			HGrab:  true,
+		})
+		d.content.AddChild(text)
+	}
+	d.content.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	d.content.MarkForLayoutAndRedraw()
+}
+
+// TitleIcon implements unison.Dockable
+func (d *RollerDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Randomize,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable
+func (d *RollerDockable) Title() string {
+	return i18n.Text("Dice Roller")
+}
+
+func (d *RollerDockable) String() string {
+	return d.Title()
+}
+
+// Tooltip implements unison.Dockable
+func (d *RollerDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable
+func (d *RollerDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser
+func (d *RollerDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser
+func (d *RollerDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
+
+// UndoManager implements unison.UndoManagerProvider
+func (d *RollerDockable) UndoManager() *unison.UndoManager {
+	return d.undoMgr
+}
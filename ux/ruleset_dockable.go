@@ -0,0 +1,192 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"io/fs"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+var _ GroupedCloser = &rulesetDockable{}
+
+type rulesetDockable struct {
+	SettingsDockable
+	owner      EntityPanel
+	parts      gurps.RulesetParts
+	name       string
+	notes      string
+	nameField  *unison.Field
+	notesField *unison.Field
+}
+
+// ShowRuleset the Ruleset dockable, which allows house rules to be captured into a ruleset file and applied from one.
+// Pass in nil to work with the default sheet settings or a sheet to work with the sheet's.
+func ShowRuleset(owner EntityPanel) {
+	if Activate(func(d unison.Dockable) bool {
+		if s, ok := d.AsPanel().Self.(*rulesetDockable); ok && owner == s.owner {
+			return true
+		}
+		return false
+	}) {
+		return
+	}
+	d := &rulesetDockable{
+		owner: owner,
+		parts: gurps.AllRulesetParts(),
+	}
+	d.Self = d
+	if owner != nil {
+		d.TabTitle = i18n.Text("Ruleset: " + owner.Entity().Profile.Name)
+	} else {
+		d.TabTitle = i18n.Text("Default Ruleset")
+	}
+	d.TabIcon = svg.Settings
+	d.Extensions = []string{gurps.RulesetExt}
+	d.Loader = d.load
+	d.Saver = d.save
+	d.Setup(d.addToStartToolbar, nil, d.initContent)
+}
+
+func (d *rulesetDockable) addToStartToolbar(toolbar *unison.Panel) {
+	helpButton := unison.NewSVGButton(svg.Help)
+	helpButton.Tooltip = newWrappedTooltip(i18n.Text("Help"))
+	helpButton.ClickCallback = func() { HandleLink(nil, "md:User%20Guide/Rulesets") }
+	toolbar.AddChild(helpButton)
+}
+
+func (d *rulesetDockable) CloseWithGroup(other unison.Paneler) bool {
+	return d.owner != nil && d.owner == other
+}
+
+func (d *rulesetDockable) settings() *gurps.SheetSettings {
+	if d.owner != nil {
+		return d.owner.Entity().SheetSettings
+	}
+	return gurps.GlobalSettings().Sheet
+}
+
+func (d *rulesetDockable) initContent(content *unison.Panel) {
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.DefaultLabelTheme.Font.LineHeight(),
+	})
+	d.createDescription(content)
+	d.createParts(content)
+}
+
+func (d *rulesetDockable) createDescription(content *unison.Panel) {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{HAlign: align.Fill})
+	createRulesetHeader(panel, i18n.Text("Description (written when exporting)"), 2)
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Name"), false))
+	d.nameField = unison.NewField()
+	d.nameField.SetText(d.name)
+	d.nameField.ModifiedCallback = func(_, after *unison.FieldState) { d.name = after.Text }
+	d.nameField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	panel.AddChild(d.nameField)
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Notes"), false))
+	d.notesField = unison.NewMultiLineField()
+	d.notesField.SetText(d.notes)
+	d.notesField.ModifiedCallback = func(_, after *unison.FieldState) { d.notes = after.Text }
+	d.notesField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	panel.AddChild(d.notesField)
+	content.AddChild(panel)
+}
+
+func (d *rulesetDockable) createParts(content *unison.Panel) {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{HAlign: align.Fill})
+	createRulesetHeader(panel, i18n.Text("Parts to apply when importing"), 1)
+	d.addPartCheckBox(panel, i18n.Text("Damage progression & optional rules"), &d.parts.Options)
+	d.addPartCheckBox(panel, i18n.Text("Skill difficulty modifiers"), &d.parts.SkillModifiers)
+	d.addPartCheckBox(panel, i18n.Text("Dodge & passive defense customization"), &d.parts.DodgeCustomization)
+	d.addPartCheckBox(panel, i18n.Text("Attributes"), &d.parts.Attributes)
+	d.addPartCheckBox(panel, i18n.Text("Body type"), &d.parts.BodyType)
+	content.AddChild(panel)
+}
+
+func (d *rulesetDockable) addPartCheckBox(panel *unison.Panel, title string, value *bool) {
+	checkbox := unison.NewCheckBox()
+	checkbox.SetTitle(title)
+	checkbox.State = check.FromBool(*value)
+	checkbox.ClickCallback = func() { *value = checkbox.State == check.On }
+	panel.AddChild(checkbox)
+}
+
+func createRulesetHeader(panel *unison.Panel, title string, hspan int) {
+	label := unison.NewLabel()
+	desc := label.Font.Descriptor()
+	desc.Weight = weight.Bold
+	label.Font = desc.Font()
+	label.SetTitle(title)
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: hspan})
+	panel.AddChild(label)
+	sep := unison.NewSeparator()
+	sep.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  hspan,
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	panel.AddChild(sep)
+}
+
+func (d *rulesetDockable) load(fileSystem fs.FS, filePath string) error {
+	ruleset, err := gurps.NewRulesetFromFile(fileSystem, filePath)
+	if err != nil {
+		return err
+	}
+	ruleset.ApplyTo(d.settings(), d.parts)
+	d.name = ruleset.Name
+	d.notes = ruleset.Notes
+	d.nameField.SetText(d.name)
+	d.notesField.SetText(d.notes)
+	var entity *gurps.Entity
+	if d.owner != nil {
+		entity = d.owner.Entity()
+	}
+	for _, one := range AllDockables() {
+		if s, ok := one.(gurps.SheetSettingsResponder); ok {
+			s.SheetSettingsUpdated(entity, true)
+		}
+	}
+	d.MarkForRedraw()
+	return nil
+}
+
+func (d *rulesetDockable) save(filePath string) error {
+	ruleset := gurps.NewRulesetFromSheetSettings(d.settings())
+	ruleset.Name = d.name
+	ruleset.Notes = d.notes
+	return ruleset.Save(filePath)
+}
@@ -0,0 +1,363 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+func (d *TableDockable[T]) savedFilters() []*gurps.SavedFilter {
+	return gurps.GlobalSettings().SavedFiltersFor(d.extension)
+}
+
+func (d *TableDockable[T]) setSavedFilters(filters []*gurps.SavedFilter) {
+	gurps.GlobalSettings().SetSavedFiltersFor(d.extension, filters)
+	for _, one := range AllDockables() {
+		if other, ok := one.(*TableDockable[T]); ok && other.extension == d.extension {
+			if other.activeFilter != nil && !slices.Contains(filters, other.activeFilter) {
+				other.activeFilter = nil
+				other.ApplyFilter(SelectedTags(other.filterPopup))
+			}
+			other.rebuildSmartGroups()
+		}
+	}
+}
+
+func (d *TableDockable[T]) createSavedFiltersButton() *unison.Button {
+	b := unison.NewSVGButton(svg.Star)
+	b.Tooltip = newWrappedTooltip(i18n.Text("Saved filters"))
+	b.ClickCallback = func() { d.showSavedFiltersMenu(b) }
+	return b
+}
+
+func (d *TableDockable[T]) showSavedFiltersMenu(b *unison.Button) {
+	f := unison.DefaultMenuFactory()
+	id := unison.ContextMenuIDFlag
+	m := f.NewMenu(id, "", nil)
+	for _, one := range d.savedFilters() {
+		id++
+		item := f.NewItem(id, one.Name, unison.KeyBinding{}, nil,
+			func(_ unison.MenuItem) { d.applySavedFilter(one) })
+		item.SetCheckState(check.FromBool(one == d.activeFilter))
+		m.InsertItem(-1, item)
+	}
+	if m.Count() != 0 {
+		m.InsertSeparator(-1, true)
+	}
+	id++
+	m.InsertItem(-1, f.NewItem(id, i18n.Text("Save Current Filter…"), unison.KeyBinding{}, nil,
+		func(_ unison.MenuItem) { d.saveCurrentFilter() }))
+	id++
+	m.InsertItem(-1, f.NewItem(id, i18n.Text("Manage Saved Filters…"), unison.KeyBinding{},
+		func(_ unison.MenuItem) bool { return len(d.savedFilters()) != 0 },
+		func(_ unison.MenuItem) { d.manageSavedFilters() }))
+	id++
+	m.InsertItem(-1, f.NewItem(id, i18n.Text("Clear Filter"), unison.KeyBinding{},
+		func(_ unison.MenuItem) bool { return d.filterActive() },
+		func(_ unison.MenuItem) { d.applySavedFilter(nil) }))
+	m.Popup(b.RectToRoot(b.ContentRect(true)), 0)
+}
+
+func (d *TableDockable[T]) filterActive() bool {
+	return d.activeFilter != nil || strings.TrimSpace(d.filterField.GetFieldState().Text) != "" ||
+		len(SelectedTags(d.filterPopup)) != 0
+}
+
+// applySavedFilter replaces the current filtering with that of the saved filter. Pass nil to clear all filtering.
+func (d *TableDockable[T]) applySavedFilter(filter *gurps.SavedFilter) {
+	d.activeFilter = filter
+	var text string
+	var namesOnly bool
+	var tags []string
+	if filter != nil {
+		text = filter.Text
+		namesOnly = filter.NamesOnly
+		tags = filter.Tags
+	}
+	d.namesOnlyCheckBox.State = check.FromBool(namesOnly)
+	d.namesOnlyCheckBox.MarkForRedraw()
+	d.filterPopup.WillShowMenuCallback(d.filterPopup)
+	if len(tags) != 0 {
+		d.filterPopup.Select(tags...)
+	}
+	if len(SelectedTags(d.filterPopup)) == 0 {
+		d.filterPopup.SelectIndex(0)
+	}
+	d.filterField.SetText(text)
+	d.ApplyFilter(SelectedTags(d.filterPopup))
+}
+
+func (d *TableDockable[T]) saveCurrentFilter() {
+	filter := &gurps.SavedFilter{
+		Text:      strings.TrimSpace(d.filterField.GetFieldState().Text),
+		NamesOnly: d.namesOnlyCheckBox.State == check.On,
+		Tags:      SelectedTags(d.filterPopup),
+	}
+	if d.activeFilter != nil {
+		filter.Name = d.activeFilter.Name
+		filter.TechLevel = d.activeFilter.TechLevel
+		filter.PointRange = d.activeFilter.PointRange
+		filter.MinPoints = d.activeFilter.MinPoints
+		filter.MaxPoints = d.activeFilter.MaxPoints
+		filter.Pinned = d.activeFilter.Pinned
+	}
+
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Name"), false))
+	nameField := NewStringField(nil, "", i18n.Text("Name"), func() string { return filter.Name },
+		func(s string) { filter.Name = s })
+	nameField.SetMinimumTextWidthUsing(strings.Repeat("M", 20))
+	content.AddChild(nameField)
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Criteria"), false))
+	content.AddChild(NewNonEditableField(func(field *NonEditableField) { field.SetTitle(describeFilterBasics(filter)) }))
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Tech Level"), false))
+	content.AddChild(NewStringField(nil, "", i18n.Text("Tech Level"), func() string { return filter.TechLevel },
+		func(s string) { filter.TechLevel = strings.TrimSpace(s) }))
+	content.AddChild(unison.NewPanel())
+	pointsPanel := unison.NewPanel()
+	pointsPanel.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing,
+	})
+	minField := NewDecimalField(nil, "", i18n.Text("Minimum Points"), func() fxp.Int { return filter.MinPoints },
+		func(v fxp.Int) { filter.MinPoints = v }, fxp.Min, fxp.Max, false, false)
+	maxField := NewDecimalField(nil, "", i18n.Text("Maximum Points"), func() fxp.Int { return filter.MaxPoints },
+		func(v fxp.Int) { filter.MaxPoints = v }, fxp.Min, fxp.Max, false, false)
+	pointsCheckBox := unison.NewCheckBox()
+	pointsCheckBox.SetTitle(i18n.Text("Points from"))
+	pointsCheckBox.State = check.FromBool(filter.PointRange)
+	pointsCheckBox.ClickCallback = func() {
+		filter.PointRange = pointsCheckBox.State == check.On
+		minField.SetEnabled(filter.PointRange)
+		maxField.SetEnabled(filter.PointRange)
+	}
+	minField.SetEnabled(filter.PointRange)
+	maxField.SetEnabled(filter.PointRange)
+	pointsPanel.AddChild(pointsCheckBox)
+	pointsPanel.AddChild(minField)
+	pointsPanel.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("to"), false))
+	pointsPanel.AddChild(maxField)
+	content.AddChild(pointsPanel)
+	content.AddChild(unison.NewPanel())
+	pinnedCheckBox := unison.NewCheckBox()
+	pinnedCheckBox.SetTitle(i18n.Text("Show as a smart group above the table"))
+	pinnedCheckBox.State = check.FromBool(filter.Pinned)
+	pinnedCheckBox.ClickCallback = func() { filter.Pinned = pinnedCheckBox.State == check.On }
+	content.AddChild(pinnedCheckBox)
+
+	dialog, err := unison.NewDialog(nil, nil, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption(), unison.NotResizableWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	nameField.RequestFocus()
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	if filter.Name = strings.TrimSpace(filter.Name); filter.Name == "" {
+		filter.Name = i18n.Text("Unnamed Filter")
+	}
+	if filter.MaxPoints < filter.MinPoints {
+		filter.MinPoints, filter.MaxPoints = filter.MaxPoints, filter.MinPoints
+	}
+	filters := slices.Clone(d.savedFilters())
+	if i := slices.IndexFunc(filters, func(one *gurps.SavedFilter) bool {
+		return strings.EqualFold(one.Name, filter.Name)
+	}); i != -1 {
+		filters[i] = filter
+	} else {
+		filters = append(filters, filter)
+	}
+	d.activeFilter = filter
+	d.setSavedFilters(filters)
+	d.ApplyFilter(SelectedTags(d.filterPopup))
+}
+
+func describeFilterBasics(filter *gurps.SavedFilter) string {
+	var parts []string
+	if filter.Text != "" {
+		if filter.NamesOnly {
+			parts = append(parts, fmt.Sprintf(i18n.Text(`name contains "%s"`), filter.Text))
+		} else {
+			parts = append(parts, fmt.Sprintf(i18n.Text(`contains "%s"`), filter.Text))
+		}
+	}
+	if len(filter.Tags) != 0 {
+		parts = append(parts, fmt.Sprintf(i18n.Text("tagged %s"), strings.Join(filter.Tags, ", ")))
+	}
+	if len(parts) == 0 {
+		return i18n.Text("No text or tag criteria")
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (d *TableDockable[T]) manageSavedFilters() {
+	filters := make([]*gurps.SavedFilter, 0, len(d.savedFilters()))
+	for _, one := range d.savedFilters() {
+		filters = append(filters, one.Clone())
+	}
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	var rebuild func()
+	rebuild = func() {
+		content.RemoveAllChildren()
+		if len(filters) == 0 {
+			label := unison.NewLabel()
+			label.SetTitle(i18n.Text("No saved filters"))
+			label.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+			content.AddChild(label)
+		}
+		for i, one := range filters {
+			label := unison.NewLabel()
+			label.SetTitle(one.Name)
+			label.Tooltip = newWrappedTooltip(describeFilterBasics(one))
+			label.SetLayoutData(&unison.FlexLayoutData{HGrab: true, VAlign: align.Middle})
+			content.AddChild(label)
+			pinned := unison.NewCheckBox()
+			pinned.SetTitle(i18n.Text("Smart group"))
+			pinned.State = check.FromBool(one.Pinned)
+			pinned.ClickCallback = func() { one.Pinned = pinned.State == check.On }
+			content.AddChild(pinned)
+			deleteButton := unison.NewSVGButton(svg.Trash)
+			deleteButton.Tooltip = newWrappedTooltip(i18n.Text("Remove this filter"))
+			deleteButton.ClickCallback = func() {
+				filters = slices.Delete(filters, i, i+1)
+				rebuild()
+			}
+			content.AddChild(deleteButton)
+		}
+		content.MarkForLayoutRecursivelyUpward()
+		if wnd := content.Window(); wnd != nil {
+			wnd.Pack()
+		}
+	}
+	rebuild()
+	content.SetLayoutData(&unison.FlexLayoutData{MinSize: geom.Size{Width: 300}})
+	dialog, err := unison.NewDialog(nil, nil, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	var active *gurps.SavedFilter
+	if d.activeFilter != nil {
+		if i := slices.IndexFunc(filters, func(one *gurps.SavedFilter) bool {
+			return one.Name == d.activeFilter.Name
+		}); i != -1 {
+			active = filters[i]
+		}
+	}
+	d.activeFilter = active
+	d.setSavedFilters(filters)
+}
+
+func (d *TableDockable[T]) createSmartGroupsPanel() *unison.Panel {
+	d.smartGroups = unison.NewPanel()
+	d.smartGroups.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	d.rebuildSmartGroups()
+	return d.smartGroups
+}
+
+// rebuildSmartGroups creates a button for each pinned filter, so that they can be switched between with a single click.
+// Nothing is shown when no filters have been pinned.
+func (d *TableDockable[T]) rebuildSmartGroups() {
+	if d.smartGroups == nil {
+		return
+	}
+	d.smartGroups.RemoveAllChildren()
+	d.smartGroups.SetBorder(nil)
+	d.smartGroup = nil
+	d.smartGroupButtons = make(map[*gurps.SavedFilter]*unison.Button)
+	var pinned []*gurps.SavedFilter
+	for _, one := range d.savedFilters() {
+		if one.Pinned {
+			pinned = append(pinned, one)
+		}
+	}
+	if len(pinned) != 0 {
+		d.smartGroups.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+			geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+		d.smartGroup = unison.NewGroup()
+		d.smartGroupButtons[nil] = d.newSmartGroupButton(i18n.Text("All"), nil)
+		for _, one := range pinned {
+			d.smartGroupButtons[one] = d.newSmartGroupButton(one.Name, one)
+		}
+	}
+	d.smartGroups.SetLayout(&unison.FlexLayout{
+		Columns:  max(len(d.smartGroups.Children()), 1),
+		HSpacing: unison.StdHSpacing,
+	})
+	d.syncSmartGroupSelection()
+	d.MarkForLayoutRecursivelyUpward()
+	d.MarkForRedraw()
+}
+
+func (d *TableDockable[T]) newSmartGroupButton(title string, filter *gurps.SavedFilter) *unison.Button {
+	b := unison.NewButton()
+	b.Sticky = true
+	b.SetTitle(title)
+	b.ClickCallback = func() { d.applySavedFilter(filter) }
+	if filter != nil {
+		b.Tooltip = newWrappedTooltip(describeFilterBasics(filter))
+	}
+	d.smartGroup.Add(b)
+	d.smartGroups.AddChild(b)
+	return b
+}
+
+func (d *TableDockable[T]) syncSmartGroupSelection() {
+	if d.smartGroup == nil {
+		return
+	}
+	var target *unison.Button
+	if d.activeFilter != nil {
+		target = d.smartGroupButtons[d.activeFilter]
+	} else if !d.filterActive() {
+		target = d.smartGroupButtons[nil]
+	}
+	if target != nil {
+		d.smartGroup.Select(target)
+	} else {
+		d.smartGroup.Select(nil)
+	}
+	for _, one := range d.smartGroupButtons {
+		one.MarkForRedraw()
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+const automationScriptTimeout = 30 * time.Second
+
+var (
+	_ unison.Dockable  = &ScriptConsoleDockable{}
+	_ unison.TabCloser = &ScriptConsoleDockable{}
+)
+
+// scriptTarget wraps a sheet so that it can be shown in a popup menu.
+type scriptTarget struct {
+	sheet *Sheet
+}
+
+func (t scriptTarget) String() string {
+	return t.sheet.Title()
+}
+
+// entityState holds a snapshot of an entity for undo purposes.
+type entityState struct {
+	Owner *Sheet
+	Data  []byte
+}
+
+// Apply restores the snapshot into the owning sheet's entity.
+func (s *entityState) Apply() {
+	entity := s.Owner.Entity()
+	entity.DiscardCaches()
+	if err := jio.DecompressAndDeserialize(s.Data, entity); err != nil {
+		errs.Log(err)
+		return
+	}
+	entity.Recalculate()
+	s.Owner.Rebuild(true)
+	MarkModified(s.Owner)
+}
+
+// ScriptConsoleDockable provides a place to write and run automation scripts against an open sheet.
+type ScriptConsoleDockable struct {
+	unison.Panel
+	targetPopup *unison.PopupMenu[scriptTarget]
+	editor      *unison.Field
+	output      *unison.Panel
+	scroll      *unison.ScrollPanel
+}
+
+// ShowScriptConsole shows the script console.
+func ShowScriptConsole() {
+	showScriptConsole()
+}
+
+func showScriptConsole() *ScriptConsoleDockable {
+	var existing *ScriptConsoleDockable
+	if Activate(func(d unison.Dockable) bool {
+		var ok bool
+		existing, ok = d.AsPanel().Self.(*ScriptConsoleDockable)
+		return ok
+	}) {
+		return existing
+	}
+	target := ActiveSheet()
+	d := &ScriptConsoleDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.editor = unison.NewMultiLineField()
+	d.editor.Font = unison.MonospacedFont
+	d.editor.Watermark = i18n.Text("Enter a script to run against the selected sheet")
+	d.editor.SetLayoutData(&unison.FlexLayoutData{
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		SizeHint: geom.Size{Height: 200},
+		HGrab:    true,
+	})
+
+	d.output = unison.NewPanel()
+	d.output.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	d.output.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.scroll = unison.NewScrollPanel()
+	d.scroll.SetContent(d.output, behavior.HintedFill, behavior.Fill)
+	d.scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar(target))
+	d.AddChild(d.editor)
+	d.AddChild(d.scroll)
+	PlaceInDock(d, dgroup.Editors, false)
+	d.editor.RequestFocus()
+	return d
+}
+
+func (d *ScriptConsoleDockable) createToolbar(target *Sheet) *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+
+	runButton := unison.NewSVGButton(svg.Forward)
+	runButton.Tooltip = newWrappedTooltip(i18n.Text("Run the script against the selected sheet"))
+	runButton.ClickCallback = d.run
+	toolbar.AddChild(runButton)
+
+	d.targetPopup = unison.NewPopupMenu[scriptTarget]()
+	d.targetPopup.WillShowMenuCallback = func(popup *unison.PopupMenu[scriptTarget]) {
+		d.fillTargets(popup)
+	}
+	if target != nil {
+		d.targetPopup.AddItem(scriptTarget{sheet: target})
+		d.targetPopup.SelectIndex(0)
+	} else {
+		d.fillTargets(d.targetPopup)
+	}
+	toolbar.AddChild(d.targetPopup)
+
+	openButton := unison.NewSVGButton(svg.OpenFolder)
+	openButton.Tooltip = newWrappedTooltip(i18n.Text("Load a script from a file"))
+	openButton.ClickCallback = d.load
+	toolbar.AddChild(openButton)
+
+	saveButton := unison.NewSVGButton(svg.Download)
+	saveButton.Tooltip = newWrappedTooltip(i18n.Text("Save the script to a file"))
+	saveButton.ClickCallback = d.save
+	toolbar.AddChild(saveButton)
+
+	clearButton := unison.NewSVGButton(svg.Trash)
+	clearButton.Tooltip = newWrappedTooltip(i18n.Text("Clear the output"))
+	clearButton.ClickCallback = func() {
+		d.output.RemoveAllChildren()
+		d.scroll.MarkForLayoutAndRedraw()
+	}
+	toolbar.AddChild(clearButton)
+
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *ScriptConsoleDockable) fillTargets(popup *unison.PopupMenu[scriptTarget]) {
+	current, hasCurrent := popup.Selected()
+	popup.RemoveAllItems()
+	for _, sheet := range OpenSheets(nil) {
+		popup.AddItem(scriptTarget{sheet: sheet})
+	}
+	if hasCurrent {
+		popup.Select(current)
+	}
+	if popup.SelectedIndex() == -1 && popup.ItemCount() > 0 {
+		popup.SelectIndex(0)
+	}
+}
+
+func (d *ScriptConsoleDockable) run() {
+	d.fillTargets(d.targetPopup)
+	target, ok := d.targetPopup.Selected()
+	if !ok {
+		d.appendOutput(i18n.Text("No sheet is open to run the script against."), true)
+		return
+	}
+	runAutomationScript(target.sheet, target.sheet.Title(), d.editor.Text())
+}
+
+func (d *ScriptConsoleDockable) load() {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.AutomationScriptExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	if !dialog.RunModal() {
+		return
+	}
+	p := dialog.Path()
+	global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(p))
+	data, err := os.ReadFile(p)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load script"), err)
+		return
+	}
+	d.editor.SetText(string(data))
+}
+
+func (d *ScriptConsoleDockable) save() {
+	dialog := unison.NewSaveDialog()
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	dialog.SetAllowedExtensions(gurps.AutomationScriptExt)
+	if !dialog.RunModal() {
+		return
+	}
+	if p, ok := unison.ValidateSaveFilePath(dialog.Path(), gurps.AutomationScriptExt, false); ok {
+		global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(p))
+		if err := os.WriteFile(p, []byte(d.editor.Text()), 0o640); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to save script"), err)
+		}
+	}
+}
+
+func (d *ScriptConsoleDockable) appendOutput(text string, emphasize bool) {
+	label := unison.NewLabel()
+	if !emphasize {
+		label.Font = unison.MonospacedFont
+	}
+	label.SetTitle(text)
+	d.output.AddChild(label)
+	d.scroll.MarkForLayoutAndRedraw()
+	d.scroll.ValidateLayout()
+	d.scroll.ScrollRectIntoView(label.FrameRect())
+}
+
+func (d *ScriptConsoleDockable) appendResult(title string, result *gurps.AutomationResult, err error) {
+	d.appendOutput(fmt.Sprintf(i18n.Text("Ran %s at %s"), title, time.Now().Format(time.TimeOnly)), true)
+	if result != nil {
+		for _, line := range result.Output {
+			d.appendOutput(line, false)
+		}
+		for _, issue := range result.Issues {
+			d.appendOutput(fmt.Sprintf(i18n.Text("Issue: %s"), issue), false)
+		}
+		if result.Value != "" {
+			d.appendOutput("=> "+result.Value, false)
+		}
+	}
+	if err != nil {
+		d.appendOutput(fmt.Sprintf(i18n.Text("Error: %s"), err.Error()), false)
+	}
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *ScriptConsoleDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Script,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *ScriptConsoleDockable) Title() string {
+	return i18n.Text("Script Console")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *ScriptConsoleDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *ScriptConsoleDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *ScriptConsoleDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *ScriptConsoleDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
+
+// runAutomationScript runs the script against the sheet's entity, registering an undo edit if it changed anything. The
+// script console is shown with the results if the script produced output, reported issues, or failed.
+func runAutomationScript(sheet *Sheet, title, text string) {
+	entity := sheet.Entity()
+	before, err := jio.SerializeAndCompress(entity)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to run script"), err)
+		return
+	}
+	result, err := gurps.RunAutomationScript(entity, text, automationScriptTimeout)
+	if result != nil && result.Modified {
+		if after, err2 := jio.SerializeAndCompress(entity); err2 != nil {
+			errs.Log(err2)
+		} else {
+			addUndo(sheet.UndoManager(), &unison.UndoEdit[*entityState]{
+				ID:         unison.NextUndoID(),
+				EditName:   fmt.Sprintf(i18n.Text("Run %s"), title),
+				UndoFunc:   func(e *unison.UndoEdit[*entityState]) { e.BeforeData.Apply() },
+				RedoFunc:   func(e *unison.UndoEdit[*entityState]) { e.AfterData.Apply() },
+				BeforeData: &entityState{Owner: sheet, Data: before},
+				AfterData:  &entityState{Owner: sheet, Data: after},
+			})
+		}
+		sheet.Rebuild(true)
+		MarkModified(sheet)
+	}
+	if err != nil || (result != nil && (len(result.Output) != 0 || len(result.Issues) != 0 || result.Value != "")) {
+		showScriptConsole().appendResult(title, result, err)
+	}
+}
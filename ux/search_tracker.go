@@ -16,6 +16,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/check"
 )
@@ -212,3 +213,25 @@ func showSearchResolvedRef[T gurps.NodeTypes](table *unison.Table[*Node[T]], row
 	table.SelectByIndex(rowIndex)
 	table.ScrollRowIntoView(rowIndex)
 }
+
+func locateTableRowByID[T gurps.NodeTypes](table *unison.Table[*Node[T]], id tid.TID) bool {
+	if row := findTableRowByID(id, table.RootRows()); row != nil {
+		showSearchResolvedRef(table, row)
+		return true
+	}
+	return false
+}
+
+func findTableRowByID[T gurps.NodeTypes](id tid.TID, rows []*Node[T]) *Node[T] {
+	for _, row := range rows {
+		if row.dataAsNode.ID() == id {
+			return row
+		}
+		if row.CanHaveChildren() {
+			if found := findTableRowByID(id, row.Children()); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,629 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/dice"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/gurps/export"
+	"github.com/richardwilkes/gcs/v5/model/restapi"
+	"github.com/richardwilkes/gcs/v5/model/session"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+const (
+	sessionSyncDelay = time.Second
+	sessionTopSkills = 5
+)
+
+var (
+	_ unison.Dockable  = &SessionDockable{}
+	_ unison.TabCloser = &SessionDockable{}
+)
+
+// Only accessed from the UI thread.
+var activeSession *SessionDockable
+
+// sessionMember holds a player connected to a hosted session, along with the copies of the sheets they are sharing.
+type sessionMember struct {
+	player *session.Player
+	sheets []*gurps.Entity
+}
+
+// SessionDockable shows the state of the shared session this instance is either hosting as the GM or has joined as a
+// player.
+type SessionDockable struct {
+	unison.Panel
+	host       *session.Host
+	client     *session.Client
+	status     *unison.Label
+	content    *unison.Panel
+	log        *unison.Panel
+	logScroll  *unison.ScrollPanel
+	members    []*sessionMember
+	shared     map[tid.TID]*gurps.Entity
+	pending    map[*gurps.Entity]bool
+	syncQueued bool
+}
+
+// ShowHostSessionDialog asks for the details of a session and starts hosting it.
+func ShowHostSessionDialog() {
+	if activeSession != nil {
+		ActivateDockable(activeSession)
+		return
+	}
+	gs := gurps.GlobalSettings().General
+	port := gs.SessionPort
+	if port == 0 {
+		port = session.DefaultPort
+	}
+	var password string
+	content := newSessionDialogContent()
+	portLabel := i18n.Text("Port")
+	content.AddChild(NewFieldLeadingLabel(portLabel, false))
+	content.AddChild(NewIntegerField(nil, "", portLabel, func() int { return port }, func(v int) { port = v }, 1024,
+		65535, false, false))
+	addSessionPasswordField(content, &password,
+		i18n.Text(`The password players must enter to join. Leave empty to let anyone on the network join.`))
+	if !runSessionDialog(content, i18n.Text("Host")) {
+		return
+	}
+	d := newSessionDockable()
+	d.host = &session.Host{
+		Password: password,
+		Invoke:   unison.InvokeTask,
+		Joined:   d.playerJoined,
+		Left:     d.playerLeft,
+		Received: d.receivedFromPlayer,
+	}
+	if err := d.host.Start(port); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to host the session"), err)
+		return
+	}
+	gs.SessionPort = port
+	if addresses := sessionHostAddresses(port); len(addresses) != 0 {
+		d.status.SetTitle(fmt.Sprintf(i18n.Text("Hosting a session at %s"), strings.Join(addresses, ", ")))
+	} else {
+		d.status.SetTitle(fmt.Sprintf(i18n.Text("Hosting a session on port %d"), port))
+	}
+	d.rebuild()
+	d.place()
+}
+
+// ShowJoinSessionDialog asks for the details of a session and joins it, sharing the open character sheets.
+func ShowJoinSessionDialog() {
+	if activeSession != nil {
+		ActivateDockable(activeSession)
+		return
+	}
+	gs := gurps.GlobalSettings().General
+	address := gs.SessionAddress
+	player := gs.SessionPlayerName
+	if player == "" {
+		player = gs.DefaultPlayerName
+	}
+	var password string
+	content := newSessionDialogContent()
+	addressLabel := i18n.Text("GM Address")
+	content.AddChild(NewFieldLeadingLabel(addressLabel, false))
+	addressField := NewStringField(nil, "", addressLabel, func() string { return address },
+		func(s string) { address = strings.TrimSpace(s) })
+	addressField.Tooltip = newWrappedTooltip(fmt.Sprintf(i18n.Text(`The host name or IP address of the GM's machine. The port may be omitted if the GM is using the default of %d.`), session.DefaultPort))
+	addressField.SetMinimumTextWidthUsing("000.000.000.000:00000")
+	content.AddChild(addressField)
+	playerLabel := i18n.Text("Player Name")
+	content.AddChild(NewFieldLeadingLabel(playerLabel, false))
+	content.AddChild(NewStringField(nil, "", playerLabel, func() string { return player },
+		func(s string) { player = strings.TrimSpace(s) }))
+	addSessionPasswordField(content, &password, i18n.Text("The password the GM set for the session"))
+	if !runSessionDialog(content, i18n.Text("Join")) {
+		return
+	}
+	if address == "" || player == "" {
+		Workspace.ErrorHandler(i18n.Text("Unable to join the session"),
+			errs.New(i18n.Text("Both the GM address and a player name are required")))
+		return
+	}
+	d := newSessionDockable()
+	d.client = &session.Client{
+		Invoke:       unison.InvokeTask,
+		Received:     d.receivedFromGM,
+		Disconnected: d.disconnected,
+	}
+	if err := d.client.Join(address, player, password); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to join the session"), err)
+		return
+	}
+	gs.SessionAddress = address
+	gs.SessionPlayerName = player
+	d.status.SetTitle(fmt.Sprintf(i18n.Text("Joined the session at %s as %s"), address, player))
+	d.shared = make(map[tid.TID]*gurps.Entity)
+	d.pending = make(map[*gurps.Entity]bool)
+	d.place()
+	QueueSessionSync(nil)
+}
+
+func newSessionDialogContent() *unison.Panel {
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	return content
+}
+
+func addSessionPasswordField(content *unison.Panel, password *string, tooltip string) {
+	label := i18n.Text("Password")
+	content.AddChild(NewFieldLeadingLabel(label, false))
+	field := NewStringField(nil, "", label, func() string { return *password }, func(s string) { *password = s })
+	field.ObscurementRune = '•'
+	field.Tooltip = newWrappedTooltip(tooltip)
+	content.AddChild(field)
+}
+
+func runSessionDialog(content *unison.Panel, okTitle string) bool {
+	icon := &unison.DrawableSVG{
+		SVG:  svg.Link,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	okButton := unison.NewOKButtonInfo()
+	okButton.Title = okTitle
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), okButton},
+		unison.FloatingWindowOption(), unison.NotResizableWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return false
+	}
+	return dialog.RunModal() == unison.ModalResponseOK
+}
+
+// sessionHostAddresses returns the addresses players on the local network may use to reach this machine.
+func sessionHostAddresses(port int) []string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		errs.Log(err)
+		return nil
+	}
+	var list []string
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			list = append(list, net.JoinHostPort(ipNet.IP.String(), strconv.Itoa(port)))
+		}
+	}
+	return list
+}
+
+func newSessionDockable() *SessionDockable {
+	d := &SessionDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	endButton := unison.NewSVGButton(svg.Not)
+	endButton.Tooltip = newWrappedTooltip(i18n.Text("End the session"))
+	endButton.ClickCallback = func() { d.AttemptClose() }
+	toolbar.AddChild(endButton)
+	d.status = unison.NewLabel()
+	toolbar.AddChild(d.status)
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.log = unison.NewPanel()
+	d.log.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	d.log.SetLayout(&unison.FlexLayout{Columns: 1})
+	d.logScroll = unison.NewScrollPanel()
+	d.logScroll.SetBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{}, geom.Insets{Top: 1}, false))
+	d.logScroll.SetContent(d.log, behavior.HintedFill, behavior.Fill)
+	d.logScroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		SizeHint: geom.Size{Height: 150},
+		HGrab:    true,
+	})
+
+	d.AddChild(toolbar)
+	d.AddChild(scroll)
+	d.AddChild(d.logScroll)
+	return d
+}
+
+func (d *SessionDockable) place() {
+	activeSession = d
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *SessionDockable) appendLog(text string) {
+	label := unison.NewLabel()
+	label.SetTitle(time.Now().Format(time.TimeOnly) + " " + text)
+	d.log.AddChild(label)
+	d.logScroll.MarkForLayoutAndRedraw()
+	d.logScroll.ValidateLayout()
+	d.logScroll.ScrollRectIntoView(label.FrameRect())
+}
+
+func (d *SessionDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	if d.host != nil {
+		d.rebuildMembers()
+	} else {
+		d.rebuildShared()
+	}
+	d.content.MarkForLayoutAndRedraw()
+}
+
+func (d *SessionDockable) rebuildMembers() {
+	if len(d.members) == 0 {
+		d.addContentText(i18n.Text("No players have joined yet."))
+		return
+	}
+	for _, m := range d.members {
+		d.addContentText(fmt.Sprintf(i18n.Text("%s (%s)"), m.player.Name, m.player.Address))
+		if len(m.sheets) == 0 {
+			d.addContentText(i18n.Text("Not sharing any sheets."))
+		}
+		for _, entity := range m.sheets {
+			d.content.AddChild(newGMScreenBlockPanel(gurps.NewGMScreenBlock(entity, sessionTopSkills)))
+			d.content.AddChild(d.createSheetButtons(m, entity))
+		}
+	}
+}
+
+func (d *SessionDockable) rebuildShared() {
+	if len(d.shared) == 0 {
+		d.addContentText(i18n.Text("No character sheets are open to share."))
+		return
+	}
+	names := make([]string, 0, len(d.shared))
+	for _, entity := range d.shared {
+		names = append(names, sessionSheetName(entity))
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		d.addContentText(fmt.Sprintf(i18n.Text("Sharing %s"), name))
+	}
+}
+
+func (d *SessionDockable) addContentText(text string) {
+	label := unison.NewLabel()
+	label.SetTitle(text)
+	d.content.AddChild(label)
+}
+
+func (d *SessionDockable) createSheetButtons(m *sessionMember, entity *gurps.Entity) *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+
+	viewButton := unison.NewSVGButton(svg.MarkdownFile)
+	viewButton.Tooltip = newWrappedTooltip(i18n.Text("View the full sheet"))
+	viewButton.ClickCallback = func() { viewSessionSheet(m, entity) }
+	panel.AddChild(viewButton)
+
+	hitButton := unison.NewSVGButton(svg.FirstAidKit)
+	hitButton.Tooltip = newWrappedTooltip(i18n.Text("Record a hit against this character"))
+	hitButton.ClickCallback = func() {
+		if locationID, damageType, basic, ok := askForHit(entity); ok {
+			m.player.Send(&session.Message{
+				Type:  session.DamageMsg,
+				Sheet: entity.ID,
+				Damage: &session.Damage{
+					Location: locationID,
+					Type:     damageType,
+					Basic:    basic,
+				},
+			})
+			d.appendLog(fmt.Sprintf(i18n.Text("Sent a hit of %d %s to the %s of %s"), basic, damageType, locationID,
+				sessionSheetName(entity)))
+		}
+	}
+	panel.AddChild(hitButton)
+
+	conditionButton := unison.NewSVGButton(svg.MagicWand)
+	conditionButton.Tooltip = newWrappedTooltip(i18n.Text("Add a condition to this character"))
+	conditionButton.ClickCallback = func() {
+		if c := askForCondition(entity); c != nil {
+			m.player.Send(&session.Message{
+				Type:      session.ConditionMsg,
+				Sheet:     entity.ID,
+				Condition: c,
+			})
+			d.appendLog(fmt.Sprintf(i18n.Text("Sent the %s condition to %s"), c.Name, sessionSheetName(entity)))
+		}
+	}
+	panel.AddChild(conditionButton)
+
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  len(panel.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+	return panel
+}
+
+func viewSessionSheet(m *sessionMember, entity *gurps.Entity) {
+	var buffer bytes.Buffer
+	if err := export.WriteMarkdown(&buffer, entity); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to view the sheet"), err)
+		return
+	}
+	title := fmt.Sprintf(i18n.Text("%s (%s)"), sessionSheetName(entity), m.player.Name)
+	d, err := NewMarkdownDockableWithContent(title, buffer.String(), false, false)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to view the sheet"), err)
+		return
+	}
+	DisplayNewDockable(d)
+}
+
+func sessionSheetName(entity *gurps.Entity) string {
+	if entity.Profile.Name != "" {
+		return entity.Profile.Name
+	}
+	return i18n.Text("Unnamed")
+}
+
+func (d *SessionDockable) member(p *session.Player) *sessionMember {
+	for _, m := range d.members {
+		if m.player == p {
+			return m
+		}
+	}
+	return nil
+}
+
+func (d *SessionDockable) playerJoined(p *session.Player) {
+	if d.host == nil {
+		return
+	}
+	d.members = append(d.members, &sessionMember{player: p})
+	d.appendLog(fmt.Sprintf(i18n.Text("%s joined the session"), p.Name))
+	d.rebuild()
+}
+
+func (d *SessionDockable) playerLeft(p *session.Player) {
+	if d.host == nil {
+		return
+	}
+	d.members = slices.DeleteFunc(d.members, func(m *sessionMember) bool { return m.player == p })
+	d.appendLog(fmt.Sprintf(i18n.Text("%s left the session"), p.Name))
+	d.rebuild()
+}
+
+func (d *SessionDockable) receivedFromPlayer(p *session.Player, msg *session.Message) {
+	m := d.member(p)
+	if d.host == nil || m == nil {
+		return
+	}
+	switch msg.Type {
+	case session.SheetMsg:
+		if msg.Entity == nil {
+			return
+		}
+		if i := slices.IndexFunc(m.sheets, func(e *gurps.Entity) bool { return e.ID == msg.Entity.ID }); i != -1 {
+			m.sheets[i] = msg.Entity
+		} else {
+			m.sheets = append(m.sheets, msg.Entity)
+			d.appendLog(fmt.Sprintf(i18n.Text("%s is sharing %s"), p.Name, sessionSheetName(msg.Entity)))
+		}
+		d.rebuild()
+	case session.SheetClosedMsg:
+		m.sheets = slices.DeleteFunc(m.sheets, func(e *gurps.Entity) bool { return e.ID == msg.Sheet })
+		d.rebuild()
+	case session.RollMsg:
+		if msg.Roll != nil {
+			d.appendLog(fmt.Sprintf(i18n.Text("%s rolled: %s"), p.Name, msg.Roll.Text))
+		}
+	}
+}
+
+func (d *SessionDockable) receivedFromGM(msg *session.Message) {
+	if d.client == nil {
+		return
+	}
+	var sheet *Sheet
+	for _, one := range OpenSheets(nil) {
+		if one.Entity().ID == msg.Sheet {
+			sheet = one
+			break
+		}
+	}
+	if sheet == nil {
+		return
+	}
+	entity := sheet.Entity()
+	switch msg.Type {
+	case session.DamageMsg:
+		if dmg := msg.Damage; dmg != nil {
+			editWounds(sheet, entity, i18n.Text("Record Hit"), func() {
+				entity.RecordWound(entity.NewWound(dmg.Location, dmg.Type, dmg.Basic))
+			})
+			d.appendLog(fmt.Sprintf(i18n.Text("The GM recorded a hit of %d %s to the %s of %s"), dmg.Basic, dmg.Type,
+				dmg.Location, sessionSheetName(entity)))
+		}
+	case session.ConditionMsg:
+		if c := msg.Condition; c != nil {
+			editConditions(sheet, entity, i18n.Text("Add Condition"), func() { entity.AddCondition(c) })
+			d.appendLog(fmt.Sprintf(i18n.Text("The GM added the %s condition to %s"), c.Name,
+				sessionSheetName(entity)))
+		}
+	}
+}
+
+func (d *SessionDockable) disconnected(err error) {
+	if d.client == nil {
+		return
+	}
+	d.client = nil
+	if activeSession == d {
+		activeSession = nil
+	}
+	if err != nil {
+		d.appendLog(fmt.Sprintf(i18n.Text("Disconnected from the session: %s"), err.Error()))
+	} else {
+		d.appendLog(i18n.Text("The GM ended the session"))
+	}
+	d.status.SetTitle(i18n.Text("Not connected"))
+	d.status.MarkForLayoutAndRedraw()
+}
+
+// QueueSessionSync schedules the sheets shared with the GM of the session this instance has joined, if any, to be
+// brought up to date. Pass the entity that changed, or nil to only look for sheets that have been opened or closed.
+func QueueSessionSync(entity *gurps.Entity) {
+	d := activeSession
+	if d == nil || d.client == nil {
+		return
+	}
+	if entity != nil {
+		d.pending[entity] = true
+	}
+	if !d.syncQueued {
+		d.syncQueued = true
+		unison.InvokeTaskAfter(d.sync, sessionSyncDelay)
+	}
+}
+
+func (d *SessionDockable) sync() {
+	d.syncQueued = false
+	if d.client == nil {
+		return
+	}
+	pending := d.pending
+	d.pending = make(map[*gurps.Entity]bool)
+	open := make(map[tid.TID]bool)
+	for _, sheet := range OpenSheets(nil) {
+		entity := sheet.Entity()
+		open[entity.ID] = true
+		if _, shared := d.shared[entity.ID]; !shared || pending[entity] {
+			d.shared[entity.ID] = entity
+			d.client.Send(&session.Message{Type: session.SheetMsg, Entity: entity})
+		}
+	}
+	for id := range d.shared {
+		if !open[id] {
+			delete(d.shared, id)
+			d.client.Send(&session.Message{Type: session.SheetClosedMsg, Sheet: id})
+		}
+	}
+	d.rebuild()
+}
+
+func installSessionRollForwarder() {
+	dice.GlobalHistory().AddListener(func(r *dice.Result) {
+		roll := restapi.NewRollResult(r)
+		unison.InvokeTask(func() {
+			if d := activeSession; d != nil && d.client != nil {
+				d.client.Send(&session.Message{Type: session.RollMsg, Roll: roll})
+			}
+		})
+	})
+}
+
+func (d *SessionDockable) end() {
+	if activeSession == d {
+		activeSession = nil
+	}
+	if d.host != nil {
+		host := d.host
+		d.host = nil
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if err := host.Stop(ctx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			errs.Log(err)
+		}
+		cancel()
+	}
+	if d.client != nil {
+		client := d.client
+		d.client = nil
+		client.Leave()
+	}
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *SessionDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Link,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *SessionDockable) Title() string {
+	if d.host != nil {
+		return i18n.Text("Hosted Session")
+	}
+	return i18n.Text("Shared Session")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *SessionDockable) Tooltip() string {
+	return d.status.Text.String()
+}
+
+// Modified implements unison.Dockable.
+func (d *SessionDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *SessionDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *SessionDockable) AttemptClose() bool {
+	if !AttemptCloseForDockable(d) {
+		return false
+	}
+	d.end()
+	return true
+}
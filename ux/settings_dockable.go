@@ -28,10 +28,11 @@ import (
 
 // Known dockable kinds
 const (
-	SheetDockableKind     = "sheet"
-	TemplateDockableKind  = "template"
-	LootSheetDockableKind = "loot"
-	ListDockableKind      = "list"
+	SheetDockableKind        = "sheet"
+	TemplateDockableKind     = "template"
+	LootSheetDockableKind    = "loot"
+	VehicleSheetDockableKind = "vehicle"
+	ListDockableKind         = "list"
 )
 
 var (
@@ -45,6 +46,8 @@ type SettingsDockable struct {
 	TabTitle          string
 	TabIcon           *unison.SVG
 	Extensions        []string
+	ExportFileName    func() string
+	FileSets          func() []*gurps.NamedFileSet
 	Loader            func(fileSystem fs.FS, filePath string) error
 	Saver             func(filePath string) error
 	Resetter          func()
@@ -183,8 +186,12 @@ func (d *SettingsDockable) showMenu(b *unison.Button) {
 		id++
 	}
 	if d.Loader != nil {
-		libraries := gurps.GlobalSettings().Libraries()
-		sets := gurps.ScanForNamedFileSets(nil, "", false, libraries, d.Extensions...)
+		var sets []*gurps.NamedFileSet
+		if d.FileSets != nil {
+			sets = d.FileSets()
+		} else {
+			sets = gurps.ScanForNamedFileSets(nil, "", false, gurps.GlobalSettings().Libraries(), d.Extensions...)
+		}
 		if len(sets) != 0 {
 			m.InsertSeparator(-1, false)
 			for _, lib := range sets {
@@ -235,7 +242,11 @@ func (d *SettingsDockable) handleExport(_ unison.MenuItem) {
 	dialog.SetAllowedExtensions(d.Extensions[0])
 	global := gurps.GlobalSettings()
 	dialog.SetInitialDirectory(global.LastDir(gurps.SettingsLastDirKey))
-	dialog.SetInitialFileName(xfilepath.SanitizeName(xfilepath.BaseName(d.Title())))
+	name := xfilepath.BaseName(d.Title())
+	if d.ExportFileName != nil {
+		name = d.ExportFileName()
+	}
+	dialog.SetInitialFileName(xfilepath.SanitizeName(name))
 	if dialog.RunModal() {
 		if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), d.Extensions[0], false); ok {
 			global.SetLastDir(gurps.SettingsLastDirKey, filepath.Dir(filePath))
@@ -15,7 +15,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/richardwilkes/gcs/v5/model/diag"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/jio"
 	"github.com/richardwilkes/gcs/v5/model/kinds"
@@ -65,31 +67,44 @@ type itemCreator interface {
 // Sheet holds the view for a GURPS character sheet.
 type Sheet struct {
 	unison.Panel
-	path                 string
-	targetMgr            *TargetMgr
-	undoMgr              *unison.UndoManager
-	toolbar              *unison.Panel
-	scroll               *unison.ScrollPanel
-	entity               *gurps.Entity
-	hash                 uint64
-	content              *unison.Panel
-	modifiedFunc         func()
-	syncDisclosureFunc   func()
-	Reactions            *PageList[*gurps.ConditionalModifier]
-	ConditionalModifiers *PageList[*gurps.ConditionalModifier]
-	MeleeWeapons         *PageList[*gurps.Weapon]
-	RangedWeapons        *PageList[*gurps.Weapon]
-	Traits               *PageList[*gurps.Trait]
-	Skills               *PageList[*gurps.Skill]
-	Spells               *PageList[*gurps.Spell]
-	CarriedEquipment     *PageList[*gurps.Equipment]
-	OtherEquipment       *PageList[*gurps.Equipment]
-	Notes                *PageList[*gurps.Note]
-	dragReroutePanel     *unison.Panel
-	searchTracker        *SearchTracker
-	scale                int
-	awaitingUpdate       bool
-	needsSaveAsPrompt    bool
+	path                   string
+	targetMgr              *TargetMgr
+	undoMgr                *unison.UndoManager
+	toolbar                *unison.Panel
+	scroll                 *unison.ScrollPanel
+	entity                 *gurps.Entity
+	hash                   uint64
+	savedData              []byte
+	content                *unison.Panel
+	modifiedFunc           func()
+	syncDisclosureFunc     func()
+	Reactions              *PageList[*gurps.ConditionalModifier]
+	ConditionalModifiers   *PageList[*gurps.ConditionalModifier]
+	ReactionSummary        *ReactionSummaryPanel
+	PoolTrackers           *PoolTrackersPanel
+	Wounds                 *WoundsPanel
+	Conditions             *ConditionsPanel
+	Grappling              *GrapplingPanel
+	Fatigue                *FatiguePanel
+	MeleeWeapons           *PageList[*gurps.Weapon]
+	RangedWeapons          *PageList[*gurps.Weapon]
+	Traits                 *PageList[*gurps.Trait]
+	Skills                 *PageList[*gurps.Skill]
+	Spells                 *PageList[*gurps.Spell]
+	Grimoire               *GrimoirePanel
+	CarriedEquipment       *PageList[*gurps.Equipment]
+	OtherEquipment         *PageList[*gurps.Equipment]
+	Notes                  *PageList[*gurps.Note]
+	CustomBlocks           map[string]*CustomBlockPanel
+	dragReroutePanel       *unison.Panel
+	searchTracker          *SearchTracker
+	lockButton             *unison.Button
+	scale                  int
+	awaitingUpdate         bool
+	needsSaveAsPrompt      bool
+	maneuverPopup          *unison.PopupMenu[*gurps.Maneuver]
+	equipmentLocationPopup *unison.PopupMenu[string]
+	restoreThemeColors     func()
 }
 
 // ActiveSheet returns the currently active sheet.
@@ -123,6 +138,9 @@ func NewSheetFromFile(filePath string) (unison.Dockable, error) {
 	}
 	s := NewSheet(filePath, entity)
 	s.needsSaveAsPrompt = false
+	if s.savedData, err = json.Marshal(entity); err != nil {
+		errs.Log(err, "path", filePath)
+	}
 	return s, nil
 }
 
@@ -188,6 +206,8 @@ func NewSheet(filePath string, entity *gurps.Entity) *Sheet {
 		Columns:  1,
 		VSpacing: 1,
 	})
+	s.content.DrawCallback = s.applyThemeColors
+	s.content.DrawOverCallback = s.removeThemeColors
 	var top *Page
 	top, s.modifiedFunc, s.syncDisclosureFunc = createPageTopBlock(s.entity, s.targetMgr)
 	s.content.AddChild(top)
@@ -204,15 +224,17 @@ func NewSheet(filePath string, entity *gurps.Entity) *Sheet {
 
 	s.InstallCmdHandlers(SaveItemID, func(_ any) bool { return s.Modified() }, func(_ any) { s.save(false) })
 	s.InstallCmdHandlers(SaveAsItemID, unison.AlwaysEnabled, func(_ any) { s.save(true) })
-	s.installNewItemCmdHandlers(NewTraitItemID, NewTraitContainerItemID, s.Traits)
-	s.installNewItemCmdHandlers(NewSkillItemID, NewSkillContainerItemID, s.Skills)
-	s.installNewItemCmdHandlers(NewTechniqueItemID, -1, s.Skills)
-	s.installNewItemCmdHandlers(NewSpellItemID, NewSpellContainerItemID, s.Spells)
-	s.installNewItemCmdHandlers(NewRitualMagicSpellItemID, -1, s.Spells)
-	s.installNewItemCmdHandlers(NewCarriedEquipmentItemID, NewCarriedEquipmentContainerItemID, s.CarriedEquipment)
-	s.installNewItemCmdHandlers(NewOtherEquipmentItemID, NewOtherEquipmentContainerItemID, s.OtherEquipment)
-	s.installNewItemCmdHandlers(NewNoteItemID, NewNoteContainerItemID, s.Notes)
-	s.InstallCmdHandlers(AddNaturalAttacksItemID, unison.AlwaysEnabled, func(_ any) {
+	s.installNewItemCmdHandlers(NewTraitItemID, NewTraitContainerItemID, s.Traits, s.unlocked)
+	s.installNewItemCmdHandlers(NewSkillItemID, NewSkillContainerItemID, s.Skills, s.unlocked)
+	s.installNewItemCmdHandlers(NewTechniqueItemID, -1, s.Skills, s.unlocked)
+	s.installNewItemCmdHandlers(NewSpellItemID, NewSpellContainerItemID, s.Spells, s.unlocked)
+	s.installNewItemCmdHandlers(NewRitualMagicSpellItemID, -1, s.Spells, s.unlocked)
+	s.installNewItemCmdHandlers(NewCarriedEquipmentItemID, NewCarriedEquipmentContainerItemID, s.CarriedEquipment,
+		unison.AlwaysEnabled)
+	s.installNewItemCmdHandlers(NewOtherEquipmentItemID, NewOtherEquipmentContainerItemID, s.OtherEquipment,
+		unison.AlwaysEnabled)
+	s.installNewItemCmdHandlers(NewNoteItemID, NewNoteContainerItemID, s.Notes, unison.AlwaysEnabled)
+	s.InstallCmdHandlers(AddNaturalAttacksItemID, s.unlocked, func(_ any) {
 		InsertItems(s, s.Traits.Table, s.entity.TraitList, s.entity.SetTraitList,
 			func(_ *unison.Table[*Node[*gurps.Trait]]) []*Node[*gurps.Trait] {
 				return s.Traits.provider.RootRows()
@@ -222,7 +244,11 @@ func NewSheet(filePath string, entity *gurps.Entity) *Sheet {
 	InstallExportCmdHandlers(s)
 	s.InstallCmdHandlers(ClearPortraitItemID, s.canClearPortrait, s.clearPortrait)
 	s.InstallCmdHandlers(ExportPortraitItemID, s.canExportPortrait, s.exportPortrait)
+	s.InstallCmdHandlers(ExportTokenItemID, s.canExportToken, s.exportToken)
 	s.InstallCmdHandlers(CloneSheetItemID, unison.AlwaysEnabled, func(_ any) { s.cloneSheet() })
+	s.InstallCmdHandlers(CheckIntegrityItemID, unison.AlwaysEnabled, func(_ any) { s.checkIntegrity() })
+	s.InstallCmdHandlers(SpellPrereqGraphItemID, unison.AlwaysEnabled, func(_ any) { ShowSpellPrereqGraphForSheet(s) })
+	s.InstallCmdHandlers(FindReplaceItemID, unison.AlwaysEnabled, func(_ any) { ShowFindReplace(s) })
 	return s
 }
 
@@ -325,6 +351,32 @@ func (s *Sheet) createToolbar() {
 	bodyTypeButton.ClickCallback = func() { ShowBodySettings(s) }
 	s.toolbar.AddChild(bodyTypeButton)
 
+	recordHitButton := unison.NewSVGButton(svg.FirstAidKit)
+	recordHitButton.Tooltip = newWrappedTooltip(i18n.Text("Record a hit against a specific location"))
+	recordHitButton.ClickCallback = func() { ShowRecordHitDialog(s) }
+	s.toolbar.AddChild(recordHitButton)
+
+	addConditionButton := unison.NewSVGButton(svg.MagicWand)
+	addConditionButton.Tooltip = newWrappedTooltip(i18n.Text("Add a condition, such as being stunned or under a spell"))
+	addConditionButton.ClickCallback = func() { ShowAddConditionDialog(s) }
+	s.toolbar.AddChild(addConditionButton)
+
+	spendFatigueButton := unison.NewSVGButton(svg.Weight)
+	spendFatigueButton.Tooltip = newWrappedTooltip(i18n.Text("Spend fatigue on sprinting, extra effort or spellcasting"))
+	spendFatigueButton.ClickCallback = func() { ShowSpendFatigueDialog(s) }
+	s.toolbar.AddChild(spendFatigueButton)
+
+	powersButton := unison.NewSVGButton(svg.GCSTraits)
+	powersButton.Tooltip = newWrappedTooltip(i18n.Text("Powers by source"))
+	powersButton.ClickCallback = func() { ShowPowersBySource(s) }
+	s.toolbar.AddChild(powersButton)
+
+	s.maneuverPopup = s.createManeuverPopup()
+	s.toolbar.AddChild(s.maneuverPopup)
+
+	s.equipmentLocationPopup = s.createEquipmentLocationFilterPopup()
+	s.toolbar.AddChild(s.equipmentLocationPopup)
+
 	cloneSheetButton := unison.NewSVGButton(svg.Clone)
 	cloneSheetButton.Tooltip = newWrappedTooltip(cloneSheetAction.Title)
 	cloneSheetButton.ClickCallback = s.cloneSheet
@@ -335,11 +387,23 @@ func (s *Sheet) createToolbar() {
 	syncSourceButton.ClickCallback = s.syncWithAllSources
 	s.toolbar.AddChild(syncSourceButton)
 
+	historyButton := unison.NewSVGButton(svg.Stack)
+	historyButton.Tooltip = newWrappedTooltip(i18n.Text("Snapshots and change log"))
+	historyButton.ClickCallback = func() { ShowSheetHistory(s) }
+	s.toolbar.AddChild(historyButton)
+
+	s.toolbar.AddChild(s.createLockButton())
+
 	calcButton := unison.NewSVGButton(svg.Calculator)
 	calcButton.Tooltip = newWrappedTooltip(i18n.Text("Calculators (jumping, throwing, hiking, etc.)"))
 	calcButton.ClickCallback = func() { DisplayCalculator(s) }
 	s.toolbar.AddChild(calcButton)
 
+	rollerButton := unison.NewSVGButton(svg.Randomize)
+	rollerButton.Tooltip = newWrappedTooltip(diceRollerAction.Title)
+	rollerButton.ClickCallback = ShowRoller
+	s.toolbar.AddChild(rollerButton)
+
 	s.searchTracker = InstallSearchTracker(s.toolbar, func() {
 		s.Reactions.Table.ClearSelection()
 		s.ConditionalModifiers.Table.ClearSelection()
@@ -375,24 +439,37 @@ func (s *Sheet) DataOwner() gurps.DataOwner {
 	return s.entity
 }
 
+// applyThemeColors applies the colors of the sheet's theme, if any, while the pages are drawn.
+func (s *Sheet) applyThemeColors(_ *unison.Canvas, _ geom.Rect) {
+	if theme := s.entity.SheetSettings.ResolvedTheme(); theme != nil {
+		s.restoreThemeColors = theme.ApplyColors()
+	}
+}
+
+func (s *Sheet) removeThemeColors(_ *unison.Canvas, _ geom.Rect) {
+	if s.restoreThemeColors != nil {
+		s.restoreThemeColors()
+		s.restoreThemeColors = nil
+	}
+}
+
 func (s *Sheet) canExportPortrait(_ any) bool {
-	return s.entity.Profile.CanExportPortrait()
+	return gurps.PortraitDataExtension(s.entity.Profile.ShownPortraitData()) != ""
 }
 
 func (s *Sheet) exportPortrait(_ any) {
-	if s.entity.Profile.CanExportPortrait() {
-		if ext := s.entity.Profile.PortraitExtension(); ext != "" {
-			s.Window().ShowCursor()
-			dialog := unison.NewSaveDialog()
-			backingFilePath := s.BackingFilePath()
-			dialog.SetInitialDirectory(filepath.Dir(backingFilePath))
-			dialog.SetAllowedExtensions(ext)
-			dialog.SetInitialFileName(xfilepath.SanitizeName(xfilepath.BaseName(backingFilePath)))
-			if dialog.RunModal() {
-				if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), ext, false); ok {
-					if err := s.entity.Profile.ExportPortrait(filePath); err != nil {
-						Workspace.ErrorHandler(i18n.Text("Unable to export portrait"), err)
-					}
+	data := s.entity.Profile.ShownPortraitData()
+	if ext := gurps.PortraitDataExtension(data); ext != "" {
+		s.Window().ShowCursor()
+		dialog := unison.NewSaveDialog()
+		backingFilePath := s.BackingFilePath()
+		dialog.SetInitialDirectory(filepath.Dir(backingFilePath))
+		dialog.SetAllowedExtensions(ext)
+		dialog.SetInitialFileName(xfilepath.SanitizeName(xfilepath.BaseName(backingFilePath)))
+		if dialog.RunModal() {
+			if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), ext, false); ok {
+				if err := os.WriteFile(filePath, data, 0o640); err != nil {
+					Workspace.ErrorHandler(i18n.Text("Unable to export portrait"), errs.Wrap(err))
 				}
 			}
 		}
@@ -400,30 +477,127 @@ func (s *Sheet) exportPortrait(_ any) {
 }
 
 func (s *Sheet) canClearPortrait(_ any) bool {
-	return len(s.entity.Profile.PortraitData) != 0
+	return len(s.entity.Profile.ShownPortraitData()) != 0
 }
 
 func (s *Sheet) clearPortrait(_ any) {
 	if s.canClearPortrait(nil) {
-		s.undoMgr.Add(&unison.UndoEdit[[]byte]{
-			ID:         unison.NextUndoID(),
-			EditName:   clearPortraitAction.Title,
-			UndoFunc:   func(edit *unison.UndoEdit[[]byte]) { s.updatePortrait(edit.BeforeData) },
-			RedoFunc:   func(edit *unison.UndoEdit[[]byte]) { s.updatePortrait(edit.AfterData) },
-			BeforeData: s.entity.Profile.PortraitData,
-			AfterData:  nil,
-		})
-		s.updatePortrait(nil)
+		s.changePortraits(clearPortraitAction.Title, func(p *gurps.Profile) { p.SetShownPortraitData(nil) })
 	}
 }
 
-func (s *Sheet) updatePortrait(data []byte) {
-	s.entity.Profile.PortraitData = data
-	s.entity.Profile.PortraitImage = nil
+func (s *Sheet) canExportToken(_ any) bool {
+	return len(s.entity.Profile.TokenPortraitData()) != 0
+}
+
+func (s *Sheet) exportToken(_ any) {
+	data := s.entity.Profile.TokenPortraitData()
+	if len(data) == 0 {
+		return
+	}
+	general := gurps.GlobalSettings().General
+	ringColor := general.TokenRingColor
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Ring Color"), false))
+	well := unison.NewWell()
+	well.Mask = unison.ColorWellMask
+	well.SetInk(ringColor)
+	well.InkChangedCallback = func() {
+		if clr, ok := well.Ink().(unison.Color); ok {
+			ringColor = clr
+		}
+	}
+	content.AddChild(well)
+	icon := &unison.DrawableSVG{
+		SVG:  svg.GCSSheet,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	general.TokenRingColor = ringColor
+	var token []byte
+	if token, err = gurps.CreateTokenPNG(data, ringColor); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create token"), err)
+		return
+	}
+	s.Window().ShowCursor()
+	saveDialog := unison.NewSaveDialog()
+	backingFilePath := s.BackingFilePath()
+	saveDialog.SetInitialDirectory(filepath.Dir(backingFilePath))
+	saveDialog.SetAllowedExtensions("png")
+	saveDialog.SetInitialFileName(xfilepath.SanitizeName(xfilepath.BaseName(backingFilePath)) + " " +
+		i18n.Text("Token"))
+	if saveDialog.RunModal() {
+		if filePath, ok := unison.ValidateSaveFilePath(saveDialog.Path(), "png", false); ok {
+			if err = os.WriteFile(filePath, token, 0o640); err != nil {
+				Workspace.ErrorHandler(i18n.Text("Unable to export token"), errs.Wrap(err))
+			}
+		}
+	}
+}
+
+// portraitState holds a snapshot of the portraits of the sheet's entity.
+type portraitState struct {
+	primary []byte
+	alts    []*gurps.AltPortrait
+	shown   int
+}
+
+func (s *Sheet) portraitState() *portraitState {
+	p := &s.entity.Profile
+	state := &portraitState{
+		primary: p.PortraitData,
+		alts:    make([]*gurps.AltPortrait, len(p.AltPortraits)),
+		shown:   p.ShownPortrait,
+	}
+	for i, one := range p.AltPortraits {
+		state.alts[i] = one.Clone()
+	}
+	return state
+}
+
+func (s *Sheet) applyPortraitState(state *portraitState) {
+	p := &s.entity.Profile
+	p.PortraitData = state.primary
+	p.PortraitImage = nil
+	p.AltPortraits = make([]*gurps.AltPortrait, len(state.alts))
+	for i, one := range state.alts {
+		p.AltPortraits[i] = one.Clone()
+	}
+	p.ShownPortrait = state.shown
 	s.MarkForRedraw()
 	s.MarkModified(s)
 }
 
+// changePortraits calls change to alter the portraits of the sheet's entity, recording the result as an undoable edit.
+func (s *Sheet) changePortraits(name string, change func(p *gurps.Profile)) {
+	before := s.portraitState()
+	change(&s.entity.Profile)
+	after := s.portraitState()
+	addUndo(s.undoMgr, &unison.UndoEdit[*portraitState]{
+		ID:         unison.NextUndoID(),
+		EditName:   name,
+		UndoFunc:   func(edit *unison.UndoEdit[*portraitState]) { s.applyPortraitState(edit.BeforeData) },
+		RedoFunc:   func(edit *unison.UndoEdit[*portraitState]) { s.applyPortraitState(edit.AfterData) },
+		BeforeData: before,
+		AfterData:  after,
+	})
+	s.applyPortraitState(after)
+}
+
 func (s *Sheet) keyToPanel(key string) *unison.Panel {
 	var p unison.Paneler
 	switch key {
@@ -443,15 +617,14 @@ func (s *Sheet) keyToPanel(key string) *unison.Panel {
 	return p.AsPanel()
 }
 
-func (s *Sheet) installNewItemCmdHandlers(itemID, containerID int, creator itemCreator) {
+func (s *Sheet) installNewItemCmdHandlers(itemID, containerID int, creator itemCreator, can func(any) bool) {
 	variant := NoItemVariant
 	if containerID == -1 {
 		variant = AlternateItemVariant
 	} else {
-		s.InstallCmdHandlers(containerID, unison.AlwaysEnabled,
-			func(_ any) { creator.CreateItem(s, ContainerItemVariant) })
+		s.InstallCmdHandlers(containerID, can, func(_ any) { creator.CreateItem(s, ContainerItemVariant) })
 	}
-	s.InstallCmdHandlers(itemID, unison.AlwaysEnabled, func(_ any) { creator.CreateItem(s, variant) })
+	s.InstallCmdHandlers(itemID, can, func(_ any) { creator.CreateItem(s, variant) })
 }
 
 // DockableKind implements widget.DockableKind
@@ -522,6 +695,9 @@ func (s *Sheet) MarkModified(src unison.Paneler) {
 		focusRefKey := s.targetMgr.CurrentFocusRef()
 		s.entity.DiscardCaches()
 		s.modifiedFunc()
+		CheckDiscordThresholds(s.entity)
+		PublishAPIServerChanges(s.entity)
+		QueueSessionSync(s.entity)
 		UpdateTitleForDockable(s)
 		// TODO: This can be too slow when the lists have many rows of content, impinging upon interactive typing.
 		//       Looks like most of the time is spent in updating the tables. Unfortunately, there isn't a fast way to
@@ -548,8 +724,11 @@ func (s *Sheet) MayAttemptClose() bool {
 
 // AttemptClose implements unison.TabCloser
 func (s *Sheet) AttemptClose() bool {
-	if AttemptSaveForDockable(s) {
-		return AttemptCloseForDockable(s)
+	if AttemptSaveForDockable(s) && AttemptCloseForDockable(s) {
+		forgetDiscordThresholds(s.entity)
+		PublishAPIServerChanges(nil)
+		QueueSessionSync(nil)
+		return true
 	}
 	return false
 }
@@ -566,10 +745,39 @@ func (s *Sheet) save(forceSaveAs bool) bool {
 	}
 	if success {
 		s.needsSaveAsPrompt = false
+		s.recordChanges()
+		autoSnapshotSheet(s)
 	}
 	return success
 }
 
+// recordChanges appends the changes made since the sheet was last saved or opened to its change log.
+func (s *Sheet) recordChanges() {
+	data, err := json.Marshal(s.entity)
+	if err != nil {
+		errs.Log(err, "path", s.path)
+		return
+	}
+	if s.savedData != nil {
+		if err = gurps.RecordSheetChanges(s.path, s.savedData, data); err != nil {
+			errs.Log(err, "path", s.path)
+		}
+		reloadSheetHistory(s)
+	}
+	s.savedData = data
+}
+
+func (s *Sheet) autosaver() func(filePath string) error {
+	return s.entity.Save
+}
+
+func (s *Sheet) markRecovered(filePath string, untitled bool) {
+	s.path = filePath
+	s.needsSaveAsPrompt = untitled
+	s.hash = 0 // Force it to be recognized as unsaved
+	UpdateTitleForDockable(s)
+}
+
 func (s *Sheet) createLists() {
 	children := s.content.Children()
 	if len(children) == 0 {
@@ -587,7 +795,8 @@ func (s *Sheet) createLists() {
 		page.RemoveChildAtIndex(i)
 	}
 	// Add the various blocks, based on the layout preference.
-	for _, col := range s.entity.SheetSettings.BlockLayout.ByRow() {
+	layout := s.entity.SheetSettings.BlockLayout
+	for _, col := range layout.ByRow() {
 		rowPanel := unison.NewPanel()
 		for _, c := range col {
 			switch c {
@@ -598,7 +807,7 @@ func (s *Sheet) createLists() {
 					s.Reactions.Sync()
 				}
 				SetDataOwnerProvider(s.Reactions.Table, s)
-				if s.Reactions.Table.RootRowCount() > 0 {
+				if s.Reactions.Table.RootRowCount() >= max(layout.MinimumEntries(c), 1) {
 					rowPanel.AddChild(s.Reactions)
 				}
 			case gurps.BlockLayoutConditionalModifiersKey:
@@ -608,9 +817,61 @@ func (s *Sheet) createLists() {
 					s.ConditionalModifiers.Sync()
 				}
 				SetDataOwnerProvider(s.ConditionalModifiers.Table, s)
-				if s.ConditionalModifiers.Table.RootRowCount() > 0 {
+				if s.ConditionalModifiers.Table.RootRowCount() >= max(layout.MinimumEntries(c), 1) {
 					rowPanel.AddChild(s.ConditionalModifiers)
 				}
+			case gurps.BlockLayoutReactionSummaryKey:
+				if s.ReactionSummary == nil {
+					s.ReactionSummary = NewReactionSummaryPanel(s.entity)
+				} else {
+					s.ReactionSummary.Sync()
+				}
+				if s.ReactionSummary.HasReactions() {
+					rowPanel.AddChild(s.ReactionSummary)
+				}
+			case gurps.BlockLayoutPoolTrackersKey:
+				if s.PoolTrackers == nil {
+					s.PoolTrackers = NewPoolTrackersPanel(s.entity, s.targetMgr)
+				} else {
+					s.PoolTrackers.Sync()
+				}
+				if s.PoolTrackers.HasTrackers() {
+					rowPanel.AddChild(s.PoolTrackers)
+				}
+			case gurps.BlockLayoutWoundsKey:
+				if s.Wounds == nil {
+					s.Wounds = NewWoundsPanel(s.entity, true)
+				} else {
+					s.Wounds.Sync()
+				}
+				if s.Wounds.HasWounds() {
+					rowPanel.AddChild(s.Wounds)
+				}
+			case gurps.BlockLayoutConditionsKey:
+				if s.Conditions == nil {
+					s.Conditions = NewConditionsPanel(s.entity, true)
+				} else {
+					s.Conditions.Sync()
+				}
+				if s.Conditions.HasConditions() {
+					rowPanel.AddChild(s.Conditions)
+				}
+			case gurps.BlockLayoutGrapplingKey:
+				if s.Grappling == nil {
+					s.Grappling = NewGrapplingPanel(s.entity, s.targetMgr)
+				}
+				if s.Grappling.Enabled() {
+					rowPanel.AddChild(s.Grappling)
+				}
+			case gurps.BlockLayoutFatigueKey:
+				if s.Fatigue == nil {
+					s.Fatigue = NewFatiguePanel(s.entity, true)
+				} else {
+					s.Fatigue.Sync()
+				}
+				if s.Fatigue.HasExpenditures() {
+					rowPanel.AddChild(s.Fatigue)
+				}
 			case gurps.BlockLayoutMeleeKey:
 				if s.MeleeWeapons == nil {
 					s.MeleeWeapons = NewMeleeWeaponsPageList(s.entity)
@@ -618,7 +879,7 @@ func (s *Sheet) createLists() {
 					s.MeleeWeapons.Sync()
 				}
 				SetDataOwnerProvider(s.MeleeWeapons.Table, s)
-				if s.MeleeWeapons.Table.RootRowCount() > 0 {
+				if s.MeleeWeapons.Table.RootRowCount() >= max(layout.MinimumEntries(c), 1) {
 					rowPanel.AddChild(s.MeleeWeapons)
 				}
 			case gurps.BlockLayoutRangedKey:
@@ -628,51 +889,92 @@ func (s *Sheet) createLists() {
 					s.RangedWeapons.Sync()
 				}
 				SetDataOwnerProvider(s.RangedWeapons.Table, s)
-				if s.RangedWeapons.Table.RootRowCount() > 0 {
+				if s.RangedWeapons.Table.RootRowCount() >= max(layout.MinimumEntries(c), 1) {
 					rowPanel.AddChild(s.RangedWeapons)
 				}
 			case gurps.BlockLayoutTraitsKey:
 				if s.Traits.needReconstruction() {
 					s.Traits = NewTraitsPageList(s, s.entity)
+					s.Traits.lockWith(s.entity)
 				} else {
 					s.Traits.Sync()
 				}
-				rowPanel.AddChild(s.Traits)
+				if s.Traits.Table.RootRowCount() >= layout.MinimumEntries(c) {
+					rowPanel.AddChild(s.Traits)
+				}
 			case gurps.BlockLayoutSkillsKey:
 				if s.Skills.needReconstruction() {
 					s.Skills = NewSkillsPageList(s, s.entity)
+					s.Skills.lockWith(s.entity)
 				} else {
 					s.Skills.Sync()
 				}
-				rowPanel.AddChild(s.Skills)
+				if s.Skills.Table.RootRowCount() >= layout.MinimumEntries(c) {
+					rowPanel.AddChild(s.Skills)
+				}
 			case gurps.BlockLayoutSpellsKey:
 				if s.Spells.needReconstruction() {
 					s.Spells = NewSpellsPageList(s, s.entity)
+					s.Spells.lockWith(s.entity)
 				} else {
 					s.Spells.Sync()
 				}
-				rowPanel.AddChild(s.Spells)
+				if s.Spells.Table.RootRowCount() >= layout.MinimumEntries(c) {
+					rowPanel.AddChild(s.Spells)
+				}
+			case gurps.BlockLayoutGrimoireKey:
+				if s.Grimoire == nil {
+					s.Grimoire = NewGrimoirePanel(s.entity, true)
+				} else {
+					s.Grimoire.Sync()
+				}
+				if s.Grimoire.Enabled() {
+					rowPanel.AddChild(s.Grimoire)
+				}
 			case gurps.BlockLayoutEquipmentKey:
 				if s.CarriedEquipment.needReconstruction() {
 					s.CarriedEquipment = NewCarriedEquipmentPageList(s, s.entity)
 				} else {
 					s.CarriedEquipment.Sync()
 				}
-				rowPanel.AddChild(s.CarriedEquipment)
+				s.applyEquipmentLocationFilter()
+				if s.CarriedEquipment.Table.RootRowCount() >= layout.MinimumEntries(c) {
+					rowPanel.AddChild(s.CarriedEquipment)
+				}
 			case gurps.BlockLayoutOtherEquipmentKey:
 				if s.OtherEquipment.needReconstruction() {
 					s.OtherEquipment = NewOtherEquipmentPageList(s, s.entity)
 				} else {
 					s.OtherEquipment.Sync()
 				}
-				rowPanel.AddChild(s.OtherEquipment)
+				if s.OtherEquipment.Table.RootRowCount() >= layout.MinimumEntries(c) {
+					rowPanel.AddChild(s.OtherEquipment)
+				}
 			case gurps.BlockLayoutNotesKey:
 				if s.Notes.needReconstruction() {
 					s.Notes = NewNotesPageList(s, s.entity)
 				} else {
 					s.Notes.Sync()
 				}
-				rowPanel.AddChild(s.Notes)
+				if s.Notes.Table.RootRowCount() >= layout.MinimumEntries(c) {
+					rowPanel.AddChild(s.Notes)
+				}
+			default:
+				if gurps.IsCustomBlockKey(c) {
+					if s.CustomBlocks == nil {
+						s.CustomBlocks = make(map[string]*CustomBlockPanel)
+					}
+					panel, exists := s.CustomBlocks[c]
+					if exists {
+						panel.rebuild()
+					} else {
+						panel = NewCustomBlockPanel(s.entity, c, s.targetMgr)
+						s.CustomBlocks[c] = panel
+					}
+					if panel.HasContent() {
+						rowPanel.AddChild(panel)
+					}
+				}
 			}
 		}
 		if len(rowPanel.Children()) != 0 {
@@ -733,7 +1035,7 @@ func (s *Sheet) swapDefaults(_ any) {
 	}
 	s.Skills.Sync()
 	undo.AfterData = NewTableUndoEditData(s.Skills.Table)
-	s.UndoManager().Add(undo)
+	addUndo(s.UndoManager(), undo)
 }
 
 // SheetSettingsUpdated implements gurps.SheetSettingsResponder.
@@ -773,20 +1075,51 @@ func (s *sheetTablesUndoData) Apply() {
 	s.notes.Apply()
 }
 
+func (s *Sheet) countTags(counts map[string]int) {
+	gurps.CountTagsInProvider(counts, s.entity)
+}
+
+func (s *Sheet) renameTag(from, to string) {
+	s.updateTables(i18n.Text("Rename Tag"), func() { gurps.RenameTagInProvider(from, to, s.entity) })
+}
+
+func (s *Sheet) retargetSources(replacements map[gurps.Source]gurps.Source) {
+	s.updateTables(i18n.Text("Merge Library Duplicates"), func() { gurps.RetargetSources(s.entity, replacements) })
+}
+
+func (s *Sheet) searchNodes(hits []*gurps.SearchHit, text string) []*gurps.SearchHit {
+	return gurps.SearchNodesInProvider(hits, s.BackingFilePath(), text, s.entity)
+}
+
+func (s *Sheet) locateNode(id tid.TID) bool {
+	return locateTableRowByID(s.Traits.Table, id) ||
+		locateTableRowByID(s.Skills.Table, id) ||
+		locateTableRowByID(s.Spells.Table, id) ||
+		locateTableRowByID(s.CarriedEquipment.Table, id) ||
+		locateTableRowByID(s.OtherEquipment.Table, id) ||
+		locateTableRowByID(s.Notes.Table, id)
+}
+
 func (s *Sheet) syncWithAllSources() {
+	s.updateTables(syncWithSourceAction.Title, s.entity.SyncWithLibrarySources)
+}
+
+// updateTables calls update to modify the sheet's list data, then refreshes the tables and records an undo for the
+// change.
+func (s *Sheet) updateTables(editName string, update func()) {
 	var undo *unison.UndoEdit[*sheetTablesUndoData]
 	mgr := unison.UndoManagerFor(s)
 	if mgr != nil {
 		undo = &unison.UndoEdit[*sheetTablesUndoData]{
 			ID:         unison.NextUndoID(),
-			EditName:   syncWithSourceAction.Title,
+			EditName:   editName,
 			UndoFunc:   func(e *unison.UndoEdit[*sheetTablesUndoData]) { e.BeforeData.Apply() },
 			RedoFunc:   func(e *unison.UndoEdit[*sheetTablesUndoData]) { e.AfterData.Apply() },
 			AbsorbFunc: func(_ *unison.UndoEdit[*sheetTablesUndoData], _ unison.Undoable) bool { return false },
 			BeforeData: newSheetTablesUndoData(s),
 		}
 	}
-	s.entity.SyncWithLibrarySources()
+	update()
 	s.Traits.Table.SyncToModel()
 	s.Skills.Table.SyncToModel()
 	s.Spells.Table.SyncToModel()
@@ -795,13 +1128,14 @@ func (s *Sheet) syncWithAllSources() {
 	s.Notes.Table.SyncToModel()
 	if mgr != nil && undo != nil {
 		undo.AfterData = newSheetTablesUndoData(s)
-		mgr.Add(undo)
+		addUndo(mgr, undo)
 	}
 	s.Rebuild(true)
 }
 
 // Rebuild implements widget.Rebuildable.
 func (s *Sheet) Rebuild(full bool) {
+	defer diag.Track(diag.SheetRebuildKey, time.Now())
 	gurps.DiscardGlobalResolveCache()
 	h, v := s.scroll.Position()
 	focusRefKey := s.targetMgr.CurrentFocusRef()
@@ -832,11 +1166,16 @@ func (s *Sheet) Rebuild(full bool) {
 		s.createLists()
 	}
 	DeepSync(s)
+	syncBuildData(s.entity, s.content)
+	s.syncLockButton()
+	s.syncManeuverPopup()
 	UpdateTitleForDockable(s)
 	s.searchTracker.Refresh()
 	s.targetMgr.ReacquireFocus(focusRefKey, s.toolbar, s.scroll.Content())
 	s.scroll.SetPosition(h, v)
 	UpdateCalculator(s)
+	UpdateSpellPrereqGraph(s)
+	QueueSessionSync(s.entity)
 }
 
 func drawBandedBackground(p unison.Paneler, gc *unison.Canvas, rect geom.Rect, start, step int, overrideFunc func(rowIndex int, ink unison.Ink) unison.Ink) {
@@ -0,0 +1,330 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+const maxComparedKeySkills = 15
+
+var (
+	_ unison.Dockable  = &sheetComparisonDockable{}
+	_ unison.TabCloser = &sheetComparisonDockable{}
+)
+
+type sheetComparisonEntry struct {
+	path     string
+	sheet    *Sheet
+	entity   *gurps.Entity
+	fromFile bool
+	included bool
+}
+
+func (e *sheetComparisonEntry) title() string {
+	if e.sheet != nil {
+		return e.sheet.Title()
+	}
+	return xfilepath.BaseName(e.path)
+}
+
+type sheetComparisonDockable struct {
+	unison.Panel
+	entries         []*sheetComparisonEntry
+	differencesOnly bool
+	sheetsPanel     *unison.Panel
+	content         *unison.Panel
+}
+
+// ShowSheetComparison shows the characters in the sheet files side by side. If no paths are provided, the open sheets
+// are compared instead.
+func ShowSheetComparison(paths ...string) {
+	if len(paths) == 0 && Activate(func(d unison.Dockable) bool {
+		_, ok := d.AsPanel().Self.(*sheetComparisonDockable)
+		return ok
+	}) {
+		return
+	}
+	d := &sheetComparisonDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+	for _, p := range paths {
+		d.addPath(p)
+	}
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.content, behavior.Unmodified, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	d.refresh()
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *sheetComparisonDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+
+	refreshButton := unison.NewSVGButton(svg.Reset)
+	refreshButton.Tooltip = newWrappedTooltip(i18n.Text("Update the comparison with the current contents of the sheets"))
+	refreshButton.ClickCallback = d.refresh
+	toolbar.AddChild(refreshButton)
+
+	addButton := unison.NewButton()
+	addButton.SetTitle(i18n.Text("Add Sheet…"))
+	addButton.Tooltip = newWrappedTooltip(i18n.Text("Add character sheets that aren't open to the comparison"))
+	addButton.ClickCallback = d.addSheets
+	toolbar.AddChild(addButton)
+
+	differencesOnly := unison.NewCheckBox()
+	differencesOnly.SetTitle(i18n.Text("Differences Only"))
+	differencesOnly.ClickCallback = func() {
+		d.differencesOnly = differencesOnly.State == check.On
+		d.rebuild()
+	}
+	toolbar.AddChild(differencesOnly)
+
+	d.sheetsPanel = unison.NewPanel()
+	toolbar.AddChild(d.sheetsPanel)
+
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *sheetComparisonDockable) addPath(p string) {
+	for _, one := range d.entries {
+		if one.path == p {
+			one.included = true
+			return
+		}
+	}
+	d.entries = append(d.entries, &sheetComparisonEntry{path: p, fromFile: true, included: true})
+}
+
+func (d *sheetComparisonDockable) addSheets() {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(true)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.SheetExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	if dialog.RunModal() {
+		paths := dialog.Paths()
+		if len(paths) != 0 {
+			global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(paths[0]))
+		}
+		for _, p := range paths {
+			d.addPath(p)
+		}
+		d.refresh()
+	}
+}
+
+// refresh picks up the current contents of the sheets being compared, reloading those that aren't open, and adds any
+// newly opened sheets as choices.
+func (d *sheetComparisonDockable) refresh() {
+	open := OpenSheets(nil)
+	entries := make([]*sheetComparisonEntry, 0, len(d.entries)+len(open))
+	for _, one := range d.entries {
+		one.sheet = nil
+		for _, sheet := range open {
+			if sheet.BackingFilePath() == one.path {
+				one.sheet = sheet
+				break
+			}
+		}
+		switch {
+		case one.sheet != nil:
+			one.entity = one.sheet.entity
+		case !one.fromFile:
+			// The sheet was closed, so drop it from the comparison
+			continue
+		default:
+			entity, err := gurps.NewEntityFromFile(os.DirFS(filepath.Dir(one.path)), filepath.Base(one.path))
+			if err != nil {
+				Workspace.ErrorHandler(i18n.Text("Unable to load character sheet"), err)
+				continue
+			}
+			one.entity = entity
+		}
+		entries = append(entries, one)
+	}
+	for _, sheet := range open {
+		if !slices.ContainsFunc(entries, func(one *sheetComparisonEntry) bool { return one.sheet == sheet }) {
+			entries = append(entries, &sheetComparisonEntry{
+				path:     sheet.BackingFilePath(),
+				sheet:    sheet,
+				entity:   sheet.entity,
+				included: len(d.entries) == 0,
+			})
+		}
+	}
+	d.entries = entries
+	d.rebuild()
+}
+
+func (d *sheetComparisonDockable) rebuild() {
+	d.sheetsPanel.RemoveAllChildren()
+	for _, one := range d.entries {
+		box := unison.NewCheckBox()
+		box.SetTitle(one.title())
+		box.State = check.FromBool(one.included)
+		box.ClickCallback = func() {
+			one.included = box.State == check.On
+			d.rebuild()
+		}
+		d.sheetsPanel.AddChild(box)
+	}
+	d.sheetsPanel.SetLayout(&unison.FlexLayout{
+		Columns:  max(len(d.sheetsPanel.Children()), 1),
+		HSpacing: unison.StdHSpacing,
+	})
+
+	var included []*sheetComparisonEntry
+	for _, one := range d.entries {
+		if one.included {
+			included = append(included, one)
+		}
+	}
+	columns := len(included) + 1
+	d.content.RemoveAllChildren()
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  columns,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	boldFont := &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			desc := unison.DefaultLabelTheme.Font.Descriptor()
+			desc.Weight = weight.Bold
+			return desc
+		},
+	}
+	if len(included) < 2 {
+		label := unison.NewLabel()
+		label.SetTitle(i18n.Text("Choose two or more sheets to compare"))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: columns})
+		d.content.AddChild(label)
+		d.MarkForLayoutAndRedraw()
+		return
+	}
+	d.content.AddChild(unison.NewPanel())
+	entities := make([]*gurps.Entity, len(included))
+	for i, one := range included {
+		entities[i] = one.entity
+		label := unison.NewLabel()
+		label.Font = boldFont
+		label.SetTitle(one.title())
+		label.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+		d.content.AddChild(label)
+	}
+	category := ""
+	for _, row := range gurps.CompareEntities(entities, maxComparedKeySkills) {
+		differs := row.Differs()
+		if d.differencesOnly && !differs {
+			continue
+		}
+		if row.Category != category {
+			category = row.Category
+			label := unison.NewLabel()
+			label.Font = boldFont
+			label.SetTitle(category)
+			label.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+			label.SetLayoutData(&unison.FlexLayoutData{HSpan: columns})
+			d.content.AddChild(label)
+		}
+		label := unison.NewLabel()
+		label.SetTitle(row.Name)
+		label.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: unison.StdHSpacing * 2}))
+		d.content.AddChild(label)
+		for _, value := range row.Values {
+			label = unison.NewLabel()
+			if value == "" {
+				value = "—"
+			}
+			label.SetTitle(value)
+			if differs {
+				label.Font = boldFont
+				label.OnBackgroundInk = unison.ThemeWarning
+			}
+			label.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+			d.content.AddChild(label)
+		}
+	}
+	d.MarkForLayoutAndRedraw()
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *sheetComparisonDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.GCSSheet,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *sheetComparisonDockable) Title() string {
+	return i18n.Text("Sheet Comparison")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *sheetComparisonDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *sheetComparisonDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *sheetComparisonDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *sheetComparisonDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/export"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/unison"
+)
+
+// ExportSheet asks for a file location with the given extension and then uses the exporter to write the sheet's
+// character to it.
+func ExportSheet(sheet *Sheet, ext string, exporter func(entity *gurps.Entity, path string) error) {
+	if filePath, ok := promptForExportPath(sheet.BackingFilePath(), ext); ok {
+		if err := exporter(sheet.Entity(), filePath); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Export failed"), err)
+		}
+	}
+}
+
+// promptForExportPath asks for a file location with the given extension, suggesting a name derived from the source
+// path.
+func promptForExportPath(sourcePath, ext string) (string, bool) {
+	dialog := unison.NewSaveDialog()
+	settings := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(settings.LastDir(gurps.DefaultLastDirKey))
+	dialog.SetAllowedExtensions(ext)
+	dialog.SetInitialFileName(xfilepath.SanitizeName(xfilepath.BaseName(sourcePath)))
+	if dialog.RunModal() {
+		if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), ext, false); ok {
+			settings.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(filePath))
+			return filePath, true
+		}
+	}
+	return "", false
+}
+
+// CopyStatblock loads the character sheet at the given path and places its statblock on the clipboard.
+func CopyStatblock(sheetPath string) {
+	entity, err := gurps.NewEntityFromFile(os.DirFS(filepath.Dir(sheetPath)), filepath.Base(sheetPath))
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load character sheet"), err)
+		return
+	}
+	var text string
+	if text, err = export.Statblock(entity, export.StatblockOptionsFromSettings()); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create statblock"), err)
+		return
+	}
+	unison.GlobalClipboard.SetText(text)
+}
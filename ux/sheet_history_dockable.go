@@ -0,0 +1,466 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+const sheetChangeLogMaxRows = 500
+
+var (
+	_ unison.Dockable  = &sheetHistoryDockable{}
+	_ unison.TabCloser = &sheetHistoryDockable{}
+	_ GroupedCloser    = &sheetHistoryDockable{}
+)
+
+type sheetHistoryDockable struct {
+	unison.Panel
+	sheet         *Sheet
+	compareButton *unison.Button
+	restoreButton *unison.Button
+	deleteButton  *unison.Button
+	content       *unison.Panel
+	snapshots     []*gurps.SheetSnapshot
+	selected      map[*gurps.SheetSnapshot]bool
+	diffs         []*gurps.EntityDifference
+	diffTitle     string
+	changes       []*gurps.SheetChange
+}
+
+// takeSnapshotOfSheet saves a snapshot of the sheet's current contents. Returns false if the snapshot could not be
+// taken.
+func takeSnapshotOfSheet(sheet *Sheet) bool {
+	if !xos.FileExists(sheet.BackingFilePath()) {
+		unison.WarningDialogWithMessage(i18n.Text("Unable to take a snapshot"),
+			i18n.Text("The character sheet must be saved before snapshots can be taken."))
+		return false
+	}
+	if _, err := gurps.TakeSnapshot(sheet.entity, sheet.BackingFilePath()); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to take a snapshot"), err)
+		return false
+	}
+	return true
+}
+
+// autoSnapshotSheet saves a snapshot of the sheet, if the settings call for one to be taken on save.
+func autoSnapshotSheet(sheet *Sheet) {
+	if gurps.GlobalSettings().General.SnapshotOnSave {
+		if _, err := gurps.TakeSnapshot(sheet.entity, sheet.BackingFilePath()); err != nil {
+			errs.Log(err, "path", sheet.BackingFilePath())
+		}
+		reloadSheetHistory(sheet)
+	}
+}
+
+// reloadSheetHistory reloads the history of the sheet, if it is being shown.
+func reloadSheetHistory(sheet *Sheet) {
+	for _, d := range AllDockables() {
+		if h, ok := d.(*sheetHistoryDockable); ok && h.sheet == sheet {
+			h.reload()
+		}
+	}
+}
+
+// ShowSheetHistory shows the snapshots and change log for the sheet.
+func ShowSheetHistory(sheet *Sheet) {
+	if Activate(func(d unison.Dockable) bool {
+		if h, ok := d.AsPanel().Self.(*sheetHistoryDockable); ok {
+			return h.sheet == sheet
+		}
+		return false
+	}) {
+		return
+	}
+	d := &sheetHistoryDockable{sheet: sheet}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	d.reload()
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *sheetHistoryDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+
+	snapshotButton := unison.NewButton()
+	snapshotButton.SetTitle(i18n.Text("Take Snapshot"))
+	snapshotButton.Tooltip = newWrappedTooltip(i18n.Text("Save a snapshot of the sheet as it is now"))
+	snapshotButton.ClickCallback = func() {
+		if takeSnapshotOfSheet(d.sheet) {
+			d.reload()
+		}
+	}
+	toolbar.AddChild(snapshotButton)
+
+	d.compareButton = unison.NewButton()
+	d.compareButton.SetTitle(i18n.Text("Compare"))
+	d.compareButton.Tooltip = newWrappedTooltip(i18n.Text(`Compare the two selected snapshots, or the selected snapshot
+with the sheet as it is now`))
+	d.compareButton.ClickCallback = d.compare
+	toolbar.AddChild(d.compareButton)
+
+	d.restoreButton = unison.NewButton()
+	d.restoreButton.SetTitle(i18n.Text("Restore"))
+	d.restoreButton.Tooltip = newWrappedTooltip(i18n.Text("Replace the contents of the sheet with the selected snapshot"))
+	d.restoreButton.ClickCallback = d.restore
+	toolbar.AddChild(d.restoreButton)
+
+	d.deleteButton = unison.NewButton()
+	d.deleteButton.SetTitle(i18n.Text("Delete"))
+	d.deleteButton.Tooltip = newWrappedTooltip(i18n.Text("Delete the selected snapshots"))
+	d.deleteButton.ClickCallback = d.deleteSelection
+	toolbar.AddChild(d.deleteButton)
+
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *sheetHistoryDockable) reload() {
+	var err error
+	if d.snapshots, err = gurps.Snapshots(d.sheet.BackingFilePath()); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load the snapshot history"), err)
+	}
+	if d.changes, err = gurps.SheetChanges(d.sheet.BackingFilePath()); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load the change log"), err)
+	}
+	selected := make(map[*gurps.SheetSnapshot]bool)
+	for _, one := range d.snapshots {
+		for prior := range d.selected {
+			if prior.Path == one.Path {
+				selected[one] = true
+			}
+		}
+	}
+	d.selected = selected
+	d.rebuild()
+}
+
+func (d *sheetHistoryDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	if len(d.snapshots) == 0 {
+		label := unison.NewLabel()
+		label.SetTitle(i18n.Text("No snapshots have been taken of this sheet"))
+		d.content.AddChild(label)
+	}
+	for _, one := range d.snapshots {
+		box := unison.NewCheckBox()
+		box.SetTitle(one.String())
+		box.State = check.FromBool(d.selected[one])
+		box.ClickCallback = func() {
+			if box.State == check.On {
+				d.selected[one] = true
+			} else {
+				delete(d.selected, one)
+			}
+			d.adjustButtons()
+		}
+		d.content.AddChild(box)
+	}
+	if d.diffTitle != "" {
+		d.content.AddChild(d.createDiffPanel())
+	}
+	d.content.AddChild(d.createChangeLogPanel())
+	d.adjustButtons()
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *sheetHistoryDockable) adjustButtons() {
+	count := len(d.selected)
+	d.compareButton.SetEnabled(count == 1 || count == 2)
+	d.restoreButton.SetEnabled(count == 1 && !d.sheet.entity.Locked)
+	d.deleteButton.SetEnabled(count != 0)
+}
+
+// selection returns the selected snapshots, oldest first.
+func (d *sheetHistoryDockable) selection() []*gurps.SheetSnapshot {
+	var list []*gurps.SheetSnapshot
+	for i := len(d.snapshots) - 1; i >= 0; i-- {
+		if d.selected[d.snapshots[i]] {
+			list = append(list, d.snapshots[i])
+		}
+	}
+	return list
+}
+
+func (d *sheetHistoryDockable) compare() {
+	list := d.selection()
+	if len(list) == 0 || len(list) > 2 {
+		return
+	}
+	before, err := list[0].Load()
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to load snapshot"), err)
+		return
+	}
+	after := d.sheet.entity
+	afterTitle := i18n.Text("the current sheet")
+	if len(list) == 2 {
+		if after, err = list[1].Load(); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to load snapshot"), err)
+			return
+		}
+		afterTitle = list[1].String()
+	}
+	d.diffs = gurps.DiffEntities(before, after)
+	d.diffTitle = fmt.Sprintf(i18n.Text("Changes from %s to %s"), list[0].String(), afterTitle)
+	d.rebuild()
+}
+
+func (d *sheetHistoryDockable) createDiffPanel() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	boldFont := sheetHistoryBoldFont()
+	header := unison.NewLabel()
+	header.Font = boldFont
+	header.SetTitle(d.diffTitle)
+	header.SetLayoutData(&unison.FlexLayoutData{HSpan: 4})
+	panel.AddChild(header)
+	if len(d.diffs) == 0 {
+		label := unison.NewLabel()
+		label.SetTitle(i18n.Text("No differences"))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 4})
+		panel.AddChild(label)
+		return panel
+	}
+	for _, title := range []string{i18n.Text("Category"), i18n.Text("Name"), i18n.Text("Before"), i18n.Text("After")} {
+		label := unison.NewLabel()
+		label.Font = boldFont
+		label.SetTitle(title)
+		panel.AddChild(label)
+	}
+	for _, diff := range d.diffs {
+		beforeText := diff.Before
+		afterText := diff.After
+		switch {
+		case beforeText == "":
+			beforeText = i18n.Text("(added)")
+		case afterText == "":
+			afterText = i18n.Text("(removed)")
+		}
+		for _, text := range []string{diff.Category, diff.Name, beforeText, afterText} {
+			label := unison.NewLabel()
+			label.SetTitle(text)
+			panel.AddChild(label)
+		}
+	}
+	return panel
+}
+
+func (d *sheetHistoryDockable) createChangeLogPanel() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	header := unison.NewLabel()
+	header.Font = sheetHistoryBoldFont()
+	header.SetTitle(i18n.Text("Change Log"))
+	header.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+	panel.AddChild(header)
+	if len(d.changes) == 0 {
+		label := unison.NewLabel()
+		label.SetTitle(i18n.Text("No changes have been saved since the change log was started"))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+		panel.AddChild(label)
+		return panel
+	}
+	var when time.Time
+	shown := 0
+	for i := len(d.changes) - 1; i >= 0; i-- {
+		if shown == sheetChangeLogMaxRows {
+			label := unison.NewLabel()
+			label.SetTitle(fmt.Sprintf(i18n.Text("%d older changes are not shown"), i+1))
+			label.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+			panel.AddChild(label)
+			break
+		}
+		change := d.changes[i]
+		if !change.When.Equal(when) {
+			when = change.When
+			label := unison.NewLabel()
+			label.SetTitle(fmt.Sprintf(i18n.Text("Saved %s"), when.Local().Format(time.DateTime)))
+			label.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing}))
+			label.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+			panel.AddChild(label)
+		}
+		label := unison.NewLabel()
+		label.SetTitle(strings.Join(change.Path, " › "))
+		panel.AddChild(label)
+		panel.AddChild(newChangeLogValueLabel(change.Before, i18n.Text("(added)")))
+		panel.AddChild(newChangeLogValueLabel(change.After, i18n.Text("(removed)")))
+		shown++
+	}
+	return panel
+}
+
+func newChangeLogValueLabel(v jsontext.Value, absent string) *unison.Label {
+	label := unison.NewLabel()
+	if len(v) == 0 {
+		label.SetTitle(absent)
+		return label
+	}
+	text := describeMergeValue(v)
+	label.SetTitle(text)
+	if text != string(v) {
+		label.Tooltip = newWrappedTooltip(string(v))
+	}
+	return label
+}
+
+func (d *sheetHistoryDockable) restore() {
+	list := d.selection()
+	if len(list) != 1 {
+		return
+	}
+	if d.sheet.entity.Locked {
+		unison.ErrorDialogWithMessage(i18n.Text("Unable to restore snapshot"), gurps.SheetLocked())
+		return
+	}
+	if unison.QuestionDialog(fmt.Sprintf(i18n.Text("Restore the snapshot from %s?"), list[0].String()),
+		i18n.Text("A snapshot of the sheet as it is now will be taken first.")) != unison.ModalResponseOK {
+		return
+	}
+	if !takeSnapshotOfSheet(d.sheet) {
+		return
+	}
+	if err := list[0].RestoreInto(d.sheet.entity); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to restore snapshot"), err)
+		return
+	}
+	d.sheet.undoMgr.Clear()
+	d.sheet.Rebuild(true)
+	d.diffs = nil
+	d.diffTitle = ""
+	d.reload()
+}
+
+func (d *sheetHistoryDockable) deleteSelection() {
+	list := d.selection()
+	if len(list) == 0 {
+		return
+	}
+	var msg string
+	if len(list) == 1 {
+		msg = fmt.Sprintf(i18n.Text("Delete the snapshot from %s?"), list[0].String())
+	} else {
+		msg = fmt.Sprintf(i18n.Text("Delete %d snapshots?"), len(list))
+	}
+	if unison.QuestionDialog(msg, i18n.Text("This cannot be undone.")) != unison.ModalResponseOK {
+		return
+	}
+	for _, one := range list {
+		if err := one.Delete(); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to delete snapshot"), err)
+			break
+		}
+	}
+	d.reload()
+}
+
+func sheetHistoryBoldFont() unison.Font {
+	return &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			desc := unison.DefaultLabelTheme.Font.Descriptor()
+			desc.Weight = weight.Bold
+			return desc
+		},
+	}
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *sheetHistoryDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Stack,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *sheetHistoryDockable) Title() string {
+	return fmt.Sprintf(i18n.Text("History of %s"), d.sheet.Title())
+}
+
+// Tooltip implements unison.Dockable.
+func (d *sheetHistoryDockable) Tooltip() string {
+	return gurps.SnapshotDir(d.sheet.BackingFilePath())
+}
+
+// Modified implements unison.Dockable.
+func (d *sheetHistoryDockable) Modified() bool {
+	return false
+}
+
+// CloseWithGroup implements GroupedCloser.
+func (d *sheetHistoryDockable) CloseWithGroup(other unison.Paneler) bool {
+	return d.sheet != nil && d.sheet == other
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *sheetHistoryDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *sheetHistoryDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/importer"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+// ImportSheet asks for a file with one of the given extensions and then uses the importer to convert it into a new,
+// unsaved character sheet. Any items the importer could not match against the libraries are reported afterward.
+func ImportSheet(importFile func(filePath string, catalog *importer.Catalog) (*importer.Result, error), ext ...string) {
+	filePath, ok := chooseFileToImport(ext...)
+	if !ok {
+		return
+	}
+	result, err := importFile(filePath, importer.NewCatalogFromLibraries(gurps.GlobalSettings().Libraries()))
+	showImportedSheet(filePath, result, err)
+}
+
+// chooseFileToImport asks for a file with one of the given extensions to import.
+func chooseFileToImport(ext ...string) (filePath string, ok bool) {
+	dialog := unison.NewOpenDialog()
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(ext...)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	if !dialog.RunModal() {
+		return "", false
+	}
+	filePath = dialog.Path()
+	global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(filePath))
+	return filePath, true
+}
+
+// showImportedSheet displays the sheet imported from the file, or reports the error that prevented it.
+func showImportedSheet(filePath string, result *importer.Result, err error) {
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Import failed"), err)
+		return
+	}
+	name := result.Entity.Profile.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+	DisplayNewDockable(NewSheet(name+gurps.SheetExt, result.Entity))
+	if len(result.Unmatched) != 0 {
+		unison.WarningDialogWithMessage(i18n.Text("Some items need review"),
+			fmt.Sprintf(i18n.Text("The following items could not be matched against the libraries and have been tagged with %q:"),
+				importer.ReviewTag)+"\n\n"+strings.Join(result.Unmatched, "\n"))
+	}
+}
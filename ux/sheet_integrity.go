@@ -0,0 +1,130 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+// checkIntegrity recalculates every derived value of the sheet from scratch, then summarizes those stored in its file
+// that disagree, offering to repair them by saving the sheet again.
+func (s *Sheet) checkIntegrity() {
+	if s.needsSaveAsPrompt {
+		unison.WarningDialogWithMessage(i18n.Text("Unable to check the sheet"),
+			i18n.Text("The sheet has not been saved yet, so there are no stored values to check."))
+		return
+	}
+	filePath := s.BackingFilePath()
+	issues, err := gurps.CheckSheetIntegrity(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath))
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to check the sheet"), err)
+		return
+	}
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	content.AddChild(label)
+	buttons := []*unison.DialogButtonInfo{unison.NewOKButtonInfo()}
+	if len(issues) == 0 {
+		label.SetTitle(i18n.Text("Every stored value agrees with the value calculated from scratch."))
+	} else {
+		label.SetTitle(fmt.Sprintf(i18n.Text("%d stored values in %d rows disagree with those calculated from scratch."),
+			len(issues), countIntegrityRows(issues)))
+		content.AddChild(newIntegrityIssuesPanel(issues))
+		note := unison.NewLabel()
+		if s.Modified() {
+			note.SetTitle(i18n.Text("Repairing saves the sheet with the recalculated values, along with its unsaved changes."))
+		} else {
+			note.SetTitle(i18n.Text("Repairing saves the sheet with the recalculated values."))
+		}
+		content.AddChild(note)
+		repairButton := unison.NewOKButtonInfo()
+		repairButton.Title = i18n.Text("Repair")
+		buttons = []*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), repairButton}
+	}
+	icon := &unison.DrawableSVG{
+		SVG:  svg.Calculator,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content, buttons)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create integrity check dialog"), err)
+		return
+	}
+	if dialog.RunModal() == unison.ModalResponseOK && len(issues) != 0 {
+		s.save(false)
+	}
+}
+
+func countIntegrityRows(issues []*gurps.IntegrityIssue) int {
+	rows := make(map[string]bool)
+	for _, issue := range issues {
+		rows[strings.Join(issue.Row, "\x00")] = true
+	}
+	return len(rows)
+}
+
+func newIntegrityIssuesPanel(issues []*gurps.IntegrityIssue) *unison.ScrollPanel {
+	panel := unison.NewPanel()
+	panel.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	for _, title := range []string{i18n.Text("Row"), i18n.Text("Value"), i18n.Text("Stored"), i18n.Text("Calculated")} {
+		header := unison.NewLabel()
+		header.Font = sheetHistoryBoldFont()
+		header.SetTitle(title)
+		panel.AddChild(header)
+	}
+	for _, issue := range issues {
+		row := unison.NewLabel()
+		if len(issue.Row) == 0 {
+			row.SetTitle(i18n.Text("(sheet)"))
+		} else {
+			row.SetTitle(strings.Join(issue.Row, " › "))
+		}
+		panel.AddChild(row)
+		field := unison.NewLabel()
+		if issue.Field == "" {
+			field.SetTitle(i18n.Text("(all)"))
+		} else {
+			field.SetTitle(issue.Field)
+		}
+		panel.AddChild(field)
+		panel.AddChild(newChangeLogValueLabel(issue.Stored, i18n.Text("(missing)")))
+		panel.AddChild(newChangeLogValueLabel(issue.Calculated, i18n.Text("(none)")))
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(panel, behavior.Fill, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: geom.NewSize(600, min(400, 24*float32(len(issues)+1))),
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	return scroll
+}
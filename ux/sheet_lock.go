@@ -0,0 +1,171 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+// buildDataClientKey marks panels that change a sheet's build data, which may not be edited while the sheet is locked.
+const buildDataClientKey = "build-data"
+
+// buildDataCmdIDs holds the commands that alter the contents of a list holding build data.
+var buildDataCmdIDs = []int{
+	OpenEditorItemID,
+	unison.DeleteItemID,
+	DuplicateItemID,
+	SyncWithSourceItemID,
+	ReviewSourceChangesItemID,
+	ClearSourceItemID,
+	ToggleStateItemID,
+	IncrementItemID,
+	DecrementItemID,
+	IncrementSkillLevelItemID,
+	DecrementSkillLevelItemID,
+	IncrementTechLevelItemID,
+	DecrementTechLevelItemID,
+	ApplyTraitModifierItemID,
+	OptimizeSkillLevelsItemID,
+}
+
+// markAsBuildData marks the panel as one that changes the entity's build data, disabling it while the entity is locked.
+func markAsBuildData(entity *gurps.Entity, p unison.Paneler) {
+	panel := p.AsPanel()
+	panel.ClientData()[buildDataClientKey] = true
+	panel.SetEnabled(!entity.Locked)
+}
+
+// syncBuildData enables or disables the panels within root that were marked with markAsBuildData, to match the lock
+// state of the entity.
+func syncBuildData(entity *gurps.Entity, root unison.Paneler) {
+	root.AsPanel().HasInSelfOrDescendants(func(p *unison.Panel) bool {
+		if _, ok := p.ClientData()[buildDataClientKey]; ok && p.Enabled() == entity.Locked {
+			p.SetEnabled(!entity.Locked)
+			p.MarkForRedraw()
+		}
+		return false
+	})
+}
+
+// lockWith prevents the list from being altered while the entity is locked. Its contents may still be selected,
+// searched and dragged elsewhere.
+func (p *PageList[T]) lockWith(entity *gurps.Entity) {
+	for _, panel := range []*unison.Panel{p.AsPanel(), p.Table.AsPanel()} {
+		for _, id := range buildDataCmdIDs {
+			if can, do := panel.InstallCmdHandlers(id, nil, nil); can != nil {
+				panel.InstallCmdHandlers(id, func(src any) bool { return !entity.Locked && can(src) }, do)
+			} else {
+				panel.RemoveCmdHandler(id)
+			}
+		}
+	}
+	originalDataDragOverCallback := p.Table.DataDragOverCallback
+	p.Table.DataDragOverCallback = func(where geom.Point, data map[string]any) bool {
+		return !entity.Locked && originalDataDragOverCallback != nil && originalDataDragOverCallback(where, data)
+	}
+	originalDataDragDropCallback := p.Table.DataDragDropCallback
+	p.Table.DataDragDropCallback = func(where geom.Point, data map[string]any) {
+		if !entity.Locked && originalDataDragDropCallback != nil {
+			originalDataDragDropCallback(where, data)
+		}
+	}
+}
+
+func (s *Sheet) createLockButton() *unison.Button {
+	s.lockButton = unison.NewSVGButton(svg.Unlock)
+	s.lockButton.ClickCallback = s.toggleLock
+	s.syncLockButton()
+	return s.lockButton
+}
+
+func (s *Sheet) syncLockButton() {
+	if s.lockButton == nil {
+		return
+	}
+	var icon *unison.SVG
+	var tip string
+	if s.entity.Locked {
+		icon = svg.Lock
+		if s.entity.LockPassword != "" {
+			tip = i18n.Text("Locked by the GM. Traits, skills, spells, attributes and points may not be changed until a password is entered.")
+		} else {
+			tip = i18n.Text("Locked. Traits, skills, spells, attributes and points may not be changed until the sheet is unlocked.")
+		}
+	} else {
+		icon = svg.Unlock
+		tip = i18n.Text("Lock the sheet, preventing changes to traits, skills, spells, attributes and points while still allowing play-time changes, such as to current HP & FP, equipment and notes")
+	}
+	if d, ok := s.lockButton.Drawable.(*unison.DrawableSVG); ok {
+		d.SVG = icon
+	}
+	s.lockButton.Tooltip = newWrappedTooltip(tip)
+	s.lockButton.MarkForRedraw()
+}
+
+// unlocked may be used as a command's enablement check, permitting it only while the sheet is unlocked.
+func (s *Sheet) unlocked(_ any) bool {
+	return !s.entity.Locked
+}
+
+func (s *Sheet) toggleLock() {
+	if s.entity.Locked {
+		password := ""
+		if s.entity.LockPassword != "" {
+			if !askForLockPassword(i18n.Text("Unlock Sheet"), &password) {
+				return
+			}
+		}
+		if !s.entity.Unlock(password) {
+			unison.ErrorDialogWithMessage(i18n.Text("Unable to unlock the sheet"), i18n.Text("The password is incorrect."))
+			return
+		}
+	} else {
+		s.entity.Locked = true
+		// Edits made before the sheet was locked must not be undone while it is locked.
+		s.undoMgr.Clear()
+	}
+	s.Rebuild(true)
+	reloadSheetHistory(s)
+	MarkModified(s)
+}
+
+// askForLockPassword asks for a lock password, returning false if the dialog was canceled.
+func askForLockPassword(okTitle string, password *string) bool {
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := i18n.Text("Password")
+	content.AddChild(NewFieldLeadingLabel(label, false))
+	field := NewStringField(nil, "", label, func() string { return *password }, func(s string) { *password = s })
+	field.ObscurementRune = '•'
+	field.SetMinimumTextWidthUsing("0123456789012345678901234")
+	content.AddChild(field)
+	icon := &unison.DrawableSVG{
+		SVG:  svg.Lock,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	okButton := unison.NewOKButtonInfo()
+	okButton.Title = okTitle
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), okButton},
+		unison.NotResizableWindowOption())
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create password dialog"), err)
+		return false
+	}
+	return dialog.RunModal() == unison.ModalResponseOK
+}
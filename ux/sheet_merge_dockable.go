@@ -0,0 +1,354 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/merge"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+const maxMergeValueDisplayLength = 60
+
+var (
+	_ unison.Dockable  = &sheetMergeDockable{}
+	_ unison.TabCloser = &sheetMergeDockable{}
+)
+
+type sheetMergeDockable struct {
+	unison.Panel
+	paths      [3]string
+	pathLabels [3]*unison.Label
+	result     *merge.Result
+	saveButton *unison.Button
+	content    *unison.Panel
+}
+
+// ShowSheetMerge shows a view for combining two edited copies of a character sheet or template with the copy they were
+// both made from.
+func ShowSheetMerge() {
+	if Activate(func(d unison.Dockable) bool {
+		_, ok := d.AsPanel().Self.(*sheetMergeDockable)
+		return ok
+	}) {
+		return
+	}
+	d := &sheetMergeDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.content, behavior.Unmodified, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	d.rebuild()
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *sheetMergeDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	titles := [3]string{i18n.Text("Common Ancestor"), i18n.Text("Mine"), i18n.Text("Theirs")}
+	tooltips := [3]string{
+		i18n.Text("The copy that both of the edited copies were made from"),
+		i18n.Text("Your edited copy"),
+		i18n.Text("The other edited copy"),
+	}
+	for i := range d.paths {
+		toolbar.AddChild(NewFieldLeadingLabel(titles[i], false))
+		d.pathLabels[i] = unison.NewLabel()
+		d.pathLabels[i].SetTitle(i18n.Text("None chosen"))
+		d.pathLabels[i].SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		toolbar.AddChild(d.pathLabels[i])
+		button := unison.NewButton()
+		button.SetTitle(i18n.Text("Choose…"))
+		button.Tooltip = newWrappedTooltip(tooltips[i])
+		button.ClickCallback = func() { d.choose(i) }
+		toolbar.AddChild(button)
+	}
+
+	buttons := unison.NewPanel()
+	mergeButton := unison.NewButton()
+	mergeButton.SetTitle(i18n.Text("Merge"))
+	mergeButton.Tooltip = newWrappedTooltip(i18n.Text("Combine the changes made in both edited copies"))
+	mergeButton.ClickCallback = d.merge
+	buttons.AddChild(mergeButton)
+	d.saveButton = unison.NewButton()
+	d.saveButton.SetTitle(i18n.Text("Save Merged…"))
+	d.saveButton.Tooltip = newWrappedTooltip(i18n.Text("Save the merged result, then open it"))
+	d.saveButton.ClickCallback = d.save
+	d.saveButton.SetEnabled(false)
+	buttons.AddChild(d.saveButton)
+	buttons.SetLayout(&unison.FlexLayout{
+		Columns:  len(buttons.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+	buttons.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+	toolbar.AddChild(buttons)
+
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *sheetMergeDockable) choose(which int) {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.SheetExt, gurps.TemplatesExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	global := gurps.GlobalSettings()
+	if d.paths[which] != "" {
+		dialog.SetInitialDirectory(filepath.Dir(d.paths[which]))
+	} else {
+		dialog.SetInitialDirectory(global.LastDir(gurps.DefaultLastDirKey))
+	}
+	if dialog.RunModal() {
+		p := dialog.Path()
+		global.SetLastDir(gurps.DefaultLastDirKey, filepath.Dir(p))
+		d.paths[which] = p
+		d.pathLabels[which].SetTitle(p)
+		d.pathLabels[which].Tooltip = newWrappedTooltip(p)
+		d.result = nil
+		d.saveButton.SetEnabled(false)
+		d.rebuild()
+	}
+}
+
+func (d *sheetMergeDockable) merge() {
+	var data [3][]byte
+	for i, p := range d.paths {
+		if p == "" {
+			unison.WarningDialogWithMessage(i18n.Text("Unable to merge"),
+				i18n.Text("Choose the common ancestor and both edited copies first."))
+			return
+		}
+		if !strings.EqualFold(filepath.Ext(p), filepath.Ext(d.paths[0])) {
+			unison.WarningDialogWithMessage(i18n.Text("Unable to merge"),
+				i18n.Text("All three copies must be the same kind of file."))
+			return
+		}
+		var err error
+		if data[i], err = os.ReadFile(p); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Unable to read ")+p, err)
+			return
+		}
+	}
+	result, err := merge.Merge(data[0], data[1], data[2])
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to merge"), err)
+		return
+	}
+	d.result = result
+	d.saveButton.SetEnabled(true)
+	d.rebuild()
+}
+
+func (d *sheetMergeDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  5,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: 5})
+	d.content.AddChild(label)
+	if d.result == nil {
+		label.SetTitle(i18n.Text("Choose the three copies to merge, then press Merge"))
+		d.MarkForLayoutAndRedraw()
+		return
+	}
+	label.SetTitle(fmt.Sprintf(i18n.Text("%d of their changes were merged automatically. %d conflicting changes remain."),
+		d.result.Applied, len(d.result.Conflicts)))
+	if len(d.result.Conflicts) == 0 {
+		d.MarkForLayoutAndRedraw()
+		return
+	}
+	for _, header := range []string{
+		i18n.Text("Location"),
+		i18n.Text("Common Ancestor"),
+		i18n.Text("Mine"),
+		i18n.Text("Theirs"),
+		i18n.Text("Keep"),
+	} {
+		label = unison.NewLabel()
+		label.Font = fonts.PageLabelSecondary
+		label.SetTitle(header)
+		d.content.AddChild(label)
+	}
+	keepMine := i18n.Text("Mine")
+	keepTheirs := i18n.Text("Theirs")
+	for _, c := range d.result.Conflicts {
+		label = unison.NewLabel()
+		label.SetTitle(strings.Join(c.Path, " › "))
+		d.content.AddChild(label)
+		for _, v := range []jsontext.Value{c.Base, c.Ours, c.Theirs} {
+			label = unison.NewLabel()
+			text := describeMergeValue(v)
+			label.SetTitle(text)
+			if len(v) > 0 && text != string(v) {
+				label.Tooltip = newWrappedTooltip(string(v))
+			}
+			d.content.AddChild(label)
+		}
+		popup := unison.NewPopupMenu[string]()
+		popup.AddItem(keepMine, keepTheirs)
+		if c.Resolution == merge.UseTheirs {
+			popup.Select(keepTheirs)
+		} else {
+			popup.Select(keepMine)
+		}
+		popup.SelectionChangedCallback = func(p *unison.PopupMenu[string]) {
+			if item, ok := p.Selected(); ok && item == keepTheirs {
+				c.Resolution = merge.UseTheirs
+			} else {
+				c.Resolution = merge.UseOurs
+			}
+		}
+		d.content.AddChild(popup)
+	}
+	d.MarkForLayoutAndRedraw()
+}
+
+// describeMergeValue returns a short description of a JSON value for display.
+func describeMergeValue(v jsontext.Value) string {
+	if len(v) == 0 {
+		return i18n.Text("(removed)")
+	}
+	var text string
+	if v.Kind() == '"' {
+		if err := json.Unmarshal(v, &text); err != nil {
+			text = string(v)
+		}
+	} else {
+		text = string(v)
+	}
+	if text = strings.Join(strings.Fields(text), " "); len([]rune(text)) > maxMergeValueDisplayLength {
+		text = string([]rune(text)[:maxMergeValueDisplayLength-1]) + "…"
+	}
+	return text
+}
+
+func (d *sheetMergeDockable) save() {
+	if d.result == nil {
+		return
+	}
+	data, err := d.result.Bytes()
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to merge"), err)
+		return
+	}
+	ext := filepath.Ext(d.paths[1])
+	dialog := unison.NewSaveDialog()
+	dialog.SetInitialDirectory(filepath.Dir(d.paths[1]))
+	dialog.SetAllowedExtensions(ext)
+	dialog.SetInitialFileName(xfilepath.SanitizeName(xfilepath.BaseName(d.paths[1])))
+	if !dialog.RunModal() {
+		return
+	}
+	filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), ext, false)
+	if !ok {
+		return
+	}
+	if existing := LocateFileBackedDockable(filePath); existing != nil {
+		if existing.Modified() {
+			unison.WarningDialogWithMessage(i18n.Text("Unable to save the merged result"),
+				fmt.Sprintf(i18n.Text("%s is open and has unsaved changes."), existing.Title()))
+			return
+		}
+		if !AttemptCloseForDockable(existing) {
+			return
+		}
+	}
+	if err = xos.WriteSafeFile(filePath, func(w io.Writer) error {
+		_, writeErr := w.Write(data)
+		return errs.Wrap(writeErr)
+	}); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to save ")+filePath, err)
+		return
+	}
+	Workspace.Navigator.EventuallyReload()
+	OpenFile(filePath, 0)
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *sheetMergeDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.GCSSheet,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *sheetMergeDockable) Title() string {
+	return i18n.Text("Sheet Merge")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *sheetMergeDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *sheetMergeDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *sheetMergeDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *sheetMergeDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
@@ -16,8 +16,12 @@ import (
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/casting"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/density"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/display"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/eqploc"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/progression"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/study"
 	"github.com/richardwilkes/gcs/v5/model/paper"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/v2/i18n"
@@ -46,12 +50,17 @@ type sheetSettingsDockable struct {
 	SettingsDockable
 	owner                              EntityPanel
 	damageProgressionPopup             *unison.PopupMenu[progression.Option]
+	castingModePopup                   *unison.PopupMenu[casting.Mode]
+	magicThresholdField                *IntegerField
+	studyTrackingPopup                 *unison.PopupMenu[study.Tracking]
 	showTraitModifier                  *unison.CheckBox
 	showEquipmentModifier              *unison.CheckBox
 	showAllWeapons                     *unison.CheckBox
 	showSpellAdjustments               *unison.CheckBox
 	hideSourceMismatch                 *unison.CheckBox
 	hidePageRefColumn                  *unison.CheckBox
+	showHitLocationColumn              *unison.CheckBox
+	showEquipmentLocationColumn        *unison.CheckBox
 	hideTLColumn                       *unison.CheckBox
 	hideLCColumn                       *unison.CheckBox
 	showTitleInsteadOfNameInPageFooter *unison.CheckBox
@@ -61,6 +70,9 @@ type sheetSettingsDockable struct {
 	useHalfStatDefaults                *unison.CheckBox
 	showLiftingSTDamage                *unison.CheckBox
 	showIQBasedDamage                  *unison.CheckBox
+	useTechnicalGrappling              *unison.CheckBox
+	useRitualPathMagic                 *unison.CheckBox
+	useHeightBasedSizeModifier         *unison.CheckBox
 	lengthUnitsPopup                   *unison.PopupMenu[fxp.LengthUnit]
 	weightUnitsPopup                   *unison.PopupMenu[fxp.WeightUnit]
 	userDescDisplayPopup               *unison.PopupMenu[display.Option]
@@ -68,12 +80,17 @@ type sheetSettingsDockable struct {
 	notesDisplayPopup                  *unison.PopupMenu[display.Option]
 	skillLevelAdjDisplayPopup          *unison.PopupMenu[display.Option]
 	orientationPopup                   *unison.PopupMenu[paper.Orientation]
+	themePopup                         *unison.PopupMenu[sheetThemeChoice]
+	densityPopup                       *unison.PopupMenu[density.Option]
+	fontScaleField                     *PercentageField
+	languagePopup                      *unison.PopupMenu[sheetLanguageChoice]
 	paperSizeField                     *unison.Field
 	topMarginField                     *unison.Field
 	leftMarginField                    *unison.Field
 	bottomMarginField                  *unison.Field
 	rightMarginField                   *unison.Field
 	blockLayoutField                   *unison.Field
+	lastBlockLayout                    string
 	useSkillModifierAdjustments        *unison.CheckBox
 	skillModifierOverridePanel         *unison.Panel
 	skillModifierAdjustmentPanel       *unison.Panel
@@ -89,6 +106,9 @@ type sheetSettingsDockable struct {
 	includeDodgeFlatBonus                     *unison.CheckBox
 	usePassiveDefense                         *unison.CheckBox
 	dodgeOverrideField                        *DecimalField
+	locationEncumbranceCheckBoxes             []*unison.CheckBox
+	locationValueCheckBoxes                   []*unison.CheckBox
+	customBlocksPanel                         *unison.Panel
 }
 
 // ShowSheetSettings the Sheet Settings. Pass in nil to edit the defaults or a sheet to edit the sheet's.
@@ -141,10 +161,13 @@ func (d *sheetSettingsDockable) initContent(content *unison.Panel) {
 		VSpacing: unison.DefaultLabelTheme.Font.LineHeight(),
 	})
 	d.createDamageProgression(content)
+	d.createCastingMode(content)
+	d.createStudyTracking(content)
 	d.createOptions(content)
 	d.createSkillDifficultyModifiers(content)
 	d.createDodgeCustomization(content)
 	d.createPassiveDefense(content)
+	d.createEquipmentLocations(content)
 	d.createUnitsOfMeasurement(content)
 	d.createWhereToDisplay(content)
 	d.createPageSettings(content)
@@ -175,6 +198,55 @@ func (d *sheetSettingsDockable) createDamageProgression(content *unison.Panel) {
 	content.AddChild(panel)
 }
 
+func (d *sheetSettingsDockable) createCastingMode(content *unison.Panel) {
+	s := d.settings()
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	desc := unison.NewMarkdown(true)
+	desc.SetContent(s.CastingMode.AltString(), -1)
+	d.castingModePopup = createSettingPopup(d, panel, i18n.Text("Spellcasting Cost"), casting.Modes, s.CastingMode,
+		func(item casting.Mode) {
+			d.settings().CastingMode = item
+			d.magicThresholdField.SetEnabled(item == casting.Threshold)
+			desc.SetContent(item.AltString(), -1)
+			desc.MarkForLayoutRecursivelyUpward()
+			desc.MarkForRedraw()
+		})
+	d.castingModePopup.Tooltip = newWrappedTooltip(i18n.Text("Determines how the energy needed to cast and maintain spells is paid for"))
+	panel.AddChild(unison.NewPanel())
+	panel.AddChild(desc)
+	label := i18n.Text("Threshold")
+	panel.AddChild(NewFieldLeadingLabel(label, false))
+	d.magicThresholdField = NewIntegerField(nil, "", label,
+		func() int { return d.settings().MagicThreshold },
+		func(value int) {
+			d.settings().MagicThreshold = value
+			d.syncSheet(false)
+		}, 1, 999, false, false)
+	d.magicThresholdField.Tooltip = newWrappedTooltip(i18n.Text("The tally above which threshold-limited magic requires a calamity check"))
+	d.magicThresholdField.SetEnabled(s.CastingMode == casting.Threshold)
+	panel.AddChild(d.magicThresholdField)
+	content.AddChild(panel)
+}
+
+func (d *sheetSettingsDockable) createStudyTracking(content *unison.Panel) {
+	s := d.settings()
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.studyTrackingPopup = createSettingPopup(d, panel, i18n.Text("Points Earned Through Study"), study.Trackings,
+		s.StudyTracking, func(item study.Tracking) { d.settings().StudyTracking = item })
+	d.studyTrackingPopup.Tooltip = newWrappedTooltip(i18n.Text("Whether points added by increasing a level must first be earned through recorded study (B292)"))
+	content.AddChild(panel)
+}
+
 func (d *sheetSettingsDockable) createOptions(content *unison.Panel) {
 	s := d.settings()
 	panel := unison.NewPanel()
@@ -203,6 +275,16 @@ func (d *sheetSettingsDockable) createOptions(content *unison.Panel) {
 			d.settings().HideLCColumn = d.hideLCColumn.State != check.On
 			d.syncSheet(true)
 		})
+	d.showHitLocationColumn = d.addCheckBox(panel, i18n.Text("Show hit location column in weapon tables"),
+		s.ShowHitLocationColumn, func() {
+			d.settings().ShowHitLocationColumn = d.showHitLocationColumn.State == check.On
+			d.syncSheet(true)
+		})
+	d.showEquipmentLocationColumn = d.addCheckBox(panel, i18n.Text("Show location column in carried equipment table"),
+		s.ShowEquipmentLocationColumn, func() {
+			d.settings().ShowEquipmentLocationColumn = d.showEquipmentLocationColumn.State == check.On
+			d.syncSheet(true)
+		})
 	d.showTraitModifier = d.addCheckBox(panel, i18n.Text("Show trait modifier cost adjustments"),
 		s.ShowTraitModifierAdj, func() {
 			d.settings().ShowTraitModifierAdj = d.showTraitModifier.State == check.On
@@ -258,6 +340,21 @@ func (d *sheetSettingsDockable) createOptions(content *unison.Panel) {
 			d.settings().ShowIQBasedDamage = d.showIQBasedDamage.State == check.On
 			d.syncSheet(false)
 		})
+	d.useHeightBasedSizeModifier = d.addCheckBoxWithLink(panel, i18n.Text("Derive Size Modifier from height"), "B19",
+		s.UseHeightBasedSizeModifier, func() {
+			d.settings().UseHeightBasedSizeModifier = d.useHeightBasedSizeModifier.State == check.On
+			d.syncSheet(true)
+		})
+	d.useTechnicalGrappling = d.addCheckBoxWithLink(panel, i18n.Text("Use Technical Grappling"), "TG4",
+		s.TechnicalGrappling, func() {
+			d.settings().TechnicalGrappling = d.useTechnicalGrappling.State == check.On
+			d.syncSheet(true)
+		})
+	d.useRitualPathMagic = d.addCheckBoxWithLink(panel, i18n.Text("Use Ritual Path Magic"), "RPM4",
+		s.RitualPathMagic, func() {
+			d.settings().RitualPathMagic = d.useRitualPathMagic.State == check.On
+			d.syncSheet(true)
+		})
 	content.AddChild(panel)
 }
 
@@ -500,6 +597,35 @@ func (d *sheetSettingsDockable) createPassiveDefense(content *unison.Panel) {
 	content.AddChild(panel)
 }
 
+func (d *sheetSettingsDockable) createEquipmentLocations(content *unison.Panel) {
+	s := d.settings()
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{HAlign: align.Fill})
+	d.createHeader(panel, i18n.Text("Carried Equipment Locations"), 3)
+	d.locationEncumbranceCheckBoxes = make([]*unison.CheckBox, len(eqploc.Locations))
+	d.locationValueCheckBoxes = make([]*unison.CheckBox, len(eqploc.Locations))
+	for i, loc := range eqploc.Locations {
+		panel.AddChild(NewFieldLeadingLabel(loc.String(), false))
+		d.locationEncumbranceCheckBoxes[i] = d.addCheckBox(panel, i18n.Text("Counts toward encumbrance"),
+			s.EquipmentLocations.CountsTowardEncumbrance(loc), func() {
+				d.settings().EquipmentLocations.SetCountsTowardEncumbrance(loc,
+					d.locationEncumbranceCheckBoxes[i].State == check.On)
+				d.syncSheet(false)
+			})
+		d.locationValueCheckBoxes[i] = d.addCheckBox(panel, i18n.Text("Counts toward carried value"),
+			s.EquipmentLocations.CountsTowardValue(loc), func() {
+				d.settings().EquipmentLocations.SetCountsTowardValue(loc, d.locationValueCheckBoxes[i].State == check.On)
+				d.syncSheet(false)
+			})
+	}
+	content.AddChild(panel)
+}
+
 func (d *sheetSettingsDockable) addCheckBox(panel *unison.Panel, title string, checked bool, onClick func()) *unison.CheckBox {
 	checkbox := unison.NewCheckBox()
 	checkbox.SetTitle(title)
@@ -591,6 +717,25 @@ func (d *sheetSettingsDockable) createPageSettings(content *unison.Panel) {
 		func(value paper.Length) { d.settings().Page.LeftMargin = value })
 	d.rightMarginField = d.createPaperMarginField(panel, i18n.Text("Right Margin"), s.Page.RightMargin,
 		func(value paper.Length) { d.settings().Page.RightMargin = value })
+	d.themePopup = createSettingPopup(d, panel, i18n.Text("Theme"), sheetThemeChoices(s.Theme),
+		sheetThemeChoice(s.Theme), func(option sheetThemeChoice) { d.settings().Theme = string(option) })
+	d.themePopup.Tooltip = newWrappedTooltip(i18n.Text("The colors and fonts used to render the sheet, including when exporting. Themes are created with the Sheet Themes editor in the Settings menu."))
+	d.densityPopup = createSettingPopup(d, panel, i18n.Text("Density"), density.Options, s.Density,
+		func(option density.Option) { d.settings().Density = option })
+	d.densityPopup.Tooltip = newWrappedTooltip(i18n.Text("How tightly the content is packed onto each page. Compact and dense fit more onto each page, which can reduce the page count of larger characters."))
+	fontScaleTitle := i18n.Text("Font Scale")
+	panel.AddChild(NewFieldLeadingLabel(fontScaleTitle, false))
+	d.fontScaleField = NewPercentageField(nil, "", fontScaleTitle,
+		func() int { return d.settings().EffectiveFontScale() },
+		func(value int) {
+			d.settings().FontScale = value
+			d.syncSheet(false)
+		}, gurps.SheetFontScaleMin, gurps.SheetFontScaleMax, false, false)
+	d.fontScaleField.Tooltip = newWrappedTooltip(i18n.Text("Scales the content of each page, both on screen and when exporting. The page size and margins are not affected."))
+	panel.AddChild(d.fontScaleField)
+	d.languagePopup = createSettingPopup(d, panel, i18n.Text("Language"), sheetLanguageChoices(),
+		sheetLanguageChoice(s.Language), func(option sheetLanguageChoice) { d.settings().Language = string(option) })
+	d.languagePopup.Tooltip = newWrappedTooltip(i18n.Text("The language the sheet is written in. When exporting, the pages use a font suited to it, if one is installed."))
 	content.AddChild(panel)
 }
 
@@ -608,20 +753,37 @@ func (d *sheetSettingsDockable) createBlockLayout(content *unison.Panel) {
 	desc.Weight = weight.Bold
 	label.Font = desc.Font()
 	label.SetTitle(i18n.Text("Block Layout"))
-	panel.AddChild(label)
+	header := unison.NewPanel()
+	header.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VAlign:   align.Middle,
+	})
+	header.AddChild(label)
+	visualButton := unison.NewButton()
+	visualButton.SetTitle(i18n.Text("Visual Editor…"))
+	visualButton.Tooltip = newWrappedTooltip(i18n.Text("Arrange the blocks by dragging them around"))
+	visualButton.ClickCallback = func() { ShowBlockLayoutEditor(d.owner) }
+	header.AddChild(visualButton)
+	addCustomButton := unison.NewButton()
+	addCustomButton.SetTitle(i18n.Text("Add Custom Block…"))
+	addCustomButton.Tooltip = newWrappedTooltip(i18n.Text("Add a block of your own fields and text to the sheet"))
+	addCustomButton.ClickCallback = d.addCustomBlock
+	header.AddChild(addCustomButton)
+	panel.AddChild(header)
 	d.blockLayoutField = unison.NewMultiLineField()
-	lastBlockLayout := s.BlockLayout.String()
-	d.blockLayoutField.SetText(lastBlockLayout)
+	d.lastBlockLayout = s.BlockLayout.String()
+	d.blockLayoutField.SetText(d.lastBlockLayout)
 	d.blockLayoutField.ValidateCallback = func() bool {
-		_, valid := gurps.NewBlockLayoutFromString(d.blockLayoutField.Text())
+		_, valid := gurps.NewBlockLayoutFromString(d.blockLayoutField.Text(), d.settings().CustomBlockKeys()...)
 		return valid
 	}
 	d.blockLayoutField.ModifiedCallback = func(_, after *unison.FieldState) {
-		if blockLayout, valid := gurps.NewBlockLayoutFromString(after.Text); valid {
+		if blockLayout, valid := gurps.NewBlockLayoutFromString(after.Text, d.settings().CustomBlockKeys()...); valid {
 			localSettings := d.settings()
 			currentBlockLayout := blockLayout.String()
-			if lastBlockLayout != currentBlockLayout {
-				lastBlockLayout = currentBlockLayout
+			if d.lastBlockLayout != currentBlockLayout {
+				d.lastBlockLayout = currentBlockLayout
 				localSettings.BlockLayout = blockLayout
 				d.syncSheet(true)
 			}
@@ -632,9 +794,72 @@ func (d *sheetSettingsDockable) createBlockLayout(content *unison.Panel) {
 		HGrab:  true,
 	})
 	panel.AddChild(d.blockLayoutField)
+	d.customBlocksPanel = unison.NewPanel()
+	d.customBlocksPanel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	panel.AddChild(d.customBlocksPanel)
+	d.rebuildCustomBlocks()
 	content.AddChild(panel)
 }
 
+func (d *sheetSettingsDockable) rebuildCustomBlocks() {
+	d.customBlocksPanel.RemoveAllChildren()
+	d.customBlocksPanel.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	for _, block := range d.settings().CustomBlocks {
+		editButton := unison.NewSVGButton(svg.Edit)
+		editButton.Tooltip = newWrappedTooltip(i18n.Text("Edit this custom block"))
+		editButton.ClickCallback = func() {
+			working := block.Clone()
+			if ShowCustomBlockEditor(working) {
+				*block = *working
+				d.customBlocksChanged()
+			}
+		}
+		d.customBlocksPanel.AddChild(editButton)
+		removeButton := unison.NewSVGButton(svg.Trash)
+		removeButton.Tooltip = newWrappedTooltip(i18n.Text("Remove this custom block"))
+		removeButton.ClickCallback = func() {
+			d.settings().RemoveCustomBlock(block.Key())
+			d.customBlocksChanged()
+		}
+		d.customBlocksPanel.AddChild(removeButton)
+		label := unison.NewLabel()
+		label.SetTitle(block.TitleOrDefault())
+		label.Tooltip = newWrappedTooltip(block.Key())
+		d.customBlocksPanel.AddChild(label)
+	}
+	d.customBlocksPanel.MarkForLayoutAndRedraw()
+	MarkRootAncestorForLayoutRecursively(d.customBlocksPanel)
+}
+
+func (d *sheetSettingsDockable) addCustomBlock() {
+	block := &gurps.CustomBlock{Title: i18n.Text("Custom Block")}
+	if ShowCustomBlockEditor(block) {
+		d.settings().AddCustomBlock(block)
+		d.customBlocksChanged()
+	}
+}
+
+func (d *sheetSettingsDockable) customBlocksChanged() {
+	d.syncBlockLayoutField()
+	d.rebuildCustomBlocks()
+	d.syncSheet(true)
+}
+
+// syncBlockLayoutField updates the block layout field to match the current settings, without triggering another
+// update of the sheet.
+func (d *sheetSettingsDockable) syncBlockLayoutField() {
+	d.lastBlockLayout = d.settings().BlockLayout.String()
+	d.blockLayoutField.SetText(d.lastBlockLayout)
+}
+
 func (d *sheetSettingsDockable) createPaperSizeField(panel *unison.Panel, current string, set func(value string)) *unison.Field {
 	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Paper Size"), false))
 	wrapper := unison.NewPanel()
@@ -748,8 +973,14 @@ func (d *sheetSettingsDockable) reset() {
 func (d *sheetSettingsDockable) sync() {
 	s := d.settings()
 	d.damageProgressionPopup.Select(s.DamageProgression)
+	d.castingModePopup.Select(s.CastingMode)
+	d.magicThresholdField.Sync()
+	d.magicThresholdField.SetEnabled(s.CastingMode == casting.Threshold)
+	d.studyTrackingPopup.Select(s.StudyTracking)
 	d.hideSourceMismatch.State = check.FromBool(!s.HideSourceMismatch)
 	d.hidePageRefColumn.State = check.FromBool(!s.HidePageRefColumn)
+	d.showHitLocationColumn.State = check.FromBool(s.ShowHitLocationColumn)
+	d.showEquipmentLocationColumn.State = check.FromBool(s.ShowEquipmentLocationColumn)
 	d.hideTLColumn.State = check.FromBool(!s.HideTLColumn)
 	d.hideLCColumn.State = check.FromBool(!s.HideLCColumn)
 	d.showTraitModifier.State = check.FromBool(s.ShowTraitModifierAdj)
@@ -759,6 +990,9 @@ func (d *sheetSettingsDockable) sync() {
 	d.showTitleInsteadOfNameInPageFooter.State = check.FromBool(s.UseTitleInFooter)
 	d.showLiftingSTDamage.State = check.FromBool(s.ShowLiftingSTDamage)
 	d.showIQBasedDamage.State = check.FromBool(s.ShowIQBasedDamage)
+	d.useTechnicalGrappling.State = check.FromBool(s.TechnicalGrappling)
+	d.useRitualPathMagic.State = check.FromBool(s.RitualPathMagic)
+	d.useHeightBasedSizeModifier.State = check.FromBool(s.UseHeightBasedSizeModifier)
 	d.useMultiplicativeModifiers.State = check.FromBool(s.UseMultiplicativeModifiers)
 	d.useHalfStatDefaults.State = check.FromBool(s.UseHalfStatDefaults)
 	d.useModifyDicePlusAdds.State = check.FromBool(s.UseModifyingDicePlusAdds)
@@ -779,7 +1013,16 @@ func (d *sheetSettingsDockable) sync() {
 	d.leftMarginField.SetText(s.Page.LeftMargin.String())
 	d.bottomMarginField.SetText(s.Page.BottomMargin.String())
 	d.rightMarginField.SetText(s.Page.RightMargin.String())
+	d.themePopup.RemoveAllItems()
+	for _, one := range sheetThemeChoices(s.Theme) {
+		d.themePopup.AddItem(one)
+	}
+	d.themePopup.Select(sheetThemeChoice(s.Theme))
+	d.densityPopup.Select(s.Density)
+	d.fontScaleField.Sync()
+	d.languagePopup.Select(sheetLanguageChoice(s.Language))
 	d.blockLayoutField.SetText(s.BlockLayout.String())
+	d.rebuildCustomBlocks()
 	if d.easySkillModifierOverrideField != nil {
 		d.easySkillModifierOverrideField.Sync()
 		d.averageSkillModifierOverrideField.Sync()
@@ -804,6 +1047,10 @@ func (d *sheetSettingsDockable) sync() {
 	if d.dodgeOverrideField != nil {
 		d.dodgeOverrideField.Sync()
 	}
+	for i, loc := range eqploc.Locations {
+		d.locationEncumbranceCheckBoxes[i].State = check.FromBool(s.EquipmentLocations.CountsTowardEncumbrance(loc))
+		d.locationValueCheckBoxes[i].State = check.FromBool(s.EquipmentLocations.CountsTowardValue(loc))
+	}
 	d.MarkForRedraw()
 }
 
@@ -838,3 +1085,18 @@ func (d *sheetSettingsDockable) load(fileSystem fs.FS, filePath string) error {
 func (d *sheetSettingsDockable) save(filePath string) error {
 	return d.settings().Save(filePath)
 }
+
+type sheetLanguageChoice string
+
+func (c sheetLanguageChoice) String() string {
+	return gurps.SheetLanguageName(string(c))
+}
+
+func sheetLanguageChoices() []sheetLanguageChoice {
+	codes := gurps.SheetLanguages()
+	choices := make([]sheetLanguageChoice, len(codes))
+	for i, code := range codes {
+		choices[i] = sheetLanguageChoice(code)
+	}
+	return choices
+}
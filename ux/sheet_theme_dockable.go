@@ -0,0 +1,220 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"io/fs"
+
+	"github.com/richardwilkes/gcs/v5/model/colors"
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+// sheetThemeChoice is the name of a sheet theme, as offered in a popup menu. An empty name means no theme.
+type sheetThemeChoice string
+
+func (c sheetThemeChoice) String() string {
+	if c == "" {
+		return i18n.Text("None")
+	}
+	return string(c)
+}
+
+// sheetThemeChoices returns the available sheet themes, preceded by the choice for no theme. If current isn't one of
+// the available themes, it is included anyway.
+func sheetThemeChoices(current string) []sheetThemeChoice {
+	choices := []sheetThemeChoice{""}
+	found := current == ""
+	for _, set := range gurps.AvailableSheetThemes(gurps.GlobalSettings().Libraries()) {
+		for _, one := range set.List {
+			choices = append(choices, sheetThemeChoice(one.Name))
+			if one.Name == current {
+				found = true
+			}
+		}
+	}
+	if !found {
+		choices = append(choices, sheetThemeChoice(current))
+	}
+	return choices
+}
+
+type sheetThemeDockable struct {
+	SettingsDockable
+	theme   *gurps.SheetTheme
+	content *unison.Panel
+}
+
+// ShowSheetThemeEditor shows the sheet theme editor, which creates and modifies the theme files that sheets can choose
+// from in their sheet settings.
+func ShowSheetThemeEditor() {
+	if Activate(func(d unison.Dockable) bool {
+		_, ok := d.AsPanel().Self.(*sheetThemeDockable)
+		return ok
+	}) {
+		return
+	}
+	d := &sheetThemeDockable{theme: gurps.NewSheetTheme()}
+	d.Self = d
+	d.TabTitle = i18n.Text("Sheet Theme")
+	d.TabIcon = svg.Settings
+	d.Extensions = []string{gurps.SheetThemeExt}
+	d.FileSets = func() []*gurps.NamedFileSet {
+		return gurps.AvailableSheetThemes(gurps.GlobalSettings().Libraries())
+	}
+	d.Loader = d.load
+	d.Saver = d.save
+	d.Resetter = d.reset
+	d.Setup(nil, nil, d.initContent)
+}
+
+func (d *sheetThemeDockable) initContent(content *unison.Panel) {
+	d.content = content
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.fill()
+}
+
+func (d *sheetThemeDockable) fill() {
+	d.createHeader(i18n.Text("Colors"), 0)
+	for _, one := range gurps.SheetThemeColors() {
+		d.content.AddChild(NewFieldLeadingLabel(one.Title, false))
+		d.createColorWellField(one, true)
+		d.createColorWellField(one, false)
+		createRevertButtonFor(d, one.ID, d.theme.Colors)
+	}
+	d.createHeader(i18n.Text("Fonts"), unison.StdVSpacing*4)
+	for _, one := range gurps.SheetThemeFonts() {
+		d.content.AddChild(NewFieldLeadingLabel(one.Title, false))
+		d.createFontPanel(one)
+		createRevertButtonFor(d, one.ID, d.theme.Fonts)
+	}
+	notice := unison.NewLabel()
+	notice.Font = unison.SystemFont
+	notice.SetTitle(i18n.Text("Anything the theme doesn't set uses the app's setting. Theme fonts apply to exports and printing."))
+	notice.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+	notice.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  4,
+		HAlign: align.Middle,
+		VAlign: align.Middle,
+	})
+	d.content.AddChild(notice)
+}
+
+func (d *sheetThemeDockable) sync() {
+	d.content.RemoveAllChildren()
+	d.fill()
+	MarkRootAncestorForLayoutRecursively(d.content)
+	d.MarkForRedraw()
+}
+
+func (d *sheetThemeDockable) createHeader(title string, topMargin float32) {
+	label := unison.NewLabel()
+	if topMargin > 0 {
+		label.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: topMargin}))
+	}
+	desc := label.Font.Descriptor()
+	desc.Weight = weight.Bold
+	label.Font = desc.Font()
+	label.Underline = true
+	label.SetTitle(title)
+	label.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  4,
+		HAlign: align.Middle,
+		VAlign: align.Middle,
+	})
+	d.content.AddChild(label)
+}
+
+func (d *sheetThemeDockable) createColorWellField(c *colors.ThemedColor, light bool) {
+	w := unison.NewWell()
+	w.Mask = unison.ColorWellMask
+	clr := d.theme.Color(c)
+	if light {
+		w.SetInk(clr.Light)
+		w.Tooltip = newWrappedTooltip(i18n.Text("Light Mode Color"))
+	} else {
+		w.SetInk(clr.Dark)
+		w.Tooltip = newWrappedTooltip(i18n.Text("Dark Mode Color"))
+	}
+	w.InkChangedCallback = func() {
+		if ink, ok := w.Ink().(unison.Color); ok {
+			current := d.theme.Color(c)
+			if light {
+				current.Light = ink
+			} else {
+				current.Dark = ink
+			}
+			d.theme.Colors[c.ID] = current
+		}
+	}
+	d.content.AddChild(w)
+}
+
+func (d *sheetThemeDockable) createFontPanel(f *fonts.ThemedFont) {
+	fp := unison.NewFontPanel()
+	fp.SetFontDescriptor(d.theme.Font(f))
+	fp.FontModifiedCallback = func(fd unison.FontDescriptor) { d.theme.Fonts[f.ID] = fd }
+	fp.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	d.content.AddChild(fp)
+}
+
+func createRevertButtonFor[T any](d *sheetThemeDockable, id string, overrides map[string]T) {
+	b := unison.NewSVGButton(svg.Reset)
+	b.Tooltip = newWrappedTooltip(i18n.Text("Use the application setting instead"))
+	b.ClickCallback = func() {
+		if _, ok := overrides[id]; ok {
+			delete(overrides, id)
+			d.sync()
+		}
+	}
+	b.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Start,
+		VAlign: align.Middle,
+	})
+	d.content.AddChild(b)
+}
+
+func (d *sheetThemeDockable) reset() {
+	d.theme = gurps.NewSheetTheme()
+	d.sync()
+}
+
+func (d *sheetThemeDockable) load(fileSystem fs.FS, filePath string) error {
+	theme, err := gurps.NewSheetThemeFromFile(fileSystem, filePath)
+	if err != nil {
+		return err
+	}
+	d.theme = theme
+	d.sync()
+	return nil
+}
+
+func (d *sheetThemeDockable) save(filePath string) error {
+	if err := d.theme.Clone().Save(filePath); err != nil {
+		return err
+	}
+	gurps.DiscardSheetThemeCache()
+	for _, one := range AllDockables() {
+		if s, ok := one.(*Sheet); ok {
+			s.MarkForRedraw()
+		}
+	}
+	return nil
+}
@@ -159,13 +159,18 @@ func initSkillEditor(e *editor[*gurps.Skill, *gurps.SkillEditData], content *uni
 				var level gurps.Level
 				if e.target.IsTechnique() {
 					level = gurps.CalculateTechniqueLevel(entity, e.target.NameableReplacements(), localName, localSpec,
-						e.editorData.Tags, e.editorData.TechniqueDefault, e.editorData.Difficulty.Difficulty, points,
-						true, e.editorData.TechniqueLimitModifier, nil)
+						e.editorData.Tags, e.editorData.TechniqueDefault, e.editorData.TechniqueAlternateDefaults,
+						e.editorData.Difficulty.Difficulty, points, true, e.editorData.TechniqueLimitModifier, nil)
 				} else {
 					level = gurps.CalculateSkillLevel(entity, localName, localSpec, e.editorData.Tags,
 						e.editorData.DefaultedFrom, e.editorData.Difficulty, points,
 						e.editorData.EncumbrancePenaltyMultiplier)
 				}
+				if tooltip := level.FullTooltip(); tooltip != "" {
+					field.Tooltip = newWrappedTooltip(tooltip)
+				} else {
+					field.Tooltip = nil
+				}
 				lvl := level.Level.Floor()
 				if lvl <= 0 {
 					field.SetTitle("-")
@@ -189,11 +194,15 @@ func initSkillEditor(e *editor[*gurps.Skill, *gurps.SkillEditData], content *uni
 	}
 	addPageRefLabelAndField(content, &e.editorData.PageRef)
 	addPageRefHighlightLabelAndField(content, &e.editorData.PageRefHighlight)
+	addProvenanceLabelsAndFields(content, &e.editorData.Provenance)
 	addSourceFields(content, &e.target.SourcedID)
 	if !e.target.Container() {
 		content.AddChild(newPrereqPanel(entity, &e.editorData.Prereq, prereq.TypesForNonEquipment))
-		if !e.target.IsTechnique() {
-			content.AddChild(newDefaultsPanel(entity, &e.editorData.Defaults))
+		if e.target.IsTechnique() {
+			content.AddChild(newDefaultsPanel(entity, i18n.Text("Alternate Defaults"),
+				&e.editorData.TechniqueAlternateDefaults))
+		} else {
+			content.AddChild(newDefaultsPanel(entity, i18n.Text("Defaults"), &e.editorData.Defaults))
 		}
 		content.AddChild(newFeaturesPanel(entity, e.target, &e.editorData.Features, false))
 		e.meleeWeapons = newWeaponsPanel(e, e.target, true, &e.editorData.Weapons)
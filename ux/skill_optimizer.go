@@ -0,0 +1,174 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+)
+
+func canOptimizeSkillLevels(table *unison.Table[*Node[*gurps.Skill]]) bool {
+	skills := selectedSkillsForOptimizer(table)
+	return len(skills) != 0 && gurps.EntityFromNode(skills[0]) != nil
+}
+
+func selectedSkillsForOptimizer(table *unison.Table[*Node[*gurps.Skill]]) []*gurps.Skill {
+	var skills []*gurps.Skill
+	for _, row := range table.SelectedRows(false) {
+		if s := row.Data(); s != nil && !s.Container() {
+			skills = append(skills, s)
+		}
+	}
+	return skills
+}
+
+// ShowSkillOptimizer asks for target levels for the skills selected in the table, then suggests the cheapest ways to
+// reach them and applies the chosen one.
+func ShowSkillOptimizer(owner Rebuildable, table *unison.Table[*Node[*gurps.Skill]]) {
+	skills := selectedSkillsForOptimizer(table)
+	if len(skills) == 0 {
+		return
+	}
+	entity := gurps.EntityFromNode(skills[0])
+	if entity == nil {
+		return
+	}
+	targets := make([]*gurps.SkillTarget, len(skills))
+	for i, s := range skills {
+		targets[i] = &gurps.SkillTarget{
+			Skill: s,
+			Level: s.LevelData.Level.Floor() + fxp.One,
+		}
+	}
+	budget := entity.UnspentPoints()
+
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	for _, header := range []string{i18n.Text("Skill"), i18n.Text("Current"), i18n.Text("Target")} {
+		label := unison.NewLabel()
+		label.Font = fonts.PageLabelSecondary
+		label.SetTitle(header)
+		content.AddChild(label)
+	}
+	plansPanel := unison.NewPanel()
+	plansPanel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	plansPanel.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  3,
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	var selected *gurps.SkillPlan
+	suggest := func() {
+		plansPanel.RemoveAllChildren()
+		selected = nil
+		plans := gurps.SuggestSkillPlans(entity, targets, budget)
+		if len(plans) == 0 {
+			label := unison.NewLabel()
+			label.SetTitle(i18n.Text("No way to reach the target levels was found"))
+			plansPanel.AddChild(label)
+		}
+		group := unison.NewGroup()
+		for i, plan := range plans {
+			rb := unison.NewRadioButton()
+			text := fmt.Sprintf(i18n.Text("%s pts: %s"), plan.Cost.String(), plan.String())
+			if !plan.Affordable {
+				text += " " + i18n.Text("(over budget)")
+			}
+			rb.SetTitle(text)
+			rb.ClickCallback = func() { selected = plan }
+			group.Add(rb)
+			if i == 0 {
+				group.Select(rb)
+				selected = plan
+			}
+			plansPanel.AddChild(rb)
+		}
+		plansPanel.MarkForLayoutRecursivelyUpward()
+		if wnd := plansPanel.Window(); wnd != nil {
+			wnd.Pack()
+		}
+	}
+	for _, target := range targets {
+		name := unison.NewLabel()
+		name.SetTitle(target.Skill.String())
+		content.AddChild(name)
+		current := unison.NewLabel()
+		current.SetTitle(target.Skill.LevelData.LevelAsString(false))
+		current.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+		content.AddChild(current)
+		content.AddChild(NewIntegerField(nil, "", i18n.Text("Target"),
+			func() int { return fxp.AsInteger[int](target.Level) },
+			func(v int) {
+				target.Level = fxp.FromInteger(v)
+				suggest()
+			}, -99, 99, false, false))
+	}
+	budgetLabel := unison.NewLabel()
+	budgetLabel.SetTitle(fmt.Sprintf(i18n.Text("Unspent points: %s"), budget.String()))
+	budgetLabel.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+	content.AddChild(budgetLabel)
+	content.AddChild(plansPanel)
+	suggest()
+
+	icon := &unison.DrawableSVG{
+		SVG:  svg.GCSSkills,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	applyButton := unison.NewOKButtonInfo()
+	applyButton.Title = i18n.Text("Apply")
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), applyButton}, unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK || selected == nil || len(selected.Steps) == 0 {
+		return
+	}
+	applySkillPlan(owner, table, selected)
+}
+
+func applySkillPlan(owner Rebuildable, table *unison.Table[*Node[*gurps.Skill]], plan *gurps.SkillPlan) {
+	before := plan.Inverse()
+	plan.Apply()
+	if mgr := unison.UndoManagerFor(table); mgr != nil {
+		addUndo(mgr, &unison.UndoEdit[*gurps.SkillPlan]{
+			ID:       unison.NextUndoID(),
+			EditName: optimizeSkillLevelsAction.Title,
+			UndoFunc: func(e *unison.UndoEdit[*gurps.SkillPlan]) {
+				e.BeforeData.Apply()
+				owner.Rebuild(true)
+			},
+			RedoFunc: func(e *unison.UndoEdit[*gurps.SkillPlan]) {
+				e.AfterData.Apply()
+				owner.Rebuild(true)
+			},
+			BeforeData: before,
+			AfterData:  plan,
+		})
+	}
+	owner.Rebuild(true)
+}
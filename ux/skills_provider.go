@@ -139,6 +139,13 @@ func (p *skillsProvider) SyncHeader(_ []unison.TableColumnHeader[*Node[*gurps.Sk
 }
 
 func (p *skillsProvider) ColumnIDs() []int {
+	if p.forPage {
+		return customizedColumnIDs(p)
+	}
+	return p.defaultColumnIDs()
+}
+
+func (p *skillsProvider) defaultColumnIDs() []int {
 	columnIDs := make([]int, 0, 5)
 	columnIDs = append(columnIDs, gurps.SkillDescriptionColumn)
 	if p.forPage {
@@ -176,6 +183,24 @@ func (p *skillsProvider) ColumnIDs() []int {
 	return columnIDs
 }
 
+func (p *skillsProvider) availableColumnIDs() []int {
+	columnIDs := make([]int, 0, 8)
+	columnIDs = append(columnIDs, gurps.SkillDescriptionColumn, gurps.SkillDifficultyColumn)
+	if _, ok := p.provider.(*gurps.Entity); ok {
+		columnIDs = append(columnIDs, gurps.SkillLevelColumn, gurps.SkillRelativeLevelColumn)
+	}
+	return append(columnIDs,
+		gurps.SkillPointsColumn,
+		gurps.SkillTagsColumn,
+		gurps.SkillReferenceColumn,
+		gurps.SkillLibSrcColumn,
+	)
+}
+
+func (p *skillsProvider) columnHeaderData(columnID int) gurps.HeaderData {
+	return gurps.SkillsHeaderData(columnID)
+}
+
 func (p *skillsProvider) HierarchyColumnID() int {
 	return gurps.SkillDescriptionColumn
 }
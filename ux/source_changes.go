@@ -0,0 +1,166 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xstrings"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+const sourceChangeValueMaxLength = 60
+
+// CanReviewSourceChangesForSelection returns true if a single node that can report its changes from its source is
+// selected.
+func CanReviewSourceChangesForSelection[T gurps.NodeTypes](table *unison.Table[*Node[T]]) bool {
+	if !HasSelectionAndNotFiltered(table) {
+		return false
+	}
+	sel := table.SelectedRows(false)
+	if len(sel) != 1 {
+		return false
+	}
+	_, ok := any(sel[0].Data()).(gurps.SourceChangesProvider)
+	return ok && !gurps.AsNode(sel[0].Data()).GetSource().IsZero()
+}
+
+// ReviewSourceChangesForSelection shows the differences between the selected node and its source, allowing each one
+// to be accepted or rejected individually.
+func ReviewSourceChangesForSelection[T gurps.NodeTypes](table *unison.Table[*Node[T]]) {
+	if !CanReviewSourceChangesForSelection(table) {
+		return
+	}
+	target := table.SelectedRows(false)[0].Data()
+	provider, ok := any(target).(gurps.SourceChangesProvider)
+	if !ok {
+		return
+	}
+	changes := provider.SourceChanges()
+	if len(changes) == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("No changes to review"),
+			fmt.Sprintf(i18n.Text("%s matches its source, or its source is not available."), target.String()))
+		return
+	}
+	boxes, ok := showSourceChangesDialog(target.String(), changes)
+	if !ok {
+		return
+	}
+	var undo *unison.UndoEdit[*TableUndoEditData[T]]
+	mgr := unison.UndoManagerFor(table)
+	if mgr != nil {
+		undo = &unison.UndoEdit[*TableUndoEditData[T]]{
+			ID:         unison.NextUndoID(),
+			EditName:   syncWithSourceAction.Title,
+			UndoFunc:   func(e *unison.UndoEdit[*TableUndoEditData[T]]) { e.BeforeData.Apply() },
+			RedoFunc:   func(e *unison.UndoEdit[*TableUndoEditData[T]]) { e.AfterData.Apply() },
+			AbsorbFunc: func(_ *unison.UndoEdit[*TableUndoEditData[T]], _ unison.Undoable) bool { return false },
+			BeforeData: NewTableUndoEditData(table),
+		}
+	}
+	applied := false
+	for i, change := range changes {
+		if boxes[i].State == check.On {
+			change.Apply()
+			applied = true
+		}
+	}
+	if !applied {
+		return
+	}
+	table.SyncToModel()
+	if mgr != nil && undo != nil {
+		undo.AfterData = NewTableUndoEditData(table)
+		addUndo(mgr, undo)
+	}
+	if builder := unison.AncestorOrSelf[Rebuildable](table); builder != nil {
+		builder.Rebuild(true)
+	}
+}
+
+func showSourceChangesDialog(name string, changes []*gurps.SourceChange) ([]*unison.CheckBox, bool) {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.SetTitle(fmt.Sprintf(i18n.Text("Select the changes from the source to apply to %s:"), name))
+	panel.AddChild(label)
+
+	list := unison.NewPanel()
+	list.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing)))
+	list.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	for _, title := range []string{i18n.Text("Field"), i18n.Text("Current"), i18n.Text("Source")} {
+		header := unison.NewLabel()
+		header.SetTitle(title)
+		header.OnBackgroundInk = unison.ThemeOnSurface
+		list.AddChild(header)
+	}
+	boxes := make([]*unison.CheckBox, len(changes))
+	for i, change := range changes {
+		box := unison.NewCheckBox()
+		box.SetTitle(change.Field)
+		box.State = check.On
+		boxes[i] = box
+		list.AddChild(box)
+		list.AddChild(newSourceChangeValueLabel(change.Current))
+		list.AddChild(newSourceChangeValueLabel(change.Source))
+	}
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{}, geom.NewUniformInsets(1), false))
+	scroll.SetContent(list, behavior.Fill, behavior.Fill)
+	scroll.BackgroundInk = unison.ThemeSurface
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	panel.AddChild(scroll)
+
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel,
+		[]*unison.DialogButtonInfo{
+			unison.NewCancelButtonInfo(),
+			unison.NewOKButtonInfoWithTitle(i18n.Text("Apply")),
+		})
+	if err != nil {
+		errs.Log(err)
+		return nil, false
+	}
+	return boxes, dialog.RunModal() == unison.ModalResponseOK
+}
+
+func newSourceChangeValueLabel(value string) *unison.Label {
+	label := unison.NewLabel()
+	if value == "" {
+		label.SetTitle(i18n.Text("(none)"))
+	} else {
+		title := xstrings.Truncate(value, sourceChangeValueMaxLength, true)
+		label.SetTitle(title)
+		if title != value {
+			label.Tooltip = newWrappedTooltip(value)
+		}
+	}
+	return label
+}
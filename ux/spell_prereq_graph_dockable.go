@@ -0,0 +1,360 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/gurps/importer"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+const (
+	spellGraphMargin         = 16
+	spellGraphClusterPadding = 8
+	spellGraphClusterGap     = 16
+	spellGraphNodePadding    = 4
+	spellGraphColumnGap      = 48
+	spellGraphRowGap         = 8
+)
+
+var (
+	_ unison.Dockable  = &spellPrereqGraphDockable{}
+	_ unison.TabCloser = &spellPrereqGraphDockable{}
+	_ GroupedCloser    = &spellPrereqGraphDockable{}
+)
+
+type spellPrereqGraphDockable struct {
+	unison.Panel
+	owner        unison.Dockable
+	sheet        *Sheet
+	spells       func() []*gurps.Spell
+	graph        *gurps.SpellPrereqGraph
+	canvas       *unison.Panel
+	addButton    *unison.Button
+	selected     string
+	rects        map[*gurps.SpellPrereqNode]geom.Rect
+	clusterRects []geom.Rect
+	size         geom.Size
+}
+
+// ShowSpellPrereqGraphForSheet shows the prerequisite graph for the spells in the sheet.
+func ShowSpellPrereqGraphForSheet(sheet *Sheet) {
+	showSpellPrereqGraph(sheet, sheet, func() []*gurps.Spell { return sheet.entity.Spells })
+}
+
+// ShowSpellPrereqGraphForLibrary shows the prerequisite graph for the spells in the library file.
+func ShowSpellPrereqGraphForLibrary(d *TableDockable[*gurps.Spell]) {
+	showSpellPrereqGraph(d, nil, d.rootData)
+}
+
+// UpdateSpellPrereqGraph refreshes the prerequisite graph for the owner, if one is being shown.
+func UpdateSpellPrereqGraph(owner unison.Dockable) {
+	for _, other := range AllDockables() {
+		if g, ok := other.(*spellPrereqGraphDockable); ok && g.owner == owner {
+			g.reload()
+			break
+		}
+	}
+}
+
+func showSpellPrereqGraph(owner unison.Dockable, sheet *Sheet, spells func() []*gurps.Spell) {
+	if Activate(func(d unison.Dockable) bool {
+		if g, ok := d.AsPanel().Self.(*spellPrereqGraphDockable); ok {
+			return g.owner == owner
+		}
+		return false
+	}) {
+		return
+	}
+	d := &spellPrereqGraphDockable{
+		owner:  owner,
+		sheet:  sheet,
+		spells: spells,
+	}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.canvas = unison.NewPanel()
+	d.canvas.SetSizer(func(_ geom.Size) (minSize, prefSize, maxSize geom.Size) {
+		return d.size, d.size, unison.MaxSize(d.size)
+	})
+	d.canvas.DrawCallback = d.draw
+	d.canvas.MouseDownCallback = d.mouseDown
+	d.canvas.UpdateTooltipCallback = d.updateTooltip
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.canvas, behavior.Unmodified, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	d.reload()
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *spellPrereqGraphDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+
+	d.addButton = unison.NewButton()
+	d.addButton.SetTitle(i18n.Text("Add Missing Prerequisites"))
+	if d.sheet != nil {
+		d.addButton.Tooltip = newWrappedTooltip(i18n.Text(`Add the spells from the libraries that the selected spell
+needs, along with the ones they need in turn`))
+	} else {
+		d.addButton.Tooltip = newWrappedTooltip(i18n.Text("Missing prerequisites can only be added to character sheets"))
+	}
+	d.addButton.ClickCallback = d.addMissing
+	toolbar.AddChild(d.addButton)
+
+	legend := unison.NewLabel()
+	legend.SetTitle(i18n.Text("Spells with unmet prerequisites are outlined in red; missing prerequisites are shown in yellow"))
+	legend.SetLayoutData(&unison.FlexLayoutData{VAlign: align.Middle})
+	toolbar.AddChild(legend)
+
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+	return toolbar
+}
+
+func (d *spellPrereqGraphDockable) reload() {
+	d.graph = gurps.NewSpellPrereqGraph(d.spells())
+	d.layoutGraph()
+	d.adjustButtons()
+	d.canvas.MarkForLayoutRecursivelyUpward()
+	d.canvas.MarkForRedraw()
+}
+
+func (d *spellPrereqGraphDockable) layoutGraph() {
+	d.rects = make(map[*gurps.SpellPrereqNode]geom.Rect)
+	d.clusterRects = d.clusterRects[:0]
+	var nodeWidth float32
+	for _, cluster := range d.graph.Clusters {
+		for _, node := range cluster.Nodes {
+			nodeWidth = max(nodeWidth, unison.LabelFont.SimpleWidth(node.Name))
+		}
+	}
+	nodeWidth += spellGraphNodePadding * 2
+	nodeHeight := unison.LabelFont.LineHeight() + spellGraphNodePadding*2
+	titleHeight := unison.SystemFont.LineHeight() + spellGraphNodePadding
+	width := float32(d.graph.MaxDepth+1)*(nodeWidth+spellGraphColumnGap) - spellGraphColumnGap +
+		spellGraphClusterPadding*2
+	y := float32(spellGraphMargin)
+	for _, cluster := range d.graph.Clusters {
+		rows := make([]int, d.graph.MaxDepth+1)
+		maxRows := 0
+		for _, node := range cluster.Nodes {
+			x := spellGraphMargin + spellGraphClusterPadding + float32(node.Depth)*(nodeWidth+spellGraphColumnGap)
+			top := y + spellGraphClusterPadding + titleHeight + float32(rows[node.Depth])*(nodeHeight+spellGraphRowGap)
+			d.rects[node] = geom.NewRect(x, top, nodeWidth, nodeHeight)
+			rows[node.Depth]++
+			maxRows = max(maxRows, rows[node.Depth])
+		}
+		height := spellGraphClusterPadding*2 + titleHeight + float32(maxRows)*(nodeHeight+spellGraphRowGap) -
+			spellGraphRowGap
+		d.clusterRects = append(d.clusterRects, geom.NewRect(spellGraphMargin, y, width, height))
+		y += height + spellGraphClusterGap
+	}
+	d.size = geom.NewSize(width+spellGraphMargin*2, y-spellGraphClusterGap+spellGraphMargin)
+}
+
+func (d *spellPrereqGraphDockable) draw(gc *unison.Canvas, rect geom.Rect) {
+	gc.DrawRect(rect, unison.ThemeSurface.Paint(gc, rect, paintstyle.Fill))
+	radius := geom.NewSize(6, 6)
+	for i, cluster := range d.graph.Clusters {
+		r := d.clusterRects[i]
+		gc.DrawRoundedRect(r, radius, unison.ThemeBelowSurface.Paint(gc, r, paintstyle.Fill))
+		gc.DrawRoundedRect(r, radius, unison.ThemeSurfaceEdge.Paint(gc, r, paintstyle.Stroke))
+		gc.DrawSimpleString(cluster.Title(), geom.NewPoint(r.X+spellGraphClusterPadding,
+			r.Y+spellGraphClusterPadding+unison.SystemFont.Baseline()), unison.SystemFont,
+			unison.ThemeOnBelowSurface.Paint(gc, r, paintstyle.Fill))
+	}
+	edgePaint := unison.ThemeOnSurface.Paint(gc, rect, paintstyle.Stroke)
+	edgePaint.SetStrokeWidth(1)
+	for node, r := range d.rects {
+		for _, prereq := range node.Prereqs {
+			from := d.rects[prereq]
+			gc.DrawLine(geom.NewPoint(from.Right(), from.CenterY()), geom.NewPoint(r.X, r.CenterY()), edgePaint)
+		}
+	}
+	for node, r := range d.rects {
+		var fill, text unison.Ink
+		switch {
+		case strings.EqualFold(node.Name, d.selected):
+			fill = unison.ThemeFocus
+			text = unison.ThemeOnFocus
+		case node.Missing():
+			fill = unison.ThemeWarning
+			text = unison.ThemeOnWarning
+		default:
+			fill = unison.ThemeAboveSurface
+			text = unison.ThemeOnAboveSurface
+		}
+		gc.DrawRoundedRect(r, radius, fill.Paint(gc, r, paintstyle.Fill))
+		border := unison.ThemeSurfaceEdge.Paint(gc, r, paintstyle.Stroke)
+		if node.Unmet {
+			border = unison.ThemeError.Paint(gc, r, paintstyle.Stroke)
+			border.SetStrokeWidth(2)
+		}
+		gc.DrawRoundedRect(r, radius, border)
+		gc.DrawSimpleString(node.Name, geom.NewPoint(r.X+spellGraphNodePadding,
+			r.Y+spellGraphNodePadding+unison.LabelFont.Baseline()), unison.LabelFont, text.Paint(gc, r, paintstyle.Fill))
+	}
+}
+
+func (d *spellPrereqGraphDockable) nodeAt(where geom.Point) *gurps.SpellPrereqNode {
+	for node, r := range d.rects {
+		if where.In(r) {
+			return node
+		}
+	}
+	return nil
+}
+
+func (d *spellPrereqGraphDockable) mouseDown(where geom.Point, _, _ int, _ unison.Modifiers) bool {
+	d.selected = ""
+	if node := d.nodeAt(where); node != nil {
+		d.selected = node.Name
+	}
+	d.adjustButtons()
+	d.canvas.MarkForRedraw()
+	return true
+}
+
+func (d *spellPrereqGraphDockable) updateTooltip(where geom.Point, _ geom.Rect) geom.Rect {
+	node := d.nodeAt(where)
+	if node == nil {
+		d.canvas.Tooltip = nil
+		return geom.Rect{}
+	}
+	var tip string
+	switch {
+	case node.Missing():
+		tip = i18n.Text("Not present")
+	case node.Spell.UnsatisfiedReason != "":
+		tip = node.Spell.UnsatisfiedReason
+	case node.Unmet:
+		tip = i18n.Text("Missing one or more prerequisites")
+	default:
+		tip = i18n.Text("Prerequisites met")
+	}
+	d.canvas.Tooltip = newWrappedTooltipWithSecondaryText(node.Name, tip)
+	return d.canvas.RectToRoot(d.rects[node])
+}
+
+func (d *spellPrereqGraphDockable) selectedNode() *gurps.SpellPrereqNode {
+	if d.selected == "" {
+		return nil
+	}
+	for node := range d.rects {
+		if strings.EqualFold(node.Name, d.selected) {
+			return node
+		}
+	}
+	return nil
+}
+
+func (d *spellPrereqGraphDockable) adjustButtons() {
+	node := d.selectedNode()
+	d.addButton.SetEnabled(d.sheet != nil && node != nil && (node.Missing() || node.Unmet))
+}
+
+func (d *spellPrereqGraphDockable) addMissing() {
+	node := d.selectedNode()
+	if d.sheet == nil || node == nil {
+		return
+	}
+	entity := d.sheet.entity
+	catalog := importer.NewCatalogFromLibraries(gurps.GlobalSettings().Libraries())
+	lookup := func(name string) *gurps.Spell { return catalog.Spell(entity, name) }
+	target := node.Spell
+	if node.Missing() {
+		if target = lookup(node.Name); target == nil {
+			unison.WarningDialogWithMessage(i18n.Text("Unable to add missing prerequisites"),
+				fmt.Sprintf(i18n.Text("%s could not be found in the libraries."), node.Name))
+			return
+		}
+	}
+	found, notFound := gurps.MissingSpellPrereqs(entity.Spells, target, lookup)
+	if node.Missing() {
+		found = append(found, target)
+	}
+	if len(found) != 0 {
+		InsertItems(d.sheet, d.sheet.Spells.Table, entity.SpellList, entity.SetSpellList,
+			func(_ *unison.Table[*Node[*gurps.Spell]]) []*Node[*gurps.Spell] {
+				return d.sheet.Spells.provider.RootRows()
+			}, found...)
+	}
+	if len(notFound) != 0 {
+		unison.WarningDialogWithMessage(i18n.Text("Some prerequisites could not be added"),
+			i18n.Text("The following spells could not be found in the libraries:")+"\n\n"+strings.Join(notFound, "\n"))
+	}
+	d.reload()
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *spellPrereqGraphDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.GCSSpells,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *spellPrereqGraphDockable) Title() string {
+	return fmt.Sprintf(i18n.Text("Spell Prerequisites for %s"), d.owner.Title())
+}
+
+// Tooltip implements unison.Dockable.
+func (d *spellPrereqGraphDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *spellPrereqGraphDockable) Modified() bool {
+	return false
+}
+
+// CloseWithGroup implements GroupedCloser.
+func (d *spellPrereqGraphDockable) CloseWithGroup(other unison.Paneler) bool {
+	return d.owner != nil && d.owner == other
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *spellPrereqGraphDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *spellPrereqGraphDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
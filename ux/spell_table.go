@@ -47,7 +47,10 @@ func NewSpellTableDockableFromFile(filePath string) (unison.Dockable, error) {
 // NewSpellTableDockable creates a new unison.Dockable for spell list files.
 func NewSpellTableDockable(filePath string, spells []*gurps.Spell) *TableDockable[*gurps.Spell] {
 	provider := &spellListProvider{spells: spells}
-	return NewTableDockable(filePath, gurps.SpellsExt, NewSpellsProvider(provider, false),
+	d := NewTableDockable(filePath, gurps.SpellsExt, NewSpellsProvider(provider, false),
 		func(path string) error { return gurps.SaveSpells(provider.SpellList(), path) },
 		NewSpellItemID, NewSpellContainerItemID, NewRitualMagicSpellItemID)
+	d.InstallCmdHandlers(SpellPrereqGraphItemID, unison.AlwaysEnabled,
+		func(_ any) { ShowSpellPrereqGraphForLibrary(d) })
+	return d
 }
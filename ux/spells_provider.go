@@ -138,6 +138,13 @@ func (p *spellsProvider) SyncHeader(_ []unison.TableColumnHeader[*Node[*gurps.Sp
 }
 
 func (p *spellsProvider) ColumnIDs() []int {
+	if p.forPage {
+		return customizedColumnIDs(p)
+	}
+	return p.defaultColumnIDs()
+}
+
+func (p *spellsProvider) defaultColumnIDs() []int {
 	columnIDs := make([]int, 0, 11)
 	if p.forPage {
 		if _, ok := p.provider.(*gurps.Entity); ok {
@@ -190,6 +197,35 @@ func (p *spellsProvider) ColumnIDs() []int {
 	return columnIDs
 }
 
+func (p *spellsProvider) availableColumnIDs() []int {
+	columnIDs := make([]int, 0, 16)
+	columnIDs = append(columnIDs,
+		gurps.SpellDescriptionForPageColumn,
+		gurps.SpellCollegeColumn,
+		gurps.SpellResistColumn,
+		gurps.SpellClassColumn,
+		gurps.SpellCastCostColumn,
+		gurps.SpellMaintainCostColumn,
+		gurps.SpellCastTimeColumn,
+		gurps.SpellDurationColumn,
+		gurps.SpellDifficultyColumn,
+		gurps.SpellPrereqCountColumn,
+	)
+	if _, ok := p.provider.(*gurps.Entity); ok {
+		columnIDs = append(columnIDs, gurps.SpellLevelColumn, gurps.SpellRelativeLevelColumn)
+	}
+	return append(columnIDs,
+		gurps.SpellPointsColumn,
+		gurps.SpellTagsColumn,
+		gurps.SpellReferenceColumn,
+		gurps.SpellLibSrcColumn,
+	)
+}
+
+func (p *spellsProvider) columnHeaderData(columnID int) gurps.HeaderData {
+	return gurps.SpellsHeaderData(columnID)
+}
+
 func (p *spellsProvider) HierarchyColumnID() int {
 	if p.forPage {
 		return gurps.SpellDescriptionForPageColumn
@@ -47,6 +47,11 @@ func Start(files []string) {
 			xos.ExitIfErr(err)
 			SetupMenuBar(wnd)
 			InitWorkspace(wnd)
+			installAPIServerRollFeed()
+			installDiscordAnnouncer()
+			installSessionRollForwarder()
+			UpdateAPIServer()
+			updateLibrarySearchIndex(false)
 			OpenFiles(files)
 			go func() {
 				for paths := range pathsChan {
@@ -91,7 +91,7 @@ func (f *StringField) modified(before, after *unison.FieldState) {
 					self.setWithoutUndo(data, true)
 				}, before)
 			undo.AfterData = after
-			mgr.Add(undo)
+			addUndo(mgr, undo)
 		}
 	}
 	f.adjustForText()
@@ -32,6 +32,7 @@ type studyPanel struct {
 	studyNeeded *study.Level
 	study       *[]*gurps.Study
 	total       *unison.Label
+	progress    *unison.ProgressBar
 }
 
 func newStudyPanel(entity *gurps.Entity, studyNeeded *study.Level, s *[]*gurps.Study) *studyPanel {
@@ -101,6 +102,16 @@ func newStudyPanel(entity *gurps.Entity, studyNeeded *study.Level, s *[]*gurps.S
 	trailer.SetTitle(i18n.Text(") for 1 point"))
 	topRight.AddChild(trailer)
 
+	if gurps.SheetSettingsFor(entity).StudyTracking != study.Off {
+		p.progress = newStudyProgressBar(gurps.NewStudyProgress(*s, *studyNeeded, 0))
+		p.progress.SetLayoutData(&unison.FlexLayoutData{
+			HSpan:  2,
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		top.AddChild(p.progress)
+	}
+
 	for i, one := range *s {
 		p.insertStudyEntry(i+1, one, false)
 	}
@@ -189,4 +200,7 @@ func (p *studyPanel) updateTotal() {
 		p.total.SetTitle(text)
 		p.total.MarkForLayoutAndRedraw()
 	}
+	if p.progress != nil {
+		updateStudyProgressBar(p.progress, gurps.NewStudyProgress(*p.study, *p.studyNeeded, 0))
+	}
 }
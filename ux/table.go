@@ -83,6 +83,8 @@ func NewNodeTable[T gurps.NodeTypes](provider TableProvider[T], font unison.Font
 		table.HierarchyIndent = font.LineHeight()
 		table.MinimumRowHeight = font.LineHeight()
 		layoutData.MinSize = geom.Size{Height: 4 + fonts.PageFieldPrimary.LineHeight()}
+	} else {
+		installVirtualCells(table.AsPanel())
 	}
 	table.SetLayoutData(layoutData)
 
@@ -201,14 +203,19 @@ func canCopySelectionToTemplate[T gurps.NodeTypes](table *unison.Table[*Node[T]]
 
 func libraryFileFromTable[T gurps.NodeTypes](table *unison.Table[*Node[T]]) gurps.LibraryFile {
 	if d := unison.Ancestor[*TableDockable[T]](table); d != nil {
-		for _, lib := range gurps.GlobalSettings().Libraries() {
-			libPathOnDisk := lib.PathOnDisk + string(filepath.Separator)
-			filePathOnDisk := d.BackingFilePath()
-			if strings.HasPrefix(filePathOnDisk, libPathOnDisk) {
-				return gurps.LibraryFile{
-					Library: lib.Key(),
-					Path:    filepath.ToSlash(strings.TrimPrefix(filePathOnDisk, libPathOnDisk)),
-				}
+		return libraryFileForPath(d.BackingFilePath())
+	}
+	return gurps.LibraryFile{}
+}
+
+// libraryFileForPath returns the library file for the path on disk, or an empty one if it isn't within a library.
+func libraryFileForPath(filePathOnDisk string) gurps.LibraryFile {
+	for _, lib := range gurps.GlobalSettings().Libraries() {
+		libPathOnDisk := lib.PathOnDisk + string(filepath.Separator)
+		if strings.HasPrefix(filePathOnDisk, libPathOnDisk) {
+			return gurps.LibraryFile{
+				Library: lib.Key(),
+				Path:    filepath.ToSlash(strings.TrimPrefix(filePathOnDisk, libPathOnDisk)),
 			}
 		}
 	}
@@ -220,6 +227,14 @@ func copySelectionToSheet[T gurps.NodeTypes](table *unison.Table[*Node[T]]) {
 		if sheets := PromptForDestination(OpenSheets(unison.Ancestor[*Sheet](table))); len(sheets) > 0 {
 			sel := table.SelectedRows(true)
 			for _, s := range sheets {
+				switch any(sel[0].Data()).(type) {
+				case *gurps.Trait, *gurps.Skill, *gurps.Spell:
+					if s.entity.Locked {
+						unison.ErrorDialogWithMessage(fmt.Sprintf(i18n.Text("Unable to copy to %s"), s.Title()),
+							gurps.SheetLocked())
+						continue
+					}
+				}
 				var targetTable *unison.Table[*Node[T]]
 				var postProcessor func(rows []*Node[T])
 				switch any(sel[0].Data()).(type) {
@@ -393,7 +408,7 @@ func DeleteSelection[T gurps.NodeTypes](table *unison.Table[*Node[T]], recordUnd
 		provider.SetRootData(topLevelData)
 		if recordUndo && mgr != nil && undo != nil {
 			undo.AfterData = NewTableUndoEditData(table)
-			mgr.Add(undo)
+			addUndo(mgr, undo)
 		}
 		if builder := unison.AncestorOrSelf[Rebuildable](table); builder != nil {
 			builder.Rebuild(true)
@@ -456,7 +471,7 @@ func DuplicateSelection[T gurps.NodeTypes](table *unison.Table[*Node[T]]) {
 		table.SetSelectionMap(selMap)
 		if mgr != nil && undo != nil {
 			undo.AfterData = NewTableUndoEditData(table)
-			mgr.Add(undo)
+			addUndo(mgr, undo)
 		}
 		if builder := unison.AncestorOrSelf[Rebuildable](table); builder != nil {
 			builder.Rebuild(true)
@@ -494,7 +509,7 @@ func ClearSourceFromSelection[T gurps.NodeTypes](table *unison.Table[*Node[T]])
 		table.SyncToModel()
 		if mgr != nil && undo != nil {
 			undo.AfterData = NewTableUndoEditData(table)
-			mgr.Add(undo)
+			addUndo(mgr, undo)
 		}
 		if builder := unison.AncestorOrSelf[Rebuildable](table); builder != nil {
 			builder.Rebuild(true)
@@ -527,7 +542,7 @@ func SyncWithSourceForSelection[T gurps.NodeTypes](table *unison.Table[*Node[T]]
 		table.SyncToModel()
 		if mgr != nil && undo != nil {
 			undo.AfterData = NewTableUndoEditData(table)
-			mgr.Add(undo)
+			addUndo(mgr, undo)
 		}
 		if builder := unison.AncestorOrSelf[Rebuildable](table); builder != nil {
 			builder.Rebuild(true)
@@ -571,7 +586,7 @@ func CopyRowsTo[T gurps.NodeTypes](table *unison.Table[*Node[T]], rows []*Node[T
 	table.ScrollRowCellIntoView(table.FirstSelectedRowIndex(), 0)
 	if recordUndo && mgr != nil && undo != nil {
 		undo.AfterData = NewTableUndoEditData(table)
-		mgr.Add(undo)
+		addUndo(mgr, undo)
 	}
 	unison.Ancestor[Rebuildable](table).Rebuild(true)
 }
@@ -0,0 +1,236 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+const maxCustomColumnWidth = 1000
+
+// columnCustomizer is implemented by the table providers whose page columns the user may choose, reorder and resize.
+type columnCustomizer interface {
+	gurps.DataOwnerProvider
+	RefKey() string
+	HierarchyColumnID() int
+	defaultColumnIDs() []int
+	availableColumnIDs() []int
+	columnHeaderData(columnID int) gurps.HeaderData
+}
+
+func columnSheetSettings(c columnCustomizer) *gurps.SheetSettings {
+	return gurps.SheetSettingsFor(c.DataOwner().OwningEntity())
+}
+
+func customizedColumnIDs(c columnCustomizer) []int {
+	return columnSheetSettings(c).ColumnsFor(c.RefKey()).Visible(c.availableColumnIDs(), c.defaultColumnIDs(),
+		c.HierarchyColumnID())
+}
+
+func columnName(data gurps.HeaderData) string {
+	if data.TitleIsImageKey {
+		switch data.Title {
+		case gurps.HeaderCheckmark:
+			return i18n.Text("Equipped")
+		case gurps.HeaderCoins:
+			return i18n.Text("Value")
+		case gurps.HeaderStackedCoins:
+			return i18n.Text("Extended Value")
+		case gurps.HeaderWeight:
+			return i18n.Text("Weight")
+		case gurps.HeaderStackedWeight:
+			return i18n.Text("Extended Weight")
+		case gurps.HeaderBookmark:
+			return i18n.Text("Page Reference")
+		case gurps.HeaderDatabase:
+			return i18n.Text("Library Source")
+		}
+	}
+	if data.Primary {
+		return i18n.Text("Description")
+	}
+	// Some details are sentences meant for tooltips, so only use those that just spell out an abbreviated title.
+	if data.Detail != "" && len(strings.Fields(data.Detail)) <= 3 {
+		return data.Detail
+	}
+	return data.Title
+}
+
+func updateTableColumns(c columnCustomizer, adjuster func(columns *gurps.TableColumns)) {
+	settings := columnSheetSettings(c)
+	columns := settings.ColumnsFor(c.RefKey()).Clone()
+	if columns == nil {
+		columns = &gurps.TableColumns{}
+	}
+	adjuster(columns)
+	settings.SetColumnsFor(c.RefKey(), columns)
+	entity := c.DataOwner().OwningEntity()
+	for _, one := range AllDockables() {
+		if s, ok := one.(gurps.SheetSettingsResponder); ok {
+			s.SheetSettingsUpdated(entity, true)
+		}
+	}
+}
+
+func installColumnChooser[T gurps.NodeTypes](header *unison.TableHeader[*Node[T]], c columnCustomizer) {
+	header.MouseDownCallback = func(where geom.Point, button, clickCount int, mod unison.Modifiers) bool {
+		if button == unison.ButtonRight && clickCount == 1 {
+			showColumnChooserMenu(header.AsPanel(), where, c)
+			return true
+		}
+		return header.DefaultMouseDown(where, button, clickCount, mod)
+	}
+}
+
+func showColumnChooserMenu(panel *unison.Panel, where geom.Point, c columnCustomizer) {
+	f := unison.DefaultMenuFactory()
+	cm := f.NewMenu(unison.PopupMenuTemporaryBaseID|unison.ContextMenuIDFlag, "", nil)
+	columns := columnSheetSettings(c).ColumnsFor(c.RefKey())
+	defaults := c.defaultColumnIDs()
+	required := c.HierarchyColumnID()
+	id := unison.PopupMenuTemporaryBaseID + 1
+	for _, columnID := range columns.Arrange(c.availableColumnIDs()) {
+		byDefault := slices.Contains(defaults, columnID)
+		shown := columnID == required || columns.Shown(columnID, byDefault)
+		item := f.NewItem(id, columnName(c.columnHeaderData(columnID)), unison.KeyBinding{},
+			func(_ unison.MenuItem) bool { return columnID != required },
+			func(_ unison.MenuItem) {
+				updateTableColumns(c, func(cols *gurps.TableColumns) { cols.SetShown(columnID, byDefault, !shown) })
+			})
+		item.SetCheckState(check.FromBool(shown))
+		cm.InsertItem(-1, item)
+		id++
+	}
+	cm.InsertSeparator(-1, true)
+	cm.InsertItem(-1, f.NewItem(id, i18n.Text("Customize Columns…"), unison.KeyBinding{}, nil,
+		func(_ unison.MenuItem) { showColumnCustomizer(c) }))
+	id++
+	cm.InsertItem(-1, f.NewItem(id, i18n.Text("Reset Columns"), unison.KeyBinding{},
+		func(_ unison.MenuItem) bool { return !columns.Empty() },
+		func(_ unison.MenuItem) {
+			updateTableColumns(c, func(cols *gurps.TableColumns) { *cols = gurps.TableColumns{} })
+		}))
+	panel.FlushDrawing()
+	cm.Popup(geom.Rect{
+		Point: panel.PointToRoot(where),
+		Size:  geom.Size{Width: 1, Height: 1},
+	}, 0)
+	cm.Dispose()
+}
+
+// showColumnCustomizer shows a dialog that lets the user choose, reorder and set the widths of the columns shown in a
+// table on a sheet.
+func showColumnCustomizer(c columnCustomizer) {
+	columns := columnSheetSettings(c).ColumnsFor(c.RefKey()).Clone()
+	if columns == nil {
+		columns = &gurps.TableColumns{}
+	}
+	order := columns.Arrange(c.availableColumnIDs())
+	defaults := c.defaultColumnIDs()
+	required := c.HierarchyColumnID()
+
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	var rebuild func()
+	rebuild = func() {
+		content.RemoveAllChildren()
+		for _, header := range []string{i18n.Text("Column"), i18n.Text("Width"), "", ""} {
+			label := unison.NewLabel()
+			label.Font = fonts.PageLabelSecondary
+			label.SetTitle(header)
+			content.AddChild(label)
+		}
+		for i, columnID := range order {
+			byDefault := slices.Contains(defaults, columnID)
+			name := columnName(c.columnHeaderData(columnID))
+			checkbox := unison.NewCheckBox()
+			checkbox.SetTitle(name)
+			checkbox.State = check.FromBool(columnID == required || columns.Shown(columnID, byDefault))
+			checkbox.SetEnabled(columnID != required)
+			checkbox.ClickCallback = func() { columns.SetShown(columnID, byDefault, checkbox.State == check.On) }
+			content.AddChild(checkbox)
+			field := NewIntegerField(nil, "", fmt.Sprintf(i18n.Text("Width of %s"), name),
+				func() int { return int(columns.Width(columnID)) },
+				func(v int) { columns.SetWidth(columnID, float32(v)) },
+				0, maxCustomColumnWidth, false, false)
+			field.Tooltip = newWrappedTooltip(i18n.Text("The width of the column, or 0 to size it automatically"))
+			content.AddChild(field)
+			content.AddChild(newColumnMoveButton(unison.SortAscendingSVG, i18n.Text("Move up"), i > 0, func() {
+				order[i-1], order[i] = order[i], order[i-1]
+				rebuild()
+			}))
+			content.AddChild(newColumnMoveButton(unison.SortDescendingSVG, i18n.Text("Move down"), i < len(order)-1,
+				func() {
+					order[i], order[i+1] = order[i+1], order[i]
+					rebuild()
+				}))
+		}
+		content.MarkForLayoutRecursivelyUpward()
+		content.MarkForRedraw()
+	}
+	rebuild()
+
+	dialog, err := unison.NewDialog(nil, nil, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption(), unison.NotResizableWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	columns.Order = order
+	updateTableColumns(c, func(cols *gurps.TableColumns) { *cols = *columns })
+}
+
+func newColumnMoveButton(icon *unison.SVG, tooltip string, enabled bool, clickCallback func()) *unison.Button {
+	b := unison.NewSVGButton(icon)
+	b.Tooltip = newWrappedTooltip(tooltip)
+	b.SetEnabled(enabled)
+	b.ClickCallback = clickCallback
+	b.SetLayoutData(&unison.FlexLayoutData{VAlign: align.Middle})
+	return b
+}
+
+func (p *PageList[T]) applyColumnWidths() {
+	c, ok := p.provider.(columnCustomizer)
+	if !ok || len(p.defaultColumns) != len(p.Table.Columns) {
+		return
+	}
+	columns := columnSheetSettings(c).ColumnsFor(c.RefKey())
+	for i := range p.Table.Columns {
+		col := &p.Table.Columns[i]
+		if width := columns.Width(col.ID); width > 0 {
+			col.AutoMinimum = width
+			col.AutoMaximum = width
+			col.Minimum = width + p.Table.Padding.Left + p.Table.Padding.Right
+		} else {
+			col.AutoMinimum = p.defaultColumns[i].AutoMinimum
+			col.AutoMaximum = p.defaultColumns[i].AutoMaximum
+			col.Minimum = p.defaultColumns[i].Minimum
+		}
+	}
+}
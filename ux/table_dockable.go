@@ -17,10 +17,12 @@ import (
 
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/cell"
+	"github.com/richardwilkes/gcs/v5/model/gurps/export"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
 	"github.com/richardwilkes/toolbox/v2/xfilepath"
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/align"
@@ -49,7 +51,12 @@ type TableDockable[T gurps.NodeTypes] struct {
 	saver             func(path string) error
 	canCreateIDs      map[int]bool
 	filterField       *unison.Field
+	filterPopup       *unison.PopupMenu[string]
 	namesOnlyCheckBox *unison.CheckBox
+	activeFilter      *gurps.SavedFilter
+	smartGroups       *unison.Panel
+	smartGroup        *unison.Group
+	smartGroupButtons map[*gurps.SavedFilter]*unison.Button
 	scroll            *unison.ScrollPanel
 	tableHeader       *unison.TableHeader[*Node[T]]
 	table             *unison.Table[*Node[T]]
@@ -112,6 +119,7 @@ func NewTableDockable[T gurps.NodeTypes](filePath, extension string, provider Ta
 	})
 
 	d.AddChild(d.createToolbar())
+	d.AddChild(d.createSmartGroupsPanel())
 	d.AddChild(d.scroll)
 
 	d.InstallCmdHandlers(OpenEditorItemID,
@@ -136,6 +144,9 @@ func NewTableDockable[T gurps.NodeTypes](filePath, extension string, provider Ta
 	table.InstallCmdHandlers(SyncWithSourceItemID,
 		func(_ any) bool { return HasSelectionAndNotFiltered(d.table) },
 		func(_ any) { SyncWithSourceForSelection(d.table) })
+	table.InstallCmdHandlers(ReviewSourceChangesItemID,
+		func(_ any) bool { return CanReviewSourceChangesForSelection(d.table) },
+		func(_ any) { ReviewSourceChangesForSelection(d.table) })
 	table.InstallCmdHandlers(ClearSourceItemID,
 		func(_ any) bool { return HasSelectionAndNotFiltered(d.table) },
 		func(_ any) { ClearSourceFromSelection(d.table) })
@@ -179,15 +190,15 @@ func (d *TableDockable[T]) createToolbar() *unison.Panel {
 	sizeToFitButton.Tooltip = newWrappedTooltip(i18n.Text("Sets the width of each column to fit its contents"))
 	sizeToFitButton.ClickCallback = d.sizeToFit
 
-	filterPopup := NewTagFilterPopup(d)
+	d.filterPopup = NewTagFilterPopup(d)
 
 	d.filterField = NewSearchField(i18n.Text("Content Filter"), func(_, _ *unison.FieldState) {
-		d.ApplyFilter(SelectedTags(filterPopup))
+		d.ApplyFilter(SelectedTags(d.filterPopup))
 	})
 
 	d.namesOnlyCheckBox = unison.NewCheckBox()
 	d.namesOnlyCheckBox.SetTitle(i18n.Text("Names Only"))
-	d.namesOnlyCheckBox.ClickCallback = func() { d.ApplyFilter(SelectedTags(filterPopup)) }
+	d.namesOnlyCheckBox.ClickCallback = func() { d.ApplyFilter(SelectedTags(d.filterPopup)) }
 
 	toolbar := unison.NewPanel()
 	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
@@ -209,9 +220,16 @@ func (d *TableDockable[T]) createToolbar() *unison.Panel {
 	toolbar.AddChild(hierarchyButton)
 	toolbar.AddChild(noteToggleButton)
 	toolbar.AddChild(sizeToFitButton)
+	if export.SupportsTable[T]() {
+		exportButton := unison.NewSVGButton(svg.Menu)
+		exportButton.Tooltip = newWrappedTooltip(i18n.Text("Export the list to a spreadsheet"))
+		exportButton.ClickCallback = func() { d.showExportMenu(exportButton) }
+		toolbar.AddChild(exportButton)
+	}
 	toolbar.AddChild(d.filterField)
 	toolbar.AddChild(d.namesOnlyCheckBox)
-	toolbar.AddChild(filterPopup)
+	toolbar.AddChild(d.filterPopup)
+	toolbar.AddChild(d.createSavedFiltersButton())
 	toolbar.SetLayoutData(&unison.FlexLayoutData{
 		HAlign: align.Fill,
 		HGrab:  true,
@@ -223,6 +241,27 @@ func (d *TableDockable[T]) createToolbar() *unison.Panel {
 	return toolbar
 }
 
+func (d *TableDockable[T]) showExportMenu(b *unison.Button) {
+	f := unison.DefaultMenuFactory()
+	id := unison.ContextMenuIDFlag
+	m := f.NewMenu(id, "", nil)
+	id++
+	m.InsertItem(-1, f.NewItem(id, i18n.Text("Export as CSV…"), unison.KeyBinding{}, nil,
+		func(_ unison.MenuItem) { d.exportTable(export.CSVExtension, export.ToCSV) }))
+	id++
+	m.InsertItem(-1, f.NewItem(id, i18n.Text("Export as XLSX…"), unison.KeyBinding{}, nil,
+		func(_ unison.MenuItem) { d.exportTable(export.XLSXExtension, export.ToXLSX) }))
+	m.Popup(b.RectToRoot(b.ContentRect(true)), 0)
+}
+
+func (d *TableDockable[T]) exportTable(ext string, exporter func(t *export.Table, path string) error) {
+	if filePath, ok := promptForExportPath(d.path, ext); ok {
+		if err := exporter(export.TableFor(d.provider, d.provider.RootData()), filePath); err != nil {
+			Workspace.ErrorHandler(i18n.Text("Export failed"), err)
+		}
+	}
+}
+
 // Entity implements gurps.EntityProvider
 func (d *TableDockable[T]) Entity() *gurps.Entity {
 	return nil
@@ -311,6 +350,17 @@ func (d *TableDockable[T]) save(forceSaveAs bool) bool {
 	return success
 }
 
+func (d *TableDockable[T]) autosaver() func(filePath string) error {
+	return d.saver
+}
+
+func (d *TableDockable[T]) markRecovered(filePath string, untitled bool) {
+	d.path = filePath
+	d.needsSaveAsPrompt = untitled
+	d.hash = 0 // Force it to be recognized as unsaved
+	UpdateTitleForDockable(d)
+}
+
 func (d *TableDockable[T]) preserveColumns() {
 	m := make(map[int]float32, len(d.table.Columns))
 	m[-1] = gurps.ToColumnCutoff(time.Now().Unix())
@@ -420,6 +470,7 @@ func (d *TableDockable[T]) Rebuild(_ bool) {
 	d.table.SetSelectionMap(sel)
 	UpdateTitleForDockable(d)
 	d.scroll.SetPosition(h, v)
+	UpdateSpellPrereqGraph(d)
 }
 
 // Hash writes this object's contents into the hasher.
@@ -434,6 +485,44 @@ func (d *TableDockable[T]) Hash(h hash.Hash) {
 	}
 }
 
+func (d *TableDockable[T]) rootData() []T {
+	rows := d.table.RootRows()
+	data := make([]T, 0, len(rows))
+	for _, row := range rows {
+		data = append(data, row.Data())
+	}
+	return data
+}
+
+func (d *TableDockable[T]) countTags(counts map[string]int) {
+	gurps.CountTags(counts, d.rootData()...)
+}
+
+func (d *TableDockable[T]) renameTag(from, to string) {
+	undo := &unison.UndoEdit[*TableUndoEditData[T]]{
+		ID:         unison.NextUndoID(),
+		EditName:   i18n.Text("Rename Tag"),
+		UndoFunc:   func(e *unison.UndoEdit[*TableUndoEditData[T]]) { e.BeforeData.Apply() },
+		RedoFunc:   func(e *unison.UndoEdit[*TableUndoEditData[T]]) { e.AfterData.Apply() },
+		AbsorbFunc: func(_ *unison.UndoEdit[*TableUndoEditData[T]], _ unison.Undoable) bool { return false },
+		BeforeData: NewTableUndoEditData(d.table),
+	}
+	if gurps.RenameTagInNodes(from, to, d.rootData()...) {
+		d.table.SyncToModel()
+		undo.AfterData = NewTableUndoEditData(d.table)
+		addUndo(d.undoMgr, undo)
+		d.MarkModified(d)
+	}
+}
+
+func (d *TableDockable[T]) searchNodes(hits []*gurps.SearchHit, text string) []*gurps.SearchHit {
+	return gurps.SearchNodes(hits, d.path, text, d.rootData()...)
+}
+
+func (d *TableDockable[T]) locateNode(id tid.TID) bool {
+	return locateTableRowByID(d.table, id)
+}
+
 // AllTags returns all tags currently present in the data.
 func (d *TableDockable[T]) AllTags() []string {
 	return d.provider.AllTags()
@@ -444,8 +533,11 @@ func (d *TableDockable[T]) ApplyFilter(tags []string) {
 	if d.filterField != nil {
 		text := strings.ToLower(strings.TrimSpace(d.filterField.GetFieldState().Text))
 		var f func(row *Node[T]) bool
-		if len(tags) != 0 || text != "" {
+		if len(tags) != 0 || text != "" || d.activeFilter != nil {
 			f = func(row *Node[T]) bool {
+				if d.activeFilter != nil && !d.activeFilter.MatchesCriteria(row.Data()) {
+					return true
+				}
 				match := false
 				if d.namesOnlyCheckBox.State == check.On {
 					match = strings.Contains(strings.ToLower(row.dataAsNode.String()), text)
@@ -464,5 +556,6 @@ func (d *TableDockable[T]) ApplyFilter(tags []string) {
 			}
 		}
 		d.table.ApplyFilter(f)
+		d.syncSmartGroupSelection()
 	}
 }
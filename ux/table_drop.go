@@ -130,7 +130,7 @@ func isForCharacterOrLootSheet(panel unison.Paneler) bool {
 		return false
 	}
 	switch unison.AncestorOrSelf[unison.Dockable](panel).(type) {
-	case *Sheet, *LootSheet:
+	case *Sheet, *LootSheet, *VehicleSheet:
 		return true
 	default:
 		return false
@@ -149,5 +149,5 @@ func finishDidDrop[T gurps.NodeTypes](undo *unison.UndoEdit[*TableDragUndoEditDa
 		from = nil
 	}
 	undo.AfterData = NewTableDragUndoEditData(from, to)
-	mgr.Add(undo)
+	addUndo(mgr, undo)
 }
@@ -17,11 +17,14 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/fonts"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/cell"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/eqploc"
+	"github.com/richardwilkes/gcs/v5/model/textlayout"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
 	"github.com/richardwilkes/toolbox/v2/xos"
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/align"
@@ -33,9 +36,10 @@ var _ unison.TableRowData[*Node[*gurps.Trait]] = &Node[*gurps.Trait]{}
 
 // CellCache holds data for a table row's cell to reduce the need to constantly recreate them.
 type CellCache struct {
-	Panel unison.Paneler
-	Data  gurps.CellData
-	Width float32
+	Panel    unison.Paneler
+	Data     gurps.CellData
+	Width    float32
+	measures []cellMeasure
 }
 
 // Matches returns true if the provided width and data match the current contents.
@@ -148,11 +152,31 @@ func (n *Node[T]) ColumnCell(row, col int, foreground, background unison.Ink, se
 	var cellData gurps.CellData
 	n.dataAsNode.CellData(n.table.Columns[col].ID, &cellData)
 	width := n.table.CellWidth(row, col)
+	v := virtualCellsFor(n.table.AsPanel())
 	if n.cellCache[col].Matches(width, &cellData) {
 		applyInkRecursively(n.cellCache[col].Panel.AsPanel(), foreground, background, selected || indirectlySelected)
+		if v != nil {
+			v.touch(n.cellCache[col])
+		}
 		return n.cellCache[col].Panel
 	}
-	c := n.CellFromCellData(&cellData, width, foreground, background, selected || indirectlySelected)
+	build := func() unison.Paneler {
+		c := n.CellFromCellData(&cellData, width, foreground, background, selected || indirectlySelected)
+		if n.forPage {
+			n.installRollHandler(c, n.table.Columns[col].ID)
+		}
+		return c
+	}
+	if v != nil {
+		if cache := n.cellCache[col]; cache != nil {
+			v.release(cache)
+		}
+		if n.cellCache[col] == nil || n.cellCache[col].Data != cellData {
+			n.cellCache[col] = &CellCache{Data: cellData}
+		}
+		return v.bind(n.cellCache[col], width, build)
+	}
+	c := build()
 	n.cellCache[col] = &CellCache{
 		Panel: c,
 		Data:  cellData,
@@ -161,6 +185,60 @@ func (n *Node[T]) ColumnCell(row, col int, foreground, background unison.Ink, se
 	return c
 }
 
+// installRollHandler makes the cell roll the dice when clicked, if the column holds something that can be rolled.
+func (n *Node[T]) installRollHandler(c unison.Paneler, columnID int) {
+	var roll func()
+	switch item := any(n.data).(type) {
+	case *gurps.Skill:
+		if columnID == gurps.SkillLevelColumn && !item.Container() && item.LevelData.Level > 0 {
+			roll = func() { RollSuccessFor(gurps.EntityFromNode(item), item.String(), item.LevelData.Level) }
+		}
+	case *gurps.Spell:
+		if columnID == gurps.SpellLevelColumn && !item.Container() && item.LevelData.Level > 0 {
+			roll = func() { RollSuccessFor(gurps.EntityFromNode(item), item.String(), item.LevelData.Level) }
+		}
+	case *gurps.Weapon:
+		switch columnID {
+		case gurps.WeaponSLColumn:
+			roll = func() {
+				if level := item.SkillLevel(nil); level > 0 {
+					fired, warning := fireWeapon(c, item)
+					if warning != "" {
+						unison.WarningDialogWithMessage(i18n.Text("Ammunition"), warning)
+					}
+					if fired {
+						RollSuccessFor(item.Entity(), weaponRollName(item), level)
+					}
+				}
+			}
+		case gurps.WeaponDamageColumn:
+			roll = func() { RollDamageFor(item.Entity(), weaponRollName(item), item.Damage.ResolvedDamage(nil)) }
+		case gurps.WeaponHitLocationColumn:
+			roll = func() { RollHitLocationFor(item) }
+		}
+	}
+	if roll == nil {
+		return
+	}
+	panel := c.AsPanel()
+	panel.MouseDownCallback = func(_ geom.Point, button, clickCount int, _ unison.Modifiers) bool {
+		if button != unison.ButtonLeft || clickCount != 1 {
+			return false
+		}
+		roll()
+		return true
+	}
+	panel.UpdateCursorCallback = func(_ geom.Point) *unison.Cursor { return unison.PointingCursor() }
+}
+
+// weaponRollName returns the name to use for a roll made with the weapon, noting the maneuver in effect, if any.
+func weaponRollName(w *gurps.Weapon) string {
+	if entity := w.Entity(); entity != nil && entity.Maneuver != "" {
+		return fmt.Sprintf("%s (%s)", w.String(), entity.CurrentManeuver().Name)
+	}
+	return w.String()
+}
+
 func applyInkRecursively(panel *unison.Panel, foreground, background unison.Ink, selected bool) {
 	switch part := panel.Self.(type) {
 	case *unison.Markdown:
@@ -219,14 +297,15 @@ func (n *Node[T]) Data() T {
 	return n.data
 }
 
-// HasTag returns true if the specified tag is present on the node. An empty tag will match all nodes.
+// HasTag returns true if the specified tag, or one of its descendants in the tag hierarchy, is present on the node. An
+// empty tag will match all nodes.
 func (n *Node[T]) HasTag(tag string) bool {
 	if tag == "" {
 		return true
 	}
 	if tagListable, ok := any(n.Data()).(interface{ TagList() []string }); ok {
 		for _, one := range tagListable.TagList() {
-			if strings.EqualFold(tag, one) {
+			if gurps.TagMatches(tag, one) {
 				return true
 			}
 		}
@@ -392,25 +471,75 @@ func (n *Node[T]) createLabelCell(c *gurps.CellData, width float32, foreground,
 	}
 	tooltip := c.Tooltip
 	if c.UnsatisfiedReason != "" {
-		p.AddChild(makeTagForNode(i18n.Text("Unsatisfied prerequisite(s)"), unison.ThemeError, unison.ThemeOnError,
-			n.secondaryFieldFont(), unison.TriangleExclamationSVG))
-		tooltip = c.UnsatisfiedReason
+		tag := makeTagForNode(i18n.Text("Unsatisfied prerequisite(s)"), unison.ThemeError, unison.ThemeOnError,
+			n.secondaryFieldFont(), unison.TriangleExclamationSVG)
+		tag.MouseDownCallback = func(_ geom.Point, _, _ int, _ unison.Modifiers) bool {
+			ShowPrereqExplanation(c.Self)
+			return true
+		}
+		p.AddChild(tag)
+		tooltip = c.UnsatisfiedReason + "\n\n" + i18n.Text("Click the tag for details.")
 	}
 	if c.TemplateInfo != "" {
 		p.AddChild(makeTagForNode(c.TemplateInfo, foreground, background, n.secondaryFieldFont(), svg.GCSTemplate))
 	}
-	if tooltip != "" {
-		var workingDir string
-		if wd, ok := n.table.ClientData()[WorkingDirKey]; ok {
-			if wdStr, ok2 := wd.(string); ok2 {
-				workingDir = wdStr
-			}
+	if c.CapacityFill != "" {
+		if c.CapacityProblem != "" {
+			tag := makeTagForNode(c.CapacityFill, unison.ThemeError, unison.ThemeOnError, n.secondaryFieldFont(),
+				unison.TriangleExclamationSVG)
+			tag.Tooltip = newWrappedTooltip(c.CapacityProblem)
+			p.AddChild(tag)
+		} else {
+			p.AddChild(makeTagForNode(c.CapacityFill, foreground, background, n.secondaryFieldFont(), svg.Weight))
 		}
+	}
+	var workingDir string
+	if wd, ok := n.table.ClientData()[WorkingDirKey]; ok {
+		if wdStr, ok2 := wd.(string); ok2 {
+			workingDir = wdStr
+		}
+	}
+	if c.LinkedSheet != "" {
+		if t, ok := c.Self.(*gurps.Trait); ok {
+			p.AddChild(n.createLinkedSheetTag(t, workingDir, foreground, background))
+		}
+	}
+	if tooltip != "" {
 		p.Tooltip = newMarkdownTooltip(tooltip, workingDir)
 	}
 	return p
 }
 
+// createLinkedSheetTag creates a tag showing the name and point total of the character the trait is linked to. Clicking
+// the tag opens the linked character's sheet.
+func (n *Node[T]) createLinkedSheetTag(t *gurps.Trait, workingDir string, foreground, background unison.Ink) *unison.Tag {
+	sheetPath := t.LinkedSheetPath(workingDir)
+	var tag *unison.Tag
+	if linked, err := t.LoadLinkedSheet(workingDir); err != nil {
+		tag = makeTagForNode(fmt.Sprintf(i18n.Text("%s (missing)"), xfilepath.BaseName(sheetPath)), unison.ThemeError,
+			unison.ThemeOnError, n.secondaryFieldFont(), svg.GCSSheet)
+		tag.Tooltip = newWrappedTooltip(sheetPath)
+	} else {
+		name := linked.Profile.Name
+		if name == "" {
+			name = xfilepath.BaseName(sheetPath)
+		}
+		title := fmt.Sprintf(i18n.Text("%s (%s pts)"), name, linked.TotalPoints.Comma())
+		if problem := t.LinkedSheetProblem(linked); problem != "" {
+			tag = makeTagForNode(title, unison.ThemeError, unison.ThemeOnError, n.secondaryFieldFont(), svg.GCSSheet)
+			tag.Tooltip = newWrappedTooltip(problem)
+		} else {
+			tag = makeTagForNode(title, foreground, background, n.secondaryFieldFont(), svg.GCSSheet)
+			tag.Tooltip = newWrappedTooltip(fmt.Sprintf(i18n.Text("Click to open %s"), sheetPath))
+		}
+	}
+	tag.MouseDownCallback = func(_ geom.Point, _, _ int, _ unison.Modifiers) bool {
+		OpenFile(sheetPath, 0)
+		return true
+	}
+	return tag
+}
+
 func makeTagForNode(title string, bg, fg unison.Ink, font unison.Font, img *unison.SVG) *unison.Tag {
 	tag := unison.NewTag()
 	tag.BackgroundInk = bg
@@ -447,29 +576,31 @@ func (n *Node[T]) addLabelCell(c *gurps.CellData, parent *unison.Panel, width fl
 		tag.SetTitle(inlineTag)
 		tag.SetEnabled(!c.Dim)
 	}
-	var lines []*unison.Text
+	var lines []string
 	if width > 0 {
 		if tag != nil {
 			_, size, _ := tag.Sizes(geom.Size{})
-			lines = unison.NewTextWrappedLines(text, decoration, width-(size.Width+unison.StdHSpacing))
+			lines = wrapTextLines(text, decoration, width-(size.Width+unison.StdHSpacing))
 			if len(lines) > 1 {
 				lines = lines[:1]
-				lines = append(lines, unison.NewTextWrappedLines(strings.TrimPrefix(text, lines[0].String()),
-					decoration, width)...)
+				lines = append(lines, wrapTextLines(strings.TrimPrefix(text, lines[0]), decoration, width)...)
 			}
 		} else {
-			lines = unison.NewTextWrappedLines(text, decoration, width)
+			lines = wrapTextLines(text, decoration, width)
 		}
 	} else {
-		lines = unison.NewTextLines(text, decoration)
+		lines = strings.Split(text, "\n")
 	}
 	for _, line := range lines {
 		label := unison.NewLabel()
 		label.Font = f
 		label.StrikeThrough = primary && c.Disabled
 		label.HAlign = c.Alignment
+		if label.HAlign == align.Start && textlayout.IsRightToLeft(line) {
+			label.HAlign = align.End
+		}
 		label.OnBackgroundInk = foreground
-		label.SetTitle(line.String())
+		label.SetTitle(textlayout.Visual(line))
 		label.SetEnabled(!c.Dim)
 		if tag != nil {
 			wrapper := unison.NewPanel()
@@ -538,10 +669,11 @@ func (n *Node[T]) createToggleCell(c *gurps.CellData, foreground unison.Ink) uni
 func handleCheck(data any, check unison.Paneler, checked bool) {
 	switch item := data.(type) {
 	case *gurps.Equipment:
-		item.Equipped = checked
+		before := item.Location
+		item.SetEquipped(checked)
 		if mgr := unison.UndoManagerFor(check); mgr != nil {
 			owner := unison.AncestorOrSelf[Rebuildable](check)
-			mgr.Add(&unison.UndoEdit[*equipmentAdjuster]{
+			addUndo(mgr, &unison.UndoEdit[*equipmentAdjuster]{
 				ID:       unison.NextUndoID(),
 				EditName: i18n.Text("Toggle Equipped"),
 				UndoFunc: func(edit *unison.UndoEdit[*equipmentAdjuster]) { edit.BeforeData.Apply() },
@@ -549,21 +681,48 @@ func handleCheck(data any, check unison.Paneler, checked bool) {
 				BeforeData: &equipmentAdjuster{
 					Owner:    owner,
 					Target:   item,
-					Equipped: !item.Equipped,
+					Location: before,
 				},
 				AfterData: &equipmentAdjuster{
 					Owner:    owner,
 					Target:   item,
-					Equipped: item.Equipped,
+					Location: item.Location,
 				},
 			})
 		}
 		gurps.EntityFromNode(item).Recalculate()
+	case *gurps.ConditionalModifier:
+		entity := item.Entity()
+		if entity == nil {
+			return
+		}
+		item.SetActive(checked)
+		if mgr := unison.UndoManagerFor(check); mgr != nil {
+			owner := unison.AncestorOrSelf[Rebuildable](check)
+			addUndo(mgr, &unison.UndoEdit[*conditionalModifierAdjuster]{
+				ID:       unison.NextUndoID(),
+				EditName: i18n.Text("Toggle Conditional Modifier"),
+				UndoFunc: func(edit *unison.UndoEdit[*conditionalModifierAdjuster]) { edit.BeforeData.Apply() },
+				RedoFunc: func(edit *unison.UndoEdit[*conditionalModifierAdjuster]) { edit.AfterData.Apply() },
+				BeforeData: &conditionalModifierAdjuster{
+					Owner:     owner,
+					Entity:    entity,
+					Situation: item.From,
+					Active:    !checked,
+				},
+				AfterData: &conditionalModifierAdjuster{
+					Owner:     owner,
+					Entity:    entity,
+					Situation: item.From,
+					Active:    checked,
+				},
+			})
+		}
 	case *gurps.TraitModifier:
 		item.Disabled = !checked
 		if mgr := unison.UndoManagerFor(check); mgr != nil {
 			owner := unison.AncestorOrSelf[Rebuildable](check)
-			mgr.Add(&unison.UndoEdit[*traitModifierAdjuster]{
+			addUndo(mgr, &unison.UndoEdit[*traitModifierAdjuster]{
 				ID:       unison.NextUndoID(),
 				EditName: i18n.Text("Toggle Trait Modifier"),
 				UndoFunc: func(edit *unison.UndoEdit[*traitModifierAdjuster]) { edit.BeforeData.Apply() },
@@ -585,7 +744,7 @@ func handleCheck(data any, check unison.Paneler, checked bool) {
 		item.Disabled = !checked
 		if mgr := unison.UndoManagerFor(check); mgr != nil {
 			owner := unison.AncestorOrSelf[Rebuildable](check)
-			mgr.Add(&unison.UndoEdit[*equipmentModifierAdjuster]{
+			addUndo(mgr, &unison.UndoEdit[*equipmentModifierAdjuster]{
 				ID:       unison.NextUndoID(),
 				EditName: i18n.Text("Toggle Equipment Modifier"),
 				UndoFunc: func(edit *unison.UndoEdit[*equipmentModifierAdjuster]) { edit.BeforeData.Apply() },
@@ -607,7 +766,7 @@ func handleCheck(data any, check unison.Paneler, checked bool) {
 		item.Hide = checked
 		if mgr := unison.UndoManagerFor(check); mgr != nil {
 			owner := unison.AncestorOrSelf[Rebuildable](check)
-			mgr.Add(&unison.UndoEdit[*weaponAdjuster]{
+			addUndo(mgr, &unison.UndoEdit[*weaponAdjuster]{
 				ID:       unison.NextUndoID(),
 				EditName: i18n.Text("Toggle Hidden"),
 				UndoFunc: func(edit *unison.UndoEdit[*weaponAdjuster]) { edit.BeforeData.Apply() },
@@ -631,11 +790,11 @@ func handleCheck(data any, check unison.Paneler, checked bool) {
 type equipmentAdjuster struct {
 	Owner    Rebuildable
 	Target   *gurps.Equipment
-	Equipped bool
+	Location eqploc.Location
 }
 
 func (e *equipmentAdjuster) Apply() {
-	e.Target.Equipped = e.Equipped
+	e.Target.Location = e.Location
 	gurps.EntityFromNode(e.Target).Recalculate()
 	MarkModified(e.Owner)
 }
@@ -652,6 +811,18 @@ func (e *equipmentModifierAdjuster) Apply() {
 	MarkModified(e.Owner)
 }
 
+type conditionalModifierAdjuster struct {
+	Owner     Rebuildable
+	Entity    *gurps.Entity
+	Situation string
+	Active    bool
+}
+
+func (c *conditionalModifierAdjuster) Apply() {
+	c.Entity.SetConditionalModifierActive(c.Situation, c.Active)
+	MarkModified(c.Owner)
+}
+
 type traitModifierAdjuster struct {
 	Owner    Rebuildable
 	Target   *gurps.TraitModifier
@@ -677,11 +848,18 @@ func (w *weaponAdjuster) Apply() {
 }
 
 func convertLinksForPageRef(in string) (string, *unison.SVG) {
+	title, target := gurps.ParsePageRefLink(in)
 	switch {
-	case unison.HasURLPrefix(in):
-		return i18n.Text("link"), svg.Link
-	case strings.HasPrefix(in, "md:"):
-		return i18n.Text("md"), svg.MarkdownFile
+	case unison.HasURLPrefix(target):
+		if title == "" {
+			title = i18n.Text("link")
+		}
+		return title, svg.Link
+	case strings.HasPrefix(target, "md:"):
+		if title == "" {
+			title = i18n.Text("md")
+		}
+		return title, svg.MarkdownFile
 	default:
 		return in, nil
 	}
@@ -691,20 +869,23 @@ func (n *Node[T]) createPageRefCell(c *gurps.CellData, foreground unison.Ink) un
 	var title, tooltip string
 	var icon *unison.DrawableSVG
 	font := n.primaryFieldFont()
-	parts := strings.FieldsFunc(c.Primary, func(ch rune) bool { return ch == ',' || ch == ';' })
+	parts := gurps.ExtractPageReferences(c.Primary)
 	switch len(parts) {
 	case 0:
 	case 1:
 		var img *unison.SVG
 		title, img = convertLinksForPageRef(parts[0])
 		if img != nil {
-			title = ""
+			var target string
+			if _, target = gurps.ParsePageRefLink(parts[0]); target == parts[0] {
+				title = ""
+			}
 			height := font.Baseline()
 			icon = &unison.DrawableSVG{
 				SVG:  img,
 				Size: geom.NewSize(height, height).Ceil(),
 			}
-			tooltip = parts[0]
+			tooltip = target
 		}
 	default:
 		title, _ = convertLinksForPageRef(parts[0])
@@ -715,7 +896,7 @@ func (n *Node[T]) createPageRefCell(c *gurps.CellData, foreground unison.Ink) un
 	theme.OnBackgroundInk = foreground
 	theme.Font = font
 	link := unison.NewLink(title, tooltip, "", theme, func(_ unison.Paneler, _ string) {
-		list := ExtractPageReferences(c.Primary)
+		list := gurps.ExtractPageReferences(c.Primary)
 		if len(list) != 0 {
 			OpenPageReference(list[0], c.Secondary, nil)
 		}
@@ -826,7 +1007,7 @@ func InsertItems[T gurps.NodeTypes](owner Rebuildable, table *unison.Table[*Node
 	table.ScrollRowCellIntoView(table.FirstSelectedRowIndex(), 0)
 	if mgr != nil && undo != nil {
 		undo.AfterData = NewTableUndoEditData(table)
-		mgr.Add(undo)
+		addUndo(mgr, undo)
 	}
 	owner.Rebuild(true)
 }
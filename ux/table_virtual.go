@@ -0,0 +1,139 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"container/list"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/unison"
+)
+
+const (
+	virtualCellsClientDataKey = "virtual_cells"
+	// virtualCellsMaxRetained is the number of cell panels a table keeps around. It needs to comfortably exceed the
+	// number of cells that can be visible at once, since those are the ones that are drawn and interacted with.
+	virtualCellsMaxRetained = 4096
+	// cellMeasuresMax is the number of size measurements kept for each cell.
+	cellMeasuresMax = 4
+)
+
+type cellMeasure struct {
+	width   float32
+	hint    geom.Size
+	minSize geom.Size
+	pref    geom.Size
+	maxSize geom.Size
+}
+
+// virtualCells keeps a table from holding onto a panel for every one of its cells. The table needs the size of every
+// row to lay itself out, but only draws the rows that are visible, so the sizes are remembered separately from the
+// panels and only the most recently used panels are kept. Cells that don't currently have a panel are represented by a
+// single stand-in panel that answers size queries from the remembered sizes, building the real panel only when it is
+// actually needed.
+type virtualCells struct {
+	proxy    *unison.Panel
+	retained *list.List
+	elements map[*CellCache]*list.Element
+	bound    *CellCache
+	build    func() unison.Paneler
+}
+
+// installVirtualCells enables virtual cells for the table.
+func installVirtualCells(table *unison.Panel) {
+	v := &virtualCells{
+		proxy:    unison.NewPanel(),
+		retained: list.New(),
+		elements: make(map[*CellCache]*list.Element),
+	}
+	v.proxy.SetLayout(v)
+	table.ClientData()[virtualCellsClientDataKey] = v
+}
+
+// virtualCellsFor returns the virtual cells for the table, or nil if it doesn't use them.
+func virtualCellsFor(table *unison.Panel) *virtualCells {
+	if v, ok := table.ClientData()[virtualCellsClientDataKey].(*virtualCells); ok {
+		return v
+	}
+	return nil
+}
+
+// bind the stand-in panel to a cell that currently has no panel and return it. build will be called to create the
+// cell's panel if it turns out to be needed.
+func (v *virtualCells) bind(cache *CellCache, width float32, build func() unison.Paneler) unison.Paneler {
+	if cache.Width != width {
+		cache.Width = width
+		cache.measures = nil
+	}
+	v.proxy.RemoveAllChildren()
+	v.proxy.NeedsLayout = true
+	v.bound = cache
+	v.build = build
+	return v.proxy
+}
+
+// content returns the bound cell's panel, building it if necessary.
+func (v *virtualCells) content() unison.Paneler {
+	if v.bound.Panel == nil {
+		v.bound.Panel = v.build()
+		v.elements[v.bound] = v.retained.PushFront(v.bound)
+		for v.retained.Len() > virtualCellsMaxRetained {
+			v.release(v.retained.Back().Value.(*CellCache)) //nolint:errcheck // Only *CellCache is ever stored
+		}
+	}
+	return v.bound.Panel
+}
+
+// touch marks the cell's panel as recently used.
+func (v *virtualCells) touch(cache *CellCache) {
+	if elem, ok := v.elements[cache]; ok {
+		v.retained.MoveToFront(elem)
+	}
+}
+
+// release the cell's panel.
+func (v *virtualCells) release(cache *CellCache) {
+	if elem, ok := v.elements[cache]; ok {
+		v.retained.Remove(elem)
+		delete(v.elements, cache)
+	}
+	cache.Panel = nil
+}
+
+// LayoutSizes implements unison.Layout.
+func (v *virtualCells) LayoutSizes(_ *unison.Panel, hint geom.Size) (minSize, prefSize, maxSize geom.Size) {
+	cache := v.bound
+	for _, m := range cache.measures {
+		if m.width == cache.Width && m.hint == hint {
+			return m.minSize, m.pref, m.maxSize
+		}
+	}
+	minSize, prefSize, maxSize = v.content().AsPanel().Sizes(hint)
+	if len(cache.measures) >= cellMeasuresMax {
+		cache.measures = cache.measures[1:]
+	}
+	cache.measures = append(cache.measures, cellMeasure{
+		width:   cache.Width,
+		hint:    hint,
+		minSize: minSize,
+		pref:    prefSize,
+		maxSize: maxSize,
+	})
+	return minSize, prefSize, maxSize
+}
+
+// PerformLayout implements unison.Layout.
+func (v *virtualCells) PerformLayout(_ *unison.Panel) {
+	c := v.content()
+	if c.AsPanel().Parent() != v.proxy {
+		v.proxy.AddChild(c)
+	}
+	c.AsPanel().SetFrameRect(v.proxy.ContentRect(false))
+}
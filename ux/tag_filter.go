@@ -15,6 +15,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/align"
@@ -40,7 +41,7 @@ func NewTagFilterPopup(tagProvider TagProvider) *unison.PopupMenu[string] {
 		}
 		p.RemoveAllItems()
 		p.AddItem(i18n.Text("Any Tag"))
-		tags := tagProvider.AllTags()
+		tags := gurps.ExpandTagHierarchy(tagProvider.AllTags())
 		if len(tags) != 0 {
 			p.AddSeparator()
 			for _, tag := range tags {
@@ -0,0 +1,237 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+var (
+	_ unison.Dockable  = &TagManagerDockable{}
+	_ unison.TabCloser = &TagManagerDockable{}
+)
+
+// tagHolder defines the methods a dockable must provide for its tags to be managed by the tag manager.
+type tagHolder interface {
+	unison.Dockable
+	countTags(counts map[string]int)
+	renameTag(from, to string)
+}
+
+// TagManagerDockable lists the tags used by the open documents and allows them to be renamed or merged.
+type TagManagerDockable struct {
+	unison.Panel
+	content *unison.Panel
+}
+
+// ShowTagManager shows the tag manager.
+func ShowTagManager() {
+	if Activate(func(d unison.Dockable) bool {
+		_, ok := d.AsPanel().Self.(*TagManagerDockable)
+		return ok
+	}) {
+		return
+	}
+	d := &TagManagerDockable{}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.rebuild()
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar())
+	d.AddChild(scroll)
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *TagManagerDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	refreshButton := unison.NewSVGButton(svg.Reset)
+	refreshButton.Tooltip = newWrappedTooltip(i18n.Text("Refresh the list of tags from the open documents"))
+	refreshButton.ClickCallback = d.rebuild
+	toolbar.AddChild(refreshButton)
+	label := unison.NewLabel()
+	label.SetTitle(fmt.Sprintf(i18n.Text("Use %s to separate the levels of a hierarchical tag, e.g. DF%sWizard"),
+		gurps.TagSeparator, gurps.TagSeparator))
+	toolbar.AddChild(label)
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return toolbar
+}
+
+func (d *TagManagerDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	counts := make(map[string]int)
+	for _, one := range d.holders() {
+		one.countTags(counts)
+	}
+	tags := gurps.ExpandTagHierarchy(slices.Collect(maps.Keys(counts)))
+	if len(tags) == 0 {
+		label := unison.NewLabel()
+		label.SetTitle(i18n.Text("No tags are in use by the open documents."))
+		d.content.AddChild(label)
+	}
+	for _, tag := range tags {
+		parts := strings.Split(tag, gurps.TagSeparator)
+		label := unison.NewLabel()
+		label.SetTitle(strings.TrimSpace(parts[len(parts)-1]))
+		label.Tooltip = newWrappedTooltip(tag)
+		label.SetBorder(unison.NewEmptyBorder(geom.Insets{Left: float32(len(parts)-1) * unison.StdHSpacing * 3}))
+		d.content.AddChild(label)
+
+		total := 0
+		for k, v := range counts {
+			if gurps.TagMatches(tag, k) {
+				total += v
+			}
+		}
+		countLabel := unison.NewLabel()
+		countLabel.SetTitle(fmt.Sprint(total))
+		countLabel.HAlign = align.End
+		countLabel.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+		d.content.AddChild(countLabel)
+
+		button := unison.NewSVGButton(svg.Edit)
+		button.Tooltip = newWrappedTooltip(i18n.Text("Rename or merge this tag"))
+		button.ClickCallback = func() { d.rename(tag) }
+		d.content.AddChild(button)
+	}
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *TagManagerDockable) holders() []tagHolder {
+	var list []tagHolder
+	for _, one := range AllDockables() {
+		if holder, ok := one.(tagHolder); ok {
+			list = append(list, holder)
+		}
+	}
+	return list
+}
+
+func (d *TagManagerDockable) rename(tag string) {
+	newTag := tag
+	oldField := NewStringField(nil, "", "", func() string { return tag }, func(_ string) {})
+	oldField.SetEnabled(false)
+	newField := NewStringField(nil, "", "", func() string { return newTag }, func(s string) { newTag = s })
+	newField.SetMinimumTextWidthUsing(minTextWidthCandidate)
+	newField.Tooltip = newWrappedTooltip(i18n.Text(`Using the name of an existing tag will merge the two.
+Tags beneath this one in the hierarchy are renamed, too.`))
+
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Current Tag"), false))
+	panel.AddChild(oldField)
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("New Tag"), false))
+	panel.AddChild(newField)
+
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()})
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to create rename dialog"), err)
+		return
+	}
+	newField.ValidateCallback = func() bool {
+		trimmed := strings.TrimSpace(newTag)
+		valid := trimmed != "" && !strings.Contains(trimmed, ",")
+		dialog.Button(unison.ModalResponseOK).SetEnabled(valid)
+		return valid
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	newTag = strings.TrimSpace(newTag)
+	for _, one := range d.holders() {
+		counts := make(map[string]int)
+		one.countTags(counts)
+		for k := range counts {
+			if gurps.TagMatches(tag, k) {
+				one.renameTag(tag, newTag)
+				break
+			}
+		}
+	}
+	d.rebuild()
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *TagManagerDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Naming,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *TagManagerDockable) Title() string {
+	return i18n.Text("Tag Manager")
+}
+
+// Tooltip implements unison.Dockable.
+func (d *TagManagerDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *TagManagerDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *TagManagerDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *TagManagerDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
@@ -16,11 +16,9 @@ import (
 	"path/filepath"
 	"slices"
 
-	"github.com/richardwilkes/gcs/v5/model/criteria"
 	"github.com/richardwilkes/gcs/v5/model/fonts"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
-	"github.com/richardwilkes/gcs/v5/model/gurps/enums/picker"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/v2/errs"
 	"github.com/richardwilkes/toolbox/v2/geom"
@@ -313,10 +311,10 @@ func (t *Template) newSheetFromTemplate(_ any) {
 	e := gurps.NewEntity()
 	sheet := NewSheet(e.Profile.Name+gurps.SheetExt, e)
 	DisplayNewDockable(sheet)
-	if t.applyTemplateToSheet(sheet, true) {
+	t.applyTemplateToSheet(sheet, true, func() {
 		sheet.undoMgr.Clear()
 		sheet.hash = 0
-	}
+	})
 	sheet.SetBackingFilePath(e.Profile.Name + gurps.SheetExt)
 }
 
@@ -338,11 +336,60 @@ func (t *Template) applyTemplate(suppressRandomizePromptAsBool any) {
 	//nolint:errcheck // The default of false on failure is acceptable
 	suppressRandomizePrompt, _ := suppressRandomizePromptAsBool.(bool)
 	for _, sheet := range PromptForDestination(OpenSheets(nil)) {
-		t.applyTemplateToSheet(sheet, suppressRandomizePrompt)
+		t.applyTemplateToSheet(sheet, suppressRandomizePrompt, nil)
 	}
 }
 
-func (t *Template) applyTemplateToSheet(sheet *Sheet, suppressRandomizePrompt bool) bool {
+// applyTemplateToSheet applies the template to the sheet. If the template asks for choices to be made, a wizard is
+// shown to collect them first and the template is only applied once the wizard is finished. done, if not nil, is called
+// after the template has been applied.
+func (t *Template) applyTemplateToSheet(sheet *Sheet, suppressRandomizePrompt bool, done func()) {
+	if templateTargetLocked(sheet) {
+		return
+	}
+	traits := cloneRows(sheet.Traits.Table, t.Traits.Table.RootRows())
+	skills := cloneRows(sheet.Skills.Table, t.Skills.Table.RootRows())
+	spells := cloneRows(sheet.Spells.Table, t.Spells.Table.RootRows())
+	equipment := cloneRows(sheet.CarriedEquipment.Table, t.Equipment.Table.RootRows())
+	notes := cloneRows(sheet.Notes.Table, t.Notes.Table.RootRows())
+	byRow := make(map[any]*templatePickerStep)
+	always := func() bool { return true }
+	steps := collectPickerSteps(nil, byRow, always, ExtractNodeDataFromList(traits)...)
+	steps = collectPickerSteps(steps, byRow, always, ExtractNodeDataFromList(skills)...)
+	steps = collectPickerSteps(steps, byRow, always, ExtractNodeDataFromList(spells)...)
+	finish := func() {
+		// The sheet may have been locked while the wizard was open.
+		if templateTargetLocked(sheet) {
+			return
+		}
+		traits = applyPickerSteps(traits, byRow)
+		skills = applyPickerSteps(skills, byRow)
+		spells = applyPickerSteps(spells, byRow)
+		t.finishApplyingTemplateToSheet(sheet, suppressRandomizePrompt, traits, skills, spells, equipment, notes)
+		if done != nil {
+			done()
+		}
+	}
+	if len(steps) == 0 {
+		finish()
+		return
+	}
+	showTemplateWizard(t, sheet, steps, finish)
+}
+
+// templateTargetLocked returns true, after saying why, if the sheet is locked and so may not have a template applied.
+func templateTargetLocked(sheet *Sheet) bool {
+	if !sheet.entity.Locked {
+		return false
+	}
+	unison.ErrorDialogWithMessage(fmt.Sprintf(i18n.Text("Unable to apply the template to %s"), sheet.Title()),
+		gurps.SheetLocked())
+	return true
+}
+
+func (t *Template) finishApplyingTemplateToSheet(sheet *Sheet, suppressRandomizePrompt bool,
+	traits []*Node[*gurps.Trait], skills []*Node[*gurps.Skill], spells []*Node[*gurps.Spell],
+	equipment []*Node[*gurps.Equipment], notes []*Node[*gurps.Note]) {
 	var undo *unison.UndoEdit[*ApplyTemplateUndoEditData]
 	mgr := unison.UndoManagerFor(sheet)
 	if mgr != nil {
@@ -377,21 +424,6 @@ Disable your character's existing Ancestry (%s)?`),
 			}
 		}
 	}
-	traits := cloneRows(sheet.Traits.Table, t.Traits.Table.RootRows())
-	skills := cloneRows(sheet.Skills.Table, t.Skills.Table.RootRows())
-	spells := cloneRows(sheet.Spells.Table, t.Spells.Table.RootRows())
-	equipment := cloneRows(sheet.CarriedEquipment.Table, t.Equipment.Table.RootRows())
-	notes := cloneRows(sheet.Notes.Table, t.Notes.Table.RootRows())
-	var abort bool
-	if traits, abort = processPickerRows(traits); abort {
-		return false
-	}
-	if skills, abort = processPickerRows(skills); abort {
-		return false
-	}
-	if spells, abort = processPickerRows(spells); abort {
-		return false
-	}
 	appendRows(sheet.Traits.Table, traits)
 	appendRows(sheet.Skills.Table, skills)
 	appendRows(sheet.Spells.Table, spells)
@@ -424,12 +456,11 @@ Disable your character's existing Ancestry (%s)?`),
 		if undo.AfterData, err = NewApplyTemplateUndoEditData(sheet); err != nil {
 			errs.Log(err)
 		} else {
-			mgr.Add(undo)
+			addUndo(mgr, undo)
 		}
 	}
 	sheet.Window().ToFront()
 	sheet.RequestFocus()
-	return true
 }
 
 func updateRandomizedProfileFieldsWithoutUndo(sheet *Sheet) {
@@ -597,31 +628,6 @@ func mergeSpellRows(spellTable *unison.Table[*Node[*gurps.Spell]], spellNodes, r
 	return replacements
 }
 
-func rawPoints(child any) fxp.Int {
-	switch nc := child.(type) {
-	case *gurps.Skill:
-		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == picker.Points &&
-			nc.TemplatePicker.Qualifier.Compare == criteria.EqualsNumber {
-			return nc.TemplatePicker.Qualifier.Qualifier
-		}
-		return nc.RawPoints()
-	case *gurps.Spell:
-		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == picker.Points &&
-			nc.TemplatePicker.Qualifier.Compare == criteria.EqualsNumber {
-			return nc.TemplatePicker.Qualifier.Qualifier
-		}
-		return nc.RawPoints()
-	case *gurps.Trait:
-		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == picker.Points &&
-			nc.TemplatePicker.Qualifier.Compare == criteria.EqualsNumber {
-			return nc.TemplatePicker.Qualifier.Qualifier
-		}
-		return nc.AdjustedPoints()
-	default:
-		return 0
-	}
-}
-
 func (t *Template) installNewItemCmdHandlers(itemID, containerID int, creator itemCreator) {
 	variant := NoItemVariant
 	if containerID == -1 {
@@ -736,6 +742,17 @@ func (t *Template) save(forceSaveAs bool) bool {
 	return success
 }
 
+func (t *Template) autosaver() func(filePath string) error {
+	return t.template.Save
+}
+
+func (t *Template) markRecovered(filePath string, untitled bool) {
+	t.path = filePath
+	t.needsSaveAsPrompt = untitled
+	t.hash = 0 // Force it to be recognized as unsaved
+	UpdateTitleForDockable(t)
+}
+
 func (t *Template) createLists() {
 	h, v := t.scroll.Position()
 	var refocusOnKey string
@@ -932,20 +949,49 @@ func (t *templateTablesUndoData) Apply() {
 	t.notes.Apply()
 }
 
+func (t *Template) countTags(counts map[string]int) {
+	gurps.CountTagsInProvider(counts, t.template)
+}
+
+func (t *Template) renameTag(from, to string) {
+	t.updateTables(i18n.Text("Rename Tag"), func() { gurps.RenameTagInProvider(from, to, t.template) })
+}
+
+func (t *Template) retargetSources(replacements map[gurps.Source]gurps.Source) {
+	t.updateTables(i18n.Text("Merge Library Duplicates"), func() { gurps.RetargetSources(t.template, replacements) })
+}
+
+func (t *Template) searchNodes(hits []*gurps.SearchHit, text string) []*gurps.SearchHit {
+	return gurps.SearchNodesInProvider(hits, t.BackingFilePath(), text, t.template)
+}
+
+func (t *Template) locateNode(id tid.TID) bool {
+	return locateTableRowByID(t.Traits.Table, id) ||
+		locateTableRowByID(t.Skills.Table, id) ||
+		locateTableRowByID(t.Spells.Table, id) ||
+		locateTableRowByID(t.Equipment.Table, id) ||
+		locateTableRowByID(t.Notes.Table, id)
+}
+
 func (t *Template) syncWithAllSources() {
+	t.updateTables(syncWithSourceAction.Title, t.template.SyncWithLibrarySources)
+}
+
+// updateTables calls update to modify the list data, then refreshes the tables and records an undo for the change.
+func (t *Template) updateTables(editName string, update func()) {
 	var undo *unison.UndoEdit[*templateTablesUndoData]
 	mgr := unison.UndoManagerFor(t)
 	if mgr != nil {
 		undo = &unison.UndoEdit[*templateTablesUndoData]{
 			ID:         unison.NextUndoID(),
-			EditName:   syncWithSourceAction.Title,
+			EditName:   editName,
 			UndoFunc:   func(e *unison.UndoEdit[*templateTablesUndoData]) { e.BeforeData.Apply() },
 			RedoFunc:   func(e *unison.UndoEdit[*templateTablesUndoData]) { e.AfterData.Apply() },
 			AbsorbFunc: func(_ *unison.UndoEdit[*templateTablesUndoData], _ unison.Undoable) bool { return false },
 			BeforeData: newTemplateTablesUndoData(t),
 		}
 	}
-	t.template.SyncWithLibrarySources()
+	update()
 	t.Traits.Table.SyncToModel()
 	t.Skills.Table.SyncToModel()
 	t.Spells.Table.SyncToModel()
@@ -953,7 +999,7 @@ func (t *Template) syncWithAllSources() {
 	t.Notes.Table.SyncToModel()
 	if mgr != nil && undo != nil {
 		undo.AfterData = newTemplateTablesUndoData(t)
-		mgr.Add(undo)
+		addUndo(mgr, undo)
 	}
 	t.Rebuild(true)
 }
@@ -12,7 +12,6 @@ package ux
 import (
 	"fmt"
 
-	"github.com/richardwilkes/gcs/v5/model/fonts"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/picker"
@@ -23,195 +22,125 @@ import (
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/unison"
 	"github.com/richardwilkes/unison/enums/align"
-	"github.com/richardwilkes/unison/enums/behavior"
-	"github.com/richardwilkes/unison/enums/check"
-	"github.com/richardwilkes/unison/enums/paintstyle"
-	"github.com/richardwilkes/unison/enums/side"
 )
 
-func processPickerRows[T gurps.NodeTypes](rows []*Node[T]) (revised []*Node[T], abort bool) {
-	for _, one := range ExtractNodeDataFromList(rows) {
-		result, cancel := processPickerRow(one)
-		if cancel {
-			return nil, true
+// templatePickerStep holds one of the choices a template asks the user to make when it is applied.
+type templatePickerStep struct {
+	title     string
+	notes     string
+	tp        *gurps.TemplatePicker
+	selected  []bool
+	include   bool
+	reachable func() bool
+	points    func(index int) fxp.Int
+	addRows   func(list *unison.Panel, callback func()) []*unison.CheckBox
+}
+
+// collectPickerSteps appends a step for each container within rows that has a template picker, recording them in byRow
+// as well. Steps nested within the children of another step are only reachable when those children are included.
+func collectPickerSteps[T gurps.NodeTypes](steps []*templatePickerStep, byRow map[any]*templatePickerStep, reachable func() bool, rows ...T) []*templatePickerStep {
+	for _, row := range rows {
+		n := gurps.AsNode(row)
+		if !n.Container() {
+			continue
 		}
-		for _, replacement := range result {
-			revised = append(revised, NewNodeLike(rows[0], replacement))
+		children := n.NodeChildren()
+		tpp, ok := n.(gurps.TemplatePickerProvider)
+		if !ok || tpp.TemplatePickerData().IsZero() {
+			steps = collectPickerSteps(steps, byRow, reachable, children...)
+			continue
+		}
+		step := &templatePickerStep{
+			title:     row.String(),
+			tp:        tpp.TemplatePickerData(),
+			selected:  make([]bool, len(children)),
+			reachable: reachable,
+			points:    func(index int) fxp.Int { return gurps.TemplatePickerPoints(children[index]) },
+			addRows: func(list *unison.Panel, callback func()) []*unison.CheckBox {
+				boxes := make([]*unison.CheckBox, 0, len(children))
+				for _, child := range children {
+					boxes = addPickerRow(list, child, callback, boxes)
+				}
+				return boxes
+			},
+		}
+		if notesCapable, hasNotes := any(row).(interface{ Notes() string }); hasNotes {
+			step.notes = notesCapable.Notes()
+		}
+		byRow[row] = step
+		steps = append(steps, step)
+		for i, child := range children {
+			steps = collectPickerSteps(steps, byRow, func() bool { return step.reachable() && step.includes(i) }, child)
 		}
 	}
-	return revised, false
+	return steps
 }
 
-func processPickerRow[T gurps.NodeTypes](row T) (revised []T, abort bool) {
-	n := gurps.AsNode(row)
-	if !n.Container() {
-		return []T{row}, false
-	}
-	children := n.NodeChildren()
-	tpp, ok := n.(gurps.TemplatePickerProvider)
-	if !ok || tpp.TemplatePickerData().IsZero() {
-		rowChildren := make([]T, 0, len(children))
-		for _, child := range children {
-			var result []T
-			result, abort = processPickerRow(child)
-			if abort {
-				return nil, true
-			}
-			rowChildren = append(rowChildren, result...)
-		}
-		n.SetChildren(rowChildren)
-		SetParents(rowChildren, row)
-		return []T{row}, false
+// includes returns true if the child at the given index will be included when the template is applied.
+func (s *templatePickerStep) includes(index int) bool {
+	switch s.tp.Type {
+	case picker.Allocate:
+		return true
+	case picker.Optional:
+		return s.include
+	default:
+		return s.selected[index]
 	}
-	tp := tpp.TemplatePickerData()
-
-	list := unison.NewPanel()
-	list.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing)))
-	list.SetLayout(&unison.FlexLayout{
-		Columns:  2,
-		HSpacing: unison.StdHSpacing,
-		VSpacing: unison.StdVSpacing,
-	})
+}
 
-	progress := unison.NewLabel()
-	progressBackground := pickerMatchStateColor(tp.Qualifier.Matches(0))
-	progress.SetBorder(unison.NewCompoundBorder(
-		unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}),
-		unison.NewEmptyBorder(geom.NewHorizontalInsets(unison.StdHSpacing)),
-	))
-	progress.Side = side.Right
-	progress.OnBackgroundInk = progressBackground.On()
-	progress.DrawCallback = func(gc *unison.Canvas, _ geom.Rect) {
-		if tp.Type == picker.NotApplicable {
-			return
-		}
-		r := progress.ContentRect(true)
-		r.Y += unison.StdVSpacing * 2
-		r.Height -= unison.StdVSpacing * 2
-		gc.DrawRoundedRect(r, geom.NewUniformSize(8), progressBackground.Paint(gc, r, paintstyle.Fill))
-		progress.DefaultDraw(gc, r)
-	}
-	boxes := make([]*unison.CheckBox, 0, len(children))
-	var dialog *unison.Dialog
-	callback := func() {
-		var total fxp.Int
-		for i, box := range boxes {
-			if box.State == check.On {
-				switch tp.Type {
-				case picker.NotApplicable:
-				case picker.Count:
-					total += fxp.One
-				case picker.Points:
-					total += rawPoints(children[i])
-				}
-			}
-		}
-		matches := tp.Qualifier.Matches(total)
-		dialog.Button(unison.ModalResponseOK).SetEnabled(matches)
-		if tp.Type != picker.NotApplicable {
-			var img *unison.SVG
-			if matches {
-				img = unison.CheckmarkSVG
-			} else {
-				img = svg.Not
-			}
-			size := max(progress.Font.Baseline()-2, 6)
-			progress.Drawable = &unison.DrawableSVG{
-				SVG:  img,
-				Size: geom.NewSize(size, size),
+// total returns the count or points of the included children, as appropriate for the type of picker.
+func (s *templatePickerStep) total() fxp.Int {
+	var total fxp.Int
+	for i := range s.selected {
+		if s.includes(i) {
+			switch s.tp.Type {
+			case picker.Count:
+				total += fxp.One
+			case picker.Points, picker.Allocate:
+				total += s.points(i)
+			default:
 			}
-			progressBackground = pickerMatchStateColor(matches)
-			progress.OnBackgroundInk = progressBackground.On()
-			progress.SetTitle(total.Comma())
-			progress.MarkForLayoutRecursivelyUpward()
-			progress.MarkForRedraw()
 		}
 	}
-	for _, child := range children {
-		boxes = addPickerRow(list, child, callback, boxes)
-	}
+	return total
+}
 
-	scroll := unison.NewScrollPanel()
-	scroll.SetBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{}, geom.NewUniformInsets(1), false))
-	scroll.SetContent(list, behavior.Fill, behavior.Fill)
-	scroll.BackgroundInk = unison.ThemeSurface
-	scroll.SetLayoutData(&unison.FlexLayoutData{
-		HAlign: align.Fill,
-		VAlign: align.Fill,
-		HSpan:  2,
-		HGrab:  true,
-		VGrab:  true,
-	})
+// satisfied returns true if the choices made meet the requirements of the picker.
+func (s *templatePickerStep) satisfied() bool {
+	return !templatePickerHasQualifier(s.tp.Type) || s.tp.Qualifier.Matches(s.total())
+}
 
-	panel := unison.NewPanel()
-	panel.SetLayout(&unison.FlexLayout{
-		Columns:  2,
-		HSpacing: unison.StdHSpacing,
-		VSpacing: unison.StdVSpacing,
-		HAlign:   align.Fill,
-		VAlign:   align.Fill,
-	})
-	label := unison.NewLabel()
-	label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
-	label.SetTitle(row.String())
-	panel.AddChild(label)
-	if notesCapable, hasNotes := any(row).(interface{ Notes() string }); hasNotes {
-		if notes := notesCapable.Notes(); notes != "" {
-			label = unison.NewLabel()
-			label.Font = fonts.FieldSecondary
-			label.SetTitle(notes)
-			label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
-			panel.AddChild(label)
+// applyPickerSteps returns the rows that result from applying the choices made in the steps.
+func applyPickerSteps[T gurps.NodeTypes](rows []*Node[T], byRow map[any]*templatePickerStep) []*Node[T] {
+	var revised []*Node[T]
+	for _, one := range ExtractNodeDataFromList(rows) {
+		for _, replacement := range applyPickerStepsToRow(one, byRow) {
+			revised = append(revised, NewNodeLike(rows[0], replacement))
 		}
 	}
-	label = unison.NewLabel()
-	label.SetTitle(tp.String())
-	label.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
-	label.SetLayoutData(&unison.FlexLayoutData{
-		HAlign: align.Start,
-		VAlign: align.Middle,
-	})
-	panel.AddChild(label)
-	progress.SetLayoutData(&unison.FlexLayoutData{
-		HAlign: align.End,
-		VAlign: align.Middle,
-	})
-	panel.AddChild(progress)
-	panel.AddChild(scroll)
+	return revised
+}
 
-	var err error
-	dialog, err = unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
-		unison.DefaultDialogTheme.QuestionIconInk, panel,
-		[]*unison.DialogButtonInfo{
-			unison.NewCancelButtonInfo(),
-			{
-				Title:        i18n.Text("Override"),
-				ResponseCode: unison.ModalResponseUserBase,
-			},
-			unison.NewOKButtonInfo(),
-		})
-	if err != nil {
-		errs.Log(err)
-		return nil, true
-	}
-	callback()
-	if dialog.RunModal() == unison.ModalResponseCancel {
-		return nil, true
+func applyPickerStepsToRow[T gurps.NodeTypes](row T, byRow map[any]*templatePickerStep) []T {
+	n := gurps.AsNode(row)
+	if !n.Container() {
+		return []T{row}
 	}
-
+	children := n.NodeChildren()
 	rowChildren := make([]T, 0, len(children))
-	for i, box := range boxes {
-		if box.State == check.On {
-			var result []T
-			result, abort = processPickerRow(children[i])
-			if abort {
-				return nil, true
-			}
-			rowChildren = append(rowChildren, result...)
+	step, hasStep := byRow[row]
+	for i, child := range children {
+		if !hasStep || step.includes(i) {
+			rowChildren = append(rowChildren, applyPickerStepsToRow(child, byRow)...)
 		}
 	}
+	if !hasStep {
+		n.SetChildren(rowChildren)
+		SetParents(rowChildren, row)
+		return []T{row}
+	}
 	SetParents(rowChildren, n.Parent())
-	return rowChildren, false
+	return rowChildren
 }
 
 func pickerMatchStateColor(matches bool) unison.Color {
@@ -257,18 +186,21 @@ func addPickerRow[T gurps.NodeTypes](parent *unison.Panel, row T, callback func(
 		pageRefHighlight = actual.PageRefHighlight
 	}
 	if pageRef != "" {
-		if pageRefs := ExtractPageReferences(pageRef); len(pageRefs) > 0 {
+		if pageRefs := gurps.ExtractPageReferences(pageRef); len(pageRefs) > 0 {
 			var tooltip string
 			var icon *unison.DrawableSVG
 			title, img := convertLinksForPageRef(pageRefs[0])
 			if img != nil {
-				title = ""
+				var target string
+				if _, target = gurps.ParsePageRefLink(pageRefs[0]); target == pageRefs[0] {
+					title = ""
+				}
 				height := unison.DefaultLinkTheme.Font.Baseline()
 				icon = &unison.DrawableSVG{
 					SVG:  img,
 					Size: geom.NewSize(height, height).Ceil(),
 				}
-				tooltip = pageRefs[0]
+				tooltip = target
 			}
 			link := unison.NewLink(title, tooltip, "", unison.DefaultLinkTheme, func(_ unison.Paneler, _ string) {
 				OpenPageReference(pageRefs[0], pageRefHighlight, nil)
@@ -297,7 +229,7 @@ func addPickerRow[T gurps.NodeTypes](parent *unison.Panel, row T, callback func(
 
 func updatePickerCheckBoxTitle[T gurps.NodeTypes](checkBox *unison.CheckBox, row T) {
 	title := row.String()
-	points := rawPoints(row)
+	points := gurps.TemplatePickerPoints(row)
 	if points != 0 {
 		pointsLabel := i18n.Text("points")
 		if points == fxp.One {
@@ -0,0 +1,356 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/picker"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/check"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+	"github.com/richardwilkes/unison/enums/side"
+	"github.com/richardwilkes/unison/enums/weight"
+)
+
+var (
+	_ unison.Dockable  = &TemplateWizardDockable{}
+	_ unison.TabCloser = &TemplateWizardDockable{}
+)
+
+// TemplateWizardDockable walks the user through the choices a template asks for before it is applied to a sheet.
+type TemplateWizardDockable struct {
+	unison.Panel
+	template       *Template
+	sheet          *Sheet
+	steps          []*templatePickerStep
+	current        int
+	finish         func()
+	content        *unison.Panel
+	stepLabel      *unison.Label
+	backButton     *unison.Button
+	overrideButton *unison.Button
+	nextButton     *unison.Button
+}
+
+func showTemplateWizard(t *Template, sheet *Sheet, steps []*templatePickerStep, finish func()) {
+	d := &TemplateWizardDockable{
+		template: t,
+		sheet:    sheet,
+		steps:    steps,
+		finish:   finish,
+	}
+	d.Self = d
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+	d.current = d.adjacentStep(-1, 1)
+
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing * 2)))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		HAlign:   align.Fill,
+		VAlign:   align.Fill,
+	})
+	d.content.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.content)
+	d.AddChild(d.createButtonBar())
+	PlaceInDock(d, dgroup.Editors, false)
+	d.rebuild()
+}
+
+func (d *TemplateWizardDockable) createButtonBar() *unison.Panel {
+	bar := unison.NewPanel()
+	bar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Top: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	d.stepLabel = unison.NewLabel()
+	d.stepLabel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Start,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	bar.AddChild(d.stepLabel)
+	cancelButton := unison.NewButton()
+	cancelButton.SetTitle(i18n.Text("Cancel"))
+	cancelButton.ClickCallback = func() { d.AttemptClose() }
+	bar.AddChild(cancelButton)
+	d.backButton = unison.NewButton()
+	d.backButton.SetTitle(i18n.Text("Back"))
+	d.backButton.ClickCallback = func() {
+		if prev := d.adjacentStep(d.current, -1); prev != -1 {
+			d.current = prev
+			d.rebuild()
+		}
+	}
+	bar.AddChild(d.backButton)
+	d.overrideButton = unison.NewButton()
+	d.overrideButton.SetTitle(i18n.Text("Override"))
+	d.overrideButton.Tooltip = newWrappedTooltip(
+		i18n.Text("Continue, even though the choices made don't meet the requirements"))
+	d.overrideButton.ClickCallback = d.advance
+	bar.AddChild(d.overrideButton)
+	d.nextButton = unison.NewButton()
+	d.nextButton.ClickCallback = d.advance
+	bar.AddChild(d.nextButton)
+	bar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	bar.SetLayout(&unison.FlexLayout{
+		Columns:  len(bar.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		VAlign:   align.Middle,
+	})
+	return bar
+}
+
+// adjacentStep returns the index of the next reachable step in the given direction from the step at index from, or -1
+// if there isn't one.
+func (d *TemplateWizardDockable) adjacentStep(from, direction int) int {
+	for i := from + direction; i >= 0 && i < len(d.steps); i += direction {
+		if d.steps[i].reachable() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *TemplateWizardDockable) advance() {
+	if next := d.adjacentStep(d.current, 1); next != -1 {
+		d.current = next
+		d.rebuild()
+		return
+	}
+	if !d.AttemptClose() {
+		return
+	}
+	for _, one := range AllDockables() {
+		if one == unison.Dockable(d.sheet) {
+			d.finish()
+			return
+		}
+	}
+}
+
+func (d *TemplateWizardDockable) rebuild() {
+	d.content.RemoveAllChildren()
+	step := d.steps[d.current]
+
+	label := unison.NewLabel()
+	label.Font = &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			desc := unison.DefaultLabelTheme.Font.Descriptor()
+			desc.Weight = weight.Bold
+			return desc
+		},
+	}
+	label.SetTitle(step.title)
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	d.content.AddChild(label)
+	if step.notes != "" {
+		label = unison.NewLabel()
+		label.Font = fonts.FieldSecondary
+		label.SetTitle(step.notes)
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+		d.content.AddChild(label)
+	}
+
+	var boxes []*unison.CheckBox
+	var includeBox *unison.CheckBox
+	if step.tp.Type == picker.Optional {
+		includeBox = unison.NewCheckBox()
+		includeBox.SetTitle(i18n.Text("Include this section"))
+		includeBox.State = check.FromBool(step.include)
+		includeBox.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+		includeBox.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+		d.content.AddChild(includeBox)
+	} else {
+		label = unison.NewLabel()
+		label.SetTitle(step.tp.String())
+		label.SetBorder(unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}))
+		label.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Start,
+			VAlign: align.Middle,
+		})
+		d.content.AddChild(label)
+	}
+	progress := newPickerProgressLabel()
+	if includeBox == nil {
+		d.content.AddChild(progress)
+	}
+
+	list := unison.NewPanel()
+	list.SetBorder(unison.NewEmptyBorder(geom.NewUniformInsets(unison.StdHSpacing)))
+	list.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	callback := func() {
+		switch step.tp.Type {
+		case picker.Count, picker.Points:
+			for i, box := range boxes {
+				step.selected[i] = box.State == check.On
+			}
+		case picker.Optional:
+			step.include = includeBox.State == check.On
+			for _, box := range boxes {
+				box.State = check.FromBool(step.include)
+				box.MarkForRedraw()
+			}
+		default:
+		}
+		if templatePickerHasQualifier(step.tp.Type) {
+			updatePickerProgressLabel(progress, step.total(), step.satisfied())
+		}
+		d.updateButtons()
+	}
+	boxes = step.addRows(list, callback)
+	for i, box := range boxes {
+		switch step.tp.Type {
+		case picker.Allocate:
+			box.State = check.On
+			box.SetEnabled(false)
+		case picker.Optional:
+			box.State = check.FromBool(step.include)
+			box.SetEnabled(false)
+		default:
+			box.State = check.FromBool(step.selected[i])
+		}
+	}
+	if includeBox != nil {
+		includeBox.ClickCallback = callback
+	}
+
+	scroll := unison.NewScrollPanel()
+	scroll.SetBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{}, geom.NewUniformInsets(1), false))
+	scroll.SetContent(list, behavior.Fill, behavior.Fill)
+	scroll.BackgroundInk = unison.ThemeSurface
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HSpan:  2,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	d.content.AddChild(scroll)
+	callback()
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *TemplateWizardDockable) updateButtons() {
+	total := 0
+	index := 0
+	for i, step := range d.steps {
+		if step.reachable() {
+			total++
+			if i == d.current {
+				index = total
+			}
+		}
+	}
+	d.stepLabel.SetTitle(fmt.Sprintf(i18n.Text("Choice %d of %d"), index, total))
+	satisfied := d.steps[d.current].satisfied()
+	d.backButton.SetEnabled(d.adjacentStep(d.current, -1) != -1)
+	d.overrideButton.SetEnabled(!satisfied)
+	d.nextButton.SetEnabled(satisfied)
+	if d.adjacentStep(d.current, 1) == -1 {
+		d.nextButton.SetTitle(i18n.Text("Finish"))
+	} else {
+		d.nextButton.SetTitle(i18n.Text("Next"))
+	}
+	d.stepLabel.Parent().MarkForLayoutAndRedraw()
+}
+
+func newPickerProgressLabel() *unison.Label {
+	progress := unison.NewLabel()
+	progress.SetBorder(unison.NewCompoundBorder(
+		unison.NewEmptyBorder(geom.Insets{Top: unison.StdVSpacing * 2}),
+		unison.NewEmptyBorder(geom.NewHorizontalInsets(unison.StdHSpacing)),
+	))
+	progress.Side = side.Right
+	progress.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.End,
+		VAlign: align.Middle,
+	})
+	return progress
+}
+
+func updatePickerProgressLabel(progress *unison.Label, total fxp.Int, matches bool) {
+	background := pickerMatchStateColor(matches)
+	var img *unison.SVG
+	if matches {
+		img = unison.CheckmarkSVG
+	} else {
+		img = svg.Not
+	}
+	size := max(progress.Font.Baseline()-2, 6)
+	progress.Drawable = &unison.DrawableSVG{
+		SVG:  img,
+		Size: geom.NewSize(size, size),
+	}
+	progress.OnBackgroundInk = background.On()
+	progress.DrawCallback = func(gc *unison.Canvas, _ geom.Rect) {
+		r := progress.ContentRect(true)
+		r.Y += unison.StdVSpacing * 2
+		r.Height -= unison.StdVSpacing * 2
+		gc.DrawRoundedRect(r, geom.NewUniformSize(8), background.Paint(gc, r, paintstyle.Fill))
+		progress.DefaultDraw(gc, r)
+	}
+	progress.SetTitle(total.Comma())
+	progress.MarkForLayoutRecursivelyUpward()
+	progress.MarkForRedraw()
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *TemplateWizardDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return d.template.TitleIcon(suggestedSize)
+}
+
+// Title implements unison.Dockable.
+func (d *TemplateWizardDockable) Title() string {
+	return fmt.Sprintf(i18n.Text("Apply %s"), d.template.Title())
+}
+
+// Tooltip implements unison.Dockable.
+func (d *TemplateWizardDockable) Tooltip() string {
+	return fmt.Sprintf(i18n.Text("Applying %s to %s"), d.template.Title(), d.sheet.Title())
+}
+
+// Modified implements unison.Dockable.
+func (d *TemplateWizardDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *TemplateWizardDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *TemplateWizardDockable) AttemptClose() bool {
+	return AttemptCloseForDockable(d)
+}
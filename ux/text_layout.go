@@ -0,0 +1,73 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/richardwilkes/gcs/v5/model/textlayout"
+	"github.com/richardwilkes/unison"
+)
+
+// newTextWrappedLines is a replacement for unison.NewTextWrappedLines that can also break lines of Chinese and Japanese
+// text and that puts each line into visual order, so that right-to-left scripts are drawn correctly.
+func newTextWrappedLines(text string, decoration *unison.TextDecoration, width float32) []*unison.Text {
+	lines := wrapTextLines(text, decoration, width)
+	result := make([]*unison.Text, len(lines))
+	for i, line := range lines {
+		result[i] = unison.NewText(textlayout.Visual(line), decoration)
+	}
+	return result
+}
+
+// wrapTextLines splits the text on any line feeds found and then wraps each line to the given width. The lines are
+// returned in logical order, with any whitespace at the point each was broken left at its end.
+func wrapTextLines(text string, decoration *unison.TextDecoration, width float32) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		runes := []rune(paragraph)
+		if len(runes) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		for start := 0; start < len(runes); {
+			rest := unison.NewTextFromRunes(runes[start:], decoration)
+			if rest.Width() <= width {
+				lines = append(lines, string(runes[start:]))
+				break
+			}
+			end := start + rest.RuneIndexForPosition(width)
+			for end > start && rest.PositionForRuneIndex(end-start) > width {
+				end--
+			}
+			// Whitespace past the edge doesn't need to fit
+			for end < len(runes) && unicode.IsSpace(runes[end]) {
+				end++
+			}
+			brk := end
+			if brk < len(runes) {
+				for brk > start && !textlayout.CanBreakBefore(runes[brk-1], runes[brk]) {
+					brk--
+				}
+			}
+			if brk == start {
+				// Nothing fits, so take the first piece that can't be broken, along with any whitespace after it
+				brk++
+				for brk < len(runes) && !textlayout.CanBreakBefore(runes[brk-1], runes[brk]) {
+					brk++
+				}
+			}
+			lines = append(lines, string(runes[start:brk]))
+			start = brk
+		}
+	}
+	return lines
+}
@@ -10,6 +10,10 @@
 package ux
 
 import (
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/threshold"
 	"github.com/richardwilkes/gcs/v5/svg"
@@ -122,6 +126,16 @@ func (p *thresholdSettingsPanel) createContent() *unison.Panel {
 		content.AddChild(p.createOpCheckBox(op))
 	}
 
+	for _, effect := range p.threshold.Effects {
+		content.AddChild(NewFieldLeadingLabel(i18n.Text("Adjust"), false))
+		content.AddChild(p.createEffect(effect))
+	}
+	content.AddChild(unison.NewPanel())
+	addButton := unison.NewSVGButton(svg.CircledAdd)
+	addButton.Tooltip = newWrappedTooltip(i18n.Text("Add an attribute adjustment that applies while this threshold is in effect"))
+	addButton.ClickCallback = p.addEffect
+	content.AddChild(addButton)
+
 	text = i18n.Text("Explanation")
 	content.AddChild(NewFieldLeadingLabel(text, false))
 	addScriptField(content, p.pool.dockable.targetMgr, p.threshold.KeyPrefix+"explanation", text,
@@ -149,3 +163,61 @@ func (p *thresholdSettingsPanel) createOpCheckBox(op threshold.Op) *CheckBox {
 	c.Tooltip = newWrappedTooltip(op.AltString())
 	return c
 }
+
+func (p *thresholdSettingsPanel) createEffect(effect *gurps.PoolThresholdEffect) *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+	})
+	text := i18n.Text("Attribute ID")
+	field := NewStringField(p.pool.dockable.targetMgr, effect.KeyPrefix+"attr", text,
+		func() string { return effect.AttrID },
+		func(s string) { effect.AttrID = strings.TrimSpace(strings.ToLower(s)) })
+	field.SetMinimumTextWidthUsing(prototypeMinIDWidth)
+	field.Tooltip = newWrappedTooltip(i18n.Text("The ID of the attribute to adjust while this threshold is in effect"))
+	panel.AddChild(field)
+	amount := NewDecimalField(p.pool.dockable.targetMgr, effect.KeyPrefix+"amount", i18n.Text("Adjustment"),
+		func() fxp.Int { return effect.Amount },
+		func(value fxp.Int) { effect.Amount = value }, fxp.Min, fxp.Max, true, false)
+	amount.Tooltip = newWrappedTooltip(i18n.Text("The amount to add to the attribute while this threshold is in effect"))
+	panel.AddChild(amount)
+	deleteButton := unison.NewSVGButton(svg.Trash)
+	deleteButton.Tooltip = newWrappedTooltip(i18n.Text("Remove attribute adjustment"))
+	deleteButton.ClickCallback = func() {
+		p.editEffects(i18n.Text("Remove Attribute Adjustment"), func() {
+			if i := slices.Index(p.threshold.Effects, effect); i != -1 {
+				p.threshold.Effects = slices.Delete(p.threshold.Effects, i, i+1)
+			}
+		})
+	}
+	panel.AddChild(deleteButton)
+	return panel
+}
+
+func (p *thresholdSettingsPanel) addEffect() {
+	p.editEffects(i18n.Text("Add Attribute Adjustment"), func() {
+		p.threshold.Effects = append(p.threshold.Effects,
+			&gurps.PoolThresholdEffect{KeyPrefix: p.pool.dockable.targetMgr.NextPrefix()})
+	})
+}
+
+func (p *thresholdSettingsPanel) editEffects(name string, edit func()) {
+	pool := p.pool
+	undo := &unison.UndoEdit[[]*gurps.PoolThreshold]{
+		ID:       unison.NextUndoID(),
+		EditName: name,
+		UndoFunc: func(e *unison.UndoEdit[[]*gurps.PoolThreshold]) {
+			pool.applyThresholds(e.BeforeData)
+		},
+		RedoFunc: func(e *unison.UndoEdit[[]*gurps.PoolThreshold]) {
+			pool.applyThresholds(e.AfterData)
+		},
+		AbsorbFunc: func(_ *unison.UndoEdit[[]*gurps.PoolThreshold], _ unison.Undoable) bool { return false },
+	}
+	undo.BeforeData = clonePoolThresholds(pool.def.Thresholds)
+	edit()
+	undo.AfterData = clonePoolThresholds(pool.def.Thresholds)
+	addUndo(pool.dockable.UndoManager(), undo)
+	pool.dockable.sync()
+}
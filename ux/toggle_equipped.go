@@ -11,6 +11,7 @@ package ux
 
 import (
 	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/eqploc"
 	"github.com/richardwilkes/toolbox/v2/i18n"
 	"github.com/richardwilkes/unison"
 )
@@ -36,18 +37,18 @@ func (a *toggleEquippedList) Finish() {
 
 type equippedAdjuster struct {
 	Target   *gurps.Equipment
-	Equipped bool
+	Location eqploc.Location
 }
 
 func newEquippedAdjuster(target *gurps.Equipment) *equippedAdjuster {
 	return &equippedAdjuster{
 		Target:   target,
-		Equipped: target.Equipped,
+		Location: target.Location,
 	}
 }
 
 func (a *equippedAdjuster) Apply() {
-	a.Target.Equipped = a.Equipped
+	a.Target.Location = a.Location
 }
 
 func canToggleEquipped(table *unison.Table[*Node[*gurps.Equipment]]) bool {
@@ -65,13 +66,13 @@ func toggleEquipped(owner Rebuildable, table *unison.Table[*Node[*gurps.Equipmen
 	for _, row := range table.SelectedRows(false) {
 		if eqp := row.Data(); eqp != nil {
 			before.List = append(before.List, newEquippedAdjuster(eqp))
-			eqp.Equipped = !eqp.Equipped
+			eqp.SetEquipped(!eqp.IsEquipped())
 			after.List = append(after.List, newEquippedAdjuster(eqp))
 		}
 	}
 	if len(before.List) > 0 {
 		if mgr := unison.UndoManagerFor(table); mgr != nil {
-			mgr.Add(&unison.UndoEdit[*toggleEquippedList]{
+			addUndo(mgr, &unison.UndoEdit[*toggleEquippedList]{
 				ID:         unison.NextUndoID(),
 				EditName:   i18n.Text("Toggle Equipped"),
 				UndoFunc:   func(edit toggleEquippedUndoEdit) { edit.BeforeData.Apply() },
@@ -0,0 +1,149 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/study"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+type trainingLogEntry struct {
+	kind    string
+	name    string
+	tracker gurps.StudyTracker
+}
+
+func warnStudyPointsNeeded() {
+	unison.WarningDialogWithMessage(i18n.Text("Not enough points have been earned through study"),
+		i18n.Text("Record more study for the item, or change the study setting in the sheet settings, to raise it."))
+}
+
+func newStudyProgressBar(p gurps.StudyProgress) *unison.ProgressBar {
+	bar := unison.NewProgressBar(1)
+	updateStudyProgressBar(bar, p)
+	bar.SetLayoutData(&unison.FlexLayoutData{
+		MinSize: geom.Size{Width: 100},
+		HAlign:  align.Fill,
+		VAlign:  align.Middle,
+		HGrab:   true,
+	})
+	return bar
+}
+
+func updateStudyProgressBar(bar *unison.ProgressBar, p gurps.StudyProgress) {
+	bar.SetCurrent(p.NextPointFraction())
+	bar.Tooltip = newWrappedTooltip(fmt.Sprintf(i18n.Text("%s of %s hours toward the next point"),
+		p.HoursTowardNextPoint().Comma(), p.Needed.Hours().Comma()))
+}
+
+func collectTrainingLogEntries(entity *gurps.Entity) []*trainingLogEntry {
+	var list []*trainingLogEntry
+	add := func(kind, name string, tracker gurps.StudyTracker) {
+		if p := tracker.StudyProgress(); p.Hours > 0 || p.Spent > 0 {
+			list = append(list, &trainingLogEntry{kind: kind, name: name, tracker: tracker})
+		}
+	}
+	gurps.Traverse(func(t *gurps.Trait) bool {
+		add(i18n.Text("Trait"), t.String(), t)
+		return false
+	}, false, true, entity.Traits...)
+	gurps.Traverse(func(s *gurps.Skill) bool {
+		add(i18n.Text("Skill"), s.String(), s)
+		return false
+	}, false, true, entity.Skills...)
+	gurps.Traverse(func(s *gurps.Spell) bool {
+		add(i18n.Text("Spell"), s.String(), s)
+		return false
+	}, false, true, entity.Spells...)
+	return list
+}
+
+// ShowTrainingLog shows the study recorded for the entity's traits, skills and spells, along with the points earned and
+// spent through it.
+func ShowTrainingLog(entity *gurps.Entity) {
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  5,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	var note string
+	switch gurps.SheetSettingsFor(entity).StudyTracking {
+	case study.Off:
+		note = i18n.Text("Study tracking is off, so points spent are not being charged against study.")
+	case study.Display:
+		note = i18n.Text("Points spent are charged against study, but may exceed what has been earned.")
+	case study.Enforce:
+		note = i18n.Text("Points may only be added by increasing a level when they have been earned through study.")
+	}
+	label := unison.NewLabel()
+	label.SetTitle(note)
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: 5})
+	content.AddChild(label)
+	entries := collectTrainingLogEntries(entity)
+	if len(entries) == 0 {
+		label = unison.NewLabel()
+		label.SetTitle(i18n.Text("No study has been recorded"))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 5})
+		content.AddChild(label)
+	} else {
+		for _, header := range []string{
+			i18n.Text("Item"), i18n.Text("Next Point"), i18n.Text("Earned"), i18n.Text("Spent"),
+			i18n.Text("Unspent"),
+		} {
+			label = unison.NewLabel()
+			label.Font = fonts.PageLabelSecondary
+			label.SetTitle(header)
+			content.AddChild(label)
+		}
+		for _, entry := range entries {
+			p := entry.tracker.StudyProgress()
+			label = unison.NewLabel()
+			label.SetTitle(fmt.Sprintf("%s: %s", entry.kind, entry.name))
+			content.AddChild(label)
+			content.AddChild(newStudyProgressBar(p))
+			for _, value := range []string{p.Earned().Comma(), p.Spent.Comma(), p.Available().Comma()} {
+				label = unison.NewLabel()
+				label.SetTitle(value)
+				label.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+				content.AddChild(label)
+			}
+		}
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetContent(content, behavior.Fill, behavior.Unmodified)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	icon := &unison.DrawableSVG{
+		SVG:  svg.Bookmark,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, scroll,
+		[]*unison.DialogButtonInfo{unison.NewOKButtonInfo()}, unison.FloatingWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return
+	}
+	dialog.RunModal()
+}
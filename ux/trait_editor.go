@@ -10,10 +10,14 @@
 package ux
 
 import (
+	"path/filepath"
+	"strings"
+
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/gurps"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/container"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/frequency"
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/power"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/prereq"
 	"github.com/richardwilkes/gcs/v5/model/gurps/enums/selfctrl"
 	"github.com/richardwilkes/gcs/v5/svg"
@@ -28,6 +32,41 @@ func EditTrait(owner Rebuildable, t *gurps.Trait) *editor[*gurps.Trait, *gurps.T
 	return displayEditor(owner, t, svg.GCSTraits, "md:User%20Guide/Traits", nil, initTraitEditor, nil)
 }
 
+func addLinkedSheetField(e *editor[*gurps.Trait, *gurps.TraitEditData], content *unison.Panel) {
+	title := i18n.Text("Linked Sheet")
+	wrapper := addFillWrapper(content, title, 2)
+	field := addStringField(wrapper, title,
+		i18n.Text("The character sheet for the Ally, Dependent, or Familiar this trait represents"),
+		&e.editorData.LinkedSheet)
+	field.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	chooseButton := unison.NewButton()
+	chooseButton.SetTitle(i18n.Text("Choose…"))
+	chooseButton.ClickCallback = func() {
+		workingDir := WorkingDirProvider(e.owner)
+		dialog := unison.NewOpenDialog()
+		dialog.SetAllowsMultipleSelection(false)
+		dialog.SetResolvesAliases(true)
+		dialog.SetAllowedExtensions(gurps.SheetExt)
+		dialog.SetCanChooseDirectories(false)
+		dialog.SetCanChooseFiles(true)
+		dialog.SetInitialDirectory(workingDir)
+		if dialog.RunModal() {
+			p := dialog.Path()
+			if rel, err := filepath.Rel(workingDir, p); err == nil && !strings.HasPrefix(rel, "..") {
+				p = filepath.ToSlash(rel)
+			}
+			e.editorData.LinkedSheet = p
+			field.Sync()
+			MarkModified(wrapper)
+		}
+	}
+	wrapper.AddChild(chooseButton)
+}
+
 func initTraitEditor(e *editor[*gurps.Trait, *gurps.TraitEditData], content *unison.Panel) func() {
 	addNameLabelAndField(content, &e.editorData.Name)
 	addNotesLabelAndField(content, &e.editorData.LocalNotes)
@@ -42,8 +81,8 @@ func initTraitEditor(e *editor[*gurps.Trait, *gurps.TraitEditData], content *uni
 		wrapper := addFlowWrapper(content, i18n.Text("Point Cost"), 2)
 		costField := NewNonEditableField(func(field *NonEditableField) {
 			field.SetTitle(gurps.AdjustedPoints(entity, e.target, e.editorData.CanLevel, e.editorData.BasePoints,
-				e.editorData.Levels, e.editorData.PointsPerLevel, e.editorData.SelfControl, e.editorData.Frequency, e.editorData.Modifiers,
-				e.editorData.RoundCostDown).String())
+				e.editorData.Levels, e.editorData.PointsPerLevel, e.editorData.SelfControl, e.editorData.Frequency,
+				traitEditorModifiers(e), e.editorData.RoundCostDown).String())
 			field.MarkForLayoutAndRedraw()
 		})
 		insets := costField.Border().Insets()
@@ -75,6 +114,7 @@ func initTraitEditor(e *editor[*gurps.Trait, *gurps.TraitEditData], content *uni
 			&e.editorData.PointsPerLevel, -fxp.MaxBasePoints, fxp.MaxBasePoints)
 		adjustFieldBlank(perLevelField, !e.editorData.CanLevel)
 		adjustFieldBlank(levelField, !e.editorData.CanLevel)
+		addLinkedSheetField(e, content)
 	}
 	addLabelAndPopup(content, i18n.Text("Self-Control"), "", selfctrl.Rolls, &e.editorData.SelfControl)
 	adjustment := i18n.Text("Self-Control Adjustment")
@@ -83,6 +123,16 @@ func initTraitEditor(e *editor[*gurps.Trait, *gurps.TraitEditData], content *uni
 		crAdjPopup.SetEnabled(false)
 	}
 	addLabelAndPopup(content, i18n.Text("Frequency of Appearance"), "", frequency.Rolls, &e.editorData.Frequency)
+	powerSourceTooltip := i18n.Text("The source of the power this ability draws upon, which applies its power modifier")
+	if e.target.Container() {
+		addLabelAndPopup(content, i18n.Text("Power Source"), powerSourceTooltip, power.Sources,
+			&e.editorData.PowerSource)
+	} else {
+		wrapper := addFlowWrapper(content, i18n.Text("Power Source"), 2)
+		addPopup(wrapper, power.Sources, &e.editorData.PowerSource).Tooltip = newWrappedTooltip(powerSourceTooltip)
+		talentCheckBox := addCheckBox(wrapper, i18n.Text("Talent"), &e.editorData.PowerTalent)
+		talentCheckBox.Tooltip = newWrappedTooltip(i18n.Text("A Talent adds its level to the skills and spells that have the name of its power source as their power source or as one of their tags"))
+	}
 	var ancestryPopup *unison.PopupMenu[string]
 	if e.target.Container() {
 		addLabelAndPopup(content, i18n.Text("Container Type"), "", container.Types,
@@ -99,6 +149,7 @@ func initTraitEditor(e *editor[*gurps.Trait, *gurps.TraitEditData], content *uni
 	}
 	addPageRefLabelAndField(content, &e.editorData.PageRef)
 	addPageRefHighlightLabelAndField(content, &e.editorData.PageRefHighlight)
+	addProvenanceLabelsAndFields(content, &e.editorData.Provenance)
 	addSourceFields(content, &e.target.SourcedID)
 	modifiersPanel := newTraitModifiersPanel(entity, &e.editorData.Modifiers)
 	content.AddChild(newPrereqPanel(entity, &e.editorData.Prereq, prereq.TypesForNonEquipment))
@@ -145,3 +196,18 @@ func initTraitEditor(e *editor[*gurps.Trait, *gurps.TraitEditData], content *uni
 		}
 	}
 }
+
+// traitEditorModifiers returns the modifiers used to calculate the cost of the trait being edited, including the power
+// modifier for its power source.
+func traitEditorModifiers(e *editor[*gurps.Trait, *gurps.TraitEditData]) []*gurps.TraitModifier {
+	if e.editorData.PowerTalent {
+		return e.editorData.Modifiers
+	}
+	source := e.editorData.PowerSource
+	if source == power.None {
+		if parent := e.target.Parent(); parent != nil {
+			source = parent.EffectivePowerSource()
+		}
+	}
+	return gurps.WithPowerModifier(e.editorData.Modifiers, source)
+}
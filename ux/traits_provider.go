@@ -148,6 +148,13 @@ func (p *traitsProvider) SyncHeader(_ []unison.TableColumnHeader[*Node[*gurps.Tr
 }
 
 func (p *traitsProvider) ColumnIDs() []int {
+	if p.forPage {
+		return customizedColumnIDs(p)
+	}
+	return p.defaultColumnIDs()
+}
+
+func (p *traitsProvider) defaultColumnIDs() []int {
 	columnIDs := append(make([]int, 0, 4),
 		gurps.TraitDescriptionColumn,
 		gurps.TraitPointsColumn,
@@ -176,6 +183,20 @@ func (p *traitsProvider) ColumnIDs() []int {
 	return columnIDs
 }
 
+func (p *traitsProvider) availableColumnIDs() []int {
+	return []int{
+		gurps.TraitDescriptionColumn,
+		gurps.TraitPointsColumn,
+		gurps.TraitTagsColumn,
+		gurps.TraitReferenceColumn,
+		gurps.TraitLibSrcColumn,
+	}
+}
+
+func (p *traitsProvider) columnHeaderData(columnID int) gurps.HeaderData {
+	return gurps.TraitsHeaderData(columnID)
+}
+
 func (p *traitsProvider) HierarchyColumnID() int {
 	return gurps.TraitDescriptionColumn
 }
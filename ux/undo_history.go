@@ -0,0 +1,152 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"runtime"
+	"slices"
+	"time"
+	"weak"
+
+	"github.com/richardwilkes/unison"
+)
+
+var _ unison.Undoable = &undoHistoryEntry{}
+
+// undoHistories maps each undo manager to its history. Neither is kept alive by the map: the manager holds its edits,
+// which in turn hold the history, so the history lives exactly as long as the manager has edits to show.
+var undoHistories = make(map[weak.Pointer[unison.UndoManager]]weak.Pointer[undoHistory])
+
+// undoHistory tracks the edits held by an undo manager, which does not otherwise reveal them, so that they can be
+// listed in the Undo History.
+type undoHistory struct {
+	entries  []*undoHistoryEntry
+	onChange func()
+}
+
+// undoHistoryEntry wraps an edit as it is added to an undo manager, recording when it was made and whether it is
+// currently applied.
+type undoHistoryEntry struct {
+	edit    unison.Undoable
+	history *undoHistory
+	when    time.Time
+	applied bool
+}
+
+// addUndo adds the edit to the undo manager, recording it in the manager's history.
+func addUndo(mgr *unison.UndoManager, edit unison.Undoable) {
+	h := undoHistoryFor(mgr)
+	h.entries = append(h.entries, &undoHistoryEntry{
+		edit:    edit,
+		history: h,
+		when:    time.Now(),
+		applied: true,
+	})
+	mgr.Add(h.entries[len(h.entries)-1])
+	h.changed()
+}
+
+// undoHistoryFor returns the history for the undo manager, creating it if necessary.
+func undoHistoryFor(mgr *unison.UndoManager) *undoHistory {
+	key := weak.Make(mgr)
+	if wh, ok := undoHistories[key]; ok {
+		if h := wh.Value(); h != nil {
+			return h
+		}
+	} else {
+		runtime.AddCleanup(mgr, func(k weak.Pointer[unison.UndoManager]) {
+			unison.InvokeTask(func() { delete(undoHistories, k) })
+		}, key)
+	}
+	h := &undoHistory{}
+	undoHistories[key] = weak.Make(h)
+	return h
+}
+
+func (h *undoHistory) changed() {
+	if h.onChange != nil {
+		h.onChange()
+	}
+}
+
+// current returns the most recent entry that is applied, or nil if none are.
+func (h *undoHistory) current() *undoHistoryEntry {
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if h.entries[i].applied {
+			return h.entries[i]
+		}
+	}
+	return nil
+}
+
+// jumpTo undoes or redoes edits until the entry is the most recent one applied. A nil entry undoes everything.
+func (h *undoHistory) jumpTo(mgr *unison.UndoManager, entry *undoHistoryEntry) {
+	for range len(h.entries) {
+		switch current := h.current(); {
+		case current == entry:
+			return
+		case entry == nil || entry.applied:
+			if !mgr.CanUndo() {
+				return
+			}
+			mgr.Undo()
+		default:
+			if !mgr.CanRedo() {
+				return
+			}
+			mgr.Redo()
+		}
+	}
+}
+
+// Name implements unison.Undoable.
+func (e *undoHistoryEntry) Name() string {
+	return e.edit.Name()
+}
+
+// Cost implements unison.Undoable.
+func (e *undoHistoryEntry) Cost() int {
+	return e.edit.Cost()
+}
+
+// Undo implements unison.Undoable.
+func (e *undoHistoryEntry) Undo() {
+	e.edit.Undo()
+	e.applied = false
+	e.history.changed()
+}
+
+// Redo implements unison.Undoable.
+func (e *undoHistoryEntry) Redo() {
+	e.edit.Redo()
+	e.applied = true
+	e.history.changed()
+}
+
+// Absorb implements unison.Undoable.
+func (e *undoHistoryEntry) Absorb(other unison.Undoable) bool {
+	if o, ok := other.(*undoHistoryEntry); ok {
+		if e.edit.Absorb(o.edit) {
+			e.when = o.when
+			return true
+		}
+		return false
+	}
+	return e.edit.Absorb(other)
+}
+
+// Release implements unison.Undoable.
+func (e *undoHistoryEntry) Release() {
+	e.edit.Release()
+	if i := slices.Index(e.history.entries, e); i != -1 {
+		e.history.entries = slices.Delete(e.history.entries, i, i+1)
+		e.history.changed()
+	}
+}
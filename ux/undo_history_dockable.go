@@ -0,0 +1,299 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps/enums/dgroup"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/model/merge"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+)
+
+var (
+	_ unison.Dockable  = &undoHistoryDockable{}
+	_ unison.TabCloser = &undoHistoryDockable{}
+)
+
+// undoHistoryTarget is a dockable whose edits can be shown in the Undo History.
+type undoHistoryTarget interface {
+	unison.Dockable
+	unison.UndoManagerProvider
+}
+
+// undoHistoryDockable lists the edits that can be undone or redone in another dockable, allowing any point in that
+// history to be returned to. Edits to a character sheet may also be reverted on their own, provided nothing changed
+// afterward touches the same data.
+type undoHistoryDockable struct {
+	unison.Panel
+	target     undoHistoryTarget
+	history    *undoHistory
+	content    *unison.Panel
+	scroll     *unison.ScrollPanel
+	rebuilding bool
+}
+
+// ShowUndoHistory shows the Undo History for the active dockable.
+func ShowUndoHistory() {
+	target, ok := ActiveDockable().(undoHistoryTarget)
+	if !ok || target.UndoManager() == nil {
+		return
+	}
+	if Activate(func(d unison.Dockable) bool {
+		existing, ok2 := d.AsPanel().Self.(*undoHistoryDockable)
+		return ok2 && existing.target == target
+	}) {
+		return
+	}
+	d := &undoHistoryDockable{
+		target:  target,
+		history: undoHistoryFor(target.UndoManager()),
+	}
+	d.Self = d
+	d.history.onChange = d.scheduleRebuild
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+	d.content = unison.NewPanel()
+	d.content.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	d.content.SetLayout(&unison.FlexLayout{
+		Columns:  5,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.scroll = unison.NewScrollPanel()
+	d.scroll.SetContent(d.content, behavior.HintedFill, behavior.Fill)
+	d.scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	d.AddChild(d.scroll)
+	d.rebuild()
+	PlaceInDock(d, dgroup.Editors, false)
+}
+
+func (d *undoHistoryDockable) scheduleRebuild() {
+	if !d.rebuilding {
+		d.rebuilding = true
+		unison.InvokeTask(d.rebuild)
+	}
+}
+
+func (d *undoHistoryDockable) rebuild() {
+	d.rebuilding = false
+	d.content.RemoveAllChildren()
+	if !slices.Contains(AllDockables(), unison.Dockable(d.target)) {
+		d.addNote(i18n.Text("The document this history belongs to has been closed."))
+		d.scroll.MarkForLayoutAndRedraw()
+		return
+	}
+	mgr := d.target.UndoManager()
+	current := d.history.current()
+	d.addRow(nil, current == nil, mgr)
+	for _, entry := range d.history.entries {
+		d.addRow(entry, entry == current, mgr)
+	}
+	d.scroll.MarkForLayoutAndRedraw()
+}
+
+func (d *undoHistoryDockable) addNote(text string) {
+	label := unison.NewLabel()
+	label.SetTitle(text)
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: 5})
+	d.content.AddChild(label)
+}
+
+func (d *undoHistoryDockable) addRow(entry *undoHistoryEntry, isCurrent bool, mgr *unison.UndoManager) {
+	marker := unison.NewLabel()
+	if isCurrent {
+		marker.Drawable = &unison.DrawableSVG{
+			SVG:  svg.Forward,
+			Size: geom.NewSize(12, 12),
+		}
+		marker.Tooltip = newWrappedTooltip(i18n.Text("The current state"))
+	}
+	marker.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: geom.NewSize(12, 12),
+		VAlign:   align.Middle,
+	})
+	d.content.AddChild(marker)
+
+	when := unison.NewLabel()
+	name := unison.NewLabel()
+	if entry == nil {
+		name.SetTitle(i18n.Text("Before the first edit"))
+	} else {
+		when.SetTitle(formatUndoHistoryTime(entry.when))
+		name.SetTitle(entry.Name())
+		if !entry.applied {
+			when.OnBackgroundInk = dimmedPointsColor
+			name.OnBackgroundInk = dimmedPointsColor
+			name.Tooltip = newWrappedTooltip(i18n.Text("Undone. Use Go Here or Redo to apply it again."))
+		}
+	}
+	when.SetLayoutData(&unison.FlexLayoutData{VAlign: align.Middle})
+	d.content.AddChild(when)
+	name.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	d.content.AddChild(name)
+
+	goButton := unison.NewButton()
+	goButton.SetTitle(i18n.Text("Go Here"))
+	goButton.Tooltip = newWrappedTooltip(i18n.Text("Undo or redo edits until this is the most recent one applied"))
+	goButton.SetEnabled(!isCurrent)
+	goButton.ClickCallback = func() { d.history.jumpTo(mgr, entry) }
+	d.content.AddChild(goButton)
+
+	revertButton := unison.NewButton()
+	revertButton.SetTitle(i18n.Text("Revert"))
+	revertButton.Tooltip = newWrappedTooltip(i18n.Text(`Reverse just this edit, keeping those made after it.
+This is only possible on character sheets, and only when no later edit changed the same data.`))
+	_, isSheet := d.target.(*Sheet)
+	revertButton.SetEnabled(isSheet && entry != nil && entry.applied)
+	revertButton.ClickCallback = func() { d.revert(entry) }
+	d.content.AddChild(revertButton)
+}
+
+// revert the entry's edit out of order. The document is taken back to just after and just before the edit by undoing,
+// then brought forward again, and the difference between those two states is then removed from the current state with
+// a three-way merge. Any overlap with the later edits shows up as a conflict, in which case nothing is changed.
+func (d *undoHistoryDockable) revert(entry *undoHistoryEntry) {
+	sheet, ok := d.target.(*Sheet)
+	if !ok || !entry.applied {
+		return
+	}
+	mgr := sheet.UndoManager()
+	entity := sheet.Entity()
+	current, err := json.Marshal(entity)
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to revert the edit"), err)
+		return
+	}
+	position := d.history.current()
+	i := slices.Index(d.history.entries, entry)
+	if i == -1 {
+		return
+	}
+	var before *undoHistoryEntry
+	if i > 0 {
+		before = d.history.entries[i-1]
+	}
+	d.history.jumpTo(mgr, entry)
+	var afterEdit, beforeEdit []byte
+	if afterEdit, err = json.Marshal(entity); err == nil {
+		d.history.jumpTo(mgr, before)
+		beforeEdit, err = json.Marshal(entity)
+	}
+	d.history.jumpTo(mgr, position)
+	if d.history.current() != position {
+		err = errs.New("unable to return to the current state")
+	}
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to revert the edit"), err)
+		return
+	}
+	var result *merge.Result
+	if result, err = merge.Merge(afterEdit, current, beforeEdit); err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to revert the edit"), err)
+		return
+	}
+	if len(result.Conflicts) != 0 {
+		paths := make([]string, 0, len(result.Conflicts))
+		for _, c := range result.Conflicts {
+			paths = append(paths, strings.Join(c.Path, " › "))
+		}
+		unison.WarningDialogWithMessage(fmt.Sprintf(i18n.Text("Unable to revert %s"), entry.Name()),
+			i18n.Text("Later edits changed the same data:")+"\n\n"+strings.Join(paths, "\n"))
+		return
+	}
+	var merged, beforeData, afterData []byte
+	if merged, err = result.Bytes(); err == nil {
+		if beforeData, err = jio.SerializeAndCompress(entity); err == nil {
+			entity.DiscardCaches()
+			if err = json.Unmarshal(merged, entity); err == nil {
+				entity.Recalculate()
+				afterData, err = jio.SerializeAndCompress(entity)
+			}
+		}
+	}
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to revert the edit"), err)
+		return
+	}
+	addUndo(mgr, &unison.UndoEdit[*entityState]{
+		ID:         unison.NextUndoID(),
+		EditName:   fmt.Sprintf(i18n.Text("Revert %s"), entry.Name()),
+		UndoFunc:   func(e *unison.UndoEdit[*entityState]) { e.BeforeData.Apply() },
+		RedoFunc:   func(e *unison.UndoEdit[*entityState]) { e.AfterData.Apply() },
+		BeforeData: &entityState{Owner: sheet, Data: beforeData},
+		AfterData:  &entityState{Owner: sheet, Data: afterData},
+	})
+	sheet.Rebuild(true)
+	MarkModified(sheet)
+}
+
+func formatUndoHistoryTime(when time.Time) string {
+	now := time.Now()
+	if y, m, day := when.Date(); y == now.Year() && m == now.Month() && day == now.Day() {
+		return when.Format(time.TimeOnly)
+	}
+	return when.Format(time.DateTime)
+}
+
+// TitleIcon implements unison.Dockable.
+func (d *undoHistoryDockable) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Back,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable.
+func (d *undoHistoryDockable) Title() string {
+	return fmt.Sprintf(i18n.Text("Undo History: %s"), d.target.Title())
+}
+
+// Tooltip implements unison.Dockable.
+func (d *undoHistoryDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable.
+func (d *undoHistoryDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (d *undoHistoryDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser.
+func (d *undoHistoryDockable) AttemptClose() bool {
+	if !AttemptCloseForDockable(d) {
+		return false
+	}
+	d.history.onChange = nil
+	return true
+}
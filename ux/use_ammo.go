@@ -0,0 +1,144 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type ammoUndoEdit = *unison.UndoEdit[*ammoList]
+
+type ammoList struct {
+	Owner unison.Paneler
+	List  []*ammoAdjuster
+}
+
+func (a *ammoList) Apply() {
+	for _, one := range a.List {
+		one.Apply()
+	}
+	a.Finish()
+}
+
+func (a *ammoList) Finish() {
+	if entity := gurps.EntityFromNode(a.List[0].Target); entity != nil {
+		entity.Recalculate()
+	}
+	MarkModified(a.Owner)
+}
+
+type ammoAdjuster struct {
+	Target   *gurps.Equipment
+	Quantity fxp.Int
+	Loaded   fxp.Int
+}
+
+// newAmmoAdjusters captures the state of the equipment the weapon draws from: the weapon's own equipment, for its loaded
+// count, and its ammunition, for the quantity remaining.
+func newAmmoAdjusters(w *gurps.Weapon) []*ammoAdjuster {
+	list := []*ammoAdjuster{newAmmoAdjuster(w.OwningEquipment())}
+	if ammo := w.Ammunition(); ammo != nil && ammo != list[0].Target {
+		list = append(list, newAmmoAdjuster(ammo))
+	}
+	return list
+}
+
+func newAmmoAdjuster(target *gurps.Equipment) *ammoAdjuster {
+	return &ammoAdjuster{
+		Target:   target,
+		Quantity: target.Quantity,
+		Loaded:   target.AmmoLoaded,
+	}
+}
+
+func (a *ammoAdjuster) Apply() {
+	a.Target.Quantity = a.Quantity
+	a.Target.AmmoLoaded = a.Loaded
+}
+
+func canUseAmmo(table *unison.Table[*Node[*gurps.Weapon]]) bool {
+	for _, row := range table.SelectedRows(false) {
+		if w := row.Data(); w != nil && w.CanUseAmmo() {
+			return true
+		}
+	}
+	return false
+}
+
+func useAmmo(owner unison.Paneler, table *unison.Table[*Node[*gurps.Weapon]]) {
+	var warnings []string
+	for _, row := range table.SelectedRows(false) {
+		if w := row.Data(); w != nil && w.CanUseAmmo() {
+			if _, warning := fireWeapon(owner, w); warning != "" {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	if len(warnings) != 0 {
+		unison.WarningDialogWithMessage(i18n.Text("Ammunition"), strings.Join(warnings, "\n"))
+	}
+}
+
+// fireWeapon uses up the ammunition for a single shot from the weapon, recording an undo for it. Returns true if the
+// weapon could be fired, along with any warning that should be shown to the user.
+func fireWeapon(owner unison.Paneler, w *gurps.Weapon) (fired bool, warning string) {
+	if w.Ammunition() == nil {
+		return true, ""
+	}
+	before := &ammoList{Owner: owner, List: newAmmoAdjusters(w)}
+	if fired, warning = w.UseAmmo(); fired {
+		after := &ammoList{Owner: owner, List: newAmmoAdjusters(w)}
+		addAmmoUndo(owner, i18n.Text("Use Ammo"), before, after)
+	}
+	return fired, warning
+}
+
+func canReloadWeapon(table *unison.Table[*Node[*gurps.Weapon]]) bool {
+	for _, row := range table.SelectedRows(false) {
+		if w := row.Data(); w != nil && w.NeedsReload() {
+			return true
+		}
+	}
+	return false
+}
+
+func reloadWeapon(owner unison.Paneler, table *unison.Table[*Node[*gurps.Weapon]]) {
+	before := &ammoList{Owner: owner}
+	after := &ammoList{Owner: owner}
+	for _, row := range table.SelectedRows(false) {
+		if w := row.Data(); w != nil && w.NeedsReload() {
+			before.List = append(before.List, newAmmoAdjusters(w)...)
+			w.Reload()
+			after.List = append(after.List, newAmmoAdjusters(w)...)
+		}
+	}
+	if len(before.List) > 0 {
+		addAmmoUndo(owner, i18n.Text("Reload"), before, after)
+	}
+}
+
+func addAmmoUndo(owner unison.Paneler, name string, before, after *ammoList) {
+	if mgr := unison.UndoManagerFor(owner); mgr != nil {
+		addUndo(mgr, &unison.UndoEdit[*ammoList]{
+			ID:         unison.NextUndoID(),
+			EditName:   name,
+			UndoFunc:   func(edit ammoUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit ammoUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	before.Finish()
+}
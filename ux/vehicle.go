@@ -0,0 +1,804 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"github.com/richardwilkes/toolbox/v2/xfilepath"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/behavior"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var (
+	_ FileBackedDockable           = &VehicleSheet{}
+	_ ExportDockable               = &VehicleSheet{}
+	_ unison.UndoManagerProvider   = &VehicleSheet{}
+	_ ModifiableRoot               = &VehicleSheet{}
+	_ Rebuildable                  = &VehicleSheet{}
+	_ unison.TabCloser             = &VehicleSheet{}
+	_ gurps.SheetSettingsResponder = &VehicleSheet{}
+	_ KeyedDockable                = &VehicleSheet{}
+)
+
+// VehicleSheet holds the view for a vehicle sheet.
+type VehicleSheet struct {
+	unison.Panel
+	path              string
+	targetMgr         *TargetMgr
+	undoMgr           *unison.UndoManager
+	toolbar           *unison.Panel
+	scroll            *unison.ScrollPanel
+	content           *unison.Panel
+	crewBlock         *unison.Panel
+	vehicle           *gurps.Vehicle
+	hash              uint64
+	Equipment         *PageList[*gurps.Equipment]
+	Notes             *PageList[*gurps.Note]
+	dragReroutePanel  *unison.Panel
+	searchTracker     *SearchTracker
+	scale             int
+	awaitingUpdate    bool
+	needsSaveAsPrompt bool
+}
+
+// NewVehicleSheetFromFile loads a vehicle sheet file and creates a new unison.Dockable for it.
+func NewVehicleSheetFromFile(filePath string) (unison.Dockable, error) {
+	vehicle, err := gurps.NewVehicleFromFile(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath))
+	if err != nil {
+		return nil, err
+	}
+	v := NewVehicleSheet(filePath, vehicle)
+	v.needsSaveAsPrompt = false
+	return v, nil
+}
+
+// NewVehicleSheet creates a new unison.Dockable for vehicle sheet files.
+func NewVehicleSheet(filePath string, vehicle *gurps.Vehicle) *VehicleSheet {
+	v := &VehicleSheet{
+		path:              filePath,
+		undoMgr:           unison.NewUndoManager(200, func(err error) { errs.Log(err) }),
+		scroll:            unison.NewScrollPanel(),
+		content:           unison.NewPanel(),
+		vehicle:           vehicle,
+		scale:             gurps.GlobalSettings().General.InitialSheetUIScale,
+		hash:              gurps.Hash64(vehicle),
+		needsSaveAsPrompt: true,
+	}
+	v.Self = v
+	v.targetMgr = NewTargetMgr(v)
+	v.SetLayout(&unison.FlexLayout{
+		Columns: 1,
+		HAlign:  align.Fill,
+		VAlign:  align.Fill,
+	})
+
+	v.MouseDownCallback = func(_ geom.Point, _, _ int, _ unison.Modifiers) bool {
+		v.RequestFocus()
+		return false
+	}
+	v.DataDragOverCallback = func(_ geom.Point, data map[string]any) bool {
+		v.dragReroutePanel = nil
+		for _, key := range dropKeys {
+			if _, ok := data[key]; ok {
+				if v.dragReroutePanel = v.keyToPanel(key); v.dragReroutePanel != nil {
+					v.dragReroutePanel.DataDragOverCallback(geom.Point{Y: 100000000}, data)
+					return true
+				}
+				break
+			}
+		}
+		return false
+	}
+	v.DataDragExitCallback = func() {
+		if v.dragReroutePanel != nil {
+			v.dragReroutePanel.DataDragExitCallback()
+			v.dragReroutePanel = nil
+		}
+	}
+	v.DataDragDropCallback = func(_ geom.Point, data map[string]any) {
+		if v.dragReroutePanel != nil {
+			v.dragReroutePanel.DataDragDropCallback(geom.Point{Y: 10000000}, data)
+			v.dragReroutePanel = nil
+		}
+	}
+	v.DrawOverCallback = func(gc *unison.Canvas, _ geom.Rect) {
+		if v.dragReroutePanel != nil {
+			r := v.RectFromRoot(v.dragReroutePanel.RectToRoot(v.dragReroutePanel.ContentRect(true)))
+			paint := unison.ThemeWarning.Paint(gc, r, paintstyle.Fill)
+			paint.SetColorFilter(unison.Alpha30Filter())
+			gc.DrawRect(r, paint)
+		}
+	}
+
+	v.content.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: 1,
+	})
+	var page *Page
+	page, v.crewBlock = createVehicleTopBlock(v.vehicle, v.targetMgr, v)
+	v.content.AddChild(page)
+	v.createLists()
+
+	v.scroll.SetContent(v.content, behavior.Unmodified, behavior.Unmodified)
+	v.scroll.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+		VGrab:  true,
+	})
+	v.createToolbar()
+	v.AddChild(v.scroll)
+
+	v.InstallCmdHandlers(SaveItemID, func(_ any) bool { return v.Modified() }, func(_ any) { v.save(false) })
+	v.InstallCmdHandlers(SaveAsItemID, unison.AlwaysEnabled, func(_ any) { v.save(true) })
+	v.installNewItemCmdHandlers(NewOtherEquipmentItemID, NewOtherEquipmentContainerItemID, v.Equipment)
+	v.installNewItemCmdHandlers(NewNoteItemID, NewNoteContainerItemID, v.Notes)
+	InstallExportCmdHandlers(v)
+
+	v.vehicle.EnsureAttachments()
+	v.vehicle.SourceMatcher().PrepareHashes(v.vehicle)
+	return v
+}
+
+// DockKey implements KeyedDockable.
+func (v *VehicleSheet) DockKey() string {
+	return filePrefix + v.path
+}
+
+func (v *VehicleSheet) createToolbar() {
+	v.toolbar = unison.NewPanel()
+	v.AddChild(v.toolbar)
+	v.toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.ThemeSurfaceEdge, geom.Size{},
+		geom.Insets{Bottom: 1}, false), unison.NewEmptyBorder(unison.StdInsets())))
+	v.toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	v.toolbar.AddChild(NewDefaultInfoPop())
+
+	v.toolbar.AddChild(
+		NewScaleField(
+			gurps.InitialUIScaleMin,
+			gurps.InitialUIScaleMax,
+			func() int { return gurps.GlobalSettings().General.InitialSheetUIScale },
+			func() int { return v.scale },
+			func(scale int) { v.scale = scale },
+			nil,
+			false,
+			true,
+			v.scroll,
+		),
+	)
+
+	hierarchyButton := unison.NewSVGButton(svg.Hierarchy)
+	hierarchyButton.Tooltip = newWrappedTooltip(i18n.Text("Opens/closes all hierarchical rows"))
+	hierarchyButton.ClickCallback = v.toggleHierarchy
+	v.toolbar.AddChild(hierarchyButton)
+
+	noteToggleButton := unison.NewSVGButton(svg.NotesToggle)
+	noteToggleButton.Tooltip = newWrappedTooltip(i18n.Text("Opens/closes all embedded notes"))
+	noteToggleButton.ClickCallback = v.toggleNotes
+	v.toolbar.AddChild(noteToggleButton)
+
+	syncSourceButton := unison.NewSVGButton(svg.DownToBracket)
+	syncSourceButton.Tooltip = newWrappedTooltip(i18n.Text("Sync with all sources in this sheet"))
+	syncSourceButton.ClickCallback = func() { v.syncWithAllSources() }
+	v.toolbar.AddChild(syncSourceButton)
+
+	addCrewButton := unison.NewSVGButton(svg.GCSSheet)
+	addCrewButton.Tooltip = newWrappedTooltip(i18n.Text("Add crew from character sheets"))
+	addCrewButton.ClickCallback = v.addCrew
+	v.toolbar.AddChild(addCrewButton)
+
+	v.searchTracker = InstallSearchTracker(v.toolbar, func() {
+		v.Equipment.Table.ClearSelection()
+		v.Notes.Table.ClearSelection()
+	}, func(refList *[]*searchRef, text string, namesOnly bool) {
+		searchSheetTable(refList, text, namesOnly, v.Equipment)
+		searchSheetTable(refList, text, namesOnly, v.Notes)
+	})
+
+	v.toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(v.toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+}
+
+const (
+	vehiclePanelFieldPrefix          = "vehicle:"
+	vehiclePanelNameFieldRefKey      = vehiclePanelFieldPrefix + "name"
+	vehiclePanelTLFieldRefKey        = vehiclePanelFieldPrefix + "tl"
+	vehiclePanelCostFieldRefKey      = vehiclePanelFieldPrefix + "cost"
+	vehiclePanelSTFieldRefKey        = vehiclePanelFieldPrefix + "st"
+	vehiclePanelHPFieldRefKey        = vehiclePanelFieldPrefix + "hp"
+	vehiclePanelHandlingFieldRefKey  = vehiclePanelFieldPrefix + "handling"
+	vehiclePanelStabilityFieldRefKey = vehiclePanelFieldPrefix + "stability"
+	vehiclePanelHTFieldRefKey        = vehiclePanelFieldPrefix + "ht"
+	vehiclePanelMoveFieldRefKey      = vehiclePanelFieldPrefix + "move"
+	vehiclePanelLWtFieldRefKey       = vehiclePanelFieldPrefix + "lwt"
+	vehiclePanelLoadFieldRefKey      = vehiclePanelFieldPrefix + "load"
+	vehiclePanelSMFieldRefKey        = vehiclePanelFieldPrefix + "sm"
+	vehiclePanelOccupancyFieldRefKey = vehiclePanelFieldPrefix + "occupancy"
+	vehiclePanelDRFieldRefKey        = vehiclePanelFieldPrefix + "dr"
+	vehiclePanelLocationsFieldRefKey = vehiclePanelFieldPrefix + "locations"
+	vehiclePanelRangeFieldRefKey     = vehiclePanelFieldPrefix + "range"
+	vehiclePanelCrewRoleFieldRefKey  = vehiclePanelFieldPrefix + "crew.role:"
+)
+
+// createVehicleTopBlock creates the page for a vehicle, along with the block its crew is shown in. sheet may be nil,
+// in which case the crew can't be opened or removed, as is the case when exporting.
+func createVehicleTopBlock(vehicle *gurps.Vehicle, targetMgr *TargetMgr, sheet *VehicleSheet) (page *Page, crewBlock *unison.Panel) {
+	page = NewPage(vehicle)
+	top := unison.NewPanel()
+	top.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: 1,
+		VSpacing: 1,
+	})
+	top.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+
+	info := newVehicleBlock(i18n.Text("Vehicle"), 6)
+	info.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	nameField := addVehicleTextField(info, targetMgr, i18n.Text("Name"), "", vehiclePanelNameFieldRefKey,
+		&vehicle.Name)
+	nameField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+		HGrab:  true,
+	})
+	addVehicleTextField(info, targetMgr, i18n.Text("TL"), i18n.Text("Tech Level"), vehiclePanelTLFieldRefKey,
+		&vehicle.TechLevel)
+	addVehicleDecimalField(info, targetMgr, i18n.Text("Cost"), "", vehiclePanelCostFieldRefKey, &vehicle.Cost, 0,
+		fxp.BillionMinusOne)
+	top.AddChild(info)
+
+	stats := newVehicleBlock(i18n.Text("Statistics"), 4)
+	addVehicleDecimalField(stats, targetMgr, i18n.Text("ST"), i18n.Text("Strength"), vehiclePanelSTFieldRefKey,
+		&vehicle.ST, 0, fxp.TenThousandMinusOne)
+	addVehicleDecimalField(stats, targetMgr, i18n.Text("HP"), i18n.Text("Hit Points"), vehiclePanelHPFieldRefKey,
+		&vehicle.HP, 0, fxp.TenThousandMinusOne)
+	addVehicleDecimalField(stats, targetMgr, i18n.Text("Hnd"), i18n.Text("Handling"),
+		vehiclePanelHandlingFieldRefKey, &vehicle.Handling, -fxp.Ten, fxp.Ten)
+	addVehicleDecimalField(stats, targetMgr, i18n.Text("SR"), i18n.Text("Stability Rating"),
+		vehiclePanelStabilityFieldRefKey, &vehicle.Stability, 0, fxp.Ten)
+	addVehicleTextField(stats, targetMgr, i18n.Text("HT"), i18n.Text("Health, including any reliability codes"),
+		vehiclePanelHTFieldRefKey, &vehicle.HT)
+	addVehicleTextField(stats, targetMgr, i18n.Text("Move"), i18n.Text("Acceleration/Top Speed"),
+		vehiclePanelMoveFieldRefKey, &vehicle.Move)
+	addVehicleDecimalField(stats, targetMgr, i18n.Text("LWt"), i18n.Text("Loaded Weight, in tons"),
+		vehiclePanelLWtFieldRefKey, &vehicle.LWt, 0, fxp.MillionMinusOne)
+	addVehicleDecimalField(stats, targetMgr, i18n.Text("Load"), i18n.Text("Load, in tons"),
+		vehiclePanelLoadFieldRefKey, &vehicle.Load, 0, fxp.MillionMinusOne)
+	addVehicleDecimalField(stats, targetMgr, i18n.Text("SM"), i18n.Text("Size Modifier"), vehiclePanelSMFieldRefKey,
+		&vehicle.SM, -fxp.Ten, fxp.Thirty)
+	addVehicleTextField(stats, targetMgr, i18n.Text("Occ."), i18n.Text("Occupancy"),
+		vehiclePanelOccupancyFieldRefKey, &vehicle.Occupancy)
+	addVehicleTextField(stats, targetMgr, i18n.Text("DR"), i18n.Text("Damage Resistance"), vehiclePanelDRFieldRefKey,
+		&vehicle.DR)
+	addVehicleTextField(stats, targetMgr, i18n.Text("Locations"), "", vehiclePanelLocationsFieldRefKey,
+		&vehicle.Locations)
+	rangeField := addVehicleTextField(stats, targetMgr, i18n.Text("Range"), "", vehiclePanelRangeFieldRefKey,
+		&vehicle.Range)
+	rangeField.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  3,
+		HAlign: align.Fill,
+		VAlign: align.Middle,
+	})
+	top.AddChild(stats)
+
+	crewBlock = newVehicleBlock(i18n.Text("Crew"), 1)
+	fillVehicleCrewBlock(crewBlock, vehicle, targetMgr, sheet)
+	top.AddChild(crewBlock)
+
+	page.AddChild(top)
+	return page, crewBlock
+}
+
+func newVehicleBlock(title string, columns int) *unison.Panel {
+	block := unison.NewPanel()
+	block.SetLayout(&unison.FlexLayout{
+		Columns:  columns,
+		HSpacing: 4,
+	})
+	block.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	block.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: title},
+		unison.NewEmptyBorder(geom.Insets{
+			Top:    1,
+			Left:   2,
+			Bottom: 1,
+			Right:  2,
+		})))
+	return block
+}
+
+func fillVehicleCrewBlock(block *unison.Panel, vehicle *gurps.Vehicle, targetMgr *TargetMgr, sheet *VehicleSheet) {
+	block.RemoveAllChildren()
+	columns := 2
+	if sheet != nil {
+		columns = 4
+	}
+	block.SetLayout(&unison.FlexLayout{
+		Columns:  columns,
+		HSpacing: 4,
+	})
+	for i, crew := range vehicle.Crew {
+		var crewPath string
+		exists := false
+		if sheet != nil {
+			crewPath = crew.AbsolutePath(sheet.path)
+			exists = xos.FileExists(crewPath)
+			openButton := unison.NewSVGButton(svg.OpenFolder)
+			openButton.Tooltip = newWrappedTooltip(i18n.Text("Open"))
+			openButton.SetEnabled(exists)
+			openButton.ClickCallback = func() { OpenFile(crewPath, 0) }
+			block.AddChild(openButton)
+		}
+		name := crew.Name
+		if sheet != nil && !exists {
+			name = fmt.Sprintf(i18n.Text("%s (missing)"), name)
+		}
+		label := NewPageLabel(name)
+		if crewPath != "" {
+			label.Tooltip = newWrappedTooltip(crewPath)
+		}
+		block.AddChild(label)
+		roleField := NewStringPageField(targetMgr, fmt.Sprintf("%s%d", vehiclePanelCrewRoleFieldRefKey, i),
+			i18n.Text("Crew Role"), func() string { return crew.Role }, func(s string) { crew.Role = s })
+		roleField.Watermark = i18n.Text("Role")
+		roleField.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			VAlign: align.Middle,
+			HGrab:  true,
+		})
+		block.AddChild(roleField)
+		if sheet != nil {
+			removeButton := unison.NewSVGButton(svg.Trash)
+			removeButton.Tooltip = newWrappedTooltip(i18n.Text("Remove from the crew"))
+			removeButton.ClickCallback = func() { sheet.removeCrew(crew) }
+			block.AddChild(removeButton)
+		}
+	}
+	if len(vehicle.Crew) == 0 {
+		label := NewPageLabel(i18n.Text("No crew assigned"))
+		label.SetLayoutData(&unison.FlexLayoutData{
+			HSpan:  columns,
+			HAlign: align.Fill,
+			VAlign: align.Middle,
+		})
+		block.AddChild(label)
+	}
+}
+
+func addVehicleTextField(parent *unison.Panel, targetMgr *TargetMgr, title, tooltip, fieldRefKey string, field *string) *StringField {
+	addVehicleLabel(parent, title, tooltip)
+	f := NewStringPageField(targetMgr, fieldRefKey, title,
+		func() string { return *field },
+		func(s string) { *field = s })
+	parent.AddChild(f)
+	return f
+}
+
+func addVehicleDecimalField(parent *unison.Panel, targetMgr *TargetMgr, title, tooltip, fieldRefKey string, field *fxp.Int, minValue, maxValue fxp.Int) {
+	addVehicleLabel(parent, title, tooltip)
+	parent.AddChild(NewDecimalPageField(targetMgr, fieldRefKey, title,
+		func() fxp.Int { return *field },
+		func(value fxp.Int) { *field = value },
+		minValue, maxValue, false))
+}
+
+func addVehicleLabel(parent *unison.Panel, title, tooltip string) {
+	label := NewPageLabelEnd(title)
+	if tooltip != "" {
+		label.Tooltip = newWrappedTooltip(tooltip)
+	}
+	parent.AddChild(label)
+}
+
+func (v *VehicleSheet) installNewItemCmdHandlers(itemID, containerID int, creator itemCreator) {
+	variant := NoItemVariant
+	if containerID == -1 {
+		variant = AlternateItemVariant
+	} else {
+		v.InstallCmdHandlers(containerID, unison.AlwaysEnabled,
+			func(_ any) { creator.CreateItem(v, ContainerItemVariant) })
+	}
+	v.InstallCmdHandlers(itemID, unison.AlwaysEnabled, func(_ any) { creator.CreateItem(v, variant) })
+}
+
+func (v *VehicleSheet) keyToPanel(key string) *unison.Panel {
+	var p unison.Paneler
+	switch key {
+	case equipmentDragKey:
+		p = v.Equipment.Table
+	case noteDragKey:
+		p = v.Notes.Table
+	default:
+		return nil
+	}
+	return p.AsPanel()
+}
+
+func (v *VehicleSheet) addCrew() {
+	if v.needsSaveAsPrompt {
+		unison.WarningDialogWithMessage(i18n.Text("The vehicle sheet must be saved first"),
+			i18n.Text("Crew are referenced relative to the vehicle sheet, so it must be saved before any can be added."))
+		return
+	}
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(true)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(gurps.SheetExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	dialog.SetInitialDirectory(filepath.Dir(v.path))
+	if !dialog.RunModal() {
+		return
+	}
+	type crewInfo struct {
+		path string
+		name string
+	}
+	var crew []crewInfo
+	for _, p := range dialog.Paths() {
+		e, err := gurps.NewEntityFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p))
+		if err != nil {
+			Workspace.ErrorHandler(fmt.Sprintf(i18n.Text("Unable to load %s"), xfilepath.BaseName(p)), err)
+			return
+		}
+		crew = append(crew, crewInfo{path: p, name: e.Profile.Name})
+	}
+	v.updateTables(i18n.Text("Add Crew"), func() {
+		for _, one := range crew {
+			v.vehicle.AddCrew(v.path, one.path, one.name, "")
+		}
+	})
+}
+
+func (v *VehicleSheet) removeCrew(crew *gurps.VehicleCrew) {
+	v.updateTables(i18n.Text("Remove Crew"), func() { v.vehicle.RemoveCrew(crew) })
+}
+
+// Entity implements gurps.EntityProvider
+func (v *VehicleSheet) Entity() *gurps.Entity {
+	return nil
+}
+
+// DockableKind implements widget.DockableKind
+func (v *VehicleSheet) DockableKind() string {
+	return VehicleSheetDockableKind
+}
+
+// UndoManager implements undo.Provider
+func (v *VehicleSheet) UndoManager() *unison.UndoManager {
+	return v.undoMgr
+}
+
+// TitleIcon implements ux.FileBackedDockable
+func (v *VehicleSheet) TitleIcon(suggestedSize geom.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  gurps.FileInfoFor(v.path).SVG,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements ux.FileBackedDockable
+func (v *VehicleSheet) Title() string {
+	return xfilepath.BaseName(v.path)
+}
+
+func (v *VehicleSheet) String() string {
+	return v.Title()
+}
+
+// Tooltip implements ux.FileBackedDockable
+func (v *VehicleSheet) Tooltip() string {
+	return v.path
+}
+
+// BackingFilePath implements ux.FileBackedDockable
+func (v *VehicleSheet) BackingFilePath() string {
+	if v.needsSaveAsPrompt {
+		name := strings.TrimSpace(v.vehicle.Name)
+		if name == "" {
+			name = i18n.Text("Unnamed Vehicle")
+		}
+		return name + gurps.VehicleExt
+	}
+	return v.path
+}
+
+// SetBackingFilePath implements ux.FileBackedDockable
+func (v *VehicleSheet) SetBackingFilePath(p string) {
+	v.path = p
+	UpdateTitleForDockable(v)
+}
+
+// Modified implements ux.FileBackedDockable
+func (v *VehicleSheet) Modified() bool {
+	return v.hash != gurps.Hash64(v.vehicle)
+}
+
+// MarkModified implements widget.ModifiableRoot.
+func (v *VehicleSheet) MarkModified(_ unison.Paneler) {
+	if !v.awaitingUpdate {
+		v.awaitingUpdate = true
+		h, vp := v.scroll.Position()
+		focusRefKey := v.targetMgr.CurrentFocusRef()
+		v.vehicle.ModifiedOn = jio.Now()
+		DeepSync(v)
+		UpdateTitleForDockable(v)
+		v.awaitingUpdate = false
+		v.searchTracker.Refresh()
+		v.targetMgr.ReacquireFocus(focusRefKey, v.toolbar, v.scroll.Content())
+		v.scroll.SetPosition(h, vp)
+	}
+}
+
+// MayAttemptClose implements unison.TabCloser.
+func (v *VehicleSheet) MayAttemptClose() bool {
+	return MayAttemptCloseOfGroup(v)
+}
+
+// AttemptClose implements unison.TabCloser.
+func (v *VehicleSheet) AttemptClose() bool {
+	if AttemptSaveForDockable(v) {
+		return AttemptCloseForDockable(v)
+	}
+	return false
+}
+
+func (v *VehicleSheet) save(forceSaveAs bool) bool {
+	success := false
+	if forceSaveAs || v.needsSaveAsPrompt {
+		success = SaveDockableAs(v, gurps.VehicleExt, v.vehicle.Save, func(path string) {
+			v.hash = gurps.Hash64(v.vehicle)
+			v.path = path
+		})
+	} else {
+		success = SaveDockable(v, v.vehicle.Save, func() { v.hash = gurps.Hash64(v.vehicle) })
+	}
+	if success {
+		v.needsSaveAsPrompt = false
+	}
+	return success
+}
+
+func (v *VehicleSheet) autosaver() func(filePath string) error {
+	return v.vehicle.Save
+}
+
+func (v *VehicleSheet) markRecovered(filePath string, untitled bool) {
+	v.path = filePath
+	v.needsSaveAsPrompt = untitled
+	v.hash = 0 // Force it to be recognized as unsaved
+	UpdateTitleForDockable(v)
+}
+
+type vehicleTablesUndoData struct {
+	vehicle   *gurps.Vehicle
+	crew      []*gurps.VehicleCrew
+	equipment *TableUndoEditData[*gurps.Equipment]
+	notes     *TableUndoEditData[*gurps.Note]
+}
+
+func newVehicleTablesUndoData(v *VehicleSheet) *vehicleTablesUndoData {
+	return &vehicleTablesUndoData{
+		vehicle:   v.vehicle,
+		crew:      slices.Clone(v.vehicle.Crew),
+		equipment: NewTableUndoEditData(v.Equipment.Table),
+		notes:     NewTableUndoEditData(v.Notes.Table),
+	}
+}
+
+func (v *vehicleTablesUndoData) Apply() {
+	v.vehicle.Crew = slices.Clone(v.crew)
+	v.equipment.Apply()
+	v.notes.Apply()
+}
+
+func (v *VehicleSheet) countTags(counts map[string]int) {
+	gurps.CountTagsInProvider(counts, v.vehicle)
+}
+
+func (v *VehicleSheet) renameTag(from, to string) {
+	v.updateTables(i18n.Text("Rename Tag"), func() { gurps.RenameTagInProvider(from, to, v.vehicle) })
+}
+
+func (v *VehicleSheet) retargetSources(replacements map[gurps.Source]gurps.Source) {
+	v.updateTables(i18n.Text("Merge Library Duplicates"), func() { gurps.RetargetSources(v.vehicle, replacements) })
+}
+
+func (v *VehicleSheet) searchNodes(hits []*gurps.SearchHit, text string) []*gurps.SearchHit {
+	return gurps.SearchNodesInProvider(hits, v.BackingFilePath(), text, v.vehicle)
+}
+
+func (v *VehicleSheet) locateNode(id tid.TID) bool {
+	return locateTableRowByID(v.Equipment.Table, id) || locateTableRowByID(v.Notes.Table, id)
+}
+
+func (v *VehicleSheet) syncWithAllSources() {
+	v.updateTables(syncWithSourceAction.Title, v.vehicle.SyncWithLibrarySources)
+}
+
+// updateTables calls update to modify the crew or list data, then refreshes the sheet and records an undo for the
+// change.
+func (v *VehicleSheet) updateTables(editName string, update func()) {
+	var undo *unison.UndoEdit[*vehicleTablesUndoData]
+	mgr := unison.UndoManagerFor(v)
+	if mgr != nil {
+		undo = &unison.UndoEdit[*vehicleTablesUndoData]{
+			ID:       unison.NextUndoID(),
+			EditName: editName,
+			UndoFunc: func(e *unison.UndoEdit[*vehicleTablesUndoData]) {
+				e.BeforeData.Apply()
+				v.Rebuild(true)
+			},
+			RedoFunc: func(e *unison.UndoEdit[*vehicleTablesUndoData]) {
+				e.AfterData.Apply()
+				v.Rebuild(true)
+			},
+			AbsorbFunc: func(_ *unison.UndoEdit[*vehicleTablesUndoData], _ unison.Undoable) bool { return false },
+			BeforeData: newVehicleTablesUndoData(v),
+		}
+	}
+	update()
+	v.Equipment.Table.SyncToModel()
+	v.Notes.Table.SyncToModel()
+	if mgr != nil && undo != nil {
+		undo.AfterData = newVehicleTablesUndoData(v)
+		addUndo(mgr, undo)
+	}
+	v.Rebuild(true)
+}
+
+// Rebuild implements widget.Rebuildable.
+func (v *VehicleSheet) Rebuild(full bool) {
+	gurps.DiscardGlobalResolveCache()
+	v.vehicle.EnsureAttachments()
+	v.vehicle.SourceMatcher().PrepareHashes(v.vehicle)
+	h, vp := v.scroll.Position()
+	focusRefKey := v.targetMgr.CurrentFocusRef()
+	if full {
+		equipmentSelMap := v.Equipment.RecordSelection()
+		notesSelMap := v.Notes.RecordSelection()
+		defer func() {
+			v.Equipment.ApplySelection(equipmentSelMap)
+			v.Notes.ApplySelection(notesSelMap)
+		}()
+		fillVehicleCrewBlock(v.crewBlock, v.vehicle, v.targetMgr, v)
+		v.createLists()
+	}
+	DeepSync(v)
+	UpdateTitleForDockable(v)
+	v.searchTracker.Refresh()
+	v.targetMgr.ReacquireFocus(focusRefKey, v.toolbar, v.scroll.Content())
+	v.scroll.SetPosition(h, vp)
+}
+
+func (v *VehicleSheet) createLists() {
+	children := v.content.Children()
+	if len(children) == 0 {
+		return
+	}
+	page, ok := children[0].Self.(*Page)
+	if !ok {
+		return
+	}
+	if children = page.Children(); len(children) == 0 {
+		return
+	}
+	for i := len(children) - 1; i > 0; i-- {
+		page.RemoveChildAtIndex(i)
+	}
+	if v.Equipment.needReconstruction() {
+		v.Equipment = NewOtherEquipmentPageList(v, v.vehicle)
+	} else {
+		v.Equipment.Sync()
+	}
+	v.Equipment.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	page.AddChild(v.Equipment)
+	if v.Notes.needReconstruction() {
+		v.Notes = NewNotesPageList(v, v.vehicle)
+	} else {
+		v.Notes.Sync()
+	}
+	v.Notes.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	page.AddChild(v.Notes)
+	page.ApplyPreferredSize()
+}
+
+// PageInfoProvider returns the page info provider for this sheet.
+func (v *VehicleSheet) PageInfoProvider() gurps.PageInfoProvider {
+	return v.vehicle
+}
+
+// SheetSettingsUpdated implements gurps.SheetSettingsResponder.
+func (v *VehicleSheet) SheetSettingsUpdated(_ *gurps.Entity, blockLayout bool) {
+	v.MarkModified(nil)
+	v.Rebuild(blockLayout)
+}
+
+func (v *VehicleSheet) disclosureTables() []disclosureTables {
+	return []disclosureTables{
+		v.Equipment,
+		v.Notes,
+	}
+}
+
+func (v *VehicleSheet) toggleHierarchy() {
+	tables := v.disclosureTables()
+	var open, exists bool
+	for _, table := range tables {
+		if open, exists = table.FirstDisclosureState(); exists {
+			break
+		}
+	}
+	open = !open
+	for _, table := range tables {
+		table.SetDisclosureState(open)
+	}
+	v.Rebuild(true)
+}
+
+func (v *VehicleSheet) toggleNotes() {
+	tables := v.disclosureTables()
+	state := 0
+	for _, table := range tables {
+		if state = table.FirstNoteState(); state != 0 {
+			break
+		}
+	}
+	if state == 0 {
+		return
+	}
+	var closed bool
+	if state == 1 {
+		closed = true
+	}
+	for _, table := range tables {
+		table.ApplyNoteState(closed)
+	}
+	v.Rebuild(true)
+}
@@ -56,7 +56,7 @@ func (we *weaponEditor) initWeaponEditor(e *editor[*gurps.Weapon, *gurps.Weapon]
 		we.addRecoilBlock(w, content)
 	}
 	we.addStrengthBlock(w, content)
-	content.AddChild(newDefaultsPanel(gurps.EntityFromNode(w), &w.Defaults))
+	content.AddChild(newDefaultsPanel(gurps.EntityFromNode(w), i18n.Text("Defaults"), &w.Defaults))
 	if w.IsRanged() {
 		we.jetCheckBox.OnSet = func() {
 			state := we.jetCheckBox.State == check.Off
@@ -66,6 +66,18 @@ func (p *weaponsProvider) showAllWeapons() bool {
 	return entity.SheetSettings.ShowAllWeapons
 }
 
+func (p *weaponsProvider) showHitLocations() bool {
+	owner := p.provider.DataOwner()
+	if xreflect.IsNil(owner) {
+		return false
+	}
+	entity := owner.OwningEntity()
+	if entity == nil {
+		return false
+	}
+	return entity.SheetSettings.ShowHitLocationColumn
+}
+
 func (p *weaponsProvider) RootRowCount() int {
 	return len(p.provider.Weapons(p.melee, p.showAllWeapons(), p.forPage))
 }
@@ -162,7 +174,11 @@ func (p *weaponsProvider) ColumnIDs() []int {
 			gurps.WeaponRecoilColumn,
 		)
 	}
-	return append(columnIDs, gurps.WeaponSTColumn)
+	columnIDs = append(columnIDs, gurps.WeaponSTColumn)
+	if p.forPage && p.showHitLocations() {
+		columnIDs = append(columnIDs, gurps.WeaponHitLocationColumn)
+	}
+	return columnIDs
 }
 
 func (p *weaponsProvider) HierarchyColumnID() int {
@@ -107,6 +107,17 @@ func addPageRefHighlightLabelAndField(parent *unison.Panel, fieldData *string) {
 		fieldData)
 }
 
+func addProvenanceLabelsAndFields(parent *unison.Panel, fieldData *gurps.Provenance) {
+	addLabelAndStringField(parent, i18n.Text("Author"), i18n.Text("The person or group that created this item"),
+		&fieldData.Author)
+	addLabelAndStringField(parent, i18n.Text("License"),
+		i18n.Text("The license this item may be shared and used under, e.g. CC BY 4.0"), &fieldData.License)
+	addLabelAndStringField(parent, i18n.Text("Source Book"),
+		i18n.Text("The book or other publication this item was taken from"), &fieldData.SourceBook)
+	addLabelAndStringField(parent, i18n.Text("Version"), i18n.Text("The version or revision of this item"),
+		&fieldData.Version)
+}
+
 func addNotesLabelAndField(parent *unison.Panel, fieldData *string) {
 	labelText := i18n.Text("Notes")
 	addLabel(parent, labelText, "")
@@ -675,19 +686,23 @@ func addTemplateChoices(parent *unison.Panel, targetmgr *TargetMgr, targetKey st
 	templatePickerTypePopup.SelectionChangedCallback = func(p *unison.PopupMenu[picker.Type]) {
 		if item, ok := p.Selected(); ok {
 			(*tp).Type = item
-			if last == picker.NotApplicable && item != picker.NotApplicable {
+			if !templatePickerHasQualifier(last) && templatePickerHasQualifier(item) {
 				(*tp).Qualifier.Qualifier = fxp.One
 				if syncer, ok2 := field.(Syncer); ok2 {
 					syncer.Sync()
 				}
 			}
 			last = item
-			adjustFieldBlank(field, item == picker.NotApplicable || (*tp).Qualifier.Compare == criteria.AnyNumber)
-			adjustPopupBlank(popup, item == picker.NotApplicable)
+			adjustFieldBlank(field, !templatePickerHasQualifier(item) || (*tp).Qualifier.Compare == criteria.AnyNumber)
+			adjustPopupBlank(popup, !templatePickerHasQualifier(item))
 			MarkModified(parent)
 		}
 	}
-	adjustFieldBlank(field, (*tp).Type == picker.NotApplicable)
+	adjustFieldBlank(field, !templatePickerHasQualifier((*tp).Type))
+}
+
+func templatePickerHasQualifier(pickerType picker.Type) bool {
+	return pickerType != picker.NotApplicable && pickerType != picker.Optional
 }
 
 func addScriptField(parent *unison.Panel, targetMgr *TargetMgr, targetKey, undoTitle, tooltip string, get func() string, set func(string), includeMarkdownButton bool) *StringField {
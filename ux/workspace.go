@@ -95,7 +95,13 @@ func InitWorkspace(wnd *unison.Window) {
 
 func finishInit() {
 	Workspace.Window.ResizedCallback = nil
-	if gurps.GlobalSettings().General.RestoreWorkspaceOnStart {
+	global := gurps.GlobalSettings()
+	if layout, ok := global.Workspaces[global.LaunchWorkspace]; ok {
+		currentWorkspaceName = global.LaunchWorkspace
+		xos.SafeCall(func() { applyWorkspaceLayout(layout) }, func(err error) {
+			slog.Warn("Unable to restore workspace", "name", global.LaunchWorkspace, "error", err)
+		})
+	} else if global.General.RestoreWorkspaceOnStart {
 		xos.SafeCall(restoreDockState, func(err error) {
 			slog.Warn("Unable to restore workspace state", "error", err)
 		})
@@ -104,13 +110,18 @@ func finishInit() {
 	}
 	Workspace.Navigator.InitialFocus()
 	Workspace.ErrorHandler = func(msg string, err error) { unison.ErrorDialogWithError(msg, err) }
+	unison.InvokeTask(offerRecovery)
 }
 
 func restoreDockState() {
 	global := gurps.GlobalSettings()
+	restoreDockStates(global.TopDockState, global.DocDockState)
+}
+
+func restoreDockStates(topDockState, docDockState *unison.DockState) {
 	m := make(map[string]unison.Dockable)
-	extractDockKeys(m, global.TopDockState)
-	extractDockKeys(m, global.DocDockState)
+	extractDockKeys(m, topDockState)
+	extractDockKeys(m, docDockState)
 	if len(m) == 0 {
 		return
 	}
@@ -120,8 +131,8 @@ func restoreDockState() {
 			files = append(files, k[len(filePrefix):])
 		}
 	}
-	if global.TopDockState != nil {
-		global.TopDockState.Apply(Workspace.TopDock, func(key string) unison.Dockable {
+	if topDockState != nil {
+		topDockState.Apply(Workspace.TopDock, func(key string) unison.Dockable {
 			switch key {
 			case NavigatorDockKey:
 				return Workspace.Navigator
@@ -139,8 +150,8 @@ func restoreDockState() {
 			m[filePrefix+k] = newNotFoundDockable(k)
 		}
 	}
-	if global.DocDockState != nil {
-		global.DocDockState.Apply(Workspace.DocumentDock.Dock, func(key string) unison.Dockable {
+	if docDockState != nil {
+		docDockState.Apply(Workspace.DocumentDock.Dock, func(key string) unison.Dockable {
 			if d, ok := m[key]; ok {
 				return d
 			}
@@ -245,19 +256,7 @@ func isWorkspaceAllowedToClose() bool {
 	global.DocDockState = unison.NewDockState(Workspace.DocumentDock.Dock, collectDockKeys)
 
 	// Finally, close all of the remaining dockables.
-	for _, d := range AllDockables() {
-		if tc, ok := d.(unison.TabCloser); ok {
-			if _, ok = d.(GroupedCloser); !ok {
-				if !tc.MayAttemptClose() {
-					return false
-				}
-				if !tc.AttemptClose() {
-					return false
-				}
-			}
-		}
-	}
-	return true
+	return closeAllDockables()
 }
 
 func collectDockKeys(dockable unison.Dockable) string {
@@ -268,6 +267,7 @@ func collectDockKeys(dockable unison.Dockable) string {
 }
 
 func workspaceWillClose() {
+	stopAutosave()
 	frame := Workspace.Window.FrameRect()
 	global := gurps.GlobalSettings()
 	global.WorkspaceFrame = &frame
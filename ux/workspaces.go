@@ -0,0 +1,222 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/toolbox/v2/xos"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/check"
+)
+
+// currentWorkspaceName holds the name of the workspace that was last saved or switched to, if any.
+var currentWorkspaceName string
+
+// SaveWorkspace asks for a name, then saves the open dockables, their arrangement and scroll positions under it.
+func SaveWorkspace() {
+	name := currentWorkspaceName
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	nameLabel := i18n.Text("Name")
+	content.AddChild(NewFieldLeadingLabel(nameLabel, false))
+	nameField := NewStringField(nil, "", nameLabel, func() string { return name }, func(s string) { name = s })
+	nameField.SetMinimumTextWidthUsing("Game Night with the Group")
+	nameField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	content.AddChild(nameField)
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		content, []*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.NotResizableWindowOption())
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to save workspace"), err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return
+	}
+	global := gurps.GlobalSettings()
+	if _, exists := global.Workspaces[name]; exists && name != currentWorkspaceName &&
+		unison.QuestionDialog(fmt.Sprintf(i18n.Text("Replace the workspace %q?"), name), "") != unison.ModalResponseOK {
+		return
+	}
+	if global.Workspaces == nil {
+		global.Workspaces = make(map[string]*gurps.WorkspaceLayout)
+	}
+	global.Workspaces[name] = captureWorkspaceLayout()
+	currentWorkspaceName = name
+}
+
+// SwitchToWorkspace closes the open dockables, giving the user the chance to save any changes, then restores the named
+// workspace in their place.
+func SwitchToWorkspace(name string) {
+	layout, ok := gurps.GlobalSettings().Workspaces[name]
+	if !ok || !closeAllDockables() {
+		return
+	}
+	currentWorkspaceName = name
+	xos.SafeCall(func() { applyWorkspaceLayout(layout) }, func(err error) {
+		Workspace.ErrorHandler(fmt.Sprintf(i18n.Text("Unable to restore the workspace %q"), name), err)
+	})
+}
+
+// ShowManageWorkspaces shows a dialog for choosing the workspace to open on launch and for deleting saved workspaces.
+func ShowManageWorkspaces() {
+	global := gurps.GlobalSettings()
+	names := global.WorkspaceNames()
+	if len(names) == 0 {
+		unison.WarningDialogWithMessage(i18n.Text("No workspaces have been saved"),
+			i18n.Text("Use Save Workspace… to save the open documents and their arrangement."))
+		return
+	}
+	launch := global.LaunchWorkspace
+	deleted := make(map[string]bool)
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("On Launch"), false))
+	lastUsed := i18n.Text("Restore the last arrangement")
+	launchPopup := unison.NewPopupMenu[string]()
+	launchPopup.AddItem(lastUsed)
+	launchPopup.AddItem(names...)
+	if launch == "" {
+		launchPopup.Select(lastUsed)
+	} else {
+		launchPopup.Select(launch)
+	}
+	launchPopup.SelectionChangedCallback = func(p *unison.PopupMenu[string]) {
+		if item, ok := p.Selected(); ok && item != lastUsed {
+			launch = item
+		} else {
+			launch = ""
+		}
+	}
+	launchPopup.Tooltip = newWrappedTooltip(i18n.Text(`The workspace to open on launch. The last arrangement is only
+restored if that is enabled in the general settings.`))
+	content.AddChild(launchPopup)
+	for _, name := range names {
+		content.AddChild(NewFieldLeadingLabel(name, false))
+		checkBox := NewCheckBox(nil, "", i18n.Text("Delete"),
+			func() check.Enum { return check.FromBool(deleted[name]) },
+			func(state check.Enum) { deleted[name] = state == check.On })
+		content.AddChild(checkBox)
+	}
+	dialog, err := unison.NewDialog(nil, nil, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.NotResizableWindowOption())
+	if err != nil {
+		Workspace.ErrorHandler(i18n.Text("Unable to manage workspaces"), err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	for name := range deleted {
+		if deleted[name] {
+			delete(global.Workspaces, name)
+			if name == currentWorkspaceName {
+				currentWorkspaceName = ""
+			}
+		}
+	}
+	global.LaunchWorkspace = launch
+	global.EnsureValidity()
+}
+
+func captureWorkspaceLayout() *gurps.WorkspaceLayout {
+	layout := &gurps.WorkspaceLayout{
+		TopDockState: unison.NewDockState(Workspace.TopDock, collectDockKeys),
+		DocDockState: unison.NewDockState(Workspace.DocumentDock.Dock, collectDockKeys),
+	}
+	for _, d := range AllDockables() {
+		key := collectDockKeys(d)
+		if key == "" {
+			continue
+		}
+		if scroller := dockableScrollPanel(d); scroller != nil {
+			h, v := scroller.Position()
+			if h != 0 || v != 0 {
+				if layout.ScrollPositions == nil {
+					layout.ScrollPositions = make(map[string]geom.Point)
+				}
+				layout.ScrollPositions[key] = geom.Point{X: h, Y: v}
+			}
+		}
+	}
+	return layout
+}
+
+func applyWorkspaceLayout(layout *gurps.WorkspaceLayout) {
+	restoreDockStates(layout.TopDockState, layout.DocDockState)
+	if len(layout.ScrollPositions) == 0 {
+		return
+	}
+	// The scroll positions can only be set once the restored dockables have been laid out.
+	unison.InvokeTask(func() {
+		for _, d := range AllDockables() {
+			if pt, ok := layout.ScrollPositions[collectDockKeys(d)]; ok {
+				if scroller := dockableScrollPanel(d); scroller != nil {
+					d.AsPanel().ValidateLayout()
+					scroller.SetPosition(pt.X, pt.Y)
+				}
+			}
+		}
+	})
+}
+
+// dockableScrollPanel returns the outermost scroll panel within the dockable, or nil if it has none.
+func dockableScrollPanel(d unison.Dockable) *unison.ScrollPanel {
+	queue := slices.Clone(d.AsPanel().Children())
+	for len(queue) != 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if scroller, ok := p.Self.(*unison.ScrollPanel); ok {
+			return scroller
+		}
+		queue = append(queue, p.Children()...)
+	}
+	return nil
+}
+
+// closeAllDockables closes the dockables that may be closed, giving the user the chance to save any changes. Returns
+// false if the user canceled.
+func closeAllDockables() bool {
+	for _, d := range AllDockables() {
+		if tc, ok := d.(unison.TabCloser); ok {
+			if _, ok = d.(GroupedCloser); !ok {
+				if !tc.MayAttemptClose() {
+					return false
+				}
+				if !tc.AttemptClose() {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,338 @@
+// Copyright (c) 1998-2025 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package ux
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fonts"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/gurps"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/geom"
+	"github.com/richardwilkes/toolbox/v2/i18n"
+	"github.com/richardwilkes/unison"
+	"github.com/richardwilkes/unison/enums/align"
+	"github.com/richardwilkes/unison/enums/paintstyle"
+)
+
+var _ pageHelper = &WoundsPanel{}
+
+// WoundsPanel holds the list of wounds a character has suffered, along with the penalties derived from them.
+type WoundsPanel struct {
+	unison.Panel
+	entity    *gurps.Entity
+	editable  bool
+	hash      uint64
+	drawStart int
+	drawEnd   int
+}
+
+// NewWoundsPanel creates a new wounds panel. If editable is true, buttons for removing wounds will be included.
+func NewWoundsPanel(entity *gurps.Entity, editable bool) *WoundsPanel {
+	p := &WoundsPanel{
+		entity:   entity,
+		editable: editable,
+		drawEnd:  1,
+	}
+	p.Self = p
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: align.Fill,
+		VAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewCompoundBorder(&TitledBorder{Title: i18n.Text("Wounds")},
+		unison.NewEmptyBorder(geom.NewSymmetricInsets(2, 1))))
+	p.DrawCallback = func(gc *unison.Canvas, rect geom.Rect) {
+		gc.DrawRect(rect, unison.ThemeBelowSurface.Paint(gc, rect, paintstyle.Fill))
+	}
+	p.rebuild()
+	return p
+}
+
+// HasWounds returns true if at least one wound is present.
+func (p *WoundsPanel) HasWounds() bool {
+	return len(p.entity.Wounds) != 0
+}
+
+func (p *WoundsPanel) columns() int {
+	if p.editable {
+		return 7
+	}
+	return 6
+}
+
+func (p *WoundsPanel) rebuild() {
+	p.hash = gurps.HashWounds(p.entity.Wounds)
+	p.RemoveAllChildren()
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  p.columns(),
+		HSpacing: 4,
+	})
+	for _, title := range []string{
+		i18n.Text("Location"),
+		i18n.Text("Type"),
+		i18n.Text("Basic"),
+		i18n.Text("DR"),
+		i18n.Text("Injury"),
+		i18n.Text("Effects"),
+	} {
+		label := NewPageLabel(title)
+		label.Font = fonts.PageLabelSecondary
+		p.AddChild(label)
+	}
+	if p.editable {
+		p.AddChild(unison.NewPanel())
+	}
+	body := gurps.BodyFor(p.entity)
+	for _, w := range p.entity.Wounds {
+		name := w.LocationID
+		if loc := body.LookupLocationByID(p.entity, w.LocationID); loc != nil {
+			name = loc.ChoiceName
+		}
+		p.AddChild(NewPageLabel(name))
+		p.AddChild(NewPageLabel(w.DamageType))
+		p.addNumber(w.Basic)
+		p.addNumber(w.DR)
+		p.addNumber(w.Injury)
+		var effects []string
+		if w.Crippling {
+			effects = append(effects, i18n.Text("Crippling"))
+		}
+		if w.Bleeding {
+			effects = append(effects, i18n.Text("Bleeding"))
+		}
+		effectsLabel := NewPageLabel(strings.Join(effects, ", "))
+		effectsLabel.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: align.Fill,
+			HGrab:  true,
+		})
+		if w.Notes != "" {
+			effectsLabel.Tooltip = newWrappedTooltip(w.Notes)
+		}
+		p.AddChild(effectsLabel)
+		if p.editable {
+			p.AddChild(p.createRemoveButton(w))
+		}
+	}
+	summary := NewPageLabel(p.summary())
+	summary.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  p.columns(),
+		HAlign: align.Fill,
+		HGrab:  true,
+	})
+	p.AddChild(summary)
+}
+
+func (p *WoundsPanel) addNumber(value int) {
+	label := NewPageLabelEnd(strconv.Itoa(value))
+	label.SetLayoutData(&unison.FlexLayoutData{HAlign: align.End})
+	p.AddChild(label)
+}
+
+func (p *WoundsPanel) summary() string {
+	var parts []string
+	if shock := p.entity.ShockPenalty(); shock != 0 {
+		parts = append(parts, fmt.Sprintf(i18n.Text("Shock: %d to DX and IQ"), shock))
+	}
+	if crippled := p.entity.CrippledLocationIDs(); len(crippled) != 0 {
+		body := gurps.BodyFor(p.entity)
+		for i, id := range crippled {
+			if loc := body.LookupLocationByID(p.entity, id); loc != nil {
+				crippled[i] = loc.ChoiceName
+			}
+		}
+		parts = append(parts, fmt.Sprintf(i18n.Text("Crippled: %s"), strings.Join(crippled, ", ")))
+	}
+	if p.entity.Bleeding() {
+		parts = append(parts, i18n.Text("Bleeding"))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (p *WoundsPanel) createRemoveButton(w *gurps.Wound) *unison.Button {
+	b := unison.NewSVGButton(svg.Trash)
+	b.Tooltip = newWrappedTooltip(i18n.Text("Remove this wound and restore the hit points it cost"))
+	b.ClickCallback = func() {
+		editWounds(p, p.entity, i18n.Text("Remove Wound"), func() {
+			if i := slices.Index(p.entity.Wounds, w); i != -1 {
+				p.entity.Wounds = slices.Delete(p.entity.Wounds, i, i+1)
+				if attr, ok := p.entity.Attributes.Set["hp"]; ok {
+					attr.Damage = (attr.Damage - fxp.FromInteger(w.Injury)).Max(0)
+				}
+			}
+		})
+	}
+	return b
+}
+
+// Sync the panel to the current data.
+func (p *WoundsPanel) Sync() {
+	if gurps.HashWounds(p.entity.Wounds) != p.hash {
+		p.rebuild()
+	} else if children := p.Children(); len(children) != 0 {
+		if label, ok := children[len(children)-1].Self.(*unison.Label); ok {
+			label.SetTitle(p.summary())
+		}
+	}
+	MarkForLayoutWithinDockable(p)
+}
+
+// OverheadHeight implements pageHelper.
+func (p *WoundsPanel) OverheadHeight() float32 {
+	return 0
+}
+
+// RowHeights implements pageHelper. The wounds are always kept together, so they are reported as a single row.
+func (p *WoundsPanel) RowHeights() []float32 {
+	return []float32{p.FrameRect().Height}
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *WoundsPanel) CurrentDrawRowRange() (start, endBefore int) {
+	return p.drawStart, p.drawEnd
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *WoundsPanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+}
+
+type woundsSnapshot struct {
+	wounds   []*gurps.Wound
+	hpDamage fxp.Int
+}
+
+func captureWounds(entity *gurps.Entity) *woundsSnapshot {
+	s := &woundsSnapshot{wounds: gurps.CloneWoundList(entity.Wounds)}
+	if attr, ok := entity.Attributes.Set["hp"]; ok {
+		s.hpDamage = attr.Damage
+	}
+	return s
+}
+
+func (s *woundsSnapshot) apply(entity *gurps.Entity) {
+	entity.SetWounds(s.wounds)
+	if attr, ok := entity.Attributes.Set["hp"]; ok {
+		attr.Damage = s.hpDamage
+	}
+}
+
+func editWounds(src unison.Paneler, entity *gurps.Entity, name string, edit func()) {
+	owner := unison.AncestorOrSelf[Rebuildable](src)
+	before := captureWounds(entity)
+	edit()
+	after := captureWounds(entity)
+	if mgr := unison.UndoManagerFor(src); mgr != nil && owner != nil {
+		addUndo(mgr, &unison.UndoEdit[*woundsSnapshot]{
+			ID:       unison.NextUndoID(),
+			EditName: name,
+			UndoFunc: func(e *unison.UndoEdit[*woundsSnapshot]) {
+				e.BeforeData.apply(entity)
+				owner.Rebuild(true)
+			},
+			RedoFunc: func(e *unison.UndoEdit[*woundsSnapshot]) {
+				e.AfterData.apply(entity)
+				owner.Rebuild(true)
+			},
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	if owner != nil {
+		owner.Rebuild(true)
+	}
+}
+
+// ShowRecordHitDialog asks for the details of a hit against the sheet's character and records the resulting wound.
+func ShowRecordHitDialog(sheet *Sheet) {
+	entity := sheet.Entity()
+	if locationID, damageType, basic, ok := askForHit(entity); ok {
+		editWounds(sheet, entity, i18n.Text("Record Hit"), func() {
+			entity.RecordWound(entity.NewWound(locationID, damageType, basic))
+		})
+	}
+}
+
+// askForHit asks for the hit location, damage type, and basic damage of a hit against the entity.
+func askForHit(entity *gurps.Entity) (locationID, damageType string, basic int, ok bool) {
+	locations := gurps.BodyFor(entity).UniqueHitLocations(entity)
+	if len(locations) == 0 {
+		return "", "", 0, false
+	}
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Location"), false))
+	locationPopup := unison.NewPopupMenu[string]()
+	for _, loc := range locations {
+		locationPopup.AddItem(loc.ChoiceName)
+	}
+	locationPopup.SelectIndex(max(slices.IndexFunc(locations, func(loc *gurps.HitLocation) bool {
+		return loc.ID() == "torso"
+	}), 0))
+	content.AddChild(locationPopup)
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Damage Type"), false))
+	typePopup := unison.NewPopupMenu[string]()
+	for _, one := range gurps.WoundDamageTypes {
+		typePopup.AddItem(one)
+	}
+	typePopup.Select("cr")
+	content.AddChild(typePopup)
+
+	label := i18n.Text("Basic Damage")
+	content.AddChild(NewFieldLeadingLabel(label, false))
+	preview := unison.NewLabel()
+	updatePreview := func() {
+		loc := locations[max(locationPopup.SelectedIndex(), 0)]
+		selectedType, _ := typePopup.Selected()
+		w := entity.NewWound(loc.ID(), selectedType, basic)
+		preview.SetTitle(fmt.Sprintf(i18n.Text("DR %d, injury %d"), w.DR, w.Injury))
+		preview.MarkForLayoutAndRedraw()
+	}
+	content.AddChild(NewIntegerField(nil, "", label,
+		func() int { return basic },
+		func(value int) {
+			basic = value
+			updatePreview()
+		}, 0, 9999, false, false))
+	locationPopup.SelectionChangedCallback = func(_ *unison.PopupMenu[string]) { updatePreview() }
+	typePopup.SelectionChangedCallback = func(_ *unison.PopupMenu[string]) { updatePreview() }
+	content.AddChild(unison.NewPanel())
+	content.AddChild(preview)
+	updatePreview()
+
+	icon := &unison.DrawableSVG{
+		SVG:  svg.FirstAidKit,
+		Size: geom.Size{Width: 48, Height: 48},
+	}
+	dialog, err := unison.NewDialog(icon, unison.DefaultDialogTheme.QuestionIconInk, content,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()},
+		unison.FloatingWindowOption(), unison.NotResizableWindowOption())
+	if err != nil {
+		errs.Log(err)
+		return "", "", 0, false
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return "", "", 0, false
+	}
+	damageType, _ = typePopup.Selected()
+	return locations[max(locationPopup.SelectedIndex(), 0)].ID(), damageType, basic, true
+}